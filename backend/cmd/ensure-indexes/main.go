@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"backend/config"
+	"backend/database"
+)
+
+// Builds the MongoDB indexes the server relies on, as a deploy step ahead of servers started with
+// MONGO_ENSURE_INDEXES=false. Indexes that already exist are left alone; each one built or failed is
+// logged, and the command exits non-zero if any could not be built.
+func main() {
+	fmt.Println("🗂️  Ensuring MongoDB indexes ...")
+
+	cfg := config.LoadConfig()
+	cfg.Database.EnsureIndexes = false
+
+	db, err := database.ConnectMongoDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+
+	if err := database.EnsureIndexes(context.Background(), db); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	fmt.Println("✅ MongoDB indexes are in place")
+}
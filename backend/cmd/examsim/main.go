@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"backend/models"
+)
+
+// Simulates an exam day against a running server: K students log in, start a quiz, answer at a
+// human pace and submit, all through the public HTTP API. Prints latency percentiles and error
+// rates per operation, and exits non-zero when the error rate is above -max-error-rate.
+//
+// Accounts come from a file of "email,password" lines, one per student. Without one, each
+// student takes a time quiz with its own guest token.
+//
+//	go run ./cmd/examsim -base-url https://staging.example.com -students 200 -accounts students.csv
+func main() {
+	baseURL := flag.String("base-url", "http://localhost:8080", "server to test, without the /api/v1 prefix")
+	students := flag.Int("students", 50, "number of concurrent simulated students")
+	accountsFile := flag.String("accounts", "", "file of email,password lines, one account per student; guests are used when empty")
+	quizType := flag.String("quiz-type", string(models.MockTest), "quiz type to start (mock_test or time_quiz)")
+	templateID := flag.String("template-id", "", "quiz template to start instead of the quiz type's default")
+	ramp := flag.Duration("ramp", 30*time.Second, "spread student start times over this long")
+	thinkMin := flag.Duration("think-min", 5*time.Second, "shortest time a student spends on a question")
+	thinkMax := flag.Duration("think-max", 30*time.Second, "longest time a student spends on a question")
+	skipRate := flag.Float64("skip-rate", 0.05, "fraction of questions skipped instead of answered")
+	captchaToken := flag.String("captcha-token", "", "X-Captcha-Token sent on login and guest requests, for servers with captcha enabled")
+	timeout := flag.Duration("timeout", 15*time.Second, "timeout of each HTTP request")
+	maxErrorRate := flag.Float64("max-error-rate", 0.01, "exit with status 1 when the overall error rate is higher")
+	flag.Parse()
+
+	if *students < 1 {
+		log.Fatal("❌ -students must be at least 1")
+	}
+	if *thinkMax < *thinkMin {
+		log.Fatal("❌ -think-max must not be shorter than -think-min")
+	}
+
+	var accounts []account
+	if *accountsFile != "" {
+		var err error
+		accounts, err = loadAccounts(*accountsFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to read accounts: %v", err)
+		}
+		// Students sharing an account would resume each other's session
+		if len(accounts) < *students {
+			log.Fatalf("❌ %d students need %d accounts, %s has %d", *students, *students, *accountsFile, len(accounts))
+		}
+	} else if *quizType != string(models.TimeQuiz) {
+		log.Fatal("❌ guests can only take time quizzes; pass -accounts or -quiz-type time_quiz")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sim := &simulator{
+		client:       &http.Client{Timeout: *timeout},
+		apiURL:       strings.TrimRight(*baseURL, "/") + "/api/v1",
+		quizType:     models.QuizType(*quizType),
+		templateID:   *templateID,
+		thinkMin:     *thinkMin,
+		thinkMax:     *thinkMax,
+		skipRate:     *skipRate,
+		captchaToken: *captchaToken,
+		stats:        newStats(),
+	}
+
+	fmt.Printf("🚀 Simulating %d students against %s (quiz type %s, ramp %s)\n", *students, sim.apiURL, *quizType, *ramp)
+	started := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < *students; i++ {
+		var acct *account
+		if accounts != nil {
+			acct = &accounts[i]
+		}
+		delay := time.Duration(0)
+		if *students > 1 {
+			delay = *ramp * time.Duration(i) / time.Duration(*students-1)
+		}
+
+		wg.Add(1)
+		go func(id int, acct *account, delay time.Duration) {
+			defer wg.Done()
+			if !sleep(ctx, delay) {
+				return
+			}
+			sim.runStudent(ctx, id, acct)
+		}(i, acct, delay)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		fmt.Println("⚠️  Interrupted; results cover the requests made so far")
+	}
+
+	errorRate := sim.stats.report(os.Stdout, time.Since(started))
+	if errorRate > *maxErrorRate {
+		fmt.Printf("❌ Error rate %.2f%% is above the %.2f%% limit\n", errorRate*100, *maxErrorRate*100)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Error rate within limit")
+}
+
+type account struct {
+	email    string
+	password string
+}
+
+// loadAccounts reads "email,password" lines, skipping blank lines and # comments
+func loadAccounts(path string) ([]account, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var accounts []account
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		email, password, ok := strings.Cut(text, ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected email,password", line)
+		}
+		accounts = append(accounts, account{email: strings.TrimSpace(email), password: strings.TrimSpace(password)})
+	}
+	return accounts, scanner.Err()
+}
+
+type simulator struct {
+	client       *http.Client
+	apiURL       string
+	quizType     models.QuizType
+	templateID   string
+	thinkMin     time.Duration
+	thinkMax     time.Duration
+	skipRate     float64
+	captchaToken string
+	stats        *stats
+}
+
+// runStudent takes one quiz from sign-in to submission. A failed step ends the student's run;
+// the failure is already counted against that step.
+func (s *simulator) runStudent(ctx context.Context, id int, acct *account) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(id)))
+
+	token, err := s.signIn(ctx, acct)
+	if err != nil {
+		return
+	}
+
+	var started models.StartQuizResponse
+	startReq := models.StartQuizRequest{QuizType: s.quizType, TemplateID: s.templateID}
+	if err := s.call(ctx, "start", http.MethodPost, "/quiz/start", token, startReq, &started); err != nil {
+		return
+	}
+	session := started.Session
+
+	// Leave a margin so the last answers and the submit land before the server's deadline
+	deadline := session.StartTime.Add(time.Duration(session.TimeLimitMinutes)*time.Minute - 10*time.Second)
+
+	for index, question := range session.Questions {
+		think := s.thinkMin + time.Duration(rng.Int63n(int64(s.thinkMax-s.thinkMin)+1))
+		if time.Now().Add(think).After(deadline) {
+			break
+		}
+		if !sleep(ctx, think) {
+			return
+		}
+
+		path := "/quiz/session/" + session.SessionToken
+		if rng.Float64() < s.skipRate {
+			skip := models.SkipQuestionRequest{QuestionIndex: index, TimeSpent: int64(think.Seconds())}
+			if err := s.call(ctx, "skip", http.MethodPost, path+"/skip", token, skip, nil); err != nil && isSessionOver(err) {
+				break
+			}
+			continue
+		}
+
+		answer := models.SaveAnswerRequest{
+			QuestionIndex: index,
+			Answer:        simulatedAnswer(rng, question),
+			TimeSpent:     int64(think.Seconds()),
+		}
+		if err := s.call(ctx, "answer", http.MethodPost, path+"/answer", token, answer, nil); err != nil && isSessionOver(err) {
+			break
+		}
+	}
+
+	_ = s.call(ctx, "submit", http.MethodPost, "/quiz/session/"+session.SessionToken+"/submit", token, nil, nil)
+}
+
+// signIn logs in with the student's account, or gets a guest token when there is none
+func (s *simulator) signIn(ctx context.Context, acct *account) (string, error) {
+	if acct == nil {
+		var guest models.GuestSessionResponse
+		if err := s.call(ctx, "guest", http.MethodPost, "/auth/guest", "", nil, &guest); err != nil {
+			return "", err
+		}
+		return guest.AccessToken, nil
+	}
+
+	var auth models.AuthResponse
+	login := models.LoginRequest{Email: acct.email, Password: acct.password}
+	if err := s.call(ctx, "login", http.MethodPost, "/auth/login", "", login, &auth); err != nil {
+		return "", err
+	}
+	return auth.AccessToken, nil
+}
+
+// apiError is a non-2xx response
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.status, e.body)
+}
+
+// isSessionOver reports whether the server refused a request because the session ended or expired
+func isSessionOver(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && (strings.Contains(apiErr.body, "expired") || strings.Contains(apiErr.body, "not active"))
+}
+
+// call sends a JSON request, records its latency and outcome under the operation name, and
+// decodes a successful response into out when given
+func (s *simulator) call(ctx context.Context, operation, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.apiURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "z0nata-examsim")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if s.captchaToken != "" && strings.HasPrefix(path, "/auth/") {
+		req.Header.Set("X-Captcha-Token", s.captchaToken)
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Requests cut short by an interrupt say nothing about the server
+		if ctx.Err() == nil {
+			s.stats.record(operation, time.Since(start), "transport error")
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+	if err != nil {
+		s.stats.record(operation, elapsed, "read error")
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		s.stats.record(operation, elapsed, fmt.Sprintf("HTTP %d", resp.StatusCode))
+		return &apiError{status: resp.StatusCode, body: string(data)}
+	}
+	s.stats.record(operation, elapsed, "")
+
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// simulatedAnswer picks a plausible answer of the right shape; whether it is correct doesn't matter here
+func simulatedAnswer(rng *rand.Rand, question models.SessionQuestion) interface{} {
+	switch question.Type {
+	case models.SingleChoice:
+		if len(question.Options) > 0 {
+			return question.Options[rng.Intn(len(question.Options))].ID
+		}
+	case models.MultipleChoice:
+		var picked []string
+		for _, option := range question.Options {
+			if rng.Intn(2) == 0 {
+				picked = append(picked, option.ID)
+			}
+		}
+		if len(picked) == 0 && len(question.Options) > 0 {
+			picked = append(picked, question.Options[0].ID)
+		}
+		return picked
+	case models.FillInBlank:
+		responses := make(map[string]string)
+		for _, id := range models.BlankPlaceholders(question.Title) {
+			responses[id] = "simulated answer"
+		}
+		return responses
+	case models.Numeric:
+		return strconv.FormatFloat(rng.Float64()*100, 'f', 2, 64)
+	case models.Ordering:
+		arrangement := make([]string, len(question.Options))
+		for i, j := range rng.Perm(len(question.Options)) {
+			arrangement[i] = question.Options[j].ID
+		}
+		return arrangement
+	}
+	return "This is a simulated answer written during an exam day soak test."
+}
+
+// sleep waits for d, returning false if the run was interrupted first
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// stats collects latencies and failures per operation
+type stats struct {
+	mu         sync.Mutex
+	operations map[string]*operationStats
+}
+
+type operationStats struct {
+	latencies []time.Duration
+	failures  map[string]int // failure kind -> count
+}
+
+func newStats() *stats {
+	return &stats{operations: make(map[string]*operationStats)}
+}
+
+// record adds one request; failure is empty for a success
+func (s *stats) record(operation string, latency time.Duration, failure string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.operations[operation]
+	if !ok {
+		op = &operationStats{failures: make(map[string]int)}
+		s.operations[operation] = op
+	}
+	op.latencies = append(op.latencies, latency)
+	if failure != "" {
+		op.failures[failure]++
+	}
+}
+
+// report prints a table of latency percentiles and errors per operation and returns the overall error rate
+func (s *stats) report(w io.Writer, elapsed time.Duration) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Operations in the order a student performs them
+	order := []string{"guest", "login", "start", "answer", "skip", "submit"}
+
+	fmt.Fprintf(w, "\n📊 Results after %s\n\n", elapsed.Round(time.Second))
+	fmt.Fprintf(w, "%-8s %8s %8s %7s %9s %9s %9s %9s %9s\n", "op", "requests", "errors", "err%", "p50", "p90", "p95", "p99", "max")
+
+	var total, failed int
+	for _, name := range order {
+		op, ok := s.operations[name]
+		if !ok {
+			continue
+		}
+
+		latencies := append([]time.Duration(nil), op.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		errorCount := 0
+		for _, count := range op.failures {
+			errorCount += count
+		}
+		total += len(latencies)
+		failed += errorCount
+
+		fmt.Fprintf(w, "%-8s %8d %8d %6.2f%% %9s %9s %9s %9s %9s\n",
+			name, len(latencies), errorCount, percent(errorCount, len(latencies)),
+			percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 95), percentile(latencies, 99),
+			latencies[len(latencies)-1].Round(time.Millisecond))
+	}
+
+	if total == 0 {
+		fmt.Fprintln(w, "\nNo requests were made")
+		return 0
+	}
+
+	fmt.Fprintf(w, "\nTotal: %d requests, %d errors (%.2f%%), %.1f requests/s\n",
+		total, failed, percent(failed, total), float64(total)/elapsed.Seconds())
+
+	for _, name := range order {
+		op, ok := s.operations[name]
+		if !ok || len(op.failures) == 0 {
+			continue
+		}
+		kinds := make([]string, 0, len(op.failures))
+		for kind := range op.failures {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Fprintf(w, "  %s: %d × %s\n", name, op.failures[kind], kind)
+		}
+	}
+	fmt.Fprintln(w)
+
+	return float64(failed) / float64(total)
+}
+
+func percent(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole) * 100
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"backend/config"
+	"backend/database"
+	"backend/repository"
+)
+
+// Stores the content of modules and submodules written before content blocks as a single
+// markdown block each. Reads already upgrade such modules on the fly, so this can run at any
+// time, and running it again changes nothing.
+func main() {
+	fmt.Println("🧱 Migrating module content to blocks ...")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.ConnectMongoDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+
+	migrated, err := repository.NewModuleRepository(db).MigrateLegacyContent(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to migrate module content after %d modules: %v", migrated, err)
+	}
+
+	fmt.Printf("✅ Migrated %d modules\n", migrated)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"backend/config"
+	"backend/database"
+	"backend/repository"
+)
+
+// Readies flat submodules for nesting: submodules stay directly in their module, with any dangling
+// parent cleared, and each group of siblings is numbered from 1 in reading order. Reads already show
+// such modules in order, so this can run at any time, and running it again changes nothing.
+func main() {
+	fmt.Println("🌳 Migrating submodules to the submodule tree ...")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.ConnectMongoDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+
+	migrated, err := repository.NewModuleRepository(db).MigrateSubModuleTree(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to migrate submodules after %d modules: %v", migrated, err)
+	}
+
+	fmt.Printf("✅ Migrated %d modules\n", migrated)
+}
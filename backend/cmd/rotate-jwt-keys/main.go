@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"backend/config"
+	"backend/database"
+	"backend/repository"
+	"backend/services"
+	"backend/utils"
+)
+
+// Rotates the JWT signing key. New tokens are signed with the new key as soon as each
+// server reloads its keys (JWT_KEY_REFRESH_INTERVAL); tokens signed with the outgoing key
+// stay valid until they expire.
+func main() {
+	fmt.Println("🔑 Rotating JWT signing keys ...")
+
+	cfg := config.LoadConfig()
+
+	db, err := database.ConnectMongoDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+
+	signingKeyService := services.NewSigningKeyService(
+		repository.NewSigningKeyRepository(db),
+		utils.NewJWTManager(cfg.JWT),
+		cfg.JWT,
+	)
+
+	result, err := signingKeyService.RotateKeys(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Failed to rotate signing keys: %v", err)
+	}
+
+	fmt.Printf("✅ New signing key: %s\n", result.CurrentKID)
+	if result.PreviousKID != "" {
+		fmt.Printf("🔄 Previous key %s stays valid for verification until %s\n", result.PreviousKID, result.VerifyUntil.Format("2006-01-02 15:04:05 MST"))
+	}
+	for _, kid := range result.RetiredKIDs {
+		fmt.Printf("🗑️  Retired key %s\n", kid)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"backend/config"
+	"backend/database"
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type demoQuestion struct {
+	title   string
+	options []string
+	correct int // 0-based index of correct answer
+}
+
+// Demo questions for local development; never seeded unless this command is run
+var demoQuestions = map[models.DifficultyLevel][]demoQuestion{
+	models.Easy: {
+		{"What is 2 + 2?", []string{"3", "4", "5", "6"}, 1},
+		{"What is the capital of France?", []string{"London", "Berlin", "Paris", "Madrid"}, 2},
+		{"Which color is made by mixing red and blue?", []string{"Green", "Purple", "Yellow", "Orange"}, 1},
+		{"How many days are in a week?", []string{"5", "6", "7", "8"}, 2},
+		{"What is the first letter of the alphabet?", []string{"A", "B", "C", "D"}, 0},
+		{"Which programming language uses 'print()' function?", []string{"Java", "Python", "C++", "Assembly"}, 1},
+		{"What does HTML stand for?", []string{"Hypertext Markup Language", "High Tech Modern Language", "Home Tool Markup Language", "Hyperlink Text Markup Language"}, 0},
+		{"Which planet is closest to the Sun?", []string{"Venus", "Earth", "Mercury", "Mars"}, 2},
+	},
+	models.Medium: {
+		{"What is the time complexity of binary search?", []string{"O(n)", "O(log n)", "O(n²)", "O(1)"}, 1},
+		{"Which HTTP status code indicates 'Not Found'?", []string{"200", "404", "500", "301"}, 1},
+		{"In object-oriented programming, what is inheritance?", []string{"Creating objects", "Reusing code from parent class", "Deleting objects", "Changing object state"}, 1},
+		{"What does SQL stand for?", []string{"Structured Query Language", "Simple Query Language", "Standard Query Language", "Sequential Query Language"}, 0},
+		{"Which data structure follows LIFO principle?", []string{"Queue", "Array", "Stack", "Tree"}, 2},
+		{"What is the default port for HTTP?", []string{"21", "22", "80", "443"}, 2},
+		{"In Git, what command is used to create a new branch?", []string{"git new-branch", "git create-branch", "git branch", "git checkout -b"}, 3},
+	},
+	models.Hard: {
+		{"What is the purpose of the 'volatile' keyword in Java?", []string{"Prevents method overriding", "Ensures thread-safe access to variables", "Makes variables immutable", "Improves performance"}, 1},
+		{"Which algorithm has the best average-case time complexity for sorting?", []string{"Bubble Sort", "Quick Sort", "Merge Sort", "Selection Sort"}, 2},
+		{"In database design, what is normalization?", []string{"Adding indexes", "Organizing data to reduce redundancy", "Encrypting data", "Backing up data"}, 1},
+		{"What does the CAP theorem state?", []string{"Consistency, Availability, Partition tolerance - pick two", "All distributed systems are consistent", "Performance is more important than consistency", "Databases should always be available"}, 0},
+		{"Which design pattern ensures a class has only one instance?", []string{"Factory", "Observer", "Singleton", "Strategy"}, 2},
+		{"What is the space complexity of merge sort?", []string{"O(1)", "O(log n)", "O(n)", "O(n log n)"}, 2},
+	},
+}
+
+func main() {
+	force := flag.Bool("force", false, "seed even when ENVIRONMENT is production")
+	flag.Parse()
+
+	fmt.Println("🚀 Starting demo question seed script ...")
+
+	cfg := config.LoadConfig()
+	if cfg.Server.Environment == "production" && !*force {
+		log.Fatal("❌ Refusing to seed demo questions in production (pass -force to override)")
+	}
+
+	db, err := database.ConnectMongoDB(cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+	}
+
+	questionRepo := repository.NewQuestionRepository(db)
+	ctx := context.Background()
+
+	created, skipped := 0, 0
+	for _, difficulty := range []models.DifficultyLevel{models.Easy, models.Medium, models.Hard} {
+		for _, demo := range demoQuestions[difficulty] {
+			// Seeding twice must not duplicate questions
+			existing, _, err := questionRepo.List(ctx, bson.M{"title": demo.title}, 1, 1)
+			if err != nil {
+				log.Fatalf("❌ Failed to check existing questions: %v", err)
+			}
+			if len(existing) > 0 {
+				skipped++
+				continue
+			}
+
+			if err := questionRepo.Create(ctx, newDemoQuestion(demo, difficulty)); err != nil {
+				fmt.Printf("❌ Failed to create question %q: %v\n", demo.title, err)
+				continue
+			}
+			created++
+		}
+	}
+
+	fmt.Printf("✅ Demo question seeding completed (%d created, %d already present)\n", created, skipped)
+}
+
+func newDemoQuestion(demo demoQuestion, difficulty models.DifficultyLevel) *models.Question {
+	options := make([]models.Option, len(demo.options))
+	for i, text := range demo.options {
+		options[i] = models.Option{
+			ID:    primitive.NewObjectID().Hex(),
+			Text:  text,
+			Order: i + 1,
+		}
+	}
+
+	return &models.Question{
+		Title:          demo.title,
+		Type:           models.SingleChoice,
+		Difficulty:     difficulty,
+		Points:         10,
+		IsActive:       true,
+		Options:        options,
+		CorrectAnswers: []string{options[demo.correct].ID},
+	}
+}
@@ -1,7 +1,8 @@
 package config
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
@@ -23,50 +24,77 @@ func LoadConfig() models.Config {
 	envLoaded := false
 	for _, path := range envPaths {
 		if err := godotenv.Load(path); err == nil {
-			log.Printf("Loaded environment variables from: %s", path)
+			slog.Info("Loaded environment variables", "path", path)
 			envLoaded = true
 			break
 		}
 	}
 
 	if !envLoaded {
-		log.Println("No .env file found in any expected location, using system environment variables")
+		slog.Info("No .env file found in any expected location, using system environment variables")
 	}
 
+	// Settings from a config file fill in whatever the environment and .env leave unset
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadConfigFile(path); err != nil {
+			slog.Error("Failed to load config file", "path", path, "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Loaded config file", "path", path)
+	}
+
+	invalidValues = nil
+
 	// MongoDB Atlas configuration with fallback support
 	mongoURI := getEnvWithFallback("MONGODB_URI", "MONGO_URI", "")
-	if mongoURI == "" {
-		log.Fatal("MONGODB_URI (or MONGO_URI) environment variable is required for MongoDB Atlas connection")
-	}
 
 	// Validate that it's an Atlas connection string
-	if !strings.Contains(mongoURI, "mongodb+srv://") && !strings.Contains(mongoURI, "mongodb.net") {
-		log.Println("Warning: MongoDB URI doesn't appear to be a MongoDB Atlas connection string")
+	if mongoURI != "" && !strings.Contains(mongoURI, "mongodb+srv://") && !strings.Contains(mongoURI, "mongodb.net") {
+		slog.Warn("MongoDB URI doesn't appear to be a MongoDB Atlas connection string")
+	}
+
+	environment := getEnvWithFallback("SERVER_ENVIRONMENT", "ENVIRONMENT", "development")
+	logFormat := "text"
+	if environment == "production" {
+		logFormat = "json"
+	}
+
+	// The traces endpoint may be given whole or, as OpenTelemetry SDKs allow, as the collector's base URL
+	tracesEndpoint := getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	if base := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""); tracesEndpoint == "" && base != "" {
+		tracesEndpoint = strings.TrimRight(base, "/") + "/v1/traces"
 	}
 
 	config := models.Config{
 		Server: models.ServerConfig{
 			Port:        getEnvWithFallback("SERVER_PORT", "PORT", "8080"),
 			Host:        getEnvWithFallback("SERVER_HOST", "HOST", "localhost"),
-			Environment: getEnvWithFallback("SERVER_ENVIRONMENT", "ENVIRONMENT", "development"),
+			Environment: environment,
 			AllowedOrigins: getEnvArray("ALLOWED_ORIGINS", []string{
 				"http://localhost:5173",
 				"http://127.0.0.1:5173",
 			}),
+			TrustedProxies:  getEnvArray("TRUSTED_PROXIES", nil),
 			ReadTimeout:     getEnvDurationWithFallback("SERVER_READ_TIMEOUT", "READ_TIMEOUT", 30*time.Second),
 			WriteTimeout:    getEnvDurationWithFallback("SERVER_WRITE_TIMEOUT", "WRITE_TIMEOUT", 30*time.Second),
 			ShutdownTimeout: getEnvDurationWithFallback("SERVER_SHUTDOWN_TIMEOUT", "SHUTDOWN_TIMEOUT", 10*time.Second),
+			DrainDelay:      getEnvDuration("SHUTDOWN_DRAIN_DELAY", 0),
+
+			SessionCleanupInterval:  getEnvDuration("SESSION_CLEANUP_INTERVAL", time.Minute),
+			SettingsRefreshInterval: getEnvDuration("SETTINGS_REFRESH_INTERVAL", time.Minute),
 		},
 		Database: models.DatabaseConfig{
-			URI:         mongoURI,
-			Name:        getEnvWithFallback("MONGODB_DATABASE", "MONGO_DB_NAME", "z0nata"),
-			MaxPoolSize: uint64(getEnvIntWithFallback("MONGODB_MAX_POOL_SIZE", "MONGO_MAX_POOL_SIZE", 100)),
+			URI:           mongoURI,
+			Name:          getEnvWithFallback("MONGODB_DATABASE", "MONGO_DB_NAME", "z0nata"),
+			MaxPoolSize:   uint64(getEnvIntWithFallback("MONGODB_MAX_POOL_SIZE", "MONGO_MAX_POOL_SIZE", 100)),
+			EnsureIndexes: getEnvBool("MONGO_ENSURE_INDEXES", true),
 		},
 		JWT: models.JWTConfig{
-			SecretKey:            getEnvRequiredWithFallback("JWT_SECRET", "JWT_SECRET_KEY"),
+			SecretKey:            getEnvWithFallback("JWT_SECRET", "JWT_SECRET_KEY", ""),
 			AccessTokenDuration:  getEnvDurationWithFallback("JWT_ACCESS_TOKEN_EXPIRY", "JWT_ACCESS_DURATION", 2*time.Hour), // Extended to 2 hours for development
 			RefreshTokenDuration: getEnvDurationWithFallback("JWT_REFRESH_TOKEN_EXPIRY", "JWT_REFRESH_DURATION", 168*time.Hour),
 			RememberMeDuration:   getEnvDurationWithFallback("JWT_REFRESH_TOKEN_EXPIRY", "JWT_REMEMBER_DURATION", 168*time.Hour),
+			KeyRefreshInterval:   getEnvDuration("JWT_KEY_REFRESH_INTERVAL", time.Minute),
 		},
 		OAuth: models.OAuthConfig{
 			Google: models.OAuthProvider{
@@ -102,11 +130,142 @@ func LoadConfig() models.Config {
 			FromEmail:    getEnvWithFallback("EMAIL_FROM_ADDRESS", "FROM_EMAIL", "noreply@quizapp.com"),
 			FromName:     getEnvWithFallback("EMAIL_FROM_NAME", "FROM_NAME", "QuizApp Team"),
 		},
+		PasswordPolicy: models.PasswordPolicyConfig{
+			MinLength:          getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			RequireUppercase:   getEnvBool("PASSWORD_REQUIRE_UPPERCASE", true),
+			RequireLowercase:   getEnvBool("PASSWORD_REQUIRE_LOWERCASE", true),
+			RequireDigit:       getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+			RequireSymbol:      getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+			BanCommonPasswords: getEnvBool("PASSWORD_BAN_COMMON", true),
+			HistorySize:        getEnvInt("PASSWORD_HISTORY_SIZE", 5),
+		},
+		TTS: models.TTSConfig{
+			Provider:     getEnv("TTS_PROVIDER", ""),
+			Endpoint:     getEnv("TTS_ENDPOINT", ""),
+			APIKey:       getEnv("TTS_API_KEY", ""),
+			Voice:        getEnv("TTS_VOICE", ""),
+			LanguageCode: getEnv("TTS_LANGUAGE_CODE", "id-ID"),
+			Timeout:      getEnvDuration("TTS_TIMEOUT", 30*time.Second),
+			AutoGenerate: getEnvBool("TTS_AUTO_GENERATE", false),
+		},
+		Captcha: models.CaptchaConfig{
+			Provider:  getEnv("CAPTCHA_PROVIDER", ""),
+			SiteKey:   getEnv("CAPTCHA_SITE_KEY", ""),
+			SecretKey: getEnv("CAPTCHA_SECRET_KEY", ""),
+			MinScore:  getEnvFloat("CAPTCHA_MIN_SCORE", 0.5),
+			Timeout:   getEnvDuration("CAPTCHA_TIMEOUT", 10*time.Second),
+		},
+		AtRisk: models.AtRiskConfig{
+			Interval:         getEnvDuration("AT_RISK_INTERVAL", 24*time.Hour),
+			LookbackDays:     getEnvInt("AT_RISK_LOOKBACK_DAYS", 60),
+			InactiveDays:     getEnvInt("AT_RISK_INACTIVE_DAYS", 14),
+			ScoreDrop:        getEnvFloat("AT_RISK_SCORE_DROP", 15),
+			TimeoutThreshold: getEnvInt("AT_RISK_TIMEOUT_THRESHOLD", 3),
+			NotifyCooldown:   getEnvDuration("AT_RISK_NOTIFY_COOLDOWN", 14*24*time.Hour),
+		},
+		Webhooks: models.WebhookConfig{
+			Timeout:              getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			ReleaseCheckInterval: getEnvDuration("WEBHOOK_RELEASE_CHECK_INTERVAL", time.Minute),
+			AllowHTTP:            getEnvBool("WEBHOOK_ALLOW_HTTP", false),
+		},
+		Embed: models.EmbedConfig{
+			ScriptURL:      getEnv("EMBED_SCRIPT_URL", strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:5173"), "/")+"/embed/widget.js"),
+			FrameAncestors: getEnvArray("EMBED_FRAME_ANCESTORS", []string{}),
+		},
+		Certificates: models.CertificateConfig{
+			PassingScore: getEnvFloat("CERTIFICATE_PASSING_SCORE", 70),
+			SigningKey:   getEnv("CERTIFICATE_SIGNING_KEY", ""),
+			VerifyURL:    getEnv("CERTIFICATE_VERIFY_URL", ""),
+		},
+		Scheduler: models.SchedulerConfig{
+			Enabled:                  getEnvBool("SCHEDULER_ENABLED", true),
+			LeaseTTL:                 getEnvDuration("SCHEDULER_LEASE_TTL", 30*time.Second),
+			Jitter:                   getEnvFloat("SCHEDULER_JITTER", 0.1),
+			HistoryRetention:         getEnvDuration("SCHEDULER_HISTORY_RETENTION", 30*24*time.Hour),
+			UserStatsInterval:        getEnvDuration("USER_STATS_REFRESH_INTERVAL", time.Hour),
+			ActivityLogRetentionDays: getEnvInt("ACTIVITY_LOG_RETENTION_DAYS", 90),
+			ActivityLogInterval:      getEnvDuration("ACTIVITY_LOG_RETENTION_INTERVAL", 24*time.Hour),
+		},
+		Recalibration: models.RecalibrationConfig{
+			Interval:     getEnvDuration("RECALIBRATION_INTERVAL", 7*24*time.Hour),
+			Mode:         getEnv("RECALIBRATION_MODE", models.RecalibrationModeSuggest),
+			LookbackDays: getEnvInt("RECALIBRATION_LOOKBACK_DAYS", 180),
+			MinAttempts:  getEnvInt("RECALIBRATION_MIN_ATTEMPTS", 30),
+			EasyRate:     getEnvFloat("RECALIBRATION_EASY_RATE", 0.75),
+			HardRate:     getEnvFloat("RECALIBRATION_HARD_RATE", 0.4),
+			Margin:       getEnvFloat("RECALIBRATION_MARGIN", 0.05),
+		},
+		Storage: models.StorageConfig{
+			Provider:    getEnv("STORAGE_PROVIDER", "local"),
+			LocalDir:    getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			S3Bucket:    getEnv("STORAGE_S3_BUCKET", ""),
+			S3Region:    getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Endpoint:  getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3AccessKey: getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("STORAGE_S3_SECRET_KEY", ""),
+			Timeout:     getEnvDuration("STORAGE_TIMEOUT", 5*time.Minute),
+
+			MaxAttachmentSize: int64(getEnvInt("ATTACHMENT_MAX_SIZE_MB", 50)) << 20,
+		},
+		Log: models.LogConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", logFormat),
+		},
+		Tracing: models.TracingConfig{
+			Endpoint:    tracesEndpoint,
+			Headers:     getEnv("OTEL_EXPORTER_OTLP_HEADERS", ""),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "z0nata-backend"),
+			Environment: environment,
+			SampleRatio: getEnvFloat("OTEL_TRACES_SAMPLER_ARG", 1),
+			Timeout:     time.Duration(getEnvInt("OTEL_EXPORTER_OTLP_TIMEOUT", 10000)) * time.Millisecond,
+		},
+		RateLimit: models.RateLimitConfig{
+			Store:        getEnv("RATE_LIMIT_STORE", "memory"),
+			RedisURL:     getEnv("RATE_LIMIT_REDIS_URL", ""),
+			RedisTimeout: getEnvDuration("RATE_LIMIT_REDIS_TIMEOUT", 500*time.Millisecond),
+			Policies: map[models.RateLimitPolicyName]models.RateLimitPolicy{
+				models.RateLimitAuth: {
+					Limit:  getEnvInt("RATE_LIMIT_AUTH_LIMIT", 10),
+					Window: getEnvDuration("RATE_LIMIT_AUTH_WINDOW", time.Minute),
+				},
+				models.RateLimitOAuth: {
+					Limit:  getEnvInt("RATE_LIMIT_OAUTH_LIMIT", 20),
+					Window: getEnvDuration("RATE_LIMIT_OAUTH_WINDOW", time.Minute),
+				},
+				models.RateLimitRandomQuestions: {
+					Limit:  getEnvInt("RATE_LIMIT_RANDOM_QUESTIONS_LIMIT", 60),
+					Window: getEnvDuration("RATE_LIMIT_RANDOM_QUESTIONS_WINDOW", time.Minute),
+				},
+			},
+		},
+		Cache: models.CacheConfig{
+			Store:        getEnv("CACHE_STORE", "memory"),
+			RedisURL:     getEnvWithFallback("CACHE_REDIS_URL", "RATE_LIMIT_REDIS_URL", ""),
+			RedisTimeout: getEnvDuration("CACHE_REDIS_TIMEOUT", 500*time.Millisecond),
+			TTL:          getEnvDuration("CACHE_TTL", time.Minute),
+		},
+	}
+
+	// Stop before connecting to anything if any setting is unusable, listing every problem at once
+	problems := append(invalidValues, validationProblems(&config)...)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			slog.Error("Invalid configuration", "problem", problem)
+		}
+		os.Exit(1)
 	}
 
 	return config
 }
 
+// invalidValues collects the environment variables LoadConfig could not parse. A typo such as
+// READ_TIMEOUT=30 would otherwise be replaced by the default without anyone noticing.
+var invalidValues []string
+
+func reportInvalidValue(key, value, kind string) {
+	invalidValues = append(invalidValues, fmt.Sprintf("%s is not a valid %s (got %q)", key, kind, value))
+}
+
 // Helper functions with fallback support for backward compatibility
 func getEnvWithFallback(key, fallbackKey, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -118,29 +277,19 @@ func getEnvWithFallback(key, fallbackKey, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvRequiredWithFallback(key, fallbackKey string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	if value := os.Getenv(fallbackKey); value != "" {
-		return value
-	}
-	log.Fatalf("Required environment variable %s (or %s) is not set", key, fallbackKey)
-	return ""
-}
-
 func getEnvIntWithFallback(key, fallbackKey string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
-		log.Printf("Invalid integer value for %s: %s, using default: %d", key, value, defaultValue)
+		reportInvalidValue(key, value, "integer")
+		return defaultValue
 	}
 	if value := os.Getenv(fallbackKey); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
-		log.Printf("Invalid integer value for %s: %s, using default: %d", fallbackKey, value, defaultValue)
+		reportInvalidValue(fallbackKey, value, "integer")
 	}
 	return defaultValue
 }
@@ -150,13 +299,14 @@ func getEnvDurationWithFallback(key, fallbackKey string, defaultValue time.Durat
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
-		log.Printf("Invalid duration value for %s: %s, using default: %v", key, value, defaultValue)
+		reportInvalidValue(key, value, "duration")
+		return defaultValue
 	}
 	if value := os.Getenv(fallbackKey); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
-		log.Printf("Invalid duration value for %s: %s, using default: %v", fallbackKey, value, defaultValue)
+		reportInvalidValue(fallbackKey, value, "duration")
 	}
 	return defaultValue
 }
@@ -168,27 +318,49 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvRequired(key string) string {
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		reportInvalidValue(key, value, "integer")
+		return defaultValue
+	}
+
+	return intValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
 	value := os.Getenv(key)
 	if value == "" {
-		log.Fatalf("Required environment variable %s is not set", key)
+		return defaultValue
 	}
-	return value
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		reportInvalidValue(key, value, "number")
+		return defaultValue
+	}
+
+	return floatValue
 }
 
-func getEnvInt(key string, defaultValue int) int {
+func getEnvBool(key string, defaultValue bool) bool {
 	value := os.Getenv(key)
 	if value == "" {
 		return defaultValue
 	}
 
-	intValue, err := strconv.Atoi(value)
+	boolValue, err := strconv.ParseBool(value)
 	if err != nil {
-		log.Printf("Invalid integer value for %s: %s, using default: %d", key, value, defaultValue)
+		reportInvalidValue(key, value, "boolean")
 		return defaultValue
 	}
 
-	return intValue
+	return boolValue
 }
 
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
@@ -199,7 +371,7 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 
 	duration, err := time.ParseDuration(value)
 	if err != nil {
-		log.Printf("Invalid duration value for %s: %s, using default: %v", key, value, defaultValue)
+		reportInvalidValue(key, value, "duration")
 		return defaultValue
 	}
 
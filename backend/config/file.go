@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadConfigFile sets environment variables from a JSON config file. Its keys are the variable names
+// env.example lists, optionally grouped into sections of any name, and its values strings, numbers,
+// booleans or arrays for comma separated lists:
+//
+//	{"PORT": 8080, "jwt": {"JWT_ACCESS_DURATION": "15m"}, "ALLOWED_ORIGINS": ["https://quiz.example.com"]}
+//
+// Variables already set to a value, by the environment or a .env file, override the file.
+func loadConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var settings map[string]interface{}
+	if err := decoder.Decode(&settings); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfigFile(settings, values); err != nil {
+		return err
+	}
+	for key, value := range values {
+		if os.Getenv(key) != "" {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// flattenConfigFile collects the file's settings into values, descending into sections
+func flattenConfigFile(settings map[string]interface{}, values map[string]string) error {
+	for key, raw := range settings {
+		if raw == nil {
+			continue
+		}
+		if section, ok := raw.(map[string]interface{}); ok {
+			if err := flattenConfigFile(section, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := configFileValue(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if _, duplicate := values[key]; duplicate {
+			return fmt.Errorf("%s is set more than once", key)
+		}
+		values[key] = value
+	}
+	return nil
+}
+
+func configFileValue(raw interface{}) (string, error) {
+	switch value := raw.(type) {
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			if _, nested := item.([]interface{}); nested {
+				return "", errors.New("lists cannot be nested")
+			}
+			if _, section := item.(map[string]interface{}); section {
+				return "", errors.New("lists can only hold strings, numbers and booleans")
+			}
+			text, err := configFileValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
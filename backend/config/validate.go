@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"backend/models"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var configValidator = newConfigValidator()
+
+// newConfigValidator checks the validate tags of models.Config, naming fields by the environment
+// variable that sets them so problems can be fixed without reading the code
+func newConfigValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name, _, _ := strings.Cut(field.Tag.Get("env"), ","); name != "" {
+			return name
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Validate checks a loaded configuration and reports every problem it finds, not just the first
+func Validate(config *models.Config) error {
+	problems := validationProblems(config)
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+func validationProblems(config *models.Config) []string {
+	err := configValidator.Struct(config)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+
+	problems := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		problems = append(problems, fieldName(fe)+" "+describeFieldError(fe))
+	}
+	return problems
+}
+
+// fieldName is the environment variable behind the field, or its path in the config for settings
+// such as rate limit policies that are not set by a variable of their own
+func fieldName(fe validator.FieldError) string {
+	if name := fe.Field(); name == strings.ToUpper(name) {
+		return name
+	}
+	return strings.TrimPrefix(fe.Namespace(), "Config.")
+}
+
+func describeFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_if":
+		field, value, _ := strings.Cut(fe.Param(), " ")
+		return fmt.Sprintf("is required when the %s is %s", strings.ToLower(field), value)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s (got %v)", fe.Param(), fe.Value())
+	case "gte", "min":
+		return fmt.Sprintf("must be at least %s (got %v)", fe.Param(), fe.Value())
+	case "lt":
+		return fmt.Sprintf("must be less than %s (got %v)", fe.Param(), fe.Value())
+	case "lte", "max":
+		return fmt.Sprintf("must be at most %s (got %v)", fe.Param(), fe.Value())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s (got %q)", strings.ReplaceAll(fe.Param(), " ", ", "), fe.Value())
+	case "numeric":
+		return fmt.Sprintf("must be a number (got %q)", fe.Value())
+	case "ip|cidr":
+		return fmt.Sprintf("must be an IP address or CIDR range (got %q)", fe.Value())
+	default:
+		return fmt.Sprintf("failed the %s check", fe.Tag())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"backend/middleware"
 	"backend/models"
 	"backend/services"
 
@@ -71,10 +72,12 @@ func (c *ActivityLogController) GetActivityLogs(ctx *gin.Context) {
 		}
 	}
 
+	req.Cursor = ctx.Query("cursor")
+
 	// Get activity logs
 	response, err := c.activityLogService.GetActivityLogs(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity logs: " + err.Error()})
+		middleware.RespondServiceFailure(ctx, http.StatusInternalServerError, "Failed to get activity logs: "+err.Error(), err)
 		return
 	}
 
@@ -85,7 +88,7 @@ func (c *ActivityLogController) GetActivityLogs(ctx *gin.Context) {
 func (c *ActivityLogController) GetActivityStats(ctx *gin.Context) {
 	stats, err := c.activityLogService.GetActivityStats(ctx.Request.Context())
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity stats: " + err.Error()})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Failed to get activity stats: "+err.Error())
 		return
 	}
 
@@ -103,7 +106,7 @@ func (c *ActivityLogController) GetRecentActivities(ctx *gin.Context) {
 
 	activities, err := c.activityLogService.GetRecentActivities(ctx.Request.Context(), limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recent activities: " + err.Error()})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Failed to get recent activities: "+err.Error())
 		return
 	}
 
@@ -120,7 +123,7 @@ func (c *ActivityLogController) CleanupOldActivities(ctx *gin.Context) {
 	}
 
 	if err := ctx.ShouldBindJSON(&cleanupRequest); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		middleware.RespondError(ctx, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
@@ -130,7 +133,7 @@ func (c *ActivityLogController) CleanupOldActivities(ctx *gin.Context) {
 
 	deletedCount, err := c.activityLogService.CleanupOldActivities(ctx.Request.Context(), cleanupRequest.RetentionDays)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cleanup old activities: " + err.Error()})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Failed to cleanup old activities: "+err.Error())
 		return
 	}
 
@@ -146,7 +149,7 @@ func (c *ActivityLogController) GetActivityLogByID(ctx *gin.Context) {
 	activityID := ctx.Param("id")
 
 	if activityID == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Activity ID is required"})
+		middleware.RespondError(ctx, http.StatusBadRequest, "Activity ID is required")
 		return
 	}
 
@@ -154,7 +157,7 @@ func (c *ActivityLogController) GetActivityLogByID(ctx *gin.Context) {
 	// In a production system, you might want to add a specific GetByID method
 	activities, err := c.activityLogService.GetRecentActivities(ctx.Request.Context(), 100)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get activity: " + err.Error()})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Failed to get activity: "+err.Error())
 		return
 	}
 
@@ -165,7 +168,7 @@ func (c *ActivityLogController) GetActivityLogByID(ctx *gin.Context) {
 		}
 	}
 
-	ctx.JSON(http.StatusNotFound, gin.H{"error": "Activity not found"})
+	middleware.RespondError(ctx, http.StatusNotFound, "Activity not found")
 }
 
 // GetActivityTypes handles GET /api/admin/activity-logs/types
@@ -191,6 +194,7 @@ func (c *ActivityLogController) GetActivityTypes(ctx *gin.Context) {
 		{"value": string(models.ActivityQuestionDeleted), "label": "Question Deleted"},
 		{"value": string(models.ActivityQuestionActivated), "label": "Question Activated"},
 		{"value": string(models.ActivityQuestionDeactivated), "label": "Question Deactivated"},
+		{"value": string(models.ActivityQuestionReviewed), "label": "Question Reviewed"},
 
 		// User management activities
 		{"value": string(models.ActivityUserAccessGranted), "label": "User Access Granted"},
@@ -198,6 +202,11 @@ func (c *ActivityLogController) GetActivityTypes(ctx *gin.Context) {
 		{"value": string(models.ActivityUserSuspended), "label": "User Suspended"},
 		{"value": string(models.ActivityUserActivated), "label": "User Activated"},
 		{"value": string(models.ActivityUserRoleChanged), "label": "User Role Changed"},
+		{"value": string(models.ActivityUserImpersonated), "label": "User Impersonated"},
+		{"value": string(models.ActivityAccountDeletion), "label": "Account Deletion Requested"},
+		{"value": string(models.ActivityInvitationSent), "label": "Invitation Sent"},
+		{"value": string(models.ActivityInvitationRevoked), "label": "Invitation Revoked"},
+		{"value": string(models.ActivityInvitationAccepted), "label": "Invitation Accepted"},
 
 		// Authentication activities
 		{"value": string(models.ActivityUserLogin), "label": "User Login"},
@@ -207,11 +216,54 @@ func (c *ActivityLogController) GetActivityTypes(ctx *gin.Context) {
 		{"value": string(models.ActivityMahasiswaLogin), "label": "Mahasiswa Login"},
 		{"value": string(models.ActivityExternalLogin), "label": "External Login"},
 
+		// API key activities
+		{"value": string(models.ActivityAPIKeyCreated), "label": "API Key Created"},
+		{"value": string(models.ActivityAPIKeyUpdated), "label": "API Key Updated"},
+		{"value": string(models.ActivityAPIKeyRevoked), "label": "API Key Revoked"},
+		{"value": string(models.ActivityAPIKeyDeleted), "label": "API Key Deleted"},
+		{"value": string(models.ActivityEmbedWidgetCreated), "label": "Embed Widget Created"},
+		{"value": string(models.ActivityEmbedWidgetUpdated), "label": "Embed Widget Updated"},
+		{"value": string(models.ActivityEmbedWidgetRevoked), "label": "Embed Widget Revoked"},
+
+		// Result webhook activities
+		{"value": string(models.ActivityResultWebhookCreated), "label": "Result Webhook Created"},
+		{"value": string(models.ActivityResultWebhookUpdated), "label": "Result Webhook Updated"},
+		{"value": string(models.ActivityResultWebhookDeleted), "label": "Result Webhook Deleted"},
+		{"value": string(models.ActivityResultWebhookSecretRotated), "label": "Result Webhook Secret Rotated"},
+
+		// Changelog activities
+		{"value": string(models.ActivityChangelogCreated), "label": "Changelog Entry Created"},
+		{"value": string(models.ActivityChangelogUpdated), "label": "Changelog Entry Updated"},
+		{"value": string(models.ActivityChangelogPublished), "label": "Changelog Entry Published"},
+		{"value": string(models.ActivityChangelogDeleted), "label": "Changelog Entry Deleted"},
+
+		// Short link activities
+		{"value": string(models.ActivityShortLinkCreated), "label": "Short Link Created"},
+		{"value": string(models.ActivityShortLinkRevoked), "label": "Short Link Revoked"},
+
+		// Question proposal activities
+		{"value": string(models.ActivityQuestionProposalAccepted), "label": "Question Proposal Accepted"},
+		{"value": string(models.ActivityQuestionProposalRejected), "label": "Question Proposal Rejected"},
+
+		// Exam activities
+		{"value": string(models.ActivityScoreModerated), "label": "Score Moderated"},
+		{"value": string(models.ActivityScoresScaled), "label": "Scores Scaled"},
+		{"value": string(models.ActivityEssayGraded), "label": "Essay Graded"},
+
+		// Quiz template activities
+		{"value": string(models.ActivityQuizTemplateCreated), "label": "Quiz Template Created"},
+		{"value": string(models.ActivityQuizTemplateUpdated), "label": "Quiz Template Updated"},
+		{"value": string(models.ActivityQuizTemplateDeleted), "label": "Quiz Template Deleted"},
+
+		// Impersonation activities
+		{"value": string(models.ActivityImpersonatedAction), "label": "Impersonated Action"},
+
 		// System activities
 		{"value": string(models.ActivitySystemMaintenance), "label": "System Maintenance"},
 		{"value": string(models.ActivityBulkOperation), "label": "Bulk Operation"},
 		{"value": string(models.ActivityDataExport), "label": "Data Export"},
 		{"value": string(models.ActivityDataImport), "label": "Data Import"},
+		{"value": string(models.ActivitySettingsUpdated), "label": "Settings Updated"},
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
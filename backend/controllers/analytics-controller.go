@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AnalyticsController struct {
+	analyticsService services.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsService services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		analyticsService: analyticsService,
+	}
+}
+
+// @Summary Compare cohorts (Admin only)
+// @Description Compare score distributions, completion times and performance by question difficulty and type between faculties, majors or academic semesters. Comparisons are cached for 15 minutes
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param group_by query string true "faculty, major or semester"
+// @Param cohorts query string false "Comma-separated cohorts to compare, e.g. 2024/2025-2,2025/2026-1; all when empty"
+// @Param quiz_type query string false "mock_test, time_quiz or practice"
+// @Param date_from query string false "Results submitted on or after (YYYY-MM-DD)"
+// @Param date_to query string false "Results submitted on or before (YYYY-MM-DD)"
+// @Param pass_mark query number false "Score percentage counted as a pass"
+// @Param refresh query bool false "Recompute instead of using a cached comparison"
+// @Success 200 {object} models.CohortComparisonResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/analytics/cohorts/compare [get]
+func (ac *AnalyticsController) CompareCohorts(c *gin.Context) {
+	var req models.CompareCohortsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ac.analyticsService.CompareCohorts(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to compare cohorts", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List at-risk students (Admin only)
+// @Description Students flagged by the latest early-warning analysis with the signals that contributed, highest risk first
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param signal query string false "declining_scores, low_activity or repeated_timeouts"
+// @Param faculty query string false "Only students in this faculty"
+// @Param major query string false "Only students in this major"
+// @Success 200 {object} models.ListAtRiskStudentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/analytics/at-risk [get]
+func (ac *AnalyticsController) ListAtRiskStudents(c *gin.Context) {
+	var req models.ListAtRiskStudentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ac.analyticsService.ListAtRiskStudents(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list at-risk students", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Run at-risk analysis (Admin only)
+// @Description Re-analyse every student now instead of waiting for the schedule. Newly flagged students receive an outreach notification
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AtRiskAnalysisResult
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/analytics/at-risk/run [post]
+func (ac *AnalyticsController) RunAtRiskAnalysis(c *gin.Context) {
+	result, err := ac.analyticsService.AnalyzeAtRisk(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to run at-risk analysis", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Session abandonment analytics (Admin only)
+// @Description How sessions started in the range ended per quiz type, with abandonment broken down by reason (timeout, inactivity, forced, discarded) and how far students got before leaving
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param quiz_type query string false "Only sessions of this quiz type"
+// @Param date_from query string false "Sessions started on or after (YYYY-MM-DD)"
+// @Param date_to query string false "Sessions started on or before (YYYY-MM-DD)"
+// @Success 200 {object} models.AbandonmentStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/analytics/abandonment [get]
+func (ac *AnalyticsController) GetAbandonmentStats(c *gin.Context) {
+	var req models.AbandonmentStatsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ac.analyticsService.GetAbandonmentStats(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get abandonment analytics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Question item analysis (Admin only)
+// @Description Classical item analysis of a question over finished attempts: difficulty index, discrimination index between the top and bottom 27% by total score, how often each option was chosen and average time spent. Indices need at least 20 attempts; flags point out questions worth revising or retiring
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param date_from query string false "Attempts submitted on or after (YYYY-MM-DD)"
+// @Param date_to query string false "Attempts submitted on or before (YYYY-MM-DD)"
+// @Success 200 {object} models.QuestionAnalytics
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/analytics [get]
+func (ac *AnalyticsController) GetQuestionAnalytics(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID")
+		return
+	}
+
+	var req models.QuestionAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	analytics, err := ac.analyticsService.AnalyzeQuestion(c.Request.Context(), questionID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to analyze question", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
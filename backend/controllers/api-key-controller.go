@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type APIKeyController struct {
+	apiKeyService      services.APIKeyService
+	activityLogService services.ActivityLogService
+}
+
+func NewAPIKeyController(apiKeyService services.APIKeyService, activityLogService services.ActivityLogService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService:      apiKeyService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Create API key (Admin only)
+// @Description Issue an API key for a machine integration. The key is returned once and only its hash is stored
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateAPIKeyRequest true "API key settings"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/api-keys [post]
+func (ac *APIKeyController) CreateAPIKey(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	response, err := ac.apiKeyService.CreateAPIKey(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create API key", err)
+		return
+	}
+
+	ac.logAPIKeyActivity(c, models.ActivityAPIKeyCreated, "Created API key", response.APIKey)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary List API keys (Admin only)
+// @Description List API keys with usage tracking, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (active, revoked, expired)"
+// @Success 200 {object} models.ListAPIKeysResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/api-keys [get]
+func (ac *APIKeyController) ListAPIKeys(c *gin.Context) {
+	var req models.ListAPIKeysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ac.apiKeyService.ListAPIKeys(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list API keys", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get API key (Admin only)
+// @Description Get an API key's settings and usage
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/api-keys/{id} [get]
+func (ac *APIKeyController) GetAPIKey(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	apiKey, err := ac.apiKeyService.GetAPIKey(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get API key", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey)
+}
+
+// @Summary Update API key (Admin only)
+// @Description Rename an API key or change its scopes or rate limit
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Param request body models.UpdateAPIKeyRequest true "Changes"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/api-keys/{id} [put]
+func (ac *APIKeyController) UpdateAPIKey(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	apiKey, err := ac.apiKeyService.UpdateAPIKey(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update API key", err)
+		return
+	}
+
+	ac.logAPIKeyActivity(c, models.ActivityAPIKeyUpdated, "Updated API key", apiKey)
+
+	c.JSON(http.StatusOK, apiKey)
+}
+
+// @Summary Revoke API key (Admin only)
+// @Description Permanently disable an API key while keeping its record for auditing
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/api-keys/{id}/revoke [post]
+func (ac *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	apiKey, err := ac.apiKeyService.RevokeAPIKey(c.Request.Context(), adminID, id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to revoke API key", err)
+		return
+	}
+
+	ac.logAPIKeyActivity(c, models.ActivityAPIKeyRevoked, "Revoked API key", apiKey)
+
+	c.JSON(http.StatusOK, apiKey)
+}
+
+// @Summary Delete API key (Admin only)
+// @Description Delete an API key and its usage record
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/api-keys/{id} [delete]
+func (ac *APIKeyController) DeleteAPIKey(c *gin.Context) {
+	id, ok := parseAPIKeyID(c)
+	if !ok {
+		return
+	}
+
+	apiKey, err := ac.apiKeyService.DeleteAPIKey(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete API key", err)
+		return
+	}
+
+	ac.logAPIKeyActivity(c, models.ActivityAPIKeyDeleted, "Deleted API key", apiKey)
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key deleted successfully"})
+}
+
+func parseAPIKeyID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid API key ID")
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+func (ac *APIKeyController) logAPIKeyActivity(c *gin.Context, activityType models.ActivityType, action string, apiKey *models.APIKey) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		"api_key",
+		apiKey.ID.Hex(),
+		apiKey.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("prefix", apiKey.Prefix).
+		SetDetails("scopes", apiKey.Scopes).
+		SetDetails("rate_limit_per_minute", apiKey.RateLimitPerMinute).
+		SetClientInfo(ipAddress, userAgent)
+	ac.activityLogService.LogActivityAsync(activityLog)
+}
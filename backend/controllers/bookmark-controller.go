@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BookmarkController struct {
+	bookmarkService    services.BookmarkService
+	quizSessionService services.QuizSessionService
+}
+
+func NewBookmarkController(bookmarkService services.BookmarkService, quizSessionService services.QuizSessionService) *BookmarkController {
+	return &BookmarkController{
+		bookmarkService:    bookmarkService,
+		quizSessionService: quizSessionService,
+	}
+}
+
+// @Summary Bookmark a question
+// @Description Save a question for later review, optionally linked to the quiz result it came from
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateBookmarkRequest true "Bookmark data"
+// @Success 201 {object} models.Bookmark
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /user/bookmarks [post]
+func (bc *BookmarkController) CreateBookmark(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	bookmark, err := bc.bookmarkService.CreateBookmark(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create bookmark", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, bookmark)
+}
+
+// @Summary List bookmarks
+// @Description Get the authenticated user's bookmarked questions with optional filtering
+// @Tags bookmarks
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param search query string false "Search in question title"
+// @Param type query string false "Filter by question type"
+// @Param difficulty query string false "Filter by difficulty"
+// @Success 200 {object} models.ListBookmarksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /user/bookmarks [get]
+func (bc *BookmarkController) ListBookmarks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ListBookmarksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := bc.bookmarkService.ListBookmarks(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list bookmarks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Remove a bookmark
+// @Description Delete one of the authenticated user's bookmarks
+// @Tags bookmarks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Bookmark ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/bookmarks/{id} [delete]
+func (bc *BookmarkController) DeleteBookmark(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid bookmark ID")
+		return
+	}
+
+	if err := bc.bookmarkService.DeleteBookmark(c.Request.Context(), userID, id); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete bookmark", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark removed successfully"})
+}
+
+// @Summary Start practice from bookmarks
+// @Description Start a practice quiz session using the authenticated user's bookmarked questions
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BookmarkPracticeRequest false "Practice filters"
+// @Success 200 {object} models.StartQuizResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/bookmarks/practice [post]
+func (bc *BookmarkController) StartPractice(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.BookmarkPracticeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondInvalidRequest(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	questions, err := bc.bookmarkService.GetPracticeQuestions(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to load bookmarked questions", err)
+		return
+	}
+
+	response, err := bc.quizSessionService.StartPracticeQuiz(c.Request.Context(), userID, questions)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start practice session", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
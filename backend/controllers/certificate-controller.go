@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CertificateController struct {
+	certificateService services.CertificateService
+}
+
+func NewCertificateController(certificateService services.CertificateService) *CertificateController {
+	return &CertificateController{
+		certificateService: certificateService,
+	}
+}
+
+// @Summary Download a certificate
+// @Description Download the signed PDF certificate of a passed mock test. The certificate is issued into the registry on first download and carries a QR code linking to its public verification page
+// @Tags quiz
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Detailed result ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /quiz/results/{id}/certificate [get]
+func (cc *CertificateController) GetCertificate(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	certificate, err := cc.certificateService.GetCertificate(c.Request.Context(), userID, resultID, requestBaseURL(c)+"/api/v1/certificates/verify")
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to generate certificate", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="certificate-%s.pdf"`, certificate.Certificate.Code))
+	c.Data(http.StatusOK, "application/pdf", certificate.Content)
+}
+
+// @Summary Verify a certificate
+// @Description Look up a certificate by the code printed on it. valid is false when the registry entry no longer matches its signature
+// @Tags certificates
+// @Produce json
+// @Param code path string true "Certificate code"
+// @Success 200 {object} models.CertificateVerification
+// @Failure 404 {object} models.ErrorResponse
+// @Router /certificates/verify/{code} [get]
+func (cc *CertificateController) VerifyCertificate(c *gin.Context) {
+	verification, err := cc.certificateService.VerifyCertificate(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to verify certificate", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
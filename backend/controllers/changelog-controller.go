@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChangelogController struct {
+	changelogService   services.ChangelogService
+	activityLogService services.ActivityLogService
+}
+
+func NewChangelogController(changelogService services.ChangelogService, activityLogService services.ActivityLogService) *ChangelogController {
+	return &ChangelogController{
+		changelogService:   changelogService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Get changelog
+// @Description Get published release notes, newest first, so clients can show what's new after a deployment. Admins see admin notes unless they ask for the student audience; everyone else, signed in or not, sees student notes. Pass since to get only notes published after the client last looked
+// @Tags changelog
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param audience query string false "Audience, admins only" Enums(student, admin)
+// @Param since query string false "Only notes published after this RFC 3339 time"
+// @Success 200 {object} models.ListChangelogResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /changelog [get]
+func (cc *ChangelogController) GetChangelog(c *gin.Context) {
+	var req models.ListChangelogRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	if !middleware.IsAdmin(c) {
+		req.Audience = models.ChangelogForStudents
+	} else if req.Audience == "" {
+		req.Audience = models.ChangelogForAdmins
+	}
+
+	response, err := cc.changelogService.GetChangelog(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get changelog", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Create changelog entry (Admin only)
+// @Description Write release notes for a version, for students, admins or both. The entry is a draft unless publish is set
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateChangelogEntryRequest true "Release notes"
+// @Success 201 {object} models.ChangelogEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/changelog [post]
+func (cc *ChangelogController) CreateEntry(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateChangelogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	entry, err := cc.changelogService.CreateEntry(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create changelog entry", err)
+		return
+	}
+
+	if entry.Published {
+		cc.logChangelogActivity(c, models.ActivityChangelogPublished, "Published changelog entry", entry)
+	} else {
+		cc.logChangelogActivity(c, models.ActivityChangelogCreated, "Created changelog entry", entry)
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary List changelog entries (Admin only)
+// @Description List release notes, drafts included, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param audience query string false "Filter by audience" Enums(student, admin)
+// @Success 200 {object} models.ListChangelogResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/changelog [get]
+func (cc *ChangelogController) ListEntries(c *gin.Context) {
+	var req models.ListChangelogRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := cc.changelogService.ListEntries(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list changelog entries", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get changelog entry (Admin only)
+// @Description Get release notes, published or not
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Entry ID"
+// @Success 200 {object} models.ChangelogEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/changelog/{id} [get]
+func (cc *ChangelogController) GetEntry(c *gin.Context) {
+	id, ok := parseChangelogID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := cc.changelogService.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get changelog entry", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary Update changelog entry (Admin only)
+// @Description Edit release notes, or publish and unpublish them
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Entry ID"
+// @Param request body models.UpdateChangelogEntryRequest true "Changes"
+// @Success 200 {object} models.ChangelogEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/changelog/{id} [put]
+func (cc *ChangelogController) UpdateEntry(c *gin.Context) {
+	id, ok := parseChangelogID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChangelogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	entry, err := cc.changelogService.UpdateEntry(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update changelog entry", err)
+		return
+	}
+
+	if req.Published != nil && *req.Published {
+		cc.logChangelogActivity(c, models.ActivityChangelogPublished, "Published changelog entry", entry)
+	} else {
+		cc.logChangelogActivity(c, models.ActivityChangelogUpdated, "Updated changelog entry", entry)
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary Delete changelog entry (Admin only)
+// @Description Delete release notes
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/changelog/{id} [delete]
+func (cc *ChangelogController) DeleteEntry(c *gin.Context) {
+	id, ok := parseChangelogID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := cc.changelogService.DeleteEntry(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete changelog entry", err)
+		return
+	}
+
+	cc.logChangelogActivity(c, models.ActivityChangelogDeleted, "Deleted changelog entry", entry)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Changelog entry deleted successfully"})
+}
+
+func parseChangelogID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid changelog entry ID")
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+func (cc *ChangelogController) logChangelogActivity(c *gin.Context, activityType models.ActivityType, action string, entry *models.ChangelogEntry) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		"changelog",
+		entry.ID.Hex(),
+		entry.Version,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("title", entry.Title).
+		SetDetails("audiences", entry.Audiences).
+		SetDetails("published", entry.Published).
+		SetClientInfo(ipAddress, userAgent)
+	cc.activityLogService.LogActivityAsync(activityLog)
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ClassQuizController struct {
+	classQuizService services.ClassQuizService
+}
+
+func NewClassQuizController(classQuizService services.ClassQuizService) *ClassQuizController {
+	return &ClassQuizController{
+		classQuizService: classQuizService,
+	}
+}
+
+// @Summary Start class quiz (Admin only)
+// @Description Start an ad-hoc quick quiz for a class. Students join with the returned join code, or by scanning a QR code of join_url, until the join window closes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateClassQuizRequest true "Class quiz data"
+// @Success 201 {object} models.ClassQuiz
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/class-quizzes [post]
+func (qc *ClassQuizController) CreateClassQuiz(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateClassQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	quiz, err := qc.classQuizService.CreateClassQuiz(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start class quiz", err)
+		return
+	}
+	quiz.JoinURL = classQuizJoinURL(quiz.JoinCode)
+
+	c.JSON(http.StatusCreated, quiz)
+}
+
+// @Summary List class quizzes (Admin only)
+// @Description List class quizzes, newest first. Quizzes past their join window are listed as closed
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.ListClassQuizzesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/class-quizzes [get]
+func (qc *ClassQuizController) ListClassQuizzes(c *gin.Context) {
+	var req models.ListClassQuizzesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := qc.classQuizService.ListClassQuizzes(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list class quizzes", err)
+		return
+	}
+
+	for i := range response.Quizzes {
+		response.Quizzes[i].JoinURL = classQuizJoinURL(response.Quizzes[i].JoinCode)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Close class quiz (Admin only)
+// @Description Stop students from joining. Students already answering can still finish
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Class quiz ID"
+// @Success 200 {object} models.ClassQuiz
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/class-quizzes/{id}/close [post]
+func (qc *ClassQuizController) CloseClassQuiz(c *gin.Context) {
+	quizID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid class quiz ID")
+		return
+	}
+
+	quiz, err := qc.classQuizService.CloseClassQuiz(c.Request.Context(), quizID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to close class quiz", err)
+		return
+	}
+	quiz.JoinURL = classQuizJoinURL(quiz.JoinCode)
+
+	c.JSON(http.StatusOK, quiz)
+}
+
+// @Summary Class quiz results board (Admin only)
+// @Description Live results for the classroom projector: students ranked by score once they submit, those still answering, and the share of the class answering each question correctly. Poll it while the quiz runs
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Class quiz ID"
+// @Success 200 {object} models.ClassQuizBoard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/class-quizzes/{id}/board [get]
+func (qc *ClassQuizController) GetBoard(c *gin.Context) {
+	quizID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid class quiz ID")
+		return
+	}
+
+	board, err := qc.classQuizService.GetBoard(c.Request.Context(), quizID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get results board", err)
+		return
+	}
+	board.Quiz.JoinURL = classQuizJoinURL(board.Quiz.JoinCode)
+
+	c.JSON(http.StatusOK, board)
+}
+
+// @Summary Join class quiz
+// @Description Join a class quiz with its code and start answering straight away. Joining again resumes the same session; each student takes a class quiz once
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Join code"
+// @Param request body models.JoinClassQuizRequest true "Name shown on the results board"
+// @Success 200 {object} models.StartQuizResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 410 {object} models.ErrorResponse
+// @Router /quiz/join/{code} [post]
+func (qc *ClassQuizController) JoinClassQuiz(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.JoinClassQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	req.IPAddress, req.UserAgent = services.ExtractClientInfo(c.Request)
+	req.IsGuest = middleware.IsGuest(c)
+
+	response, err := qc.classQuizService.JoinClassQuiz(c.Request.Context(), userID, c.Param("code"), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to join class quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// classQuizJoinURL returns the frontend page students open to join, for the projector's QR code
+func classQuizJoinURL(code string) string {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	return strings.TrimRight(frontendURL, "/") + "/join?code=" + url.QueryEscape(code)
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/graph"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DashboardQueryController struct {
+	server http.Handler
+}
+
+// NewDashboardQueryController serves dashboard queries with server, the GraphQL server generated
+// from graph/schema.graphqls
+func NewDashboardQueryController(server http.Handler) *DashboardQueryController {
+	return &DashboardQueryController{
+		server: server,
+	}
+}
+
+// @Summary Dashboard query
+// @Description Answer a GraphQL query over users, modules, questions, quiz results and activity logs in one request. The schema is graph/schema.graphqls; fields are snake_case, and users, questions, activity logs and their statistics are for admins only
+// @Tags dashboard
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GraphQLRequest true "Query and variables"
+// @Success 200 {object} models.GraphQLResponse
+// @Failure 422 {object} models.GraphQLResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /graphql [post]
+func (dc *DashboardQueryController) Query(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	ctx := graph.WithViewer(c.Request.Context(), graph.Viewer{ID: userID, IsAdmin: middleware.IsAdmin(c)})
+	dc.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"backend/middleware"
 	"backend/models"
 	"backend/repository"
 	"backend/services"
@@ -147,16 +148,12 @@ func (dc *DevController) LoginAdmin(c *gin.Context) {
 
 	admin, err := dc.findFirstAdminUser(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to find admin user: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to find admin user: "+err.Error())
 		return
 	}
 
 	if admin == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No admin user found in database. Please ensure admin users are seeded.",
-		})
+		middleware.RespondError(c, http.StatusNotFound, "No admin user found in database. Please ensure admin users are seeded.")
 		return
 	}
 
@@ -164,9 +161,7 @@ func (dc *DevController) LoginAdmin(c *gin.Context) {
 	// This simulates a successful login without requiring the actual password
 	authResp, err := dc.generateAuthResponse(ctx, &admin.User, admin.ID.Hex(), admin.Email, string(admin.UserType), true)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate tokens: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to generate tokens: "+err.Error())
 		return
 	}
 
@@ -179,25 +174,19 @@ func (dc *DevController) LoginStudent(c *gin.Context) {
 
 	mahasiswa, err := dc.findFirstMahasiswaUser(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to find mahasiswa user: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to find mahasiswa user: "+err.Error())
 		return
 	}
 
 	if mahasiswa == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No mahasiswa user found in database. Please ensure mahasiswa users are seeded.",
-		})
+		middleware.RespondError(c, http.StatusNotFound, "No mahasiswa user found in database. Please ensure mahasiswa users are seeded.")
 		return
 	}
 
 	// For dev mode, we bypass password verification and directly generate tokens
 	authResp, err := dc.generateAuthResponse(ctx, mahasiswa, mahasiswa.ID.Hex(), mahasiswa.Email, string(mahasiswa.UserType), false)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate tokens: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to generate tokens: "+err.Error())
 		return
 	}
 
@@ -210,25 +199,19 @@ func (dc *DevController) LoginUser(c *gin.Context) {
 
 	user, err := dc.findFirstExternalUser(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to find external user: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to find external user: "+err.Error())
 		return
 	}
 
 	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No external user found in database. Please ensure external users are seeded.",
-		})
+		middleware.RespondError(c, http.StatusNotFound, "No external user found in database. Please ensure external users are seeded.")
 		return
 	}
 
 	// For dev mode, we bypass password verification and directly generate tokens
 	authResp, err := dc.generateAuthResponse(ctx, user, user.ID.Hex(), user.Email, string(user.UserType), false)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate tokens: " + err.Error(),
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to generate tokens: "+err.Error())
 		return
 	}
 
@@ -286,11 +269,7 @@ func (dc *DevController) GetDevUsers(c *gin.Context) {
 	}
 
 	if len(users) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "No test users found in database",
-			"message": "Please ensure test users are seeded in the database",
-			"users":   []gin.H{},
-		})
+		middleware.RespondError(c, http.StatusNotFound, "No test users found in database. Please ensure test users are seeded in the database")
 		return
 	}
 
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DifficultyRecalibrationController struct {
+	recalibrationService services.DifficultyRecalibrationService
+	activityLogService   services.ActivityLogService
+}
+
+func NewDifficultyRecalibrationController(recalibrationService services.DifficultyRecalibrationService, activityLogService services.ActivityLogService) *DifficultyRecalibrationController {
+	return &DifficultyRecalibrationController{
+		recalibrationService: recalibrationService,
+		activityLogService:   activityLogService,
+	}
+}
+
+// @Summary Run difficulty recalibration (Admin only)
+// @Description Compare question difficulty labels with observed correct rates now instead of waiting for the schedule. Depending on the configured mode, mislabeled questions are relabeled or queued as suggestions
+// @Tags difficulty-recalibration
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RecalibrationResult
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/difficulty/recalibrate [post]
+func (rc *DifficultyRecalibrationController) RunRecalibration(c *gin.Context) {
+	result, err := rc.recalibrationService.Recalibrate(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to run difficulty recalibration", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary List difficulty suggestions (Admin only)
+// @Description Review queue of difficulty changes suggested from observed correct rates; pending suggestions are listed oldest first
+// @Tags difficulty-recalibration
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Success 200 {object} models.ListDifficultySuggestionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/difficulty-suggestions [get]
+func (rc *DifficultyRecalibrationController) ListSuggestions(c *gin.Context) {
+	var req models.ListDifficultySuggestionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := rc.recalibrationService.ListSuggestions(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list difficulty suggestions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Review a difficulty suggestion (Admin only)
+// @Description Approve a suggestion, relabeling the question, or reject it so the same change is not suggested again
+// @Tags difficulty-recalibration
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Suggestion ID"
+// @Param request body models.ReviewDifficultySuggestionRequest true "Review outcome"
+// @Success 200 {object} models.DifficultySuggestion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/difficulty-suggestions/{id} [put]
+func (rc *DifficultyRecalibrationController) ReviewSuggestion(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	adminEmail, _ := middleware.GetUserEmail(c)
+
+	suggestionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid suggestion ID")
+		return
+	}
+
+	var req models.ReviewDifficultySuggestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	suggestion, err := rc.recalibrationService.ReviewSuggestion(c.Request.Context(), adminID, suggestionID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to review difficulty suggestion", err)
+		return
+	}
+
+	// Approvals are logged against the question they relabeled
+	activityType, action := models.ActivityDifficultySuggestionRejected, "Rejected difficulty suggestion"
+	entityType, entityID := "difficulty_suggestion", suggestion.ID.Hex()
+	if suggestion.Status == models.SuggestionStatusApproved {
+		activityType, action = models.ActivityQuestionDifficultyRecalibrated, "Recalibrated question difficulty"
+		entityType, entityID = "question", suggestion.QuestionID.Hex()
+	}
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		entityType,
+		entityID,
+		suggestion.QuestionTitle,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("suggestion_id", suggestion.ID.Hex()).
+		SetDetails("from", suggestion.CurrentDifficulty).
+		SetDetails("to", suggestion.SuggestedDifficulty).
+		SetDetails("correct_rate", suggestion.CorrectRate).
+		SetDetails("attempts", suggestion.Attempts).
+		SetDetails("mode", models.RecalibrationModeSuggest).
+		SetClientInfo(ipAddress, userAgent)
+	rc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, suggestion)
+}
@@ -0,0 +1,368 @@
+package controllers
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// embedPage is the page served inside the iframe; the widget script renders the quiz into it
+var embedPage = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}}</title>
+</head>
+<body>
+<div id="quiz-widget" data-api="{{.API}}" data-token="{{.Token}}"></div>
+<script src="{{.ScriptURL}}" defer></script>
+</body>
+</html>
+`))
+
+type EmbedWidgetController struct {
+	embedWidgetService services.EmbedWidgetService
+	activityLogService services.ActivityLogService
+	config             models.EmbedConfig
+}
+
+func NewEmbedWidgetController(embedWidgetService services.EmbedWidgetService, activityLogService services.ActivityLogService, config models.EmbedConfig) *EmbedWidgetController {
+	return &EmbedWidgetController{
+		embedWidgetService: embedWidgetService,
+		activityLogService: activityLogService,
+		config:             config,
+	}
+}
+
+// @Summary Create embed widget (Admin only)
+// @Description Create a practice quiz widget that the listed sites can embed in an iframe
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateEmbedWidgetRequest true "Widget settings"
+// @Success 201 {object} models.EmbedWidgetResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/embed-widgets [post]
+func (ec *EmbedWidgetController) CreateWidget(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateEmbedWidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	widget, err := ec.embedWidgetService.CreateWidget(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create embed widget", err)
+		return
+	}
+
+	ec.logEmbedWidgetActivity(c, models.ActivityEmbedWidgetCreated, "Created embed widget", widget)
+
+	c.JSON(http.StatusCreated, ec.widgetResponse(c, widget))
+}
+
+// @Summary List embed widgets (Admin only)
+// @Description List embed widgets with usage counts, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (active, revoked)"
+// @Success 200 {object} models.ListEmbedWidgetsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/embed-widgets [get]
+func (ec *EmbedWidgetController) ListWidgets(c *gin.Context) {
+	var req models.ListEmbedWidgetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ec.embedWidgetService.ListWidgets(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list embed widgets", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get embed widget (Admin only)
+// @Description Get an embed widget's settings, usage and embed code
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Embed widget ID"
+// @Success 200 {object} models.EmbedWidgetResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/embed-widgets/{id} [get]
+func (ec *EmbedWidgetController) GetWidget(c *gin.Context) {
+	id, ok := parseEmbedWidgetID(c)
+	if !ok {
+		return
+	}
+
+	widget, err := ec.embedWidgetService.GetWidget(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get embed widget", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ec.widgetResponse(c, widget))
+}
+
+// @Summary Update embed widget (Admin only)
+// @Description Change an embed widget's name, allowed origins or quiz settings
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Embed widget ID"
+// @Param request body models.UpdateEmbedWidgetRequest true "Changes"
+// @Success 200 {object} models.EmbedWidgetResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/embed-widgets/{id} [put]
+func (ec *EmbedWidgetController) UpdateWidget(c *gin.Context) {
+	id, ok := parseEmbedWidgetID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateEmbedWidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	widget, err := ec.embedWidgetService.UpdateWidget(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update embed widget", err)
+		return
+	}
+
+	ec.logEmbedWidgetActivity(c, models.ActivityEmbedWidgetUpdated, "Updated embed widget", widget)
+
+	c.JSON(http.StatusOK, ec.widgetResponse(c, widget))
+}
+
+// @Summary Revoke embed widget (Admin only)
+// @Description Permanently disable an embed widget; sites embedding it stop loading the quiz
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Embed widget ID"
+// @Success 200 {object} models.EmbedWidget
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/embed-widgets/{id}/revoke [post]
+func (ec *EmbedWidgetController) RevokeWidget(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	id, ok := parseEmbedWidgetID(c)
+	if !ok {
+		return
+	}
+
+	widget, err := ec.embedWidgetService.RevokeWidget(c.Request.Context(), adminID, id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to revoke embed widget", err)
+		return
+	}
+
+	ec.logEmbedWidgetActivity(c, models.ActivityEmbedWidgetRevoked, "Revoked embed widget", widget)
+
+	c.JSON(http.StatusOK, widget)
+}
+
+// @Summary Embed page
+// @Description The page departmental sites load in an iframe. Only the widget's allowed origins and the configured frame ancestors may frame it
+// @Tags embed
+// @Produce html
+// @Param token path string true "Embed token"
+// @Success 200 {string} string "HTML page"
+// @Failure 404 {string} string "Widget not found"
+// @Router /embed/{token} [get]
+func (ec *EmbedWidgetController) ServeEmbedPage(c *gin.Context) {
+	token := c.Param("token")
+	widget, err := ec.embedWidgetService.Authenticate(c.Request.Context(), token)
+	if err != nil {
+		if serviceErr, ok := services.ErrorStatus(err); ok && serviceErr.Status == http.StatusNotFound {
+			c.String(http.StatusNotFound, "This quiz is no longer available")
+			return
+		}
+		c.String(http.StatusInternalServerError, "Failed to load quiz")
+		return
+	}
+
+	c.Header("Content-Security-Policy", "frame-ancestors "+ec.frameAncestors(widget))
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	_ = embedPage.Execute(c.Writer, gin.H{
+		"Name":      widget.Name,
+		"API":       requestBaseURL(c) + "/api/v1/widget/" + widget.Token,
+		"Token":     widget.Token,
+		"ScriptURL": ec.config.ScriptURL,
+	})
+}
+
+// GetConfig tells the embedded widget what quiz it runs
+// GET /api/v1/widget/:token/config
+func (ec *EmbedWidgetController) GetConfig(c *gin.Context) {
+	widget, _ := middleware.GetEmbedWidget(c)
+	c.JSON(http.StatusOK, ec.embedWidgetService.Config(widget))
+}
+
+// StartSession starts an anonymous attempt at the widget's quiz
+// POST /api/v1/widget/:token/sessions
+func (ec *EmbedWidgetController) StartSession(c *gin.Context) {
+	widget, _ := middleware.GetEmbedWidget(c)
+
+	response, err := ec.embedWidgetService.StartSession(c.Request.Context(), widget)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetSession returns a widget attempt's current state
+// GET /api/v1/widget/:token/sessions/:session
+func (ec *EmbedWidgetController) GetSession(c *gin.Context) {
+	widget, _ := middleware.GetEmbedWidget(c)
+
+	response, err := ec.embedWidgetService.GetSession(c.Request.Context(), widget, c.Param("session"))
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to get session", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SaveAnswer saves an answer in a widget attempt
+// POST /api/v1/widget/:token/sessions/:session/answer
+func (ec *EmbedWidgetController) SaveAnswer(c *gin.Context) {
+	widget, _ := middleware.GetEmbedWidget(c)
+
+	var req models.SaveAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+
+	response, err := ec.embedWidgetService.SaveAnswer(c.Request.Context(), widget, c.Param("session"), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to save answer", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SubmitSession scores a widget attempt
+// POST /api/v1/widget/:token/sessions/:session/submit
+func (ec *EmbedWidgetController) SubmitSession(c *gin.Context) {
+	widget, _ := middleware.GetEmbedWidget(c)
+
+	response, err := ec.embedWidgetService.Submit(c.Request.Context(), widget, c.Param("session"))
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to submit quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// frameAncestors lists who may frame a widget: its own origins plus the sites allowed for every widget
+func (ec *EmbedWidgetController) frameAncestors(widget *models.EmbedWidget) string {
+	sources := append([]string{}, widget.AllowedOrigins...)
+	for _, source := range ec.config.FrameAncestors {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		return "'none'"
+	}
+	return strings.Join(sources, " ")
+}
+
+func (ec *EmbedWidgetController) widgetResponse(c *gin.Context, widget *models.EmbedWidget) *models.EmbedWidgetResponse {
+	embedURL := requestBaseURL(c) + "/embed/" + widget.Token
+	return &models.EmbedWidgetResponse{
+		Widget:    widget,
+		EmbedURL:  embedURL,
+		EmbedCode: `<iframe src="` + template.HTMLEscapeString(embedURL) + `" title="` + template.HTMLEscapeString(widget.Name) + `" width="100%" height="640" style="border:0"></iframe>`,
+	}
+}
+
+// requestBaseURL is the scheme and host the request reached the API on
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+func parseEmbedWidgetID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid embed widget ID")
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+func (ec *EmbedWidgetController) logEmbedWidgetActivity(c *gin.Context, activityType models.ActivityType, action string, widget *models.EmbedWidget) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		"embed_widget",
+		widget.ID.Hex(),
+		widget.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("allowed_origins", widget.AllowedOrigins).
+		SetDetails("tags", widget.Tags).
+		SetDetails("question_count", widget.QuestionCount).
+		SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EssayGradingController struct {
+	essayGradingService services.EssayGradingService
+	activityLogService  services.ActivityLogService
+}
+
+func NewEssayGradingController(essayGradingService services.EssayGradingService, activityLogService services.ActivityLogService) *EssayGradingController {
+	return &EssayGradingController{
+		essayGradingService: essayGradingService,
+		activityLogService:  activityLogService,
+	}
+}
+
+// @Summary List essays awaiting a grade (Admin only)
+// @Description Grading queue of answered essays, oldest submission first, with the question's sample answer as a guide
+// @Tags grading
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param quiz_type query string false "Only essays from this quiz type"
+// @Param sitting_id query string false "Only essays from this exam sitting"
+// @Success 200 {object} models.ListPendingGradingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/grading/pending [get]
+func (gc *EssayGradingController) ListPendingEssays(c *gin.Context) {
+	var req models.ListPendingGradingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := gc.essayGradingService.ListPendingEssays(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list pending essays", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Grade an essay answer (Admin only)
+// @Description Score an essay with points or a rubric, plus feedback. Grading a result's last essay recomputes its final score and notifies the student; essays can be regraded
+// @Tags grading
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param resultId path string true "Detailed result ID"
+// @Param index path int true "Zero-based question index"
+// @Param request body models.GradeEssayRequest true "Points or rubric, and feedback"
+// @Success 200 {object} models.GradeEssayResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/grading/results/{resultId}/questions/{index} [put]
+func (gc *EssayGradingController) GradeEssay(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("resultId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid question index")
+		return
+	}
+
+	var req models.GradeEssayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	response, err := gc.essayGradingService.GradeEssay(c.Request.Context(), adminID, adminEmail, resultID, index, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to grade essay", err)
+		return
+	}
+
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityEssayGraded,
+		fmt.Sprintf("Graded essay %d with %d points", index+1, response.Grade.Points),
+		"quiz_result",
+		resultID.Hex(),
+		fmt.Sprintf("Question %d", index+1),
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("question_index", index).
+		SetDetails("points", response.Grade.Points).
+		SetDetails("fully_graded", response.FullyGraded).
+		SetClientInfo(ipAddress, userAgent)
+	gc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,1014 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxOfflineAnswerFileSize limits uploaded offline answer files to 20 MB
+const maxOfflineAnswerFileSize = 20 << 20
+
+type ExamController struct {
+	examService        services.ExamService
+	activityLogService services.ActivityLogService
+}
+
+func NewExamController(examService services.ExamService, activityLogService services.ActivityLogService) *ExamController {
+	return &ExamController{
+		examService:        examService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Create exam sitting (Admin only)
+// @Description Schedule a supervised sitting of a quiz type
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateExamSittingRequest true "Sitting data"
+// @Success 201 {object} models.ExamSitting
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/exams [post]
+func (ec *ExamController) CreateSitting(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateExamSittingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	sitting, err := ec.examService.CreateSitting(c.Request.Context(), adminID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create exam sitting", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sitting)
+}
+
+// @Summary List exam sittings (Admin only)
+// @Description List all exam sittings, most recent first
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/exams [get]
+func (ec *ExamController) ListSittings(c *gin.Context) {
+	sittings, err := ec.examService.ListSittings(c.Request.Context())
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sittings": sittings})
+}
+
+// @Summary Get exam sitting (Admin only)
+// @Description Get an exam sitting with its seat assignments
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Success 200 {object} models.ExamSittingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id} [get]
+func (ec *ExamController) GetSitting(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	response, err := ec.examService.GetSitting(c.Request.Context(), sittingID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Delete exam sitting (Admin only)
+// @Description Delete an exam sitting and its seat assignments; quiz sessions are not affected
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id} [delete]
+func (ec *ExamController) DeleteSitting(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	if err := ec.examService.DeleteSitting(c.Request.Context(), sittingID); err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exam sitting deleted successfully"})
+}
+
+// @Summary Update result release settings (Admin only)
+// @Description Choose when students see their scores: immediately, at a scheduled time, or when released manually. Changing the settings withdraws an earlier manual release
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.UpdateResultReleaseRequest true "Release settings"
+// @Success 200 {object} models.ExamSitting
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/result-release [put]
+func (ec *ExamController) UpdateResultRelease(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.UpdateResultReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	sitting, err := ec.examService.UpdateResultRelease(c.Request.Context(), sittingID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sitting)
+}
+
+// @Summary Release results (Admin only)
+// @Description Publish the sitting's results to students now, once grading and moderation are finished
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param force query bool false "Release even if results are awaiting moderation"
+// @Success 200 {object} models.ExamSitting
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/release-results [post]
+func (ec *ExamController) ReleaseResults(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	force, _ := strconv.ParseBool(c.Query("force"))
+	sitting, err := ec.examService.ReleaseResults(c.Request.Context(), sittingID, force)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sitting)
+}
+
+// @Summary Update moderation settings (Admin only)
+// @Description Set the sitting's pass mark and the band around it whose results are flagged for review. Existing results are flagged or cleared to match
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.UpdateModerationSettingsRequest true "Moderation settings"
+// @Success 200 {object} models.ModerationSettingsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/moderation [put]
+func (ec *ExamController) UpdateModerationSettings(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.UpdateModerationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := ec.examService.UpdateModerationSettings(c.Request.Context(), sittingID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get moderation queue (Admin only)
+// @Description List the sitting's results flagged near the pass mark and those already moderated, lowest score first
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param status query string false "pending or moderated"
+// @Success 200 {object} models.ModerationQueueResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/moderation [get]
+func (ec *ExamController) GetModerationQueue(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var filter models.ModerationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ec.examService.GetModerationQueue(c.Request.Context(), sittingID, filter)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Adjust a result's final score (Admin only)
+// @Description Set the final score of a sitting result before release, with a justification that is kept on the result and in the activity log. Submitting the current score confirms it
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param resultId path string true "Detailed result ID"
+// @Param request body models.AdjustScoreRequest true "New score and justification"
+// @Success 200 {object} models.ModerationQueueItem
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/moderation/{resultId} [put]
+func (ec *ExamController) AdjustScore(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+	resultID, err := primitive.ObjectIDFromHex(c.Param("resultId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	var req models.AdjustScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	item, err := ec.examService.AdjustScore(c.Request.Context(), adminID, adminEmail, sittingID, resultID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	adjustment := item.Moderation.Adjustments[len(item.Moderation.Adjustments)-1]
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityScoreModerated,
+		fmt.Sprintf("Moderated score from %d to %d", adjustment.PreviousScore, adjustment.NewScore),
+		"quiz_result",
+		resultID.Hex(),
+		item.FullName,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("sitting_id", sittingID.Hex()).
+		SetDetails("previous_score", adjustment.PreviousScore).
+		SetDetails("new_score", adjustment.NewScore).
+		SetDetails("justification", adjustment.Justification).
+		SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Preview grade scaling (Admin only)
+// @Description Show the sitting's score distribution before and after a curve without saving it. Linear maps a percentage p to slope*p + intercept; sqrt maps it to 10*sqrt(p). Scaled percentages are clamped to 0-100
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.ScaleScoresRequest true "Scaling function"
+// @Success 200 {object} models.ScalingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/scaling/preview [post]
+func (ec *ExamController) PreviewScaling(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.ScaleScoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := ec.examService.PreviewScaling(c.Request.Context(), sittingID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Apply grade scaling (Admin only)
+// @Description Curve the sitting's final scores from their raw scores, which are kept on each result. Results submitted later are scaled the same way
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.ScaleScoresRequest true "Scaling function"
+// @Success 200 {object} models.ScalingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/scaling [put]
+func (ec *ExamController) ApplyScaling(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.ScaleScoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := ec.examService.ApplyScaling(c.Request.Context(), adminID, sittingID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	ec.logScalingActivity(c, sittingID, response, fmt.Sprintf("Scaled %d results (%s)", len(response.Results), req.Method))
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Remove grade scaling (Admin only)
+// @Description Restore the raw scores of the sitting's results
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Success 200 {object} models.ScalingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/scaling [delete]
+func (ec *ExamController) RemoveScaling(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	response, err := ec.examService.RemoveScaling(c.Request.Context(), sittingID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	ec.logScalingActivity(c, sittingID, response, fmt.Sprintf("Removed scaling from %d results", len(response.Results)))
+	c.JSON(http.StatusOK, response)
+}
+
+func (ec *ExamController) logScalingActivity(c *gin.Context, sittingID primitive.ObjectID, response *models.ScalingResponse, description string) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityScoresScaled,
+		description,
+		"exam_sitting",
+		sittingID.Hex(),
+		"",
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("results", len(response.Results)).
+		SetDetails("mean_before", response.Before.Mean).
+		SetDetails("mean_after", response.After.Mean).
+		SetClientInfo(ipAddress, userAgent)
+	if response.Scaling != nil {
+		activityLog.SetDetails("method", response.Scaling.Method).
+			SetDetails("slope", response.Scaling.Slope).
+			SetDetails("intercept", response.Scaling.Intercept)
+	}
+	ec.activityLogService.LogActivityAsync(activityLog)
+}
+
+// @Summary Update lockdown browser settings (Admin only)
+// @Description Require seated students to start and answer the sitting from a lockdown browser. The signing key is returned only when it is issued
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.UpdateLockdownSettingsRequest true "Lockdown settings"
+// @Success 200 {object} models.LockdownSettingsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/lockdown [put]
+func (ec *ExamController) UpdateLockdownSettings(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.UpdateLockdownSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := ec.examService.UpdateLockdownSettings(c.Request.Context(), sittingID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Assign seats (Admin only)
+// @Description Assign students (by user ID or NIM) to a venue, room and seat; existing assignments are replaced
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param request body models.AssignSeatsRequest true "Seat assignments"
+// @Success 200 {object} models.AssignSeatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/seats [put]
+func (ec *ExamController) AssignSeats(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.AssignSeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := ec.examService.AssignSeats(c.Request.Context(), adminID, sittingID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to assign seats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Remove participant (Admin only)
+// @Description Remove a student's seat assignment from a sitting
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/seats/{userId} [delete]
+func (ec *ExamController) RemoveParticipant(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	if err := ec.examService.RemoveParticipant(c.Request.Context(), sittingID, userID); err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Participant removed successfully"})
+}
+
+// @Summary Attendance report (Admin only)
+// @Description Compare seat assignments with the sessions actually started (including start IPs). Use format=csv to download for the exam office
+// @Tags exams
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param format query string false "Report format (json or csv)" default(json)
+// @Success 200 {object} models.AttendanceReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/attendance [get]
+func (ec *ExamController) GetAttendanceReport(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid format, must be json or csv")
+		return
+	}
+
+	report, err := ec.examService.GetAttendanceReport(c.Request.Context(), sittingID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build attendance report", err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	data, err := buildAttendanceCSV(report)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build attendance report", err)
+		return
+	}
+
+	// Log export for the exam office
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		"Exported exam attendance report",
+		"exam_sitting",
+		sittingID.Hex(),
+		report.Sitting.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("records", len(report.Records)).SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	filename := fmt.Sprintf("attendance-%s-%s.csv", slugify(report.Sitting.Name), report.Sitting.ScheduledStart.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "text/csv", data)
+}
+
+// @Summary Create offline exam package (Admin only)
+// @Description Freeze a question set for a sitting so it can be taken at venues without reliable internet. The returned encryption key is needed by the invigilator client and is shown only once
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Success 201 {object} models.CreateOfflinePackageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/offline-packages [post]
+func (ec *ExamController) CreateOfflinePackage(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	response, err := ec.examService.CreateOfflinePackage(c.Request.Context(), adminID, sittingID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create offline package", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		"Created offline exam package",
+		"exam_sitting",
+		sittingID.Hex(),
+		response.Package.ID.Hex(),
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("questions", response.Package.QuestionCount).SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary List offline exam packages (Admin only)
+// @Description List the offline packages created for a sitting with how many submissions each has ingested
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/offline-packages [get]
+func (ec *ExamController) ListOfflinePackages(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	packages, err := ec.examService.ListOfflinePackages(c.Request.Context(), sittingID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list offline packages", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"packages": packages})
+}
+
+// @Summary Download offline exam package (Admin only)
+// @Description Download the encrypted package file (questions, timing and current seat assignments) for the invigilator client
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param packageId path string true "Package ID"
+// @Success 200 {object} models.EncryptedOfflineFile
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/offline-packages/{packageId}/download [get]
+func (ec *ExamController) DownloadOfflinePackage(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	packageID, err := primitive.ObjectIDFromHex(c.Param("packageId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid package ID")
+		return
+	}
+
+	file, err := ec.examService.GetOfflinePackageFile(c.Request.Context(), sittingID, packageID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build offline package", err)
+		return
+	}
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build offline package", err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		"Downloaded offline exam package",
+		"exam_sitting",
+		sittingID.Hex(),
+		packageID.Hex(),
+		adminID,
+		adminEmail,
+		userType,
+	).SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="exam-package-%s.json"`, packageID.Hex()))
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// @Summary Upload offline answers (Admin only)
+// @Description Upload an encrypted answer file from an invigilator client. Each submission is validated against the package and seat assignments, then scored like an online attempt
+// @Tags exams
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param packageId path string true "Package ID"
+// @Param file formData file true "Encrypted answer file"
+// @Success 200 {object} models.IngestOfflineAnswersResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/offline-packages/{packageId}/answers [post]
+func (ec *ExamController) UploadOfflineAnswers(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	packageID, err := primitive.ObjectIDFromHex(c.Param("packageId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid package ID")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Answer file is required")
+		return
+	}
+
+	if fileHeader.Size > maxOfflineAnswerFileSize {
+		middleware.RespondError(c, http.StatusRequestEntityTooLarge, "Answer file must be 20 MB or smaller")
+		return
+	}
+
+	upload, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Failed to read answer file")
+		return
+	}
+	defer upload.Close()
+
+	var file models.EncryptedOfflineFile
+	if err := json.NewDecoder(upload).Decode(&file); err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Answer file is not a valid offline file")
+		return
+	}
+
+	response, err := ec.examService.IngestOfflineAnswers(c.Request.Context(), sittingID, packageID, &file)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to ingest offline answers", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataImport,
+		"Imported offline exam answers",
+		"exam_sitting",
+		sittingID.Hex(),
+		packageID.Hex(),
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("submissions", response.Submissions).
+		SetDetails("scored", response.Scored).
+		SetDetails("duplicates", response.Duplicates).
+		SetDetails("invalid", response.Invalid).
+		SetDetails("failed", response.Failed).
+		SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary My exam seats
+// @Description Get the authenticated user's exam sittings with venue and seat
+// @Tags exams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Router /user/exams [get]
+func (ec *ExamController) GetMyAssignments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	assignments, err := ec.examService.GetUserAssignments(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// @Summary Print exam papers (Admin only)
+// @Description Render a sitting's question papers, or their answer key, as a PDF for paper-based administration. Each room gets its own shuffle of the same questions, marked with a version code that matches its answer key
+// @Tags exams
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Sitting ID"
+// @Param document query string false "paper or answer_key" default(paper)
+// @Param room query string false "Print only this room's version"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/exams/{id}/print [get]
+func (ec *ExamController) PrintPaper(c *gin.Context) {
+	sittingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid sitting ID")
+		return
+	}
+
+	var req models.PrintExamPaperRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	adminID, _ := middleware.GetUserID(c)
+	paper, err := ec.examService.PrintPaper(c.Request.Context(), adminID, sittingID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to print exam papers", err)
+		return
+	}
+
+	description := "Printed exam papers"
+	prefix := "exam-paper"
+	if paper.Document == models.PaperAnswerKey {
+		description = "Printed exam answer key"
+		prefix = "answer-key"
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		description,
+		"exam_sitting",
+		sittingID.Hex(),
+		paper.Sitting.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("document", paper.Document).
+		SetDetails("versions", paper.Versions).
+		SetClientInfo(ipAddress, userAgent)
+	ec.activityLogService.LogActivityAsync(activityLog)
+
+	filename := fmt.Sprintf("%s-%s-%s.pdf", prefix, slugify(paper.Sitting.Name), paper.Sitting.ScheduledStart.Format("20060102"))
+	if req.Room != "" {
+		filename = fmt.Sprintf("%s-%s-%s-%s.pdf", prefix, slugify(paper.Sitting.Name), slugify(req.Room), paper.Sitting.ScheduledStart.Format("20060102"))
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", paper.Content)
+}
+
+// buildAttendanceCSV renders an attendance report as a CSV sheet, one row per record
+func buildAttendanceCSV(report *models.AttendanceReport) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{
+		"NIM", "Name", "Email", "Venue", "Room", "Seat", "Status",
+		"Started At", "Minutes Late", "Start IP", "Session Status", "Sessions", "Distinct IPs", "Session ID",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, record := range report.Records {
+		startedAt := ""
+		if record.StartedAt != nil {
+			startedAt = record.StartedAt.Format(time.RFC3339)
+		}
+
+		row := []string{
+			record.NIM,
+			record.FullName,
+			record.Email,
+			record.Venue,
+			record.Room,
+			record.Seat,
+			string(record.Status),
+			startedAt,
+			strconv.Itoa(record.MinutesLate),
+			record.StartIP,
+			string(record.SessionStatus),
+			strconv.Itoa(record.SessionCount),
+			strconv.Itoa(record.IPAddressCount),
+			record.SessionID,
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// slugify turns a name into a lowercase, dash-separated string safe for file names
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, ch := range strings.ToLower(name) {
+		if ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' {
+			b.WriteRune(ch)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FlashcardController struct {
+	flashcardService services.FlashcardService
+}
+
+func NewFlashcardController(flashcardService services.FlashcardService) *FlashcardController {
+	return &FlashcardController{
+		flashcardService: flashcardService,
+	}
+}
+
+// @Summary Generate flashcards from a module
+// @Description Generate (or refresh) a deck from the key points of a module's published submodules
+// @Tags flashcards
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Success 200 {object} models.GenerateDeckResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /user/flashcards/decks/module/{moduleId} [post]
+func (fc *FlashcardController) GenerateModuleDeck(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	response, err := fc.flashcardService.GenerateModuleDeck(c.Request.Context(), userID, moduleID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to generate flashcards", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Generate flashcards from missed questions
+// @Description Generate (or refresh) a deck from questions the user answered incorrectly in recent quizzes
+// @Tags flashcards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GenerateMissedDeckRequest false "Generation options"
+// @Success 200 {object} models.GenerateDeckResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/flashcards/decks/missed [post]
+func (fc *FlashcardController) GenerateMissedQuestionsDeck(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.GenerateMissedDeckRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondInvalidRequest(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	response, err := fc.flashcardService.GenerateMissedQuestionsDeck(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to generate flashcards", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List flashcard decks
+// @Description Get the authenticated user's flashcard decks
+// @Tags flashcards
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Router /user/flashcards/decks [get]
+func (fc *FlashcardController) ListDecks(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	decks, err := fc.flashcardService.ListDecks(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"decks": decks})
+}
+
+// @Summary Get deck cards
+// @Description Get all cards of a flashcard deck
+// @Tags flashcards
+// @Produce json
+// @Security BearerAuth
+// @Param deckId path string true "Deck ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/flashcards/decks/{deckId} [get]
+func (fc *FlashcardController) GetDeckCards(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	deckID, err := primitive.ObjectIDFromHex(c.Param("deckId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid deck ID")
+		return
+	}
+
+	cards, err := fc.flashcardService.GetDeckCards(c.Request.Context(), userID, deckID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cards": cards})
+}
+
+// @Summary Delete flashcard deck
+// @Description Delete a flashcard deck and its cards
+// @Tags flashcards
+// @Produce json
+// @Security BearerAuth
+// @Param deckId path string true "Deck ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/flashcards/decks/{deckId} [delete]
+func (fc *FlashcardController) DeleteDeck(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	deckID, err := primitive.ObjectIDFromHex(c.Param("deckId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid deck ID")
+		return
+	}
+
+	if err := fc.flashcardService.DeleteDeck(c.Request.Context(), userID, deckID); err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Flashcard deck deleted successfully"})
+}
+
+// @Summary Get due flashcards
+// @Description Get flashcards due for review, optionally limited to one deck
+// @Tags flashcards
+// @Produce json
+// @Security BearerAuth
+// @Param deck_id query string false "Deck ID"
+// @Param limit query int false "Maximum cards" default(20)
+// @Success 200 {object} models.DueFlashcardsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /user/flashcards/due [get]
+func (fc *FlashcardController) GetDueCards(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var deckID *primitive.ObjectID
+	if deckIDStr := c.Query("deck_id"); deckIDStr != "" {
+		id, err := primitive.ObjectIDFromHex(deckIDStr)
+		if err != nil {
+			middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid deck ID")
+			return
+		}
+		deckID = &id
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	response, err := fc.flashcardService.GetDueCards(c.Request.Context(), userID, deckID, limit)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Review a flashcard
+// @Description Record a review graded 0-5 and reschedule the card
+// @Tags flashcards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cardId path string true "Flashcard ID"
+// @Param request body models.ReviewFlashcardRequest true "Review grade"
+// @Success 200 {object} models.Flashcard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/flashcards/{cardId}/review [post]
+func (fc *FlashcardController) ReviewCard(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cardID, err := primitive.ObjectIDFromHex(c.Param("cardId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid flashcard ID")
+		return
+	}
+
+	var req models.ReviewFlashcardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	card, err := fc.flashcardService.ReviewCard(c.Request.Context(), userID, cardID, *req.Quality)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GuestController struct {
+	guestService    services.GuestService
+	captchaVerifier utils.CaptchaVerifier
+}
+
+func NewGuestController(guestService services.GuestService, captchaVerifier utils.CaptchaVerifier) *GuestController {
+	return &GuestController{
+		guestService:    guestService,
+		captchaVerifier: captchaVerifier,
+	}
+}
+
+// @Summary Start guest session
+// @Description Get an ephemeral guest token for taking a time quiz without an account. Guest tokens are only accepted on quiz routes
+// @Tags auth
+// @Produce json
+// @Param X-Captcha-Token header string false "Captcha widget token (required when captcha is enabled)"
+// @Success 201 {object} models.GuestSessionResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /auth/guest [post]
+func (gc *GuestController) StartGuestSession(c *gin.Context) {
+	if !verifyCaptcha(c, gc.captchaVerifier) {
+		return
+	}
+
+	response, err := gc.guestService.StartGuestSession(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start guest session", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary Claim guest results
+// @Description Attach the quiz sessions and results taken with a guest token to the signed-in account, e.g. right after registering
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ClaimGuestResultsRequest true "Guest token"
+// @Success 200 {object} models.ClaimGuestResultsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /quiz/claim-guest-results [post]
+func (gc *GuestController) ClaimGuestResults(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ClaimGuestResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := gc.guestService.ClaimGuestResults(c.Request.Context(), userID, req.GuestToken)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to claim guest results", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
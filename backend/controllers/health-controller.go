@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	healthService services.HealthService
+}
+
+func NewHealthController(healthService services.HealthService) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+// @Summary Health check
+// @Description Ping the database and report pending background work and the job scheduler. Answers 503 while the database is unreachable
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /health [get]
+func (hc *HealthController) Health(c *gin.Context) {
+	response := hc.healthService.Health(c.Request.Context())
+
+	status := http.StatusOK
+	if response.Status != models.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
+
+// @Summary Readiness check
+// @Description Whether this instance should receive traffic. Answers 503 during startup and shutdown and while the database is unreachable
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse
+// @Router /ready [get]
+func (hc *HealthController) Ready(c *gin.Context) {
+	response := hc.healthService.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
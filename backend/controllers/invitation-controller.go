@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type InvitationController struct {
+	invitationService  services.InvitationService
+	activityLogService services.ActivityLogService
+}
+
+func NewInvitationController(invitationService services.InvitationService, activityLogService services.ActivityLogService) *InvitationController {
+	return &InvitationController{
+		invitationService:  invitationService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Invite a user to register (Admin only)
+// @Description Email a signed, expiring invite link restricted to one user type. Accounts registered from it skip pending approval
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateInvitationRequest true "Invitation data"
+// @Success 201 {object} models.CreateInvitationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/invitations [post]
+func (ic *InvitationController) CreateInvitation(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	// Invite links open the frontend registration page
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	registerURL := strings.TrimRight(frontendURL, "/") + "/register"
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	response, err := ic.invitationService.CreateInvitation(c.Request.Context(), adminID, adminEmail, &req, registerURL)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create invitation", err)
+		return
+	}
+
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityInvitationSent,
+		"Sent registration invitation",
+		"invitation",
+		response.Invitation.ID.Hex(),
+		response.Invitation.Email,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("invited_user_type", response.Invitation.UserType).
+		SetDetails("expires_at", response.Invitation.ExpiresAt).
+		SetDetails("email_sent", response.Invitation.EmailSent).
+		SetClientInfo(ipAddress, userAgent)
+	ic.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary List invitations (Admin only)
+// @Description List registration invitations, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (pending, accepted, revoked, expired)"
+// @Param email query string false "Filter by email"
+// @Success 200 {object} models.ListInvitationsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/invitations [get]
+func (ic *InvitationController) ListInvitations(c *gin.Context) {
+	var req models.ListInvitationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := ic.invitationService.ListInvitations(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list invitations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke invitation (Admin only)
+// @Description Revoke a pending invitation so its link can no longer be used
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} models.Invitation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/invitations/{id} [delete]
+func (ic *InvitationController) RevokeInvitation(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid invitation ID")
+		return
+	}
+
+	invitation, err := ic.invitationService.RevokeInvitation(c.Request.Context(), adminID, invitationID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to revoke invitation", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityInvitationRevoked,
+		"Revoked registration invitation",
+		"invitation",
+		invitation.ID.Hex(),
+		invitation.Email,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("invited_user_type", invitation.UserType).SetClientInfo(ipAddress, userAgent)
+	ic.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+// @Summary Look up invitation
+// @Description Get the email and user type an invite link was issued for, so the registration form can be prefilled
+// @Tags auth
+// @Produce json
+// @Param token query string true "Invite token"
+// @Success 200 {object} models.InvitationLookupResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /auth/invitations/lookup [get]
+func (ic *InvitationController) LookupInvitation(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Invite token is required")
+		return
+	}
+
+	response, err := ic.invitationService.LookupInvitation(c.Request.Context(), token)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to look up invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobSchedulerController struct {
+	jobScheduler services.JobScheduler
+}
+
+func NewJobSchedulerController(jobScheduler services.JobScheduler) *JobSchedulerController {
+	return &JobSchedulerController{
+		jobScheduler: jobScheduler,
+	}
+}
+
+// @Summary List scheduled jobs (Admin only)
+// @Description Background jobs with their interval, when the answering instance runs them next and how their last run went. Only the instance holding the scheduler lease runs jobs
+// @Tags scheduler
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ListScheduledJobsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/jobs [get]
+func (jc *JobSchedulerController) ListJobs(c *gin.Context) {
+	response, err := jc.jobScheduler.ListJobs(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list scheduled jobs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List job runs (Admin only)
+// @Description Run history of the background jobs, newest first
+// @Tags scheduler
+// @Produce json
+// @Security BearerAuth
+// @Param job query string false "Only runs of this job"
+// @Param limit query int false "Number of runs" default(20)
+// @Success 200 {object} models.ListJobRunsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/jobs/runs [get]
+func (jc *JobSchedulerController) ListRuns(c *gin.Context) {
+	var req models.ListJobRunsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := jc.jobScheduler.ListRuns(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list job runs", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
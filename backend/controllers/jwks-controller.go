@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSController struct {
+	signingKeyService services.SigningKeyService
+}
+
+func NewJWKSController(signingKeyService services.SigningKeyService) *JWKSController {
+	return &JWKSController{
+		signingKeyService: signingKeyService,
+	}
+}
+
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens, including recently rotated-out keys
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.JWKSet
+// @Failure 500 {object} models.ErrorResponse
+// @Router /.well-known/jwks.json [get]
+func (jc *JWKSController) GetJWKS(c *gin.Context) {
+	jwks, err := jc.signingKeyService.GetJWKS()
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get signing keys", err)
+		return
+	}
+
+	// Verifiers may cache briefly; rotated-out keys stay listed far longer than this
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwks)
+}
@@ -2,13 +2,20 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 
 	"backend/middleware"
 	"backend/models"
 	"backend/services"
+	"backend/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -80,7 +87,46 @@ func (mc *ModuleController) GetAllModules(c *gin.Context) {
 
 	response, err := mc.moduleService.GetAllModules(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Learners see what they cannot read yet locked; admins read everything
+	if !middleware.IsAdmin(c) {
+		if err := mc.moduleService.ApplyGating(c.Request.Context(), response.Modules, learnerID(c)); err != nil {
+			middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Search modules
+// @Description Search module and submodule names, descriptions and content, ranked by relevance with the matches highlighted. Learners only find published content, and content locked for them by name and description alone
+// @Tags modules
+// @Produce json
+// @Param q query string true "Search text; quote phrases, prefix a word with - to exclude it"
+// @Param published query bool false "Filter by published status (admins only)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Results per page" default(10)
+// @Success 200 {object} models.ModuleSearchResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /modules/search [get]
+func (mc *ModuleController) SearchModules(c *gin.Context) {
+	var req models.ModuleSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Search text is required")
+		return
+	}
+
+	response, err := mc.moduleService.SearchModules(c.Request.Context(), &req, learnerID(c), !middleware.IsAdmin(c))
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to search modules", err)
 		return
 	}
 
@@ -88,30 +134,55 @@ func (mc *ModuleController) GetAllModules(c *gin.Context) {
 }
 
 // @Summary Get module by ID
-// @Description Get a specific module with its submodules
+// @Description Get a specific module with its submodules. Learners are refused a module whose prerequisites they have not completed, and see its locked submodules without content
 // @Tags modules
 // @Produce json
 // @Param id path string true "Module ID"
 // @Success 200 {object} models.Module
-// @Failure 404 {object} map[string]string
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /modules/{id} [get]
 func (mc *ModuleController) GetModuleByID(c *gin.Context) {
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
-	module, err := mc.moduleService.GetModuleByID(c.Request.Context(), moduleID)
+	if middleware.IsAdmin(c) {
+		module, err := mc.moduleService.GetModuleByID(c.Request.Context(), moduleID)
+		if err != nil {
+			middleware.RespondServiceError(c, http.StatusNotFound, err)
+			return
+		}
+		c.JSON(http.StatusOK, module)
+		return
+	}
+
+	module, err := mc.moduleService.GetModuleForLearner(c.Request.Context(), moduleID, learnerID(c))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrModuleLocked) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeContentLocked, "Module is locked", gin.H{
+				"missing_prerequisites": module.MissingPrerequisites,
+			})
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get module", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, module)
 }
 
+// learnerID is the signed-in learner module content is gated for, if any
+func learnerID(c *gin.Context) *primitive.ObjectID {
+	if userID, exists := middleware.GetUserID(c); exists {
+		return &userID
+	}
+	return nil
+}
+
 // @Summary Create new module
 // @Description Create a new module (Admin only)
 // @Tags modules
@@ -120,29 +191,26 @@ func (mc *ModuleController) GetModuleByID(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.CreateModuleRequest true "Module data"
 // @Success 201 {object} models.Module
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/modules [post]
 func (mc *ModuleController) CreateModule(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	var req models.CreateModuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	module, err := mc.moduleService.CreateModule(c.Request.Context(), &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -150,7 +218,6 @@ func (mc *ModuleController) CreateModule(c *gin.Context) {
 	go func() {
 		ctx := context.Background()
 		userName, userType := mc.getUserInfo(c)
-		fmt.Printf("🔄 Attempting to log module creation activity for module %s by user %s (%s)\n", module.Name, userName, userType)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivityModuleCreated,
@@ -165,9 +232,7 @@ func (mc *ModuleController) CreateModule(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log module creation activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Module creation activity logged successfully\n")
+			slog.Error("Failed to log module creation activity", "error", err)
 		}
 	}()
 
@@ -183,37 +248,34 @@ func (mc *ModuleController) CreateModule(c *gin.Context) {
 // @Param id path string true "Module ID"
 // @Param request body models.UpdateModuleRequest true "Module update data"
 // @Success 200 {object} models.Module
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{id} [put]
 func (mc *ModuleController) UpdateModule(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	var req models.UpdateModuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	module, err := mc.moduleService.UpdateModule(c.Request.Context(), moduleID, &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -232,7 +294,6 @@ func (mc *ModuleController) UpdateModule(c *gin.Context) {
 			changes["order"] = *req.Order
 		}
 
-		fmt.Printf("🔄 Attempting to log module update activity for module %s by user %s (%s)\n", module.Name, userName, userType)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivityModuleUpdated,
@@ -244,9 +305,7 @@ func (mc *ModuleController) UpdateModule(c *gin.Context) {
 			changes,
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log module update activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Module update activity logged successfully\n")
+			slog.Error("Failed to log module update activity", "error", err)
 		}
 	}()
 
@@ -260,29 +319,29 @@ func (mc *ModuleController) UpdateModule(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Module ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{id} [delete]
 func (mc *ModuleController) DeleteModule(c *gin.Context) {
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	// Get module info before deletion for logging
 	module, err := mc.moduleService.GetModuleByID(c.Request.Context(), moduleID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
 	err = mc.moduleService.DeleteModule(c.Request.Context(), moduleID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -291,7 +350,6 @@ func (mc *ModuleController) DeleteModule(c *gin.Context) {
 		ctx := context.Background()
 		userID, _ := middleware.GetUserID(c)
 		userName, userType := mc.getUserInfo(c)
-		fmt.Printf("🔄 Attempting to log module deletion activity for module %s\n", module.Name)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivityModuleDeleted,
@@ -305,9 +363,7 @@ func (mc *ModuleController) DeleteModule(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log module deletion activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Module deletion activity logged successfully\n")
+			slog.Error("Failed to log module deletion activity", "error", err)
 		}
 	}()
 
@@ -323,22 +379,22 @@ func (mc *ModuleController) DeleteModule(c *gin.Context) {
 // @Param id path string true "Module ID"
 // @Param request body map[string]bool true "Publication status"
 // @Success 200 {object} models.Module
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{id}/publish [patch]
 func (mc *ModuleController) ToggleModulePublication(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
@@ -346,16 +402,13 @@ func (mc *ModuleController) ToggleModulePublication(c *gin.Context) {
 		Published bool `json:"published"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	module, err := mc.moduleService.ToggleModulePublication(c.Request.Context(), moduleID, req.Published, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -368,7 +421,6 @@ func (mc *ModuleController) ToggleModulePublication(c *gin.Context) {
 			activityType = models.ActivityModuleUnpublished
 		}
 
-		fmt.Printf("🔄 Attempting to log module publication activity for module %s\n", module.Name)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			activityType,
@@ -382,9 +434,7 @@ func (mc *ModuleController) ToggleModulePublication(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log module publication activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Module publication activity logged successfully\n")
+			slog.Error("Failed to log module publication activity", "error", err)
 		}
 	}()
 
@@ -402,36 +452,33 @@ func (mc *ModuleController) ToggleModulePublication(c *gin.Context) {
 // @Param moduleId path string true "Module ID"
 // @Param request body models.CreateSubModuleRequest true "Submodule data"
 // @Success 201 {object} models.SubModule
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/modules/{moduleId}/submodules [post]
 func (mc *ModuleController) CreateSubModule(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	var req models.CreateSubModuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	subModule, err := mc.moduleService.CreateSubModule(c.Request.Context(), moduleID, &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -439,7 +486,6 @@ func (mc *ModuleController) CreateSubModule(c *gin.Context) {
 	go func() {
 		ctx := context.Background()
 		userName, userType := mc.getUserInfo(c)
-		fmt.Printf("🔄 Attempting to log submodule creation activity for %s\n", subModule.Name)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivitySubModuleCreated,
@@ -455,9 +501,7 @@ func (mc *ModuleController) CreateSubModule(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log submodule creation activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Submodule creation activity logged successfully\n")
+			slog.Error("Failed to log submodule creation activity", "error", err)
 		}
 	}()
 
@@ -474,44 +518,41 @@ func (mc *ModuleController) CreateSubModule(c *gin.Context) {
 // @Param submoduleId path string true "Submodule ID"
 // @Param request body models.CreateSubModuleRequest true "Submodule update data"
 // @Success 200 {object} models.SubModule
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{moduleId}/submodules/{submoduleId} [put]
 func (mc *ModuleController) UpdateSubModule(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	subModuleIDStr := c.Param("submoduleId")
 	subModuleID, err := primitive.ObjectIDFromHex(subModuleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submodule ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
 		return
 	}
 
 	var req models.CreateSubModuleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	subModule, err := mc.moduleService.UpdateSubModule(c.Request.Context(), moduleID, subModuleID, &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -519,7 +560,6 @@ func (mc *ModuleController) UpdateSubModule(c *gin.Context) {
 	go func() {
 		ctx := context.Background()
 		userName, userType := mc.getUserInfo(c)
-		fmt.Printf("🔄 Attempting to log submodule update activity for %s\n", subModule.Name)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivitySubModuleUpdated,
@@ -536,9 +576,7 @@ func (mc *ModuleController) UpdateSubModule(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log submodule update activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Submodule update activity logged successfully\n")
+			slog.Error("Failed to log submodule update activity", "error", err)
 		}
 	}()
 
@@ -553,30 +591,30 @@ func (mc *ModuleController) UpdateSubModule(c *gin.Context) {
 // @Param moduleId path string true "Module ID"
 // @Param submoduleId path string true "Submodule ID"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{moduleId}/submodules/{submoduleId} [delete]
 func (mc *ModuleController) DeleteSubModule(c *gin.Context) {
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	subModuleIDStr := c.Param("submoduleId")
 	subModuleID, err := primitive.ObjectIDFromHex(subModuleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submodule ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
 		return
 	}
 
 	// Get module and submodule info before deletion for logging
 	module, err := mc.moduleService.GetModuleByID(c.Request.Context(), moduleID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Module not found"})
+		middleware.RespondError(c, http.StatusNotFound, "Module not found")
 		return
 	}
 
@@ -590,7 +628,7 @@ func (mc *ModuleController) DeleteSubModule(c *gin.Context) {
 
 	err = mc.moduleService.DeleteSubModule(c.Request.Context(), moduleID, subModuleID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -599,7 +637,6 @@ func (mc *ModuleController) DeleteSubModule(c *gin.Context) {
 		ctx := context.Background()
 		userID, _ := middleware.GetUserID(c)
 		userName, userType := mc.getUserInfo(c)
-		fmt.Printf("🔄 Attempting to log submodule deletion activity for %s\n", subModuleName)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			models.ActivitySubModuleDeleted,
@@ -614,15 +651,86 @@ func (mc *ModuleController) DeleteSubModule(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log submodule deletion activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Submodule deletion activity logged successfully\n")
+			slog.Error("Failed to log submodule deletion activity", "error", err)
 		}
 	}()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Submodule deleted successfully"})
 }
 
+// @Summary Move submodule
+// @Description Nest a submodule under another submodule of its module, or with a null parent_id directly in the module; the submodules under it move along (Admin only)
+// @Tags submodules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Param request body models.MoveSubModuleRequest true "New parent and order"
+// @Success 200 {object} models.SubModule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/submodules/{submoduleId}/parent [put]
+func (mc *ModuleController) MoveSubModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleIDStr := c.Param("moduleId")
+	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+
+	var req models.MoveSubModuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	subModule, err := mc.moduleService.MoveSubModule(c.Request.Context(), moduleID, subModuleID, &req, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to move submodule", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		parentID := ""
+		if subModule.ParentID != nil {
+			parentID = subModule.ParentID.Hex()
+		}
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivitySubModuleUpdated,
+			subModule.ID.Hex(),
+			subModule.Name,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"parent_module_id":    moduleIDStr,
+				"parent_submodule_id": parentID,
+				"order":               subModule.Order,
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log submodule move activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, subModule)
+}
+
 // @Summary Publish/Unpublish submodule
 // @Description Toggle submodule publication status (Admin only)
 // @Tags submodules
@@ -633,29 +741,29 @@ func (mc *ModuleController) DeleteSubModule(c *gin.Context) {
 // @Param submoduleId path string true "Submodule ID"
 // @Param request body map[string]bool true "Publication status"
 // @Success 200 {object} models.SubModule
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/modules/{moduleId}/submodules/{submoduleId}/publish [patch]
 func (mc *ModuleController) ToggleSubModulePublication(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
 	subModuleIDStr := c.Param("submoduleId")
 	subModuleID, err := primitive.ObjectIDFromHex(subModuleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submodule ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
 		return
 	}
 
@@ -663,16 +771,13 @@ func (mc *ModuleController) ToggleSubModulePublication(c *gin.Context) {
 		Published bool `json:"published"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	subModule, err := mc.moduleService.ToggleSubModulePublication(c.Request.Context(), moduleID, subModuleID, req.Published, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -685,7 +790,6 @@ func (mc *ModuleController) ToggleSubModulePublication(c *gin.Context) {
 			activityType = models.ActivitySubModuleUnpublished
 		}
 
-		fmt.Printf("🔄 Attempting to log submodule publication activity for %s\n", subModule.Name)
 		err := mc.activityLogService.LogModuleActivity(
 			ctx,
 			activityType,
@@ -700,9 +804,7 @@ func (mc *ModuleController) ToggleSubModulePublication(c *gin.Context) {
 			},
 		)
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log submodule publication activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Submodule publication activity logged successfully\n")
+			slog.Error("Failed to log submodule publication activity", "error", err)
 		}
 	}()
 
@@ -717,14 +819,14 @@ func (mc *ModuleController) ToggleSubModulePublication(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body map[string][]string true "Module order mapping"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/modules/reorder [post]
 func (mc *ModuleController) ReorderModules(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
@@ -732,16 +834,13 @@ func (mc *ModuleController) ReorderModules(c *gin.Context) {
 		ModuleIDs []string `json:"module_ids" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	err := mc.moduleService.ReorderModules(c.Request.Context(), req.ModuleIDs, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -757,21 +856,21 @@ func (mc *ModuleController) ReorderModules(c *gin.Context) {
 // @Param moduleId path string true "Module ID"
 // @Param request body map[string][]string true "Submodule order mapping"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/modules/{moduleId}/submodules/reorder [post]
 func (mc *ModuleController) ReorderSubModules(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	moduleIDStr := c.Param("moduleId")
 	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid module ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
 		return
 	}
 
@@ -779,16 +878,13 @@ func (mc *ModuleController) ReorderSubModules(c *gin.Context) {
 		SubModuleIDs []string `json:"submodule_ids" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	err = mc.moduleService.ReorderSubModules(c.Request.Context(), moduleID, req.SubModuleIDs, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -803,31 +899,1253 @@ func (mc *ModuleController) ReorderSubModules(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.BulkReorderRequest true "Bulk reorder data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/modules/bulk-reorder [post]
 func (mc *ModuleController) BulkReorder(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	var req models.BulkReorderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	err := mc.moduleService.BulkReorder(c.Request.Context(), &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Items reordered successfully"})
 }
+
+// @Summary Atom feed of published content
+// @Description Newly published modules and submodules for portals to syndicate. Requires an API key with the content:read scope
+// @Tags integrations
+// @Produce application/atom+xml
+// @Param X-API-Key header string true "API key"
+// @Param limit query int false "Maximum entries" default(50)
+// @Success 200 {string} string "Atom 1.0 document"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /integrations/feeds/modules.atom [get]
+func (mc *ModuleController) GetModulesAtomFeed(c *gin.Context) {
+	mc.writeContentFeed(c, models.FeedAtom)
+}
+
+// @Summary RSS feed of published content
+// @Description Newly published modules and submodules for portals to syndicate. Requires an API key with the content:read scope
+// @Tags integrations
+// @Produce application/rss+xml
+// @Param X-API-Key header string true "API key"
+// @Param limit query int false "Maximum entries" default(50)
+// @Success 200 {string} string "RSS 2.0 document"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /integrations/feeds/modules.rss [get]
+func (mc *ModuleController) GetModulesRSSFeed(c *gin.Context) {
+	mc.writeContentFeed(c, models.FeedRSS)
+}
+
+func (mc *ModuleController) writeContentFeed(c *gin.Context, format models.FeedFormat) {
+	var req models.ContentFeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	// Entries link to the frontend documentation pages
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	selfURL := scheme + "://" + c.Request.Host + c.Request.URL.Path
+
+	feed, err := mc.moduleService.GetContentFeed(c.Request.Context(), req.Limit, strings.TrimRight(frontendURL, "/"), selfURL)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build content feed", err)
+		return
+	}
+
+	body, contentType, err := utils.RenderFeed(feed, format)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to render content feed", err)
+		return
+	}
+
+	c.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// @Summary Complete a module
+// @Description Record that the signed-in learner finished a module whose prerequisites they have completed
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Success 200 {object} models.ModuleProgress
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/complete [post]
+func (mc *ModuleController) CompleteModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	progress, err := mc.moduleService.CompleteModule(c.Request.Context(), userID, moduleID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to record completion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// @Summary Complete a submodule
+// @Description Record that the signed-in learner finished a submodule they can read; finishing the last published submodule completes the module
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Success 200 {object} models.ModuleProgress
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/submodules/{submoduleId}/complete [post]
+func (mc *ModuleController) CompleteSubModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+
+	progress, err := mc.moduleService.CompleteSubModule(c.Request.Context(), userID, moduleID, subModuleID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to record completion", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// @Summary Unlock a module for a learner
+// @Description Let one learner into a module without completing its prerequisites, or take that back (Admin only)
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param userId path string true "Learner's user ID"
+// @Param request body models.UnlockModuleRequest true "Whether the module is unlocked"
+// @Success 200 {object} models.ModuleProgress
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/unlocks/{userId} [put]
+func (mc *ModuleController) SetModuleUnlock(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleIDStr := c.Param("moduleId")
+	moduleID, err := primitive.ObjectIDFromHex(moduleIDStr)
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	learner, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	var req models.UnlockModuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	progress, err := mc.moduleService.SetModuleUnlocked(c.Request.Context(), adminID, learner, moduleID, *req.Unlocked)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update module unlock", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleUnlocked,
+			moduleIDStr,
+			"",
+			adminID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"user_id":  learner.Hex(),
+				"unlocked": *req.Unlocked,
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log module unlock activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// @Summary List module revisions
+// @Description Every stored revision of a module, newest first, with who made it and the fields it changed (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Success 200 {object} models.ModuleRevisionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/revisions [get]
+func (mc *ModuleController) GetModuleRevisions(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	response, err := mc.moduleService.GetModuleRevisions(c.Request.Context(), moduleID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list module revisions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Diff module revisions
+// @Description The fields that changed from one revision of a module to another, with a line diff of text (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param from query int true "Revision to compare from"
+// @Param to query int false "Revision to compare to; defaults to the current revision"
+// @Success 200 {object} models.ModuleRevisionDiff
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/revisions/diff [get]
+func (mc *ModuleController) DiffModuleRevisions(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var req models.ModuleRevisionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	diff, err := mc.moduleService.DiffModuleRevisions(c.Request.Context(), moduleID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to diff module revisions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary Roll back a module
+// @Description Restore a module's content to an earlier revision, recorded as a new revision. Publication and order are kept (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param revision path int true "Revision to restore"
+// @Success 200 {object} models.Module
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/revisions/{revision}/rollback [post]
+func (mc *ModuleController) RollbackModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid revision")
+		return
+	}
+
+	module, err := mc.moduleService.RollbackModule(c.Request.Context(), moduleID, revision, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to roll back module", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleRolledBack,
+			module.ID.Hex(),
+			module.Name,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{"revision": revision},
+		)
+		if err != nil {
+			slog.Error("Failed to log module rollback activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, module)
+}
+
+// @Summary Upload a module attachment
+// @Description Attach a PDF or slide deck (pdf, ppt, pptx, odp, key) to a module, or to one of its submodules (Admin only)
+// @Tags modules
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param file formData file true "File to attach"
+// @Param submodule_id formData string false "Submodule to attach the file to"
+// @Success 201 {object} models.ModuleAttachment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/attachments [post]
+func (mc *ModuleController) UploadModuleAttachment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var subModuleID *primitive.ObjectID
+	if value := c.PostForm("submodule_id"); value != "" {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+			return
+		}
+		subModuleID = &id
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "File is required")
+		return
+	}
+
+	upload, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer upload.Close()
+
+	attachment, err := mc.moduleService.UploadModuleAttachment(c.Request.Context(), moduleID, subModuleID, fileHeader.Filename, fileHeader.Size, upload, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to upload attachment", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		details := map[string]interface{}{
+			"attachment_id": attachment.ID.Hex(),
+			"file_name":     attachment.FileName,
+			"size":          attachment.Size,
+		}
+		if attachment.SubModuleID != nil {
+			details["submodule_id"] = attachment.SubModuleID.Hex()
+		}
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleAttachmentAdded,
+			moduleID.Hex(),
+			attachment.FileName,
+			userID,
+			userName,
+			userType,
+			details,
+		)
+		if err != nil {
+			slog.Error("Failed to log module attachment activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// @Summary List module attachments
+// @Description Files attached to a module and its submodules. Learners only see those of content they can read; admins see all
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Success 200 {object} models.ModuleAttachmentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/attachments [get]
+func (mc *ModuleController) GetModuleAttachments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var response *models.ModuleAttachmentsResponse
+	if middleware.IsAdmin(c) {
+		response, err = mc.moduleService.GetModuleAttachments(c.Request.Context(), moduleID)
+	} else {
+		response, err = mc.moduleService.GetLearnerAttachments(c.Request.Context(), moduleID, userID)
+	}
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list attachments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Download a module attachment
+// @Description Download a file attached to a module the caller can read; admins can download any attachment
+// @Tags modules
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/attachments/{attachmentId}/download [get]
+func (mc *ModuleController) DownloadModuleAttachment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	attachmentID, err := primitive.ObjectIDFromHex(c.Param("attachmentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid attachment ID")
+		return
+	}
+
+	var attachment *models.ModuleAttachment
+	var file io.ReadCloser
+	if middleware.IsAdmin(c) {
+		attachment, file, err = mc.moduleService.OpenModuleAttachment(c.Request.Context(), moduleID, attachmentID)
+	} else {
+		attachment, file, err = mc.moduleService.OpenLearnerAttachment(c.Request.Context(), moduleID, attachmentID, userID)
+	}
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to download attachment", err)
+		return
+	}
+	defer file.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition": disposition,
+	})
+}
+
+// @Summary Delete a module attachment
+// @Description Remove a file attached to a module, along with the stored file (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/attachments/{attachmentId} [delete]
+func (mc *ModuleController) DeleteModuleAttachment(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	attachmentID, err := primitive.ObjectIDFromHex(c.Param("attachmentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid attachment ID")
+		return
+	}
+
+	attachment, err := mc.moduleService.DeleteModuleAttachment(c.Request.Context(), moduleID, attachmentID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete attachment", err)
+		return
+	}
+
+	go func() {
+		userID, _ := middleware.GetUserID(c)
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleAttachmentDeleted,
+			moduleID.Hex(),
+			attachment.FileName,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{"attachment_id": attachment.ID.Hex()},
+		)
+		if err != nil {
+			slog.Error("Failed to log module attachment activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// @Summary Record a module view
+// @Description Record that the learner read a module page, or one of its submodules, and for how long. Admin previews are not counted
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param request body models.RecordModuleViewRequest true "What was read and for how long"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/views [post]
+func (mc *ModuleController) RecordModuleView(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var req models.RecordModuleViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	if middleware.IsAdmin(c) {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Admin views are not recorded"})
+		return
+	}
+
+	if err := mc.moduleService.RecordModuleView(c.Request.Context(), moduleID, userID, middleware.IsGuest(c), &req); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to record module view", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "View recorded"})
+}
+
+// @Summary Module view analytics
+// @Description Views, unique viewers and reading time of a module over a range of days, the last 30 by default, with views over time and how many viewers each submodule keeps in reading order (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param date_from query string false "First day, YYYY-MM-DD"
+// @Param date_to query string false "Last day, YYYY-MM-DD"
+// @Param interval query string false "Periods of views_over_time: day or week" default(day)
+// @Success 200 {object} models.ModuleAnalytics
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/analytics [get]
+func (mc *ModuleController) GetModuleAnalytics(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var req models.ModuleAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	analytics, err := mc.moduleService.GetModuleAnalytics(c.Request.Context(), moduleID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get module analytics", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// @Summary Open or close a submodule discussion
+// @Description Lets learners who can read a submodule ask questions and reply under it. Closing the discussion hides it from learners without deleting comments (Admin only)
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Param request body object true "{\"enabled\": true}"
+// @Success 200 {object} models.SubModule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/submodules/{submoduleId}/discussion [patch]
+func (mc *ModuleController) SetSubModuleDiscussion(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	subModule, err := mc.moduleService.SetSubModuleDiscussion(c.Request.Context(), moduleID, subModuleID, req.Enabled, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update submodule discussion", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		activityType := models.ActivitySubModuleDiscussionOpened
+		if !req.Enabled {
+			activityType = models.ActivitySubModuleDiscussionClosed
+		}
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			activityType,
+			moduleID.Hex(),
+			subModule.Name,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{"submodule_id": subModuleID.Hex()},
+		)
+		if err != nil {
+			slog.Error("Failed to log submodule discussion activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, subModule)
+}
+
+// @Summary List a submodule discussion
+// @Description Top-level comments of a submodule's discussion, newest first, each with its replies oldest first. Learners need to be able to read the submodule and its discussion to be open; admins also see reports
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Comments per page" default(20)
+// @Success 200 {object} models.ModuleCommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/submodules/{submoduleId}/comments [get]
+func (mc *ModuleController) GetSubModuleComments(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+
+	var req models.ListModuleCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := mc.moduleService.GetSubModuleComments(c.Request.Context(), moduleID, subModuleID, userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list comments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Comment on a submodule
+// @Description Ask a question in a submodule's discussion, or reply to one with parent_id. Everyone already in the thread is notified of a reply
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Param request body models.PostModuleCommentRequest true "Comment"
+// @Success 201 {object} models.ModuleComment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/submodules/{submoduleId}/comments [post]
+func (mc *ModuleController) PostSubModuleComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+
+	var req models.PostModuleCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Comment must not be empty")
+		return
+	}
+
+	comment, err := mc.moduleService.PostSubModuleComment(c.Request.Context(), moduleID, subModuleID, userID, middleware.IsAdmin(c), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to post comment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// @Summary Report a discussion comment
+// @Description Flag an abusive, spam or off-topic comment for an admin to review. Each learner reports a comment once
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param submoduleId path string true "Submodule ID"
+// @Param commentId path string true "Comment ID"
+// @Param request body models.ReportModuleCommentRequest true "Report"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/submodules/{submoduleId}/comments/{commentId}/report [post]
+func (mc *ModuleController) ReportModuleComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	subModuleID, err := primitive.ObjectIDFromHex(c.Param("submoduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid submodule ID")
+		return
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid comment ID")
+		return
+	}
+
+	var req models.ReportModuleCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	if err := mc.moduleService.ReportModuleComment(c.Request.Context(), moduleID, subModuleID, commentID, userID, &req); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to report comment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Comment reported for review"})
+}
+
+// @Summary List reported discussion comments
+// @Description The moderation queue: comments learners reported that are not yet removed, most reported first (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Comments per page" default(20)
+// @Success 200 {object} models.ModuleCommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/modules/comments/reported [get]
+func (mc *ModuleController) GetReportedModuleComments(c *gin.Context) {
+	var req models.ListReportedModuleCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := mc.moduleService.GetReportedModuleComments(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list reported comments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Remove a discussion comment
+// @Description Take a comment down, clearing its body and settling its reports. Replies to it stay (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/comments/{commentId} [delete]
+func (mc *ModuleController) RemoveModuleComment(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid comment ID")
+		return
+	}
+
+	comment, err := mc.moduleService.RemoveModuleComment(c.Request.Context(), moduleID, commentID, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to remove comment", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleCommentRemoved,
+			moduleID.Hex(),
+			comment.AuthorName,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"comment_id":   comment.ID.Hex(),
+				"submodule_id": comment.SubModuleID.Hex(),
+				"author_id":    comment.AuthorID.Hex(),
+				"report_count": comment.ReportCount,
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log comment removal activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment removed successfully"})
+}
+
+// @Summary Dismiss reports of a discussion comment
+// @Description Keep a reported comment and clear its reports from the moderation queue (Admin only)
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/comments/{commentId}/reports [delete]
+func (mc *ModuleController) DismissModuleCommentReports(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid comment ID")
+		return
+	}
+
+	if err := mc.moduleService.DismissModuleCommentReports(c.Request.Context(), moduleID, commentID); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to dismiss comment reports", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment reports dismissed"})
+}
+
+// @Summary Import a module from a markdown bundle
+// @Description Create a draft module and its submodules from a zip of markdown pages with front-matter (title, description, order) and the images they show. index.md at the root is the module; a folder's index.md is a submodule with the folder's pages nested under it. Nothing is created unless the whole bundle is valid; a dry run reports the outcome without creating anything (Admin only)
+// @Tags modules
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Zip of markdown pages and images"
+// @Param dry_run formData bool false "Check the bundle without creating the module"
+// @Success 201 {object} models.ImportModuleResponse
+// @Success 200 {object} models.ImportModuleResponse "Dry run"
+// @Failure 400 {object} models.ImportModuleResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /admin/modules/import [post]
+func (mc *ModuleController) ImportModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ImportModuleRequest
+	if err := c.ShouldBind(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Module bundle is required")
+		return
+	}
+
+	bundle, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Failed to read module bundle")
+		return
+	}
+	defer bundle.Close()
+
+	response, err := mc.moduleService.ImportModule(c.Request.Context(), bundle, fileHeader.Size, &req, userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to import module", err)
+		return
+	}
+	if len(response.Problems) > 0 {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if response.DryRun {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleImported,
+			response.Module.ID.Hex(),
+			response.Module.Name,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"file_name":  fileHeader.Filename,
+				"submodules": len(response.Module.SubModules),
+				"images":     response.Images,
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log module import activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary Get a module image
+// @Description An image shown in module content, uploaded with it. Public, as content links to it from image tags
+// @Tags modules
+// @Produce image/png,image/jpeg,image/gif,image/webp
+// @Param moduleId path string true "Module ID"
+// @Param imageId path string true "Image ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/images/{imageId} [get]
+func (mc *ModuleController) GetModuleImage(c *gin.Context) {
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+	imageID, err := primitive.ObjectIDFromHex(c.Param("imageId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid image ID")
+		return
+	}
+
+	image, file, err := mc.moduleService.OpenModuleImage(c.Request.Context(), moduleID, imageID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get image", err)
+		return
+	}
+	defer file.Close()
+
+	// An image never changes under its ID
+	c.DataFromReader(http.StatusOK, image.Size, image.ContentType, file, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// @Summary Export a module for an LMS
+// @Description Download a published module and its published submodules as a SCORM 1.2 or xAPI (Tin Can) zip package, to load into an LMS such as Moodle. Each page reports completion to the LMS when opened (Admin only)
+// @Tags modules
+// @Produce application/zip
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param format query string false "Package format" Enums(scorm12, xapi) default(scorm12)
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/modules/{moduleId}/export [get]
+func (mc *ModuleController) ExportModule(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var req models.ExportModuleRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	// Pages are identified by their documentation links, and other links into the API are made absolute
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	pkg, err := mc.moduleService.GetModulePackage(c.Request.Context(), moduleID, strings.TrimRight(frontendURL, "/"), scheme+"://"+c.Request.Host)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to export module", err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.zip", slugify(pkg.Title), req.Format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", "application/zip")
+	if err := utils.WriteLearningPackage(c.Writer, pkg, req.Format); err != nil {
+		// Once the zip has started the status is sent; the download just ends short
+		if c.Writer.Written() {
+			slog.WarnContext(c.Request.Context(), "Module export ended early", "module_id", moduleID.Hex(), "error", err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to export module", err)
+		return
+	}
+
+	go func() {
+		userName, userType := mc.getUserInfo(c)
+		err := mc.activityLogService.LogModuleActivity(
+			context.Background(),
+			models.ActivityModuleExported,
+			moduleID.Hex(),
+			pkg.Title,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"format": req.Format,
+				"pages":  len(pkg.Pages),
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log module export activity", "error", err)
+		}
+	}()
+}
+
+// @Summary Save the reading position
+// @Description Store where the learner is in a module, or one of its submodules, so they can continue from there later. Replaces the previous position in the module
+// @Tags modules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param moduleId path string true "Module ID"
+// @Param request body models.SaveReadingPositionRequest true "Page, content block and scroll position"
+// @Success 200 {object} models.ReadingPosition
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /modules/{moduleId}/position [put]
+func (mc *ModuleController) SaveReadingPosition(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	moduleID, err := primitive.ObjectIDFromHex(c.Param("moduleId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid module ID")
+		return
+	}
+
+	var req models.SaveReadingPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	position, err := mc.moduleService.SaveReadingPosition(c.Request.Context(), userID, moduleID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to save reading position", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// @Summary Continue reading
+// @Description Modules the learner started and has not completed, most recently read first, with where they left off and about how many minutes of reading are left
+// @Tags modules
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of modules" default(5)
+// @Success 200 {object} models.ContinueReadingResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /modules/continue [get]
+func (mc *ModuleController) GetContinueReading(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ContinueReadingRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := mc.moduleService.GetContinueReading(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get modules to continue", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PracticeController struct {
+	practiceService services.PracticeService
+}
+
+func NewPracticeController(practiceService services.PracticeService) *PracticeController {
+	return &PracticeController{
+		practiceService: practiceService,
+	}
+}
+
+// @Summary Get questions due for review
+// @Description Get the questions the spaced-repetition schedule says are due, most overdue first. Schedules follow the student's quiz answers and practice reviews
+// @Tags practice
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum questions" default(20)
+// @Success 200 {object} models.DuePracticeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /practice/due [get]
+func (pc *PracticeController) GetDueQuestions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.DuePracticeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := pc.practiceService.GetDueQuestions(c.Request.Context(), userID, req.Limit)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Record a practice review
+// @Description Record how a review of one question went outside a quiz session, as a 0-5 grade or whether it was answered correctly, and reschedule the question
+// @Tags practice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.RecordPracticeReviewRequest true "Review outcome"
+// @Success 200 {object} models.QuestionMemory
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /practice/reviews [post]
+func (pc *PracticeController) RecordReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.RecordPracticeReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	memory, err := pc.practiceService.RecordReview(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, memory)
+}
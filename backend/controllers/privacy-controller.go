@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrivacyController struct {
+	privacyService     services.PrivacyService
+	activityLogService services.ActivityLogService
+}
+
+func NewPrivacyController(privacyService services.PrivacyService, activityLogService services.ActivityLogService) *PrivacyController {
+	return &PrivacyController{
+		privacyService:     privacyService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Export personal data
+// @Description Download everything stored about the authenticated user as a JSON file, or a ZIP with one JSON file per section
+// @Tags privacy
+// @Produce json
+// @Produce application/zip
+// @Security BearerAuth
+// @Param format query string false "Export format (json or zip)" default(json)
+// @Success 200 {object} models.UserDataExport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/data-export [post]
+func (pc *PrivacyController) ExportData(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid format, must be json or zip")
+		return
+	}
+
+	export, err := pc.privacyService.ExportUserData(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to export user data", err)
+		return
+	}
+
+	var data []byte
+	contentType := "application/json"
+	if format == "zip" {
+		data, err = buildExportArchive(export)
+		contentType = "application/zip"
+	} else {
+		data, err = json.MarshalIndent(export, "", "  ")
+	}
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to build export file", err)
+		return
+	}
+
+	// Log data export
+	userEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		"Exported personal data",
+		"user",
+		userID.Hex(),
+		userEmail,
+		userID,
+		userEmail,
+		userType,
+	).SetDetails("format", format).SetClientInfo(ipAddress, userAgent)
+	pc.activityLogService.LogActivityAsync(activityLog)
+
+	filename := fmt.Sprintf("z0nata-data-export-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// @Summary Delete account
+// @Description Schedule the authenticated user's account for deletion. Logging in during the grace period cancels it; afterwards personal data is removed and quiz results are kept anonymized
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DeleteAccountRequest false "Password confirmation"
+// @Success 200 {object} models.AccountDeletionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /user/account [delete]
+func (pc *PrivacyController) DeleteAccount(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Admins deleting an account on the user's behalf must not do it through impersonation
+	if _, impersonating := middleware.GetImpersonatedBy(c); impersonating {
+		middleware.RespondError(c, http.StatusForbidden, "Account deletion is not allowed while impersonating")
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondInvalidRequest(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	response, err := pc.privacyService.RequestAccountDeletion(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete account", err)
+		return
+	}
+
+	// Log deletion request
+	userEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityAccountDeletion,
+		"Requested account deletion",
+		"user",
+		userID.Hex(),
+		userEmail,
+		userID,
+		userEmail,
+		userType,
+	).SetDetails("deletion_scheduled_at", response.DeletionScheduledAt).
+		SetDetails("reason", req.Reason).
+		SetClientInfo(ipAddress, userAgent)
+	pc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Purge deleted accounts (Admin only)
+// @Description Permanently remove accounts whose deletion grace period has ended, anonymizing their quiz results
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PurgeDeletedAccountsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/users/purge-deleted [post]
+func (pc *PrivacyController) PurgeDeletedAccounts(c *gin.Context) {
+	response, err := pc.privacyService.PurgeDeletedAccounts(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorWithDetails(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to purge deleted accounts", gin.H{
+			"reason": err.Error(),
+			"result": response,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// buildExportArchive writes each export section to its own JSON file inside a zip archive
+func buildExportArchive(export *models.UserDataExport) ([]byte, error) {
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"quiz_results.json", export.QuizResults},
+		{"detailed_results.json", export.DetailedResults},
+		{"quiz_sessions.json", export.QuizSessions},
+		{"stats.json", export.Stats},
+		{"achievements.json", export.Achievements},
+		{"bookmarks.json", export.Bookmarks},
+		{"flashcard_decks.json", export.FlashcardDecks},
+		{"activity_logs.json", export.ActivityLogs},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range sections {
+		file, err := archive.Create(section.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
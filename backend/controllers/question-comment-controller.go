@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionCommentController struct {
+	questionCommentService services.QuestionCommentService
+}
+
+func NewQuestionCommentController(questionCommentService services.QuestionCommentService) *QuestionCommentController {
+	return &QuestionCommentController{
+		questionCommentService: questionCommentService,
+	}
+}
+
+// @Summary List question comments
+// @Description Get the reviewers' discussion on a question, oldest comment first, with the number still unresolved (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param resolved query bool false "Only resolved or only unresolved comments"
+// @Success 200 {object} models.QuestionCommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/comments [get]
+func (qcc *QuestionCommentController) ListComments(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	var req models.ListQuestionCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := qcc.questionCommentService.ListComments(c.Request.Context(), questionID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list comments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Comment on a question
+// @Description Add a comment to a question's discussion. Mentioned admins are notified (Admin only)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param request body models.PostQuestionCommentRequest true "Comment"
+// @Success 201 {object} models.QuestionComment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/comments [post]
+func (qcc *QuestionCommentController) PostComment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	var req models.PostQuestionCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	comment, err := qcc.questionCommentService.PostComment(c.Request.Context(), adminID, questionID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to post comment", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// @Summary Resolve question comment
+// @Description Mark a comment on a question resolved, or reopen it (Admin only)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param commentId path string true "Comment ID"
+// @Param request body models.ResolveQuestionCommentRequest true "Resolved state"
+// @Success 200 {object} models.QuestionComment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/comments/{commentId} [patch]
+func (qcc *QuestionCommentController) ResolveComment(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid comment ID format")
+		return
+	}
+
+	var req models.ResolveQuestionCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	comment, err := qcc.questionCommentService.ResolveComment(c.Request.Context(), adminID, questionID, commentID, *req.Resolved)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update comment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
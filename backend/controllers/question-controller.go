@@ -2,27 +2,33 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"backend/middleware"
 	"backend/models"
 	"backend/services"
+	"backend/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type QuestionController struct {
-	questionService    services.QuestionService
-	activityLogService services.ActivityLogService
+	questionService      services.QuestionService
+	questionAudioService services.QuestionAudioService
+	activityLogService   services.ActivityLogService
 }
 
-func NewQuestionController(questionService services.QuestionService, activityLogService services.ActivityLogService) *QuestionController {
+func NewQuestionController(questionService services.QuestionService, questionAudioService services.QuestionAudioService, activityLogService services.ActivityLogService) *QuestionController {
 	return &QuestionController{
-		questionService:    questionService,
-		activityLogService: activityLogService,
+		questionService:      questionService,
+		questionAudioService: questionAudioService,
+		activityLogService:   activityLogService,
 	}
 }
 
@@ -56,42 +62,37 @@ func (qc *QuestionController) getUserInfo(c *gin.Context) (string, string) {
 // @Security BearerAuth
 // @Param request body models.CreateQuestionRequest true "Question data"
 // @Success 201 {object} models.Question
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/questions [post]
 func (qc *QuestionController) CreateQuestion(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	var req models.CreateQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	question, err := qc.questionService.CreateQuestion(c.Request.Context(), &req, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	qc.questionAudioService.QueueGeneration(question.ID)
+
 	// Log question creation activity
 	go func() {
 		// Use background context instead of request context which might be cancelled
 		ctx := context.Background()
 		userName, userType := qc.getUserInfo(c)
 
-		fmt.Printf("🔄 Attempting to log question creation activity...\n")
-		fmt.Printf("📝 Question: %s (ID: %s)\n", question.Title, question.ID.Hex())
-		fmt.Printf("👤 User: %s (%s)\n", userName, userType)
-
 		err := qc.activityLogService.LogQuestionActivity(
 			ctx,
 			models.ActivityQuestionCreated,
@@ -108,9 +109,7 @@ func (qc *QuestionController) CreateQuestion(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log question creation activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Question creation activity logged successfully\n")
+			slog.Error("Failed to log question creation activity", "error", err)
 		}
 	}()
 
@@ -124,21 +123,21 @@ func (qc *QuestionController) CreateQuestion(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Question ID"
 // @Success 200 {object} models.Question
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /admin/questions/{id} [get]
 func (qc *QuestionController) GetQuestion(c *gin.Context) {
 	idParam := c.Param("id")
 	questionID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
 		return
 	}
 
 	question, err := qc.questionService.GetQuestion(c.Request.Context(), questionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -154,37 +153,35 @@ func (qc *QuestionController) GetQuestion(c *gin.Context) {
 // @Param id path string true "Question ID"
 // @Param request body models.UpdateQuestionRequest true "Question updates"
 // @Success 200 {object} models.Question
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Router /admin/questions/{id} [put]
 func (qc *QuestionController) UpdateQuestion(c *gin.Context) {
 	idParam := c.Param("id")
 	questionID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
 		return
 	}
 
 	var req models.UpdateQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
-	question, err := qc.questionService.UpdateQuestion(c.Request.Context(), questionID, &req)
+	updatedBy, _ := middleware.GetUserID(c)
+	question, err := qc.questionService.UpdateQuestion(c.Request.Context(), questionID, &req, updatedBy)
 	if err != nil {
-		if err.Error() == "question not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		}
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	// Audio is only regenerated when the spoken text actually changed
+	qc.questionAudioService.QueueGeneration(question.ID)
+
 	// Log question update activity
 	go func() {
 		ctx := context.Background()
@@ -204,7 +201,6 @@ func (qc *QuestionController) UpdateQuestion(c *gin.Context) {
 			changes["is_active"] = *req.IsActive
 		}
 
-		fmt.Printf("🔄 Attempting to log question update activity...\n")
 		err := qc.activityLogService.LogQuestionActivity(
 			ctx,
 			models.ActivityQuestionUpdated,
@@ -217,65 +213,93 @@ func (qc *QuestionController) UpdateQuestion(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log question update activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Question update activity logged successfully\n")
+			slog.Error("Failed to log question update activity", "error", err)
 		}
 	}()
 
 	c.JSON(http.StatusOK, question)
 }
 
+// @Summary List question versions
+// @Description Every stored version of a question, newest first, with the fields each edit changed, who made it, and how many quiz sessions were built from it (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Success 200 {object} models.QuestionVersionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/versions [get]
+func (qc *QuestionController) GetQuestionVersions(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	response, err := qc.questionService.GetQuestionVersions(c.Request.Context(), questionID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list question versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // @Summary Delete question
-// @Description Delete a question (Admin only)
+// @Description Archive a question so it leaves the bank but stays available to the sessions that used it, or delete it for good with permanent=true. Questions used in quiz sessions or results cannot be permanently deleted (Admin only)
 // @Tags questions
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Question ID"
+// @Param permanent query bool false "Delete for good instead of archiving"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Router /admin/questions/{id} [delete]
 func (qc *QuestionController) DeleteQuestion(c *gin.Context) {
 	idParam := c.Param("id")
 	questionID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
 		return
 	}
 
 	// Get question info before deletion for logging
 	question, err := qc.questionService.GetQuestion(c.Request.Context(), questionID)
 	if err != nil {
-		if err.Error() == "question not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get question"})
-		}
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to get question", err)
 		return
 	}
 
-	err = qc.questionService.DeleteQuestion(c.Request.Context(), questionID)
+	permanent, _ := strconv.ParseBool(c.Query("permanent"))
+	deletedBy, _ := middleware.GetUserID(c)
+	err = qc.questionService.DeleteQuestion(c.Request.Context(), questionID, deletedBy, permanent)
 	if err != nil {
-		if err.Error() == "question not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete question"})
-		}
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to delete question", err)
 		return
 	}
 
+	// Archived questions keep their audio in case they are restored
+	activityType, message := models.ActivityQuestionArchived, "Question archived successfully"
+	if permanent {
+		activityType, message = models.ActivityQuestionDeleted, "Question deleted successfully"
+		if err := qc.questionAudioService.DeleteAudio(c.Request.Context(), questionID); err != nil {
+			slog.Error("Failed to delete question audio", "question_id", questionID.Hex(), "error", err)
+		}
+	}
+
 	// Log question deletion activity
 	go func() {
 		ctx := context.Background()
 		userID, _ := middleware.GetUserID(c)
 		userName, userType := qc.getUserInfo(c)
 
-		fmt.Printf("🔄 Attempting to log question deletion activity...\n")
 		err := qc.activityLogService.LogQuestionActivity(
 			ctx,
-			models.ActivityQuestionDeleted,
+			activityType,
 			question.ID.Hex(),
 			question.Title,
 			userID,
@@ -289,29 +313,75 @@ func (qc *QuestionController) DeleteQuestion(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log question deletion activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Question deletion activity logged successfully\n")
+			slog.Error("Failed to log question deletion activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// @Summary Restore question
+// @Description Return an archived question to the bank, active again if it was when archived (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Success 200 {object} models.Question
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/restore [post]
+func (qc *QuestionController) RestoreQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	question, err := qc.questionService.RestoreQuestion(c.Request.Context(), questionID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to restore question", err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	userName, userType := qc.getUserInfo(c)
+	go func() {
+		if err := qc.activityLogService.LogQuestionActivity(
+			context.Background(),
+			models.ActivityQuestionRestored,
+			question.ID.Hex(),
+			question.Title,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{"is_active": question.IsActive},
+		); err != nil {
+			slog.Error("Failed to log question restore activity", "error", err)
 		}
 	}()
 
-	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+	c.JSON(http.StatusOK, question)
 }
 
 // @Summary List questions
-// @Description Get a paginated list of questions with filtering (Admin only)
+// @Description Get a paginated list of questions with filtering. Searches are ranked by relevance, with the matches highlighted (Admin only)
 // @Tags questions
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
-// @Param search query string false "Search in question titles"
-// @Param type query string false "Filter by question type" Enums(single_choice, multiple_choice, essay)
+// @Param search query string false "Search titles, options and explanations; quote phrases, prefix a word with - to exclude it"
+// @Param type query string false "Filter by question type" Enums(single_choice, multiple_choice, essay, fill_in_blank, numeric, ordering)
 // @Param difficulty query string false "Filter by difficulty" Enums(easy, medium, hard)
 // @Param is_active query bool false "Filter by active status"
+// @Param archived query bool false "List archived questions instead of the bank"
+// @Param tag query string false "Filter by tag"
+// @Param review_status query string false "Filter by publishing workflow stage" Enums(draft, in_review, approved)
+// @Param cursor query string false "Continue after the next_cursor of an earlier page instead of paging by number; not with search"
 // @Success 200 {object} models.ListQuestionsResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Router /admin/questions [get]
 func (qc *QuestionController) ListQuestions(c *gin.Context) {
 	// Parse query parameters with defaults
@@ -354,34 +424,393 @@ func (qc *QuestionController) ListQuestions(c *gin.Context) {
 		}
 	}
 
+	// Handle archived filter
+	req.Archived, _ = strconv.ParseBool(c.Query("archived"))
+
+	// Handle tag filter
+	req.Tag = c.Query("tag")
+
+	// Handle author and reviewer filters
+	req.AuthorID = c.Query("author_id")
+	req.ReviewerID = c.Query("reviewer_id")
+
+	// Handle review status filter
+	req.ReviewStatus = models.QuestionReviewStatus(c.Query("review_status"))
+
+	req.Cursor = c.Query("cursor")
+
 	response, err := qc.questionService.ListQuestions(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list questions"})
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to list questions", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// questionExportContentTypes maps each export format to its content type
+var questionExportContentTypes = map[string]string{
+	"csv":  "text/csv",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"json": "application/json",
+}
+
+// @Summary Export questions
+// @Description Download every question matching the list filters as CSV, XLSX or JSON, streamed as it is read. Spreadsheets use the import column format. Answer keys, sample answers and explanations can be left out for sharing with external reviewers (Admin only)
+// @Tags questions
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(csv, xlsx, json) default(csv)
+// @Param exclude_answers query bool false "Leave out answers and explanations"
+// @Param search query string false "Search titles, options and explanations; quote phrases, prefix a word with - to exclude it"
+// @Param type query string false "Filter by question type" Enums(single_choice, multiple_choice, essay, fill_in_blank, numeric, ordering)
+// @Param difficulty query string false "Filter by difficulty" Enums(easy, medium, hard)
+// @Param is_active query bool false "Filter by active status"
+// @Param archived query bool false "Export archived questions instead of the bank"
+// @Param tag query string false "Filter by tag"
+// @Param review_status query string false "Filter by publishing workflow stage" Enums(draft, in_review, approved)
+// @Param author_id query string false "Filter by author"
+// @Param reviewer_id query string false "Filter by reviewer"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /admin/questions/export [get]
+func (qc *QuestionController) ExportQuestions(c *gin.Context) {
+	var req models.ExportQuestionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	filename := fmt.Sprintf("questions-%s.%s", time.Now().Format("20060102-150405"), req.Format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Content-Type", questionExportContentTypes[req.Format])
+
+	count, err := qc.questionService.ExportQuestions(c.Request.Context(), &req, c.Writer)
+	if err != nil {
+		// Once rows have gone out the status is sent; the download just ends short
+		if c.Writer.Written() {
+			slog.WarnContext(c.Request.Context(), "Question export ended early", "questions", count, "error", err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to export questions", err)
+		return
+	}
+
+	// Log export
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataExport,
+		"Exported questions",
+		"question",
+		"",
+		filename,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("format", req.Format).
+		SetDetails("questions", count).
+		SetDetails("exclude_answers", req.ExcludeAnswers).
+		SetDetails("archived", req.Archived).
+		SetClientInfo(ipAddress, userAgent)
+	qc.activityLogService.LogActivityAsync(activityLog)
+}
+
 // @Summary Get question statistics
 // @Description Get statistics about questions (Admin only)
 // @Tags questions
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} models.QuestionStatsResponse
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /admin/questions/stats [get]
 func (qc *QuestionController) GetQuestionStats(c *gin.Context) {
 	stats, err := qc.questionService.GetQuestionStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get question statistics"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to get question statistics")
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
 
+// @Summary Get accessibility report
+// @Description List questions with images missing alt text, for remediation (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param is_active query bool false "Filter by active status"
+// @Success 200 {object} models.AccessibilityReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/questions/accessibility-report [get]
+func (qc *QuestionController) GetAccessibilityReport(c *gin.Context) {
+	var req models.AccessibilityReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	report, err := qc.questionService.GetAccessibilityReport(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get accessibility report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Get authoring report
+// @Description Count the questions each admin or student wrote and reviewed, to verify workload claims (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param date_from query string false "Start date (YYYY-MM-DD)"
+// @Param date_to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} models.AuthoringReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/questions/authoring-report [get]
+func (qc *QuestionController) GetAuthoringReport(c *gin.Context) {
+	var req models.AuthoringReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	report, err := qc.questionService.GetAuthoringReport(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get authoring report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Record question review
+// @Description Credit the current admin with reviewing a question (Admin only)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param request body models.RecordQuestionReviewRequest true "Review note"
+// @Success 200 {object} models.Question
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/reviews [post]
+func (qc *QuestionController) RecordReview(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	var req models.RecordQuestionReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	question, err := qc.questionService.RecordReview(c.Request.Context(), questionID, userID, &req)
+	if err != nil {
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to record review", err)
+		return
+	}
+
+	// Log question review activity
+	go func() {
+		userName, userType := qc.getUserInfo(c)
+		err := qc.activityLogService.LogQuestionActivity(
+			context.Background(),
+			models.ActivityQuestionReviewed,
+			question.ID.Hex(),
+			question.Title,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"note": req.Note,
+			},
+		)
+		if err != nil {
+			slog.Error("Failed to log question review activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Submit question for review
+// @Description Send a draft question to the reviewers; it reaches quizzes once approved (Admin only)
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Success 200 {object} models.Question
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/submit [post]
+func (qc *QuestionController) SubmitQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	question, err := qc.questionService.SubmitQuestion(c.Request.Context(), questionID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to submit question for review", err)
+		return
+	}
+
+	qc.logReviewActivity(c, models.ActivityQuestionSubmitted, question, userID, "")
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Approve question
+// @Description Publish a question submitted for review, crediting the reviewer. Content authors cannot approve, nor can anyone approve their own question (Admin only)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param request body models.ApproveQuestionRequest false "Review note"
+// @Success 200 {object} models.Question
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/approve [post]
+func (qc *QuestionController) ApproveQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	// The note is optional, so an empty body is accepted
+	var req models.ApproveQuestionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondInvalidRequest(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	question, err := qc.questionService.ApproveQuestion(c.Request.Context(), questionID, userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to approve question", err)
+		return
+	}
+
+	qc.logReviewActivity(c, models.ActivityQuestionApproved, question, userID, req.Note)
+	c.JSON(http.StatusOK, question)
+}
+
+// @Summary Reject question
+// @Description Return a question in review to its author as a draft, with a note on what to change (Admin only)
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param request body models.RejectQuestionRequest true "Review note"
+// @Success 200 {object} models.Question
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/reject [post]
+func (qc *QuestionController) RejectQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	var req models.RejectQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	question, err := qc.questionService.RejectQuestion(c.Request.Context(), questionID, userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to reject question", err)
+		return
+	}
+
+	qc.logReviewActivity(c, models.ActivityQuestionRejected, question, userID, req.Note)
+	c.JSON(http.StatusOK, question)
+}
+
+// reviewWorkflowError maps publishing workflow errors to responses
+
+func (qc *QuestionController) logReviewActivity(c *gin.Context, activityType models.ActivityType, question *models.Question, userID primitive.ObjectID, note string) {
+	userName, userType := qc.getUserInfo(c)
+	go func() {
+		if err := qc.activityLogService.LogQuestionActivity(
+			context.Background(),
+			activityType,
+			question.ID.Hex(),
+			question.Title,
+			userID,
+			userName,
+			userType,
+			map[string]interface{}{
+				"review_status": question.ReviewStatus,
+				"note":          note,
+			},
+		); err != nil {
+			slog.Error("Failed to log question review activity", "error", err)
+		}
+	}()
+}
+
 // @Summary Toggle question status
 // @Description Enable or disable a question (Admin only)
 // @Tags questions
@@ -391,15 +820,16 @@ func (qc *QuestionController) GetQuestionStats(c *gin.Context) {
 // @Param id path string true "Question ID"
 // @Param request body map[string]bool true "Status update" example({"is_active": true})
 // @Success 200 {object} models.Question
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Router /admin/questions/{id}/status [patch]
 func (qc *QuestionController) ToggleQuestionStatus(c *gin.Context) {
 	idParam := c.Param("id")
 	questionID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
 		return
 	}
 
@@ -408,20 +838,13 @@ func (qc *QuestionController) ToggleQuestionStatus(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	question, err := qc.questionService.ToggleQuestionStatus(c.Request.Context(), questionID, req.IsActive)
 	if err != nil {
-		if err.Error() == "question not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update question status"})
-		}
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to update question status", err)
 		return
 	}
 
@@ -455,16 +878,16 @@ func (qc *QuestionController) ToggleQuestionStatus(c *gin.Context) {
 // @Description Get random questions for quiz generation (Public for quiz taking)
 // @Tags questions
 // @Produce json
-// @Param type query string true "Question type" Enums(single_choice, multiple_choice, essay)
+// @Param type query string true "Question type" Enums(single_choice, multiple_choice, essay, fill_in_blank, numeric, ordering)
 // @Param limit query int false "Number of questions" default(10)
 // @Success 200 {array} models.QuestionForQuiz
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /questions/random [get]
 func (qc *QuestionController) GetRandomQuestions(c *gin.Context) {
 	typeParam := c.Query("type")
 	if typeParam == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Question type is required"})
+		middleware.RespondError(c, http.StatusBadRequest, "Question type is required")
 		return
 	}
 
@@ -479,16 +902,16 @@ func (qc *QuestionController) GetRandomQuestions(c *gin.Context) {
 
 	// Validate question type
 	switch questionType {
-	case models.SingleChoice, models.MultipleChoice, models.Essay:
+	case models.SingleChoice, models.MultipleChoice, models.Essay, models.FillInBlank, models.Numeric, models.Ordering:
 		// Valid types
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question type"})
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid question type")
 		return
 	}
 
 	questions, err := qc.questionService.GetRandomQuestions(c.Request.Context(), questionType, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get random questions"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to get random questions")
 		return
 	}
 
@@ -519,24 +942,18 @@ func (qc *QuestionController) GetRandomQuestions(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.CreateQuestionRequest true "Question data to validate"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Router /admin/questions/validate [post]
 func (qc *QuestionController) ValidateQuestion(c *gin.Context) {
 	var req models.CreateQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	if err := qc.questionService.ValidateQuestionData(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"valid": false,
-			"error": err.Error(),
-		})
+		middleware.RespondErrorWithDetails(c, http.StatusBadRequest, models.ErrorCodeValidation, err.Error(), gin.H{"valid": false})
 		return
 	}
 
@@ -545,3 +962,101 @@ func (qc *QuestionController) ValidateQuestion(c *gin.Context) {
 		"message": "Question data is valid",
 	})
 }
+
+// @Summary Generate question audio (Admin only)
+// @Description Synthesize a text-to-speech rendition of the question and its options. Skipped when the text hasn't changed unless force is set
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Question ID"
+// @Param force query bool false "Regenerate even if the audio is up to date"
+// @Success 200 {object} models.QuestionAudio
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /admin/questions/{id}/audio [post]
+func (qc *QuestionController) GenerateQuestionAudio(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	force, _ := strconv.ParseBool(c.Query("force"))
+	audio, err := qc.questionAudioService.GenerateAudio(c.Request.Context(), questionID, force)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to generate audio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audio)
+}
+
+// @Summary Generate audio for many questions (Admin only)
+// @Description Synthesize text-to-speech audio for active questions that have none or whose text changed since
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.GenerateAudioBatchRequest false "Batch options"
+// @Success 200 {object} models.GenerateAudioBatchResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /admin/questions/audio/generate [post]
+func (qc *QuestionController) GenerateQuestionAudioBatch(c *gin.Context) {
+	var req models.GenerateAudioBatchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.RespondInvalidRequest(c, "Invalid request data", err)
+			return
+		}
+	}
+
+	response, err := qc.questionAudioService.GenerateBatch(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrTTSDisabled) {
+			middleware.RespondServiceError(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to generate audio", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get question audio
+// @Description Stream the text-to-speech rendition of a question
+// @Tags questions
+// @Produce audio/mpeg
+// @Param id path string true "Question ID"
+// @Success 200 {file} binary
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /questions/{id}/audio [get]
+func (qc *QuestionController) GetQuestionAudio(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid question ID format")
+		return
+	}
+
+	file, err := qc.questionAudioService.GetAudioFile(c.Request.Context(), questionID)
+	if err != nil {
+		middleware.RespondServiceFailure(c, http.StatusInternalServerError, "Failed to get audio", err)
+		return
+	}
+
+	etag := `"` + file.TextHash + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionImportController struct {
+	questionImportService services.QuestionImportService
+	activityLogService    services.ActivityLogService
+}
+
+func NewQuestionImportController(questionImportService services.QuestionImportService, activityLogService services.ActivityLogService) *QuestionImportController {
+	return &QuestionImportController{
+		questionImportService: questionImportService,
+		activityLogService:    activityLogService,
+	}
+}
+
+// @Summary Bulk import questions (Admin only)
+// @Description Create questions from a CSV or XLSX file with columns title, type, difficulty, points and optional options, correct_answers, sample_answer, explanation, blanks, numeric_answer, tolerance, unit, ordering_scoring and tags (list values separated by "|"), or from a JSON array of question objects. Each row is validated and checked against existing titles; a dry run reports the outcome without creating anything
+// @Tags questions
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV, XLSX or JSON file"
+// @Param dry_run formData bool false "Validate without creating questions"
+// @Success 200 {object} models.ImportQuestionsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /admin/questions/import [post]
+func (qic *QuestionImportController) ImportQuestions(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ImportQuestionsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		middleware.RespondError(c, http.StatusRequestEntityTooLarge, "Import file must be 5 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+
+	response, err := qic.questionImportService.ImportQuestions(c.Request.Context(), fileHeader.Filename, file, &req, adminID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to import questions", err)
+		return
+	}
+
+	// Log the import report, dry runs included
+	action := "Imported questions"
+	if response.DryRun {
+		action = "Checked question import (dry run)"
+	}
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataImport,
+		action,
+		"question",
+		"",
+		fileHeader.Filename,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("format", strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")).
+		SetDetails("dry_run", response.DryRun).
+		SetDetails("total_rows", response.TotalRows).
+		SetDetails("created", response.Created).
+		SetDetails("valid", response.Valid).
+		SetDetails("duplicates", response.Duplicates).
+		SetDetails("invalid", response.Invalid).
+		SetDetails("failed", response.Failed).
+		SetDetails("results", response.Results).
+		SetClientInfo(ipAddress, userAgent)
+	qic.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionProposalController struct {
+	questionProposalService services.QuestionProposalService
+	questionAudioService    services.QuestionAudioService
+	activityLogService      services.ActivityLogService
+}
+
+func NewQuestionProposalController(questionProposalService services.QuestionProposalService, questionAudioService services.QuestionAudioService, activityLogService services.ActivityLogService) *QuestionProposalController {
+	return &QuestionProposalController{
+		questionProposalService: questionProposalService,
+		questionAudioService:    questionAudioService,
+		activityLogService:      activityLogService,
+	}
+}
+
+// @Summary Propose a question
+// @Description Submit a question for the bank. It is reviewed by an admin; accepted questions credit the student and award XP
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ProposeQuestionRequest true "Proposed question"
+// @Success 201 {object} models.QuestionProposal
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /questions/propose [post]
+func (pc *QuestionProposalController) ProposeQuestion(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	email, _ := middleware.GetUserEmail(c)
+
+	var req models.ProposeQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	proposal, err := pc.questionProposalService.ProposeQuestion(c.Request.Context(), userID, email, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to propose question", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+// @Summary List my proposed questions
+// @Description List the questions the caller proposed with their review status, newest first
+// @Tags questions
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (pending, accepted, rejected)"
+// @Success 200 {object} models.ListQuestionProposalsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /questions/proposals [get]
+func (pc *QuestionProposalController) ListMyProposals(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ListQuestionProposalsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := pc.questionProposalService.ListMyProposals(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list question proposals", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List question proposals (Admin only)
+// @Description Review queue of student-proposed questions; pending proposals are listed oldest first
+// @Tags question-proposals
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (pending, accepted, rejected)"
+// @Success 200 {object} models.ListQuestionProposalsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/question-proposals [get]
+func (pc *QuestionProposalController) ListProposals(c *gin.Context) {
+	var req models.ListQuestionProposalsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := pc.questionProposalService.ListProposals(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list question proposals", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get a question proposal (Admin only)
+// @Tags question-proposals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Proposal ID"
+// @Success 200 {object} models.QuestionProposal
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/question-proposals/{id} [get]
+func (pc *QuestionProposalController) GetProposal(c *gin.Context) {
+	proposalID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid proposal ID")
+		return
+	}
+
+	proposal, err := pc.questionProposalService.GetProposal(c.Request.Context(), proposalID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get question proposal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, proposal)
+}
+
+// @Summary Review a question proposal (Admin only)
+// @Description Accept a proposal into the question bank, optionally adjusting difficulty and points, or reject it. The student is notified either way
+// @Tags question-proposals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Proposal ID"
+// @Param request body models.ReviewQuestionProposalRequest true "Review outcome"
+// @Success 200 {object} models.QuestionProposal
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/question-proposals/{id} [put]
+func (pc *QuestionProposalController) ReviewProposal(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	adminEmail, _ := middleware.GetUserEmail(c)
+
+	proposalID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid proposal ID")
+		return
+	}
+
+	var req models.ReviewQuestionProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	proposal, err := pc.questionProposalService.ReviewProposal(c.Request.Context(), adminID, proposalID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to review question proposal", err)
+		return
+	}
+
+	if proposal.QuestionID != nil {
+		pc.questionAudioService.QueueGeneration(*proposal.QuestionID)
+	}
+
+	activityType, action := models.ActivityQuestionProposalRejected, "Rejected question proposal"
+	if proposal.Status == models.ProposalStatusAccepted {
+		activityType, action = models.ActivityQuestionProposalAccepted, "Accepted question proposal"
+	}
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		"question_proposal",
+		proposal.ID.Hex(),
+		proposal.Question.Title,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("proposed_by", proposal.ProposedBy.Hex()).
+		SetDetails("question_id", proposal.QuestionID).
+		SetClientInfo(ipAddress, userAgent)
+	pc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, proposal)
+}
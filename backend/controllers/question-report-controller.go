@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionReportController struct {
+	questionReportService services.QuestionReportService
+}
+
+func NewQuestionReportController(questionReportService services.QuestionReportService) *QuestionReportController {
+	return &QuestionReportController{
+		questionReportService: questionReportService,
+	}
+}
+
+// @Summary Report a question
+// @Description Flag an ambiguous or broken question of the caller's quiz session for admin review
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Session token"
+// @Param index path int true "Zero-based question index"
+// @Param request body models.FlagQuestionRequest true "Report details"
+// @Success 201 {object} models.FlagQuestionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /quiz/session/{token}/questions/{index}/flag [post]
+func (qc *QuestionReportController) FlagQuestion(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid question index")
+		return
+	}
+
+	var req models.FlagQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	response, err := qc.questionReportService.FlagQuestion(c.Request.Context(), userID, middleware.IsGuest(c), c.Param("token"), index, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to report question", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary List question reports (Admin only)
+// @Description Review queue of questions students reported during quizzes, with links to the question and session context
+// @Tags question-reports
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "open, resolved or dismissed"
+// @Param reason query string false "Report reason"
+// @Param question_id query string false "Only reports for this question"
+// @Success 200 {object} models.ListQuestionReportsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/question-reports [get]
+func (qc *QuestionReportController) ListReports(c *gin.Context) {
+	var req models.ListQuestionReportsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := qc.questionReportService.ListReports(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list question reports", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get question report context (Admin only)
+// @Description Get a report with the current question and the question as presented in the student's session
+// @Tags question-reports
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID"
+// @Success 200 {object} models.QuestionReportContextResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/question-reports/{id}/context [get]
+func (qc *QuestionReportController) GetReportContext(c *gin.Context) {
+	reportID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid report ID")
+		return
+	}
+
+	response, err := qc.questionReportService.GetReportContext(c.Request.Context(), reportID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get question report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Review a question report (Admin only)
+// @Description Mark a report resolved once the question is fixed, or dismiss it
+// @Tags question-reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID"
+// @Param request body models.ReviewQuestionReportRequest true "Review outcome"
+// @Success 200 {object} models.QuestionReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/question-reports/{id} [put]
+func (qc *QuestionReportController) ReviewReport(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	reportID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid report ID")
+		return
+	}
+
+	var req models.ReviewQuestionReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	report, err := qc.questionReportService.ReviewReport(c.Request.Context(), adminID, reportID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to review question report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
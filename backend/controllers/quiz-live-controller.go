@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// How often connected clients are sent the authoritative time remaining
+const quizLiveTimerInterval = 5 * time.Second
+
+type QuizLiveController struct {
+	quizSessionService services.QuizSessionService
+	liveHub            services.QuizLiveHub
+}
+
+func NewQuizLiveController(quizSessionService services.QuizSessionService, liveHub services.QuizLiveHub) *QuizLiveController {
+	return &QuizLiveController{
+		quizSessionService: quizSessionService,
+		liveHub:            liveHub,
+	}
+}
+
+// @Summary Live quiz session channel
+// @Description WebSocket pushing the server's time remaining every few seconds, acknowledgements of answers saved from any tab, and a submitted event when the session ends (including when the server submits it as time runs out). Browsers pass the access token in the access_token query parameter
+// @Tags quiz
+// @Param sessionToken path string true "Session token"
+// @Param access_token query string false "Access token, when an Authorization header cannot be sent"
+// @Success 101 {object} models.QuizLiveEvent
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /ws/quiz/{sessionToken} [get]
+func (lc *QuizLiveController) StreamSession(c *gin.Context) {
+	sessionToken := c.Param("sessionToken")
+	if _, err := lc.quizSessionService.GetSession(c.Request.Context(), sessionToken); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusNotFound, "Session not found", err)
+		return
+	}
+
+	server := websocket.Server{
+		// Clients authenticate with an access token rather than cookies, so another site's page
+		// cannot open the channel on a student's behalf and the origin does not need checking
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   func(conn *websocket.Conn) { lc.stream(conn, sessionToken) },
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (lc *QuizLiveController) stream(conn *websocket.Conn, sessionToken string) {
+	defer conn.Close()
+
+	// The server's read and write timeouts still apply to the hijacked connection; it must outlive them
+	conn.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, unsubscribe := lc.liveHub.Subscribe(sessionToken)
+	defer unsubscribe()
+
+	// Clients only send keep-alives; reading is how a disconnect is noticed
+	go func() {
+		defer cancel()
+		var message string
+		for websocket.Message.Receive(conn, &message) == nil {
+		}
+	}()
+
+	deadline, active := lc.sendTimer(ctx, conn, sessionToken)
+	if !active {
+		return
+	}
+
+	ticker := time.NewTicker(quizLiveTimerInterval)
+	defer ticker.Stop()
+	expiry := time.NewTimer(time.Until(deadline))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, open := <-events:
+			if !open || websocket.JSON.Send(conn, event) != nil || event.Type == models.QuizLiveSubmitted {
+				return
+			}
+		case <-ticker.C:
+			// Pausing moves the deadline, so the expiry timer follows it
+			deadline, active = lc.sendTimer(ctx, conn, sessionToken)
+			if !active {
+				return
+			}
+			expiry.Reset(time.Until(deadline))
+		case <-expiry.C:
+			// Reading an expired session submits it, so the client learns the outcome without asking
+			deadline, active = lc.sendTimer(ctx, conn, sessionToken)
+			if !active {
+				return
+			}
+			expiry.Reset(time.Until(deadline))
+		}
+	}
+}
+
+// sendTimer sends the session's time remaining, or a submitted event once it has ended. It returns the
+// session deadline and whether the session is still in progress.
+func (lc *QuizLiveController) sendTimer(ctx context.Context, conn *websocket.Conn, sessionToken string) (time.Time, bool) {
+	response, err := lc.quizSessionService.GetSession(ctx, sessionToken)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	session := response.Session
+	now := time.Now()
+	if session.Status != models.QuizInProgress {
+		reason := models.SubmitReasonStudent
+		if session.Status == models.QuizTimeout {
+			reason = models.SubmitReasonTimeExpired
+		}
+		websocket.JSON.Send(conn, models.QuizLiveEvent{
+			Type:       models.QuizLiveSubmitted,
+			ServerTime: now,
+			Status:     session.Status,
+			Reason:     reason,
+		})
+		return time.Time{}, false
+	}
+
+	deadline := session.Deadline(now)
+	event := models.QuizLiveEvent{
+		Type:          models.QuizLiveTimer,
+		ServerTime:    now,
+		TimeRemaining: &response.TimeRemaining,
+		Deadline:      &deadline,
+		Status:        session.Status,
+		Paused:        session.PausedAt != nil,
+	}
+	if websocket.JSON.Send(conn, event) != nil {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
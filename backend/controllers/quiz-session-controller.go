@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"backend/middleware"
 	"backend/models"
 	"backend/services"
+	"backend/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,13 +17,25 @@ import (
 
 type QuizSessionController interface {
 	StartQuiz(c *gin.Context)
+	StartCustomQuiz(c *gin.Context)
+	ListCustomQuizTags(c *gin.Context)
 	GetSession(c *gin.Context)
+	GetMediaManifest(c *gin.Context)
 	SaveAnswer(c *gin.Context)
+	SaveAnswersBatch(c *gin.Context)
 	NavigateToQuestion(c *gin.Context)
 	SkipQuestion(c *gin.Context)
 	SubmitQuiz(c *gin.Context)
+	ResolveAbandonedSession(c *gin.Context)
 	GetUserResults(c *gin.Context)
+	GetResult(c *gin.Context)
+	GetResultReview(c *gin.Context)
+	GetModuleMastery(c *gin.Context)
 	ResumeSession(c *gin.Context)
+	RecordIntegrityEvents(c *gin.Context)
+	GetSessionIntegrity(c *gin.Context)
+	PauseSession(c *gin.Context)
+	ResumePausedSession(c *gin.Context)
 }
 
 type quizSessionController struct {
@@ -37,59 +53,116 @@ func NewQuizSessionController(quizSessionService services.QuizSessionService) Qu
 func (ctrl *quizSessionController) StartQuiz(c *gin.Context) {
 	var req models.StartQuizRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
 		return
 	}
 
 	// Get user ID from JWT token
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+		middleware.RespondError(c, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjectID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid user ID format",
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
+	req.IPAddress, req.UserAgent = services.ExtractClientInfo(c.Request)
+	req.Lockdown = utils.LockdownHandshakeFromRequest(c.Request)
+	req.IsGuest = middleware.IsGuest(c)
+
+	// The language can also be given as ?lang=
+	if req.Lang == "" {
+		req.Lang = c.Query("lang")
+	}
+
 	response, err := ctrl.quizSessionService.StartQuiz(c.Request.Context(), userObjectID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to start quiz",
-			"details": err.Error(),
-		})
+		if errors.Is(err, utils.ErrLockdownRequired) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeLockdownRequired, "This exam must be taken in the lockdown browser", err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrQuestionPoolTooSmall) {
+			middleware.RespondErrorWithDetails(c, http.StatusServiceUnavailable, models.ErrorCodeQuestionPoolShort, "This quiz is not available until more questions are added", err.Error())
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start quiz", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// StartCustomQuiz starts a practice quiz built from the student's tags, difficulties and question count
+// POST /api/v1/quiz/custom/start
+func (ctrl *quizSessionController) StartCustomQuiz(c *gin.Context) {
+	var req models.StartCustomQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	req.IsGuest = middleware.IsGuest(c)
+
+	response, err := ctrl.quizSessionService.StartCustomQuiz(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start custom quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListCustomQuizTags lists the tags custom quizzes can be built from, with their question counts
+// GET /api/v1/quiz/custom/tags
+func (ctrl *quizSessionController) ListCustomQuizTags(c *gin.Context) {
+	tags, err := ctrl.quizSessionService.ListCustomQuizTags(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list tags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
 // GetSession retrieves current session state
 // GET /api/v1/quiz/session/:token
 func (ctrl *quizSessionController) GetSession(c *gin.Context) {
 	sessionToken := c.Param("token")
 	if sessionToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Session token is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
 		return
 	}
 
 	response, err := ctrl.quizSessionService.GetSession(c.Request.Context(), sessionToken)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Session not found",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusNotFound, "Session not found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMediaManifest lists all media URLs in the session for clients to prefetch
+// GET /api/v1/quiz/session/:token/media-manifest
+func (ctrl *quizSessionController) GetMediaManifest(c *gin.Context) {
+	sessionToken := c.Param("token")
+	if sessionToken == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
+		return
+	}
+
+	response, err := ctrl.quizSessionService.GetMediaManifest(c.Request.Context(), sessionToken)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusNotFound, "Session not found", err)
 		return
 	}
 
@@ -101,27 +174,55 @@ func (ctrl *quizSessionController) GetSession(c *gin.Context) {
 func (ctrl *quizSessionController) SaveAnswer(c *gin.Context) {
 	sessionToken := c.Param("token")
 	if sessionToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Session token is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
 		return
 	}
 
 	var req models.SaveAnswerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
 		return
 	}
 
+	req.Lockdown = utils.LockdownHandshakeFromRequest(c.Request)
+
 	response, err := ctrl.quizSessionService.SaveAnswer(c.Request.Context(), sessionToken, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to save answer",
-			"details": err.Error(),
-		})
+		if errors.Is(err, utils.ErrLockdownRequired) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeLockdownRequired, "This exam must be taken in the lockdown browser", err.Error())
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to save answer", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SaveAnswersBatch replays answers buffered while the client was offline
+// POST /api/v1/quiz/session/:token/answers/batch
+func (ctrl *quizSessionController) SaveAnswersBatch(c *gin.Context) {
+	sessionToken := c.Param("token")
+	if sessionToken == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
+		return
+	}
+
+	var req models.SaveAnswersBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+
+	req.Lockdown = utils.LockdownHandshakeFromRequest(c.Request)
+
+	response, err := ctrl.quizSessionService.SaveAnswersBatch(c.Request.Context(), sessionToken, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrLockdownRequired) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeLockdownRequired, "This exam must be taken in the lockdown browser", err.Error())
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to save answers", err)
 		return
 	}
 
@@ -133,27 +234,19 @@ func (ctrl *quizSessionController) SaveAnswer(c *gin.Context) {
 func (ctrl *quizSessionController) NavigateToQuestion(c *gin.Context) {
 	sessionToken := c.Param("token")
 	if sessionToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Session token is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
 		return
 	}
 
 	var req models.NavigateQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
 		return
 	}
 
 	err := ctrl.quizSessionService.NavigateToQuestion(c.Request.Context(), sessionToken, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to navigate to question",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to navigate to question", err)
 		return
 	}
 
@@ -168,27 +261,19 @@ func (ctrl *quizSessionController) NavigateToQuestion(c *gin.Context) {
 func (ctrl *quizSessionController) SkipQuestion(c *gin.Context) {
 	sessionToken := c.Param("token")
 	if sessionToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Session token is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
 		return
 	}
 
 	var req models.SkipQuestionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request format",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
 		return
 	}
 
 	err := ctrl.quizSessionService.SkipQuestion(c.Request.Context(), sessionToken, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to skip question",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to skip question", err)
 		return
 	}
 
@@ -203,18 +288,44 @@ func (ctrl *quizSessionController) SkipQuestion(c *gin.Context) {
 func (ctrl *quizSessionController) SubmitQuiz(c *gin.Context) {
 	sessionToken := c.Param("token")
 	if sessionToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Session token is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
 		return
 	}
 
-	response, err := ctrl.quizSessionService.SubmitQuiz(c.Request.Context(), sessionToken)
+	userID, _ := middleware.GetUserID(c)
+	response, err := ctrl.quizSessionService.SubmitQuiz(c.Request.Context(), userID, sessionToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Failed to submit quiz",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusBadRequest, "Failed to submit quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ResolveAbandonedSession resumes an abandoned session or discards an unfinished one
+// POST /api/v1/quiz/session/:token/resolve
+func (ctrl *quizSessionController) ResolveAbandonedSession(c *gin.Context) {
+	sessionToken := c.Param("token")
+	if sessionToken == "" {
+		middleware.RespondError(c, http.StatusBadRequest, "Session token is required")
+		return
+	}
+
+	var req models.ResolveAbandonedSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+	req.Lockdown = utils.LockdownHandshakeFromRequest(c.Request)
+
+	userID, _ := middleware.GetUserID(c)
+	response, err := ctrl.quizSessionService.ResolveAbandonedSession(c.Request.Context(), userID, sessionToken, &req)
+	if err != nil {
+		if errors.Is(err, utils.ErrLockdownRequired) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeLockdownRequired, "This exam must be taken in the lockdown browser", err.Error())
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to resolve quiz session", err)
 		return
 	}
 
@@ -227,17 +338,13 @@ func (ctrl *quizSessionController) GetUserResults(c *gin.Context) {
 	// Get user ID from JWT token
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+		middleware.RespondError(c, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjectID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid user ID format",
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
@@ -257,10 +364,7 @@ func (ctrl *quizSessionController) GetUserResults(c *gin.Context) {
 
 	results, err := ctrl.quizSessionService.GetUserResults(c.Request.Context(), userObjectID, quizType, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get user results",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get user results", err)
 		return
 	}
 
@@ -270,48 +374,104 @@ func (ctrl *quizSessionController) GetUserResults(c *gin.Context) {
 	})
 }
 
+// GetResult retrieves one of the user's results with its percentile, mean and median among recent results
+// GET /api/v1/quiz/results/:id
+func (ctrl *quizSessionController) GetResult(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	response, err := ctrl.quizSessionService.GetResult(c.Request.Context(), userID, resultID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get result", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetResultReview walks through a submitted result with chosen and correct answers, explanations and linked modules
+// GET /api/v1/quiz/results/:id/review
+func (ctrl *quizSessionController) GetResultReview(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	review, err := ctrl.quizSessionService.GetResultReview(c.Request.Context(), userID, resultID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get result review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// GetModuleMastery reports the user's mastery of each module their quizzes assessed, with the weakest
+// modules to study next
+// GET /api/v1/quiz/mastery
+func (ctrl *quizSessionController) GetModuleMastery(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	report, err := ctrl.quizSessionService.GetModuleMastery(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get module mastery", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // ResumeSession checks if user has an active session to resume
 // GET /api/v1/quiz/resume/:quiz_type
 func (ctrl *quizSessionController) ResumeSession(c *gin.Context) {
 	quizTypeStr := c.Param("quiz_type")
 	if quizTypeStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Quiz type is required",
-		})
+		middleware.RespondError(c, http.StatusBadRequest, "Quiz type is required")
 		return
 	}
 
 	quizType := models.QuizType(quizTypeStr)
-	if quizType != models.MockTest && quizType != models.TimeQuiz {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid quiz type",
-		})
+	if quizType != models.MockTest && quizType != models.TimeQuiz && quizType != models.AdaptivePractice && quizType != models.CustomQuiz {
+		middleware.RespondError(c, http.StatusBadRequest, "Invalid quiz type")
 		return
 	}
 
 	// Get user ID from JWT token
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+		middleware.RespondError(c, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjectID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid user ID format",
-		})
+		middleware.RespondError(c, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	session, err := ctrl.quizSessionService.ResumeSession(c.Request.Context(), userObjectID, quizType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to check for resume session",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to check for resume session", err)
 		return
 	}
 
@@ -326,10 +486,7 @@ func (ctrl *quizSessionController) ResumeSession(c *gin.Context) {
 	// Calculate remaining time
 	response, err := ctrl.quizSessionService.GetSession(c.Request.Context(), session.SessionToken)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to get session details",
-			"details": err.Error(),
-		})
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get session details", err)
 		return
 	}
 
@@ -340,3 +497,89 @@ func (ctrl *quizSessionController) ResumeSession(c *gin.Context) {
 		"message":            "Active session found",
 	})
 }
+
+// RecordIntegrityEvents stores client-reported integrity events (focus loss, tab switches, fullscreen exits,
+// copy/paste, devtools) for proctoring review
+// POST /api/v1/quiz/session/:token/events
+func (ctrl *quizSessionController) RecordIntegrityEvents(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.RecordIntegrityEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+
+	response, err := ctrl.quizSessionService.RecordIntegrityEvents(c.Request.Context(), userID, c.Param("token"), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to record integrity events", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// GetSessionIntegrity returns a session's integrity events and their summary for admin review
+// GET /api/v1/admin/quiz-sessions/:id/integrity
+func (ctrl *quizSessionController) GetSessionIntegrity(c *gin.Context) {
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid session ID")
+		return
+	}
+
+	response, err := ctrl.quizSessionService.GetSessionIntegrity(c.Request.Context(), sessionID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get integrity events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PauseSession freezes an in-progress session's timer, e.g. for a proctor intervention or an outage
+// POST /api/v1/admin/quiz-sessions/:id/pause
+func (ctrl *quizSessionController) PauseSession(c *gin.Context) {
+	ctrl.changePause(c, ctrl.quizSessionService.PauseSession, "Failed to pause quiz session")
+}
+
+// ResumePausedSession restarts a paused session's timer
+// POST /api/v1/admin/quiz-sessions/:id/resume
+func (ctrl *quizSessionController) ResumePausedSession(c *gin.Context) {
+	ctrl.changePause(c, ctrl.quizSessionService.ResumePausedSession, "Failed to resume quiz session")
+}
+
+type pauseChange func(ctx context.Context, adminID primitive.ObjectID, adminEmail string, sessionID primitive.ObjectID, req *models.SessionPauseRequest) (*models.GetSessionResponse, error)
+
+func (ctrl *quizSessionController) changePause(c *gin.Context, change pauseChange, message string) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid session ID")
+		return
+	}
+
+	var req models.SessionPauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request format", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	response, err := change(c.Request.Context(), adminID, adminEmail, sessionID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, message, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
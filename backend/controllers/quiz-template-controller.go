@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuizTemplateController struct {
+	quizTemplateService services.QuizTemplateService
+	activityLogService  services.ActivityLogService
+}
+
+func NewQuizTemplateController(quizTemplateService services.QuizTemplateService, activityLogService services.ActivityLogService) *QuizTemplateController {
+	return &QuizTemplateController{
+		quizTemplateService: quizTemplateService,
+		activityLogService:  activityLogService,
+	}
+}
+
+// @Summary List available quizzes
+// @Description List active quiz templates students can start a quiz from
+// @Tags quiz
+// @Produce json
+// @Security BearerAuth
+// @Param quiz_type query string false "mock_test or time_quiz"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /quiz/templates [get]
+func (tc *QuizTemplateController) ListActiveTemplates(c *gin.Context) {
+	var req models.ListQuizTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+	req.ActiveOnly = true
+
+	templates, err := tc.quizTemplateService.ListTemplates(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list quizzes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// @Summary Create quiz template (Admin only)
+// @Description Define question counts per difficulty, time limit and scoring rules for a quiz
+// @Tags quiz-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateQuizTemplateRequest true "Template data"
+// @Success 201 {object} models.QuizTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /admin/quiz-templates [post]
+func (tc *QuizTemplateController) CreateTemplate(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateQuizTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	template, err := tc.quizTemplateService.CreateTemplate(c.Request.Context(), adminID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create quiz template", err)
+		return
+	}
+
+	tc.logTemplateActivity(c, models.ActivityQuizTemplateCreated, template)
+	c.JSON(http.StatusCreated, template)
+}
+
+// @Summary List quiz templates (Admin only)
+// @Description List all quiz templates, including inactive ones
+// @Tags quiz-templates
+// @Produce json
+// @Security BearerAuth
+// @Param quiz_type query string false "mock_test or time_quiz"
+// @Param active_only query bool false "Only active templates"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/quiz-templates [get]
+func (tc *QuizTemplateController) ListTemplates(c *gin.Context) {
+	var req models.ListQuizTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	templates, err := tc.quizTemplateService.ListTemplates(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list quiz templates", err)
+		return
+	}
+
+	// Show which built-in definitions are still in use
+	builtins := []*models.QuizTemplate{}
+	for _, quizType := range []models.QuizType{models.MockTest, models.TimeQuiz} {
+		hasDefault := false
+		for _, t := range templates {
+			if t.QuizType == quizType && t.IsDefault && t.IsActive {
+				hasDefault = true
+				break
+			}
+		}
+		if !hasDefault {
+			builtins = append(builtins, models.DefaultQuizTemplate(quizType))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates":        templates,
+		"builtin_defaults": builtins,
+	})
+}
+
+// @Summary Get quiz template (Admin only)
+// @Tags quiz-templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.QuizTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/quiz-templates/{id} [get]
+func (tc *QuizTemplateController) GetTemplate(c *gin.Context) {
+	templateID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid template ID")
+		return
+	}
+
+	template, err := tc.quizTemplateService.GetTemplate(c.Request.Context(), templateID)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Update quiz template (Admin only)
+// @Description Change a template; sessions already in progress keep the rules they started with
+// @Tags quiz-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param request body models.UpdateQuizTemplateRequest true "Fields to change"
+// @Success 200 {object} models.QuizTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/quiz-templates/{id} [put]
+func (tc *QuizTemplateController) UpdateTemplate(c *gin.Context) {
+	templateID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid template ID")
+		return
+	}
+
+	var req models.UpdateQuizTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	template, err := tc.quizTemplateService.UpdateTemplate(c.Request.Context(), templateID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update quiz template", err)
+		return
+	}
+
+	tc.logTemplateActivity(c, models.ActivityQuizTemplateUpdated, template)
+	c.JSON(http.StatusOK, template)
+}
+
+// @Summary Delete quiz template (Admin only)
+// @Description Delete a template. If it was a quiz type's default, the built-in definition is used again
+// @Tags quiz-templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/quiz-templates/{id} [delete]
+func (tc *QuizTemplateController) DeleteTemplate(c *gin.Context) {
+	templateID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid template ID")
+		return
+	}
+
+	template, err := tc.quizTemplateService.GetTemplate(c.Request.Context(), templateID)
+	if err == nil {
+		err = tc.quizTemplateService.DeleteTemplate(c.Request.Context(), templateID)
+	}
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete quiz template", err)
+		return
+	}
+
+	tc.logTemplateActivity(c, models.ActivityQuizTemplateDeleted, template)
+	c.JSON(http.StatusOK, gin.H{"message": "Quiz template deleted successfully"})
+}
+
+// @Summary Check question pools (Admin only)
+// @Description Warn about active templates the question bank cannot fill; students cannot start those quizzes until more questions are added
+// @Tags quiz-templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.QuestionPoolReport
+// @Router /admin/quiz-templates/pool-status [get]
+func (tc *QuizTemplateController) GetPoolStatus(c *gin.Context) {
+	report, err := tc.quizTemplateService.CheckQuestionPools(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to check question pools", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (tc *QuizTemplateController) logTemplateActivity(c *gin.Context, activityType models.ActivityType, template *models.QuizTemplate) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		activityType,
+		"",
+		"quiz_template",
+		template.ID.Hex(),
+		template.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("quiz_type", template.QuizType).SetClientInfo(ipAddress, userAgent)
+	tc.activityLogService.LogActivityAsync(activityLog)
+}
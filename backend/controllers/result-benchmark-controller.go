@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ResultBenchmarkController struct {
+	resultBenchmarkService services.ResultBenchmarkService
+}
+
+func NewResultBenchmarkController(resultBenchmarkService services.ResultBenchmarkService) *ResultBenchmarkController {
+	return &ResultBenchmarkController{
+		resultBenchmarkService: resultBenchmarkService,
+	}
+}
+
+// @Summary Benchmark a result
+// @Description Compare one of the caller's results with anonymized peer percentiles: everyone in the same exam sitting, or recent attempts of the same quiz type. Peer aggregates are cached for 15 minutes
+// @Tags quiz
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Detailed result ID"
+// @Success 200 {object} models.ResultBenchmark
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /quiz/results/{id}/benchmark [get]
+func (bc *ResultBenchmarkController) GetBenchmark(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	benchmark, err := bc.resultBenchmarkService.GetBenchmark(c.Request.Context(), userID, resultID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to benchmark result", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, benchmark)
+}
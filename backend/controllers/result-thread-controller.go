@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ResultThreadController struct {
+	resultThreadService services.ResultThreadService
+}
+
+func NewResultThreadController(resultThreadService services.ResultThreadService) *ResultThreadController {
+	return &ResultThreadController{
+		resultThreadService: resultThreadService,
+	}
+}
+
+// @Summary Get result discussion
+// @Description Get the discussion thread of one of the authenticated user's detailed results and mark it read
+// @Tags result-threads
+// @Produce json
+// @Security BearerAuth
+// @Param resultId path string true "Detailed result ID"
+// @Success 200 {object} models.ResultThreadResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/result-threads/results/{resultId} [get]
+func (rc *ResultThreadController) GetStudentThread(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("resultId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	response, err := rc.resultThreadService.GetStudentThread(c.Request.Context(), userID, resultID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get result thread", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Post to result discussion
+// @Description Ask instructors about a detailed result, optionally referencing one of its questions
+// @Tags result-threads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param resultId path string true "Detailed result ID"
+// @Param request body models.PostThreadMessageRequest true "Message"
+// @Success 201 {object} models.ThreadMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /user/result-threads/results/{resultId}/messages [post]
+func (rc *ResultThreadController) PostStudentMessage(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	resultID, err := primitive.ObjectIDFromHex(c.Param("resultId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid result ID")
+		return
+	}
+
+	var req models.PostThreadMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	message, err := rc.resultThreadService.PostStudentMessage(c.Request.Context(), userID, resultID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to post message", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// @Summary List my result discussions
+// @Description List discussion threads on the authenticated user's results
+// @Tags result-threads
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param unread_only query bool false "Only threads with unread messages"
+// @Success 200 {object} models.ListResultThreadsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /user/result-threads [get]
+func (rc *ResultThreadController) ListStudentThreads(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ListResultThreadsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := rc.resultThreadService.ListStudentThreads(c.Request.Context(), userID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list result threads", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List result discussions (Admin only)
+// @Description List all result discussion threads, most recent activity first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param unread_only query bool false "Only threads with unread student messages"
+// @Success 200 {object} models.ListResultThreadsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/result-threads [get]
+func (rc *ResultThreadController) ListThreads(c *gin.Context) {
+	var req models.ListResultThreadsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := rc.resultThreadService.ListThreads(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list result threads", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get result discussion (Admin only)
+// @Description Get a result discussion thread with its messages and mark it read for instructors
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Thread ID"
+// @Success 200 {object} models.ResultThreadResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-threads/{id} [get]
+func (rc *ResultThreadController) GetThread(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid thread ID")
+		return
+	}
+
+	response, err := rc.resultThreadService.GetThread(c.Request.Context(), adminID, threadID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get result thread", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Reply to result discussion (Admin only)
+// @Description Reply to a student's result discussion, optionally referencing one of the result's questions
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Thread ID"
+// @Param request body models.PostThreadMessageRequest true "Message"
+// @Success 201 {object} models.ThreadMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-threads/{id}/messages [post]
+func (rc *ResultThreadController) PostInstructorMessage(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	threadID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid thread ID")
+		return
+	}
+
+	var req models.PostThreadMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	message, err := rc.resultThreadService.PostInstructorMessage(c.Request.Context(), adminID, threadID, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to post message", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
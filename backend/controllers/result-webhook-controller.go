@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ResultWebhookController struct {
+	resultWebhookService services.ResultWebhookService
+	activityLogService   services.ActivityLogService
+}
+
+func NewResultWebhookController(resultWebhookService services.ResultWebhookService, activityLogService services.ActivityLogService) *ResultWebhookController {
+	return &ResultWebhookController{
+		resultWebhookService: resultWebhookService,
+		activityLogService:   activityLogService,
+	}
+}
+
+// @Summary Register result webhook (Admin only)
+// @Description Register a department's webhook, which receives signed summaries of its faculty's or major's exam results once they are finalized. The signing secret is returned once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateResultWebhookRequest true "Webhook settings"
+// @Success 201 {object} models.ResultWebhookSecretResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/result-webhooks [post]
+func (rc *ResultWebhookController) CreateWebhook(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateResultWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	response, err := rc.resultWebhookService.CreateWebhook(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create result webhook", err)
+		return
+	}
+
+	rc.logWebhookActivity(c, models.ActivityResultWebhookCreated, "Created result webhook", response.Webhook)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary List result webhooks (Admin only)
+// @Description List department result webhooks with their delivery status, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ListResultWebhooksResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/result-webhooks [get]
+func (rc *ResultWebhookController) ListWebhooks(c *gin.Context) {
+	response, err := rc.resultWebhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list result webhooks", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Get result webhook (Admin only)
+// @Description Get a result webhook's settings and delivery status
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.ResultWebhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-webhooks/{id} [get]
+func (rc *ResultWebhookController) GetWebhook(c *gin.Context) {
+	id, ok := parseResultWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := rc.resultWebhookService.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get result webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// @Summary Update result webhook (Admin only)
+// @Description Change a result webhook's URL or department, or pause and resume its deliveries
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Param request body models.UpdateResultWebhookRequest true "Changes"
+// @Success 200 {object} models.ResultWebhook
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-webhooks/{id} [put]
+func (rc *ResultWebhookController) UpdateWebhook(c *gin.Context) {
+	id, ok := parseResultWebhookID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateResultWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	webhook, err := rc.resultWebhookService.UpdateWebhook(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update result webhook", err)
+		return
+	}
+
+	rc.logWebhookActivity(c, models.ActivityResultWebhookUpdated, "Updated result webhook", webhook)
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// @Summary Delete result webhook (Admin only)
+// @Description Stop sending results to a department's webhook and delete it
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-webhooks/{id} [delete]
+func (rc *ResultWebhookController) DeleteWebhook(c *gin.Context) {
+	id, ok := parseResultWebhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := rc.resultWebhookService.DeleteWebhook(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to delete result webhook", err)
+		return
+	}
+
+	rc.logWebhookActivity(c, models.ActivityResultWebhookDeleted, "Deleted result webhook", webhook)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Result webhook deleted successfully"})
+}
+
+// @Summary Rotate result webhook secret (Admin only)
+// @Description Issue a new signing secret; later deliveries are signed with it. The secret is returned once
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.ResultWebhookSecretResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-webhooks/{id}/rotate-secret [post]
+func (rc *ResultWebhookController) RotateSecret(c *gin.Context) {
+	id, ok := parseResultWebhookID(c)
+	if !ok {
+		return
+	}
+
+	response, err := rc.resultWebhookService.RotateSecret(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to rotate webhook secret", err)
+		return
+	}
+
+	rc.logWebhookActivity(c, models.ActivityResultWebhookSecretRotated, "Rotated result webhook secret", response.Webhook)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Ping result webhook (Admin only)
+// @Description Send a signed webhook.ping delivery without results and report how the endpoint responded
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.PingResultWebhookResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/result-webhooks/{id}/ping [post]
+func (rc *ResultWebhookController) PingWebhook(c *gin.Context) {
+	id, ok := parseResultWebhookID(c)
+	if !ok {
+		return
+	}
+
+	response, err := rc.resultWebhookService.PingWebhook(c.Request.Context(), id)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to ping result webhook", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func parseResultWebhookID(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid webhook ID")
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+func (rc *ResultWebhookController) logWebhookActivity(c *gin.Context, activityType models.ActivityType, action string, webhook *models.ResultWebhook) {
+	adminID, _ := middleware.GetUserID(c)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+
+	activityLog := models.NewActivityLog(
+		activityType,
+		action,
+		"result_webhook",
+		webhook.ID.Hex(),
+		webhook.Name,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("url", webhook.URL).
+		SetDetails("group_by", webhook.GroupBy).
+		SetDetails("group", webhook.Group).
+		SetDetails("active", webhook.Active).
+		SetClientInfo(ipAddress, userAgent)
+	rc.activityLogService.LogActivityAsync(activityLog)
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionTelemetryController struct {
+	telemetryService services.SessionTelemetryService
+}
+
+func NewSessionTelemetryController(telemetryService services.SessionTelemetryService) *SessionTelemetryController {
+	return &SessionTelemetryController{
+		telemetryService: telemetryService,
+	}
+}
+
+// @Summary Send session telemetry
+// @Description Store a batch of client measurements for the caller's quiz session: render times, answer latency, network retries and timer stalls
+// @Tags quiz
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Session token"
+// @Param request body models.IngestTelemetryRequest true "Telemetry events"
+// @Success 202 {object} models.IngestTelemetryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /quiz/session/{token}/telemetry [post]
+func (tc *SessionTelemetryController) Ingest(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.IngestTelemetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+	_, req.UserAgent = services.ExtractClientInfo(c.Request)
+	req.IsGuest = middleware.IsGuest(c)
+
+	response, err := tc.telemetryService.Ingest(c.Request.Context(), userID, c.Param("token"), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to store telemetry", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// @Summary Session telemetry summary (Admin only)
+// @Description Aggregate client telemetry by event type; choosing a session also returns its event timeline
+// @Tags analytics
+// @Produce json
+// @Security BearerAuth
+// @Param session_id query string false "Only this session"
+// @Param session_token query string false "Only this session, by token"
+// @Param quiz_type query string false "Quiz type"
+// @Param type query string false "render, answer_latency, network_retry or timer_stall"
+// @Param date_from query string false "Start date (YYYY-MM-DD)"
+// @Param date_to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} models.TelemetrySummaryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/telemetry [get]
+func (tc *SessionTelemetryController) GetSummary(c *gin.Context) {
+	var req models.TelemetrySummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := tc.telemetryService.GetSummary(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get telemetry summary", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SettingsController struct {
+	settingsService    services.SettingsService
+	activityLogService services.ActivityLogService
+}
+
+func NewSettingsController(settingsService services.SettingsService, activityLogService services.ActivityLogService) *SettingsController {
+	return &SettingsController{
+		settingsService:    settingsService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Get runtime settings (Admin only)
+// @Description Settings admins can change without a restart, such as activity log retention and quiz defaults. Until saved they come from the environment
+// @Tags settings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RuntimeSettings
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/settings [get]
+func (sc *SettingsController) GetSettings(c *gin.Context) {
+	settings, err := sc.settingsService.GetSettings(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary Update runtime settings (Admin only)
+// @Description Change the settings given. The answering instance uses them at once and the others on their next refresh
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateRuntimeSettingsRequest true "Settings to change"
+// @Success 200 {object} models.RuntimeSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/settings [put]
+func (sc *SettingsController) UpdateSettings(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	adminEmail, _ := middleware.GetUserEmail(c)
+
+	var req models.UpdateRuntimeSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	settings, err := sc.settingsService.UpdateSettings(c.Request.Context(), adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update settings", err)
+		return
+	}
+
+	logSettingsUpdate(c, sc.activityLogService, adminID, adminEmail, &req)
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// logSettingsUpdate records which runtime settings an admin changed, for both API versions
+func logSettingsUpdate(c *gin.Context, activityLogService services.ActivityLogService, adminID primitive.ObjectID, adminEmail string, req *models.UpdateRuntimeSettingsRequest) {
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivitySettingsUpdated,
+		"Updated runtime settings",
+		"settings",
+		"runtime",
+		"Runtime settings",
+		adminID,
+		adminEmail,
+		userType,
+	).SetClientInfo(ipAddress, userAgent)
+	if req.ActivityLogRetentionDays != nil {
+		activityLog.SetDetails("activity_log_retention_days", *req.ActivityLogRetentionDays)
+	}
+	if req.CertificatePassingScore != nil {
+		activityLog.SetDetails("certificate_passing_score", *req.CertificatePassingScore)
+	}
+	if req.CustomQuizMinutesPerQuestion != nil {
+		activityLog.SetDetails("custom_quiz_minutes_per_question", *req.CustomQuizMinutesPerQuestion)
+	}
+	activityLogService.LogActivityAsync(activityLog)
+}
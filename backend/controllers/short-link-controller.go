@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ShortLinkController struct {
+	shortLinkService   services.ShortLinkService
+	activityLogService services.ActivityLogService
+}
+
+func NewShortLinkController(shortLinkService services.ShortLinkService, activityLogService services.ActivityLogService) *ShortLinkController {
+	return &ShortLinkController{
+		shortLinkService:   shortLinkService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Create short link (Admin only)
+// @Description Create a short code redirecting to a frontend page, such as a module or exam, for printed materials and chat groups. A code is generated unless a custom one is given
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateShortLinkRequest true "Short link data"
+// @Success 201 {object} models.ShortLink
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/links [post]
+func (lc *ShortLinkController) CreateShortLink(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	link, err := lc.shortLinkService.CreateShortLink(c.Request.Context(), adminID, adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create short link", err)
+		return
+	}
+	link.ShortURL = shortLinkURL(c, link.Code)
+
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityShortLinkCreated,
+		"Created short link",
+		"short_link",
+		link.ID.Hex(),
+		link.Code,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("path", link.Path).
+		SetDetails("expires_at", link.ExpiresAt).
+		SetClientInfo(ipAddress, userAgent)
+	lc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// @Summary List short links (Admin only)
+// @Description List short links with their click counts, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param status query string false "Filter by status (active, revoked, expired)"
+// @Param search query string false "Search code, label or path"
+// @Success 200 {object} models.ListShortLinksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/links [get]
+func (lc *ShortLinkController) ListShortLinks(c *gin.Context) {
+	var req models.ListShortLinksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := lc.shortLinkService.ListShortLinks(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list short links", err)
+		return
+	}
+
+	for i := range response.Links {
+		response.Links[i].ShortURL = shortLinkURL(c, response.Links[i].Code)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Revoke short link (Admin only)
+// @Description Stop a short link from redirecting. Its code stays reserved so it cannot later point somewhere else
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Short link ID"
+// @Success 200 {object} models.ShortLink
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/links/{id} [delete]
+func (lc *ShortLinkController) RevokeShortLink(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	linkID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid short link ID")
+		return
+	}
+
+	link, err := lc.shortLinkService.RevokeShortLink(c.Request.Context(), adminID, linkID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to revoke short link", err)
+		return
+	}
+	link.ShortURL = shortLinkURL(c, link.Code)
+
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityShortLinkRevoked,
+		"Revoked short link",
+		"short_link",
+		link.ID.Hex(),
+		link.Code,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("click_count", link.ClickCount).SetClientInfo(ipAddress, userAgent)
+	lc.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, link)
+}
+
+// @Summary Follow short link
+// @Description Count a click and redirect to the frontend page the code points to
+// @Tags links
+// @Param code path string true "Short code"
+// @Success 302
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 410 {object} models.ErrorResponse
+// @Router /s/{code} [get]
+func (lc *ShortLinkController) FollowShortLink(c *gin.Context) {
+	link, err := lc.shortLinkService.ResolveShortLink(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to follow short link", err)
+		return
+	}
+
+	// Links only ever lead into the frontend, so a short link cannot be used as an open redirect
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+
+	// Not permanent: the link may be revoked or expire, and browsers cache permanent redirects
+	c.Redirect(http.StatusFound, strings.TrimRight(frontendURL, "/")+link.Path)
+}
+
+// shortLinkURL returns the public URL of a short code on the host serving this request
+func shortLinkURL(c *gin.Context, code string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + "/s/" + code
+}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TeamController struct {
+	teamService services.TeamService
+}
+
+func NewTeamController(teamService services.TeamService) *TeamController {
+	return &TeamController{
+		teamService: teamService,
+	}
+}
+
+// @Summary Create team
+// @Description Create a team for team quizzes with yourself as captain. Classmates join with the returned join code
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateTeamRequest true "Team data"
+// @Success 201 {object} models.Team
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /teams [post]
+func (tc *TeamController) CreateTeam(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	email, _ := middleware.GetUserEmail(c)
+	team, err := tc.teamService.CreateTeam(c.Request.Context(), userID, email, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to create team", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, team)
+}
+
+// @Summary Join team
+// @Description Join a team with its join code. Teams have at most 5 members
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.JoinTeamRequest true "Join code"
+// @Success 200 {object} models.Team
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /teams/join [post]
+func (tc *TeamController) JoinTeam(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.JoinTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	email, _ := middleware.GetUserEmail(c)
+	team, err := tc.teamService.JoinTeam(c.Request.Context(), userID, email, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to join team", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary List my teams
+// @Description List the teams you belong to
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Team
+// @Failure 401 {object} models.ErrorResponse
+// @Router /teams [get]
+func (tc *TeamController) GetUserTeams(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teams, err := tc.teamService.GetUserTeams(c.Request.Context(), userID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get teams", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+// @Summary Get team
+// @Description Get a team you belong to
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} models.Team
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /teams/{id} [get]
+func (tc *TeamController) GetTeam(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid team ID")
+		return
+	}
+
+	team, err := tc.teamService.GetTeam(c.Request.Context(), userID, teamID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get team", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Leave team
+// @Description Leave a team. A leaving captain hands over to the longest-standing member; the last member to leave deletes the team. Results already earned with the team are kept
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /teams/{id}/leave [post]
+func (tc *TeamController) LeaveTeam(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid team ID")
+		return
+	}
+
+	if err := tc.teamService.LeaveTeam(c.Request.Context(), userID, teamID); err != nil {
+		if serviceErr, ok := services.ErrorStatus(err); ok && serviceErr.Status == http.StatusNotFound {
+			middleware.RespondError(c, http.StatusNotFound, "team not found")
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to leave team", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left team successfully"})
+}
+
+// @Summary Remove team member
+// @Description Remove a member from your team (captain only)
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param userId path string true "Member user ID"
+// @Success 200 {object} models.Team
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /teams/{id}/members/{userId} [delete]
+func (tc *TeamController) RemoveMember(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid team ID")
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	team, err := tc.teamService.RemoveMember(c.Request.Context(), userID, teamID, memberID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to remove team member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// @Summary Start team quiz
+// @Description Start the team's shared quiz session, or join it if a teammate already started one. Every member answers in the same session; only the captain can submit, and all members are credited with the result
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body models.StartTeamQuizRequest true "Quiz type or template"
+// @Success 200 {object} models.StartQuizResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /teams/{id}/quiz [post]
+func (tc *TeamController) StartTeamQuiz(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid team ID")
+		return
+	}
+
+	var req models.StartTeamQuizRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+	req.IPAddress, req.UserAgent = services.ExtractClientInfo(c.Request)
+
+	response, err := tc.teamService.StartTeamQuiz(c.Request.Context(), userID, teamID, &req)
+	if err != nil {
+		if errors.Is(err, services.ErrQuestionPoolTooSmall) {
+			middleware.RespondErrorWithDetails(c, http.StatusServiceUnavailable, models.ErrorCodeQuestionPoolShort, "This quiz is not available until more questions are added", err.Error())
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to start team quiz", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Team leaderboard
+// @Description Rank teams by their best team quiz score, then their average
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param date_from query string false "From date (YYYY-MM-DD)"
+// @Param date_to query string false "To date (YYYY-MM-DD), inclusive"
+// @Param limit query int false "Number of teams" default(20)
+// @Success 200 {object} models.TeamLeaderboardResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /teams/leaderboard [get]
+func (tc *TeamController) GetLeaderboard(c *gin.Context) {
+	var req models.TeamLeaderboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	response, err := tc.teamService.GetLeaderboard(c.Request.Context(), &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get team leaderboard", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
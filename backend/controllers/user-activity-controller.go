@@ -4,6 +4,7 @@ import (
 	"math"
 	"net/http"
 
+	"backend/middleware"
 	"backend/models"
 	"backend/services"
 
@@ -34,33 +35,33 @@ func NewUserActivityController(userActivityService services.UserActivityService)
 // @Produce json
 // @Param quiz_result body models.QuizResultRequest true "Quiz result data"
 // @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/quiz-results [post]
 func (c *UserActivityController) CreateQuizResult(ctx *gin.Context) {
 	// Get user from context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	var request models.QuizResultRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	result, achievements, err := c.userActivityService.CreateQuizResult(ctx, userObjID, request)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -87,34 +88,36 @@ func (c *UserActivityController) CreateQuizResult(ctx *gin.Context) {
 // @Param date_to query string false "Filter to date (YYYY-MM-DD)"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Continue after the next_cursor of an earlier page instead of paging by number"
 // @Success 200 {object} models.UserResultsResponse
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/quiz-results [get]
 func (c *UserActivityController) GetUserResults(ctx *gin.Context) {
 	// Get user from context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	// Parse query parameters
 	var filter models.QuizResultsFilter
 	if err := ctx.ShouldBindQuery(&filter); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	response, err := c.userActivityService.GetUserResults(ctx, userObjID, filter)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -128,21 +131,21 @@ func (c *UserActivityController) GetUserResults(ctx *gin.Context) {
 // @Produce json
 // @Param id path string true "Quiz result ID"
 // @Success 200 {object} models.QuizResult
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/quiz-results/{id} [get]
 func (c *UserActivityController) GetQuizResultByID(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	id, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quiz result ID"})
+		middleware.RespondErrorCode(ctx, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid quiz result ID")
 		return
 	}
 
 	result, err := c.userActivityService.GetQuizResultByID(ctx, id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusNotFound, err)
 		return
 	}
 
@@ -155,26 +158,26 @@ func (c *UserActivityController) GetQuizResultByID(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.UserStats
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/user/stats [get]
 func (c *UserActivityController) GetUserStats(ctx *gin.Context) {
 	// Get user from context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	stats, err := c.userActivityService.GetUserStats(ctx, userObjID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -187,26 +190,26 @@ func (c *UserActivityController) GetUserStats(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} []models.Achievement
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/user/achievements [get]
 func (c *UserActivityController) GetUserAchievements(ctx *gin.Context) {
 	// Get user from context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	achievements, err := c.userActivityService.GetUserAchievements(ctx, userObjID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -219,26 +222,26 @@ func (c *UserActivityController) GetUserAchievements(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /api/user/performance-summary [get]
 func (c *UserActivityController) GetPerformanceSummary(ctx *gin.Context) {
 	// Get user from context
 	userID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	userObjID, ok := userID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid user ID format")
 		return
 	}
 
 	stats, err := c.userActivityService.GetUserStats(ctx, userObjID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -281,18 +284,43 @@ func (c *UserActivityController) GetPerformanceSummary(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, summary)
 }
 
+// GetUserResultsForIntegration gets quiz results for any user; only mounted behind API key
+// authentication with the results:read scope, which stands in for the per-user check
+func (c *UserActivityController) GetUserResultsForIntegration(ctx *gin.Context) {
+	targetUserObjID, err := primitive.ObjectIDFromHex(ctx.Param("userID"))
+	if err != nil {
+		middleware.RespondErrorCode(ctx, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid target user ID format")
+		return
+	}
+
+	var filter models.QuizResultsFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		middleware.RespondServiceError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	filter.IncludeWithheld = true
+
+	response, err := c.userActivityService.GetUserResults(ctx, targetUserObjID, filter)
+	if err != nil {
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 // GetUserResultsByUserID gets quiz results for a specific user by user ID (with authorization)
 func (c *UserActivityController) GetUserResultsByUserID(ctx *gin.Context) {
 	// Get the requesting user from context
 	requestingUserID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	requestingUserObjID, ok := requestingUserID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid requesting user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid requesting user ID format")
 		return
 	}
 
@@ -300,7 +328,7 @@ func (c *UserActivityController) GetUserResultsByUserID(ctx *gin.Context) {
 	targetUserIDParam := ctx.Param("userID")
 	targetUserObjID, err := primitive.ObjectIDFromHex(targetUserIDParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target user ID format"})
+		middleware.RespondErrorCode(ctx, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid target user ID format")
 		return
 	}
 
@@ -308,21 +336,21 @@ func (c *UserActivityController) GetUserResultsByUserID(ctx *gin.Context) {
 	// For now, we'll allow users to view their own results only
 	// In future, add admin role check here
 	if requestingUserObjID != targetUserObjID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own results"})
+		middleware.RespondError(ctx, http.StatusForbidden, "You can only view your own results")
 		return
 	}
 
 	// Parse query parameters
 	var filter models.QuizResultsFilter
 	if err := ctx.ShouldBindQuery(&filter); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	// Get results using existing service method
 	response, err := c.userActivityService.GetUserResults(ctx, targetUserObjID, filter)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -334,13 +362,13 @@ func (c *UserActivityController) GetUserStatsByUserID(ctx *gin.Context) {
 	// Get the requesting user from context
 	requestingUserID, exists := ctx.Get("userID")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		middleware.RespondError(ctx, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	requestingUserObjID, ok := requestingUserID.(primitive.ObjectID)
 	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid requesting user ID format"})
+		middleware.RespondError(ctx, http.StatusInternalServerError, "Invalid requesting user ID format")
 		return
 	}
 
@@ -348,20 +376,20 @@ func (c *UserActivityController) GetUserStatsByUserID(ctx *gin.Context) {
 	targetUserIDParam := ctx.Param("userID")
 	targetUserObjID, err := primitive.ObjectIDFromHex(targetUserIDParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target user ID format"})
+		middleware.RespondErrorCode(ctx, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid target user ID format")
 		return
 	}
 
 	// Authorization: Users can only view their own stats unless they're admin
 	if requestingUserObjID != targetUserObjID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own statistics"})
+		middleware.RespondError(ctx, http.StatusForbidden, "You can only view your own statistics")
 		return
 	}
 
 	// Get statistics using existing service method
 	stats, err := c.userActivityService.GetUserStats(ctx, targetUserObjID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -2,8 +2,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"backend/models"
 	"backend/repository"
 	"backend/services"
+	"backend/utils"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,66 +24,128 @@ type UserController struct {
 	userService        services.UserService
 	userRepository     repository.UserRepository
 	activityLogService services.ActivityLogService
+	captchaVerifier    utils.CaptchaVerifier
 }
 
-func NewUserController(userService services.UserService, userRepository repository.UserRepository, activityLogService services.ActivityLogService) *UserController {
+func NewUserController(userService services.UserService, userRepository repository.UserRepository, activityLogService services.ActivityLogService, captchaVerifier utils.CaptchaVerifier) *UserController {
 	return &UserController{
 		userService:        userService,
 		userRepository:     userRepository,
 		activityLogService: activityLogService,
+		captchaVerifier:    captchaVerifier,
 	}
 }
 
+// verifyCaptcha checks the X-Captcha-Token header on public endpoints bots target.
+// It writes the error response and returns false when the request should be rejected.
+func verifyCaptcha(c *gin.Context, captchaVerifier utils.CaptchaVerifier) bool {
+	ipAddress, _ := services.ExtractClientInfo(c.Request)
+	err := captchaVerifier.Verify(c.Request.Context(), c.GetHeader("X-Captcha-Token"), ipAddress)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, utils.ErrCaptchaFailed) {
+		middleware.RespondServiceError(c, http.StatusForbidden, err)
+	} else {
+		slog.WarnContext(c.Request.Context(), "Captcha verification failed", "error", err)
+		middleware.RespondError(c, http.StatusServiceUnavailable, "Captcha verification is temporarily unavailable")
+	}
+	return false
+}
+
+// @Summary Get captcha settings
+// @Description Get the captcha provider and site key the register, login and forgot-password forms must render. Send the widget token in the X-Captcha-Token header
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.CaptchaSettingsResponse
+// @Router /auth/captcha [get]
+func (uc *UserController) GetCaptchaSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, models.CaptchaSettingsResponse{
+		Enabled:  uc.captchaVerifier.Enabled(),
+		Provider: uc.captchaVerifier.Provider(),
+		SiteKey:  uc.captchaVerifier.SiteKey(),
+	})
+}
+
 // @Summary Register a new user
 // @Description Register a new user account (mahasiswa or admin)
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body models.RegisterRequest true "Registration data"
+// @Param X-Captcha-Token header string false "Captcha widget token (required when captcha is enabled)"
 // @Success 201 {object} models.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 409 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Router /auth/register [post]
 func (uc *UserController) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	if !verifyCaptcha(c, uc.captchaVerifier) {
 		return
 	}
 
 	response, err := uc.userService.Register(c.Request.Context(), &req)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "user with this email already exists" || err.Error() == "user with this NIM already exists" {
-			statusCode = http.StatusConflict
-		}
-		c.JSON(statusCode, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
+	// Record invite usage so admins can see who joined from their invitations
+	if invitation := response.Invitation; invitation != nil && invitation.AcceptedBy != nil {
+		ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+		activityLog := models.NewActivityLog(
+			models.ActivityInvitationAccepted,
+			"Registered from invitation",
+			"invitation",
+			invitation.ID.Hex(),
+			invitation.Email,
+			*invitation.AcceptedBy,
+			req.FullName,
+			string(invitation.UserType),
+		).SetDetails("invited_by", invitation.InvitedByEmail).SetClientInfo(ipAddress, userAgent)
+		uc.activityLogService.LogActivityAsync(activityLog)
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
+// @Summary Get password policy
+// @Description Get the password rules enforced on registration, password change and reset so clients can validate before submitting
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.PasswordPolicyResponse
+// @Router /auth/password-policy [get]
+func (uc *UserController) GetPasswordPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, uc.userService.GetPasswordPolicy())
+}
+
 // @Summary Login user
 // @Description Login with email and password
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body models.LoginRequest true "Login credentials"
+// @Param X-Captcha-Token header string false "Captcha widget token (required when captcha is enabled)"
 // @Success 200 {object} models.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /auth/login [post]
 func (uc *UserController) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	if !verifyCaptcha(c, uc.captchaVerifier) {
 		return
 	}
 
@@ -106,7 +170,7 @@ func (uc *UserController) Login(c *gin.Context) {
 			)
 		}()
 
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -181,22 +245,19 @@ func (uc *UserController) Login(c *gin.Context) {
 // @Produce json
 // @Param request body models.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} models.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Router /auth/refresh [post]
 func (uc *UserController) RefreshToken(c *gin.Context) {
 	var req models.RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	response, err := uc.userService.RefreshToken(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -209,49 +270,38 @@ func (uc *UserController) RefreshToken(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
 // @Router /auth/logout [post]
 func (uc *UserController) Logout(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "Unauthorized",
-			"message": "User not authenticated",
-		})
+		middleware.RespondError(c, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
 	// Get the token from Authorization header
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Bad Request",
-			"message": "Authorization header required",
-		})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeAuthRequired, "Authorization header required")
 		return
 	}
 
 	// Extract token from "Bearer <token>"
 	tokenParts := strings.Split(authHeader, " ")
 	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Bad Request",
-			"message": "Invalid authorization header format",
-		})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeTokenInvalid, "Invalid authorization header format")
 		return
 	}
 
-	token := tokenParts[1]
-
 	// Log the logout attempt
-	log.Printf("User logout attempt - UserID: %v, Token: %s...", userID, token[:min(len(token), 10)])
+	slog.InfoContext(c.Request.Context(), "User logging out", "user_id", fmt.Sprintf("%v", userID))
 
 	// Update user's last logout time (optional)
 	userIDStr := fmt.Sprintf("%v", userID)
 	err := uc.userService.UpdateLastLogout(userIDStr)
 	if err != nil {
-		log.Printf("Failed to update last logout for user %s: %v", userIDStr, err)
+		slog.ErrorContext(c.Request.Context(), "Failed to update last logout", "user_id", userIDStr, "error", err)
 		// Don't fail the logout for this error
 	}
 
@@ -308,19 +358,19 @@ func (uc *UserController) Logout(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} interface{}
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Router /user/profile [get]
 func (uc *UserController) GetProfile(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	profile, err := uc.userService.GetProfile(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusNotFound, err)
 		return
 	}
 
@@ -335,27 +385,24 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body map[string]interface{} true "Profile updates"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Router /user/profile [put]
 func (uc *UserController) UpdateProfile(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	var updates map[string]interface{}
 	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	if err := uc.userService.UpdateProfile(c.Request.Context(), userID, updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -370,27 +417,24 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.ChangePasswordRequest true "Password change data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
 // @Router /user/change-password [post]
 func (uc *UserController) ChangePassword(c *gin.Context) {
 	userID, exists := middleware.GetUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	var req models.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	if err := uc.userService.ChangePassword(c.Request.Context(), userID, &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -403,22 +447,25 @@ func (uc *UserController) ChangePassword(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body models.PasswordResetRequest true "Email for password reset"
+// @Param X-Captcha-Token header string false "Captcha widget token (required when captcha is enabled)"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /auth/forgot-password [post]
 func (uc *UserController) RequestPasswordReset(c *gin.Context) {
 	var req models.PasswordResetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	if !verifyCaptcha(c, uc.captchaVerifier) {
 		return
 	}
 
 	response, err := uc.userService.RequestPasswordReset(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process password reset request"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to process password reset request")
 		return
 	}
 
@@ -432,20 +479,17 @@ func (uc *UserController) RequestPasswordReset(c *gin.Context) {
 // @Produce json
 // @Param request body models.PasswordResetConfirm true "Password reset confirmation"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
 // @Router /auth/reset-password [post]
 func (uc *UserController) ResetPassword(c *gin.Context) {
 	var req models.PasswordResetConfirm
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	if err := uc.userService.ResetPassword(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -459,20 +503,20 @@ func (uc *UserController) ResetPassword(c *gin.Context) {
 // @Param provider path string true "OAuth provider" Enums(google, facebook, x, github)
 // @Param user_type query string true "User type" Enums(mahasiswa, admin)
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
 // @Router /auth/oauth/{provider}/url [get]
 func (uc *UserController) GetOAuthURL(c *gin.Context) {
 	provider := c.Param("provider")
 	userType := c.Query("user_type")
 
 	if userType == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "user_type query parameter is required"})
+		middleware.RespondError(c, http.StatusBadRequest, "user_type query parameter is required")
 		return
 	}
 
 	authURL, err := uc.userService.GetOAuthURL(provider, userType)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -490,21 +534,18 @@ func (uc *UserController) GetOAuthURL(c *gin.Context) {
 // @Produce json
 // @Param request body models.OAuthRequest true "OAuth data"
 // @Success 200 {object} models.AuthResponse
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
 // @Router /auth/oauth/callback [post]
 func (uc *UserController) OAuthCallback(c *gin.Context) {
 	var req models.OAuthRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	response, err := uc.userService.OAuthLogin(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -517,17 +558,17 @@ func (uc *UserController) OAuthCallback(c *gin.Context) {
 // @Produce json
 // @Param token query string true "Verification token"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
 // @Router /auth/verify-email [get]
 func (uc *UserController) VerifyEmail(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		middleware.RespondError(c, http.StatusBadRequest, "Token is required")
 		return
 	}
 
 	if err := uc.userService.VerifyEmail(c.Request.Context(), token); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -541,7 +582,7 @@ func (uc *UserController) VerifyEmail(c *gin.Context) {
 // @Produce json
 // @Param request body map[string]string true "Email address"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
 // @Router /auth/resend-verification [post]
 func (uc *UserController) ResendVerification(c *gin.Context) {
 	var req struct {
@@ -549,15 +590,12 @@ func (uc *UserController) ResendVerification(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	if err := uc.userService.ResendVerification(c.Request.Context(), req.Email); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
 		return
 	}
 
@@ -576,24 +614,23 @@ func (uc *UserController) ResendVerification(c *gin.Context) {
 // @Param search query string false "Search term"
 // @Param user_type query string false "User type filter"
 // @Param status query string false "Status filter"
+// @Param sort_by query string false "Sort field (created_at, full_name, email, last_login, status, user_type)" default(created_at)
+// @Param sort_order query string false "Sort order (asc or desc)" default(desc)
 // @Success 200 {object} models.ListUsersResponse
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/users [get]
 func (uc *UserController) GetAllUsers(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	var req models.ListUsersRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
 		return
 	}
 
@@ -607,7 +644,7 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 
 	response, err := uc.userRepository.ListUsers(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get users"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to get users")
 		return
 	}
 
@@ -620,20 +657,20 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} models.UserStatsResponse
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/users/stats [get]
 func (uc *UserController) GetUserStats(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
-	stats, err := uc.userRepository.GetUserStats(c.Request.Context())
+	stats, err := uc.userService.GetUserStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user statistics"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to get user statistics")
 		return
 	}
 
@@ -649,43 +686,40 @@ func (uc *UserController) GetUserStats(c *gin.Context) {
 // @Param id path string true "User ID"
 // @Param request body models.UpdateUserStatusRequest true "Status update data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/users/{id}/status [put]
 func (uc *UserController) UpdateUserStatus(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	userIDStr := c.Param("id")
 	userID, err := primitive.ObjectIDFromHex(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid user ID")
 		return
 	}
 
 	var req models.UpdateUserStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	// Get user info before updating for logging
 	user, err := uc.userRepository.GetByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
-	if err := uc.userRepository.UpdateUserStatus(c.Request.Context(), userID, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user status"})
+	if err := uc.userService.UpdateUserStatus(c.Request.Context(), userID, req.Status); err != nil {
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to update user status")
 		return
 	}
 
@@ -700,7 +734,6 @@ func (uc *UserController) UpdateUserStatus(c *gin.Context) {
 			activityType = models.ActivityUserSuspended
 		}
 
-		fmt.Printf("🔄 Attempting to log user status update activity...\n")
 		err := uc.activityLogService.LogUserActivity(
 			ctx,
 			activityType,
@@ -718,9 +751,7 @@ func (uc *UserController) UpdateUserStatus(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log user status update activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: User status update activity logged successfully\n")
+			slog.Error("Failed to log user status update activity", "error", err)
 		}
 	}()
 
@@ -731,6 +762,81 @@ func (uc *UserController) UpdateUserStatus(c *gin.Context) {
 	})
 }
 
+// @Summary Impersonate user (Admin only)
+// @Description Issue a short-lived access token scoped to the target user for support purposes
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.ImpersonationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/users/{id}/impersonate [post]
+func (uc *UserController) ImpersonateUser(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	// Prevent chaining impersonation sessions
+	if _, impersonating := middleware.GetImpersonatedBy(c); impersonating {
+		middleware.RespondError(c, http.StatusForbidden, "Cannot impersonate while impersonating")
+		return
+	}
+
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	response, err := uc.userService.ImpersonateUser(c.Request.Context(), adminID, targetID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to impersonate user", err)
+		return
+	}
+
+	// Log impersonation start
+	targetName := targetID.Hex()
+	switch target := response.User.(type) {
+	case *models.UserMahasiswa:
+		targetName = target.FullName
+	case *models.User:
+		targetName = target.FullName
+	}
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	adminEmail, _ := middleware.GetUserEmail(c)
+	// Read the admin from the request now; gin reuses the context once the handler returns
+	adminUserName, adminUserType := uc.getUserInfo(c)
+	go func() {
+		ctx := context.Background()
+		err := uc.activityLogService.LogUserActivity(
+			ctx,
+			models.ActivityUserImpersonated,
+			targetID.Hex(),
+			targetName,
+			adminID,
+			adminUserName,
+			adminUserType,
+			map[string]interface{}{
+				"admin_email": adminEmail,
+				"expires_in":  response.ExpiresIn,
+				"ip_address":  ipAddress,
+				"user_agent":  userAgent,
+			},
+		)
+
+		if err != nil {
+			slog.Error("Failed to log impersonation activity", "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, response)
+}
+
 // @Summary Get access requests (Admin only)
 // @Description Get paginated list of access requests
 // @Tags admin
@@ -741,23 +847,20 @@ func (uc *UserController) UpdateUserStatus(c *gin.Context) {
 // @Param status query string false "Status filter"
 // @Param type query string false "Type filter"
 // @Success 200 {object} models.ListAccessRequestsResponse
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/access-requests [get]
 func (uc *UserController) GetAccessRequests(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	var req models.ListAccessRequestsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid query parameters",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid query parameters", err)
 		return
 	}
 
@@ -780,7 +883,7 @@ func (uc *UserController) GetAccessRequests(c *gin.Context) {
 
 	userResponse, err := uc.userRepository.ListUsers(c.Request.Context(), userReq)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get access requests"})
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to get access requests")
 		return
 	}
 
@@ -818,44 +921,41 @@ func (uc *UserController) GetAccessRequests(c *gin.Context) {
 // @Param id path string true "Request ID"
 // @Param request body models.ApproveAccessRequest true "Approval data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/access-requests/{id}/approve [post]
 func (uc *UserController) ApproveAccessRequest(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	requestIDStr := c.Param("id")
 	requestID, err := primitive.ObjectIDFromHex(requestIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid request ID")
 		return
 	}
 
 	var req models.ApproveAccessRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	// Get user info before approving for logging
 	user, err := uc.userRepository.GetByID(c.Request.Context(), requestID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
 	// Update user status to active
-	if err := uc.userRepository.UpdateUserStatus(c.Request.Context(), requestID, models.UserStatusActive); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve access request"})
+	if err := uc.userService.UpdateUserStatus(c.Request.Context(), requestID, models.UserStatusActive); err != nil {
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to approve access request")
 		return
 	}
 
@@ -865,7 +965,6 @@ func (uc *UserController) ApproveAccessRequest(c *gin.Context) {
 		adminUserID, _ := middleware.GetUserID(c)
 		adminUserName, adminUserType := uc.getUserInfo(c)
 
-		fmt.Printf("🔄 Attempting to log access request approval activity...\n")
 		err := uc.activityLogService.LogUserActivity(
 			ctx,
 			models.ActivityUserAccessGranted,
@@ -884,9 +983,7 @@ func (uc *UserController) ApproveAccessRequest(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log access approval activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Access approval activity logged successfully\n")
+			slog.Error("Failed to log access approval activity", "error", err)
 		}
 	}()
 
@@ -905,44 +1002,41 @@ func (uc *UserController) ApproveAccessRequest(c *gin.Context) {
 // @Param id path string true "Request ID"
 // @Param request body models.RejectAccessRequest true "Rejection data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Router /admin/access-requests/{id}/reject [post]
 func (uc *UserController) RejectAccessRequest(c *gin.Context) {
 	// Check if user is admin
 	userType, exists := middleware.GetUserType(c)
 	if !exists || userType != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		middleware.RespondError(c, http.StatusForbidden, "Admin access required")
 		return
 	}
 
 	requestIDStr := c.Param("id")
 	requestID, err := primitive.ObjectIDFromHex(requestIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		middleware.RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid request ID")
 		return
 	}
 
 	var req models.RejectAccessRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request data",
-			"details": err.Error(),
-		})
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
 		return
 	}
 
 	// Get user info before rejecting for logging
 	user, err := uc.userRepository.GetByID(c.Request.Context(), requestID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		middleware.RespondError(c, http.StatusNotFound, "User not found")
 		return
 	}
 
 	// Update user status to rejected
-	if err := uc.userRepository.UpdateUserStatus(c.Request.Context(), requestID, models.UserStatusRejected); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject access request"})
+	if err := uc.userService.UpdateUserStatus(c.Request.Context(), requestID, models.UserStatusRejected); err != nil {
+		middleware.RespondError(c, http.StatusInternalServerError, "Failed to reject access request")
 		return
 	}
 
@@ -952,7 +1046,6 @@ func (uc *UserController) RejectAccessRequest(c *gin.Context) {
 		adminUserID, _ := middleware.GetUserID(c)
 		adminUserName, adminUserType := uc.getUserInfo(c)
 
-		fmt.Printf("🔄 Attempting to log access request rejection activity...\n")
 		err := uc.activityLogService.LogUserActivity(
 			ctx,
 			models.ActivityUserAccessRevoked,
@@ -971,9 +1064,7 @@ func (uc *UserController) RejectAccessRequest(c *gin.Context) {
 		)
 
 		if err != nil {
-			fmt.Printf("❌ ERROR: Failed to log access rejection activity: %v\n", err)
-		} else {
-			fmt.Printf("✅ SUCCESS: Access rejection activity logged successfully\n")
+			slog.Error("Failed to log access rejection activity", "error", err)
 		}
 	}()
 
@@ -1014,14 +1105,12 @@ func (uc *UserController) GithubOAuthCallback(c *gin.Context) {
 // handleOAuthCallback is a helper method to handle OAuth callbacks for all providers
 func (uc *UserController) handleOAuthCallback(c *gin.Context, provider string) {
 	code := c.Query("code")
-	state := c.Query("state")
 	errorParam := c.Query("error")
 
-	fmt.Printf("🔄 OAuth callback for %s: code=%s, state=%s, error=%s\n", provider, code, state, errorParam)
+	slog.InfoContext(c.Request.Context(), "OAuth callback", "provider", provider, "provider_error", errorParam)
 
 	// Force all OAuth logins to use "user" role only
 	userType := "user"
-	fmt.Printf("🔒 OAuth login forced to use 'user' role (state was: %s)\n", state)
 
 	// Get frontend URL from environment or use default (always 5173 now)
 	frontendURL := os.Getenv("FRONTEND_URL")
@@ -1048,14 +1137,12 @@ func (uc *UserController) handleOAuthCallback(c *gin.Context, provider string) {
 		UserType: models.UserType(userType),
 	}
 
-	fmt.Printf("🔄 Processing OAuth login for %s with user type %s\n", provider, userType)
 	response, err := uc.userService.OAuthLogin(c.Request.Context(), &request)
 	if err != nil {
-		fmt.Printf("❌ OAuth login failed: %v\n", err)
+		slog.WarnContext(c.Request.Context(), "OAuth login failed", "provider", provider, "error", err)
 		c.Redirect(302, fmt.Sprintf("%s/oauth-callback?error=%s", frontendURL, url.QueryEscape(err.Error())))
 		return
 	}
-	fmt.Printf("✅ OAuth login successful\n")
 
 	// Determine user type from response
 	var userTypeStr string
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize limits uploaded import spreadsheets to 5 MB
+const maxImportFileSize = 5 << 20
+
+type UserImportController struct {
+	userImportService  services.UserImportService
+	activityLogService services.ActivityLogService
+}
+
+func NewUserImportController(userImportService services.UserImportService, activityLogService services.ActivityLogService) *UserImportController {
+	return &UserImportController{
+		userImportService:  userImportService,
+		activityLogService: activityLogService,
+	}
+}
+
+// @Summary Bulk import mahasiswa (Admin only)
+// @Description Create mahasiswa accounts from a CSV or XLSX file with columns name, email, nim, faculty, major and optional password. Each row is validated and checked for duplicates across all user collections
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV or XLSX file"
+// @Param generate_passwords formData bool false "Generate passwords for rows without one"
+// @Param send_invitations formData bool false "Email login details to created users"
+// @Success 200 {object} models.ImportUsersResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /admin/users/import [post]
+func (uic *UserImportController) ImportUsers(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.ImportUsersRequest
+	if err := c.ShouldBind(&req); err != nil {
+		middleware.RespondInvalidRequest(c, "Invalid request data", err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Import file is required")
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		middleware.RespondError(c, http.StatusRequestEntityTooLarge, "Import file must be 5 MB or smaller")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		middleware.RespondError(c, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+
+	rows, err := utils.ReadSpreadsheet(fileHeader.Filename, file)
+	if err != nil {
+		middleware.RespondServiceError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Invitations link to the frontend login page
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	loginURL := strings.TrimRight(frontendURL, "/") + "/login"
+
+	response, err := uic.userImportService.ImportMahasiswa(c.Request.Context(), rows, &req, loginURL)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to import users", err)
+		return
+	}
+
+	// Log import
+	adminEmail, _ := middleware.GetUserEmail(c)
+	userType, _ := middleware.GetUserType(c)
+	ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+	activityLog := models.NewActivityLog(
+		models.ActivityDataImport,
+		"Imported mahasiswa accounts",
+		"user",
+		"",
+		fileHeader.Filename,
+		adminID,
+		adminEmail,
+		userType,
+	).SetDetails("total_rows", response.TotalRows).
+		SetDetails("created", response.Created).
+		SetDetails("duplicates", response.Duplicates).
+		SetDetails("invalid", response.Invalid).
+		SetDetails("failed", response.Failed).
+		SetDetails("invitations_sent", response.InvitationsSent).
+		SetClientInfo(ipAddress, userAgent)
+	uic.activityLogService.LogActivityAsync(activityLog)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// V2AdminController serves the /api/v2/admin routes. The route group checks that the caller is an
+// admin, so none of these handlers do.
+type V2AdminController struct {
+	userService        services.UserService
+	activityLogService services.ActivityLogService
+	settingsService    services.SettingsService
+	jobScheduler       services.JobScheduler
+}
+
+func NewV2AdminController(
+	userService services.UserService,
+	activityLogService services.ActivityLogService,
+	settingsService services.SettingsService,
+	jobScheduler services.JobScheduler,
+) *V2AdminController {
+	return &V2AdminController{
+		userService:        userService,
+		activityLogService: activityLogService,
+		settingsService:    settingsService,
+		jobScheduler:       jobScheduler,
+	}
+}
+
+// @Summary List users (Admin only)
+// @Description Accounts filtered by type, status or a search of name and email
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param search query string false "Search term"
+// @Param user_type query string false "Filter by user type"
+// @Param status query string false "Filter by status"
+// @Param sort_by query string false "Sort field" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
+// @Success 200 {object} models.Envelope{data=[]models.UserSummary,meta=models.PageMeta}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/users [get]
+func (ac *V2AdminController) ListUsers(c *gin.Context) {
+	var req models.V2ListUsersRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+
+	response, err := ac.userService.ListUsers(c.Request.Context(), &models.ListUsersRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Search:    req.Search,
+		UserType:  req.UserType,
+		Status:    req.Status,
+		SortBy:    req.SortBy,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list users", err)
+		return
+	}
+
+	middleware.RespondPage(c, response.Users, models.NewPageMeta(response.Page, response.Limit, response.Total))
+}
+
+// @Summary User statistics (Admin only)
+// @Description Account counts by type and status
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Envelope{data=models.UserStatsResponse}
+// @Router /v2/admin/users/stats [get]
+func (ac *V2AdminController) UserStats(c *gin.Context) {
+	stats, err := ac.userService.GetUserStats(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get user statistics", err)
+		return
+	}
+
+	middleware.RespondData(c, http.StatusOK, stats)
+}
+
+// @Summary List activity logs (Admin only)
+// @Description Activity log entries, newest first. Pass meta.next_cursor as cursor to page deep into the log without counting it
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param cursor query string false "Continue after this cursor instead of paging by number"
+// @Param type query string false "Filter by activity type"
+// @Param entity_type query string false "Filter by entity type"
+// @Param user_id query string false "Filter by user"
+// @Param date_from query string false "RFC 3339 start"
+// @Param date_to query string false "RFC 3339 end"
+// @Param success query bool false "Filter by outcome"
+// @Success 200 {object} models.Envelope{data=[]models.ActivityLog,meta=models.PageMeta}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/activity-logs [get]
+func (ac *V2AdminController) ListActivityLogs(c *gin.Context) {
+	var req models.V2ListActivityLogsRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+
+	response, err := ac.activityLogService.GetActivityLogs(c.Request.Context(), &models.GetActivityLogsRequest{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Cursor:     req.Cursor,
+		Type:       req.Type,
+		EntityType: req.EntityType,
+		UserID:     req.UserID,
+		DateFrom:   req.DateFrom,
+		DateTo:     req.DateTo,
+		Success:    req.Success,
+	})
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list activity logs", err)
+		return
+	}
+
+	meta := models.NewPageMeta(response.Page, response.Limit, response.Total)
+	meta.NextCursor = response.NextCursor
+	middleware.RespondPage(c, response.Activities, meta)
+}
+
+// @Summary Get runtime settings (Admin only)
+// @Description Settings admins can change without a restart. Until saved they come from the environment
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Envelope{data=models.RuntimeSettings}
+// @Router /v2/admin/settings [get]
+func (ac *V2AdminController) GetSettings(c *gin.Context) {
+	settings, err := ac.settingsService.GetSettings(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get settings", err)
+		return
+	}
+
+	middleware.RespondData(c, http.StatusOK, settings)
+}
+
+// @Summary Update runtime settings (Admin only)
+// @Description Change the settings given and leave the rest as they are
+// @Tags v2-admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateRuntimeSettingsRequest true "Settings to change"
+// @Success 200 {object} models.Envelope{data=models.RuntimeSettings}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/settings [patch]
+func (ac *V2AdminController) UpdateSettings(c *gin.Context) {
+	adminID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	adminEmail, _ := middleware.GetUserEmail(c)
+
+	var req models.UpdateRuntimeSettingsRequest
+	if !middleware.BindJSON(c, &req) {
+		return
+	}
+
+	settings, err := ac.settingsService.UpdateSettings(c.Request.Context(), adminEmail, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to update settings", err)
+		return
+	}
+
+	logSettingsUpdate(c, ac.activityLogService, adminID, adminEmail, &req)
+
+	middleware.RespondData(c, http.StatusOK, settings)
+}
+
+// @Summary List scheduled jobs (Admin only)
+// @Description Background jobs with their interval, next run and last run, and whether the answering instance runs them
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Envelope{data=models.ListScheduledJobsResponse}
+// @Router /v2/admin/jobs [get]
+func (ac *V2AdminController) ListJobs(c *gin.Context) {
+	response, err := ac.jobScheduler.ListJobs(c.Request.Context())
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list scheduled jobs", err)
+		return
+	}
+
+	middleware.RespondData(c, http.StatusOK, response)
+}
+
+// @Summary List a job's runs (Admin only)
+// @Description Run history of one background job, newest first
+// @Tags v2-admin
+// @Produce json
+// @Security BearerAuth
+// @Param job_name path string true "Job name"
+// @Param limit query int false "Number of runs" default(20)
+// @Success 200 {object} models.Envelope{data=[]models.JobRun}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Failure 404 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/jobs/{job_name}/runs [get]
+func (ac *V2AdminController) ListJobRuns(c *gin.Context) {
+	var req models.V2ListJobRunsRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+
+	response, err := ac.jobScheduler.ListRuns(c.Request.Context(), &models.ListJobRunsRequest{
+		Job:   c.Param("job_name"),
+		Limit: req.Limit,
+	})
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list job runs", err)
+		return
+	}
+
+	middleware.RespondPage(c, response.Runs, nil)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// V2ModuleController serves modules under /api/v2. Learners and admins read modules through routes
+// of their own, so what a handler answers never depends on who is asking.
+type V2ModuleController struct {
+	moduleService services.ModuleService
+}
+
+func NewV2ModuleController(moduleService services.ModuleService) *V2ModuleController {
+	return &V2ModuleController{
+		moduleService: moduleService,
+	}
+}
+
+// @Summary List modules
+// @Description Published modules in order. Those the signed-in learner cannot read yet are marked locked
+// @Tags v2-modules
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param search query string false "Search term"
+// @Success 200 {object} models.Envelope{data=[]models.Module,meta=models.PageMeta}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/modules [get]
+func (mc *V2ModuleController) ListModules(c *gin.Context) {
+	var req models.V2ListModulesRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+	published := true
+	req.Published = &published
+
+	response, err := mc.listModules(c, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list modules", err)
+		return
+	}
+	if err := mc.moduleService.ApplyGating(c.Request.Context(), response.Modules, learnerID(c)); err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list modules", err)
+		return
+	}
+
+	middleware.RespondPage(c, response.Modules, models.NewPageMeta(response.Page, response.Limit, response.Total))
+}
+
+// @Summary Get module
+// @Description A published module with its submodules. Learners are refused a module whose prerequisites they have not completed, and see its locked submodules without content
+// @Tags v2-modules
+// @Produce json
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} models.Envelope{data=models.Module}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Failure 403 {object} models.Envelope{error=models.ErrorResponse}
+// @Failure 404 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/modules/{module_id} [get]
+func (mc *V2ModuleController) GetModule(c *gin.Context) {
+	moduleID, ok := middleware.PathObjectID(c, "module_id", "module")
+	if !ok {
+		return
+	}
+
+	module, err := mc.moduleService.GetModuleForLearner(c.Request.Context(), moduleID, learnerID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrModuleLocked) {
+			middleware.RespondErrorWithDetails(c, http.StatusForbidden, models.ErrorCodeContentLocked, "Module is locked", gin.H{
+				"missing_prerequisites": module.MissingPrerequisites,
+			})
+			return
+		}
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get module", err)
+		return
+	}
+
+	middleware.RespondData(c, http.StatusOK, module)
+}
+
+// @Summary List modules (Admin only)
+// @Description Every module in order, published or not
+// @Tags v2-modules
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param search query string false "Search term"
+// @Param published query bool false "Filter by published status"
+// @Success 200 {object} models.Envelope{data=[]models.Module,meta=models.PageMeta}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/modules [get]
+func (mc *V2ModuleController) AdminListModules(c *gin.Context) {
+	var req models.V2ListModulesRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+
+	response, err := mc.listModules(c, &req)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list modules", err)
+		return
+	}
+
+	middleware.RespondPage(c, response.Modules, models.NewPageMeta(response.Page, response.Limit, response.Total))
+}
+
+// @Summary Get module (Admin only)
+// @Description A module with all its submodules and their content, published or not
+// @Tags v2-modules
+// @Produce json
+// @Security BearerAuth
+// @Param module_id path string true "Module ID"
+// @Success 200 {object} models.Envelope{data=models.Module}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Failure 404 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/admin/modules/{module_id} [get]
+func (mc *V2ModuleController) AdminGetModule(c *gin.Context) {
+	moduleID, ok := middleware.PathObjectID(c, "module_id", "module")
+	if !ok {
+		return
+	}
+
+	module, err := mc.moduleService.GetModuleByID(c.Request.Context(), moduleID)
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to get module", err)
+		return
+	}
+
+	middleware.RespondData(c, http.StatusOK, module)
+}
+
+func (mc *V2ModuleController) listModules(c *gin.Context, req *models.V2ListModulesRequest) (*models.GetModulesResponse, error) {
+	return mc.moduleService.GetAllModules(c.Request.Context(), &models.GetModulesRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Search:    req.Search,
+		Published: req.Published,
+	})
+}
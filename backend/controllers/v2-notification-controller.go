@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"net/http"
+
+	"backend/middleware"
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type V2NotificationController struct {
+	notificationService services.NotificationService
+}
+
+func NewV2NotificationController(notificationService services.NotificationService) *V2NotificationController {
+	return &V2NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// @Summary List notifications
+// @Description The authenticated user's notifications, newest first, with the unread count in the meta
+// @Tags v2-notifications
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param unread_only query bool false "Only unread notifications"
+// @Success 200 {object} models.Envelope{data=[]models.Notification,meta=models.PageMeta}
+// @Failure 400 {object} models.Envelope{error=models.ErrorResponse}
+// @Failure 401 {object} models.Envelope{error=models.ErrorResponse}
+// @Router /v2/notifications [get]
+func (nc *V2NotificationController) ListNotifications(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		middleware.RespondError(c, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req models.V2ListNotificationsRequest
+	if !middleware.BindQuery(c, &req) {
+		return
+	}
+
+	response, err := nc.notificationService.ListNotifications(c.Request.Context(), userID, &models.ListNotificationsRequest{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		UnreadOnly: req.UnreadOnly,
+	})
+	if err != nil {
+		middleware.RespondErrorDetails(c, http.StatusInternalServerError, "Failed to list notifications", err)
+		return
+	}
+
+	meta := models.NewPageMeta(response.Page, response.Limit, response.Total)
+	meta.UnreadCount = &response.UnreadCount
+	middleware.RespondPage(c, response.Notifications, meta)
+}
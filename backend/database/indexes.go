@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexBuildTimeout bounds the index step on startup; cmd/ensure-indexes runs without one
+const indexBuildTimeout = 2 * time.Minute
+
+// EnsureIndexes creates the capped collections and every index the repositories rely on that is
+// missing. Indexes are built one at a time, so one that cannot be built, say for duplicate documents
+// under a unique key, is logged and the rest are still built. It returns an error naming how many
+// failed once all were tried.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	start := time.Now()
+
+	if err := fixExistingIndexes(ctx, db); err != nil {
+		slog.WarnContext(ctx, "Failed to fix existing indexes", "error", err)
+	}
+	if err := createCappedCollections(ctx, db); err != nil {
+		slog.WarnContext(ctx, "Failed to create capped collections", "error", err)
+	}
+
+	ix := &indexBuild{}
+	createIndexes(ctx, db, ix)
+
+	slog.InfoContext(ctx, "MongoDB indexes ready",
+		"created", ix.created,
+		"existing", ix.existing,
+		"failed", ix.failed,
+		"duration", time.Since(start),
+	)
+	if ix.failed > 0 {
+		return fmt.Errorf("%d of %d indexes could not be built", ix.failed, ix.created+ix.existing+ix.failed)
+	}
+	return nil
+}
+
+// indexBuild tallies the indexes an EnsureIndexes run built, found already built and failed to build
+type indexBuild struct {
+	created  int
+	existing int
+	failed   int
+}
+
+// ensure builds the indexes a collection is missing
+func (b *indexBuild) ensure(ctx context.Context, collection *mongo.Collection, indexes []mongo.IndexModel) {
+	// Listing fails for a collection that does not exist yet; creating an index creates it
+	existing, _ := collection.Indexes().ListSpecifications(ctx)
+	names := make(map[string]bool, len(existing))
+	for _, spec := range existing {
+		names[spec.Name] = true
+	}
+
+	for _, index := range indexes {
+		name := indexName(index)
+
+		// Creating an index that exists with the same keys and options does nothing, and one that
+		// exists with other options fails, so existing indexes are still sent to catch drift
+		start := time.Now()
+		if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+			b.failed++
+			slog.WarnContext(ctx, "Failed to build MongoDB index",
+				"collection", collection.Name(),
+				"index", name,
+				"error", err,
+				"hint", indexErrorHint(err),
+			)
+			continue
+		}
+
+		if names[name] {
+			b.existing++
+			continue
+		}
+		b.created++
+		slog.InfoContext(ctx, "Built MongoDB index",
+			"collection", collection.Name(),
+			"index", name,
+			"duration", time.Since(start),
+		)
+	}
+}
+
+// ensureOne builds an index if the collection is missing it
+func (b *indexBuild) ensureOne(ctx context.Context, collection *mongo.Collection, index mongo.IndexModel) {
+	b.ensure(ctx, collection, []mongo.IndexModel{index})
+}
+
+// indexName is the name MongoDB gives an index: the one set in its options, or else its keys and
+// their directions joined by underscores
+func indexName(index mongo.IndexModel) string {
+	if index.Options != nil && index.Options.Name != nil {
+		return *index.Options.Name
+	}
+
+	keys, ok := index.Keys.(bson.D)
+	if !ok {
+		return fmt.Sprint(index.Keys)
+	}
+	parts := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// indexErrorHint says what to do about the index build failures an operator can fix
+func indexErrorHint(err error) string {
+	var cmdErr mongo.CommandError
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return "documents share a value the index requires to be unique; resolve the duplicates and run again"
+	case errors.As(err, &cmdErr) && (cmdErr.Name == "IndexOptionsConflict" || cmdErr.Name == "IndexKeySpecsConflict"):
+		return "an index with this name or these keys exists with other options; drop it and run again"
+	default:
+		return ""
+	}
+}
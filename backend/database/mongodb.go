@@ -2,8 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"backend/models"
@@ -25,63 +26,85 @@ func ConnectMongoDB(config models.DatabaseConfig) (*mongo.Database, error) {
 		SetSocketTimeout(30 * time.Second).          // Socket timeout
 		SetHeartbeatInterval(10 * time.Second).      // Heartbeat for Atlas
 		SetRetryWrites(true).                        // Enable retry writes (Atlas default)
-		SetRetryReads(true)                          // Enable retry reads
+		SetRetryReads(true).                         // Enable retry reads
+		SetMonitor(commandTracer())                  // Trace commands sent while handling requests
 
 	// Connect to MongoDB Atlas
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	log.Println("Connecting to MongoDB Atlas...")
+	slog.Info("Connecting to MongoDB Atlas")
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB Atlas: %w", err)
 	}
 
 	// Test the connection with ping
-	log.Println("Testing MongoDB Atlas connection...")
+	slog.Info("Testing MongoDB Atlas connection")
 	err = client.Ping(ctx, nil)
 	if err != nil {
 		client.Disconnect(ctx)
 		return nil, fmt.Errorf("failed to ping MongoDB Atlas: %w", err)
 	}
 
-	log.Printf("Successfully connected to MongoDB Atlas database: %s", config.Name)
+	slog.Info("Connected to MongoDB Atlas", "database", config.Name)
 
 	db := client.Database(config.Name)
 
-	// Fix existing indexes (drop and recreate with sparse option)
-	if err := fixExistingIndexes(ctx, db); err != nil {
-		log.Printf("Warning: Failed to fix existing indexes: %v", err)
-	}
-
-	// Create indexes
-	if err := createIndexes(ctx, db); err != nil {
-		log.Printf("Warning: Failed to create indexes: %v", err)
+	if config.EnsureIndexes {
+		indexCtx, cancel := context.WithTimeout(context.Background(), indexBuildTimeout)
+		defer cancel()
+		if err := EnsureIndexes(indexCtx, db); err != nil {
+			slog.Warn("Failed to ensure MongoDB indexes", "error", err)
+		}
 	}
 
 	return db, nil
 }
 
+// fixExistingIndexes drops account email indexes built before they were sparse, so they are
+// rebuilt sparse
 func fixExistingIndexes(ctx context.Context, db *mongo.Database) error {
 	collections := []string{"users", "mahasiswa", "admins"}
 
 	for _, collectionName := range collections {
 		collection := db.Collection(collectionName)
 
-		// Try to drop the existing email index if it exists
-		_, err := collection.Indexes().DropOne(ctx, "email_1")
+		specs, err := collection.Indexes().ListSpecifications(ctx)
 		if err != nil {
-			// Index might not exist, which is fine
-			log.Printf("Note: Could not drop email index for %s collection (might not exist): %v", collectionName, err)
-		} else {
-			log.Printf("Dropped existing email index for %s collection", collectionName)
+			return fmt.Errorf("failed to list %s indexes: %w", collectionName, err)
+		}
+		for _, spec := range specs {
+			if spec.Name != "email_1" || (spec.Sparse != nil && *spec.Sparse) {
+				continue
+			}
+			if _, err := collection.Indexes().DropOne(ctx, spec.Name); err != nil {
+				return fmt.Errorf("failed to drop %s email index: %w", collectionName, err)
+			}
+			slog.Info("Dropped non-sparse email index", "collection", collectionName)
 		}
 	}
 
 	return nil
 }
 
-func createIndexes(ctx context.Context, db *mongo.Database) error {
+// sessionTelemetryMaxBytes bounds the client telemetry kept; the oldest batches go first
+const sessionTelemetryMaxBytes = 256 << 20
+
+// createCappedCollections creates the collections that keep only their most recent documents.
+// Existing collections are left as they are.
+func createCappedCollections(ctx context.Context, db *mongo.Database) error {
+	err := db.CreateCollection(ctx, "session_telemetry", options.CreateCollection().SetCapped(true).SetSizeInBytes(sessionTelemetryMaxBytes))
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists") {
+		return fmt.Errorf("failed to create session telemetry collection: %w", err)
+	}
+	return nil
+}
+
+// createIndexes declares the unique and query indexes the repositories rely on, building the
+// missing ones through ix
+func createIndexes(ctx context.Context, db *mongo.Database, ix *indexBuild) {
 	// Users collection indexes
 	usersCollection := db.Collection("users")
 
@@ -146,10 +169,7 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		resetTokenExpiryIndex,
 	}
 
-	_, err := usersCollection.Indexes().CreateMany(ctx, userIndexes)
-	if err != nil {
-		return fmt.Errorf("failed to create users indexes: %w", err)
-	}
+	ix.ensure(ctx, usersCollection, userIndexes)
 
 	// Mahasiswa collection indexes
 	mahasiswaCollection := db.Collection("mahasiswa")
@@ -215,11 +235,17 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		mahasiswaRefreshTokenIndex,
 	}
 
-	_, err = mahasiswaCollection.Indexes().CreateMany(ctx, mahasiswaIndexes)
-	if err != nil {
-		return fmt.Errorf("failed to create mahasiswa indexes: %w", err)
+	// Accounts created without a NIM used to store an empty string, which the sparse index still
+	// counts; drop those so only real NIMs must be unique
+	if _, err := mahasiswaCollection.UpdateMany(ctx,
+		bson.M{"mahasiswa_id": ""},
+		bson.M{"$unset": bson.M{"mahasiswa_id": ""}},
+	); err != nil {
+		slog.Warn("Failed to clear empty NIMs", "error", err)
 	}
 
+	ix.ensure(ctx, mahasiswaCollection, mahasiswaIndexes)
+
 	// Admin collection indexes
 	adminCollection := db.Collection("admins")
 
@@ -277,11 +303,531 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		adminRefreshTokenIndex,
 	}
 
-	_, err = adminCollection.Indexes().CreateMany(ctx, adminIndexes)
-	if err != nil {
-		return fmt.Errorf("failed to create admin indexes: %w", err)
+	ix.ensure(ctx, adminCollection, adminIndexes)
+
+	// Bookmarks collection indexes
+	bookmarksCollection := db.Collection("bookmarks")
+
+	// One bookmark per user per question
+	bookmarkUserQuestionIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "question_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
 	}
 
-	log.Println("Successfully created MongoDB indexes")
-	return nil
+	bookmarkUserCreatedIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+
+	ix.ensure(ctx, bookmarksCollection, []mongo.IndexModel{
+		bookmarkUserQuestionIndex,
+		bookmarkUserCreatedIndex,
+	})
+
+	// Flashcard collections indexes
+	flashcardDecksCollection := db.Collection("flashcard_decks")
+	ix.ensureOne(ctx, flashcardDecksCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "source", Value: 1}, {Key: "source_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	flashcardsCollection := db.Collection("flashcards")
+
+	// One card per key point / question within a deck
+	flashcardDeckSourceIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "deck_id", Value: 1}, {Key: "source_key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	// Due-card lookups
+	flashcardDueIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "schedule.due_at", Value: 1}},
+	}
+
+	ix.ensure(ctx, flashcardsCollection, []mongo.IndexModel{
+		flashcardDeckSourceIndex,
+		flashcardDueIndex,
+	})
+
+	// Question memory indexes: one spaced-repetition schedule per student and question, looked up by due date
+	questionMemoriesCollection := db.Collection("question_memories")
+	ix.ensure(ctx, questionMemoriesCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "question_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "schedule.due_at", Value: 1}},
+		},
+	})
+
+	// Result thread collections indexes
+	resultThreadsCollection := db.Collection("result_threads")
+
+	// One thread per detailed result
+	resultThreadResultIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "result_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	resultThreadStudentIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "student_id", Value: 1}, {Key: "last_message_at", Value: -1}},
+	}
+
+	resultThreadActivityIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "last_message_at", Value: -1}},
+	}
+
+	ix.ensure(ctx, resultThreadsCollection, []mongo.IndexModel{
+		resultThreadResultIndex,
+		resultThreadStudentIndex,
+		resultThreadActivityIndex,
+	})
+
+	ix.ensureOne(ctx, db.Collection("result_thread_messages"), mongo.IndexModel{
+		Keys: bson.D{{Key: "thread_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+
+	// Notifications collection indexes
+	ix.ensureOne(ctx, db.Collection("notifications"), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	// Exam collections indexes
+	ix.ensureOne(ctx, db.Collection("exam_sittings"), mongo.IndexModel{
+		Keys: bson.D{{Key: "scheduled_start", Value: -1}},
+	})
+
+	examParticipantsCollection := db.Collection("exam_participants")
+
+	// One assignment per student per sitting
+	examParticipantUserIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "sitting_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	// One student per seat; assignments without a seat number are exempt
+	examParticipantSeatIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "sitting_id", Value: 1}, {Key: "venue", Value: 1}, {Key: "room", Value: 1}, {Key: "seat", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"seat": bson.M{"$gt": ""}}),
+	}
+
+	examParticipantUserLookupIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+	}
+
+	ix.ensure(ctx, examParticipantsCollection, []mongo.IndexModel{
+		examParticipantUserIndex,
+		examParticipantSeatIndex,
+		examParticipantUserLookupIndex,
+	})
+
+	// Offline exam packages are listed per sitting
+	examPackagesCollection := db.Collection("exam_offline_packages")
+	ix.ensureOne(ctx, examPackagesCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "sitting_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+
+	// A sitting keeps one printed paper set
+	ix.ensureOne(ctx, db.Collection("exam_paper_sets"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "sitting_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Invitations are looked up by email when checking for an active one, and listed newest first
+	invitationsCollection := db.Collection("invitations")
+	ix.ensure(ctx, invitationsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	})
+
+	// API keys are looked up by hash on every integration request
+	apiKeysCollection := db.Collection("api_keys")
+	ix.ensure(ctx, apiKeysCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	})
+
+	// Only one JWT signing key may be current; the partial unique index makes concurrent
+	// rotations or first-start bootstraps fail instead of producing two
+	signingKeysCollection := db.Collection("jwt_signing_keys")
+	ix.ensure(ctx, signingKeysCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "kid", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"status": "current"}),
+		},
+	})
+
+	// Each question has at most one stored audio rendition
+	questionAudioCollection := db.Collection("question_audio")
+	ix.ensureOne(ctx, questionAudioCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "question_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Moderation queues and release checks look up a sitting's flagged results; sparse because
+	// most results are not from exam sittings
+	detailedResultsCollection := db.Collection("detailed_quiz_results")
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "exam_sitting_id", Value: 1}, {Key: "moderation.status", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+
+	// The essay grading queue only holds results with essays still to grade
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "pending_essays", Value: 1}, {Key: "submitted_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"pending_essays": bson.M{"$gt": 0}}),
+	})
+
+	// A question can be reported once per session; the queue is filtered by status and question
+	questionReportsCollection := db.Collection("question_reports")
+	ix.ensure(ctx, questionReportsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "session_id", Value: 1}, {Key: "question_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "question_id", Value: 1}}},
+	})
+
+	// Cohort comparisons select finished results by submission date
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "submitted_at", Value: 1}, {Key: "quiz_type", Value: 1}},
+	})
+
+	// Item analysis reads every result that asked a question
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "question_results.question_id", Value: 1}, {Key: "submitted_at", Value: 1}},
+	})
+
+	// The cleanup job scans in-progress sessions by start time for ones past their time limit
+	quizSessionsCollection := db.Collection("quiz_sessions")
+	ix.ensureOne(ctx, quizSessionsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "start_time", Value: 1}},
+	})
+
+	// The cleanup job finds sessions gone idle; abandonment analytics read sessions by start time
+	ix.ensure(ctx, quizSessionsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "start_time", Value: 1}, {Key: "quiz_type", Value: 1}}},
+	})
+
+	// Attempt limits count a student's sessions per template, and retake cooldowns find their latest
+	// session of a quiz type; stats read their earlier results of a template
+	ix.ensure(ctx, quizSessionsCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "template_id", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "quiz_type", Value: 1}, {Key: "start_time", Value: -1}},
+		},
+	})
+	ix.ensureOne(ctx, db.Collection("quiz_results"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "template_id", Value: 1}, {Key: "completed_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+
+	// At-risk analysis reads each student's results in submission order
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "submitted_at", Value: -1}},
+	})
+
+	// One at-risk record per student, listed by risk
+	atRiskCollection := db.Collection("at_risk_students")
+	ix.ensure(ctx, atRiskCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "risk_score", Value: -1}},
+		},
+	})
+
+	// Each version has one set of release notes; clients list published notes newest first
+	changelogCollection := db.Collection("changelog")
+	ix.ensure(ctx, changelogCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "version", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "published", Value: 1}, {Key: "audiences", Value: 1}, {Key: "published_at", Value: -1}},
+		},
+	})
+
+	// A result gets one certificate; verification looks certificates up by their printed code
+	certificatesCollection := db.Collection("certificates")
+	ix.ensure(ctx, certificatesCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "result_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	// Short codes are unique and looked up on every redirect; admins list links newest first
+	shortLinksCollection := db.Collection("short_links")
+	ix.ensure(ctx, shortLinksCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	})
+
+	// Students join class quizzes by code; boards load every session joined from one
+	classQuizzesCollection := db.Collection("class_quizzes")
+	ix.ensure(ctx, classQuizzesCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "join_code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+	})
+
+	ix.ensureOne(ctx, quizSessionsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "class_quiz_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+
+	// Teams are joined by code and listed per member; team results are listed for every member
+	// and grouped by team for the leaderboard
+	teamsCollection := db.Collection("teams")
+	ix.ensure(ctx, teamsCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "join_code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "members.user_id", Value: 1}},
+		},
+	})
+
+	ix.ensure(ctx, detailedResultsCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "team_member_ids", Value: 1}, {Key: "submitted_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "team_id", Value: 1}, {Key: "submitted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	})
+
+	// Student question proposals: the admin review queue and each student's own list
+	questionProposalsCollection := db.Collection("question_proposals")
+	ix.ensure(ctx, questionProposalsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "proposed_by", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	// Result deliveries look up the active department webhooks
+	resultWebhooksCollection := db.Collection("result_webhooks")
+	ix.ensureOne(ctx, resultWebhooksCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "active", Value: 1}},
+	})
+
+	// Quizzes started without a template look up their type's default
+	quizTemplatesCollection := db.Collection("quiz_templates")
+	ix.ensureOne(ctx, quizTemplatesCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "quiz_type", Value: 1}, {Key: "is_default", Value: 1}},
+	})
+
+	// Question lists filter by author and reviewer; custom quizzes draw by tag
+	questionsCollection := db.Collection("questions")
+	ix.ensure(ctx, questionsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_by", Value: 1}}},
+		{Keys: bson.D{{Key: "contributed_by", Value: 1}}},
+		{Keys: bson.D{{Key: "reviews.reviewer_id", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "is_active", Value: 1}}},
+	})
+
+	// Question search ranks by this text index, weighting titles over options over explanations.
+	// Content mixes Indonesian and English, so words are matched as written rather than stemmed.
+	// Search falls back to pattern matching without the index.
+	ix.ensureOne(ctx, questionsCollection, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "options.text", Value: "text"},
+			{Key: "explanation", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("question_text_search").
+			SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "options.text", Value: 4}, {Key: "explanation", Value: 1}}).
+			SetDefaultLanguage("none").
+			SetLanguageOverride("text_language"),
+	})
+
+	// Telemetry is looked up per session and by when it arrived
+	sessionTelemetryCollection := db.Collection("session_telemetry")
+	ix.ensure(ctx, sessionTelemetryCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "session_id", Value: 1}}},
+		{Keys: bson.D{{Key: "received_at", Value: 1}}},
+	})
+
+	// Embed tokens are looked up on every widget request
+	embedWidgetsCollection := db.Collection("embed_widgets")
+	ix.ensure(ctx, embedWidgetsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	// Difficulty suggestions: at most one pending per question, and the admin review queue
+	difficultySuggestionsCollection := db.Collection("difficulty_suggestions")
+	ix.ensure(ctx, difficultySuggestionsCollection, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "question_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"status": models.SuggestionStatusPending}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+
+	// Question comments are listed per question, oldest first
+	ix.ensureOne(ctx, db.Collection("question_comments"), mongo.IndexModel{
+		Keys: bson.D{{Key: "question_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+
+	// One progress record per learner per module
+	ix.ensureOne(ctx, db.Collection("module_progress"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "module_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Module search ranks by this text index, weighting names over descriptions over content, for
+	// modules and submodules alike. Like question search, words are matched as written.
+	modulesCollection := db.Collection("modules")
+	ix.ensureOne(ctx, modulesCollection, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "content", Value: "text"},
+			{Key: "sub_modules.name", Value: "text"},
+			{Key: "sub_modules.description", Value: "text"},
+			{Key: "sub_modules.content", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("module_text_search").
+			SetWeights(bson.D{
+				{Key: "name", Value: 10},
+				{Key: "sub_modules.name", Value: 8},
+				{Key: "description", Value: 4},
+				{Key: "sub_modules.description", Value: 3},
+				{Key: "content", Value: 1},
+				{Key: "sub_modules.content", Value: 1},
+			}).
+			SetDefaultLanguage("none").
+			SetLanguageOverride("text_language"),
+	})
+
+	// Module attachments are listed per module
+	moduleAttachmentsCollection := db.Collection("module_attachments")
+	ix.ensureOne(ctx, moduleAttachmentsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "module_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+
+	// Module views are aggregated per module over a range of days
+	moduleViewsCollection := db.Collection("module_views")
+	ix.ensureOne(ctx, moduleViewsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "module_id", Value: 1}, {Key: "viewed_at", Value: 1}},
+	})
+
+	// Module comments are paged per submodule discussion, replies are read per thread, and reported
+	// ones are queued for moderation
+	moduleCommentsCollection := db.Collection("module_comments")
+	ix.ensure(ctx, moduleCommentsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "module_id", Value: 1}, {Key: "submodule_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "report_count", Value: -1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"report_count": bson.M{"$gt": 0}}),
+		},
+	})
+
+	// Module images are looked up and cleaned up per module
+	moduleImagesCollection := db.Collection("module_images")
+	ix.ensureOne(ctx, moduleImagesCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "module_id", Value: 1}},
+	})
+
+	// Module revisions are numbered per module; the unique key lets only one edit claim each number
+	moduleRevisionsCollection := db.Collection("module_revisions")
+	ix.ensureOne(ctx, moduleRevisionsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "module_id", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Question versions are numbered per question; the unique key lets only one edit claim each number
+	questionVersionsCollection := db.Collection("question_versions")
+	ix.ensureOne(ctx, questionVersionsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "question_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Version history counts the sessions built from each version of a question
+	ix.ensureOne(ctx, quizSessionsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "questions.question_id", Value: 1}},
+	})
+
+	// Sessions are resumed by their token
+	ix.ensureOne(ctx, quizSessionsCollection, mongo.IndexModel{
+		Keys:    bson.D{{Key: "session_token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	// Quiz history lists a user's results newest first; results are read back per session
+	ix.ensureOne(ctx, db.Collection("quiz_results"), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "completed_at", Value: -1}},
+	})
+	ix.ensureOne(ctx, detailedResultsCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "session_id", Value: 1}},
+	})
+
+	// Each user has one stats document, upserted by user; achievements are listed newest first
+	ix.ensureOne(ctx, db.Collection("user_stats"), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	ix.ensureOne(ctx, db.Collection("achievements"), mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "earned_at", Value: -1}},
+	})
+
+	// The activity log is paged newest first, overall, per admin and per type, and pruned by age
+	ix.ensure(ctx, db.Collection("activity_logs"), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "performed_by", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+
+	// Access requests are looked up per user and reviewed by status, newest first
+	ix.ensure(ctx, db.Collection("access_requests"), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "requested_at", Value: -1}}},
+	})
+
+	// Quizzes draw active questions by type or difficulty; admin lists page them newest first
+	ix.ensure(ctx, questionsCollection, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "is_active", Value: 1}, {Key: "review_status", Value: 1}}},
+		{Keys: bson.D{{Key: "difficulty", Value: 1}, {Key: "is_active", Value: 1}, {Key: "review_status", Value: 1}}},
+		{Keys: bson.D{{Key: "is_active", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+
+	// Published modules are listed in their set order
+	ix.ensureOne(ctx, modulesCollection, mongo.IndexModel{
+		Keys: bson.D{{Key: "is_published", Value: 1}, {Key: "order", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+
+	// Job run history is listed newest first, per job or overall, and expires on its own
+	ix.ensure(ctx, db.Collection("job_runs"), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
 }
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandTracer records a client span for each MongoDB command sent while handling a traced
+// request, so repository calls show under the request that made them. Commands from background
+// jobs, which run outside any trace, are left out. Spans name the command and collection only;
+// filters and documents hold learners' data and are never recorded.
+func commandTracer() *event.CommandMonitor {
+	var spans sync.Map // request ID of the command → trace.Span
+
+	finish := func(requestID int64, failure string) {
+		value, ok := spans.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		if failure != "" {
+			utils.RecordSpanError(span, errors.New(failure))
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if !trace.SpanFromContext(ctx).IsRecording() {
+				return
+			}
+			collection := commandCollection(e.Command, e.CommandName)
+			name := e.CommandName
+			if collection != "" {
+				name += " " + collection
+			}
+			_, span := utils.StartClientSpan(ctx, name,
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.namespace", e.DatabaseName),
+				attribute.String("db.operation.name", e.CommandName),
+				attribute.String("db.collection.name", collection),
+				attribute.String("server.address", serverAddress(e.ConnectionID)),
+			)
+			spans.Store(e.RequestID, span)
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			finish(e.RequestID, "")
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			finish(e.RequestID, e.Failure)
+		},
+	}
+}
+
+// commandCollection is the collection a command runs on: the value of its first field for most
+// commands, and the "collection" field for getMore
+func commandCollection(command bson.Raw, commandName string) string {
+	if collection, ok := command.Lookup("collection").StringValueOK(); ok && commandName == "getMore" {
+		return collection
+	}
+	elements, err := command.Elements()
+	if err != nil || len(elements) == 0 {
+		return ""
+	}
+	collection, _ := elements[0].Value().StringValueOK()
+	return collection
+}
+
+// serverAddress drops the driver's connection counter from a connection ID such as "host:27017[-4]"
+func serverAddress(connectionID string) string {
+	address, _, _ := strings.Cut(connectionID, "[")
+	return address
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"net/http"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+//go:generate go tool gqlgen generate
+
+// Resolver answers dashboard queries through the same services as the REST API, so every field
+// returns what the matching REST call would
+type Resolver struct {
+	userService        services.UserService
+	moduleService      services.ModuleService
+	questionService    services.QuestionService
+	quizSessionService services.QuizSessionService
+	activityLogService services.ActivityLogService
+}
+
+func NewResolver(
+	userService services.UserService,
+	moduleService services.ModuleService,
+	questionService services.QuestionService,
+	quizSessionService services.QuizSessionService,
+	activityLogService services.ActivityLogService,
+) *Resolver {
+	return &Resolver{
+		userService:        userService,
+		moduleService:      moduleService,
+		questionService:    questionService,
+		quizSessionService: quizSessionService,
+		activityLogService: activityLogService,
+	}
+}
+
+// validateArguments checks a field's arguments against the rules of the REST request they fill in
+func validateArguments(req any) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeValidation, "Invalid arguments: "+err.Error())
+	}
+	return nil
+}
+
+// value is what an optional argument holds, or the zero value when it was left out
+func value[T any](arg *T) T {
+	if arg == nil {
+		var zero T
+		return zero
+	}
+	return *arg
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MarshalObjectID writes an ObjectID as its hex string, as the REST API does
+func MarshalObjectID(id primitive.ObjectID) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		io.WriteString(w, strconv.Quote(id.Hex()))
+	})
+}
+
+func UnmarshalObjectID(v any) (primitive.ObjectID, error) {
+	hex, ok := v.(string)
+	if !ok {
+		return primitive.NilObjectID, errors.New("ObjectID must be a string")
+	}
+	return primitive.ObjectIDFromHex(hex)
+}
+
+// MarshalCounts writes counts by key as a JSON object
+func MarshalCounts(counts map[string]int64) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		if counts == nil {
+			counts = map[string]int64{}
+		}
+		data, _ := json.Marshal(counts) // A map of strings to integers always encodes
+		w.Write(data)
+	})
+}
+
+func UnmarshalCounts(v any) (map[string]int64, error) {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return nil, errors.New("Counts must be an object")
+	}
+	counts := make(map[string]int64, len(object))
+	for key, value := range object {
+		count, err := graphql.UnmarshalInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		counts[key] = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,214 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.80
+
+import (
+	"backend/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ByType is the resolver for the by_type field.
+func (r *activityStatsResolver) ByType(ctx context.Context, obj *models.ActivityStats) (map[string]int64, error) {
+	byType := make(map[string]int64, len(obj.ByType))
+	for activityType, count := range obj.ByType {
+		byType[string(activityType)] = count
+	}
+	return byType, nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, page int, limit int, search *string, userType *string, status *string, sortBy string, sortOrder string) (*UserSummaryPage, error) {
+	req := &models.V2ListUsersRequest{
+		PageRequest: models.PageRequest{Page: page, Limit: limit},
+		Search:      value(search),
+		UserType:    models.UserType(value(userType)),
+		Status:      models.UserStatus(value(status)),
+		SortBy:      sortBy,
+		SortOrder:   sortOrder,
+	}
+	if err := validateArguments(req); err != nil {
+		return nil, err
+	}
+	response, err := r.userService.ListUsers(ctx, &models.ListUsersRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Search:    req.Search,
+		UserType:  req.UserType,
+		Status:    req.Status,
+		SortBy:    req.SortBy,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UserSummaryPage{
+		Items:      response.Users,
+		Page:       response.Page,
+		Limit:      response.Limit,
+		Total:      int(response.Total),
+		TotalPages: response.TotalPages,
+	}, nil
+}
+
+// UserStats is the resolver for the user_stats field.
+func (r *queryResolver) UserStats(ctx context.Context) (*models.UserStatsResponse, error) {
+	return r.userService.GetUserStats(ctx)
+}
+
+// Modules is the resolver for the modules field.
+func (r *queryResolver) Modules(ctx context.Context, page int, limit int, search *string, published *bool) (*ModulePage, error) {
+	viewer := viewerFrom(ctx)
+	req := &models.V2ListModulesRequest{
+		PageRequest: models.PageRequest{Page: page, Limit: limit},
+		Search:      value(search),
+		Published:   published,
+	}
+	if err := validateArguments(req); err != nil {
+		return nil, err
+	}
+	if !viewer.IsAdmin {
+		published := true
+		req.Published = &published
+	}
+
+	response, err := r.moduleService.GetAllModules(ctx, &models.GetModulesRequest{
+		Page:      req.Page,
+		Limit:     req.Limit,
+		Search:    req.Search,
+		Published: req.Published,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.IsAdmin {
+		if err := r.moduleService.ApplyGating(ctx, response.Modules, &viewer.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &ModulePage{
+		Items:      response.Modules,
+		Page:       response.Page,
+		Limit:      response.Limit,
+		Total:      int(response.Total),
+		TotalPages: response.TotalPages,
+	}, nil
+}
+
+// Questions is the resolver for the questions field.
+func (r *queryResolver) Questions(ctx context.Context, page int, limit int, search *string, typeArg *string, difficulty *string, isActive *bool, archived bool, tag *string, authorID *string, reviewerID *string, reviewStatus *string, cursor *string) (*QuestionPage, error) {
+	req := &models.ListQuestionsRequest{
+		Page:         page,
+		Limit:        limit,
+		Search:       value(search),
+		Type:         models.QuestionType(value(typeArg)),
+		Difficulty:   models.DifficultyLevel(value(difficulty)),
+		IsActive:     isActive,
+		Archived:     archived,
+		Tag:          value(tag),
+		AuthorID:     value(authorID),
+		ReviewerID:   value(reviewerID),
+		ReviewStatus: models.QuestionReviewStatus(value(reviewStatus)),
+		Cursor:       value(cursor),
+	}
+	if err := validateArguments(req); err != nil {
+		return nil, err
+	}
+	response, err := r.questionService.ListQuestions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.Question, len(response.Questions))
+	for i, question := range response.Questions {
+		items[i] = *question
+	}
+	return &QuestionPage{
+		Items:      items,
+		Page:       response.Page,
+		Limit:      response.Limit,
+		Total:      int(response.Total),
+		TotalPages: response.TotalPages,
+		NextCursor: response.NextCursor,
+	}, nil
+}
+
+// QuestionStats is the resolver for the question_stats field.
+func (r *queryResolver) QuestionStats(ctx context.Context) (*models.QuestionStatsResponse, error) {
+	return r.questionService.GetQuestionStats(ctx)
+}
+
+// QuizResults is the resolver for the quiz_results field.
+func (r *queryResolver) QuizResults(ctx context.Context, userID *primitive.ObjectID, quizType *string, limit int) ([]models.DetailedQuizResult, error) {
+	req := &models.DashboardQuizResultsRequest{UserID: userID, QuizType: models.QuizType(value(quizType)), Limit: limit}
+	if err := validateArguments(req); err != nil {
+		return nil, err
+	}
+	if req.UserID == nil {
+		viewerID := viewerFrom(ctx).ID
+		req.UserID = &viewerID
+	}
+	results, err := r.quizSessionService.GetUserResults(ctx, *req.UserID, req.QuizType, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.DetailedQuizResult{}
+	}
+	return results, nil
+}
+
+// ActivityLogs is the resolver for the activity_logs field.
+func (r *queryResolver) ActivityLogs(ctx context.Context, page int, limit int, cursor *string, typeArg *string, entityType *string, userID *string, dateFrom *time.Time, dateTo *time.Time, success *bool) (*ActivityLogPage, error) {
+	req := &models.V2ListActivityLogsRequest{
+		PageRequest: models.PageRequest{Page: page, Limit: limit, Cursor: value(cursor)},
+		Type:        models.ActivityType(value(typeArg)),
+		EntityType:  value(entityType),
+		UserID:      value(userID),
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+		Success:     success,
+	}
+	if err := validateArguments(req); err != nil {
+		return nil, err
+	}
+	response, err := r.activityLogService.GetActivityLogs(ctx, &models.GetActivityLogsRequest{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Cursor:     req.Cursor,
+		Type:       req.Type,
+		EntityType: req.EntityType,
+		UserID:     req.UserID,
+		DateFrom:   req.DateFrom,
+		DateTo:     req.DateTo,
+		Success:    req.Success,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ActivityLogPage{
+		Items:      response.Activities,
+		Page:       response.Page,
+		Limit:      response.Limit,
+		Total:      int(response.Total),
+		TotalPages: response.TotalPages,
+		NextCursor: response.NextCursor,
+	}, nil
+}
+
+// ActivityStats is the resolver for the activity_stats field.
+func (r *queryResolver) ActivityStats(ctx context.Context) (*models.ActivityStats, error) {
+	return r.activityLogService.GetActivityStats(ctx)
+}
+
+// ActivityStats returns ActivityStatsResolver implementation.
+func (r *Resolver) ActivityStats() ActivityStatsResolver { return &activityStatsResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type activityStatsResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
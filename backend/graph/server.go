@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// maxRootFields caps the top-level fields of one query, each of which is a database query
+const maxRootFields = 10
+
+// NewServer answers dashboard queries POSTed as JSON. Errors carry the REST API's error codes under
+// extensions.code, and fields that fail to resolve are null without failing the rest of the query.
+func NewServer(resolver *Resolver) *handler.Server {
+	server := handler.New(NewExecutableSchema(Config{
+		Resolvers:  resolver,
+		Directives: DirectiveRoot{Admin: admin},
+	}))
+	server.AddTransport(transport.POST{})
+	server.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	server.Use(rootFieldLimit{})
+	server.AroundFields(hideFailures)
+	server.SetErrorPresenter(presentError)
+	return server
+}
+
+// admin enforces @admin: learners are refused the field or argument it marks, and the field does not
+// resolve
+func admin(ctx context.Context, _ any, next graphql.Resolver) (any, error) {
+	if viewerFrom(ctx).IsAdmin {
+		return next(ctx)
+	}
+	field := graphql.GetFieldContext(ctx)
+	if path := graphql.GetPathContext(ctx); path != nil && path.ParentField == field && path.Field != nil {
+		return nil, models.ForbiddenError(fmt.Sprintf("Not authorized to use argument %q on field \"%s.%s\"", *path.Field, field.Object, field.Field.Name))
+	}
+	return nil, models.ForbiddenError(fmt.Sprintf("Not authorized to query field \"%s.%s\"", field.Object, field.Field.Name))
+}
+
+// hideFailures logs what went wrong when a resolver fails for a reason other than the query, and
+// answers with the field's name only, as the REST API does
+func hideFailures(ctx context.Context, next graphql.Resolver) (any, error) {
+	result, err := next(ctx)
+	if err == nil || services.ErrorCode(err) != "" {
+		return result, err
+	}
+	field := graphql.GetFieldContext(ctx)
+	slog.ErrorContext(ctx, "Failed to resolve dashboard query field", "field", field.Path().String(), "error", err)
+	return nil, models.NewError(http.StatusInternalServerError, "Failed to resolve "+field.Field.Name)
+}
+
+// presentError adds the error code; errors without one are arguments that did not coerce to their type
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	code := services.ErrorCode(err)
+	if code == "" {
+		code = models.ErrorCodeValidation
+	}
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]any{}
+	}
+	presented.Extensions["code"] = code
+	return presented
+}
+
+// rootFieldLimit refuses queries selecting more than maxRootFields top-level fields before any runs
+type rootFieldLimit struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = rootFieldLimit{}
+
+func (rootFieldLimit) ExtensionName() string {
+	return "RootFieldLimit"
+}
+
+func (rootFieldLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (rootFieldLimit) MutateOperationContext(_ context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	if len(oc.Operation.SelectionSet) <= maxRootFields {
+		return nil
+	}
+	err := gqlerror.Errorf("A query may select at most %d top-level fields", maxRootFields)
+	err.Extensions = map[string]any{"code": models.ErrorCodeBadRequest}
+	return err
+}
@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/models"
+	"backend/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Only the methods the dashboard query resolves through are stubbed; the embedded interfaces are nil
+
+type stubUserService struct {
+	services.UserService
+	listed *models.ListUsersRequest
+}
+
+func (s *stubUserService) ListUsers(_ context.Context, req *models.ListUsersRequest) (*models.ListUsersResponse, error) {
+	s.listed = req
+	return &models.ListUsersResponse{
+		Users: []models.UserSummary{
+			{FullName: "Ani", Email: "ani@example.com", UserType: models.UserTypeMahasiswa},
+			{FullName: "Budi", Email: "budi@example.com", UserType: models.UserTypeAdmin},
+		},
+		Total: 42, Page: req.Page, Limit: req.Limit, TotalPages: 21,
+	}, nil
+}
+
+type stubModuleService struct {
+	services.ModuleService
+	listed *models.GetModulesRequest
+	gated  *primitive.ObjectID
+}
+
+func (s *stubModuleService) GetAllModules(_ context.Context, req *models.GetModulesRequest) (*models.GetModulesResponse, error) {
+	s.listed = req
+	return &models.GetModulesResponse{Modules: []models.Module{{Name: "Algebra"}}, Total: 1, Page: req.Page, Limit: req.Limit, TotalPages: 1}, nil
+}
+
+func (s *stubModuleService) ApplyGating(_ context.Context, modules []models.Module, learnerID *primitive.ObjectID) error {
+	s.gated = learnerID
+	for i := range modules {
+		modules[i].Locked = true
+	}
+	return nil
+}
+
+type stubQuizSessionService struct {
+	services.QuizSessionService
+	resultsOf primitive.ObjectID
+}
+
+func (s *stubQuizSessionService) GetUserResults(_ context.Context, userID primitive.ObjectID, _ models.QuizType, _ int) ([]models.DetailedQuizResult, error) {
+	s.resultsOf = userID
+	return nil, nil
+}
+
+type stubActivityLogService struct {
+	services.ActivityLogService
+}
+
+func (s *stubActivityLogService) GetActivityStats(context.Context) (*models.ActivityStats, error) {
+	return nil, errors.New("database is down")
+}
+
+type serverFixture struct {
+	users   *stubUserService
+	modules *stubModuleService
+	quizzes *stubQuizSessionService
+	server  http.Handler
+}
+
+func newServerFixture() *serverFixture {
+	f := &serverFixture{
+		users:   &stubUserService{},
+		modules: &stubModuleService{},
+		quizzes: &stubQuizSessionService{},
+	}
+	f.server = NewServer(NewResolver(f.users, f.modules, nil, f.quizzes, &stubActivityLogService{}))
+	return f
+}
+
+// query POSTs a query as viewer, returning the status and body of the answer
+func (f *serverFixture) query(t *testing.T, viewer Viewer, query string, variables map[string]any) (int, string) {
+	t.Helper()
+	body, err := json.Marshal(models.GraphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(WithViewer(req.Context(), viewer))
+
+	recorder := httptest.NewRecorder()
+	f.server.ServeHTTP(recorder, req)
+	return recorder.Code, recorder.Body.String()
+}
+
+var adminViewer = Viewer{ID: primitive.NewObjectID(), IsAdmin: true}
+
+func TestQuerySelectsFieldsInOrder(t *testing.T) {
+	f := newServerFixture()
+	_, body := f.query(t, adminViewer, `query ($limit: Int!) {
+		people: users(limit: $limit, sort_by: "full_name") { total items { full_name __typename } __typename }
+		__typename
+	}`, map[string]any{"limit": 2})
+
+	want := `{"data":{"people":{"total":42,"items":[{"full_name":"Ani","__typename":"UserSummary"},{"full_name":"Budi","__typename":"UserSummary"}],"__typename":"UserSummaryPage"},"__typename":"Query"}}`
+	if body != want {
+		t.Errorf("query = %s\nwant %s", body, want)
+	}
+
+	// Arguments default like the REST query parameters
+	if got := f.users.listed; got == nil || got.Limit != 2 || got.Page != 1 || got.SortBy != "full_name" || got.SortOrder != "desc" {
+		t.Errorf("ListUsers() got %+v", got)
+	}
+}
+
+func TestQueryFieldLevelAuth(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"admin-only field", `{ users { total } }`, `Not authorized to query field \"Query.users\"`},
+		{"admin-only argument", `{ modules(published: false) { total } }`, `Not authorized to use argument \"published\" on field \"Query.modules\"`},
+		{"another user's results", `{ quiz_results(user_id: "64b7f0c2a1b2c3d4e5f60718") { score } }`, `Not authorized to use argument \"user_id\" on field \"Query.quiz_results\"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServerFixture()
+			_, body := f.query(t, Viewer{ID: primitive.NewObjectID()}, tt.query, nil)
+			if !strings.Contains(body, tt.want) || !strings.Contains(body, `"code":"forbidden"`) {
+				t.Errorf("query = %s, want a forbidden error containing %s", body, tt.want)
+			}
+			if f.users.listed != nil || f.modules.listed != nil || f.quizzes.resultsOf != primitive.NilObjectID {
+				t.Error("query resolved a field the viewer may not select")
+			}
+		})
+	}
+
+	t.Run("open fields still resolve", func(t *testing.T) {
+		f := newServerFixture()
+		_, body := f.query(t, Viewer{ID: primitive.NewObjectID()}, `{ modules { total } activity_logs { total } }`, nil)
+		want := `{"errors":[{"message":"Not authorized to query field \"Query.activity_logs\"","path":["activity_logs"],"extensions":{"code":"forbidden"}}],"data":{"modules":{"total":1},"activity_logs":null}}`
+		if body != want {
+			t.Errorf("query = %s\nwant %s", body, want)
+		}
+	})
+}
+
+func TestQueryLearnerViews(t *testing.T) {
+	f := newServerFixture()
+	viewer := Viewer{ID: primitive.NewObjectID()}
+	_, body := f.query(t, viewer, `{ modules { items { name locked } } quiz_results { score } }`, nil)
+
+	want := `{"data":{"modules":{"items":[{"name":"Algebra","locked":true}]},"quiz_results":[]}}`
+	if body != want {
+		t.Errorf("query = %s\nwant %s", body, want)
+	}
+	if f.modules.listed.Published == nil || !*f.modules.listed.Published {
+		t.Error("learners were not limited to published modules")
+	}
+	if f.modules.gated == nil || *f.modules.gated != viewer.ID {
+		t.Error("modules were not gated for the learner")
+	}
+	if f.quizzes.resultsOf != viewer.ID {
+		t.Errorf("quiz results were fetched for %s, want the viewer %s", f.quizzes.resultsOf.Hex(), viewer.ID.Hex())
+	}
+}
+
+func TestQueryRejectsInvalidQueries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"syntax", `{ users { total }`, "Expected Name"},
+		{"unknown root field", `{ teams { total } }`, `Cannot query field \"teams\" on type \"Query\"`},
+		{"unknown nested field", `{ users { items { password_hash } } }`, `Cannot query field \"password_hash\" on type \"UserSummary\"`},
+		{"object without selection", `{ users }`, "must have a selection of subfields"},
+		{"scalar with selection", `{ users { total { value } } }`, "must not have a selection"},
+		{"unknown argument", `{ users(order: "asc") { total } }`, `Unknown argument \"order\"`},
+		{"invalid argument", `{ users(limit: 500) { total } }`, `"code":"validation_failed"`},
+		{"missing variable", `query ($limit: Int!) { users(limit: $limit) { total } }`, "must be defined"},
+		{"too many fields", `{ a: user_stats { total_users } b: user_stats { total_users } c: user_stats { total_users }
+			d: user_stats { total_users } e: user_stats { total_users } f: user_stats { total_users }
+			g: user_stats { total_users } h: user_stats { total_users } i: user_stats { total_users }
+			j: user_stats { total_users } k: user_stats { total_users } }`, "at most 10 top-level fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServerFixture()
+			_, body := f.query(t, adminViewer, tt.query, nil)
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("query = %s, want an error containing %s", body, tt.want)
+			}
+			if f.users.listed != nil {
+				t.Error("query resolved a field of a query it rejected")
+			}
+		})
+	}
+}
+
+func TestQueryFieldErrorsLeaveTheRest(t *testing.T) {
+	f := newServerFixture()
+	_, body := f.query(t, adminViewer, `{ activity_stats { total_activities } users { total } }`, nil)
+
+	// The failure is logged; the answer only names the field
+	want := `{"errors":[{"message":"Failed to resolve activity_stats","path":["activity_stats"],"extensions":{"code":"internal_error"}}],"data":{"activity_stats":null,"users":{"total":42}}}`
+	if body != want {
+		t.Errorf("query = %s\nwant %s", body, want)
+	}
+}
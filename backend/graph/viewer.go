@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Viewer is the signed-in user a query is answered for
+type Viewer struct {
+	ID      primitive.ObjectID
+	IsAdmin bool
+}
+
+type viewerKey struct{}
+
+// WithViewer returns a context whose queries are answered for viewer
+func WithViewer(ctx context.Context, viewer Viewer) context.Context {
+	return context.WithValue(ctx, viewerKey{}, viewer)
+}
+
+// viewerFrom is the viewer of the query; without one, nothing admin-only is allowed
+func viewerFrom(ctx context.Context) Viewer {
+	viewer, _ := ctx.Value(viewerKey{}).(Viewer)
+	return viewer
+}
@@ -3,7 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,7 +14,9 @@ import (
 	"backend/config"
 	"backend/controllers"
 	"backend/database"
+	"backend/graph"
 	"backend/middleware"
+	"backend/models"
 	"backend/repository"
 	"backend/routes"
 	"backend/services"
@@ -27,6 +30,30 @@ func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// The Docker health check runs the binary again with this flag, as the image has no HTTP client
+	if len(os.Args) > 1 && os.Args[1] == "--health-check" {
+		os.Exit(runHealthCheck(cfg.Server))
+	}
+
+	// Log structured records, with request IDs and secrets redacted, from here on
+	logger, err := utils.NewLogger(cfg.Log, os.Stdout)
+	if err != nil {
+		slog.Error("Failed to configure logging", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	// Send traces to the OpenTelemetry collector, when one is configured
+	tracerProvider, err := utils.NewTracerProvider(cfg.Tracing)
+	if err != nil {
+		slog.Error("Failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+	utils.SetTracerProvider(tracerProvider)
+	if tracerProvider != nil {
+		slog.Info("Sending traces", "endpoint", cfg.Tracing.Endpoint, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
 	// Set Gin mode based on environment
 	if cfg.Server.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -35,7 +62,8 @@ func main() {
 	// Connect to MongoDB Atlas
 	db, err := database.ConnectMongoDB(cfg.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB Atlas: %v", err)
+		slog.Error("Failed to connect to MongoDB Atlas", "error", err)
+		os.Exit(1)
 	}
 
 	// Initialize repositories
@@ -45,58 +73,295 @@ func main() {
 	questionRepo := repository.NewQuestionRepository(db)
 	activityLogRepo := repository.NewActivityLogRepository(db)
 	quizSessionRepo := repository.NewQuizSessionRepository(db)
+	bookmarkRepo := repository.NewBookmarkRepository(db)
+	flashcardRepo := repository.NewFlashcardRepository(db)
+	questionMemoryRepo := repository.NewQuestionMemoryRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	resultThreadRepo := repository.NewResultThreadRepository(db)
+	examRepo := repository.NewExamRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	questionAudioRepo := repository.NewQuestionAudioRepository(db)
+	questionReportRepo := repository.NewQuestionReportRepository(db)
+	quizTemplateRepo := repository.NewQuizTemplateRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+	shortLinkRepo := repository.NewShortLinkRepository(db)
+	classQuizRepo := repository.NewClassQuizRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
+	questionProposalRepo := repository.NewQuestionProposalRepository(db)
+	resultWebhookRepo := repository.NewResultWebhookRepository(db)
+	changelogRepo := repository.NewChangelogRepository(db)
+	sessionTelemetryRepo := repository.NewSessionTelemetryRepository(db)
+	embedWidgetRepo := repository.NewEmbedWidgetRepository(db)
+	certificateRepo := repository.NewCertificateRepository(db)
+	difficultySuggestionRepo := repository.NewDifficultySuggestionRepository(db)
+	questionVersionRepo := repository.NewQuestionVersionRepository(db)
+	questionCommentRepo := repository.NewQuestionCommentRepository(db)
+	moduleProgressRepo := repository.NewModuleProgressRepository(db)
+	moduleRevisionRepo := repository.NewModuleRevisionRepository(db)
+	moduleAttachmentRepo := repository.NewModuleAttachmentRepository(db)
+	moduleViewRepo := repository.NewModuleViewRepository(db)
+	moduleCommentRepo := repository.NewModuleCommentRepository(db)
+	moduleImageRepo := repository.NewModuleImageRepository(db)
+	transactor := repository.NewTransactor(db)
+	jobRepo := repository.NewJobRepository(db)
+	healthRepo := repository.NewHealthRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
 
 	// Initialize utilities
 	jwtManager := utils.NewJWTManager(cfg.JWT)
-	// Note: emailService removed - using recovery codes instead of email for password reset
+
+	// Load rotating signing keys and keep them in sync with rotations made elsewhere
+	signingKeyService := services.NewSigningKeyService(signingKeyRepo, jwtManager, cfg.JWT)
+	if err := signingKeyService.LoadKeys(context.Background()); err != nil {
+		slog.Error("Failed to load JWT signing keys", "error", err)
+		os.Exit(1)
+	}
+	refreshCtx, stopKeyRefresh := context.WithCancel(context.Background())
+	defer stopKeyRefresh()
+	signingKeyService.StartAutoRefresh(refreshCtx, cfg.JWT.KeyRefreshInterval)
+
+	// Settings admins change at runtime override the environment, on every instance
+	settingsService := services.NewSettingsService(settingsRepo, cfg)
+	if err := settingsService.LoadSettings(context.Background()); err != nil {
+		slog.Error("Failed to load runtime settings", "error", err)
+		os.Exit(1)
+	}
+	settingsService.StartAutoRefresh(refreshCtx, cfg.Server.SettingsRefreshInterval)
+
+	// Work handed off from requests, counted for the health check and waited for on shutdown
+	backgroundTasks := utils.NewBackgroundTasks()
+
+	// Note: password reset uses recovery codes; email is only used for invitations
+	emailService := utils.NewEmailService(cfg.Email)
+
+	captchaVerifier, err := utils.NewCaptchaVerifier(cfg.Captcha)
+	if err != nil {
+		slog.Error("Failed to configure captcha", "error", err)
+		os.Exit(1)
+	}
+	rateLimitStore, err := utils.NewRateLimitStore(cfg.RateLimit)
+	if err != nil {
+		slog.Error("Failed to configure rate limiting", "error", err)
+		os.Exit(1)
+	}
+	cache, err := utils.NewCache(cfg.Cache)
+	if err != nil {
+		slog.Error("Failed to configure cache", "error", err)
+		os.Exit(1)
+	}
+
+	ttsProvider, err := utils.NewTTSProvider(cfg.TTS)
+	if err != nil {
+		slog.Error("Failed to configure text-to-speech", "error", err)
+		os.Exit(1)
+	}
+
+	fileStorage, err := utils.NewFileStorage(cfg.Storage)
+	if err != nil {
+		slog.Error("Failed to configure file storage", "error", err)
+		os.Exit(1)
+	}
 
 	// Initialize services
-	userService := services.NewUserService(userRepo, jwtManager, cfg)
-	moduleService := services.NewModuleService(moduleRepo)
-	userActivityService := services.NewUserActivityService(userActivityRepo)
-	questionService := services.NewQuestionService(questionRepo)
+	passwordPolicyService := services.NewPasswordPolicyService(cfg.PasswordPolicy)
+	invitationService := services.NewInvitationService(invitationRepo, userRepo, jwtManager, emailService)
+	userService := services.NewUserService(userRepo, transactor, jwtManager, cfg, passwordPolicyService, invitationService, cache)
+	moduleService := services.NewModuleService(moduleRepo, moduleProgressRepo, moduleRevisionRepo, moduleAttachmentRepo, moduleViewRepo, moduleCommentRepo, moduleImageRepo, userRepo, notificationRepo, transactor, fileStorage, cfg.Storage, cache)
+	userActivityService := services.NewUserActivityService(userActivityRepo, examRepo)
+	questionService := services.NewQuestionService(questionRepo, moduleRepo, userRepo, questionVersionRepo, quizSessionRepo, cache)
+	questionImportService := services.NewQuestionImportService(questionService, questionRepo)
+	questionCommentService := services.NewQuestionCommentService(questionCommentRepo, questionRepo, notificationRepo, userRepo)
 	activityLogService := services.NewActivityLogService(activityLogRepo)
-	quizSessionService := services.NewQuizSessionService(quizSessionRepo, questionRepo, userActivityRepo)
+	quizLiveHub := services.NewQuizLiveHub()
+	resultWebhookService := services.NewResultWebhookService(resultWebhookRepo, examRepo, quizSessionRepo, userRepo, cfg.Webhooks, backgroundTasks)
+	practiceService := services.NewPracticeService(questionMemoryRepo, questionRepo, backgroundTasks)
+	quizSessionService := services.NewQuizSessionService(quizSessionRepo, questionRepo, userActivityRepo, examRepo, quizTemplateRepo, moduleRepo, transactor, quizLiveHub, resultWebhookService, practiceService, settingsService, cache)
+	bookmarkService := services.NewBookmarkService(bookmarkRepo, questionRepo)
+	flashcardService := services.NewFlashcardService(flashcardRepo, moduleRepo, quizSessionRepo, questionRepo, examRepo)
+	userImportService := services.NewUserImportService(userRepo, emailService, cache)
+	privacyService := services.NewPrivacyService(userRepo, userActivityRepo, quizSessionRepo, activityLogRepo, bookmarkRepo, flashcardRepo, questionMemoryRepo, notificationRepo, resultThreadRepo, examRepo, questionReportRepo, questionProposalRepo, cache)
+	notificationService := services.NewNotificationService(notificationRepo)
+	resultThreadService := services.NewResultThreadService(resultThreadRepo, quizSessionRepo, notificationRepo, userRepo, examRepo)
+	examService := services.NewExamService(examRepo, quizSessionRepo, userRepo, userActivityRepo, quizSessionService, resultWebhookService)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, rateLimitStore)
+	guestService := services.NewGuestService(quizSessionRepo, userActivityRepo, jwtManager)
+	questionAudioService := services.NewQuestionAudioService(questionRepo, questionAudioRepo, ttsProvider, cfg.TTS, backgroundTasks)
+	questionReportService := services.NewQuestionReportService(questionReportRepo, quizSessionRepo, questionRepo)
+	quizTemplateService := services.NewQuizTemplateService(quizTemplateRepo, questionRepo, moduleRepo, cache)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, notificationRepo, questionRepo, cfg.AtRisk)
+	shortLinkService := services.NewShortLinkService(shortLinkRepo)
+	classQuizService := services.NewClassQuizService(classQuizRepo, questionRepo, quizSessionService)
+	teamService := services.NewTeamService(teamRepo, quizSessionRepo, quizSessionService, cache)
+	changelogService := services.NewChangelogService(changelogRepo)
+	questionProposalService := services.NewQuestionProposalService(questionProposalRepo, questionService, userActivityRepo, notificationRepo)
+	sessionTelemetryService := services.NewSessionTelemetryService(sessionTelemetryRepo, quizSessionRepo)
+	embedWidgetService := services.NewEmbedWidgetService(embedWidgetRepo, quizSessionRepo, quizSessionService, settingsService)
+	resultBenchmarkService := services.NewResultBenchmarkService(quizSessionRepo, examRepo)
+	certificateService := services.NewCertificateService(certificateRepo, quizSessionRepo, userRepo, examRepo, settingsService, cfg.Certificates, cfg.JWT.SecretKey)
+	difficultyRecalibrationService := services.NewDifficultyRecalibrationService(difficultySuggestionRepo, analyticsRepo, questionRepo, activityLogService, cfg.Recalibration, cache)
+	essayGradingService := services.NewEssayGradingService(quizSessionRepo, questionRepo, userRepo, userActivityRepo, examRepo, notificationRepo, resultWebhookService)
+	dashboardQueryResolver := graph.NewResolver(userService, moduleService, questionService, quizSessionService, activityLogService)
 
 	// Initialize controllers
-	userController := controllers.NewUserController(userService, userRepo, activityLogService)
+	userController := controllers.NewUserController(userService, userRepo, activityLogService, captchaVerifier)
 	moduleController := controllers.NewModuleController(moduleService, activityLogService)
 	userActivityController := controllers.NewUserActivityController(userActivityService)
-	questionController := controllers.NewQuestionController(questionService, activityLogService)
+	questionController := controllers.NewQuestionController(questionService, questionAudioService, activityLogService)
+	questionImportController := controllers.NewQuestionImportController(questionImportService, activityLogService)
+	questionCommentController := controllers.NewQuestionCommentController(questionCommentService)
 	activityLogController := controllers.NewActivityLogController(activityLogService)
 	quizSessionController := controllers.NewQuizSessionController(quizSessionService)
+	quizLiveController := controllers.NewQuizLiveController(quizSessionService, quizLiveHub)
+	bookmarkController := controllers.NewBookmarkController(bookmarkService, quizSessionService)
+	flashcardController := controllers.NewFlashcardController(flashcardService)
+	practiceController := controllers.NewPracticeController(practiceService)
+	privacyController := controllers.NewPrivacyController(privacyService, activityLogService)
+	userImportController := controllers.NewUserImportController(userImportService, activityLogService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	resultThreadController := controllers.NewResultThreadController(resultThreadService)
+	examController := controllers.NewExamController(examService, activityLogService)
+	invitationController := controllers.NewInvitationController(invitationService, activityLogService)
+	guestController := controllers.NewGuestController(guestService, captchaVerifier)
+	apiKeyController := controllers.NewAPIKeyController(apiKeyService, activityLogService)
+	jwksController := controllers.NewJWKSController(signingKeyService)
+	questionReportController := controllers.NewQuestionReportController(questionReportService)
+	quizTemplateController := controllers.NewQuizTemplateController(quizTemplateService, activityLogService)
+	analyticsController := controllers.NewAnalyticsController(analyticsService)
+	shortLinkController := controllers.NewShortLinkController(shortLinkService, activityLogService)
+	classQuizController := controllers.NewClassQuizController(classQuizService)
+	teamController := controllers.NewTeamController(teamService)
+	questionProposalController := controllers.NewQuestionProposalController(questionProposalService, questionAudioService, activityLogService)
+	resultWebhookController := controllers.NewResultWebhookController(resultWebhookService, activityLogService)
+	changelogController := controllers.NewChangelogController(changelogService, activityLogService)
+	essayGradingController := controllers.NewEssayGradingController(essayGradingService, activityLogService)
+	sessionTelemetryController := controllers.NewSessionTelemetryController(sessionTelemetryService)
+	embedWidgetController := controllers.NewEmbedWidgetController(embedWidgetService, activityLogService, cfg.Embed)
+	resultBenchmarkController := controllers.NewResultBenchmarkController(resultBenchmarkService)
+	certificateController := controllers.NewCertificateController(certificateService)
+	difficultyRecalibrationController := controllers.NewDifficultyRecalibrationController(difficultyRecalibrationService, activityLogService)
+	settingsController := controllers.NewSettingsController(settingsService, activityLogService)
+	v2ModuleController := controllers.NewV2ModuleController(moduleService)
+	v2NotificationController := controllers.NewV2NotificationController(notificationService)
+	dashboardQueryController := controllers.NewDashboardQueryController(graph.NewServer(dashboardQueryResolver))
+
+	// Periodic jobs run on whichever instance leads the scheduler
+	jobScheduler := services.NewJobScheduler(jobRepo, cfg.Scheduler)
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "quiz-session-cleanup",
+		Description: "Score quiz sessions that ran out of time even if the student never came back",
+		Interval:    cfg.Server.SessionCleanupInterval,
+		Run: func(ctx context.Context) (string, error) {
+			submitted, err := quizSessionService.CleanupExpiredSessions(ctx)
+			return fmt.Sprintf("submitted %d expired sessions", submitted), err
+		},
+	})
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "results-webhook-release",
+		Description: "Send results to department webhooks once scheduled releases pass",
+		Interval:    cfg.Webhooks.ReleaseCheckInterval,
+		Run: func(ctx context.Context) (string, error) {
+			sent, err := resultWebhookService.SendReleasedResults(ctx)
+			return fmt.Sprintf("handled %d released sittings", sent), err
+		},
+	})
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "user-stats-refresh",
+		Description: "Break lapsed streaks and recount weekly progress",
+		Interval:    cfg.Scheduler.UserStatsInterval,
+		Run: func(ctx context.Context) (string, error) {
+			changed, err := userActivityService.RefreshUserStats(ctx)
+			return fmt.Sprintf("refreshed stats of %d users", changed), err
+		},
+	})
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "activity-log-retention",
+		Description: "Delete activity logs older than the retention period in the runtime settings",
+		Interval:    cfg.Scheduler.ActivityLogInterval,
+		Run: func(ctx context.Context) (string, error) {
+			retentionDays := settingsService.Current().ActivityLogRetentionDays
+			deleted, err := activityLogService.CleanupOldActivities(ctx, retentionDays)
+			return fmt.Sprintf("deleted %d activity logs older than %d days", deleted, retentionDays), err
+		},
+	})
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "at-risk-analysis",
+		Description: "Flag struggling students for outreach",
+		Interval:    cfg.AtRisk.Interval,
+		Run: func(ctx context.Context) (string, error) {
+			result, err := analyticsService.AnalyzeAtRisk(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("analyzed %d students, flagged %d (%d newly), cleared %d",
+				result.Analyzed, result.Flagged, result.NewlyFlagged, result.Cleared), nil
+		},
+	})
+	jobScheduler.Register(services.ScheduledJob{
+		Name:        "difficulty-recalibration",
+		Description: "Check question difficulty labels against how students actually score",
+		Interval:    cfg.Recalibration.Interval,
+		Run: func(ctx context.Context) (string, error) {
+			result, err := difficultyRecalibrationService.Recalibrate(ctx)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("analyzed %d questions, adjusted %d, suggested %d",
+				result.Analyzed, result.Adjusted, result.Suggested), nil
+		},
+	})
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	jobScheduler.Start(schedulerCtx)
+	jobSchedulerController := controllers.NewJobSchedulerController(jobScheduler)
+	v2AdminController := controllers.NewV2AdminController(userService, activityLogService, settingsService, jobScheduler)
+
+	healthService := services.NewHealthService(healthRepo, jobScheduler, backgroundTasks)
+	healthController := controllers.NewHealthController(healthService)
 
 	// Development-only controller for quick login helpers
 	devController := controllers.NewDevController(userService, userRepo, jwtManager)
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(jwtManager)
+	apiKeyMiddleware := middleware.NewAPIKeyMiddleware(apiKeyService)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitStore, cfg.RateLimit, jwtManager)
+	embedMiddleware := middleware.NewEmbedMiddleware(embedWidgetService)
 
 	// Create Gin router
 	router := gin.New()
 
+	// Believe X-Forwarded-For only from our own proxies, so clients cannot pick their IP for rate limits
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		slog.Error("Invalid trusted proxies", "error", err)
+		os.Exit(1)
+	}
+
 	// Add middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.Tracing())
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Recovery())
 
 	// CORS configuration
 	corsConfig := cors.Config{
 		AllowOrigins:     cfg.Server.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Cache-Control", "X-API-Key", "X-Lockdown-Timestamp", "X-Lockdown-Signature", "X-Captcha-Token", middleware.RequestIDHeader, middleware.TraceParentHeader},
+		ExposeHeaders:    []string{middleware.RequestIDHeader, middleware.TraceParentHeader, "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
-	router.Use(cors.New(corsConfig))
+	// Embed pages and the widget API set their own policy from each widget's allowed origins
+	router.Use(middleware.ExceptPaths(cors.New(corsConfig), "/embed/", "/api/v1/widget/"))
 
-	// Health check endpoint for Docker health checks
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":   "ok",
-			"database": "connected",
-			"service":  "quizapp-backend",
-		})
-	})
+	// Flag every request made with an impersonation token in the activity log
+	router.Use(middleware.ImpersonationAudit(activityLogService))
+
+	// Health check for Docker and readiness probe for Kubernetes
+	routes.SetupHealthRoutes(router, healthController)
+
+	routes.SetupJWKSRoutes(router, jwksController)
 
 	// API version prefix
 	api := router.Group("/api/v1")
@@ -107,12 +372,47 @@ func main() {
 	admin.Use(authMiddleware.RequireAdmin())
 
 	// Setup routes
-	routes.SetupAuthRoutes(api, userController, authMiddleware, admin)
+	routes.SetupAuthRoutes(api, userController, authMiddleware, rateLimitMiddleware, admin)
 	routes.SetupModuleRoutes(api, moduleController, authMiddleware, admin)
 	routes.SetupUserActivityRoutes(api, userActivityController, authMiddleware)
-	routes.SetupQuestionRoutes(api, questionController, authMiddleware, admin)
+	routes.SetupQuestionRoutes(api, questionController, authMiddleware, rateLimitMiddleware, admin)
 	routes.SetupActivityLogRoutes(api, activityLogController, authMiddleware, admin)
-	routes.SetupQuizSessionRoutes(router, quizSessionController, authMiddleware)
+	routes.SetupBookmarkRoutes(api, bookmarkController, authMiddleware)
+	routes.SetupFlashcardRoutes(api, flashcardController, authMiddleware)
+	routes.SetupPracticeRoutes(api, practiceController, authMiddleware)
+	routes.SetupPrivacyRoutes(api, privacyController, authMiddleware, admin)
+	routes.SetupUserImportRoutes(admin, userImportController)
+	routes.SetupQuestionImportRoutes(admin, questionImportController)
+	routes.SetupQuestionCommentRoutes(admin, questionCommentController)
+	routes.SetupAnalyticsRoutes(admin, analyticsController)
+	routes.SetupDifficultyRecalibrationRoutes(admin, difficultyRecalibrationController)
+	routes.SetupJobSchedulerRoutes(admin, jobSchedulerController)
+	routes.SetupSettingsRoutes(admin, settingsController)
+	routes.SetupInvitationRoutes(api, invitationController, admin)
+	routes.SetupGuestRoutes(api, guestController, authMiddleware)
+	routes.SetupAPIKeyRoutes(api, apiKeyController, apiKeyMiddleware, questionController, userActivityController, examController, moduleController, admin)
+	routes.SetupNotificationRoutes(api, notificationController, authMiddleware)
+	routes.SetupResultThreadRoutes(api, resultThreadController, authMiddleware, admin)
+	routes.SetupExamRoutes(api, examController, authMiddleware, admin)
+	routes.SetupQuizSessionRoutes(router, quizSessionController, authMiddleware, admin)
+	routes.SetupQuizLiveRoutes(api, quizLiveController, authMiddleware)
+	routes.SetupQuestionReportRoutes(api, questionReportController, authMiddleware, admin)
+	routes.SetupQuizTemplateRoutes(api, quizTemplateController, authMiddleware, admin)
+	routes.SetupShortLinkRoutes(router, shortLinkController, admin)
+	routes.SetupClassQuizRoutes(api, classQuizController, authMiddleware, admin)
+	routes.SetupTeamRoutes(api, teamController, authMiddleware)
+	routes.SetupQuestionProposalRoutes(api, questionProposalController, authMiddleware, admin)
+	routes.SetupResultWebhookRoutes(admin, resultWebhookController)
+	routes.SetupChangelogRoutes(api, changelogController, authMiddleware, admin)
+	routes.SetupEssayGradingRoutes(admin, essayGradingController)
+	routes.SetupSessionTelemetryRoutes(api, sessionTelemetryController, authMiddleware, admin)
+	routes.SetupEmbedWidgetRoutes(router, api, embedWidgetController, embedMiddleware, admin)
+	routes.SetupResultBenchmarkRoutes(api, resultBenchmarkController, authMiddleware)
+	routes.SetupCertificateRoutes(api, certificateController, authMiddleware)
+
+	// v1 stays frozen; new clients and new endpoints use v2
+	routes.SetupV2Routes(router, authMiddleware, v2ModuleController, v2NotificationController, v2AdminController)
+	routes.SetupDashboardQueryRoutes(router, dashboardQueryController, authMiddleware)
 
 	// Register development-only routes when not in production
 	if cfg.Server.Environment != "production" {
@@ -129,6 +429,12 @@ func main() {
 					"POST /auth/register":                "Register new user",
 					"POST /auth/login":                   "Login user",
 					"POST /auth/refresh":                 "Refresh access token",
+					"GET  /.well-known/jwks.json":        "Public keys for verifying access tokens (site root)",
+					"GET  /s/:code":                      "Follow a short link to its frontend page (site root)",
+					"GET  /auth/password-policy":         "Password rules for client-side validation",
+					"GET  /auth/captcha":                 "Captcha provider and site key for register, login and forgot-password",
+					"POST /auth/guest":                   "Get a guest token for taking a time quiz without an account",
+					"GET  /auth/invitations/lookup":      "Get email and user type of an invite link",
 					"POST /auth/logout":                  "Logout user (requires auth)",
 					"POST /auth/request-reset":           "Get password reset options",
 					"POST /auth/reset-password-recovery": "Reset password with recovery code",
@@ -138,36 +444,235 @@ func main() {
 					"POST /auth/oauth/callback":          "OAuth callback",
 				},
 				"user": gin.H{
-					"GET  /user/profile":           "Get user profile (requires auth)",
-					"PUT  /user/profile":           "Update user profile (requires auth)",
-					"POST /user/change-password":   "Change password (requires auth)",
-					"GET  /user/recovery-codes":    "View current recovery codes (requires auth)",
-					"POST /user/generate-recovery": "Generate new recovery codes (requires auth)",
+					"GET  /user/profile":                                   "Get user profile (requires auth)",
+					"PUT  /user/profile":                                   "Update user profile (requires auth)",
+					"POST /user/change-password":                           "Change password (requires auth)",
+					"GET  /user/recovery-codes":                            "View current recovery codes (requires auth)",
+					"POST /user/generate-recovery":                         "Generate new recovery codes (requires auth)",
+					"POST /user/bookmarks":                                 "Bookmark a question (requires auth)",
+					"GET  /user/bookmarks":                                 "List bookmarked questions with filtering (requires auth)",
+					"DELETE /user/bookmarks/:id":                           "Remove a bookmark (requires auth)",
+					"POST /user/bookmarks/practice":                        "Start practice session from bookmarks (requires auth)",
+					"POST /user/flashcards/decks/module/:moduleId":         "Generate flashcards from module key points (requires auth)",
+					"POST /user/flashcards/decks/missed":                   "Generate flashcards from missed questions (requires auth)",
+					"GET  /user/flashcards/decks":                          "List flashcard decks (requires auth)",
+					"GET  /user/flashcards/due":                            "Get flashcards due for review (requires auth)",
+					"POST /user/flashcards/:cardId/review":                 "Review flashcard and reschedule (requires auth)",
+					"GET  /practice/due":                                   "Get questions due for spaced-repetition review, scheduled from quiz history (requires auth)",
+					"POST /practice/reviews":                               "Record a practice review outcome and reschedule the question (requires auth)",
+					"POST /user/data-export":                               "Download personal data as JSON or ZIP (requires auth)",
+					"GET  /user/notifications":                             "List notifications with unread count (requires auth)",
+					"POST /user/notifications/:id/read":                    "Mark notification read (requires auth)",
+					"POST /user/notifications/read-all":                    "Mark all notifications read (requires auth)",
+					"GET  /user/result-threads":                            "List discussions on own results (requires auth)",
+					"GET  /user/result-threads/results/:resultId":          "Get discussion for a detailed result (requires auth)",
+					"POST /user/result-threads/results/:resultId/messages": "Ask about a result or question (requires auth)",
+					"GET  /user/exams":                                     "Get own exam venue and seat assignments (requires auth)",
+					"DELETE /user/account":                                 "Schedule account deletion with grace period (requires auth)",
+					"POST /quiz/claim-guest-results":                       "Attach results taken as a guest to own account (requires auth)",
+					"POST /quiz/session/:token/questions/:index/flag":      "Report an ambiguous or broken question during a quiz (requires auth or guest token)",
+					"POST /quiz/session/:token/answers/batch":              "Replay answers buffered offline; latest client timestamp wins per question (requires auth or guest token)",
+					"POST /quiz/custom/start":                              "Start a practice quiz from chosen tags, difficulties and question count; kept out of mock test statistics (requires auth)",
+					"GET /quiz/custom/tags":                                "Tags custom quizzes can be built from, with question counts (requires auth or guest token)",
+					"POST /quiz/session/:token/telemetry":                  "Send buffered render times, answer latency, network retries and timer stalls (requires auth or guest token)",
+					"POST /quiz/session/:token/events":                     "Report integrity events (blur, tab switches, fullscreen exits, copy/paste, devtools) for proctoring review (requires auth or guest token)",
+					"GET /quiz/results/:id":                                "Get a result with its percentile and the mean and median of recent results of the same quiz type (requires auth or guest token)",
+					"GET /quiz/results/:id/review":                         "Review a submitted result question by question with explanations, chosen and correct answers and linked modules (requires auth or guest token)",
+					"GET /quiz/mastery":                                    "Mastery of each module from quiz history, recent quizzes weighing more, with the weakest modules to study (requires auth or guest token)",
+					"GET /quiz/results/:id/benchmark":                      "Compare a result with anonymized percentiles of peers on the same quiz or exam (requires auth or guest token)",
+					"GET /quiz/results/:id/certificate":                    "Download the signed PDF certificate of a passed mock test (requires auth or guest token)",
+					"GET /certificates/verify/:code":                       "Verify a certificate by its printed code (public)",
+					"POST /quiz/session/:token/resolve":                    "Resume an abandoned session with time left, or discard an unfinished one (requires auth or guest token)",
+					"POST /teams":                                          "Create a team for team quizzes, as its captain (requires auth)",
+					"GET /teams":                                           "List own teams (requires auth)",
+					"POST /teams/join":                                     "Join a team with its join code (requires auth)",
+					"GET /teams/leaderboard":                               "Rank teams by team quiz scores (requires auth)",
+					"GET /teams/:id":                                       "Get own team (requires auth)",
+					"POST /teams/:id/leave":                                "Leave a team (requires auth)",
+					"DELETE /teams/:id/members/:userId":                    "Remove a team member (requires auth, captain only)",
+					"POST /teams/:id/quiz":                                 "Start or join the team's shared quiz session; only the captain submits (requires auth)",
+					"POST /quiz/join/:code":                                "Join a class quiz by its code and start answering (requires auth or guest token)",
+					"GET /quiz/templates":                                  "List quizzes that can be started by template ID (requires auth or guest token)",
+					"GET /ws/quiz/:sessionToken":                           "WebSocket with the live timer, answer acknowledgements and submit events (auth or guest token, header or access_token query)",
 				},
 				"mahasiswa": gin.H{
 					"GET /mahasiswa/dashboard": "Mahasiswa dashboard (requires mahasiswa auth)",
 				},
 				"admin": gin.H{
-					"GET    /admin/users":                 "Get all users (requires admin auth)",
-					"DELETE /admin/users/:id":             "Delete user (requires admin auth)",
-					"GET    /admin/dashboard":             "Admin dashboard (requires admin auth)",
-					"POST   /admin/questions":             "Create new question (requires admin auth)",
-					"GET    /admin/questions":             "List questions with filtering (requires admin auth)",
-					"GET    /admin/questions/:id":         "Get specific question (requires admin auth)",
-					"PUT    /admin/questions/:id":         "Update question (requires admin auth)",
-					"DELETE /admin/questions/:id":         "Delete question (requires admin auth)",
-					"PATCH  /admin/questions/:id/status":  "Toggle question status (requires admin auth)",
-					"GET    /admin/questions/stats":       "Get question statistics (requires admin auth)",
-					"POST   /admin/questions/validate":    "Validate question data (requires admin auth)",
-					"GET    /admin/activity-logs":         "Get activity logs with filtering (requires admin auth)",
-					"GET    /admin/activity-logs/stats":   "Get activity statistics (requires admin auth)",
-					"GET    /admin/activity-logs/recent":  "Get recent activities (requires admin auth)",
-					"GET    /admin/activity-logs/types":   "Get available activity types (requires admin auth)",
-					"GET    /admin/activity-logs/:id":     "Get specific activity log (requires admin auth)",
-					"POST   /admin/activity-logs/cleanup": "Cleanup old activity logs (requires admin auth)",
+					"GET    /admin/users":                                          "Get all users (requires admin auth)",
+					"DELETE /admin/users/:id":                                      "Delete user (requires admin auth)",
+					"POST   /admin/users/:id/impersonate":                          "Issue short-lived impersonation token (requires admin auth)",
+					"GET    /admin/result-threads":                                 "List result discussions (requires admin auth)",
+					"GET    /admin/result-threads/:id":                             "Get result discussion (requires admin auth)",
+					"POST   /admin/result-threads/:id/messages":                    "Reply to result discussion (requires admin auth)",
+					"GET    /admin/question-reports":                               "Review queue of questions reported by students (requires admin auth)",
+					"GET    /admin/question-reports/:id/context":                   "Get a report with its question and session context (requires admin auth)",
+					"PUT    /admin/question-reports/:id":                           "Resolve or dismiss a question report (requires admin auth)",
+					"GET    /admin/question-proposals":                             "Review queue of questions proposed by students (requires admin auth)",
+					"GET    /admin/question-proposals/:id":                         "Get a proposed question (requires admin auth)",
+					"PUT    /admin/question-proposals/:id":                         "Accept a proposal into the bank or reject it (requires admin auth)",
+					"POST   /admin/quiz-templates":                                 "Create quiz template (requires admin auth)",
+					"GET    /admin/quiz-templates":                                 "List quiz templates and built-in defaults (requires admin auth)",
+					"GET    /admin/quiz-templates/pool-status":                     "Warn about templates the question bank cannot fill (requires admin auth)",
+					"GET    /admin/quiz-templates/:id":                             "Get quiz template (requires admin auth)",
+					"PUT    /admin/quiz-templates/:id":                             "Update quiz template (requires admin auth)",
+					"DELETE /admin/quiz-templates/:id":                             "Delete quiz template (requires admin auth)",
+					"POST   /admin/exams":                                          "Create exam sitting (requires admin auth)",
+					"GET    /admin/exams":                                          "List exam sittings (requires admin auth)",
+					"GET    /admin/exams/:id":                                      "Get exam sitting with seat assignments (requires admin auth)",
+					"DELETE /admin/exams/:id":                                      "Delete exam sitting (requires admin auth)",
+					"PUT    /admin/exams/:id/lockdown":                             "Require a lockdown browser for the sitting and issue its signing key (requires admin auth)",
+					"PUT    /admin/exams/:id/result-release":                       "Set when students see scores: immediate, scheduled or manual (requires admin auth)",
+					"POST   /admin/exams/:id/release-results":                      "Publish the sitting's results to students now; ?force=true skips the moderation check (requires admin auth)",
+					"PUT    /admin/exams/:id/moderation":                           "Set the pass mark and moderation band (requires admin auth)",
+					"GET    /admin/exams/:id/moderation":                           "List results flagged near the pass mark (requires admin auth)",
+					"PUT    /admin/exams/:id/moderation/:resultId":                 "Adjust a result's final score with a justification (requires admin auth)",
+					"GET    /admin/grading/pending":                                "Queue of essay answers awaiting a grade, oldest first (requires admin auth)",
+					"PUT    /admin/grading/results/:resultId/questions/:index":     "Grade an essay answer with points or a rubric and feedback (requires admin auth)",
+					"POST   /admin/exams/:id/scaling/preview":                      "Preview a linear or square-root curve of the sitting's scores (requires admin auth)",
+					"PUT    /admin/exams/:id/scaling":                              "Apply a curve to the sitting's scores, keeping raw scores (requires admin auth)",
+					"DELETE /admin/exams/:id/scaling":                              "Restore the sitting's raw scores (requires admin auth)",
+					"PUT    /admin/exams/:id/seats":                                "Assign venue and seats (requires admin auth)",
+					"DELETE /admin/exams/:id/seats/:userId":                        "Remove seat assignment (requires admin auth)",
+					"GET    /admin/exams/:id/attendance":                           "Attendance report, JSON or CSV (requires admin auth)",
+					"POST   /admin/exams/:id/offline-packages":                     "Create encrypted offline exam package (requires admin auth)",
+					"GET    /admin/exams/:id/offline-packages":                     "List offline exam packages (requires admin auth)",
+					"GET    /admin/exams/:id/offline-packages/:packageId/download": "Download encrypted offline package (requires admin auth)",
+					"POST   /admin/exams/:id/offline-packages/:packageId/answers":  "Upload and score offline answer file (requires admin auth)",
+					"GET    /admin/exams/:id/print":                                "Print exam papers or answer key as PDF, one version per room (requires admin auth)",
+					"POST   /admin/invitations":                                    "Email a signed, expiring registration invite (requires admin auth)",
+					"GET    /admin/invitations":                                    "List registration invitations (requires admin auth)",
+					"DELETE /admin/invitations/:id":                                "Revoke pending invitation (requires admin auth)",
+					"POST   /admin/changelog":                                      "Write release notes for a version, as a draft or published (requires admin auth)",
+					"GET    /admin/changelog":                                      "List release notes including drafts (requires admin auth)",
+					"GET    /admin/changelog/:id":                                  "Get release notes (requires admin auth)",
+					"PUT    /admin/changelog/:id":                                  "Edit, publish or unpublish release notes (requires admin auth)",
+					"DELETE /admin/changelog/:id":                                  "Delete release notes (requires admin auth)",
+					"POST   /admin/links":                                          "Create a short link to a frontend page, with optional custom code and expiry (requires admin auth)",
+					"GET    /admin/links":                                          "List short links with click counts (requires admin auth)",
+					"POST   /admin/class-quizzes":                                  "Start an in-class quick quiz with a join code and QR join URL (requires admin auth)",
+					"GET    /admin/class-quizzes":                                  "List class quizzes (requires admin auth)",
+					"POST   /admin/class-quizzes/:id/close":                        "Stop students joining a class quiz (requires admin auth)",
+					"GET    /admin/class-quizzes/:id/board":                        "Live results board for the classroom projector (requires admin auth)",
+					"DELETE /admin/links/:id":                                      "Revoke short link (requires admin auth)",
+					"POST   /admin/api-keys":                                       "Issue API key for a machine integration (requires admin auth)",
+					"GET    /admin/api-keys":                                       "List API keys with usage (requires admin auth)",
+					"GET    /admin/api-keys/:id":                                   "Get API key (requires admin auth)",
+					"PUT    /admin/api-keys/:id":                                   "Update API key name, scopes or rate limit (requires admin auth)",
+					"POST   /admin/api-keys/:id/revoke":                            "Revoke API key (requires admin auth)",
+					"DELETE /admin/api-keys/:id":                                   "Delete API key (requires admin auth)",
+					"GET    /admin/quiz-sessions/:id/integrity":                    "Integrity events a session's client reported, with their summary (requires admin auth)",
+					"POST   /admin/quiz-sessions/:id/pause":                        "Pause a student's quiz session and freeze its timer, with a reason (requires admin auth)",
+					"POST   /admin/quiz-sessions/:id/resume":                       "Resume a paused quiz session with the time it had left (requires admin auth)",
+					"POST   /admin/embed-widgets":                                  "Create embeddable practice quiz widget for departmental sites (requires admin auth)",
+					"GET    /admin/embed-widgets":                                  "List embed widgets with usage (requires admin auth)",
+					"GET    /admin/embed-widgets/:id":                              "Get embed widget and its embed code (requires admin auth)",
+					"PUT    /admin/embed-widgets/:id":                              "Update embed widget origins or quiz settings (requires admin auth)",
+					"POST   /admin/embed-widgets/:id/revoke":                       "Revoke embed widget (requires admin auth)",
+					"POST   /admin/result-webhooks":                                "Register department webhook for finalized exam results; returns signing secret once (requires admin auth)",
+					"GET    /admin/result-webhooks":                                "List result webhooks with delivery status (requires admin auth)",
+					"GET    /admin/result-webhooks/:id":                            "Get result webhook (requires admin auth)",
+					"PUT    /admin/result-webhooks/:id":                            "Update result webhook URL, department or active flag (requires admin auth)",
+					"DELETE /admin/result-webhooks/:id":                            "Delete result webhook (requires admin auth)",
+					"POST   /admin/result-webhooks/:id/rotate-secret":              "Rotate result webhook signing secret (requires admin auth)",
+					"POST   /admin/result-webhooks/:id/ping":                       "Send a signed test delivery to a result webhook (requires admin auth)",
+					"POST   /admin/users/import":                                   "Bulk import mahasiswa from CSV/XLSX (requires admin auth)",
+					"GET    /admin/analytics/cohorts/compare":                      "Compare scores, completion times and category performance between faculties, majors or semesters (requires admin auth)",
+					"GET    /admin/analytics/at-risk":                              "Students flagged for declining scores, low activity or repeated timeouts, highest risk first (requires admin auth)",
+					"GET    /admin/analytics/abandonment":                          "How sessions ended per quiz type, with abandonment reasons and drop-off points (requires admin auth)",
+					"GET    /admin/questions/:id/analytics":                        "Item analysis of a question: difficulty and discrimination indices, distractor effectiveness and average time (requires admin auth)",
+					"GET    /admin/telemetry":                                      "Client telemetry by event type, with a session's timeline when one is chosen (requires admin auth)",
+					"POST   /admin/analytics/at-risk/run":                          "Run the at-risk analysis now and send outreach notifications (requires admin auth)",
+					"POST   /admin/questions/difficulty/recalibrate":               "Compare difficulty labels with observed correct rates now; relabels questions or queues suggestions depending on RECALIBRATION_MODE (requires admin auth)",
+					"GET    /admin/difficulty-suggestions":                         "Difficulty changes suggested by recalibration, pending oldest first (requires admin auth)",
+					"PUT    /admin/difficulty-suggestions/:id":                     "Approve a difficulty suggestion, relabeling the question, or reject it (requires admin auth)",
+					"POST   /admin/users/purge-deleted":                            "Remove accounts past their deletion grace period (requires admin auth)",
+					"GET    /admin/jobs":                                           "Background jobs with their interval, next run on this instance and last run; only the scheduler leader runs them (requires admin auth)",
+					"GET    /admin/jobs/runs":                                      "Run history of the background jobs, newest first, optionally of one job (requires admin auth)",
+					"GET    /admin/settings":                                       "Runtime settings such as activity log retention and quiz defaults, from the environment until saved (requires admin auth)",
+					"PUT    /admin/settings":                                       "Change runtime settings; other instances pick them up on their next refresh (requires admin auth)",
+					"GET    /admin/dashboard":                                      "Admin dashboard (requires admin auth)",
+					"POST   /admin/questions":                                      "Create new question (requires admin auth)",
+					"GET    /admin/questions":                                      "List questions with filtering (requires admin auth)",
+					"GET    /admin/questions/:id":                                  "Get specific question (requires admin auth)",
+					"PUT    /admin/questions/:id":                                  "Update question (requires admin auth)",
+					"DELETE /admin/questions/:id":                                  "Archive question, or delete it for good with ?permanent=true when no quiz session uses it (requires admin auth)",
+					"POST   /admin/questions/:id/restore":                          "Return an archived question to the bank (requires admin auth)",
+					"PATCH  /admin/questions/:id/status":                           "Toggle question status (requires admin auth)",
+					"GET    /admin/questions/accessibility-report":                 "List questions with images missing alt text (requires admin auth)",
+					"GET    /admin/questions/authoring-report":                     "Questions written and reviewed per person (requires admin auth)",
+					"POST   /admin/questions/:id/reviews":                          "Record a review of a question (requires admin auth)",
+					"POST   /admin/questions/:id/submit":                           "Submit a draft question for review (requires admin auth)",
+					"POST   /admin/questions/:id/approve":                          "Approve a question in review so quizzes can use it (requires publishing admin auth)",
+					"POST   /admin/questions/:id/reject":                           "Return a question in review to its author as a draft, with a note (requires publishing admin auth)",
+					"GET    /admin/questions/:id/comments":                         "Reviewers' discussion on a question with the number of unresolved comments; filter with resolved (requires admin auth)",
+					"POST   /admin/questions/:id/comments":                         "Comment on a question, notifying the mentioned admins (requires admin auth)",
+					"PATCH  /admin/questions/:id/comments/:commentId":              "Resolve or reopen a question comment (requires admin auth)",
+					"GET    /admin/questions/:id/versions":                         "Stored versions of a question with author, time, changed fields and the quiz sessions built from each (requires admin auth)",
+					"GET    /admin/modules/:moduleId/revisions":                    "Stored revisions of a module's content with author, time and changed fields (requires admin auth)",
+					"GET    /admin/modules/:moduleId/revisions/diff":               "Fields changed between two module revisions, with a line diff of text (requires admin auth)",
+					"POST   /admin/modules/:moduleId/revisions/:revision/rollback": "Restore a module's content to an earlier revision as a new revision (requires admin auth)",
+					"POST   /admin/modules/:moduleId/attachments":                  "Attach a PDF or slide deck to a module or one of its submodules as multipart file, with optional submodule_id (requires admin auth)",
+					"DELETE /admin/modules/:moduleId/attachments/:attachmentId":    "Delete a module attachment and its stored file (requires admin auth)",
+					"GET    /admin/modules/:moduleId/analytics":                    "Views, unique viewers and reading time of a module with views over time and drop-off by submodule; date_from, date_to, interval=day|week (requires admin auth)",
+					"POST   /modules/:moduleId/views":                              "Record that the learner read a module or submodule, with submodule_id and duration_seconds (requires auth or guest token)",
+					"POST   /admin/modules/import":                                 "Create a draft module from a zip of markdown pages with front-matter and images; dry_run checks it only (requires admin auth)",
+					"GET    /modules/:moduleId/images/:imageId":                    "Image uploaded with module content (public)",
+					"GET    /admin/modules/:moduleId/export":                       "Download a published module as a SCORM 1.2 or xAPI package for an LMS; format=scorm12|xapi (requires admin auth)",
+					"PUT    /modules/:moduleId/position":                           "Save where the learner is in a module: submodule_id, block_index, scroll_percent (requires auth)",
+					"GET    /modules/continue":                                     "Modules the learner left part way through with their position and minutes of reading left; limit (requires auth)",
+					"GET    /modules/search":                                       "Search module and submodule names, descriptions and content with highlighted matches and q, published (admins), page, limit; learners only find published content (optional auth)",
+					"GET    /modules/:moduleId/attachments":                        "Files attached to a module; learners see those of content they can read (requires auth)",
+					"GET    /modules/:moduleId/attachments/:attachmentId/download": "Download a module attachment (requires auth)",
+					"GET    /admin/questions/stats":                                "Get question statistics (requires admin auth)",
+					"POST   /admin/questions/validate":                             "Validate question data (requires admin auth)",
+					"POST   /admin/questions/import":                               "Bulk import questions from CSV/XLSX/JSON with optional dry_run (requires admin auth)",
+					"GET    /admin/questions/export":                               "Export questions as CSV/XLSX/JSON with list filters, optionally without answers (requires admin auth)",
+					"POST   /admin/questions/:id/audio":                            "Generate text-to-speech audio for a question (requires admin auth)",
+					"POST   /admin/questions/audio/generate":                       "Generate missing or stale question audio in bulk (requires admin auth)",
+					"GET    /admin/activity-logs":                                  "Get activity logs with filtering (requires admin auth)",
+					"GET    /admin/activity-logs/stats":                            "Get activity statistics (requires admin auth)",
+					"GET    /admin/activity-logs/recent":                           "Get recent activities (requires admin auth)",
+					"GET    /admin/activity-logs/types":                            "Get available activity types (requires admin auth)",
+					"GET    /admin/activity-logs/:id":                              "Get specific activity log (requires admin auth)",
+					"POST   /admin/activity-logs/cleanup":                          "Cleanup old activity logs (requires admin auth)",
+
+					"PATCH  /admin/modules/:moduleId/submodules/:submoduleId/discussion":           "Open or close a submodule's discussion with {enabled} (requires admin auth)",
+					"GET    /modules/:moduleId/submodules/:submoduleId/comments":                   "Page through a submodule's discussion, newest questions first with their replies (requires auth)",
+					"POST   /modules/:moduleId/submodules/:submoduleId/comments":                   "Ask a question under a submodule, or reply to one with parent_id; the thread is notified (requires auth)",
+					"POST   /modules/:moduleId/submodules/:submoduleId/comments/:commentId/report": "Report an abusive comment with a reason: spam, abuse, off_topic or other (requires auth)",
+					"GET    /admin/modules/comments/reported":                                      "Reported discussion comments awaiting moderation, most reported first (requires admin auth)",
+					"DELETE /admin/modules/:moduleId/comments/:commentId":                          "Remove a discussion comment, keeping its replies (requires admin auth)",
+					"DELETE /admin/modules/:moduleId/comments/:commentId/reports":                  "Dismiss the reports of a discussion comment (requires admin auth)",
+				},
+				"integrations": gin.H{
+					"GET /integrations/questions":             "List questions (X-API-Key, questions:read)",
+					"GET /integrations/questions/stats":       "Question statistics (X-API-Key, questions:read)",
+					"GET /integrations/questions/:id":         "Get question (X-API-Key, questions:read)",
+					"GET /integrations/users/:userID/results": "Quiz results for a user (X-API-Key, results:read)",
+					"GET /integrations/exams/:id/attendance":  "Exam attendance report, JSON or CSV (X-API-Key, results:read)",
+					"GET /integrations/feeds/modules.atom":    "Atom feed of newly published modules and submodules (X-API-Key, content:read)",
+					"GET /integrations/feeds/modules.rss":     "RSS feed of newly published modules and submodules (X-API-Key, content:read)",
+				},
+				"widget": gin.H{
+					"GET  /embed/:token":                           "Embed page for a departmental site's iframe, framed only by allowed origins (site root)",
+					"GET  /widget/:token/config":                   "Quiz the widget runs (embed token, allowed origins)",
+					"POST /widget/:token/sessions":                 "Start an anonymous widget attempt (embed token, allowed origins)",
+					"GET  /widget/:token/sessions/:session":        "Get a widget attempt (embed token, allowed origins)",
+					"POST /widget/:token/sessions/:session/answer": "Save an answer in a widget attempt (embed token, allowed origins)",
+					"POST /widget/:token/sessions/:session/submit": "Submit a widget attempt for scoring (embed token, allowed origins)",
 				},
 				"questions": gin.H{
-					"GET /questions/random": "Get random questions for quiz (public)",
+					"GET /questions/random":    "Get random questions for quiz (public)",
+					"GET /questions/:id/audio": "Stream text-to-speech audio for a question (public)",
+					"POST /questions/propose":  "Propose a question for the bank; accepted questions award XP (requires auth)",
+					"GET /questions/proposals": "List the caller's proposed questions and their review status (requires auth)",
+				},
+				"changelog": gin.H{
+					"GET /changelog": "Published release notes for the caller's audience, optionally since a time (public; admins see admin notes)",
+				},
+				"dashboard": gin.H{
+					"POST /api/graphql": "GraphQL query over users, modules, questions, quiz results and activity logs in one request; users, questions and activity logs are admin-only fields (site root, requires auth)",
 				},
 			},
 			"oauth_providers": []string{"google", "facebook", "apple", "github"},
@@ -175,6 +680,40 @@ func main() {
 		})
 	})
 
+	router.GET("/api/v2/docs", middleware.Envelope(), func(c *gin.Context) {
+		middleware.RespondData(c, http.StatusOK, gin.H{
+			"message": "QuizApp API Documentation",
+			"version": "v2",
+			"conventions": gin.H{
+				"envelope":   "Every body is {data, meta, error}: data holds the resource or list, meta how a list was paged, error what went wrong",
+				"paging":     "Lists take page and limit (1-100, default 20); meta has page, limit, total and total_pages",
+				"cursor":     "Lists that support it take cursor instead of page; pass meta.next_cursor to get the next page",
+				"naming":     "Paths, path parameters, query parameters and fields are snake_case",
+				"admin_only": "Every route under /admin requires an admin token; no other route answers differently for admins",
+			},
+			"endpoints": gin.H{
+				"modules": gin.H{
+					"GET /modules":            "Published modules in order, marked locked when the signed-in learner has not met their prerequisites (public)",
+					"GET /modules/:module_id": "A published module; 403 with the missing prerequisites when locked (public)",
+				},
+				"notifications": gin.H{
+					"GET /notifications": "The caller's notifications with the unread count in meta (requires auth)",
+				},
+				"admin": gin.H{
+					"GET   /admin/modules":             "Every module, published or not (requires admin auth)",
+					"GET   /admin/modules/:module_id":  "A module with all its content (requires admin auth)",
+					"GET   /admin/users":               "Accounts filtered by type, status or search (requires admin auth)",
+					"GET   /admin/users/stats":         "Account counts by type and status (requires admin auth)",
+					"GET   /admin/activity-logs":       "Activity log, by page or cursor (requires admin auth)",
+					"GET   /admin/settings":            "Runtime settings (requires admin auth)",
+					"PATCH /admin/settings":            "Change the runtime settings given (requires admin auth)",
+					"GET   /admin/jobs":                "Scheduled jobs and their last runs (requires admin auth)",
+					"GET   /admin/jobs/:job_name/runs": "Run history of one scheduled job (requires admin auth)",
+				},
+			},
+		})
+	})
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
@@ -185,29 +724,73 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on %s:%s", cfg.Server.Host, cfg.Server.Port)
-		log.Printf("Environment: %s", cfg.Server.Environment)
-		log.Printf("API documentation available at: http://%s:%s/api/v1/docs", cfg.Server.Host, cfg.Server.Port)
+		slog.Info("Starting server",
+			"address", server.Addr,
+			"environment", cfg.Server.Environment,
+			"docs", fmt.Sprintf("http://%s:%s/api/v1/docs", cfg.Server.Host, cfg.Server.Port),
+		)
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			slog.Error("Failed to start server", "error", err)
+			os.Exit(1)
 		}
 	}()
+	healthService.SetReady(true)
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server")
+
+	// Fail readiness first so load balancers stop sending new requests before the listener closes
+	healthService.SetReady(false)
+	if cfg.Server.DrainDelay > 0 {
+		slog.Info("Draining traffic before shutdown", "delay", cfg.Server.DrainDelay)
+		time.Sleep(cfg.Server.DrainDelay)
+	}
 
 	// Give outstanding requests time to complete
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		slog.Error("Server forced to shutdown", "error", err)
+		os.Exit(1)
+	}
+	stopScheduler()
+	if err := backgroundTasks.Wait(ctx); err != nil {
+		pending, byKind := backgroundTasks.Pending()
+		slog.Warn("Background tasks still running at shutdown", "pending", pending, "by_kind", byKind)
+	}
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			slog.Warn("Failed to send the last spans", "error", err)
+		}
+	}
+
+	slog.Info("Server exited")
+}
+
+// runHealthCheck asks the running server for its health, returning the exit code for the Docker health check
+func runHealthCheck(cfg models.ServerConfig) int {
+	host := cfg.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
 	}
 
-	log.Println("Server exited")
+	client := &http.Client{Timeout: 3 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://%s/health", net.JoinHostPort(host, cfg.Port)))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "health check failed:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(os.Stderr, "health check failed: status", resp.StatusCode)
+		return 1
+	}
+	return 0
 }
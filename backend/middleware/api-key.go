@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type APIKeyMiddleware struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyMiddleware(apiKeyService services.APIKeyService) *APIKeyMiddleware {
+	return &APIKeyMiddleware{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// RequireAPIKey authenticates machine integrations by the X-API-Key header,
+// checking the key's scope and per-minute rate limit
+func (m *APIKeyMiddleware) RequireAPIKey(scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeAPIKeyRequired, "X-API-Key header required")
+			c.Abort()
+			return
+		}
+
+		ipAddress, _ := services.ExtractClientInfo(c.Request)
+		apiKey, err := m.apiKeyService.Authenticate(c.Request.Context(), rawKey, ipAddress)
+		if err != nil {
+			RespondErrorDetails(c, http.StatusInternalServerError, "Failed to authenticate api key", err)
+			c.Abort()
+			return
+		}
+
+		if !apiKey.HasScope(scope) {
+			RespondError(c, http.StatusForbidden, "API key is missing the "+string(scope)+" scope")
+			c.Abort()
+			return
+		}
+
+		allowed, remaining, resetAt := m.apiKeyService.CheckRateLimit(c.Request.Context(), apiKey)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimitPerMinute))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+		if !allowed {
+			retryAfter := int(time.Until(resetAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			RespondError(c, http.StatusTooManyRequests, "API key rate limit exceeded")
+			c.Abort()
+			return
+		}
+
+		c.Set("apiKeyID", apiKey.ID)
+		c.Set("apiKeyName", apiKey.Name)
+
+		c.Next()
+	}
+}
+
+// GetAPIKeyID helper function to get the authenticated API key ID from context
+func GetAPIKeyID(c *gin.Context) (primitive.ObjectID, bool) {
+	apiKeyID, exists := c.Get("apiKeyID")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+	return apiKeyID.(primitive.ObjectID), true
+}
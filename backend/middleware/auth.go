@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"backend/models"
 	"backend/utils"
 
 	"github.com/gin-gonic/gin"
@@ -28,55 +29,93 @@ func (a *AuthMiddleware) GetJWTManager() *utils.JWTManager {
 // RequireAuth validates JWT token and sets user context
 func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authorization header required",
-			})
-			c.Abort()
-			return
+		if a.authenticate(c, false) {
+			c.Next()
 		}
+	}
+}
 
-		// Extract token from "Bearer <token>"
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization header format",
-			})
-			c.Abort()
-			return
+// RequireAuthOrGuest is RequireAuth that also accepts guest tokens, for routes anonymous
+// quiz takers may use
+func (a *AuthMiddleware) RequireAuthOrGuest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.authenticate(c, true) {
+			c.Next()
 		}
+	}
+}
 
-		token := tokenParts[1]
-
-		// Validate token
-		claims, err := a.jwtManager.ValidateToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
-			})
-			c.Abort()
-			return
+// RequireSocketAuth is RequireAuthOrGuest for WebSocket handshakes. Browsers cannot set headers on
+// those, so the access token may be passed in the access_token query parameter instead
+func (a *AuthMiddleware) RequireSocketAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
 		}
-
-		// Convert user ID to ObjectID
-		userID, err := primitive.ObjectIDFromHex(claims.UserID)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid user ID",
-			})
-			c.Abort()
-			return
+		if a.authenticate(c, true) {
+			c.Next()
 		}
+	}
+}
 
-		// Set user context
-		c.Set("userID", userID)
-		c.Set("userEmail", claims.Email)
-		c.Set("userType", claims.UserType)
-		c.Set("isAdmin", claims.IsAdmin)
+// authenticate validates the bearer token and sets user context, aborting the request on failure
+func (a *AuthMiddleware) authenticate(c *gin.Context, allowGuest bool) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeAuthRequired, "Authorization header required")
+		c.Abort()
+		return false
+	}
 
-		c.Next()
+	// Extract token from "Bearer <token>"
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "Invalid authorization header format")
+		c.Abort()
+		return false
 	}
+
+	token := tokenParts[1]
+
+	// Validate token
+	claims, err := a.jwtManager.ValidateToken(token)
+	if utils.IsTokenExpired(err) {
+		RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeTokenExpired, "Token has expired")
+		c.Abort()
+		return false
+	}
+	if err != nil {
+		RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "Invalid token")
+		c.Abort()
+		return false
+	}
+
+	if claims.UserType == utils.GuestUserType && !allowGuest {
+		RespondErrorCode(c, http.StatusForbidden, models.ErrorCodeAccountRequired, "An account is required for this action")
+		c.Abort()
+		return false
+	}
+
+	// Convert user ID to ObjectID
+	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "Invalid user ID")
+		c.Abort()
+		return false
+	}
+
+	// Set user context
+	c.Set("userID", userID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userType", claims.UserType)
+	c.Set("isAdmin", claims.IsAdmin)
+	c.Set("isGuest", claims.UserType == utils.GuestUserType)
+	setImpersonationContext(c, claims)
+	setCacheBypass(c, claims)
+
+	return true
 }
 
 // RequireAdmin requires admin privileges
@@ -84,17 +123,13 @@ func (a *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		isAdmin, exists := c.Get("isAdmin")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authentication required",
-			})
+			RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeAuthRequired, "Authentication required")
 			c.Abort()
 			return
 		}
 
 		if !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Admin privileges required",
-			})
+			RespondError(c, http.StatusForbidden, "Admin privileges required")
 			c.Abort()
 			return
 		}
@@ -108,17 +143,13 @@ func (a *AuthMiddleware) RequireMahasiswa() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userType, exists := c.Get("userType")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authentication required",
-			})
+			RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeAuthRequired, "Authentication required")
 			c.Abort()
 			return
 		}
 
 		if userType.(string) != "mahasiswa" {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Mahasiswa privileges required",
-			})
+			RespondError(c, http.StatusForbidden, "Mahasiswa privileges required")
 			c.Abort()
 			return
 		}
@@ -132,9 +163,7 @@ func (a *AuthMiddleware) RequireUserType(allowedTypes ...string) gin.HandlerFunc
 	return func(c *gin.Context) {
 		userType, exists := c.Get("userType")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Authentication required",
-			})
+			RespondErrorCode(c, http.StatusUnauthorized, models.ErrorCodeAuthRequired, "Authentication required")
 			c.Abort()
 			return
 		}
@@ -147,9 +176,7 @@ func (a *AuthMiddleware) RequireUserType(allowedTypes ...string) gin.HandlerFunc
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Insufficient privileges",
-		})
+		RespondError(c, http.StatusForbidden, "Insufficient privileges")
 		c.Abort()
 	}
 }
@@ -172,9 +199,9 @@ func (a *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 
 		token := tokenParts[1]
 
-		// Validate token
+		// Validate token; guest tokens don't identify anyone outside quiz routes
 		claims, err := a.jwtManager.ValidateToken(token)
-		if err != nil {
+		if err != nil || claims.UserType == utils.GuestUserType {
 			c.Next()
 			return
 		}
@@ -191,11 +218,38 @@ func (a *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		c.Set("userEmail", claims.Email)
 		c.Set("userType", claims.UserType)
 		c.Set("isAdmin", claims.IsAdmin)
+		setImpersonationContext(c, claims)
+		setCacheBypass(c, claims)
 
 		c.Next()
 	}
 }
 
+// setImpersonationContext marks the request as impersonated when the token carries an impersonator
+func setImpersonationContext(c *gin.Context, claims *utils.Claims) {
+	if claims.ImpersonatedBy == "" {
+		return
+	}
+
+	impersonatedBy, err := primitive.ObjectIDFromHex(claims.ImpersonatedBy)
+	if err != nil {
+		return
+	}
+
+	c.Set("impersonatedBy", impersonatedBy)
+}
+
+// setCacheBypass lets admins read past the cache by sending Cache-Control: no-cache, to see their own
+// changes at once or check what is cached against the database
+func setCacheBypass(c *gin.Context, claims *utils.Claims) {
+	if !claims.IsAdmin || claims.ImpersonatedBy != "" {
+		return
+	}
+	if strings.Contains(c.GetHeader("Cache-Control"), "no-cache") || c.GetHeader("Pragma") == "no-cache" {
+		c.Request = c.Request.WithContext(utils.WithCacheBypass(c.Request.Context()))
+	}
+}
+
 // GetUserID helper function to get user ID from context
 func GetUserID(c *gin.Context) (primitive.ObjectID, bool) {
 	userID, exists := c.Get("userID")
@@ -231,3 +285,21 @@ func IsAdmin(c *gin.Context) bool {
 	}
 	return isAdmin.(bool)
 }
+
+// IsGuest helper function to check if the request comes from an anonymous guest
+func IsGuest(c *gin.Context) bool {
+	isGuest, exists := c.Get("isGuest")
+	if !exists {
+		return false
+	}
+	return isGuest.(bool)
+}
+
+// GetImpersonatedBy helper function to get the impersonating admin ID from context
+func GetImpersonatedBy(c *gin.Context) (primitive.ObjectID, bool) {
+	impersonatedBy, exists := c.Get("impersonatedBy")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+	return impersonatedBy.(primitive.ObjectID), true
+}
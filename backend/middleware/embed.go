@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmbedMiddleware struct {
+	embedWidgetService services.EmbedWidgetService
+}
+
+func NewEmbedMiddleware(embedWidgetService services.EmbedWidgetService) *EmbedMiddleware {
+	return &EmbedMiddleware{
+		embedWidgetService: embedWidgetService,
+	}
+}
+
+// RequireWidget authenticates widget API calls by the embed token in the path. Browsers calling
+// from another site must come from one of the widget's allowed origins, and only those origins get
+// CORS headers; the widget API never accepts credentials.
+func (m *EmbedMiddleware) RequireWidget() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		widget, err := m.embedWidgetService.Authenticate(c.Request.Context(), c.Param("token"))
+		if err != nil {
+			RespondErrorDetails(c, http.StatusInternalServerError, "Failed to load embed widget", err)
+			c.Abort()
+			return
+		}
+
+		origin := c.GetHeader("Origin")
+		if origin != "" && !sameOrigin(origin, c.Request.Host) {
+			if !widget.AllowsOrigin(strings.ToLower(origin)) {
+				RespondError(c, http.StatusForbidden, "This site is not allowed to embed the widget")
+				c.Abort()
+				return
+			}
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Content-Type")
+			c.Header("Vary", "Origin")
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Set("embedWidget", widget)
+		c.Next()
+	}
+}
+
+// GetEmbedWidget returns the widget authenticated by RequireWidget
+func GetEmbedWidget(c *gin.Context) (*models.EmbedWidget, bool) {
+	widget, exists := c.Get("embedWidget")
+	if !exists {
+		return nil, false
+	}
+	w, ok := widget.(*models.EmbedWidget)
+	return w, ok
+}
+
+// ExceptPaths runs handler for every request outside the path prefixes. The global CORS policy
+// only knows the app's own origins, so it is skipped for routes that set their own.
+func ExceptPaths(handler gin.HandlerFunc, prefixes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		handler(c)
+	}
+}
+
+func sameOrigin(origin, host string) bool {
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, host)
+}
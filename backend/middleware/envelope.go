@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+
+	"backend/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// envelopeKey marks requests answered with models.Envelope
+const envelopeKey = "envelope"
+
+// Envelope makes every answer to the routes it guards, errors from other middleware included, use
+// models.Envelope. Routes outside it keep their bare bodies, which is what keeps /api/v1 frozen.
+func Envelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(envelopeKey, true)
+		c.Next()
+	}
+}
+
+func usesEnvelope(c *gin.Context) bool {
+	return c.GetBool(envelopeKey)
+}
+
+// RespondData answers with a resource as the envelope's data
+func RespondData(c *gin.Context, status int, data any) {
+	c.JSON(status, models.Envelope{Data: data})
+}
+
+// RespondPage answers with a page of a list as the envelope's data, and how it was paged as its meta.
+// An empty page is an empty list, never null.
+func RespondPage[T any](c *gin.Context, items []T, meta *models.PageMeta) {
+	if items == nil {
+		items = []T{}
+	}
+	c.JSON(http.StatusOK, models.Envelope{Data: items, Meta: meta})
+}
+
+// BindQuery binds the query string into req, answering the request itself when it does not bind
+func BindQuery(c *gin.Context, req any) bool {
+	if err := c.ShouldBindQuery(req); err != nil {
+		RespondInvalidRequest(c, "Invalid query parameters", err)
+		return false
+	}
+	return true
+}
+
+// BindJSON binds the body into req, answering the request itself when it does not bind
+func BindJSON(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		RespondInvalidRequest(c, "Invalid request data", err)
+		return false
+	}
+	return true
+}
+
+// PathObjectID reads an ID from the path, answering the request itself when it is malformed.
+// name is what the ID identifies, for the error message.
+func PathObjectID(c *gin.Context, param, name string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param(param))
+	if err != nil {
+		RespondErrorCode(c, http.StatusBadRequest, models.ErrorCodeInvalidID, "Invalid "+name+" ID")
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RespondError answers with the error envelope under the general code of the status
+func RespondError(c *gin.Context, status int, message string) {
+	respondError(c, status, models.StatusErrorCode(status), message, nil)
+}
+
+// RespondErrorCode answers with the error envelope under a code of its own
+func RespondErrorCode(c *gin.Context, status int, code models.ErrorCode, message string) {
+	respondError(c, status, code, message, nil)
+}
+
+// RespondServiceError answers with an error a service returned, under the status, code and details
+// the error carries or else the given status and its general code
+func RespondServiceError(c *gin.Context, status int, err error) {
+	var details any
+	if serviceErr, ok := services.ErrorStatus(err); ok {
+		status, details = serviceErr.Status, serviceErr.Details
+		if retry, ok := details.(models.RetryDetails); ok {
+			c.Header("Retry-After", strconv.Itoa(retry.RetryAfterSeconds))
+		}
+	}
+	respondError(c, status, serviceErrorCode(status, err), err.Error(), details)
+}
+
+// RespondErrorDetails answers with a message of the handler's own and the error that caused it as the
+// details. An error that carries its own status is answered like RespondServiceError instead.
+func RespondErrorDetails(c *gin.Context, status int, message string, err error) {
+	if _, ok := services.ErrorStatus(err); ok {
+		RespondServiceError(c, status, err)
+		return
+	}
+	respondError(c, status, serviceErrorCode(status, err), message, err.Error())
+}
+
+// RespondServiceFailure answers an error that carries its own status like RespondServiceError, and
+// any other with status and the handler's message alone, so unexpected errors are not shown
+func RespondServiceFailure(c *gin.Context, status int, message string, err error) {
+	if _, ok := services.ErrorStatus(err); ok {
+		RespondServiceError(c, status, err)
+		return
+	}
+	RespondError(c, status, message)
+}
+
+// RespondInvalidRequest answers a body or query that did not bind, with why as the details
+func RespondInvalidRequest(c *gin.Context, message string, err error) {
+	respondError(c, http.StatusBadRequest, models.ErrorCodeValidation, message, err.Error())
+}
+
+// RespondErrorWithDetails answers with the error envelope and details other than an error, such as
+// when to try again
+func RespondErrorWithDetails(c *gin.Context, status int, code models.ErrorCode, message string, details any) {
+	respondError(c, status, code, message, details)
+}
+
+func serviceErrorCode(status int, err error) models.ErrorCode {
+	if code := services.ErrorCode(err); code != "" {
+		return code
+	}
+	return models.StatusErrorCode(status)
+}
+
+func respondError(c *gin.Context, status int, code models.ErrorCode, message string, details any) {
+	response := models.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: utils.RequestID(c.Request.Context()),
+	}
+	if usesEnvelope(c) {
+		c.JSON(status, models.Envelope{Error: &response})
+		return
+	}
+	c.JSON(status, response)
+}
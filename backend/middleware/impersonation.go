@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"backend/models"
+	"backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationAudit records every request made with an impersonation token in the activity log.
+// It must be registered before the route groups so it wraps the handlers that set the auth context.
+func ImpersonationAudit(activityLogService services.ActivityLogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatedBy, exists := GetImpersonatedBy(c)
+		if !exists {
+			return
+		}
+
+		userID, _ := GetUserID(c)
+		userEmail, _ := GetUserEmail(c)
+		userType, _ := GetUserType(c)
+		ipAddress, userAgent := services.ExtractClientInfo(c.Request)
+
+		activityLog := models.NewActivityLog(
+			models.ActivityImpersonatedAction,
+			fmt.Sprintf("%s %s", c.Request.Method, c.FullPath()),
+			"user",
+			userID.Hex(),
+			userEmail,
+			userID,
+			userEmail,
+			userType,
+		)
+		activityLog.SetImpersonatedBy(impersonatedBy)
+		activityLog.SetDetails("method", c.Request.Method)
+		activityLog.SetDetails("path", c.Request.URL.Path)
+		activityLog.SetDetails("status", c.Writer.Status())
+		activityLog.SetClientInfo(ipAddress, userAgent)
+		if c.Writer.Status() >= 400 {
+			activityLog.MarkFailed(fmt.Sprintf("request failed with status %d", c.Writer.Status()))
+		}
+
+		go func() {
+			if err := activityLogService.LogActivity(context.Background(), activityLog); err != nil {
+				slog.Error("Failed to log impersonated action", "error", err)
+			}
+		}()
+	}
+}
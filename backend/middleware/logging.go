@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader names the request in both directions: a caller, such as a proxy, may send one to
+// correlate its logs with ours, and every response carries the one used
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID keeps request IDs sent by callers to a safe size and alphabet for logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger gives each request an ID, puts it in the request context for everything logged while
+// handling it, and logs the request when it is done. Paths are logged without their query, which can
+// hold OAuth codes and tokens.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = utils.NewRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), requestID))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", requestID))
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+		}
+		if userID, ok := GetUserID(c); ok {
+			attrs = append(attrs, slog.String("user_id", userID.Hex()))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "Handled request", attrs...)
+	}
+}
+
+// Recovery answers a panicking request with a 500 and logs the panic with its stack
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "Recovered from panic",
+			"error", fmt.Sprint(recovered),
+			"path", c.Request.URL.Path,
+			"stack", string(debug.Stack()),
+		)
+		RespondError(c, http.StatusInternalServerError, "Internal server error")
+		c.Abort()
+	})
+}
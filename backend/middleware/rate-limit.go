@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitMiddleware struct {
+	store      utils.RateLimitStore
+	policies   map[models.RateLimitPolicyName]models.RateLimitPolicy
+	jwtManager *utils.JWTManager
+}
+
+func NewRateLimitMiddleware(store utils.RateLimitStore, config models.RateLimitConfig, jwtManager *utils.JWTManager) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store:      store,
+		policies:   config.Policies,
+		jwtManager: jwtManager,
+	}
+}
+
+// Limit counts each client's requests to the routes sharing the policy, by IP address, answering
+// 429 with Retry-After once the limit is reached. Requests with an admin token are not counted.
+// Should the store fail, requests are let through rather than locking everyone out.
+func (m *RateLimitMiddleware) Limit(name models.RateLimitPolicyName) gin.HandlerFunc {
+	policy := m.policies[name]
+	if policy.Limit <= 0 || policy.Window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if m.hasAdminToken(c) {
+			c.Next()
+			return
+		}
+
+		count, resetAt, err := m.store.Hit(c.Request.Context(), "ratelimit:"+string(name)+":"+c.ClientIP(), policy.Window)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to check rate limit", "policy", name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(max(policy.Limit-count, 0)))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+		if count > policy.Limit {
+			retryAfter := int(time.Until(resetAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			RespondError(c, http.StatusTooManyRequests, "Too many requests, please try again later")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasAdminToken reports whether the request carries a valid admin access token. Impersonation
+// tokens act as the impersonated user and are counted like theirs.
+func (m *RateLimitMiddleware) hasAdminToken(c *gin.Context) bool {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return false
+	}
+	claims, err := m.jwtManager.ValidateToken(token)
+	return err == nil && claims.IsAdmin && claims.ImpersonatedBy == ""
+}
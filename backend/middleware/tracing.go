@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TraceParentHeader carries W3C trace context, letting a caller's trace continue through the server
+const TraceParentHeader = "traceparent"
+
+// Tracing records a server span for each request, named by its route so requests to the same
+// endpoint group together. Work done with the request context, down to database commands, nests
+// under it, and the response names the span so a slow request can be looked up.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := utils.StartServerSpan(c.Request.Context(), name, c.Request.Header,
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", c.Request.URL.Path),
+			attribute.String("client.address", c.ClientIP()),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		utils.InjectTraceContext(ctx, c.Writer.Header())
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if userID, ok := GetUserID(c); ok {
+			span.SetAttributes(attribute.String("user.id", userID.Hex()))
+		}
+		if status >= http.StatusInternalServerError {
+			if err := c.Errors.Last(); err != nil {
+				utils.RecordSpanError(span, err)
+			} else {
+				utils.RecordSpanError(span, errors.New(http.StatusText(status)))
+			}
+		}
+	}
+}
@@ -16,6 +16,13 @@ const (
 	ActivityModuleDeleted     ActivityType = "module_deleted"
 	ActivityModulePublished   ActivityType = "module_published"
 	ActivityModuleUnpublished ActivityType = "module_unpublished"
+	ActivityModuleUnlocked    ActivityType = "module_unlocked" // Prerequisites waived or reinstated for one learner
+	ActivityModuleRolledBack  ActivityType = "module_rolled_back"
+	ActivityModuleImported    ActivityType = "module_imported" // Created from a markdown bundle
+	ActivityModuleExported    ActivityType = "module_exported" // Packaged for an LMS
+
+	ActivityModuleAttachmentAdded   ActivityType = "module_attachment_added"
+	ActivityModuleAttachmentDeleted ActivityType = "module_attachment_deleted"
 
 	// SubModule activities
 	ActivitySubModuleCreated     ActivityType = "submodule_created"
@@ -24,19 +31,34 @@ const (
 	ActivitySubModulePublished   ActivityType = "submodule_published"
 	ActivitySubModuleUnpublished ActivityType = "submodule_unpublished"
 
+	ActivitySubModuleDiscussionOpened ActivityType = "submodule_discussion_opened"
+	ActivitySubModuleDiscussionClosed ActivityType = "submodule_discussion_closed"
+	ActivityModuleCommentRemoved      ActivityType = "module_comment_removed"
+
 	// Question activities
 	ActivityQuestionCreated     ActivityType = "question_created"
 	ActivityQuestionUpdated     ActivityType = "question_updated"
 	ActivityQuestionDeleted     ActivityType = "question_deleted"
 	ActivityQuestionActivated   ActivityType = "question_activated"
 	ActivityQuestionDeactivated ActivityType = "question_deactivated"
+	ActivityQuestionReviewed    ActivityType = "question_reviewed"
+	ActivityQuestionArchived    ActivityType = "question_archived"
+	ActivityQuestionRestored    ActivityType = "question_restored"
+	ActivityQuestionSubmitted   ActivityType = "question_submitted"
+	ActivityQuestionApproved    ActivityType = "question_approved"
+	ActivityQuestionRejected    ActivityType = "question_rejected"
 
 	// User management activities
-	ActivityUserAccessGranted ActivityType = "user_access_granted"
-	ActivityUserAccessRevoked ActivityType = "user_access_revoked"
-	ActivityUserSuspended     ActivityType = "user_suspended"
-	ActivityUserActivated     ActivityType = "user_activated"
-	ActivityUserRoleChanged   ActivityType = "user_role_changed"
+	ActivityUserAccessGranted  ActivityType = "user_access_granted"
+	ActivityUserAccessRevoked  ActivityType = "user_access_revoked"
+	ActivityUserSuspended      ActivityType = "user_suspended"
+	ActivityUserActivated      ActivityType = "user_activated"
+	ActivityUserRoleChanged    ActivityType = "user_role_changed"
+	ActivityUserImpersonated   ActivityType = "user_impersonated"
+	ActivityAccountDeletion    ActivityType = "account_deletion_requested"
+	ActivityInvitationSent     ActivityType = "invitation_sent"
+	ActivityInvitationRevoked  ActivityType = "invitation_revoked"
+	ActivityInvitationAccepted ActivityType = "invitation_accepted"
 
 	// Authentication activities
 	ActivityUserLogin       ActivityType = "user_login"
@@ -46,11 +68,60 @@ const (
 	ActivityMahasiswaLogin  ActivityType = "mahasiswa_login"
 	ActivityExternalLogin   ActivityType = "external_login"
 
+	// API key activities
+	ActivityAPIKeyCreated ActivityType = "api_key_created"
+	ActivityAPIKeyUpdated ActivityType = "api_key_updated"
+	ActivityAPIKeyRevoked ActivityType = "api_key_revoked"
+	ActivityAPIKeyDeleted ActivityType = "api_key_deleted"
+
+	// Embed widget activities
+	ActivityEmbedWidgetCreated ActivityType = "embed_widget_created"
+	ActivityEmbedWidgetUpdated ActivityType = "embed_widget_updated"
+	ActivityEmbedWidgetRevoked ActivityType = "embed_widget_revoked"
+
+	// Result webhook activities
+	ActivityResultWebhookCreated       ActivityType = "result_webhook_created"
+	ActivityResultWebhookUpdated       ActivityType = "result_webhook_updated"
+	ActivityResultWebhookDeleted       ActivityType = "result_webhook_deleted"
+	ActivityResultWebhookSecretRotated ActivityType = "result_webhook_secret_rotated"
+
+	// Changelog activities
+	ActivityChangelogCreated   ActivityType = "changelog_created"
+	ActivityChangelogUpdated   ActivityType = "changelog_updated"
+	ActivityChangelogPublished ActivityType = "changelog_published"
+	ActivityChangelogDeleted   ActivityType = "changelog_deleted"
+
+	// Short link activities
+	ActivityShortLinkCreated ActivityType = "short_link_created"
+	ActivityShortLinkRevoked ActivityType = "short_link_revoked"
+
+	// Question proposal activities
+	ActivityQuestionProposalAccepted ActivityType = "question_proposal_accepted"
+	ActivityQuestionProposalRejected ActivityType = "question_proposal_rejected"
+
+	// Difficulty recalibration activities
+	ActivityQuestionDifficultyRecalibrated ActivityType = "question_difficulty_recalibrated"
+	ActivityDifficultySuggestionRejected   ActivityType = "difficulty_suggestion_rejected"
+
+	// Exam activities
+	ActivityScoreModerated ActivityType = "score_moderated"
+	ActivityScoresScaled   ActivityType = "scores_scaled"
+	ActivityEssayGraded    ActivityType = "essay_graded"
+
+	// Quiz template activities
+	ActivityQuizTemplateCreated ActivityType = "quiz_template_created"
+	ActivityQuizTemplateUpdated ActivityType = "quiz_template_updated"
+	ActivityQuizTemplateDeleted ActivityType = "quiz_template_deleted"
+
+	// Impersonation activities
+	ActivityImpersonatedAction ActivityType = "impersonated_action"
+
 	// System activities
 	ActivitySystemMaintenance ActivityType = "system_maintenance"
 	ActivityBulkOperation     ActivityType = "bulk_operation"
 	ActivityDataExport        ActivityType = "data_export"
 	ActivityDataImport        ActivityType = "data_import"
+	ActivitySettingsUpdated   ActivityType = "settings_updated"
 )
 
 // ActivityLog represents a system activity log entry
@@ -73,6 +144,9 @@ type ActivityLog struct {
 	IPAddress string                 `json:"ip_address,omitempty" bson:"ip_address,omitempty"` // Client IP address
 	UserAgent string                 `json:"user_agent,omitempty" bson:"user_agent,omitempty"` // Client user agent
 
+	// Admin who was impersonating the performing user, if any
+	ImpersonatedBy *primitive.ObjectID `json:"impersonated_by,omitempty" bson:"impersonated_by,omitempty"`
+
 	// Metadata
 	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
 	Success   bool      `json:"success" bson:"success"`                         // Whether the action was successful
@@ -89,6 +163,7 @@ type GetActivityLogsRequest struct {
 	DateFrom   *time.Time   `json:"date_from,omitempty"`
 	DateTo     *time.Time   `json:"date_to,omitempty"`
 	Success    *bool        `json:"success,omitempty"`
+	Cursor     string       `json:"cursor,omitempty"` // Continue after a next_cursor instead of paging by number
 }
 
 type GetActivityLogsResponse struct {
@@ -97,6 +172,10 @@ type GetActivityLogsResponse struct {
 	Page       int           `json:"page"`
 	Limit      int           `json:"limit"`
 	TotalPages int           `json:"total_pages"`
+
+	// Pass as cursor to get the page after this one; empty on the last page. Pages fetched by cursor
+	// leave total, page and total_pages unset, since counting is what makes deep pages slow.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ActivityStats struct {
@@ -158,6 +237,12 @@ func (a *ActivityLog) SetClientInfo(ipAddress, userAgent string) *ActivityLog {
 	return a
 }
 
+// SetImpersonatedBy flags the activity as performed during an impersonation session
+func (a *ActivityLog) SetImpersonatedBy(adminID primitive.ObjectID) *ActivityLog {
+	a.ImpersonatedBy = &adminID
+	return a
+}
+
 // MarkFailed marks the activity as failed with an error message
 func (a *ActivityLog) MarkFailed(errorMsg string) *ActivityLog {
 	a.Success = false
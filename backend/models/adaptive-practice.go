@@ -0,0 +1,82 @@
+package models
+
+// Adaptive practice draws questions one at a time. A run of correct answers moves the session up a
+// difficulty; a miss that drags the rolling accuracy too low moves it down.
+const (
+	AdaptiveWindow           = 5   // Latest answers the rolling accuracy is measured over
+	AdaptiveStepUpStreak     = 3   // Correct answers in a row that move the session up a difficulty
+	AdaptiveStepDownAccuracy = 0.6 // A miss moves the session down when the rolling accuracy falls below this
+)
+
+// AdaptiveState tracks the difficulty of an adaptive practice session. The window starts over
+// whenever the difficulty changes, so each level is judged on its own answers.
+type AdaptiveState struct {
+	Difficulty DifficultyLevel `json:"difficulty" bson:"difficulty"` // Difficulty of the question being answered
+	Recent     []bool          `json:"recent" bson:"recent"`         // Whether each answer at this difficulty was correct, latest last
+	Accuracy   float64         `json:"accuracy" bson:"accuracy"`     // Share of Recent answered correctly
+}
+
+// NewAdaptiveState starts a session in the middle of the difficulty range
+func NewAdaptiveState() *AdaptiveState {
+	return &AdaptiveState{Difficulty: Medium, Recent: []bool{}}
+}
+
+// Record adds an answer to the window and moves the difficulty when the answers call for it.
+// Skipped questions count as misses.
+func (a *AdaptiveState) Record(correct bool) {
+	a.Recent = append(a.Recent, correct)
+	if len(a.Recent) > AdaptiveWindow {
+		a.Recent = a.Recent[len(a.Recent)-AdaptiveWindow:]
+	}
+
+	streak, right := 0, 0
+	for i := len(a.Recent) - 1; i >= 0 && a.Recent[i]; i-- {
+		streak++
+	}
+	for _, r := range a.Recent {
+		if r {
+			right++
+		}
+	}
+	a.Accuracy = float64(right) / float64(len(a.Recent))
+
+	next := a.Difficulty
+	switch {
+	case correct && streak >= AdaptiveStepUpStreak:
+		next = harderThan(a.Difficulty)
+	case !correct && a.Accuracy < AdaptiveStepDownAccuracy:
+		next = easierThan(a.Difficulty)
+	}
+	if next != a.Difficulty {
+		a.Difficulty = next
+		a.Recent = []bool{}
+		a.Accuracy = 0
+	}
+}
+
+// DrawOrder lists the difficulties to draw the next question from: the current one, then the
+// nearest others for when the bank runs out of it
+func (a *AdaptiveState) DrawOrder() []DifficultyLevel {
+	switch a.Difficulty {
+	case Easy:
+		return []DifficultyLevel{Easy, Medium, Hard}
+	case Hard:
+		return []DifficultyLevel{Hard, Medium, Easy}
+	default:
+		return []DifficultyLevel{Medium, Easy, Hard}
+	}
+}
+
+func harderThan(d DifficultyLevel) DifficultyLevel {
+	if d == Easy {
+		return Medium
+	}
+	return Hard
+}
+
+func easierThan(d DifficultyLevel) DifficultyLevel {
+	if d == Hard {
+		return Medium
+	}
+	return Easy
+}
@@ -0,0 +1,227 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CohortGroupBy is how results are split into cohorts for comparison
+type CohortGroupBy string
+
+const (
+	CohortByFaculty CohortGroupBy = "faculty" // Mahasiswa faculty
+	CohortByMajor   CohortGroupBy = "major"   // Mahasiswa major
+	// Academic semester the result was submitted in: the odd semester runs from August to
+	// January ("2025/2026-1"), the even semester from February to July ("2025/2026-2")
+	CohortBySemester CohortGroupBy = "semester"
+)
+
+// CohortResultRow is a cohort's finished attempts as aggregated from detailed results
+type CohortResultRow struct {
+	Cohort    string    `bson:"_id"`
+	Students  int       `bson:"students"`
+	Attempts  int       `bson:"attempts"`
+	TimedOut  int       `bson:"timed_out"`
+	Scores    []float64 `bson:"scores"`
+	TimesUsed []int64   `bson:"times_used"`
+}
+
+// CohortCategoryRow counts a cohort's answers to questions of one difficulty and type
+type CohortCategoryRow struct {
+	Key struct {
+		Cohort     string          `bson:"cohort"`
+		Difficulty DifficultyLevel `bson:"difficulty"`
+		Type       QuestionType    `bson:"type"`
+	} `bson:"_id"`
+	Total   int `bson:"total"`
+	Correct int `bson:"correct"`
+	Skipped int `bson:"skipped"`
+}
+
+// AbandonmentRow counts one quiz type's sessions that ended one way, as aggregated from sessions
+type AbandonmentRow struct {
+	Key struct {
+		QuizType QuizType      `bson:"quiz_type"`
+		Status   QuizStatus    `bson:"status"`
+		Reason   AbandonReason `bson:"reason"`
+	} `bson:"_id"`
+	Sessions      int     `bson:"sessions"`
+	AnsweredRatio float64 `bson:"answered_ratio"` // Mean share of questions answered
+	StopQuestion  float64 `bson:"stop_question"`  // Mean zero-based question the student was on
+}
+
+// Request/Response models for analytics
+
+// CompareCohortsRequest selects the cohorts and results to compare
+type CompareCohortsRequest struct {
+	GroupBy  CohortGroupBy `form:"group_by" binding:"required,oneof=faculty major semester"`
+	Cohorts  string        `form:"cohorts"` // Comma-separated cohort names; empty compares every cohort
+	QuizType QuizType      `form:"quiz_type" binding:"omitempty,oneof=mock_test time_quiz practice quick_quiz team_quiz adaptive_practice custom_quiz embed_quiz"`
+	DateFrom string        `form:"date_from"`                                   // YYYY-MM-DD
+	DateTo   string        `form:"date_to"`                                     // YYYY-MM-DD, inclusive
+	PassMark float64       `form:"pass_mark" binding:"omitempty,min=0,max=100"` // Score percentage counted as a pass
+	Refresh  bool          `form:"refresh"`                                     // Recompute instead of using a cached comparison
+}
+
+// CohortFilter is a parsed CompareCohortsRequest
+type CohortFilter struct {
+	GroupBy  CohortGroupBy
+	Cohorts  []string
+	QuizType QuizType
+	From     *time.Time
+	To       *time.Time // Exclusive
+	PassMark float64
+}
+
+// DurationStats summarises completion times in seconds
+type DurationStats struct {
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+}
+
+// CategoryPerformance is how a cohort answered the questions of one category
+type CategoryPerformance struct {
+	Category string  `json:"category"`
+	Answered int     `json:"answered"` // Questions asked, including skipped ones
+	Correct  int     `json:"correct"`
+	Skipped  int     `json:"skipped"`
+	Accuracy float64 `json:"accuracy"` // Correct / Answered * 100
+}
+
+// CohortStats compares one cohort's results
+type CohortStats struct {
+	Cohort         string                `json:"cohort"`
+	Students       int                   `json:"students"`
+	Attempts       int                   `json:"attempts"`
+	TimedOut       int                   `json:"timed_out"`
+	Scores         ScoreDistribution     `json:"scores"`
+	CompletionTime DurationStats         `json:"completion_time"`
+	ByDifficulty   []CategoryPerformance `json:"by_difficulty"`
+	ByQuestionType []CategoryPerformance `json:"by_question_type"`
+}
+
+// CohortComparisonResponse is a side-by-side comparison of cohorts
+type CohortComparisonResponse struct {
+	GroupBy     CohortGroupBy `json:"group_by"`
+	QuizType    QuizType      `json:"quiz_type,omitempty"`
+	DateFrom    string        `json:"date_from,omitempty"`
+	DateTo      string        `json:"date_to,omitempty"`
+	Cohorts     []CohortStats `json:"cohorts"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Cached      bool          `json:"cached"`
+}
+
+// AbandonmentStatsRequest selects the sessions to analyse abandonment of, by start date
+type AbandonmentStatsRequest struct {
+	QuizType QuizType `form:"quiz_type"`
+	DateFrom string   `form:"date_from"` // YYYY-MM-DD
+	DateTo   string   `form:"date_to"`   // YYYY-MM-DD, inclusive
+}
+
+// AbandonmentReasonStats is how sessions abandoned for one reason had progressed
+type AbandonmentReasonStats struct {
+	Reason             AbandonReason `json:"reason"`
+	Sessions           int           `json:"sessions"`
+	Share              float64       `json:"share"`                // Percentage of the quiz type's abandoned sessions
+	AvgAnsweredPercent float64       `json:"avg_answered_percent"` // Questions answered before leaving
+	AvgStopQuestion    float64       `json:"avg_stop_question"`    // One-based question the student was on
+}
+
+// AbandonmentStats is how one quiz type's sessions ended
+type AbandonmentStats struct {
+	QuizType        QuizType                 `json:"quiz_type"`
+	Started         int                      `json:"started"`
+	Submitted       int                      `json:"submitted"` // By the student, or on time running out while they were there
+	InProgress      int                      `json:"in_progress"`
+	Abandoned       int                      `json:"abandoned"`
+	AbandonmentRate float64                  `json:"abandonment_rate"` // Abandoned / finished sessions * 100
+	Reasons         []AbandonmentReasonStats `json:"reasons"`
+}
+
+// AbandonmentStatsResponse reports abandonment per quiz type, most abandoned first
+type AbandonmentStatsResponse struct {
+	QuizType    QuizType           `json:"quiz_type,omitempty"`
+	DateFrom    string             `json:"date_from,omitempty"`
+	DateTo      string             `json:"date_to,omitempty"`
+	QuizTypes   []AbandonmentStats `json:"quiz_types"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// ItemResponseRow is one finished attempt's answer to a question with the attempt's total score,
+// as aggregated from detailed results
+type ItemResponseRow struct {
+	ScorePercentage float64     `bson:"score_percentage"`
+	IsCorrect       bool        `bson:"is_correct"`
+	IsSkipped       bool        `bson:"is_skipped"`
+	PendingGrade    bool        `bson:"pending_grade"`
+	Points          int         `bson:"points"`
+	PointsEarned    int         `bson:"points_earned"`
+	UserAnswer      interface{} `bson:"user_answer"`
+	TimeSpent       int64       `bson:"time_spent"`
+}
+
+// QuestionAnalyticsRequest limits item analysis to attempts submitted in a date range
+type QuestionAnalyticsRequest struct {
+	DateFrom string `form:"date_from"` // YYYY-MM-DD
+	DateTo   string `form:"date_to"`   // YYYY-MM-DD, inclusive
+}
+
+// Flags raised by item analysis on questions worth revising or retiring
+const (
+	ItemFlagTooHard                 = "too_hard"                  // Difficulty index below 0.2
+	ItemFlagTooEasy                 = "too_easy"                  // Difficulty index above 0.9
+	ItemFlagPoorDiscrimination      = "poor_discrimination"       // Discrimination index below 0.2
+	ItemFlagNegativeDiscrimination  = "negative_discrimination"   // Weaker students do better than stronger ones
+	ItemFlagNonFunctionalDistractor = "non_functional_distractor" // A wrong option almost nobody chooses
+)
+
+// DistractorStats is how often one option of a choice question was chosen, overall and by the
+// upper and lower scoring groups
+type DistractorStats struct {
+	OptionID    string  `json:"option_id"`
+	Text        string  `json:"text"`
+	IsCorrect   bool    `json:"is_correct"`
+	Chosen      int     `json:"chosen"`
+	ChosenShare float64 `json:"chosen_share"` // Of answered attempts
+	UpperChosen int     `json:"upper_chosen"`
+	LowerChosen int     `json:"lower_chosen"`
+
+	// Upper minus lower group share choosing the option; a working distractor draws the lower group
+	Discrimination float64 `json:"discrimination"`
+	Functional     bool    `json:"functional"` // Wrong options chosen by at least 5% of answered attempts
+}
+
+// QuestionAnalytics is a classical item analysis of one question over finished attempts. The
+// indices are left out until there are enough attempts to compute them.
+type QuestionAnalytics struct {
+	QuestionID primitive.ObjectID `json:"question_id"`
+	Title      string             `json:"title"`
+	Type       QuestionType       `json:"type"`
+	Difficulty DifficultyLevel    `json:"difficulty"`
+	IsActive   bool               `json:"is_active"`
+
+	Attempts      int `json:"attempts"` // Excluding essays awaiting a grade
+	Answered      int `json:"answered"`
+	Skipped       int `json:"skipped"`
+	Correct       int `json:"correct"`
+	PendingGrades int `json:"pending_grades"`
+
+	// Mean share of the question's points earned; lower is harder
+	DifficultyIndex *float64 `json:"difficulty_index,omitempty"`
+	// Difficulty index of the top 27% of attempts by total score minus that of the bottom 27%
+	DiscriminationIndex *float64 `json:"discrimination_index,omitempty"`
+	GroupSize           int      `json:"group_size"`
+
+	// Share of wrong options chosen by at least 5% of answered attempts; choice questions only
+	DistractorEffectiveness *float64          `json:"distractor_effectiveness,omitempty"`
+	Distractors             []DistractorStats `json:"distractors,omitempty"`
+
+	AverageTimeSeconds float64 `json:"average_time_seconds"` // Over answered attempts
+
+	Flags    []string   `json:"flags"`
+	DateFrom *time.Time `json:"date_from,omitempty"`
+	DateTo   *time.Time `json:"date_to,omitempty"` // Exclusive
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyScope grants an API key access to a group of read-only endpoints
+type APIKeyScope string
+
+const (
+	APIKeyScopeQuestionsRead APIKeyScope = "questions:read" // Question bank listing and export
+	APIKeyScopeResultsRead   APIKeyScope = "results:read"   // Quiz results and exam reporting
+	APIKeyScopeContentRead   APIKeyScope = "content:read"   // Feeds of published learning content
+)
+
+// APIKeyStatus represents the lifecycle of an API key
+type APIKeyStatus string
+
+const (
+	APIKeyActive  APIKeyStatus = "active"
+	APIKeyRevoked APIKeyStatus = "revoked"
+	APIKeyExpired APIKeyStatus = "expired" // Active past its expiry; derived, never stored
+)
+
+// DefaultAPIKeyRateLimit is the requests-per-minute allowance when none is set
+const DefaultAPIKeyRateLimit = 60
+
+// APIKey authenticates a machine integration through the X-API-Key header.
+// Only a hash of the key is stored; the key itself is shown once when created.
+type APIKey struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Prefix      string             `json:"prefix" bson:"prefix"` // Leading characters of the key, to tell keys apart
+	KeyHash     string             `json:"-" bson:"key_hash"`
+	Scopes      []APIKeyScope      `json:"scopes" bson:"scopes"`
+	Status      APIKeyStatus       `json:"status" bson:"status"`
+
+	RateLimitPerMinute int        `json:"rate_limit_per_minute" bson:"rate_limit_per_minute"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+
+	// Usage tracking
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty" bson:"last_used_ip,omitempty"`
+	UsageCount int64      `json:"usage_count" bson:"usage_count"`
+
+	// Lifecycle
+	CreatedBy      primitive.ObjectID  `json:"created_by" bson:"created_by"`
+	CreatedByEmail string              `json:"created_by_email" bson:"created_by_email"`
+	RevokedAt      *time.Time          `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedBy      *primitive.ObjectID `json:"revoked_by,omitempty" bson:"revoked_by,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// IsUsable reports whether the key can still authenticate requests
+func (k *APIKey) IsUsable() bool {
+	if k.Status != APIKeyActive {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
+
+// HasScope reports whether the key was granted the scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Request/Response models for API
+
+// CreateAPIKeyRequest represents the request to issue a new API key
+type CreateAPIKeyRequest struct {
+	Name               string        `json:"name" binding:"required,max=100"`
+	Description        string        `json:"description,omitempty" binding:"max=500"`
+	Scopes             []APIKeyScope `json:"scopes" binding:"required,min=1,dive,oneof=questions:read results:read content:read"`
+	RateLimitPerMinute int           `json:"rate_limit_per_minute,omitempty" binding:"omitempty,min=1,max=10000"`
+	ExpiresInDays      int           `json:"expires_in_days,omitempty" binding:"omitempty,min=1,max=3650"`
+}
+
+// CreateAPIKeyResponse returns the new key; the plaintext key is never shown again
+type CreateAPIKeyResponse struct {
+	APIKey  *APIKey `json:"api_key"`
+	Key     string  `json:"key"`
+	Message string  `json:"message"`
+}
+
+// UpdateAPIKeyRequest represents the request to change an API key's settings
+type UpdateAPIKeyRequest struct {
+	Name               *string       `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description        *string       `json:"description,omitempty" binding:"omitempty,max=500"`
+	Scopes             []APIKeyScope `json:"scopes,omitempty" binding:"omitempty,min=1,dive,oneof=questions:read results:read content:read"`
+	RateLimitPerMinute *int          `json:"rate_limit_per_minute,omitempty" binding:"omitempty,min=1,max=10000"`
+}
+
+// ListAPIKeysRequest represents the request to list API keys
+type ListAPIKeysRequest struct {
+	Page   int          `form:"page,default=1" binding:"min=1"`
+	Limit  int          `form:"limit,default=20" binding:"min=1,max=100"`
+	Status APIKeyStatus `form:"status" binding:"omitempty,oneof=active revoked expired"`
+}
+
+// ListAPIKeysResponse represents a page of API keys
+type ListAPIKeysResponse struct {
+	APIKeys    []APIKey `json:"api_keys"`
+	Total      int64    `json:"total"`
+	Page       int      `json:"page"`
+	Limit      int      `json:"limit"`
+	TotalPages int      `json:"total_pages"`
+}
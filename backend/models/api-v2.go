@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Envelope is the body of every /api/v2 answer: the resource or list as data, with paging as meta,
+// or what went wrong as error
+type Envelope struct {
+	Data  any            `json:"data,omitempty"`
+	Meta  *PageMeta      `json:"meta,omitempty"`
+	Error *ErrorResponse `json:"error,omitempty"`
+}
+
+// PageRequest is how every /api/v2 list is paged. Lists that support it continue after a cursor
+// instead of paging by number.
+type PageRequest struct {
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+// PageMeta describes the page of a list. Pages fetched by cursor leave total and total_pages unset.
+type PageMeta struct {
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"` // Pass as cursor to get the next page; empty on the last page
+
+	UnreadCount *int64 `json:"unread_count,omitempty"` // Notification lists only
+}
+
+// NewPageMeta describes page of a list of total items, limit to a page
+func NewPageMeta(page, limit int, total int64) *PageMeta {
+	meta := &PageMeta{Page: page, Limit: limit, Total: total}
+	if limit > 0 {
+		meta.TotalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return meta
+}
+
+// V2ListModulesRequest filters the modules listed by /api/v2; learners only ever see published ones
+type V2ListModulesRequest struct {
+	PageRequest
+	Search    string `form:"search"`
+	Published *bool  `form:"published"` // Admin listing only
+}
+
+// V2ListNotificationsRequest filters the signed-in user's notifications
+type V2ListNotificationsRequest struct {
+	PageRequest
+	UnreadOnly bool `form:"unread_only"`
+}
+
+// V2ListUsersRequest filters and sorts the accounts admins list
+type V2ListUsersRequest struct {
+	PageRequest
+	Search    string     `form:"search"`
+	UserType  UserType   `form:"user_type"`
+	Status    UserStatus `form:"status"`
+	SortBy    string     `form:"sort_by,default=created_at" binding:"oneof=created_at full_name email last_login status user_type"`
+	SortOrder string     `form:"sort_order,default=desc" binding:"oneof=asc desc"`
+}
+
+// V2ListActivityLogsRequest filters the activity log; dates are RFC 3339
+type V2ListActivityLogsRequest struct {
+	PageRequest
+	Type       ActivityType `form:"type"`
+	EntityType string       `form:"entity_type"`
+	UserID     string       `form:"user_id"`
+	DateFrom   *time.Time   `form:"date_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	DateTo     *time.Time   `form:"date_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Success    *bool        `form:"success"`
+}
+
+// V2ListJobRunsRequest pages through one scheduled job's run history
+type V2ListJobRunsRequest struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
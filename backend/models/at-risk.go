@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RiskSignalType is a reason a student was flagged by the early-warning analysis
+type RiskSignalType string
+
+const (
+	RiskDecliningScores  RiskSignalType = "declining_scores"  // Recent scores are well below earlier ones
+	RiskLowActivity      RiskSignalType = "low_activity"      // No attempts for a while
+	RiskRepeatedTimeouts RiskSignalType = "repeated_timeouts" // Keeps running out of time
+)
+
+// RiskSignal is one contributing signal with how strongly it applies
+type RiskSignal struct {
+	Type     RiskSignalType `json:"type" bson:"type"`
+	Detail   string         `json:"detail" bson:"detail"`
+	Value    float64        `json:"value" bson:"value"`       // Score drop in points, days inactive or timed-out attempts
+	Severity float64        `json:"severity" bson:"severity"` // 0-1; 0.5 is exactly at the configured threshold
+}
+
+// AtRiskStudent is a student flagged by the latest analysis
+type AtRiskStudent struct {
+	UserID   primitive.ObjectID `json:"user_id" bson:"user_id"`
+	FullName string             `json:"full_name" bson:"full_name"`
+	Email    string             `json:"email" bson:"email"`
+	NIM      string             `json:"nim" bson:"nim"`
+	Faculty  string             `json:"faculty,omitempty" bson:"faculty,omitempty"`
+	Major    string             `json:"major,omitempty" bson:"major,omitempty"`
+
+	// Combined severity of the signals, 0-100; higher is more at risk
+	RiskScore float64      `json:"risk_score" bson:"risk_score"`
+	Signals   []RiskSignal `json:"signals" bson:"signals"`
+
+	Attempts      int        `json:"attempts" bson:"attempts"` // Finished attempts in the lookback window
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty" bson:"last_attempt_at,omitempty"`
+
+	FlaggedAt  time.Time  `json:"flagged_at" bson:"flagged_at"` // First analysis in the current run of flags
+	AnalyzedAt time.Time  `json:"analyzed_at" bson:"analyzed_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty" bson:"notified_at,omitempty"` // Last outreach notification
+}
+
+// StudentResultPoint is one finished attempt considered by the analysis
+type StudentResultPoint struct {
+	ScorePercentage  float64    `bson:"score_percentage"`
+	CompletionStatus QuizStatus `bson:"completion_status"`
+	SubmittedAt      time.Time  `bson:"submitted_at"`
+}
+
+// StudentActivityRow is a student with their recent results, oldest first
+type StudentActivityRow struct {
+	UserID        primitive.ObjectID   `bson:"_id"`
+	FullName      string               `bson:"full_name"`
+	Email         string               `bson:"email"`
+	NIM           string               `bson:"mahasiswa_id"`
+	Faculty       string               `bson:"faculty"`
+	Major         string               `bson:"major"`
+	CreatedAt     time.Time            `bson:"created_at"`
+	Results       []StudentResultPoint `bson:"results"`
+	LastAttemptAt *time.Time           `bson:"last_attempt_at"` // Most recent attempt ever, even outside the window
+}
+
+// Request/Response models for at-risk analysis
+
+// ListAtRiskStudentsRequest represents query parameters for the ranked list
+type ListAtRiskStudentsRequest struct {
+	Page    int            `form:"page,default=1" binding:"min=1"`
+	Limit   int            `form:"limit,default=20" binding:"min=1,max=100"`
+	Signal  RiskSignalType `form:"signal" binding:"omitempty,oneof=declining_scores low_activity repeated_timeouts"`
+	Faculty string         `form:"faculty"`
+	Major   string         `form:"major"`
+}
+
+// ListAtRiskStudentsResponse is a page of flagged students, highest risk first
+type ListAtRiskStudentsResponse struct {
+	Students     []AtRiskStudent       `json:"students"`
+	Total        int64                 `json:"total"`
+	Page         int                   `json:"page"`
+	Limit        int                   `json:"limit"`
+	TotalPages   int                   `json:"total_pages"`
+	LastAnalysis *AtRiskAnalysisResult `json:"last_analysis,omitempty"` // Latest run on this instance
+}
+
+// AtRiskAnalysisResult summarises one analysis run
+type AtRiskAnalysisResult struct {
+	Analyzed     int       `json:"analyzed"` // Students considered
+	Flagged      int       `json:"flagged"`
+	NewlyFlagged int       `json:"newly_flagged"`
+	Cleared      int64     `json:"cleared"` // Previously flagged students no longer at risk
+	Notified     int       `json:"notified"`
+	AnalyzedAt   time.Time `json:"analyzed_at"`
+	Duration     string    `json:"duration"`
+}
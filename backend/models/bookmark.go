@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bookmark represents a question a student saved for later review
+type Bookmark struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
+
+	// Snapshot of the question so the list can be rendered and filtered without a lookup
+	QuestionTitle string          `json:"question_title" bson:"question_title"`
+	QuestionType  QuestionType    `json:"question_type" bson:"question_type"`
+	Difficulty    DifficultyLevel `json:"difficulty" bson:"difficulty"`
+
+	// Quiz result the bookmark was created from, if any
+	ResultID *primitive.ObjectID `json:"result_id,omitempty" bson:"result_id,omitempty"`
+	Note     string              `json:"note,omitempty" bson:"note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Request/Response models for API
+
+// CreateBookmarkRequest represents the request to bookmark a question
+type CreateBookmarkRequest struct {
+	QuestionID string `json:"question_id" binding:"required"`
+	ResultID   string `json:"result_id,omitempty"`
+	Note       string `json:"note,omitempty" binding:"max=500"`
+}
+
+// ListBookmarksRequest represents the filters for listing bookmarks
+type ListBookmarksRequest struct {
+	Page       int             `form:"page,default=1" binding:"min=1"`
+	Limit      int             `form:"limit,default=20" binding:"min=1,max=100"`
+	Search     string          `form:"search"`
+	Type       QuestionType    `form:"type"`
+	Difficulty DifficultyLevel `form:"difficulty"`
+}
+
+// ListBookmarksResponse represents the response for listing bookmarks
+type ListBookmarksResponse struct {
+	Bookmarks  []Bookmark `json:"bookmarks"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	TotalPages int        `json:"total_pages"`
+}
+
+// BookmarkPracticeRequest represents the request to start a practice session from bookmarks
+type BookmarkPracticeRequest struct {
+	Type       QuestionType    `json:"type,omitempty"`
+	Difficulty DifficultyLevel `json:"difficulty,omitempty"`
+	Limit      int             `json:"limit,omitempty" binding:"omitempty,min=1,max=100"`
+}
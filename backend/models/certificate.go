@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Certificate is the registry entry of a certificate issued for a passing mock test result. The PDF is
+// rendered from it on every download, and anyone holding the code can check it against the registry.
+type Certificate struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Code     string             `json:"code" bson:"code"` // Printed on the certificate and in its verification link
+	ResultID primitive.ObjectID `json:"result_id" bson:"result_id"`
+	UserID   primitive.ObjectID `json:"user_id" bson:"user_id"`
+
+	HolderName      string    `json:"holder_name" bson:"holder_name"`
+	QuizType        QuizType  `json:"quiz_type" bson:"quiz_type"`
+	ScorePercentage float64   `json:"score_percentage" bson:"score_percentage"`
+	PassingScore    float64   `json:"passing_score" bson:"passing_score"` // Threshold in force when it was issued
+	CompletedAt     time.Time `json:"completed_at" bson:"completed_at"`
+	IssuedAt        time.Time `json:"issued_at" bson:"issued_at"`
+
+	// HMAC-SHA256 of the fields above, so entries altered in the database fail verification
+	Signature string `json:"-" bson:"signature"`
+}
+
+// IssuedCertificate is a rendered certificate ready for download
+type IssuedCertificate struct {
+	Certificate *Certificate
+	VerifyURL   string
+	Content     []byte
+}
+
+// CertificateVerification is the public answer for a certificate code. Only what is printed on the
+// certificate is shown.
+type CertificateVerification struct {
+	Valid           bool      `json:"valid"` // False when the registry entry no longer matches its signature
+	Code            string    `json:"code"`
+	HolderName      string    `json:"holder_name"`
+	QuizType        QuizType  `json:"quiz_type"`
+	ScorePercentage float64   `json:"score_percentage"`
+	PassingScore    float64   `json:"passing_score"`
+	CompletedAt     time.Time `json:"completed_at"`
+	IssuedAt        time.Time `json:"issued_at"`
+}
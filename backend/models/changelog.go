@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ChangelogAudience is who a release note is shown to
+type ChangelogAudience string
+
+const (
+	ChangelogForStudents ChangelogAudience = "student" // Mahasiswa, external users and signed-out visitors
+	ChangelogForAdmins   ChangelogAudience = "admin"
+)
+
+// ChangelogEntry is the release notes of one deployed version, shown by clients as "what's new".
+// Entries are drafts until published, and only published entries are served by GET /changelog.
+type ChangelogEntry struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Version   string              `json:"version" bson:"version"`
+	Title     string              `json:"title" bson:"title"`
+	Body      string              `json:"body" bson:"body"` // Markdown
+	Audiences []ChangelogAudience `json:"audiences" bson:"audiences"`
+
+	Published   bool       `json:"published" bson:"published"`
+	PublishedAt *time.Time `json:"published_at,omitempty" bson:"published_at,omitempty"` // Set the first time the entry is published
+
+	// Who wrote it
+	CreatedBy      primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedByEmail string             `json:"created_by_email" bson:"created_by_email"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// CreateChangelogEntryRequest represents the request to write release notes for a version
+type CreateChangelogEntryRequest struct {
+	Version   string              `json:"version" binding:"required,max=32"`
+	Title     string              `json:"title" binding:"required,max=200"`
+	Body      string              `json:"body" binding:"required,max=20000"`
+	Audiences []ChangelogAudience `json:"audiences" binding:"required,min=1,dive,oneof=student admin"`
+	Publish   bool                `json:"publish"`
+}
+
+// UpdateChangelogEntryRequest represents the request to edit, publish or unpublish release notes
+type UpdateChangelogEntryRequest struct {
+	Version   *string             `json:"version,omitempty" binding:"omitempty,min=1,max=32"`
+	Title     *string             `json:"title,omitempty" binding:"omitempty,min=1,max=200"`
+	Body      *string             `json:"body,omitempty" binding:"omitempty,min=1,max=20000"`
+	Audiences []ChangelogAudience `json:"audiences,omitempty" binding:"omitempty,min=1,dive,oneof=student admin"`
+	Published *bool               `json:"published,omitempty"`
+}
+
+// ListChangelogRequest represents the request to list release notes. Admin listings include
+// drafts; the public listing only returns published entries for the caller's audience.
+type ListChangelogRequest struct {
+	Page     int               `form:"page,default=1" binding:"min=1"`
+	Limit    int               `form:"limit,default=20" binding:"min=1,max=100"`
+	Audience ChangelogAudience `form:"audience" binding:"omitempty,oneof=student admin"`
+	// Only entries published after this time, so clients can show what's new since they last looked
+	Since *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ListChangelogResponse represents a page of release notes, newest first
+type ListChangelogResponse struct {
+	Entries       []ChangelogEntry `json:"entries"`
+	Total         int64            `json:"total"`
+	Page          int              `json:"page"`
+	Limit         int              `json:"limit"`
+	TotalPages    int              `json:"total_pages"`
+	LatestVersion string           `json:"latest_version,omitempty"` // Newest version the audience can see, regardless of since
+}
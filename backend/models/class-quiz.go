@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClassQuizStatus represents whether students can still join a class quiz
+type ClassQuizStatus string
+
+const (
+	ClassQuizOpen   ClassQuizStatus = "open"
+	ClassQuizClosed ClassQuizStatus = "closed" // Closed by the instructor, or open past its join window when listed
+)
+
+// ClassQuiz is an ad-hoc quick quiz an instructor runs in class. Students join with its code,
+// usually by scanning a QR code of the join URL shown on the projector, and each get a session
+// with the same questions in their own order.
+type ClassQuiz struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Title       string               `json:"title" bson:"title"`
+	JoinCode    string               `json:"join_code" bson:"join_code"`
+	Status      ClassQuizStatus      `json:"status" bson:"status"`
+	QuestionIDs []primitive.ObjectID `json:"question_ids" bson:"question_ids"`
+
+	TimeLimitMinutes int       `json:"time_limit_minutes" bson:"time_limit_minutes"` // Per student, from when they join
+	JoinClosesAt     time.Time `json:"join_closes_at" bson:"join_closes_at"`
+
+	// Who runs it
+	CreatedBy      primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedByEmail string             `json:"created_by_email" bson:"created_by_email"`
+
+	ClosedAt *time.Time `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Frontend join page for this quiz, to show as a QR code; filled in for responses
+	JoinURL string `json:"join_url,omitempty" bson:"-"`
+}
+
+// IsJoinable reports whether students can still join
+func (q *ClassQuiz) IsJoinable(now time.Time) bool {
+	return q.Status == ClassQuizOpen && now.Before(q.JoinClosesAt)
+}
+
+// Request/Response models for API
+
+// CreateClassQuizRequest represents the request to start a class quiz
+type CreateClassQuizRequest struct {
+	Title             string   `json:"title" binding:"required,max=200"`
+	QuestionIDs       []string `json:"question_ids" binding:"required,min=1,max=50"`
+	TimeLimitMinutes  int      `json:"time_limit_minutes" binding:"required,min=1,max=120"`
+	JoinWindowMinutes int      `json:"join_window_minutes,omitempty" binding:"omitempty,min=1,max=240"` // Defaults to 30
+}
+
+// JoinClassQuizRequest represents a student joining a class quiz
+type JoinClassQuizRequest struct {
+	DisplayName string `json:"display_name" binding:"required,min=1,max=40"` // Shown on the results board
+
+	// Filled in by the controller from the request
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+	IsGuest   bool   `json:"-"`
+}
+
+// ListClassQuizzesRequest represents the request to list class quizzes
+type ListClassQuizzesRequest struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// ListClassQuizzesResponse represents a page of class quizzes
+type ListClassQuizzesResponse struct {
+	Quizzes    []ClassQuiz `json:"quizzes"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// ClassQuizBoardEntry is one student on the results board. Scores are set once they submit.
+type ClassQuizBoardEntry struct {
+	Rank            int        `json:"rank,omitempty"` // Among submitted students
+	DisplayName     string     `json:"display_name"`
+	Status          QuizStatus `json:"status"`
+	AnsweredCount   int        `json:"answered_count"`
+	TotalQuestions  int        `json:"total_questions"`
+	FinalScore      *int       `json:"final_score,omitempty"`
+	ScorePercentage *float64   `json:"score_percentage,omitempty"`
+	TimeUsedSeconds *int64     `json:"time_used_seconds,omitempty"`
+}
+
+// ClassQuizQuestionStat is how the class did on one question, over submitted students
+type ClassQuizQuestionStat struct {
+	QuestionID  primitive.ObjectID `json:"question_id"`
+	Title       string             `json:"title"`
+	Answered    int                `json:"answered"`
+	Correct     int                `json:"correct"`
+	CorrectRate float64            `json:"correct_rate"` // Percentage of submitted students
+}
+
+// ClassQuizBoard is the live results board shown on the classroom projector
+type ClassQuizBoard struct {
+	Quiz       ClassQuiz               `json:"quiz"`
+	Joined     int                     `json:"joined"`
+	InProgress int                     `json:"in_progress"`
+	Submitted  int                     `json:"submitted"`
+	Entries    []ClassQuizBoardEntry   `json:"entries"` // Submitted by rank, then students still answering
+	Questions  []ClassQuizQuestionStat `json:"questions"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
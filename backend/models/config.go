@@ -8,29 +8,101 @@ type Config struct {
 	JWT      JWTConfig      `json:"jwt"`
 	OAuth    OAuthConfig    `json:"oauth"`
 	Email    EmailConfig    `json:"email"`
+
+	PasswordPolicy PasswordPolicyConfig `json:"password_policy"`
+	TTS            TTSConfig            `json:"tts"`
+	Captcha        CaptchaConfig        `json:"captcha"`
+	AtRisk         AtRiskConfig         `json:"at_risk"`
+	Webhooks       WebhookConfig        `json:"webhooks"`
+	Embed          EmbedConfig          `json:"embed"`
+	Certificates   CertificateConfig    `json:"certificates"`
+	Recalibration  RecalibrationConfig  `json:"recalibration"`
+	Storage        StorageConfig        `json:"storage"`
+	Log            LogConfig            `json:"log"`
+	Tracing        TracingConfig        `json:"tracing"`
+	RateLimit      RateLimitConfig      `json:"rate_limit"`
+	Cache          CacheConfig          `json:"cache"`
+	Scheduler      SchedulerConfig      `json:"scheduler"`
 }
 
 type ServerConfig struct {
-	Port            string        `json:"port" env:"PORT" env-default:"8080"`
+	Port            string        `json:"port" env:"PORT" env-default:"8080" validate:"required,numeric"`
 	Host            string        `json:"host" env:"HOST" env-default:"localhost"`
 	Environment     string        `json:"environment" env:"ENVIRONMENT" env-default:"development"`
 	AllowedOrigins  []string      `json:"allowed_origins" env:"ALLOWED_ORIGINS" env-default:"http://localhost:5173"`
-	ReadTimeout     time.Duration `json:"read_timeout" env:"READ_TIMEOUT" env-default:"30s"`
-	WriteTimeout    time.Duration `json:"write_timeout" env:"WRITE_TIMEOUT" env-default:"30s"`
-	ShutdownTimeout time.Duration `json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"10s"`
+	TrustedProxies  []string      `json:"trusted_proxies" env:"TRUSTED_PROXIES" validate:"dive,ip|cidr"` // Proxies whose X-Forwarded-For is believed; none when unset, so the client IP is the connection's
+	ReadTimeout     time.Duration `json:"read_timeout" env:"READ_TIMEOUT" env-default:"30s" validate:"gt=0"`
+	WriteTimeout    time.Duration `json:"write_timeout" env:"WRITE_TIMEOUT" env-default:"30s" validate:"gt=0"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"10s" validate:"gt=0"`
+	DrainDelay      time.Duration `json:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" env-default:"0s" validate:"gte=0"` // How long /ready fails before shutdown starts, so load balancers stop sending traffic first
+
+	SessionCleanupInterval  time.Duration `json:"session_cleanup_interval" env:"SESSION_CLEANUP_INTERVAL" env-default:"1m" validate:"gte=0"`   // How often expired quiz sessions are auto-submitted; 0 disables
+	SettingsRefreshInterval time.Duration `json:"settings_refresh_interval" env:"SETTINGS_REFRESH_INTERVAL" env-default:"1m" validate:"gte=0"` // How often instances reload runtime settings saved on another instance; 0 disables
+}
+
+// LogConfig sets what the server logs and how
+type LogConfig struct {
+	Level  string `json:"level" env:"LOG_LEVEL" env-default:"info"`           // debug, info, warn or error
+	Format string `json:"format" env:"LOG_FORMAT" validate:"oneof=json text"` // json or text; json in production, text otherwise
+}
+
+// TracingConfig points traces at an OpenTelemetry collector; tracing is off without an endpoint
+type TracingConfig struct {
+	Endpoint    string        `json:"endpoint" env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"` // OTLP/HTTP traces URL; OTEL_EXPORTER_OTLP_ENDPOINT + /v1/traces when unset
+	Headers     string        `json:"-" env:"OTEL_EXPORTER_OTLP_HEADERS"`                // key=value pairs, comma separated, such as the collector's API key
+	ServiceName string        `json:"service_name" env:"OTEL_SERVICE_NAME" env-default:"z0nata-backend"`
+	Environment string        `json:"environment"`
+	SampleRatio float64       `json:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG" env-default:"1" validate:"gte=0,lte=1"` // Share of new traces kept; callers' decisions are followed
+	Timeout     time.Duration `json:"timeout" env:"OTEL_EXPORTER_OTLP_TIMEOUT" env-default:"10000" validate:"gt=0"`      // Milliseconds, as OpenTelemetry specifies
+}
+
+// RateLimitConfig limits how often a client may call sensitive or expensive public routes
+type RateLimitConfig struct {
+	Store        string        `json:"store" env:"RATE_LIMIT_STORE" env-default:"memory" validate:"oneof=memory redis"` // memory or redis; redis shares counters between instances
+	RedisURL     string        `json:"-" env:"RATE_LIMIT_REDIS_URL" validate:"required_if=Store redis"`                 // redis://[:password@]host:port[/db], or rediss:// over TLS
+	RedisTimeout time.Duration `json:"redis_timeout" env:"RATE_LIMIT_REDIS_TIMEOUT" env-default:"500ms" validate:"gt=0"`
+
+	Policies map[RateLimitPolicyName]RateLimitPolicy `json:"policies" validate:"dive"`
+}
+
+// RateLimitPolicyName names the routes sharing a limit
+type RateLimitPolicyName string
+
+const (
+	RateLimitAuth            RateLimitPolicyName = "auth"             // Login, registration, token refresh and account recovery
+	RateLimitOAuth           RateLimitPolicyName = "oauth"            // OAuth callbacks
+	RateLimitRandomQuestions RateLimitPolicyName = "random_questions" // Public random question draws
+)
+
+// RateLimitPolicy allows Limit requests per client in each Window; a zero limit turns it off
+type RateLimitPolicy struct {
+	Limit  int           `json:"limit" validate:"min=0"`
+	Window time.Duration `json:"window" validate:"gt=0"`
+}
+
+// CacheConfig sets where hot reads are cached and for how long
+type CacheConfig struct {
+	Store        string        `json:"store" env:"CACHE_STORE" env-default:"memory" validate:"oneof=memory redis none"` // memory, redis or none; redis shares entries and invalidations between instances
+	RedisURL     string        `json:"-" env:"CACHE_REDIS_URL" validate:"required_if=Store redis"`                      // Defaults to RATE_LIMIT_REDIS_URL
+	RedisTimeout time.Duration `json:"redis_timeout" env:"CACHE_REDIS_TIMEOUT" env-default:"500ms" validate:"gt=0"`
+	TTL          time.Duration `json:"ttl" env:"CACHE_TTL" env-default:"1m" validate:"gt=0"` // Longest a cached read is served, should an invalidation be missed
 }
 
 type DatabaseConfig struct {
-	URI         string `json:"uri" env:"MONGO_URI" env-required:"true"`
-	Name        string `json:"name" env:"MONGO_DB_NAME" env-default:"quizapp"`
-	MaxPoolSize uint64 `json:"max_pool_size" env:"MONGO_MAX_POOL_SIZE" env-default:"100"`
+	URI         string `json:"uri" env:"MONGO_URI" env-required:"true" validate:"required"`
+	Name        string `json:"name" env:"MONGO_DB_NAME" env-default:"quizapp" validate:"required"`
+	MaxPoolSize uint64 `json:"max_pool_size" env:"MONGO_MAX_POOL_SIZE" env-default:"100" validate:"min=1"`
+	// EnsureIndexes builds missing indexes on startup; turn it off where cmd/ensure-indexes runs
+	// as a deploy step instead
+	EnsureIndexes bool `json:"ensure_indexes" env:"MONGO_ENSURE_INDEXES" env-default:"true"`
 }
 
 type JWTConfig struct {
-	SecretKey            string        `json:"secret_key" env:"JWT_SECRET_KEY" env-required:"true"`
-	AccessTokenDuration  time.Duration `json:"access_token_duration" env:"JWT_ACCESS_DURATION" env-default:"15m"`
-	RefreshTokenDuration time.Duration `json:"refresh_token_duration" env:"JWT_REFRESH_DURATION" env-default:"7d"`
-	RememberMeDuration   time.Duration `json:"remember_me_duration" env:"JWT_REMEMBER_DURATION" env-default:"30d"`
+	SecretKey            string        `json:"secret_key" env:"JWT_SECRET_KEY" env-required:"true" validate:"required"`
+	AccessTokenDuration  time.Duration `json:"access_token_duration" env:"JWT_ACCESS_DURATION" env-default:"15m" validate:"gt=0"`
+	RefreshTokenDuration time.Duration `json:"refresh_token_duration" env:"JWT_REFRESH_DURATION" env-default:"7d" validate:"gt=0"`
+	RememberMeDuration   time.Duration `json:"remember_me_duration" env:"JWT_REMEMBER_DURATION" env-default:"30d" validate:"gt=0"`
+	KeyRefreshInterval   time.Duration `json:"key_refresh_interval" env:"JWT_KEY_REFRESH_INTERVAL" env-default:"1m" validate:"gte=0"` // How often instances reload signing keys after a rotation
 }
 
 type OAuthConfig struct {
@@ -47,11 +119,108 @@ type OAuthProvider struct {
 	Scopes       []string `json:"scopes"`
 }
 
+// TTSConfig selects the text-to-speech provider used to pre-generate question audio
+type TTSConfig struct {
+	Provider     string        `json:"provider" env:"TTS_PROVIDER" env-default:""` // "google", "http", or empty to disable
+	Endpoint     string        `json:"endpoint" env:"TTS_ENDPOINT"`                // Synthesis URL for the http provider
+	APIKey       string        `json:"-" env:"TTS_API_KEY"`
+	Voice        string        `json:"voice" env:"TTS_VOICE"`
+	LanguageCode string        `json:"language_code" env:"TTS_LANGUAGE_CODE" env-default:"id-ID"`
+	Timeout      time.Duration `json:"timeout" env:"TTS_TIMEOUT" env-default:"30s" validate:"gt=0"`
+	AutoGenerate bool          `json:"auto_generate" env:"TTS_AUTO_GENERATE" env-default:"false"` // Generate audio whenever a question is saved
+}
+
+// StorageConfig selects where uploaded files such as module attachments are kept
+type StorageConfig struct {
+	Provider    string        `json:"provider" env:"STORAGE_PROVIDER" env-default:"local"` // "local" or "s3"
+	LocalDir    string        `json:"local_dir" env:"STORAGE_LOCAL_DIR" env-default:"uploads"`
+	S3Bucket    string        `json:"s3_bucket" env:"STORAGE_S3_BUCKET"`
+	S3Region    string        `json:"s3_region" env:"STORAGE_S3_REGION" env-default:"us-east-1"`
+	S3Endpoint  string        `json:"s3_endpoint" env:"STORAGE_S3_ENDPOINT"` // For S3-compatible services such as MinIO; defaults to AWS
+	S3AccessKey string        `json:"-" env:"STORAGE_S3_ACCESS_KEY"`
+	S3SecretKey string        `json:"-" env:"STORAGE_S3_SECRET_KEY"`
+	Timeout     time.Duration `json:"timeout" env:"STORAGE_TIMEOUT" env-default:"5m" validate:"gt=0"`
+
+	MaxAttachmentSize int64 `json:"max_attachment_size" env:"ATTACHMENT_MAX_SIZE_MB" env-default:"50" validate:"gt=0"` // In bytes; the env var is in megabytes
+}
+
+// CaptchaConfig selects the bot check required on registration, login and password reset requests
+type CaptchaConfig struct {
+	Provider  string        `json:"provider" env:"CAPTCHA_PROVIDER" env-default:""` // "recaptcha", "turnstile", or empty to disable
+	SiteKey   string        `json:"site_key" env:"CAPTCHA_SITE_KEY"`                // Public key the frontend renders the widget with
+	SecretKey string        `json:"-" env:"CAPTCHA_SECRET_KEY"`
+	MinScore  float64       `json:"min_score" env:"CAPTCHA_MIN_SCORE" env-default:"0.5" validate:"gte=0,lte=1"` // reCAPTCHA v3 only
+	Timeout   time.Duration `json:"timeout" env:"CAPTCHA_TIMEOUT" env-default:"10s" validate:"gt=0"`
+}
+
+// AtRiskConfig tunes the scheduled early-warning analysis of student results
+type AtRiskConfig struct {
+	Interval         time.Duration `json:"interval" env:"AT_RISK_INTERVAL" env-default:"24h" validate:"gte=0"`                 // How often the analysis runs; 0 disables the schedule
+	LookbackDays     int           `json:"lookback_days" env:"AT_RISK_LOOKBACK_DAYS" env-default:"60" validate:"min=1"`        // Results older than this are ignored
+	InactiveDays     int           `json:"inactive_days" env:"AT_RISK_INACTIVE_DAYS" env-default:"14" validate:"min=1"`        // Days without an attempt before low activity is flagged
+	ScoreDrop        float64       `json:"score_drop" env:"AT_RISK_SCORE_DROP" env-default:"15" validate:"gte=0"`              // Percentage points recent scores must fall by
+	TimeoutThreshold int           `json:"timeout_threshold" env:"AT_RISK_TIMEOUT_THRESHOLD" env-default:"3" validate:"min=1"` // Timed-out attempts before repeated timeouts are flagged
+	NotifyCooldown   time.Duration `json:"notify_cooldown" env:"AT_RISK_NOTIFY_COOLDOWN" env-default:"336h" validate:"gte=0"`  // Minimum time between outreach notifications to a student
+}
+
+// RecalibrationConfig tunes the scheduled job that checks question difficulty labels against observed correct rates
+type RecalibrationConfig struct {
+	Interval     time.Duration `json:"interval" env:"RECALIBRATION_INTERVAL" env-default:"168h" validate:"gte=0"`          // How often the job runs; 0 disables the schedule
+	Mode         string        `json:"mode" env:"RECALIBRATION_MODE" env-default:"suggest" validate:"oneof=auto suggest"`  // "auto" relabels questions, "suggest" queues changes for admin approval
+	LookbackDays int           `json:"lookback_days" env:"RECALIBRATION_LOOKBACK_DAYS" env-default:"180" validate:"min=1"` // Attempts older than this are ignored
+	MinAttempts  int           `json:"min_attempts" env:"RECALIBRATION_MIN_ATTEMPTS" env-default:"30" validate:"min=1"`    // Questions with fewer attempts are left alone
+	EasyRate     float64       `json:"easy_rate" env:"RECALIBRATION_EASY_RATE" env-default:"0.75" validate:"gt=0,lte=1"`   // Correct rate at or above which a question is easy
+	HardRate     float64       `json:"hard_rate" env:"RECALIBRATION_HARD_RATE" env-default:"0.4" validate:"gte=0,lte=1"`   // Correct rate below which a question is hard
+	Margin       float64       `json:"margin" env:"RECALIBRATION_MARGIN" env-default:"0.05" validate:"gte=0,lt=1"`         // How far past a boundary the rate must be before a label changes
+}
+
+// SchedulerConfig tunes the background job scheduler. Every instance runs a scheduler, but only the one
+// holding the leader lease runs jobs.
+type SchedulerConfig struct {
+	Enabled                  bool          `json:"enabled" env:"SCHEDULER_ENABLED" env-default:"true"`                                              // Off stops this instance from ever running jobs
+	LeaseTTL                 time.Duration `json:"lease_ttl" env:"SCHEDULER_LEASE_TTL" env-default:"30s" validate:"gte=0"`                          // How long a leader that stops renewing keeps the lease
+	Jitter                   float64       `json:"jitter" env:"SCHEDULER_JITTER" env-default:"0.1" validate:"gte=0,lte=1"`                          // Share of a job's interval its runs are randomly delayed by
+	HistoryRetention         time.Duration `json:"history_retention" env:"SCHEDULER_HISTORY_RETENTION" env-default:"720h" validate:"gt=0"`          // How long job run history is kept
+	UserStatsInterval        time.Duration `json:"user_stats_interval" env:"USER_STATS_REFRESH_INTERVAL" env-default:"1h" validate:"gte=0"`         // How often streaks and weekly progress are recomputed; 0 disables
+	ActivityLogRetentionDays int           `json:"activity_log_retention_days" env:"ACTIVITY_LOG_RETENTION_DAYS" env-default:"90" validate:"min=1"` // Activity logs older than this are deleted, until admins change it in the runtime settings
+	ActivityLogInterval      time.Duration `json:"activity_log_interval" env:"ACTIVITY_LOG_RETENTION_INTERVAL" env-default:"24h" validate:"gte=0"`  // How often old activity logs are deleted; 0 disables
+}
+
+// WebhookConfig tunes delivery of finalized exam results to department webhooks
+type WebhookConfig struct {
+	Timeout              time.Duration `json:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"10s" validate:"gt=0"`                               // Per delivery attempt
+	ReleaseCheckInterval time.Duration `json:"release_check_interval" env:"WEBHOOK_RELEASE_CHECK_INTERVAL" env-default:"1m" validate:"gte=0"` // How often released sittings are sent; 0 disables the check
+	AllowHTTP            bool          `json:"allow_http" env:"WEBHOOK_ALLOW_HTTP" env-default:"false"`                                       // Accept plain http URLs, e.g. for local testing
+}
+
+// EmbedConfig sets up the quiz widgets departmental sites embed in an iframe
+type EmbedConfig struct {
+	ScriptURL      string   `json:"script_url" env:"EMBED_SCRIPT_URL"`           // Frontend bundle the embed page loads; defaults to FRONTEND_URL + /embed/widget.js
+	FrameAncestors []string `json:"frame_ancestors" env:"EMBED_FRAME_ANCESTORS"` // Sources allowed to frame every widget, e.g. the university portal, besides each widget's own origins
+}
+
+// CertificateConfig sets when passing mock tests earn a certificate and how certificates are verified
+type CertificateConfig struct {
+	PassingScore float64 `json:"passing_score" env:"CERTIFICATE_PASSING_SCORE" env-default:"70" validate:"gte=0,lte=100"` // Score percentage a mock test needs, until admins change it in the runtime settings
+	SigningKey   string  `json:"-" env:"CERTIFICATE_SIGNING_KEY"`                                                         // Defaults to a key derived from the JWT secret
+	VerifyURL    string  `json:"verify_url" env:"CERTIFICATE_VERIFY_URL"`                                                 // Page the QR code opens, with the code appended; defaults to the API's verify endpoint
+}
+
 type EmailConfig struct {
 	SMTPHost     string `json:"smtp_host" env:"SMTP_HOST" env-default:"smtp.gmail.com"`
-	SMTPPort     int    `json:"smtp_port" env:"SMTP_PORT" env-default:"587"`
+	SMTPPort     int    `json:"smtp_port" env:"SMTP_PORT" env-default:"587" validate:"min=1,max=65535"`
 	SMTPUsername string `json:"smtp_username" env:"SMTP_USERNAME" env-required:"true"`
 	SMTPPassword string `json:"smtp_password" env:"SMTP_PASSWORD" env-required:"true"`
 	FromEmail    string `json:"from_email" env:"FROM_EMAIL" env-required:"true"`
 	FromName     string `json:"from_name" env:"FROM_NAME" env-default:"QuizApp"`
 }
+
+type PasswordPolicyConfig struct {
+	MinLength          int  `json:"min_length" env:"PASSWORD_MIN_LENGTH" env-default:"8" validate:"min=1"`
+	RequireUppercase   bool `json:"require_uppercase" env:"PASSWORD_REQUIRE_UPPERCASE" env-default:"true"`
+	RequireLowercase   bool `json:"require_lowercase" env:"PASSWORD_REQUIRE_LOWERCASE" env-default:"true"`
+	RequireDigit       bool `json:"require_digit" env:"PASSWORD_REQUIRE_DIGIT" env-default:"true"`
+	RequireSymbol      bool `json:"require_symbol" env:"PASSWORD_REQUIRE_SYMBOL" env-default:"false"`
+	BanCommonPasswords bool `json:"ban_common_passwords" env:"PASSWORD_BAN_COMMON" env-default:"true"`
+	HistorySize        int  `json:"history_size" env:"PASSWORD_HISTORY_SIZE" env-default:"5" validate:"min=0"` // Previous passwords that cannot be reused, 0 disables
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"strings"
+)
+
+// ContentBlockType is the kind of a block of module content
+type ContentBlockType string
+
+const (
+	BlockMarkdown ContentBlockType = "markdown" // Text in the question markdown subset
+	BlockImage    ContentBlockType = "image"
+	BlockVideo    ContentBlockType = "video" // YouTube or Vimeo video, stored as its embed URL
+	BlockCode     ContentBlockType = "code"
+	BlockCallout  ContentBlockType = "callout" // Highlighted markdown, styled by Variant
+)
+
+// CalloutVariant is how a callout block is styled
+type CalloutVariant string
+
+const (
+	CalloutInfo    CalloutVariant = "info"
+	CalloutTip     CalloutVariant = "tip"
+	CalloutWarning CalloutVariant = "warning"
+	CalloutDanger  CalloutVariant = "danger"
+)
+
+// MaxContentBlocks bounds the blocks in one module or submodule
+const MaxContentBlocks = 200
+
+// ContentBlock is one block of module content, shown in order. Which fields apply depends on Type:
+//
+//   - markdown: Text
+//   - image: URL, Alt and an optional Caption
+//   - video: URL and an optional Caption; Provider is set from the URL
+//   - code: Code and an optional Language for highlighting
+//   - callout: Text, Variant and an optional Title
+type ContentBlock struct {
+	Type     ContentBlockType `json:"type" bson:"type" binding:"required,oneof=markdown image video code callout"`
+	Text     string           `json:"text,omitempty" bson:"text,omitempty"`
+	URL      string           `json:"url,omitempty" bson:"url,omitempty"`
+	Alt      string           `json:"alt,omitempty" bson:"alt,omitempty" binding:"max=500"`
+	Caption  string           `json:"caption,omitempty" bson:"caption,omitempty" binding:"max=500"`
+	Provider string           `json:"provider,omitempty" bson:"provider,omitempty"`
+	Code     string           `json:"code,omitempty" bson:"code,omitempty"`
+	Language string           `json:"language,omitempty" bson:"language,omitempty" binding:"max=30"`
+	Variant  CalloutVariant   `json:"variant,omitempty" bson:"variant,omitempty"`
+	Title    string           `json:"title,omitempty" bson:"title,omitempty" binding:"max=200"`
+}
+
+// BlocksMarkdown writes blocks out as one markdown document, the form Content keeps them in for search,
+// study material and clients that only read Content
+func BlocksMarkdown(blocks []ContentBlock) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Type {
+		case BlockMarkdown:
+			parts = append(parts, block.Text)
+		case BlockImage:
+			image := "![" + block.Alt + "](" + block.URL + ")"
+			if block.Caption != "" {
+				image += "\n\n*" + block.Caption + "*"
+			}
+			parts = append(parts, image)
+		case BlockVideo:
+			label := block.Caption
+			if label == "" {
+				label = "Video"
+			}
+			parts = append(parts, "["+label+"]("+block.URL+")")
+		case BlockCode:
+			parts = append(parts, "```"+block.Language+"\n"+block.Code+"\n```")
+		case BlockCallout:
+			text := block.Text
+			if block.Title != "" {
+				text = "**" + block.Title + "**\n\n" + text
+			}
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// legacyBlocks turns content written before blocks into a single markdown block
+func legacyBlocks(content string, blocks []ContentBlock) []ContentBlock {
+	if len(blocks) > 0 || strings.TrimSpace(content) == "" {
+		return blocks
+	}
+	return []ContentBlock{{Type: BlockMarkdown, Text: content}}
+}
+
+// UpgradeContent gives a module, and its submodules, written before content blocks its Content as a
+// markdown block. It reports whether anything changed, so migrations know what to save.
+func (m *Module) UpgradeContent() bool {
+	changed := false
+	if upgraded := legacyBlocks(m.Content, m.Blocks); len(upgraded) != len(m.Blocks) {
+		m.Blocks = upgraded
+		changed = true
+	}
+	for i := range m.SubModules {
+		sub := &m.SubModules[i]
+		if upgraded := legacyBlocks(sub.Content, sub.Blocks); len(upgraded) != len(sub.Blocks) {
+			sub.Blocks = upgraded
+			changed = true
+		}
+	}
+	return changed
+}
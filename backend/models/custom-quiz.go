@@ -0,0 +1,31 @@
+package models
+
+// Limits on a custom quiz built by a student
+const (
+	CustomQuizMaxQuestions       = 50
+	CustomQuizMinutesPerQuestion = 2 // The time limit is this many minutes for each question drawn, until admins change it in the runtime settings
+)
+
+// StartCustomQuizRequest picks the questions for a custom practice quiz. Questions with any of
+// the tags are drawn; Difficulties limits them to those levels when set.
+type StartCustomQuizRequest struct {
+	Tags          []string          `json:"tags" binding:"required,min=1,max=10,dive,required,max=50"`
+	Difficulties  []DifficultyLevel `json:"difficulties,omitempty" binding:"omitempty,max=3,dive,oneof=easy medium hard"`
+	QuestionCount int               `json:"question_count" binding:"required,min=1,max=50"`
+
+	// Filled in by the controller from the request
+	IsGuest bool `json:"-"`
+}
+
+// CustomQuizFilters records what a custom quiz session was built from
+type CustomQuizFilters struct {
+	Tags          []string          `json:"tags" bson:"tags"`
+	Difficulties  []DifficultyLevel `json:"difficulties,omitempty" bson:"difficulties,omitempty"`
+	QuestionCount int               `json:"question_count" bson:"question_count"` // Requested; the session may hold fewer
+}
+
+// QuestionTagCount is how many active questions carry a tag
+type QuestionTagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Recalibration modes: relabel questions directly, or queue suggestions for an admin to approve
+const (
+	RecalibrationModeAuto    = "auto"
+	RecalibrationModeSuggest = "suggest"
+)
+
+// DifficultySuggestionStatus tracks an admin's review of a suggested difficulty change
+type DifficultySuggestionStatus string
+
+const (
+	SuggestionStatusPending  DifficultySuggestionStatus = "pending"
+	SuggestionStatusApproved DifficultySuggestionStatus = "approved" // Applied to the question
+	SuggestionStatusRejected DifficultySuggestionStatus = "rejected" // Not suggested again while the label stays the same
+)
+
+// QuestionCorrectRateRow is how often a question was answered correctly over the recalibration window
+type QuestionCorrectRateRow struct {
+	QuestionID primitive.ObjectID `bson:"_id"`
+	Attempts   int                `bson:"attempts"`
+	Correct    int                `bson:"correct"`
+}
+
+// DifficultySuggestion is a difficulty change the recalibration job proposed from observed correct rates,
+// held until an admin reviews it
+type DifficultySuggestion struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	QuestionID    primitive.ObjectID `json:"question_id" bson:"question_id"`
+	QuestionTitle string             `json:"question_title" bson:"question_title"`
+
+	CurrentDifficulty   DifficultyLevel `json:"current_difficulty" bson:"current_difficulty"`
+	SuggestedDifficulty DifficultyLevel `json:"suggested_difficulty" bson:"suggested_difficulty"`
+	CorrectRate         float64         `json:"correct_rate" bson:"correct_rate"` // 0-1, as of the latest run
+	Attempts            int             `json:"attempts" bson:"attempts"`
+
+	// Review
+	Status     DifficultySuggestionStatus `json:"status" bson:"status"`
+	ReviewNote string                     `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedBy *primitive.ObjectID        `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time                 `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// DifficultyChange is one question the recalibration job relabeled or suggested relabeling
+type DifficultyChange struct {
+	QuestionID  primitive.ObjectID `json:"question_id"`
+	Title       string             `json:"title"`
+	From        DifficultyLevel    `json:"from"`
+	To          DifficultyLevel    `json:"to"`
+	CorrectRate float64            `json:"correct_rate"`
+	Attempts    int                `json:"attempts"`
+}
+
+// RecalibrationResult summarizes one run of the recalibration job
+type RecalibrationResult struct {
+	Mode      string             `json:"mode"`
+	Analyzed  int                `json:"analyzed"`  // Questions with enough attempts to judge
+	Adjusted  int                `json:"adjusted"`  // Relabeled directly, in auto mode
+	Suggested int                `json:"suggested"` // Queued or refreshed for review, in suggest mode
+	Changes   []DifficultyChange `json:"changes"`
+	RanAt     time.Time          `json:"ran_at"`
+	Duration  string             `json:"duration"`
+}
+
+// Request/Response models for API
+
+// ListDifficultySuggestionsRequest represents the filters for a list of suggestions
+type ListDifficultySuggestionsRequest struct {
+	Page   int                        `form:"page,default=1" binding:"min=1"`
+	Limit  int                        `form:"limit,default=20" binding:"min=1,max=100"`
+	Status DifficultySuggestionStatus `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+// ListDifficultySuggestionsResponse represents a page of suggestions
+type ListDifficultySuggestionsResponse struct {
+	Suggestions  []DifficultySuggestion `json:"suggestions"`
+	Total        int64                  `json:"total"`
+	PendingCount int64                  `json:"pending_count"`
+	Page         int                    `json:"page"`
+	Limit        int                    `json:"limit"`
+	TotalPages   int                    `json:"total_pages"`
+	LastRun      *RecalibrationResult   `json:"last_run,omitempty"`
+}
+
+// ReviewDifficultySuggestionRequest approves or rejects a suggestion
+type ReviewDifficultySuggestionRequest struct {
+	Status DifficultySuggestionStatus `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string                     `json:"note,omitempty" binding:"max=1000"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmbedWidgetStatus represents the lifecycle of an embed widget
+type EmbedWidgetStatus string
+
+const (
+	EmbedWidgetActive  EmbedWidgetStatus = "active"
+	EmbedWidgetRevoked EmbedWidgetStatus = "revoked"
+)
+
+// EmbedWidget is a practice quiz that departmental sites embed in an iframe. Its token appears in
+// the embed code, so it is not a secret: access is limited to the allowed origins instead, by the
+// frame-ancestors policy of the embed page and by CORS on the widget API.
+type EmbedWidget struct {
+	ID    primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name  string             `json:"name" bson:"name"`
+	Token string             `json:"token" bson:"token"`
+
+	// Sites allowed to frame the widget or call its API, as origins such as "https://cs.example.ac.id"
+	AllowedOrigins []string `json:"allowed_origins" bson:"allowed_origins"`
+
+	// Questions each attempt is drawn from; no tags means the whole active bank
+	Tags             []string          `json:"tags,omitempty" bson:"tags,omitempty"`
+	Difficulties     []DifficultyLevel `json:"difficulties,omitempty" bson:"difficulties,omitempty"`
+	QuestionCount    int               `json:"question_count" bson:"question_count"`
+	TimeLimitMinutes int               `json:"time_limit_minutes" bson:"time_limit_minutes"`
+
+	Status EmbedWidgetStatus `json:"status" bson:"status"`
+
+	// Usage tracking
+	SessionCount  int64      `json:"session_count" bson:"session_count"`
+	LastStartedAt *time.Time `json:"last_started_at,omitempty" bson:"last_started_at,omitempty"`
+
+	// Lifecycle
+	CreatedBy      primitive.ObjectID  `json:"created_by" bson:"created_by"`
+	CreatedByEmail string              `json:"created_by_email" bson:"created_by_email"`
+	RevokedAt      *time.Time          `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedBy      *primitive.ObjectID `json:"revoked_by,omitempty" bson:"revoked_by,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// AllowsOrigin reports whether a site at the origin may embed the widget
+func (w *EmbedWidget) AllowsOrigin(origin string) bool {
+	for _, allowed := range w.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// Request/Response models for API
+
+// CreateEmbedWidgetRequest represents the request to create an embed widget
+type CreateEmbedWidgetRequest struct {
+	Name             string            `json:"name" binding:"required,max=100"`
+	AllowedOrigins   []string          `json:"allowed_origins" binding:"required,min=1,max=20,dive,required,max=200"`
+	Tags             []string          `json:"tags,omitempty" binding:"omitempty,max=10,dive,max=50"`
+	Difficulties     []DifficultyLevel `json:"difficulties,omitempty" binding:"omitempty,max=3,dive,oneof=easy medium hard"`
+	QuestionCount    int               `json:"question_count" binding:"required,min=1,max=50"`
+	TimeLimitMinutes int               `json:"time_limit_minutes,omitempty" binding:"omitempty,min=1,max=180"` // Defaults to two minutes per question
+}
+
+// UpdateEmbedWidgetRequest represents the request to change an embed widget's settings
+type UpdateEmbedWidgetRequest struct {
+	Name             *string           `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	AllowedOrigins   []string          `json:"allowed_origins,omitempty" binding:"omitempty,min=1,max=20,dive,required,max=200"`
+	Tags             []string          `json:"tags,omitempty" binding:"omitempty,max=10,dive,max=50"` // An empty list draws from the whole bank
+	Difficulties     []DifficultyLevel `json:"difficulties,omitempty" binding:"omitempty,max=3,dive,oneof=easy medium hard"`
+	QuestionCount    *int              `json:"question_count,omitempty" binding:"omitempty,min=1,max=50"`
+	TimeLimitMinutes *int              `json:"time_limit_minutes,omitempty" binding:"omitempty,min=1,max=180"`
+}
+
+// ListEmbedWidgetsRequest represents the request to list embed widgets
+type ListEmbedWidgetsRequest struct {
+	Page   int               `form:"page,default=1" binding:"min=1"`
+	Limit  int               `form:"limit,default=20" binding:"min=1,max=100"`
+	Status EmbedWidgetStatus `form:"status" binding:"omitempty,oneof=active revoked"`
+}
+
+// ListEmbedWidgetsResponse represents a page of embed widgets
+type ListEmbedWidgetsResponse struct {
+	Widgets    []EmbedWidget `json:"widgets"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// EmbedWidgetResponse is an embed widget with the snippet departmental sites paste into their pages
+type EmbedWidgetResponse struct {
+	Widget    *EmbedWidget `json:"widget"`
+	EmbedURL  string       `json:"embed_url"`
+	EmbedCode string       `json:"embed_code"`
+}
+
+// WidgetConfig is what the embedded widget is told about itself before starting
+type WidgetConfig struct {
+	Name             string            `json:"name"`
+	Tags             []string          `json:"tags,omitempty"`
+	Difficulties     []DifficultyLevel `json:"difficulties,omitempty"`
+	QuestionCount    int               `json:"question_count"`
+	TimeLimitMinutes int               `json:"time_limit_minutes"`
+}
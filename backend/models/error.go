@@ -0,0 +1,160 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// ErrorCode names what went wrong for API clients to branch on. Messages are meant for people and
+// may be reworded; codes stay.
+type ErrorCode string
+
+// General codes, one per status, for errors without a code of their own
+const (
+	ErrorCodeBadRequest      ErrorCode = "bad_request"
+	ErrorCodeUnauthorized    ErrorCode = "unauthorized"
+	ErrorCodeForbidden       ErrorCode = "forbidden"
+	ErrorCodeNotFound        ErrorCode = "not_found"
+	ErrorCodeConflict        ErrorCode = "conflict"
+	ErrorCodeGone            ErrorCode = "gone"
+	ErrorCodePayloadTooLarge ErrorCode = "payload_too_large"
+	ErrorCodeUnprocessable   ErrorCode = "unprocessable"
+	ErrorCodeRateLimited     ErrorCode = "rate_limited"
+	ErrorCodeUnavailable     ErrorCode = "unavailable"
+	ErrorCodeInternal        ErrorCode = "internal_error"
+)
+
+// Request errors
+const (
+	ErrorCodeValidation ErrorCode = "validation_failed" // The body or query did not bind; details say why
+	ErrorCodeInvalidID  ErrorCode = "invalid_id"        // An ID in the path or body is malformed
+	ErrorCodeCaptcha    ErrorCode = "captcha_failed"    // Solve the captcha again and retry
+)
+
+// Authentication errors
+const (
+	ErrorCodeAuthRequired       ErrorCode = "auth_required"       // No bearer token was sent
+	ErrorCodeTokenInvalid       ErrorCode = "token_invalid"       // The token is malformed, forged or revoked
+	ErrorCodeTokenExpired       ErrorCode = "token_expired"       // Refresh the access token and retry
+	ErrorCodeAccountRequired    ErrorCode = "account_required"    // Guests cannot do this
+	ErrorCodeInvalidCredentials ErrorCode = "invalid_credentials" // Wrong email, password or recovery code
+	ErrorCodeEmailTaken         ErrorCode = "email_taken"
+	ErrorCodeNIMTaken           ErrorCode = "nim_taken"
+	ErrorCodeAPIKeyRequired     ErrorCode = "api_key_required"
+	ErrorCodeAPIKeyInvalid      ErrorCode = "api_key_invalid" // Unknown, revoked or expired
+)
+
+// Content and quiz errors
+const (
+	ErrorCodeEditConflict      ErrorCode = "edit_conflict"       // Someone else saved first; reload and retry
+	ErrorCodeContentLocked     ErrorCode = "content_locked"      // Prerequisites are not completed yet
+	ErrorCodeQuizInProgress    ErrorCode = "quiz_in_progress"    // Resume or finish the quiz already started
+	ErrorCodeSessionExpired    ErrorCode = "session_expired"     // The quiz time ran out
+	ErrorCodeSessionFinished   ErrorCode = "session_finished"    // The quiz was already submitted
+	ErrorCodeSessionPaused     ErrorCode = "session_paused"      // An invigilator paused the quiz
+	ErrorCodeAttemptsExhausted ErrorCode = "attempts_exhausted"  // No attempts are left
+	ErrorCodeRetakeCooldown    ErrorCode = "retake_cooldown"     // Details say when the next attempt is allowed
+	ErrorCodeQuestionPoolShort ErrorCode = "question_pool_short" // Too few questions to draw the quiz from
+	ErrorCodeLockdownRequired  ErrorCode = "lockdown_required"   // The exam must be taken in the lockdown browser
+	ErrorCodeResultsPending    ErrorCode = "results_pending"     // Results are not released or graded yet
+)
+
+// ErrorResponse is the body of every error answer
+type ErrorResponse struct {
+	Code      ErrorCode `json:"code"`
+	Message   string    `json:"message"`
+	Details   any       `json:"details,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// StatusErrorCode is the general code of an error status
+func StatusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusGone:
+		return ErrorCodeGone
+	case http.StatusRequestEntityTooLarge:
+		return ErrorCodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeUnprocessable
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrorCodeUnavailable
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrorCodeInternal
+	}
+	return ErrorCodeBadRequest
+}
+
+// Error is an error answered with a status and code of its own. Both are fixed where the error is
+// made, so rewording its message changes neither. Errors callers tell apart are kept in variables
+// and matched with errors.Is.
+type Error struct {
+	Status  int
+	Code    ErrorCode
+	Message string
+	Details any // Sent along with the message, such as when to try again
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError makes an error answered with status under the general code of the status
+func NewError(status int, message string) error {
+	return &Error{Status: status, Code: StatusErrorCode(status), Message: message}
+}
+
+// NewCodedError makes an error answered with status under a code of its own
+func NewCodedError(status int, code ErrorCode, message string) error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// RetryDetails tells a client when a request refused for now can be made again
+type RetryDetails struct {
+	NextEligibleAt    time.Time `json:"next_eligible_at"`
+	RetryAfterSeconds int       `json:"retry_after_seconds"`
+}
+
+// NewRetryError makes an error answered with 429 Too Many Requests under code, telling the client
+// to try again at retryAt
+func NewRetryError(code ErrorCode, message string, retryAt time.Time) error {
+	return &Error{
+		Status:  http.StatusTooManyRequests,
+		Code:    code,
+		Message: message,
+		Details: RetryDetails{
+			NextEligibleAt:    retryAt,
+			RetryAfterSeconds: int(time.Until(retryAt).Seconds()) + 1,
+		},
+	}
+}
+
+// BadRequestError is for a request the client has to change before retrying
+func BadRequestError(message string) error {
+	return NewError(http.StatusBadRequest, message)
+}
+
+// ForbiddenError is for something the user is not allowed to do
+func ForbiddenError(message string) error {
+	return NewError(http.StatusForbidden, message)
+}
+
+// NotFoundError is for something that does not exist, or that the user may not know exists
+func NotFoundError(message string) error {
+	return NewError(http.StatusNotFound, message)
+}
+
+// ConflictError is for a request that clashes with the current state, such as a duplicate
+func ConflictError(message string) error {
+	return NewError(http.StatusConflict, message)
+}
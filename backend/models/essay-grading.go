@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RubricScore is the points awarded for one criterion of an essay rubric
+type RubricScore struct {
+	Criterion string `json:"criterion" bson:"criterion" binding:"required,max=200"`
+	Points    int    `json:"points" bson:"points" binding:"min=0"`
+	MaxPoints int    `json:"max_points" bson:"max_points" binding:"required,min=1"`
+}
+
+// EssayGrade is an admin's score and feedback for an essay answer. An essay counts as correct
+// when it earns at least half of its points.
+type EssayGrade struct {
+	Points       int                `json:"points" bson:"points"`
+	Rubric       []RubricScore      `json:"rubric,omitempty" bson:"rubric,omitempty"`
+	Feedback     string             `json:"feedback,omitempty" bson:"feedback,omitempty"`
+	GradedBy     primitive.ObjectID `json:"graded_by" bson:"graded_by"`
+	GradedByName string             `json:"graded_by_name" bson:"graded_by_name"`
+	GradedAt     time.Time          `json:"graded_at" bson:"graded_at"`
+}
+
+// EssayPassed reports whether an essay graded with points out of maxPoints counts as correct
+func EssayPassed(points, maxPoints int) bool {
+	return maxPoints > 0 && points*2 >= maxPoints
+}
+
+// Request/Response models for API
+
+// ListPendingGradingRequest represents the request to list essay answers awaiting a grade
+type ListPendingGradingRequest struct {
+	Page      int      `form:"page,default=1" binding:"min=1"`
+	Limit     int      `form:"limit,default=20" binding:"min=1,max=100"`
+	QuizType  QuizType `form:"quiz_type"`
+	SittingID string   `form:"sitting_id"` // Only answers from this exam sitting
+}
+
+// PendingEssay is one essay answer in the grading queue
+type PendingEssay struct {
+	ResultID      primitive.ObjectID  `json:"result_id" bson:"result_id"`
+	QuestionIndex int                 `json:"question_index" bson:"question_index"`
+	QuestionID    primitive.ObjectID  `json:"question_id" bson:"question_id"`
+	Title         string              `json:"title" bson:"title"`
+	Points        int                 `json:"points" bson:"points"`
+	Answer        interface{}         `json:"answer" bson:"answer"`
+	SampleAnswer  string              `json:"sample_answer,omitempty" bson:"-"` // From the question bank, as a grading guide
+	UserID        primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	FullName      string              `json:"full_name,omitempty" bson:"-"`
+	Email         string              `json:"email,omitempty" bson:"-"`
+	QuizType      QuizType            `json:"quiz_type" bson:"quiz_type"`
+	ExamSittingID *primitive.ObjectID `json:"exam_sitting_id,omitempty" bson:"exam_sitting_id,omitempty"`
+	SubmittedAt   time.Time           `json:"submitted_at" bson:"submitted_at"`
+}
+
+// ListPendingGradingResponse represents a page of the grading queue, oldest submissions first
+type ListPendingGradingResponse struct {
+	Essays     []PendingEssay `json:"essays"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// GradeEssayRequest represents the request to score an essay answer. With a rubric, the points
+// are the rubric's total and may be left out.
+type GradeEssayRequest struct {
+	Points   *int          `json:"points,omitempty" binding:"omitempty,min=0"`
+	Rubric   []RubricScore `json:"rubric,omitempty" binding:"omitempty,max=20,dive"`
+	Feedback string        `json:"feedback,omitempty" binding:"max=5000"`
+}
+
+// GradeEssayResponse represents the graded answer and the result's grading progress
+type GradeEssayResponse struct {
+	ResultID        primitive.ObjectID `json:"result_id"`
+	QuestionIndex   int                `json:"question_index"`
+	Grade           *EssayGrade        `json:"grade"`
+	PendingEssays   int                `json:"pending_essays"`
+	FullyGraded     bool               `json:"fully_graded"`
+	FinalScore      int                `json:"final_score"`
+	ScorePercentage float64            `json:"score_percentage"`
+}
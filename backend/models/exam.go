@@ -0,0 +1,396 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AttendanceStatus represents how a participant's actual session compares to their assignment
+type AttendanceStatus string
+
+const (
+	AttendancePresent    AttendanceStatus = "present"
+	AttendanceLate       AttendanceStatus = "late"
+	AttendanceAbsent     AttendanceStatus = "absent"
+	AttendanceUnassigned AttendanceStatus = "unassigned" // Started a session without a seat assignment
+)
+
+// ResultReleaseMode controls when students of a sitting can see their scores
+type ResultReleaseMode string
+
+const (
+	ResultReleaseImmediate ResultReleaseMode = "immediate" // As soon as the attempt is submitted
+	ResultReleaseScheduled ResultReleaseMode = "scheduled" // At ResultsReleaseAt
+	ResultReleaseManual    ResultReleaseMode = "manual"    // When an admin releases them
+)
+
+// ExamSitting is a scheduled, supervised run of a quiz type at one or more venues
+type ExamSitting struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	QuizType    QuizType           `json:"quiz_type" bson:"quiz_type"`
+
+	// Schedule
+	ScheduledStart   time.Time `json:"scheduled_start" bson:"scheduled_start"`
+	ScheduledEnd     time.Time `json:"scheduled_end" bson:"scheduled_end"`
+	LateAfterMinutes int       `json:"late_after_minutes" bson:"late_after_minutes"` // Sessions started later count as late
+
+	ParticipantCount int `json:"participant_count" bson:"participant_count"`
+
+	// Lockdown browser: when required, sessions started by seated students during the sitting
+	// must carry a handshake signed with LockdownKey (base64, configured in the lockdown browser)
+	RequireLockdownBrowser bool   `json:"require_lockdown_browser" bson:"require_lockdown_browser"`
+	LockdownKey            string `json:"-" bson:"lockdown_key,omitempty"`
+
+	// Result embargo; an empty mode (sittings created before embargoes existed) means immediate
+	ResultRelease     ResultReleaseMode `json:"result_release" bson:"result_release,omitempty"`
+	ResultsReleaseAt  *time.Time        `json:"results_release_at,omitempty" bson:"results_release_at,omitempty"` // Scheduled release time
+	ResultsReleasedAt *time.Time        `json:"results_released_at,omitempty" bson:"results_released_at,omitempty"`
+	ResultsWebhookAt  *time.Time        `json:"results_webhook_at,omitempty" bson:"results_webhook_at,omitempty"` // When released results were sent to department webhooks
+
+	// Moderation: results scoring within ModerationBand percentage points of PassMark (a score
+	// percentage) are flagged for instructor review; a zero band turns moderation off
+	PassMark       float64 `json:"pass_mark" bson:"pass_mark"`
+	ModerationBand float64 `json:"moderation_band" bson:"moderation_band"`
+
+	// Curve applied to the sitting's results before release
+	Scaling *ScoreScaling `json:"scaling,omitempty" bson:"scaling,omitempty"`
+
+	// Metadata
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// ResultsReleased reports whether students may see their results for the sitting at now
+func (s *ExamSitting) ResultsReleased(now time.Time) bool {
+	if s.ResultsReleasedAt != nil {
+		return true
+	}
+	switch s.ResultRelease {
+	case ResultReleaseScheduled:
+		return s.ResultsReleaseAt != nil && !now.Before(*s.ResultsReleaseAt)
+	case ResultReleaseManual:
+		return false
+	default:
+		return true
+	}
+}
+
+// ResultsAvailableAt is when withheld results become visible, if known in advance
+func (s *ExamSitting) ResultsAvailableAt() *time.Time {
+	if s.ResultRelease == ResultReleaseScheduled {
+		return s.ResultsReleaseAt
+	}
+	return nil
+}
+
+// NeedsModeration reports whether a score percentage is close enough to the pass mark to be reviewed
+func (s *ExamSitting) NeedsModeration(scorePercentage float64) bool {
+	if s.ModerationBand <= 0 {
+		return false
+	}
+	return scorePercentage >= s.PassMark-s.ModerationBand && scorePercentage <= s.PassMark+s.ModerationBand
+}
+
+// ExamParticipant is a student's participation record for a sitting with their venue and seat
+type ExamParticipant struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SittingID primitive.ObjectID `json:"sitting_id" bson:"sitting_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+
+	// Snapshot of the student at assignment time
+	FullName string `json:"full_name" bson:"full_name"`
+	Email    string `json:"email" bson:"email"`
+	NIM      string `json:"nim,omitempty" bson:"nim,omitempty"`
+
+	// Seating
+	Venue string `json:"venue" bson:"venue"`
+	Room  string `json:"room,omitempty" bson:"room,omitempty"`
+	Seat  string `json:"seat,omitempty" bson:"seat,omitempty"`
+
+	// Metadata
+	AssignedBy primitive.ObjectID `json:"assigned_by" bson:"assigned_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// CreateExamSittingRequest represents the request to schedule a sitting
+type CreateExamSittingRequest struct {
+	Name             string    `json:"name" binding:"required,min=3,max=200"`
+	Description      string    `json:"description,omitempty" binding:"max=1000"`
+	QuizType         QuizType  `json:"quiz_type" binding:"required,oneof=mock_test time_quiz"`
+	ScheduledStart   time.Time `json:"scheduled_start" binding:"required"`
+	ScheduledEnd     time.Time `json:"scheduled_end" binding:"required"`
+	LateAfterMinutes int       `json:"late_after_minutes,omitempty" binding:"min=0,max=240"`
+
+	ResultRelease    ResultReleaseMode `json:"result_release,omitempty" binding:"omitempty,oneof=immediate scheduled manual"`
+	ResultsReleaseAt *time.Time        `json:"results_release_at,omitempty"` // Required for scheduled release
+
+	PassMark       float64 `json:"pass_mark,omitempty" binding:"min=0,max=100"`
+	ModerationBand float64 `json:"moderation_band,omitempty" binding:"min=0,max=50"`
+}
+
+// SeatAssignment assigns one student, identified by user ID or NIM, to a seat
+type SeatAssignment struct {
+	UserID string `json:"user_id,omitempty"`
+	NIM    string `json:"nim,omitempty"`
+	Venue  string `json:"venue" binding:"required,max=200"`
+	Room   string `json:"room,omitempty" binding:"max=100"`
+	Seat   string `json:"seat,omitempty" binding:"max=50"`
+}
+
+// AssignSeatsRequest represents a batch of seat assignments for a sitting
+type AssignSeatsRequest struct {
+	Assignments []SeatAssignment `json:"assignments" binding:"required,min=1,max=1000,dive"`
+}
+
+// SeatAssignmentError reports an assignment that could not be applied
+type SeatAssignmentError struct {
+	Index   int    `json:"index"`
+	UserID  string `json:"user_id,omitempty"`
+	NIM     string `json:"nim,omitempty"`
+	Message string `json:"message"`
+}
+
+// AssignSeatsResponse summarizes a batch of seat assignments
+type AssignSeatsResponse struct {
+	Assigned int                   `json:"assigned"`
+	Errors   []SeatAssignmentError `json:"errors,omitempty"`
+}
+
+// ExamSittingResponse represents a sitting with its participants
+type ExamSittingResponse struct {
+	Sitting      *ExamSitting      `json:"sitting"`
+	Participants []ExamParticipant `json:"participants"`
+}
+
+// AttendanceRecord compares one assignment (or unassigned session) with the session actually started
+type AttendanceRecord struct {
+	UserID   string           `json:"user_id"`
+	FullName string           `json:"full_name"`
+	Email    string           `json:"email"`
+	NIM      string           `json:"nim,omitempty"`
+	Venue    string           `json:"venue,omitempty"`
+	Room     string           `json:"room,omitempty"`
+	Seat     string           `json:"seat,omitempty"`
+	Status   AttendanceStatus `json:"status"`
+
+	// Session actually started, if any
+	SessionID      string     `json:"session_id,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	StartIP        string     `json:"start_ip,omitempty"`
+	SessionStatus  QuizStatus `json:"session_status,omitempty"`
+	MinutesLate    int        `json:"minutes_late,omitempty"`
+	SessionCount   int        `json:"session_count,omitempty"`    // More than one suggests a restart
+	IPAddressCount int        `json:"ip_address_count,omitempty"` // Distinct start IPs across those sessions
+}
+
+// AttendanceSummary counts records by status
+type AttendanceSummary struct {
+	Assigned   int `json:"assigned"`
+	Present    int `json:"present"`
+	Late       int `json:"late"`
+	Absent     int `json:"absent"`
+	Unassigned int `json:"unassigned"`
+}
+
+// AttendanceReport is the reconciliation of assigned seats against started sessions for a sitting
+type AttendanceReport struct {
+	Sitting     *ExamSitting       `json:"sitting"`
+	Summary     AttendanceSummary  `json:"summary"`
+	Records     []AttendanceRecord `json:"records"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// ExamAssignmentResponse represents one of the current user's seat assignments
+type ExamAssignmentResponse struct {
+	Sitting    ExamSitting     `json:"sitting"`
+	Assignment ExamParticipant `json:"assignment"`
+}
+
+// OfflineSubmissionStatus represents the outcome of ingesting one student's offline answers
+type OfflineSubmissionStatus string
+
+const (
+	OfflineSubmissionScored    OfflineSubmissionStatus = "scored"
+	OfflineSubmissionDuplicate OfflineSubmissionStatus = "duplicate"
+	OfflineSubmissionInvalid   OfflineSubmissionStatus = "invalid"
+	OfflineSubmissionFailed    OfflineSubmissionStatus = "failed"
+)
+
+// OfflineExamPackage is a frozen question set for a sitting, distributed encrypted to invigilator
+// clients at venues without reliable connectivity. Answer keys never leave the server.
+type OfflineExamPackage struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SittingID        primitive.ObjectID `json:"sitting_id" bson:"sitting_id"`
+	QuizType         QuizType           `json:"quiz_type" bson:"quiz_type"`
+	Questions        []SessionQuestion  `json:"-" bson:"questions"`
+	QuestionCount    int                `json:"question_count" bson:"question_count"`
+	MaxPoints        int                `json:"max_points" bson:"max_points"`
+	TimeLimitMinutes int                `json:"time_limit_minutes" bson:"time_limit_minutes"`
+	Scoring          *QuizScoring       `json:"scoring,omitempty" bson:"scoring,omitempty"` // Rules of the template the set was drawn from
+	EncryptionKey    string             `json:"-" bson:"encryption_key"`                    // base64 AES-256 key shared with the invigilator client
+
+	// Copied from the template, so ingested results keep their answer key from students too
+	HideCorrectAnswers bool `json:"hide_correct_answers,omitempty" bson:"hide_correct_answers,omitempty"`
+
+	// User IDs whose answers have been ingested, so re-uploaded files don't score twice
+	IngestedUserIDs []primitive.ObjectID `json:"-" bson:"ingested_user_ids"`
+	IngestedCount   int                  `json:"ingested_count" bson:"ingested_count"`
+
+	// Metadata
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// EncryptedOfflineFile is the envelope used for both downloaded packages and uploaded answer files.
+// Payload is AES-256-GCM ciphertext of the JSON content, authenticated with the package ID as
+// additional data so files cannot be swapped between packages.
+type EncryptedOfflineFile struct {
+	Version   int    `json:"version"`
+	PackageID string `json:"package_id"`
+	Algorithm string `json:"algorithm"`
+	Nonce     string `json:"nonce"`   // base64
+	Payload   string `json:"payload"` // base64
+}
+
+// OfflinePackageParticipant is a seat assignment shipped inside a package
+type OfflinePackageParticipant struct {
+	UserID   string `json:"user_id"`
+	FullName string `json:"full_name"`
+	NIM      string `json:"nim,omitempty"`
+	Venue    string `json:"venue"`
+	Room     string `json:"room,omitempty"`
+	Seat     string `json:"seat,omitempty"`
+}
+
+// OfflinePackageContent is the decrypted content of a downloaded package
+type OfflinePackageContent struct {
+	PackageID        string                      `json:"package_id"`
+	SittingID        string                      `json:"sitting_id"`
+	SittingName      string                      `json:"sitting_name"`
+	QuizType         QuizType                    `json:"quiz_type"`
+	ScheduledStart   time.Time                   `json:"scheduled_start"`
+	ScheduledEnd     time.Time                   `json:"scheduled_end"`
+	TimeLimitMinutes int                         `json:"time_limit_minutes"`
+	MaxPoints        int                         `json:"max_points"`
+	Questions        []SessionQuestion           `json:"questions"` // Correct answers are excluded by SessionQuestion's JSON tags
+	Participants     []OfflinePackageParticipant `json:"participants"`
+	GeneratedAt      time.Time                   `json:"generated_at"`
+}
+
+// OfflineAnswer is a student's response to one package question
+type OfflineAnswer struct {
+	QuestionID string      `json:"question_id"`
+	Answer     interface{} `json:"answer,omitempty"` // string or []string
+	Skipped    bool        `json:"skipped,omitempty"`
+	TimeSpent  int64       `json:"time_spent,omitempty"` // seconds
+}
+
+// OfflineSubmission is one student's completed attempt recorded by the invigilator client
+type OfflineSubmission struct {
+	UserID      string          `json:"user_id"`
+	StartedAt   time.Time       `json:"started_at"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	Answers     []OfflineAnswer `json:"answers"`
+}
+
+// OfflineAnswerFile is the decrypted content of an uploaded answer file
+type OfflineAnswerFile struct {
+	PackageID   string              `json:"package_id"`
+	Submissions []OfflineSubmission `json:"submissions"`
+}
+
+// Request/Response models for offline packages
+
+// UpdateResultReleaseRequest changes when a sitting's results are published
+type UpdateResultReleaseRequest struct {
+	Mode             ResultReleaseMode `json:"mode" binding:"required,oneof=immediate scheduled manual"`
+	ResultsReleaseAt *time.Time        `json:"results_release_at,omitempty"` // Required for scheduled release
+}
+
+// UpdateLockdownSettingsRequest turns the lockdown browser requirement on or off for a sitting
+type UpdateLockdownSettingsRequest struct {
+	Required  *bool `json:"required" binding:"required"`
+	RotateKey bool  `json:"rotate_key,omitempty"` // Issue a new key, invalidating browsers configured with the old one
+}
+
+// LockdownSettingsResponse returns the sitting with its lockdown key when one was just issued
+type LockdownSettingsResponse struct {
+	Sitting     *ExamSitting `json:"sitting"`
+	LockdownKey string       `json:"lockdown_key,omitempty"`
+	Message     string       `json:"message"`
+}
+
+// CreateOfflinePackageResponse returns the new package with its decryption key, which is shown only once
+type CreateOfflinePackageResponse struct {
+	Package       *OfflineExamPackage `json:"package"`
+	EncryptionKey string              `json:"encryption_key"`
+	Message       string              `json:"message"`
+}
+
+// OfflineSubmissionResult reports the outcome for one submission in an answer file
+type OfflineSubmissionResult struct {
+	Index      int                     `json:"index"`
+	UserID     string                  `json:"user_id,omitempty"`
+	Status     OfflineSubmissionStatus `json:"status"`
+	Message    string                  `json:"message,omitempty"`
+	ResultID   string                  `json:"result_id,omitempty"`
+	FinalScore int                     `json:"final_score,omitempty"`
+}
+
+// IngestOfflineAnswersResponse summarizes an uploaded answer file
+type IngestOfflineAnswersResponse struct {
+	PackageID   string                    `json:"package_id"`
+	Submissions int                       `json:"submissions"`
+	Scored      int                       `json:"scored"`
+	Duplicates  int                       `json:"duplicates"`
+	Invalid     int                       `json:"invalid"`
+	Failed      int                       `json:"failed"`
+	Results     []OfflineSubmissionResult `json:"results"`
+}
+
+// ExamPaperSet is the question set printed for a sitting's paper-based contingency. It is drawn on
+// the first print and kept, so reprints and every room's version ask the same questions.
+type ExamPaperSet struct {
+	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SittingID        primitive.ObjectID `json:"sitting_id" bson:"sitting_id"`
+	QuizType         QuizType           `json:"quiz_type" bson:"quiz_type"`
+	Questions        []SessionQuestion  `json:"-" bson:"questions"`
+	QuestionCount    int                `json:"question_count" bson:"question_count"`
+	MaxPoints        int                `json:"max_points" bson:"max_points"`
+	TimeLimitMinutes int                `json:"time_limit_minutes" bson:"time_limit_minutes"`
+	Seed             string             `json:"-" bson:"seed"` // Each room's shuffle is derived from it and the room name
+
+	// Metadata
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// PaperDocument is what printing a sitting produces
+type PaperDocument string
+
+const (
+	PaperQuestions PaperDocument = "paper"      // Question papers handed to students
+	PaperAnswerKey PaperDocument = "answer_key" // Answers for markers, kept apart from the papers
+)
+
+// PrintExamPaperRequest holds the query parameters of a print
+type PrintExamPaperRequest struct {
+	Document PaperDocument `form:"document" binding:"omitempty,oneof=paper answer_key"`
+	// Only this room's version; any name is accepted so spare versions can be printed for rooms without seats
+	Room string `form:"room" binding:"max=100"`
+}
+
+// PrintedExamPaper is a rendered PDF with one version per room
+type PrintedExamPaper struct {
+	Sitting  *ExamSitting
+	Document PaperDocument
+	Versions int
+	Content  []byte
+}
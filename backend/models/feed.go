@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// FeedFormat is the syndication format a content feed is served in
+type FeedFormat string
+
+const (
+	FeedAtom FeedFormat = "atom" // application/atom+xml
+	FeedRSS  FeedFormat = "rss"  // RSS 2.0
+)
+
+// FeedEntry is a published module or submodule in a content feed
+type FeedEntry struct {
+	ID          string // Module or submodule ID, stable across feed refreshes
+	Title       string
+	Summary     string
+	Link        string
+	Category    string // Parent module name for submodules
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// ContentFeed is newly published learning content, newest first
+type ContentFeed struct {
+	ID       string
+	Title    string
+	Subtitle string
+	Link     string // Where the content is read
+	SelfLink string // The feed itself
+	Updated  time.Time
+	Entries  []FeedEntry
+}
+
+// ContentFeedRequest represents query parameters for content feeds
+type ContentFeedRequest struct {
+	Limit int `form:"limit,default=50" binding:"min=1,max=100"`
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// blankPlaceholder matches a blank written into a question title, such as {{1}} or {{ capital }}
+var blankPlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// Blank is one gap of a fill-in-the-blank question, written as {{id}} in the title
+type Blank struct {
+	ID              string   `json:"id" bson:"id" binding:"required,max=20"`
+	AcceptedAnswers []string `json:"accepted_answers" bson:"accepted_answers" binding:"required,min=1,max=20"`
+	CaseSensitive   bool     `json:"case_sensitive,omitempty" bson:"case_sensitive,omitempty"`
+	Regex           bool     `json:"regex,omitempty" bson:"regex,omitempty"` // Accepted answers are patterns the whole response must match
+}
+
+// BlankPlaceholders returns the blank IDs written into a title, in order of appearance
+func BlankPlaceholders(title string) []string {
+	var ids []string
+	for _, match := range blankPlaceholder.FindAllStringSubmatch(title, -1) {
+		ids = append(ids, match[1])
+	}
+	return ids
+}
+
+// ReplaceBlankPlaceholders rewrites each blank placeholder in a title, e.g. to draw it on paper
+func ReplaceBlankPlaceholders(title string, replace func(id string) string) string {
+	return blankPlaceholder.ReplaceAllStringFunc(title, func(placeholder string) string {
+		return replace(blankPlaceholder.FindStringSubmatch(placeholder)[1])
+	})
+}
+
+// NormalizeBlankResponse trims and collapses whitespace, and lowercases unless the blank is case sensitive
+func NormalizeBlankResponse(response string, caseSensitive bool) string {
+	normalized := strings.Join(strings.Fields(response), " ")
+	if !caseSensitive {
+		normalized = strings.ToLower(normalized)
+	}
+	return normalized
+}
+
+// CompileBlankPattern compiles an accepted answer of a regex blank so it must match the whole response.
+// The pattern must be valid on its own, so one like "a)|(b" cannot break out of the anchors.
+func CompileBlankPattern(pattern string, caseSensitive bool) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	flags := "(?i)"
+	if caseSensitive {
+		flags = ""
+	}
+	return regexp.Compile(flags + `^(?:` + pattern + `)$`)
+}
+
+// Accepts reports whether a response fills the blank correctly. Questions are only saved when every
+// pattern compiles, so a pattern that does not, stored before it was checked, matches nothing.
+func (b Blank) Accepts(response string) bool {
+	normalized := NormalizeBlankResponse(response, b.CaseSensitive)
+	if normalized == "" {
+		return false
+	}
+
+	for _, accepted := range b.AcceptedAnswers {
+		if b.Regex {
+			pattern, err := CompileBlankPattern(accepted, b.CaseSensitive)
+			if err == nil && pattern.MatchString(normalized) {
+				return true
+			}
+			continue
+		}
+		if normalized == NormalizeBlankResponse(accepted, b.CaseSensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlankResponses reads a fill-in-the-blank answer into responses keyed by blank ID. The answer may be
+// an object keyed by blank ID, a list in blank order, or a plain string when there is a single blank.
+func BlankResponses(answer interface{}, blanks []Blank) (map[string]string, bool) {
+	responses := make(map[string]string, len(blanks))
+
+	fromList := func(values []interface{}) bool {
+		if len(values) > len(blanks) {
+			return false
+		}
+		for i, value := range values {
+			text, ok := value.(string)
+			if !ok {
+				return false
+			}
+			responses[blanks[i].ID] = text
+		}
+		return true
+	}
+
+	fromMap := func(values map[string]interface{}) bool {
+		for id, value := range values {
+			text, ok := value.(string)
+			if !ok {
+				return false
+			}
+			responses[id] = text
+		}
+		return true
+	}
+
+	var ok bool
+	switch v := answer.(type) {
+	case string:
+		if len(blanks) != 1 {
+			return nil, false
+		}
+		responses[blanks[0].ID] = v
+		ok = true
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, text := range v {
+			values[i] = text
+		}
+		ok = fromList(values)
+	case []interface{}:
+		ok = fromList(v)
+	case primitive.A:
+		ok = fromList(v)
+	case map[string]string:
+		for id, text := range v {
+			responses[id] = text
+		}
+		ok = true
+	case map[string]interface{}:
+		ok = fromMap(v)
+	case primitive.M:
+		ok = fromMap(v)
+	case primitive.D:
+		values := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			values[element.Key] = element.Value
+		}
+		ok = fromMap(values)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	// Responses must only name blanks the question has
+	known := make(map[string]bool, len(blanks))
+	for _, blank := range blanks {
+		known[blank.ID] = true
+	}
+	for id := range responses {
+		if !known[id] {
+			return nil, false
+		}
+	}
+
+	return responses, true
+}
+
+// GradeBlanks checks each blank of an answer, keyed by blank ID
+func GradeBlanks(answer interface{}, blanks []Blank) map[string]bool {
+	results := make(map[string]bool, len(blanks))
+	responses, _ := BlankResponses(answer, blanks)
+	for _, blank := range blanks {
+		results[blank.ID] = blank.Accepts(responses[blank.ID])
+	}
+	return results
+}
+
+// BlankAnswerKey lists the accepted answers of each blank, keyed by blank ID, for result review
+func BlankAnswerKey(blanks []Blank) map[string][]string {
+	key := make(map[string][]string, len(blanks))
+	for _, blank := range blanks {
+		key[blank.ID] = blank.AcceptedAnswers
+	}
+	return key
+}
+
+// FillBlanks renders a title with each blank replaced by its first accepted answer.
+// Regex blanks have no literal answer and are left as placeholders.
+func FillBlanks(title string, blanks []Blank) string {
+	byID := make(map[string]Blank, len(blanks))
+	for _, blank := range blanks {
+		byID[blank.ID] = blank
+	}
+
+	return blankPlaceholder.ReplaceAllStringFunc(title, func(placeholder string) string {
+		id := blankPlaceholder.FindStringSubmatch(placeholder)[1]
+		blank, ok := byID[id]
+		if !ok || blank.Regex || len(blank.AcceptedAnswers) == 0 {
+			return placeholder
+		}
+		return blank.AcceptedAnswers[0]
+	})
+}
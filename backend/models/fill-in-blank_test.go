@@ -0,0 +1,135 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeBlankResponse(t *testing.T) {
+	tests := []struct {
+		response      string
+		caseSensitive bool
+		want          string
+	}{
+		{"Jakarta", false, "jakarta"},
+		{"Jakarta", true, "Jakarta"},
+		{"  New \t York\n", false, "new york"},
+		{"  New \t York\n", true, "New York"},
+		{"ÉCOLE", false, "école"},
+		{"   ", false, ""},
+		{"", true, ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeBlankResponse(tt.response, tt.caseSensitive); got != tt.want {
+			t.Errorf("NormalizeBlankResponse(%q, %v) = %q, want %q", tt.response, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestBlankAccepts(t *testing.T) {
+	tests := []struct {
+		name     string
+		blank    Blank
+		response string
+		want     bool
+	}{
+		{"exact", Blank{AcceptedAnswers: []string{"Jakarta"}}, "Jakarta", true},
+		{"any case", Blank{AcceptedAnswers: []string{"Jakarta"}}, "jAKARTA", true},
+		{"case sensitive", Blank{AcceptedAnswers: []string{"Jakarta"}, CaseSensitive: true}, "jakarta", false},
+		{"case sensitive match", Blank{AcceptedAnswers: []string{"Jakarta"}, CaseSensitive: true}, "Jakarta", true},
+		{"surrounding whitespace", Blank{AcceptedAnswers: []string{"Jakarta"}}, "  Jakarta\n", true},
+		{"inner whitespace", Blank{AcceptedAnswers: []string{"New York"}}, "new \t  york", true},
+		{"whitespace in the accepted answer", Blank{AcceptedAnswers: []string{" New  York "}}, "New York", true},
+		{"second accepted answer", Blank{AcceptedAnswers: []string{"Batavia", "Jakarta"}}, "jakarta", true},
+		{"wrong", Blank{AcceptedAnswers: []string{"Jakarta"}}, "Bandung", false},
+		{"empty response", Blank{AcceptedAnswers: []string{"Jakarta"}}, "", false},
+		{"blank response", Blank{AcceptedAnswers: []string{"Jakarta"}}, "   ", false},
+		{"literal metacharacters", Blank{AcceptedAnswers: []string{"a.c"}}, "abc", false},
+
+		{"regex", Blank{AcceptedAnswers: []string{`\d+`}, Regex: true}, "42", true},
+		{"regex matches the whole response", Blank{AcceptedAnswers: []string{`\d+`}, Regex: true}, "42a", false},
+		{"regex alternation is anchored", Blank{AcceptedAnswers: []string{"cat|dog"}, Regex: true}, "hotdog", false},
+		{"regex alternation", Blank{AcceptedAnswers: []string{"cat|dog"}, Regex: true}, "dog", true},
+		{"regex any case", Blank{AcceptedAnswers: []string{"colou?r"}, Regex: true}, "COLOR", true},
+		{"regex case sensitive", Blank{AcceptedAnswers: []string{"colou?r"}, Regex: true, CaseSensitive: true}, "COLOR", false},
+		{"regex sees the normalized response", Blank{AcceptedAnswers: []string{"h2 o"}, Regex: true}, "  H2   O ", true},
+		{"regex empty response", Blank{AcceptedAnswers: []string{".*"}, Regex: true}, "", false},
+		{"invalid pattern matches nothing", Blank{AcceptedAnswers: []string{"(", "x"}, Regex: true}, "(", false},
+		{"valid pattern next to an invalid one", Blank{AcceptedAnswers: []string{"(", "x"}, Regex: true}, "x", true},
+		{"pattern breaking out of the anchors", Blank{AcceptedAnswers: []string{"a)|(b"}, Regex: true}, "abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.blank.Accepts(tt.response); got != tt.want {
+				t.Errorf("Accepts(%q) = %v, want %v", tt.response, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileBlankPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{`\d+`, true},
+		{"cat|dog", true},
+		{"(", false},
+		{"[a-", false},
+		{"a)|(b", false}, // Only compiles once wrapped, and would then match anything starting with "a"
+		{`\p{Foo}`, false},
+	}
+
+	for _, tt := range tests {
+		_, err := CompileBlankPattern(tt.pattern, false)
+		if (err == nil) != tt.valid {
+			t.Errorf("CompileBlankPattern(%q) error = %v, want valid %v", tt.pattern, err, tt.valid)
+		}
+	}
+}
+
+func TestGradeBlanks(t *testing.T) {
+	blanks := []Blank{
+		{ID: "city", AcceptedAnswers: []string{"Jakarta"}},
+		{ID: "year", AcceptedAnswers: []string{`19\d\d`}, Regex: true},
+	}
+	none := map[string]bool{"city": false, "year": false}
+
+	tests := []struct {
+		name   string
+		answer interface{}
+		want   map[string]bool
+	}{
+		{"object", map[string]interface{}{"city": " jakarta ", "year": "1945"}, map[string]bool{"city": true, "year": true}},
+		{"object of strings", map[string]string{"year": "1945"}, map[string]bool{"city": false, "year": true}},
+		{"list in blank order", []interface{}{"Jakarta", "2045"}, map[string]bool{"city": true, "year": false}},
+		{"list of strings", []string{"Bandung", "1928"}, map[string]bool{"city": false, "year": true}},
+		{"short list", []interface{}{"Jakarta"}, map[string]bool{"city": true, "year": false}},
+		{"from the database as a list", primitive.A{"Jakarta", "1945"}, map[string]bool{"city": true, "year": true}},
+		{"from the database as a map", primitive.M{"city": "Jakarta"}, map[string]bool{"city": true, "year": false}},
+		{"from the database as a document", primitive.D{{Key: "year", Value: "1998"}}, map[string]bool{"city": false, "year": true}},
+		{"string with several blanks", "Jakarta", none},
+		{"unknown blank", map[string]interface{}{"city": "Jakarta", "country": "Indonesia"}, none},
+		{"list too long", []interface{}{"Jakarta", "1945", "extra"}, none},
+		{"non-string value", map[string]interface{}{"city": "Jakarta", "year": 1945}, none},
+		{"non-string list item", []interface{}{"Jakarta", 1945}, none},
+		{"no answer", nil, none},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GradeBlanks(tt.answer, blanks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GradeBlanks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	single := []Blank{{ID: "city", AcceptedAnswers: []string{"Jakarta"}}}
+	if got := GradeBlanks("jakarta", single); !got["city"] {
+		t.Errorf("GradeBlanks() of a plain string for a single blank = %v, want it accepted", got)
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FlashcardSource represents where a deck's cards were generated from
+type FlashcardSource string
+
+const (
+	FlashcardSourceModule FlashcardSource = "module"          // Key points of a module's submodules
+	FlashcardSourceMissed FlashcardSource = "missed_question" // Questions the user answered incorrectly
+)
+
+// ReviewSchedule holds spaced-repetition state (SM-2) for a reviewable item
+type ReviewSchedule struct {
+	EaseFactor     float64    `json:"ease_factor" bson:"ease_factor"`
+	IntervalDays   int        `json:"interval_days" bson:"interval_days"`
+	Repetitions    int        `json:"repetitions" bson:"repetitions"`
+	DueAt          time.Time  `json:"due_at" bson:"due_at"`
+	LastReviewedAt *time.Time `json:"last_reviewed_at,omitempty" bson:"last_reviewed_at,omitempty"`
+	LastQuality    int        `json:"last_quality" bson:"last_quality"`
+}
+
+// FlashcardDeck groups generated flashcards for a user
+type FlashcardDeck struct {
+	ID       primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID   primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	Name     string              `json:"name" bson:"name"`
+	Source   FlashcardSource     `json:"source" bson:"source"`
+	SourceID *primitive.ObjectID `json:"source_id,omitempty" bson:"source_id,omitempty"` // Module ID for module decks
+
+	CardCount int `json:"card_count" bson:"card_count"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Flashcard represents a single front/back card with its review schedule
+type Flashcard struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DeckID    primitive.ObjectID `json:"deck_id" bson:"deck_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Source    FlashcardSource    `json:"source" bson:"source"`
+	SourceID  primitive.ObjectID `json:"source_id" bson:"source_id"`   // SubModule ID or Question ID
+	SourceKey string             `json:"source_key" bson:"source_key"` // Stable key within the source, keeps schedules across regeneration
+
+	Front string `json:"front" bson:"front"`
+	Back  string `json:"back" bson:"back"`
+
+	Schedule ReviewSchedule `json:"schedule" bson:"schedule"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// GenerateMissedDeckRequest represents the request to build a deck from missed questions
+type GenerateMissedDeckRequest struct {
+	QuizType QuizType `json:"quiz_type,omitempty"`
+	Results  int      `json:"results,omitempty" binding:"omitempty,min=1,max=100"` // How many recent results to scan
+}
+
+// ReviewFlashcardRequest represents a review answer graded on the SM-2 0-5 scale
+type ReviewFlashcardRequest struct {
+	Quality *int `json:"quality" binding:"required,min=0,max=5"`
+}
+
+// GenerateDeckResponse represents the response after generating a deck
+type GenerateDeckResponse struct {
+	Deck  FlashcardDeck `json:"deck"`
+	Cards []Flashcard   `json:"cards"`
+}
+
+// DueFlashcardsResponse represents cards due for review
+type DueFlashcardsResponse struct {
+	Cards []Flashcard `json:"cards"`
+	Total int         `json:"total"`
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScalingMethod is the function used to curve a sitting's score percentages
+type ScalingMethod string
+
+const (
+	// ScalingLinear maps a score percentage p to Slope*p + Intercept
+	ScalingLinear ScalingMethod = "linear"
+	// ScalingSquareRoot maps a score percentage p to 10*sqrt(p): 0 and 100 stay put, 49 becomes 70
+	// and 81 becomes 90, so low scores gain the most
+	ScalingSquareRoot ScalingMethod = "sqrt"
+)
+
+// ScoreScaling is the curve applied to an exam sitting's results. Scaled percentages are clamped
+// to 0-100 and the order of students never changes.
+type ScoreScaling struct {
+	Method    ScalingMethod      `json:"method" bson:"method"`
+	Slope     float64            `json:"slope,omitempty" bson:"slope,omitempty"` // Linear only
+	Intercept float64            `json:"intercept,omitempty" bson:"intercept,omitempty"`
+	AppliedBy primitive.ObjectID `json:"applied_by,omitempty" bson:"applied_by,omitempty"`
+	AppliedAt *time.Time         `json:"applied_at,omitempty" bson:"applied_at,omitempty"`
+}
+
+// Scale returns the scaled score percentage for a raw one
+func (s *ScoreScaling) Scale(percentage float64) float64 {
+	var scaled float64
+	switch s.Method {
+	case ScalingLinear:
+		scaled = s.Slope*percentage + s.Intercept
+	case ScalingSquareRoot:
+		scaled = 10 * math.Sqrt(math.Max(0, percentage))
+	default:
+		scaled = percentage
+	}
+	return math.Max(0, math.Min(100, scaled))
+}
+
+// ApplyTo scales a result from its raw scores, recording them the first time so that
+// re-scaling never compounds
+func (s *ScoreScaling) ApplyTo(r *DetailedQuizResult, now time.Time) {
+	if r.Scaling == nil {
+		r.Scaling = &ResultScaling{
+			RawFinalScore:      r.FinalScore,
+			RawScorePercentage: r.ScorePercentage,
+			RawScore:           r.Score,
+		}
+	}
+	r.Scaling.Method = s.Method
+	r.Scaling.ScaledAt = now
+
+	r.ScorePercentage = s.Scale(r.Scaling.RawScorePercentage)
+	r.FinalScore = int(math.Round(r.ScorePercentage * float64(r.TotalPoints) / 100))
+	r.Score = int(r.ScorePercentage)
+}
+
+// ResultScaling keeps a scaled result's raw scores
+type ResultScaling struct {
+	Method             ScalingMethod `json:"method" bson:"method"`
+	RawFinalScore      int           `json:"raw_final_score" bson:"raw_final_score"`
+	RawScorePercentage float64       `json:"raw_score_percentage" bson:"raw_score_percentage"`
+	RawScore           int           `json:"raw_score" bson:"raw_score"`
+	ScaledAt           time.Time     `json:"scaled_at" bson:"scaled_at"`
+}
+
+// RemoveScaling puts a scaled result back to its raw scores
+func (r *DetailedQuizResult) RemoveScaling() {
+	if r.Scaling == nil {
+		return
+	}
+	r.FinalScore = r.Scaling.RawFinalScore
+	r.ScorePercentage = r.Scaling.RawScorePercentage
+	r.Score = r.Scaling.RawScore
+	r.Scaling = nil
+}
+
+// ScoreDistribution summarises a set of score percentages
+type ScoreDistribution struct {
+	Count    int           `json:"count"`
+	Mean     float64       `json:"mean"`
+	Median   float64       `json:"median"`
+	StdDev   float64       `json:"std_dev"`
+	Min      float64       `json:"min"`
+	Max      float64       `json:"max"`
+	PassMark float64       `json:"pass_mark"`
+	Passed   int           `json:"passed"` // Results at or above PassMark
+	Buckets  []ScoreBucket `json:"buckets"`
+}
+
+// ScoreBucket counts the results whose percentage is in [From, To); the last bucket includes 100
+type ScoreBucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+// NewScoreDistribution summarises score percentages in ten-point buckets
+func NewScoreDistribution(percentages []float64, passMark float64) ScoreDistribution {
+	dist := ScoreDistribution{
+		Count:    len(percentages),
+		PassMark: passMark,
+		Buckets:  make([]ScoreBucket, 10),
+	}
+	for i := range dist.Buckets {
+		dist.Buckets[i] = ScoreBucket{From: i * 10, To: (i + 1) * 10}
+	}
+	if len(percentages) == 0 {
+		return dist
+	}
+
+	sorted := append([]float64(nil), percentages...)
+	sort.Float64s(sorted)
+	dist.Min = sorted[0]
+	dist.Max = sorted[len(sorted)-1]
+	if n := len(sorted); n%2 == 1 {
+		dist.Median = sorted[n/2]
+	} else {
+		dist.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	var sum float64
+	for _, p := range sorted {
+		sum += p
+		if p >= passMark {
+			dist.Passed++
+		}
+		bucket := int(p / 10)
+		if bucket > 9 {
+			bucket = 9
+		}
+		if bucket < 0 {
+			bucket = 0
+		}
+		dist.Buckets[bucket].Count++
+	}
+	dist.Mean = sum / float64(len(sorted))
+
+	var variance float64
+	for _, p := range sorted {
+		variance += (p - dist.Mean) * (p - dist.Mean)
+	}
+	dist.StdDev = math.Sqrt(variance / float64(len(sorted)))
+
+	return dist
+}
+
+// Request/Response models for grade scaling
+
+// ScaleScoresRequest describes the curve to preview or apply
+type ScaleScoresRequest struct {
+	Method    ScalingMethod `json:"method" binding:"required,oneof=linear sqrt"`
+	Slope     *float64      `json:"slope,omitempty" binding:"omitempty,gt=0,max=10"` // Linear only; defaults to 1
+	Intercept float64       `json:"intercept,omitempty" binding:"min=-100,max=100"`  // Linear only
+}
+
+// ScaledResultItem shows one result before and after scaling
+type ScaledResultItem struct {
+	ResultID              primitive.ObjectID `json:"result_id"`
+	UserID                primitive.ObjectID `json:"user_id"`
+	RawFinalScore         int                `json:"raw_final_score"`
+	RawScorePercentage    float64            `json:"raw_score_percentage"`
+	ScaledFinalScore      int                `json:"scaled_final_score"`
+	ScaledScorePercentage float64            `json:"scaled_score_percentage"`
+	TotalPoints           int                `json:"total_points"`
+}
+
+// ScalingResponse compares a sitting's score distribution before and after a curve
+type ScalingResponse struct {
+	SittingID primitive.ObjectID `json:"sitting_id"`
+	Scaling   *ScoreScaling      `json:"scaling"`
+	Before    ScoreDistribution  `json:"before"`
+	After     ScoreDistribution  `json:"after"`
+	Results   []ScaledResultItem `json:"results"`
+	Applied   bool               `json:"applied"`
+	Message   string             `json:"message,omitempty"`
+}
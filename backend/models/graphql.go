@@ -0,0 +1,35 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// GraphQLRequest is the body of POST /api/graphql
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLResponse holds what the query selected, and what went wrong with the fields that could not
+// be resolved. Data is null when the query was rejected before anything ran.
+type GraphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message    string                  `json:"message"`
+	Path       []any                   `json:"path,omitempty"`
+	Extensions *GraphQLErrorExtensions `json:"extensions,omitempty"`
+}
+
+// GraphQLErrorExtensions carries the same error codes as the REST API
+type GraphQLErrorExtensions struct {
+	Code ErrorCode `json:"code"`
+}
+
+// DashboardQuizResultsRequest holds the arguments of a dashboard query for quiz results
+type DashboardQuizResultsRequest struct {
+	UserID   *primitive.ObjectID // Admins only; the viewer's own results otherwise
+	QuizType QuizType
+	Limit    int `binding:"min=1,max=100"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// GuestSessionResponse carries the token an anonymous visitor takes a time quiz with
+type GuestSessionResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	GuestID     string `json:"guest_id"`
+	ExpiresIn   int64  `json:"expires_in"` // seconds
+	Message     string `json:"message"`
+}
+
+// ClaimGuestResultsRequest attaches a guest's quiz sessions and results to the signed-in account
+type ClaimGuestResultsRequest struct {
+	GuestToken string `json:"guest_token" binding:"required"`
+}
+
+// ClaimGuestResultsResponse reports what was moved to the account
+type ClaimGuestResultsResponse struct {
+	ClaimedSessions int64  `json:"claimed_sessions"`
+	ClaimedResults  int64  `json:"claimed_results"`
+	Message         string `json:"message"`
+}
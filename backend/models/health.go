@@ -0,0 +1,42 @@
+package models
+
+// Health statuses: degraded means the server runs but a dependency it needs is failing
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+)
+
+// HealthResponse reports whether the server and the dependencies it needs are working
+type HealthResponse struct {
+	Status    string          `json:"status"`
+	Service   string          `json:"service"`
+	Database  DatabaseHealth  `json:"database"`
+	Queue     QueueHealth     `json:"queue"`
+	Scheduler SchedulerStatus `json:"scheduler"`
+}
+
+type DatabaseHealth struct {
+	Status    string `json:"status"` // connected or unreachable
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// QueueHealth counts work handed off from requests that has not finished yet
+type QueueHealth struct {
+	Pending int            `json:"pending"`
+	ByKind  map[string]int `json:"by_kind"`
+}
+
+// SchedulerStatus describes this instance's part in running background jobs
+type SchedulerStatus struct {
+	Enabled  bool   `json:"enabled"`
+	Instance string `json:"instance"`
+	Leader   bool   `json:"leader"` // Whether this instance runs the jobs
+	Jobs     int    `json:"jobs"`   // Enabled jobs
+}
+
+// ReadinessResponse tells a load balancer or orchestrator whether to send this instance traffic
+type ReadinessResponse struct {
+	Ready  bool   `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IntegrityEventType is a kind of behaviour the quiz client reports for proctoring review
+type IntegrityEventType string
+
+const (
+	IntegrityBlur             IntegrityEventType = "blur"              // The quiz window lost focus
+	IntegrityVisibilityChange IntegrityEventType = "visibility_change" // The tab was hidden, e.g. by switching tabs
+	IntegrityFullscreenExit   IntegrityEventType = "fullscreen_exit"   // The student left fullscreen
+	IntegrityCopy             IntegrityEventType = "copy"
+	IntegrityPaste            IntegrityEventType = "paste"
+	IntegrityDevtools         IntegrityEventType = "devtools" // Developer tools were opened
+)
+
+// SessionIntegrityMaxEvents caps the integrity events kept on a session; later ones are only counted
+const SessionIntegrityMaxEvents = 500
+
+// IntegrityEvent is one client-reported integrity event. They are signals for an admin to review,
+// not proof of misconduct: a notification stealing focus reports a blur too.
+type IntegrityEvent struct {
+	Type          IntegrityEventType `json:"type" bson:"type" binding:"required,oneof=blur visibility_change fullscreen_exit copy paste devtools"`
+	QuestionIndex *int               `json:"question_index,omitempty" bson:"question_index,omitempty" binding:"omitempty,min=0"`
+	DurationMs    int64              `json:"duration_ms,omitempty" bson:"duration_ms,omitempty" binding:"min=0,max=86400000"` // How long focus or visibility was lost, when known
+	Detail        string             `json:"detail,omitempty" bson:"detail,omitempty" binding:"max=200"`
+	OccurredAt    time.Time          `json:"occurred_at" bson:"occurred_at" binding:"required"` // Client clock
+	ReceivedAt    time.Time          `json:"received_at" bson:"received_at"`                    // Set by the server
+}
+
+// IntegritySummary condenses a session's integrity events for admins reviewing its result
+type IntegritySummary struct {
+	TotalEvents int                        `json:"total_events" bson:"total_events"`
+	Counts      map[IntegrityEventType]int `json:"counts" bson:"counts"`
+	AwayMs      int64                      `json:"away_ms" bson:"away_ms"` // Total reported time without focus or visibility
+	FirstAt     *time.Time                 `json:"first_at,omitempty" bson:"first_at,omitempty"`
+	LastAt      *time.Time                 `json:"last_at,omitempty" bson:"last_at,omitempty"`
+	Truncated   bool                       `json:"truncated,omitempty" bson:"truncated,omitempty"` // More events were reported than kept
+}
+
+// SummarizeIntegrity condenses a session's integrity events, or returns nil when there were none
+func SummarizeIntegrity(events []IntegrityEvent, reported int) *IntegritySummary {
+	if len(events) == 0 {
+		return nil
+	}
+
+	summary := &IntegritySummary{
+		TotalEvents: reported,
+		Counts:      make(map[IntegrityEventType]int),
+		Truncated:   reported > len(events),
+	}
+	if summary.TotalEvents < len(events) {
+		summary.TotalEvents = len(events)
+	}
+	for i, event := range events {
+		summary.Counts[event.Type]++
+		if event.Type == IntegrityBlur || event.Type == IntegrityVisibilityChange {
+			summary.AwayMs += event.DurationMs
+		}
+		if summary.FirstAt == nil || event.OccurredAt.Before(*summary.FirstAt) {
+			summary.FirstAt = &events[i].OccurredAt
+		}
+		if summary.LastAt == nil || event.OccurredAt.After(*summary.LastAt) {
+			summary.LastAt = &events[i].OccurredAt
+		}
+	}
+	return summary
+}
+
+// Request/Response models for API
+
+// RecordIntegrityEventsRequest is a batch of integrity events buffered by the quiz client
+type RecordIntegrityEventsRequest struct {
+	Events []IntegrityEvent `json:"events" binding:"required,min=1,max=50,dive"`
+}
+
+// RecordIntegrityEventsResponse confirms how many events were stored
+type RecordIntegrityEventsResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// SessionIntegrityResponse is a session's integrity events for admin review
+type SessionIntegrityResponse struct {
+	SessionID primitive.ObjectID `json:"session_id"`
+	UserID    primitive.ObjectID `json:"user_id"`
+	QuizType  QuizType           `json:"quiz_type"`
+	Status    QuizStatus         `json:"status"`
+	Summary   *IntegritySummary  `json:"summary"`
+	Events    []IntegrityEvent   `json:"events"`
+}
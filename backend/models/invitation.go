@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvitationStatus represents the lifecycle of a registration invitation
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationRevoked  InvitationStatus = "revoked"
+	InvitationExpired  InvitationStatus = "expired" // Pending past its expiry; derived, never stored
+)
+
+// Invitation lets an admin invite someone to register as a specific user type without approval
+type Invitation struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Email    string             `json:"email" bson:"email"`
+	UserType UserType           `json:"user_type" bson:"user_type"` // Registration type: mahasiswa, user or admin
+	Status   InvitationStatus   `json:"status" bson:"status"`
+
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+	EmailSent bool      `json:"email_sent" bson:"email_sent"`
+
+	// Who sent it
+	InvitedBy      primitive.ObjectID `json:"invited_by" bson:"invited_by"`
+	InvitedByEmail string             `json:"invited_by_email" bson:"invited_by_email"`
+
+	// Outcome
+	AcceptedAt *time.Time          `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+	AcceptedBy *primitive.ObjectID `json:"accepted_by,omitempty" bson:"accepted_by,omitempty"` // ID of the account created
+	RevokedAt  *time.Time          `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedBy  *primitive.ObjectID `json:"revoked_by,omitempty" bson:"revoked_by,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// IsUsable reports whether the invitation can still be used to register
+func (i *Invitation) IsUsable() bool {
+	return i.Status == InvitationPending && time.Now().Before(i.ExpiresAt)
+}
+
+// Request/Response models for API
+
+// CreateInvitationRequest represents the request to invite someone to register
+type CreateInvitationRequest struct {
+	Email          string   `json:"email" binding:"required,email"`
+	UserType       UserType `json:"user_type" binding:"required,oneof=mahasiswa user admin"`
+	ExpiresInHours int      `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+// CreateInvitationResponse returns the invitation with its link, which can be shared manually if email fails
+type CreateInvitationResponse struct {
+	Invitation *Invitation `json:"invitation"`
+	InviteURL  string      `json:"invite_url"`
+	Message    string      `json:"message"`
+}
+
+// ListInvitationsRequest represents the request to list invitations
+type ListInvitationsRequest struct {
+	Page   int              `form:"page,default=1" binding:"min=1"`
+	Limit  int              `form:"limit,default=20" binding:"min=1,max=100"`
+	Status InvitationStatus `form:"status" binding:"omitempty,oneof=pending accepted revoked expired"`
+	Email  string           `form:"email"`
+}
+
+// ListInvitationsResponse represents a page of invitations
+type ListInvitationsResponse struct {
+	Invitations []Invitation `json:"invitations"`
+	Total       int64        `json:"total"`
+	Page        int          `json:"page"`
+	Limit       int          `json:"limit"`
+	TotalPages  int          `json:"total_pages"`
+}
+
+// InvitationLookupResponse tells the registration page what an invite link is for
+type InvitationLookupResponse struct {
+	Email     string    `json:"email"`
+	UserType  UserType  `json:"user_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModerationStatus tracks instructor review of a borderline exam result
+type ModerationStatus string
+
+const (
+	ModerationPending   ModerationStatus = "pending"   // Near the pass mark, awaiting review
+	ModerationModerated ModerationStatus = "moderated" // Reviewed; the final score stands
+)
+
+// ResultModeration is the review state of an exam result, kept on the detailed result
+type ResultModeration struct {
+	Status             ModerationStatus  `json:"status" bson:"status"`
+	OriginalScore      int               `json:"original_score" bson:"original_score"` // FinalScore as marked
+	OriginalPercentage float64           `json:"original_percentage" bson:"original_percentage"`
+	FlaggedAt          *time.Time        `json:"flagged_at,omitempty" bson:"flagged_at,omitempty"` // Unset for results adjusted without being flagged
+	ModeratedAt        *time.Time        `json:"moderated_at,omitempty" bson:"moderated_at,omitempty"`
+	Adjustments        []ScoreAdjustment `json:"adjustments" bson:"adjustments"`
+}
+
+// ScoreAdjustment records one change to a result's final score and why it was made
+type ScoreAdjustment struct {
+	PreviousScore  int                `json:"previous_score" bson:"previous_score"`
+	NewScore       int                `json:"new_score" bson:"new_score"`
+	Justification  string             `json:"justification" bson:"justification"`
+	AdjustedBy     primitive.ObjectID `json:"adjusted_by" bson:"adjusted_by"`
+	AdjustedByName string             `json:"adjusted_by_name" bson:"adjusted_by_name"`
+	AdjustedAt     time.Time          `json:"adjusted_at" bson:"adjusted_at"`
+}
+
+// Request/Response models for moderation
+
+// UpdateModerationSettingsRequest sets a sitting's pass mark and the band around it that is reviewed
+type UpdateModerationSettingsRequest struct {
+	PassMark       *float64 `json:"pass_mark" binding:"required,min=0,max=100"`
+	ModerationBand float64  `json:"moderation_band" binding:"min=0,max=50"` // Zero turns moderation off
+}
+
+// ModerationSettingsResponse returns the sitting with how many existing results were flagged or cleared
+type ModerationSettingsResponse struct {
+	Sitting *ExamSitting `json:"sitting"`
+	Flagged int64        `json:"flagged"`
+	Cleared int64        `json:"cleared"` // Pending results now outside the band
+	Message string       `json:"message"`
+}
+
+// ModerationFilter narrows the moderation queue of a sitting
+type ModerationFilter struct {
+	Status ModerationStatus `form:"status" binding:"omitempty,oneof=pending moderated"`
+}
+
+// ModerationQueueItem is a sitting result under moderation
+type ModerationQueueItem struct {
+	ResultID        primitive.ObjectID `json:"result_id"`
+	UserID          primitive.ObjectID `json:"user_id"`
+	FullName        string             `json:"full_name,omitempty"`
+	Email           string             `json:"email,omitempty"`
+	FinalScore      int                `json:"final_score"`
+	TotalPoints     int                `json:"total_points"`
+	ScorePercentage float64            `json:"score_percentage"`
+	SubmittedAt     time.Time          `json:"submitted_at"`
+	Moderation      *ResultModeration  `json:"moderation"`
+	Integrity       *IntegritySummary  `json:"integrity,omitempty"` // Proctoring events reported during the attempt
+}
+
+// ModerationQueueResponse lists a sitting's moderated and pending results
+type ModerationQueueResponse struct {
+	SittingID      primitive.ObjectID    `json:"sitting_id"`
+	PassMark       float64               `json:"pass_mark"`
+	ModerationBand float64               `json:"moderation_band"`
+	Pending        int                   `json:"pending"`
+	Items          []ModerationQueueItem `json:"items"`
+}
+
+// AdjustScoreRequest sets a result's final score; submitting the current score confirms it
+type AdjustScoreRequest struct {
+	FinalScore    *int   `json:"final_score" binding:"required,min=0"`
+	Justification string `json:"justification" binding:"required,min=10,max=2000"`
+}
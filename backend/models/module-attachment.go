@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleAttachment is a file, such as lecture slides or a PDF handout, uploaded to a module or to one
+// of its submodules. The file itself lives in file storage under StorageKey.
+type ModuleAttachment struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ModuleID    primitive.ObjectID  `json:"module_id" bson:"module_id"`
+	SubModuleID *primitive.ObjectID `json:"submodule_id,omitempty" bson:"submodule_id,omitempty"` // Unset for the module itself
+
+	FileName    string `json:"file_name" bson:"file_name"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	StorageKey  string `json:"-" bson:"storage_key"`
+
+	UploadedBy primitive.ObjectID `json:"uploaded_by" bson:"uploaded_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Request/Response models for API
+
+// ModuleAttachmentsResponse lists the attachments of a module and its submodules, oldest first
+type ModuleAttachmentsResponse struct {
+	ModuleID    primitive.ObjectID `json:"module_id"`
+	Attachments []ModuleAttachment `json:"attachments"`
+	Total       int                `json:"total"`
+}
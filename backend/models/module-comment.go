@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleCommentReportReason is why a learner reported a discussion comment
+type ModuleCommentReportReason string
+
+const (
+	CommentReportSpam     ModuleCommentReportReason = "spam"
+	CommentReportAbuse    ModuleCommentReportReason = "abuse" // Harassment, hate or otherwise abusive
+	CommentReportOffTopic ModuleCommentReportReason = "off_topic"
+	CommentReportOther    ModuleCommentReportReason = "other"
+)
+
+// ModuleComment is a question or reply in the discussion of a submodule. Replies answer a top-level
+// comment and are not nested further.
+type ModuleComment struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ModuleID    primitive.ObjectID  `json:"module_id" bson:"module_id"`
+	SubModuleID primitive.ObjectID  `json:"submodule_id" bson:"submodule_id"`
+	ParentID    *primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"` // Unset for top-level comments
+
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	FromAdmin  bool               `json:"from_admin,omitempty" bson:"from_admin,omitempty"` // Answered by an instructor
+	Body       string             `json:"body" bson:"body"`
+
+	// Top-level comments only: the number of replies, and in responses the replies themselves, oldest first
+	ReplyCount int             `json:"reply_count" bson:"reply_count"`
+	Replies    []ModuleComment `json:"replies,omitempty" bson:"-"`
+
+	// Moderation, shown to admins only. A removed comment keeps its place in the thread without its body.
+	Reports     []ModuleCommentReport `json:"reports,omitempty" bson:"reports,omitempty"`
+	ReportCount int                   `json:"report_count,omitempty" bson:"report_count"`
+	Removed     bool                  `json:"removed,omitempty" bson:"removed,omitempty"`
+	RemovedBy   *primitive.ObjectID   `json:"removed_by,omitempty" bson:"removed_by,omitempty"`
+	RemovedAt   *time.Time            `json:"removed_at,omitempty" bson:"removed_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ModuleCommentReport is one learner's report of an abusive comment
+type ModuleCommentReport struct {
+	UserID    primitive.ObjectID        `json:"user_id" bson:"user_id"`
+	Reason    ModuleCommentReportReason `json:"reason" bson:"reason"`
+	Comment   string                    `json:"comment,omitempty" bson:"comment,omitempty"`
+	CreatedAt time.Time                 `json:"created_at" bson:"created_at"`
+}
+
+// Request/Response models for API
+
+// ListModuleCommentsRequest pages through the top-level comments of a discussion, newest first
+type ListModuleCommentsRequest struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=50"`
+}
+
+// PostModuleCommentRequest asks a question, or replies to one when ParentID is set
+type PostModuleCommentRequest struct {
+	Body     string              `json:"body" binding:"required,min=1,max=5000"`
+	ParentID *primitive.ObjectID `json:"parent_id,omitempty"`
+}
+
+// ReportModuleCommentRequest reports a comment for an admin to review
+type ReportModuleCommentRequest struct {
+	Reason  ModuleCommentReportReason `json:"reason" binding:"required,oneof=spam abuse off_topic other"`
+	Comment string                    `json:"comment,omitempty" binding:"max=500"`
+}
+
+// ListReportedModuleCommentsRequest pages through the comments awaiting moderation, most reported first
+type ListReportedModuleCommentsRequest struct {
+	Page  int `form:"page,default=1" binding:"min=1"`
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// ModuleCommentsResponse is a page of a discussion or of the moderation queue
+type ModuleCommentsResponse struct {
+	Comments   []ModuleComment `json:"comments"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "io"
+
+// LearningPackageFormat is the standard a module export is packaged to, for loading into an LMS
+type LearningPackageFormat string
+
+const (
+	PackageSCORM12 LearningPackageFormat = "scorm12" // One SCO per page, completed when opened
+	PackageXAPI    LearningPackageFormat = "xapi"    // tincan.xml launch, pages report to the LMS's LRS
+)
+
+// Request/Response models for API
+
+// ExportModuleRequest represents the query of a module export
+type ExportModuleRequest struct {
+	Format LearningPackageFormat `form:"format,default=scorm12" binding:"oneof=scorm12 xapi"`
+}
+
+// LearningPackage is a published module laid out as standalone pages, ready to be written in an LMS
+// package format. Pages are in reading order, the module's own page first.
+type LearningPackage struct {
+	ID          string // IRI of the module, which xAPI statements name as the activity
+	Identifier  string // Manifest identifier, unique per module
+	Title       string
+	Description string
+	Pages       []LearningPage
+	Assets      []LearningAsset
+}
+
+// LearningPage is one page of a learning package, with its content rendered to HTML
+type LearningPage struct {
+	ID       string // IRI of the page's module or submodule
+	FileName string
+	Title    string
+	Depth    int // 0 for the module's page, 1 for its top-level submodules and so on
+	HTML     string
+}
+
+// LearningAsset is a file pages link to, such as an uploaded image, opened when the package is written
+type LearningAsset struct {
+	Path string
+	Open func() (io.ReadCloser, error)
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleImage is an image shown in module content, uploaded with it rather than linked from elsewhere.
+// The file lives in file storage under StorageKey and is served at URL.
+type ModuleImage struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ModuleID primitive.ObjectID `json:"module_id" bson:"module_id"`
+
+	FileName    string `json:"file_name" bson:"file_name"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	Size        int64  `json:"size" bson:"size"`
+	StorageKey  string `json:"-" bson:"storage_key"`
+	URL         string `json:"url" bson:"url"`
+
+	UploadedBy primitive.ObjectID `json:"uploaded_by" bson:"uploaded_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Request/Response models for API
+
+// ImportModuleRequest represents the multipart form options sent with a module bundle: a zip of
+// markdown files that each start with front-matter between --- lines:
+//
+//	---
+//	title: Sorting algorithms
+//	description: Comparison sorts and their running times
+//	order: 2
+//	---
+//
+// title is required, unless the page opens with a # heading, which then becomes the title. order
+// places a page among its siblings; pages without one follow in file name order. index.md at the
+// root of the bundle is the module, and every other page a submodule. A folder's index.md is a
+// submodule with the folder's other pages, and its subfolders, nested under it. Images (PNG, JPEG,
+// GIF or WebP) are linked from pages by their path relative to the page, as in
+// ![Call tree](images/call-tree.png), and are uploaded with the module. A single folder wrapping the
+// whole bundle, as zipping a folder makes, is looked through.
+type ImportModuleRequest struct {
+	DryRun bool `form:"dry_run"` // Check the bundle without creating the module
+}
+
+// ModuleImportPage reports what one markdown file of a bundle becomes
+type ModuleImportPage struct {
+	Path        string              `json:"path"`
+	Name        string              `json:"name"`
+	SubModuleID *primitive.ObjectID `json:"submodule_id,omitempty"` // Unset for the module's own page
+	ParentPath  string              `json:"parent_path,omitempty"`  // Page of the submodule it nests under
+	Order       int                 `json:"order"`
+	Images      int                 `json:"images"`
+}
+
+// ModuleImportProblem is why a bundle cannot be imported, by file where there is one
+type ModuleImportProblem struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportModuleResponse summarizes a module import. The module is only created when there are no
+// problems, and never on a dry run.
+type ImportModuleResponse struct {
+	DryRun   bool                  `json:"dry_run"`
+	Module   *Module               `json:"module,omitempty"`
+	Pages    []ModuleImportPage    `json:"pages"`
+	Images   int                   `json:"images"` // Images uploaded, or a dry run would upload
+	Problems []ModuleImportProblem `json:"problems,omitempty"`
+	Warnings []string              `json:"warnings,omitempty"` // Files skipped
+}
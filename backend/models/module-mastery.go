@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleMastery is how well a student answers the questions assessing one module, as a percentage of
+// the points available. Recent quizzes weigh more, so the figure follows the student's progress.
+type ModuleMastery struct {
+	ModuleID        primitive.ObjectID `json:"module_id"`
+	ModuleName      string             `json:"module_name"`
+	Mastery         float64            `json:"mastery"`
+	Questions       int                `json:"questions"` // Questions answered or skipped across the quizzes counted
+	Correct         int                `json:"correct"`
+	LastPracticedAt time.Time          `json:"last_practiced_at"`
+}
+
+// ModuleMasteryReport is a student's mastery of every module their quiz history covers, weakest first,
+// with the modules recommended for study
+type ModuleMasteryReport struct {
+	Modules         []ModuleMastery `json:"modules"`
+	Weakest         []ModuleMastery `json:"weakest"` // Published modules with enough answers to judge, lowest mastery first
+	ResultsAnalyzed int             `json:"results_analyzed"`
+}
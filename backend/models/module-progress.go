@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContentRef points at a module, or at one of its submodules when SubModuleID is set
+type ContentRef struct {
+	ModuleID    primitive.ObjectID  `json:"module_id" bson:"module_id" binding:"required"`
+	SubModuleID *primitive.ObjectID `json:"submodule_id,omitempty" bson:"submodule_id,omitempty"`
+}
+
+// ModuleProgress is what a learner has completed of a module. Unlocked is an admin's override letting
+// the learner in without the module's prerequisites.
+type ModuleProgress struct {
+	ID                  primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	UserID              primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	ModuleID            primitive.ObjectID   `json:"module_id" bson:"module_id"`
+	CompletedSubModules []primitive.ObjectID `json:"completed_submodules" bson:"completed_submodules"`
+	Completed           bool                 `json:"completed" bson:"completed"`
+	CompletedAt         *time.Time           `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	Unlocked            bool                 `json:"unlocked" bson:"unlocked"`
+	UnlockedBy          *primitive.ObjectID  `json:"unlocked_by,omitempty" bson:"unlocked_by,omitempty"`
+	LastPosition        *ReadingPosition     `json:"last_position,omitempty" bson:"last_position,omitempty"`
+	UpdatedAt           time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// ReadingPosition is where a learner last was in a module, so they can continue from there
+type ReadingPosition struct {
+	SubModuleID   *primitive.ObjectID `json:"submodule_id,omitempty" bson:"submodule_id,omitempty"` // Unset for the module page
+	BlockIndex    int                 `json:"block_index" bson:"block_index"`                       // Content block in view, from 0
+	ScrollPercent float64             `json:"scroll_percent" bson:"scroll_percent"`                 // How far down the page
+	UpdatedAt     time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// HasCompleted reports whether the progress covers the content ref points at
+func (p *ModuleProgress) HasCompleted(ref ContentRef) bool {
+	if p == nil {
+		return false
+	}
+	if ref.SubModuleID == nil {
+		return p.Completed
+	}
+	for _, id := range p.CompletedSubModules {
+		if id == *ref.SubModuleID {
+			return true
+		}
+	}
+	return false
+}
+
+// UnlockModuleRequest sets or lifts an admin's override of a module's prerequisites for one learner
+type UnlockModuleRequest struct {
+	Unlocked *bool `json:"unlocked" binding:"required"`
+}
+
+// SaveReadingPositionRequest is sent by the client as the learner reads, to resume from later
+type SaveReadingPositionRequest struct {
+	SubModuleID   *primitive.ObjectID `json:"submodule_id,omitempty"`
+	BlockIndex    int                 `json:"block_index" binding:"min=0"`
+	ScrollPercent float64             `json:"scroll_percent" binding:"min=0,max=100"`
+}
+
+// ContinueReadingRequest limits the modules offered to continue
+type ContinueReadingRequest struct {
+	Limit int `form:"limit,default=5" binding:"min=1,max=20"`
+}
+
+// ContinueReadingItem is a module the learner started and has not completed, with where they were and
+// about how long the rest takes to read
+type ContinueReadingItem struct {
+	ModuleID      primitive.ObjectID  `json:"module_id"`
+	ModuleName    string              `json:"module_name"`
+	SubModuleID   *primitive.ObjectID `json:"submodule_id,omitempty"`
+	SubModuleName string              `json:"submodule_name,omitempty"`
+	Position      ReadingPosition     `json:"position"`
+	MinutesLeft   int                 `json:"minutes_left"`
+}
+
+type ContinueReadingResponse struct {
+	Items []ContinueReadingItem `json:"items"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleRevision is an immutable copy of a module's content as it stood after one edit. Revisions are
+// numbered per module from 1; rolling back adds a new revision rather than removing later ones.
+type ModuleRevision struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ModuleID primitive.ObjectID `json:"module_id" bson:"module_id"`
+	Revision int                `json:"revision" bson:"revision"`
+
+	// Left out when revisions are listed
+	Snapshot *Module `json:"snapshot,omitempty" bson:"snapshot,omitempty"`
+
+	ChangedFields []string `json:"changed_fields,omitempty" bson:"changed_fields,omitempty"` // Against the previous revision
+	RestoredFrom  int      `json:"restored_from,omitempty" bson:"restored_from,omitempty"`   // Set when the edit was a rollback
+
+	// Who made the edit
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name,omitempty" bson:"-"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// ModuleFieldChange is one field that differs between two revisions. Text fields also come as a line
+// diff; sub_modules.<id>.<field> names a field of one submodule.
+type ModuleFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+	Lines []DiffLine  `json:"lines,omitempty"`
+}
+
+// DiffLine is one line of a line diff: "=" kept, "-" removed or "+" added
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Request/Response models for API
+
+// ModuleRevisionsResponse lists a module's revisions, newest first
+type ModuleRevisionsResponse struct {
+	ModuleID        primitive.ObjectID `json:"module_id"`
+	CurrentRevision int                `json:"current_revision"`
+	Revisions       []ModuleRevision   `json:"revisions"`
+	Total           int                `json:"total"`
+}
+
+// ModuleRevisionDiffRequest picks the revisions to compare; To defaults to the current revision
+type ModuleRevisionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"omitempty,min=1"`
+}
+
+// ModuleRevisionDiff is what changed from one revision of a module to another
+type ModuleRevisionDiff struct {
+	ModuleID primitive.ObjectID  `json:"module_id"`
+	From     int                 `json:"from"`
+	To       int                 `json:"to"`
+	Changes  []ModuleFieldChange `json:"changes"`
+}
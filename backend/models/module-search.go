@@ -0,0 +1,41 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ModuleSearchRequest searches module and submodule names, descriptions and content
+type ModuleSearchRequest struct {
+	Query     string `form:"q" binding:"required,max=200"`
+	Published *bool  `form:"published"` // Admins only; learners only ever find published content
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	Limit     int    `form:"limit,default=10" binding:"min=1,max=50"`
+}
+
+// ModuleSearchResult is a module that matched a search, with where it matched
+type ModuleSearchResult struct {
+	ModuleID    primitive.ObjectID      `json:"module_id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	IsPublished bool                    `json:"is_published"`
+	Locked      bool                    `json:"locked,omitempty"` // Learners only
+	Score       float64                 `json:"score,omitempty"`  // Relevance from the text index
+	Highlights  []ModuleSearchHighlight `json:"highlights"`
+}
+
+// ModuleSearchHighlight is a piece of a module, or one of its submodules, where the search terms matched
+type ModuleSearchHighlight struct {
+	Field   string `json:"field"`   // name, description or content
+	Snippet string `json:"snippet"` // HTML-escaped text with the matches wrapped in <mark>
+
+	// Set when the match is in a submodule, with the path down to it
+	SubModuleID *primitive.ObjectID `json:"submodule_id,omitempty"`
+	Breadcrumbs []Breadcrumb        `json:"breadcrumbs,omitempty"`
+}
+
+type ModuleSearchResponse struct {
+	Results    []ModuleSearchResult `json:"results"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+	SearchMode QuestionSearchMode   `json:"search_mode"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModuleView is one learner reading a module page, or one of its submodules, for some time
+type ModuleView struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ModuleID    primitive.ObjectID  `json:"module_id" bson:"module_id"`
+	SubModuleID *primitive.ObjectID `json:"submodule_id,omitempty" bson:"submodule_id,omitempty"` // Unset for the module page itself
+	UserID      primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	IsGuest     bool                `json:"is_guest,omitempty" bson:"is_guest,omitempty"`
+
+	DurationSeconds int       `json:"duration_seconds" bson:"duration_seconds"`
+	ViewedAt        time.Time `json:"viewed_at" bson:"viewed_at"`
+}
+
+// ModuleViewStats are the views of a module, a submodule or one period
+type ModuleViewStats struct {
+	Views           int     `json:"views" bson:"views"`
+	UniqueViewers   int     `json:"unique_viewers" bson:"unique_viewers"`
+	AverageDuration float64 `json:"average_duration_seconds" bson:"average_duration"`
+}
+
+// ModuleViewPeriod is the views in one day or week
+type ModuleViewPeriod struct {
+	Period          string `json:"period" bson:"_id"` // YYYY-MM-DD, or YYYY-Www for weeks
+	ModuleViewStats `bson:",inline"`
+}
+
+// SubModuleViewStats is how far into a module its viewers read. Reach is the share of the module's
+// viewers who opened the submodule; DropOff is the share of the previous submodule's viewers, in
+// reading order, who did not.
+type SubModuleViewStats struct {
+	SubModuleID primitive.ObjectID `json:"submodule_id"`
+	Name        string             `json:"name"`
+	Depth       int                `json:"depth"`
+	IsPublished bool               `json:"is_published"`
+	ModuleViewStats
+
+	Reach   float64 `json:"reach"`
+	DropOff float64 `json:"drop_off"`
+}
+
+// Request/Response models for API
+
+// RecordModuleViewRequest is sent by the client when a learner leaves a module or submodule page
+type RecordModuleViewRequest struct {
+	SubModuleID     *primitive.ObjectID `json:"submodule_id,omitempty"`
+	DurationSeconds int                 `json:"duration_seconds" binding:"min=0,max=86400"`
+}
+
+// ModuleAnalyticsRequest picks the days to analyze, the last 30 by default
+type ModuleAnalyticsRequest struct {
+	DateFrom string `form:"date_from"` // YYYY-MM-DD
+	DateTo   string `form:"date_to"`
+	Interval string `form:"interval" binding:"omitempty,oneof=day week"` // Periods of views_over_time; day by default
+}
+
+// ModuleAnalytics is who reads a module and how far they get
+type ModuleAnalytics struct {
+	ModuleID   primitive.ObjectID `json:"module_id"`
+	ModuleName string             `json:"module_name"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"` // Exclusive
+	Interval   string             `json:"interval"`
+
+	// Views of the module page and its submodules together; a viewer of several counts once
+	ModuleViewStats
+	PageViews int `json:"page_views"` // Views of the module page itself
+
+	ViewsOverTime []ModuleViewPeriod   `json:"views_over_time"`
+	SubModules    []SubModuleViewStats `json:"sub_modules"` // In reading order
+	Unread        []Breadcrumb         `json:"unread"`      // Published submodules nobody viewed in the range
+}
+
+// ModuleViewSummary is what the view aggregation returns for one module and range
+type ModuleViewSummary struct {
+	Totals     ModuleViewStats
+	PageViews  int
+	Periods    []ModuleViewPeriod
+	SubModules map[primitive.ObjectID]ModuleViewStats
+}
@@ -11,10 +11,34 @@ type Module struct {
 	Name        string             `json:"name" bson:"name" binding:"required,min=1,max=200"`
 	Description string             `json:"description" bson:"description" binding:"max=500"`
 	SubModules  []SubModule        `json:"sub_modules" bson:"sub_modules"`
-	Content     string             `json:"content" bson:"content"`           // Markdown content
+	Content     string             `json:"content" bson:"content"`           // Markdown rendition of Blocks; the only content of modules written before blocks
 	IsPublished bool               `json:"is_published" bson:"is_published"` // Publication status
 	Order       int                `json:"order" bson:"order"`               // Display order (for sorting)
 
+	// The content, block by block in display order
+	Blocks []ContentBlock `json:"blocks" bson:"blocks,omitempty"`
+
+	// Content a learner must complete first; until then the content is locked for them
+	Prerequisites []ContentRef `json:"prerequisites,omitempty" bson:"prerequisites,omitempty"`
+
+	// Learner views only: whether the viewer may read the content yet, and what is left to complete
+	Locked               bool         `json:"locked,omitempty" bson:"-"`
+	MissingPrerequisites []ContentRef `json:"missing_prerequisites,omitempty" bson:"-"`
+	Completed            bool         `json:"completed,omitempty" bson:"-"`
+
+	// Learner views only: where the viewer last was in the module, to resume from
+	LastPosition *ReadingPosition `json:"last_position,omitempty" bson:"-"`
+
+	// Responses only: estimated minutes to read the module's page, and it with its published submodules
+	ReadingMinutes      int `json:"reading_minutes" bson:"-"`
+	TotalReadingMinutes int `json:"total_reading_minutes" bson:"-"`
+
+	// First time the content was published; unpublishing keeps it
+	PublishedAt *time.Time `json:"published_at,omitempty" bson:"published_at,omitempty"`
+
+	// Search results only: relevance from the text index
+	SearchScore float64 `json:"-" bson:"search_score,omitempty"`
+
 	// Metadata
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
@@ -26,10 +50,38 @@ type SubModule struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name        string             `json:"name" bson:"name" binding:"required,min=1,max=200"`
 	Description string             `json:"description" bson:"description" binding:"max=500"`
-	Content     string             `json:"content" bson:"content"`           // Markdown content
+	Content     string             `json:"content" bson:"content"`           // Markdown rendition of Blocks; the only content of modules written before blocks
 	IsPublished bool               `json:"is_published" bson:"is_published"` // Publication status
 	Order       int                `json:"order" bson:"order"`               // Display order (for sorting)
 
+	// The content, block by block in display order
+	Blocks []ContentBlock `json:"blocks" bson:"blocks,omitempty"`
+
+	// Content a learner must complete first; until then the content is locked for them
+	Prerequisites []ContentRef `json:"prerequisites,omitempty" bson:"prerequisites,omitempty"`
+
+	// Submodule this one nests under; nil places it directly in the module. Order sorts it among the
+	// submodules sharing its parent.
+	ParentID *primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+
+	// Whether learners who can read the submodule may discuss it; off until an admin opens it
+	DiscussionEnabled bool `json:"discussion_enabled" bson:"discussion_enabled,omitempty"`
+
+	// Responses only: how deeply the submodule nests, from 1, and the path down to it from the module
+	Depth       int          `json:"depth" bson:"-"`
+	Breadcrumbs []Breadcrumb `json:"breadcrumbs" bson:"-"`
+
+	// Responses only: estimated minutes to read the submodule
+	ReadingMinutes int `json:"reading_minutes" bson:"-"`
+
+	// Learner views only: whether the viewer may read the content yet, and what is left to complete
+	Locked               bool         `json:"locked,omitempty" bson:"-"`
+	MissingPrerequisites []ContentRef `json:"missing_prerequisites,omitempty" bson:"-"`
+	Completed            bool         `json:"completed,omitempty" bson:"-"`
+
+	// First time the content was published; unpublishing keeps it
+	PublishedAt *time.Time `json:"published_at,omitempty" bson:"published_at,omitempty"`
+
 	// Metadata
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
@@ -41,24 +93,40 @@ type SubModule struct {
 type CreateModuleRequest struct {
 	Name        string      `json:"name" binding:"required,min=1,max=200"`
 	Description string      `json:"description" binding:"max=500"`
-	Content     string      `json:"content" binding:"required"`
-	Order       int         `json:"order,omitempty"` // Optional order field
+	Content     string      `json:"content,omitempty"` // Deprecated: sent as a single markdown block when Blocks is empty
+	Order       int         `json:"order,omitempty"`   // Optional order field
 	SubModules  []SubModule `json:"sub_modules,omitempty"`
+
+	Blocks []ContentBlock `json:"blocks,omitempty" binding:"omitempty,max=200,dive"`
+
+	Prerequisites []ContentRef `json:"prerequisites,omitempty" binding:"omitempty,max=50,dive"`
 }
 
 type UpdateModuleRequest struct {
 	Name        *string     `json:"name,omitempty" binding:"omitempty,min=1,max=200"`
 	Description *string     `json:"description,omitempty" binding:"omitempty,max=500"`
-	Content     *string     `json:"content,omitempty"`
-	Order       *int        `json:"order,omitempty"` // Optional order field
+	Content     *string     `json:"content,omitempty"` // Deprecated: replaces the blocks with a single markdown block
+	Order       *int        `json:"order,omitempty"`   // Optional order field
 	SubModules  []SubModule `json:"sub_modules,omitempty"`
+
+	Blocks []ContentBlock `json:"blocks,omitempty" binding:"omitempty,max=200,dive"` // Replaces every block
+
+	Prerequisites []ContentRef `json:"prerequisites,omitempty" binding:"omitempty,max=50,dive"` // An empty list removes every prerequisite
 }
 
 type CreateSubModuleRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=200"`
 	Description string `json:"description" binding:"max=500"`
-	Content     string `json:"content" binding:"required"`
-	Order       int    `json:"order,omitempty"` // Optional order field
+	Content     string `json:"content,omitempty"` // Deprecated: sent as a single markdown block when Blocks is empty
+	Order       int    `json:"order,omitempty"`   // Optional order field
+
+	Blocks []ContentBlock `json:"blocks,omitempty" binding:"omitempty,max=200,dive"`
+
+	// Left out, an update keeps the submodule's prerequisites; an empty list removes them
+	Prerequisites []ContentRef `json:"prerequisites,omitempty" binding:"omitempty,max=50,dive"`
+
+	// Submodule to create this one under; updates ignore it, moving is done on its own
+	ParentID *primitive.ObjectID `json:"parent_id,omitempty"`
 }
 
 // Additional request/response models for API
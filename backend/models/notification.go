@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationType represents what a notification is about
+type NotificationType string
+
+const (
+	NotificationThreadMessage  NotificationType = "thread_message"   // New message in a result discussion thread
+	NotificationAtRiskOutreach NotificationType = "at_risk_outreach" // Check-in sent to a student flagged as at risk
+	NotificationProposalReview NotificationType = "proposal_review"  // A question the student proposed was accepted or rejected
+	NotificationEssaysGraded   NotificationType = "essays_graded"    // Every essay of a quiz result has been graded
+	NotificationCommentMention NotificationType = "comment_mention"  // An admin was mentioned in a question comment
+	NotificationModuleReply    NotificationType = "discussion_reply" // New reply in a submodule discussion the user took part in
+)
+
+// Notification is an in-platform message shown to a single user
+type Notification struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID  primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Type    NotificationType   `json:"type" bson:"type"`
+	Title   string             `json:"title" bson:"title"`
+	Message string             `json:"message" bson:"message"`
+
+	// What the notification links to
+	EntityType string `json:"entity_type,omitempty" bson:"entity_type,omitempty"`
+	EntityID   string `json:"entity_id,omitempty" bson:"entity_id,omitempty"`
+
+	ReadAt    *time.Time `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+}
+
+// Request/Response models for API
+
+// ListNotificationsRequest represents query parameters for listing notifications
+type ListNotificationsRequest struct {
+	Page       int  `form:"page,default=1" binding:"min=1"`
+	Limit      int  `form:"limit,default=20" binding:"min=1,max=100"`
+	UnreadOnly bool `form:"unread_only"`
+}
+
+// ListNotificationsResponse represents a page of notifications with the unread total
+type ListNotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Total         int64          `json:"total"`
+	UnreadCount   int64          `json:"unread_count"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+	TotalPages    int            `json:"total_pages"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericResponse splits a typed answer into its number and an optional trailing unit, such as "9.81 m/s2"
+var numericResponse = regexp.MustCompile(`^([+-]?(?:\d[\d.,]*|[.,]\d+)(?:[eE][+-]?\d+)?)\s*(.*)$`)
+
+// NumericAnswer is the correct answer of a numeric question. A response is accepted when it is within
+// the larger of the two tolerances of Value; with neither set it must equal Value.
+type NumericAnswer struct {
+	Value             float64 `json:"value" bson:"value"`
+	AbsoluteTolerance float64 `json:"absolute_tolerance,omitempty" bson:"absolute_tolerance,omitempty" binding:"min=0"`
+	RelativeTolerance float64 `json:"relative_tolerance,omitempty" bson:"relative_tolerance,omitempty" binding:"min=0,max=1"` // Fraction of Value, e.g. 0.05 for 5%
+	Unit              string  `json:"unit,omitempty" bson:"unit,omitempty" binding:"max=20"`                                  // Shown after the input; a response may repeat it but not name another unit
+}
+
+// ParseNumber reads a number typed by a student. Either "." or "," may be the decimal separator;
+// when both appear the last one is, and the other groups thousands, so "1.234,5" and "1,234.5" agree.
+// A separator repeated on its own only groups thousands, as in "1.234.567" or "1,234,567".
+func ParseNumber(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	lastDot, lastComma := strings.LastIndex(text, "."), strings.LastIndex(text, ",")
+
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			text = strings.ReplaceAll(text, ".", "")
+			text = strings.Replace(text, ",", ".", 1)
+		} else {
+			text = strings.ReplaceAll(text, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(text, ",") > 1 {
+			text = strings.ReplaceAll(text, ",", "")
+		} else {
+			text = strings.Replace(text, ",", ".", 1)
+		}
+	case lastDot >= 0:
+		if strings.Count(text, ".") > 1 {
+			text = strings.ReplaceAll(text, ".", "")
+		}
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// NumericResponse reads a numeric answer, typed as text with an optional unit or sent as a JSON number
+func NumericResponse(answer interface{}) (value float64, unit string, ok bool) {
+	switch v := answer.(type) {
+	case float64:
+		return v, "", !math.IsNaN(v) && !math.IsInf(v, 0)
+	case float32:
+		value := float64(v)
+		return value, "", !math.IsNaN(value) && !math.IsInf(value, 0)
+	case int:
+		return float64(v), "", true
+	case int32:
+		return float64(v), "", true
+	case int64:
+		return float64(v), "", true
+	case json.Number:
+		value, err := v.Float64()
+		return value, "", err == nil
+	case string:
+		match := numericResponse.FindStringSubmatch(strings.TrimSpace(v))
+		if match == nil {
+			return 0, "", false
+		}
+		value, ok := ParseNumber(match[1])
+		return value, strings.TrimSpace(match[2]), ok
+	}
+	return 0, "", false
+}
+
+// Accepts reports whether an answer is within tolerance and names no unit other than the expected one
+func (n NumericAnswer) Accepts(answer interface{}) bool {
+	value, unit, ok := NumericResponse(answer)
+	if !ok {
+		return false
+	}
+	// Units compare exactly apart from spacing, since case separates units like mV and MV
+	if unit != "" && strings.Join(strings.Fields(unit), "") != strings.Join(strings.Fields(n.Unit), "") {
+		return false
+	}
+
+	tolerance := math.Max(n.AbsoluteTolerance, n.RelativeTolerance*math.Abs(n.Value))
+	// Absorb float rounding so an exact answer such as 0.3 matches 0.1+0.2
+	tolerance += 1e-9 * math.Max(1, math.Abs(n.Value))
+	return math.Abs(value-n.Value) <= tolerance
+}
+
+// String renders the answer for result review and study material, e.g. "9.81 m/s2 (± 0.05)"
+func (n NumericAnswer) String() string {
+	text := strconv.FormatFloat(n.Value, 'f', -1, 64)
+	if n.Unit != "" {
+		text += " " + n.Unit
+	}
+	switch {
+	case n.AbsoluteTolerance > 0 && n.AbsoluteTolerance >= n.RelativeTolerance*math.Abs(n.Value):
+		text += " (± " + strconv.FormatFloat(n.AbsoluteTolerance, 'f', -1, 64) + ")"
+	case n.RelativeTolerance > 0:
+		text += " (± " + strconv.FormatFloat(n.RelativeTolerance*100, 'f', -1, 64) + "%)"
+	}
+	return text
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestNumericAnswerAccepts(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer NumericAnswer
+		given  interface{}
+		want   bool
+	}{
+		{"exact", NumericAnswer{Value: 42}, "42", true},
+		{"off by one", NumericAnswer{Value: 42}, "43", false},
+		{"absolute tolerance edge", NumericAnswer{Value: 9.81, AbsoluteTolerance: 0.05}, "9.86", true},
+		{"past absolute tolerance", NumericAnswer{Value: 9.81, AbsoluteTolerance: 0.05}, "9.87", false},
+
+		// Relative tolerance is a share of the value's size, whichever its sign
+		{"relative on a negative value, above", NumericAnswer{Value: -100, RelativeTolerance: 0.05}, "-95", true},
+		{"relative on a negative value, below", NumericAnswer{Value: -100, RelativeTolerance: 0.05}, "-105", true},
+		{"past relative on a negative value", NumericAnswer{Value: -100, RelativeTolerance: 0.05}, "-105.01", false},
+		{"relative on a negative value, wrong sign", NumericAnswer{Value: -100, RelativeTolerance: 0.05}, "100", false},
+		{"larger tolerance wins", NumericAnswer{Value: -100, AbsoluteTolerance: 1, RelativeTolerance: 0.05}, "-104", true},
+		{"larger tolerance wins, absolute", NumericAnswer{Value: 10, AbsoluteTolerance: 2, RelativeTolerance: 0.01}, "11.9", true},
+
+		// 1e-9 of the value, at least 1e-9, absorbs float rounding
+		{"float rounding", NumericAnswer{Value: 0.3}, 0.1 + 0.2, true},
+		{"slack around zero", NumericAnswer{Value: 0}, "0.0000000005", true},
+		{"past the slack around zero", NumericAnswer{Value: 0}, "0.000000002", false},
+		{"slack scales with the value", NumericAnswer{Value: 1e6}, "1000000.0005", true},
+		{"past the scaled slack", NumericAnswer{Value: 1e6}, "1000000.002", false},
+
+		{"expected unit", NumericAnswer{Value: 9.81, Unit: "m/s2"}, "9.81 m/s2", true},
+		{"unit left out", NumericAnswer{Value: 9.81, Unit: "m/s2"}, "9.81", true},
+		{"unit without a space", NumericAnswer{Value: 9.81, Unit: "m/s2"}, "9.81m/s2", true},
+		{"unit spaced differently", NumericAnswer{Value: 9.81, Unit: "m/s2"}, "9.81  m / s2", true},
+		{"unit spaced in the answer key", NumericAnswer{Value: 9.81, Unit: "m / s2"}, "9.81 m/s2", true},
+		{"other unit", NumericAnswer{Value: 9.81, Unit: "m/s2"}, "9.81 km/h", false},
+		{"unit case matters", NumericAnswer{Value: 5, Unit: "mV"}, "5 MV", false},
+		{"unit where none is expected", NumericAnswer{Value: 5}, "5 kg", false},
+
+		{"decimal comma", NumericAnswer{Value: 1.5}, "1,5", true},
+		{"thousands separators", NumericAnswer{Value: 1234.5}, "1.234,5", true},
+		{"repeated thousands dots", NumericAnswer{Value: 1234567}, "1.234.567", true},
+		{"exponent", NumericAnswer{Value: 6.02e23, RelativeTolerance: 0.01}, "6.02e23", true},
+		{"JSON number", NumericAnswer{Value: 42}, float64(42), true},
+		{"json.Number", NumericAnswer{Value: 42}, json.Number("42"), true},
+		{"integer", NumericAnswer{Value: 42}, 42, true},
+		{"not a number", NumericAnswer{Value: 42}, "forty-two", false},
+		{"NaN", NumericAnswer{Value: 42}, math.NaN(), false},
+		{"float32 NaN", NumericAnswer{Value: 42}, float32(math.NaN()), false},
+		{"float32 infinity", NumericAnswer{Value: 42}, float32(math.Inf(1)), false},
+		{"no answer", NumericAnswer{Value: 0}, nil, false},
+		{"empty answer", NumericAnswer{Value: 0}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.answer.Accepts(tt.given); got != tt.want {
+				t.Errorf("%+v.Accepts(%v) = %v, want %v", tt.answer, tt.given, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+		ok   bool
+	}{
+		{"42", 42, true},
+		{" -3.5 ", -3.5, true},
+		{"1,5", 1.5, true},
+		{"1.234,5", 1234.5, true},
+		{"1,234.5", 1234.5, true},
+		{"1,234,567", 1234567, true},
+		{"1.234.567", 1234567, true},
+		{"1.234.567,5", 1234567.5, true},
+		{"1e-3", 0.001, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"NaN", 0, false},
+		{"Inf", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseNumber(tt.text)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseNumber(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNumericAnswerString(t *testing.T) {
+	tests := []struct {
+		answer NumericAnswer
+		want   string
+	}{
+		{NumericAnswer{Value: 42}, "42"},
+		{NumericAnswer{Value: 9.81, Unit: "m/s2", AbsoluteTolerance: 0.05}, "9.81 m/s2 (± 0.05)"},
+		{NumericAnswer{Value: -200, RelativeTolerance: 0.05}, "-200 (± 5%)"},
+		{NumericAnswer{Value: 10, AbsoluteTolerance: 2, RelativeTolerance: 0.01}, "10 (± 2)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.answer.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
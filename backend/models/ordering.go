@@ -0,0 +1,81 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// OrderingScoring decides how an ordering question's arrangement earns points
+type OrderingScoring string
+
+const (
+	OrderingExact    OrderingScoring = "exact"    // All points for the exact order, none otherwise
+	OrderingPairwise OrderingScoring = "pairwise" // The share of option pairs placed in the right relative order
+)
+
+// OrderingResponse reads a student's arrangement, option IDs from first to last, from a saved answer
+func OrderingResponse(answer interface{}) ([]string, bool) {
+	var items []interface{}
+	switch v := answer.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		items = v
+	case primitive.A: // Answers read back from the database
+		items = v
+	default:
+		return nil, false
+	}
+
+	arrangement := make([]string, 0, len(items))
+	for _, item := range items {
+		id, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		arrangement = append(arrangement, id)
+	}
+	return arrangement, true
+}
+
+// GradeOrdering returns the credit, from 0 to 1, that an arrangement earns against the correct
+// sequence. An arrangement that doesn't place every option exactly once earns nothing.
+func GradeOrdering(answer interface{}, correct []string, scoring OrderingScoring) float64 {
+	arrangement, ok := OrderingResponse(answer)
+	if !ok || len(correct) == 0 || len(arrangement) != len(correct) {
+		return 0
+	}
+
+	position := make(map[string]int, len(arrangement))
+	for i, id := range arrangement {
+		if _, repeated := position[id]; repeated {
+			return 0
+		}
+		position[id] = i
+	}
+	for _, id := range correct {
+		if _, placed := position[id]; !placed {
+			return 0
+		}
+	}
+
+	if scoring != OrderingPairwise {
+		for i, id := range correct {
+			if position[id] != i {
+				return 0
+			}
+		}
+		return 1
+	}
+
+	if len(correct) == 1 {
+		return 1
+	}
+	inOrder, pairs := 0, 0
+	for i := range correct {
+		for j := i + 1; j < len(correct); j++ {
+			pairs++
+			if position[correct[i]] < position[correct[j]] {
+				inOrder++
+			}
+		}
+	}
+	return float64(inOrder) / float64(pairs)
+}
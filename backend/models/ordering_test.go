@@ -0,0 +1,53 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGradeOrdering(t *testing.T) {
+	correct := []string{"a", "b", "c", "d"}
+	tests := []struct {
+		name     string
+		answer   interface{}
+		correct  []string
+		pairwise float64
+		exact    float64
+	}{
+		{"correct order", []string{"a", "b", "c", "d"}, correct, 1, 1},
+		{"one adjacent swap", []string{"a", "c", "b", "d"}, correct, 5.0 / 6, 0},
+		{"first moved to last", []string{"b", "c", "d", "a"}, correct, 3.0 / 6, 0},
+		{"reversed", []string{"d", "c", "b", "a"}, correct, 0, 0},
+		{"repeated ID", []string{"a", "a", "c", "d"}, correct, 0, 0},
+		{"missing ID", []string{"a", "b", "c", "x"}, correct, 0, 0},
+		{"too short", []string{"a", "b", "c"}, correct, 0, 0},
+		{"too long", []string{"a", "b", "c", "d", "e"}, correct, 0, 0},
+		{"empty arrangement", []string{}, correct, 0, 0},
+		{"single item", []string{"a"}, []string{"a"}, 1, 1},
+		{"single wrong item", []string{"b"}, []string{"a"}, 0, 0},
+		{"no correct sequence", []string{}, nil, 0, 0},
+		{"from JSON", []interface{}{"a", "b", "d", "c"}, correct, 5.0 / 6, 0},
+		{"from the database", primitive.A{"a", "b", "c", "d"}, correct, 1, 1},
+		{"from the database out of order", primitive.A{"b", "a", "c", "d"}, correct, 5.0 / 6, 0},
+		{"non-string item", []interface{}{"a", "b", "c", 4}, correct, 0, 0},
+		{"not a list", "a,b,c,d", correct, 0, 0},
+		{"no answer", nil, correct, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GradeOrdering(tt.answer, tt.correct, OrderingPairwise); math.Abs(got-tt.pairwise) > 1e-9 {
+				t.Errorf("pairwise GradeOrdering() = %v, want %v", got, tt.pairwise)
+			}
+			if got := GradeOrdering(tt.answer, tt.correct, OrderingExact); got != tt.exact {
+				t.Errorf("exact GradeOrdering() = %v, want %v", got, tt.exact)
+			}
+			// Questions saved before scoring modes existed have none, and are scored exactly
+			if got := GradeOrdering(tt.answer, tt.correct, ""); got != tt.exact {
+				t.Errorf("GradeOrdering() without a scoring mode = %v, want %v", got, tt.exact)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned for a cursor no listing handed out
+var ErrInvalidCursor = BadRequestError("invalid cursor")
+
+// PageCursor marks where a listing sorted newest first left off: the sort time and ID of the last
+// item on the page. Clients only see it as the opaque string String returns.
+type PageCursor struct {
+	At time.Time          `json:"t"`
+	ID primitive.ObjectID `json:"id"`
+}
+
+// NewPageCursor marks the item a page ended with
+func NewPageCursor(at time.Time, id primitive.ObjectID) *PageCursor {
+	return &PageCursor{At: at, ID: id}
+}
+
+func (c *PageCursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParsePageCursor reads a cursor a listing returned as next_cursor
+func ParsePageCursor(value string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Grades given to quiz answers on the SM-2 0-5 scale when they update a question's memory
+const (
+	PracticeQualityCorrect = 4 // Recalled; 5 is left for reviews reported as perfect
+	PracticeQualityPartial = 2 // Some credit, but not enough to count as recalled
+	PracticeQualityWrong   = 1 // Answered wrong or skipped
+)
+
+// QuestionMemory is how well a student remembers a question, kept as an SM-2 schedule that quiz
+// answers and practice reviews both move along
+type QuestionMemory struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
+
+	Schedule ReviewSchedule `json:"schedule" bson:"schedule"`
+	Reviews  int            `json:"reviews" bson:"reviews"`
+	Lapses   int            `json:"lapses" bson:"lapses"` // Reviews graded below 3, which restart the schedule
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// DuePracticeRequest represents the filters for questions due for review
+type DuePracticeRequest struct {
+	Limit int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// PracticeQuestion is a question due for review, shown without its answer
+type PracticeQuestion struct {
+	QuestionID      primitive.ObjectID `json:"question_id"`
+	Title           string             `json:"title"`
+	Type            QuestionType       `json:"type"`
+	Difficulty      DifficultyLevel    `json:"difficulty"`
+	Media           []QuestionMedia    `json:"media,omitempty"`
+	Audio           *QuestionAudio     `json:"audio,omitempty"`
+	Options         []Option           `json:"options,omitempty"` // Shuffled
+	Unit            string             `json:"unit,omitempty"`
+	OrderingScoring OrderingScoring    `json:"ordering_scoring,omitempty"`
+
+	Schedule ReviewSchedule `json:"schedule"`
+	Reviews  int            `json:"reviews"`
+	Lapses   int            `json:"lapses"`
+}
+
+// DuePracticeResponse lists the questions due for review, most overdue first
+type DuePracticeResponse struct {
+	Questions []PracticeQuestion `json:"questions"`
+	Total     int                `json:"total"`
+	TotalDue  int64              `json:"total_due"` // Including ones past the limit
+}
+
+// RecordPracticeReviewRequest reports how a review of one question went, as an SM-2 0-5 grade
+// or simply whether it was answered correctly
+type RecordPracticeReviewRequest struct {
+	QuestionID string `json:"question_id" binding:"required"`
+	Quality    *int   `json:"quality,omitempty" binding:"required_without=Correct,omitempty,min=0,max=5"`
+	Correct    *bool  `json:"correct,omitempty"`
+}
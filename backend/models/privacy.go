@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// UserDataExport bundles everything stored about a user for a data export request
+type UserDataExport struct {
+	ExportedAt time.Time `json:"exported_at"`
+	UserID     string    `json:"user_id"`
+
+	Profile         interface{}          `json:"profile"`
+	QuizResults     []QuizResult         `json:"quiz_results"`
+	DetailedResults []DetailedQuizResult `json:"detailed_results"`
+	QuizSessions    []QuizSession        `json:"quiz_sessions"`
+	Stats           *UserStats           `json:"stats,omitempty"`
+	Achievements    []Achievement        `json:"achievements"`
+	Bookmarks       []Bookmark           `json:"bookmarks"`
+	FlashcardDecks  []FlashcardDeck      `json:"flashcard_decks"`
+	ActivityLogs    []ActivityLog        `json:"activity_logs"`
+}
+
+// DeleteAccountRequest represents the request to delete the current account
+type DeleteAccountRequest struct {
+	Password string `json:"password,omitempty"` // Required for accounts with a password
+	Reason   string `json:"reason,omitempty" binding:"max=500"`
+}
+
+// AccountDeletionResponse describes a scheduled account deletion
+type AccountDeletionResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+	GracePeriodDays     int       `json:"grace_period_days"`
+}
+
+// PurgeDeletedAccountsResponse summarizes a purge of accounts past their grace period
+type PurgeDeletedAccountsResponse struct {
+	PurgedAccounts    int   `json:"purged_accounts"`
+	AnonymizedResults int64 `json:"anonymized_results"`
+	DeletedSessions   int64 `json:"deleted_sessions"`
+}
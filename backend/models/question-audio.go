@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionAudioFile holds the generated audio bytes for a question, one per question
+type QuestionAudioFile struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	QuestionID  primitive.ObjectID `bson:"question_id"`
+	Data        []byte             `bson:"data"`
+	ContentType string             `bson:"content_type"`
+	TextHash    string             `bson:"text_hash"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+// GenerateAudioBatchRequest represents the request to pre-generate audio for many questions
+type GenerateAudioBatchRequest struct {
+	Limit int  `json:"limit,omitempty" binding:"omitempty,min=1,max=500"`
+	Force bool `json:"force,omitempty"` // Regenerate even when the audio is up to date
+}
+
+// QuestionAudioError reports a question whose audio could not be generated
+type QuestionAudioError struct {
+	QuestionID primitive.ObjectID `json:"question_id"`
+	Error      string             `json:"error"`
+}
+
+// GenerateAudioBatchResponse summarizes a batch generation run
+type GenerateAudioBatchResponse struct {
+	Generated int                  `json:"generated"`
+	Skipped   int                  `json:"skipped"` // Already up to date
+	Failed    int                  `json:"failed"`
+	Errors    []QuestionAudioError `json:"errors,omitempty"`
+}
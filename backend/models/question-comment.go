@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionComment is a reviewer's remark in the discussion on a question's wording. Each comment
+// raises a point that stays unresolved until someone resolves it.
+type QuestionComment struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	Body       string             `json:"body" bson:"body"`
+
+	// Admins called into the discussion; they are notified of the comment
+	Mentions []CommentMention `json:"mentions,omitempty" bson:"mentions,omitempty"`
+
+	Resolved   bool                `json:"resolved" bson:"resolved"`
+	ResolvedBy *primitive.ObjectID `json:"resolved_by,omitempty" bson:"resolved_by,omitempty"`
+	ResolvedAt *time.Time          `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CommentMention is an admin mentioned in a comment, with their name when it was posted
+type CommentMention struct {
+	UserID primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name   string             `json:"name" bson:"name"`
+}
+
+// Request/Response models for API
+
+// PostQuestionCommentRequest represents a new comment on a question
+type PostQuestionCommentRequest struct {
+	Body     string   `json:"body" binding:"required,min=1,max=5000"`
+	Mentions []string `json:"mentions,omitempty" binding:"omitempty,max=20"` // IDs of the admins to notify
+}
+
+// ResolveQuestionCommentRequest resolves a comment or reopens it
+type ResolveQuestionCommentRequest struct {
+	Resolved *bool `json:"resolved" binding:"required"`
+}
+
+// ListQuestionCommentsRequest represents query parameters for listing a question's comments
+type ListQuestionCommentsRequest struct {
+	Resolved *bool `form:"resolved"` // Only resolved or only unresolved comments
+}
+
+// QuestionCommentsResponse represents the discussion on a question, oldest comment first
+type QuestionCommentsResponse struct {
+	QuestionID primitive.ObjectID `json:"question_id"`
+	Comments   []QuestionComment  `json:"comments"`
+	Unresolved int64              `json:"unresolved"` // Across the whole discussion, whatever the filter
+}
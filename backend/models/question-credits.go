@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionReview credits a staff member with reviewing a question. Each reviewer is credited
+// once; reviewing again moves ReviewedAt forward.
+type QuestionReview struct {
+	ReviewerID primitive.ObjectID `json:"reviewer_id" bson:"reviewer_id"`
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"`
+	ReviewedAt time.Time          `json:"reviewed_at" bson:"reviewed_at"`
+}
+
+// QuestionContributor is a person credited on a question. People who hide their question credits
+// are shown as anonymous, without their ID.
+type QuestionContributor struct {
+	UserID     *primitive.ObjectID `json:"user_id,omitempty"`
+	Name       string              `json:"name"`
+	Anonymous  bool                `json:"anonymous,omitempty"`
+	ReviewedAt *time.Time          `json:"reviewed_at,omitempty"` // Reviewers only
+}
+
+// QuestionCredits says who wrote a question and who reviewed it. The author is the student who
+// proposed it when it came from a proposal, otherwise the admin who created it.
+type QuestionCredits struct {
+	Author    QuestionContributor   `json:"author"`
+	Reviewers []QuestionContributor `json:"reviewers"`
+}
+
+// RecordQuestionReviewRequest credits the calling admin with reviewing a question
+type RecordQuestionReviewRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000"`
+}
+
+// AuthoringReportRequest filters the authoring report by when questions were written and reviewed
+type AuthoringReportRequest struct {
+	DateFrom string `form:"date_from"` // YYYY-MM-DD
+	DateTo   string `form:"date_to"`   // YYYY-MM-DD, inclusive
+}
+
+// ContributionCount is how many questions one person wrote or reviewed
+type ContributionCount struct {
+	UserID primitive.ObjectID `bson:"_id"`
+	Count  int                `bson:"count"`
+	Active int                `bson:"active"` // Of those, questions still active
+}
+
+// AuthorContribution is one person's row in the authoring report. The report is for verifying
+// workload, so everyone is named, including those who hide their credits on questions.
+type AuthorContribution struct {
+	UserID         primitive.ObjectID `json:"user_id"`
+	FullName       string             `json:"full_name"`
+	Email          string             `json:"email,omitempty"`
+	Authored       int                `json:"authored"`
+	ActiveAuthored int                `json:"active_authored"`
+	Reviewed       int                `json:"reviewed"`
+	HidesCredits   bool               `json:"hides_credits,omitempty"`
+}
+
+// AuthoringReportResponse lists contributors with the most questions written and reviewed first
+type AuthoringReportResponse struct {
+	DateFrom      string               `json:"date_from,omitempty"`
+	DateTo        string               `json:"date_to,omitempty"`
+	TotalAuthored int                  `json:"total_authored"`
+	TotalReviewed int                  `json:"total_reviewed"`
+	Contributors  []AuthorContribution `json:"contributors"`
+}
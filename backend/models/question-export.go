@@ -0,0 +1,15 @@
+package models
+
+// Request/Response models for API
+
+// ExportQuestionsRequest represents the query of a question bank export. The list filters apply;
+// paging does not, every matching question is exported.
+//
+// CSV and XLSX exports use the column format of ImportQuestionsRequest, with correct answers as
+// option letters, plus the question's id, is_active, version and created_at. JSON exports hold the
+// questions as the API returns them.
+type ExportQuestionsRequest struct {
+	ListQuestionsRequest
+	Format         string `form:"format,default=csv" binding:"oneof=csv xlsx json"`
+	ExcludeAnswers bool   `form:"exclude_answers"` // Leave out answer keys, sample answers and explanations, e.g. for external reviewers
+}
@@ -0,0 +1,52 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Request/Response models for API
+
+// ImportQuestionsRequest represents the multipart form options sent with a question import file.
+//
+// CSV and XLSX files have a header row naming these columns, in any order and case:
+//
+//	title              required; for fill_in_blank, with {{id}} placeholders
+//	type               required; single_choice, multiple_choice, essay, fill_in_blank, numeric or ordering
+//	difficulty         required; easy, medium or hard
+//	points             required; at least 1
+//	options            option texts separated by "|"; ordering questions list them in the correct sequence
+//	correct_answers    correct options by letter (A) or number (1), separated by "|"
+//	sample_answer      essay questions
+//	explanation        shown when students review their result
+//	blanks             fill_in_blank answers as id=answer;answer, one blank per "|"
+//	numeric_answer     numeric questions: the expected value
+//	tolerance          numeric questions: the accepted absolute difference
+//	relative_tolerance numeric questions: the accepted difference as a fraction of the value
+//	unit               numeric questions
+//	ordering_scoring   ordering questions; exact or pairwise
+//	tags               separated by "|"
+//
+// JSON files hold an array of question objects in the format POST /admin/questions accepts.
+type ImportQuestionsRequest struct {
+	DryRun bool `form:"dry_run"` // Validate and check for duplicates without creating anything
+}
+
+// QuestionImportRowResult reports what happened to one question of the import file
+type QuestionImportRowResult struct {
+	Row        int                 `json:"row"` // Spreadsheet row number with the header as row 1, or position in a JSON array
+	Title      string              `json:"title"`
+	Type       QuestionType        `json:"type"`
+	Status     ImportRowStatus     `json:"status"`
+	Errors     []string            `json:"errors,omitempty"`
+	QuestionID *primitive.ObjectID `json:"question_id,omitempty"`
+}
+
+// ImportQuestionsResponse summarizes a bulk question import
+type ImportQuestionsResponse struct {
+	DryRun     bool                      `json:"dry_run"`
+	TotalRows  int                       `json:"total_rows"`
+	Created    int                       `json:"created"`
+	Valid      int                       `json:"valid"` // Rows a dry run would create
+	Duplicates int                       `json:"duplicates"`
+	Invalid    int                       `json:"invalid"`
+	Failed     int                       `json:"failed"`
+	Results    []QuestionImportRowResult `json:"results"`
+}
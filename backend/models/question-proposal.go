@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContributionXP is the experience awarded to a student when their proposed question is accepted
+const ContributionXP = 50
+
+// QuestionProposalStatus tracks an admin's review of a proposed question
+type QuestionProposalStatus string
+
+const (
+	ProposalStatusPending  QuestionProposalStatus = "pending"
+	ProposalStatusAccepted QuestionProposalStatus = "accepted" // Added to the question bank
+	ProposalStatusRejected QuestionProposalStatus = "rejected"
+)
+
+// QuestionProposal is a question a student submitted for the bank, held until an admin reviews it
+type QuestionProposal struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProposedBy    primitive.ObjectID `json:"proposed_by" bson:"proposed_by"`
+	ProposerEmail string             `json:"proposer_email,omitempty" bson:"proposer_email,omitempty"`
+
+	// The question as the student wrote it; admins may adjust difficulty and points on acceptance
+	Question  CreateQuestionRequest `json:"question" bson:"question"`
+	Rationale string                `json:"rationale,omitempty" bson:"rationale,omitempty"` // Why the answer is correct, for the reviewer
+
+	// Review
+	Status     QuestionProposalStatus `json:"status" bson:"status"`
+	ReviewNote string                 `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ReviewedBy *primitive.ObjectID    `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time             `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+
+	// Set on acceptance
+	QuestionID *primitive.ObjectID `json:"question_id,omitempty" bson:"question_id,omitempty"`
+	XPAwarded  int                 `json:"xp_awarded,omitempty" bson:"xp_awarded,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// ProposeQuestionRequest represents a student's proposed question
+type ProposeQuestionRequest struct {
+	CreateQuestionRequest
+	Rationale string `json:"rationale,omitempty" binding:"max=2000"`
+}
+
+// ListQuestionProposalsRequest represents the filters for a list of proposals
+type ListQuestionProposalsRequest struct {
+	Page   int                    `form:"page,default=1" binding:"min=1"`
+	Limit  int                    `form:"limit,default=20" binding:"min=1,max=100"`
+	Status QuestionProposalStatus `form:"status" binding:"omitempty,oneof=pending accepted rejected"`
+}
+
+// ListQuestionProposalsResponse represents a page of proposals
+type ListQuestionProposalsResponse struct {
+	Proposals    []QuestionProposal `json:"proposals"`
+	Total        int64              `json:"total"`
+	PendingCount int64              `json:"pending_count"`
+	Page         int                `json:"page"`
+	Limit        int                `json:"limit"`
+	TotalPages   int                `json:"total_pages"`
+}
+
+// ReviewQuestionProposalRequest accepts or rejects a proposal
+type ReviewQuestionProposalRequest struct {
+	Status     QuestionProposalStatus `json:"status" binding:"required,oneof=accepted rejected"`
+	Note       string                 `json:"note,omitempty" binding:"max=1000"`
+	Difficulty *DifficultyLevel       `json:"difficulty,omitempty" binding:"omitempty,oneof=easy medium hard"`
+	Points     *int                   `json:"points,omitempty" binding:"omitempty,min=1"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionReportReason is why a student reported a question
+type QuestionReportReason string
+
+const (
+	ReportReasonAmbiguous      QuestionReportReason = "ambiguous"
+	ReportReasonWrongAnswer    QuestionReportReason = "wrong_answer" // The marked correct answer looks wrong
+	ReportReasonTypo           QuestionReportReason = "typo"
+	ReportReasonBrokenMedia    QuestionReportReason = "broken_media"
+	ReportReasonDisplayProblem QuestionReportReason = "display_problem"
+	ReportReasonOther          QuestionReportReason = "other"
+)
+
+// QuestionReportStatus tracks an admin's review of a report
+type QuestionReportStatus string
+
+const (
+	ReportStatusOpen      QuestionReportStatus = "open"
+	ReportStatusResolved  QuestionReportStatus = "resolved"  // The question was fixed
+	ReportStatusDismissed QuestionReportStatus = "dismissed" // No change needed
+)
+
+// QuestionReport is a student's report of an ambiguous or broken question, raised during a quiz
+type QuestionReport struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
+	ReporterID primitive.ObjectID `json:"reporter_id" bson:"reporter_id"`
+	IsGuest    bool               `json:"is_guest,omitempty" bson:"is_guest,omitempty"`
+
+	Reason  QuestionReportReason `json:"reason" bson:"reason"`
+	Comment string               `json:"comment,omitempty" bson:"comment,omitempty"`
+
+	// Snapshot of the question as the student saw it
+	QuestionTitle string          `json:"question_title" bson:"question_title"`
+	QuestionType  QuestionType    `json:"question_type" bson:"question_type"`
+	Difficulty    DifficultyLevel `json:"difficulty" bson:"difficulty"`
+
+	// Session context
+	SessionID     primitive.ObjectID  `json:"session_id" bson:"session_id"`
+	QuizType      QuizType            `json:"quiz_type" bson:"quiz_type"`
+	QuestionIndex int                 `json:"question_index" bson:"question_index"` // Zero-based position in the session
+	UserAnswer    interface{}         `json:"user_answer,omitempty" bson:"user_answer,omitempty"`
+	ExamSittingID *primitive.ObjectID `json:"exam_sitting_id,omitempty" bson:"exam_sitting_id,omitempty"`
+
+	// Review
+	Status         QuestionReportStatus `json:"status" bson:"status"`
+	ResolutionNote string               `json:"resolution_note,omitempty" bson:"resolution_note,omitempty"`
+	ReviewedBy     *primitive.ObjectID  `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time           `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Request/Response models for API
+
+// FlagQuestionRequest represents a student's report of the question at an index of their session
+type FlagQuestionRequest struct {
+	Reason  QuestionReportReason `json:"reason" binding:"required,oneof=ambiguous wrong_answer typo broken_media display_problem other"`
+	Comment string               `json:"comment,omitempty" binding:"max=1000"`
+}
+
+// FlagQuestionResponse confirms a report without exposing the review queue
+type FlagQuestionResponse struct {
+	ReportID primitive.ObjectID `json:"report_id"`
+	Message  string             `json:"message"`
+}
+
+// ListQuestionReportsRequest represents the filters for the admin review queue
+type ListQuestionReportsRequest struct {
+	Page       int                  `form:"page,default=1" binding:"min=1"`
+	Limit      int                  `form:"limit,default=20" binding:"min=1,max=100"`
+	Status     QuestionReportStatus `form:"status" binding:"omitempty,oneof=open resolved dismissed"`
+	Reason     QuestionReportReason `form:"reason"`
+	QuestionID string               `form:"question_id"`
+}
+
+// QuestionReportLinks points admins at the reported question and session
+type QuestionReportLinks struct {
+	Question string `json:"question"` // Admin question endpoint
+	Session  string `json:"session"`  // Admin session context endpoint
+}
+
+// QuestionReportItem is a report in the review queue with links to its context
+type QuestionReportItem struct {
+	QuestionReport `bson:",inline"`
+	Links          QuestionReportLinks `json:"links"`
+}
+
+// ListQuestionReportsResponse represents a page of the review queue
+type ListQuestionReportsResponse struct {
+	Reports    []QuestionReportItem `json:"reports"`
+	Total      int64                `json:"total"`
+	OpenCount  int64                `json:"open_count"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ReviewQuestionReportRequest closes a report
+type ReviewQuestionReportRequest struct {
+	Status QuestionReportStatus `json:"status" binding:"required,oneof=resolved dismissed"`
+	Note   string               `json:"note,omitempty" binding:"max=1000"`
+}
+
+// QuestionReportContextResponse shows a report with the question as it is now and as the student saw it
+type QuestionReportContextResponse struct {
+	Report          *QuestionReport  `json:"report"`
+	Question        *Question        `json:"question,omitempty"`         // Current version; nil if deleted
+	SessionQuestion *SessionQuestion `json:"session_question,omitempty"` // Options as shuffled, with the student's answer
+	Session         *SessionSummary  `json:"session,omitempty"`          // Nil if the session was deleted
+}
+
+// SessionSummary is the session metadata shown alongside a report
+type SessionSummary struct {
+	ID              primitive.ObjectID `json:"id"`
+	UserID          primitive.ObjectID `json:"user_id"`
+	QuizType        QuizType           `json:"quiz_type"`
+	Status          QuizStatus         `json:"status"`
+	StartTime       time.Time          `json:"start_time"`
+	EndTime         *time.Time         `json:"end_time,omitempty"`
+	TotalQuestions  int                `json:"total_questions"`
+	CurrentQuestion int                `json:"current_question"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// QuestionSearchMode is how a question search was matched
+type QuestionSearchMode string
+
+const (
+	// Words matched through the text index, ranked by relevance
+	SearchModeText QuestionSearchMode = "text"
+	// Case-insensitive matching used while the text index is missing, newest first
+	SearchModePattern QuestionSearchMode = "pattern"
+)
+
+// QuestionSearchHighlight is a piece of a question's wording where the search terms matched
+type QuestionSearchHighlight struct {
+	Field   string `json:"field"`   // title, options or explanation
+	Snippet string `json:"snippet"` // HTML-escaped text with the matches wrapped in <mark>
+}
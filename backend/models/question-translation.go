@@ -0,0 +1,26 @@
+package models
+
+// DefaultQuestionLanguage is the language a question's own title, options and explanation are
+// written in. Translations add other languages; anything they leave out is served in this one.
+const DefaultQuestionLanguage = "id"
+
+// QuestionLanguages are the languages questions can be served in
+var QuestionLanguages = []string{"id", "en"}
+
+// QuestionTranslation is a question's wording in another language. Options are translated in the
+// order the question stores them; answers, blanks and media are shared with the original.
+type QuestionTranslation struct {
+	Title       string   `json:"title" bson:"title" binding:"required"` // For fill_in_blank, with the original's {{id}} placeholders
+	Options     []string `json:"options,omitempty" bson:"options,omitempty"`
+	Explanation string   `json:"explanation,omitempty" bson:"explanation,omitempty" binding:"max=5000"`
+}
+
+// IsQuestionLanguage reports whether questions can be served in the language
+func IsQuestionLanguage(lang string) bool {
+	for _, l := range QuestionLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
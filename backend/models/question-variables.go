@@ -0,0 +1,16 @@
+package models
+
+// QuestionVariable is a number drawn afresh for each session of a parameterized numeric question.
+// Values run from Min to Max in steps of Step, so Min 1, Max 2, Step 0.5 draws 1, 1.5 or 2.
+//
+// The title and explanation name a variable as {name}; each placeholder is replaced with the value
+// drawn, and the question's AnswerExpression is evaluated with the same values to give the answer.
+type QuestionVariable struct {
+	Name string  `json:"name" bson:"name" binding:"required,max=20"`
+	Min  float64 `json:"min" bson:"min"`
+	Max  float64 `json:"max" bson:"max"`
+	Step float64 `json:"step,omitempty" bson:"step,omitempty" binding:"min=0"` // Whole numbers when unset
+}
+
+// MaxQuestionVariables bounds the variables one question may declare
+const MaxQuestionVariables = 10
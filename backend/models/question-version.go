@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuestionVersion is an immutable copy of a question as it stood after one edit. Quiz sessions record
+// the version they were built from, so past results can be traced to the exact wording and answer key.
+type QuestionVersion struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
+	Version    int                `json:"version" bson:"version"`
+
+	Snapshot Question              `json:"snapshot" bson:"snapshot"`
+	Changes  []QuestionFieldChange `json:"changes,omitempty" bson:"changes,omitempty"` // Against the previous version; empty for the first
+
+	// Who made the edit
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name,omitempty" bson:"-"`
+
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+
+	// Filled in when listed: quiz sessions built from this version, which keep it frozen
+	SessionCount int64 `json:"session_count" bson:"-"`
+	Frozen       bool  `json:"frozen" bson:"-"`
+}
+
+// QuestionFieldChange is one field an edit changed, with its value before and after
+type QuestionFieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	From  interface{} `json:"from,omitempty" bson:"from,omitempty"`
+	To    interface{} `json:"to,omitempty" bson:"to,omitempty"`
+}
+
+// Request/Response models for API
+
+// QuestionVersionsResponse lists a question's versions, newest first
+type QuestionVersionsResponse struct {
+	QuestionID     primitive.ObjectID `json:"question_id"`
+	CurrentVersion int                `json:"current_version"`
+	Versions       []QuestionVersion  `json:"versions"`
+	Total          int                `json:"total"`
+
+	// Sessions built before versioning began, which cannot be tied to a version
+	UnversionedSessions int64 `json:"unversioned_sessions"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// QuestionReviewStatus is where a question stands in the publishing workflow:
+// draft → in_review → approved. Only approved questions are drawn into quizzes; questions written
+// before the workflow have no status and count as approved.
+type QuestionReviewStatus string
+
+const (
+	QuestionDraft    QuestionReviewStatus = "draft"
+	QuestionInReview QuestionReviewStatus = "in_review"
+	QuestionApproved QuestionReviewStatus = "approved"
+)
+
+// UnapprovedReviewStatuses are the statuses that keep a question out of quizzes
+var UnapprovedReviewStatuses = []QuestionReviewStatus{QuestionDraft, QuestionInReview}
+
+// Request/Response models for API
+
+// ApproveQuestionRequest represents a reviewer approving a question submitted for review
+type ApproveQuestionRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000"`
+}
+
+// RejectQuestionRequest represents a reviewer sending a question back to its author as a draft
+type RejectQuestionRequest struct {
+	Note string `json:"note" binding:"required,max=1000"` // What the author should change
+}
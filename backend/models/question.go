@@ -13,6 +13,9 @@ const (
 	SingleChoice   QuestionType = "single_choice"
 	MultipleChoice QuestionType = "multiple_choice"
 	Essay          QuestionType = "essay"
+	FillInBlank    QuestionType = "fill_in_blank" // Title holds {{id}} placeholders answered by Blanks
+	Numeric        QuestionType = "numeric"       // Answered with a number, graded against NumericAnswer
+	Ordering       QuestionType = "ordering"      // Options arranged into the sequence held by CorrectAnswers
 )
 
 // DifficultyLevel represents the difficulty of a question
@@ -24,31 +27,126 @@ const (
 	Hard   DifficultyLevel = "hard"
 )
 
+// MediaType represents the kind of media attached to a question
+type MediaType string
+
+const (
+	MediaImage MediaType = "image"
+	MediaAudio MediaType = "audio"
+	MediaVideo MediaType = "video"
+)
+
+// QuestionMedia represents a media file shown with a question
+type QuestionMedia struct {
+	URL     string    `json:"url" bson:"url" binding:"required,url"`
+	Type    MediaType `json:"type" bson:"type" binding:"required,oneof=image audio video"`
+	AltText string    `json:"alt_text,omitempty" bson:"alt_text,omitempty" binding:"max=500"` // Required for images, read by screen readers
+}
+
+// QuestionAudio describes the generated audio for a question; the audio itself is stored separately
+type QuestionAudio struct {
+	URL         string    `json:"url" bson:"url"` // API path the audio is streamed from
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Provider    string    `json:"provider" bson:"provider"`
+	Voice       string    `json:"voice,omitempty" bson:"voice,omitempty"`
+	TextHash    string    `json:"-" bson:"text_hash"` // Detects audio gone stale after the question was edited
+	SizeBytes   int       `json:"size_bytes" bson:"size_bytes"`
+	GeneratedAt time.Time `json:"generated_at" bson:"generated_at"`
+}
+
 // Option represents a choice option for single/multiple choice questions
 type Option struct {
-	ID    string `json:"id" bson:"id"`
-	Text  string `json:"text" bson:"text"`
-	Order int    `json:"order" bson:"order"`
+	ID       string `json:"id" bson:"id"`
+	Text     string `json:"text" bson:"text"`                               // Markdown with embedded $LaTeX$
+	TextHTML string `json:"text_html,omitempty" bson:"text_html,omitempty"` // Text rendered for display; stored only in session snapshots
+	ImageURL string `json:"image_url,omitempty" bson:"image_url,omitempty"`
+	ImageAlt string `json:"image_alt,omitempty" bson:"image_alt,omitempty"` // Required when ImageURL is set
+	Order    int    `json:"order" bson:"order"`
 }
 
 // Question represents a quiz question with support for different types
 type Question struct {
 	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Title      string             `json:"title" bson:"title"`
+	Title      string             `json:"title" bson:"title"`            // Markdown with embedded $LaTeX$
+	TitleHTML  string             `json:"title_html,omitempty" bson:"-"` // Title rendered for display
 	Type       QuestionType       `json:"type" bson:"type"`
 	Difficulty DifficultyLevel    `json:"difficulty" bson:"difficulty"`
 	Points     int                `json:"points" bson:"points"` // Total points for this question
 	IsActive   bool               `json:"is_active" bson:"is_active"`
 
-	// Options for single/multiple choice questions with shuffling support
+	// Images, audio or video shown with the question
+	Media []QuestionMedia `json:"media,omitempty" bson:"media,omitempty"`
+
+	// Spoken rendition of the question text, generated by text-to-speech
+	Audio *QuestionAudio `json:"audio,omitempty" bson:"audio,omitempty"`
+
+	// Options for single/multiple choice and ordering questions with shuffling support
 	Options []Option `json:"options,omitempty" bson:"options,omitempty"`
 
-	// Correct answers as option IDs (allows for shuffling)
+	// Correct answers as option IDs (allows for shuffling); for ordering questions, the correct sequence
 	CorrectAnswers []string `json:"correct_answers,omitempty" bson:"correct_answers,omitempty"`
 
+	// Ordering: whether only the exact sequence scores, or each pair of options in the right order
+	OrderingScoring OrderingScoring `json:"ordering_scoring,omitempty" bson:"ordering_scoring,omitempty"`
+
 	// Essay-specific field
 	SampleAnswer string `json:"sample_answer,omitempty" bson:"sample_answer,omitempty"`
 
+	// Why the correct answer is right, shown when students review their result
+	Explanation string `json:"explanation,omitempty" bson:"explanation,omitempty"`
+
+	// Fill-in-the-blank: the gaps written into the title and the answers each accepts
+	Blanks []Blank `json:"blanks,omitempty" bson:"blanks,omitempty"`
+
+	// Numeric: the expected value, its tolerance and unit
+	NumericAnswer *NumericAnswer `json:"numeric_answer,omitempty" bson:"numeric_answer,omitempty"`
+
+	// Parameterized numeric questions: values drawn per session fill the title, and the answer is
+	// computed from them; NumericAnswer then only sets the tolerance and unit
+	Variables        []QuestionVariable `json:"variables,omitempty" bson:"variables,omitempty"`
+	AnswerExpression string             `json:"answer_expression,omitempty" bson:"answer_expression,omitempty"`
+
+	// Module sections that teach the question, recommended to students who miss it
+	Sections []SectionRef `json:"sections,omitempty" bson:"sections,omitempty"`
+
+	// Modules the question assesses, counted toward students' mastery of them along with the modules of
+	// its sections
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" bson:"module_ids,omitempty"`
+
+	// Topics the question covers, lowercased; students build custom quizzes from them
+	Tags []string `json:"tags,omitempty" bson:"tags,omitempty"`
+
+	// Wording in other languages, keyed by language code; quizzes started in one of them use it
+	Translations map[string]QuestionTranslation `json:"translations,omitempty" bson:"translations,omitempty"`
+
+	// Set when a student proposed the question; CreatedBy is then the admin who accepted it
+	ContributedBy *primitive.ObjectID `json:"contributed_by,omitempty" bson:"contributed_by,omitempty"`
+
+	// Staff who reviewed the question, shown through Credits
+	Reviews []QuestionReview `json:"-" bson:"reviews,omitempty"`
+	Credits *QuestionCredits `json:"credits,omitempty" bson:"-"`
+
+	// Each edit adds a QuestionVersion; questions written before versioning start at 0
+	Version int `json:"version" bson:"version"`
+
+	// Deleting a question archives it: it stays inactive and out of the bank list, and the sessions
+	// that used it keep pointing at it, until it is restored or permanently deleted
+	Archived            bool                `json:"archived,omitempty" bson:"archived,omitempty"`
+	ArchivedAt          *time.Time          `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	ArchivedBy          *primitive.ObjectID `json:"archived_by,omitempty" bson:"archived_by,omitempty"`
+	ActiveBeforeArchive bool                `json:"-" bson:"active_before_archive,omitempty"` // Restored along with the question
+
+	// Publishing workflow; ReviewNote is the reviewer's feedback when a question is sent back
+	ReviewStatus QuestionReviewStatus `json:"review_status,omitempty" bson:"review_status,omitempty"`
+	SubmittedAt  *time.Time           `json:"submitted_at,omitempty" bson:"submitted_at,omitempty"`
+	ReviewNote   string               `json:"review_note,omitempty" bson:"review_note,omitempty"`
+	ApprovedBy   *primitive.ObjectID  `json:"approved_by,omitempty" bson:"approved_by,omitempty"`
+	ApprovedAt   *time.Time           `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+
+	// Search results only: relevance from the text index and where the search terms matched
+	SearchScore float64                   `json:"search_score,omitempty" bson:"search_score,omitempty"`
+	Highlights  []QuestionSearchHighlight `json:"highlights,omitempty" bson:"-"`
+
 	// Metadata
 	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
@@ -57,20 +155,44 @@ type Question struct {
 
 // Request/Response models for API
 
-// CreateQuestionRequest represents the request to create a new question
+// CreateQuestionRequest represents the request to create a new question; student proposals store it as submitted
 type CreateQuestionRequest struct {
-	Title          string          `json:"title" binding:"required"`
-	Type           QuestionType    `json:"type" binding:"required,oneof=single_choice multiple_choice essay"`
-	Difficulty     DifficultyLevel `json:"difficulty" binding:"required,oneof=easy medium hard"`
-	Points         int             `json:"points" binding:"required,min=1"`
-	Options        []CreateOption  `json:"options,omitempty"`
-	CorrectAnswers []string        `json:"correct_answers,omitempty"`
-	SampleAnswer   string          `json:"sample_answer,omitempty"`
+	Title          string          `json:"title" bson:"title" binding:"required"`
+	Type           QuestionType    `json:"type" bson:"type" binding:"required,oneof=single_choice multiple_choice essay fill_in_blank numeric ordering"`
+	Difficulty     DifficultyLevel `json:"difficulty" bson:"difficulty" binding:"required,oneof=easy medium hard"`
+	Points         int             `json:"points" bson:"points" binding:"required,min=1"`
+	Media          []QuestionMedia `json:"media,omitempty" bson:"media,omitempty" binding:"omitempty,max=10,dive"`
+	Options        []CreateOption  `json:"options,omitempty" bson:"options,omitempty"`
+	CorrectAnswers []string        `json:"correct_answers,omitempty" bson:"correct_answers,omitempty"`
+	SampleAnswer   string          `json:"sample_answer,omitempty" bson:"sample_answer,omitempty"`
+	Explanation    string          `json:"explanation,omitempty" bson:"explanation,omitempty" binding:"max=5000"`
+	Blanks         []Blank         `json:"blanks,omitempty" bson:"blanks,omitempty" binding:"omitempty,max=20,dive"`
+	NumericAnswer  *NumericAnswer  `json:"numeric_answer,omitempty" bson:"numeric_answer,omitempty"`
+
+	// Ordering questions list Options in the correct sequence; they are shuffled when shown
+	OrderingScoring OrderingScoring `json:"ordering_scoring,omitempty" bson:"ordering_scoring,omitempty" binding:"omitempty,oneof=exact pairwise"`
+
+	// Numeric questions may draw their numbers per session; see QuestionVariable
+	Variables        []QuestionVariable `json:"variables,omitempty" bson:"variables,omitempty" binding:"omitempty,max=10,dive"`
+	AnswerExpression string             `json:"answer_expression,omitempty" bson:"answer_expression,omitempty" binding:"max=500"`
+
+	Sections []SectionRef `json:"sections,omitempty" bson:"sections,omitempty" binding:"omitempty,max=10,dive"`
+	Tags     []string     `json:"tags,omitempty" bson:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"`
+
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" bson:"module_ids,omitempty" binding:"omitempty,max=10"`
+
+	// Wording in other languages, keyed by language code
+	Translations map[string]QuestionTranslation `json:"translations,omitempty" bson:"translations,omitempty" binding:"omitempty,dive"`
+
+	// Set by the server when an accepted proposal is added to the bank
+	ContributedBy *primitive.ObjectID `json:"-" bson:"-"`
 }
 
 // CreateOption represents an option when creating a question
 type CreateOption struct {
-	Text string `json:"text" binding:"required"`
+	Text     string `json:"text" bson:"text" binding:"required"`
+	ImageURL string `json:"image_url,omitempty" bson:"image_url,omitempty" binding:"omitempty,url"`
+	ImageAlt string `json:"image_alt,omitempty" bson:"image_alt,omitempty" binding:"max=500"`
 }
 
 // UpdateQuestionRequest represents the request to update a question
@@ -79,9 +201,27 @@ type UpdateQuestionRequest struct {
 	Difficulty     *DifficultyLevel `json:"difficulty,omitempty"`
 	Points         *int             `json:"points,omitempty"`
 	IsActive       *bool            `json:"is_active,omitempty"`
-	Options        []CreateOption   `json:"options,omitempty"`
+	Media          []QuestionMedia  `json:"media,omitempty" binding:"omitempty,max=10,dive"`
+	Options        []CreateOption   `json:"options,omitempty" binding:"omitempty,dive"`
 	CorrectAnswers []string         `json:"correct_answers,omitempty"`
 	SampleAnswer   *string          `json:"sample_answer,omitempty"`
+	Explanation    *string          `json:"explanation,omitempty" binding:"omitempty,max=5000"` // An empty string removes the explanation
+	Blanks         []Blank          `json:"blanks,omitempty" binding:"omitempty,max=20,dive"`
+	NumericAnswer  *NumericAnswer   `json:"numeric_answer,omitempty"`
+
+	OrderingScoring *OrderingScoring `json:"ordering_scoring,omitempty" binding:"omitempty,oneof=exact pairwise"`
+
+	// An empty variable list, with an empty expression, makes the question a fixed one again
+	Variables        []QuestionVariable `json:"variables,omitempty" binding:"omitempty,max=10,dive"`
+	AnswerExpression *string            `json:"answer_expression,omitempty" binding:"omitempty,max=500"`
+
+	Sections []SectionRef `json:"sections,omitempty" binding:"omitempty,max=10,dive"`    // An empty list unlinks every section
+	Tags     []string     `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"` // An empty list removes every tag
+
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" binding:"omitempty,max=10"` // An empty list unlinks every module
+
+	// Replaces every translation; an empty object removes them all
+	Translations map[string]QuestionTranslation `json:"translations,omitempty" binding:"omitempty,dive"`
 }
 
 // ListQuestionsRequest represents the request to list questions with filters
@@ -92,6 +232,25 @@ type ListQuestionsRequest struct {
 	Type       QuestionType    `form:"type"`
 	Difficulty DifficultyLevel `form:"difficulty"`
 	IsActive   *bool           `form:"is_active"`
+	Archived   bool            `form:"archived"` // List archived questions instead of the bank
+	Tag        string          `form:"tag"`
+	AuthorID   string          `form:"author_id"`   // Questions written by this user, including accepted proposals
+	ReviewerID string          `form:"reviewer_id"` // Questions this user reviewed
+
+	// Questions at one stage of the publishing workflow; approved includes those from before it
+	ReviewStatus QuestionReviewStatus `form:"review_status" binding:"omitempty,oneof=draft in_review approved"`
+
+	// Continue after a next_cursor instead of paging by number; not available when searching
+	Cursor string `form:"cursor"`
+}
+
+// QuestionSample narrows the selectable questions a quiz draws from at random. Empty fields leave
+// the draw unrestricted by them.
+type QuestionSample struct {
+	Type         QuestionType
+	Difficulties []DifficultyLevel
+	Tags         []string // Questions carrying any of these tags
+	ExcludeIDs   []primitive.ObjectID
 }
 
 // ListQuestionsResponse represents the response for listing questions
@@ -101,6 +260,12 @@ type ListQuestionsResponse struct {
 	Page       int         `json:"page"`
 	Limit      int         `json:"limit"`
 	TotalPages int         `json:"total_pages"`
+
+	SearchMode QuestionSearchMode `json:"search_mode,omitempty"` // Set when the list was searched
+
+	// Pass as cursor to get the page after this one; empty on the last page and when searching. Pages
+	// fetched by cursor leave total, page and total_pages unset.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // QuestionStatsResponse represents question statistics
@@ -113,10 +278,44 @@ type QuestionStatsResponse struct {
 	SingleChoice   int64            `json:"single_choice"`
 	MultipleChoice int64            `json:"multiple_choice"`
 	Essay          int64            `json:"essay"`
+	FillInBlank    int64            `json:"fill_in_blank"`
+	Numeric        int64            `json:"numeric"`
+	Ordering       int64            `json:"ordering"`
 	TotalPoints    int64            `json:"total_points"`
 	AveragePoints  float64          `json:"average_points"`
 }
 
+// AccessibilityReportRequest represents the request to list questions with accessibility gaps
+type AccessibilityReportRequest struct {
+	Page     int   `form:"page,default=1" binding:"min=1"`
+	Limit    int   `form:"limit,default=20" binding:"min=1,max=100"`
+	IsActive *bool `form:"is_active"`
+}
+
+// AccessibilityIssue is one image on a question that has no alt text
+type AccessibilityIssue struct {
+	Field string `json:"field"` // e.g. "media[0]" or "options[2]"
+	URL   string `json:"url"`
+}
+
+// AccessibilityReportItem lists the images missing alt text on a question
+type AccessibilityReportItem struct {
+	QuestionID primitive.ObjectID   `json:"question_id"`
+	Title      string               `json:"title"`
+	Type       QuestionType         `json:"type"`
+	IsActive   bool                 `json:"is_active"`
+	Issues     []AccessibilityIssue `json:"issues"`
+}
+
+// AccessibilityReportResponse represents a page of questions needing alt text remediation
+type AccessibilityReportResponse struct {
+	Items      []AccessibilityReportItem `json:"items"`
+	Total      int64                     `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
+
 // QuestionForQuiz represents a question prepared for quiz (with shuffled options)
 type QuestionForQuiz struct {
 	ID      primitive.ObjectID `json:"id"`
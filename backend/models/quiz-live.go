@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// QuizLiveEventType is the kind of message pushed over a quiz session's live channel
+type QuizLiveEventType string
+
+const (
+	QuizLiveTimer       QuizLiveEventType = "timer"        // Authoritative time remaining
+	QuizLiveAnswerSaved QuizLiveEventType = "answer_saved" // An answer or skip was stored
+	QuizLiveNavigated   QuizLiveEventType = "navigated"    // Another tab moved to a different question
+	QuizLiveSubmitted   QuizLiveEventType = "submitted"    // The session is over; clients must stop taking answers
+	QuizLivePaused      QuizLiveEventType = "paused"       // An admin paused the session; the timer is frozen
+	QuizLiveResumed     QuizLiveEventType = "resumed"      // An admin resumed the session
+)
+
+// Reasons a session was submitted
+const (
+	SubmitReasonStudent     = "submitted"    // The student submitted it
+	SubmitReasonTimeExpired = "time_expired" // The server submitted it when time ran out
+	SubmitReasonDiscarded   = "discarded"    // The student discarded it without a result
+)
+
+// QuizLiveEvent is a message pushed to every connection watching a quiz session
+type QuizLiveEvent struct {
+	Type       QuizLiveEventType `json:"type"`
+	ServerTime time.Time         `json:"server_time"`
+
+	// Timer
+	TimeRemaining *int64     `json:"time_remaining,omitempty"` // Seconds
+	Deadline      *time.Time `json:"deadline,omitempty"`
+	Status        QuizStatus `json:"status,omitempty"`
+	Paused        bool       `json:"paused,omitempty"`
+
+	// Answer acknowledgements and navigation
+	QuestionIndex *int `json:"question_index,omitempty"`
+	Skipped       bool `json:"skipped,omitempty"`
+
+	// Submission
+	Reason   string `json:"reason,omitempty"`
+	ResultID string `json:"result_id,omitempty"`
+}
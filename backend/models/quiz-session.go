@@ -15,6 +15,38 @@ const (
 	QuizAbandoned  QuizStatus = "abandoned"
 )
 
+// AbandonReason is why a session ended without the student submitting it
+type AbandonReason string
+
+const (
+	AbandonTimeout    AbandonReason = "timeout"    // Time ran out while the student was away; scored as it stood
+	AbandonInactivity AbandonReason = "inactivity" // No activity for an hour with time left
+	AbandonForced     AbandonReason = "forced"     // Left behind when the student started another quiz
+	AbandonDiscarded  AbandonReason = "discarded"  // The student chose to discard it
+)
+
+// SessionPauseAction is an entry in a session's pause trail
+type SessionPauseAction string
+
+const (
+	SessionPaused  SessionPauseAction = "paused"
+	SessionResumed SessionPauseAction = "resumed"
+)
+
+// SessionPauseEvent records an admin pausing or resuming a session, e.g. for a proctor
+// intervention or an outage
+type SessionPauseEvent struct {
+	Action        SessionPauseAction `json:"action" bson:"action"`
+	Reason        string             `json:"reason" bson:"reason"`
+	At            time.Time          `json:"at" bson:"at"`
+	By            primitive.ObjectID `json:"by" bson:"by"`
+	ByEmail       string             `json:"by_email" bson:"by_email"`
+	TimeRemaining int64              `json:"time_remaining" bson:"time_remaining"` // Seconds left at the time
+}
+
+// AbandonedSessionIdleTime is how long an in-progress session may go without activity before it is abandoned
+const AbandonedSessionIdleTime = time.Hour
+
 // QuizSession represents an active quiz session
 type QuizSession struct {
 	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
@@ -30,11 +62,73 @@ type QuizSession struct {
 	// Questions
 	Questions []SessionQuestion `json:"questions" bson:"questions"`
 
+	// Language requested when starting; questions without a translation in it use the default language
+	Language string `json:"language,omitempty" bson:"language,omitempty"`
+
 	// Timing
 	StartTime     time.Time  `json:"start_time" bson:"start_time"`
 	EndTime       *time.Time `json:"end_time,omitempty" bson:"end_time,omitempty"`
 	TimeRemaining int64      `json:"time_remaining" bson:"time_remaining"` // seconds left
 
+	// Set while an admin has the session paused. Paused time does not count against the time
+	// limit; PausedSeconds totals the pauses already resumed.
+	PausedAt      *time.Time          `json:"paused_at,omitempty" bson:"paused_at,omitempty"`
+	PausedSeconds int64               `json:"paused_seconds,omitempty" bson:"paused_seconds,omitempty"`
+	PauseEvents   []SessionPauseEvent `json:"pause_events,omitempty" bson:"pause_events,omitempty"`
+
+	// Integrity events reported by the client for proctoring review, up to SessionIntegrityMaxEvents,
+	// and how many were reported in all. Only admins see them.
+	IntegrityEvents     []IntegrityEvent `json:"-" bson:"integrity_events,omitempty"`
+	IntegrityEventCount int              `json:"-" bson:"integrity_event_count,omitempty"`
+
+	// Client that started the session, used for attendance reconciliation
+	StartIP        string `json:"start_ip,omitempty" bson:"start_ip,omitempty"`
+	StartUserAgent string `json:"start_user_agent,omitempty" bson:"start_user_agent,omitempty"`
+
+	// Set when the attempt was taken offline and uploaded from an exam package
+	OfflinePackageID *primitive.ObjectID `json:"offline_package_id,omitempty" bson:"offline_package_id,omitempty"`
+
+	// Set when the session belongs to an exam sitting that requires a lockdown browser
+	LockdownSittingID *primitive.ObjectID `json:"lockdown_sitting_id,omitempty" bson:"lockdown_sitting_id,omitempty"`
+
+	// Set when the student was seated in an exam sitting of this quiz type when starting
+	ExamSittingID *primitive.ObjectID `json:"exam_sitting_id,omitempty" bson:"exam_sitting_id,omitempty"`
+
+	// Set when the session was joined from a class quiz, with the name shown on its results board
+	ClassQuizID *primitive.ObjectID `json:"class_quiz_id,omitempty" bson:"class_quiz_id,omitempty"`
+	DisplayName string              `json:"display_name,omitempty" bson:"display_name,omitempty"`
+
+	// Set for team quizzes. Members are those in the team when the session started; all of them
+	// are credited with the result, but only the captain can submit.
+	TeamID        *primitive.ObjectID  `json:"team_id,omitempty" bson:"team_id,omitempty"`
+	TeamName      string               `json:"team_name,omitempty" bson:"team_name,omitempty"`
+	TeamMemberIDs []primitive.ObjectID `json:"team_member_ids,omitempty" bson:"team_member_ids,omitempty"`
+	TeamCaptainID *primitive.ObjectID  `json:"team_captain_id,omitempty" bson:"team_captain_id,omitempty"`
+
+	// Started by an anonymous guest; UserID is the guest ID until the results are claimed
+	IsGuest bool `json:"is_guest,omitempty" bson:"is_guest,omitempty"`
+
+	// Template the session was built from and a copy of its scoring rules; nil for sessions
+	// created before templates, which score by the quiz type's built-in rules
+	TemplateID   *primitive.ObjectID `json:"template_id,omitempty" bson:"template_id,omitempty"`
+	TemplateName string              `json:"template_name,omitempty" bson:"template_name,omitempty"`
+	Scoring      *QuizScoring        `json:"scoring,omitempty" bson:"scoring,omitempty"`
+
+	// Modules the template assesses, as they were when the session started
+	ModuleIDs []primitive.ObjectID `json:"-" bson:"module_ids,omitempty"`
+
+	// Copied from the template; results of the session keep their answer key from students
+	HideCorrectAnswers bool `json:"hide_correct_answers,omitempty" bson:"hide_correct_answers,omitempty"`
+
+	// Set for adaptive practice, whose questions are added one at a time up to TotalQuestions
+	Adaptive *AdaptiveState `json:"adaptive,omitempty" bson:"adaptive,omitempty"`
+
+	// Set for custom quizzes, with the filters the student chose
+	CustomFilters *CustomQuizFilters `json:"custom_filters,omitempty" bson:"custom_filters,omitempty"`
+
+	// Set for sessions taken in an embed widget; UserID is then an anonymous visitor ID
+	EmbedWidgetID *primitive.ObjectID `json:"embed_widget_id,omitempty" bson:"embed_widget_id,omitempty"`
+
 	// Progress
 	CurrentQuestion int `json:"current_question" bson:"current_question"` // 0-based index
 	AnsweredCount   int `json:"answered_count" bson:"answered_count"`
@@ -44,26 +138,80 @@ type QuizSession struct {
 	Status      QuizStatus `json:"status" bson:"status"`
 	IsSubmitted bool       `json:"is_submitted" bson:"is_submitted"`
 
+	// Set when the session ended without the student submitting it
+	AbandonReason AbandonReason `json:"abandon_reason,omitempty" bson:"abandon_reason,omitempty"`
+	AbandonedAt   *time.Time    `json:"abandoned_at,omitempty" bson:"abandoned_at,omitempty"`
+
 	// Metadata
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
+// IsTakenBy reports whether the user is taking the session, alone or as a member of its team
+func (s *QuizSession) IsTakenBy(userID primitive.ObjectID) bool {
+	if s.UserID == userID {
+		return true
+	}
+	for _, memberID := range s.TeamMemberIDs {
+		if memberID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// PausedDuration is how long the session has spent paused by now, including a pause still running
+func (s *QuizSession) PausedDuration(now time.Time) time.Duration {
+	paused := time.Duration(s.PausedSeconds) * time.Second
+	if s.PausedAt != nil && now.After(*s.PausedAt) {
+		paused += now.Sub(*s.PausedAt)
+	}
+	return paused
+}
+
+// Deadline is when the session's time runs out, pushed back by the time spent paused.
+// While the session is paused, the deadline moves with now so the time remaining stays frozen.
+func (s *QuizSession) Deadline(now time.Time) time.Time {
+	return s.StartTime.Add(time.Duration(s.TimeLimitMinutes)*time.Minute + s.PausedDuration(now))
+}
+
 // SessionQuestion represents a question in a quiz session with user's answer
 type SessionQuestion struct {
 	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
 	Title      string             `json:"title" bson:"title"`
+	TitleHTML  string             `json:"title_html,omitempty" bson:"title_html,omitempty"` // Rendered when the session was built
 	Type       QuestionType       `json:"type" bson:"type"`
 	Difficulty DifficultyLevel    `json:"difficulty" bson:"difficulty"`
 	Points     int                `json:"points" bson:"points"`
 
+	// The question version the session was built from, kept frozen for the result
+	QuestionVersion int `json:"question_version,omitempty" bson:"question_version,omitempty"`
+
+	// Language the wording is in, set when the session asked for one
+	Language string `json:"language,omitempty" bson:"language,omitempty"`
+
+	// Values drawn for a parameterized question, already filled into the title
+	Variables map[string]float64 `json:"variables,omitempty" bson:"variables,omitempty"`
+
+	Media []QuestionMedia `json:"media,omitempty" bson:"media,omitempty"`
+	Audio *QuestionAudio  `json:"audio,omitempty" bson:"audio,omitempty"`
+
 	// Shuffled options for this session
-	Options        []Option `json:"options" bson:"options"`
-	CorrectAnswers []string `json:"-" bson:"correct_answers"` // Hidden from frontend
-	SampleAnswer   string   `json:"-" bson:"sample_answer"`   // Hidden from frontend, for essay questions
+	Options        []Option       `json:"options" bson:"options"`
+	CorrectAnswers []string       `json:"-" bson:"correct_answers"`             // Hidden from frontend
+	SampleAnswer   string         `json:"-" bson:"sample_answer"`               // Hidden from frontend, for essay questions
+	Blanks         []Blank        `json:"-" bson:"blanks,omitempty"`            // Hidden from frontend; placeholders in Title mark the gaps
+	NumericAnswer  *NumericAnswer `json:"-" bson:"numeric_answer,omitempty"`    // Hidden from frontend
+	Unit           string         `json:"unit,omitempty" bson:"unit,omitempty"` // Unit of a numeric answer, shown after the input
+	// How an ordering question is scored, so students know whether a partly right order earns points
+	OrderingScoring OrderingScoring `json:"ordering_scoring,omitempty" bson:"ordering_scoring,omitempty"`
+	Sections        []SectionRef    `json:"-" bson:"sections,omitempty"`    // Hidden from frontend until the result review
+	Explanation     string          `json:"-" bson:"explanation,omitempty"` // Hidden from frontend until the result review
+
+	ModuleIDs []primitive.ObjectID `json:"-" bson:"module_ids,omitempty"` // Modules the question assesses
 
 	// User's response
-	UserAnswer   interface{} `json:"user_answer,omitempty" bson:"user_answer,omitempty"` // string or []string
+	UserAnswer   interface{} `json:"user_answer,omitempty" bson:"user_answer,omitempty"` // string, []string (option IDs in order for ordering), blank ID -> text for fill-in-the-blank, or a number
 	IsAnswered   bool        `json:"is_answered" bson:"is_answered"`
 	IsSkipped    bool        `json:"is_skipped" bson:"is_skipped"`
 	IsCorrect    bool        `json:"is_correct" bson:"is_correct"`
@@ -83,6 +231,11 @@ type DetailedQuizResult struct {
 	QuizResult `bson:",inline"`   // Embed existing QuizResult
 	SessionID  primitive.ObjectID `json:"session_id" bson:"session_id"`
 
+	// Set for team quizzes; every member is credited, UserID is the member who started the session
+	TeamID        *primitive.ObjectID  `json:"team_id,omitempty" bson:"team_id,omitempty"`
+	TeamName      string               `json:"team_name,omitempty" bson:"team_name,omitempty"`
+	TeamMemberIDs []primitive.ObjectID `json:"team_member_ids,omitempty" bson:"team_member_ids,omitempty"`
+
 	// Enhanced Scoring (extends the basic 0-100 score)
 	TotalPoints     int     `json:"total_points" bson:"total_points"`         // Max possible points
 	EarnedPoints    int     `json:"earned_points" bson:"earned_points"`       // Points earned
@@ -109,16 +262,77 @@ type DetailedQuizResult struct {
 	// Status
 	CompletionStatus QuizStatus `json:"completion_status" bson:"completion_status"`
 	SubmittedAt      time.Time  `json:"submitted_at" bson:"submitted_at"`
+
+	// Essay answers still to be graded; scores leave them out until they are
+	PendingEssays int `json:"pending_essays,omitempty" bson:"pending_essays"`
+
+	// Instructor review of exam sitting results near the pass mark; only shown to admins
+	Moderation *ResultModeration `json:"-" bson:"moderation,omitempty"`
+
+	// Client-reported integrity events during the attempt; only shown to admins
+	Integrity *IntegritySummary `json:"-" bson:"integrity,omitempty"`
+
+	// Raw scores of a result curved by its sitting's scaling
+	Scaling *ResultScaling `json:"scaling,omitempty" bson:"scaling,omitempty"`
+
+	// Set for quizzes whose questions are reused, so students never see the answer key
+	CorrectAnswersHidden bool `json:"correct_answers_hidden,omitempty" bson:"correct_answers_hidden,omitempty"`
+
+	// Sections to study for the missed questions, filled in when the result is reviewed
+	Remediation *RemediationSummary `json:"remediation,omitempty" bson:"-"`
+}
+
+// BelongsTo reports whether the result is the user's own or one their team earned with them
+func (r *DetailedQuizResult) BelongsTo(userID primitive.ObjectID) bool {
+	if r.UserID == userID {
+		return true
+	}
+	for _, memberID := range r.TeamMemberIDs {
+		if memberID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Withhold blanks scores and answer review while the sitting's results are embargoed
+func (r *DetailedQuizResult) Withhold(releaseAt *time.Time) {
+	r.QuizResult.Withhold(releaseAt)
+	r.EarnedPoints = 0
+	r.TimeBonus = 0
+	r.FinalScore = 0
+	r.ScorePercentage = 0
+	r.EasyCorrect = 0
+	r.MediumCorrect = 0
+	r.HardCorrect = 0
+	r.QuestionResults = nil
+	r.Scaling = nil
+	r.Remediation = nil
+}
+
+// HideAnswerKey removes correct answers and explanations from a result shown to a student whose
+// quiz keeps them hidden. Students still see what they chose and whether it was right.
+func (r *DetailedQuizResult) HideAnswerKey() {
+	if !r.CorrectAnswersHidden {
+		return
+	}
+	for i := range r.QuestionResults {
+		r.QuestionResults[i].CorrectAnswer = nil
+		r.QuestionResults[i].Explanation = ""
+	}
 }
 
 // QuestionResult represents the result for a specific question
 type QuestionResult struct {
 	QuestionID primitive.ObjectID `json:"question_id" bson:"question_id"`
 	Title      string             `json:"title" bson:"title"`
+	TitleHTML  string             `json:"title_html,omitempty" bson:"title_html,omitempty"` // Rendered when the session was built
 	Type       QuestionType       `json:"type" bson:"type"`
 	Difficulty DifficultyLevel    `json:"difficulty" bson:"difficulty"`
 	Points     int                `json:"points" bson:"points"`
 
+	QuestionVersion int `json:"question_version,omitempty" bson:"question_version,omitempty"`
+
 	UserAnswer    interface{} `json:"user_answer" bson:"user_answer"`
 	CorrectAnswer interface{} `json:"correct_answer" bson:"correct_answer"`
 	IsCorrect     bool        `json:"is_correct" bson:"is_correct"`
@@ -126,14 +340,47 @@ type QuestionResult struct {
 	PointsEarned  int         `json:"points_earned" bson:"points_earned"`
 	TimeSpent     int64       `json:"time_spent" bson:"time_spent"`
 
+	// Fill-in-the-blank: whether each blank was filled correctly, keyed by blank ID
+	BlankResults map[string]bool `json:"blank_results,omitempty" bson:"blank_results,omitempty"`
+
+	// Essays: answered essays await an admin's grade, which then sets PointsEarned
+	PendingGrade bool        `json:"pending_grade,omitempty" bson:"pending_grade,omitempty"`
+	EssayGrade   *EssayGrade `json:"essay_grade,omitempty" bson:"essay_grade,omitempty"`
+
 	// For review purposes - include options
 	Options []Option `json:"options" bson:"options"`
+
+	// Why the correct answer is right; left out when the quiz hides its answer key
+	Explanation string `json:"explanation,omitempty" bson:"explanation,omitempty"`
+
+	// Sections teaching the question; missed questions list the published ones to study when reviewed
+	Sections      []SectionRef   `json:"-" bson:"sections,omitempty"`
+	StudySections []StudySection `json:"study_sections,omitempty" bson:"-"`
+
+	// Modules the answer counts toward: the question's, its sections' and the quiz template's
+	ModuleIDs []primitive.ObjectID `json:"-" bson:"module_ids,omitempty"`
 }
 
 // API Request/Response Models
 
 type StartQuizRequest struct {
-	QuizType QuizType `json:"quiz_type" binding:"required,oneof=mock_test time_quiz"`
+	QuizType   QuizType `json:"quiz_type,omitempty" binding:"required_without=TemplateID,omitempty,oneof=mock_test time_quiz adaptive_practice"`
+	TemplateID string   `json:"template_id,omitempty"` // Quiz template to use; defaults to the quiz type's default template
+	Lang       string   `json:"lang,omitempty"`        // Language to serve questions in, where translated
+
+	// Filled in by the controller from the request
+	IPAddress string             `json:"-"`
+	UserAgent string             `json:"-"`
+	Lockdown  *LockdownHandshake `json:"-"`
+	IsGuest   bool               `json:"-"`
+}
+
+// LockdownHandshake is the signature a lockdown browser attaches to a request
+type LockdownHandshake struct {
+	Timestamp string
+	Signature string
+	Method    string
+	Path      string
 }
 
 type StartQuizResponse struct {
@@ -143,9 +390,12 @@ type StartQuizResponse struct {
 }
 
 type SaveAnswerRequest struct {
-	QuestionIndex int         `json:"question_index" binding:"required"`
+	QuestionIndex int         `json:"question_index" binding:"min=0"`
 	Answer        interface{} `json:"answer" binding:"required"`
-	TimeSpent     int64       `json:"time_spent" binding:"required"` // seconds spent on this question
+	TimeSpent     int64       `json:"time_spent" binding:"min=0"` // seconds spent on this question
+
+	// Filled in by the controller from the request
+	Lockdown *LockdownHandshake `json:"-"`
 }
 
 type SaveAnswerResponse struct {
@@ -155,6 +405,52 @@ type SaveAnswerResponse struct {
 	PointsEarned  int         `json:"points_earned,omitempty"`  // Only for TimeQuiz immediate feedback
 	SampleAnswer  string      `json:"sample_answer,omitempty"`  // For essay questions in TimeQuiz
 	Message       string      `json:"message"`
+
+	// Adaptive practice only: the difficulty after this answer and the question it drew, appended
+	// to the session's questions. NextQuestion is nil once the last question has been answered.
+	Adaptive     *AdaptiveState   `json:"adaptive,omitempty"`
+	NextQuestion *SessionQuestion `json:"next_question,omitempty"`
+}
+
+// BatchAnswerItem is an answer the client buffered while offline, stamped with when it was given
+type BatchAnswerItem struct {
+	QuestionIndex   int         `json:"question_index" binding:"min=0"`
+	Answer          interface{} `json:"answer" binding:"required"`
+	TimeSpent       int64       `json:"time_spent" binding:"min=0"` // seconds spent on this question
+	ClientTimestamp time.Time   `json:"client_timestamp" binding:"required"`
+}
+
+// SaveAnswersBatchRequest replays buffered answers. Each answer only replaces the stored one if it
+// was given later, so replaying the same batch twice, or out of order, leaves the same state.
+type SaveAnswersBatchRequest struct {
+	Answers []BatchAnswerItem `json:"answers" binding:"required,min=1,max=200,dive"`
+
+	// Filled in by the controller from the request
+	Lockdown *LockdownHandshake `json:"-"`
+}
+
+// BatchAnswerStatus is what happened to one answer in a batch
+type BatchAnswerStatus string
+
+const (
+	BatchAnswerApplied  BatchAnswerStatus = "applied"  // Stored
+	BatchAnswerStale    BatchAnswerStatus = "stale"    // A later answer is already stored, or this one was replayed
+	BatchAnswerRejected BatchAnswerStatus = "rejected" // Invalid; see error
+)
+
+type BatchAnswerResult struct {
+	QuestionIndex   int               `json:"question_index"`
+	ClientTimestamp time.Time         `json:"client_timestamp"`
+	Status          BatchAnswerStatus `json:"status"`
+	Error           string            `json:"error,omitempty"`
+}
+
+type SaveAnswersBatchResponse struct {
+	Results       []BatchAnswerResult `json:"results"` // In request order
+	Applied       int                 `json:"applied"`
+	Stale         int                 `json:"stale"`
+	Rejected      int                 `json:"rejected"`
+	TimeRemaining int64               `json:"time_remaining"` // seconds
 }
 
 type NavigateQuestionRequest struct {
@@ -162,8 +458,8 @@ type NavigateQuestionRequest struct {
 }
 
 type SkipQuestionRequest struct {
-	QuestionIndex int   `json:"question_index" binding:"required"`
-	TimeSpent     int64 `json:"time_spent" binding:"required"` // seconds spent on this question
+	QuestionIndex int   `json:"question_index" binding:"min=0"`
+	TimeSpent     int64 `json:"time_spent" binding:"min=0"` // seconds spent on this question
 }
 
 type SubmitQuizRequest struct {
@@ -171,8 +467,78 @@ type SubmitQuizRequest struct {
 }
 
 type SubmitQuizResponse struct {
-	Result  DetailedQuizResult `json:"result"`
-	Message string             `json:"message"`
+	Result     DetailedQuizResult `json:"result"`
+	Comparison *ScoreComparison   `json:"comparison,omitempty"` // Left out while the result is withheld or has too few peers
+	Message    string             `json:"message"`
+}
+
+// QuizResultResponse is one of the student's results with how it compares to recent results
+type QuizResultResponse struct {
+	Result     DetailedQuizResult `json:"result"`
+	Comparison *ScoreComparison   `json:"comparison,omitempty"`
+}
+
+// QuizReview walks through a submitted result question by question. Correct answers and
+// explanations are left out when the quiz hides its answer key.
+type QuizReview struct {
+	ResultID             primitive.ObjectID   `json:"result_id"`
+	QuizType             QuizType             `json:"quiz_type"`
+	ScorePercentage      float64              `json:"score_percentage"`
+	PendingEssays        int                  `json:"pending_essays,omitempty"`
+	CorrectAnswersHidden bool                 `json:"correct_answers_hidden"`
+	Questions            []QuestionReviewItem `json:"questions"`
+}
+
+// QuestionReviewItem is one question of a quiz review with what the student chose against the correct answer
+type QuestionReviewItem struct {
+	QuestionID    primitive.ObjectID `json:"question_id"`
+	Title         string             `json:"title"`
+	Type          QuestionType       `json:"type"`
+	Difficulty    DifficultyLevel    `json:"difficulty"`
+	Points        int                `json:"points"`
+	PointsEarned  int                `json:"points_earned"`
+	Options       []Option           `json:"options,omitempty"`
+	ChosenAnswer  interface{}        `json:"chosen_answer"`
+	CorrectAnswer interface{}        `json:"correct_answer,omitempty"`
+	IsCorrect     bool               `json:"is_correct"`
+	IsSkipped     bool               `json:"is_skipped"`
+	PendingGrade  bool               `json:"pending_grade,omitempty"`
+	BlankResults  map[string]bool    `json:"blank_results,omitempty"`
+	Explanation   string             `json:"explanation,omitempty"`
+
+	// Published module sections linked to the question, whether or not it was missed
+	Modules []StudySection `json:"modules"`
+}
+
+// ScoreComparison places a result's score among recent practice results of the same quiz type.
+// Exam sitting results are left out of the peers, and only aggregates are reported.
+type ScoreComparison struct {
+	QuizType    QuizType `json:"quiz_type"`
+	WindowDays  int      `json:"window_days"`
+	PeerCount   int64    `json:"peer_count"`
+	Percentile  float64  `json:"percentile"` // Share of peers scoring below, with ties counted half
+	MeanScore   float64  `json:"mean_score"`
+	MedianScore float64  `json:"median_score"`
+}
+
+// ResolveAbandonedSessionRequest is a student's choice for a session they left unfinished:
+// resume an abandoned session that still has time left, or discard it for good
+type ResolveAbandonedSessionRequest struct {
+	Action string `json:"action" binding:"required,oneof=resume discard"`
+
+	// Filled in by the controller from the request
+	Lockdown *LockdownHandshake `json:"-"`
+}
+
+// SessionPauseRequest is an admin pausing or resuming a session, with the reason kept in its pause trail
+type SessionPauseRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ResolveAbandonedSessionResponse struct {
+	Session       QuizSession `json:"session"`
+	TimeRemaining int64       `json:"time_remaining"`
+	Message       string      `json:"message"`
 }
 
 type GetSessionResponse struct {
@@ -181,63 +547,29 @@ type GetSessionResponse struct {
 	IsExpired     bool        `json:"is_expired"`
 }
 
-// Quiz Configuration for different types
-type QuizConfig struct {
-	Type             QuizType `json:"type"`
-	MaxPoints        int      `json:"max_points"`
-	TimeLimitMinutes int      `json:"time_limit_minutes"`
-	EasyQuestions    int      `json:"easy_questions"`
-	MediumQuestions  int      `json:"medium_questions"`
-	HardQuestions    int      `json:"hard_questions"`
-	TotalQuestions   int      `json:"total_questions"`
-	EasyPoints       int      `json:"easy_points"`   // Points per easy question
-	MediumPoints     int      `json:"medium_points"` // Points per medium question
-	HardPoints       int      `json:"hard_points"`   // Points per hard question
-}
-
-// GetQuizConfig returns configuration for different quiz types
-func GetQuizConfig(quizType QuizType) QuizConfig {
-	switch quizType {
-	case MockTest:
-		return QuizConfig{
-			Type:             MockTest,
-			MaxPoints:        1000,
-			TimeLimitMinutes: 60,
-			EasyPoints:       10, // Easy questions worth 10 points
-			MediumPoints:     15, // Medium questions worth 15 points
-			HardPoints:       25, // Hard questions worth 25 points
-			// Questions will be dynamically allocated to reach ~1000 points
-			// Target: ~50-100 questions total (10-25 points each = 1000 points)
-			TotalQuestions: 0, // Will be calculated based on available questions
-		}
-	case TimeQuiz:
-		return QuizConfig{
-			Type:             TimeQuiz,
-			MaxPoints:        200, // 10*10 + 5*15 + 5*25 = 300 points
-			TimeLimitMinutes: 5,
-			EasyQuestions:    10,
-			MediumQuestions:  5,
-			HardQuestions:    5,
-			TotalQuestions:   20,
-			EasyPoints:       10,
-			MediumPoints:     15,
-			HardPoints:       25,
-		}
-	default:
-		return QuizConfig{}
-	}
+// MediaManifestItem is one media file a session needs, with the questions that use it
+type MediaManifestItem struct {
+	URL             string    `json:"url"`
+	Type            MediaType `json:"type"`
+	QuestionIndexes []int     `json:"question_indexes"` // Zero-based positions in the session
+}
+
+// MediaManifestResponse lists every media URL in a session so clients can prefetch them up front
+type MediaManifestResponse struct {
+	SessionToken string              `json:"session_token"`
+	Items        []MediaManifestItem `json:"items"`
+	TotalItems   int                 `json:"total_items"`
 }
 
-// CalculateTimeBonus calculates time bonus for TimeQuiz
-func CalculateTimeBonus(timeLeftSeconds int64, maxBonus int) int {
-	if timeLeftSeconds <= 0 {
+// CalculateTimeBonus calculates the time bonus for a quiz whose scoring rules award one
+func CalculateTimeBonus(timeLeftSeconds, timeLimitSeconds int64, maxBonus int) int {
+	if timeLeftSeconds <= 0 || timeLimitSeconds <= 0 {
 		return 0
 	}
 
 	// Time bonus formula: up to maxBonus points based on time remaining
 	// More time left = more bonus (linear scale)
-	maxTimeSeconds := int64(5 * 60) // 5 minutes
-	bonusPercentage := float64(timeLeftSeconds) / float64(maxTimeSeconds)
+	bonusPercentage := float64(timeLeftSeconds) / float64(timeLimitSeconds)
 	bonus := int(bonusPercentage * float64(maxBonus))
 
 	if bonus > maxBonus {
@@ -0,0 +1,330 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PointsSource decides where the questions of a quiz get their points from
+type PointsSource string
+
+const (
+	PointsFromQuestion PointsSource = "question"   // Each question's own points
+	PointsByDifficulty PointsSource = "difficulty" // The template's points per difficulty
+)
+
+// AttemptScoring decides which of a student's attempts at a template count toward their stats
+type AttemptScoring string
+
+const (
+	AttemptScoringAll     AttemptScoring = "all"     // Every attempt counts; the default
+	AttemptScoringBest    AttemptScoring = "best"    // Only the highest-scoring attempt
+	AttemptScoringLast    AttemptScoring = "last"    // Only the most recent attempt
+	AttemptScoringAverage AttemptScoring = "average" // The attempts count once, at their average
+)
+
+// MinMixedQuestions is the smallest pool a mixed-difficulty draw settles for when the bank is short
+const MinMixedQuestions = 10
+
+// QuizScoring holds a template's scoring rules. Sessions keep a copy, so editing a template
+// does not change attempts already in progress.
+type QuizScoring struct {
+	PointsSource PointsSource `json:"points_source" bson:"points_source"`
+	EasyPoints   int          `json:"easy_points,omitempty" bson:"easy_points,omitempty"` // Used with PointsByDifficulty
+	MediumPoints int          `json:"medium_points,omitempty" bson:"medium_points,omitempty"`
+	HardPoints   int          `json:"hard_points,omitempty" bson:"hard_points,omitempty"`
+	TimeBonusMax int          `json:"time_bonus_max" bson:"time_bonus_max"` // Extra points for finishing early, scaled by time left; 0 disables
+
+	// Which attempts count toward the student's stats; every attempt when empty
+	AttemptScoring AttemptScoring `json:"attempt_scoring,omitempty" bson:"attempt_scoring,omitempty"`
+}
+
+// PointsFor returns what a question is worth under these rules
+func (s QuizScoring) PointsFor(q *Question) int {
+	if s.PointsSource != PointsByDifficulty {
+		return q.Points
+	}
+	switch q.Difficulty {
+	case Easy:
+		return s.EasyPoints
+	case Medium:
+		return s.MediumPoints
+	case Hard:
+		return s.HardPoints
+	}
+	return q.Points
+}
+
+// QuizTemplate defines how a quiz is assembled and scored
+type QuizTemplate struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+
+	// Session type the template runs as; history, stats and exam sittings are grouped by it
+	QuizType QuizType `json:"quiz_type" bson:"quiz_type"`
+
+	TimeLimitMinutes int `json:"time_limit_minutes" bson:"time_limit_minutes"`
+
+	// Attempts each student may start from the template; 0 allows any number
+	MaxAttempts int `json:"max_attempts" bson:"max_attempts"`
+
+	// Time a student waits after starting a quiz of the template's type before starting another; 0 disables
+	RetakeCooldownMinutes int `json:"retake_cooldown_minutes" bson:"retake_cooldown_minutes"`
+
+	// Keeps correct answers and explanations out of students' results, for quizzes whose questions are reused
+	HideCorrectAnswers bool `json:"hide_correct_answers" bson:"hide_correct_answers"`
+
+	// Questions drawn per difficulty, plus MixedQuestions drawn from every difficulty at random
+	EasyQuestions   int `json:"easy_questions" bson:"easy_questions"`
+	MediumQuestions int `json:"medium_questions" bson:"medium_questions"`
+	HardQuestions   int `json:"hard_questions" bson:"hard_questions"`
+	MixedQuestions  int `json:"mixed_questions" bson:"mixed_questions"`
+
+	Scoring QuizScoring `json:"scoring" bson:"scoring"`
+
+	// Modules the quiz assesses; every question of a session from the template counts toward them
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" bson:"module_ids,omitempty"`
+
+	IsActive  bool `json:"is_active" bson:"is_active"`
+	IsDefault bool `json:"is_default" bson:"is_default"` // Used when a quiz of QuizType is started without a template
+	IsBuiltin bool `json:"is_builtin,omitempty" bson:"-"`
+
+	// Metadata
+	CreatedBy primitive.ObjectID `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// TotalQuestions is the number of questions a quiz from the template asks for
+func (t *QuizTemplate) TotalQuestions() int {
+	return t.EasyQuestions + t.MediumQuestions + t.HardQuestions + t.MixedQuestions
+}
+
+// PoolRequirement is how many active questions of a difficulty a template needs against how many exist.
+// Difficulty "mixed" covers the questions left over once the per-difficulty draws are made.
+type PoolRequirement struct {
+	Difficulty string `json:"difficulty"`
+	Required   int    `json:"required"`
+	Available  int    `json:"available"`
+}
+
+// QuestionPoolStatus reports whether the question bank can fill a template
+type QuestionPoolStatus struct {
+	TemplateID   primitive.ObjectID `json:"template_id,omitempty"`
+	TemplateName string             `json:"template_name"`
+	QuizType     QuizType           `json:"quiz_type"`
+	IsDefault    bool               `json:"is_default"`
+	IsBuiltin    bool               `json:"is_builtin,omitempty"`
+	Ready        bool               `json:"ready"`
+	Shortfalls   []PoolRequirement  `json:"shortfalls"`
+}
+
+// CheckPool compares the template's draws with the active questions available per difficulty.
+// Mixed draws accept a smaller pool, down to MinMixedQuestions.
+func (t *QuizTemplate) CheckPool(available map[DifficultyLevel]int) *QuestionPoolStatus {
+	status := &QuestionPoolStatus{
+		TemplateID:   t.ID,
+		TemplateName: t.Name,
+		QuizType:     t.QuizType,
+		IsDefault:    t.IsDefault,
+		IsBuiltin:    t.IsBuiltin,
+		Shortfalls:   []PoolRequirement{},
+	}
+
+	leftover := 0
+	for _, need := range []struct {
+		difficulty DifficultyLevel
+		count      int
+	}{
+		{Easy, t.EasyQuestions},
+		{Medium, t.MediumQuestions},
+		{Hard, t.HardQuestions},
+	} {
+		have := available[need.difficulty]
+		if have < need.count {
+			status.Shortfalls = append(status.Shortfalls, PoolRequirement{
+				Difficulty: string(need.difficulty),
+				Required:   need.count,
+				Available:  have,
+			})
+		}
+		leftover += max(0, have-need.count)
+	}
+
+	if t.MixedQuestions > 0 {
+		required := min(t.MixedQuestions, MinMixedQuestions)
+		if leftover < required {
+			status.Shortfalls = append(status.Shortfalls, PoolRequirement{
+				Difficulty: "mixed",
+				Required:   required,
+				Available:  leftover,
+			})
+		}
+	}
+
+	status.Ready = len(status.Shortfalls) == 0
+	return status
+}
+
+// QuestionPoolReport lists the templates the question bank cannot fill
+type QuestionPoolReport struct {
+	Available map[DifficultyLevel]int `json:"available"` // Active questions per difficulty
+	Ready     bool                    `json:"ready"`     // Every active template can be filled
+	Templates []QuestionPoolStatus    `json:"templates"`
+}
+
+// DefaultQuizTemplate returns the built-in definition of a quiz type, used until an admin
+// marks a template as the default for it
+func DefaultQuizTemplate(quizType QuizType) *QuizTemplate {
+	switch quizType {
+	case MockTest:
+		return &QuizTemplate{
+			Name:             "Mock Test",
+			QuizType:         MockTest,
+			TimeLimitMinutes: 60,
+			MixedQuestions:   100,
+			Scoring:          QuizScoring{PointsSource: PointsFromQuestion},
+			IsActive:         true,
+			IsDefault:        true,
+			IsBuiltin:        true,
+		}
+	case TimeQuiz:
+		return &QuizTemplate{
+			Name:             "Time Quiz",
+			QuizType:         TimeQuiz,
+			TimeLimitMinutes: 5,
+			EasyQuestions:    10,
+			MediumQuestions:  5,
+			HardQuestions:    5,
+			Scoring:          QuizScoring{PointsSource: PointsFromQuestion, TimeBonusMax: 50},
+			IsActive:         true,
+			IsDefault:        true,
+			IsBuiltin:        true,
+		}
+	case Practice:
+		// Questions come from the user's bookmarks
+		return &QuizTemplate{
+			Name:             "Practice",
+			QuizType:         Practice,
+			TimeLimitMinutes: 30,
+			Scoring: QuizScoring{
+				PointsSource: PointsByDifficulty,
+				EasyPoints:   10,
+				MediumPoints: 15,
+				HardPoints:   25,
+			},
+			IsActive:  true,
+			IsDefault: true,
+			IsBuiltin: true,
+		}
+	case CustomQuiz:
+		// Questions match the student's chosen tags; the time limit is set from the question count
+		return &QuizTemplate{
+			Name:     "Custom Quiz",
+			QuizType: CustomQuiz,
+			Scoring: QuizScoring{
+				PointsSource: PointsByDifficulty,
+				EasyPoints:   10,
+				MediumPoints: 15,
+				HardPoints:   25,
+			},
+			IsActive:  true,
+			IsDefault: true,
+			IsBuiltin: true,
+		}
+	case EmbedQuiz:
+		// Questions and time limit come from the embed widget's settings
+		return &QuizTemplate{
+			Name:     "Embedded Quiz",
+			QuizType: EmbedQuiz,
+			Scoring: QuizScoring{
+				PointsSource: PointsByDifficulty,
+				EasyPoints:   10,
+				MediumPoints: 15,
+				HardPoints:   25,
+			},
+			IsActive:  true,
+			IsDefault: true,
+			IsBuiltin: true,
+		}
+	case AdaptivePractice:
+		// Questions are drawn one at a time as the student answers; MixedQuestions is how many
+		return &QuizTemplate{
+			Name:             "Adaptive Practice",
+			QuizType:         AdaptivePractice,
+			TimeLimitMinutes: 30,
+			MixedQuestions:   20,
+			Scoring: QuizScoring{
+				PointsSource: PointsByDifficulty,
+				EasyPoints:   10,
+				MediumPoints: 15,
+				HardPoints:   25,
+			},
+			IsActive:  true,
+			IsDefault: true,
+			IsBuiltin: true,
+		}
+	default:
+		return nil
+	}
+}
+
+// Request/Response models for API
+
+// QuizScoringRequest represents a template's scoring rules
+type QuizScoringRequest struct {
+	PointsSource PointsSource `json:"points_source" binding:"required,oneof=question difficulty"`
+	EasyPoints   int          `json:"easy_points,omitempty" binding:"min=0,max=1000"`
+	MediumPoints int          `json:"medium_points,omitempty" binding:"min=0,max=1000"`
+	HardPoints   int          `json:"hard_points,omitempty" binding:"min=0,max=1000"`
+	TimeBonusMax int          `json:"time_bonus_max,omitempty" binding:"min=0,max=1000"`
+
+	AttemptScoring AttemptScoring `json:"attempt_scoring,omitempty" binding:"omitempty,oneof=all best last average"`
+}
+
+// CreateQuizTemplateRequest represents the request to create a quiz template
+type CreateQuizTemplateRequest struct {
+	Name                  string             `json:"name" binding:"required,min=3,max=200"`
+	Description           string             `json:"description,omitempty" binding:"max=1000"`
+	QuizType              QuizType           `json:"quiz_type" binding:"required,oneof=mock_test time_quiz"`
+	TimeLimitMinutes      int                `json:"time_limit_minutes" binding:"required,min=1,max=600"`
+	MaxAttempts           int                `json:"max_attempts,omitempty" binding:"min=0,max=100"`
+	RetakeCooldownMinutes int                `json:"retake_cooldown_minutes,omitempty" binding:"min=0,max=43200"` // Up to 30 days
+	HideCorrectAnswers    bool               `json:"hide_correct_answers,omitempty"`
+	EasyQuestions         int                `json:"easy_questions" binding:"min=0,max=500"`
+	MediumQuestions       int                `json:"medium_questions" binding:"min=0,max=500"`
+	HardQuestions         int                `json:"hard_questions" binding:"min=0,max=500"`
+	MixedQuestions        int                `json:"mixed_questions" binding:"min=0,max=500"`
+	Scoring               QuizScoringRequest `json:"scoring" binding:"required"`
+	IsActive              *bool              `json:"is_active,omitempty"` // Defaults to true
+	IsDefault             bool               `json:"is_default,omitempty"`
+
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" binding:"omitempty,max=20"`
+}
+
+// UpdateQuizTemplateRequest represents the request to update a quiz template; omitted fields are unchanged
+type UpdateQuizTemplateRequest struct {
+	Name                  *string             `json:"name,omitempty" binding:"omitempty,min=3,max=200"`
+	Description           *string             `json:"description,omitempty" binding:"omitempty,max=1000"`
+	TimeLimitMinutes      *int                `json:"time_limit_minutes,omitempty" binding:"omitempty,min=1,max=600"`
+	MaxAttempts           *int                `json:"max_attempts,omitempty" binding:"omitempty,min=0,max=100"`
+	RetakeCooldownMinutes *int                `json:"retake_cooldown_minutes,omitempty" binding:"omitempty,min=0,max=43200"`
+	HideCorrectAnswers    *bool               `json:"hide_correct_answers,omitempty"`
+	EasyQuestions         *int                `json:"easy_questions,omitempty" binding:"omitempty,min=0,max=500"`
+	MediumQuestions       *int                `json:"medium_questions,omitempty" binding:"omitempty,min=0,max=500"`
+	HardQuestions         *int                `json:"hard_questions,omitempty" binding:"omitempty,min=0,max=500"`
+	MixedQuestions        *int                `json:"mixed_questions,omitempty" binding:"omitempty,min=0,max=500"`
+	Scoring               *QuizScoringRequest `json:"scoring,omitempty"`
+	IsActive              *bool               `json:"is_active,omitempty"`
+	IsDefault             *bool               `json:"is_default,omitempty"`
+
+	ModuleIDs []primitive.ObjectID `json:"module_ids,omitempty" binding:"omitempty,max=20"` // An empty list unlinks every module
+}
+
+// ListQuizTemplatesRequest represents the filters for listing templates
+type ListQuizTemplatesRequest struct {
+	QuizType   QuizType `form:"quiz_type" binding:"omitempty,oneof=mock_test time_quiz"`
+	ActiveOnly bool     `form:"active_only"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+)
+
+// Reading time is estimated from the content: prose at an average adult reading speed, code at half
+// of it, and a few seconds to take in each image. Videos are left out, as their length is not known.
+const (
+	ProseWordsPerMinute = 200
+	CodeWordsPerMinute  = 100
+	ImageSeconds        = 12
+)
+
+// ReadingMinutes estimates how long content takes to read, rounded up to whole minutes. Content with
+// anything to read takes at least a minute.
+func ReadingMinutes(blocks []ContentBlock, content string) int {
+	seconds := 0.0
+	for _, block := range legacyBlocks(content, blocks) {
+		switch block.Type {
+		case BlockMarkdown, BlockCallout:
+			text := block.Title + " " + block.Text
+			seconds += float64(len(strings.Fields(text))) * 60 / ProseWordsPerMinute
+			seconds += float64(strings.Count(text, "![") * ImageSeconds)
+		case BlockCode:
+			seconds += float64(len(strings.Fields(block.Code))) * 60 / CodeWordsPerMinute
+		case BlockImage:
+			seconds += ImageSeconds + float64(len(strings.Fields(block.Caption)))*60/ProseWordsPerMinute
+		}
+	}
+	if seconds == 0 {
+		return 0
+	}
+	return max(int((seconds+59)/60), 1)
+}
+
+// EstimateReadingTime fills in the reading time of the module's page and each submodule, and of the
+// whole module as learners read it: its page and every published submodule
+func (m *Module) EstimateReadingTime() {
+	m.ReadingMinutes = ReadingMinutes(m.Blocks, m.Content)
+	m.TotalReadingMinutes = m.ReadingMinutes
+	for i := range m.SubModules {
+		sub := &m.SubModules[i]
+		sub.ReadingMinutes = ReadingMinutes(sub.Blocks, sub.Content)
+		if m.SubModulePublished(sub.ID) {
+			m.TotalReadingMinutes += sub.ReadingMinutes
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SectionRef points a question at the module section that teaches it
+type SectionRef struct {
+	ModuleID    primitive.ObjectID `json:"module_id" bson:"module_id" binding:"required"`
+	SubModuleID primitive.ObjectID `json:"submodule_id" bson:"submodule_id" binding:"required"`
+}
+
+// StudySection is a published module section recommended to a student, with where to read it
+type StudySection struct {
+	ModuleID      primitive.ObjectID `json:"module_id"`
+	SubModuleID   primitive.ObjectID `json:"submodule_id"`
+	ModuleName    string             `json:"module_name"`
+	SubModuleName string             `json:"submodule_name"`
+	Link          string             `json:"link"` // Frontend documentation page of the section
+}
+
+// RemediationSection is a section in a result's remediation summary with the missed questions it covers
+type RemediationSection struct {
+	StudySection
+	MissedQuestions int   `json:"missed_questions"`
+	PointsMissed    int   `json:"points_missed"`
+	QuestionIndexes []int `json:"question_indexes"` // Zero-based positions in the result
+}
+
+// RemediationSummary collects the sections behind a result's missed questions, most missed first
+type RemediationSummary struct {
+	MissedQuestions int                  `json:"missed_questions"`
+	UnlinkedMissed  int                  `json:"unlinked_missed"` // Missed questions with no published section to study
+	Sections        []RemediationSection `json:"sections"`
+}
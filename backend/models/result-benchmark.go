@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResultBenchmarkMinPeers is the fewest peer results a benchmark is drawn from, so that no single
+// student's score can be worked out from it
+const ResultBenchmarkMinPeers = 5
+
+// BenchmarkPeerGroup is who a result is compared against
+type BenchmarkPeerGroup string
+
+const (
+	BenchmarkByExamSitting BenchmarkPeerGroup = "exam_sitting" // Everyone in the same exam sitting
+	BenchmarkByQuizType    BenchmarkPeerGroup = "quiz_type"    // Recent practice attempts of the same quiz type
+)
+
+// BenchmarkPeerFilter selects the peer results of a benchmark
+type BenchmarkPeerFilter struct {
+	ExamSittingID *primitive.ObjectID
+	QuizType      QuizType
+	Since         *time.Time
+}
+
+// BenchmarkBucket counts the peer results whose value falls in the histogram bucket starting at Value
+type BenchmarkBucket struct {
+	Value float64 `bson:"_id"`
+	Count int64   `bson:"count"`
+}
+
+// BenchmarkDistribution is the aggregate a benchmark is computed from: histograms of peers' score
+// percentages (1 point buckets) and time used (10 second buckets), never individual results
+type BenchmarkDistribution struct {
+	Scores []BenchmarkBucket `bson:"scores"`
+	Times  []BenchmarkBucket `bson:"times"`
+}
+
+// BenchmarkPercentiles are points of a peer distribution
+type BenchmarkPercentiles struct {
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+}
+
+// ResultBenchmark shows a student where their result stands among anonymized peers
+type ResultBenchmark struct {
+	ResultID      primitive.ObjectID  `json:"result_id"`
+	QuizType      QuizType            `json:"quiz_type"`
+	PeerGroup     BenchmarkPeerGroup  `json:"peer_group"`
+	ExamSittingID *primitive.ObjectID `json:"exam_sitting_id,omitempty"`
+	PeerCount     int64               `json:"peer_count"`
+
+	ScorePercentage  float64              `json:"score_percentage"`
+	ScorePercentile  float64              `json:"score_percentile"` // Share of peers who scored lower, 0-100
+	ScorePercentiles BenchmarkPercentiles `json:"score_percentiles"`
+
+	TimeUsedSeconds int64                `json:"time_used_seconds"`
+	TimePercentile  float64              `json:"time_percentile"`  // Share of peers who took longer, 0-100
+	TimePercentiles BenchmarkPercentiles `json:"time_percentiles"` // Seconds
+
+	GeneratedAt time.Time `json:"generated_at"` // When the peer aggregates were computed
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ThreadRole identifies which side of a result discussion a participant is on
+type ThreadRole string
+
+const (
+	ThreadRoleStudent    ThreadRole = "student"
+	ThreadRoleInstructor ThreadRole = "instructor"
+)
+
+// ResultThread is the private discussion between a student and instructors about one detailed quiz result
+type ResultThread struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ResultID    primitive.ObjectID `json:"result_id" bson:"result_id"`
+	StudentID   primitive.ObjectID `json:"student_id" bson:"student_id"`
+	ResultTitle string             `json:"result_title" bson:"result_title"`
+
+	// Instructors who have posted, notified about new student messages
+	InstructorIDs []primitive.ObjectID `json:"instructor_ids" bson:"instructor_ids"`
+
+	MessageCount  int                `json:"message_count" bson:"message_count"`
+	LastMessageAt time.Time          `json:"last_message_at" bson:"last_message_at"`
+	LastMessageBy ThreadRole         `json:"last_message_by" bson:"last_message_by"`
+	LastAuthorID  primitive.ObjectID `json:"last_author_id" bson:"last_author_id"`
+
+	// Read state per side
+	StudentUnread        int        `json:"student_unread" bson:"student_unread"`
+	InstructorUnread     int        `json:"instructor_unread" bson:"instructor_unread"`
+	StudentLastReadAt    *time.Time `json:"student_last_read_at,omitempty" bson:"student_last_read_at,omitempty"`
+	InstructorLastReadAt *time.Time `json:"instructor_last_read_at,omitempty" bson:"instructor_last_read_at,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ThreadMessage is a single message in a result thread, optionally about one question of the result
+type ThreadMessage struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ThreadID   primitive.ObjectID `json:"thread_id" bson:"thread_id"`
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	AuthorRole ThreadRole         `json:"author_role" bson:"author_role"`
+
+	// References a QuestionResult of the discussed result
+	QuestionID    *primitive.ObjectID `json:"question_id,omitempty" bson:"question_id,omitempty"`
+	QuestionTitle string              `json:"question_title,omitempty" bson:"question_title,omitempty"`
+
+	Body      string    `json:"body" bson:"body"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Request/Response models for API
+
+// PostThreadMessageRequest represents a new message in a result thread
+type PostThreadMessageRequest struct {
+	Body       string `json:"body" binding:"required,min=1,max=5000"`
+	QuestionID string `json:"question_id,omitempty"` // QuestionResult being discussed
+}
+
+// ListResultThreadsRequest represents query parameters for listing threads
+type ListResultThreadsRequest struct {
+	Page       int  `form:"page,default=1" binding:"min=1"`
+	Limit      int  `form:"limit,default=20" binding:"min=1,max=100"`
+	UnreadOnly bool `form:"unread_only"`
+}
+
+// ResultThreadResponse represents a thread with its messages
+type ResultThreadResponse struct {
+	Thread   *ResultThread   `json:"thread"`
+	Messages []ThreadMessage `json:"messages"`
+}
+
+// ListResultThreadsResponse represents a page of threads
+type ListResultThreadsResponse struct {
+	Threads    []ResultThread `json:"threads"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"total_pages"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResultWebhookEvent names what a webhook delivery reports
+type ResultWebhookEvent string
+
+const (
+	ResultWebhookFinalized ResultWebhookEvent = "exam_results.finalized" // Exam sitting results became visible to students
+	ResultWebhookPing      ResultWebhookEvent = "webhook.ping"           // Sent on request to check the endpoint and signature
+)
+
+// ResultWebhook delivers a department's finalized exam results to its own dashboard. The department
+// is the faculty or major named by GroupBy and Group; only mahasiswa results in it are sent.
+// Each delivery is signed with Secret, which is shown only when the webhook is created or rotated.
+type ResultWebhook struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name    string             `json:"name" bson:"name"`
+	URL     string             `json:"url" bson:"url"`
+	GroupBy CohortGroupBy      `json:"group_by" bson:"group_by"` // faculty or major
+	Group   string             `json:"group" bson:"group"`
+	Secret  string             `json:"-" bson:"secret"`
+	Active  bool               `json:"active" bson:"active"`
+
+	// Delivery tracking
+	LastDeliveryAt      *time.Time `json:"last_delivery_at,omitempty" bson:"last_delivery_at,omitempty"`
+	LastStatusCode      int        `json:"last_status_code,omitempty" bson:"last_status_code,omitempty"`
+	LastError           string     `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	DeliveredCount      int64      `json:"delivered_count" bson:"delivered_count"`
+	FailedCount         int64      `json:"failed_count" bson:"failed_count"`                 // Deliveries that failed every attempt
+	ConsecutiveFailures int        `json:"consecutive_failures" bson:"consecutive_failures"` // Reset by the next successful delivery
+
+	// Metadata
+	CreatedBy      primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedByEmail string             `json:"created_by_email" bson:"created_by_email"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// ResultWebhookPayload is the signed JSON body POSTed to a webhook URL. Results may be delivered
+// more than once, e.g. when an admin withholds and re-releases a sitting, so receivers should
+// upsert them by result_id.
+type ResultWebhookPayload struct {
+	Event      ResultWebhookEvent     `json:"event"`
+	DeliveryID string                 `json:"delivery_id"`
+	WebhookID  string                 `json:"webhook_id"`
+	GroupBy    CohortGroupBy          `json:"group_by"`
+	Group      string                 `json:"group"`
+	Sitting    *ResultWebhookSitting  `json:"sitting,omitempty"`
+	Results    []ResultWebhookSummary `json:"results"`
+	SentAt     time.Time              `json:"sent_at"`
+}
+
+// ResultWebhookSitting identifies the exam sitting the results belong to
+type ResultWebhookSitting struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	QuizType QuizType `json:"quiz_type"`
+	PassMark float64  `json:"pass_mark,omitempty"`
+}
+
+// ResultWebhookSummary is one student's finalized result, without their answers
+type ResultWebhookSummary struct {
+	ResultID         string     `json:"result_id"`
+	UserID           string     `json:"user_id"`
+	NIM              string     `json:"nim,omitempty"`
+	FullName         string     `json:"full_name"`
+	Faculty          string     `json:"faculty,omitempty"`
+	Major            string     `json:"major,omitempty"`
+	FinalScore       int        `json:"final_score"`
+	TotalPoints      int        `json:"total_points"`
+	ScorePercentage  float64    `json:"score_percentage"`
+	Passed           *bool      `json:"passed,omitempty"` // Set when the sitting has a pass mark
+	Scaled           bool       `json:"scaled"`           // Scores were curved by the sitting's scaling
+	CorrectAnswers   int        `json:"correct_answers"`
+	TotalQuestions   int        `json:"total_questions"`
+	CompletionStatus QuizStatus `json:"completion_status"`
+	SubmittedAt      time.Time  `json:"submitted_at"`
+}
+
+// Request/Response models for API
+
+// CreateResultWebhookRequest registers a department's webhook
+type CreateResultWebhookRequest struct {
+	Name    string        `json:"name" binding:"required,max=100"`
+	URL     string        `json:"url" binding:"required,url,max=500"`
+	GroupBy CohortGroupBy `json:"group_by" binding:"required,oneof=faculty major"`
+	Group   string        `json:"group" binding:"required,max=200"`
+}
+
+// UpdateResultWebhookRequest changes a webhook's settings
+type UpdateResultWebhookRequest struct {
+	Name    *string        `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	URL     *string        `json:"url,omitempty" binding:"omitempty,url,max=500"`
+	GroupBy *CohortGroupBy `json:"group_by,omitempty" binding:"omitempty,oneof=faculty major"`
+	Group   *string        `json:"group,omitempty" binding:"omitempty,min=1,max=200"`
+	Active  *bool          `json:"active,omitempty"`
+}
+
+// ResultWebhookSecretResponse returns a webhook with its signing secret, which is shown only once
+type ResultWebhookSecretResponse struct {
+	Webhook *ResultWebhook `json:"webhook"`
+	Secret  string         `json:"secret"`
+	Message string         `json:"message"`
+}
+
+// ListResultWebhooksResponse lists every registered webhook
+type ListResultWebhooksResponse struct {
+	Webhooks []ResultWebhook `json:"webhooks"`
+	Total    int             `json:"total"`
+}
+
+// PingResultWebhookResponse reports the outcome of a ping delivery
+type PingResultWebhookResponse struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JobRunStatus is how a scheduled job run ended
+type JobRunStatus string
+
+const (
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun is one run of a scheduled job, kept as run history until ExpiresAt
+type JobRun struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Job        string             `json:"job" bson:"job"`
+	Instance   string             `json:"instance" bson:"instance"` // Scheduler instance that held the leader lease
+	Status     JobRunStatus       `json:"status" bson:"status"`
+	Summary    string             `json:"summary,omitempty" bson:"summary,omitempty"` // What the job did, such as how many records it touched
+	Error      string             `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt  time.Time          `json:"started_at" bson:"started_at"`
+	FinishedAt time.Time          `json:"finished_at" bson:"finished_at"`
+	Duration   string             `json:"duration" bson:"duration"`
+	ExpiresAt  time.Time          `json:"-" bson:"expires_at"`
+}
+
+// ScheduledJobStatus describes a job the scheduler owns
+type ScheduledJobStatus struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Interval    string     `json:"interval"`
+	Enabled     bool       `json:"enabled"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"` // When this instance runs the job next, if it still leads then
+	LastRun     *JobRun    `json:"last_run,omitempty"`
+}
+
+type ListScheduledJobsResponse struct {
+	Instance string               `json:"instance"`
+	Leader   bool                 `json:"leader"` // Whether the answering instance runs the jobs
+	Jobs     []ScheduledJobStatus `json:"jobs"`
+}
+
+type ListJobRunsRequest struct {
+	Job   string `form:"job"`
+	Limit int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+type ListJobRunsResponse struct {
+	Runs []JobRun `json:"runs"`
+}
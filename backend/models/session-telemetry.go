@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TelemetryEventType is what a client telemetry event measures
+type TelemetryEventType string
+
+const (
+	TelemetryRender        TelemetryEventType = "render"         // Time to render a question, DurationMs
+	TelemetryAnswerLatency TelemetryEventType = "answer_latency" // Round trip of saving an answer, DurationMs
+	TelemetryNetworkRetry  TelemetryEventType = "network_retry"  // A request retried; Attempts so far and the endpoint
+	TelemetryTimerStall    TelemetryEventType = "timer_stall"    // Gap between countdown ticks longer than a second, DurationMs
+)
+
+// TelemetryEvent is one measurement reported by the quiz client
+type TelemetryEvent struct {
+	Type          TelemetryEventType `json:"type" bson:"type" binding:"required,oneof=render answer_latency network_retry timer_stall"`
+	QuestionIndex *int               `json:"question_index,omitempty" bson:"question_index,omitempty" binding:"omitempty,min=0"`
+	DurationMs    int64              `json:"duration_ms,omitempty" bson:"duration_ms,omitempty" binding:"min=0,max=3600000"`
+	Attempts      int                `json:"attempts,omitempty" bson:"attempts,omitempty" binding:"min=0,max=100"`
+	Endpoint      string             `json:"endpoint,omitempty" bson:"endpoint,omitempty" binding:"max=200"`
+	Detail        string             `json:"detail,omitempty" bson:"detail,omitempty" binding:"max=200"` // e.g. an error code or "tab_hidden"
+	OccurredAt    time.Time          `json:"occurred_at" bson:"occurred_at" binding:"required"`          // Client clock
+}
+
+// SessionTelemetryBatch is a batch of client telemetry for a quiz session. Batches are kept in a
+// capped collection, so the oldest are dropped once it fills.
+type SessionTelemetryBatch struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	SessionID primitive.ObjectID `json:"session_id" bson:"session_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	QuizType  QuizType           `json:"quiz_type" bson:"quiz_type"`
+	IsGuest   bool               `json:"is_guest,omitempty" bson:"is_guest,omitempty"`
+	UserAgent string             `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+
+	// Server time minus the client's clock when it sent the batch; large offsets explain odd timestamps
+	ClockOffsetMs *int64 `json:"clock_offset_ms,omitempty" bson:"clock_offset_ms,omitempty"`
+
+	Events     []TelemetryEvent `json:"events" bson:"events"`
+	ReceivedAt time.Time        `json:"received_at" bson:"received_at"`
+}
+
+// Request/Response models for API
+
+// IngestTelemetryRequest is a batch of events buffered by the quiz client
+type IngestTelemetryRequest struct {
+	Events []TelemetryEvent `json:"events" binding:"required,min=1,max=200,dive"`
+	SentAt *time.Time       `json:"sent_at,omitempty"` // Client clock when the batch was sent
+
+	// Filled in by the controller from the request
+	UserAgent string `json:"-"`
+	IsGuest   bool   `json:"-"`
+}
+
+// IngestTelemetryResponse confirms how many events were stored
+type IngestTelemetryResponse struct {
+	Accepted int `json:"accepted"`
+}
+
+// TelemetrySummaryRequest filters the telemetry an admin aggregates
+type TelemetrySummaryRequest struct {
+	SessionID    string             `form:"session_id"`    // One session, e.g. from a student's complaint; also returns its events
+	SessionToken string             `form:"session_token"` // Alternative to session_id
+	QuizType     QuizType           `form:"quiz_type"`
+	Type         TelemetryEventType `form:"type" binding:"omitempty,oneof=render answer_latency network_retry timer_stall"`
+	DateFrom     string             `form:"date_from"` // YYYY-MM-DD, by when the batch was received
+	DateTo       string             `form:"date_to"`
+}
+
+// TelemetryFilter is a parsed TelemetrySummaryRequest
+type TelemetryFilter struct {
+	SessionID *primitive.ObjectID
+	QuizType  QuizType
+	Type      TelemetryEventType
+	From      *time.Time
+	To        *time.Time // Exclusive
+}
+
+// TelemetryTypeSummary aggregates the events of one type
+type TelemetryTypeSummary struct {
+	Type          TelemetryEventType `json:"type" bson:"_id"`
+	Events        int                `json:"events" bson:"events"`
+	Sessions      int                `json:"sessions" bson:"sessions"`
+	AvgDurationMs float64            `json:"avg_duration_ms" bson:"avg_duration_ms"`
+	MaxDurationMs int64              `json:"max_duration_ms" bson:"max_duration_ms"`
+	MaxAttempts   int                `json:"max_attempts" bson:"max_attempts"`
+}
+
+// TelemetrySessionEvent is an event in a session's telemetry timeline
+type TelemetrySessionEvent struct {
+	TelemetryEvent `bson:",inline"`
+	ReceivedAt     time.Time `json:"received_at" bson:"received_at"`
+	ClockOffsetMs  *int64    `json:"clock_offset_ms,omitempty" bson:"clock_offset_ms,omitempty"`
+}
+
+// TelemetrySummaryResponse is the aggregated telemetry, with the session's timeline when one was chosen
+type TelemetrySummaryResponse struct {
+	SessionID *primitive.ObjectID     `json:"session_id,omitempty"`
+	Summary   []TelemetryTypeSummary  `json:"summary"`
+	Events    []TelemetrySessionEvent `json:"events,omitempty"`
+}
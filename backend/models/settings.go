@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RuntimeSettings are the values admins can tune while the server runs. Until an admin saves them,
+// each comes from its environment variable or built-in default.
+type RuntimeSettings struct {
+	ActivityLogRetentionDays     int     `json:"activity_log_retention_days" bson:"activity_log_retention_days"`           // Activity logs older than this are deleted
+	CertificatePassingScore      float64 `json:"certificate_passing_score" bson:"certificate_passing_score"`               // Score percentage a mock test needs to earn a certificate
+	CustomQuizMinutesPerQuestion int     `json:"custom_quiz_minutes_per_question" bson:"custom_quiz_minutes_per_question"` // Time allowed per question in custom quizzes and widgets without a time limit
+
+	UpdatedAt *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"` // Unset while the defaults are in force
+	UpdatedBy string     `json:"updated_by,omitempty" bson:"updated_by,omitempty"` // Email of the admin who last saved them
+}
+
+// UpdateRuntimeSettingsRequest changes the settings given and leaves the rest as they are
+type UpdateRuntimeSettingsRequest struct {
+	ActivityLogRetentionDays     *int     `json:"activity_log_retention_days,omitempty" binding:"omitempty,min=1,max=3650"`
+	CertificatePassingScore      *float64 `json:"certificate_passing_score,omitempty" binding:"omitempty,min=0,max=100"`
+	CustomQuizMinutesPerQuestion *int     `json:"custom_quiz_minutes_per_question,omitempty" binding:"omitempty,min=1,max=30"`
+}
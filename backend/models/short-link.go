@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShortLinkStatus represents whether a short link still redirects
+type ShortLinkStatus string
+
+const (
+	ShortLinkActive  ShortLinkStatus = "active"
+	ShortLinkRevoked ShortLinkStatus = "revoked"
+	ShortLinkExpired ShortLinkStatus = "expired" // Active past its expiry; derived, never stored
+)
+
+// ShortLink is a short code that redirects to a page of the frontend, for module or exam links
+// printed on handouts or posted in chat groups
+type ShortLink struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Code   string             `json:"code" bson:"code"`
+	Path   string             `json:"path" bson:"path"` // Frontend path and query, e.g. /dokumentasi?section=...
+	Label  string             `json:"label,omitempty" bson:"label,omitempty"`
+	Status ShortLinkStatus    `json:"status" bson:"status"`
+
+	ExpiresAt *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"` // Never expires when unset
+
+	// Click tracking
+	ClickCount    int64      `json:"click_count" bson:"click_count"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty" bson:"last_clicked_at,omitempty"`
+
+	// Who created it
+	CreatedBy      primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedByEmail string             `json:"created_by_email" bson:"created_by_email"`
+
+	RevokedAt *time.Time          `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	RevokedBy *primitive.ObjectID `json:"revoked_by,omitempty" bson:"revoked_by,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// Full short URL, filled in for responses
+	ShortURL string `json:"short_url,omitempty" bson:"-"`
+}
+
+// Request/Response models for API
+
+// CreateShortLinkRequest represents the request to create a short link. Codes are generated
+// unless a custom one is given.
+type CreateShortLinkRequest struct {
+	Path           string `json:"path" binding:"required,startswith=/,max=2048"`
+	Code           string `json:"code,omitempty" binding:"omitempty,alphanum,min=3,max=32"`
+	Label          string `json:"label,omitempty" binding:"max=200"`
+	ExpiresInHours int    `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=8760"`
+}
+
+// ListShortLinksRequest represents the request to list short links
+type ListShortLinksRequest struct {
+	Page   int             `form:"page,default=1" binding:"min=1"`
+	Limit  int             `form:"limit,default=20" binding:"min=1,max=100"`
+	Status ShortLinkStatus `form:"status" binding:"omitempty,oneof=active revoked expired"`
+	Search string          `form:"search"` // Matches code, label or path
+}
+
+// ListShortLinksResponse represents a page of short links
+type ListShortLinksResponse struct {
+	Links      []ShortLink `json:"links"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page"`
+	Limit      int         `json:"limit"`
+	TotalPages int         `json:"total_pages"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SigningKeyStatus represents where a JWT signing key is in its rotation
+type SigningKeyStatus string
+
+const (
+	SigningKeyCurrent  SigningKeyStatus = "current"  // Signs new tokens
+	SigningKeyPrevious SigningKeyStatus = "previous" // Rotated out; still verifies tokens it signed until VerifyUntil
+	SigningKeyRetired  SigningKeyStatus = "retired"  // No longer trusted
+)
+
+// JWTSigningKey is an ES256 key pair used to sign tokens, identified by the kid header.
+// The private key is stored encrypted with a key derived from the JWT secret.
+type JWTSigningKey struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	KID          string             `json:"kid" bson:"kid"`
+	Algorithm    string             `json:"algorithm" bson:"algorithm"`
+	Status       SigningKeyStatus   `json:"status" bson:"status"`
+	EncryptedKey string             `json:"-" bson:"encrypted_key"`
+	Nonce        string             `json:"-" bson:"nonce"`
+
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	RotatedAt   *time.Time `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`     // When it stopped signing
+	VerifyUntil *time.Time `json:"verify_until,omitempty" bson:"verify_until,omitempty"` // Outlives every token it signed
+	RetiredAt   *time.Time `json:"retired_at,omitempty" bson:"retired_at,omitempty"`
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeyRotationResult reports what a signing key rotation changed
+type KeyRotationResult struct {
+	CurrentKID  string    `json:"current_kid"`
+	PreviousKID string    `json:"previous_kid,omitempty"`
+	VerifyUntil time.Time `json:"verify_until,omitempty"`
+	RetiredKIDs []string  `json:"retired_kids"`
+}
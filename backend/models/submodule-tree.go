@@ -0,0 +1,157 @@
+package models
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Submodules nest under one another through ParentID, and are still stored as one flat list on their
+// module, so a submodule's ID alone identifies it wherever it is in the tree.
+
+// MaxSubModuleDepth bounds how deeply submodules nest; submodules directly in a module are at depth 1
+const MaxSubModuleDepth = 5
+
+// Breadcrumb is one step of the path from a module down to a submodule
+type Breadcrumb struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// MoveSubModuleRequest nests a submodule under another, or with a null parent_id directly in its
+// module. Without an order it goes after its new siblings.
+type MoveSubModuleRequest struct {
+	ParentID *primitive.ObjectID `json:"parent_id"`
+	Order    int                 `json:"order,omitempty" binding:"min=0"`
+}
+
+// subModuleParents gives each submodule the parent it is shown under. A parent that is missing, or
+// that would nest the submodule inside itself, leaves the submodule directly in the module.
+func (m *Module) subModuleParents() map[primitive.ObjectID]*primitive.ObjectID {
+	exists := make(map[primitive.ObjectID]bool, len(m.SubModules))
+	for _, sub := range m.SubModules {
+		exists[sub.ID] = true
+	}
+	parents := make(map[primitive.ObjectID]*primitive.ObjectID, len(m.SubModules))
+	for _, sub := range m.SubModules {
+		if sub.ParentID != nil && exists[*sub.ParentID] {
+			parents[sub.ID] = sub.ParentID
+		} else {
+			parents[sub.ID] = nil
+		}
+	}
+
+	// Break cycles where they close, so every chain of parents ends at the module
+	for _, sub := range m.SubModules {
+		seen := map[primitive.ObjectID]bool{sub.ID: true}
+		for id := sub.ID; parents[id] != nil; id = *parents[id] {
+			if seen[*parents[id]] {
+				parents[id] = nil
+				break
+			}
+			seen[*parents[id]] = true
+		}
+	}
+	return parents
+}
+
+// ArrangeSubModules puts the submodules in reading order, each followed by the submodules under it,
+// siblings by Order then creation, and fills in their Depth and Breadcrumbs
+func (m *Module) ArrangeSubModules() {
+	parents := m.subModuleParents()
+	children := make(map[primitive.ObjectID][]SubModule)
+	var roots []SubModule
+	for _, sub := range m.SubModules {
+		if parent := parents[sub.ID]; parent != nil {
+			children[*parent] = append(children[*parent], sub)
+		} else {
+			roots = append(roots, sub)
+		}
+	}
+
+	arranged := make([]SubModule, 0, len(m.SubModules))
+	var visit func(siblings []SubModule, path []Breadcrumb)
+	visit = func(siblings []SubModule, path []Breadcrumb) {
+		sortSiblings(siblings)
+		for _, sub := range siblings {
+			sub.Depth = len(path)
+			sub.Breadcrumbs = path
+			arranged = append(arranged, sub)
+			next := append(append([]Breadcrumb{}, path...), Breadcrumb{ID: sub.ID, Name: sub.Name})
+			visit(children[sub.ID], next)
+		}
+	}
+	visit(roots, []Breadcrumb{{ID: m.ID, Name: m.Name}})
+	m.SubModules = arranged
+}
+
+// RepairSubModuleTree places submodules with a missing or circular parent directly in the module, and
+// numbers each group of siblings from 1 in their current order. It reports whether anything changed.
+func (m *Module) RepairSubModuleTree() bool {
+	changed := false
+	parents := m.subModuleParents()
+	for i := range m.SubModules {
+		sub := &m.SubModules[i]
+		if sub.ParentID != nil && parents[sub.ID] == nil {
+			sub.ParentID = nil
+			changed = true
+		}
+	}
+
+	m.ArrangeSubModules()
+	next := make(map[primitive.ObjectID]int)
+	for i := range m.SubModules {
+		sub := &m.SubModules[i]
+		var parent primitive.ObjectID
+		if sub.ParentID != nil {
+			parent = *sub.ParentID
+		}
+		next[parent]++
+		if sub.Order != next[parent] {
+			sub.Order = next[parent]
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SubModulePublished reports whether learners can see a submodule: it, and every submodule above it,
+// is published
+func (m *Module) SubModulePublished(id primitive.ObjectID) bool {
+	byID := make(map[primitive.ObjectID]*SubModule, len(m.SubModules))
+	for i := range m.SubModules {
+		byID[m.SubModules[i].ID] = &m.SubModules[i]
+	}
+	parents := m.subModuleParents()
+	for current := &id; current != nil; current = parents[*current] {
+		sub, ok := byID[*current]
+		if !ok || !sub.IsPublished {
+			return false
+		}
+	}
+	return true
+}
+
+// SubModuleDescendants lists the submodules nested under one, at any depth
+func (m *Module) SubModuleDescendants(id primitive.ObjectID) []primitive.ObjectID {
+	parents := m.subModuleParents()
+	var descendants []primitive.ObjectID
+	for _, sub := range m.SubModules {
+		for parent := parents[sub.ID]; parent != nil; parent = parents[*parent] {
+			if *parent == id {
+				descendants = append(descendants, sub.ID)
+				break
+			}
+		}
+	}
+	return descendants
+}
+
+func sortSiblings(siblings []SubModule) {
+	sort.SliceStable(siblings, func(i, j int) bool {
+		if siblings[i].Order != siblings[j].Order {
+			return siblings[i].Order < siblings[j].Order
+		}
+		return siblings[i].CreatedAt.Before(siblings[j].CreatedAt)
+	})
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TeamMaxMembers keeps teams small enough to work through one session together
+const TeamMaxMembers = 5
+
+// TeamRole is a member's role in a team
+type TeamRole string
+
+const (
+	TeamCaptain TeamRole = "captain" // Starts new team quizzes and is the only one who can submit them
+	TeamMember  TeamRole = "member"
+)
+
+// Team is a group of students who take team quizzes together in one shared session
+type Team struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name     string             `json:"name" bson:"name"`
+	JoinCode string             `json:"join_code" bson:"join_code"` // Shared with classmates to join
+	Members  []TeamMemberInfo   `json:"members" bson:"members"`
+
+	// The team's shared session while one is running. Claimed atomically so members starting at the
+	// same moment end up in the same session; it stays set after the session ends until the next start.
+	ActiveSessionID *primitive.ObjectID `json:"-" bson:"active_session_id,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// TeamMemberInfo is one student in a team
+type TeamMemberInfo struct {
+	UserID   primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Email    string             `json:"email" bson:"email"`
+	Role     TeamRole           `json:"role" bson:"role"`
+	JoinedAt time.Time          `json:"joined_at" bson:"joined_at"`
+}
+
+// Member returns the team member with the given user ID, or nil if they are not in the team
+func (t *Team) Member(userID primitive.ObjectID) *TeamMemberInfo {
+	for i := range t.Members {
+		if t.Members[i].UserID == userID {
+			return &t.Members[i]
+		}
+	}
+	return nil
+}
+
+// Captain returns the team's captain
+func (t *Team) Captain() *TeamMemberInfo {
+	for i := range t.Members {
+		if t.Members[i].Role == TeamCaptain {
+			return &t.Members[i]
+		}
+	}
+	return nil
+}
+
+// MemberIDs returns the user IDs of every member
+func (t *Team) MemberIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(t.Members))
+	for _, member := range t.Members {
+		ids = append(ids, member.UserID)
+	}
+	return ids
+}
+
+// Request/Response models for API
+
+// CreateTeamRequest represents the request to create a team; the creator becomes its captain
+type CreateTeamRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=60"`
+}
+
+// JoinTeamRequest represents the request to join a team with its code
+type JoinTeamRequest struct {
+	JoinCode string `json:"join_code" binding:"required"`
+}
+
+// StartTeamQuizRequest starts, or joins, the team's shared session
+type StartTeamQuizRequest struct {
+	QuizType   QuizType `json:"quiz_type,omitempty" binding:"required_without=TemplateID,omitempty,oneof=mock_test time_quiz"`
+	TemplateID string   `json:"template_id,omitempty"` // Quiz template to use; defaults to the quiz type's default template
+
+	// Filled in by the controller from the request
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// TeamLeaderboardRequest represents query parameters for the team leaderboard
+type TeamLeaderboardRequest struct {
+	DateFrom string `form:"date_from"` // YYYY-MM-DD
+	DateTo   string `form:"date_to"`   // YYYY-MM-DD, inclusive
+	Limit    int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// TeamLeaderboardEntry is a team's standing, from its team quiz results
+type TeamLeaderboardEntry struct {
+	Rank          int                `json:"rank" bson:"-"`
+	TeamID        primitive.ObjectID `json:"team_id" bson:"_id"`
+	TeamName      string             `json:"team_name" bson:"team_name"`
+	BestScore     float64            `json:"best_score" bson:"best_score"`       // Best score percentage
+	AverageScore  float64            `json:"average_score" bson:"average_score"` // Score percentage
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	LastSubmitted time.Time          `json:"last_submitted" bson:"last_submitted"`
+}
+
+// TeamLeaderboardResponse ranks teams by their best score, then average score
+type TeamLeaderboardResponse struct {
+	Entries []TeamLeaderboardEntry `json:"entries"`
+}
@@ -10,8 +10,15 @@ import (
 type QuizType string
 
 const (
-	MockTest QuizType = "mock_test"
-	TimeQuiz QuizType = "time_quiz"
+	MockTest  QuizType = "mock_test"
+	TimeQuiz  QuizType = "time_quiz"
+	Practice  QuizType = "practice"   // Untimed-style review session built from the user's bookmarks
+	QuickQuiz QuizType = "quick_quiz" // Joined with a code during a class quiz an instructor runs
+	TeamQuiz  QuizType = "team_quiz"  // Taken by a team in one shared session
+
+	AdaptivePractice QuizType = "adaptive_practice" // Each question's difficulty follows how well the previous ones went
+	CustomQuiz       QuizType = "custom_quiz"       // Built by the student from tags, difficulty and a question count; not an official mock test
+	EmbedQuiz        QuizType = "embed_quiz"        // Taken anonymously in a widget embedded on a departmental site
 )
 
 // QuizResult represents a completed quiz attempt by a user
@@ -47,10 +54,35 @@ type QuizResult struct {
 	Status     string `json:"status" bson:"status"`             // completed, abandoned, in_progress
 	IsTimedOut bool   `json:"is_timed_out" bson:"is_timed_out"` // for time quiz
 
+	// Set when the owning account was deleted; UserID is then a random placeholder
+	Anonymized bool `json:"anonymized,omitempty" bson:"anonymized,omitempty"`
+
+	// Set for attempts taken in an exam sitting, whose release settings decide when scores are shown
+	ExamSittingID *primitive.ObjectID `json:"exam_sitting_id,omitempty" bson:"exam_sitting_id,omitempty"`
+
+	// Set for attempts at an admin-defined template, whose attempt policy decides which attempts count toward stats
+	TemplateID     *primitive.ObjectID `json:"template_id,omitempty" bson:"template_id,omitempty"`
+	AttemptScoring AttemptScoring      `json:"attempt_scoring,omitempty" bson:"attempt_scoring,omitempty"`
+
+	// Set on responses while the sitting's results are embargoed; scores are blanked
+	Withheld         bool       `json:"withheld,omitempty" bson:"-"`
+	ResultsReleaseAt *time.Time `json:"results_release_at,omitempty" bson:"-"`
+
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
+// Withhold blanks the scores of a result whose sitting hasn't released results yet
+func (r *QuizResult) Withhold(releaseAt *time.Time) {
+	r.Score = 0
+	r.CorrectAnswers = 0
+	r.SingleChoiceCorrect = 0
+	r.MultipleChoiceCorrect = 0
+	r.EssayCorrect = 0
+	r.Withheld = true
+	r.ResultsReleaseAt = releaseAt
+}
+
 // UserStats represents aggregated statistics for a user
 type UserStats struct {
 	ID     primitive.ObjectID `json:"id" bson:"_id,omitempty"`
@@ -89,6 +121,10 @@ type UserStats struct {
 	WeeklyProgress     int `json:"weekly_progress" bson:"weekly_progress"`
 	TargetAverageScore int `json:"target_average_score" bson:"target_average_score"`
 
+	// Experience earned outside quizzes, such as accepted question contributions
+	XP                    int `json:"xp" bson:"xp"`
+	AcceptedContributions int `json:"accepted_contributions" bson:"accepted_contributions"`
+
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
@@ -128,6 +164,10 @@ type UserResultsResponse struct {
 	Stats        UserStats     `json:"stats"`
 	Achievements []Achievement `json:"achievements"`
 	TotalCount   int64         `json:"total_count"`
+
+	// Pass as cursor to get the page after this one; empty on the last page. Pages fetched by cursor
+	// leave total_count unset.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type QuizResultsFilter struct {
@@ -136,4 +176,8 @@ type QuizResultsFilter struct {
 	DateTo   string `form:"date_to"`
 	Page     int    `form:"page,default=1"`
 	Limit    int    `form:"limit,default=10"`
+	Cursor   string `form:"cursor"` // Continue after a next_cursor instead of paging by number
+
+	// Set by staff-facing handlers; students only see scores their sitting has released
+	IncludeWithheld bool `form:"-"`
 }
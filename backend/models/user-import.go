@@ -0,0 +1,45 @@
+package models
+
+// ImportRowStatus represents the outcome of importing a single spreadsheet row
+type ImportRowStatus string
+
+const (
+	ImportRowCreated   ImportRowStatus = "created"
+	ImportRowDuplicate ImportRowStatus = "duplicate" // Already exists, or repeated in the file
+	ImportRowInvalid   ImportRowStatus = "invalid"   // Failed validation
+	ImportRowFailed    ImportRowStatus = "failed"    // Valid but could not be saved
+	ImportRowValid     ImportRowStatus = "valid"     // Passed every check in a dry run
+)
+
+// Request/Response models for API
+
+// ImportUsersRequest represents the multipart form options sent with an import file
+type ImportUsersRequest struct {
+	GeneratePasswords bool `form:"generate_passwords"` // Generate passwords for rows without one
+	SendInvitations   bool `form:"send_invitations"`   // Email login details to each created user
+}
+
+// ImportRowResult reports what happened to one row of the import file
+type ImportRowResult struct {
+	Row      int             `json:"row"` // Spreadsheet row number, header is row 1
+	FullName string          `json:"full_name"`
+	Email    string          `json:"email"`
+	NIM      string          `json:"nim"`
+	Status   ImportRowStatus `json:"status"`
+	Errors   []string        `json:"errors,omitempty"`
+
+	UserID            string `json:"user_id,omitempty"`
+	GeneratedPassword string `json:"generated_password,omitempty"` // Only returned when no invitation was delivered
+	InvitationSent    bool   `json:"invitation_sent"`
+}
+
+// ImportUsersResponse summarizes a bulk user import
+type ImportUsersResponse struct {
+	TotalRows       int               `json:"total_rows"`
+	Created         int               `json:"created"`
+	Duplicates      int               `json:"duplicates"`
+	Invalid         int               `json:"invalid"`
+	Failed          int               `json:"failed"`
+	InvitationsSent int               `json:"invitations_sent"`
+	Results         []ImportRowResult `json:"results"`
+}
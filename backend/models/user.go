@@ -50,6 +50,9 @@ type User struct {
 	// Recovery codes for password reset (single-use backup codes)
 	RecoveryCodes []string `json:"-" bson:"recovery_codes,omitempty"`
 
+	// Hashes of previous passwords, most recent first, checked by the password policy
+	PasswordHistory []string `json:"-" bson:"password_history,omitempty"`
+
 	// Email verification
 	VerificationToken string `json:"-" bson:"verification_token,omitempty"`
 
@@ -58,19 +61,43 @@ type User struct {
 	RememberMe   bool      `json:"-" bson:"remember_me"`
 	LastLogin    time.Time `json:"last_login" bson:"last_login"`
 
+	// Shown as anonymous in the credits of questions they wrote or reviewed
+	HideQuestionCredits bool `json:"hide_question_credits" bson:"hide_question_credits,omitempty"`
+
+	// Account deletion (soft delete with grace period)
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty" bson:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" bson:"deletion_scheduled_at,omitempty"`
+
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
 
+// Admin permissions
+const (
+	// Content authors: their questions start as drafts and reach quizzes only once another admin
+	// approves them. Admins without it publish their own questions and review others'.
+	PermissionQuestionAuthor = "question_author"
+)
+
 type Admin struct {
 	User        `bson:",inline"`
 	IsAdmin     bool     `json:"is_admin" bson:"is_admin"`
 	Permissions []string `json:"permissions" bson:"permissions"`
 }
 
+// HasPermission reports whether the admin was granted the permission
+func (a *Admin) HasPermission(permission string) bool {
+	for _, p := range a.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 type UserMahasiswa struct {
 	User    `bson:",inline"`
-	NIM     string `json:"mahasiswa_id" bson:"mahasiswa_id"`
+	NIM     string `json:"mahasiswa_id" bson:"mahasiswa_id,omitempty"` // Omitted when unknown so the sparse unique index skips it
 	Faculty string `json:"faculty" bson:"faculty,omitempty"`
 	Major   string `json:"major" bson:"major,omitempty"`
 }
@@ -88,6 +115,9 @@ type RegisterRequest struct {
 	Password string   `json:"password" binding:"required,min=8"`
 	UserType UserType `json:"user_type" binding:"required,oneof=mahasiswa user admin"`
 
+	// Signed invite token from an invitation link; invited accounts skip approval
+	InviteToken string `json:"invite_token,omitempty"`
+
 	// Fields for mahasiswa
 	NIM     string `json:"nim,omitempty"`
 	Faculty string `json:"faculty,omitempty"`
@@ -104,6 +134,17 @@ type AuthResponse struct {
 	AccessToken  string      `json:"access_token"`
 	RefreshToken string      `json:"refresh_token,omitempty"`
 	ExpiresIn    int64       `json:"expires_in"`
+
+	// Set when registration used an invitation, for activity logging
+	Invitation *Invitation `json:"-"`
+}
+
+// ImpersonationResponse is returned when an admin starts impersonating a user
+type ImpersonationResponse struct {
+	User           interface{} `json:"user"`
+	AccessToken    string      `json:"access_token"`
+	ExpiresIn      int64       `json:"expires_in"`
+	ImpersonatedBy string      `json:"impersonated_by"`
 }
 
 type OAuthRequest struct {
@@ -144,6 +185,19 @@ type RecoveryCodesResponse struct {
 	Message string   `json:"message"`
 }
 
+// PasswordPolicyResponse describes the active password rules so clients can validate before submitting
+type PasswordPolicyResponse struct {
+	PasswordPolicyConfig
+	Requirements []string `json:"requirements"` // Human-readable rules, in display order
+}
+
+// CaptchaSettingsResponse tells clients which captcha widget to render on public auth forms
+type CaptchaSettingsResponse struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider,omitempty"`
+	SiteKey  string `json:"site_key,omitempty"`
+}
+
 type PasswordResetOptionsResponse struct {
 	Message          string   `json:"message"`
 	Options          []string `json:"options"`
@@ -179,11 +233,13 @@ type AccessRequest struct {
 
 // User Management Request Models
 type ListUsersRequest struct {
-	Page     int        `form:"page" binding:"omitempty,min=1"`
-	Limit    int        `form:"limit" binding:"omitempty,min=1,max=100"`
-	Search   string     `form:"search"`
-	UserType UserType   `form:"user_type"`
-	Status   UserStatus `form:"status"`
+	Page      int        `form:"page" binding:"omitempty,min=1"`
+	Limit     int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search    string     `form:"search"`
+	UserType  UserType   `form:"user_type"`
+	Status    UserStatus `form:"status"`
+	SortBy    string     `form:"sort_by" binding:"omitempty,oneof=created_at full_name email last_login status user_type"`
+	SortOrder string     `form:"sort_order" binding:"omitempty,oneof=asc desc"`
 }
 
 type ListUsersResponse struct {
@@ -195,20 +251,20 @@ type ListUsersResponse struct {
 }
 
 type UserSummary struct {
-	ID            primitive.ObjectID `json:"id"`
-	FullName      string             `json:"full_name"`
-	Email         string             `json:"email"`
-	UserType      UserType           `json:"user_type"`
-	Status        UserStatus         `json:"status"`
-	EmailVerified bool               `json:"email_verified"`
-	LastLogin     time.Time          `json:"last_login"`
-	CreatedAt     time.Time          `json:"created_at"`
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	FullName      string             `json:"full_name" bson:"full_name"`
+	Email         string             `json:"email" bson:"email"`
+	UserType      UserType           `json:"user_type" bson:"user_type"`
+	Status        UserStatus         `json:"status" bson:"status"`
+	EmailVerified bool               `json:"email_verified" bson:"email_verified"`
+	LastLogin     time.Time          `json:"last_login" bson:"last_login"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
 
 	// Additional fields based on user type
-	NIM          string `json:"nim,omitempty"`          // For mahasiswa
-	Faculty      string `json:"faculty,omitempty"`      // For mahasiswa
-	Major        string `json:"major,omitempty"`        // For mahasiswa
-	Organization string `json:"organization,omitempty"` // For external
+	NIM          string `json:"nim,omitempty" bson:"nim,omitempty"`                   // For mahasiswa
+	Faculty      string `json:"faculty,omitempty" bson:"faculty,omitempty"`           // For mahasiswa
+	Major        string `json:"major,omitempty" bson:"major,omitempty"`               // For mahasiswa
+	Organization string `json:"organization,omitempty" bson:"organization,omitempty"` // For external
 }
 
 type UpdateUserStatusRequest struct {
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"backend/models"
@@ -15,10 +14,12 @@ import (
 
 type ActivityLogRepository interface {
 	CreateActivityLog(ctx context.Context, activityLog *models.ActivityLog) error
-	GetActivityLogs(ctx context.Context, req *models.GetActivityLogsRequest) ([]models.ActivityLog, int64, error)
+	GetActivityLogs(ctx context.Context, req *models.GetActivityLogsRequest, after *models.PageCursor) ([]models.ActivityLog, int64, error)
 	GetActivityStats(ctx context.Context) (*models.ActivityStats, error)
 	GetRecentActivities(ctx context.Context, limit int) ([]models.ActivityLog, error)
 	DeleteOldActivities(ctx context.Context, olderThan time.Time) (int64, error)
+	GetByPerformer(ctx context.Context, userID primitive.ObjectID) ([]models.ActivityLog, error)
+	AnonymizePerformer(ctx context.Context, userID primitive.ObjectID) (int64, error)
 }
 
 type activityLogRepository struct {
@@ -34,9 +35,6 @@ func NewActivityLogRepository(db *mongo.Database) ActivityLogRepository {
 }
 
 func (r *activityLogRepository) CreateActivityLog(ctx context.Context, activityLog *models.ActivityLog) error {
-	fmt.Printf("DEBUG REPO: Creating activity log - Type: %s, Entity: %s, User: %s\n",
-		activityLog.Type, activityLog.EntityName, activityLog.PerformedByName)
-
 	if activityLog.ID.IsZero() {
 		activityLog.ID = primitive.NewObjectID()
 	}
@@ -45,17 +43,15 @@ func (r *activityLogRepository) CreateActivityLog(ctx context.Context, activityL
 		activityLog.Timestamp = time.Now()
 	}
 
-	result, err := r.activityLogCollection.InsertOne(ctx, activityLog)
-	if err != nil {
-		fmt.Printf("ERROR REPO: Failed to insert activity log: %v\n", err)
+	if _, err := r.activityLogCollection.InsertOne(ctx, activityLog); err != nil {
 		return err
 	}
-
-	fmt.Printf("SUCCESS REPO: Activity log inserted with ID: %v\n", result.InsertedID)
 	return nil
 }
 
-func (r *activityLogRepository) GetActivityLogs(ctx context.Context, req *models.GetActivityLogsRequest) ([]models.ActivityLog, int64, error) {
+// GetActivityLogs pages through the activity log newest first. Given a cursor, it continues after it
+// without counting the total and returns one log past the limit, so callers can tell whether more follow.
+func (r *activityLogRepository) GetActivityLogs(ctx context.Context, req *models.GetActivityLogsRequest, after *models.PageCursor) ([]models.ActivityLog, int64, error) {
 	// Build filter
 	filter := bson.M{}
 
@@ -94,17 +90,24 @@ func (r *activityLogRepository) GetActivityLogs(ctx context.Context, req *models
 		filter["success"] = *req.Success
 	}
 
-	// Count total documents
-	total, err := r.activityLogCollection.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
 	// Build options for pagination and sorting
 	opts := options.Find()
-	opts.SetSkip(int64((req.Page - 1) * req.Limit))
-	opts.SetLimit(int64(req.Limit))
-	opts.SetSort(bson.D{{Key: "timestamp", Value: -1}}) // Sort by timestamp descending (newest first)
+	opts.SetSort(newestFirst("timestamp")) // Sort by timestamp descending (newest first)
+
+	var total int64
+	if after != nil {
+		filter = afterCursor(filter, "timestamp", after)
+		opts.SetLimit(int64(req.Limit) + 1)
+	} else {
+		// Count total documents
+		var err error
+		total, err = r.activityLogCollection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		opts.SetSkip(int64((req.Page - 1) * req.Limit))
+		opts.SetLimit(int64(req.Limit))
+	}
 
 	// Find activity logs
 	cursor, err := r.activityLogCollection.Find(ctx, filter, opts)
@@ -200,7 +203,7 @@ func (r *activityLogRepository) GetActivityStats(ctx context.Context) (*models.A
 	recentActivities, _, err := r.GetActivityLogs(ctx, &models.GetActivityLogsRequest{
 		Page:  1,
 		Limit: 10,
-	})
+	}, nil)
 	if err == nil {
 		stats.RecentActivities = recentActivities
 	}
@@ -278,3 +281,35 @@ func (r *activityLogRepository) DeleteOldActivities(ctx context.Context, olderTh
 
 	return result.DeletedCount, nil
 }
+
+func (r *activityLogRepository) GetByPerformer(ctx context.Context, userID primitive.ObjectID) ([]models.ActivityLog, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	cursor, err := r.activityLogCollection.Find(ctx, bson.M{"performed_by": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var activities []models.ActivityLog
+	if err = cursor.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// AnonymizePerformer strips personal details from the user's activity entries while keeping the audit trail
+func (r *activityLogRepository) AnonymizePerformer(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.activityLogCollection.UpdateMany(ctx,
+		bson.M{"performed_by": userID},
+		bson.M{
+			"$set":   bson.M{"performed_by_name": "Deleted User"},
+			"$unset": bson.M{"ip_address": "", "user_agent": ""},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
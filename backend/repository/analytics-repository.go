@@ -0,0 +1,433 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AnalyticsRepository interface {
+	GetCohortResults(ctx context.Context, filter *models.CohortFilter) ([]models.CohortResultRow, error)
+	GetCohortCategoryStats(ctx context.Context, filter *models.CohortFilter) ([]models.CohortCategoryRow, error)
+
+	// At-risk analysis
+	GetStudentActivity(ctx context.Context, since time.Time) ([]models.StudentActivityRow, error)
+	UpsertAtRiskStudent(ctx context.Context, student *models.AtRiskStudent) (*models.AtRiskStudent, error)
+	MarkAtRiskNotified(ctx context.Context, userID primitive.ObjectID, notifiedAt time.Time) error
+	DeleteAtRiskAnalyzedBefore(ctx context.Context, before time.Time) (int64, error)
+	ListAtRiskStudents(ctx context.Context, req *models.ListAtRiskStudentsRequest) ([]models.AtRiskStudent, int64, error)
+
+	// Abandonment
+	GetAbandonmentRows(ctx context.Context, quizType models.QuizType, from, to *time.Time) ([]models.AbandonmentRow, error)
+
+	// Item analysis
+	GetItemResponses(ctx context.Context, questionID primitive.ObjectID, from, to *time.Time) ([]models.ItemResponseRow, error)
+
+	// Difficulty recalibration
+	GetQuestionCorrectRates(ctx context.Context, since time.Time, minAttempts int) ([]models.QuestionCorrectRateRow, error)
+}
+
+type analyticsRepository struct {
+	db                  *mongo.Database
+	resultCollection    *mongo.Collection
+	mahasiswaCollection *mongo.Collection
+	atRiskCollection    *mongo.Collection
+	sessionCollection   *mongo.Collection
+}
+
+func NewAnalyticsRepository(db *mongo.Database) AnalyticsRepository {
+	return &analyticsRepository{
+		db:                  db,
+		resultCollection:    db.Collection("detailed_quiz_results"),
+		mahasiswaCollection: db.Collection("mahasiswa"),
+		atRiskCollection:    db.Collection("at_risk_students"),
+		sessionCollection:   db.Collection("quiz_sessions"),
+	}
+}
+
+// GetCohortResults groups finished attempts by cohort with their scores and completion times
+func (r *analyticsRepository) GetCohortResults(ctx context.Context, filter *models.CohortFilter) ([]models.CohortResultRow, error) {
+	pipeline := append(cohortPipeline(filter),
+		bson.M{"$group": bson.M{
+			"_id":        "$cohort",
+			"students":   bson.M{"$addToSet": "$user_id"},
+			"attempts":   bson.M{"$sum": 1},
+			"timed_out":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$completion_status", models.QuizTimeout}}, 1, 0}}},
+			"scores":     bson.M{"$push": "$score_percentage"},
+			"times_used": bson.M{"$push": "$time_used_seconds"},
+		}},
+		bson.M{"$addFields": bson.M{"students": bson.M{"$size": "$students"}}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	)
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate cohort results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.CohortResultRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode cohort results: %w", err)
+	}
+	return rows, nil
+}
+
+// GetCohortCategoryStats counts each cohort's answers by question difficulty and type
+func (r *analyticsRepository) GetCohortCategoryStats(ctx context.Context, filter *models.CohortFilter) ([]models.CohortCategoryRow, error) {
+	pipeline := append(cohortPipeline(filter),
+		bson.M{"$project": bson.M{"cohort": 1, "question_results.difficulty": 1, "question_results.type": 1, "question_results.is_correct": 1, "question_results.is_skipped": 1}},
+		bson.M{"$unwind": "$question_results"},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"cohort":     "$cohort",
+				"difficulty": "$question_results.difficulty",
+				"type":       "$question_results.type",
+			},
+			"total":   bson.M{"$sum": 1},
+			"correct": bson.M{"$sum": bson.M{"$cond": bson.A{"$question_results.is_correct", 1, 0}}},
+			"skipped": bson.M{"$sum": bson.M{"$cond": bson.A{"$question_results.is_skipped", 1, 0}}},
+		}},
+	)
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate cohort categories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.CohortCategoryRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode cohort categories: %w", err)
+	}
+	return rows, nil
+}
+
+// GetStudentActivity returns every active mahasiswa with their finished attempts since the given
+// time, oldest first, and when they last finished any attempt
+func (r *analyticsRepository) GetStudentActivity(ctx context.Context, since time.Time) ([]models.StudentActivityRow, error) {
+	finished := bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}}
+	ownResult := bson.M{"$expr": bson.M{"$eq": bson.A{"$user_id", "$$uid"}}}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"status":                models.UserStatusActive,
+			"deletion_requested_at": bson.M{"$exists": false},
+		}},
+		{"$lookup": bson.M{
+			"from": "detailed_quiz_results",
+			"let":  bson.M{"uid": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": ownResult},
+				bson.M{"$match": bson.M{"completion_status": finished, "submitted_at": bson.M{"$gte": since}}},
+				bson.M{"$sort": bson.M{"submitted_at": 1}},
+				bson.M{"$project": bson.M{"_id": 0, "score_percentage": 1, "completion_status": 1, "submitted_at": 1}},
+			},
+			"as": "results",
+		}},
+		{"$lookup": bson.M{
+			"from": "detailed_quiz_results",
+			"let":  bson.M{"uid": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": ownResult},
+				bson.M{"$match": bson.M{"completion_status": finished}},
+				bson.M{"$sort": bson.M{"submitted_at": -1}},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"_id": 0, "submitted_at": 1}},
+			},
+			"as": "last_attempt",
+		}},
+		{"$project": bson.M{
+			"full_name":       1,
+			"email":           1,
+			"mahasiswa_id":    1,
+			"faculty":         1,
+			"major":           1,
+			"created_at":      1,
+			"results":         1,
+			"last_attempt_at": bson.M{"$arrayElemAt": bson.A{"$last_attempt.submitted_at", 0}},
+		}},
+	}
+
+	cursor, err := r.mahasiswaCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate student activity: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.StudentActivityRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode student activity: %w", err)
+	}
+	return rows, nil
+}
+
+// UpsertAtRiskStudent stores a student's latest analysis, keeping when they were first flagged
+// and last notified
+func (r *analyticsRepository) UpsertAtRiskStudent(ctx context.Context, student *models.AtRiskStudent) (*models.AtRiskStudent, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"full_name":       student.FullName,
+			"email":           student.Email,
+			"nim":             student.NIM,
+			"faculty":         student.Faculty,
+			"major":           student.Major,
+			"risk_score":      student.RiskScore,
+			"signals":         student.Signals,
+			"attempts":        student.Attempts,
+			"last_attempt_at": student.LastAttemptAt,
+			"analyzed_at":     student.AnalyzedAt,
+		},
+		"$setOnInsert": bson.M{"flagged_at": student.AnalyzedAt},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var stored models.AtRiskStudent
+	if err := r.atRiskCollection.FindOneAndUpdate(ctx, bson.M{"user_id": student.UserID}, update, opts).Decode(&stored); err != nil {
+		return nil, fmt.Errorf("failed to save at-risk student: %w", err)
+	}
+	return &stored, nil
+}
+
+func (r *analyticsRepository) MarkAtRiskNotified(ctx context.Context, userID primitive.ObjectID, notifiedAt time.Time) error {
+	_, err := r.atRiskCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"notified_at": notifiedAt}},
+	)
+	return err
+}
+
+// DeleteAtRiskAnalyzedBefore clears students the latest analysis no longer flagged
+func (r *analyticsRepository) DeleteAtRiskAnalyzedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.atRiskCollection.DeleteMany(ctx, bson.M{"analyzed_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear at-risk students: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *analyticsRepository) ListAtRiskStudents(ctx context.Context, req *models.ListAtRiskStudentsRequest) ([]models.AtRiskStudent, int64, error) {
+	filter := bson.M{}
+	if req.Signal != "" {
+		filter["signals.type"] = req.Signal
+	}
+	if req.Faculty != "" {
+		filter["faculty"] = req.Faculty
+	}
+	if req.Major != "" {
+		filter["major"] = req.Major
+	}
+
+	total, err := r.atRiskCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.D{{Key: "risk_score", Value: -1}, {Key: "flagged_at", Value: 1}})
+
+	cursor, err := r.atRiskCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var students []models.AtRiskStudent
+	if err = cursor.All(ctx, &students); err != nil {
+		return nil, 0, err
+	}
+
+	return students, total, nil
+}
+
+// cohortPipeline selects the finished results matching the filter and sets a "cohort" field on each
+func cohortPipeline(filter *models.CohortFilter) []bson.M {
+	match := bson.M{
+		"completion_status": bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+	}
+	if filter.QuizType != "" {
+		match["quiz_type"] = filter.QuizType
+	}
+	if filter.From != nil || filter.To != nil {
+		submitted := bson.M{}
+		if filter.From != nil {
+			submitted["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			submitted["$lt"] = *filter.To
+		}
+		match["submitted_at"] = submitted
+	}
+	pipeline := []bson.M{{"$match": match}}
+
+	switch filter.GroupBy {
+	case models.CohortByFaculty, models.CohortByMajor:
+		// Only mahasiswa belong to a faculty and major
+		pipeline = append(pipeline,
+			bson.M{"$lookup": bson.M{
+				"from":         "mahasiswa",
+				"localField":   "user_id",
+				"foreignField": "_id",
+				"as":           "student",
+			}},
+			bson.M{"$unwind": "$student"},
+			bson.M{"$addFields": bson.M{"cohort": "$student." + string(filter.GroupBy)}},
+			bson.M{"$match": bson.M{"cohort": bson.M{"$nin": bson.A{nil, ""}}}},
+		)
+	case models.CohortBySemester:
+		month := bson.M{"$month": "$submitted_at"}
+		year := bson.M{"$year": "$submitted_at"}
+		// January closes the odd semester that began the previous August
+		startYear := bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{month, 8}}, year, bson.M{"$subtract": bson.A{year, 1}}}}
+		term := bson.M{"$cond": bson.A{bson.M{"$or": bson.A{bson.M{"$gte": bson.A{month, 8}}, bson.M{"$eq": bson.A{month, 1}}}}, "1", "2"}}
+		pipeline = append(pipeline, bson.M{"$addFields": bson.M{"cohort": bson.M{"$concat": bson.A{
+			bson.M{"$toString": startYear}, "/",
+			bson.M{"$toString": bson.M{"$add": bson.A{startYear, 1}}}, "-", term,
+		}}}})
+	}
+
+	if len(filter.Cohorts) > 0 {
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"cohort": bson.M{"$in": filter.Cohorts}}})
+	}
+	return pipeline
+}
+
+// GetAbandonmentRows counts sessions started in [from, to) by quiz type, status and abandon reason,
+// with how far they had got
+func (r *analyticsRepository) GetAbandonmentRows(ctx context.Context, quizType models.QuizType, from, to *time.Time) ([]models.AbandonmentRow, error) {
+	match := bson.M{}
+	if quizType != "" {
+		match["quiz_type"] = quizType
+	}
+	if from != nil || to != nil {
+		started := bson.M{}
+		if from != nil {
+			started["$gte"] = *from
+		}
+		if to != nil {
+			started["$lt"] = *to
+		}
+		match["start_time"] = started
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"quiz_type": "$quiz_type",
+				"status":    "$status",
+				"reason":    "$abandon_reason",
+			},
+			"sessions": bson.M{"$sum": 1},
+			"answered_ratio": bson.M{"$avg": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$total_questions", 0}},
+				bson.M{"$divide": bson.A{"$answered_count", "$total_questions"}},
+				nil,
+			}}},
+			"stop_question": bson.M{"$avg": "$current_question"},
+		}},
+	}
+
+	cursor, err := r.sessionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate abandoned sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.AbandonmentRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode abandoned sessions: %w", err)
+	}
+	return rows, nil
+}
+
+// GetItemResponses returns every finished attempt's answer to a question with the attempt's score
+func (r *analyticsRepository) GetItemResponses(ctx context.Context, questionID primitive.ObjectID, from, to *time.Time) ([]models.ItemResponseRow, error) {
+	match := bson.M{
+		"question_results.question_id": questionID,
+		"completion_status":            bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+	}
+	if from != nil || to != nil {
+		submitted := bson.M{}
+		if from != nil {
+			submitted["$gte"] = *from
+		}
+		if to != nil {
+			submitted["$lt"] = *to
+		}
+		match["submitted_at"] = submitted
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$project": bson.M{"score_percentage": 1, "question_results": 1}},
+		{"$unwind": "$question_results"},
+		{"$match": bson.M{"question_results.question_id": questionID}},
+		{"$project": bson.M{
+			"_id":              0,
+			"score_percentage": 1,
+			"is_correct":       "$question_results.is_correct",
+			"is_skipped":       "$question_results.is_skipped",
+			"pending_grade":    "$question_results.pending_grade",
+			"points":           "$question_results.points",
+			"points_earned":    "$question_results.points_earned",
+			"user_answer":      "$question_results.user_answer",
+			"time_spent":       "$question_results.time_spent",
+		}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate item responses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.ItemResponseRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode item responses: %w", err)
+	}
+	return rows, nil
+}
+
+// GetQuestionCorrectRates counts attempts and correct answers per question over finished quizzes submitted
+// since the given time. Essays awaiting a grade are left out, and so are questions with fewer attempts
+// than minAttempts.
+func (r *analyticsRepository) GetQuestionCorrectRates(ctx context.Context, since time.Time, minAttempts int) ([]models.QuestionCorrectRateRow, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"completion_status": bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+			"submitted_at":      bson.M{"$gte": since},
+		}},
+		{"$project": bson.M{"question_results": 1}},
+		{"$unwind": "$question_results"},
+		{"$match": bson.M{"question_results.pending_grade": bson.M{"$ne": true}}},
+		{"$group": bson.M{
+			"_id":      "$question_results.question_id",
+			"attempts": bson.M{"$sum": 1},
+			"correct":  bson.M{"$sum": bson.M{"$cond": bson.A{"$question_results.is_correct", 1, 0}}},
+		}},
+		{"$match": bson.M{"attempts": bson.M{"$gte": minAttempts}}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate question correct rates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []models.QuestionCorrectRateRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode question correct rates: %w", err)
+	}
+	return rows, nil
+}
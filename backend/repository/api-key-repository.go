@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrAPIKeyNotFound is returned when no api key has the ID
+var ErrAPIKeyNotFound = models.NotFoundError("api key not found")
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, apiKey *models.APIKey) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	List(ctx context.Context, req *models.ListAPIKeysRequest) ([]models.APIKey, int64, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.APIKey, error)
+	Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.APIKey, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	RecordUsage(ctx context.Context, id primitive.ObjectID, ipAddress string, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewAPIKeyRepository(db *mongo.Database) APIKeyRepository {
+	return &apiKeyRepository{
+		db:         db,
+		collection: db.Collection("api_keys"),
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) error {
+	apiKey.ID = primitive.NewObjectID()
+	apiKey.Status = models.APIKeyActive
+	apiKey.CreatedAt = time.Now()
+	apiKey.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, apiKey)
+	return err
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return r.findOne(ctx, bson.M{"key_hash": keyHash})
+}
+
+func (r *apiKeyRepository) findOne(ctx context.Context, filter bson.M) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&apiKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) List(ctx context.Context, req *models.ListAPIKeysRequest) ([]models.APIKey, int64, error) {
+	filter := bson.M{}
+
+	// Expired is an active key past its expiry; active excludes those
+	now := time.Now()
+	switch req.Status {
+	case models.APIKeyExpired:
+		filter["status"] = models.APIKeyActive
+		filter["expires_at"] = bson.M{"$lte": now}
+	case models.APIKeyActive:
+		filter["status"] = models.APIKeyActive
+		filter["$or"] = bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		}
+	case "":
+	default:
+		filter["status"] = req.Status
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var apiKeys []models.APIKey
+	if err = cursor.All(ctx, &apiKeys); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range apiKeys {
+		if apiKeys[i].Status == models.APIKeyActive && !apiKeys[i].IsUsable() {
+			apiKeys[i].Status = models.APIKeyExpired
+		}
+	}
+
+	return apiKeys, total, nil
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.APIKey, error) {
+	updates["updated_at"] = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var apiKey models.APIKey
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&apiKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// Revoke permanently disables an active key and returns it
+func (r *apiKeyRepository) Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.APIKey, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var apiKey models.APIKey
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.APIKeyActive},
+		bson.M{"$set": bson.M{
+			"status":     models.APIKeyRevoked,
+			"revoked_at": now,
+			"revoked_by": adminID,
+			"updated_at": now,
+		}},
+		opts,
+	).Decode(&apiKey)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.ConflictError("api key is already revoked")
+		}
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) RecordUsage(ctx context.Context, id primitive.ObjectID, ipAddress string, usedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{"last_used_at": usedAt, "last_used_ip": ipAddress},
+			"$inc": bson.M{"usage_count": 1},
+		},
+	)
+	return err
+}
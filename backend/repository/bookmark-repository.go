@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BookmarkRepository interface {
+	Create(ctx context.Context, bookmark *models.Bookmark) error
+	Delete(ctx context.Context, userID, id primitive.ObjectID) error
+	List(ctx context.Context, userID primitive.ObjectID, req *models.ListBookmarksRequest) ([]models.Bookmark, int64, error)
+	GetQuestionIDs(ctx context.Context, userID primitive.ObjectID, questionType models.QuestionType, difficulty models.DifficultyLevel) ([]primitive.ObjectID, error)
+	GetAllByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Bookmark, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type bookmarkRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewBookmarkRepository(db *mongo.Database) BookmarkRepository {
+	return &bookmarkRepository{
+		db:         db,
+		collection: db.Collection("bookmarks"),
+	}
+}
+
+func (r *bookmarkRepository) Create(ctx context.Context, bookmark *models.Bookmark) error {
+	bookmark.ID = primitive.NewObjectID()
+	bookmark.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, bookmark)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ConflictError("question already bookmarked")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) Delete(ctx context.Context, userID, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return models.NotFoundError("bookmark not found")
+	}
+
+	return nil
+}
+
+func (r *bookmarkRepository) List(ctx context.Context, userID primitive.ObjectID, req *models.ListBookmarksRequest) ([]models.Bookmark, int64, error) {
+	filter := r.buildFilter(userID, req.Type, req.Difficulty)
+	if req.Search != "" {
+		filter["question_title"] = bson.M{"$regex": req.Search, "$options": "i"}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var bookmarks []models.Bookmark
+	if err = cursor.All(ctx, &bookmarks); err != nil {
+		return nil, 0, err
+	}
+
+	return bookmarks, total, nil
+}
+
+func (r *bookmarkRepository) GetQuestionIDs(ctx context.Context, userID primitive.ObjectID, questionType models.QuestionType, difficulty models.DifficultyLevel) ([]primitive.ObjectID, error) {
+	filter := r.buildFilter(userID, questionType, difficulty)
+	opts := options.Find().SetProjection(bson.M{"question_id": 1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var questionIDs []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var bookmark models.Bookmark
+		if err := cursor.Decode(&bookmark); err != nil {
+			return nil, err
+		}
+		questionIDs = append(questionIDs, bookmark.QuestionID)
+	}
+
+	return questionIDs, nil
+}
+
+func (r *bookmarkRepository) GetAllByUser(ctx context.Context, userID primitive.ObjectID) ([]models.Bookmark, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var bookmarks []models.Bookmark
+	if err = cursor.All(ctx, &bookmarks); err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+func (r *bookmarkRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (r *bookmarkRepository) buildFilter(userID primitive.ObjectID, questionType models.QuestionType, difficulty models.DifficultyLevel) bson.M {
+	filter := bson.M{"user_id": userID}
+	if questionType != "" {
+		filter["question_type"] = questionType
+	}
+	if difficulty != "" {
+		filter["difficulty"] = difficulty
+	}
+	return filter
+}
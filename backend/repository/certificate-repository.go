@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrCertificateNotFound is returned when no certificate has the ID
+	ErrCertificateNotFound = models.NotFoundError("certificate not found")
+	// ErrCertificateIssued is returned when the result already has a certificate
+	ErrCertificateIssued = models.ConflictError("certificate already issued for this result")
+)
+
+type CertificateRepository interface {
+	Create(ctx context.Context, certificate *models.Certificate) error
+	GetByResultID(ctx context.Context, resultID primitive.ObjectID) (*models.Certificate, error)
+	GetByCode(ctx context.Context, code string) (*models.Certificate, error)
+}
+
+type certificateRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewCertificateRepository(db *mongo.Database) CertificateRepository {
+	return &certificateRepository{
+		db:         db,
+		collection: db.Collection("certificates"),
+	}
+}
+
+// Create registers a certificate. A result has at most one; a second one fails with
+// "certificate already issued for this result".
+func (r *certificateRepository) Create(ctx context.Context, certificate *models.Certificate) error {
+	certificate.ID = primitive.NewObjectID()
+	if certificate.IssuedAt.IsZero() {
+		certificate.IssuedAt = time.Now()
+	}
+
+	_, err := r.collection.InsertOne(ctx, certificate)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrCertificateIssued
+	}
+	return err
+}
+
+func (r *certificateRepository) GetByResultID(ctx context.Context, resultID primitive.ObjectID) (*models.Certificate, error) {
+	return r.findOne(ctx, bson.M{"result_id": resultID})
+}
+
+func (r *certificateRepository) GetByCode(ctx context.Context, code string) (*models.Certificate, error) {
+	return r.findOne(ctx, bson.M{"code": code})
+}
+
+func (r *certificateRepository) findOne(ctx context.Context, filter bson.M) (*models.Certificate, error) {
+	var certificate models.Certificate
+	err := r.collection.FindOne(ctx, filter).Decode(&certificate)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, err
+	}
+	return &certificate, nil
+}
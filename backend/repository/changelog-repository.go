@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrChangelogEntryNotFound is returned when no changelog entry has the ID
+	ErrChangelogEntryNotFound = models.NotFoundError("changelog entry not found")
+	// ErrChangelogVersionExists is returned when another entry has the version
+	ErrChangelogVersionExists = models.ConflictError("changelog version already exists")
+)
+
+type ChangelogRepository interface {
+	Create(ctx context.Context, entry *models.ChangelogEntry) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error)
+	List(ctx context.Context, req *models.ListChangelogRequest, publishedOnly bool) ([]models.ChangelogEntry, int64, error)
+	GetLatestPublished(ctx context.Context, audience models.ChangelogAudience) (*models.ChangelogEntry, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.ChangelogEntry, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type changelogRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewChangelogRepository(db *mongo.Database) ChangelogRepository {
+	return &changelogRepository{
+		db:         db,
+		collection: db.Collection("changelog"),
+	}
+}
+
+// Create inserts the entry; it fails with "changelog version already exists" if the version has notes
+func (r *changelogRepository) Create(ctx context.Context, entry *models.ChangelogEntry) error {
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrChangelogVersionExists
+	}
+	return err
+}
+
+func (r *changelogRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error) {
+	var entry models.ChangelogEntry
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrChangelogEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns entries newest first. Published listings are ordered by when entries were
+// published; admin listings, which include drafts, by when they were written.
+func (r *changelogRepository) List(ctx context.Context, req *models.ListChangelogRequest, publishedOnly bool) ([]models.ChangelogEntry, int64, error) {
+	filter := bson.M{}
+	sortField := "created_at"
+	if publishedOnly {
+		filter["published"] = true
+		sortField = "published_at"
+	}
+	if req.Audience != "" {
+		filter["audiences"] = req.Audience
+	}
+	if req.Since != nil {
+		filter["published_at"] = bson.M{"$gt": *req.Since}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.ChangelogEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// GetLatestPublished returns the most recently published entry for the audience, or nil if there is none
+func (r *changelogRepository) GetLatestPublished(ctx context.Context, audience models.ChangelogAudience) (*models.ChangelogEntry, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "published_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	var entry models.ChangelogEntry
+	err := r.collection.FindOne(ctx, bson.M{"published": true, "audiences": audience}, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Update applies the changes and returns the updated entry
+func (r *changelogRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.ChangelogEntry, error) {
+	updates["updated_at"] = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var entry models.ChangelogEntry
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrChangelogVersionExists
+		}
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrChangelogEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *changelogRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrChangelogEntryNotFound
+	}
+
+	return nil
+}
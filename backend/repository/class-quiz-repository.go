@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClassQuizRepository interface {
+	Create(ctx context.Context, quiz *models.ClassQuiz) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error)
+	GetByJoinCode(ctx context.Context, code string) (*models.ClassQuiz, error)
+	List(ctx context.Context, req *models.ListClassQuizzesRequest) ([]models.ClassQuiz, int64, error)
+	Close(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error)
+
+	// Sessions and results of students who joined
+	GetSessions(ctx context.Context, classQuizID primitive.ObjectID) ([]models.QuizSession, error)
+	GetUserSession(ctx context.Context, classQuizID, userID primitive.ObjectID) (*models.QuizSession, error)
+	GetResults(ctx context.Context, sessionIDs []primitive.ObjectID) ([]models.DetailedQuizResult, error)
+}
+
+type classQuizRepository struct {
+	db                *mongo.Database
+	collection        *mongo.Collection
+	sessionCollection *mongo.Collection
+	resultCollection  *mongo.Collection
+}
+
+func NewClassQuizRepository(db *mongo.Database) ClassQuizRepository {
+	return &classQuizRepository{
+		db:                db,
+		collection:        db.Collection("class_quizzes"),
+		sessionCollection: db.Collection("quiz_sessions"),
+		resultCollection:  db.Collection("detailed_quiz_results"),
+	}
+}
+
+// Create inserts the quiz; it fails with "join code already in use" if the code is taken
+func (r *classQuizRepository) Create(ctx context.Context, quiz *models.ClassQuiz) error {
+	quiz.ID = primitive.NewObjectID()
+	quiz.Status = models.ClassQuizOpen
+	quiz.CreatedAt = time.Now()
+	quiz.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, quiz)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrJoinCodeInUse
+	}
+	return err
+}
+
+func (r *classQuizRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error) {
+	var quiz models.ClassQuiz
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&quiz)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("class quiz not found")
+		}
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *classQuizRepository) GetByJoinCode(ctx context.Context, code string) (*models.ClassQuiz, error) {
+	var quiz models.ClassQuiz
+	err := r.collection.FindOne(ctx, bson.M{"join_code": code}).Decode(&quiz)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("class quiz not found")
+		}
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (r *classQuizRepository) List(ctx context.Context, req *models.ListClassQuizzesRequest) ([]models.ClassQuiz, int64, error) {
+	filter := bson.M{}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var quizzes []models.ClassQuiz
+	if err = cursor.All(ctx, &quizzes); err != nil {
+		return nil, 0, err
+	}
+
+	// Quizzes past their join window are closed even if the instructor never closed them
+	now := time.Now()
+	for i := range quizzes {
+		if !quizzes[i].IsJoinable(now) {
+			quizzes[i].Status = models.ClassQuizClosed
+		}
+	}
+
+	return quizzes, total, nil
+}
+
+// Close stops students from joining and returns the quiz. Students already answering can finish.
+func (r *classQuizRepository) Close(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var quiz models.ClassQuiz
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.ClassQuizOpen},
+		bson.M{"$set": bson.M{
+			"status":     models.ClassQuizClosed,
+			"closed_at":  now,
+			"updated_at": now,
+		}},
+		opts,
+	).Decode(&quiz)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.ConflictError("class quiz is already closed")
+		}
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+func (r *classQuizRepository) GetSessions(ctx context.Context, classQuizID primitive.ObjectID) ([]models.QuizSession, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: 1}})
+	cursor, err := r.sessionCollection.Find(ctx, bson.M{"class_quiz_id": classQuizID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.QuizSession
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetUserSession returns the session the user joined the class quiz with, or nil if they have not joined
+func (r *classQuizRepository) GetUserSession(ctx context.Context, classQuizID, userID primitive.ObjectID) (*models.QuizSession, error) {
+	var session models.QuizSession
+	err := r.sessionCollection.FindOne(ctx, bson.M{"class_quiz_id": classQuizID, "user_id": userID}).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *classQuizRepository) GetResults(ctx context.Context, sessionIDs []primitive.ObjectID) ([]models.DetailedQuizResult, error) {
+	if len(sessionIDs) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.resultCollection.Find(ctx, bson.M{"session_id": bson.M{"$in": sessionIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.DetailedQuizResult
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
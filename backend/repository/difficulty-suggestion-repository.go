@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DifficultySuggestionRepository interface {
+	UpsertPending(ctx context.Context, suggestion *models.DifficultySuggestion) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.DifficultySuggestion, error)
+	List(ctx context.Context, req *models.ListDifficultySuggestionsRequest) ([]models.DifficultySuggestion, int64, error)
+	CountPending(ctx context.Context) (int64, error)
+	WasRejected(ctx context.Context, questionID primitive.ObjectID, from, to models.DifficultyLevel) (bool, error)
+	ClaimForReview(ctx context.Context, id, reviewerID primitive.ObjectID, status models.DifficultySuggestionStatus, note string) error
+	ReleaseReview(ctx context.Context, id primitive.ObjectID) error
+	DeletePending(ctx context.Context, questionID primitive.ObjectID) error
+}
+
+type difficultySuggestionRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewDifficultySuggestionRepository(db *mongo.Database) DifficultySuggestionRepository {
+	return &difficultySuggestionRepository{
+		db:         db,
+		collection: db.Collection("difficulty_suggestions"),
+	}
+}
+
+// UpsertPending queues a suggestion, or refreshes the question's pending one with the latest
+// observation, filling in its ID
+func (r *difficultySuggestionRepository) UpsertPending(ctx context.Context, suggestion *models.DifficultySuggestion) error {
+	now := time.Now()
+	filter := bson.M{"question_id": suggestion.QuestionID, "status": models.SuggestionStatusPending}
+	update := bson.M{
+		"$set": bson.M{
+			"question_title":       suggestion.QuestionTitle,
+			"current_difficulty":   suggestion.CurrentDifficulty,
+			"suggested_difficulty": suggestion.SuggestedDifficulty,
+			"correct_rate":         suggestion.CorrectRate,
+			"attempts":             suggestion.Attempts,
+			"updated_at":           now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(suggestion)
+}
+
+func (r *difficultySuggestionRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.DifficultySuggestion, error) {
+	var suggestion models.DifficultySuggestion
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&suggestion)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("difficulty suggestion not found")
+		}
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+// List returns a page of suggestions. Pending suggestions are listed oldest first so the queue is
+// worked in order.
+func (r *difficultySuggestionRepository) List(ctx context.Context, req *models.ListDifficultySuggestionsRequest) ([]models.DifficultySuggestion, int64, error) {
+	filter := bson.M{}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortOrder := -1
+	if req.Status == models.SuggestionStatusPending {
+		sortOrder = 1
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"created_at": sortOrder})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var suggestions []models.DifficultySuggestion
+	if err = cursor.All(ctx, &suggestions); err != nil {
+		return nil, 0, err
+	}
+
+	return suggestions, total, nil
+}
+
+func (r *difficultySuggestionRepository) CountPending(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"status": models.SuggestionStatusPending})
+}
+
+// WasRejected reports whether an admin already turned down moving the question from one difficulty to another
+func (r *difficultySuggestionRepository) WasRejected(ctx context.Context, questionID primitive.ObjectID, from, to models.DifficultyLevel) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"question_id":          questionID,
+		"status":               models.SuggestionStatusRejected,
+		"current_difficulty":   from,
+		"suggested_difficulty": to,
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ClaimForReview moves a pending suggestion to its reviewed status. Only one reviewer can win,
+// so a suggestion is never applied twice.
+func (r *difficultySuggestionRepository) ClaimForReview(ctx context.Context, id, reviewerID primitive.ObjectID, status models.DifficultySuggestionStatus, note string) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.SuggestionStatusPending},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"review_note": note,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+			"updated_at":  now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return models.ConflictError("difficulty suggestion already reviewed")
+	}
+	return nil
+}
+
+// ReleaseReview returns a claimed suggestion to the queue when applying it failed
+func (r *difficultySuggestionRepository) ReleaseReview(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": models.SuggestionStatusPending, "updated_at": time.Now()},
+			"$unset": bson.M{"review_note": "", "reviewed_by": "", "reviewed_at": ""},
+		},
+	)
+	return err
+}
+
+// DeletePending drops a question's pending suggestion once the observed rate agrees with its label again
+func (r *difficultySuggestionRepository) DeletePending(ctx context.Context, questionID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"question_id": questionID, "status": models.SuggestionStatusPending})
+	return err
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EmbedWidgetRepository interface {
+	Create(ctx context.Context, widget *models.EmbedWidget) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.EmbedWidget, error)
+	GetByToken(ctx context.Context, token string) (*models.EmbedWidget, error)
+	List(ctx context.Context, req *models.ListEmbedWidgetsRequest) ([]models.EmbedWidget, int64, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.EmbedWidget, error)
+	Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.EmbedWidget, error)
+	RecordSession(ctx context.Context, id primitive.ObjectID, startedAt time.Time) error
+}
+
+type embedWidgetRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmbedWidgetRepository(db *mongo.Database) EmbedWidgetRepository {
+	return &embedWidgetRepository{
+		collection: db.Collection("embed_widgets"),
+	}
+}
+
+func (r *embedWidgetRepository) Create(ctx context.Context, widget *models.EmbedWidget) error {
+	widget.ID = primitive.NewObjectID()
+	widget.Status = models.EmbedWidgetActive
+	widget.CreatedAt = time.Now()
+	widget.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, widget)
+	return err
+}
+
+func (r *embedWidgetRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.EmbedWidget, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *embedWidgetRepository) GetByToken(ctx context.Context, token string) (*models.EmbedWidget, error) {
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *embedWidgetRepository) findOne(ctx context.Context, filter bson.M) (*models.EmbedWidget, error) {
+	var widget models.EmbedWidget
+	err := r.collection.FindOne(ctx, filter).Decode(&widget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("embed widget not found")
+		}
+		return nil, err
+	}
+	return &widget, nil
+}
+
+func (r *embedWidgetRepository) List(ctx context.Context, req *models.ListEmbedWidgetsRequest) ([]models.EmbedWidget, int64, error) {
+	filter := bson.M{}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var widgets []models.EmbedWidget
+	if err = cursor.All(ctx, &widgets); err != nil {
+		return nil, 0, err
+	}
+
+	return widgets, total, nil
+}
+
+func (r *embedWidgetRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.EmbedWidget, error) {
+	updates["updated_at"] = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var widget models.EmbedWidget
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&widget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("embed widget not found")
+		}
+		return nil, err
+	}
+
+	return &widget, nil
+}
+
+// Revoke permanently disables an active widget and returns it
+func (r *embedWidgetRepository) Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.EmbedWidget, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var widget models.EmbedWidget
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.EmbedWidgetActive},
+		bson.M{"$set": bson.M{
+			"status":     models.EmbedWidgetRevoked,
+			"revoked_at": now,
+			"revoked_by": adminID,
+			"updated_at": now,
+		}},
+		opts,
+	).Decode(&widget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.ConflictError("embed widget is already revoked")
+		}
+		return nil, err
+	}
+
+	return &widget, nil
+}
+
+func (r *embedWidgetRepository) RecordSession(ctx context.Context, id primitive.ObjectID, startedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{"last_started_at": startedAt},
+			"$inc": bson.M{"session_count": 1},
+		},
+	)
+	return err
+}
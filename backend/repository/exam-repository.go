@@ -0,0 +1,473 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrSittingNotFound is returned when no exam sitting has the ID asked for
+	ErrSittingNotFound = models.NotFoundError("exam sitting not found")
+	// ErrPaperSetNotFound is returned when no paper set was generated for the sitting yet
+	ErrPaperSetNotFound = models.NotFoundError("exam paper set not found")
+)
+
+type ExamRepository interface {
+	// Sittings
+	CreateSitting(ctx context.Context, sitting *models.ExamSitting) error
+	GetSitting(ctx context.Context, id primitive.ObjectID) (*models.ExamSitting, error)
+	ListSittings(ctx context.Context) ([]models.ExamSitting, error)
+	DeleteSitting(ctx context.Context, id primitive.ObjectID) error
+	RefreshParticipantCount(ctx context.Context, sittingID primitive.ObjectID) error
+	UpdateLockdownSettings(ctx context.Context, sittingID primitive.ObjectID, required bool, key string) error
+	UpdateResultRelease(ctx context.Context, sittingID primitive.ObjectID, mode models.ResultReleaseMode, releaseAt *time.Time) error
+	ReleaseResults(ctx context.Context, sittingID primitive.ObjectID, releasedAt time.Time) error
+	UpdateModerationSettings(ctx context.Context, sittingID primitive.ObjectID, passMark, band float64) error
+	UpdateScaling(ctx context.Context, sittingID primitive.ObjectID, scaling *models.ScoreScaling) error
+	ClaimResultsWebhook(ctx context.Context, sittingID primitive.ObjectID, sentAt time.Time) (bool, error)
+	ListSittingsAwaitingResultsWebhook(ctx context.Context, now time.Time) ([]models.ExamSitting, error)
+
+	// Participants
+	UpsertParticipant(ctx context.Context, participant *models.ExamParticipant) error
+	ListParticipants(ctx context.Context, sittingID primitive.ObjectID) ([]models.ExamParticipant, error)
+	DeleteParticipant(ctx context.Context, sittingID, userID primitive.ObjectID) error
+	GetParticipationsByUser(ctx context.Context, userID primitive.ObjectID) ([]models.ExamParticipant, error)
+	DeleteParticipantsByUser(ctx context.Context, userID primitive.ObjectID) error
+
+	// Offline packages
+	CreateOfflinePackage(ctx context.Context, pkg *models.OfflineExamPackage) error
+	GetOfflinePackage(ctx context.Context, sittingID, packageID primitive.ObjectID) (*models.OfflineExamPackage, error)
+	ListOfflinePackages(ctx context.Context, sittingID primitive.ObjectID) ([]models.OfflineExamPackage, error)
+
+	// Printed papers
+	GetPaperSet(ctx context.Context, sittingID primitive.ObjectID) (*models.ExamPaperSet, error)
+	CreatePaperSet(ctx context.Context, set *models.ExamPaperSet) (*models.ExamPaperSet, error)
+	ClaimOfflineSubmission(ctx context.Context, packageID, userID primitive.ObjectID) (bool, error)
+	ReleaseOfflineSubmission(ctx context.Context, packageID, userID primitive.ObjectID) error
+}
+
+type examRepository struct {
+	db                    *mongo.Database
+	sittingCollection     *mongo.Collection
+	participantCollection *mongo.Collection
+	packageCollection     *mongo.Collection
+	paperCollection       *mongo.Collection
+}
+
+func NewExamRepository(db *mongo.Database) ExamRepository {
+	return &examRepository{
+		db:                    db,
+		sittingCollection:     db.Collection("exam_sittings"),
+		participantCollection: db.Collection("exam_participants"),
+		packageCollection:     db.Collection("exam_offline_packages"),
+		paperCollection:       db.Collection("exam_paper_sets"),
+	}
+}
+
+func (r *examRepository) CreateSitting(ctx context.Context, sitting *models.ExamSitting) error {
+	sitting.ID = primitive.NewObjectID()
+	sitting.CreatedAt = time.Now()
+	sitting.UpdatedAt = time.Now()
+
+	_, err := r.sittingCollection.InsertOne(ctx, sitting)
+	return err
+}
+
+func (r *examRepository) GetSitting(ctx context.Context, id primitive.ObjectID) (*models.ExamSitting, error) {
+	var sitting models.ExamSitting
+	err := r.sittingCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&sitting)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSittingNotFound
+		}
+		return nil, err
+	}
+	return &sitting, nil
+}
+
+func (r *examRepository) ListSittings(ctx context.Context) ([]models.ExamSitting, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "scheduled_start", Value: -1}})
+	cursor, err := r.sittingCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sittings []models.ExamSitting
+	if err = cursor.All(ctx, &sittings); err != nil {
+		return nil, err
+	}
+
+	return sittings, nil
+}
+
+func (r *examRepository) DeleteSitting(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.sittingCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrSittingNotFound
+	}
+
+	if _, err = r.participantCollection.DeleteMany(ctx, bson.M{"sitting_id": id}); err != nil {
+		return err
+	}
+
+	if _, err = r.packageCollection.DeleteMany(ctx, bson.M{"sitting_id": id}); err != nil {
+		return err
+	}
+
+	_, err = r.paperCollection.DeleteMany(ctx, bson.M{"sitting_id": id})
+	return err
+}
+
+func (r *examRepository) RefreshParticipantCount(ctx context.Context, sittingID primitive.ObjectID) error {
+	count, err := r.participantCollection.CountDocuments(ctx, bson.M{"sitting_id": sittingID})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID},
+		bson.M{"$set": bson.M{"participant_count": count, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *examRepository) UpdateLockdownSettings(ctx context.Context, sittingID primitive.ObjectID, required bool, key string) error {
+	result, err := r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID},
+		bson.M{"$set": bson.M{
+			"require_lockdown_browser": required,
+			"lockdown_key":             key,
+			"updated_at":               time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSittingNotFound
+	}
+	return nil
+}
+
+// UpdateResultRelease changes the release settings; results released earlier are embargoed again
+// until the new settings release them, and are then sent to department webhooks again
+func (r *examRepository) UpdateResultRelease(ctx context.Context, sittingID primitive.ObjectID, mode models.ResultReleaseMode, releaseAt *time.Time) error {
+	result, err := r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID},
+		bson.M{
+			"$set": bson.M{
+				"result_release":     mode,
+				"results_release_at": releaseAt,
+				"updated_at":         time.Now(),
+			},
+			"$unset": bson.M{"results_released_at": "", "results_webhook_at": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSittingNotFound
+	}
+	return nil
+}
+
+func (r *examRepository) ReleaseResults(ctx context.Context, sittingID primitive.ObjectID, releasedAt time.Time) error {
+	result, err := r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID},
+		bson.M{"$set": bson.M{
+			"results_released_at": releasedAt,
+			"updated_at":          time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSittingNotFound
+	}
+	return nil
+}
+
+// ClaimResultsWebhook marks the sitting's released results as sent to department webhooks,
+// returning false when another caller already claimed them
+func (r *examRepository) ClaimResultsWebhook(ctx context.Context, sittingID primitive.ObjectID, sentAt time.Time) (bool, error) {
+	result, err := r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID, "results_webhook_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"results_webhook_at": sentAt}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListSittingsAwaitingResultsWebhook returns withheld-result sittings whose results have been
+// released, manually or on schedule, but not yet sent to department webhooks. Sittings releasing
+// immediately send each result as it is submitted instead.
+func (r *examRepository) ListSittingsAwaitingResultsWebhook(ctx context.Context, now time.Time) ([]models.ExamSitting, error) {
+	cursor, err := r.sittingCollection.Find(ctx, bson.M{
+		"result_release":     bson.M{"$in": bson.A{models.ResultReleaseScheduled, models.ResultReleaseManual}},
+		"results_webhook_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"results_released_at": bson.M{"$exists": true}},
+			bson.M{"result_release": models.ResultReleaseScheduled, "results_release_at": bson.M{"$lte": now}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sittings []models.ExamSitting
+	if err := cursor.All(ctx, &sittings); err != nil {
+		return nil, err
+	}
+	return sittings, nil
+}
+
+func (r *examRepository) UpdateModerationSettings(ctx context.Context, sittingID primitive.ObjectID, passMark, band float64) error {
+	result, err := r.sittingCollection.UpdateOne(ctx,
+		bson.M{"_id": sittingID},
+		bson.M{"$set": bson.M{
+			"pass_mark":       passMark,
+			"moderation_band": band,
+			"updated_at":      time.Now(),
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSittingNotFound
+	}
+	return nil
+}
+
+// UpdateScaling stores the curve applied to a sitting's results; nil removes it
+func (r *examRepository) UpdateScaling(ctx context.Context, sittingID primitive.ObjectID, scaling *models.ScoreScaling) error {
+	update := bson.M{
+		"$set": bson.M{"scaling": scaling, "updated_at": time.Now()},
+	}
+	if scaling == nil {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"scaling": ""},
+		}
+	}
+
+	result, err := r.sittingCollection.UpdateOne(ctx, bson.M{"_id": sittingID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSittingNotFound
+	}
+	return nil
+}
+
+// UpsertParticipant creates or replaces the seat assignment for (sitting, user)
+func (r *examRepository) UpsertParticipant(ctx context.Context, participant *models.ExamParticipant) error {
+	now := time.Now()
+	filter := bson.M{
+		"sitting_id": participant.SittingID,
+		"user_id":    participant.UserID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"full_name":   participant.FullName,
+			"email":       participant.Email,
+			"nim":         participant.NIM,
+			"venue":       participant.Venue,
+			"room":        participant.Room,
+			"seat":        participant.Seat,
+			"assigned_by": participant.AssignedBy,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := r.participantCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(participant)
+	if err != nil && mongo.IsDuplicateKeyError(err) {
+		return errors.New("seat already assigned to another student")
+	}
+	return err
+}
+
+func (r *examRepository) ListParticipants(ctx context.Context, sittingID primitive.ObjectID) ([]models.ExamParticipant, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "venue", Value: 1},
+		{Key: "room", Value: 1},
+		{Key: "seat", Value: 1},
+	})
+	cursor, err := r.participantCollection.Find(ctx, bson.M{"sitting_id": sittingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var participants []models.ExamParticipant
+	if err = cursor.All(ctx, &participants); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+func (r *examRepository) DeleteParticipant(ctx context.Context, sittingID, userID primitive.ObjectID) error {
+	result, err := r.participantCollection.DeleteOne(ctx, bson.M{"sitting_id": sittingID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return models.NotFoundError("exam participant not found")
+	}
+
+	return nil
+}
+
+func (r *examRepository) GetParticipationsByUser(ctx context.Context, userID primitive.ObjectID) ([]models.ExamParticipant, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.participantCollection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var participants []models.ExamParticipant
+	if err = cursor.All(ctx, &participants); err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+func (r *examRepository) DeleteParticipantsByUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.participantCollection.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+
+	_, err := r.packageCollection.UpdateMany(ctx,
+		bson.M{"ingested_user_ids": userID},
+		bson.M{"$pull": bson.M{"ingested_user_ids": userID}},
+	)
+	return err
+}
+
+func (r *examRepository) CreateOfflinePackage(ctx context.Context, pkg *models.OfflineExamPackage) error {
+	pkg.ID = primitive.NewObjectID()
+	pkg.CreatedAt = time.Now()
+	if pkg.IngestedUserIDs == nil {
+		pkg.IngestedUserIDs = []primitive.ObjectID{}
+	}
+
+	_, err := r.packageCollection.InsertOne(ctx, pkg)
+	return err
+}
+
+func (r *examRepository) GetOfflinePackage(ctx context.Context, sittingID, packageID primitive.ObjectID) (*models.OfflineExamPackage, error) {
+	var pkg models.OfflineExamPackage
+	err := r.packageCollection.FindOne(ctx, bson.M{"_id": packageID, "sitting_id": sittingID}).Decode(&pkg)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("offline package not found")
+		}
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (r *examRepository) ListOfflinePackages(ctx context.Context, sittingID primitive.ObjectID) ([]models.OfflineExamPackage, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"questions": 0, "encryption_key": 0, "ingested_user_ids": 0})
+	cursor, err := r.packageCollection.Find(ctx, bson.M{"sitting_id": sittingID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var packages []models.OfflineExamPackage
+	if err = cursor.All(ctx, &packages); err != nil {
+		return nil, err
+	}
+
+	return packages, nil
+}
+
+// ClaimOfflineSubmission atomically records that a user's answers are being ingested for a package.
+// It returns false if they were already ingested.
+func (r *examRepository) ClaimOfflineSubmission(ctx context.Context, packageID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.packageCollection.UpdateOne(ctx,
+		bson.M{"_id": packageID, "ingested_user_ids": bson.M{"$ne": userID}},
+		bson.M{
+			"$push": bson.M{"ingested_user_ids": userID},
+			"$inc":  bson.M{"ingested_count": 1},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseOfflineSubmission undoes a claim when scoring fails so the answers can be uploaded again
+func (r *examRepository) ReleaseOfflineSubmission(ctx context.Context, packageID, userID primitive.ObjectID) error {
+	_, err := r.packageCollection.UpdateOne(ctx,
+		bson.M{"_id": packageID, "ingested_user_ids": userID},
+		bson.M{
+			"$pull": bson.M{"ingested_user_ids": userID},
+			"$inc":  bson.M{"ingested_count": -1},
+		},
+	)
+	return err
+}
+
+func (r *examRepository) GetPaperSet(ctx context.Context, sittingID primitive.ObjectID) (*models.ExamPaperSet, error) {
+	var set models.ExamPaperSet
+	err := r.paperCollection.FindOne(ctx, bson.M{"sitting_id": sittingID}).Decode(&set)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPaperSetNotFound
+		}
+		return nil, err
+	}
+	return &set, nil
+}
+
+// CreatePaperSet stores a sitting's paper set unless it already has one, returning the stored set,
+// so two admins printing at once end up with the same papers
+func (r *examRepository) CreatePaperSet(ctx context.Context, set *models.ExamPaperSet) (*models.ExamPaperSet, error) {
+	set.ID = primitive.NewObjectID()
+	set.CreatedAt = time.Now()
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var stored models.ExamPaperSet
+	err := r.paperCollection.FindOneAndUpdate(ctx,
+		bson.M{"sitting_id": set.SittingID},
+		bson.M{"$setOnInsert": set},
+		opts,
+	).Decode(&stored)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FlashcardRepository interface {
+	// Decks
+	UpsertDeck(ctx context.Context, deck *models.FlashcardDeck) error
+	GetDeck(ctx context.Context, userID, deckID primitive.ObjectID) (*models.FlashcardDeck, error)
+	ListDecks(ctx context.Context, userID primitive.ObjectID) ([]models.FlashcardDeck, error)
+	DeleteDeck(ctx context.Context, userID, deckID primitive.ObjectID) error
+	RefreshDeckCount(ctx context.Context, deckID primitive.ObjectID) (int, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+
+	// Cards
+	UpsertCards(ctx context.Context, cards []models.Flashcard) error
+	DeleteCardsNotIn(ctx context.Context, deckID primitive.ObjectID, sourceKeys []string) error
+	ListCards(ctx context.Context, userID, deckID primitive.ObjectID) ([]models.Flashcard, error)
+	GetCard(ctx context.Context, userID, cardID primitive.ObjectID) (*models.Flashcard, error)
+	GetDueCards(ctx context.Context, userID primitive.ObjectID, deckID *primitive.ObjectID, dueBefore time.Time, limit int) ([]models.Flashcard, error)
+	UpdateSchedule(ctx context.Context, cardID primitive.ObjectID, schedule models.ReviewSchedule) error
+}
+
+type flashcardRepository struct {
+	db             *mongo.Database
+	deckCollection *mongo.Collection
+	cardCollection *mongo.Collection
+}
+
+func NewFlashcardRepository(db *mongo.Database) FlashcardRepository {
+	return &flashcardRepository{
+		db:             db,
+		deckCollection: db.Collection("flashcard_decks"),
+		cardCollection: db.Collection("flashcards"),
+	}
+}
+
+// UpsertDeck creates the deck for (user, source, source_id) or refreshes its name, filling in the deck ID
+func (r *flashcardRepository) UpsertDeck(ctx context.Context, deck *models.FlashcardDeck) error {
+	now := time.Now()
+	filter := bson.M{
+		"user_id":   deck.UserID,
+		"source":    deck.Source,
+		"source_id": deck.SourceID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":       deck.Name,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"card_count": 0,
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.deckCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(deck)
+}
+
+func (r *flashcardRepository) GetDeck(ctx context.Context, userID, deckID primitive.ObjectID) (*models.FlashcardDeck, error) {
+	var deck models.FlashcardDeck
+	err := r.deckCollection.FindOne(ctx, bson.M{"_id": deckID, "user_id": userID}).Decode(&deck)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("flashcard deck not found")
+		}
+		return nil, err
+	}
+	return &deck, nil
+}
+
+func (r *flashcardRepository) ListDecks(ctx context.Context, userID primitive.ObjectID) ([]models.FlashcardDeck, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}})
+	cursor, err := r.deckCollection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var decks []models.FlashcardDeck
+	if err = cursor.All(ctx, &decks); err != nil {
+		return nil, err
+	}
+
+	return decks, nil
+}
+
+func (r *flashcardRepository) DeleteDeck(ctx context.Context, userID, deckID primitive.ObjectID) error {
+	result, err := r.deckCollection.DeleteOne(ctx, bson.M{"_id": deckID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return models.NotFoundError("flashcard deck not found")
+	}
+
+	_, err = r.cardCollection.DeleteMany(ctx, bson.M{"deck_id": deckID})
+	return err
+}
+
+func (r *flashcardRepository) RefreshDeckCount(ctx context.Context, deckID primitive.ObjectID) (int, error) {
+	count, err := r.cardCollection.CountDocuments(ctx, bson.M{"deck_id": deckID})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = r.deckCollection.UpdateOne(ctx,
+		bson.M{"_id": deckID},
+		bson.M{"$set": bson.M{"card_count": count, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+func (r *flashcardRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.cardCollection.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := r.deckCollection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+// UpsertCards refreshes card content keyed by (deck_id, source_key) while keeping existing review schedules
+func (r *flashcardRepository) UpsertCards(ctx context.Context, cards []models.Flashcard) error {
+	if len(cards) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(cards))
+	for _, card := range cards {
+		filter := bson.M{
+			"deck_id":    card.DeckID,
+			"source_key": card.SourceKey,
+		}
+		update := bson.M{
+			"$set": bson.M{
+				"user_id":    card.UserID,
+				"source":     card.Source,
+				"source_id":  card.SourceID,
+				"front":      card.Front,
+				"back":       card.Back,
+				"updated_at": now,
+			},
+			"$setOnInsert": bson.M{
+				"_id":        primitive.NewObjectID(),
+				"schedule":   card.Schedule,
+				"created_at": now,
+			},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	_, err := r.cardCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *flashcardRepository) DeleteCardsNotIn(ctx context.Context, deckID primitive.ObjectID, sourceKeys []string) error {
+	_, err := r.cardCollection.DeleteMany(ctx, bson.M{
+		"deck_id":    deckID,
+		"source_key": bson.M{"$nin": sourceKeys},
+	})
+	return err
+}
+
+func (r *flashcardRepository) ListCards(ctx context.Context, userID, deckID primitive.ObjectID) ([]models.Flashcard, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.cardCollection.Find(ctx, bson.M{"deck_id": deckID, "user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Flashcard
+	if err = cursor.All(ctx, &cards); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+func (r *flashcardRepository) GetCard(ctx context.Context, userID, cardID primitive.ObjectID) (*models.Flashcard, error) {
+	var card models.Flashcard
+	err := r.cardCollection.FindOne(ctx, bson.M{"_id": cardID, "user_id": userID}).Decode(&card)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("flashcard not found")
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+func (r *flashcardRepository) GetDueCards(ctx context.Context, userID primitive.ObjectID, deckID *primitive.ObjectID, dueBefore time.Time, limit int) ([]models.Flashcard, error) {
+	filter := bson.M{
+		"user_id":         userID,
+		"schedule.due_at": bson.M{"$lte": dueBefore},
+	}
+	if deckID != nil {
+		filter["deck_id"] = *deckID
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "schedule.due_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.cardCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Flashcard
+	if err = cursor.All(ctx, &cards); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+func (r *flashcardRepository) UpdateSchedule(ctx context.Context, cardID primitive.ObjectID, schedule models.ReviewSchedule) error {
+	result, err := r.cardCollection.UpdateOne(ctx,
+		bson.M{"_id": cardID},
+		bson.M{"$set": bson.M{"schedule": schedule, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return models.NotFoundError("flashcard not found")
+	}
+
+	return nil
+}
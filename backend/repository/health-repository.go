@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+}
+
+type healthRepository struct {
+	db *mongo.Database
+}
+
+func NewHealthRepository(db *mongo.Database) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+// Ping checks the primary answers, since most requests write
+func (r *healthRepository) Ping(ctx context.Context) error {
+	return r.db.Client().Ping(ctx, readpref.Primary())
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvitationNotFound is returned when no invitation has the ID
+var ErrInvitationNotFound = models.NotFoundError("invitation not found")
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Invitation, error)
+	GetActiveByEmail(ctx context.Context, email string) (*models.Invitation, error)
+	List(ctx context.Context, req *models.ListInvitationsRequest) ([]models.Invitation, int64, error)
+	SetEmailSent(ctx context.Context, id primitive.ObjectID, sent bool) error
+	MarkAccepted(ctx context.Context, id, userID primitive.ObjectID) error
+	Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.Invitation, error)
+}
+
+type invitationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewInvitationRepository(db *mongo.Database) InvitationRepository {
+	return &invitationRepository{
+		db:         db,
+		collection: db.Collection("invitations"),
+	}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	invitation.ID = primitive.NewObjectID()
+	invitation.Status = models.InvitationPending
+	invitation.CreatedAt = time.Now()
+	invitation.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, invitation)
+	return err
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetActiveByEmail returns a pending, unexpired invitation for the email if there is one
+func (r *invitationRepository) GetActiveByEmail(ctx context.Context, email string) (*models.Invitation, error) {
+	filter := bson.M{
+		"email":      strings.ToLower(email),
+		"status":     models.InvitationPending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	var invitation models.Invitation
+	err := r.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) List(ctx context.Context, req *models.ListInvitationsRequest) ([]models.Invitation, int64, error) {
+	filter := bson.M{}
+
+	// Expired is a pending invitation past its expiry; pending excludes those
+	now := time.Now()
+	switch req.Status {
+	case models.InvitationExpired:
+		filter["status"] = models.InvitationPending
+		filter["expires_at"] = bson.M{"$lte": now}
+	case models.InvitationPending:
+		filter["status"] = models.InvitationPending
+		filter["expires_at"] = bson.M{"$gt": now}
+	case "":
+	default:
+		filter["status"] = req.Status
+	}
+
+	if req.Email != "" {
+		filter["email"] = bson.M{"$regex": regexp.QuoteMeta(strings.ToLower(req.Email))}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []models.Invitation
+	if err = cursor.All(ctx, &invitations); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range invitations {
+		if invitations[i].Status == models.InvitationPending && !now.Before(invitations[i].ExpiresAt) {
+			invitations[i].Status = models.InvitationExpired
+		}
+	}
+
+	return invitations, total, nil
+}
+
+func (r *invitationRepository) SetEmailSent(ctx context.Context, id primitive.ObjectID, sent bool) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"email_sent": sent, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// MarkAccepted consumes a usable invitation; it fails if the invitation was used, revoked or expired meanwhile
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id, userID primitive.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":        id,
+			"status":     models.InvitationPending,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{
+			"status":      models.InvitationAccepted,
+			"accepted_at": now,
+			"accepted_by": userID,
+			"updated_at":  now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeTokenInvalid, "invitation is no longer valid")
+	}
+
+	return nil
+}
+
+// Revoke cancels a pending invitation and returns it
+func (r *invitationRepository) Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.Invitation, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invitation models.Invitation
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.InvitationPending},
+		bson.M{"$set": bson.M{
+			"status":     models.InvitationRevoked,
+			"revoked_at": now,
+			"revoked_by": adminID,
+			"updated_at": now,
+		}},
+		opts,
+	).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.ConflictError("only pending invitations can be revoked")
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type JobRepository interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+	CreateRun(ctx context.Context, run *models.JobRun) error
+	ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error)
+}
+
+type jobRepository struct {
+	db     *mongo.Database
+	leases *mongo.Collection
+	runs   *mongo.Collection
+}
+
+func NewJobRepository(db *mongo.Database) JobRepository {
+	return &jobRepository{
+		db:     db,
+		leases: db.Collection("job_leases"),
+		runs:   db.Collection("job_runs"),
+	}
+}
+
+// AcquireLease takes the named lease for holder until ttl from now, or extends it when holder already
+// has it. It reports false while another holder's lease has not expired.
+func (r *jobRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"holder":     holder,
+		"expires_at": now.Add(ttl),
+		"renewed_at": now,
+	}}
+
+	// When someone else holds the lease the filter misses, and the upsert collides with their document
+	_, err := r.leases.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseLease gives up holder's lease so another instance can take it without waiting for it to expire
+func (r *jobRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := r.leases.DeleteOne(ctx, bson.M{"_id": name, "holder": holder})
+	return err
+}
+
+func (r *jobRepository) CreateRun(ctx context.Context, run *models.JobRun) error {
+	run.ID = primitive.NewObjectID()
+	_, err := r.runs.InsertOne(ctx, run)
+	return err
+}
+
+// ListRuns returns the latest runs, newest first, of one job or of every job when job is empty
+func (r *jobRepository) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	filter := bson.M{}
+	if job != "" {
+		filter["job"] = job
+	}
+	opts := options.Find().SetSort(newestFirst("started_at")).SetLimit(int64(limit))
+
+	cursor, err := r.runs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var runs []models.JobRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
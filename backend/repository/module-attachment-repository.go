@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ModuleAttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.ModuleAttachment) error
+	GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleAttachment, error)
+	ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleAttachment, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+type moduleAttachmentRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleAttachmentRepository(db *mongo.Database) ModuleAttachmentRepository {
+	return &moduleAttachmentRepository{
+		db:         db,
+		collection: db.Collection("module_attachments"),
+	}
+}
+
+func (r *moduleAttachmentRepository) Create(ctx context.Context, attachment *models.ModuleAttachment) error {
+	attachment.ID = primitive.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, attachment)
+	return err
+}
+
+// GetByID finds an attachment of the given module
+func (r *moduleAttachmentRepository) GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleAttachment, error) {
+	var attachment models.ModuleAttachment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "module_id": moduleID}).Decode(&attachment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListByModule lists the attachments of a module and its submodules, oldest first
+func (r *moduleAttachmentRepository) ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleAttachment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"module_id": moduleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var attachments []models.ModuleAttachment
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *moduleAttachmentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
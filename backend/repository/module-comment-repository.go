@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCommentAlreadyReported is returned when the user already reported the comment
+var ErrCommentAlreadyReported = models.ConflictError("comment already reported")
+
+type ModuleCommentRepository interface {
+	Create(ctx context.Context, comment *models.ModuleComment) error
+	GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleComment, error)
+	ListThreads(ctx context.Context, moduleID, subModuleID primitive.ObjectID, page, limit int) ([]models.ModuleComment, int64, error)
+	ListReplies(ctx context.Context, parentIDs []primitive.ObjectID) ([]models.ModuleComment, error)
+	ListReported(ctx context.Context, page, limit int) ([]models.ModuleComment, int64, error)
+	IncrementReplies(ctx context.Context, id primitive.ObjectID) error
+	AddReport(ctx context.Context, id primitive.ObjectID, report models.ModuleCommentReport) error
+	ClearReports(ctx context.Context, id primitive.ObjectID) error
+	Remove(ctx context.Context, id, adminID primitive.ObjectID) error
+	DeleteBySubModules(ctx context.Context, moduleID primitive.ObjectID, subModuleIDs []primitive.ObjectID) error
+	DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error
+}
+
+type moduleCommentRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleCommentRepository(db *mongo.Database) ModuleCommentRepository {
+	return &moduleCommentRepository{
+		db:         db,
+		collection: db.Collection("module_comments"),
+	}
+}
+
+func (r *moduleCommentRepository) Create(ctx context.Context, comment *models.ModuleComment) error {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, comment)
+	return err
+}
+
+// GetByID finds a comment in the discussions of the given module
+func (r *moduleCommentRepository) GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleComment, error) {
+	var comment models.ModuleComment
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "module_id": moduleID}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListThreads returns a page of the top-level comments of a submodule's discussion, newest first
+func (r *moduleCommentRepository) ListThreads(ctx context.Context, moduleID, subModuleID primitive.ObjectID, page, limit int) ([]models.ModuleComment, int64, error) {
+	filter := bson.M{
+		"module_id":    moduleID,
+		"submodule_id": subModuleID,
+		"parent_id":    bson.M{"$exists": false},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	return r.findPage(ctx, filter, opts)
+}
+
+// ListReplies returns the replies to the given comments, oldest first
+func (r *moduleCommentRepository) ListReplies(ctx context.Context, parentIDs []primitive.ObjectID) ([]models.ModuleComment, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"parent_id": bson.M{"$in": parentIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var replies []models.ModuleComment
+	if err = cursor.All(ctx, &replies); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// ListReported returns a page of the reported comments not yet removed, most reported first
+func (r *moduleCommentRepository) ListReported(ctx context.Context, page, limit int) ([]models.ModuleComment, int64, error) {
+	filter := bson.M{"report_count": bson.M{"$gt": 0}, "removed": bson.M{"$ne": true}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "report_count", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	return r.findPage(ctx, filter, opts)
+}
+
+func (r *moduleCommentRepository) findPage(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.ModuleComment, int64, error) {
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []models.ModuleComment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *moduleCommentRepository) IncrementReplies(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$inc": bson.M{"reply_count": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	return err
+}
+
+// AddReport records a learner's report, once per learner and comment
+func (r *moduleCommentRepository) AddReport(ctx context.Context, id primitive.ObjectID, report models.ModuleCommentReport) error {
+	report.CreatedAt = time.Now()
+	filter := bson.M{
+		"_id":             id,
+		"reports.user_id": bson.M{"$ne": report.UserID},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"reports": report},
+		"$inc":  bson.M{"report_count": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentAlreadyReported
+	}
+	return nil
+}
+
+// ClearReports dismisses the reports of a comment, leaving it in place
+func (r *moduleCommentRepository) ClearReports(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$unset": bson.M{"reports": ""},
+		"$set":   bson.M{"report_count": 0},
+	})
+	return err
+}
+
+// Remove takes a comment down, clearing its body and settling its reports. Its replies stay.
+func (r *moduleCommentRepository) Remove(ctx context.Context, id, adminID primitive.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"body":         "",
+			"removed":      true,
+			"removed_by":   adminID,
+			"removed_at":   now,
+			"report_count": 0,
+			"updated_at":   now,
+		},
+		"$unset": bson.M{"reports": ""},
+	})
+	return err
+}
+
+func (r *moduleCommentRepository) DeleteBySubModules(ctx context.Context, moduleID primitive.ObjectID, subModuleIDs []primitive.ObjectID) error {
+	if len(subModuleIDs) == 0 {
+		return nil
+	}
+	_, err := r.collection.DeleteMany(ctx, bson.M{"module_id": moduleID, "submodule_id": bson.M{"$in": subModuleIDs}})
+	return err
+}
+
+func (r *moduleCommentRepository) DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"module_id": moduleID})
+	return err
+}
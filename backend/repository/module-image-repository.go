@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ModuleImageRepository interface {
+	CreateMany(ctx context.Context, images []models.ModuleImage) error
+	GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleImage, error)
+	ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleImage, error)
+	DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error
+}
+
+type moduleImageRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleImageRepository(db *mongo.Database) ModuleImageRepository {
+	return &moduleImageRepository{
+		db:         db,
+		collection: db.Collection("module_images"),
+	}
+}
+
+// CreateMany records images whose IDs are already set, as their URLs name them
+func (r *moduleImageRepository) CreateMany(ctx context.Context, images []models.ModuleImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+	documents := make([]interface{}, len(images))
+	for i := range images {
+		documents[i] = images[i]
+	}
+	_, err := r.collection.InsertMany(ctx, documents)
+	return err
+}
+
+// GetByID finds an image of the given module
+func (r *moduleImageRepository) GetByID(ctx context.Context, moduleID, id primitive.ObjectID) (*models.ModuleImage, error) {
+	var image models.ModuleImage
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "module_id": moduleID}).Decode(&image)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("image not found")
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *moduleImageRepository) ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleImage, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"module_id": moduleID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var images []models.ModuleImage
+	if err = cursor.All(ctx, &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *moduleImageRepository) DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"module_id": moduleID})
+	return err
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ModuleProgressRepository interface {
+	ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.ModuleProgress, error)
+	CompleteModule(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.ModuleProgress, error)
+	CompleteSubModule(ctx context.Context, userID, moduleID, subModuleID primitive.ObjectID) (*models.ModuleProgress, error)
+	SetUnlocked(ctx context.Context, userID, moduleID primitive.ObjectID, unlocked bool, by primitive.ObjectID) (*models.ModuleProgress, error)
+	SetLastPosition(ctx context.Context, userID, moduleID primitive.ObjectID, position models.ReadingPosition) (*models.ModuleProgress, error)
+	ListInProgress(ctx context.Context, userID primitive.ObjectID, limit int) ([]models.ModuleProgress, error)
+}
+
+type moduleProgressRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleProgressRepository(db *mongo.Database) ModuleProgressRepository {
+	return &moduleProgressRepository{
+		db:         db,
+		collection: db.Collection("module_progress"),
+	}
+}
+
+func (r *moduleProgressRepository) ListByUser(ctx context.Context, userID primitive.ObjectID) ([]models.ModuleProgress, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var progress []models.ModuleProgress
+	if err := cursor.All(ctx, &progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// CompleteModule marks a module completed, keeping the time it was first completed
+func (r *moduleProgressRepository) CompleteModule(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.ModuleProgress, error) {
+	return r.upsert(ctx, userID, moduleID, bson.M{
+		"$set": bson.M{"completed": true},
+		"$min": bson.M{"completed_at": time.Now()},
+	})
+}
+
+func (r *moduleProgressRepository) CompleteSubModule(ctx context.Context, userID, moduleID, subModuleID primitive.ObjectID) (*models.ModuleProgress, error) {
+	return r.upsert(ctx, userID, moduleID, bson.M{"$addToSet": bson.M{"completed_submodules": subModuleID}})
+}
+
+func (r *moduleProgressRepository) SetUnlocked(ctx context.Context, userID, moduleID primitive.ObjectID, unlocked bool, by primitive.ObjectID) (*models.ModuleProgress, error) {
+	update := bson.M{"$set": bson.M{"unlocked": true, "unlocked_by": by}}
+	if !unlocked {
+		update = bson.M{"$set": bson.M{"unlocked": false}, "$unset": bson.M{"unlocked_by": ""}}
+	}
+	return r.upsert(ctx, userID, moduleID, update)
+}
+
+func (r *moduleProgressRepository) SetLastPosition(ctx context.Context, userID, moduleID primitive.ObjectID, position models.ReadingPosition) (*models.ModuleProgress, error) {
+	return r.upsert(ctx, userID, moduleID, bson.M{"$set": bson.M{"last_position": position}})
+}
+
+// ListInProgress lists the learner's modules with a reading position that are not completed, most
+// recently read first
+func (r *moduleProgressRepository) ListInProgress(ctx context.Context, userID primitive.ObjectID, limit int) ([]models.ModuleProgress, error) {
+	filter := bson.M{"user_id": userID, "completed": false, "last_position": bson.M{"$exists": true}}
+	opts := options.Find().SetSort(bson.D{{Key: "last_position.updated_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var progress []models.ModuleProgress
+	if err := cursor.All(ctx, &progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// upsert applies an update to a learner's progress in a module, starting it when there is none yet
+func (r *moduleProgressRepository) upsert(ctx context.Context, userID, moduleID primitive.ObjectID, update bson.M) (*models.ModuleProgress, error) {
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updated_at"] = time.Now()
+
+	onInsert := bson.M{"_id": primitive.NewObjectID()}
+	if _, ok := update["$addToSet"]; !ok {
+		onInsert["completed_submodules"] = []primitive.ObjectID{}
+	}
+	if _, ok := set["completed"]; !ok {
+		onInsert["completed"] = false
+	}
+	if _, ok := set["unlocked"]; !ok {
+		onInsert["unlocked"] = false
+	}
+	update["$setOnInsert"] = onInsert
+
+	var progress models.ModuleProgress
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"user_id": userID, "module_id": moduleID}, update, opts).Decode(&progress)
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
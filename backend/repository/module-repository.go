@@ -13,6 +13,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrModuleNotFound is returned when no module has the ID
+	ErrModuleNotFound = models.NotFoundError("module not found")
+	// ErrModuleTextIndexNotFound is returned when the text index search needs has not been created
+	ErrModuleTextIndexNotFound = errors.New("module text index not found")
+)
+
 type ModuleRepository interface {
 	GetAllModules(ctx context.Context, req *models.GetModulesRequest) ([]models.Module, int64, error)
 	GetModuleByID(ctx context.Context, moduleID primitive.ObjectID) (*models.Module, error)
@@ -20,7 +27,15 @@ type ModuleRepository interface {
 	UpdateModule(ctx context.Context, module *models.Module) error
 	DeleteModule(ctx context.Context, moduleID primitive.ObjectID) error
 	GetPublishedModules(ctx context.Context, page, limit int) ([]models.Module, int64, error)
-	BulkUpdateModuleOrder(ctx context.Context, updates []models.ModuleOrderUpdate) error
+	BulkUpdateOrder(ctx context.Context, moduleUpdates []models.ModuleOrderUpdate, subModuleUpdates []models.SubModuleOrderUpdate) error
+	GetRecentlyUpdatedPublished(ctx context.Context, limit int) ([]models.Module, error)
+	MigrateLegacyContent(ctx context.Context) (int, error)
+	MigrateSubModuleTree(ctx context.Context) (int, error)
+	RemovePrerequisite(ctx context.Context, ref models.ContentRef) error
+
+	// Search
+	SearchModules(ctx context.Context, filter bson.M, limit int) ([]models.Module, error)
+	FindModules(ctx context.Context, filter bson.M, limit int) ([]models.Module, error)
 }
 
 type moduleRepository struct {
@@ -80,22 +95,7 @@ func (r *moduleRepository) GetAllModules(ctx context.Context, req *models.GetMod
 	if err = cursor.All(ctx, &modules); err != nil {
 		return nil, 0, err
 	}
-
-	// Sort submodules within each module by order
-	for i := range modules {
-		if len(modules[i].SubModules) > 1 {
-			// Sort submodules by order, then by created_at
-			for j := 0; j < len(modules[i].SubModules)-1; j++ {
-				for k := j + 1; k < len(modules[i].SubModules); k++ {
-					if modules[i].SubModules[j].Order > modules[i].SubModules[k].Order ||
-						(modules[i].SubModules[j].Order == modules[i].SubModules[k].Order &&
-							modules[i].SubModules[j].CreatedAt.After(modules[i].SubModules[k].CreatedAt)) {
-						modules[i].SubModules[j], modules[i].SubModules[k] = modules[i].SubModules[k], modules[i].SubModules[j]
-					}
-				}
-			}
-		}
-	}
+	prepareModules(modules)
 
 	return modules, total, nil
 }
@@ -105,10 +105,13 @@ func (r *moduleRepository) GetModuleByID(ctx context.Context, moduleID primitive
 	err := r.moduleCollection.FindOne(ctx, bson.M{"_id": moduleID}).Decode(&module)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("module not found")
+			return nil, ErrModuleNotFound
 		}
 		return nil, err
 	}
+	module.UpgradeContent()
+	module.ArrangeSubModules()
+	module.EstimateReadingTime()
 	return &module, nil
 }
 
@@ -137,7 +140,7 @@ func (r *moduleRepository) UpdateModule(ctx context.Context, module *models.Modu
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("module not found")
+		return ErrModuleNotFound
 	}
 
 	return nil
@@ -150,7 +153,7 @@ func (r *moduleRepository) DeleteModule(ctx context.Context, moduleID primitive.
 	}
 
 	if result.DeletedCount == 0 {
-		return errors.New("module not found")
+		return ErrModuleNotFound
 	}
 
 	return nil
@@ -186,67 +189,264 @@ func (r *moduleRepository) GetPublishedModules(ctx context.Context, page, limit
 	if err = cursor.All(ctx, &modules); err != nil {
 		return nil, 0, err
 	}
+	prepareModules(modules)
 
-	// Sort submodules within each module by order (only published ones)
+	// Learners only see the submodules published all the way down from the module
 	for i := range modules {
-		if len(modules[i].SubModules) > 0 {
-			// Filter and sort published submodules
-			var publishedSubModules []models.SubModule
-			for _, subModule := range modules[i].SubModules {
-				if subModule.IsPublished {
-					publishedSubModules = append(publishedSubModules, subModule)
-				}
+		publishedSubModules := make([]models.SubModule, 0, len(modules[i].SubModules))
+		for _, subModule := range modules[i].SubModules {
+			if modules[i].SubModulePublished(subModule.ID) {
+				publishedSubModules = append(publishedSubModules, subModule)
 			}
-
-			// Sort by order, then by created_at
-			for j := 0; j < len(publishedSubModules)-1; j++ {
-				for k := j + 1; k < len(publishedSubModules); k++ {
-					if publishedSubModules[j].Order > publishedSubModules[k].Order ||
-						(publishedSubModules[j].Order == publishedSubModules[k].Order &&
-							publishedSubModules[j].CreatedAt.After(publishedSubModules[k].CreatedAt)) {
-						publishedSubModules[j], publishedSubModules[k] = publishedSubModules[k], publishedSubModules[j]
-					}
-				}
-			}
-
-			modules[i].SubModules = publishedSubModules
 		}
+		modules[i].SubModules = publishedSubModules
 	}
 
 	return modules, total, nil
 }
 
-// Add method for bulk order updates
-func (r *moduleRepository) BulkUpdateModuleOrder(ctx context.Context, updates []models.ModuleOrderUpdate) error {
-	// Start a session for transaction
-	session, err := r.db.Client().StartSession()
+// BulkUpdateOrder sets the order of modules and submodules with one ordered bulk write. Submodules are
+// found in their modules with a single query first. Callers run it in a transaction so either every
+// update is saved or none is.
+func (r *moduleRepository) BulkUpdateOrder(ctx context.Context, moduleUpdates []models.ModuleOrderUpdate, subModuleUpdates []models.SubModuleOrderUpdate) error {
+	parents, err := r.orderTargets(ctx, moduleUpdates, subModuleUpdates)
 	if err != nil {
 		return err
 	}
-	defer session.EndSession(ctx)
-
-	// Execute updates in a transaction
-	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
-		for _, update := range updates {
-			filter := bson.M{"_id": update.ModuleID}
-			updateDoc := bson.M{
-				"$set": bson.M{
-					"order":      update.Order,
-					"updated_at": time.Now(),
-					"updated_by": update.UpdatedBy,
-				},
-			}
 
-			result, err := r.moduleCollection.UpdateOne(sc, filter, updateDoc)
-			if err != nil {
-				return nil, err
-			}
-			if result.MatchedCount == 0 {
-				return nil, errors.New("module not found: " + update.ModuleID.Hex())
-			}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(moduleUpdates)+len(subModuleUpdates))
+	for _, update := range moduleUpdates {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": update.ModuleID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"order":      update.Order,
+				"updated_at": now,
+				"updated_by": update.UpdatedBy,
+			}}))
+	}
+	for _, update := range subModuleUpdates {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": parents[update.SubModuleID], "sub_modules._id": update.SubModuleID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"sub_modules.$.order":      update.Order,
+				"sub_modules.$.updated_at": now,
+				"sub_modules.$.updated_by": update.UpdatedBy,
+				"updated_at":               now,
+				"updated_by":               update.UpdatedBy,
+			}}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	_, err = r.moduleCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	return err
+}
+
+// orderTargets checks that every module to reorder exists and finds the module of every submodule to
+// reorder, in one query
+func (r *moduleRepository) orderTargets(ctx context.Context, moduleUpdates []models.ModuleOrderUpdate, subModuleUpdates []models.SubModuleOrderUpdate) (map[primitive.ObjectID]primitive.ObjectID, error) {
+	moduleIDs := make([]primitive.ObjectID, 0, len(moduleUpdates))
+	for _, update := range moduleUpdates {
+		moduleIDs = append(moduleIDs, update.ModuleID)
+	}
+	subModuleIDs := make([]primitive.ObjectID, 0, len(subModuleUpdates))
+	for _, update := range subModuleUpdates {
+		subModuleIDs = append(subModuleIDs, update.SubModuleID)
+	}
+
+	filter := bson.M{"$or": []bson.M{
+		{"_id": bson.M{"$in": moduleIDs}},
+		{"sub_modules._id": bson.M{"$in": subModuleIDs}},
+	}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1, "sub_modules._id": 1})
+	cursor, err := r.moduleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var modules []models.Module
+	if err = cursor.All(ctx, &modules); err != nil {
+		return nil, err
+	}
+
+	found := make(map[primitive.ObjectID]bool, len(modules))
+	parents := make(map[primitive.ObjectID]primitive.ObjectID)
+	for _, module := range modules {
+		found[module.ID] = true
+		for _, sub := range module.SubModules {
+			parents[sub.ID] = module.ID
 		}
-		return nil, nil
-	})
+	}
+	for _, id := range moduleIDs {
+		if !found[id] {
+			return nil, errors.New("module not found: " + id.Hex())
+		}
+	}
+	for _, id := range subModuleIDs {
+		if _, ok := parents[id]; !ok {
+			return nil, errors.New("parent module not found for submodule: " + id.Hex())
+		}
+	}
+	return parents, nil
+}
+
+// GetRecentlyUpdatedPublished returns published modules, most recently changed first. Publishing a
+// submodule updates its module, so recently published submodules are found here too
+func (r *moduleRepository) GetRecentlyUpdatedPublished(ctx context.Context, limit int) ([]models.Module, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.moduleCollection.Find(ctx, bson.M{"is_published": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var modules []models.Module
+	if err = cursor.All(ctx, &modules); err != nil {
+		return nil, err
+	}
+	prepareModules(modules)
+	return modules, nil
+}
+
+// SearchModules finds up to limit modules matching a filter with a $text query, most relevant first
+func (r *moduleRepository) SearchModules(ctx context.Context, filter bson.M, limit int) ([]models.Module, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"search_score": score}).
+		SetSort(bson.D{{Key: "search_score", Value: score}, {Key: "order", Value: 1}}).
+		SetLimit(int64(limit))
 
+	cursor, err := r.moduleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(27) { // IndexNotFound
+			return nil, ErrModuleTextIndexNotFound
+		}
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var modules []models.Module
+	if err = cursor.All(ctx, &modules); err != nil {
+		return nil, err
+	}
+	prepareModules(modules)
+	return modules, nil
+}
+
+// FindModules finds up to limit modules matching a filter in display order
+func (r *moduleRepository) FindModules(ctx context.Context, filter bson.M, limit int) ([]models.Module, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "order", Value: 1}, {Key: "created_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.moduleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var modules []models.Module
+	if err = cursor.All(ctx, &modules); err != nil {
+		return nil, err
+	}
+	prepareModules(modules)
+	return modules, nil
+}
+
+// prepareModules shows modules written before content blocks with their content as a markdown block,
+// their submodules in reading order, and how long they take to read
+func prepareModules(modules []models.Module) {
+	for i := range modules {
+		modules[i].UpgradeContent()
+		modules[i].ArrangeSubModules()
+		modules[i].EstimateReadingTime()
+	}
+}
+
+// MigrateLegacyContent stores the content of modules and submodules written before content blocks as
+// markdown blocks, returning how many modules changed. Reads upgrade such modules on the fly, so this
+// only saves the work.
+func (r *moduleRepository) MigrateLegacyContent(ctx context.Context) (int, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"blocks": bson.M{"$exists": false}},
+		{"sub_modules": bson.M{"$elemMatch": bson.M{"blocks": bson.M{"$exists": false}}}},
+	}}
+	cursor, err := r.moduleCollection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var module models.Module
+		if err := cursor.Decode(&module); err != nil {
+			return migrated, err
+		}
+		if !module.UpgradeContent() {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"blocks": module.Blocks, "sub_modules": module.SubModules}}
+		if _, err := r.moduleCollection.UpdateByID(ctx, module.ID, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// MigrateSubModuleTree readies modules written before submodules could nest: submodules with a missing
+// or circular parent move directly into their module, and each group of siblings is numbered from 1,
+// since flat submodules often share or skip orders. It returns how many modules changed.
+func (r *moduleRepository) MigrateSubModuleTree(ctx context.Context) (int, error) {
+	cursor, err := r.moduleCollection.Find(ctx, bson.M{"sub_modules.0": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var module models.Module
+		if err := cursor.Decode(&module); err != nil {
+			return migrated, err
+		}
+		if !module.RepairSubModuleTree() {
+			continue
+		}
+		update := bson.M{"$set": bson.M{"sub_modules": module.SubModules}}
+		if _, err := r.moduleCollection.UpdateByID(ctx, module.ID, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// RemovePrerequisite drops every prerequisite pointing at deleted content, so learners are not locked
+// out by something they can no longer complete. A module ref takes the refs to its submodules with it.
+func (r *moduleRepository) RemovePrerequisite(ctx context.Context, ref models.ContentRef) error {
+	match := bson.M{"module_id": ref.ModuleID}
+	if ref.SubModuleID != nil {
+		match = bson.M{"submodule_id": *ref.SubModuleID}
+	}
+
+	if _, err := r.moduleCollection.UpdateMany(ctx,
+		bson.M{"prerequisites": bson.M{"$elemMatch": match}},
+		bson.M{"$pull": bson.M{"prerequisites": match}},
+	); err != nil {
+		return err
+	}
+	_, err := r.moduleCollection.UpdateMany(ctx,
+		bson.M{"sub_modules.prerequisites": bson.M{"$elemMatch": match}},
+		bson.M{"$pull": bson.M{"sub_modules.$[].prerequisites": match}},
+	)
 	return err
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrModuleRevisionExists is returned when another save took the revision number first
+var ErrModuleRevisionExists = models.ConflictError("module revision already exists")
+
+// ModuleRevisionRepository stores module revisions. Revisions are never edited; Discard only removes
+// one whose module update did not go through.
+type ModuleRevisionRepository interface {
+	Create(ctx context.Context, revision *models.ModuleRevision) error
+	ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleRevision, error)
+	GetLatest(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleRevision, error)
+	GetByNumber(ctx context.Context, moduleID primitive.ObjectID, revision int) (*models.ModuleRevision, error)
+	Discard(ctx context.Context, id primitive.ObjectID) error
+}
+
+type moduleRevisionRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleRevisionRepository(db *mongo.Database) ModuleRevisionRepository {
+	return &moduleRevisionRepository{
+		db:         db,
+		collection: db.Collection("module_revisions"),
+	}
+}
+
+// Create stores a revision, failing if the module already has one with the same number
+func (r *moduleRevisionRepository) Create(ctx context.Context, revision *models.ModuleRevision) error {
+	revision.ID = primitive.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrModuleRevisionExists
+	}
+	return err
+}
+
+// ListByModule lists a module's revisions newest first, without their snapshots
+func (r *moduleRevisionRepository) ListByModule(ctx context.Context, moduleID primitive.ObjectID) ([]models.ModuleRevision, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revision", Value: -1}}).
+		SetProjection(bson.M{"snapshot": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"module_id": moduleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []models.ModuleRevision
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetLatest returns the module's newest revision, or nil if it has none
+func (r *moduleRevisionRepository) GetLatest(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleRevision, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}})
+
+	var revision models.ModuleRevision
+	err := r.collection.FindOne(ctx, bson.M{"module_id": moduleID}, opts).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *moduleRevisionRepository) GetByNumber(ctx context.Context, moduleID primitive.ObjectID, number int) (*models.ModuleRevision, error) {
+	var revision models.ModuleRevision
+	err := r.collection.FindOne(ctx, bson.M{"module_id": moduleID, "revision": number}).Decode(&revision)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("module revision not found")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *moduleRevisionRepository) Discard(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ModuleViewRepository interface {
+	Record(ctx context.Context, view *models.ModuleView) error
+	Summarize(ctx context.Context, moduleID primitive.ObjectID, from, to time.Time, periodFormat string) (*models.ModuleViewSummary, error)
+	DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error
+}
+
+type moduleViewRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewModuleViewRepository(db *mongo.Database) ModuleViewRepository {
+	return &moduleViewRepository{
+		db:         db,
+		collection: db.Collection("module_views"),
+	}
+}
+
+func (r *moduleViewRepository) Record(ctx context.Context, view *models.ModuleView) error {
+	view.ID = primitive.NewObjectID()
+	view.ViewedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, view)
+	return err
+}
+
+// Summarize aggregates a module's views in [from, to) in one pass: overall, per period named with
+// periodFormat ($dateToString), and per submodule
+func (r *moduleViewRepository) Summarize(ctx context.Context, moduleID primitive.ObjectID, from, to time.Time, periodFormat string) (*models.ModuleViewSummary, error) {
+	stats := func(key interface{}) []bson.M {
+		return []bson.M{
+			{"$group": bson.M{
+				"_id":              key,
+				"views":            bson.M{"$sum": 1},
+				"viewers":          bson.M{"$addToSet": "$user_id"},
+				"average_duration": bson.M{"$avg": "$duration_seconds"},
+			}},
+			{"$addFields": bson.M{"unique_viewers": bson.M{"$size": "$viewers"}}},
+			{"$project": bson.M{"viewers": 0}},
+			{"$sort": bson.M{"_id": 1}},
+		}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"module_id": moduleID,
+			"viewed_at": bson.M{"$gte": from, "$lt": to},
+		}},
+		{"$facet": bson.M{
+			"totals":      stats(nil),
+			"periods":     stats(bson.M{"$dateToString": bson.M{"format": periodFormat, "date": "$viewed_at"}}),
+			"sub_modules": stats(bson.M{"$ifNull": bson.A{"$submodule_id", nil}}),
+		}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Totals     []models.ModuleViewStats  `bson:"totals"`
+		Periods    []models.ModuleViewPeriod `bson:"periods"`
+		SubModules []struct {
+			SubModuleID            *primitive.ObjectID `bson:"_id"`
+			models.ModuleViewStats `bson:",inline"`
+		} `bson:"sub_modules"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	summary := &models.ModuleViewSummary{
+		Periods:    []models.ModuleViewPeriod{},
+		SubModules: make(map[primitive.ObjectID]models.ModuleViewStats),
+	}
+	if len(facets) == 0 {
+		return summary, nil
+	}
+	if len(facets[0].Totals) > 0 {
+		summary.Totals = facets[0].Totals[0]
+	}
+	summary.Periods = append(summary.Periods, facets[0].Periods...)
+	for _, sub := range facets[0].SubModules {
+		if sub.SubModuleID == nil {
+			summary.PageViews = sub.Views
+			continue
+		}
+		summary.SubModules[*sub.SubModuleID] = sub.ModuleViewStats
+	}
+	return summary, nil
+}
+
+func (r *moduleViewRepository) DeleteByModule(ctx context.Context, moduleID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"module_id": moduleID})
+	return err
+}
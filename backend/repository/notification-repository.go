@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	List(ctx context.Context, userID primitive.ObjectID, req *models.ListNotificationsRequest) ([]models.Notification, int64, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	MarkRead(ctx context.Context, userID, id primitive.ObjectID) error
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	MarkEntityRead(ctx context.Context, userID primitive.ObjectID, entityType, entityID string) error
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type notificationRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepository{
+		db:         db,
+		collection: db.Collection("notifications"),
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	notification.ID = primitive.NewObjectID()
+	notification.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, notification)
+	return err
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID primitive.ObjectID, req *models.ListNotificationsRequest) ([]models.Notification, int64, error) {
+	filter := bson.M{"user_id": userID}
+	if req.UnreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []models.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"user_id": userID,
+		"read_at": bson.M{"$exists": false},
+	})
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return models.NotFoundError("notification not found")
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// MarkEntityRead marks every unread notification about an entity as read, e.g. after opening a thread
+func (r *notificationRepository) MarkEntityRead(ctx context.Context, userID primitive.ObjectID, entityType, entityID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{
+			"user_id":     userID,
+			"entity_type": entityType,
+			"entity_id":   entityID,
+			"read_at":     bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	return err
+}
+
+func (r *notificationRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// afterCursor narrows a filter to the items that come after the cursor when sorted by
+// newestFirst(field). Without a cursor the filter is returned as it is.
+func afterCursor(filter bson.M, field string, cursor *models.PageCursor) bson.M {
+	if cursor == nil {
+		return filter
+	}
+	return bson.M{"$and": bson.A{
+		filter,
+		bson.M{"$or": bson.A{
+			bson.M{field: bson.M{"$lt": cursor.At}},
+			bson.M{field: cursor.At, "_id": bson.M{"$lt": cursor.ID}},
+		}},
+	}}
+}
+
+// newestFirst sorts by a time field, newest first, breaking ties by ID so pages never overlap
+func newestFirst(field string) bson.D {
+	return bson.D{{Key: field, Value: -1}, {Key: "_id", Value: -1}}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuestionAudioRepository interface {
+	Save(ctx context.Context, file *models.QuestionAudioFile, audio *models.QuestionAudio) error
+	GetByQuestion(ctx context.Context, questionID primitive.ObjectID) (*models.QuestionAudioFile, error)
+	DeleteByQuestion(ctx context.Context, questionID primitive.ObjectID) error
+}
+
+type questionAudioRepository struct {
+	db                 *mongo.Database
+	collection         *mongo.Collection
+	questionCollection *mongo.Collection
+}
+
+func NewQuestionAudioRepository(db *mongo.Database) QuestionAudioRepository {
+	return &questionAudioRepository{
+		db:                 db,
+		collection:         db.Collection("question_audio"),
+		questionCollection: db.Collection("questions"),
+	}
+}
+
+// Save replaces the question's audio and records it on the question. The question's
+// updated_at is left alone since generating audio doesn't change its content.
+func (r *questionAudioRepository) Save(ctx context.Context, file *models.QuestionAudioFile, audio *models.QuestionAudio) error {
+	file.CreatedAt = time.Now()
+
+	opts := options.Replace().SetUpsert(true)
+	replacement := bson.M{
+		"question_id":  file.QuestionID,
+		"data":         file.Data,
+		"content_type": file.ContentType,
+		"text_hash":    file.TextHash,
+		"created_at":   file.CreatedAt,
+	}
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"question_id": file.QuestionID}, replacement, opts); err != nil {
+		return err
+	}
+
+	result, err := r.questionCollection.UpdateOne(ctx,
+		bson.M{"_id": file.QuestionID},
+		bson.M{"$set": bson.M{"audio": audio}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		// The question was deleted while its audio was being generated
+		_, _ = r.collection.DeleteOne(ctx, bson.M{"question_id": file.QuestionID})
+		return ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+func (r *questionAudioRepository) GetByQuestion(ctx context.Context, questionID primitive.ObjectID) (*models.QuestionAudioFile, error) {
+	var file models.QuestionAudioFile
+	err := r.collection.FindOne(ctx, bson.M{"question_id": questionID}).Decode(&file)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("audio not found")
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *questionAudioRepository) DeleteByQuestion(ctx context.Context, questionID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"question_id": questionID})
+	return err
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCommentNotFound is returned when no question or module comment has the ID
+var ErrCommentNotFound = models.NotFoundError("comment not found")
+
+type QuestionCommentRepository interface {
+	Create(ctx context.Context, comment *models.QuestionComment) error
+	ListByQuestion(ctx context.Context, questionID primitive.ObjectID, resolved *bool) ([]models.QuestionComment, error)
+	CountUnresolved(ctx context.Context, questionID primitive.ObjectID) (int64, error)
+	SetResolved(ctx context.Context, questionID, id primitive.ObjectID, resolved bool, by primitive.ObjectID) (*models.QuestionComment, error)
+}
+
+type questionCommentRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuestionCommentRepository(db *mongo.Database) QuestionCommentRepository {
+	return &questionCommentRepository{
+		db:         db,
+		collection: db.Collection("question_comments"),
+	}
+}
+
+func (r *questionCommentRepository) Create(ctx context.Context, comment *models.QuestionComment) error {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, comment)
+	return err
+}
+
+// ListByQuestion returns a question's comments oldest first, only resolved or unresolved ones when resolved is set
+func (r *questionCommentRepository) ListByQuestion(ctx context.Context, questionID primitive.ObjectID, resolved *bool) ([]models.QuestionComment, error) {
+	filter := bson.M{"question_id": questionID}
+	if resolved != nil {
+		filter["resolved"] = *resolved
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []models.QuestionComment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *questionCommentRepository) CountUnresolved(ctx context.Context, questionID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"question_id": questionID, "resolved": false})
+}
+
+// SetResolved resolves or reopens a comment on the question and returns it as stored
+func (r *questionCommentRepository) SetResolved(ctx context.Context, questionID, id primitive.ObjectID, resolved bool, by primitive.ObjectID) (*models.QuestionComment, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"resolved": true, "resolved_by": by, "resolved_at": now, "updated_at": now},
+	}
+	if !resolved {
+		update = bson.M{
+			"$set":   bson.M{"resolved": false, "updated_at": now},
+			"$unset": bson.M{"resolved_by": "", "resolved_at": ""},
+		}
+	}
+
+	var comment models.QuestionComment
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id, "question_id": questionID}, update, opts).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
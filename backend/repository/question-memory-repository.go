@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrQuestionMemoryNotFound is returned when the user has not practised the question yet
+var ErrQuestionMemoryNotFound = models.NotFoundError("question memory not found")
+
+type QuestionMemoryRepository interface {
+	Get(ctx context.Context, userID, questionID primitive.ObjectID) (*models.QuestionMemory, error)
+	GetForQuestions(ctx context.Context, userID primitive.ObjectID, questionIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.QuestionMemory, error)
+	Save(ctx context.Context, memory *models.QuestionMemory) error
+	GetDue(ctx context.Context, userID primitive.ObjectID, dueBefore time.Time, limit int) ([]models.QuestionMemory, error)
+	CountDue(ctx context.Context, userID primitive.ObjectID, dueBefore time.Time) (int64, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type questionMemoryRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuestionMemoryRepository(db *mongo.Database) QuestionMemoryRepository {
+	return &questionMemoryRepository{
+		db:         db,
+		collection: db.Collection("question_memories"),
+	}
+}
+
+func (r *questionMemoryRepository) Get(ctx context.Context, userID, questionID primitive.ObjectID) (*models.QuestionMemory, error) {
+	var memory models.QuestionMemory
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "question_id": questionID}).Decode(&memory)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrQuestionMemoryNotFound
+		}
+		return nil, err
+	}
+	return &memory, nil
+}
+
+// GetForQuestions returns the user's memories of the given questions, keyed by question ID.
+// Questions the user has never answered are left out.
+func (r *questionMemoryRepository) GetForQuestions(ctx context.Context, userID primitive.ObjectID, questionIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.QuestionMemory, error) {
+	memories := make(map[primitive.ObjectID]*models.QuestionMemory)
+	if len(questionIDs) == 0 {
+		return memories, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"user_id":     userID,
+		"question_id": bson.M{"$in": questionIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []models.QuestionMemory
+	if err = cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	for i := range found {
+		memories[found[i].QuestionID] = &found[i]
+	}
+	return memories, nil
+}
+
+// Save creates or replaces the user's memory of a question, filling in its ID
+func (r *questionMemoryRepository) Save(ctx context.Context, memory *models.QuestionMemory) error {
+	now := time.Now()
+	filter := bson.M{"user_id": memory.UserID, "question_id": memory.QuestionID}
+	update := bson.M{
+		"$set": bson.M{
+			"schedule":   memory.Schedule,
+			"reviews":    memory.Reviews,
+			"lapses":     memory.Lapses,
+			"updated_at": now,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        primitive.NewObjectID(),
+			"created_at": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(memory)
+}
+
+func (r *questionMemoryRepository) GetDue(ctx context.Context, userID primitive.ObjectID, dueBefore time.Time, limit int) ([]models.QuestionMemory, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "schedule.due_at", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"user_id":         userID,
+		"schedule.due_at": bson.M{"$lte": dueBefore},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var memories []models.QuestionMemory
+	if err = cursor.All(ctx, &memories); err != nil {
+		return nil, err
+	}
+	return memories, nil
+}
+
+func (r *questionMemoryRepository) CountDue(ctx context.Context, userID primitive.ObjectID, dueBefore time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"user_id":         userID,
+		"schedule.due_at": bson.M{"$lte": dueBefore},
+	})
+}
+
+func (r *questionMemoryRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
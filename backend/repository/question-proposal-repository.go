@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuestionProposalRepository interface {
+	Create(ctx context.Context, proposal *models.QuestionProposal) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuestionProposal, error)
+	List(ctx context.Context, req *models.ListQuestionProposalsRequest, proposedBy *primitive.ObjectID) ([]models.QuestionProposal, int64, error)
+	CountPending(ctx context.Context, proposedBy *primitive.ObjectID) (int64, error)
+	ClaimForReview(ctx context.Context, id, reviewerID primitive.ObjectID, status models.QuestionProposalStatus, note string) error
+	ReleaseReview(ctx context.Context, id primitive.ObjectID) error
+	SetAccepted(ctx context.Context, id, questionID primitive.ObjectID, xp int) error
+	AnonymizeProposer(ctx context.Context, userID, anonymousID primitive.ObjectID) error
+}
+
+type questionProposalRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuestionProposalRepository(db *mongo.Database) QuestionProposalRepository {
+	return &questionProposalRepository{
+		db:         db,
+		collection: db.Collection("question_proposals"),
+	}
+}
+
+func (r *questionProposalRepository) Create(ctx context.Context, proposal *models.QuestionProposal) error {
+	proposal.ID = primitive.NewObjectID()
+	proposal.Status = models.ProposalStatusPending
+	proposal.CreatedAt = time.Now()
+	proposal.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, proposal)
+	return err
+}
+
+func (r *questionProposalRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuestionProposal, error) {
+	var proposal models.QuestionProposal
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&proposal)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("question proposal not found")
+		}
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// List returns a page of proposals, optionally limited to one student's. Pending proposals are listed
+// oldest first so the queue is worked in order.
+func (r *questionProposalRepository) List(ctx context.Context, req *models.ListQuestionProposalsRequest, proposedBy *primitive.ObjectID) ([]models.QuestionProposal, int64, error) {
+	filter := bson.M{}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+	if proposedBy != nil {
+		filter["proposed_by"] = *proposedBy
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortOrder := -1
+	if req.Status == models.ProposalStatusPending {
+		sortOrder = 1
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"created_at": sortOrder})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var proposals []models.QuestionProposal
+	if err = cursor.All(ctx, &proposals); err != nil {
+		return nil, 0, err
+	}
+
+	return proposals, total, nil
+}
+
+func (r *questionProposalRepository) CountPending(ctx context.Context, proposedBy *primitive.ObjectID) (int64, error) {
+	filter := bson.M{"status": models.ProposalStatusPending}
+	if proposedBy != nil {
+		filter["proposed_by"] = *proposedBy
+	}
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// ClaimForReview moves a pending proposal to its reviewed status. Only one reviewer can win,
+// so a proposal is never accepted into the bank twice.
+func (r *questionProposalRepository) ClaimForReview(ctx context.Context, id, reviewerID primitive.ObjectID, status models.QuestionProposalStatus, note string) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.ProposalStatusPending},
+		bson.M{"$set": bson.M{
+			"status":      status,
+			"review_note": note,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+			"updated_at":  now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return models.ConflictError("question proposal already reviewed")
+	}
+	return nil
+}
+
+// ReleaseReview returns a claimed proposal to the queue when accepting it failed
+func (r *questionProposalRepository) ReleaseReview(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"status": models.ProposalStatusPending, "updated_at": time.Now()},
+			"$unset": bson.M{"review_note": "", "reviewed_by": "", "reviewed_at": ""},
+		},
+	)
+	return err
+}
+
+func (r *questionProposalRepository) SetAccepted(ctx context.Context, id, questionID primitive.ObjectID, xp int) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"question_id": questionID,
+			"xp_awarded":  xp,
+			"updated_at":  time.Now(),
+		}},
+	)
+	return err
+}
+
+// AnonymizeProposer keeps a deleted user's proposals in the queue while detaching them from the account
+func (r *questionProposalRepository) AnonymizeProposer(ctx context.Context, userID, anonymousID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"proposed_by": userID},
+		bson.M{
+			"$set":   bson.M{"proposed_by": anonymousID, "updated_at": time.Now()},
+			"$unset": bson.M{"proposer_email": ""},
+		},
+	)
+	return err
+}
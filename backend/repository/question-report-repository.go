@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuestionReportRepository interface {
+	Create(ctx context.Context, report *models.QuestionReport) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuestionReport, error)
+	List(ctx context.Context, req *models.ListQuestionReportsRequest, questionID *primitive.ObjectID) ([]models.QuestionReport, int64, error)
+	CountOpen(ctx context.Context) (int64, error)
+	Review(ctx context.Context, id, reviewerID primitive.ObjectID, status models.QuestionReportStatus, note string) error
+	AnonymizeReporter(ctx context.Context, userID, anonymousID primitive.ObjectID) error
+}
+
+type questionReportRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuestionReportRepository(db *mongo.Database) QuestionReportRepository {
+	return &questionReportRepository{
+		db:         db,
+		collection: db.Collection("question_reports"),
+	}
+}
+
+// Create stores a report; a student may report each question of a session once
+func (r *questionReportRepository) Create(ctx context.Context, report *models.QuestionReport) error {
+	report.ID = primitive.NewObjectID()
+	report.Status = models.ReportStatusOpen
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ConflictError("question already reported in this session")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *questionReportRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuestionReport, error) {
+	var report models.QuestionReport
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("question report not found")
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// List returns a page of reports, oldest open reports first so the queue is worked in order
+func (r *questionReportRepository) List(ctx context.Context, req *models.ListQuestionReportsRequest, questionID *primitive.ObjectID) ([]models.QuestionReport, int64, error) {
+	filter := bson.M{}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+	if req.Reason != "" {
+		filter["reason"] = req.Reason
+	}
+	if questionID != nil {
+		filter["question_id"] = *questionID
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sortOrder := -1
+	if req.Status == models.ReportStatusOpen {
+		sortOrder = 1
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"created_at": sortOrder})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var reports []models.QuestionReport
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+func (r *questionReportRepository) CountOpen(ctx context.Context) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"status": models.ReportStatusOpen})
+}
+
+func (r *questionReportRepository) Review(ctx context.Context, id, reviewerID primitive.ObjectID, status models.QuestionReportStatus, note string) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"status":          status,
+			"resolution_note": note,
+			"reviewed_by":     reviewerID,
+			"reviewed_at":     now,
+			"updated_at":      now,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return models.NotFoundError("question report not found")
+	}
+	return nil
+}
+
+// AnonymizeReporter keeps a deleted user's reports for review while detaching them from the account
+func (r *questionReportRepository) AnonymizeReporter(ctx context.Context, userID, anonymousID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"reporter_id": userID},
+		bson.M{
+			"$set":   bson.M{"reporter_id": anonymousID, "updated_at": time.Now()},
+			"$unset": bson.M{"user_answer": ""},
+		},
+	)
+	return err
+}
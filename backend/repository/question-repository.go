@@ -13,16 +13,41 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrQuestionNotFound is returned when no question has the ID
+	ErrQuestionNotFound = models.NotFoundError("question not found")
+	// ErrQuestionNotArchived is returned when restoring a question that is not archived
+	ErrQuestionNotArchived = models.ConflictError("question is not archived")
+	// ErrReviewStatusChanged is returned when someone else moved the question through review first
+	ErrReviewStatusChanged = models.ConflictError("question review status changed")
+	// ErrQuestionTextIndexNotFound is returned when the text index search needs has not been created
+	ErrQuestionTextIndexNotFound = errors.New("question text index not found")
+)
+
 type QuestionRepository interface {
 	Create(ctx context.Context, question *models.Question) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Question, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*models.Question, error)
+	GetByTitles(ctx context.Context, titles []string) ([]*models.Question, error)
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	Unarchive(ctx context.Context, id primitive.ObjectID, isActive bool) error
 	List(ctx context.Context, filter bson.M, page, limit int) ([]*models.Question, int64, error)
+	ListAfter(ctx context.Context, filter bson.M, after *models.PageCursor, limit int) ([]*models.Question, error)
+	Search(ctx context.Context, filter bson.M, page, limit int) ([]*models.Question, int64, error)
+	ForEach(ctx context.Context, filter bson.M, fn func(*models.Question) error) error
 	GetByType(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error)
 	GetStats(ctx context.Context) (*models.QuestionStatsResponse, error)
+	SampleQuestions(ctx context.Context, sample models.QuestionSample, limit int) ([]*models.Question, error)
 	GetRandomQuestions(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error)
 	GetRandomQuestionsByDifficulty(ctx context.Context, difficulty models.DifficultyLevel, limit int) ([]*models.Question, error)
+	GetRandomQuestionsByTags(ctx context.Context, tags []string, difficulties []models.DifficultyLevel, limit int) ([]*models.Question, error)
+	ListTags(ctx context.Context) ([]models.QuestionTagCount, error)
+	CountActiveByDifficulty(ctx context.Context) (map[models.DifficultyLevel]int, error)
+	AddReview(ctx context.Context, id primitive.ObjectID, review models.QuestionReview) error
+	TransitionReview(ctx context.Context, id primitive.ObjectID, from models.QuestionReviewStatus, updates bson.M) error
+	CountAuthored(ctx context.Context, from, to *time.Time) ([]models.ContributionCount, error)
+	CountReviewed(ctx context.Context, from, to *time.Time) ([]models.ContributionCount, error)
 }
 
 type questionRepository struct {
@@ -59,13 +84,62 @@ func (r *questionRepository) GetByID(ctx context.Context, id primitive.ObjectID)
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&question)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("question not found")
+			return nil, ErrQuestionNotFound
 		}
 		return nil, err
 	}
 	return &question, nil
 }
 
+func (r *questionRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*models.Question, error) {
+	if len(ids) == 0 {
+		return []*models.Question{}, nil
+	}
+
+	filter := selectable(bson.M{"_id": bson.M{"$in": ids}})
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var questions []*models.Question
+	for cursor.Next(ctx) {
+		var question models.Question
+		if err := cursor.Decode(&question); err != nil {
+			return nil, err
+		}
+		questions = append(questions, &question)
+	}
+
+	return questions, nil
+}
+
+// GetByTitles finds questions, archived ones included, whose title matches one of the given titles
+// ignoring case
+func (r *questionRepository) GetByTitles(ctx context.Context, titles []string) ([]*models.Question, error) {
+	if len(titles) == 0 {
+		return []*models.Question{}, nil
+	}
+
+	opts := options.Find().
+		SetCollation(&options.Collation{Locale: "en", Strength: 2}).
+		SetProjection(bson.M{"title": 1, "archived": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"title": bson.M{"$in": titles}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var questions []*models.Question
+	if err = cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
 func (r *questionRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
 	updates["updated_at"] = time.Now()
 
@@ -93,7 +167,7 @@ func (r *questionRepository) Update(ctx context.Context, id primitive.ObjectID,
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("question not found")
+		return ErrQuestionNotFound
 	}
 
 	return nil
@@ -106,12 +180,30 @@ func (r *questionRepository) Delete(ctx context.Context, id primitive.ObjectID)
 	}
 
 	if result.DeletedCount == 0 {
-		return errors.New("question not found")
+		return ErrQuestionNotFound
 	}
 
 	return nil
 }
 
+// Unarchive returns an archived question to the bank with the given active state
+func (r *questionRepository) Unarchive(ctx context.Context, id primitive.ObjectID, isActive bool) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "archived": true},
+		bson.M{
+			"$set":   bson.M{"is_active": isActive, "updated_at": time.Now()},
+			"$unset": bson.M{"archived": "", "archived_at": "", "archived_by": "", "active_before_archive": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrQuestionNotArchived
+	}
+	return nil
+}
+
 func (r *questionRepository) List(ctx context.Context, filter bson.M, page, limit int) ([]*models.Question, int64, error) {
 	// Calculate skip value
 	skip := (page - 1) * limit
@@ -120,7 +212,7 @@ func (r *questionRepository) List(ctx context.Context, filter bson.M, page, limi
 	opts := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit)).
-		SetSort(bson.M{"created_at": -1})
+		SetSort(newestFirst("created_at"))
 
 	// Get total count
 	total, err := r.collection.CountDocuments(ctx, filter)
@@ -147,11 +239,79 @@ func (r *questionRepository) List(ctx context.Context, filter bson.M, page, limi
 	return questions, total, nil
 }
 
-func (r *questionRepository) GetByType(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error) {
-	filter := bson.M{
-		"type":      questionType,
-		"is_active": true,
+// ListAfter continues a List past the question a cursor marks, without counting the total. It returns
+// one question past the limit, so callers can tell whether more follow.
+func (r *questionRepository) ListAfter(ctx context.Context, filter bson.M, after *models.PageCursor, limit int) ([]*models.Question, error) {
+	opts := options.Find().
+		SetLimit(int64(limit) + 1).
+		SetSort(newestFirst("created_at"))
+
+	cursor, err := r.collection.Find(ctx, afterCursor(filter, "created_at", after), opts)
+	if err != nil {
+		return nil, err
 	}
+	defer cursor.Close(ctx)
+
+	questions := []*models.Question{}
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// Search pages through the questions matching a filter with a $text query, most relevant first,
+// with each question's relevance in SearchScore
+func (r *questionRepository) Search(ctx context.Context, filter bson.M, page, limit int) ([]*models.Question, int64, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"search_score": score}).
+		SetSort(bson.D{{Key: "search_score", Value: score}, {Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, textIndexError(err)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, textIndexError(err)
+	}
+	defer cursor.Close(ctx)
+
+	questions := []*models.Question{}
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, 0, err
+	}
+	return questions, total, nil
+}
+
+// ForEach calls fn with every question matching the filter, newest first as List returns them,
+// decoding one at a time so large result sets are not held in memory. It stops at fn's first error.
+func (r *questionRepository) ForEach(ctx context.Context, filter bson.M, fn func(*models.Question) error) error {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return textIndexError(err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var question models.Question
+		if err := cursor.Decode(&question); err != nil {
+			return err
+		}
+		if err := fn(&question); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *questionRepository) GetByType(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error) {
+	filter := selectable(bson.M{"type": questionType})
 
 	opts := options.Find().
 		SetLimit(int64(limit)).
@@ -175,10 +335,21 @@ func (r *questionRepository) GetByType(ctx context.Context, questionType models.
 	return questions, nil
 }
 
-func (r *questionRepository) GetRandomQuestions(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error) {
-	filter := bson.M{
-		"type":      questionType,
-		"is_active": true,
+// SampleQuestions draws up to limit selectable questions at random. The database filters and samples
+// them, so only the questions drawn are read.
+func (r *questionRepository) SampleQuestions(ctx context.Context, sample models.QuestionSample, limit int) ([]*models.Question, error) {
+	filter := selectable(bson.M{})
+	if sample.Type != "" {
+		filter["type"] = sample.Type
+	}
+	if len(sample.Difficulties) > 0 {
+		filter["difficulty"] = bson.M{"$in": sample.Difficulties}
+	}
+	if len(sample.Tags) > 0 {
+		filter["tags"] = bson.M{"$in": sample.Tags}
+	}
+	if len(sample.ExcludeIDs) > 0 {
+		filter["_id"] = bson.M{"$nin": sample.ExcludeIDs}
 	}
 
 	pipeline := []bson.M{
@@ -192,27 +363,35 @@ func (r *questionRepository) GetRandomQuestions(ctx context.Context, questionTyp
 	}
 	defer cursor.Close(ctx)
 
-	var questions []*models.Question
-	for cursor.Next(ctx) {
-		var question models.Question
-		if err := cursor.Decode(&question); err != nil {
-			return nil, err
-		}
-		questions = append(questions, &question)
+	questions := []*models.Question{}
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
 	}
 
 	return questions, nil
 }
 
+func (r *questionRepository) GetRandomQuestions(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error) {
+	return r.SampleQuestions(ctx, models.QuestionSample{Type: questionType}, limit)
+}
+
 func (r *questionRepository) GetRandomQuestionsByDifficulty(ctx context.Context, difficulty models.DifficultyLevel, limit int) ([]*models.Question, error) {
-	filter := bson.M{
-		"difficulty": difficulty,
-		"is_active":  true,
-	}
+	return r.SampleQuestions(ctx, models.QuestionSample{Difficulties: []models.DifficultyLevel{difficulty}}, limit)
+}
 
+// GetRandomQuestionsByTags samples selectable questions carrying any of the tags, limited to the
+// difficulties when any are given. Without tags, the whole selectable bank is sampled.
+func (r *questionRepository) GetRandomQuestionsByTags(ctx context.Context, tags []string, difficulties []models.DifficultyLevel, limit int) ([]*models.Question, error) {
+	return r.SampleQuestions(ctx, models.QuestionSample{Tags: tags, Difficulties: difficulties}, limit)
+}
+
+// ListTags counts the selectable questions carrying each tag, in tag order
+func (r *questionRepository) ListTags(ctx context.Context) ([]models.QuestionTagCount, error) {
 	pipeline := []bson.M{
-		{"$match": filter},
-		{"$sample": bson.M{"size": limit}},
+		{"$match": selectable(bson.M{})},
+		{"$unwind": "$tags"},
+		{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+		{"$sort": bson.M{"_id": 1}},
 	}
 
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
@@ -221,128 +400,251 @@ func (r *questionRepository) GetRandomQuestionsByDifficulty(ctx context.Context,
 	}
 	defer cursor.Close(ctx)
 
-	var questions []*models.Question
-	for cursor.Next(ctx) {
-		var question models.Question
-		if err := cursor.Decode(&question); err != nil {
-			return nil, err
-		}
-		questions = append(questions, &question)
+	tags := []models.QuestionTagCount{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
 	}
 
-	return questions, nil
+	return tags, nil
 }
 
+// GetStats counts the question bank by type, difficulty and active status, and totals its points, in
+// one aggregation
 func (r *questionRepository) GetStats(ctx context.Context) (*models.QuestionStatsResponse, error) {
-	// Count total questions
-	total, err := r.collection.CountDocuments(ctx, bson.M{})
-	if err != nil {
-		return nil, err
+	byField := func(field string) []bson.M {
+		return []bson.M{{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}}}
+	}
+	pipeline := []bson.M{
+		{"$facet": bson.M{
+			"total":        []bson.M{{"$count": "count"}},
+			"active":       []bson.M{{"$match": bson.M{"is_active": true}}, {"$count": "count"}},
+			"byType":       byField("$type"),
+			"byDifficulty": byField("$difficulty"),
+			"points": []bson.M{{"$group": bson.M{
+				"_id":           nil,
+				"totalPoints":   bson.M{"$sum": "$points"},
+				"averagePoints": bson.M{"$avg": "$points"},
+			}}},
+		}},
 	}
 
-	// Count active questions
-	activeCount, err := r.collection.CountDocuments(ctx, bson.M{"is_active": true})
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 
-	// Count by type
-	typePipeline := []bson.M{
-		{
-			"$group": bson.M{
-				"_id":   "$type",
-				"count": bson.M{"$sum": 1},
-			},
-		},
+	type count struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
 	}
-
-	cursor, err := r.collection.Aggregate(ctx, typePipeline)
-	if err != nil {
+	var facets []struct {
+		Total        []count `bson:"total"`
+		Active       []count `bson:"active"`
+		ByType       []count `bson:"byType"`
+		ByDifficulty []count `bson:"byDifficulty"`
+		Points       []struct {
+			TotalPoints   int64   `bson:"totalPoints"`
+			AveragePoints float64 `bson:"averagePoints"`
+		} `bson:"points"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
+	var total, activeCount, totalPoints int64
+	var averagePoints float64
 	typeStats := make(map[string]int64)
-	for cursor.Next(ctx) {
-		var result struct {
-			ID    string `bson:"_id"`
-			Count int64  `bson:"count"`
+	difficultyStats := make(map[string]int64)
+	if len(facets) > 0 {
+		facet := facets[0]
+		if len(facet.Total) > 0 {
+			total = facet.Total[0].Count
 		}
-		if err := cursor.Decode(&result); err != nil {
-			continue
+		if len(facet.Active) > 0 {
+			activeCount = facet.Active[0].Count
+		}
+		for _, group := range facet.ByType {
+			typeStats[group.ID] = group.Count
+		}
+		for _, group := range facet.ByDifficulty {
+			difficultyStats[group.ID] = group.Count
+		}
+		if len(facet.Points) > 0 {
+			totalPoints = facet.Points[0].TotalPoints
+			averagePoints = facet.Points[0].AveragePoints
 		}
-		typeStats[result.ID] = result.Count
 	}
 
-	// Count by difficulty
-	difficultyPipeline := []bson.M{
-		{
-			"$group": bson.M{
-				"_id":   "$difficulty",
-				"count": bson.M{"$sum": 1},
-			},
-		},
+	return &models.QuestionStatsResponse{
+		Total:          total,
+		ByType:         typeStats,
+		ByDifficulty:   difficultyStats,
+		ActiveCount:    activeCount,
+		InactiveCount:  total - activeCount,
+		SingleChoice:   typeStats["single_choice"],
+		MultipleChoice: typeStats["multiple_choice"],
+		Essay:          typeStats["essay"],
+		FillInBlank:    typeStats["fill_in_blank"],
+		Numeric:        typeStats["numeric"],
+		Ordering:       typeStats["ordering"],
+		TotalPoints:    totalPoints,
+		AveragePoints:  averagePoints,
+	}, nil
+}
+
+// CountActiveByDifficulty counts the questions quizzes can draw from, per difficulty
+func (r *questionRepository) CountActiveByDifficulty(ctx context.Context) (map[models.DifficultyLevel]int, error) {
+	pipeline := []bson.M{
+		{"$match": selectable(bson.M{})},
+		{"$group": bson.M{"_id": "$difficulty", "count": bson.M{"$sum": 1}}},
 	}
 
-	cursor, err = r.collection.Aggregate(ctx, difficultyPipeline)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	difficultyStats := make(map[string]int64)
+	counts := make(map[models.DifficultyLevel]int)
 	for cursor.Next(ctx) {
 		var result struct {
-			ID    string `bson:"_id"`
-			Count int64  `bson:"count"`
+			ID    models.DifficultyLevel `bson:"_id"`
+			Count int                    `bson:"count"`
 		}
 		if err := cursor.Decode(&result); err != nil {
-			continue
+			return nil, err
 		}
-		difficultyStats[result.ID] = result.Count
+		counts[result.ID] = result.Count
 	}
 
-	// Calculate total and average points
-	pointsPipeline := []bson.M{
-		{
-			"$group": bson.M{
-				"_id":           nil,
-				"totalPoints":   bson.M{"$sum": "$points"},
-				"averagePoints": bson.M{"$avg": "$points"},
-			},
-		},
+	return counts, cursor.Err()
+}
+
+// TransitionReview applies updates to a question only while it is still at the from review status,
+// so two reviewers deciding at once cannot both succeed
+func (r *questionRepository) TransitionReview(ctx context.Context, id primitive.ObjectID, from models.QuestionReviewStatus, updates bson.M) error {
+	updates["updated_at"] = time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "review_status": from, "archived": bson.M{"$ne": true}},
+		bson.M{"$set": updates},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrReviewStatusChanged
 	}
+	return nil
+}
 
-	cursor, err = r.collection.Aggregate(ctx, pointsPipeline)
+// AddReview credits a reviewer on a question, refreshing the credit when they already have one
+func (r *questionRepository) AddReview(ctx context.Context, id primitive.ObjectID, review models.QuestionReview) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "reviews.reviewer_id": review.ReviewerID},
+		bson.M{"$set": bson.M{"reviews.$.note": review.Note, "reviews.$.reviewed_at": review.ReviewedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	result, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "reviews.reviewer_id": bson.M{"$ne": review.ReviewerID}},
+		bson.M{"$push": bson.M{"reviews": review}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		// Either the question is gone or a concurrent review by the same reviewer got there first
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountAuthored counts questions per author, created within the range. Accepted proposals count
+// for the student who wrote them rather than the admin who added them.
+func (r *questionRepository) CountAuthored(ctx context.Context, from, to *time.Time) ([]models.ContributionCount, error) {
+	match := bson.M{}
+	if createdAt := timeRange(from, to); createdAt != nil {
+		match["created_at"] = createdAt
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"$ifNull": bson.A{"$contributed_by", "$created_by"}},
+			"count":  bson.M{"$sum": 1},
+			"active": bson.M{"$sum": bson.M{"$cond": bson.A{"$is_active", 1, 0}}},
+		}}},
+	}
+	return r.countContributions(ctx, pipeline)
+}
+
+// CountReviewed counts reviews per reviewer made within the range
+func (r *questionRepository) CountReviewed(ctx context.Context, from, to *time.Time) ([]models.ContributionCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"reviews.0": bson.M{"$exists": true}}}},
+		{{Key: "$unwind", Value: "$reviews"}},
+	}
+	if reviewedAt := timeRange(from, to); reviewedAt != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"reviews.reviewed_at": reviewedAt}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":    "$reviews.reviewer_id",
+		"count":  bson.M{"$sum": 1},
+		"active": bson.M{"$sum": bson.M{"$cond": bson.A{"$is_active", 1, 0}}},
+	}}})
+	return r.countContributions(ctx, pipeline)
+}
+
+func (r *questionRepository) countContributions(ctx context.Context, pipeline mongo.Pipeline) ([]models.ContributionCount, error) {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var totalPoints int64
-	var averagePoints float64
+	var counts []models.ContributionCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
 
-	if cursor.Next(ctx) {
-		var result struct {
-			TotalPoints   int64   `bson:"totalPoints"`
-			AveragePoints float64 `bson:"averagePoints"`
-		}
-		if err := cursor.Decode(&result); err == nil {
-			totalPoints = result.TotalPoints
-			averagePoints = result.AveragePoints
-		}
+// timeRange builds a filter for times within [from, to), or nil when neither bound is set
+func timeRange(from, to *time.Time) bson.M {
+	if from == nil && to == nil {
+		return nil
+	}
+	bounds := bson.M{}
+	if from != nil {
+		bounds["$gte"] = *from
+	}
+	if to != nil {
+		bounds["$lt"] = *to
 	}
+	return bounds
+}
 
-	return &models.QuestionStatsResponse{
-		Total:          total,
-		ByType:         typeStats,
-		ByDifficulty:   difficultyStats,
-		ActiveCount:    activeCount,
-		InactiveCount:  total - activeCount,
-		SingleChoice:   typeStats["single_choice"],
-		MultipleChoice: typeStats["multiple_choice"],
-		Essay:          typeStats["essay"],
-		TotalPoints:    totalPoints,
-		AveragePoints:  averagePoints,
-	}, nil
+// selectable narrows a filter to the questions quizzes may draw: active and approved, which
+// includes questions written before the publishing workflow
+func selectable(filter bson.M) bson.M {
+	filter["is_active"] = true
+	filter["review_status"] = bson.M{"$nin": models.UnapprovedReviewStatuses}
+	return filter
+}
+
+// textIndexError reports a $text query run without the question text index in a form callers can
+// fall back on
+func textIndexError(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(27) { // IndexNotFound
+		return ErrQuestionTextIndexNotFound
+	}
+	return err
 }
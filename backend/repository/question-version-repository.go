@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrQuestionVersionExists is returned when another save took the version number first
+var ErrQuestionVersionExists = models.ConflictError("question version already exists")
+
+// QuestionVersionRepository stores question versions. Versions are never edited; Discard only
+// removes one whose question update did not go through.
+type QuestionVersionRepository interface {
+	Create(ctx context.Context, version *models.QuestionVersion) error
+	ListByQuestion(ctx context.Context, questionID primitive.ObjectID) ([]models.QuestionVersion, error)
+	Discard(ctx context.Context, id primitive.ObjectID) error
+}
+
+type questionVersionRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuestionVersionRepository(db *mongo.Database) QuestionVersionRepository {
+	return &questionVersionRepository{
+		db:         db,
+		collection: db.Collection("question_versions"),
+	}
+}
+
+// Create stores a version, failing if the question already has one with the same number
+func (r *questionVersionRepository) Create(ctx context.Context, version *models.QuestionVersion) error {
+	version.ID = primitive.NewObjectID()
+
+	_, err := r.collection.InsertOne(ctx, version)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrQuestionVersionExists
+	}
+	return err
+}
+
+func (r *questionVersionRepository) ListByQuestion(ctx context.Context, questionID primitive.ObjectID) ([]models.QuestionVersion, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"question_id": questionID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var versions []models.QuestionVersion
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *questionVersionRepository) Discard(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
@@ -13,26 +13,76 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrResultNotFound is returned when no detailed quiz result has the ID
+	ErrResultNotFound = models.NotFoundError("detailed quiz result not found")
+	// ErrSessionNotFound is returned when no quiz session has the token or ID
+	ErrSessionNotFound = models.NotFoundError("quiz session not found")
+)
+
 type QuizSessionRepository interface {
 	// Session Management
 	CreateSession(ctx context.Context, session *models.QuizSession) error
 	GetSessionByID(ctx context.Context, sessionID primitive.ObjectID) (*models.QuizSession, error)
 	GetSessionByToken(ctx context.Context, sessionToken string) (*models.QuizSession, error)
 	GetActiveSessionByUser(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType) (*models.QuizSession, error)
+	CountTemplateAttempts(ctx context.Context, userID, templateID primitive.ObjectID) (int64, error)
+	GetLastAttemptStart(ctx context.Context, userID, templateID primitive.ObjectID) (*time.Time, error)
 	UpdateSession(ctx context.Context, session *models.QuizSession) error
 	UpdateQuestionAnswer(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, answer interface{}, timeSpent int64) error
 	SkipQuestion(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, timeSpent int64) error
+	AnswerAdaptiveQuestion(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, answer interface{}, timeSpent int64, state *models.AdaptiveState, next *models.SessionQuestion) (bool, error)
+	MergeQuestionAnswer(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, answer interface{}, timeSpent int64, answeredAt time.Time) (bool, error)
 	UpdateSessionProgress(ctx context.Context, sessionID primitive.ObjectID, currentQuestion, answeredCount, skippedCount int) error
-	MarkSessionCompleted(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time) error
+	MarkSessionCompleted(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time) (bool, error)
+	AppendIntegrityEvents(ctx context.Context, sessionID primitive.ObjectID, events []models.IntegrityEvent) (bool, error)
+	PauseSession(ctx context.Context, sessionID primitive.ObjectID, event models.SessionPauseEvent) (bool, error)
+	ResumePausedSession(ctx context.Context, sessionID primitive.ObjectID, pausedAt time.Time, event models.SessionPauseEvent) (bool, error)
 
 	// Cleanup
-	CleanupExpiredSessions(ctx context.Context, expiredBefore time.Time) (int64, error)
-	CleanupAbandonedSessions(ctx context.Context, abandonedAfter time.Duration) (int64, error)
+	GetExpiredSessions(ctx context.Context, now time.Time, limit int) ([]models.QuizSession, error)
+	MarkSessionTimedOut(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time, reason models.AbandonReason) (bool, error)
+
+	// Abandonment
+	GetIdleSessions(ctx context.Context, idleBefore time.Time, limit int) ([]models.QuizSession, error)
+	HasSessionStartedSince(ctx context.Context, userID, excludeID primitive.ObjectID, since time.Time) (bool, error)
+	MarkSessionAbandoned(ctx context.Context, sessionID primitive.ObjectID, lastActivity time.Time, reason models.AbandonReason) (bool, error)
+	ResumeAbandonedSession(ctx context.Context, sessionID primitive.ObjectID) (bool, error)
+	DiscardSession(ctx context.Context, sessionID primitive.ObjectID) (bool, error)
 
 	// Results
 	CreateDetailedResult(ctx context.Context, result *models.DetailedQuizResult) error
 	GetDetailedResultBySessionID(ctx context.Context, sessionID primitive.ObjectID) (*models.DetailedQuizResult, error)
+	GetDetailedResultByID(ctx context.Context, id primitive.ObjectID) (*models.DetailedQuizResult, error)
 	GetUserDetailedResults(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, limit int) ([]models.DetailedQuizResult, error)
+	GetBenchmarkDistribution(ctx context.Context, filter *models.BenchmarkPeerFilter) (*models.BenchmarkDistribution, error)
+	GetScoreComparison(ctx context.Context, quizType models.QuizType, since time.Time, score float64) (*models.ScoreComparison, error)
+
+	// Question versions
+	CountQuestionVersionUsage(ctx context.Context, questionID primitive.ObjectID) (map[int]int64, error)
+	IsQuestionReferenced(ctx context.Context, questionID primitive.ObjectID) (bool, error)
+
+	// Account deletion
+	GetUserSessions(ctx context.Context, userID primitive.ObjectID) ([]models.QuizSession, error)
+	GetSessionsStartedBetween(ctx context.Context, quizType models.QuizType, from, to time.Time) ([]models.QuizSession, error)
+	DeleteUserSessions(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	AnonymizeUserResults(ctx context.Context, userID, anonymousID primitive.ObjectID) (int64, error)
+
+	// Guest claims
+	TransferGuestSessions(ctx context.Context, guestID, userID primitive.ObjectID) (int64, int64, error)
+
+	// Moderation
+	GetModerationResults(ctx context.Context, sittingID primitive.ObjectID, status models.ModerationStatus) ([]models.DetailedQuizResult, error)
+	CountPendingModeration(ctx context.Context, sittingID primitive.ObjectID) (int64, error)
+	SyncModerationFlags(ctx context.Context, sittingID primitive.ObjectID, enabled bool, low, high float64, flaggedAt time.Time) (int64, int64, error)
+	UpdateModeratedScore(ctx context.Context, resultID primitive.ObjectID, finalScore, score int, scorePercentage float64, moderation *models.ResultModeration) error
+	GetSittingResults(ctx context.Context, sittingID primitive.ObjectID) ([]models.DetailedQuizResult, error)
+	UpdateScaledScores(ctx context.Context, results []models.DetailedQuizResult) error
+
+	// Essay grading
+	ListPendingEssays(ctx context.Context, req *models.ListPendingGradingRequest, sittingID *primitive.ObjectID) ([]models.PendingEssay, int64, error)
+	SetEssayGrade(ctx context.Context, resultID primitive.ObjectID, questionIndex int, grade *models.EssayGrade, isCorrect bool) (*models.DetailedQuizResult, error)
+	UpdateGradedScores(ctx context.Context, result *models.DetailedQuizResult) error
 }
 
 type quizSessionRepository struct {
@@ -67,7 +117,7 @@ func (r *quizSessionRepository) GetSessionByID(ctx context.Context, sessionID pr
 	err := r.sessionCollection.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&session)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("quiz session not found")
+			return nil, ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("failed to get quiz session: %w", err)
 	}
@@ -79,7 +129,7 @@ func (r *quizSessionRepository) GetSessionByToken(ctx context.Context, sessionTo
 	err := r.sessionCollection.FindOne(ctx, bson.M{"session_token": sessionToken}).Decode(&session)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("quiz session not found")
+			return nil, ErrSessionNotFound
 		}
 		return nil, fmt.Errorf("failed to get quiz session: %w", err)
 	}
@@ -104,6 +154,46 @@ func (r *quizSessionRepository) GetActiveSessionByUser(ctx context.Context, user
 	return &session, nil
 }
 
+// attemptFilter matches the user's sessions from a template that count as attempts: those submitted
+// or timed out. Discarded and abandoned sessions were never scored, so they do not use one up.
+func attemptFilter(userID, templateID primitive.ObjectID) bson.M {
+	return bson.M{
+		"user_id":     userID,
+		"template_id": templateID,
+		"status":      bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+	}
+}
+
+// CountTemplateAttempts counts the attempts the user has made at a template
+func (r *quizSessionRepository) CountTemplateAttempts(ctx context.Context, userID, templateID primitive.ObjectID) (int64, error) {
+	count, err := r.sessionCollection.CountDocuments(ctx, attemptFilter(userID, templateID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count template attempts: %w", err)
+	}
+	return count, nil
+}
+
+// GetLastAttemptStart returns when the user last started an attempt at a template on their own, or nil
+// if they never have. Exam sittings are scheduled by their instructors, so they are left out.
+func (r *quizSessionRepository) GetLastAttemptStart(ctx context.Context, userID, templateID primitive.ObjectID) (*time.Time, error) {
+	filter := attemptFilter(userID, templateID)
+	filter["exam_sitting_id"] = nil
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "start_time", Value: -1}}).
+		SetProjection(bson.M{"start_time": 1})
+
+	var session models.QuizSession
+	err := r.sessionCollection.FindOne(ctx, filter, opts).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last attempt: %w", err)
+	}
+	return &session.StartTime, nil
+}
+
 func (r *quizSessionRepository) UpdateSession(ctx context.Context, session *models.QuizSession) error {
 	session.UpdatedAt = time.Now()
 
@@ -116,7 +206,7 @@ func (r *quizSessionRepository) UpdateSession(ctx context.Context, session *mode
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("quiz session not found")
+		return ErrSessionNotFound
 	}
 
 	return nil
@@ -150,7 +240,7 @@ func (r *quizSessionRepository) UpdateQuestionAnswer(ctx context.Context, sessio
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("quiz session not found")
+		return ErrSessionNotFound
 	}
 
 	return nil
@@ -186,12 +276,97 @@ func (r *quizSessionRepository) SkipQuestion(ctx context.Context, sessionID prim
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("quiz session not found")
+		return ErrSessionNotFound
 	}
 
 	return nil
 }
 
+// AnswerAdaptiveQuestion stores the answer to an adaptive session's latest question, a nil answer
+// skipping it, together with the difficulty it leads to and the next question drawn, if any. It
+// reports false when the question was already answered, so concurrent answers cannot draw twice.
+func (r *quizSessionRepository) AnswerAdaptiveQuestion(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, answer interface{}, timeSpent int64, state *models.AdaptiveState, next *models.SessionQuestion) (bool, error) {
+	prefix := fmt.Sprintf("questions.%d.", questionIndex)
+	filter := bson.M{
+		"_id":                  sessionID,
+		"status":               models.QuizInProgress,
+		"questions":            bson.M{"$size": questionIndex + 1},
+		prefix + "is_answered": false,
+		prefix + "is_skipped":  false,
+	}
+
+	now := time.Now()
+	set := bson.M{
+		prefix + "is_answered":      answer != nil,
+		prefix + "is_skipped":       answer == nil,
+		prefix + "time_spent":       timeSpent,
+		prefix + "first_attempt_at": now,
+		prefix + "last_modified_at": now,
+		"adaptive":                  state,
+		"updated_at":                now,
+	}
+	inc := bson.M{prefix + "visit_count": 1}
+	if answer != nil {
+		set[prefix+"user_answer"] = answer
+		inc["answered_count"] = 1
+	} else {
+		inc["skipped_count"] = 1
+	}
+
+	update := bson.M{"$set": set, "$inc": inc}
+	if next != nil {
+		set["current_question"] = questionIndex + 1
+		inc["max_points"] = next.Points
+		update["$push"] = bson.M{"questions": next}
+	}
+
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to save adaptive answer: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// MergeQuestionAnswer stores an answer given at answeredAt unless the question already holds one
+// modified at or after that time (last write wins). It reports whether the answer was stored, so
+// replaying an answer that was already merged is a no-op.
+func (r *quizSessionRepository) MergeQuestionAnswer(ctx context.Context, sessionID primitive.ObjectID, questionIndex int, answer interface{}, timeSpent int64, answeredAt time.Time) (bool, error) {
+	lastModified := fmt.Sprintf("questions.%d.last_modified_at", questionIndex)
+	filter := bson.M{
+		"_id":    sessionID,
+		"status": models.QuizInProgress,
+		"$or": []bson.M{
+			{lastModified: bson.M{"$exists": false}},
+			{lastModified: nil},
+			{lastModified: bson.M{"$lt": answeredAt}},
+		},
+	}
+
+	updates := bson.M{
+		"$set": bson.M{
+			fmt.Sprintf("questions.%d.user_answer", questionIndex): answer,
+			fmt.Sprintf("questions.%d.is_answered", questionIndex): true,
+			fmt.Sprintf("questions.%d.is_skipped", questionIndex):  false,
+			fmt.Sprintf("questions.%d.time_spent", questionIndex):  timeSpent,
+			lastModified: answeredAt,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{
+			fmt.Sprintf("questions.%d.visit_count", questionIndex): 1,
+		},
+		"$min": bson.M{
+			fmt.Sprintf("questions.%d.first_attempt_at", questionIndex): answeredAt,
+		},
+	}
+
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, updates)
+	if err != nil {
+		return false, fmt.Errorf("failed to merge question answer: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
 func (r *quizSessionRepository) UpdateSessionProgress(ctx context.Context, sessionID primitive.ObjectID, currentQuestion, answeredCount, skippedCount int) error {
 	filter := bson.M{"_id": sessionID}
 	update := bson.M{
@@ -209,14 +384,16 @@ func (r *quizSessionRepository) UpdateSessionProgress(ctx context.Context, sessi
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("quiz session not found")
+		return ErrSessionNotFound
 	}
 
 	return nil
 }
 
-func (r *quizSessionRepository) MarkSessionCompleted(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time) error {
-	filter := bson.M{"_id": sessionID}
+// MarkSessionCompleted ends a session its student submitted. It reports false when the session was
+// no longer in progress, so a late submission never overwrites a timeout or scores a session twice
+func (r *quizSessionRepository) MarkSessionCompleted(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time) (bool, error) {
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress}
 	update := bson.M{
 		"$set": bson.M{
 			"status":       models.QuizCompleted,
@@ -228,63 +405,227 @@ func (r *quizSessionRepository) MarkSessionCompleted(ctx context.Context, sessio
 
 	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("failed to mark session completed: %w", err)
+		return false, fmt.Errorf("failed to mark session completed: %w", err)
 	}
+	return result.ModifiedCount > 0, nil
+}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("quiz session not found")
+// AppendIntegrityEvents adds client-reported integrity events to an in-progress session, keeping the
+// first SessionIntegrityMaxEvents and counting the rest. It reports false when the session was finished
+func (r *quizSessionRepository) AppendIntegrityEvents(ctx context.Context, sessionID primitive.ObjectID, events []models.IntegrityEvent) (bool, error) {
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress}
+	update := bson.M{
+		"$push": bson.M{"integrity_events": bson.M{
+			"$each":  events,
+			"$slice": models.SessionIntegrityMaxEvents,
+		}},
+		"$inc": bson.M{"integrity_event_count": len(events)},
 	}
 
-	return nil
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to record integrity events: %w", err)
+	}
+	return result.MatchedCount > 0, nil
 }
 
-func (r *quizSessionRepository) CleanupExpiredSessions(ctx context.Context, expiredBefore time.Time) (int64, error) {
-	filter := bson.M{
-		"status": models.QuizInProgress,
-		"start_time": bson.M{
-			"$lt": expiredBefore,
+// PauseSession freezes an in-progress session's timer, recording the event in its pause trail.
+// It reports false when the session was finished or already paused
+func (r *quizSessionRepository) PauseSession(ctx context.Context, sessionID primitive.ObjectID, event models.SessionPauseEvent) (bool, error) {
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress, "paused_at": nil}
+	update := bson.M{
+		"$set": bson.M{
+			"paused_at":      event.At,
+			"time_remaining": event.TimeRemaining,
+			"updated_at":     event.At,
 		},
+		"$push": bson.M{"pause_events": event},
 	}
 
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to pause session: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ResumePausedSession restarts the timer of a session paused at pausedAt, adding the pause to the
+// session's paused time. It reports false when the session was finished or resumed in the meantime
+func (r *quizSessionRepository) ResumePausedSession(ctx context.Context, sessionID primitive.ObjectID, pausedAt time.Time, event models.SessionPauseEvent) (bool, error) {
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress, "paused_at": pausedAt}
 	update := bson.M{
 		"$set": bson.M{
-			"status":     models.QuizTimeout,
-			"end_time":   time.Now(),
-			"updated_at": time.Now(),
+			"time_remaining": event.TimeRemaining,
+			"updated_at":     event.At,
 		},
+		"$unset": bson.M{"paused_at": ""},
+		"$inc":   bson.M{"paused_seconds": int64(event.At.Sub(pausedAt).Seconds())},
+		"$push":  bson.M{"pause_events": event},
 	}
 
-	result, err := r.sessionCollection.UpdateMany(ctx, filter, update)
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup expired sessions: %w", err)
+		return false, fmt.Errorf("failed to resume session: %w", err)
 	}
+	return result.ModifiedCount > 0, nil
+}
 
-	return result.ModifiedCount, nil
+// GetExpiredSessions returns in-progress sessions whose time limit, extended by the time they spent
+// paused, has run out, oldest first. Paused sessions never expire.
+func (r *quizSessionRepository) GetExpiredSessions(ctx context.Context, now time.Time, limit int) ([]models.QuizSession, error) {
+	filter := bson.M{
+		"status":    models.QuizInProgress,
+		"paused_at": nil,
+		"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$add": bson.A{
+				"$start_time",
+				bson.M{"$multiply": bson.A{"$time_limit_minutes", 60 * 1000}},
+				bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$paused_seconds", 0}}, 1000}},
+			}},
+			now,
+		}},
+	}
+	opts := options.Find().SetSort(bson.M{"start_time": 1}).SetLimit(int64(limit))
+
+	cursor, err := r.sessionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.QuizSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode expired sessions: %w", err)
+	}
+	return sessions, nil
 }
 
-func (r *quizSessionRepository) CleanupAbandonedSessions(ctx context.Context, abandonedAfter time.Duration) (int64, error) {
-	abandonedBefore := time.Now().Add(-abandonedAfter)
+// MarkSessionTimedOut ends a session that ran out of time, recording the abandon reason when the
+// student was not there to see it. It reports false when the session was no longer in progress,
+// so only one caller goes on to score it
+func (r *quizSessionRepository) MarkSessionTimedOut(ctx context.Context, sessionID primitive.ObjectID, endTime time.Time, reason models.AbandonReason) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress}
+	set := bson.M{
+		"status":       models.QuizTimeout,
+		"is_submitted": true,
+		"end_time":     endTime,
+		"updated_at":   now,
+	}
+	if reason != "" {
+		set["abandon_reason"] = reason
+		set["abandoned_at"] = now
+	}
 
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark session timed out: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetIdleSessions returns in-progress sessions with no activity since idleBefore, least recently active
+// first. Paused sessions are left alone, since students cannot answer until an admin resumes them.
+func (r *quizSessionRepository) GetIdleSessions(ctx context.Context, idleBefore time.Time, limit int) ([]models.QuizSession, error) {
 	filter := bson.M{
-		"status": models.QuizInProgress,
-		"updated_at": bson.M{
-			"$lt": abandonedBefore,
-		},
+		"status":     models.QuizInProgress,
+		"paused_at":  nil,
+		"updated_at": bson.M{"$lt": idleBefore},
 	}
+	opts := options.Find().
+		SetSort(bson.M{"updated_at": 1}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"questions": 0})
+
+	cursor, err := r.sessionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idle sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
 
+	var sessions []models.QuizSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode idle sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// HasSessionStartedSince reports whether the user started any session other than excludeID after since
+func (r *quizSessionRepository) HasSessionStartedSince(ctx context.Context, userID, excludeID primitive.ObjectID, since time.Time) (bool, error) {
+	count, err := r.sessionCollection.CountDocuments(ctx, bson.M{
+		"$or":        bson.A{bson.M{"user_id": userID}, bson.M{"team_member_ids": userID}},
+		"_id":        bson.M{"$ne": excludeID},
+		"start_time": bson.M{"$gt": since},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to check newer sessions: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkSessionAbandoned abandons an in-progress session. It reports false when the session was
+// finished or saw activity after lastActivity in the meantime
+func (r *quizSessionRepository) MarkSessionAbandoned(ctx context.Context, sessionID primitive.ObjectID, lastActivity time.Time, reason models.AbandonReason) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"_id": sessionID, "status": models.QuizInProgress, "updated_at": lastActivity}
 	update := bson.M{
 		"$set": bson.M{
-			"status":     models.QuizAbandoned,
-			"updated_at": time.Now(),
+			"status":         models.QuizAbandoned,
+			"abandon_reason": reason,
+			"abandoned_at":   now,
+			"updated_at":     now,
 		},
 	}
 
-	result, err := r.sessionCollection.UpdateMany(ctx, filter, update)
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup abandoned sessions: %w", err)
+		return false, fmt.Errorf("failed to mark session abandoned: %w", err)
 	}
+	return result.ModifiedCount > 0, nil
+}
 
-	return result.ModifiedCount, nil
+// ResumeAbandonedSession puts an abandoned session back in progress unless it was discarded
+func (r *quizSessionRepository) ResumeAbandonedSession(ctx context.Context, sessionID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"_id":            sessionID,
+		"status":         models.QuizAbandoned,
+		"abandon_reason": bson.M{"$ne": models.AbandonDiscarded},
+	}
+	update := bson.M{
+		"$set":   bson.M{"status": models.QuizInProgress, "updated_at": time.Now()},
+		"$unset": bson.M{"abandon_reason": "", "abandoned_at": ""},
+	}
+
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to resume session: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// DiscardSession abandons an unfinished session for good. It reports false when the session was
+// submitted or already discarded
+func (r *quizSessionRepository) DiscardSession(ctx context.Context, sessionID primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id":            sessionID,
+		"status":         bson.M{"$in": bson.A{models.QuizInProgress, models.QuizAbandoned}},
+		"abandon_reason": bson.M{"$ne": models.AbandonDiscarded},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         models.QuizAbandoned,
+			"abandon_reason": models.AbandonDiscarded,
+			"abandoned_at":   now,
+			"updated_at":     now,
+		},
+	}
+
+	result, err := r.sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to discard session: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
 }
 
 func (r *quizSessionRepository) CreateDetailedResult(ctx context.Context, result *models.DetailedQuizResult) error {
@@ -305,7 +646,19 @@ func (r *quizSessionRepository) GetDetailedResultBySessionID(ctx context.Context
 	err := r.resultCollection.FindOne(ctx, bson.M{"session_id": sessionID}).Decode(&result)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("detailed quiz result not found")
+			return nil, ErrResultNotFound
+		}
+		return nil, fmt.Errorf("failed to get detailed quiz result: %w", err)
+	}
+	return &result, nil
+}
+
+func (r *quizSessionRepository) GetDetailedResultByID(ctx context.Context, id primitive.ObjectID) (*models.DetailedQuizResult, error) {
+	var result models.DetailedQuizResult
+	err := r.resultCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrResultNotFound
 		}
 		return nil, fmt.Errorf("failed to get detailed quiz result: %w", err)
 	}
@@ -313,7 +666,11 @@ func (r *quizSessionRepository) GetDetailedResultBySessionID(ctx context.Context
 }
 
 func (r *quizSessionRepository) GetUserDetailedResults(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, limit int) ([]models.DetailedQuizResult, error) {
-	filter := bson.M{"user_id": userID}
+	// Team quiz results count for every member
+	filter := bson.M{"$or": []bson.M{
+		{"user_id": userID},
+		{"team_member_ids": userID},
+	}}
 	if quizType != "" {
 		filter["quiz_type"] = quizType
 	}
@@ -335,3 +692,581 @@ func (r *quizSessionRepository) GetUserDetailedResults(ctx context.Context, user
 
 	return results, nil
 }
+
+// GetBenchmarkDistribution builds histograms of the scores and times of finished peer results.
+// Results with essays still to grade are left out, since their scores are not final.
+func (r *quizSessionRepository) GetBenchmarkDistribution(ctx context.Context, filter *models.BenchmarkPeerFilter) (*models.BenchmarkDistribution, error) {
+	match := bson.M{
+		"completion_status": bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+		"pending_essays":    bson.M{"$not": bson.M{"$gt": 0}},
+	}
+	if filter.ExamSittingID != nil {
+		match["exam_sitting_id"] = *filter.ExamSittingID
+	} else {
+		match["quiz_type"] = filter.QuizType
+		match["exam_sitting_id"] = nil
+	}
+	if filter.Since != nil {
+		match["submitted_at"] = bson.M{"$gte": *filter.Since}
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$facet": bson.M{
+			"scores": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$floor": bson.M{"$min": bson.A{"$score_percentage", 100}}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+			"times": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$multiply": bson.A{bson.M{"$floor": bson.M{"$divide": bson.A{"$time_used_seconds", 10}}}, 10}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate benchmark distribution: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var distributions []models.BenchmarkDistribution
+	if err := cursor.All(ctx, &distributions); err != nil {
+		return nil, fmt.Errorf("failed to decode benchmark distribution: %w", err)
+	}
+	if len(distributions) == 0 {
+		return &models.BenchmarkDistribution{}, nil
+	}
+	return &distributions[0], nil
+}
+
+// GetScoreComparison compares a score percentage with practice results of a quiz type submitted since
+// the given time. Scores are counted per hundredth of a percent, so the median needs no sort of every result.
+func (r *quizSessionRepository) GetScoreComparison(ctx context.Context, quizType models.QuizType, since time.Time, score float64) (*models.ScoreComparison, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"quiz_type":         quizType,
+			"exam_sitting_id":   nil,
+			"completion_status": bson.M{"$in": bson.A{models.QuizCompleted, models.QuizTimeout}},
+			"pending_essays":    bson.M{"$not": bson.M{"$gt": 0}},
+			"submitted_at":      bson.M{"$gte": since},
+		}},
+		{"$group": bson.M{
+			"_id":   bson.M{"$round": bson.A{"$score_percentage", 2}},
+			"count": bson.M{"$sum": 1},
+			"sum":   bson.M{"$sum": "$score_percentage"},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate score comparison: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []struct {
+		Score float64 `bson:"_id"`
+		Count int64   `bson:"count"`
+		Sum   float64 `bson:"sum"`
+	}
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("failed to decode score comparison: %w", err)
+	}
+
+	comparison := &models.ScoreComparison{QuizType: quizType}
+	var sum, below float64
+	for _, bucket := range buckets {
+		comparison.PeerCount += bucket.Count
+		sum += bucket.Sum
+		switch {
+		case bucket.Score < score:
+			below += float64(bucket.Count)
+		case bucket.Score == score:
+			below += float64(bucket.Count) / 2
+		}
+	}
+	if comparison.PeerCount == 0 {
+		return comparison, nil
+	}
+
+	n := comparison.PeerCount
+	comparison.MeanScore = sum / float64(n)
+	comparison.Percentile = below / float64(n) * 100
+
+	// The median is the middle score, or the mean of the two middle scores
+	var seen int64
+	lower, upper := -1.0, -1.0
+	for _, bucket := range buckets {
+		seen += bucket.Count
+		if lower < 0 && seen >= (n+1)/2 {
+			lower = bucket.Score
+		}
+		if seen >= n/2+1 {
+			upper = bucket.Score
+			break
+		}
+	}
+	comparison.MedianScore = (lower + upper) / 2
+
+	return comparison, nil
+}
+
+// IsQuestionReferenced reports whether any quiz session or result includes the question
+func (r *quizSessionRepository) IsQuestionReferenced(ctx context.Context, questionID primitive.ObjectID) (bool, error) {
+	opts := options.Count().SetLimit(1)
+
+	sessions, err := r.sessionCollection.CountDocuments(ctx, bson.M{"questions.question_id": questionID}, opts)
+	if err != nil {
+		return false, err
+	}
+	if sessions > 0 {
+		return true, nil
+	}
+
+	results, err := r.resultCollection.CountDocuments(ctx, bson.M{"question_results.question_id": questionID}, opts)
+	if err != nil {
+		return false, err
+	}
+	return results > 0, nil
+}
+
+// CountQuestionVersionUsage counts the quiz sessions built from each version of a question, keyed by version
+func (r *quizSessionRepository) CountQuestionVersionUsage(ctx context.Context, questionID primitive.ObjectID) (map[int]int64, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"questions.question_id": questionID}},
+		{"$project": bson.M{"questions.question_id": 1, "questions.question_version": 1}},
+		{"$unwind": "$questions"},
+		{"$match": bson.M{"questions.question_id": questionID}},
+		{"$group": bson.M{
+			"_id":      bson.M{"$ifNull": bson.A{"$questions.question_version", 0}},
+			"sessions": bson.M{"$addToSet": "$_id"},
+		}},
+		{"$project": bson.M{"count": bson.M{"$size": "$sessions"}}},
+	}
+
+	cursor, err := r.sessionCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count question version usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Version int   `bson:"_id"`
+		Count   int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode question version usage: %w", err)
+	}
+
+	usage := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		usage[row.Version] = row.Count
+	}
+	return usage, nil
+}
+
+// GetSessionsStartedBetween returns sessions of a quiz type started in [from, to], without their questions
+func (r *quizSessionRepository) GetSessionsStartedBetween(ctx context.Context, quizType models.QuizType, from, to time.Time) ([]models.QuizSession, error) {
+	filter := bson.M{
+		"quiz_type":  quizType,
+		"start_time": bson.M{"$gte": from, "$lte": to},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "start_time", Value: 1}}).
+		SetProjection(bson.M{"questions": 0})
+
+	cursor, err := r.sessionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.QuizSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *quizSessionRepository) GetUserSessions(ctx context.Context, userID primitive.ObjectID) ([]models.QuizSession, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start_time", Value: -1}})
+
+	cursor, err := r.sessionCollection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user sessions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.QuizSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *quizSessionRepository) DeleteUserSessions(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.sessionCollection.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// AnonymizeUserResults reassigns the user's detailed results to a placeholder ID so item statistics stay intact
+func (r *quizSessionRepository) AnonymizeUserResults(ctx context.Context, userID, anonymousID primitive.ObjectID) (int64, error) {
+	result, err := r.resultCollection.UpdateMany(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{
+			"user_id":    anonymousID,
+			"anonymized": true,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize detailed results: %w", err)
+	}
+
+	// Team results credit every member, so swap the user out of their teammates' records too
+	if _, err := r.resultCollection.UpdateMany(ctx,
+		bson.M{"team_member_ids": userID},
+		bson.M{"$set": bson.M{
+			"team_member_ids.$": anonymousID,
+			"updated_at":        time.Now(),
+		}},
+	); err != nil {
+		return 0, fmt.Errorf("failed to anonymize team results: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// TransferGuestSessions moves a guest's sessions and detailed results to a registered user.
+// It returns the number of sessions and detailed results moved.
+func (r *quizSessionRepository) TransferGuestSessions(ctx context.Context, guestID, userID primitive.ObjectID) (int64, int64, error) {
+	sessions, err := r.sessionCollection.UpdateMany(ctx,
+		bson.M{"user_id": guestID, "is_guest": true},
+		bson.M{"$set": bson.M{
+			"user_id":    userID,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to transfer guest sessions: %w", err)
+	}
+
+	results, err := r.resultCollection.UpdateMany(ctx,
+		bson.M{"user_id": guestID},
+		bson.M{"$set": bson.M{
+			"user_id":    userID,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return sessions.ModifiedCount, 0, fmt.Errorf("failed to transfer guest results: %w", err)
+	}
+
+	return sessions.ModifiedCount, results.ModifiedCount, nil
+}
+
+// GetModerationResults returns a sitting's results under moderation, lowest score first.
+// An empty status returns both pending and moderated results.
+func (r *quizSessionRepository) GetModerationResults(ctx context.Context, sittingID primitive.ObjectID, status models.ModerationStatus) ([]models.DetailedQuizResult, error) {
+	filter := bson.M{"exam_sitting_id": sittingID, "moderation": bson.M{"$exists": true}}
+	if status != "" {
+		filter["moderation.status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "score_percentage", Value: 1}})
+	cursor, err := r.resultCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.DetailedQuizResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation results: %w", err)
+	}
+	return results, nil
+}
+
+func (r *quizSessionRepository) CountPendingModeration(ctx context.Context, sittingID primitive.ObjectID) (int64, error) {
+	count, err := r.resultCollection.CountDocuments(ctx, bson.M{
+		"exam_sitting_id":   sittingID,
+		"moderation.status": models.ModerationPending,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending moderation: %w", err)
+	}
+	return count, nil
+}
+
+// SyncModerationFlags flags unreviewed results of a sitting whose score percentage lies in [low, high]
+// and clears pending flags outside it, or all pending flags when moderation is disabled.
+// It returns the number of results flagged and cleared; reviewed results are never touched.
+func (r *quizSessionRepository) SyncModerationFlags(ctx context.Context, sittingID primitive.ObjectID, enabled bool, low, high float64, flaggedAt time.Time) (int64, int64, error) {
+	clearFilter := bson.M{
+		"exam_sitting_id":   sittingID,
+		"moderation.status": models.ModerationPending,
+	}
+	if enabled {
+		clearFilter["$or"] = bson.A{
+			bson.M{"score_percentage": bson.M{"$lt": low}},
+			bson.M{"score_percentage": bson.M{"$gt": high}},
+		}
+	}
+	cleared, err := r.resultCollection.UpdateMany(ctx, clearFilter, bson.M{
+		"$unset": bson.M{"moderation": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to clear moderation flags: %w", err)
+	}
+	if !enabled {
+		return 0, cleared.ModifiedCount, nil
+	}
+
+	// Pipeline update so the original score is copied from each document
+	flagged, err := r.resultCollection.UpdateMany(ctx,
+		bson.M{
+			"exam_sitting_id":  sittingID,
+			"moderation":       bson.M{"$exists": false},
+			"score_percentage": bson.M{"$gte": low, "$lte": high},
+		},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"moderation": bson.M{
+				"status":              models.ModerationPending,
+				"original_score":      "$final_score",
+				"original_percentage": "$score_percentage",
+				"flagged_at":          flaggedAt,
+				"adjustments":         bson.A{},
+			},
+			"updated_at": time.Now(),
+		}}}},
+	)
+	if err != nil {
+		return 0, cleared.ModifiedCount, fmt.Errorf("failed to flag results for moderation: %w", err)
+	}
+
+	return flagged.ModifiedCount, cleared.ModifiedCount, nil
+}
+
+// UpdateModeratedScore stores a moderated final score together with the result's moderation record
+func (r *quizSessionRepository) UpdateModeratedScore(ctx context.Context, resultID primitive.ObjectID, finalScore, score int, scorePercentage float64, moderation *models.ResultModeration) error {
+	result, err := r.resultCollection.UpdateOne(ctx,
+		bson.M{"_id": resultID},
+		bson.M{"$set": bson.M{
+			"final_score":      finalScore,
+			"score":            score,
+			"score_percentage": scorePercentage,
+			"moderation":       moderation,
+			"updated_at":       time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update moderated score: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrResultNotFound
+	}
+	return nil
+}
+
+// GetSittingResults returns every result recorded for an exam sitting
+func (r *quizSessionRepository) GetSittingResults(ctx context.Context, sittingID primitive.ObjectID) ([]models.DetailedQuizResult, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "score_percentage", Value: 1}})
+	cursor, err := r.resultCollection.Find(ctx, bson.M{"exam_sitting_id": sittingID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sitting results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.DetailedQuizResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode sitting results: %w", err)
+	}
+	return results, nil
+}
+
+// UpdateScaledScores writes the scores and scaling record of each result in one batch;
+// results without scaling get their raw scores back
+func (r *quizSessionRepository) UpdateScaledScores(ctx context.Context, results []models.DetailedQuizResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(results))
+	for _, result := range results {
+		set := bson.M{
+			"final_score":      result.FinalScore,
+			"score":            result.Score,
+			"score_percentage": result.ScorePercentage,
+			"updated_at":       now,
+		}
+		update := bson.M{"$set": set}
+		if result.Scaling != nil {
+			set["scaling"] = result.Scaling
+		} else {
+			update["$unset"] = bson.M{"scaling": ""}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": result.ID}).SetUpdate(update))
+	}
+
+	if _, err := r.resultCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to update scaled scores: %w", err)
+	}
+	return nil
+}
+
+// ListPendingEssays returns essay answers awaiting a grade, oldest submission first
+func (r *quizSessionRepository) ListPendingEssays(ctx context.Context, req *models.ListPendingGradingRequest, sittingID *primitive.ObjectID) ([]models.PendingEssay, int64, error) {
+	match := bson.M{"pending_essays": bson.M{"$gt": 0}}
+	if req.QuizType != "" {
+		match["quiz_type"] = req.QuizType
+	}
+	if sittingID != nil {
+		match["exam_sitting_id"] = *sittingID
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: bson.M{"path": "$question_results", "includeArrayIndex": "question_index"}}},
+		{{Key: "$match", Value: bson.M{"question_results.pending_grade": true}}},
+		{{Key: "$sort", Value: bson.D{{Key: "submitted_at", Value: 1}, {Key: "_id", Value: 1}, {Key: "question_index", Value: 1}}}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"essays": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": int64(req.Limit)},
+				bson.M{"$project": bson.M{
+					"_id":             0,
+					"result_id":       "$_id",
+					"question_index":  1,
+					"question_id":     "$question_results.question_id",
+					"title":           "$question_results.title",
+					"points":          "$question_results.points",
+					"answer":          "$question_results.user_answer",
+					"user_id":         1,
+					"quiz_type":       1,
+					"exam_sitting_id": 1,
+					"submitted_at":    1,
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pending essays: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var page []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Essays []models.PendingEssay `bson:"essays"`
+	}
+	if err := cursor.All(ctx, &page); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode pending essays: %w", err)
+	}
+	if len(page) == 0 || len(page[0].Total) == 0 {
+		return nil, 0, nil
+	}
+	return page[0].Essays, page[0].Total[0].Count, nil
+}
+
+// SetEssayGrade records the grade of an essay answer and returns the updated result. Grading a
+// pending essay counts it off the result's pending essays in the same update, so essays graded
+// at the same time by different admins are each counted once.
+func (r *quizSessionRepository) SetEssayGrade(ctx context.Context, resultID primitive.ObjectID, questionIndex int, grade *models.EssayGrade, isCorrect bool) (*models.DetailedQuizResult, error) {
+	field := fmt.Sprintf("question_results.%d", questionIndex)
+	set := bson.M{
+		field + ".essay_grade":   grade,
+		field + ".points_earned": grade.Points,
+		field + ".is_correct":    isCorrect,
+		"updated_at":             time.Now(),
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	// First grade of a pending essay
+	var result models.DetailedQuizResult
+	err := r.resultCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": resultID, field + ".pending_grade": true},
+		bson.M{
+			"$set":   set,
+			"$unset": bson.M{field + ".pending_grade": ""},
+			"$inc":   bson.M{"pending_essays": -1},
+		},
+		opts,
+	).Decode(&result)
+	if err == nil {
+		return &result, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to grade essay: %w", err)
+	}
+
+	// Regrade of an essay graded before
+	err = r.resultCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": resultID, field + ".essay_grade": bson.M{"$exists": true}},
+		bson.M{"$set": set},
+		opts,
+	).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("essay answer not found")
+		}
+		return nil, fmt.Errorf("failed to grade essay: %w", err)
+	}
+	return &result, nil
+}
+
+// UpdateGradedScores stores the scores of a result recomputed after its essays were graded
+func (r *quizSessionRepository) UpdateGradedScores(ctx context.Context, result *models.DetailedQuizResult) error {
+	set := bson.M{
+		"earned_points":    result.EarnedPoints,
+		"final_score":      result.FinalScore,
+		"score":            result.Score,
+		"score_percentage": result.ScorePercentage,
+		"correct_answers":  result.CorrectAnswers,
+		"easy_correct":     result.EasyCorrect,
+		"medium_correct":   result.MediumCorrect,
+		"hard_correct":     result.HardCorrect,
+		"updated_at":       time.Now(),
+	}
+	update := bson.M{"$set": set}
+	unset := bson.M{}
+	if result.Moderation != nil {
+		set["moderation"] = result.Moderation
+	} else {
+		unset["moderation"] = ""
+	}
+	if result.Scaling != nil {
+		set["scaling"] = result.Scaling
+	} else {
+		unset["scaling"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	updated, err := r.resultCollection.UpdateOne(ctx, bson.M{"_id": result.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update graded scores: %w", err)
+	}
+	if updated.MatchedCount == 0 {
+		return ErrResultNotFound
+	}
+	return nil
+}
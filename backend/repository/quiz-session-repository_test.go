@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// sentFilter returns the filter of the last command the mock deployment received, from a find or
+// from the $match stage of a count
+func sentFilter(mt *mtest.T) bson.Raw {
+	command := mt.GetStartedEvent().Command
+	if filter, ok := command.Lookup("filter").DocumentOK(); ok {
+		return filter
+	}
+	return command.Lookup("pipeline", "0", "$match").Document()
+}
+
+// countedStatuses returns the session statuses a filter matches
+func countedStatuses(t *testing.T, filter bson.Raw) []models.QuizStatus {
+	values, err := filter.Lookup("status", "$in").Array().Values()
+	if err != nil {
+		t.Fatalf("filter %v does not limit the status: %v", filter, err)
+	}
+	statuses := make([]models.QuizStatus, len(values))
+	for i, value := range values {
+		statuses[i] = models.QuizStatus(value.StringValue())
+	}
+	return statuses
+}
+
+func TestCountTemplateAttempts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts only submitted and timed out sessions", func(mt *mtest.T) {
+		userID, templateID := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.quiz_sessions", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}))
+
+		count, err := NewQuizSessionRepository(mt.DB).CountTemplateAttempts(context.Background(), userID, templateID)
+		if err != nil {
+			t.Fatalf("CountTemplateAttempts() error = %v", err)
+		}
+		if count != 2 {
+			t.Errorf("CountTemplateAttempts() = %d, want 2", count)
+		}
+
+		filter := sentFilter(mt)
+		if got := filter.Lookup("template_id").ObjectID(); got != templateID {
+			t.Errorf("filter template_id = %v, want %v", got, templateID)
+		}
+		// Abandoned sessions, discarded or lost to a crash, are never scored
+		want := []models.QuizStatus{models.QuizCompleted, models.QuizTimeout}
+		if got := countedStatuses(t, filter); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("filter statuses = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestGetLastAttemptStart(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	userID, templateID := primitive.NewObjectID(), primitive.NewObjectID()
+	startTime := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	lastStart := func(mt *mtest.T) bson.Raw {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.quiz_sessions", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "start_time", Value: startTime},
+		}))
+		got, err := NewQuizSessionRepository(mt.DB).GetLastAttemptStart(context.Background(), userID, templateID)
+		if err != nil {
+			t.Fatalf("GetLastAttemptStart() error = %v", err)
+		}
+		if got == nil || !got.Equal(startTime) {
+			t.Errorf("GetLastAttemptStart() = %v, want %v", got, startTime)
+		}
+		return sentFilter(mt)
+	}
+
+	mt.Run("keyed by the template the cooldown is set on", func(mt *mtest.T) {
+		filter := lastStart(mt)
+		if got := filter.Lookup("template_id").ObjectID(); got != templateID {
+			t.Errorf("filter template_id = %v, want %v", got, templateID)
+		}
+		if _, err := filter.LookupErr("quiz_type"); err == nil {
+			t.Errorf("filter %v matches other templates of the quiz type", filter)
+		}
+	})
+
+	mt.Run("leaves out exam sittings", func(mt *mtest.T) {
+		filter := lastStart(mt)
+		if value, err := filter.LookupErr("exam_sitting_id"); err != nil || value.Type != bson.TypeNull {
+			t.Errorf("filter %v matches sessions from exam sittings", filter)
+		}
+	})
+
+	mt.Run("leaves out discarded and abandoned sessions", func(mt *mtest.T) {
+		for _, status := range countedStatuses(t, lastStart(mt)) {
+			if status == models.QuizAbandoned || status == models.QuizInProgress {
+				t.Errorf("filter matches %s sessions", status)
+			}
+		}
+	})
+
+	mt.Run("none yet", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "test.quiz_sessions", mtest.FirstBatch))
+		got, err := NewQuizSessionRepository(mt.DB).GetLastAttemptStart(context.Background(), userID, templateID)
+		if err != nil || got != nil {
+			t.Errorf("GetLastAttemptStart() = %v, %v, want nil, nil", got, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type QuizTemplateRepository interface {
+	Create(ctx context.Context, template *models.QuizTemplate) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuizTemplate, error)
+	GetDefault(ctx context.Context, quizType models.QuizType) (*models.QuizTemplate, error)
+	List(ctx context.Context, req *models.ListQuizTemplatesRequest) ([]models.QuizTemplate, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	ClearDefault(ctx context.Context, quizType models.QuizType, exceptID primitive.ObjectID) error
+}
+
+type quizTemplateRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewQuizTemplateRepository(db *mongo.Database) QuizTemplateRepository {
+	return &quizTemplateRepository{
+		db:         db,
+		collection: db.Collection("quiz_templates"),
+	}
+}
+
+func (r *quizTemplateRepository) Create(ctx context.Context, template *models.QuizTemplate) error {
+	template.ID = primitive.NewObjectID()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, template)
+	return err
+}
+
+func (r *quizTemplateRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.QuizTemplate, error) {
+	var template models.QuizTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("quiz template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetDefault returns the active default template for a quiz type, or nil if there is none
+func (r *quizTemplateRepository) GetDefault(ctx context.Context, quizType models.QuizType) (*models.QuizTemplate, error) {
+	var template models.QuizTemplate
+	err := r.collection.FindOne(ctx, bson.M{
+		"quiz_type":  quizType,
+		"is_default": true,
+		"is_active":  true,
+	}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *quizTemplateRepository) List(ctx context.Context, req *models.ListQuizTemplatesRequest) ([]models.QuizTemplate, error) {
+	filter := bson.M{}
+	if req.QuizType != "" {
+		filter["quiz_type"] = req.QuizType
+	}
+	if req.ActiveOnly {
+		filter["is_active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "quiz_type", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var templates []models.QuizTemplate
+	if err = cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *quizTemplateRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
+	updates["updated_at"] = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updates})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return models.NotFoundError("quiz template not found")
+	}
+	return nil
+}
+
+func (r *quizTemplateRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return models.NotFoundError("quiz template not found")
+	}
+	return nil
+}
+
+// ClearDefault unmarks every other default template of a quiz type
+func (r *quizTemplateRepository) ClearDefault(ctx context.Context, quizType models.QuizType, exceptID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"quiz_type": quizType, "is_default": true, "_id": bson.M{"$ne": exceptID}},
+		bson.M{"$set": bson.M{"is_default": false, "updated_at": time.Now()}},
+	)
+	return err
+}
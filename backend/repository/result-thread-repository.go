@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrResultThreadNotFound is returned when no result thread has the ID
+var ErrResultThreadNotFound = models.NotFoundError("result thread not found")
+
+type ResultThreadRepository interface {
+	GetOrCreate(ctx context.Context, thread *models.ResultThread) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ResultThread, error)
+	GetByResultID(ctx context.Context, resultID primitive.ObjectID) (*models.ResultThread, error)
+	List(ctx context.Context, studentID *primitive.ObjectID, req *models.ListResultThreadsRequest) ([]models.ResultThread, int64, error)
+	AddMessage(ctx context.Context, thread *models.ResultThread, message *models.ThreadMessage) error
+	GetMessages(ctx context.Context, threadID primitive.ObjectID) ([]models.ThreadMessage, error)
+	MarkRead(ctx context.Context, threadID primitive.ObjectID, role models.ThreadRole) error
+	DeleteByStudent(ctx context.Context, studentID primitive.ObjectID) error
+}
+
+type resultThreadRepository struct {
+	db                *mongo.Database
+	threadCollection  *mongo.Collection
+	messageCollection *mongo.Collection
+}
+
+func NewResultThreadRepository(db *mongo.Database) ResultThreadRepository {
+	return &resultThreadRepository{
+		db:                db,
+		threadCollection:  db.Collection("result_threads"),
+		messageCollection: db.Collection("result_thread_messages"),
+	}
+}
+
+// GetOrCreate returns the thread for thread.ResultID, creating it if needed, and fills thread with the stored document
+func (r *resultThreadRepository) GetOrCreate(ctx context.Context, thread *models.ResultThread) error {
+	now := time.Now()
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"_id":               primitive.NewObjectID(),
+			"student_id":        thread.StudentID,
+			"result_title":      thread.ResultTitle,
+			"instructor_ids":    []primitive.ObjectID{},
+			"message_count":     0,
+			"student_unread":    0,
+			"instructor_unread": 0,
+			"created_at":        now,
+			"updated_at":        now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	return r.threadCollection.FindOneAndUpdate(ctx, bson.M{"result_id": thread.ResultID}, update, opts).Decode(thread)
+}
+
+func (r *resultThreadRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ResultThread, error) {
+	var thread models.ResultThread
+	err := r.threadCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&thread)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrResultThreadNotFound
+		}
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (r *resultThreadRepository) GetByResultID(ctx context.Context, resultID primitive.ObjectID) (*models.ResultThread, error) {
+	var thread models.ResultThread
+	err := r.threadCollection.FindOne(ctx, bson.M{"result_id": resultID}).Decode(&thread)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrResultThreadNotFound
+		}
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// List returns threads with at least one message, limited to one student when studentID is set
+func (r *resultThreadRepository) List(ctx context.Context, studentID *primitive.ObjectID, req *models.ListResultThreadsRequest) ([]models.ResultThread, int64, error) {
+	filter := bson.M{"message_count": bson.M{"$gt": 0}}
+	if studentID != nil {
+		filter["student_id"] = *studentID
+		if req.UnreadOnly {
+			filter["student_unread"] = bson.M{"$gt": 0}
+		}
+	} else if req.UnreadOnly {
+		filter["instructor_unread"] = bson.M{"$gt": 0}
+	}
+
+	total, err := r.threadCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (req.Page - 1) * req.Limit
+	opts := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(req.Limit)).
+		SetSort(bson.M{"last_message_at": -1})
+
+	cursor, err := r.threadCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var threads []models.ResultThread
+	if err = cursor.All(ctx, &threads); err != nil {
+		return nil, 0, err
+	}
+
+	return threads, total, nil
+}
+
+// AddMessage stores the message and bumps the unread count for the other side of the thread
+func (r *resultThreadRepository) AddMessage(ctx context.Context, thread *models.ResultThread, message *models.ThreadMessage) error {
+	message.ID = primitive.NewObjectID()
+	message.ThreadID = thread.ID
+	message.CreatedAt = time.Now()
+
+	if _, err := r.messageCollection.InsertOne(ctx, message); err != nil {
+		return err
+	}
+
+	unreadField := "instructor_unread"
+	readFields := bson.M{"student_unread": 0, "student_last_read_at": message.CreatedAt}
+	if message.AuthorRole == models.ThreadRoleInstructor {
+		unreadField = "student_unread"
+		readFields = bson.M{"instructor_unread": 0, "instructor_last_read_at": message.CreatedAt}
+	}
+
+	set := bson.M{
+		"last_message_at": message.CreatedAt,
+		"last_message_by": message.AuthorRole,
+		"last_author_id":  message.AuthorID,
+		"updated_at":      message.CreatedAt,
+	}
+	// Posting implies the author has read everything so far
+	for key, value := range readFields {
+		set[key] = value
+	}
+
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"message_count": 1, unreadField: 1},
+	}
+	if message.AuthorRole == models.ThreadRoleInstructor {
+		update["$addToSet"] = bson.M{"instructor_ids": message.AuthorID}
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	return r.threadCollection.FindOneAndUpdate(ctx, bson.M{"_id": thread.ID}, update, opts).Decode(thread)
+}
+
+func (r *resultThreadRepository) GetMessages(ctx context.Context, threadID primitive.ObjectID) ([]models.ThreadMessage, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.messageCollection.Find(ctx, bson.M{"thread_id": threadID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.ThreadMessage
+	if err = cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *resultThreadRepository) MarkRead(ctx context.Context, threadID primitive.ObjectID, role models.ThreadRole) error {
+	set := bson.M{"student_unread": 0, "student_last_read_at": time.Now()}
+	if role == models.ThreadRoleInstructor {
+		set = bson.M{"instructor_unread": 0, "instructor_last_read_at": time.Now()}
+	}
+
+	result, err := r.threadCollection.UpdateOne(ctx, bson.M{"_id": threadID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrResultThreadNotFound
+	}
+
+	return nil
+}
+
+func (r *resultThreadRepository) DeleteByStudent(ctx context.Context, studentID primitive.ObjectID) error {
+	cursor, err := r.threadCollection.Find(ctx, bson.M{"student_id": studentID}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var threadIDs []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var thread models.ResultThread
+		if err := cursor.Decode(&thread); err != nil {
+			return err
+		}
+		threadIDs = append(threadIDs, thread.ID)
+	}
+
+	if len(threadIDs) == 0 {
+		return nil
+	}
+
+	if _, err := r.messageCollection.DeleteMany(ctx, bson.M{"thread_id": bson.M{"$in": threadIDs}}); err != nil {
+		return err
+	}
+
+	_, err = r.threadCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": threadIDs}})
+	return err
+}
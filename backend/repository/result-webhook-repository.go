@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrResultWebhookNotFound is returned when no result webhook has the ID
+var ErrResultWebhookNotFound = models.NotFoundError("result webhook not found")
+
+type ResultWebhookRepository interface {
+	Create(ctx context.Context, webhook *models.ResultWebhook) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error)
+	List(ctx context.Context) ([]models.ResultWebhook, error)
+	ListActive(ctx context.Context) ([]models.ResultWebhook, error)
+	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.ResultWebhook, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	RecordDelivery(ctx context.Context, id primitive.ObjectID, statusCode int, deliveryErr error, deliveredAt time.Time) error
+}
+
+type resultWebhookRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewResultWebhookRepository(db *mongo.Database) ResultWebhookRepository {
+	return &resultWebhookRepository{
+		db:         db,
+		collection: db.Collection("result_webhooks"),
+	}
+}
+
+func (r *resultWebhookRepository) Create(ctx context.Context, webhook *models.ResultWebhook) error {
+	webhook.ID = primitive.NewObjectID()
+	webhook.Active = true
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, webhook)
+	return err
+}
+
+func (r *resultWebhookRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error) {
+	var webhook models.ResultWebhook
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrResultWebhookNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *resultWebhookRepository) List(ctx context.Context) ([]models.ResultWebhook, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *resultWebhookRepository) ListActive(ctx context.Context) ([]models.ResultWebhook, error) {
+	return r.find(ctx, bson.M{"active": true})
+}
+
+func (r *resultWebhookRepository) find(ctx context.Context, filter bson.M) ([]models.ResultWebhook, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var webhooks []models.ResultWebhook
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *resultWebhookRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) (*models.ResultWebhook, error) {
+	updates["updated_at"] = time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var webhook models.ResultWebhook
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": updates}, opts).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrResultWebhookNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *resultWebhookRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrResultWebhookNotFound
+	}
+
+	return nil
+}
+
+// RecordDelivery stores the outcome of a delivery's final attempt; a nil deliveryErr is a success
+func (r *resultWebhookRepository) RecordDelivery(ctx context.Context, id primitive.ObjectID, statusCode int, deliveryErr error, deliveredAt time.Time) error {
+	set := bson.M{"last_delivery_at": deliveredAt, "last_status_code": statusCode}
+	update := bson.M{"$set": set}
+
+	if deliveryErr == nil {
+		set["consecutive_failures"] = 0
+		update["$unset"] = bson.M{"last_error": ""}
+		update["$inc"] = bson.M{"delivered_count": 1}
+	} else {
+		set["last_error"] = deliveryErr.Error()
+		update["$inc"] = bson.M{"failed_count": 1, "consecutive_failures": 1}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Most events returned for one session's timeline
+const telemetryTimelineLimit = 1000
+
+type SessionTelemetryRepository interface {
+	Insert(ctx context.Context, batch *models.SessionTelemetryBatch) error
+	Summarize(ctx context.Context, filter *models.TelemetryFilter) ([]models.TelemetryTypeSummary, error)
+	ListSessionEvents(ctx context.Context, filter *models.TelemetryFilter) ([]models.TelemetrySessionEvent, error)
+}
+
+type sessionTelemetryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionTelemetryRepository(db *mongo.Database) SessionTelemetryRepository {
+	return &sessionTelemetryRepository{
+		collection: db.Collection("session_telemetry"),
+	}
+}
+
+func (r *sessionTelemetryRepository) Insert(ctx context.Context, batch *models.SessionTelemetryBatch) error {
+	batch.ID = primitive.NewObjectID()
+	batch.ReceivedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, batch)
+	return err
+}
+
+// Summarize counts events by type, with their durations, retries and how many sessions reported them
+func (r *sessionTelemetryRepository) Summarize(ctx context.Context, filter *models.TelemetryFilter) ([]models.TelemetryTypeSummary, error) {
+	pipeline := append(telemetryEventsPipeline(filter),
+		bson.M{"$group": bson.M{
+			"_id":             "$events.type",
+			"events":          bson.M{"$sum": 1},
+			"session_ids":     bson.M{"$addToSet": "$session_id"},
+			"avg_duration_ms": bson.M{"$avg": "$events.duration_ms"},
+			"max_duration_ms": bson.M{"$max": "$events.duration_ms"},
+			"max_attempts":    bson.M{"$max": "$events.attempts"},
+		}},
+		bson.M{"$addFields": bson.M{"sessions": bson.M{"$size": "$session_ids"}}},
+		bson.M{"$project": bson.M{"session_ids": 0}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	summary := []models.TelemetryTypeSummary{}
+	if err := cursor.All(ctx, &summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// ListSessionEvents returns a session's events in the order they happened on the client
+func (r *sessionTelemetryRepository) ListSessionEvents(ctx context.Context, filter *models.TelemetryFilter) ([]models.TelemetrySessionEvent, error) {
+	pipeline := append(telemetryEventsPipeline(filter),
+		bson.M{"$replaceWith": bson.M{"$mergeObjects": bson.A{
+			"$events",
+			bson.M{"received_at": "$received_at", "clock_offset_ms": "$clock_offset_ms"},
+		}}},
+		bson.M{"$sort": bson.M{"occurred_at": 1}},
+		bson.M{"$limit": telemetryTimelineLimit},
+	)
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.TelemetrySessionEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// telemetryEventsPipeline matches the batches in the filter and unwinds them to one document per event
+func telemetryEventsPipeline(filter *models.TelemetryFilter) []bson.M {
+	match := bson.M{}
+	if filter.SessionID != nil {
+		match["session_id"] = *filter.SessionID
+	}
+	if filter.QuizType != "" {
+		match["quiz_type"] = filter.QuizType
+	}
+	if filter.From != nil || filter.To != nil {
+		received := bson.M{}
+		if filter.From != nil {
+			received["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			received["$lt"] = *filter.To
+		}
+		match["received_at"] = received
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$unwind": "$events"},
+	}
+	if filter.Type != "" {
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"events.type": filter.Type}})
+	}
+	return pipeline
+}
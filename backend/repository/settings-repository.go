@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// runtimeSettingsID is the _id of the one document holding the runtime settings
+const runtimeSettingsID = "runtime"
+
+type SettingsRepository interface {
+	Get(ctx context.Context) (*models.RuntimeSettings, error)
+	Save(ctx context.Context, settings *models.RuntimeSettings) error
+}
+
+type settingsRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewSettingsRepository(db *mongo.Database) SettingsRepository {
+	return &settingsRepository{
+		db:         db,
+		collection: db.Collection("settings"),
+	}
+}
+
+// Get returns the saved runtime settings, or nil if no admin has saved any yet
+func (r *settingsRepository) Get(ctx context.Context) (*models.RuntimeSettings, error) {
+	var settings models.RuntimeSettings
+	err := r.collection.FindOne(ctx, bson.M{"_id": runtimeSettingsID}).Decode(&settings)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *settingsRepository) Save(ctx context.Context, settings *models.RuntimeSettings) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": runtimeSettingsID}, settings, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrShortCodeInUse is returned when another short link has the code
+	ErrShortCodeInUse = models.ConflictError("short code already in use")
+	// ErrShortLinkNotFound is returned when no short link has the ID
+	ErrShortLinkNotFound = models.NotFoundError("short link not found")
+)
+
+type ShortLinkRepository interface {
+	Create(ctx context.Context, link *models.ShortLink) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.ShortLink, error)
+	GetByCode(ctx context.Context, code string) (*models.ShortLink, error)
+	RecordClick(ctx context.Context, code string) (*models.ShortLink, error)
+	List(ctx context.Context, req *models.ListShortLinksRequest) ([]models.ShortLink, int64, error)
+	Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.ShortLink, error)
+}
+
+type shortLinkRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewShortLinkRepository(db *mongo.Database) ShortLinkRepository {
+	return &shortLinkRepository{
+		db:         db,
+		collection: db.Collection("short_links"),
+	}
+}
+
+// Create inserts the link; it fails with "short code already in use" if the code is taken
+func (r *shortLinkRepository) Create(ctx context.Context, link *models.ShortLink) error {
+	link.ID = primitive.NewObjectID()
+	link.Status = models.ShortLinkActive
+	link.CreatedAt = time.Now()
+	link.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, link)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrShortCodeInUse
+	}
+	return err
+}
+
+func (r *shortLinkRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.ShortLink, error) {
+	var link models.ShortLink
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrShortLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shortLinkRepository) GetByCode(ctx context.Context, code string) (*models.ShortLink, error) {
+	var link models.ShortLink
+	err := r.collection.FindOne(ctx, bson.M{"code": code}).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrShortLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// RecordClick counts a click on an active, unexpired link and returns it. It returns
+// "short link not found" when the code does not resolve to a usable link.
+func (r *shortLinkRepository) RecordClick(ctx context.Context, code string) (*models.ShortLink, error) {
+	now := time.Now()
+	filter := bson.M{
+		"code":   code,
+		"status": models.ShortLinkActive,
+		"$or": []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": now}},
+		},
+	}
+	update := bson.M{
+		"$inc": bson.M{"click_count": 1},
+		"$set": bson.M{"last_clicked_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var link models.ShortLink
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrShortLinkNotFound
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shortLinkRepository) List(ctx context.Context, req *models.ListShortLinksRequest) ([]models.ShortLink, int64, error) {
+	filter := bson.M{}
+
+	// Expired is an active link past its expiry; active excludes those
+	now := time.Now()
+	switch req.Status {
+	case models.ShortLinkExpired:
+		filter["status"] = models.ShortLinkActive
+		filter["expires_at"] = bson.M{"$lte": now}
+	case models.ShortLinkActive:
+		filter["status"] = models.ShortLinkActive
+		filter["$or"] = []bson.M{
+			{"expires_at": bson.M{"$exists": false}},
+			{"expires_at": bson.M{"$gt": now}},
+		}
+	case "":
+	default:
+		filter["status"] = req.Status
+	}
+
+	if req.Search != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(req.Search), "$options": "i"}
+		filter["$and"] = []bson.M{{"$or": []bson.M{
+			{"code": pattern},
+			{"label": pattern},
+			{"path": pattern},
+		}}}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := int64((req.Page - 1) * req.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(int64(req.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var links []models.ShortLink
+	if err = cursor.All(ctx, &links); err != nil {
+		return nil, 0, err
+	}
+
+	for i := range links {
+		links[i].Status = shortLinkStatus(&links[i], now)
+	}
+
+	return links, total, nil
+}
+
+// Revoke stops an active link from redirecting and returns it
+func (r *shortLinkRepository) Revoke(ctx context.Context, id, adminID primitive.ObjectID) (*models.ShortLink, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var link models.ShortLink
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.ShortLinkActive},
+		bson.M{"$set": bson.M{
+			"status":     models.ShortLinkRevoked,
+			"revoked_at": now,
+			"revoked_by": adminID,
+			"updated_at": now,
+		}},
+		opts,
+	).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, id); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.ConflictError("short link is already revoked")
+		}
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+func shortLinkStatus(link *models.ShortLink, now time.Time) models.ShortLinkStatus {
+	if link.Status == models.ShortLinkActive && link.ExpiresAt != nil && !now.Before(*link.ExpiresAt) {
+		return models.ShortLinkExpired
+	}
+	return link.Status
+}
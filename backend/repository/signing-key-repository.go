@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SigningKeyRepository interface {
+	Create(ctx context.Context, key *models.JWTSigningKey) error
+	GetTrusted(ctx context.Context) ([]models.JWTSigningKey, error)
+	DemoteCurrent(ctx context.Context, rotatedAt, verifyUntil time.Time) (*models.JWTSigningKey, error)
+	RetireExpired(ctx context.Context, now time.Time) ([]string, error)
+}
+
+type signingKeyRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewSigningKeyRepository(db *mongo.Database) SigningKeyRepository {
+	return &signingKeyRepository{
+		db:         db,
+		collection: db.Collection("jwt_signing_keys"),
+	}
+}
+
+// Create inserts a new current key. A unique index allows only one current key, so a
+// concurrent bootstrap or rotation fails with a duplicate key error instead of forking.
+func (r *signingKeyRepository) Create(ctx context.Context, key *models.JWTSigningKey) error {
+	key.ID = primitive.NewObjectID()
+	key.Status = models.SigningKeyCurrent
+	key.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
+
+// GetTrusted returns the current key and previous keys still inside their verification window
+func (r *signingKeyRepository) GetTrusted(ctx context.Context) ([]models.JWTSigningKey, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": models.SigningKeyCurrent},
+			bson.M{"status": models.SigningKeyPrevious, "verify_until": bson.M{"$gt": time.Now()}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []models.JWTSigningKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DemoteCurrent stops the current key from signing while keeping it trusted until verifyUntil.
+// It returns the demoted key, or nil if there was none.
+func (r *signingKeyRepository) DemoteCurrent(ctx context.Context, rotatedAt, verifyUntil time.Time) (*models.JWTSigningKey, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var key models.JWTSigningKey
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"status": models.SigningKeyCurrent},
+		bson.M{"$set": bson.M{
+			"status":       models.SigningKeyPrevious,
+			"rotated_at":   rotatedAt,
+			"verify_until": verifyUntil,
+		}},
+		opts,
+	).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RetireExpired marks previous keys past their verification window as retired and returns their kids
+func (r *signingKeyRepository) RetireExpired(ctx context.Context, now time.Time) ([]string, error) {
+	filter := bson.M{"status": models.SigningKeyPrevious, "verify_until": bson.M{"$lte": now}}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"kid": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var expired []models.JWTSigningKey
+	if err := cursor.All(ctx, &expired); err != nil {
+		return nil, err
+	}
+
+	kids := make([]string, 0, len(expired))
+	if len(expired) == 0 {
+		return kids, nil
+	}
+
+	ids := make(bson.A, 0, len(expired))
+	for _, key := range expired {
+		ids = append(ids, key.ID)
+		kids = append(kids, key.KID)
+	}
+
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{"status": models.SigningKeyRetired, "retired_at": now}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return kids, nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrJoinCodeInUse is returned when a generated join code clashes with another team or class quiz; callers draw another
+var ErrJoinCodeInUse = models.ConflictError("join code already in use")
+
+type TeamRepository interface {
+	Create(ctx context.Context, team *models.Team) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*models.Team, error)
+	GetByJoinCode(ctx context.Context, code string) (*models.Team, error)
+	GetByMember(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+
+	// Membership
+	AddMember(ctx context.Context, teamID primitive.ObjectID, member models.TeamMemberInfo) (*models.Team, error)
+	RemoveMember(ctx context.Context, teamID, userID primitive.ObjectID) (*models.Team, error)
+	SetCaptain(ctx context.Context, teamID, userID primitive.ObjectID) (*models.Team, error)
+
+	// Shared session
+	ClaimActiveSession(ctx context.Context, teamID primitive.ObjectID, current *primitive.ObjectID, sessionID primitive.ObjectID) (bool, error)
+
+	// Leaderboard
+	GetLeaderboard(ctx context.Context, from, to *time.Time, limit int) ([]models.TeamLeaderboardEntry, error)
+}
+
+type teamRepository struct {
+	db               *mongo.Database
+	collection       *mongo.Collection
+	resultCollection *mongo.Collection
+}
+
+func NewTeamRepository(db *mongo.Database) TeamRepository {
+	return &teamRepository{
+		db:               db,
+		collection:       db.Collection("teams"),
+		resultCollection: db.Collection("detailed_quiz_results"),
+	}
+}
+
+// Create inserts the team; it fails with "join code already in use" if the code is taken
+func (r *teamRepository) Create(ctx context.Context, team *models.Team) error {
+	team.ID = primitive.NewObjectID()
+	team.CreatedAt = time.Now()
+	team.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, team)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrJoinCodeInUse
+	}
+	return err
+}
+
+func (r *teamRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*models.Team, error) {
+	var team models.Team
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("team not found")
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *teamRepository) GetByJoinCode(ctx context.Context, code string) (*models.Team, error) {
+	var team models.Team
+	err := r.collection.FindOne(ctx, bson.M{"join_code": code}).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, models.NotFoundError("team not found")
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *teamRepository) GetByMember(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"members.user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var teams []models.Team
+	if err = cursor.All(ctx, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (r *teamRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// AddMember adds a member unless they already belong to the team or it is full, and returns the team
+func (r *teamRepository) AddMember(ctx context.Context, teamID primitive.ObjectID, member models.TeamMemberInfo) (*models.Team, error) {
+	filter := bson.M{
+		"_id":             teamID,
+		"members.user_id": bson.M{"$ne": member.UserID},
+		// The team is full once the member at the last allowed position exists
+		fmt.Sprintf("members.%d", models.TeamMaxMembers-1): bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var team models.Team
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			existing, getErr := r.GetByID(ctx, teamID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			if existing.Member(member.UserID) != nil {
+				return nil, models.ConflictError("already a member of this team")
+			}
+			return nil, models.ConflictError("team is full")
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+// RemoveMember removes a member and returns the team as it is afterwards
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, userID primitive.ObjectID) (*models.Team, error) {
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"user_id": userID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var team models.Team
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": teamID, "members.user_id": userID}, update, opts).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, getErr := r.GetByID(ctx, teamID); getErr != nil {
+				return nil, getErr
+			}
+			return nil, models.NotFoundError("not a member of this team")
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+// SetCaptain makes a member the captain; the previous captain stays on as a member
+func (r *teamRepository) SetCaptain(ctx context.Context, teamID, userID primitive.ObjectID) (*models.Team, error) {
+	team, err := r.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.Member(userID) == nil {
+		return nil, models.NotFoundError("not a member of this team")
+	}
+
+	for i := range team.Members {
+		team.Members[i].Role = models.TeamMember
+		if team.Members[i].UserID == userID {
+			team.Members[i].Role = models.TeamCaptain
+		}
+	}
+	team.UpdatedAt = time.Now()
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": teamID},
+		bson.M{"$set": bson.M{"members": team.Members, "updated_at": team.UpdatedAt}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// ClaimActiveSession points the team at a new shared session if its active session is still the
+// one the caller saw. It reports false when another member started a session first.
+func (r *teamRepository) ClaimActiveSession(ctx context.Context, teamID primitive.ObjectID, current *primitive.ObjectID, sessionID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": teamID}
+	if current == nil {
+		filter["active_session_id"] = bson.M{"$exists": false}
+	} else {
+		filter["active_session_id"] = *current
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"active_session_id": sessionID, "updated_at": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// GetLeaderboard ranks teams by their best team quiz score, then their average
+func (r *teamRepository) GetLeaderboard(ctx context.Context, from, to *time.Time, limit int) ([]models.TeamLeaderboardEntry, error) {
+	match := bson.M{
+		"quiz_type": models.TeamQuiz,
+		"team_id":   bson.M{"$exists": true},
+	}
+	if from != nil || to != nil {
+		submitted := bson.M{}
+		if from != nil {
+			submitted["$gte"] = *from
+		}
+		if to != nil {
+			submitted["$lt"] = *to
+		}
+		match["submitted_at"] = submitted
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "submitted_at", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            "$team_id",
+			"team_name":      bson.M{"$last": "$team_name"},
+			"best_score":     bson.M{"$max": "$score_percentage"},
+			"average_score":  bson.M{"$avg": "$score_percentage"},
+			"attempts":       bson.M{"$sum": 1},
+			"last_submitted": bson.M{"$last": "$submitted_at"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "best_score", Value: -1}, {Key: "average_score", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.resultCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.TeamLeaderboardEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
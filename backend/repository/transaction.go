@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs writes that span collections as one MongoDB transaction
+type Transactor interface {
+	// WithTransaction runs fn in a transaction, committing when it returns nil and aborting otherwise.
+	// Repository calls made with the ctx fn is given take part in the transaction. fn runs again when
+	// the transaction hits a transient error, so it should only write through that ctx.
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *mongo.Database
+
+	checkSupport sync.Once
+	supported    bool
+}
+
+func NewTransactor(db *mongo.Database) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Standalone servers, as in local development, have no transactions; the writes run one by one
+	if !t.transactionsSupported(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := t.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// transactionsSupported asks the server once whether it is a replica set member or a mongos router,
+// the deployments transactions run on
+func (t *transactor) transactionsSupported(ctx context.Context) bool {
+	t.checkSupport.Do(func() {
+		var hello struct {
+			SetName string `bson:"setName"`
+			Msg     string `bson:"msg"`
+		}
+		if err := t.db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+			// Assume the usual replica set; a server without transactions fails the first write instead
+			slog.WarnContext(ctx, "Failed to check MongoDB transaction support", "error", err)
+			t.supported = true
+			return
+		}
+
+		t.supported = hello.SetName != "" || hello.Msg == "isdbgrid"
+		if !t.supported {
+			slog.WarnContext(ctx, "MongoDB is a standalone server, so multi-collection writes run without transactions")
+		}
+	})
+	return t.supported
+}
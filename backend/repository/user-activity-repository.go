@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"backend/models"
@@ -17,18 +18,31 @@ import (
 type UserActivityRepository interface {
 	// Quiz Results
 	CreateQuizResult(ctx context.Context, result *models.QuizResult) (*models.QuizResult, error)
-	GetUserQuizResults(ctx context.Context, userID primitive.ObjectID, filter models.QuizResultsFilter) ([]models.QuizResult, int64, error)
+	GetUserQuizResults(ctx context.Context, userID primitive.ObjectID, filter models.QuizResultsFilter, after *models.PageCursor) ([]models.QuizResult, int64, error)
 	GetQuizResultByID(ctx context.Context, id primitive.ObjectID) (*models.QuizResult, error)
 
 	// User Statistics
 	GetUserStats(ctx context.Context, userID primitive.ObjectID) (*models.UserStats, error)
 	UpsertUserStats(ctx context.Context, stats *models.UserStats) error
 	UpdateUserStats(ctx context.Context, userID primitive.ObjectID, result *models.QuizResult) error
+	AwardContributionXP(ctx context.Context, userID primitive.ObjectID, xp int) error
+	RefreshTimeBasedStats(ctx context.Context, now time.Time) (int64, error)
 
 	// Achievements
 	GetUserAchievements(ctx context.Context, userID primitive.ObjectID) ([]models.Achievement, error)
 	CreateAchievement(ctx context.Context, achievement *models.Achievement) error
 	CheckAndCreateAchievements(ctx context.Context, userID primitive.ObjectID, result *models.QuizResult) ([]models.Achievement, error)
+
+	// Account deletion
+	AnonymizeUserResults(ctx context.Context, userID, anonymousID primitive.ObjectID) (int64, error)
+	DeleteUserData(ctx context.Context, userID primitive.ObjectID) error
+
+	// Guest claims
+	TransferQuizResults(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error)
+
+	// Moderation
+	UpdateSittingResultScore(ctx context.Context, userID, sittingID primitive.ObjectID, startedAt time.Time, score int) error
+	UpdateGradedResultScore(ctx context.Context, userID primitive.ObjectID, startedAt time.Time, score, correctAnswers int) error
 }
 
 type userActivityRepository struct {
@@ -66,6 +80,18 @@ func (r *userActivityRepository) CreateQuizResult(ctx context.Context, result *m
 		result.Title = fmt.Sprintf("Mock Test #%d", userQuizCount+1)
 	case models.TimeQuiz:
 		result.Title = fmt.Sprintf("Time Quiz #%d", userQuizCount+1)
+	case models.Practice:
+		result.Title = fmt.Sprintf("Practice #%d", userQuizCount+1)
+	case models.QuickQuiz:
+		result.Title = fmt.Sprintf("Quick Quiz #%d", userQuizCount+1)
+	case models.TeamQuiz:
+		result.Title = fmt.Sprintf("Team Quiz #%d", userQuizCount+1)
+	case models.AdaptivePractice:
+		result.Title = fmt.Sprintf("Adaptive Practice #%d", userQuizCount+1)
+	case models.CustomQuiz:
+		result.Title = fmt.Sprintf("Custom Quiz #%d", userQuizCount+1)
+	case models.EmbedQuiz:
+		result.Title = fmt.Sprintf("Embedded Quiz #%d", userQuizCount+1)
 	default:
 		result.Title = fmt.Sprintf("Quiz #%d", userQuizCount+1)
 	}
@@ -78,7 +104,10 @@ func (r *userActivityRepository) CreateQuizResult(ctx context.Context, result *m
 	return result, nil
 }
 
-func (r *userActivityRepository) GetUserQuizResults(ctx context.Context, userID primitive.ObjectID, filter models.QuizResultsFilter) ([]models.QuizResult, int64, error) {
+// GetUserQuizResults pages through a user's quiz results, latest first. Given a cursor, it continues
+// after it without counting the total and returns one result past the limit, so callers can tell
+// whether more follow.
+func (r *userActivityRepository) GetUserQuizResults(ctx context.Context, userID primitive.ObjectID, filter models.QuizResultsFilter, after *models.PageCursor) ([]models.QuizResult, int64, error) {
 	// Build filter
 	mongoFilter := bson.M{"user_id": userID}
 
@@ -103,22 +132,31 @@ func (r *userActivityRepository) GetUserQuizResults(ctx context.Context, userID
 		}
 	}
 
-	// Count total documents
-	totalCount, err := r.resultsCol.CountDocuments(ctx, mongoFilter)
-	if err != nil {
-		return nil, 0, err
-	}
-
 	// Build options
 	opts := options.Find()
-	opts.SetSort(bson.D{{Key: "completed_at", Value: -1}}) // Sort by completion date descending
+	opts.SetSort(newestFirst("completed_at")) // Sort by completion date descending
 
-	if filter.Limit > 0 {
-		opts.SetLimit(int64(filter.Limit))
-	}
-	if filter.Page > 1 && filter.Limit > 0 {
-		skip := int64((filter.Page - 1) * filter.Limit)
-		opts.SetSkip(skip)
+	var totalCount int64
+	if after != nil {
+		mongoFilter = afterCursor(mongoFilter, "completed_at", after)
+		if filter.Limit > 0 {
+			opts.SetLimit(int64(filter.Limit) + 1)
+		}
+	} else {
+		// Count total documents
+		var err error
+		totalCount, err = r.resultsCol.CountDocuments(ctx, mongoFilter)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if filter.Limit > 0 {
+			opts.SetLimit(int64(filter.Limit))
+		}
+		if filter.Page > 1 && filter.Limit > 0 {
+			skip := int64((filter.Page - 1) * filter.Limit)
+			opts.SetSkip(skip)
+		}
 	}
 
 	cursor, err := r.resultsCol.Find(ctx, mongoFilter, opts)
@@ -187,6 +225,23 @@ func (r *userActivityRepository) UpsertUserStats(ctx context.Context, stats *mod
 	return err
 }
 
+// AwardContributionXP credits an accepted question contribution to the user's stats
+func (r *userActivityRepository) AwardContributionXP(ctx context.Context, userID primitive.ObjectID, xp int) error {
+	// Make sure the stats document exists with its defaults before incrementing
+	if _, err := r.GetUserStats(ctx, userID); err != nil {
+		return err
+	}
+
+	_, err := r.statsCol.UpdateOne(ctx,
+		bson.M{"user_id": userID},
+		bson.M{
+			"$inc": bson.M{"xp": xp, "accepted_contributions": 1},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
 func (r *userActivityRepository) UpdateUserStats(ctx context.Context, userID primitive.ObjectID, result *models.QuizResult) error {
 	// Get current stats
 	stats, err := r.GetUserStats(ctx, userID)
@@ -194,49 +249,69 @@ func (r *userActivityRepository) UpdateUserStats(ctx context.Context, userID pri
 		return err
 	}
 
+	// A template counting one attempt, or the average of them, already contributes to the stats
+	// once a student has attempted it; a repeat attempt replaces that standing instead of adding to it
+	previous, err := r.earlierTemplateAttempts(ctx, userID, result)
+	if err != nil {
+		return err
+	}
+	repeat := len(previous) > 0
+	var before, after attemptStanding
+	if repeat {
+		before = templateStanding(previous, result.AttemptScoring)
+		after = templateStanding(append(previous, *result), result.AttemptScoring)
+	}
+
 	// Update stats with new result
-	stats.TotalQuizzesCompleted++
 	stats.TotalTimeSpent += result.TimeSpent
-	stats.TotalQuestions += result.TotalQuestions
-	stats.TotalCorrectAnswers += result.CorrectAnswers
+	if repeat {
+		stats.TotalQuestions += after.totalQuestions - before.totalQuestions
+		stats.TotalCorrectAnswers += after.correctAnswers - before.correctAnswers
+	} else {
+		stats.TotalQuizzesCompleted++
+		stats.TotalQuestions += result.TotalQuestions
+		stats.TotalCorrectAnswers += result.CorrectAnswers
+	}
 
 	// Recalculate average score
 	stats.AverageScore = float64(stats.TotalCorrectAnswers) / float64(stats.TotalQuestions) * 100
 
-	// Update question type accuracies
-	totalSingleChoice := stats.SingleChoiceAccuracy
-	totalMultipleChoice := stats.MultipleChoiceAccuracy
-	totalEssay := stats.EssayAccuracy
+	// Update question type accuracies, unless a repeat attempt left the template's standing as it was
+	if !repeat || after != before {
+		totalSingleChoice := stats.SingleChoiceAccuracy
+		totalMultipleChoice := stats.MultipleChoiceAccuracy
+		totalEssay := stats.EssayAccuracy
 
-	if result.SingleChoiceTotal > 0 {
-		newAccuracy := float64(result.SingleChoiceCorrect) / float64(result.SingleChoiceTotal) * 100
-		stats.SingleChoiceAccuracy = (totalSingleChoice + newAccuracy) / 2
-	}
-	if result.MultipleChoiceTotal > 0 {
-		newAccuracy := float64(result.MultipleChoiceCorrect) / float64(result.MultipleChoiceTotal) * 100
-		stats.MultipleChoiceAccuracy = (totalMultipleChoice + newAccuracy) / 2
-	}
-	if result.EssayTotal > 0 {
-		newAccuracy := float64(result.EssayCorrect) / float64(result.EssayTotal) * 100
-		stats.EssayAccuracy = (totalEssay + newAccuracy) / 2
+		if result.SingleChoiceTotal > 0 {
+			newAccuracy := float64(result.SingleChoiceCorrect) / float64(result.SingleChoiceTotal) * 100
+			stats.SingleChoiceAccuracy = (totalSingleChoice + newAccuracy) / 2
+		}
+		if result.MultipleChoiceTotal > 0 {
+			newAccuracy := float64(result.MultipleChoiceCorrect) / float64(result.MultipleChoiceTotal) * 100
+			stats.MultipleChoiceAccuracy = (totalMultipleChoice + newAccuracy) / 2
+		}
+		if result.EssayTotal > 0 {
+			newAccuracy := float64(result.EssayCorrect) / float64(result.EssayTotal) * 100
+			stats.EssayAccuracy = (totalEssay + newAccuracy) / 2
+		}
 	}
 
-	// Update quiz type averages and counts
+	// Update quiz type averages and counts; a repeat attempt moves the template's score within the average
+	addScore := func(count *int, average *float64) {
+		if repeat {
+			if *count > 0 {
+				*average += float64(after.score-before.score) / float64(*count)
+			}
+			return
+		}
+		*count++
+		*average = (*average*float64(*count-1) + float64(result.Score)) / float64(*count)
+	}
 	switch result.QuizType {
 	case models.MockTest:
-		stats.MockTestCount++
-		if stats.MockTestCount == 1 {
-			stats.MockTestAverage = float64(result.Score)
-		} else {
-			stats.MockTestAverage = ((stats.MockTestAverage * float64(stats.MockTestCount-1)) + float64(result.Score)) / float64(stats.MockTestCount)
-		}
+		addScore(&stats.MockTestCount, &stats.MockTestAverage)
 	case models.TimeQuiz:
-		stats.TimeQuizCount++
-		if stats.TimeQuizCount == 1 {
-			stats.TimeQuizAverage = float64(result.Score)
-		} else {
-			stats.TimeQuizAverage = ((stats.TimeQuizAverage * float64(stats.TimeQuizCount-1)) + float64(result.Score)) / float64(stats.TimeQuizCount)
-		}
+		addScore(&stats.TimeQuizCount, &stats.TimeQuizAverage)
 		// Update timeout count
 		if result.IsTimedOut {
 			stats.TimeoutCount++
@@ -261,7 +336,7 @@ func (r *userActivityRepository) UpdateUserStats(ctx context.Context, userID pri
 	// Update streak
 	now := time.Now()
 	lastQuizDate := stats.LastQuizDate
-	if lastQuizDate.IsZero() || now.Sub(lastQuizDate).Hours() <= 48 { // Within 2 days
+	if lastQuizDate.IsZero() || now.Sub(lastQuizDate) <= streakGap {
 		stats.CurrentStreak++
 		if stats.CurrentStreak > stats.LongestStreak {
 			stats.LongestStreak = stats.CurrentStreak
@@ -272,18 +347,141 @@ func (r *userActivityRepository) UpdateUserStats(ctx context.Context, userID pri
 	stats.LastQuizDate = now
 
 	// Update weekly progress
-	weekStart := now.AddDate(0, 0, -int(now.Weekday()))
-	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
-
 	weeklyCount, _ := r.resultsCol.CountDocuments(ctx, bson.M{
 		"user_id":      userID,
-		"completed_at": bson.M{"$gte": weekStart},
+		"completed_at": bson.M{"$gte": startOfWeek(now)},
 	})
 	stats.WeeklyProgress = int(weeklyCount)
 
 	return r.UpsertUserStats(ctx, stats)
 }
 
+// RefreshTimeBasedStats brings the stats that only change when a quiz is completed up to date with the
+// clock: streaks without a quiz inside the streak gap are broken, and weekly progress counts this week's
+// results only. It returns how many users' stats changed.
+func (r *userActivityRepository) RefreshTimeBasedStats(ctx context.Context, now time.Time) (int64, error) {
+	broken, err := r.statsCol.UpdateMany(ctx,
+		bson.M{"current_streak": bson.M{"$gt": 0}, "last_quiz_date": bson.M{"$lt": now.Add(-streakGap)}},
+		bson.M{"$set": bson.M{"current_streak": 0, "updated_at": now}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset broken streaks: %w", err)
+	}
+	changed := broken.ModifiedCount
+
+	cursor, err := r.resultsCol.Aggregate(ctx, []bson.M{
+		{"$match": bson.M{"completed_at": bson.M{"$gte": startOfWeek(now)}}},
+		{"$group": bson.M{"_id": "$user_id", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return changed, fmt.Errorf("failed to count weekly results: %w", err)
+	}
+	var weekly []struct {
+		UserID primitive.ObjectID `bson:"_id"`
+		Count  int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &weekly); err != nil {
+		return changed, fmt.Errorf("failed to decode weekly results: %w", err)
+	}
+
+	active := make([]primitive.ObjectID, 0, len(weekly))
+	updates := make([]mongo.WriteModel, 0, len(weekly))
+	for _, user := range weekly {
+		active = append(active, user.UserID)
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": user.UserID, "weekly_progress": bson.M{"$ne": user.Count}}).
+			SetUpdate(bson.M{"$set": bson.M{"weekly_progress": user.Count, "updated_at": now}}))
+	}
+	if len(updates) > 0 {
+		result, err := r.statsCol.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return changed, fmt.Errorf("failed to update weekly progress: %w", err)
+		}
+		changed += result.ModifiedCount
+	}
+
+	// Users without a result this week start it at zero
+	idle, err := r.statsCol.UpdateMany(ctx,
+		bson.M{"user_id": bson.M{"$nin": active}, "weekly_progress": bson.M{"$ne": 0}},
+		bson.M{"$set": bson.M{"weekly_progress": 0, "updated_at": now}},
+	)
+	if err != nil {
+		return changed, fmt.Errorf("failed to reset weekly progress: %w", err)
+	}
+	return changed + idle.ModifiedCount, nil
+}
+
+// streakGap is the longest time between quizzes that keeps a streak going
+const streakGap = 48 * time.Hour
+
+// startOfWeek returns midnight on the Sunday starting the week of t
+func startOfWeek(t time.Time) time.Time {
+	weekStart := t.AddDate(0, 0, -int(t.Weekday()))
+	return time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+}
+
+// attemptStanding is what a student's attempts at a template contribute to their performance stats
+type attemptStanding struct {
+	score          int
+	totalQuestions int
+	correctAnswers int
+}
+
+// earlierTemplateAttempts returns the user's earlier results, oldest first, at the result's template
+// under the same attempt policy. Results counting every attempt have none.
+func (r *userActivityRepository) earlierTemplateAttempts(ctx context.Context, userID primitive.ObjectID, result *models.QuizResult) ([]models.QuizResult, error) {
+	if result.TemplateID == nil || result.AttemptScoring == "" || result.AttemptScoring == models.AttemptScoringAll {
+		return nil, nil
+	}
+
+	cursor, err := r.resultsCol.Find(ctx, bson.M{
+		"user_id":         userID,
+		"template_id":     result.TemplateID,
+		"attempt_scoring": result.AttemptScoring,
+		"_id":             bson.M{"$ne": result.ID},
+	}, options.Find().SetSort(bson.D{{Key: "completed_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get earlier attempts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.QuizResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode earlier attempts: %w", err)
+	}
+	return results, nil
+}
+
+// templateStanding reduces a template's attempts, oldest first, to the standing its policy counts
+func templateStanding(attempts []models.QuizResult, policy models.AttemptScoring) attemptStanding {
+	switch policy {
+	case models.AttemptScoringBest:
+		best := attempts[0]
+		for _, attempt := range attempts[1:] {
+			if attempt.Score >= best.Score {
+				best = attempt
+			}
+		}
+		return attemptStanding{score: best.Score, totalQuestions: best.TotalQuestions, correctAnswers: best.CorrectAnswers}
+	case models.AttemptScoringAverage:
+		var sum attemptStanding
+		for _, attempt := range attempts {
+			sum.score += attempt.Score
+			sum.totalQuestions += attempt.TotalQuestions
+			sum.correctAnswers += attempt.CorrectAnswers
+		}
+		n := float64(len(attempts))
+		return attemptStanding{
+			score:          int(math.Round(float64(sum.score) / n)),
+			totalQuestions: int(math.Round(float64(sum.totalQuestions) / n)),
+			correctAnswers: int(math.Round(float64(sum.correctAnswers) / n)),
+		}
+	default:
+		last := attempts[len(attempts)-1]
+		return attemptStanding{score: last.Score, totalQuestions: last.TotalQuestions, correctAnswers: last.CorrectAnswers}
+	}
+}
+
 // Achievements
 func (r *userActivityRepository) GetUserAchievements(ctx context.Context, userID primitive.ObjectID) ([]models.Achievement, error) {
 	cursor, err := r.achievementsCol.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "earned_at", Value: -1}}))
@@ -419,3 +617,70 @@ func (r *userActivityRepository) CheckAndCreateAchievements(ctx context.Context,
 
 	return newAchievements, nil
 }
+
+// Account deletion
+
+// AnonymizeUserResults reassigns the user's quiz results to a placeholder ID so aggregate statistics stay intact
+func (r *userActivityRepository) AnonymizeUserResults(ctx context.Context, userID, anonymousID primitive.ObjectID) (int64, error) {
+	result, err := r.resultsCol.UpdateMany(ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{
+			"user_id":    anonymousID,
+			"anonymized": true,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *userActivityRepository) DeleteUserData(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.statsCol.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := r.achievementsCol.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+// TransferQuizResults moves quiz results recorded under one ID (e.g. a guest) to another user
+func (r *userActivityRepository) TransferQuizResults(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	result, err := r.resultsCol.UpdateMany(ctx,
+		bson.M{"user_id": fromUserID},
+		bson.M{"$set": bson.M{
+			"user_id":    toUserID,
+			"updated_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// UpdateSittingResultScore applies a moderated score to the summary result of an exam sitting attempt
+func (r *userActivityRepository) UpdateSittingResultScore(ctx context.Context, userID, sittingID primitive.ObjectID, startedAt time.Time, score int) error {
+	_, err := r.resultsCol.UpdateOne(ctx,
+		bson.M{"user_id": userID, "exam_sitting_id": sittingID, "started_at": startedAt},
+		bson.M{"$set": bson.M{
+			"score":      score,
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
+
+// UpdateGradedResultScore applies the score of an attempt recomputed after its essays were graded
+// to the attempt's summary result
+func (r *userActivityRepository) UpdateGradedResultScore(ctx context.Context, userID primitive.ObjectID, startedAt time.Time, score, correctAnswers int) error {
+	_, err := r.resultsCol.UpdateOne(ctx,
+		bson.M{"user_id": userID, "started_at": startedAt},
+		bson.M{"$set": bson.M{
+			"score":           score,
+			"correct_answers": correctAnswers,
+			"updated_at":      time.Now(),
+		}},
+	)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"backend/models"
@@ -14,6 +15,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrAdminNotFound is returned when no admin has the ID
+	ErrAdminNotFound = models.NotFoundError("admin not found")
+	// ErrNIMTaken is returned when another user already has the NIM
+	ErrNIMTaken = models.NewCodedError(http.StatusConflict, models.ErrorCodeNIMTaken, "user with this NIM already exists")
+)
+
+// ErrUserNotFound is returned when no user has the ID or email
+var ErrUserNotFound = models.NotFoundError("user not found")
+
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	CreateMahasiswa(ctx context.Context, mahasiswa *models.UserMahasiswa) error
@@ -31,6 +42,7 @@ type UserRepository interface {
 	GetByRefreshToken(ctx context.Context, token string) (*models.User, error)
 	Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error
 	UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error
+	UpdatePasswordWithHistory(ctx context.Context, id primitive.ObjectID, passwordHash string, history []string) error
 	SetResetToken(ctx context.Context, id primitive.ObjectID, token string, expiry time.Time) error
 	ClearResetToken(ctx context.Context, id primitive.ObjectID) error
 	SetVerificationToken(ctx context.Context, id primitive.ObjectID, token string) error
@@ -50,6 +62,11 @@ type UserRepository interface {
 
 	// UpdateLastLogout updates the user's last logout timestamp
 	UpdateLastLogout(userID string) error
+
+	// Account deletion
+	MarkForDeletion(ctx context.Context, id primitive.ObjectID, requestedAt, scheduledAt time.Time) error
+	CancelDeletion(ctx context.Context, id primitive.ObjectID) error
+	GetAccountsDueForDeletion(ctx context.Context, before time.Time) ([]primitive.ObjectID, error)
 }
 
 type userRepository struct {
@@ -83,9 +100,27 @@ func (r *userRepository) CreateMahasiswa(ctx context.Context, mahasiswa *models.
 	mahasiswa.UpdatedAt = time.Now()
 
 	_, err := r.mahasiswaCollection.InsertOne(ctx, mahasiswa)
+	if duplicateKeyOn(err, "mahasiswa_id") {
+		return ErrNIMTaken
+	}
 	return err
 }
 
+// duplicateKeyOn reports whether a write failed on a unique index over field, going by the index key
+// pattern the server sends with the error
+func duplicateKeyOn(err error, field string) bool {
+	var writeErr mongo.WriteException
+	if !mongo.IsDuplicateKeyError(err) || !errors.As(err, &writeErr) {
+		return false
+	}
+	for _, e := range writeErr.WriteErrors {
+		if _, lookupErr := e.Raw.LookupErr("keyPattern", field); lookupErr == nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *userRepository) CreateAdmin(ctx context.Context, admin *models.Admin) error {
 	admin.ID = primitive.NewObjectID()
 	admin.CreatedAt = time.Now()
@@ -101,7 +136,7 @@ func (r *userRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*m
 	err := r.userCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -113,7 +148,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 	err := r.userCollection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -161,7 +196,7 @@ func (r *userRepository) GetAdminByID(ctx context.Context, id primitive.ObjectID
 	err := r.adminCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&admin)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("admin not found")
+			return nil, ErrAdminNotFound
 		}
 		return nil, err
 	}
@@ -173,7 +208,7 @@ func (r *userRepository) GetAdminByEmail(ctx context.Context, email string) (*mo
 	err := r.adminCollection.FindOne(ctx, bson.M{"email": email}).Decode(&admin)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("admin not found")
+			return nil, ErrAdminNotFound
 		}
 		return nil, err
 	}
@@ -209,7 +244,7 @@ func (r *userRepository) GetByOAuthID(ctx context.Context, provider, oauthID str
 		}
 	}
 
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func (r *userRepository) GetByResetToken(ctx context.Context, token string) (*models.User, error) {
@@ -232,7 +267,7 @@ func (r *userRepository) GetByResetToken(ctx context.Context, token string) (*mo
 		}
 	}
 
-	return nil, errors.New("invalid or expired reset token")
+	return nil, models.NewCodedError(http.StatusBadRequest, models.ErrorCodeTokenInvalid, "invalid or expired reset token")
 }
 
 func (r *userRepository) GetByVerificationToken(ctx context.Context, token string) (*models.User, error) {
@@ -272,7 +307,7 @@ func (r *userRepository) GetByRefreshToken(ctx context.Context, token string) (*
 		}
 	}
 
-	return nil, errors.New("invalid refresh token")
+	return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "invalid refresh token")
 }
 
 func (r *userRepository) Update(ctx context.Context, id primitive.ObjectID, updates bson.M) error {
@@ -291,13 +326,20 @@ func (r *userRepository) Update(ctx context.Context, id primitive.ObjectID, upda
 		}
 	}
 
-	return errors.New("user not found")
+	return ErrUserNotFound
 }
 
 func (r *userRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error {
 	return r.Update(ctx, id, bson.M{"password_hash": passwordHash})
 }
 
+func (r *userRepository) UpdatePasswordWithHistory(ctx context.Context, id primitive.ObjectID, passwordHash string, history []string) error {
+	return r.Update(ctx, id, bson.M{
+		"password_hash":    passwordHash,
+		"password_history": history,
+	})
+}
+
 func (r *userRepository) SetResetToken(ctx context.Context, id primitive.ObjectID, token string, expiry time.Time) error {
 	return r.Update(ctx, id, bson.M{
 		"reset_token":        token,
@@ -353,7 +395,7 @@ func (r *userRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 		}
 	}
 
-	return errors.New("user not found")
+	return ErrUserNotFound
 }
 
 func (r *userRepository) List(ctx context.Context, filter bson.M, page, limit int) ([]*models.User, int64, error) {
@@ -396,7 +438,7 @@ func (r *userRepository) ListUsers(ctx context.Context, req *models.ListUsersReq
 		limit = req.Limit
 	}
 
-	// Build filter for search across all collections
+	// Build filter applied after the collections are combined
 	filter := bson.M{}
 
 	// Search filter
@@ -404,6 +446,7 @@ func (r *userRepository) ListUsers(ctx context.Context, req *models.ListUsersReq
 		filter["$or"] = []bson.M{
 			{"full_name": bson.M{"$regex": req.Search, "$options": "i"}},
 			{"email": bson.M{"$regex": req.Search, "$options": "i"}},
+			{"mahasiswa_id": bson.M{"$regex": req.Search, "$options": "i"}},
 		}
 	}
 
@@ -417,112 +460,86 @@ func (r *userRepository) ListUsers(ctx context.Context, req *models.ListUsersReq
 		filter["status"] = req.Status
 	}
 
-	skip := (page - 1) * limit
-	opts := options.Find().
-		SetSkip(int64(skip)).
-		SetLimit(int64(limit)).
-		SetSort(bson.M{"created_at": -1})
-
-	var allUsers []models.UserSummary
-	totalCount := int64(0)
-
-	// Search in all user collections
-	collections := []struct {
-		coll     *mongo.Collection
-		userType models.UserType
-	}{
-		{r.userCollection, ""}, // General users
-		{r.mahasiswaCollection, models.UserTypeMahasiswa},
-		{r.adminCollection, models.UserTypeAdmin},
+	sortField := "created_at"
+	if req.SortBy != "" {
+		sortField = req.SortBy
+	}
+	sortOrder := -1
+	if req.SortOrder == "asc" {
+		sortOrder = 1
 	}
 
-	for _, c := range collections {
-		// Apply user type filter for specific collections
-		collectionFilter := filter
-		if c.userType != "" && req.UserType == "" {
-			// If no specific user type requested, include collection's default type
-			collectionFilter = bson.M{}
-			for k, v := range filter {
-				collectionFilter[k] = v
-			}
-		} else if req.UserType != "" && c.userType != "" && req.UserType != c.userType {
-			// Skip this collection if it doesn't match the requested user type
-			continue
-		}
+	skip := (page - 1) * limit
 
-		cursor, err := c.coll.Find(ctx, collectionFilter, opts)
-		if err != nil {
-			continue // Skip this collection on error
-		}
-
-		var users []models.UserSummary
-		if c.userType == models.UserTypeMahasiswa {
-			var mahasiswaUsers []models.UserMahasiswa
-			if err := cursor.All(ctx, &mahasiswaUsers); err == nil {
-				for _, u := range mahasiswaUsers {
-					users = append(users, models.UserSummary{
-						ID:            u.ID,
-						FullName:      u.FullName,
-						Email:         u.Email,
-						UserType:      models.UserTypeMahasiswa,
-						Status:        u.Status,
-						EmailVerified: u.EmailVerified,
-						LastLogin:     u.LastLogin,
-						CreatedAt:     u.CreatedAt,
-						NIM:           u.NIM,
-						Faculty:       u.Faculty,
-						Major:         u.Major,
-					})
-				}
-			}
-		} else if c.userType == models.UserTypeAdmin {
-			var adminUsers []models.Admin
-			if err := cursor.All(ctx, &adminUsers); err == nil {
-				for _, u := range adminUsers {
-					users = append(users, models.UserSummary{
-						ID:            u.ID,
-						FullName:      u.FullName,
-						Email:         u.Email,
-						UserType:      models.UserTypeAdmin,
-						Status:        u.Status,
-						EmailVerified: u.EmailVerified,
-						LastLogin:     u.LastLogin,
-						CreatedAt:     u.CreatedAt,
-					})
-				}
-			}
-		} else {
-			var generalUsers []models.User
-			if err := cursor.All(ctx, &generalUsers); err == nil {
-				for _, u := range generalUsers {
-					users = append(users, models.UserSummary{
-						ID:            u.ID,
-						FullName:      u.FullName,
-						Email:         u.Email,
-						UserType:      u.UserType,
-						Status:        u.Status,
-						EmailVerified: u.EmailVerified,
-						LastLogin:     u.LastLogin,
-						CreatedAt:     u.CreatedAt,
-					})
-				}
-			}
-		}
+	// Combine users, mahasiswa and admins into one stream so filtering, sorting and
+	// pagination apply across all of them. Mahasiswa and admin documents are tagged
+	// with their collection's type, matching how they are created.
+	pipeline := mongo.Pipeline{
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": r.mahasiswaCollection.Name(),
+			"pipeline": bson.A{
+				bson.M{"$addFields": bson.M{"user_type": models.UserTypeMahasiswa}},
+			},
+		}}},
+		{{Key: "$unionWith", Value: bson.M{
+			"coll": r.adminCollection.Name(),
+			"pipeline": bson.A{
+				bson.M{"$addFields": bson.M{"user_type": models.UserTypeAdmin}},
+			},
+		}}},
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"users": bson.A{
+				// _id breaks ties so pages never overlap
+				bson.M{"$sort": bson.D{{Key: sortField, Value: sortOrder}, {Key: "_id", Value: sortOrder}}},
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+				bson.M{"$project": bson.M{
+					"full_name":      1,
+					"email":          1,
+					"user_type":      1,
+					"status":         1,
+					"email_verified": 1,
+					"last_login":     1,
+					"created_at":     1,
+					"nim":            "$mahasiswa_id",
+					"faculty":        1,
+					"major":          1,
+					"organization":   1,
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := r.userCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
-		cursor.Close(ctx)
-		allUsers = append(allUsers, users...)
+	var results []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Users []models.UserSummary `bson:"users"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
 
-		// Count documents in this collection
-		count, err := c.coll.CountDocuments(ctx, collectionFilter)
-		if err == nil {
-			totalCount += count
+	allUsers := []models.UserSummary{}
+	totalCount := int64(0)
+	if len(results) > 0 {
+		allUsers = results[0].Users
+		if len(results[0].Total) > 0 {
+			totalCount = results[0].Total[0].Count
 		}
 	}
 
-	totalPages := int(totalCount)/limit + 1
-	if int(totalCount)%limit == 0 && totalCount > 0 {
-		totalPages = int(totalCount) / limit
-	}
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
 
 	return &models.ListUsersResponse{
 		Users:      allUsers,
@@ -541,6 +558,8 @@ func (r *userRepository) UpdateUserStatus(ctx context.Context, id primitive.Obje
 	return r.Update(ctx, id, updates)
 }
 
+// GetUserStats counts accounts by type and status, and those registered in the last 7 days, with one
+// aggregation per account collection
 func (r *userRepository) GetUserStats(ctx context.Context) (*models.UserStatsResponse, error) {
 	stats := &models.UserStatsResponse{
 		ByType:   make(map[string]int64),
@@ -556,42 +575,78 @@ func (r *userRepository) GetUserStats(ctx context.Context) (*models.UserStatsRes
 		{r.mahasiswaCollection, "mahasiswa"},
 		{r.adminCollection, "admin"},
 	}
+	statuses := []models.UserStatus{
+		models.UserStatusActive,
+		models.UserStatusPending,
+		models.UserStatusSuspended,
+		models.UserStatusRejected,
+	}
+	for _, status := range statuses {
+		stats.ByStatus[string(status)] = 0
+	}
+
+	// Get recent registrations (last 7 days)
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	pipeline := []bson.M{
+		{"$facet": bson.M{
+			"total":    []bson.M{{"$count": "count"}},
+			"byStatus": []bson.M{{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+			"recent": []bson.M{
+				{"$match": bson.M{"created_at": bson.M{"$gte": weekAgo}}},
+				{"$count": "count"},
+			},
+		}},
+	}
 
+	type count struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
 	for _, c := range collections {
-		total, _ := c.coll.CountDocuments(ctx, bson.M{})
+		var facets []struct {
+			Total    []count `bson:"total"`
+			ByStatus []count `bson:"byStatus"`
+			Recent   []count `bson:"recent"`
+		}
+		cursor, err := c.coll.Aggregate(ctx, pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &facets); err != nil {
+			return nil, err
+		}
+		if len(facets) == 0 {
+			continue
+		}
+		facet := facets[0]
+
+		var total int64
+		if len(facet.Total) > 0 {
+			total = facet.Total[0].Count
+		}
 		stats.TotalUsers += total
 		stats.ByType[c.userType] = total
 
 		// Count by status
-		statuses := []models.UserStatus{
-			models.UserStatusActive,
-			models.UserStatusPending,
-			models.UserStatusSuspended,
-			models.UserStatusRejected,
-		}
-
-		for _, status := range statuses {
-			count, _ := c.coll.CountDocuments(ctx, bson.M{"status": status})
-			stats.ByStatus[string(status)] += count
+		for _, group := range facet.ByStatus {
+			if _, known := stats.ByStatus[group.ID]; !known {
+				continue
+			}
+			stats.ByStatus[group.ID] += group.Count
 
-			switch status {
+			switch models.UserStatus(group.ID) {
 			case models.UserStatusActive:
-				stats.ActiveUsers += count
+				stats.ActiveUsers += group.Count
 			case models.UserStatusPending:
-				stats.PendingUsers += count
+				stats.PendingUsers += group.Count
 			case models.UserStatusSuspended:
-				stats.SuspendedUsers += count
+				stats.SuspendedUsers += group.Count
 			}
 		}
-	}
 
-	// Get recent registrations (last 7 days)
-	weekAgo := time.Now().AddDate(0, 0, -7)
-	recentFilter := bson.M{"created_at": bson.M{"$gte": weekAgo}}
-
-	for _, c := range collections {
-		count, _ := c.coll.CountDocuments(ctx, recentFilter)
-		stats.RecentRegistrations += count
+		if len(facet.Recent) > 0 {
+			stats.RecentRegistrations += facet.Recent[0].Count
+		}
 	}
 
 	// Pending requests count (assuming this is handled separately in access requests)
@@ -666,3 +721,60 @@ func (r *userRepository) UpdateLastLogout(userID string) error {
 
 	return nil
 }
+
+func (r *userRepository) MarkForDeletion(ctx context.Context, id primitive.ObjectID, requestedAt, scheduledAt time.Time) error {
+	return r.Update(ctx, id, bson.M{
+		"deletion_requested_at": requestedAt,
+		"deletion_scheduled_at": scheduledAt,
+	})
+}
+
+func (r *userRepository) CancelDeletion(ctx context.Context, id primitive.ObjectID) error {
+	collections := []*mongo.Collection{r.userCollection, r.mahasiswaCollection, r.adminCollection}
+
+	for _, collection := range collections {
+		result, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+			"$unset": bson.M{
+				"deletion_requested_at": "",
+				"deletion_scheduled_at": "",
+			},
+			"$set": bson.M{"updated_at": time.Now()},
+		})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount > 0 {
+			return nil
+		}
+	}
+
+	return ErrUserNotFound
+}
+
+func (r *userRepository) GetAccountsDueForDeletion(ctx context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	collections := []*mongo.Collection{r.userCollection, r.mahasiswaCollection, r.adminCollection}
+	filter := bson.M{"deletion_scheduled_at": bson.M{"$lte": before}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+
+	var ids []primitive.ObjectID
+	for _, collection := range collections {
+		cursor, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for cursor.Next(ctx) {
+			var doc struct {
+				ID primitive.ObjectID `bson:"_id"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				cursor.Close(ctx)
+				return nil, err
+			}
+			ids = append(ids, doc.ID)
+		}
+		cursor.Close(ctx)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// duplicateKeyResponse is the reply to an insert that broke the unique index over field
+func duplicateKeyResponse(field string) bson.D {
+	return bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "n", Value: 0},
+		{Key: "writeErrors", Value: bson.A{bson.D{
+			{Key: "index", Value: 0},
+			{Key: "code", Value: 11000},
+			{Key: "errmsg", Value: "E11000 duplicate key error collection: test.mahasiswa"},
+			{Key: "keyPattern", Value: bson.D{{Key: field, Value: 1}}},
+			{Key: "keyValue", Value: bson.D{{Key: field, Value: "taken"}}},
+		}}},
+	}
+}
+
+func TestCreateMahasiswaDuplicateKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("NIM taken", func(mt *mtest.T) {
+		mt.AddMockResponses(duplicateKeyResponse("mahasiswa_id"))
+		err := NewUserRepository(mt.DB).CreateMahasiswa(context.Background(), &models.UserMahasiswa{NIM: "taken"})
+		if !errors.Is(err, ErrNIMTaken) {
+			t.Errorf("CreateMahasiswa() error = %v, want ErrNIMTaken", err)
+		}
+	})
+
+	mt.Run("other unique index", func(mt *mtest.T) {
+		mt.AddMockResponses(duplicateKeyResponse("email"))
+		err := NewUserRepository(mt.DB).CreateMahasiswa(context.Background(), &models.UserMahasiswa{NIM: "free"})
+		if errors.Is(err, ErrNIMTaken) || !mongo.IsDuplicateKeyError(err) {
+			t.Errorf("CreateMahasiswa() error = %v, want the duplicate key error as is", err)
+		}
+	})
+}
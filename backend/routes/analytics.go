@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAnalyticsRoutes(admin *gin.RouterGroup, analyticsController *controllers.AnalyticsController) {
+	admin.GET("/analytics/cohorts/compare", analyticsController.CompareCohorts)
+	admin.GET("/analytics/at-risk", analyticsController.ListAtRiskStudents)
+	admin.POST("/analytics/at-risk/run", analyticsController.RunAtRiskAnalysis)
+	admin.GET("/analytics/abandonment", analyticsController.GetAbandonmentStats)
+	admin.GET("/questions/:id/analytics", analyticsController.GetQuestionAnalytics)
+}
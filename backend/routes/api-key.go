@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+	"backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAPIKeyRoutes(router gin.IRouter, apiKeyController *controllers.APIKeyController, apiKeyMiddleware *middleware.APIKeyMiddleware, questionController *controllers.QuestionController, userActivityController *controllers.UserActivityController, examController *controllers.ExamController, moduleController *controllers.ModuleController, admin *gin.RouterGroup) {
+	// Admin key lifecycle
+	admin.POST("/api-keys", apiKeyController.CreateAPIKey)
+	admin.GET("/api-keys", apiKeyController.ListAPIKeys)
+	admin.GET("/api-keys/:id", apiKeyController.GetAPIKey)
+	admin.PUT("/api-keys/:id", apiKeyController.UpdateAPIKey)
+	admin.POST("/api-keys/:id/revoke", apiKeyController.RevokeAPIKey)
+	admin.DELETE("/api-keys/:id", apiKeyController.DeleteAPIKey)
+
+	// Read-only endpoints for machine integrations, authenticated by X-API-Key
+	integrations := router.Group("/integrations")
+
+	questions := integrations.Group("/questions")
+	questions.Use(apiKeyMiddleware.RequireAPIKey(models.APIKeyScopeQuestionsRead))
+	{
+		questions.GET("", questionController.ListQuestions)
+		questions.GET("/stats", questionController.GetQuestionStats)
+		questions.GET("/:id", questionController.GetQuestion)
+	}
+
+	results := integrations.Group("")
+	results.Use(apiKeyMiddleware.RequireAPIKey(models.APIKeyScopeResultsRead))
+	{
+		results.GET("/users/:userID/results", userActivityController.GetUserResultsForIntegration)
+		results.GET("/exams/:id/attendance", examController.GetAttendanceReport)
+	}
+
+	feeds := integrations.Group("/feeds")
+	feeds.Use(apiKeyMiddleware.RequireAPIKey(models.APIKeyScopeContentRead))
+	{
+		feeds.GET("/modules.atom", moduleController.GetModulesAtomFeed)
+		feeds.GET("/modules.rss", moduleController.GetModulesRSSFeed)
+	}
+}
@@ -3,11 +3,12 @@ package routes
 import (
 	"backend/controllers"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(router gin.IRouter, userController *controllers.UserController, authMiddleware *middleware.AuthMiddleware, admin gin.IRouter) {
+func SetupAuthRoutes(router gin.IRouter, userController *controllers.UserController, authMiddleware *middleware.AuthMiddleware, rateLimit *middleware.RateLimitMiddleware, admin gin.IRouter) {
 	// Health check
 	router.GET("/health", userController.HealthCheck)
 
@@ -17,27 +18,29 @@ func SetupAuthRoutes(router gin.IRouter, userController *controllers.UserControl
 	auth := router.Group("/auth")
 	{
 		// Registration and login
-		auth.POST("/register", userController.Register)
-		auth.POST("/login", userController.Login)
-		auth.POST("/refresh", userController.RefreshToken)
+		auth.POST("/register", rateLimit.Limit(models.RateLimitAuth), userController.Register)
+		auth.POST("/login", rateLimit.Limit(models.RateLimitAuth), userController.Login)
+		auth.POST("/refresh", rateLimit.Limit(models.RateLimitAuth), userController.RefreshToken)
+		auth.GET("/password-policy", userController.GetPasswordPolicy)
+		auth.GET("/captcha", userController.GetCaptchaSettings)
 
 		// Password reset
-		auth.POST("/forgot-password", userController.RequestPasswordReset)
-		auth.POST("/reset-password", userController.ResetPassword)
+		auth.POST("/forgot-password", rateLimit.Limit(models.RateLimitAuth), userController.RequestPasswordReset)
+		auth.POST("/reset-password", rateLimit.Limit(models.RateLimitAuth), userController.ResetPassword)
 
 		// Email verification
-		auth.GET("/verify-email", userController.VerifyEmail)
-		auth.POST("/resend-verification", userController.ResendVerification)
+		auth.GET("/verify-email", rateLimit.Limit(models.RateLimitAuth), userController.VerifyEmail)
+		auth.POST("/resend-verification", rateLimit.Limit(models.RateLimitAuth), userController.ResendVerification)
 
 		// OAuth
 		auth.GET("/oauth/:provider/url", userController.GetOAuthURL)
-		auth.POST("/oauth/callback", userController.OAuthCallback)
+		auth.POST("/oauth/callback", rateLimit.Limit(models.RateLimitOAuth), userController.OAuthCallback)
 
 		// OAuth callback routes
-		auth.GET("/oauth/google/callback", userController.GoogleOAuthCallback)
-		auth.GET("/oauth/facebook/callback", userController.FacebookOAuthCallback)
-		auth.GET("/oauth/x/callback", userController.XOAuthCallback)
-		auth.GET("/oauth/github/callback", userController.GithubOAuthCallback)
+		auth.GET("/oauth/google/callback", rateLimit.Limit(models.RateLimitOAuth), userController.GoogleOAuthCallback)
+		auth.GET("/oauth/facebook/callback", rateLimit.Limit(models.RateLimitOAuth), userController.FacebookOAuthCallback)
+		auth.GET("/oauth/x/callback", rateLimit.Limit(models.RateLimitOAuth), userController.XOAuthCallback)
+		auth.GET("/oauth/github/callback", rateLimit.Limit(models.RateLimitOAuth), userController.GithubOAuthCallback)
 	}
 
 	// Protected auth routes (require authentication)
@@ -73,6 +76,7 @@ func SetupAuthRoutes(router gin.IRouter, userController *controllers.UserControl
 		admin.GET("/users", userController.GetAllUsers)
 		admin.GET("/users/stats", userController.GetUserStats)
 		admin.PUT("/users/:id/status", userController.UpdateUserStatus)
+		admin.POST("/users/:id/impersonate", userController.ImpersonateUser)
 
 		// Access request management
 		admin.GET("/access-requests", userController.GetAccessRequests)
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupBookmarkRoutes(router gin.IRouter, bookmarkController *controllers.BookmarkController, authMiddleware *middleware.AuthMiddleware) {
+	// Bookmarks - require authentication
+	bookmarks := router.Group("/user/bookmarks")
+	bookmarks.Use(authMiddleware.RequireAuth())
+	{
+		bookmarks.POST("", bookmarkController.CreateBookmark)
+		bookmarks.GET("", bookmarkController.ListBookmarks)
+		bookmarks.DELETE("/:id", bookmarkController.DeleteBookmark)
+
+		// Practice session built from bookmarked questions
+		bookmarks.POST("/practice", bookmarkController.StartPractice)
+	}
+}
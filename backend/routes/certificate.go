@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupCertificateRoutes(router gin.IRouter, certificateController *controllers.CertificateController, authMiddleware *middleware.AuthMiddleware) {
+	// Students download certificates of their own passed mock tests
+	results := router.Group("/quiz/results")
+	results.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		results.GET("/:id/certificate", certificateController.GetCertificate)
+	}
+
+	// Anyone holding a certificate can check it against the registry
+	router.GET("/certificates/verify/:code", certificateController.VerifyCertificate)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupChangelogRoutes(router gin.IRouter, changelogController *controllers.ChangelogController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Public release notes; a token, when sent, decides the audience
+	router.GET("/changelog", authMiddleware.OptionalAuth(), changelogController.GetChangelog)
+
+	// Admin release note management
+	admin.POST("/changelog", changelogController.CreateEntry)
+	admin.GET("/changelog", changelogController.ListEntries)
+	admin.GET("/changelog/:id", changelogController.GetEntry)
+	admin.PUT("/changelog/:id", changelogController.UpdateEntry)
+	admin.DELETE("/changelog/:id", changelogController.DeleteEntry)
+}
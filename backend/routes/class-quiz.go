@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupClassQuizRoutes(router gin.IRouter, classQuizController *controllers.ClassQuizController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Students and guests join from the code or QR shown in class
+	quiz := router.Group("/quiz")
+	quiz.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		quiz.POST("/join/:code", classQuizController.JoinClassQuiz)
+	}
+
+	// Instructor class quiz management
+	admin.POST("/class-quizzes", classQuizController.CreateClassQuiz)
+	admin.GET("/class-quizzes", classQuizController.ListClassQuizzes)
+	admin.POST("/class-quizzes/:id/close", classQuizController.CloseClassQuiz)
+	admin.GET("/class-quizzes/:id/board", classQuizController.GetBoard)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDashboardQueryRoutes registers the GraphQL endpoint outside the versioned REST API, which its
+// fields are drawn from
+func SetupDashboardQueryRoutes(router *gin.Engine, dashboardQueryController *controllers.DashboardQueryController, authMiddleware *middleware.AuthMiddleware) {
+	router.POST("/api/graphql", authMiddleware.RequireAuth(), dashboardQueryController.Query)
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupDifficultyRecalibrationRoutes(admin *gin.RouterGroup, recalibrationController *controllers.DifficultyRecalibrationController) {
+	admin.POST("/questions/difficulty/recalibrate", recalibrationController.RunRecalibration)
+	admin.GET("/difficulty-suggestions", recalibrationController.ListSuggestions)
+	admin.PUT("/difficulty-suggestions/:id", recalibrationController.ReviewSuggestion)
+}
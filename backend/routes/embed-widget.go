@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEmbedWidgetRoutes(router gin.IRouter, api *gin.RouterGroup, embedWidgetController *controllers.EmbedWidgetController, embedMiddleware *middleware.EmbedMiddleware, admin *gin.RouterGroup) {
+	// Page loaded in the departmental site's iframe, kept off /api/v1 so embed codes stay short
+	router.GET("/embed/:token", embedWidgetController.ServeEmbedPage)
+
+	// Admin widget lifecycle
+	admin.POST("/embed-widgets", embedWidgetController.CreateWidget)
+	admin.GET("/embed-widgets", embedWidgetController.ListWidgets)
+	admin.GET("/embed-widgets/:id", embedWidgetController.GetWidget)
+	admin.PUT("/embed-widgets/:id", embedWidgetController.UpdateWidget)
+	admin.POST("/embed-widgets/:id/revoke", embedWidgetController.RevokeWidget)
+
+	// Widget API: only the widget's own quiz, authenticated by the embed token and its allowed origins
+	widget := api.Group("/widget/:token")
+	widget.Use(embedMiddleware.RequireWidget())
+	{
+		// Preflight requests are answered by RequireWidget
+		widget.OPTIONS("/*path", func(c *gin.Context) {})
+		widget.GET("/config", embedWidgetController.GetConfig)
+		widget.POST("/sessions", embedWidgetController.StartSession)
+		widget.GET("/sessions/:session", embedWidgetController.GetSession)
+		widget.POST("/sessions/:session/answer", embedWidgetController.SaveAnswer)
+		widget.POST("/sessions/:session/submit", embedWidgetController.SubmitSession)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupEssayGradingRoutes(admin *gin.RouterGroup, essayGradingController *controllers.EssayGradingController) {
+	// Manual grading of essay answers
+	admin.GET("/grading/pending", essayGradingController.ListPendingEssays)
+	admin.PUT("/grading/results/:resultId/questions/:index", essayGradingController.GradeEssay)
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupExamRoutes(router gin.IRouter, examController *controllers.ExamController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Student seat lookup - requires authentication
+	exams := router.Group("/user/exams")
+	exams.Use(authMiddleware.RequireAuth())
+	{
+		exams.GET("", examController.GetMyAssignments)
+	}
+
+	// Admin-only sittings, seating and attendance
+	admin.POST("/exams", examController.CreateSitting)
+	admin.GET("/exams", examController.ListSittings)
+	admin.GET("/exams/:id", examController.GetSitting)
+	admin.DELETE("/exams/:id", examController.DeleteSitting)
+	admin.PUT("/exams/:id/lockdown", examController.UpdateLockdownSettings)
+	admin.PUT("/exams/:id/result-release", examController.UpdateResultRelease)
+	admin.POST("/exams/:id/release-results", examController.ReleaseResults)
+	admin.PUT("/exams/:id/moderation", examController.UpdateModerationSettings)
+	admin.GET("/exams/:id/moderation", examController.GetModerationQueue)
+	admin.PUT("/exams/:id/moderation/:resultId", examController.AdjustScore)
+	admin.POST("/exams/:id/scaling/preview", examController.PreviewScaling)
+	admin.PUT("/exams/:id/scaling", examController.ApplyScaling)
+	admin.DELETE("/exams/:id/scaling", examController.RemoveScaling)
+	admin.PUT("/exams/:id/seats", examController.AssignSeats)
+	admin.DELETE("/exams/:id/seats/:userId", examController.RemoveParticipant)
+	admin.GET("/exams/:id/attendance", examController.GetAttendanceReport)
+
+	// Offline packages for low-connectivity venues
+	admin.POST("/exams/:id/offline-packages", examController.CreateOfflinePackage)
+	admin.GET("/exams/:id/offline-packages", examController.ListOfflinePackages)
+	admin.GET("/exams/:id/offline-packages/:packageId/download", examController.DownloadOfflinePackage)
+	admin.POST("/exams/:id/offline-packages/:packageId/answers", examController.UploadOfflineAnswers)
+
+	// Paper versions for when the sitting has to be held on paper
+	admin.GET("/exams/:id/print", examController.PrintPaper)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupFlashcardRoutes(router gin.IRouter, flashcardController *controllers.FlashcardController, authMiddleware *middleware.AuthMiddleware) {
+	// Flashcards - require authentication
+	flashcards := router.Group("/user/flashcards")
+	flashcards.Use(authMiddleware.RequireAuth())
+	{
+		// Deck generation
+		flashcards.POST("/decks/module/:moduleId", flashcardController.GenerateModuleDeck)
+		flashcards.POST("/decks/missed", flashcardController.GenerateMissedQuestionsDeck)
+
+		// Decks
+		flashcards.GET("/decks", flashcardController.ListDecks)
+		flashcards.GET("/decks/:deckId", flashcardController.GetDeckCards)
+		flashcards.DELETE("/decks/:deckId", flashcardController.DeleteDeck)
+
+		// Review
+		flashcards.GET("/due", flashcardController.GetDueCards)
+		flashcards.POST("/:cardId/review", flashcardController.ReviewCard)
+	}
+}
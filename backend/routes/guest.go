@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupGuestRoutes(router gin.IRouter, guestController *controllers.GuestController, authMiddleware *middleware.AuthMiddleware) {
+	// Public: anonymous visitors get a guest token for time quizzes
+	router.POST("/auth/guest", guestController.StartGuestSession)
+
+	// Registered users attach what they did as a guest to their account
+	claim := router.Group("/quiz")
+	claim.Use(authMiddleware.RequireAuth())
+	{
+		claim.POST("/claim-guest-results", guestController.ClaimGuestResults)
+	}
+}
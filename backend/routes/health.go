@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes serves the probes at the root, outside the API prefix, where Docker and Kubernetes look
+func SetupHealthRoutes(router *gin.Engine, healthController *controllers.HealthController) {
+	router.GET("/health", healthController.Health)
+	router.GET("/ready", healthController.Ready)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupInvitationRoutes(router gin.IRouter, invitationController *controllers.InvitationController, admin *gin.RouterGroup) {
+	// Public lookup so the registration page can prefill an invite link
+	auth := router.Group("/auth")
+	{
+		auth.GET("/invitations/lookup", invitationController.LookupInvitation)
+	}
+
+	// Admin invitation management
+	admin.POST("/invitations", invitationController.CreateInvitation)
+	admin.GET("/invitations", invitationController.ListInvitations)
+	admin.DELETE("/invitations/:id", invitationController.RevokeInvitation)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupJobSchedulerRoutes(admin *gin.RouterGroup, jobSchedulerController *controllers.JobSchedulerController) {
+	admin.GET("/jobs", jobSchedulerController.ListJobs)
+	admin.GET("/jobs/runs", jobSchedulerController.ListRuns)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupJWKSRoutes(router gin.IRouter, jwksController *controllers.JWKSController) {
+	// Served from the site root, where token verifiers expect it
+	router.GET("/.well-known/jwks.json", jwksController.GetJWKS)
+}
@@ -11,8 +11,28 @@ func SetupModuleRoutes(router gin.IRouter, moduleController *controllers.ModuleC
 	// Public module routes
 	modules := router.Group("/modules")
 	{
-		modules.GET("", moduleController.GetAllModules)
-		modules.GET("/:moduleId", moduleController.GetModuleByID)
+		modules.GET("", authMiddleware.OptionalAuth(), moduleController.GetAllModules)
+		modules.GET("/search", authMiddleware.OptionalAuth(), moduleController.SearchModules)
+		modules.GET("/:moduleId", authMiddleware.OptionalAuth(), moduleController.GetModuleByID)
+		modules.GET("/:moduleId/images/:imageId", moduleController.GetModuleImage) // Shown in content, so public
+
+		// Learner progress, which unlocks modules that require others
+		modules.POST("/:moduleId/complete", authMiddleware.RequireAuth(), moduleController.CompleteModule)
+		modules.POST("/:moduleId/submodules/:submoduleId/complete", authMiddleware.RequireAuth(), moduleController.CompleteSubModule)
+		modules.POST("/:moduleId/views", authMiddleware.RequireAuthOrGuest(), moduleController.RecordModuleView)
+
+		// Where learners left off, to continue from there
+		modules.PUT("/:moduleId/position", authMiddleware.RequireAuth(), moduleController.SaveReadingPosition)
+		modules.GET("/continue", authMiddleware.RequireAuth(), moduleController.GetContinueReading)
+
+		// Attached files, for learners who can read where they are attached
+		modules.GET("/:moduleId/attachments", authMiddleware.RequireAuth(), moduleController.GetModuleAttachments)
+		modules.GET("/:moduleId/attachments/:attachmentId/download", authMiddleware.RequireAuth(), moduleController.DownloadModuleAttachment)
+
+		// Questions and answers under a submodule, where its discussion is open
+		modules.GET("/:moduleId/submodules/:submoduleId/comments", authMiddleware.RequireAuth(), moduleController.GetSubModuleComments)
+		modules.POST("/:moduleId/submodules/:submoduleId/comments", authMiddleware.RequireAuth(), moduleController.PostSubModuleComment)
+		modules.POST("/:moduleId/submodules/:submoduleId/comments/:commentId/report", authMiddleware.RequireAuth(), moduleController.ReportModuleComment)
 	}
 
 	// Admin module routes (use the shared admin group)
@@ -20,6 +40,7 @@ func SetupModuleRoutes(router gin.IRouter, moduleController *controllers.ModuleC
 	{
 		// Module CRUD
 		adminModules.POST("", moduleController.CreateModule)
+		adminModules.POST("/import", moduleController.ImportModule) // From a zip of markdown pages
 		adminModules.PUT("/:moduleId", moduleController.UpdateModule)
 		adminModules.DELETE("/:moduleId", moduleController.DeleteModule)
 		adminModules.PATCH("/:moduleId/publish", moduleController.ToggleModulePublication)
@@ -33,8 +54,33 @@ func SetupModuleRoutes(router gin.IRouter, moduleController *controllers.ModuleC
 		adminModules.PUT("/:moduleId/submodules/:submoduleId", moduleController.UpdateSubModule)
 		adminModules.DELETE("/:moduleId/submodules/:submoduleId", moduleController.DeleteSubModule)
 		adminModules.PATCH("/:moduleId/submodules/:submoduleId/publish", moduleController.ToggleSubModulePublication)
+		adminModules.PUT("/:moduleId/submodules/:submoduleId/parent", moduleController.MoveSubModule) // Nest under another submodule
 
 		// Submodule ordering
 		adminModules.POST("/:moduleId/submodules/reorder", moduleController.ReorderSubModules)
+
+		// Revision history of module content
+		adminModules.GET("/:moduleId/revisions", moduleController.GetModuleRevisions)
+		adminModules.GET("/:moduleId/revisions/diff", moduleController.DiffModuleRevisions)
+		adminModules.POST("/:moduleId/revisions/:revision/rollback", moduleController.RollbackModule)
+
+		// Attached PDFs and slides
+		adminModules.POST("/:moduleId/attachments", moduleController.UploadModuleAttachment)
+		adminModules.DELETE("/:moduleId/attachments/:attachmentId", moduleController.DeleteModuleAttachment)
+
+		// SCORM 1.2 or xAPI package, to load into an LMS
+		adminModules.GET("/:moduleId/export", moduleController.ExportModule)
+
+		// Submodule discussions and their moderation
+		adminModules.PATCH("/:moduleId/submodules/:submoduleId/discussion", moduleController.SetSubModuleDiscussion)
+		adminModules.GET("/comments/reported", moduleController.GetReportedModuleComments)
+		adminModules.DELETE("/:moduleId/comments/:commentId", moduleController.RemoveModuleComment)
+		adminModules.DELETE("/:moduleId/comments/:commentId/reports", moduleController.DismissModuleCommentReports)
+
+		// Who reads a module and where they stop
+		adminModules.GET("/:moduleId/analytics", moduleController.GetModuleAnalytics)
+
+		// Let one learner past a module's prerequisites
+		adminModules.PUT("/:moduleId/unlocks/:userId", moduleController.SetModuleUnlock)
 	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationRoutes(router gin.IRouter, notificationController *controllers.NotificationController, authMiddleware *middleware.AuthMiddleware) {
+	// Notifications - require authentication
+	notifications := router.Group("/user/notifications")
+	notifications.Use(authMiddleware.RequireAuth())
+	{
+		notifications.GET("", notificationController.ListNotifications)
+		notifications.POST("/read-all", notificationController.MarkAllRead)
+		notifications.POST("/:id/read", notificationController.MarkRead)
+	}
+}
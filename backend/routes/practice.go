@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPracticeRoutes(router gin.IRouter, practiceController *controllers.PracticeController, authMiddleware *middleware.AuthMiddleware) {
+	// Spaced-repetition practice - require authentication
+	practice := router.Group("/practice")
+	practice.Use(authMiddleware.RequireAuth())
+	{
+		practice.GET("/due", practiceController.GetDueQuestions)
+		practice.POST("/reviews", practiceController.RecordReview)
+	}
+}
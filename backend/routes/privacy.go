@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupPrivacyRoutes(router gin.IRouter, privacyController *controllers.PrivacyController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Personal data export and account deletion - require authentication
+	user := router.Group("/user")
+	user.Use(authMiddleware.RequireAuth())
+	{
+		user.POST("/data-export", privacyController.ExportData)
+		user.DELETE("/account", privacyController.DeleteAccount)
+	}
+
+	// Admin-only removal of accounts past their grace period
+	admin.POST("/users/purge-deleted", privacyController.PurgeDeletedAccounts)
+}
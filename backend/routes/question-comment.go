@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionCommentRoutes(admin *gin.RouterGroup, questionCommentController *controllers.QuestionCommentController) {
+	// Reviewers' discussion on a question's wording
+	admin.GET("/questions/:id/comments", questionCommentController.ListComments)
+	admin.POST("/questions/:id/comments", questionCommentController.PostComment)
+	admin.PATCH("/questions/:id/comments/:commentId", questionCommentController.ResolveComment)
+}
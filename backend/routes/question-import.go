@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionImportRoutes(admin *gin.RouterGroup, questionImportController *controllers.QuestionImportController) {
+	// Admin-only bulk question creation
+	admin.POST("/questions/import", questionImportController.ImportQuestions)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionProposalRoutes(router gin.IRouter, questionProposalController *controllers.QuestionProposalController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Student contributions to the question bank
+	questions := router.Group("/questions")
+	questions.Use(authMiddleware.RequireAuth())
+	{
+		questions.POST("/propose", questionProposalController.ProposeQuestion)
+		questions.GET("/proposals", questionProposalController.ListMyProposals)
+	}
+
+	// Admin review queue
+	admin.GET("/question-proposals", questionProposalController.ListProposals)
+	admin.GET("/question-proposals/:id", questionProposalController.GetProposal)
+	admin.PUT("/question-proposals/:id", questionProposalController.ReviewProposal)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuestionReportRoutes(router gin.IRouter, questionReportController *controllers.QuestionReportController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Reporting from a quiz session; guests may report questions of their time quizzes
+	quiz := router.Group("/quiz")
+	quiz.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		quiz.POST("/session/:token/questions/:index/flag", questionReportController.FlagQuestion)
+	}
+
+	// Admin review queue
+	admin.GET("/question-reports", questionReportController.ListReports)
+	admin.GET("/question-reports/:id/context", questionReportController.GetReportContext)
+	admin.PUT("/question-reports/:id", questionReportController.ReviewReport)
+}
@@ -3,16 +3,20 @@ package routes
 import (
 	"backend/controllers"
 	"backend/middleware"
+	"backend/models"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupQuestionRoutes(router gin.IRouter, questionController *controllers.QuestionController, authMiddleware *middleware.AuthMiddleware, admin gin.IRouter) {
+func SetupQuestionRoutes(router gin.IRouter, questionController *controllers.QuestionController, authMiddleware *middleware.AuthMiddleware, rateLimit *middleware.RateLimitMiddleware, admin gin.IRouter) {
 	// Public question routes (for quiz taking)
 	questions := router.Group("/questions")
 	{
 		// Get random questions for quiz generation
-		questions.GET("/random", questionController.GetRandomQuestions)
+		questions.GET("/random", rateLimit.Limit(models.RateLimitRandomQuestions), questionController.GetRandomQuestions)
+
+		// Text-to-speech renditions referenced from session payloads
+		questions.GET("/:id/audio", questionController.GetQuestionAudio)
 	}
 
 	// Admin question routes (use the shared admin group)
@@ -23,10 +27,23 @@ func SetupQuestionRoutes(router gin.IRouter, questionController *controllers.Que
 		admin.GET("/questions/:id", questionController.GetQuestion)
 		admin.PUT("/questions/:id", questionController.UpdateQuestion)
 		admin.DELETE("/questions/:id", questionController.DeleteQuestion)
+		admin.POST("/questions/:id/restore", questionController.RestoreQuestion)
 
 		// Question management features
 		admin.PATCH("/questions/:id/status", questionController.ToggleQuestionStatus)
 		admin.GET("/questions/stats", questionController.GetQuestionStats)
+		admin.GET("/questions/export", questionController.ExportQuestions)
+		admin.GET("/questions/accessibility-report", questionController.GetAccessibilityReport)
+		admin.GET("/questions/authoring-report", questionController.GetAuthoringReport)
+		admin.POST("/questions/:id/reviews", questionController.RecordReview)
+		admin.POST("/questions/:id/submit", questionController.SubmitQuestion)
+		admin.POST("/questions/:id/approve", questionController.ApproveQuestion)
+		admin.POST("/questions/:id/reject", questionController.RejectQuestion)
+		admin.GET("/questions/:id/versions", questionController.GetQuestionVersions)
 		admin.POST("/questions/validate", questionController.ValidateQuestion)
+
+		// Text-to-speech audio
+		admin.POST("/questions/:id/audio", questionController.GenerateQuestionAudio)
+		admin.POST("/questions/audio/generate", questionController.GenerateQuestionAudioBatch)
 	}
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuizLiveRoutes(router gin.IRouter, quizLiveController *controllers.QuizLiveController, authMiddleware *middleware.AuthMiddleware) {
+	// Live timer and multi-tab sync for quiz sessions; guests may take time quizzes
+	router.GET("/ws/quiz/:sessionToken", authMiddleware.RequireSocketAuth(), quizLiveController.StreamSession)
+}
@@ -7,25 +7,39 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupQuizSessionRoutes(router *gin.Engine, ctrl controllers.QuizSessionController, authMiddleware *middleware.AuthMiddleware) {
+func SetupQuizSessionRoutes(router *gin.Engine, ctrl controllers.QuizSessionController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
 	api := router.Group("/api/v1")
 
-	// All quiz session routes require authentication
+	// All quiz session routes require authentication; guests may take time quizzes
 	quiz := api.Group("/quiz")
-	quiz.Use(authMiddleware.RequireAuth())
+	quiz.Use(authMiddleware.RequireAuthOrGuest())
 	{
 		// Session Management
-		quiz.POST("/start", ctrl.StartQuiz)                            // Start new quiz session
-		quiz.GET("/session/:token", ctrl.GetSession)                   // Get session details
-		quiz.POST("/session/:token/answer", ctrl.SaveAnswer)           // Save question answer
-		quiz.POST("/session/:token/navigate", ctrl.NavigateToQuestion) // Navigate to question
-		quiz.POST("/session/:token/skip", ctrl.SkipQuestion)           // Skip question
-		quiz.POST("/session/:token/submit", ctrl.SubmitQuiz)           // Submit quiz for grading
+		quiz.POST("/start", ctrl.StartQuiz)                                // Start new quiz session
+		quiz.POST("/custom/start", ctrl.StartCustomQuiz)                   // Start a quiz built from tags
+		quiz.GET("/custom/tags", ctrl.ListCustomQuizTags)                  // Tags custom quizzes can use
+		quiz.GET("/session/:token", ctrl.GetSession)                       // Get session details
+		quiz.GET("/session/:token/media-manifest", ctrl.GetMediaManifest)  // Media URLs to prefetch
+		quiz.POST("/session/:token/answer", ctrl.SaveAnswer)               // Save question answer
+		quiz.POST("/session/:token/answers/batch", ctrl.SaveAnswersBatch)  // Replay answers buffered offline
+		quiz.POST("/session/:token/navigate", ctrl.NavigateToQuestion)     // Navigate to question
+		quiz.POST("/session/:token/skip", ctrl.SkipQuestion)               // Skip question
+		quiz.POST("/session/:token/submit", ctrl.SubmitQuiz)               // Submit quiz for grading
+		quiz.POST("/session/:token/resolve", ctrl.ResolveAbandonedSession) // Resume or discard an unfinished session
+		quiz.POST("/session/:token/events", ctrl.RecordIntegrityEvents)    // Report focus loss, tab switches and similar
 
 		// Session Recovery
 		quiz.GET("/resume/:quiz_type", ctrl.ResumeSession) // Check for resumable session
 
 		// Results & History
-		quiz.GET("/results", ctrl.GetUserResults) // Get user's quiz history
+		quiz.GET("/results", ctrl.GetUserResults)             // Get user's quiz history
+		quiz.GET("/results/:id", ctrl.GetResult)              // Get one result with how it compares to recent results
+		quiz.GET("/results/:id/review", ctrl.GetResultReview) // Walk through answers, explanations and linked modules
+		quiz.GET("/mastery", ctrl.GetModuleMastery)           // Mastery per module and the weakest modules to study
 	}
+
+	// Proctoring: review integrity events, and pause and resume a student's timer, recorded in the session's pause trail
+	admin.GET("/quiz-sessions/:id/integrity", ctrl.GetSessionIntegrity)
+	admin.POST("/quiz-sessions/:id/pause", ctrl.PauseSession)
+	admin.POST("/quiz-sessions/:id/resume", ctrl.ResumePausedSession)
 }
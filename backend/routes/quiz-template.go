@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupQuizTemplateRoutes(router gin.IRouter, quizTemplateController *controllers.QuizTemplateController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Quizzes students can start by template ID
+	quiz := router.Group("/quiz")
+	quiz.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		quiz.GET("/templates", quizTemplateController.ListActiveTemplates)
+	}
+
+	// Admin template management
+	admin.POST("/quiz-templates", quizTemplateController.CreateTemplate)
+	admin.GET("/quiz-templates", quizTemplateController.ListTemplates)
+	admin.GET("/quiz-templates/pool-status", quizTemplateController.GetPoolStatus)
+	admin.GET("/quiz-templates/:id", quizTemplateController.GetTemplate)
+	admin.PUT("/quiz-templates/:id", quizTemplateController.UpdateTemplate)
+	admin.DELETE("/quiz-templates/:id", quizTemplateController.DeleteTemplate)
+}
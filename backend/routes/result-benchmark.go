@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupResultBenchmarkRoutes(router gin.IRouter, resultBenchmarkController *controllers.ResultBenchmarkController, authMiddleware *middleware.AuthMiddleware) {
+	// Students and guests compare their own results with anonymized peers
+	results := router.Group("/quiz/results")
+	results.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		results.GET("/:id/benchmark", resultBenchmarkController.GetBenchmark)
+	}
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupResultThreadRoutes(router gin.IRouter, resultThreadController *controllers.ResultThreadController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Student side - require authentication
+	threads := router.Group("/user/result-threads")
+	threads.Use(authMiddleware.RequireAuth())
+	{
+		threads.GET("", resultThreadController.ListStudentThreads)
+		threads.GET("/results/:resultId", resultThreadController.GetStudentThread)
+		threads.POST("/results/:resultId/messages", resultThreadController.PostStudentMessage)
+	}
+
+	// Instructor side
+	admin.GET("/result-threads", resultThreadController.ListThreads)
+	admin.GET("/result-threads/:id", resultThreadController.GetThread)
+	admin.POST("/result-threads/:id/messages", resultThreadController.PostInstructorMessage)
+}
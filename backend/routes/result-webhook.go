@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupResultWebhookRoutes(admin *gin.RouterGroup, resultWebhookController *controllers.ResultWebhookController) {
+	// Department webhooks receiving finalized exam results
+	admin.POST("/result-webhooks", resultWebhookController.CreateWebhook)
+	admin.GET("/result-webhooks", resultWebhookController.ListWebhooks)
+	admin.GET("/result-webhooks/:id", resultWebhookController.GetWebhook)
+	admin.PUT("/result-webhooks/:id", resultWebhookController.UpdateWebhook)
+	admin.DELETE("/result-webhooks/:id", resultWebhookController.DeleteWebhook)
+	admin.POST("/result-webhooks/:id/rotate-secret", resultWebhookController.RotateSecret)
+	admin.POST("/result-webhooks/:id/ping", resultWebhookController.PingWebhook)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSessionTelemetryRoutes(router gin.IRouter, telemetryController *controllers.SessionTelemetryController, authMiddleware *middleware.AuthMiddleware, admin *gin.RouterGroup) {
+	// Clients flush buffered telemetry for their session; guests may send it for their time quizzes
+	quiz := router.Group("/quiz")
+	quiz.Use(authMiddleware.RequireAuthOrGuest())
+	{
+		quiz.POST("/session/:token/telemetry", telemetryController.Ingest)
+	}
+
+	// Admin aggregation, e.g. to diagnose reports of the timer freezing
+	admin.GET("/telemetry", telemetryController.GetSummary)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSettingsRoutes(admin *gin.RouterGroup, settingsController *controllers.SettingsController) {
+	admin.GET("/settings", settingsController.GetSettings)
+	admin.PUT("/settings", settingsController.UpdateSettings)
+}
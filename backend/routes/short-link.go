@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupShortLinkRoutes(router gin.IRouter, shortLinkController *controllers.ShortLinkController, admin *gin.RouterGroup) {
+	// Public redirect, kept off /api/v1 so printed links stay short
+	router.GET("/s/:code", shortLinkController.FollowShortLink)
+
+	// Admin short link management
+	admin.POST("/links", shortLinkController.CreateShortLink)
+	admin.GET("/links", shortLinkController.ListShortLinks)
+	admin.DELETE("/links/:id", shortLinkController.RevokeShortLink)
+}
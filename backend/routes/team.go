@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupTeamRoutes(router gin.IRouter, teamController *controllers.TeamController, authMiddleware *middleware.AuthMiddleware) {
+	// Team quizzes need an account so every member can be credited
+	teams := router.Group("/teams")
+	teams.Use(authMiddleware.RequireAuth())
+	{
+		teams.POST("", teamController.CreateTeam)
+		teams.GET("", teamController.GetUserTeams)
+		teams.POST("/join", teamController.JoinTeam)
+		teams.GET("/leaderboard", teamController.GetLeaderboard)
+		teams.GET("/:id", teamController.GetTeam)
+		teams.POST("/:id/leave", teamController.LeaveTeam)
+		teams.DELETE("/:id/members/:userId", teamController.RemoveMember)
+		teams.POST("/:id/quiz", teamController.StartTeamQuiz)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"backend/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupUserImportRoutes(admin *gin.RouterGroup, userImportController *controllers.UserImportController) {
+	// Admin-only bulk account creation
+	admin.POST("/users/import", userImportController.ImportUsers)
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"backend/controllers"
+	"backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupV2Routes registers /api/v2. Every answer uses the response envelope, resources and path
+// parameters are snake_case, lists share one way of paging, and who may call a route is decided by
+// its group's middleware rather than by the handler.
+func SetupV2Routes(
+	router *gin.Engine,
+	authMiddleware *middleware.AuthMiddleware,
+	moduleController *controllers.V2ModuleController,
+	notificationController *controllers.V2NotificationController,
+	adminController *controllers.V2AdminController,
+) {
+	v2 := router.Group("/api/v2", middleware.Envelope())
+
+	modules := v2.Group("/modules", authMiddleware.OptionalAuth())
+	{
+		modules.GET("", moduleController.ListModules)
+		modules.GET("/:module_id", moduleController.GetModule)
+	}
+
+	notifications := v2.Group("/notifications", authMiddleware.RequireAuth())
+	{
+		notifications.GET("", notificationController.ListNotifications)
+	}
+
+	admin := v2.Group("/admin", authMiddleware.RequireAuth(), authMiddleware.RequireAdmin())
+	{
+		admin.GET("/modules", moduleController.AdminListModules)
+		admin.GET("/modules/:module_id", moduleController.AdminGetModule)
+		admin.GET("/users", adminController.ListUsers)
+		admin.GET("/users/stats", adminController.UserStats)
+		admin.GET("/activity-logs", adminController.ListActivityLogs)
+		admin.GET("/settings", adminController.GetSettings)
+		admin.PATCH("/settings", adminController.UpdateSettings)
+		admin.GET("/jobs", adminController.ListJobs)
+		admin.GET("/jobs/:job_name/runs", adminController.ListJobRuns)
+	}
+}
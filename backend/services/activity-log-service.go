@@ -2,7 +2,7 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -55,7 +55,7 @@ func (s *activityLogService) asyncWorker() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		if err := s.activityLogRepo.CreateActivityLog(ctx, activityLog); err != nil {
 			// Log error but don't fail the application
-			fmt.Printf("Failed to log activity asynchronously: %v\n", err)
+			slog.Error("Failed to log activity asynchronously", "error", err)
 		}
 		cancel()
 	}
@@ -74,7 +74,7 @@ func (s *activityLogService) LogActivityAsync(activityLog *models.ActivityLog) {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 		if err := s.activityLogRepo.CreateActivityLog(ctx, activityLog); err != nil {
-			fmt.Printf("Failed to log activity (async fallback): %v\n", err)
+			slog.Error("Failed to log activity (async fallback)", "error", err)
 		}
 	}
 }
@@ -187,20 +187,33 @@ func (s *activityLogService) GetActivityLogs(ctx context.Context, req *models.Ge
 		req.Limit = 100 // Max limit
 	}
 
-	activities, total, err := s.activityLogRepo.GetActivityLogs(ctx, req)
+	after, err := parseCursor(req.Cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	activities, total, err := s.activityLogRepo.GetActivityLogs(ctx, req, after)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.GetActivityLogsResponse{Limit: req.Limit}
+	var more bool
+	if after != nil {
+		activities, more = cursorPage(activities, req.Limit)
+	} else {
+		response.Total = total
+		response.Page = req.Page
+		response.TotalPages = int((total + int64(req.Limit) - 1) / int64(req.Limit))
+		more = req.Page < response.TotalPages
+	}
+	response.Activities = activities
 
-	return &models.GetActivityLogsResponse{
-		Activities: activities,
-		Total:      total,
-		Page:       req.Page,
-		Limit:      req.Limit,
-		TotalPages: totalPages,
-	}, nil
+	if more && len(activities) > 0 {
+		last := activities[len(activities)-1]
+		response.NextCursor = models.NewPageCursor(last.Timestamp, last.ID).String()
+	}
+	return response, nil
 }
 
 func (s *activityLogService) GetActivityStats(ctx context.Context) (*models.ActivityStats, error) {
@@ -236,6 +249,13 @@ func (s *activityLogService) getActionFromType(activityType models.ActivityType)
 		models.ActivityModuleDeleted:     "Deleted module",
 		models.ActivityModulePublished:   "Published module",
 		models.ActivityModuleUnpublished: "Unpublished module",
+		models.ActivityModuleUnlocked:    "Changed module unlock",
+		models.ActivityModuleRolledBack:  "Rolled back module",
+		models.ActivityModuleImported:    "Imported module",
+		models.ActivityModuleExported:    "Exported module",
+
+		models.ActivityModuleAttachmentAdded:   "Added module attachment",
+		models.ActivityModuleAttachmentDeleted: "Deleted module attachment",
 
 		// SubModule actions
 		models.ActivitySubModuleCreated:     "Created submodule",
@@ -244,19 +264,34 @@ func (s *activityLogService) getActionFromType(activityType models.ActivityType)
 		models.ActivitySubModulePublished:   "Published submodule",
 		models.ActivitySubModuleUnpublished: "Unpublished submodule",
 
+		models.ActivitySubModuleDiscussionOpened: "Opened submodule discussion",
+		models.ActivitySubModuleDiscussionClosed: "Closed submodule discussion",
+		models.ActivityModuleCommentRemoved:      "Removed discussion comment",
+
 		// Question actions
 		models.ActivityQuestionCreated:     "Created question",
 		models.ActivityQuestionUpdated:     "Updated question",
 		models.ActivityQuestionDeleted:     "Deleted question",
 		models.ActivityQuestionActivated:   "Activated question",
 		models.ActivityQuestionDeactivated: "Deactivated question",
+		models.ActivityQuestionReviewed:    "Reviewed question",
+		models.ActivityQuestionArchived:    "Archived question",
+		models.ActivityQuestionRestored:    "Restored question",
+		models.ActivityQuestionSubmitted:   "Submitted question for review",
+		models.ActivityQuestionApproved:    "Approved question",
+		models.ActivityQuestionRejected:    "Returned question to draft",
 
 		// User management actions
-		models.ActivityUserAccessGranted: "Granted user access",
-		models.ActivityUserAccessRevoked: "Revoked user access",
-		models.ActivityUserSuspended:     "Suspended user",
-		models.ActivityUserActivated:     "Activated user",
-		models.ActivityUserRoleChanged:   "Changed user role",
+		models.ActivityUserAccessGranted:  "Granted user access",
+		models.ActivityUserAccessRevoked:  "Revoked user access",
+		models.ActivityUserSuspended:      "Suspended user",
+		models.ActivityUserActivated:      "Activated user",
+		models.ActivityUserRoleChanged:    "Changed user role",
+		models.ActivityUserImpersonated:   "Impersonated user",
+		models.ActivityAccountDeletion:    "Requested account deletion",
+		models.ActivityInvitationSent:     "Sent registration invitation",
+		models.ActivityInvitationRevoked:  "Revoked registration invitation",
+		models.ActivityInvitationAccepted: "Registered from invitation",
 
 		// Authentication actions
 		models.ActivityUserLogin:       "User logged in",
@@ -266,11 +301,58 @@ func (s *activityLogService) getActionFromType(activityType models.ActivityType)
 		models.ActivityMahasiswaLogin:  "Mahasiswa logged in",
 		models.ActivityExternalLogin:   "External user logged in",
 
+		// API key actions
+		models.ActivityAPIKeyCreated:      "Created API key",
+		models.ActivityAPIKeyUpdated:      "Updated API key",
+		models.ActivityAPIKeyRevoked:      "Revoked API key",
+		models.ActivityAPIKeyDeleted:      "Deleted API key",
+		models.ActivityEmbedWidgetCreated: "Created embed widget",
+		models.ActivityEmbedWidgetUpdated: "Updated embed widget",
+		models.ActivityEmbedWidgetRevoked: "Revoked embed widget",
+
+		// Result webhook actions
+		models.ActivityResultWebhookCreated:       "Created result webhook",
+		models.ActivityResultWebhookUpdated:       "Updated result webhook",
+		models.ActivityResultWebhookDeleted:       "Deleted result webhook",
+		models.ActivityResultWebhookSecretRotated: "Rotated result webhook secret",
+
+		// Changelog actions
+		models.ActivityChangelogCreated:   "Created changelog entry",
+		models.ActivityChangelogUpdated:   "Updated changelog entry",
+		models.ActivityChangelogPublished: "Published changelog entry",
+		models.ActivityChangelogDeleted:   "Deleted changelog entry",
+
+		// Short link actions
+		models.ActivityShortLinkCreated: "Created short link",
+		models.ActivityShortLinkRevoked: "Revoked short link",
+
+		// Question proposal actions
+		models.ActivityQuestionProposalAccepted: "Accepted question proposal",
+		models.ActivityQuestionProposalRejected: "Rejected question proposal",
+
+		// Difficulty recalibration actions
+		models.ActivityQuestionDifficultyRecalibrated: "Recalibrated question difficulty",
+		models.ActivityDifficultySuggestionRejected:   "Rejected difficulty suggestion",
+
+		// Exam actions
+		models.ActivityScoreModerated: "Moderated exam score",
+		models.ActivityScoresScaled:   "Scaled exam scores",
+		models.ActivityEssayGraded:    "Graded essay answer",
+
+		// Quiz template actions
+		models.ActivityQuizTemplateCreated: "Created quiz template",
+		models.ActivityQuizTemplateUpdated: "Updated quiz template",
+		models.ActivityQuizTemplateDeleted: "Deleted quiz template",
+
+		// Impersonation actions
+		models.ActivityImpersonatedAction: "Action performed while impersonating",
+
 		// System actions
 		models.ActivitySystemMaintenance: "System maintenance",
 		models.ActivityBulkOperation:     "Bulk operation",
 		models.ActivityDataExport:        "Data export",
 		models.ActivityDataImport:        "Data import",
+		models.ActivitySettingsUpdated:   "Updated runtime settings",
 	}
 
 	if action, exists := actionMap[activityType]; exists {
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// adaptiveDrawMargin is how many questions beyond those already served are drawn per difficulty,
+// so an unseen one is almost always among them
+const adaptiveDrawMargin = 10
+
+// drawAdaptiveQuestion draws an unseen question at the state's difficulty, falling back to the
+// nearest difficulties. Essays are left out since they cannot be marked while the student waits.
+// It returns nil when the bank has nothing left to ask. The question is worded in lang where translated.
+func (s *quizSessionService) drawAdaptiveQuestion(ctx context.Context, scoring models.QuizScoring, state *models.AdaptiveState, served []models.SessionQuestion, lang string) (*models.SessionQuestion, error) {
+	seen := make(map[primitive.ObjectID]bool, len(served))
+	for _, q := range served {
+		seen[q.QuestionID] = true
+	}
+
+	for _, difficulty := range state.DrawOrder() {
+		questions, err := s.getQuestionsByDifficulty(ctx, difficulty, len(served)+adaptiveDrawMargin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s questions: %w", difficulty, err)
+		}
+		for _, q := range questions {
+			if seen[q.ID] || q.Type == models.Essay {
+				continue
+			}
+			question := s.localizedSessionQuestion(q, lang)
+			question.Points = scoring.PointsFor(q)
+			return &question, nil
+		}
+	}
+	return nil, nil
+}
+
+// saveAdaptiveAnswer answers or, with a nil answer, skips an adaptive session's latest question,
+// then draws the next one at the difficulty the answers now call for
+func (s *quizSessionService) saveAdaptiveAnswer(ctx context.Context, session *models.QuizSession, questionIndex int, answer interface{}, timeSpent int64) (*models.SaveAnswerResponse, error) {
+	// Earlier answers decided the questions that followed, so they cannot be changed
+	if questionIndex != len(session.Questions)-1 {
+		return nil, errors.New("only the latest adaptive question can be answered")
+	}
+	question := session.Questions[questionIndex]
+	if question.IsAnswered || question.IsSkipped {
+		return nil, errors.New("question has already been answered")
+	}
+
+	isCorrect := answer != nil && s.checkAnswer(question, answer)
+	state := models.AdaptiveState{
+		Difficulty: session.Adaptive.Difficulty,
+		Recent:     append([]bool{}, session.Adaptive.Recent...),
+		Accuracy:   session.Adaptive.Accuracy,
+	}
+	state.Record(isCorrect)
+
+	var next *models.SessionQuestion
+	if len(session.Questions) < session.TotalQuestions {
+		var err error
+		next, err = s.drawAdaptiveQuestion(ctx, sessionScoring(session), &state, session.Questions, session.Language)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select next question: %w", err)
+		}
+	}
+
+	stored, err := s.sessionRepo.AnswerAdaptiveQuestion(ctx, session.ID, questionIndex, answer, timeSpent, &state, next)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+	if !stored {
+		return nil, errors.New("question has already been answered")
+	}
+	s.publishLive(session.SessionToken, models.QuizLiveEvent{Type: models.QuizLiveAnswerSaved, QuestionIndex: &questionIndex, Skipped: answer == nil})
+
+	response := &models.SaveAnswerResponse{
+		Success:      true,
+		Message:      "Answer saved successfully",
+		Adaptive:     &state,
+		NextQuestion: next,
+	}
+	if answer != nil {
+		response.IsCorrect = isCorrect
+		response.CorrectAnswer = sessionCorrectAnswer(question)
+		response.PointsEarned = answerPoints(question, answer, isCorrect)
+	}
+	if next == nil {
+		response.Message = "Answer saved. That was the last question, so the quiz is ready to submit"
+	}
+	return response, nil
+}
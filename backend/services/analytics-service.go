@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cohort comparisons scan every result in range, so they are reused for a while
+const cohortComparisonTTL = 15 * time.Minute
+
+// Fewer attempts than this are too noisy to call a trend
+const minAttemptsForTrend = 4
+
+type AnalyticsService interface {
+	CompareCohorts(ctx context.Context, req *models.CompareCohortsRequest) (*models.CohortComparisonResponse, error)
+
+	// Early-warning analysis
+	AnalyzeAtRisk(ctx context.Context) (*models.AtRiskAnalysisResult, error)
+	ListAtRiskStudents(ctx context.Context, req *models.ListAtRiskStudentsRequest) (*models.ListAtRiskStudentsResponse, error)
+
+	// Session abandonment
+	GetAbandonmentStats(ctx context.Context, req *models.AbandonmentStatsRequest) (*models.AbandonmentStatsResponse, error)
+
+	// Item analysis
+	AnalyzeQuestion(ctx context.Context, questionID primitive.ObjectID, req *models.QuestionAnalyticsRequest) (*models.QuestionAnalytics, error)
+}
+
+type cachedComparison struct {
+	response  *models.CohortComparisonResponse
+	expiresAt time.Time
+}
+
+type analyticsService struct {
+	analyticsRepo    repository.AnalyticsRepository
+	notificationRepo repository.NotificationRepository
+	questionRepo     repository.QuestionRepository
+	atRiskConfig     models.AtRiskConfig
+
+	mu           sync.Mutex
+	comparisons  map[string]cachedComparison
+	lastAnalysis *models.AtRiskAnalysisResult
+
+	// Held for the length of an at-risk analysis so scheduled and manual runs do not overlap
+	analysisMu sync.Mutex
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, notificationRepo repository.NotificationRepository, questionRepo repository.QuestionRepository, atRiskConfig models.AtRiskConfig) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo:    analyticsRepo,
+		notificationRepo: notificationRepo,
+		questionRepo:     questionRepo,
+		atRiskConfig:     atRiskConfig,
+		comparisons:      make(map[string]cachedComparison),
+	}
+}
+
+func (s *analyticsService) CompareCohorts(ctx context.Context, req *models.CompareCohortsRequest) (*models.CohortComparisonResponse, error) {
+	filter, err := parseCohortFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cohortCacheKey(filter)
+	now := time.Now()
+	if !req.Refresh {
+		if cached, ok := s.cachedComparison(key, now); ok {
+			return cached, nil
+		}
+	}
+
+	rows, err := s.analyticsRepo.GetCohortResults(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	categoryRows, err := s.analyticsRepo.GetCohortCategoryStats(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.CohortComparisonResponse{
+		GroupBy:     filter.GroupBy,
+		QuizType:    filter.QuizType,
+		DateFrom:    req.DateFrom,
+		DateTo:      req.DateTo,
+		Cohorts:     buildCohortStats(rows, categoryRows, filter.PassMark),
+		GeneratedAt: now,
+	}
+
+	s.mu.Lock()
+	s.comparisons[key] = cachedComparison{response: response, expiresAt: now.Add(cohortComparisonTTL)}
+	s.mu.Unlock()
+
+	return response, nil
+}
+
+// cachedComparison returns a copy of an unexpired comparison, dropping expired ones
+func (s *analyticsService) cachedComparison(key string, now time.Time) (*models.CohortComparisonResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, entry := range s.comparisons {
+		if !now.Before(entry.expiresAt) {
+			delete(s.comparisons, k)
+		}
+	}
+
+	entry, ok := s.comparisons[key]
+	if !ok {
+		return nil, false
+	}
+	response := *entry.response
+	response.Cached = true
+	return &response, true
+}
+
+func (s *analyticsService) AnalyzeAtRisk(ctx context.Context) (*models.AtRiskAnalysisResult, error) {
+	if !s.analysisMu.TryLock() {
+		return nil, models.ConflictError("at-risk analysis is already running")
+	}
+	defer s.analysisMu.Unlock()
+
+	// Stored times lose sub-millisecond precision; truncating lets new flags be recognised
+	start := time.Now().Truncate(time.Millisecond)
+	rows, err := s.analyticsRepo.GetStudentActivity(ctx, start.AddDate(0, 0, -s.atRiskConfig.LookbackDays))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AtRiskAnalysisResult{
+		Analyzed:   len(rows),
+		AnalyzedAt: start,
+	}
+	for _, row := range rows {
+		signals := atRiskSignals(&row, s.atRiskConfig, start)
+		if len(signals) == 0 {
+			continue
+		}
+
+		student, err := s.analyticsRepo.UpsertAtRiskStudent(ctx, &models.AtRiskStudent{
+			UserID:        row.UserID,
+			FullName:      row.FullName,
+			Email:         row.Email,
+			NIM:           row.NIM,
+			Faculty:       row.Faculty,
+			Major:         row.Major,
+			RiskScore:     riskScore(signals),
+			Signals:       signals,
+			Attempts:      len(row.Results),
+			LastAttemptAt: row.LastAttemptAt,
+			AnalyzedAt:    start,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Flagged++
+		if student.FlaggedAt.Equal(start) {
+			result.NewlyFlagged++
+		}
+		if student.NotifiedAt == nil || start.Sub(*student.NotifiedAt) >= s.atRiskConfig.NotifyCooldown {
+			if s.notifyAtRisk(ctx, student, start) {
+				result.Notified++
+			}
+		}
+	}
+
+	// Anyone not refreshed by this run has recovered
+	result.Cleared, err = s.analyticsRepo.DeleteAtRiskAnalyzedBefore(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	s.mu.Lock()
+	s.lastAnalysis = result
+	s.mu.Unlock()
+
+	return result, nil
+}
+
+func (s *analyticsService) ListAtRiskStudents(ctx context.Context, req *models.ListAtRiskStudentsRequest) (*models.ListAtRiskStudentsResponse, error) {
+	students, total, err := s.analyticsRepo.ListAtRiskStudents(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list at-risk students: %w", err)
+	}
+
+	s.mu.Lock()
+	lastAnalysis := s.lastAnalysis
+	s.mu.Unlock()
+
+	return &models.ListAtRiskStudentsResponse{
+		Students:     students,
+		Total:        total,
+		Page:         req.Page,
+		Limit:        req.Limit,
+		TotalPages:   int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+		LastAnalysis: lastAnalysis,
+	}, nil
+}
+
+// notifyAtRisk sends the student a check-in about their strongest signal
+func (s *analyticsService) notifyAtRisk(ctx context.Context, student *models.AtRiskStudent, now time.Time) bool {
+	strongest := student.Signals[0]
+	for _, signal := range student.Signals[1:] {
+		if signal.Severity > strongest.Severity {
+			strongest = signal
+		}
+	}
+
+	message := "Your recent quiz scores have dropped. Reviewing the questions you missed and the related modules can help you get back on track."
+	switch strongest.Type {
+	case models.RiskLowActivity:
+		message = "We haven't seen you take a quiz in a while. A short practice quiz is a good way to keep what you've learned fresh."
+	case models.RiskRepeatedTimeouts:
+		message = "Several of your recent quizzes ran out of time. Practice quizzes can help you get comfortable with the pace."
+	}
+
+	notification := &models.Notification{
+		UserID:  student.UserID,
+		Type:    models.NotificationAtRiskOutreach,
+		Title:   "Checking in on your progress",
+		Message: message,
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to create at-risk notification", "error", err)
+		return false
+	}
+	if err := s.analyticsRepo.MarkAtRiskNotified(ctx, student.UserID, now); err != nil {
+		slog.ErrorContext(ctx, "Failed to record at-risk notification", "error", err)
+	}
+	return true
+}
+
+// atRiskSignals checks a student's recent results against each early-warning threshold
+func atRiskSignals(row *models.StudentActivityRow, cfg models.AtRiskConfig, now time.Time) []models.RiskSignal {
+	var signals []models.RiskSignal
+
+	// Declining scores: the recent half of the attempts against the earlier half
+	if n := len(row.Results); cfg.ScoreDrop > 0 && n >= minAttemptsForTrend {
+		earlier := meanScore(row.Results[:n/2])
+		recent := meanScore(row.Results[n-n/2:])
+		if drop := earlier - recent; drop >= cfg.ScoreDrop {
+			signals = append(signals, models.RiskSignal{
+				Type:     models.RiskDecliningScores,
+				Detail:   fmt.Sprintf("Average score fell from %.1f%% to %.1f%% over the last %d attempts", earlier, recent, n),
+				Value:    math.Round(drop*10) / 10,
+				Severity: severity(drop, cfg.ScoreDrop),
+			})
+		}
+	}
+
+	// Low activity: counted from the last attempt, or from registration for students who never finished one
+	since, detail := row.LastAttemptAt, "No finished quizzes in %d days"
+	if since == nil && !row.CreatedAt.IsZero() {
+		since, detail = &row.CreatedAt, "No finished quizzes since registering %d days ago"
+	}
+	if since != nil && cfg.InactiveDays > 0 {
+		days := now.Sub(*since).Hours() / 24
+		if days >= float64(cfg.InactiveDays) {
+			signals = append(signals, models.RiskSignal{
+				Type:     models.RiskLowActivity,
+				Detail:   fmt.Sprintf(detail, int(days)),
+				Value:    math.Floor(days),
+				Severity: severity(days, float64(cfg.InactiveDays)),
+			})
+		}
+	}
+
+	// Repeated timeouts within the lookback window
+	timeouts := 0
+	for _, result := range row.Results {
+		if result.CompletionStatus == models.QuizTimeout {
+			timeouts++
+		}
+	}
+	if cfg.TimeoutThreshold > 0 && timeouts >= cfg.TimeoutThreshold {
+		signals = append(signals, models.RiskSignal{
+			Type:     models.RiskRepeatedTimeouts,
+			Detail:   fmt.Sprintf("%d of %d recent attempts ran out of time", timeouts, len(row.Results)),
+			Value:    float64(timeouts),
+			Severity: severity(float64(timeouts), float64(cfg.TimeoutThreshold)),
+		})
+	}
+
+	return signals
+}
+
+// severity maps a value to 0-1 so the threshold is 0.5 and twice the threshold or more is 1
+func severity(value, threshold float64) float64 {
+	return math.Min(value/(2*threshold), 1)
+}
+
+// riskScore combines signal severities so any strong signal ranks high and each extra signal adds to it
+func riskScore(signals []models.RiskSignal) float64 {
+	unaffected := 1.0
+	for _, signal := range signals {
+		unaffected *= 1 - signal.Severity
+	}
+	return math.Round((1-unaffected)*1000) / 10
+}
+
+func meanScore(results []models.StudentResultPoint) float64 {
+	var sum float64
+	for _, result := range results {
+		sum += result.ScorePercentage
+	}
+	return sum / float64(len(results))
+}
+
+// GetAbandonmentStats reports how sessions started in the range ended per quiz type: submitted,
+// still in progress, or abandoned and why, with how far abandoned sessions had got
+func (s *analyticsService) GetAbandonmentStats(ctx context.Context, req *models.AbandonmentStatsRequest) (*models.AbandonmentStatsResponse, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.analyticsRepo.GetAbandonmentRows(ctx, req.QuizType, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[models.QuizType]*models.AbandonmentStats)
+	for _, row := range rows {
+		stats, ok := byType[row.Key.QuizType]
+		if !ok {
+			stats = &models.AbandonmentStats{QuizType: row.Key.QuizType, Reasons: []models.AbandonmentReasonStats{}}
+			byType[row.Key.QuizType] = stats
+		}
+		stats.Started += row.Sessions
+
+		switch {
+		case row.Key.Reason != "":
+			stats.Abandoned += row.Sessions
+			stats.Reasons = append(stats.Reasons, models.AbandonmentReasonStats{
+				Reason:             row.Key.Reason,
+				Sessions:           row.Sessions,
+				AvgAnsweredPercent: math.Round(row.AnsweredRatio*1000) / 10,
+				AvgStopQuestion:    math.Round((row.StopQuestion+1)*10) / 10,
+			})
+		case row.Key.Status == models.QuizInProgress:
+			stats.InProgress += row.Sessions
+		case row.Key.Status == models.QuizAbandoned:
+			// Abandoned before reasons were recorded
+			stats.Abandoned += row.Sessions
+		default:
+			stats.Submitted += row.Sessions
+		}
+	}
+
+	response := &models.AbandonmentStatsResponse{
+		QuizType:    req.QuizType,
+		DateFrom:    req.DateFrom,
+		DateTo:      req.DateTo,
+		QuizTypes:   make([]models.AbandonmentStats, 0, len(byType)),
+		GeneratedAt: time.Now(),
+	}
+	for _, stats := range byType {
+		if finished := stats.Started - stats.InProgress; finished > 0 {
+			stats.AbandonmentRate = math.Round(float64(stats.Abandoned)/float64(finished)*1000) / 10
+		}
+		for i := range stats.Reasons {
+			stats.Reasons[i].Share = math.Round(float64(stats.Reasons[i].Sessions)/float64(stats.Abandoned)*1000) / 10
+		}
+		sort.Slice(stats.Reasons, func(i, j int) bool {
+			return stats.Reasons[i].Sessions > stats.Reasons[j].Sessions
+		})
+		response.QuizTypes = append(response.QuizTypes, *stats)
+	}
+	sort.Slice(response.QuizTypes, func(i, j int) bool {
+		if response.QuizTypes[i].AbandonmentRate != response.QuizTypes[j].AbandonmentRate {
+			return response.QuizTypes[i].AbandonmentRate > response.QuizTypes[j].AbandonmentRate
+		}
+		return response.QuizTypes[i].QuizType < response.QuizTypes[j].QuizType
+	})
+
+	return response, nil
+}
+
+func parseCohortFilter(req *models.CompareCohortsRequest) (*models.CohortFilter, error) {
+	filter := &models.CohortFilter{
+		GroupBy:  req.GroupBy,
+		QuizType: req.QuizType,
+		PassMark: req.PassMark,
+	}
+
+	for _, cohort := range strings.Split(req.Cohorts, ",") {
+		if cohort = strings.TrimSpace(cohort); cohort != "" {
+			filter.Cohorts = append(filter.Cohorts, cohort)
+		}
+	}
+	sort.Strings(filter.Cohorts)
+
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	filter.From, filter.To = from, to
+
+	return filter, nil
+}
+
+// parseDateRange parses inclusive YYYY-MM-DD bounds into a range whose end is exclusive
+func parseDateRange(dateFrom, dateTo string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if dateFrom != "" {
+		t, err := time.Parse("2006-01-02", dateFrom)
+		if err != nil {
+			return nil, nil, models.BadRequestError("invalid date_from, expected YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if dateTo != "" {
+		t, err := time.Parse("2006-01-02", dateTo)
+		if err != nil {
+			return nil, nil, models.BadRequestError("invalid date_to, expected YYYY-MM-DD")
+		}
+		// Include the whole of the last day
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, models.BadRequestError("date_from must not be after date_to")
+	}
+	return from, to, nil
+}
+
+func cohortCacheKey(filter *models.CohortFilter) string {
+	var from, to string
+	if filter.From != nil {
+		from = filter.From.Format(time.RFC3339)
+	}
+	if filter.To != nil {
+		to = filter.To.Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%g", filter.GroupBy, filter.QuizType, strings.Join(filter.Cohorts, ","), from, to, filter.PassMark)
+}
+
+func buildCohortStats(rows []models.CohortResultRow, categoryRows []models.CohortCategoryRow, passMark float64) []models.CohortStats {
+	byDifficulty := make(map[string]map[string]*models.CategoryPerformance)
+	byType := make(map[string]map[string]*models.CategoryPerformance)
+	for _, row := range categoryRows {
+		addCategoryCounts(byDifficulty, row.Key.Cohort, string(row.Key.Difficulty), row)
+		addCategoryCounts(byType, row.Key.Cohort, string(row.Key.Type), row)
+	}
+
+	cohorts := make([]models.CohortStats, 0, len(rows))
+	for _, row := range rows {
+		cohorts = append(cohorts, models.CohortStats{
+			Cohort:         row.Cohort,
+			Students:       row.Students,
+			Attempts:       row.Attempts,
+			TimedOut:       row.TimedOut,
+			Scores:         models.NewScoreDistribution(row.Scores, passMark),
+			CompletionTime: durationStats(row.TimesUsed),
+			ByDifficulty:   sortedCategories(byDifficulty[row.Cohort]),
+			ByQuestionType: sortedCategories(byType[row.Cohort]),
+		})
+	}
+	return cohorts
+}
+
+func addCategoryCounts(into map[string]map[string]*models.CategoryPerformance, cohort, category string, row models.CohortCategoryRow) {
+	if into[cohort] == nil {
+		into[cohort] = make(map[string]*models.CategoryPerformance)
+	}
+	perf := into[cohort][category]
+	if perf == nil {
+		perf = &models.CategoryPerformance{Category: category}
+		into[cohort][category] = perf
+	}
+	perf.Answered += row.Total
+	perf.Correct += row.Correct
+	perf.Skipped += row.Skipped
+}
+
+func sortedCategories(categories map[string]*models.CategoryPerformance) []models.CategoryPerformance {
+	result := make([]models.CategoryPerformance, 0, len(categories))
+	for _, perf := range categories {
+		if perf.Answered > 0 {
+			perf.Accuracy = float64(perf.Correct) / float64(perf.Answered) * 100
+		}
+		result = append(result, *perf)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Category < result[j].Category })
+	return result
+}
+
+func durationStats(seconds []int64) models.DurationStats {
+	if len(seconds) == 0 {
+		return models.DurationStats{}
+	}
+
+	sorted := append([]int64(nil), seconds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum int64
+	for _, s := range sorted {
+		sum += s
+	}
+
+	stats := models.DurationStats{
+		Mean: float64(sum) / float64(len(sorted)),
+		Min:  sorted[0],
+		Max:  sorted[len(sorted)-1],
+	}
+	if n := len(sorted); n%2 == 1 {
+		stats.Median = float64(sorted[n/2])
+	} else {
+		stats.Median = float64(sorted[n/2-1]+sorted[n/2]) / 2
+	}
+	return stats
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type APIKeyService interface {
+	// Admin lifecycle
+	CreateAPIKey(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, req *models.ListAPIKeysRequest) (*models.ListAPIKeysResponse, error)
+	GetAPIKey(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error)
+	UpdateAPIKey(ctx context.Context, id primitive.ObjectID, req *models.UpdateAPIKeyRequest) (*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, adminID, id primitive.ObjectID) (*models.APIKey, error)
+	DeleteAPIKey(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error)
+
+	// Request authentication
+	Authenticate(ctx context.Context, rawKey, ipAddress string) (*models.APIKey, error)
+	CheckRateLimit(ctx context.Context, apiKey *models.APIKey) (allowed bool, remaining int, resetAt time.Time)
+}
+
+type apiKeyService struct {
+	apiKeyRepo     repository.APIKeyRepository
+	rateLimitStore utils.RateLimitStore
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, rateLimitStore utils.RateLimitStore) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:     apiKeyRepo,
+		rateLimitStore: rateLimitStore,
+	}
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	rateLimit := req.RateLimitPerMinute
+	if rateLimit == 0 {
+		rateLimit = models.DefaultAPIKeyRateLimit
+	}
+
+	apiKey := &models.APIKey{
+		Name:               strings.TrimSpace(req.Name),
+		Description:        strings.TrimSpace(req.Description),
+		Prefix:             prefix,
+		KeyHash:            utils.HashAPIKey(key),
+		Scopes:             uniqueScopes(req.Scopes),
+		RateLimitPerMinute: rateLimit,
+		CreatedBy:          adminID,
+		CreatedByEmail:     adminEmail,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &models.CreateAPIKeyResponse{
+		APIKey:  apiKey,
+		Key:     key,
+		Message: "API key created. Store it now; it will not be shown again",
+	}, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, req *models.ListAPIKeysRequest) (*models.ListAPIKeysResponse, error) {
+	apiKeys, total, err := s.apiKeyRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	if apiKeys == nil {
+		apiKeys = []models.APIKey{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListAPIKeysResponse{
+		APIKeys:    apiKeys,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *apiKeyService) GetAPIKey(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.Status == models.APIKeyActive && !apiKey.IsUsable() {
+		apiKey.Status = models.APIKeyExpired
+	}
+	return apiKey, nil
+}
+
+func (s *apiKeyService) UpdateAPIKey(ctx context.Context, id primitive.ObjectID, req *models.UpdateAPIKeyRequest) (*models.APIKey, error) {
+	existing, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.APIKeyRevoked {
+		return nil, models.ConflictError("revoked api keys cannot be changed")
+	}
+
+	updates := bson.M{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Scopes != nil {
+		updates["scopes"] = uniqueScopes(req.Scopes)
+	}
+	if req.RateLimitPerMinute != nil {
+		updates["rate_limit_per_minute"] = *req.RateLimitPerMinute
+	}
+
+	if len(updates) == 0 {
+		return existing, nil
+	}
+
+	apiKey, err := s.apiKeyRepo.Update(ctx, id, updates)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update api key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, adminID, id primitive.ObjectID) (*models.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.Revoke(ctx, id, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// DeleteAPIKey removes the key entirely and returns what was deleted, for the audit log
+func (s *apiKeyService) DeleteAPIKey(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeyRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+// Authenticate resolves a raw X-API-Key value to a usable key and records the use
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey, ipAddress string) (*models.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, utils.HashAPIKey(strings.TrimSpace(rawKey)))
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeAPIKeyInvalid, "invalid api key")
+		}
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+
+	if apiKey.Status == models.APIKeyRevoked {
+		return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeAPIKeyInvalid, "api key has been revoked")
+	}
+	if !apiKey.IsUsable() {
+		return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeAPIKeyInvalid, "api key has expired")
+	}
+
+	// Usage tracking must not slow down or fail the request
+	go func(id primitive.ObjectID, usedAt time.Time) {
+		if err := s.apiKeyRepo.RecordUsage(context.Background(), id, ipAddress, usedAt); err != nil {
+			slog.ErrorContext(ctx, "Failed to record api key usage", "error", err)
+		}
+	}(apiKey.ID, time.Now())
+
+	return apiKey, nil
+}
+
+// CheckRateLimit counts the request against the key's per-minute allowance in the shared rate limit
+// store, so every server instance enforces the same limit. Should the store fail, the request is let
+// through like those limited by client IP.
+func (s *apiKeyService) CheckRateLimit(ctx context.Context, apiKey *models.APIKey) (bool, int, time.Time) {
+	limit := apiKey.RateLimitPerMinute
+	if limit <= 0 {
+		limit = models.DefaultAPIKeyRateLimit
+	}
+
+	count, resetAt, err := s.rateLimitStore.Hit(ctx, "ratelimit:apikey:"+apiKey.ID.Hex(), time.Minute)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check api key rate limit", "api_key_id", apiKey.ID.Hex(), "error", err)
+		return true, limit, time.Now().Add(time.Minute)
+	}
+	if count > limit {
+		return false, 0, resetAt
+	}
+	return true, limit - count, resetAt
+}
+
+// uniqueScopes drops repeated scopes while keeping the order given
+func uniqueScopes(scopes []models.APIKeyScope) []models.APIKeyScope {
+	seen := make(map[models.APIKeyScope]bool, len(scopes))
+	result := make([]models.APIKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BookmarkService interface {
+	CreateBookmark(ctx context.Context, userID primitive.ObjectID, req *models.CreateBookmarkRequest) (*models.Bookmark, error)
+	DeleteBookmark(ctx context.Context, userID, id primitive.ObjectID) error
+	ListBookmarks(ctx context.Context, userID primitive.ObjectID, req *models.ListBookmarksRequest) (*models.ListBookmarksResponse, error)
+	GetPracticeQuestions(ctx context.Context, userID primitive.ObjectID, req *models.BookmarkPracticeRequest) ([]*models.Question, error)
+}
+
+type bookmarkService struct {
+	bookmarkRepo repository.BookmarkRepository
+	questionRepo repository.QuestionRepository
+}
+
+func NewBookmarkService(bookmarkRepo repository.BookmarkRepository, questionRepo repository.QuestionRepository) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+func (s *bookmarkService) CreateBookmark(ctx context.Context, userID primitive.ObjectID, req *models.CreateBookmarkRequest) (*models.Bookmark, error) {
+	questionID, err := primitive.ObjectIDFromHex(req.QuestionID)
+	if err != nil {
+		return nil, models.BadRequestError("invalid question ID")
+	}
+
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := &models.Bookmark{
+		UserID:        userID,
+		QuestionID:    question.ID,
+		QuestionTitle: question.Title,
+		QuestionType:  question.Type,
+		Difficulty:    question.Difficulty,
+		Note:          req.Note,
+	}
+
+	if req.ResultID != "" {
+		resultID, err := primitive.ObjectIDFromHex(req.ResultID)
+		if err != nil {
+			return nil, models.BadRequestError("invalid result ID")
+		}
+		bookmark.ResultID = &resultID
+	}
+
+	if err := s.bookmarkRepo.Create(ctx, bookmark); err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+func (s *bookmarkService) DeleteBookmark(ctx context.Context, userID, id primitive.ObjectID) error {
+	return s.bookmarkRepo.Delete(ctx, userID, id)
+}
+
+func (s *bookmarkService) ListBookmarks(ctx context.Context, userID primitive.ObjectID, req *models.ListBookmarksRequest) (*models.ListBookmarksResponse, error) {
+	bookmarks, total, err := s.bookmarkRepo.List(ctx, userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmarks: %w", err)
+	}
+
+	if bookmarks == nil {
+		bookmarks = []models.Bookmark{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListBookmarksResponse{
+		Bookmarks:  bookmarks,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *bookmarkService) GetPracticeQuestions(ctx context.Context, userID primitive.ObjectID, req *models.BookmarkPracticeRequest) ([]*models.Question, error) {
+	questionIDs, err := s.bookmarkRepo.GetQuestionIDs(ctx, userID, req.Type, req.Difficulty)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarked questions: %w", err)
+	}
+
+	if len(questionIDs) == 0 {
+		return nil, models.NotFoundError("no bookmarked questions found")
+	}
+
+	// Inactive questions are dropped so practice only uses questions still in the bank
+	questions, err := s.questionRepo.GetByIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bookmarked questions: %w", err)
+	}
+
+	if len(questions) == 0 {
+		return nil, models.NotFoundError("no bookmarked questions found")
+	}
+
+	if req.Limit > 0 && len(questions) > req.Limit {
+		// Pick a random subset so repeated practice covers all bookmarks
+		for i := len(questions) - 1; i > 0; i-- {
+			j, _ := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+			questions[i], questions[j.Int64()] = questions[j.Int64()], questions[i]
+		}
+		questions = questions[:req.Limit]
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	certificateTitleStyle = utils.PDFTextStyle{Size: 26, Bold: true, Center: true}
+	certificateNameStyle  = utils.PDFTextStyle{Size: 22, Bold: true, Center: true}
+	certificateTextStyle  = utils.PDFTextStyle{Size: 12, Center: true}
+	certificateCodeStyle  = utils.PDFTextStyle{Size: 11, Bold: true, Center: true}
+	certificateHintStyle  = utils.PDFTextStyle{Size: 9, Center: true}
+)
+
+// certificateQRSize is the printed width of the verification QR code, in points
+const certificateQRSize = 150.0
+
+type CertificateService interface {
+	GetCertificate(ctx context.Context, userID, resultID primitive.ObjectID, defaultVerifyURL string) (*models.IssuedCertificate, error)
+	VerifyCertificate(ctx context.Context, code string) (*models.CertificateVerification, error)
+}
+
+type certificateService struct {
+	certificateRepo repository.CertificateRepository
+	sessionRepo     repository.QuizSessionRepository
+	userRepo        repository.UserRepository
+	examRepo        repository.ExamRepository
+	settings        SettingsService
+	config          models.CertificateConfig
+}
+
+func NewCertificateService(
+	certificateRepo repository.CertificateRepository,
+	sessionRepo repository.QuizSessionRepository,
+	userRepo repository.UserRepository,
+	examRepo repository.ExamRepository,
+	settings SettingsService,
+	config models.CertificateConfig,
+	jwtSecret string,
+) CertificateService {
+	if config.SigningKey == "" {
+		config.SigningKey = utils.DeriveEncryptionKey(jwtSecret, "certificates")
+	}
+	return &certificateService{
+		certificateRepo: certificateRepo,
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		examRepo:        examRepo,
+		settings:        settings,
+		config:          config,
+	}
+}
+
+// GetCertificate renders the certificate of one of the user's mock test results, issuing it into the
+// registry the first time. Results qualify once released, fully graded and at or above the passing score.
+// defaultVerifyURL is where the QR code points when no verification page is configured.
+func (s *certificateService) GetCertificate(ctx context.Context, userID, resultID primitive.ObjectID, defaultVerifyURL string) (*models.IssuedCertificate, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if !result.BelongsTo(userID) {
+		return nil, repository.ErrResultNotFound
+	}
+
+	certificate, err := s.certificateRepo.GetByResultID(ctx, resultID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrCertificateNotFound) {
+			return nil, fmt.Errorf("failed to get certificate: %w", err)
+		}
+		certificate, err = s.issue(ctx, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	verifyURL := s.verifyURL(certificate.Code, defaultVerifyURL)
+	content, err := renderCertificate(certificate, verifyURL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.IssuedCertificate{
+		Certificate: certificate,
+		VerifyURL:   verifyURL,
+		Content:     content,
+	}, nil
+}
+
+// issue registers a certificate for a result that has earned one
+func (s *certificateService) issue(ctx context.Context, result *models.DetailedQuizResult) (*models.Certificate, error) {
+	if result.QuizType != models.MockTest {
+		return nil, models.BadRequestError("certificates are only issued for mock tests")
+	}
+	if withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		return nil, ErrResultsNotReleased
+	}
+	if result.PendingEssays > 0 {
+		return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeResultsPending, "result is still being graded")
+	}
+	passingScore := s.settings.Current().CertificatePassingScore
+	if result.ScorePercentage < passingScore {
+		return nil, models.ConflictError("score is below the passing threshold")
+	}
+
+	holderName := ""
+	if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, result.UserID); err == nil {
+		holderName = mahasiswa.FullName
+	} else if user, err := s.userRepo.GetByID(ctx, result.UserID); err == nil {
+		holderName = user.FullName
+	}
+	holderName = strings.TrimSpace(holderName)
+	if holderName == "" {
+		return nil, models.BadRequestError("account has no name to print on the certificate")
+	}
+
+	code, err := utils.GenerateCertificateCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate code: %w", err)
+	}
+
+	certificate := &models.Certificate{
+		Code:            code,
+		ResultID:        result.ID,
+		UserID:          result.UserID,
+		HolderName:      holderName,
+		QuizType:        result.QuizType,
+		ScorePercentage: result.ScorePercentage,
+		PassingScore:    passingScore,
+		CompletedAt:     result.CompletedAt,
+		IssuedAt:        time.Now().Truncate(time.Second),
+	}
+	certificate.Signature = utils.SignCertificate(s.config.SigningKey, certificateFields(certificate)...)
+
+	if err := s.certificateRepo.Create(ctx, certificate); err != nil {
+		// Another download issued it first
+		if errors.Is(err, repository.ErrCertificateIssued) {
+			return s.certificateRepo.GetByResultID(ctx, result.ID)
+		}
+		return nil, fmt.Errorf("failed to issue certificate: %w", err)
+	}
+	return certificate, nil
+}
+
+// VerifyCertificate looks a certificate up by its printed code and checks its registry entry is intact
+func (s *certificateService) VerifyCertificate(ctx context.Context, code string) (*models.CertificateVerification, error) {
+	certificate, err := s.certificateRepo.GetByCode(ctx, strings.ToLower(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CertificateVerification{
+		Valid:           utils.VerifyCertificateSignature(s.config.SigningKey, certificate.Signature, certificateFields(certificate)...),
+		Code:            certificate.Code,
+		HolderName:      certificate.HolderName,
+		QuizType:        certificate.QuizType,
+		ScorePercentage: certificate.ScorePercentage,
+		PassingScore:    certificate.PassingScore,
+		CompletedAt:     certificate.CompletedAt,
+		IssuedAt:        certificate.IssuedAt,
+	}, nil
+}
+
+func (s *certificateService) verifyURL(code, defaultVerifyURL string) string {
+	base := s.config.VerifyURL
+	if base == "" {
+		base = defaultVerifyURL
+	}
+	return strings.TrimRight(base, "/") + "/" + code
+}
+
+// certificateFields are the signed fields of a certificate. Times are signed to the second, since
+// the database keeps only milliseconds.
+func certificateFields(c *models.Certificate) []string {
+	return []string{
+		c.Code,
+		c.ResultID.Hex(),
+		c.UserID.Hex(),
+		c.HolderName,
+		string(c.QuizType),
+		strconv.FormatFloat(c.ScorePercentage, 'f', -1, 64),
+		strconv.FormatFloat(c.PassingScore, 'f', -1, 64),
+		strconv.FormatInt(c.CompletedAt.Unix(), 10),
+		strconv.FormatInt(c.IssuedAt.Unix(), 10),
+	}
+}
+
+// renderCertificate lays out a one-page certificate with a QR code of its verification link
+func renderCertificate(c *models.Certificate, verifyURL string) ([]byte, error) {
+	qr, err := utils.EncodeQRCode(verifyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode verification link: %w", err)
+	}
+
+	quizName := string(c.QuizType)
+	if template := models.DefaultQuizTemplate(c.QuizType); template != nil {
+		quizName = template.Name
+	}
+
+	doc := utils.NewPDFDocument()
+	doc.Space(60)
+	doc.Paragraph("Certificate of Achievement", certificateTitleStyle)
+	doc.Space(36)
+	doc.Paragraph("This certifies that", certificateTextStyle)
+	doc.Space(12)
+	doc.Paragraph(c.HolderName, certificateNameStyle)
+	doc.Space(12)
+	doc.Paragraph(fmt.Sprintf("passed the %s with a score of %.1f%%", quizName, c.ScorePercentage), certificateTextStyle)
+	doc.Paragraph(fmt.Sprintf("on %s", c.CompletedAt.Format("2 January 2006")), certificateTextStyle)
+	doc.Space(48)
+	doc.QRCode(qr, certificateQRSize)
+	doc.Space(8)
+	doc.Paragraph("Certificate code: "+c.Code, certificateCodeStyle)
+	doc.Paragraph("Scan the code or visit the link below to verify this certificate", certificateHintStyle)
+	doc.Paragraph(verifyURL, certificateHintStyle)
+	doc.Paragraph(fmt.Sprintf("Issued %s", c.IssuedAt.Format("2 January 2006")), certificateHintStyle)
+
+	return doc.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ChangelogService interface {
+	// Admin management
+	CreateEntry(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateChangelogEntryRequest) (*models.ChangelogEntry, error)
+	ListEntries(ctx context.Context, req *models.ListChangelogRequest) (*models.ListChangelogResponse, error)
+	GetEntry(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error)
+	UpdateEntry(ctx context.Context, id primitive.ObjectID, req *models.UpdateChangelogEntryRequest) (*models.ChangelogEntry, error)
+	DeleteEntry(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error)
+
+	// Public release notes
+	GetChangelog(ctx context.Context, req *models.ListChangelogRequest) (*models.ListChangelogResponse, error)
+}
+
+type changelogService struct {
+	changelogRepo repository.ChangelogRepository
+}
+
+func NewChangelogService(changelogRepo repository.ChangelogRepository) ChangelogService {
+	return &changelogService{
+		changelogRepo: changelogRepo,
+	}
+}
+
+func (s *changelogService) CreateEntry(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateChangelogEntryRequest) (*models.ChangelogEntry, error) {
+	entry := &models.ChangelogEntry{
+		Version:        strings.TrimSpace(req.Version),
+		Title:          strings.TrimSpace(req.Title),
+		Body:           req.Body,
+		Audiences:      uniqueAudiences(req.Audiences),
+		Published:      req.Publish,
+		CreatedBy:      adminID,
+		CreatedByEmail: adminEmail,
+	}
+	if req.Publish {
+		now := time.Now()
+		entry.PublishedAt = &now
+	}
+
+	if err := s.changelogRepo.Create(ctx, entry); err != nil {
+		if errors.Is(err, repository.ErrChangelogVersionExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create changelog entry: %w", err)
+	}
+	return entry, nil
+}
+
+// ListEntries lists every entry, drafts included, for the admin editor
+func (s *changelogService) ListEntries(ctx context.Context, req *models.ListChangelogRequest) (*models.ListChangelogResponse, error) {
+	return s.list(ctx, req, false)
+}
+
+func (s *changelogService) GetEntry(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error) {
+	return s.changelogRepo.GetByID(ctx, id)
+}
+
+// UpdateEntry edits an entry. The first publication stamps published_at, which is kept when an
+// entry is unpublished to fix it and published again, so clients don't announce it twice.
+func (s *changelogService) UpdateEntry(ctx context.Context, id primitive.ObjectID, req *models.UpdateChangelogEntryRequest) (*models.ChangelogEntry, error) {
+	entry, err := s.changelogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := bson.M{}
+	if req.Version != nil {
+		updates["version"] = strings.TrimSpace(*req.Version)
+	}
+	if req.Title != nil {
+		updates["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.Body != nil {
+		updates["body"] = *req.Body
+	}
+	if req.Audiences != nil {
+		updates["audiences"] = uniqueAudiences(req.Audiences)
+	}
+	if req.Published != nil {
+		updates["published"] = *req.Published
+		if *req.Published && entry.PublishedAt == nil {
+			updates["published_at"] = time.Now()
+		}
+	}
+
+	if len(updates) == 0 {
+		return entry, nil
+	}
+
+	updated, err := s.changelogRepo.Update(ctx, id, updates)
+	if err != nil {
+		if errors.Is(err, repository.ErrChangelogEntryNotFound) || errors.Is(err, repository.ErrChangelogVersionExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update changelog entry: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteEntry removes the entry and returns what was deleted, for the audit log
+func (s *changelogService) DeleteEntry(ctx context.Context, id primitive.ObjectID) (*models.ChangelogEntry, error) {
+	entry, err := s.changelogRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.changelogRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetChangelog lists published entries for the request's audience, which must be set, along
+// with the newest version that audience can see
+func (s *changelogService) GetChangelog(ctx context.Context, req *models.ListChangelogRequest) (*models.ListChangelogResponse, error) {
+	response, err := s.list(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.changelogRepo.GetLatestPublished(ctx, req.Audience)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest changelog entry: %w", err)
+	}
+	if latest != nil {
+		response.LatestVersion = latest.Version
+	}
+	return response, nil
+}
+
+// Private helper methods
+
+func (s *changelogService) list(ctx context.Context, req *models.ListChangelogRequest, publishedOnly bool) (*models.ListChangelogResponse, error) {
+	entries, total, err := s.changelogRepo.List(ctx, req, publishedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changelog entries: %w", err)
+	}
+
+	if entries == nil {
+		entries = []models.ChangelogEntry{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListChangelogResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func uniqueAudiences(audiences []models.ChangelogAudience) []models.ChangelogAudience {
+	seen := make(map[models.ChangelogAudience]bool, len(audiences))
+	unique := make([]models.ChangelogAudience, 0, len(audiences))
+	for _, audience := range audiences {
+		if !seen[audience] {
+			seen[audience] = true
+			unique = append(unique, audience)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultClassQuizJoinWindow applies when the instructor doesn't choose one
+const defaultClassQuizJoinWindow = 30 * time.Minute
+
+// Generated join codes are retried this many times if they collide with an existing one
+const joinCodeAttempts = 5
+
+type ClassQuizService interface {
+	CreateClassQuiz(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateClassQuizRequest) (*models.ClassQuiz, error)
+	ListClassQuizzes(ctx context.Context, req *models.ListClassQuizzesRequest) (*models.ListClassQuizzesResponse, error)
+	CloseClassQuiz(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error)
+	GetBoard(ctx context.Context, id primitive.ObjectID) (*models.ClassQuizBoard, error)
+	JoinClassQuiz(ctx context.Context, userID primitive.ObjectID, code string, req *models.JoinClassQuizRequest) (*models.StartQuizResponse, error)
+}
+
+type classQuizService struct {
+	classQuizRepo      repository.ClassQuizRepository
+	questionRepo       repository.QuestionRepository
+	quizSessionService QuizSessionService
+}
+
+func NewClassQuizService(
+	classQuizRepo repository.ClassQuizRepository,
+	questionRepo repository.QuestionRepository,
+	quizSessionService QuizSessionService,
+) ClassQuizService {
+	return &classQuizService{
+		classQuizRepo:      classQuizRepo,
+		questionRepo:       questionRepo,
+		quizSessionService: quizSessionService,
+	}
+}
+
+func (s *classQuizService) CreateClassQuiz(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateClassQuizRequest) (*models.ClassQuiz, error) {
+	questionIDs := make([]primitive.ObjectID, 0, len(req.QuestionIDs))
+	seen := make(map[primitive.ObjectID]bool)
+	for _, idStr := range req.QuestionIDs {
+		id, err := primitive.ObjectIDFromHex(idStr)
+		if err != nil {
+			return nil, models.BadRequestError(fmt.Sprintf("invalid question ID: %s", idStr))
+		}
+		if !seen[id] {
+			seen[id] = true
+			questionIDs = append(questionIDs, id)
+		}
+	}
+
+	questions, err := s.questionRepo.GetByIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+	if len(questions) != len(questionIDs) {
+		return nil, models.BadRequestError("one or more questions not found")
+	}
+	for _, q := range questions {
+		if !q.IsActive {
+			return nil, models.BadRequestError(fmt.Sprintf("question is not active: %s", q.ID.Hex()))
+		}
+	}
+
+	joinWindow := defaultClassQuizJoinWindow
+	if req.JoinWindowMinutes > 0 {
+		joinWindow = time.Duration(req.JoinWindowMinutes) * time.Minute
+	}
+
+	quiz := &models.ClassQuiz{
+		Title:            strings.TrimSpace(req.Title),
+		QuestionIDs:      questionIDs,
+		TimeLimitMinutes: req.TimeLimitMinutes,
+		JoinClosesAt:     time.Now().Add(joinWindow),
+		CreatedBy:        adminID,
+		CreatedByEmail:   adminEmail,
+	}
+
+	for attempt := 0; attempt < joinCodeAttempts; attempt++ {
+		code, err := utils.GenerateJoinCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate join code: %w", err)
+		}
+		quiz.JoinCode = code
+
+		err = s.classQuizRepo.Create(ctx, quiz)
+		if err == nil {
+			return quiz, nil
+		}
+		if !errors.Is(err, repository.ErrJoinCodeInUse) {
+			return nil, fmt.Errorf("failed to create class quiz: %w", err)
+		}
+	}
+
+	return nil, errors.New("failed to generate a unique join code")
+}
+
+func (s *classQuizService) ListClassQuizzes(ctx context.Context, req *models.ListClassQuizzesRequest) (*models.ListClassQuizzesResponse, error) {
+	quizzes, total, err := s.classQuizRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list class quizzes: %w", err)
+	}
+
+	if quizzes == nil {
+		quizzes = []models.ClassQuiz{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListClassQuizzesResponse{
+		Quizzes:    quizzes,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *classQuizService) CloseClassQuiz(ctx context.Context, id primitive.ObjectID) (*models.ClassQuiz, error) {
+	return s.classQuizRepo.Close(ctx, id)
+}
+
+// JoinClassQuiz starts the student's session in the class quiz with the given join code
+func (s *classQuizService) JoinClassQuiz(ctx context.Context, userID primitive.ObjectID, code string, req *models.JoinClassQuizRequest) (*models.StartQuizResponse, error) {
+	quiz, err := s.classQuizRepo.GetByJoinCode(ctx, strings.ToLower(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.classQuizRepo.GetUserSession(ctx, quiz.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing session: %w", err)
+	}
+	if existing != nil && existing.Status != models.QuizInProgress {
+		return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeAttemptsExhausted, "you have already taken this quiz")
+	}
+
+	// Students who joined in time can get back in after the window closes, e.g. on another device
+	if existing == nil && !quiz.IsJoinable(time.Now()) {
+		return nil, models.NewError(http.StatusGone, "class quiz is closed")
+	}
+
+	questions, err := s.questionRepo.GetByIDs(ctx, quiz.QuestionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get questions: %w", err)
+	}
+
+	return s.quizSessionService.StartClassQuiz(ctx, userID, quiz, questions, req)
+}
+
+// GetBoard ranks the students who submitted and lists those still answering (or who left without
+// submitting), with how the class did on each question
+func (s *classQuizService) GetBoard(ctx context.Context, id primitive.ObjectID) (*models.ClassQuizBoard, error) {
+	quiz, err := s.classQuizRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !quiz.IsJoinable(now) {
+		quiz.Status = models.ClassQuizClosed
+	}
+
+	sessions, err := s.classQuizRepo.GetSessions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	sessionIDs := make([]primitive.ObjectID, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+	results, err := s.classQuizRepo.GetResults(ctx, sessionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get results: %w", err)
+	}
+	resultsBySession := make(map[primitive.ObjectID]*models.DetailedQuizResult, len(results))
+	for i := range results {
+		resultsBySession[results[i].SessionID] = &results[i]
+	}
+
+	board := &models.ClassQuizBoard{
+		Quiz:      *quiz,
+		Joined:    len(sessions),
+		Entries:   make([]models.ClassQuizBoardEntry, 0, len(sessions)),
+		Questions: make([]models.ClassQuizQuestionStat, 0, len(quiz.QuestionIDs)),
+		UpdatedAt: now,
+	}
+
+	var submitted, answering []models.ClassQuizBoardEntry
+	questionStats := make(map[primitive.ObjectID]*models.ClassQuizQuestionStat)
+	for _, session := range sessions {
+		entry := models.ClassQuizBoardEntry{
+			DisplayName:    session.DisplayName,
+			Status:         session.Status,
+			TotalQuestions: session.TotalQuestions,
+		}
+
+		result := resultsBySession[session.ID]
+		if result == nil {
+			for _, q := range session.Questions {
+				if q.IsAnswered {
+					entry.AnsweredCount++
+				}
+			}
+			if session.Status == models.QuizInProgress {
+				board.InProgress++
+			}
+			answering = append(answering, entry)
+			continue
+		}
+
+		entry.FinalScore = &result.FinalScore
+		entry.ScorePercentage = &result.ScorePercentage
+		entry.TimeUsedSeconds = &result.TimeUsedSeconds
+		for _, qr := range result.QuestionResults {
+			stat := questionStats[qr.QuestionID]
+			if stat == nil {
+				stat = &models.ClassQuizQuestionStat{QuestionID: qr.QuestionID, Title: qr.Title}
+				questionStats[qr.QuestionID] = stat
+			}
+			if !qr.IsSkipped && qr.UserAnswer != nil {
+				entry.AnsweredCount++
+				stat.Answered++
+			}
+			if qr.IsCorrect {
+				stat.Correct++
+			}
+		}
+		submitted = append(submitted, entry)
+	}
+
+	// Highest score first; the faster student wins a tie
+	sort.SliceStable(submitted, func(i, j int) bool {
+		if *submitted[i].FinalScore != *submitted[j].FinalScore {
+			return *submitted[i].FinalScore > *submitted[j].FinalScore
+		}
+		return *submitted[i].TimeUsedSeconds < *submitted[j].TimeUsedSeconds
+	})
+	for i := range submitted {
+		submitted[i].Rank = i + 1
+	}
+
+	board.Submitted = len(submitted)
+	board.Entries = append(board.Entries, submitted...)
+	board.Entries = append(board.Entries, answering...)
+
+	// Questions in the order the instructor picked them
+	for _, questionID := range quiz.QuestionIDs {
+		stat := questionStats[questionID]
+		if stat == nil {
+			continue
+		}
+		if board.Submitted > 0 {
+			stat.CorrectRate = float64(stat.Correct) / float64(board.Submitted) * 100
+		}
+		board.Questions = append(board.Questions, *stat)
+	}
+
+	return board, nil
+}
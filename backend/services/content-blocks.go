@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend/models"
+	"backend/utils"
+)
+
+// maxBlockLength bounds the text or code of one content block, in characters
+const maxBlockLength = 50000
+
+var (
+	youTubeVideoID  = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoVideoID    = regexp.MustCompile(`^[0-9]+$`)
+	codeLanguageTag = regexp.MustCompile(`^[a-z0-9_+#.-]*$`)
+)
+
+// moduleContent gives the blocks a module or submodule is saved with, and their markdown rendition: the
+// blocks sent, or for clients still sending a content string, that string as one markdown block
+func moduleContent(blocks []models.ContentBlock, content string) ([]models.ContentBlock, string, error) {
+	if len(blocks) == 0 {
+		if strings.TrimSpace(content) == "" {
+			return []models.ContentBlock{}, "", nil
+		}
+		blocks = []models.ContentBlock{{Type: models.BlockMarkdown, Text: content}}
+	}
+	normalized, err := normalizeBlocks(blocks)
+	if err != nil {
+		return nil, "", err
+	}
+	return normalized, models.BlocksMarkdown(normalized), nil
+}
+
+// requiredModuleContent is moduleContent for modules and submodules that must have some content
+func requiredModuleContent(blocks []models.ContentBlock, content string) ([]models.ContentBlock, string, error) {
+	normalized, markdown, err := moduleContent(blocks, content)
+	if err == nil && len(normalized) == 0 {
+		return nil, "", errors.New("content is required")
+	}
+	return normalized, markdown, err
+}
+
+// normalizeBlocks validates content blocks, keeping only the fields each type uses so nothing unchecked
+// is stored alongside them
+func normalizeBlocks(blocks []models.ContentBlock) ([]models.ContentBlock, error) {
+	if len(blocks) > models.MaxContentBlocks {
+		return nil, fmt.Errorf("content can have at most %d blocks", models.MaxContentBlocks)
+	}
+	normalized := make([]models.ContentBlock, len(blocks))
+	for i, block := range blocks {
+		clean, err := normalizeBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i+1, err)
+		}
+		normalized[i] = clean
+	}
+	return normalized, nil
+}
+
+func normalizeBlock(block models.ContentBlock) (models.ContentBlock, error) {
+	switch block.Type {
+	case models.BlockMarkdown:
+		text, err := blockMarkdown(block.Text)
+		if err != nil {
+			return block, err
+		}
+		return models.ContentBlock{Type: block.Type, Text: text}, nil
+
+	case models.BlockImage:
+		imageURL, err := webURL(block.URL)
+		if err != nil {
+			return block, fmt.Errorf("image %w", err)
+		}
+		alt := strings.TrimSpace(block.Alt)
+		if alt == "" {
+			return block, errors.New("image requires alt text")
+		}
+		return models.ContentBlock{Type: block.Type, URL: imageURL, Alt: alt, Caption: strings.TrimSpace(block.Caption)}, nil
+
+	case models.BlockVideo:
+		embedURL, provider, err := videoEmbedURL(block.URL)
+		if err != nil {
+			return block, err
+		}
+		return models.ContentBlock{Type: block.Type, URL: embedURL, Provider: provider, Caption: strings.TrimSpace(block.Caption)}, nil
+
+	case models.BlockCode:
+		code := strings.Trim(block.Code, "\r\n")
+		if strings.TrimSpace(code) == "" {
+			return block, errors.New("code is required")
+		}
+		if utf8.RuneCountInString(code) > maxBlockLength {
+			return block, fmt.Errorf("code is longer than %d characters", maxBlockLength)
+		}
+		// A fence inside the code would end the block early in the markdown rendition
+		if strings.Contains(code, "```") {
+			return block, errors.New("code cannot contain ```")
+		}
+		language := strings.ToLower(strings.TrimSpace(block.Language))
+		if !codeLanguageTag.MatchString(language) {
+			return block, errors.New("invalid code language")
+		}
+		return models.ContentBlock{Type: block.Type, Code: code, Language: language}, nil
+
+	case models.BlockCallout:
+		text, err := blockMarkdown(block.Text)
+		if err != nil {
+			return block, err
+		}
+		variant := block.Variant
+		if variant == "" {
+			variant = models.CalloutInfo
+		}
+		switch variant {
+		case models.CalloutInfo, models.CalloutTip, models.CalloutWarning, models.CalloutDanger:
+		default:
+			return block, errors.New("callout variant must be info, tip, warning or danger")
+		}
+		title := strings.TrimSpace(block.Title)
+		if err := utils.CheckMarkdown(title); err != nil {
+			return block, err
+		}
+		return models.ContentBlock{Type: block.Type, Text: text, Variant: variant, Title: title}, nil
+	}
+	return block, errors.New("invalid block type")
+}
+
+// blockMarkdown checks the markdown of a block; rendering escapes any raw HTML, so only its LaTeX needs
+// checking here
+func blockMarkdown(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("text is required")
+	}
+	if utf8.RuneCountInString(text) > maxBlockLength {
+		return "", fmt.Errorf("text is longer than %d characters", maxBlockLength)
+	}
+	return text, utils.CheckMarkdown(text)
+}
+
+// webURL checks a link is an absolute http or https URL
+func webURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", errors.New("URL must be an http or https link")
+	}
+	return parsed.String(), nil
+}
+
+// videoEmbedURL turns a YouTube or Vimeo link into the URL of its embeddable player, the only kind of
+// page the frontend frames
+func videoEmbedURL(raw string) (embedURL, provider string, err error) {
+	link, err := webURL(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("video %w", err)
+	}
+	parsed, _ := url.Parse(link)
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	path := strings.Trim(parsed.Path, "/")
+
+	var id string
+	switch host {
+	case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+		if path == "watch" {
+			id = parsed.Query().Get("v")
+		} else if rest, ok := strings.CutPrefix(path, "embed/"); ok {
+			id = rest
+		} else if rest, ok := strings.CutPrefix(path, "shorts/"); ok {
+			id = rest
+		}
+		if youTubeVideoID.MatchString(id) {
+			return "https://www.youtube-nocookie.com/embed/" + id, "youtube", nil
+		}
+	case "youtu.be":
+		if youTubeVideoID.MatchString(path) {
+			return "https://www.youtube-nocookie.com/embed/" + path, "youtube", nil
+		}
+	case "vimeo.com", "player.vimeo.com":
+		id = strings.TrimPrefix(path, "video/")
+		if vimeoVideoID.MatchString(id) {
+			return "https://player.vimeo.com/video/" + id, "vimeo", nil
+		}
+	}
+	return "", "", errors.New("video must be a YouTube or Vimeo video link")
+}
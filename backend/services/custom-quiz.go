@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StartCustomQuiz starts a practice quiz from questions matching the student's tags and difficulties.
+// Custom quizzes are recorded under their own quiz type, so they never count as mock tests.
+func (s *quizSessionService) StartCustomQuiz(ctx context.Context, userID primitive.ObjectID, req *models.StartCustomQuizRequest) (*models.StartQuizResponse, error) {
+	if req.IsGuest {
+		return nil, models.NewCodedError(http.StatusForbidden, models.ErrorCodeAccountRequired, "guests can only take time quizzes")
+	}
+
+	tags := normalizeTags(req.Tags)
+	if len(tags) == 0 {
+		return nil, models.BadRequestError("at least one tag is required")
+	}
+
+	// Resume an unfinished custom quiz instead of stacking new ones
+	existingSession, err := s.sessionRepo.GetActiveSessionByUser(ctx, userID, models.CustomQuiz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing session: %w", err)
+	}
+
+	if existingSession != nil {
+		if s.calculateTimeRemaining(existingSession) > 0 {
+			return &models.StartQuizResponse{
+				Session:     *existingSession,
+				Message:     "Resumed existing custom quiz session",
+				ResumeToken: existingSession.SessionToken,
+			}, nil
+		}
+
+		if _, err := s.autoSubmit(ctx, existingSession, ""); err != nil {
+			return nil, fmt.Errorf("failed to submit expired session: %w", err)
+		}
+	}
+
+	session, err := s.newTaggedSession(ctx, userID, models.CustomQuiz, tags, req.Difficulties, req.QuestionCount, 0)
+	if err != nil {
+		return nil, err
+	}
+	session.CustomFilters = &models.CustomQuizFilters{
+		Tags:          tags,
+		Difficulties:  req.Difficulties,
+		QuestionCount: req.QuestionCount,
+	}
+
+	err = s.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	message := "Custom quiz started successfully"
+	if session.TotalQuestions < req.QuestionCount {
+		message = fmt.Sprintf("Custom quiz started with the %d matching questions available", session.TotalQuestions)
+	}
+	return &models.StartQuizResponse{
+		Session:     *session,
+		Message:     message,
+		ResumeToken: session.SessionToken,
+	}, nil
+}
+
+// StartWidgetQuiz starts an anonymous attempt at an embed widget's quiz. Each attempt gets a new
+// visitor ID, so widget visitors never share sessions or results with each other.
+func (s *quizSessionService) StartWidgetQuiz(ctx context.Context, widget *models.EmbedWidget) (*models.StartQuizResponse, error) {
+	session, err := s.newTaggedSession(ctx, primitive.NewObjectID(), models.EmbedQuiz, widget.Tags, widget.Difficulties, widget.QuestionCount, widget.TimeLimitMinutes)
+	if err != nil {
+		return nil, err
+	}
+	session.EmbedWidgetID = &widget.ID
+
+	err = s.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &models.StartQuizResponse{
+		Session:     *session,
+		Message:     "Quiz started successfully",
+		ResumeToken: session.SessionToken,
+	}, nil
+}
+
+// newTaggedSession draws up to count active questions carrying any of the tags and builds an
+// unsaved session of the quiz type from them. A zero time limit allows a fixed time per question drawn.
+func (s *quizSessionService) newTaggedSession(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, tags []string, difficulties []models.DifficultyLevel, count, timeLimitMinutes int) (*models.QuizSession, error) {
+	questions, err := s.questionRepo.GetRandomQuestionsByTags(ctx, tags, difficulties, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select questions: %w", err)
+	}
+	if len(questions) == 0 {
+		return nil, models.NotFoundError("no questions match the chosen tags")
+	}
+
+	template := models.DefaultQuizTemplate(quizType)
+	template.TimeLimitMinutes = timeLimitMinutes
+	if template.TimeLimitMinutes == 0 {
+		template.TimeLimitMinutes = len(questions) * s.settings.Current().CustomQuizMinutesPerQuestion
+	}
+
+	var sessionQuestions []models.SessionQuestion
+	totalPoints := 0
+	for _, q := range questions {
+		sessionQuestion := s.convertQuestionToSessionQuestion(q)
+		sessionQuestion.Points = template.Scoring.PointsFor(q)
+		totalPoints += sessionQuestion.Points
+		sessionQuestions = append(sessionQuestions, sessionQuestion)
+	}
+	s.shuffleSessionQuestions(sessionQuestions)
+
+	sessionToken, err := generateSessionToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	return &models.QuizSession{
+		UserID:           userID,
+		QuizType:         quizType,
+		SessionToken:     sessionToken,
+		TotalQuestions:   len(sessionQuestions),
+		MaxPoints:        totalPoints,
+		TimeLimitMinutes: template.TimeLimitMinutes,
+		Questions:        sessionQuestions,
+		StartTime:        time.Now(),
+		TimeRemaining:    int64(template.TimeLimitMinutes * 60),
+		TemplateName:     template.Name,
+		Scoring:          &template.Scoring,
+		Status:           models.QuizInProgress,
+	}, nil
+}
+
+// ListCustomQuizTags lists the tags students can build custom quizzes from
+func (s *quizSessionService) ListCustomQuizTags(ctx context.Context) ([]models.QuestionTagCount, error) {
+	tags, err := s.questionRepo.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DifficultyRecalibrationService interface {
+	Recalibrate(ctx context.Context) (*models.RecalibrationResult, error)
+
+	// Suggestion review
+	ListSuggestions(ctx context.Context, req *models.ListDifficultySuggestionsRequest) (*models.ListDifficultySuggestionsResponse, error)
+	ReviewSuggestion(ctx context.Context, reviewerID, suggestionID primitive.ObjectID, req *models.ReviewDifficultySuggestionRequest) (*models.DifficultySuggestion, error)
+}
+
+type difficultyRecalibrationService struct {
+	suggestionRepo     repository.DifficultySuggestionRepository
+	analyticsRepo      repository.AnalyticsRepository
+	questionRepo       repository.QuestionRepository
+	activityLogService ActivityLogService
+	config             models.RecalibrationConfig
+	cache              *utils.Cache
+
+	mu      sync.Mutex
+	lastRun *models.RecalibrationResult
+
+	// Held for the length of a run so scheduled and manual runs do not overlap
+	runMu sync.Mutex
+}
+
+func NewDifficultyRecalibrationService(suggestionRepo repository.DifficultySuggestionRepository, analyticsRepo repository.AnalyticsRepository, questionRepo repository.QuestionRepository, activityLogService ActivityLogService, config models.RecalibrationConfig, cache *utils.Cache) DifficultyRecalibrationService {
+	return &difficultyRecalibrationService{
+		suggestionRepo:     suggestionRepo,
+		analyticsRepo:      analyticsRepo,
+		questionRepo:       questionRepo,
+		activityLogService: activityLogService,
+		config:             config,
+		cache:              cache,
+	}
+}
+
+// Recalibrate compares the labeled difficulty of every active question with enough recent attempts
+// against how often it was answered correctly. In auto mode mislabeled questions are relabeled on the
+// spot; otherwise a suggestion is queued for an admin, unless the same change was rejected before.
+func (s *difficultyRecalibrationService) Recalibrate(ctx context.Context) (*models.RecalibrationResult, error) {
+	if !s.runMu.TryLock() {
+		return nil, models.ConflictError("difficulty recalibration is already running")
+	}
+	defer s.runMu.Unlock()
+
+	start := time.Now()
+	rows, err := s.analyticsRepo.GetQuestionCorrectRates(ctx, start.AddDate(0, 0, -s.config.LookbackDays), max(s.config.MinAttempts, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	questionIDs := make([]primitive.ObjectID, len(rows))
+	for i, row := range rows {
+		questionIDs[i] = row.QuestionID
+	}
+	questions, err := s.questionRepo.GetByIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load questions: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	auto := s.config.Mode == models.RecalibrationModeAuto
+	result := &models.RecalibrationResult{
+		Mode:    models.RecalibrationModeSuggest,
+		Changes: []models.DifficultyChange{},
+		RanAt:   start,
+	}
+	if auto {
+		result.Mode = models.RecalibrationModeAuto
+	}
+
+	for _, row := range rows {
+		q, ok := byID[row.QuestionID]
+		if !ok {
+			continue
+		}
+		result.Analyzed++
+
+		rate := float64(row.Correct) / float64(row.Attempts)
+		observed := recalibratedDifficulty(q.Difficulty, rate, s.config)
+		if observed == q.Difficulty {
+			// A pending suggestion the latest attempts no longer support is withdrawn
+			if err := s.suggestionRepo.DeletePending(ctx, q.ID); err != nil {
+				return nil, fmt.Errorf("failed to withdraw difficulty suggestion: %w", err)
+			}
+			continue
+		}
+
+		change := models.DifficultyChange{
+			QuestionID:  q.ID,
+			Title:       q.Title,
+			From:        q.Difficulty,
+			To:          observed,
+			CorrectRate: roundIndex(rate),
+			Attempts:    row.Attempts,
+		}
+
+		if auto {
+			if err := s.questionRepo.Update(ctx, q.ID, bson.M{"difficulty": observed}); err != nil {
+				return nil, fmt.Errorf("failed to update question difficulty: %w", err)
+			}
+			s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+			if err := s.suggestionRepo.DeletePending(ctx, q.ID); err != nil {
+				return nil, fmt.Errorf("failed to withdraw difficulty suggestion: %w", err)
+			}
+			s.logAutoChange(change)
+			result.Adjusted++
+		} else {
+			rejected, err := s.suggestionRepo.WasRejected(ctx, q.ID, q.Difficulty, observed)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check rejected suggestions: %w", err)
+			}
+			if rejected {
+				continue
+			}
+			err = s.suggestionRepo.UpsertPending(ctx, &models.DifficultySuggestion{
+				QuestionID:          q.ID,
+				QuestionTitle:       q.Title,
+				CurrentDifficulty:   q.Difficulty,
+				SuggestedDifficulty: observed,
+				CorrectRate:         change.CorrectRate,
+				Attempts:            row.Attempts,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to queue difficulty suggestion: %w", err)
+			}
+			result.Suggested++
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	s.mu.Lock()
+	s.lastRun = result
+	s.mu.Unlock()
+
+	return result, nil
+}
+
+func (s *difficultyRecalibrationService) ListSuggestions(ctx context.Context, req *models.ListDifficultySuggestionsRequest) (*models.ListDifficultySuggestionsResponse, error) {
+	suggestions, total, err := s.suggestionRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list difficulty suggestions: %w", err)
+	}
+	pending, err := s.suggestionRepo.CountPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending difficulty suggestions: %w", err)
+	}
+	if suggestions == nil {
+		suggestions = []models.DifficultySuggestion{}
+	}
+
+	s.mu.Lock()
+	lastRun := s.lastRun
+	s.mu.Unlock()
+
+	return &models.ListDifficultySuggestionsResponse{
+		Suggestions:  suggestions,
+		Total:        total,
+		PendingCount: pending,
+		Page:         req.Page,
+		Limit:        req.Limit,
+		TotalPages:   int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+		LastRun:      lastRun,
+	}, nil
+}
+
+// ReviewSuggestion approves a suggestion, relabeling the question, or rejects it so the same change
+// is not suggested again
+func (s *difficultyRecalibrationService) ReviewSuggestion(ctx context.Context, reviewerID, suggestionID primitive.ObjectID, req *models.ReviewDifficultySuggestionRequest) (*models.DifficultySuggestion, error) {
+	if err := s.suggestionRepo.ClaimForReview(ctx, suggestionID, reviewerID, req.Status, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	suggestion, err := s.suggestionRepo.GetByID(ctx, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if suggestion.Status == models.SuggestionStatusApproved {
+		if err := s.questionRepo.Update(ctx, suggestion.QuestionID, bson.M{"difficulty": suggestion.SuggestedDifficulty}); err != nil {
+			if releaseErr := s.suggestionRepo.ReleaseReview(ctx, suggestionID); releaseErr != nil {
+				slog.ErrorContext(ctx, "Failed to release difficulty suggestion", "suggestion_id", suggestionID.Hex(), "error", releaseErr)
+			}
+			if errors.Is(err, repository.ErrQuestionNotFound) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to update question difficulty: %w", err)
+		}
+		s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+	}
+
+	return suggestion, nil
+}
+
+// logAutoChange records a relabel the job made on its own, attributed to the system
+func (s *difficultyRecalibrationService) logAutoChange(change models.DifficultyChange) {
+	activityLog := models.NewActivityLog(
+		models.ActivityQuestionDifficultyRecalibrated,
+		"Recalibrated question difficulty",
+		"question",
+		change.QuestionID.Hex(),
+		change.Title,
+		primitive.NilObjectID,
+		"Difficulty recalibration",
+		"system",
+	).SetDetails("from", change.From).
+		SetDetails("to", change.To).
+		SetDetails("correct_rate", change.CorrectRate).
+		SetDetails("attempts", change.Attempts).
+		SetDetails("mode", models.RecalibrationModeAuto)
+	s.activityLogService.LogActivityAsync(activityLog)
+}
+
+// recalibratedDifficulty maps a correct rate to the difficulty it suggests. The rate is first moved
+// back toward the current label by the margin, so a question just past a boundary keeps its label
+// and does not flip between runs.
+func recalibratedDifficulty(current models.DifficultyLevel, rate float64, cfg models.RecalibrationConfig) models.DifficultyLevel {
+	observed := difficultyForRate(rate, cfg)
+	if observed == current {
+		return current
+	}
+	if difficultyRank(observed) < difficultyRank(current) {
+		return difficultyForRate(rate-cfg.Margin, cfg)
+	}
+	return difficultyForRate(rate+cfg.Margin, cfg)
+}
+
+func difficultyForRate(rate float64, cfg models.RecalibrationConfig) models.DifficultyLevel {
+	switch {
+	case rate >= cfg.EasyRate:
+		return models.Easy
+	case rate < cfg.HardRate:
+		return models.Hard
+	default:
+		return models.Medium
+	}
+}
+
+func difficultyRank(difficulty models.DifficultyLevel) int {
+	switch difficulty {
+	case models.Easy:
+		return 0
+	case models.Hard:
+		return 2
+	default:
+		return 1
+	}
+}
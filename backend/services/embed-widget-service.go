@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmbedWidgetService interface {
+	// Admin lifecycle
+	CreateWidget(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateEmbedWidgetRequest) (*models.EmbedWidget, error)
+	ListWidgets(ctx context.Context, req *models.ListEmbedWidgetsRequest) (*models.ListEmbedWidgetsResponse, error)
+	GetWidget(ctx context.Context, id primitive.ObjectID) (*models.EmbedWidget, error)
+	UpdateWidget(ctx context.Context, id primitive.ObjectID, req *models.UpdateEmbedWidgetRequest) (*models.EmbedWidget, error)
+	RevokeWidget(ctx context.Context, adminID, id primitive.ObjectID) (*models.EmbedWidget, error)
+
+	// Widget API, limited to the widget's own quiz and sessions
+	Authenticate(ctx context.Context, token string) (*models.EmbedWidget, error)
+	Config(widget *models.EmbedWidget) *models.WidgetConfig
+	StartSession(ctx context.Context, widget *models.EmbedWidget) (*models.StartQuizResponse, error)
+	GetSession(ctx context.Context, widget *models.EmbedWidget, sessionToken string) (*models.GetSessionResponse, error)
+	SaveAnswer(ctx context.Context, widget *models.EmbedWidget, sessionToken string, req *models.SaveAnswerRequest) (*models.SaveAnswerResponse, error)
+	Submit(ctx context.Context, widget *models.EmbedWidget, sessionToken string) (*models.SubmitQuizResponse, error)
+}
+
+type embedWidgetService struct {
+	widgetRepo         repository.EmbedWidgetRepository
+	sessionRepo        repository.QuizSessionRepository
+	quizSessionService QuizSessionService
+	settings           SettingsService
+}
+
+func NewEmbedWidgetService(widgetRepo repository.EmbedWidgetRepository, sessionRepo repository.QuizSessionRepository, quizSessionService QuizSessionService, settings SettingsService) EmbedWidgetService {
+	return &embedWidgetService{
+		widgetRepo:         widgetRepo,
+		sessionRepo:        sessionRepo,
+		quizSessionService: quizSessionService,
+		settings:           settings,
+	}
+}
+
+func (s *embedWidgetService) CreateWidget(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateEmbedWidgetRequest) (*models.EmbedWidget, error) {
+	origins, err := normalizeOrigins(req.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(18)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate widget token: %w", err)
+	}
+
+	widget := &models.EmbedWidget{
+		Name:             strings.TrimSpace(req.Name),
+		Token:            "emb_" + token,
+		AllowedOrigins:   origins,
+		Tags:             normalizeTags(req.Tags),
+		Difficulties:     req.Difficulties,
+		QuestionCount:    req.QuestionCount,
+		TimeLimitMinutes: req.TimeLimitMinutes,
+		CreatedBy:        adminID,
+		CreatedByEmail:   adminEmail,
+	}
+
+	if err := s.widgetRepo.Create(ctx, widget); err != nil {
+		return nil, fmt.Errorf("failed to create embed widget: %w", err)
+	}
+	return widget, nil
+}
+
+func (s *embedWidgetService) ListWidgets(ctx context.Context, req *models.ListEmbedWidgetsRequest) (*models.ListEmbedWidgetsResponse, error) {
+	widgets, total, err := s.widgetRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embed widgets: %w", err)
+	}
+
+	if widgets == nil {
+		widgets = []models.EmbedWidget{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListEmbedWidgetsResponse{
+		Widgets:    widgets,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *embedWidgetService) GetWidget(ctx context.Context, id primitive.ObjectID) (*models.EmbedWidget, error) {
+	return s.widgetRepo.GetByID(ctx, id)
+}
+
+func (s *embedWidgetService) UpdateWidget(ctx context.Context, id primitive.ObjectID, req *models.UpdateEmbedWidgetRequest) (*models.EmbedWidget, error) {
+	existing, err := s.widgetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == models.EmbedWidgetRevoked {
+		return nil, models.ConflictError("revoked embed widgets cannot be changed")
+	}
+
+	updates := bson.M{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.AllowedOrigins != nil {
+		origins, err := normalizeOrigins(req.AllowedOrigins)
+		if err != nil {
+			return nil, err
+		}
+		updates["allowed_origins"] = origins
+	}
+	if req.Tags != nil {
+		updates["tags"] = normalizeTags(req.Tags)
+	}
+	if req.Difficulties != nil {
+		updates["difficulties"] = req.Difficulties
+	}
+	if req.QuestionCount != nil {
+		updates["question_count"] = *req.QuestionCount
+	}
+	if req.TimeLimitMinutes != nil {
+		updates["time_limit_minutes"] = *req.TimeLimitMinutes
+	}
+
+	if len(updates) == 0 {
+		return existing, nil
+	}
+
+	return s.widgetRepo.Update(ctx, id, updates)
+}
+
+func (s *embedWidgetService) RevokeWidget(ctx context.Context, adminID, id primitive.ObjectID) (*models.EmbedWidget, error) {
+	return s.widgetRepo.Revoke(ctx, id, adminID)
+}
+
+// Authenticate resolves the widget an embed token belongs to, refusing revoked widgets
+func (s *embedWidgetService) Authenticate(ctx context.Context, token string) (*models.EmbedWidget, error) {
+	widget, err := s.widgetRepo.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if widget.Status != models.EmbedWidgetActive {
+		return nil, models.NotFoundError("embed widget has been revoked")
+	}
+	return widget, nil
+}
+
+func (s *embedWidgetService) Config(widget *models.EmbedWidget) *models.WidgetConfig {
+	timeLimit := widget.TimeLimitMinutes
+	if timeLimit == 0 {
+		timeLimit = widget.QuestionCount * s.settings.Current().CustomQuizMinutesPerQuestion
+	}
+	return &models.WidgetConfig{
+		Name:             widget.Name,
+		Tags:             widget.Tags,
+		Difficulties:     widget.Difficulties,
+		QuestionCount:    widget.QuestionCount,
+		TimeLimitMinutes: timeLimit,
+	}
+}
+
+func (s *embedWidgetService) StartSession(ctx context.Context, widget *models.EmbedWidget) (*models.StartQuizResponse, error) {
+	response, err := s.quizSessionService.StartWidgetQuiz(ctx, widget)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usage counts are informational, so a failed update does not fail the attempt
+	_ = s.widgetRepo.RecordSession(ctx, widget.ID, response.Session.StartTime)
+	return response, nil
+}
+
+func (s *embedWidgetService) GetSession(ctx context.Context, widget *models.EmbedWidget, sessionToken string) (*models.GetSessionResponse, error) {
+	if _, err := s.widgetSession(ctx, widget, sessionToken); err != nil {
+		return nil, err
+	}
+	return s.quizSessionService.GetSession(ctx, sessionToken)
+}
+
+func (s *embedWidgetService) SaveAnswer(ctx context.Context, widget *models.EmbedWidget, sessionToken string, req *models.SaveAnswerRequest) (*models.SaveAnswerResponse, error) {
+	if _, err := s.widgetSession(ctx, widget, sessionToken); err != nil {
+		return nil, err
+	}
+	return s.quizSessionService.SaveAnswer(ctx, sessionToken, req)
+}
+
+func (s *embedWidgetService) Submit(ctx context.Context, widget *models.EmbedWidget, sessionToken string) (*models.SubmitQuizResponse, error) {
+	session, err := s.widgetSession(ctx, widget, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.quizSessionService.SubmitQuiz(ctx, session.UserID, sessionToken)
+}
+
+// widgetSession loads a session started through the widget. Sessions from anywhere else are
+// reported as missing, so a widget token cannot be used to reach students' own quizzes.
+func (s *embedWidgetService) widgetSession(ctx context.Context, widget *models.EmbedWidget, sessionToken string) (*models.QuizSession, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if session.EmbedWidgetID == nil || *session.EmbedWidgetID != widget.ID {
+		return nil, repository.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// normalizeOrigins reduces each allowed origin to its scheme and host, dropping duplicates.
+// Only http and https origins are accepted, since those are the only sites that can frame the widget.
+func normalizeOrigins(origins []string) ([]string, error) {
+	seen := make(map[string]bool, len(origins))
+	normalized := make([]string, 0, len(origins))
+	for _, raw := range origins {
+		parsed, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.User != nil || strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+			return nil, models.BadRequestError(fmt.Sprintf("invalid origin: %s", raw))
+		}
+
+		origin := strings.ToLower(parsed.Scheme + "://" + parsed.Host)
+		if !seen[origin] {
+			seen[origin] = true
+			normalized = append(normalized, origin)
+		}
+	}
+	return normalized, nil
+}
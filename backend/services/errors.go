@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+
+	"backend/models"
+)
+
+// ErrorStatus finds the status and code an error a service returned carries, through any wrapping.
+// Errors without one are unexpected and left to the caller to answer.
+func ErrorStatus(err error) (*models.Error, bool) {
+	var serviceErr *models.Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr, true
+	}
+	return nil, false
+}
+
+// ErrorCode is the code clients tell an error a service returned apart by, or "" when it has none of
+// its own. Errors wrapped with more context keep their code.
+func ErrorCode(err error) models.ErrorCode {
+	if serviceErr, ok := ErrorStatus(err); ok {
+		return serviceErr.Code
+	}
+	return ""
+}
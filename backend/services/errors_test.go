@@ -0,0 +1,43 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int // Zero when the error carries no status
+		code   models.ErrorCode
+	}{
+		{"sentinel", repository.ErrSittingNotFound, http.StatusNotFound, models.ErrorCodeNotFound},
+		{"coded sentinel", ErrSessionPaused, http.StatusConflict, models.ErrorCodeSessionPaused},
+		{"wrapped", fmt.Errorf("loading result: %w", ErrResultsNotReleased), http.StatusForbidden, models.ErrorCodeResultsPending},
+		{"inline", models.BadRequestError("invalid cursor"), http.StatusBadRequest, models.ErrorCodeBadRequest},
+		{"cooldown", models.NewRetryError(models.ErrorCodeRetakeCooldown, "quiz cannot be retaken yet", time.Now()), http.StatusTooManyRequests, models.ErrorCodeRetakeCooldown},
+		{"plain", errors.New("failed to connect"), 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serviceErr, ok := ErrorStatus(tt.err)
+			if ok != (tt.status != 0) {
+				t.Fatalf("ErrorStatus(%v) ok = %v, want %v", tt.err, ok, tt.status != 0)
+			}
+			if ok && serviceErr.Status != tt.status {
+				t.Errorf("ErrorStatus(%v) status = %d, want %d", tt.err, serviceErr.Status, tt.status)
+			}
+			if got := ErrorCode(tt.err); got != tt.code {
+				t.Errorf("ErrorCode(%v) = %q, want %q", tt.err, got, tt.code)
+			}
+		})
+	}
+}
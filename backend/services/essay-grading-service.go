@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EssayGradingService interface {
+	ListPendingEssays(ctx context.Context, req *models.ListPendingGradingRequest) (*models.ListPendingGradingResponse, error)
+	GradeEssay(ctx context.Context, adminID primitive.ObjectID, adminName string, resultID primitive.ObjectID, questionIndex int, req *models.GradeEssayRequest) (*models.GradeEssayResponse, error)
+}
+
+type essayGradingService struct {
+	sessionRepo      repository.QuizSessionRepository
+	questionRepo     repository.QuestionRepository
+	userRepo         repository.UserRepository
+	userActivityRepo repository.UserActivityRepository
+	examRepo         repository.ExamRepository
+	notificationRepo repository.NotificationRepository
+	resultWebhooks   ResultWebhookService
+}
+
+func NewEssayGradingService(
+	sessionRepo repository.QuizSessionRepository,
+	questionRepo repository.QuestionRepository,
+	userRepo repository.UserRepository,
+	userActivityRepo repository.UserActivityRepository,
+	examRepo repository.ExamRepository,
+	notificationRepo repository.NotificationRepository,
+	resultWebhooks ResultWebhookService,
+) EssayGradingService {
+	return &essayGradingService{
+		sessionRepo:      sessionRepo,
+		questionRepo:     questionRepo,
+		userRepo:         userRepo,
+		userActivityRepo: userActivityRepo,
+		examRepo:         examRepo,
+		notificationRepo: notificationRepo,
+		resultWebhooks:   resultWebhooks,
+	}
+}
+
+// ListPendingEssays returns the grading queue with each essay's sample answer and student
+func (s *essayGradingService) ListPendingEssays(ctx context.Context, req *models.ListPendingGradingRequest) (*models.ListPendingGradingResponse, error) {
+	var sittingID *primitive.ObjectID
+	if req.SittingID != "" {
+		id, err := primitive.ObjectIDFromHex(req.SittingID)
+		if err != nil {
+			return nil, models.BadRequestError("invalid sitting ID")
+		}
+		sittingID = &id
+	}
+
+	essays, total, err := s.sessionRepo.ListPendingEssays(ctx, req, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionIDs := make([]primitive.ObjectID, 0, len(essays))
+	for _, essay := range essays {
+		questionIDs = append(questionIDs, essay.QuestionID)
+	}
+	sampleAnswers := make(map[primitive.ObjectID]string, len(questionIDs))
+	if len(questionIDs) > 0 {
+		questions, err := s.questionRepo.GetByIDs(ctx, questionIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get questions: %w", err)
+		}
+		for _, q := range questions {
+			sampleAnswers[q.ID] = q.SampleAnswer
+		}
+	}
+
+	type student struct{ fullName, email string }
+	students := make(map[primitive.ObjectID]student)
+	for i := range essays {
+		essay := &essays[i]
+		essay.SampleAnswer = sampleAnswers[essay.QuestionID]
+
+		st, ok := students[essay.UserID]
+		if !ok {
+			if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, essay.UserID); err == nil {
+				st = student{mahasiswa.FullName, mahasiswa.Email}
+			} else if user, err := s.userRepo.GetByID(ctx, essay.UserID); err == nil {
+				st = student{user.FullName, user.Email}
+			}
+			students[essay.UserID] = st
+		}
+		essay.FullName, essay.Email = st.fullName, st.email
+	}
+
+	if essays == nil {
+		essays = []models.PendingEssay{}
+	}
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListPendingGradingResponse{
+		Essays:     essays,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GradeEssay scores an essay answer. Grading the result's last pending essay recomputes its
+// scores and tells the student; regrading an essay afterwards recomputes them again.
+func (s *essayGradingService) GradeEssay(ctx context.Context, adminID primitive.ObjectID, adminName string, resultID primitive.ObjectID, questionIndex int, req *models.GradeEssayRequest) (*models.GradeEssayResponse, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if questionIndex < 0 || questionIndex >= len(result.QuestionResults) {
+		return nil, models.BadRequestError("invalid question index")
+	}
+	qr := result.QuestionResults[questionIndex]
+	if qr.Type != models.Essay {
+		return nil, models.BadRequestError("question is not an essay")
+	}
+	if !qr.PendingGrade && qr.EssayGrade == nil {
+		return nil, models.BadRequestError("essay was not answered")
+	}
+	if result.Moderation != nil && result.Moderation.Status == models.ModerationModerated {
+		return nil, models.ConflictError("score has been moderated")
+	}
+
+	points, err := essayPoints(req, qr.Points)
+	if err != nil {
+		return nil, err
+	}
+
+	grade := &models.EssayGrade{
+		Points:       points,
+		Rubric:       req.Rubric,
+		Feedback:     strings.TrimSpace(req.Feedback),
+		GradedBy:     adminID,
+		GradedByName: adminName,
+		GradedAt:     time.Now(),
+	}
+	for i := range grade.Rubric {
+		grade.Rubric[i].Criterion = strings.TrimSpace(grade.Rubric[i].Criterion)
+	}
+
+	updated, err := s.sessionRepo.SetEssayGrade(ctx, resultID, questionIndex, grade, models.EssayPassed(points, qr.Points))
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.PendingEssays == 0 {
+		if err := s.recomputeScores(ctx, updated); err != nil {
+			return nil, err
+		}
+		// Only the grade that emptied the result's queue announces it
+		if qr.PendingGrade {
+			s.resultGraded(ctx, updated)
+		}
+	}
+
+	return &models.GradeEssayResponse{
+		ResultID:        resultID,
+		QuestionIndex:   questionIndex,
+		Grade:           grade,
+		PendingEssays:   updated.PendingEssays,
+		FullyGraded:     updated.PendingEssays == 0,
+		FinalScore:      updated.FinalScore,
+		ScorePercentage: updated.ScorePercentage,
+	}, nil
+}
+
+// essayPoints returns the points a grade awards, from the rubric when one is given
+func essayPoints(req *models.GradeEssayRequest, maxPoints int) (int, error) {
+	if len(req.Rubric) == 0 {
+		if req.Points == nil {
+			return 0, models.BadRequestError("points or rubric is required")
+		}
+		if *req.Points > maxPoints {
+			return 0, models.BadRequestError("points exceed the question's points")
+		}
+		return *req.Points, nil
+	}
+
+	total, totalMax := 0, 0
+	for _, criterion := range req.Rubric {
+		if criterion.Points > criterion.MaxPoints {
+			return 0, models.BadRequestError("rubric points exceed a criterion's maximum")
+		}
+		total += criterion.Points
+		totalMax += criterion.MaxPoints
+	}
+	if totalMax > maxPoints {
+		return 0, models.BadRequestError("rubric maximum exceeds the question's points")
+	}
+	if req.Points != nil && *req.Points != total {
+		return 0, models.BadRequestError("points must equal the rubric total")
+	}
+	return total, nil
+}
+
+// recomputeScores scores a fully graded result from its question results, then reapplies its
+// sitting's scaling and moderation flag as submission would have
+func (s *essayGradingService) recomputeScores(ctx context.Context, result *models.DetailedQuizResult) error {
+	var earnedPoints, correctAnswers, easyCorrect, mediumCorrect, hardCorrect int
+	for _, qr := range result.QuestionResults {
+		earnedPoints += qr.PointsEarned
+		if !qr.IsCorrect {
+			continue
+		}
+		correctAnswers++
+		switch qr.Difficulty {
+		case models.Easy:
+			easyCorrect++
+		case models.Medium:
+			mediumCorrect++
+		case models.Hard:
+			hardCorrect++
+		}
+	}
+
+	result.EarnedPoints = earnedPoints
+	result.CorrectAnswers = correctAnswers
+	result.EasyCorrect, result.MediumCorrect, result.HardCorrect = easyCorrect, mediumCorrect, hardCorrect
+	result.FinalScore = earnedPoints + result.TimeBonus
+	result.ScorePercentage = 0
+	if result.TotalPoints > 0 {
+		result.ScorePercentage = float64(result.FinalScore) / float64(result.TotalPoints) * 100
+	}
+	result.Score = int(math.Min(100, result.ScorePercentage))
+	result.Scaling = nil
+
+	if result.ExamSittingID != nil {
+		sitting, err := s.examRepo.GetSitting(ctx, *result.ExamSittingID)
+		if err != nil && !errors.Is(err, repository.ErrSittingNotFound) {
+			return fmt.Errorf("failed to get exam sitting: %w", err)
+		}
+		if sitting != nil {
+			if sitting.Scaling != nil {
+				sitting.Scaling.ApplyTo(result, time.Now())
+			}
+			if sitting.NeedsModeration(result.ScorePercentage) {
+				flaggedAt := time.Now()
+				result.Moderation = &models.ResultModeration{
+					Status:             models.ModerationPending,
+					OriginalScore:      result.FinalScore,
+					OriginalPercentage: result.ScorePercentage,
+					FlaggedAt:          &flaggedAt,
+					Adjustments:        []models.ScoreAdjustment{},
+				}
+			} else {
+				result.Moderation = nil
+			}
+		}
+	}
+
+	if err := s.sessionRepo.UpdateGradedScores(ctx, result); err != nil {
+		return err
+	}
+
+	creditedIDs := []primitive.ObjectID{result.UserID}
+	if result.TeamID != nil {
+		creditedIDs = result.TeamMemberIDs
+	}
+	for _, userID := range creditedIDs {
+		if err := s.userActivityRepo.UpdateGradedResultScore(ctx, userID, result.StartedAt, result.Score, result.CorrectAnswers); err != nil {
+			slog.ErrorContext(ctx, "Failed to update summary result after essay grading", "error", err)
+		}
+	}
+	return nil
+}
+
+// resultGraded tells the student their essays are graded and sends the now final result to
+// department webhooks. Scores of results still withheld by their sitting are left out.
+func (s *essayGradingService) resultGraded(ctx context.Context, result *models.DetailedQuizResult) {
+	withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID)
+
+	message := "Your result will be available when your exam results are released."
+	if !withheld {
+		message = fmt.Sprintf("Your final score is %d (%.1f%%).", result.FinalScore, result.ScorePercentage)
+	}
+
+	recipients := []primitive.ObjectID{result.UserID}
+	if result.TeamID != nil {
+		recipients = result.TeamMemberIDs
+	}
+	for _, userID := range recipients {
+		notification := &models.Notification{
+			UserID:     userID,
+			Type:       models.NotificationEssaysGraded,
+			Title:      "Your essays have been graded",
+			Message:    message,
+			EntityType: "quiz_result",
+			EntityID:   result.ID.Hex(),
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			slog.ErrorContext(ctx, "Failed to create essay grading notification", "error", err)
+		}
+	}
+
+	if s.resultWebhooks != nil && result.ExamSittingID != nil && !withheld {
+		s.resultWebhooks.ResultFinalized(result)
+	}
+}
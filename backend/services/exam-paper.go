@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	mrand "math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	paperTitleStyle   = utils.PDFTextStyle{Size: 16, Bold: true}
+	paperHeadingStyle = utils.PDFTextStyle{Size: 11, Bold: true}
+	paperTextStyle    = utils.PDFTextStyle{Size: 11}
+	paperOptionStyle  = utils.PDFTextStyle{Size: 11, Indent: 18}
+	paperHintStyle    = utils.PDFTextStyle{Size: 9, Indent: 18}
+)
+
+const (
+	paperEssaySpace    = 160.0 // Writing space left under an essay question, in points
+	paperQuestionSpace = 80.0  // Space a question needs to start on the current page
+)
+
+// paperVersion is one room's copy of a paper set, with questions and options in the room's own order
+type paperVersion struct {
+	Room      string
+	Code      string
+	Questions []models.SessionQuestion
+}
+
+// PrintPaper renders a sitting's question papers or answer key as a PDF with a version per room.
+// The paper set is drawn the first time the sitting is printed and reused afterwards.
+func (s *examService) PrintPaper(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.PrintExamPaperRequest) (*models.PrintedExamPaper, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := []string{}
+	if room := strings.TrimSpace(req.Room); room != "" {
+		rooms = append(rooms, room)
+	} else {
+		participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get participants: %w", err)
+		}
+		seen := make(map[string]bool)
+		for _, participant := range participants {
+			room := paperRoom(participant)
+			if !seen[room] {
+				seen[room] = true
+				rooms = append(rooms, room)
+			}
+		}
+		sort.Strings(rooms)
+	}
+	if len(rooms) == 0 {
+		return nil, models.BadRequestError("no rooms to print; assign seats or choose a room")
+	}
+
+	set, err := s.paperSet(ctx, adminID, sitting)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := utils.NewPDFDocument()
+	for _, room := range rooms {
+		version := newPaperVersion(set, room)
+		if req.Document == models.PaperAnswerKey {
+			renderAnswerKey(doc, sitting, set, version)
+		} else {
+			renderQuestionPaper(doc, sitting, set, version)
+		}
+	}
+
+	document := req.Document
+	if document == "" {
+		document = models.PaperQuestions
+	}
+	return &models.PrintedExamPaper{
+		Sitting:  sitting,
+		Document: document,
+		Versions: len(rooms),
+		Content:  doc.Bytes(),
+	}, nil
+}
+
+// paperSet returns the sitting's printed question set, drawing it on first use
+func (s *examService) paperSet(ctx context.Context, adminID primitive.ObjectID, sitting *models.ExamSitting) (*models.ExamPaperSet, error) {
+	set, err := s.examRepo.GetPaperSet(ctx, sitting.ID)
+	if err == nil {
+		return set, nil
+	}
+	if !errors.Is(err, repository.ErrPaperSetNotFound) {
+		return nil, fmt.Errorf("failed to get paper set: %w", err)
+	}
+
+	questions, maxPoints, template, err := s.quizSessionService.BuildQuestionSet(ctx, sitting.QuizType)
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, models.NewCodedError(http.StatusServiceUnavailable, models.ErrorCodeQuestionPoolShort, "no questions available for this quiz type")
+	}
+
+	seed, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate paper seed: %w", err)
+	}
+
+	set, err = s.examRepo.CreatePaperSet(ctx, &models.ExamPaperSet{
+		SittingID:        sitting.ID,
+		QuizType:         sitting.QuizType,
+		Questions:        questions,
+		QuestionCount:    len(questions),
+		MaxPoints:        maxPoints,
+		TimeLimitMinutes: template.TimeLimitMinutes,
+		Seed:             seed,
+		CreatedBy:        adminID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create paper set: %w", err)
+	}
+	return set, nil
+}
+
+// paperRoom names the room a participant sits in, qualified by venue
+func paperRoom(participant models.ExamParticipant) string {
+	if participant.Room == "" {
+		return participant.Venue
+	}
+	return participant.Venue + " / " + participant.Room
+}
+
+// newPaperVersion shuffles the set for a room. The shuffle is derived from the set's seed and the
+// room name, so reprinting a room gives the same paper and the code on it matches its answer key.
+func newPaperVersion(set *models.ExamPaperSet, room string) paperVersion {
+	sum := sha256.Sum256([]byte(set.Seed + "|" + room))
+	rng := mrand.New(mrand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+
+	questions := make([]models.SessionQuestion, len(set.Questions))
+	copy(questions, set.Questions)
+	rng.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	for i := range questions {
+		options := make([]models.Option, len(questions[i].Options))
+		copy(options, questions[i].Options)
+		rng.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+		questions[i].Options = options
+	}
+
+	return paperVersion{
+		Room:      room,
+		Code:      "V-" + strings.ToUpper(hex.EncodeToString(sum[8:10])),
+		Questions: questions,
+	}
+}
+
+func renderPaperHeader(doc *utils.PDFDocument, sitting *models.ExamSitting, version paperVersion, title string) {
+	doc.AddPage()
+	doc.Paragraph(sitting.Name, paperTitleStyle)
+	doc.Paragraph(title, paperHeadingStyle)
+	doc.Paragraph(fmt.Sprintf("Room: %s    Version: %s    Date: %s", version.Room, version.Code, sitting.ScheduledStart.Format("2 January 2006")), paperTextStyle)
+}
+
+func renderQuestionPaper(doc *utils.PDFDocument, sitting *models.ExamSitting, set *models.ExamPaperSet, version paperVersion) {
+	renderPaperHeader(doc, sitting, version, "Question paper")
+	doc.Paragraph(fmt.Sprintf("%d questions, %d points. Time allowed: %d minutes.", set.QuestionCount, set.MaxPoints, set.TimeLimitMinutes), paperTextStyle)
+	doc.Space(8)
+	doc.Paragraph("Name: ______________________________    NIM: ________________    Seat: ________", paperTextStyle)
+	doc.Space(16)
+
+	for i, question := range version.Questions {
+		doc.EnsureSpace(paperQuestionSpace)
+		doc.Paragraph(fmt.Sprintf("%d. (%d points)", i+1, question.Points), paperHeadingStyle)
+
+		blanks := models.BlankPlaceholders(question.Title)
+		title := question.Title
+		if question.Type == models.FillInBlank {
+			number := 0
+			title = models.ReplaceBlankPlaceholders(title, func(string) string {
+				number++
+				return fmt.Sprintf("(%d) __________", number)
+			})
+		}
+		doc.Paragraph(title, paperTextStyle)
+
+		for _, media := range question.Media {
+			switch media.Type {
+			case models.MediaImage:
+				doc.Paragraph("[Figure: "+media.AltText+"]", paperHintStyle)
+			default:
+				doc.Paragraph(fmt.Sprintf("[%s not available on paper; ask the invigilator]", media.Type), paperHintStyle)
+			}
+		}
+
+		switch question.Type {
+		case models.SingleChoice:
+			doc.Paragraph("Circle one answer.", paperHintStyle)
+			renderPaperOptions(doc, question.Options)
+		case models.MultipleChoice:
+			doc.Paragraph("Circle every correct answer.", paperHintStyle)
+			renderPaperOptions(doc, question.Options)
+		case models.Ordering:
+			doc.Paragraph("Write the letters in the correct order.", paperHintStyle)
+			renderPaperOptions(doc, question.Options)
+			doc.Paragraph("Order: ______________________________", paperOptionStyle)
+		case models.Numeric:
+			doc.Paragraph(strings.TrimSpace("Answer: ____________ "+question.Unit), paperOptionStyle)
+		case models.FillInBlank:
+			for n := range blanks {
+				doc.Paragraph(fmt.Sprintf("(%d) ______________________________", n+1), paperOptionStyle)
+			}
+		case models.Essay:
+			doc.Paragraph("Write your answer below.", paperHintStyle)
+			doc.Space(paperEssaySpace)
+		}
+		doc.Space(10)
+	}
+}
+
+func renderPaperOptions(doc *utils.PDFDocument, options []models.Option) {
+	for i, option := range options {
+		text := option.Text
+		if option.ImageURL != "" {
+			text = strings.TrimSpace(text + " [Figure: " + option.ImageAlt + "]")
+		}
+		doc.Paragraph(fmt.Sprintf("%s. %s", paperLetter(i), text), paperOptionStyle)
+	}
+}
+
+func renderAnswerKey(doc *utils.PDFDocument, sitting *models.ExamSitting, set *models.ExamPaperSet, version paperVersion) {
+	renderPaperHeader(doc, sitting, version, "Answer key - keep apart from the question papers")
+	doc.Paragraph(fmt.Sprintf("%d questions, %d points.", set.QuestionCount, set.MaxPoints), paperTextStyle)
+	doc.Space(12)
+
+	for i, question := range version.Questions {
+		doc.Paragraph(fmt.Sprintf("%d. (%d points, %s) %s", i+1, question.Points, question.Type, paperAnswer(question)), paperTextStyle)
+		doc.Space(4)
+	}
+}
+
+// paperAnswer describes a question's correct answer in terms of the letters on its paper
+func paperAnswer(question models.SessionQuestion) string {
+	letters := make(map[string]string, len(question.Options))
+	for i, option := range question.Options {
+		letters[option.ID] = paperLetter(i)
+	}
+
+	switch question.Type {
+	case models.SingleChoice, models.MultipleChoice, models.Ordering:
+		answer := make([]string, 0, len(question.CorrectAnswers))
+		for _, id := range question.CorrectAnswers {
+			answer = append(answer, letters[id])
+		}
+		if question.Type == models.Ordering {
+			text := strings.Join(answer, ", ")
+			if question.OrderingScoring == models.OrderingPairwise {
+				text += " (partial credit for pairs in the right order)"
+			}
+			return text
+		}
+		sort.Strings(answer)
+		return strings.Join(answer, ", ")
+
+	case models.Numeric:
+		if question.NumericAnswer == nil {
+			return "-"
+		}
+		numeric := question.NumericAnswer
+		text := strings.TrimSpace(strconv.FormatFloat(numeric.Value, 'g', -1, 64) + " " + numeric.Unit)
+		tolerance := math.Max(numeric.AbsoluteTolerance, numeric.RelativeTolerance*math.Abs(numeric.Value))
+		if tolerance > 0 {
+			text += " (within " + strconv.FormatFloat(tolerance, 'g', 4, 64) + ")"
+		}
+		return text
+
+	case models.FillInBlank:
+		byID := make(map[string]models.Blank, len(question.Blanks))
+		for _, blank := range question.Blanks {
+			byID[blank.ID] = blank
+		}
+		parts := []string{}
+		for n, id := range models.BlankPlaceholders(question.Title) {
+			blank := byID[id]
+			accepted := strings.Join(blank.AcceptedAnswers, " or ")
+			if blank.Regex {
+				accepted = "matching " + strings.Join(blank.AcceptedAnswers, " or ")
+			}
+			parts = append(parts, fmt.Sprintf("(%d) %s", n+1, accepted))
+		}
+		return strings.Join(parts, "; ")
+
+	case models.Essay:
+		if question.SampleAnswer == "" {
+			return "Marked by the examiner"
+		}
+		return "Sample answer: " + question.SampleAnswer
+	}
+	return "-"
+}
+
+// paperLetter labels the option at index i: A to Z, then AA, AB and so on
+func paperLetter(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return paperLetter(i/26-1) + string(rune('A'+i%26))
+}
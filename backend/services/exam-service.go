@@ -0,0 +1,1267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultLateAfterMinutes = 15
+	// Sessions started this long before the scheduled start still count towards the sitting
+	attendanceEarlyStartWindow = 30 * time.Minute
+
+	offlineFileVersion   = 1
+	offlineFileAlgorithm = "AES-256-GCM"
+	// Invigilator clocks may drift from the server's
+	offlineClockSkew      = 5 * time.Minute
+	maxOfflineSubmissions = 1000
+)
+
+type ExamService interface {
+	// Sittings
+	CreateSitting(ctx context.Context, adminID primitive.ObjectID, req *models.CreateExamSittingRequest) (*models.ExamSitting, error)
+	ListSittings(ctx context.Context) ([]models.ExamSitting, error)
+	GetSitting(ctx context.Context, sittingID primitive.ObjectID) (*models.ExamSittingResponse, error)
+	DeleteSitting(ctx context.Context, sittingID primitive.ObjectID) error
+	UpdateLockdownSettings(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateLockdownSettingsRequest) (*models.LockdownSettingsResponse, error)
+
+	// Result release
+	UpdateResultRelease(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateResultReleaseRequest) (*models.ExamSitting, error)
+	ReleaseResults(ctx context.Context, sittingID primitive.ObjectID, force bool) (*models.ExamSitting, error)
+
+	// Moderation
+	UpdateModerationSettings(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateModerationSettingsRequest) (*models.ModerationSettingsResponse, error)
+	GetModerationQueue(ctx context.Context, sittingID primitive.ObjectID, filter models.ModerationFilter) (*models.ModerationQueueResponse, error)
+	AdjustScore(ctx context.Context, adminID primitive.ObjectID, adminName string, sittingID, resultID primitive.ObjectID, req *models.AdjustScoreRequest) (*models.ModerationQueueItem, error)
+
+	// Grade scaling
+	PreviewScaling(ctx context.Context, sittingID primitive.ObjectID, req *models.ScaleScoresRequest) (*models.ScalingResponse, error)
+	ApplyScaling(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.ScaleScoresRequest) (*models.ScalingResponse, error)
+	RemoveScaling(ctx context.Context, sittingID primitive.ObjectID) (*models.ScalingResponse, error)
+
+	// Seating
+	AssignSeats(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.AssignSeatsRequest) (*models.AssignSeatsResponse, error)
+	RemoveParticipant(ctx context.Context, sittingID, userID primitive.ObjectID) error
+	GetUserAssignments(ctx context.Context, userID primitive.ObjectID) ([]models.ExamAssignmentResponse, error)
+
+	// Attendance
+	GetAttendanceReport(ctx context.Context, sittingID primitive.ObjectID) (*models.AttendanceReport, error)
+
+	// Offline packages
+	CreateOfflinePackage(ctx context.Context, adminID, sittingID primitive.ObjectID) (*models.CreateOfflinePackageResponse, error)
+	ListOfflinePackages(ctx context.Context, sittingID primitive.ObjectID) ([]models.OfflineExamPackage, error)
+	GetOfflinePackageFile(ctx context.Context, sittingID, packageID primitive.ObjectID) (*models.EncryptedOfflineFile, error)
+	IngestOfflineAnswers(ctx context.Context, sittingID, packageID primitive.ObjectID, file *models.EncryptedOfflineFile) (*models.IngestOfflineAnswersResponse, error)
+
+	// Printed papers
+	PrintPaper(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.PrintExamPaperRequest) (*models.PrintedExamPaper, error)
+}
+
+type examService struct {
+	examRepo           repository.ExamRepository
+	sessionRepo        repository.QuizSessionRepository
+	userRepo           repository.UserRepository
+	userActivityRepo   repository.UserActivityRepository
+	quizSessionService QuizSessionService
+	resultWebhooks     ResultWebhookService
+}
+
+func NewExamService(
+	examRepo repository.ExamRepository,
+	sessionRepo repository.QuizSessionRepository,
+	userRepo repository.UserRepository,
+	userActivityRepo repository.UserActivityRepository,
+	quizSessionService QuizSessionService,
+	resultWebhooks ResultWebhookService,
+) ExamService {
+	return &examService{
+		examRepo:           examRepo,
+		sessionRepo:        sessionRepo,
+		userRepo:           userRepo,
+		userActivityRepo:   userActivityRepo,
+		quizSessionService: quizSessionService,
+		resultWebhooks:     resultWebhooks,
+	}
+}
+
+func (s *examService) CreateSitting(ctx context.Context, adminID primitive.ObjectID, req *models.CreateExamSittingRequest) (*models.ExamSitting, error) {
+	if !req.ScheduledEnd.After(req.ScheduledStart) {
+		return nil, models.BadRequestError("scheduled end must be after scheduled start")
+	}
+
+	lateAfter := req.LateAfterMinutes
+	if lateAfter == 0 {
+		lateAfter = defaultLateAfterMinutes
+	}
+
+	releaseMode := req.ResultRelease
+	if releaseMode == "" {
+		releaseMode = models.ResultReleaseImmediate
+	}
+	releaseAt, err := resultReleaseTime(releaseMode, req.ResultsReleaseAt)
+	if err != nil {
+		return nil, err
+	}
+
+	sitting := &models.ExamSitting{
+		Name:             req.Name,
+		Description:      req.Description,
+		QuizType:         req.QuizType,
+		ScheduledStart:   req.ScheduledStart,
+		ScheduledEnd:     req.ScheduledEnd,
+		LateAfterMinutes: lateAfter,
+		ResultRelease:    releaseMode,
+		ResultsReleaseAt: releaseAt,
+		PassMark:         req.PassMark,
+		ModerationBand:   req.ModerationBand,
+		CreatedBy:        adminID,
+	}
+
+	if err := s.examRepo.CreateSitting(ctx, sitting); err != nil {
+		return nil, fmt.Errorf("failed to create exam sitting: %w", err)
+	}
+
+	return sitting, nil
+}
+
+func (s *examService) ListSittings(ctx context.Context) ([]models.ExamSitting, error) {
+	sittings, err := s.examRepo.ListSittings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exam sittings: %w", err)
+	}
+	return sittings, nil
+}
+
+func (s *examService) GetSitting(ctx context.Context, sittingID primitive.ObjectID) (*models.ExamSittingResponse, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+
+	return &models.ExamSittingResponse{
+		Sitting:      sitting,
+		Participants: participants,
+	}, nil
+}
+
+func (s *examService) DeleteSitting(ctx context.Context, sittingID primitive.ObjectID) error {
+	return s.examRepo.DeleteSitting(ctx, sittingID)
+}
+
+// UpdateLockdownSettings toggles the lockdown browser requirement. A key is issued the first time
+// it is enabled (or on rotation) and returned only in that response; the key is kept when disabling
+// so re-enabling doesn't require reconfiguring every browser.
+func (s *examService) UpdateLockdownSettings(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateLockdownSettingsRequest) (*models.LockdownSettingsResponse, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	required := *req.Required
+	key := sitting.LockdownKey
+	issued := false
+	if (required && key == "") || req.RotateKey {
+		key, err = utils.GenerateEncryptionKey()
+		if err != nil {
+			return nil, err
+		}
+		issued = true
+	}
+
+	if err := s.examRepo.UpdateLockdownSettings(ctx, sittingID, required, key); err != nil {
+		return nil, fmt.Errorf("failed to update lockdown settings: %w", err)
+	}
+	sitting.RequireLockdownBrowser = required
+	sitting.LockdownKey = key
+
+	response := &models.LockdownSettingsResponse{Sitting: sitting}
+	switch {
+	case issued:
+		response.LockdownKey = key
+		response.Message = "Lockdown key issued. Configure it in the lockdown browser; it is not shown again"
+	case required:
+		response.Message = "Lockdown browser required"
+	default:
+		response.Message = "Lockdown browser no longer required"
+	}
+	return response, nil
+}
+
+func (s *examService) UpdateResultRelease(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateResultReleaseRequest) (*models.ExamSitting, error) {
+	releaseAt, err := resultReleaseTime(req.Mode, req.ResultsReleaseAt)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.examRepo.UpdateResultRelease(ctx, sittingID, req.Mode, releaseAt); err != nil {
+		if errors.Is(err, repository.ErrSittingNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update result release: %w", err)
+	}
+
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results withheld until now are released by switching to immediate release or a past release time
+	if now := time.Now(); !before.ResultsReleased(now) && sitting.ResultsReleased(now) {
+		s.resultsReleased(sittingID)
+	}
+	return sitting, nil
+}
+
+// ReleaseResults publishes the sitting's results now, whatever the release mode. Results awaiting
+// moderation block the release unless force is set.
+func (s *examService) ReleaseResults(ctx context.Context, sittingID primitive.ObjectID, force bool) (*models.ExamSitting, error) {
+	if !force {
+		pending, err := s.sessionRepo.CountPendingModeration(ctx, sittingID)
+		if err != nil {
+			return nil, err
+		}
+		if pending > 0 {
+			return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeResultsPending, "results are awaiting moderation")
+		}
+	}
+
+	before, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.examRepo.ReleaseResults(ctx, sittingID, now); err != nil {
+		if errors.Is(err, repository.ErrSittingNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to release results: %w", err)
+	}
+
+	// Results already visible were sent as they were submitted
+	if !before.ResultsReleased(now) {
+		s.resultsReleased(sittingID)
+	}
+	return s.examRepo.GetSitting(ctx, sittingID)
+}
+
+// resultsReleased sends a sitting's newly released results to department webhooks
+func (s *examService) resultsReleased(sittingID primitive.ObjectID) {
+	if s.resultWebhooks != nil {
+		s.resultWebhooks.SittingReleased(sittingID)
+	}
+}
+
+// UpdateModerationSettings changes the sitting's pass mark and band, then flags or clears
+// existing results to match; reviewed results keep their moderation
+func (s *examService) UpdateModerationSettings(ctx context.Context, sittingID primitive.ObjectID, req *models.UpdateModerationSettingsRequest) (*models.ModerationSettingsResponse, error) {
+	if err := s.examRepo.UpdateModerationSettings(ctx, sittingID, *req.PassMark, req.ModerationBand); err != nil {
+		if errors.Is(err, repository.ErrSittingNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update moderation settings: %w", err)
+	}
+
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := sitting.ModerationBand > 0
+	flagged, cleared, err := s.sessionRepo.SyncModerationFlags(ctx, sittingID, enabled,
+		sitting.PassMark-sitting.ModerationBand, sitting.PassMark+sitting.ModerationBand, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ModerationSettingsResponse{
+		Sitting: sitting,
+		Flagged: flagged,
+		Cleared: cleared,
+		Message: fmt.Sprintf("%d results flagged for moderation", flagged),
+	}
+	if !enabled {
+		response.Message = "Moderation turned off"
+	}
+	return response, nil
+}
+
+// GetModerationQueue lists the sitting's flagged and moderated results, lowest score first
+func (s *examService) GetModerationQueue(ctx context.Context, sittingID primitive.ObjectID, filter models.ModerationFilter) (*models.ModerationQueueResponse, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.sessionRepo.GetModerationResults(ctx, sittingID, filter.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.sessionRepo.CountPendingModeration(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list participants: %w", err)
+	}
+	seated := make(map[primitive.ObjectID]models.ExamParticipant, len(participants))
+	for _, p := range participants {
+		seated[p.UserID] = p
+	}
+
+	response := &models.ModerationQueueResponse{
+		SittingID:      sittingID,
+		PassMark:       sitting.PassMark,
+		ModerationBand: sitting.ModerationBand,
+		Pending:        int(pending),
+		Items:          make([]models.ModerationQueueItem, 0, len(results)),
+	}
+	for i := range results {
+		item := moderationQueueItem(&results[i])
+		if p, ok := seated[item.UserID]; ok {
+			item.FullName, item.Email = p.FullName, p.Email
+		} else {
+			student := s.lookupStudent(ctx, item.UserID)
+			item.FullName, item.Email = student.FullName, student.Email
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	return response, nil
+}
+
+// AdjustScore sets a sitting result's final score before results are released, recording the
+// justification. Submitting the current score confirms it; either way the result is moderated.
+func (s *examService) AdjustScore(ctx context.Context, adminID primitive.ObjectID, adminName string, sittingID, resultID primitive.ObjectID, req *models.AdjustScoreRequest) (*models.ModerationQueueItem, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+	if sitting.ResultsReleased(time.Now()) {
+		return nil, models.ConflictError("results have already been released")
+	}
+
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExamSittingID == nil || *result.ExamSittingID != sittingID {
+		return nil, repository.ErrResultNotFound
+	}
+	if result.Scaling != nil {
+		return nil, models.ConflictError("scores have been scaled")
+	}
+	if result.PendingEssays > 0 {
+		return nil, models.ConflictError("essays still need grading")
+	}
+
+	finalScore := *req.FinalScore
+	if finalScore > result.TotalPoints+result.TimeBonus {
+		return nil, models.BadRequestError("final score exceeds the points available")
+	}
+
+	now := time.Now()
+	moderation := result.Moderation
+	if moderation == nil {
+		moderation = &models.ResultModeration{
+			OriginalScore:      result.FinalScore,
+			OriginalPercentage: result.ScorePercentage,
+		}
+	}
+	moderation.Status = models.ModerationModerated
+	moderation.ModeratedAt = &now
+	moderation.Adjustments = append(moderation.Adjustments, models.ScoreAdjustment{
+		PreviousScore:  result.FinalScore,
+		NewScore:       finalScore,
+		Justification:  strings.TrimSpace(req.Justification),
+		AdjustedBy:     adminID,
+		AdjustedByName: adminName,
+		AdjustedAt:     now,
+	})
+
+	scorePercentage := 0.0
+	if result.TotalPoints > 0 {
+		scorePercentage = float64(finalScore) / float64(result.TotalPoints) * 100
+	}
+	score := int(math.Min(100, scorePercentage))
+
+	if err := s.sessionRepo.UpdateModeratedScore(ctx, resultID, finalScore, score, scorePercentage, moderation); err != nil {
+		return nil, err
+	}
+	if err := s.userActivityRepo.UpdateSittingResultScore(ctx, result.UserID, sittingID, result.StartedAt, score); err != nil {
+		slog.ErrorContext(ctx, "Failed to update summary result after moderation", "error", err)
+	}
+
+	result.FinalScore = finalScore
+	result.Score = score
+	result.ScorePercentage = scorePercentage
+	result.Moderation = moderation
+
+	item := moderationQueueItem(result)
+	student := s.lookupStudent(ctx, result.UserID)
+	item.FullName, item.Email = student.FullName, student.Email
+	return &item, nil
+}
+
+// PreviewScaling shows how a curve would change the sitting's scores without saving anything.
+// Curves always start from the raw scores, so a preview replaces any scaling already applied.
+func (s *examService) PreviewScaling(ctx context.Context, sittingID primitive.ObjectID, req *models.ScaleScoresRequest) (*models.ScalingResponse, error) {
+	sitting, results, err := s.getScalableResults(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	scaling := scalingFromRequest(req)
+	response := scaleResults(sitting, results, scaling, time.Now())
+	response.Message = fmt.Sprintf("Preview of %d results", len(results))
+	return response, nil
+}
+
+// ApplyScaling curves the sitting's results from their raw scores and stores the curve so
+// results submitted later are scaled the same way
+func (s *examService) ApplyScaling(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.ScaleScoresRequest) (*models.ScalingResponse, error) {
+	sitting, results, err := s.getScalableResults(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scaling := scalingFromRequest(req)
+	scaling.AppliedBy = adminID
+	scaling.AppliedAt = &now
+
+	if err := s.examRepo.UpdateScaling(ctx, sittingID, scaling); err != nil {
+		return nil, fmt.Errorf("failed to save scaling: %w", err)
+	}
+
+	response := scaleResults(sitting, results, scaling, now)
+	if err := s.saveScaledResults(ctx, sittingID, results); err != nil {
+		return nil, err
+	}
+
+	response.Applied = true
+	response.Message = fmt.Sprintf("Scaled %d results", len(results))
+	return response, nil
+}
+
+// RemoveScaling restores the sitting's raw scores
+func (s *examService) RemoveScaling(ctx context.Context, sittingID primitive.ObjectID) (*models.ScalingResponse, error) {
+	sitting, results, err := s.getScalableResults(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+	if sitting.Scaling == nil {
+		return nil, models.ConflictError("scores have not been scaled")
+	}
+
+	before := make([]float64, len(results))
+	after := make([]float64, len(results))
+	for i := range results {
+		before[i] = results[i].ScorePercentage
+		results[i].RemoveScaling()
+		after[i] = results[i].ScorePercentage
+	}
+
+	if err := s.examRepo.UpdateScaling(ctx, sittingID, nil); err != nil {
+		return nil, fmt.Errorf("failed to remove scaling: %w", err)
+	}
+	if err := s.saveScaledResults(ctx, sittingID, results); err != nil {
+		return nil, err
+	}
+
+	return &models.ScalingResponse{
+		SittingID: sittingID,
+		Before:    models.NewScoreDistribution(before, sitting.PassMark),
+		After:     models.NewScoreDistribution(after, sitting.PassMark),
+		Results:   scaledResultItems(results),
+		Applied:   true,
+		Message:   fmt.Sprintf("Restored raw scores of %d results", len(results)),
+	}, nil
+}
+
+// getScalableResults loads a sitting and its results, refusing once results are out
+func (s *examService) getScalableResults(ctx context.Context, sittingID primitive.ObjectID) (*models.ExamSitting, []models.DetailedQuizResult, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sitting.ResultsReleased(time.Now()) {
+		return nil, nil, models.ConflictError("results have already been released")
+	}
+
+	results, err := s.sessionRepo.GetSittingResults(ctx, sittingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sitting, results, nil
+}
+
+// saveScaledResults stores the results' new scores and keeps the summary results in step
+func (s *examService) saveScaledResults(ctx context.Context, sittingID primitive.ObjectID, results []models.DetailedQuizResult) error {
+	if err := s.sessionRepo.UpdateScaledScores(ctx, results); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := s.userActivityRepo.UpdateSittingResultScore(ctx, result.UserID, sittingID, result.StartedAt, result.Score); err != nil {
+			slog.ErrorContext(ctx, "Failed to update summary result after scaling", "error", err)
+		}
+	}
+	return nil
+}
+
+func scalingFromRequest(req *models.ScaleScoresRequest) *models.ScoreScaling {
+	scaling := &models.ScoreScaling{Method: req.Method}
+	if req.Method == models.ScalingLinear {
+		scaling.Slope = 1
+		if req.Slope != nil {
+			scaling.Slope = *req.Slope
+		}
+		scaling.Intercept = req.Intercept
+	}
+	return scaling
+}
+
+// scaleResults applies a curve to the results in place and compares the distributions.
+// The "before" distribution uses raw scores.
+func scaleResults(sitting *models.ExamSitting, results []models.DetailedQuizResult, scaling *models.ScoreScaling, now time.Time) *models.ScalingResponse {
+	before := make([]float64, len(results))
+	after := make([]float64, len(results))
+	for i := range results {
+		results[i].RemoveScaling()
+		before[i] = results[i].ScorePercentage
+		scaling.ApplyTo(&results[i], now)
+		after[i] = results[i].ScorePercentage
+	}
+
+	return &models.ScalingResponse{
+		SittingID: sitting.ID,
+		Scaling:   scaling,
+		Before:    models.NewScoreDistribution(before, sitting.PassMark),
+		After:     models.NewScoreDistribution(after, sitting.PassMark),
+		Results:   scaledResultItems(results),
+	}
+}
+
+func scaledResultItems(results []models.DetailedQuizResult) []models.ScaledResultItem {
+	items := make([]models.ScaledResultItem, 0, len(results))
+	for _, result := range results {
+		item := models.ScaledResultItem{
+			ResultID:              result.ID,
+			UserID:                result.UserID,
+			RawFinalScore:         result.FinalScore,
+			RawScorePercentage:    result.ScorePercentage,
+			ScaledFinalScore:      result.FinalScore,
+			ScaledScorePercentage: result.ScorePercentage,
+			TotalPoints:           result.TotalPoints,
+		}
+		if result.Scaling != nil {
+			item.RawFinalScore = result.Scaling.RawFinalScore
+			item.RawScorePercentage = result.Scaling.RawScorePercentage
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func (s *examService) AssignSeats(ctx context.Context, adminID, sittingID primitive.ObjectID, req *models.AssignSeatsRequest) (*models.AssignSeatsResponse, error) {
+	if _, err := s.examRepo.GetSitting(ctx, sittingID); err != nil {
+		return nil, err
+	}
+
+	response := &models.AssignSeatsResponse{}
+	for i, assignment := range req.Assignments {
+		student, err := s.resolveStudent(ctx, assignment)
+		if err == nil {
+			participant := &models.ExamParticipant{
+				SittingID:  sittingID,
+				UserID:     student.UserID,
+				FullName:   student.FullName,
+				Email:      student.Email,
+				NIM:        student.NIM,
+				Venue:      assignment.Venue,
+				Room:       assignment.Room,
+				Seat:       assignment.Seat,
+				AssignedBy: adminID,
+			}
+			err = s.examRepo.UpsertParticipant(ctx, participant)
+		}
+
+		if err != nil {
+			response.Errors = append(response.Errors, models.SeatAssignmentError{
+				Index:   i,
+				UserID:  assignment.UserID,
+				NIM:     assignment.NIM,
+				Message: err.Error(),
+			})
+			continue
+		}
+		response.Assigned++
+	}
+
+	if err := s.examRepo.RefreshParticipantCount(ctx, sittingID); err != nil {
+		return nil, fmt.Errorf("failed to update participant count: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *examService) RemoveParticipant(ctx context.Context, sittingID, userID primitive.ObjectID) error {
+	if err := s.examRepo.DeleteParticipant(ctx, sittingID, userID); err != nil {
+		return err
+	}
+
+	if err := s.examRepo.RefreshParticipantCount(ctx, sittingID); err != nil {
+		return fmt.Errorf("failed to update participant count: %w", err)
+	}
+
+	return nil
+}
+
+func (s *examService) GetUserAssignments(ctx context.Context, userID primitive.ObjectID) ([]models.ExamAssignmentResponse, error) {
+	participations, err := s.examRepo.GetParticipationsByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam assignments: %w", err)
+	}
+
+	assignments := make([]models.ExamAssignmentResponse, 0, len(participations))
+	for _, participation := range participations {
+		sitting, err := s.examRepo.GetSitting(ctx, participation.SittingID)
+		if err != nil {
+			continue // Sitting was removed
+		}
+		assignments = append(assignments, models.ExamAssignmentResponse{
+			Sitting:    *sitting,
+			Assignment: participation,
+		})
+	}
+
+	return assignments, nil
+}
+
+func (s *examService) GetAttendanceReport(ctx context.Context, sittingID primitive.ObjectID) (*models.AttendanceReport, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+
+	sessions, err := s.sessionRepo.GetSessionsStartedBetween(ctx, sitting.QuizType,
+		sitting.ScheduledStart.Add(-attendanceEarlyStartWindow), sitting.ScheduledEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	// Sessions come sorted by start time, so the first one per user is when they actually started
+	sessionsByUser := make(map[primitive.ObjectID][]models.QuizSession)
+	for _, session := range sessions {
+		sessionsByUser[session.UserID] = append(sessionsByUser[session.UserID], session)
+	}
+
+	report := &models.AttendanceReport{
+		Sitting:     sitting,
+		Records:     make([]models.AttendanceRecord, 0, len(participants)),
+		GeneratedAt: time.Now(),
+	}
+
+	for _, participant := range participants {
+		record := models.AttendanceRecord{
+			UserID:   participant.UserID.Hex(),
+			FullName: participant.FullName,
+			Email:    participant.Email,
+			NIM:      participant.NIM,
+			Venue:    participant.Venue,
+			Room:     participant.Room,
+			Seat:     participant.Seat,
+			Status:   models.AttendanceAbsent,
+		}
+		s.applySessions(&record, sitting, sessionsByUser[participant.UserID])
+		delete(sessionsByUser, participant.UserID)
+
+		report.Summary.Assigned++
+		report.Records = append(report.Records, record)
+	}
+
+	// Whoever is left started a session without being seated
+	unassigned := make([]models.AttendanceRecord, 0, len(sessionsByUser))
+	for userID, userSessions := range sessionsByUser {
+		student := s.lookupStudent(ctx, userID)
+		record := models.AttendanceRecord{
+			UserID:   userID.Hex(),
+			FullName: student.FullName,
+			Email:    student.Email,
+			NIM:      student.NIM,
+		}
+		s.applySessions(&record, sitting, userSessions)
+		record.Status = models.AttendanceUnassigned
+		unassigned = append(unassigned, record)
+	}
+	sort.Slice(unassigned, func(i, j int) bool {
+		return unassigned[i].StartedAt.Before(*unassigned[j].StartedAt)
+	})
+	report.Records = append(report.Records, unassigned...)
+
+	for _, record := range report.Records {
+		switch record.Status {
+		case models.AttendancePresent:
+			report.Summary.Present++
+		case models.AttendanceLate:
+			report.Summary.Late++
+		case models.AttendanceAbsent:
+			report.Summary.Absent++
+		case models.AttendanceUnassigned:
+			report.Summary.Unassigned++
+		}
+	}
+
+	return report, nil
+}
+
+// CreateOfflinePackage freezes a question set for the sitting and generates the key its files are encrypted with
+func (s *examService) CreateOfflinePackage(ctx context.Context, adminID, sittingID primitive.ObjectID) (*models.CreateOfflinePackageResponse, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, maxPoints, template, err := s.quizSessionService.BuildQuestionSet(ctx, sitting.QuizType)
+	if err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, models.NewCodedError(http.StatusServiceUnavailable, models.ErrorCodeQuestionPoolShort, "no questions available for this quiz type")
+	}
+
+	key, err := utils.GenerateEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &models.OfflineExamPackage{
+		SittingID:        sittingID,
+		QuizType:         sitting.QuizType,
+		Questions:        questions,
+		QuestionCount:    len(questions),
+		MaxPoints:        maxPoints,
+		TimeLimitMinutes: template.TimeLimitMinutes,
+		Scoring:          &template.Scoring,
+		EncryptionKey:    key,
+		CreatedBy:        adminID,
+	}
+	pkg.HideCorrectAnswers = template.HideCorrectAnswers
+
+	if err := s.examRepo.CreateOfflinePackage(ctx, pkg); err != nil {
+		return nil, fmt.Errorf("failed to create offline package: %w", err)
+	}
+
+	return &models.CreateOfflinePackageResponse{
+		Package:       pkg,
+		EncryptionKey: key,
+		Message:       "Offline package created. Store the encryption key securely; it is not shown again",
+	}, nil
+}
+
+func (s *examService) ListOfflinePackages(ctx context.Context, sittingID primitive.ObjectID) ([]models.OfflineExamPackage, error) {
+	if _, err := s.examRepo.GetSitting(ctx, sittingID); err != nil {
+		return nil, err
+	}
+
+	packages, err := s.examRepo.ListOfflinePackages(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offline packages: %w", err)
+	}
+	return packages, nil
+}
+
+// GetOfflinePackageFile encrypts the package questions together with the current seat assignments
+func (s *examService) GetOfflinePackageFile(ctx context.Context, sittingID, packageID primitive.ObjectID) (*models.EncryptedOfflineFile, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := s.examRepo.GetOfflinePackage(ctx, sittingID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+
+	content := models.OfflinePackageContent{
+		PackageID:        pkg.ID.Hex(),
+		SittingID:        sitting.ID.Hex(),
+		SittingName:      sitting.Name,
+		QuizType:         pkg.QuizType,
+		ScheduledStart:   sitting.ScheduledStart,
+		ScheduledEnd:     sitting.ScheduledEnd,
+		TimeLimitMinutes: pkg.TimeLimitMinutes,
+		MaxPoints:        pkg.MaxPoints,
+		Questions:        pkg.Questions,
+		Participants:     make([]models.OfflinePackageParticipant, 0, len(participants)),
+		GeneratedAt:      time.Now(),
+	}
+	for _, participant := range participants {
+		content.Participants = append(content.Participants, models.OfflinePackageParticipant{
+			UserID:   participant.UserID.Hex(),
+			FullName: participant.FullName,
+			NIM:      participant.NIM,
+			Venue:    participant.Venue,
+			Room:     participant.Room,
+			Seat:     participant.Seat,
+		})
+	}
+
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode offline package: %w", err)
+	}
+
+	nonce, payload, err := utils.EncryptAESGCM(pkg.EncryptionKey, plaintext, []byte(pkg.ID.Hex()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt offline package: %w", err)
+	}
+
+	return &models.EncryptedOfflineFile{
+		Version:   offlineFileVersion,
+		PackageID: pkg.ID.Hex(),
+		Algorithm: offlineFileAlgorithm,
+		Nonce:     nonce,
+		Payload:   payload,
+	}, nil
+}
+
+// IngestOfflineAnswers decrypts an answer file from an invigilator client and scores each submission.
+// Submissions are validated individually so one bad record doesn't reject the whole venue.
+func (s *examService) IngestOfflineAnswers(ctx context.Context, sittingID, packageID primitive.ObjectID, file *models.EncryptedOfflineFile) (*models.IngestOfflineAnswersResponse, error) {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := s.examRepo.GetOfflinePackage(ctx, sittingID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Version != offlineFileVersion || file.Algorithm != offlineFileAlgorithm {
+		return nil, models.BadRequestError("unsupported offline file format")
+	}
+	if file.PackageID != pkg.ID.Hex() {
+		return nil, models.BadRequestError("answer file belongs to a different package")
+	}
+
+	plaintext, err := utils.DecryptAESGCM(pkg.EncryptionKey, file.Nonce, file.Payload, []byte(pkg.ID.Hex()))
+	if err != nil {
+		return nil, models.BadRequestError(err.Error())
+	}
+
+	var answers models.OfflineAnswerFile
+	if err := json.Unmarshal(plaintext, &answers); err != nil {
+		return nil, models.BadRequestError("answer file content is not valid JSON")
+	}
+	if answers.PackageID != pkg.ID.Hex() {
+		return nil, models.BadRequestError("answer file belongs to a different package")
+	}
+	if len(answers.Submissions) == 0 {
+		return nil, models.BadRequestError("answer file contains no submissions")
+	}
+	if len(answers.Submissions) > maxOfflineSubmissions {
+		return nil, models.BadRequestError(fmt.Sprintf("answer file contains more than %d submissions", maxOfflineSubmissions))
+	}
+
+	participants, err := s.examRepo.ListParticipants(ctx, sittingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	seated := make(map[primitive.ObjectID]bool, len(participants))
+	for _, participant := range participants {
+		seated[participant.UserID] = true
+	}
+
+	response := &models.IngestOfflineAnswersResponse{
+		PackageID:   pkg.ID.Hex(),
+		Submissions: len(answers.Submissions),
+		Results:     make([]models.OfflineSubmissionResult, 0, len(answers.Submissions)),
+	}
+
+	for i, submission := range answers.Submissions {
+		result := s.ingestSubmission(ctx, sitting, pkg, seated, submission)
+		result.Index = i
+		result.UserID = submission.UserID
+
+		switch result.Status {
+		case models.OfflineSubmissionScored:
+			response.Scored++
+		case models.OfflineSubmissionDuplicate:
+			response.Duplicates++
+		case models.OfflineSubmissionInvalid:
+			response.Invalid++
+		case models.OfflineSubmissionFailed:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// Private helper methods
+
+// ingestSubmission validates and scores one student's offline attempt
+func (s *examService) ingestSubmission(ctx context.Context, sitting *models.ExamSitting, pkg *models.OfflineExamPackage, seated map[primitive.ObjectID]bool, submission models.OfflineSubmission) models.OfflineSubmissionResult {
+	invalid := func(message string) models.OfflineSubmissionResult {
+		return models.OfflineSubmissionResult{Status: models.OfflineSubmissionInvalid, Message: message}
+	}
+
+	userID, err := primitive.ObjectIDFromHex(submission.UserID)
+	if err != nil {
+		return invalid("invalid user ID")
+	}
+	if !seated[userID] {
+		return invalid("student is not assigned to this sitting")
+	}
+
+	questions, endTime, err := s.buildOfflineAttempt(sitting, pkg, submission)
+	if err != nil {
+		return invalid(err.Error())
+	}
+
+	claimed, err := s.examRepo.ClaimOfflineSubmission(ctx, pkg.ID, userID)
+	if err != nil {
+		return models.OfflineSubmissionResult{Status: models.OfflineSubmissionFailed, Message: err.Error()}
+	}
+	if !claimed {
+		return models.OfflineSubmissionResult{
+			Status:  models.OfflineSubmissionDuplicate,
+			Message: "answers for this student were already ingested",
+		}
+	}
+
+	packageID := pkg.ID
+	session := &models.QuizSession{
+		UserID:           userID,
+		QuizType:         pkg.QuizType,
+		MaxPoints:        pkg.MaxPoints,
+		TimeLimitMinutes: pkg.TimeLimitMinutes,
+		Questions:        questions,
+		StartTime:        submission.StartedAt,
+		OfflinePackageID: &packageID,
+		ExamSittingID:    &sitting.ID,
+		Scoring:          pkg.Scoring,
+	}
+	session.HideCorrectAnswers = pkg.HideCorrectAnswers
+
+	detailed, err := s.quizSessionService.RecordCompletedSession(ctx, session, endTime)
+	if err != nil {
+		if releaseErr := s.examRepo.ReleaseOfflineSubmission(ctx, pkg.ID, userID); releaseErr != nil {
+			slog.ErrorContext(ctx, "Failed to release offline submission claim", "error", releaseErr)
+		}
+		return models.OfflineSubmissionResult{Status: models.OfflineSubmissionFailed, Message: err.Error()}
+	}
+
+	return models.OfflineSubmissionResult{
+		Status:     models.OfflineSubmissionScored,
+		ResultID:   detailed.ID.Hex(),
+		FinalScore: detailed.FinalScore,
+	}
+}
+
+// buildOfflineAttempt applies a submission's answers to the package questions and returns the
+// end time to score with, capped at the time limit
+func (s *examService) buildOfflineAttempt(sitting *models.ExamSitting, pkg *models.OfflineExamPackage, submission models.OfflineSubmission) ([]models.SessionQuestion, time.Time, error) {
+	if submission.StartedAt.IsZero() || submission.SubmittedAt.IsZero() {
+		return nil, time.Time{}, errors.New("started_at and submitted_at are required")
+	}
+	if submission.SubmittedAt.Before(submission.StartedAt) {
+		return nil, time.Time{}, errors.New("submitted_at is before started_at")
+	}
+	if submission.StartedAt.Before(sitting.ScheduledStart.Add(-attendanceEarlyStartWindow)) ||
+		submission.StartedAt.After(sitting.ScheduledEnd) {
+		return nil, time.Time{}, errors.New("attempt started outside the sitting schedule")
+	}
+	if submission.SubmittedAt.After(time.Now().Add(offlineClockSkew)) {
+		return nil, time.Time{}, errors.New("submitted_at is in the future")
+	}
+
+	endTime := submission.SubmittedAt
+	deadline := submission.StartedAt.Add(time.Duration(pkg.TimeLimitMinutes) * time.Minute)
+	if endTime.After(deadline) {
+		endTime = deadline
+	}
+
+	questions := make([]models.SessionQuestion, len(pkg.Questions))
+	copy(questions, pkg.Questions)
+
+	indexByID := make(map[string]int, len(questions))
+	for i, question := range questions {
+		indexByID[question.QuestionID.Hex()] = i
+	}
+
+	seen := make(map[string]bool, len(submission.Answers))
+	for _, answer := range submission.Answers {
+		index, ok := indexByID[answer.QuestionID]
+		if !ok {
+			return nil, time.Time{}, fmt.Errorf("question %s is not part of this package", answer.QuestionID)
+		}
+		if seen[answer.QuestionID] {
+			return nil, time.Time{}, fmt.Errorf("question %s is answered more than once", answer.QuestionID)
+		}
+		seen[answer.QuestionID] = true
+
+		question := &questions[index]
+		question.TimeSpent = answer.TimeSpent
+
+		if answer.Skipped {
+			question.IsSkipped = true
+			continue
+		}
+
+		value, err := normalizeOfflineAnswer(question, answer.Answer)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("question %s: %v", answer.QuestionID, err)
+		}
+		if value != nil {
+			question.UserAnswer = value
+			question.IsAnswered = true
+		}
+	}
+
+	return questions, endTime, nil
+}
+
+// normalizeOfflineAnswer converts a decoded JSON answer into the form online sessions store,
+// checking that choice answers reference the question's options. A nil result means unanswered.
+func normalizeOfflineAnswer(question *models.SessionQuestion, raw interface{}) (interface{}, error) {
+	if question.Type == models.FillInBlank {
+		if raw == nil {
+			return nil, nil
+		}
+		responses, ok := models.BlankResponses(raw, question.Blanks)
+		if !ok {
+			return nil, errors.New("fill-in-the-blank answer must map blank IDs to text, or list them in blank order")
+		}
+		if len(responses) == 0 {
+			return nil, nil
+		}
+		return responses, nil
+	}
+
+	if question.Type == models.Numeric {
+		if raw == nil {
+			return nil, nil
+		}
+		if text, ok := raw.(string); ok && strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+		if _, _, ok := models.NumericResponse(raw); !ok {
+			return nil, errors.New("numeric answer must be a number, optionally followed by its unit")
+		}
+		return raw, nil
+	}
+
+	var values []string
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, errors.New("answer must be a string or a list of strings")
+			}
+			values = append(values, str)
+		}
+		if len(values) == 0 {
+			return nil, nil
+		}
+	default:
+		return nil, errors.New("answer must be a string or a list of strings")
+	}
+
+	if question.Type == models.Essay {
+		if len(values) != 1 {
+			return nil, errors.New("essay answer must be a single string")
+		}
+		return values[0], nil
+	}
+
+	optionIDs := make(map[string]bool, len(question.Options))
+	for _, option := range question.Options {
+		optionIDs[option.ID] = true
+	}
+	for _, value := range values {
+		if !optionIDs[value] {
+			return nil, fmt.Errorf("unknown option %s", value)
+		}
+	}
+
+	if question.Type == models.SingleChoice {
+		if len(values) != 1 {
+			return nil, errors.New("single choice answer must select exactly one option")
+		}
+		return values[0], nil
+	}
+
+	if question.Type == models.Ordering {
+		placed := make(map[string]bool, len(values))
+		for _, value := range values {
+			if placed[value] {
+				return nil, fmt.Errorf("option %s is placed more than once", value)
+			}
+			placed[value] = true
+		}
+		if len(values) != len(question.Options) {
+			return nil, errors.New("ordering answer must place every option")
+		}
+	}
+
+	return values, nil
+}
+
+// examStudent holds the identifying fields copied onto participation records
+type examStudent struct {
+	UserID   primitive.ObjectID
+	FullName string
+	Email    string
+	NIM      string
+}
+
+// resolveStudent finds the student an assignment refers to, by user ID or NIM
+func (s *examService) resolveStudent(ctx context.Context, assignment models.SeatAssignment) (*examStudent, error) {
+	if assignment.UserID == "" && assignment.NIM == "" {
+		return nil, errors.New("user_id or nim is required")
+	}
+
+	if assignment.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(assignment.UserID)
+		if err != nil {
+			return nil, errors.New("invalid user ID")
+		}
+		student := s.lookupStudent(ctx, userID)
+		if student.Email == "" {
+			return nil, repository.ErrUserNotFound
+		}
+		return student, nil
+	}
+
+	mahasiswa, err := s.userRepo.GetMahasiswaByNIM(ctx, assignment.NIM)
+	if err != nil {
+		return nil, errors.New("no mahasiswa with this NIM")
+	}
+
+	return &examStudent{
+		UserID:   mahasiswa.ID,
+		FullName: mahasiswa.FullName,
+		Email:    mahasiswa.Email,
+		NIM:      mahasiswa.NIM,
+	}, nil
+}
+
+// lookupStudent returns whatever is known about a user; fields stay empty when the user no longer exists
+func (s *examService) lookupStudent(ctx context.Context, userID primitive.ObjectID) *examStudent {
+	student := &examStudent{UserID: userID}
+
+	if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, userID); err == nil {
+		student.FullName = mahasiswa.FullName
+		student.Email = mahasiswa.Email
+		student.NIM = mahasiswa.NIM
+	} else if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+		student.FullName = user.FullName
+		student.Email = user.Email
+	}
+
+	return student
+}
+
+// applySessions fills in the session columns of a record from the user's sessions in the sitting window
+func (s *examService) applySessions(record *models.AttendanceRecord, sitting *models.ExamSitting, sessions []models.QuizSession) {
+	if len(sessions) == 0 {
+		return
+	}
+
+	first := sessions[0]
+	startedAt := first.StartTime
+	record.SessionID = first.ID.Hex()
+	record.StartedAt = &startedAt
+	record.StartIP = first.StartIP
+	record.SessionStatus = first.Status
+	record.SessionCount = len(sessions)
+
+	ips := make(map[string]bool)
+	for _, session := range sessions {
+		if session.StartIP != "" {
+			ips[session.StartIP] = true
+		}
+	}
+	record.IPAddressCount = len(ips)
+
+	lateAfter := sitting.ScheduledStart.Add(time.Duration(sitting.LateAfterMinutes) * time.Minute)
+	if startedAt.After(lateAfter) {
+		record.Status = models.AttendanceLate
+		record.MinutesLate = int(startedAt.Sub(sitting.ScheduledStart).Minutes())
+	} else {
+		record.Status = models.AttendancePresent
+	}
+}
+
+func moderationQueueItem(result *models.DetailedQuizResult) models.ModerationQueueItem {
+	return models.ModerationQueueItem{
+		ResultID:        result.ID,
+		UserID:          result.UserID,
+		FinalScore:      result.FinalScore,
+		TotalPoints:     result.TotalPoints,
+		ScorePercentage: result.ScorePercentage,
+		SubmittedAt:     result.SubmittedAt,
+		Moderation:      result.Moderation,
+		Integrity:       result.Integrity,
+	}
+}
+
+// resultReleaseTime validates the release time for a mode; only scheduled releases keep one
+func resultReleaseTime(mode models.ResultReleaseMode, releaseAt *time.Time) (*time.Time, error) {
+	if mode != models.ResultReleaseScheduled {
+		return nil, nil
+	}
+	if releaseAt == nil || releaseAt.IsZero() {
+		return nil, models.BadRequestError("results_release_at is required for scheduled release")
+	}
+	return releaseAt, nil
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FlashcardService interface {
+	// Deck generation
+	GenerateModuleDeck(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.GenerateDeckResponse, error)
+	GenerateMissedQuestionsDeck(ctx context.Context, userID primitive.ObjectID, req *models.GenerateMissedDeckRequest) (*models.GenerateDeckResponse, error)
+
+	// Decks & cards
+	ListDecks(ctx context.Context, userID primitive.ObjectID) ([]models.FlashcardDeck, error)
+	GetDeckCards(ctx context.Context, userID, deckID primitive.ObjectID) ([]models.Flashcard, error)
+	DeleteDeck(ctx context.Context, userID, deckID primitive.ObjectID) error
+
+	// Review
+	GetDueCards(ctx context.Context, userID primitive.ObjectID, deckID *primitive.ObjectID, limit int) (*models.DueFlashcardsResponse, error)
+	ReviewCard(ctx context.Context, userID, cardID primitive.ObjectID, quality int) (*models.Flashcard, error)
+}
+
+type flashcardService struct {
+	flashcardRepo repository.FlashcardRepository
+	moduleRepo    repository.ModuleRepository
+	sessionRepo   repository.QuizSessionRepository
+	questionRepo  repository.QuestionRepository
+	examRepo      repository.ExamRepository
+}
+
+func NewFlashcardService(
+	flashcardRepo repository.FlashcardRepository,
+	moduleRepo repository.ModuleRepository,
+	sessionRepo repository.QuizSessionRepository,
+	questionRepo repository.QuestionRepository,
+	examRepo repository.ExamRepository,
+) FlashcardService {
+	return &flashcardService{
+		flashcardRepo: flashcardRepo,
+		moduleRepo:    moduleRepo,
+		sessionRepo:   sessionRepo,
+		questionRepo:  questionRepo,
+		examRepo:      examRepo,
+	}
+}
+
+func (s *flashcardService) GenerateModuleDeck(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.GenerateDeckResponse, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !module.IsPublished {
+		return nil, repository.ErrModuleNotFound
+	}
+
+	deck := &models.FlashcardDeck{
+		UserID:   userID,
+		Name:     module.Name,
+		Source:   models.FlashcardSourceModule,
+		SourceID: &module.ID,
+	}
+	if err := s.flashcardRepo.UpsertDeck(ctx, deck); err != nil {
+		return nil, fmt.Errorf("failed to create flashcard deck: %w", err)
+	}
+
+	now := time.Now()
+	var cards []models.Flashcard
+	var sourceKeys []string
+	for _, subModule := range module.SubModules {
+		if !module.SubModulePublished(subModule.ID) {
+			continue
+		}
+
+		for i, point := range extractKeyPoints(subModule) {
+			sourceKey := fmt.Sprintf("%s:%d", subModule.ID.Hex(), i)
+			sourceKeys = append(sourceKeys, sourceKey)
+			cards = append(cards, models.Flashcard{
+				DeckID:    deck.ID,
+				UserID:    userID,
+				Source:    models.FlashcardSourceModule,
+				SourceID:  subModule.ID,
+				SourceKey: sourceKey,
+				Front:     point.front,
+				Back:      point.back,
+				Schedule:  utils.NewReviewSchedule(now),
+			})
+		}
+	}
+
+	if len(cards) == 0 {
+		return nil, models.NewError(http.StatusUnprocessableEntity, "module has no key points to generate flashcards from")
+	}
+
+	if err := s.flashcardRepo.UpsertCards(ctx, cards); err != nil {
+		return nil, fmt.Errorf("failed to save flashcards: %w", err)
+	}
+
+	// Drop cards for key points that were removed from the module since the last generation
+	if err := s.flashcardRepo.DeleteCardsNotIn(ctx, deck.ID, sourceKeys); err != nil {
+		return nil, fmt.Errorf("failed to prune flashcards: %w", err)
+	}
+
+	return s.buildDeckResponse(ctx, userID, deck)
+}
+
+func (s *flashcardService) GenerateMissedQuestionsDeck(ctx context.Context, userID primitive.ObjectID, req *models.GenerateMissedDeckRequest) (*models.GenerateDeckResponse, error) {
+	resultLimit := req.Results
+	if resultLimit <= 0 {
+		resultLimit = 20
+	}
+
+	results, err := s.sessionRepo.GetUserDetailedResults(ctx, userID, req.QuizType, resultLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz results: %w", err)
+	}
+
+	// Embargoed results lose their answers, so they can't reveal what was missed
+	newResultReleaseChecker(s.examRepo).applyDetailed(ctx, results)
+
+	// Collect each incorrectly answered question once
+	seen := make(map[primitive.ObjectID]bool)
+	var missedIDs []primitive.ObjectID
+	for _, result := range results {
+		// Cards show the answer, which these quizzes keep from students
+		if result.CorrectAnswersHidden {
+			continue
+		}
+		for _, qr := range result.QuestionResults {
+			if qr.IsCorrect || qr.IsSkipped || qr.UserAnswer == nil || seen[qr.QuestionID] {
+				continue
+			}
+			seen[qr.QuestionID] = true
+			missedIDs = append(missedIDs, qr.QuestionID)
+		}
+	}
+
+	if len(missedIDs) == 0 {
+		return nil, models.NotFoundError("no missed questions found")
+	}
+
+	questions, err := s.questionRepo.GetByIDs(ctx, missedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load missed questions: %w", err)
+	}
+
+	deck := &models.FlashcardDeck{
+		UserID: userID,
+		Name:   "Missed Questions",
+		Source: models.FlashcardSourceMissed,
+	}
+	if err := s.flashcardRepo.UpsertDeck(ctx, deck); err != nil {
+		return nil, fmt.Errorf("failed to create flashcard deck: %w", err)
+	}
+
+	now := time.Now()
+	var cards []models.Flashcard
+	for _, q := range questions {
+		back := questionAnswerText(q)
+		if back == "" {
+			continue
+		}
+
+		cards = append(cards, models.Flashcard{
+			DeckID:    deck.ID,
+			UserID:    userID,
+			Source:    models.FlashcardSourceMissed,
+			SourceID:  q.ID,
+			SourceKey: q.ID.Hex(),
+			Front:     q.Title,
+			Back:      back,
+			Schedule:  utils.NewReviewSchedule(now),
+		})
+	}
+
+	if err := s.flashcardRepo.UpsertCards(ctx, cards); err != nil {
+		return nil, fmt.Errorf("failed to save flashcards: %w", err)
+	}
+
+	return s.buildDeckResponse(ctx, userID, deck)
+}
+
+func (s *flashcardService) ListDecks(ctx context.Context, userID primitive.ObjectID) ([]models.FlashcardDeck, error) {
+	decks, err := s.flashcardRepo.ListDecks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flashcard decks: %w", err)
+	}
+
+	if decks == nil {
+		decks = []models.FlashcardDeck{}
+	}
+
+	return decks, nil
+}
+
+func (s *flashcardService) GetDeckCards(ctx context.Context, userID, deckID primitive.ObjectID) ([]models.Flashcard, error) {
+	if _, err := s.flashcardRepo.GetDeck(ctx, userID, deckID); err != nil {
+		return nil, err
+	}
+
+	cards, err := s.flashcardRepo.ListCards(ctx, userID, deckID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flashcards: %w", err)
+	}
+
+	if cards == nil {
+		cards = []models.Flashcard{}
+	}
+
+	return cards, nil
+}
+
+func (s *flashcardService) DeleteDeck(ctx context.Context, userID, deckID primitive.ObjectID) error {
+	return s.flashcardRepo.DeleteDeck(ctx, userID, deckID)
+}
+
+func (s *flashcardService) GetDueCards(ctx context.Context, userID primitive.ObjectID, deckID *primitive.ObjectID, limit int) (*models.DueFlashcardsResponse, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	cards, err := s.flashcardRepo.GetDueCards(ctx, userID, deckID, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due flashcards: %w", err)
+	}
+
+	if cards == nil {
+		cards = []models.Flashcard{}
+	}
+
+	return &models.DueFlashcardsResponse{
+		Cards: cards,
+		Total: len(cards),
+	}, nil
+}
+
+func (s *flashcardService) ReviewCard(ctx context.Context, userID, cardID primitive.ObjectID, quality int) (*models.Flashcard, error) {
+	card, err := s.flashcardRepo.GetCard(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	card.Schedule = utils.ScheduleReview(card.Schedule, quality, time.Now())
+	if err := s.flashcardRepo.UpdateSchedule(ctx, card.ID, card.Schedule); err != nil {
+		return nil, fmt.Errorf("failed to update flashcard schedule: %w", err)
+	}
+
+	return card, nil
+}
+
+// Private helper methods
+
+func (s *flashcardService) buildDeckResponse(ctx context.Context, userID primitive.ObjectID, deck *models.FlashcardDeck) (*models.GenerateDeckResponse, error) {
+	count, err := s.flashcardRepo.RefreshDeckCount(ctx, deck.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deck: %w", err)
+	}
+	deck.CardCount = count
+
+	cards, err := s.flashcardRepo.ListCards(ctx, userID, deck.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flashcards: %w", err)
+	}
+
+	if cards == nil {
+		cards = []models.Flashcard{}
+	}
+
+	return &models.GenerateDeckResponse{
+		Deck:  *deck,
+		Cards: cards,
+	}, nil
+}
+
+type keyPoint struct {
+	front string
+	back  string
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	markdownBullet  = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.+)$`)
+)
+
+// extractKeyPoints turns a submodule's markdown into front/back pairs: each heading with bullet
+// points beneath it becomes a card. Submodules without bullets fall back to name/description.
+func extractKeyPoints(subModule models.SubModule) []keyPoint {
+	var points []keyPoint
+	heading := subModule.Name
+	var bullets []string
+
+	flush := func() {
+		if len(bullets) > 0 {
+			points = append(points, keyPoint{front: heading, back: strings.Join(bullets, "\n")})
+		}
+		bullets = nil
+	}
+
+	for _, line := range strings.Split(subModule.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if match := markdownHeading.FindStringSubmatch(line); match != nil {
+			flush()
+			heading = fmt.Sprintf("%s: %s", subModule.Name, strings.TrimSpace(match[1]))
+			continue
+		}
+		if match := markdownBullet.FindStringSubmatch(line); match != nil {
+			bullets = append(bullets, "• "+strings.TrimSpace(match[1]))
+		}
+	}
+	flush()
+
+	if len(points) == 0 && strings.TrimSpace(subModule.Description) != "" {
+		points = append(points, keyPoint{front: subModule.Name, back: subModule.Description})
+	}
+
+	return points
+}
+
+// questionAnswerText renders the correct answer of a question as readable text
+func questionAnswerText(q *models.Question) string {
+	if q.Type == models.Essay {
+		return q.SampleAnswer
+	}
+	if q.Type == models.FillInBlank {
+		return models.FillBlanks(q.Title, q.Blanks)
+	}
+	if q.Type == models.Numeric && q.AnswerExpression != "" {
+		// The numbers vary per session, so the card shows how the answer is worked out
+		return strings.TrimSpace("= " + q.AnswerExpression + " " + numericUnit(q))
+	}
+	if q.Type == models.Numeric && q.NumericAnswer != nil {
+		return q.NumericAnswer.String()
+	}
+	if q.Type == models.Ordering {
+		var steps []string
+		for i, option := range q.Options {
+			steps = append(steps, fmt.Sprintf("%d. %s", i+1, option.Text))
+		}
+		return strings.Join(steps, "\n")
+	}
+
+	var answers []string
+	for _, option := range q.Options {
+		for _, correctID := range q.CorrectAnswers {
+			if option.ID == correctID {
+				answers = append(answers, option.Text)
+			}
+		}
+	}
+
+	return strings.Join(answers, "\n")
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// guestSessionDuration is how long a guest can keep taking quizzes and still claim the results
+const guestSessionDuration = 24 * time.Hour
+
+type GuestService interface {
+	StartGuestSession(ctx context.Context) (*models.GuestSessionResponse, error)
+	ClaimGuestResults(ctx context.Context, userID primitive.ObjectID, guestToken string) (*models.ClaimGuestResultsResponse, error)
+}
+
+type guestService struct {
+	sessionRepo      repository.QuizSessionRepository
+	userActivityRepo repository.UserActivityRepository
+	jwtManager       *utils.JWTManager
+}
+
+func NewGuestService(
+	sessionRepo repository.QuizSessionRepository,
+	userActivityRepo repository.UserActivityRepository,
+	jwtManager *utils.JWTManager,
+) GuestService {
+	return &guestService{
+		sessionRepo:      sessionRepo,
+		userActivityRepo: userActivityRepo,
+		jwtManager:       jwtManager,
+	}
+}
+
+// StartGuestSession issues an ephemeral identity; nothing is stored until the guest starts a quiz
+func (s *guestService) StartGuestSession(ctx context.Context) (*models.GuestSessionResponse, error) {
+	guestID := primitive.NewObjectID()
+
+	token, err := s.jwtManager.GenerateGuestToken(guestID, guestSessionDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate guest token: %w", err)
+	}
+
+	return &models.GuestSessionResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		GuestID:     guestID.Hex(),
+		ExpiresIn:   int64(guestSessionDuration.Seconds()),
+		Message:     "Guest session started. Register and claim your results to keep them",
+	}, nil
+}
+
+// ClaimGuestResults moves everything recorded under the guest token's identity to the user
+func (s *guestService) ClaimGuestResults(ctx context.Context, userID primitive.ObjectID, guestToken string) (*models.ClaimGuestResultsResponse, error) {
+	claims, err := s.jwtManager.ValidateToken(guestToken)
+	if err != nil || claims.UserType != utils.GuestUserType {
+		return nil, models.NewCodedError(http.StatusBadRequest, models.ErrorCodeTokenInvalid, "invalid or expired guest token")
+	}
+
+	guestID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return nil, models.NewCodedError(http.StatusBadRequest, models.ErrorCodeTokenInvalid, "invalid or expired guest token")
+	}
+
+	claimedSessions, claimedResults, err := s.sessionRepo.TransferGuestSessions(ctx, guestID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userActivityRepo.TransferQuizResults(ctx, guestID, userID); err != nil {
+		return nil, fmt.Errorf("failed to transfer quiz results: %w", err)
+	}
+
+	if claimedSessions == 0 && claimedResults == 0 {
+		return nil, models.NotFoundError("no guest results to claim")
+	}
+
+	return &models.ClaimGuestResultsResponse{
+		ClaimedSessions: claimedSessions,
+		ClaimedResults:  claimedResults,
+		Message:         "Guest results added to your account",
+	}, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+)
+
+// healthCheckTimeout keeps a hanging database from holding probes past their own timeouts
+const healthCheckTimeout = 2 * time.Second
+
+type HealthService interface {
+	Health(ctx context.Context) *models.HealthResponse
+	Readiness(ctx context.Context) *models.ReadinessResponse
+	SetReady(ready bool)
+}
+
+type healthService struct {
+	healthRepo   repository.HealthRepository
+	jobScheduler JobScheduler
+	tasks        *utils.BackgroundTasks
+
+	// ready is off until startup finishes and again once shutdown begins
+	ready atomic.Bool
+}
+
+func NewHealthService(healthRepo repository.HealthRepository, jobScheduler JobScheduler, tasks *utils.BackgroundTasks) HealthService {
+	return &healthService{
+		healthRepo:   healthRepo,
+		jobScheduler: jobScheduler,
+		tasks:        tasks,
+	}
+}
+
+// Health pings the database and reports the background work of this instance. It is degraded while
+// the database is unreachable.
+func (s *healthService) Health(ctx context.Context) *models.HealthResponse {
+	pending, byKind := s.tasks.Pending()
+	response := &models.HealthResponse{
+		Status:    models.HealthStatusOK,
+		Service:   "quizapp-backend",
+		Database:  s.pingDatabase(ctx),
+		Queue:     models.QueueHealth{Pending: pending, ByKind: byKind},
+		Scheduler: s.jobScheduler.Status(),
+	}
+	if response.Database.Error != "" {
+		response.Status = models.HealthStatusDegraded
+	}
+	return response
+}
+
+// Readiness reports whether this instance should get traffic: startup has finished, shutdown has not
+// begun and the database answers
+func (s *healthService) Readiness(ctx context.Context) *models.ReadinessResponse {
+	if !s.ready.Load() {
+		return &models.ReadinessResponse{Ready: false, Reason: "server is starting or shutting down"}
+	}
+	if database := s.pingDatabase(ctx); database.Error != "" {
+		return &models.ReadinessResponse{Ready: false, Reason: "database is unreachable"}
+	}
+	return &models.ReadinessResponse{Ready: true}
+}
+
+func (s *healthService) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// Private helper methods
+
+func (s *healthService) pingDatabase(ctx context.Context) models.DatabaseHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.healthRepo.Ping(ctx)
+	health := models.DatabaseHealth{
+		Status:    "connected",
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		health.Status = "unreachable"
+		health.Error = err.Error()
+	}
+	return health
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecordIntegrityEvents stores integrity events the quiz client reports for the caller's session.
+// Events are only taken while the session is in progress, since its result summarizes them on submission.
+func (s *quizSessionService) RecordIntegrityEvents(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.RecordIntegrityEventsRequest) (*models.RecordIntegrityEventsResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsTakenBy(userID) {
+		return nil, repository.ErrSessionNotFound
+	}
+	if session.Status != models.QuizInProgress {
+		return nil, ErrSessionNotActive
+	}
+
+	now := time.Now()
+	for i := range req.Events {
+		req.Events[i].Detail = strings.TrimSpace(req.Events[i].Detail)
+		req.Events[i].ReceivedAt = now
+		if index := req.Events[i].QuestionIndex; index != nil && *index >= len(session.Questions) {
+			req.Events[i].QuestionIndex = nil
+		}
+	}
+
+	recorded, err := s.sessionRepo.AppendIntegrityEvents(ctx, session.ID, req.Events)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrSessionNotActive
+	}
+
+	return &models.RecordIntegrityEventsResponse{Accepted: len(req.Events)}, nil
+}
+
+// GetSessionIntegrity returns a session's integrity events with their summary for admin review
+func (s *quizSessionService) GetSessionIntegrity(ctx context.Context, sessionID primitive.ObjectID) (*models.SessionIntegrityResponse, error) {
+	session, err := s.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := session.IntegrityEvents
+	if events == nil {
+		events = []models.IntegrityEvent{}
+	}
+	return &models.SessionIntegrityResponse{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		QuizType:  session.QuizType,
+		Status:    session.Status,
+		Summary:   models.SummarizeIntegrity(session.IntegrityEvents, session.IntegrityEventCount),
+		Events:    events,
+	}, nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultInvitationExpiry applies when the admin doesn't choose one
+const defaultInvitationExpiry = 72 * time.Hour
+
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateInvitationRequest, registerURL string) (*models.CreateInvitationResponse, error)
+	ListInvitations(ctx context.Context, req *models.ListInvitationsRequest) (*models.ListInvitationsResponse, error)
+	RevokeInvitation(ctx context.Context, adminID, invitationID primitive.ObjectID) (*models.Invitation, error)
+	LookupInvitation(ctx context.Context, token string) (*models.InvitationLookupResponse, error)
+
+	// Registration
+	ValidateInvitation(ctx context.Context, token, email string, userType models.UserType) (*models.Invitation, error)
+	AcceptInvitation(ctx context.Context, invitation *models.Invitation, userID primitive.ObjectID) error
+}
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	jwtManager     *utils.JWTManager
+	emailService   *utils.EmailService
+}
+
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	jwtManager *utils.JWTManager,
+	emailService *utils.EmailService,
+) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		jwtManager:     jwtManager,
+		emailService:   emailService,
+	}
+}
+
+func (s *invitationService) CreateInvitation(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateInvitationRequest, registerURL string) (*models.CreateInvitationResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	// Check if an account already exists in any collection
+	if existing, _ := s.userRepo.GetByEmail(ctx, email); existing != nil {
+		return nil, ErrEmailTaken
+	}
+	if existing, _ := s.userRepo.GetMahasiswaByEmail(ctx, email); existing != nil {
+		return nil, ErrEmailTaken
+	}
+	if existing, _ := s.userRepo.GetAdminByEmail(ctx, email); existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	if active, _ := s.invitationRepo.GetActiveByEmail(ctx, email); active != nil {
+		return nil, models.ConflictError("an active invitation already exists for this email")
+	}
+
+	expiry := defaultInvitationExpiry
+	if req.ExpiresInHours > 0 {
+		expiry = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+
+	invitation := &models.Invitation{
+		Email:          email,
+		UserType:       req.UserType,
+		ExpiresAt:      time.Now().Add(expiry),
+		InvitedBy:      adminID,
+		InvitedByEmail: adminEmail,
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	token, err := s.jwtManager.GenerateInvitationToken(invitation.ID, invitation.Email, string(invitation.UserType), invitation.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign invitation: %w", err)
+	}
+	inviteURL := registerURL + "?invite=" + url.QueryEscape(token)
+
+	response := &models.CreateInvitationResponse{
+		Invitation: invitation,
+		InviteURL:  inviteURL,
+		Message:    "Invitation sent",
+	}
+
+	// The link still works if email delivery fails, so the admin can share it another way
+	if err := s.emailService.SendRegistrationInviteEmail(invitation.Email, adminEmail, string(invitation.UserType), inviteURL, invitation.ExpiresAt); err != nil {
+		slog.ErrorContext(ctx, "Failed to send invitation email", "invitation_id", invitation.ID.Hex(), "email", invitation.Email, "error", err)
+		response.Message = "Invitation created but the email could not be sent; share the invite link manually"
+		return response, nil
+	}
+
+	invitation.EmailSent = true
+	if err := s.invitationRepo.SetEmailSent(ctx, invitation.ID, true); err != nil {
+		slog.ErrorContext(ctx, "Failed to record invitation email delivery", "error", err)
+	}
+
+	return response, nil
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, req *models.ListInvitationsRequest) (*models.ListInvitationsResponse, error) {
+	invitations, total, err := s.invitationRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	if invitations == nil {
+		invitations = []models.Invitation{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListInvitationsResponse{
+		Invitations: invitations,
+		Total:       total,
+		Page:        req.Page,
+		Limit:       req.Limit,
+		TotalPages:  totalPages,
+	}, nil
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, adminID, invitationID primitive.ObjectID) (*models.Invitation, error) {
+	return s.invitationRepo.Revoke(ctx, invitationID, adminID)
+}
+
+// LookupInvitation lets the registration page prefill the email and user type of an invite link
+func (s *invitationService) LookupInvitation(ctx context.Context, token string) (*models.InvitationLookupResponse, error) {
+	invitation, err := s.loadInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InvitationLookupResponse{
+		Email:     invitation.Email,
+		UserType:  invitation.UserType,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// ValidateInvitation checks that an invite token is usable for the registration being attempted
+func (s *invitationService) ValidateInvitation(ctx context.Context, token, email string, userType models.UserType) (*models.Invitation, error) {
+	invitation, err := s.loadInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(invitation.Email, strings.TrimSpace(email)) {
+		return nil, errors.New("invitation was issued for a different email")
+	}
+	if invitation.UserType != userType {
+		return nil, fmt.Errorf("invitation is for a %s account", invitation.UserType)
+	}
+
+	return invitation, nil
+}
+
+func (s *invitationService) AcceptInvitation(ctx context.Context, invitation *models.Invitation, userID primitive.ObjectID) error {
+	if err := s.invitationRepo.MarkAccepted(ctx, invitation.ID, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationAccepted
+	invitation.AcceptedAt = &now
+	invitation.AcceptedBy = &userID
+	return nil
+}
+
+// loadInvitation verifies the token signature and returns the invitation if it is still usable
+func (s *invitationService) loadInvitation(ctx context.Context, token string) (*models.Invitation, error) {
+	claims, err := s.jwtManager.ValidateInvitationToken(token)
+	if err != nil {
+		return nil, models.NewCodedError(http.StatusNotFound, models.ErrorCodeTokenInvalid, "invalid or expired invitation")
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(claims.InvitationID)
+	if err != nil {
+		return nil, models.NewCodedError(http.StatusNotFound, models.ErrorCodeTokenInvalid, "invalid or expired invitation")
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return nil, models.NewCodedError(http.StatusNotFound, models.ErrorCodeTokenInvalid, "invalid or expired invitation")
+		}
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	if !invitation.IsUsable() {
+		return nil, models.NewCodedError(http.StatusNotFound, models.ErrorCodeTokenInvalid, "invalid or expired invitation")
+	}
+
+	return invitation, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// Fewer attempts than this leave the indices too noisy to report
+	minItemAnalysisAttempts = 20
+
+	// Share of attempts, by total score, in each of the upper and lower groups
+	itemAnalysisGroupShare = 0.27
+
+	// A wrong option chosen by fewer answered attempts than this does not distract anyone
+	functionalDistractorShare = 0.05
+)
+
+// AnalyzeQuestion runs a classical item analysis of a question over finished attempts: how hard it
+// was, how well it separated stronger students from weaker ones, which wrong options drew answers
+// and how long students spent on it. Flags point out questions worth revising or retiring.
+func (s *analyticsService) AnalyzeQuestion(ctx context.Context, questionID primitive.ObjectID, req *models.QuestionAnalyticsRequest) (*models.QuestionAnalytics, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.analyticsRepo.GetItemResponses(ctx, questionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.QuestionAnalytics{
+		QuestionID: question.ID,
+		Title:      question.Title,
+		Type:       question.Type,
+		Difficulty: question.Difficulty,
+		IsActive:   question.IsActive,
+		Flags:      []string{},
+		DateFrom:   from,
+		DateTo:     to,
+	}
+
+	// Essays awaiting a grade have no score yet
+	scored := rows[:0]
+	var timeSpent int64
+	for _, row := range rows {
+		if row.PendingGrade {
+			analytics.PendingGrades++
+			continue
+		}
+		scored = append(scored, row)
+
+		switch {
+		case row.IsSkipped:
+			analytics.Skipped++
+		case row.UserAnswer != nil:
+			analytics.Answered++
+			timeSpent += row.TimeSpent
+		}
+		if row.IsCorrect {
+			analytics.Correct++
+		}
+	}
+	analytics.Attempts = len(scored)
+	if analytics.Answered > 0 {
+		analytics.AverageTimeSeconds = math.Round(float64(timeSpent)/float64(analytics.Answered)*10) / 10
+	}
+
+	if analytics.Attempts < minItemAnalysisAttempts {
+		return analytics, nil
+	}
+
+	// Upper and lower groups by the attempt's total score
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].ScorePercentage > scored[j].ScorePercentage
+	})
+	groupSize := int(math.Round(float64(len(scored)) * itemAnalysisGroupShare))
+	upper, lower := scored[:groupSize], scored[len(scored)-groupSize:]
+	analytics.GroupSize = groupSize
+
+	difficulty := roundIndex(meanItemScore(scored))
+	discrimination := roundIndex(meanItemScore(upper) - meanItemScore(lower))
+	analytics.DifficultyIndex = &difficulty
+	analytics.DiscriminationIndex = &discrimination
+
+	switch {
+	case difficulty < 0.2:
+		analytics.Flags = append(analytics.Flags, models.ItemFlagTooHard)
+	case difficulty > 0.9:
+		analytics.Flags = append(analytics.Flags, models.ItemFlagTooEasy)
+	}
+	switch {
+	case discrimination < 0:
+		analytics.Flags = append(analytics.Flags, models.ItemFlagNegativeDiscrimination)
+	case discrimination < 0.2:
+		analytics.Flags = append(analytics.Flags, models.ItemFlagPoorDiscrimination)
+	}
+
+	if question.Type == models.SingleChoice || question.Type == models.MultipleChoice {
+		analyzeDistractors(analytics, question, scored, upper, lower)
+	}
+
+	return analytics, nil
+}
+
+// analyzeDistractors counts how often each option was chosen, overall and by the upper and lower groups
+func analyzeDistractors(analytics *models.QuestionAnalytics, question *models.Question, scored, upper, lower []models.ItemResponseRow) {
+	correct := make(map[string]bool, len(question.CorrectAnswers))
+	for _, id := range question.CorrectAnswers {
+		correct[id] = true
+	}
+
+	count := func(rows []models.ItemResponseRow) map[string]int {
+		chosen := make(map[string]int)
+		for _, row := range rows {
+			for _, id := range chosenOptionIDs(row.UserAnswer) {
+				chosen[id]++
+			}
+		}
+		return chosen
+	}
+	all, upperChosen, lowerChosen := count(scored), count(upper), count(lower)
+
+	var distractors, functional int
+	for _, option := range question.Options {
+		stats := models.DistractorStats{
+			OptionID:    option.ID,
+			Text:        option.Text,
+			IsCorrect:   correct[option.ID],
+			Chosen:      all[option.ID],
+			UpperChosen: upperChosen[option.ID],
+			LowerChosen: lowerChosen[option.ID],
+		}
+		if analytics.Answered > 0 {
+			stats.ChosenShare = roundIndex(float64(stats.Chosen) / float64(analytics.Answered))
+		}
+		if analytics.GroupSize > 0 {
+			stats.Discrimination = roundIndex(float64(stats.UpperChosen-stats.LowerChosen) / float64(analytics.GroupSize))
+		}
+		if !stats.IsCorrect {
+			distractors++
+			stats.Functional = stats.ChosenShare >= functionalDistractorShare
+			if stats.Functional {
+				functional++
+			}
+		}
+		analytics.Distractors = append(analytics.Distractors, stats)
+	}
+
+	if distractors > 0 {
+		effectiveness := roundIndex(float64(functional) / float64(distractors))
+		analytics.DistractorEffectiveness = &effectiveness
+		if functional < distractors {
+			analytics.Flags = append(analytics.Flags, models.ItemFlagNonFunctionalDistractor)
+		}
+	}
+}
+
+// meanItemScore is the mean share of the question's points the attempts earned, so partial credit counts
+func meanItemScore(rows []models.ItemResponseRow) float64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, row := range rows {
+		switch {
+		case row.Points > 0:
+			sum += math.Min(1, float64(row.PointsEarned)/float64(row.Points))
+		case row.IsCorrect:
+			sum++
+		}
+	}
+	return sum / float64(len(rows))
+}
+
+// chosenOptionIDs reads the options picked in a stored single or multiple choice answer
+func chosenOptionIDs(answer interface{}) []string {
+	switch v := answer.(type) {
+	case string:
+		return []string{v}
+	case primitive.A:
+		return chosenOptionIDs([]interface{}(v))
+	case []interface{}:
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			if id, ok := item.(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	return nil
+}
+
+func roundIndex(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	mrand "math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+)
+
+// ScheduledJob is a periodic job the scheduler owns. Run returns a short summary of what it did for
+// the run history.
+type ScheduledJob struct {
+	Name        string
+	Description string
+	Interval    time.Duration // 0 disables the job
+	Run         func(ctx context.Context) (string, error)
+}
+
+type JobScheduler interface {
+	Register(job ScheduledJob)
+	Start(ctx context.Context)
+	ListJobs(ctx context.Context) (*models.ListScheduledJobsResponse, error)
+	ListRuns(ctx context.Context, req *models.ListJobRunsRequest) (*models.ListJobRunsResponse, error)
+	Status() models.SchedulerStatus
+}
+
+// schedulerLease is the lease instances hold in turn to lead the scheduler
+const schedulerLease = "scheduler"
+
+// leaseReleaseTimeout bounds handing the lease back on shutdown
+const leaseReleaseTimeout = 5 * time.Second
+
+type jobScheduler struct {
+	jobRepo  repository.JobRepository
+	config   models.SchedulerConfig
+	instance string
+
+	mu         sync.Mutex
+	jobs       []ScheduledJob
+	nextRuns   map[string]time.Time
+	leaseUntil time.Time // This instance leads until then, unless renewing the lease says otherwise
+}
+
+func NewJobScheduler(jobRepo repository.JobRepository, config models.SchedulerConfig) JobScheduler {
+	return &jobScheduler{
+		jobRepo:  jobRepo,
+		config:   config,
+		instance: schedulerInstanceID(),
+		nextRuns: make(map[string]time.Time),
+	}
+}
+
+// Register adds a job; jobs registered after Start are listed but never run
+func (s *jobScheduler) Register(job ScheduledJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+}
+
+// Start campaigns for the leader lease and schedules every enabled job until the context is cancelled.
+// Every instance keeps the schedule, but only the leader runs jobs when they come due, so each run
+// happens once however many instances are deployed.
+func (s *jobScheduler) Start(ctx context.Context) {
+	if !s.config.Enabled || s.config.LeaseTTL <= 0 {
+		slog.InfoContext(ctx, "Job scheduler is disabled on this instance")
+		return
+	}
+
+	s.renewLeadership(ctx)
+	go s.keepLeadership(ctx)
+
+	s.mu.Lock()
+	jobs := append([]ScheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+	for _, job := range jobs {
+		if job.Interval > 0 {
+			go s.runSchedule(ctx, job)
+		}
+	}
+}
+
+func (s *jobScheduler) ListJobs(ctx context.Context) (*models.ListScheduledJobsResponse, error) {
+	s.mu.Lock()
+	jobs := append([]ScheduledJob(nil), s.jobs...)
+	nextRuns := make(map[string]time.Time, len(s.nextRuns))
+	for name, next := range s.nextRuns {
+		nextRuns[name] = next
+	}
+	s.mu.Unlock()
+
+	response := &models.ListScheduledJobsResponse{
+		Instance: s.instance,
+		Leader:   s.isLeader(),
+		Jobs:     make([]models.ScheduledJobStatus, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		status := models.ScheduledJobStatus{
+			Name:        job.Name,
+			Description: job.Description,
+			Interval:    job.Interval.String(),
+			Enabled:     s.config.Enabled && job.Interval > 0,
+		}
+		if next, ok := nextRuns[job.Name]; ok {
+			status.NextRunAt = &next
+		}
+
+		runs, err := s.jobRepo.ListRuns(ctx, job.Name, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job runs: %w", err)
+		}
+		if len(runs) > 0 {
+			status.LastRun = &runs[0]
+		}
+		response.Jobs = append(response.Jobs, status)
+	}
+	return response, nil
+}
+
+func (s *jobScheduler) ListRuns(ctx context.Context, req *models.ListJobRunsRequest) (*models.ListJobRunsResponse, error) {
+	if req.Job != "" && !s.hasJob(req.Job) {
+		return nil, models.NotFoundError("scheduled job not found")
+	}
+
+	runs, err := s.jobRepo.ListRuns(ctx, req.Job, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %w", err)
+	}
+	if runs == nil {
+		runs = []models.JobRun{}
+	}
+	return &models.ListJobRunsResponse{Runs: runs}, nil
+}
+
+// Status describes this instance's part in running jobs without asking the database
+func (s *jobScheduler) Status() models.SchedulerStatus {
+	s.mu.Lock()
+	enabledJobs := 0
+	for _, job := range s.jobs {
+		if job.Interval > 0 {
+			enabledJobs++
+		}
+	}
+	s.mu.Unlock()
+
+	return models.SchedulerStatus{
+		Enabled:  s.config.Enabled,
+		Instance: s.instance,
+		Leader:   s.isLeader(),
+		Jobs:     enabledJobs,
+	}
+}
+
+// Private helper methods
+
+// keepLeadership renews or campaigns for the lease three times per lease period, and hands it back on shutdown
+func (s *jobScheduler) keepLeadership(ctx context.Context) {
+	ticker := time.NewTicker(s.config.LeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if s.isLeader() {
+				releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), leaseReleaseTimeout)
+				if err := s.jobRepo.ReleaseLease(releaseCtx, schedulerLease, s.instance); err != nil {
+					slog.WarnContext(releaseCtx, "Failed to release job scheduler lease", "error", err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+			s.renewLeadership(ctx)
+		}
+	}
+}
+
+func (s *jobScheduler) renewLeadership(ctx context.Context) {
+	attemptedAt := time.Now()
+	acquired, err := s.jobRepo.AcquireLease(ctx, schedulerLease, s.instance, s.config.LeaseTTL)
+	if err != nil {
+		// Whatever lease this instance held stays good until it expires; nobody else can take it sooner
+		if ctx.Err() == nil {
+			slog.WarnContext(ctx, "Failed to renew job scheduler lease", "error", err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	wasLeader := time.Now().Before(s.leaseUntil)
+	if acquired {
+		s.leaseUntil = attemptedAt.Add(s.config.LeaseTTL)
+	} else {
+		s.leaseUntil = time.Time{}
+	}
+	s.mu.Unlock()
+
+	switch {
+	case acquired && !wasLeader:
+		slog.InfoContext(ctx, "Became job scheduler leader", "instance", s.instance)
+	case !acquired && wasLeader:
+		slog.WarnContext(ctx, "Lost job scheduler leadership", "instance", s.instance)
+	}
+}
+
+func (s *jobScheduler) isLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.leaseUntil)
+}
+
+// runSchedule runs the job every interval plus jitter, so instances and jobs sharing an interval
+// don't all hit the database at once
+func (s *jobScheduler) runSchedule(ctx context.Context, job ScheduledJob) {
+	for {
+		delay := job.Interval + s.jitter(job.Interval)
+		s.mu.Lock()
+		s.nextRuns[job.Name] = time.Now().Add(delay)
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if s.isLeader() {
+			s.runJob(ctx, job)
+		}
+	}
+}
+
+// runJob runs the job once and records the run in the history
+func (s *jobScheduler) runJob(ctx context.Context, job ScheduledJob) {
+	startedAt := time.Now()
+	summary, err := job.Run(ctx)
+	finishedAt := time.Now()
+
+	run := &models.JobRun{
+		Job:        job.Name,
+		Instance:   s.instance,
+		Status:     models.JobRunSucceeded,
+		Summary:    summary,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Duration:   finishedAt.Sub(startedAt).Round(time.Millisecond).String(),
+		ExpiresAt:  finishedAt.Add(s.config.HistoryRetention),
+	}
+	if err != nil {
+		run.Status = models.JobRunFailed
+		run.Error = err.Error()
+		slog.ErrorContext(ctx, "Scheduled job failed", "job", job.Name, "duration", run.Duration, "error", err)
+	} else {
+		slog.InfoContext(ctx, "Ran scheduled job", "job", job.Name, "duration", run.Duration, "summary", summary)
+	}
+
+	// A job cut short by shutdown is still recorded
+	if err := s.jobRepo.CreateRun(context.WithoutCancel(ctx), run); err != nil {
+		slog.WarnContext(ctx, "Failed to record job run", "job", job.Name, "error", err)
+	}
+}
+
+// jitter is a random delay of up to the configured share of interval
+func (s *jobScheduler) jitter(interval time.Duration) time.Duration {
+	if s.config.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(mrand.Float64() * s.config.Jitter * float64(interval))
+}
+
+func (s *jobScheduler) hasJob(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulerInstanceID names this process in leases and run history: its host, so admins can find it,
+// and a random suffix, so restarts and replicas sharing a hostname are told apart
+func schedulerInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return host + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// attachmentType is a kind of file modules accept, recognized by its extension and checked against
+// the signature its content starts with
+type attachmentType struct {
+	contentType string
+	signature   []byte
+}
+
+var (
+	pdfSignature = []byte("%PDF-")
+	zipSignature = []byte("PK\x03\x04")                       // pptx, odp and key are zip packages
+	oleSignature = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1") // Legacy Office documents
+)
+
+var attachmentTypes = map[string]attachmentType{
+	".pdf":  {"application/pdf", pdfSignature},
+	".ppt":  {"application/vnd.ms-powerpoint", oleSignature},
+	".pptx": {"application/vnd.openxmlformats-officedocument.presentationml.presentation", zipSignature},
+	".odp":  {"application/vnd.oasis.opendocument.presentation", zipSignature},
+	".key":  {"application/vnd.apple.keynote", zipSignature},
+}
+
+// UploadModuleAttachment stores a PDF or slide deck on a module, or on one of its submodules
+func (s *moduleService) UploadModuleAttachment(ctx context.Context, moduleID primitive.ObjectID, subModuleID *primitive.ObjectID, fileName string, size int64, body io.Reader, userID primitive.ObjectID) (*models.ModuleAttachment, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	if subModuleID != nil && findSubModule(module, *subModuleID) == nil {
+		return nil, ErrSubModuleNotFound
+	}
+
+	if size > s.maxAttachmentSize {
+		return nil, models.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("attachments must be %d MB or smaller", s.maxAttachmentSize>>20))
+	}
+	fileName = strings.TrimSpace(filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	ext := strings.ToLower(filepath.Ext(fileName))
+	kind, ok := attachmentTypes[ext]
+	if !ok {
+		return nil, models.BadRequestError("only PDF and slide files can be attached")
+	}
+	head := make([]byte, len(kind.signature))
+	n, _ := io.ReadFull(body, head)
+	if !bytes.Equal(head[:n], kind.signature) {
+		return nil, models.BadRequestError("file content does not match its type")
+	}
+
+	attachment := &models.ModuleAttachment{
+		ModuleID:    moduleID,
+		SubModuleID: subModuleID,
+		FileName:    fileName,
+		ContentType: kind.contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("modules/%s/%s%s", moduleID.Hex(), primitive.NewObjectID().Hex(), ext),
+		UploadedBy:  userID,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.storage.Put(ctx, attachment.StorageKey, io.MultiReader(bytes.NewReader(head[:n]), body), size, kind.contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+			slog.ErrorContext(ctx, "Failed to remove unrecorded attachment", "storage_key", attachment.StorageKey, "error", err)
+		}
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// GetModuleAttachments lists every attachment of a module, for admins
+func (s *moduleService) GetModuleAttachments(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleAttachmentsResponse, error) {
+	if _, err := s.moduleRepo.GetModuleByID(ctx, moduleID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	return attachmentsResponse(moduleID, attachments), nil
+}
+
+// GetLearnerAttachments lists the attachments of a module the learner can read, leaving out those of
+// submodules that are unpublished or still locked for them
+func (s *moduleService) GetLearnerAttachments(ctx context.Context, moduleID, learnerID primitive.ObjectID) (*models.ModuleAttachmentsResponse, error) {
+	module, err := s.readableModule(ctx, moduleID, learnerID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	visible := make([]models.ModuleAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		if attachmentReadable(module, &attachment) {
+			visible = append(visible, attachment)
+		}
+	}
+	return attachmentsResponse(moduleID, visible), nil
+}
+
+// OpenModuleAttachment opens an attachment's file for an admin. The caller closes it.
+func (s *moduleService) OpenModuleAttachment(ctx context.Context, moduleID, attachmentID primitive.ObjectID) (*models.ModuleAttachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, moduleID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := s.storage.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, utils.ErrStoredFileNotFound) {
+		return nil, nil, models.NotFoundError("attachment file not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return attachment, file, nil
+}
+
+// OpenLearnerAttachment opens an attachment's file for a learner who can read where it is attached.
+// The caller closes it.
+func (s *moduleService) OpenLearnerAttachment(ctx context.Context, moduleID, attachmentID, learnerID primitive.ObjectID) (*models.ModuleAttachment, io.ReadCloser, error) {
+	module, err := s.readableModule(ctx, moduleID, learnerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachment, err := s.attachmentRepo.GetByID(ctx, moduleID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !attachmentReadable(module, attachment) {
+		// Hidden attachments are treated as missing rather than revealed
+		return nil, nil, models.NotFoundError("attachment not found")
+	}
+	return s.OpenModuleAttachment(ctx, moduleID, attachmentID)
+}
+
+// DeleteModuleAttachment removes an attachment along with its file
+func (s *moduleService) DeleteModuleAttachment(ctx context.Context, moduleID, attachmentID primitive.ObjectID) (*models.ModuleAttachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, moduleID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+		return nil, fmt.Errorf("failed to delete attachment file: %w", err)
+	}
+	if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// removeAttachments deletes the attachments of deleted content: the whole module when no submodules
+// are given, otherwise just those submodules. Failures are logged so the deletion itself stands.
+func (s *moduleService) removeAttachments(ctx context.Context, moduleID primitive.ObjectID, subModuleIDs []primitive.ObjectID) {
+	attachments, err := s.attachmentRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list attachments of deleted content", "module_id", moduleID.Hex(), "error", err)
+		return
+	}
+
+	deleted := make(map[primitive.ObjectID]bool, len(subModuleIDs))
+	for _, id := range subModuleIDs {
+		deleted[id] = true
+	}
+	for _, attachment := range attachments {
+		if len(subModuleIDs) > 0 && (attachment.SubModuleID == nil || !deleted[*attachment.SubModuleID]) {
+			continue
+		}
+		if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+			slog.ErrorContext(ctx, "Failed to delete attachment file", "storage_key", attachment.StorageKey, "error", err)
+			continue
+		}
+		if err := s.attachmentRepo.Delete(ctx, attachment.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to delete attachment", "attachment_id", attachment.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// readableModule loads a module gated for the learner, failing unless they can read it
+func (s *moduleService) readableModule(ctx context.Context, moduleID, learnerID primitive.ObjectID) (*models.Module, error) {
+	module, err := s.GetModuleForLearner(ctx, moduleID, &learnerID)
+	if err != nil {
+		return nil, err
+	}
+	if !module.IsPublished {
+		return nil, repository.ErrModuleNotFound
+	}
+	return module, nil
+}
+
+// attachmentReadable reports whether a learner sees an attachment of a module they can read
+func attachmentReadable(module *models.Module, attachment *models.ModuleAttachment) bool {
+	if attachment.SubModuleID == nil {
+		return true
+	}
+	subModule := findSubModule(module, *attachment.SubModuleID)
+	return subModule != nil && !subModule.Locked && module.SubModulePublished(subModule.ID)
+}
+
+func attachmentsResponse(moduleID primitive.ObjectID, attachments []models.ModuleAttachment) *models.ModuleAttachmentsResponse {
+	if attachments == nil {
+		attachments = []models.ModuleAttachment{}
+	}
+	return &models.ModuleAttachmentsResponse{
+		ModuleID:    moduleID,
+		Attachments: attachments,
+		Total:       len(attachments),
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SetSubModuleDiscussion opens or closes the discussion of a submodule. Closing it hides the comments
+// from learners without deleting them.
+func (s *moduleService) SetSubModuleDiscussion(ctx context.Context, moduleID, subModuleID primitive.ObjectID, enabled bool, userID primitive.ObjectID) (*models.SubModule, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	subModule := findSubModule(module, subModuleID)
+	if subModule == nil {
+		return nil, ErrSubModuleNotFound
+	}
+
+	subModule.DiscussionEnabled = enabled
+	subModule.UpdatedAt = time.Now()
+	subModule.UpdatedBy = userID
+	module.UpdatedAt = subModule.UpdatedAt
+	module.UpdatedBy = userID
+
+	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
+		return nil, fmt.Errorf("failed to update submodule discussion: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	return subModule, nil
+}
+
+// GetSubModuleComments returns a page of a submodule's discussion, each top-level comment with its
+// replies
+func (s *moduleService) GetSubModuleComments(ctx context.Context, moduleID, subModuleID, userID primitive.ObjectID, isAdmin bool, req *models.ListModuleCommentsRequest) (*models.ModuleCommentsResponse, error) {
+	if _, _, err := s.discussedSubModule(ctx, moduleID, subModuleID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	comments, total, err := s.commentRepo.ListThreads(ctx, moduleID, subModuleID, req.Page, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	parentIDs := make([]primitive.ObjectID, len(comments))
+	for i := range comments {
+		parentIDs[i] = comments[i].ID
+	}
+	replies, err := s.commentRepo.ListReplies(ctx, parentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replies: %w", err)
+	}
+
+	threads := make(map[primitive.ObjectID]*models.ModuleComment, len(comments))
+	for i := range comments {
+		threads[comments[i].ID] = &comments[i]
+	}
+	for _, reply := range replies {
+		if thread, ok := threads[*reply.ParentID]; ok {
+			thread.Replies = append(thread.Replies, reply)
+		}
+	}
+	if !isAdmin {
+		for i := range comments {
+			hideModeration(&comments[i])
+			for j := range comments[i].Replies {
+				hideModeration(&comments[i].Replies[j])
+			}
+		}
+	}
+
+	return commentsResponse(comments, total, req.Page, req.Limit), nil
+}
+
+// PostSubModuleComment adds a question to a submodule's discussion, or a reply to one. Replies to a
+// reply join the same thread, and everyone already in the thread is notified.
+func (s *moduleService) PostSubModuleComment(ctx context.Context, moduleID, subModuleID, userID primitive.ObjectID, isAdmin bool, req *models.PostModuleCommentRequest) (*models.ModuleComment, error) {
+	_, subModule, err := s.discussedSubModule(ctx, moduleID, subModuleID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.ModuleComment{
+		ModuleID:    moduleID,
+		SubModuleID: subModuleID,
+		AuthorID:    userID,
+		AuthorName:  s.displayName(ctx, userID),
+		FromAdmin:   isAdmin,
+		Body:        req.Body,
+	}
+
+	var parent *models.ModuleComment
+	if req.ParentID != nil {
+		parent, err = s.commentRepo.GetByID(ctx, moduleID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.SubModuleID != subModuleID {
+			return nil, repository.ErrCommentNotFound
+		}
+		if parent.ParentID != nil {
+			if parent, err = s.commentRepo.GetByID(ctx, moduleID, *parent.ParentID); err != nil {
+				return nil, err
+			}
+		}
+		if parent.Removed {
+			return nil, models.ConflictError("comment was removed")
+		}
+		comment.ParentID = &parent.ID
+	}
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	if parent != nil {
+		if err := s.commentRepo.IncrementReplies(ctx, parent.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to count comment reply", "comment_id", parent.ID.Hex(), "error", err)
+		}
+		s.notifyReply(ctx, parent, comment, subModule)
+	}
+	return comment, nil
+}
+
+// ReportModuleComment flags a comment for an admin to review; each learner reports a comment once
+func (s *moduleService) ReportModuleComment(ctx context.Context, moduleID, subModuleID, commentID, userID primitive.ObjectID, req *models.ReportModuleCommentRequest) error {
+	if _, _, err := s.discussedSubModule(ctx, moduleID, subModuleID, userID, false); err != nil {
+		return err
+	}
+	comment, err := s.commentRepo.GetByID(ctx, moduleID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.SubModuleID != subModuleID || comment.Removed {
+		return repository.ErrCommentNotFound
+	}
+	if comment.AuthorID == userID {
+		return models.BadRequestError("cannot report your own comment")
+	}
+
+	report := models.ModuleCommentReport{UserID: userID, Reason: req.Reason, Comment: req.Comment}
+	if err := s.commentRepo.AddReport(ctx, commentID, report); err != nil {
+		if errors.Is(err, repository.ErrCommentAlreadyReported) {
+			return err
+		}
+		return fmt.Errorf("failed to report comment: %w", err)
+	}
+	return nil
+}
+
+// GetReportedModuleComments returns the moderation queue: reported comments not yet removed
+func (s *moduleService) GetReportedModuleComments(ctx context.Context, req *models.ListReportedModuleCommentsRequest) (*models.ModuleCommentsResponse, error) {
+	comments, total, err := s.commentRepo.ListReported(ctx, req.Page, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reported comments: %w", err)
+	}
+	return commentsResponse(comments, total, req.Page, req.Limit), nil
+}
+
+// RemoveModuleComment takes a comment down. Its replies stay, so the rest of the thread still reads.
+func (s *moduleService) RemoveModuleComment(ctx context.Context, moduleID, commentID, adminID primitive.ObjectID) (*models.ModuleComment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, moduleID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Removed {
+		return nil, models.ConflictError("comment was removed")
+	}
+	if err := s.commentRepo.Remove(ctx, commentID, adminID); err != nil {
+		return nil, fmt.Errorf("failed to remove comment: %w", err)
+	}
+	return comment, nil
+}
+
+// DismissModuleCommentReports clears the reports of a comment an admin decided to keep
+func (s *moduleService) DismissModuleCommentReports(ctx context.Context, moduleID, commentID primitive.ObjectID) error {
+	if _, err := s.commentRepo.GetByID(ctx, moduleID, commentID); err != nil {
+		return err
+	}
+	if err := s.commentRepo.ClearReports(ctx, commentID); err != nil {
+		return fmt.Errorf("failed to dismiss comment reports: %w", err)
+	}
+	return nil
+}
+
+// discussedSubModule loads a submodule whose discussion the user may take part in. Learners need to
+// be able to read the submodule, and its discussion to be open; admins may moderate any discussion.
+func (s *moduleService) discussedSubModule(ctx context.Context, moduleID, subModuleID, userID primitive.ObjectID, isAdmin bool) (*models.Module, *models.SubModule, error) {
+	if isAdmin {
+		module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+		if err != nil {
+			return nil, nil, err
+		}
+		subModule := findSubModule(module, subModuleID)
+		if subModule == nil {
+			return nil, nil, ErrSubModuleNotFound
+		}
+		return module, subModule, nil
+	}
+
+	module, err := s.readableModule(ctx, moduleID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	subModule := findSubModule(module, subModuleID)
+	if subModule == nil || !module.SubModulePublished(subModule.ID) {
+		return nil, nil, ErrSubModuleNotFound
+	}
+	if subModule.Locked {
+		return nil, nil, ErrSubModuleLocked
+	}
+	if !subModule.DiscussionEnabled {
+		return nil, nil, models.ForbiddenError("discussion is disabled")
+	}
+	return module, subModule, nil
+}
+
+// notifyReply tells the author of a thread, and everyone who replied before, about a new reply;
+// failures are logged only
+func (s *moduleService) notifyReply(ctx context.Context, parent, reply *models.ModuleComment, subModule *models.SubModule) {
+	recipients := []primitive.ObjectID{parent.AuthorID}
+	earlier, err := s.commentRepo.ListReplies(ctx, []primitive.ObjectID{parent.ID})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list comment replies", "comment_id", parent.ID.Hex(), "error", err)
+	}
+	for _, comment := range earlier {
+		recipients = append(recipients, comment.AuthorID)
+	}
+
+	notified := map[primitive.ObjectID]bool{reply.AuthorID: true}
+	for _, recipientID := range recipients {
+		if notified[recipientID] {
+			continue
+		}
+		notified[recipientID] = true
+
+		notification := &models.Notification{
+			UserID:     recipientID,
+			Type:       models.NotificationModuleReply,
+			Title:      "New reply in a module discussion",
+			Message:    fmt.Sprintf("%s replied in the discussion of %s", reply.AuthorName, subModule.Name),
+			EntityType: "module_comment",
+			EntityID:   parent.ID.Hex(),
+		}
+		if recipientID == parent.AuthorID {
+			notification.Message = fmt.Sprintf("%s replied to your question on %s", reply.AuthorName, subModule.Name)
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			slog.ErrorContext(ctx, "Failed to create discussion notification", "error", err)
+		}
+	}
+}
+
+// displayName looks up a user's full name across the user collections
+func (s *moduleService) displayName(ctx context.Context, userID primitive.ObjectID) string {
+	if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, userID); err == nil {
+		return mahasiswa.FullName
+	}
+	if admin, err := s.userRepo.GetAdminByID(ctx, userID); err == nil {
+		return admin.FullName
+	}
+	if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+		return user.FullName
+	}
+	return "Unknown User"
+}
+
+// hideModeration leaves out what only admins see of a comment
+func hideModeration(comment *models.ModuleComment) {
+	comment.Reports = nil
+	comment.ReportCount = 0
+	comment.RemovedBy = nil
+}
+
+func commentsResponse(comments []models.ModuleComment, total int64, page, limit int) *models.ModuleCommentsResponse {
+	if comments == nil {
+		comments = []models.ModuleComment{}
+	}
+	return &models.ModuleCommentsResponse{
+		Comments:   comments,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"backend/models"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// siteRelativeAttribute matches a link or image URL in rendered content that points into the site
+var siteRelativeAttribute = regexp.MustCompile(`((?:href|src)=")(/[^/"][^"]*)"`)
+
+// GetModulePackage lays out a published module for an LMS package: its page and every published
+// submodule's, rendered to HTML. Pages are named by their documentation links under siteURL. Images
+// uploaded with the content are packaged with it; other links into the site are made absolute with
+// apiURL, so they still resolve from the LMS.
+func (s *moduleService) GetModulePackage(ctx context.Context, moduleID primitive.ObjectID, siteURL, apiURL string) (*models.LearningPackage, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !module.IsPublished {
+		return nil, models.ConflictError("module is not published")
+	}
+
+	images, err := s.imageRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module images: %w", err)
+	}
+	imagesByURL := make(map[string]models.ModuleImage, len(images))
+	for _, image := range images {
+		imagesByURL[image.URL] = image
+	}
+
+	documentationURL := siteURL + "/dokumentasi"
+	pageIRI := func(id primitive.ObjectID) string {
+		return documentationURL + "?section=" + url.QueryEscape(id.Hex())
+	}
+
+	pkg := &models.LearningPackage{
+		ID:          pageIRI(module.ID),
+		Identifier:  "z0nata-module-" + module.ID.Hex(),
+		Title:       module.Name,
+		Description: module.Description,
+	}
+	packaged := make(map[string]bool)
+	linkTarget := func(target string) string {
+		image, ok := imagesByURL[target]
+		if !ok {
+			return apiURL + target
+		}
+		assetPath := "images/" + image.ID.Hex() + strings.ToLower(path.Ext(image.FileName))
+		if !packaged[assetPath] {
+			packaged[assetPath] = true
+			storageKey := image.StorageKey
+			pkg.Assets = append(pkg.Assets, models.LearningAsset{
+				Path: assetPath,
+				Open: func() (io.ReadCloser, error) { return s.storage.Open(ctx, storageKey) },
+			})
+		}
+		return assetPath
+	}
+
+	pkg.Pages = append(pkg.Pages, models.LearningPage{
+		ID:       pkg.ID,
+		FileName: "module.html",
+		Title:    module.Name,
+		HTML:     packageBlocksHTML(module.Blocks, module.Content, linkTarget),
+	})
+	module.ArrangeSubModules()
+	for _, sub := range module.SubModules {
+		if !module.SubModulePublished(sub.ID) {
+			continue
+		}
+		pkg.Pages = append(pkg.Pages, models.LearningPage{
+			ID:       pageIRI(sub.ID),
+			FileName: sub.ID.Hex() + ".html",
+			Title:    sub.Name,
+			Depth:    sub.Depth,
+			HTML:     packageBlocksHTML(sub.Blocks, sub.Content, linkTarget),
+		})
+	}
+
+	return pkg, nil
+}
+
+// packageBlocksHTML renders content blocks to HTML that stands on its own, with site-relative link
+// targets replaced by linkTarget. Math is left as TeX markup, as RenderMarkdown leaves it.
+func packageBlocksHTML(blocks []models.ContentBlock, content string, linkTarget func(string) string) string {
+	if len(blocks) == 0 && strings.TrimSpace(content) != "" {
+		blocks = []models.ContentBlock{{Type: models.BlockMarkdown, Text: content}}
+	}
+	markdown := func(text string) string {
+		return siteRelativeAttribute.ReplaceAllStringFunc(utils.RenderMarkdown(text), func(match string) string {
+			parts := siteRelativeAttribute.FindStringSubmatch(match)
+			return parts[1] + html.EscapeString(linkTarget(html.UnescapeString(parts[2]))) + `"`
+		})
+	}
+
+	var b strings.Builder
+	for _, block := range blocks {
+		switch block.Type {
+		case models.BlockMarkdown:
+			b.WriteString(markdown(block.Text))
+		case models.BlockImage:
+			fmt.Fprintf(&b, `<figure><img src="%s" alt="%s">`, html.EscapeString(block.URL), html.EscapeString(block.Alt))
+			if block.Caption != "" {
+				fmt.Fprintf(&b, "<figcaption>%s</figcaption>", html.EscapeString(block.Caption))
+			}
+			b.WriteString("</figure>\n")
+		case models.BlockVideo:
+			fmt.Fprintf(&b, `<figure class="video"><iframe src="%s" title="%s" allowfullscreen></iframe>`, html.EscapeString(block.URL), html.EscapeString(videoTitle(block)))
+			if block.Caption != "" {
+				fmt.Fprintf(&b, "<figcaption>%s</figcaption>", html.EscapeString(block.Caption))
+			}
+			b.WriteString("</figure>\n")
+		case models.BlockCode:
+			class := ""
+			if block.Language != "" {
+				class = fmt.Sprintf(` class="language-%s"`, html.EscapeString(block.Language))
+			}
+			fmt.Fprintf(&b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(block.Code))
+		case models.BlockCallout:
+			fmt.Fprintf(&b, `<aside class="callout callout-%s">`, html.EscapeString(string(block.Variant)))
+			if block.Title != "" {
+				fmt.Fprintf(&b, "<strong>%s</strong>", html.EscapeString(block.Title))
+			}
+			b.WriteString(markdown(block.Text))
+			b.WriteString("</aside>\n")
+		}
+	}
+	return b.String()
+}
+
+func videoTitle(block models.ContentBlock) string {
+	if block.Caption != "" {
+		return block.Caption
+	}
+	return "Video"
+}
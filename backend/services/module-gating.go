@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Modules and submodules can require learners to complete other modules or submodules first. A
+// submodule is also locked while its module, or the submodule it is nested under, is. Admins read everything, and can unlock a module for
+// one learner regardless of its prerequisites.
+
+var (
+	// ErrModuleLocked is returned with a locked module, whose missing prerequisites say what to complete
+	ErrModuleLocked = models.NewCodedError(http.StatusForbidden, models.ErrorCodeContentLocked, "module is locked")
+	// ErrSubModuleLocked is returned when the learner cannot read a submodule yet
+	ErrSubModuleLocked = models.NewCodedError(http.StatusForbidden, models.ErrorCodeContentLocked, "submodule is locked")
+)
+
+// ApplyGating marks which of the modules the learner cannot read yet and withholds their content.
+// Without a learner nothing has been completed, so anything with prerequisites is locked.
+func (s *moduleService) ApplyGating(ctx context.Context, modules []models.Module, learnerID *primitive.ObjectID) error {
+	progress, err := s.learnerProgress(ctx, learnerID)
+	if err != nil {
+		return err
+	}
+	for i := range modules {
+		gateModule(&modules[i], progress)
+	}
+	return nil
+}
+
+// GetModuleForLearner loads a module as the learner sees it. A locked module comes back marked, along
+// with an error, so the caller can say what is missing.
+func (s *moduleService) GetModuleForLearner(ctx context.Context, moduleID primitive.ObjectID, learnerID *primitive.ObjectID) (*models.Module, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.learnerProgress(ctx, learnerID)
+	if err != nil {
+		return nil, err
+	}
+	gateModule(module, progress)
+	if module.Locked {
+		return module, ErrModuleLocked
+	}
+	return module, nil
+}
+
+// CompleteModule records that the learner finished a module they can read
+func (s *moduleService) CompleteModule(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.ModuleProgress, error) {
+	module, err := s.GetModuleForLearner(ctx, moduleID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if !module.IsPublished {
+		return nil, models.NotFoundError("module is not published")
+	}
+
+	progress, err := s.progressRepo.CompleteModule(ctx, userID, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record module completion: %w", err)
+	}
+	return progress, nil
+}
+
+// CompleteSubModule records that the learner finished a submodule they can read. Finishing the last
+// published submodule completes the module too.
+func (s *moduleService) CompleteSubModule(ctx context.Context, userID, moduleID, subModuleID primitive.ObjectID) (*models.ModuleProgress, error) {
+	module, err := s.GetModuleForLearner(ctx, moduleID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	var subModule *models.SubModule
+	for i := range module.SubModules {
+		if module.SubModules[i].ID == subModuleID {
+			subModule = &module.SubModules[i]
+			break
+		}
+	}
+	if subModule == nil {
+		return nil, ErrSubModuleNotFound
+	}
+	if !module.IsPublished || !module.SubModulePublished(subModuleID) {
+		return nil, models.NotFoundError("submodule is not published")
+	}
+	if subModule.Locked {
+		return nil, ErrSubModuleLocked
+	}
+
+	progress, err := s.progressRepo.CompleteSubModule(ctx, userID, moduleID, subModuleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record submodule completion: %w", err)
+	}
+	if progress.Completed {
+		return progress, nil
+	}
+	for _, sub := range module.SubModules {
+		if module.SubModulePublished(sub.ID) && !progress.HasCompleted(models.ContentRef{ModuleID: moduleID, SubModuleID: &sub.ID}) {
+			return progress, nil
+		}
+	}
+	progress, err = s.progressRepo.CompleteModule(ctx, userID, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record module completion: %w", err)
+	}
+	return progress, nil
+}
+
+// SetModuleUnlocked lets a learner into a module without its prerequisites, or takes that back
+func (s *moduleService) SetModuleUnlocked(ctx context.Context, adminID, userID, moduleID primitive.ObjectID, unlocked bool) (*models.ModuleProgress, error) {
+	if _, err := s.moduleRepo.GetModuleByID(ctx, moduleID); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if _, mahasiswaErr := s.userRepo.GetMahasiswaByID(ctx, userID); mahasiswaErr != nil {
+			return nil, repository.ErrUserNotFound
+		}
+	}
+
+	progress, err := s.progressRepo.SetUnlocked(ctx, userID, moduleID, unlocked, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update module unlock: %w", err)
+	}
+	return progress, nil
+}
+
+func (s *moduleService) learnerProgress(ctx context.Context, learnerID *primitive.ObjectID) (map[primitive.ObjectID]*models.ModuleProgress, error) {
+	progress := make(map[primitive.ObjectID]*models.ModuleProgress)
+	if learnerID == nil {
+		return progress, nil
+	}
+	list, err := s.progressRepo.ListByUser(ctx, *learnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module progress: %w", err)
+	}
+	for i := range list {
+		progress[list[i].ModuleID] = &list[i]
+	}
+	return progress, nil
+}
+
+// gateModule marks the module and its submodules with the learner's completion and what they still
+// need to complete, withholding the content of whatever is locked
+func gateModule(module *models.Module, progress map[primitive.ObjectID]*models.ModuleProgress) {
+	own := progress[module.ID]
+	unlocked := own != nil && own.Unlocked
+
+	module.Completed = own.HasCompleted(models.ContentRef{ModuleID: module.ID})
+	if own != nil {
+		module.LastPosition = own.LastPosition
+	}
+	if !unlocked {
+		module.MissingPrerequisites = missingPrerequisites(module.Prerequisites, progress)
+	}
+	module.Locked = len(module.MissingPrerequisites) > 0
+
+	// Submodules come parents first, and are locked while the submodule above them is
+	locked := make(map[primitive.ObjectID]bool, len(module.SubModules))
+	for i := range module.SubModules {
+		sub := &module.SubModules[i]
+		sub.Completed = own.HasCompleted(models.ContentRef{ModuleID: module.ID, SubModuleID: &sub.ID})
+		if !unlocked {
+			sub.MissingPrerequisites = missingPrerequisites(sub.Prerequisites, progress)
+		}
+		sub.Locked = module.Locked || len(sub.MissingPrerequisites) > 0 || (sub.ParentID != nil && locked[*sub.ParentID])
+		locked[sub.ID] = sub.Locked
+		if sub.Locked {
+			sub.Content, sub.Blocks = "", nil
+		}
+	}
+	if module.Locked {
+		module.Content, module.Blocks = "", nil
+	}
+}
+
+func missingPrerequisites(refs []models.ContentRef, progress map[primitive.ObjectID]*models.ModuleProgress) []models.ContentRef {
+	var missing []models.ContentRef
+	for _, ref := range refs {
+		if !progress[ref.ModuleID].HasCompleted(ref) {
+			missing = append(missing, ref)
+		}
+	}
+	return missing
+}
+
+// normalizePrerequisites drops repeated prerequisites
+func normalizePrerequisites(refs []models.ContentRef) []models.ContentRef {
+	if refs == nil {
+		return nil
+	}
+	normalized := make([]models.ContentRef, 0, len(refs))
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if key := contentKey(ref); !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, ref)
+		}
+	}
+	return normalized
+}
+
+// contentKey names a module or submodule as a node of the prerequisite graph
+func contentKey(ref models.ContentRef) string {
+	if ref.SubModuleID != nil {
+		return "submodule:" + ref.SubModuleID.Hex()
+	}
+	return "module:" + ref.ModuleID.Hex()
+}
+
+// checkPrerequisites checks a module about to be saved: each of its and its submodules' prerequisites
+// must exist, and no content may end up requiring itself, directly or through a chain of others
+func (s *moduleService) checkPrerequisites(ctx context.Context, module *models.Module) error {
+	modules, _, err := s.moduleRepo.GetAllModules(ctx, &models.GetModulesRequest{Page: 1, Limit: 1000})
+	if err != nil {
+		return fmt.Errorf("failed to check prerequisites: %w", err)
+	}
+	byID := map[primitive.ObjectID]*models.Module{module.ID: module}
+	for i := range modules {
+		if modules[i].ID != module.ID {
+			byID[modules[i].ID] = &modules[i]
+		}
+	}
+
+	exists := func(ref models.ContentRef) error {
+		target, ok := byID[ref.ModuleID]
+		if !ok {
+			return models.BadRequestError(fmt.Sprintf("prerequisite module %s not found", ref.ModuleID.Hex()))
+		}
+		if ref.SubModuleID == nil {
+			return nil
+		}
+		for _, sub := range target.SubModules {
+			if sub.ID == *ref.SubModuleID {
+				return nil
+			}
+		}
+		return models.BadRequestError(fmt.Sprintf("prerequisite submodule %s not found in module %s", ref.SubModuleID.Hex(), ref.ModuleID.Hex()))
+	}
+	for _, ref := range module.Prerequisites {
+		if err := exists(ref); err != nil {
+			return err
+		}
+	}
+	for _, sub := range module.SubModules {
+		for _, ref := range sub.Prerequisites {
+			if err := exists(ref); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Reading content takes its prerequisites, and for a submodule its module's and its parent's as well
+	requires := make(map[string][]string)
+	for _, m := range byID {
+		moduleKey := contentKey(models.ContentRef{ModuleID: m.ID})
+		for _, ref := range m.Prerequisites {
+			requires[moduleKey] = append(requires[moduleKey], contentKey(ref))
+		}
+		for _, sub := range m.SubModules {
+			subKey := contentKey(models.ContentRef{ModuleID: m.ID, SubModuleID: &sub.ID})
+			requires[subKey] = append(requires[subKey], moduleKey)
+			if sub.ParentID != nil {
+				parentKey := contentKey(models.ContentRef{ModuleID: m.ID, SubModuleID: sub.ParentID})
+				requires[subKey] = append(requires[subKey], parentKey)
+			}
+			for _, ref := range sub.Prerequisites {
+				requires[subKey] = append(requires[subKey], contentKey(ref))
+			}
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := make(map[string]int)
+	var visit func(key string) bool
+	visit = func(key string) bool {
+		switch state[key] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[key] = visiting
+		for _, next := range requires[key] {
+			if !visit(next) {
+				return false
+			}
+		}
+		state[key] = done
+		return true
+	}
+	for key := range requires {
+		if !visit(key) {
+			return models.BadRequestError("prerequisites form a cycle; content would require itself")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"backend/models"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// moduleImageURL is where an image uploaded with module content is served, relative to the site as
+// content links to it
+func moduleImageURL(moduleID, imageID primitive.ObjectID) string {
+	return fmt.Sprintf("/api/v1/modules/%s/images/%s", moduleID.Hex(), imageID.Hex())
+}
+
+// OpenModuleImage opens an image shown in module content. Images are public like the links to them,
+// whose IDs cannot be guessed. The caller closes it.
+func (s *moduleService) OpenModuleImage(ctx context.Context, moduleID, imageID primitive.ObjectID) (*models.ModuleImage, io.ReadCloser, error) {
+	image, err := s.imageRepo.GetByID(ctx, moduleID, imageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := s.storage.Open(ctx, image.StorageKey)
+	if errors.Is(err, utils.ErrStoredFileNotFound) {
+		return nil, nil, models.NotFoundError("image not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	return image, file, nil
+}
+
+// removeImages deletes the images of a deleted module. Failures are logged so the deletion itself
+// stands.
+func (s *moduleService) removeImages(ctx context.Context, moduleID primitive.ObjectID) {
+	images, err := s.imageRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list images of deleted module", "module_id", moduleID.Hex(), "error", err)
+		return
+	}
+	s.deleteStoredImages(ctx, images)
+	if err := s.imageRepo.DeleteByModule(ctx, moduleID); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete module images", "module_id", moduleID.Hex(), "error", err)
+	}
+}
+
+func (s *moduleService) deleteStoredImages(ctx context.Context, images []models.ModuleImage) {
+	for _, image := range images {
+		if err := s.storage.Delete(ctx, image.StorageKey); err != nil {
+			slog.ErrorContext(ctx, "Failed to delete image file", "storage_key", image.StorageKey, "error", err)
+		}
+	}
+}
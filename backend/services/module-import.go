@@ -0,0 +1,533 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"backend/models"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errFileTooLarge is returned for a bundle file bigger than its kind allows
+var errFileTooLarge = errors.New("file too large")
+
+const (
+	maxModuleBundleSize  = 100 << 20 // The zip as uploaded
+	maxBundleContentSize = 200 << 20 // All its files unpacked
+	maxBundleFiles       = 1000
+	maxBundlePageSize    = 1 << 20
+	maxBundleImageSize   = 10 << 20
+)
+
+// bundleImageTypes are the images a bundle can carry, by extension; SVG is left out as it can run script
+var bundleImageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+var (
+	// markdownImage matches an inline markdown image: its alt text, link and optional "title"
+	markdownImage = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(\s+"[^"]*")?\s*\)`)
+
+	pageHeading = regexp.MustCompile(`^#\s+(.+?)\s*#*$`)
+)
+
+// bundlePage is a markdown file of a bundle on its way to becoming the module or one of its submodules
+type bundlePage struct {
+	path   string
+	folder string // For a folder's index.md, the folder it stands for
+	parent string // Folder whose submodule the page nests under; empty for the module's own pages
+
+	name        string
+	description string
+	order       int
+	content     string
+	images      int
+
+	subModuleID primitive.ObjectID
+}
+
+// ImportModule builds a module and its submodules from a zip of markdown pages and the images they
+// show, as described on models.ImportModuleRequest. Nothing is created unless the whole bundle is
+// valid; the module starts as a draft.
+func (s *moduleService) ImportModule(ctx context.Context, bundle io.ReaderAt, size int64, req *models.ImportModuleRequest, userID primitive.ObjectID) (*models.ImportModuleResponse, error) {
+	if size > maxModuleBundleSize {
+		return nil, models.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("module bundles must be %d MB or smaller", maxModuleBundleSize>>20))
+	}
+	archive, err := zip.NewReader(bundle, size)
+	if err != nil {
+		return nil, models.BadRequestError("module bundle must be a zip file")
+	}
+
+	response := &models.ImportModuleResponse{DryRun: req.DryRun, Pages: []models.ModuleImportPage{}}
+	problem := func(filePath, format string, args ...interface{}) {
+		response.Problems = append(response.Problems, models.ModuleImportProblem{Path: filePath, Message: fmt.Sprintf(format, args...)})
+	}
+
+	pageData, imageData, err := unpackBundle(archive, response)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pageData["index.md"]; !ok {
+		problem("", "the bundle has no index.md for the module")
+	}
+
+	// Read every page, then place each under the folder it belongs to
+	moduleID := primitive.NewObjectID()
+	images := make(map[string]*models.ModuleImage)
+	pages := make([]*bundlePage, 0, len(pageData))
+	folders := make(map[string]*bundlePage)
+	var modulePage *bundlePage
+	for _, pagePath := range sortedKeys(pageData) {
+		page := &bundlePage{path: pagePath}
+		dir := path.Dir(pagePath)
+		switch {
+		case pagePath == "index.md":
+			modulePage = page
+		case path.Base(pagePath) == "index.md":
+			page.folder = dir
+			page.parent = strings.TrimPrefix(path.Dir(dir), ".")
+			folders[dir] = page
+		default:
+			page.parent = strings.TrimPrefix(dir, ".")
+		}
+
+		fields, body, err := parsePage(string(pageData[pagePath]))
+		if err != nil {
+			problem(pagePath, "%s", err.Error())
+			continue
+		}
+		if err := page.apply(fields, &body, response); err != nil {
+			problem(pagePath, "%s", err.Error())
+		}
+		page.content = s.linkBundleImages(page, body, moduleID, imageData, images, problem, userID)
+		if page != modulePage {
+			pages = append(pages, page)
+		}
+	}
+	for _, page := range pages {
+		if page.parent != "" && folders[page.parent] == nil {
+			problem(page.path, "folder %s has no index.md to nest this page under", page.parent)
+		}
+	}
+	orderSiblings(pages)
+
+	for _, imagePath := range sortedKeys(imageData) {
+		image := images[imagePath]
+		if image == nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s is not shown on any page and was skipped", imagePath))
+			continue
+		}
+		if http.DetectContentType(imageData[imagePath]) != image.ContentType {
+			problem(imagePath, "file content does not match its type")
+		}
+	}
+	response.Images = len(images)
+
+	module := s.bundleModule(ctx, moduleID, modulePage, pages, folders, problem, userID)
+	if len(response.Problems) > 0 {
+		return response, nil
+	}
+
+	response.Pages = append(response.Pages, models.ModuleImportPage{
+		Path:   modulePage.path,
+		Name:   modulePage.name,
+		Order:  module.Order,
+		Images: modulePage.images,
+	})
+	for _, page := range pages {
+		report := models.ModuleImportPage{Path: page.path, Name: page.name, Order: page.order, Images: page.images}
+		if parent := folders[page.parent]; parent != nil {
+			report.ParentPath = parent.path
+		}
+		if !req.DryRun {
+			report.SubModuleID = &page.subModuleID
+		}
+		response.Pages = append(response.Pages, report)
+	}
+
+	module.ArrangeSubModules()
+	response.Module = module
+	if req.DryRun {
+		return response, nil
+	}
+
+	if err := s.storeBundleImages(ctx, images, imageData); err != nil {
+		return nil, err
+	}
+	if err := s.moduleRepo.CreateModule(ctx, module); err != nil {
+		s.removeImages(ctx, moduleID)
+		return nil, fmt.Errorf("failed to create module: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	return response, nil
+}
+
+// unpackBundle reads the markdown pages and images of a bundle by path, looking through the folder
+// wrapping them all if there is one. Folders and hidden files are left out, other files skipped with
+// a warning.
+func unpackBundle(archive *zip.Reader, response *models.ImportModuleResponse) (map[string][]byte, map[string][]byte, error) {
+	type bundleEntry struct {
+		path string
+		file *zip.File
+	}
+	var entries []bundleEntry
+	for _, file := range archive.File {
+		name := strings.ReplaceAll(file.Name, "\\", "/")
+		if file.FileInfo().IsDir() || hiddenBundlePath(name) {
+			continue
+		}
+		clean := path.Clean(name)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return nil, nil, models.BadRequestError(fmt.Sprintf("%s: path leaves the bundle", name))
+		}
+		if len(entries) == maxBundleFiles {
+			return nil, nil, models.BadRequestError(fmt.Sprintf("module bundles can hold at most %d files", maxBundleFiles))
+		}
+		entries = append(entries, bundleEntry{path: clean, file: file})
+	}
+
+	// Zipping a folder puts everything under it
+	if len(entries) > 0 {
+		root, _, nested := strings.Cut(entries[0].path, "/")
+		for _, entry := range entries {
+			if !nested || !strings.HasPrefix(entry.path, root+"/") {
+				nested = false
+				break
+			}
+		}
+		if nested {
+			for i := range entries {
+				entries[i].path = strings.TrimPrefix(entries[i].path, root+"/")
+			}
+		}
+	}
+
+	pages := make(map[string][]byte)
+	images := make(map[string][]byte)
+	var total int64
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.path))
+		kind, files, limit := "pages", pages, int64(maxBundlePageSize)
+		switch {
+		case ext == ".md" || ext == ".markdown":
+		case bundleImageTypes[ext] != "":
+			kind, files, limit = "images", images, maxBundleImageSize
+		default:
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s is not a markdown page or image and was skipped", entry.path))
+			continue
+		}
+
+		data, err := readBundleFile(entry.file, limit)
+		if err != nil {
+			if errors.Is(err, errFileTooLarge) {
+				response.Problems = append(response.Problems, models.ModuleImportProblem{
+					Path:    entry.path,
+					Message: fmt.Sprintf("%s must be %d MB or smaller", kind, limit>>20),
+				})
+				continue
+			}
+			return nil, nil, models.BadRequestError(fmt.Sprintf("%s: %v", entry.path, err))
+		}
+		total += int64(len(data))
+		if total > maxBundleContentSize {
+			return nil, nil, models.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("module bundle content must be %d MB or smaller unpacked", maxBundleContentSize>>20))
+		}
+		files[entry.path] = data
+	}
+	return pages, images, nil
+}
+
+// readBundleFile unpacks one file, reading no more than limit bytes whatever its header claims
+func readBundleFile(entry *zip.File, limit int64) ([]byte, error) {
+	if entry.UncompressedSize64 > uint64(limit) {
+		return nil, errFileTooLarge
+	}
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, errors.New("file cannot be unpacked")
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, errors.New("file cannot be unpacked")
+	}
+	if int64(len(data)) > limit {
+		return nil, errFileTooLarge
+	}
+	return data, nil
+}
+
+// hiddenBundlePath reports files an editor or archiver left behind, such as .DS_Store and __MACOSX
+func hiddenBundlePath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if (strings.HasPrefix(part, ".") && part != "." && part != "..") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePage splits a markdown page into its front-matter fields and its body
+func parsePage(text string) (map[string]string, string, error) {
+	text = strings.TrimPrefix(strings.ReplaceAll(text, "\r\n", "\n"), "\ufeff")
+	fields := make(map[string]string)
+	if !utf8.ValidString(text) {
+		return nil, "", errors.New("page is not UTF-8 text")
+	}
+	if !strings.HasPrefix(text, "---\n") {
+		return fields, text, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" || line == "..." {
+			return fields, strings.Join(lines[i+1:], "\n"), nil
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("front-matter line %d is not key: value", i+1)
+		}
+		fields[strings.ToLower(strings.TrimSpace(key))] = unquoteField(strings.TrimSpace(value))
+	}
+	return nil, "", errors.New("front-matter is not closed with ---")
+}
+
+// unquoteField reads a front-matter value written in single or double quotes
+func unquoteField(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// apply takes a page's name, description and order from its front-matter, or its name from the
+// heading it opens with, which then leaves the body
+func (page *bundlePage) apply(fields map[string]string, body *string, response *models.ImportModuleResponse) error {
+	for _, key := range sortedKeys(fields) {
+		switch key {
+		case "title", "description", "order":
+		default:
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s: front-matter field %s is not used", page.path, key))
+		}
+	}
+
+	page.name = strings.TrimSpace(fields["title"])
+	if page.name == "" {
+		trimmed := strings.TrimLeft(*body, "\n")
+		first, rest, _ := strings.Cut(trimmed, "\n")
+		if match := pageHeading.FindStringSubmatch(strings.TrimSpace(first)); match != nil {
+			page.name = match[1]
+			*body = rest
+		}
+	}
+	page.description = strings.TrimSpace(fields["description"])
+
+	switch {
+	case page.name == "":
+		return errors.New("title is required, in front-matter or as a # heading")
+	case utf8.RuneCountInString(page.name) > 200:
+		return errors.New("title must be 200 characters or fewer")
+	case utf8.RuneCountInString(page.description) > 500:
+		return errors.New("description must be 500 characters or fewer")
+	}
+	if raw := strings.TrimSpace(fields["order"]); raw != "" {
+		order, err := strconv.Atoi(raw)
+		if err != nil || order < 1 {
+			return errors.New("order must be a positive whole number")
+		}
+		page.order = order
+	}
+	return nil
+}
+
+// linkBundleImages points the images a page shows from the bundle at the URLs they are uploaded to.
+// Images already on the web are left alone.
+func (s *moduleService) linkBundleImages(page *bundlePage, body string, moduleID primitive.ObjectID, imageData map[string][]byte, images map[string]*models.ModuleImage, problem func(string, string, ...interface{}), userID primitive.ObjectID) string {
+	return markdownImage.ReplaceAllStringFunc(body, func(match string) string {
+		parts := markdownImage.FindStringSubmatch(match)
+		alt, ref, title := parts[1], parts[2], parts[3]
+		lower := strings.ToLower(ref)
+		if strings.Contains(lower, "://") || strings.HasPrefix(ref, "/") || strings.HasPrefix(lower, "data:") {
+			return match
+		}
+
+		unescaped, err := url.PathUnescape(ref)
+		if err != nil {
+			unescaped = ref
+		}
+		imagePath := path.Join(path.Dir(page.path), unescaped)
+		data, ok := imageData[imagePath]
+		if !ok {
+			problem(page.path, "image %s is not in the bundle", ref)
+			return match
+		}
+
+		image := images[imagePath]
+		if image == nil {
+			ext := strings.ToLower(path.Ext(imagePath))
+			image = &models.ModuleImage{
+				ID:          primitive.NewObjectID(),
+				ModuleID:    moduleID,
+				FileName:    path.Base(imagePath),
+				ContentType: bundleImageTypes[ext],
+				Size:        int64(len(data)),
+				UploadedBy:  userID,
+			}
+			image.StorageKey = fmt.Sprintf("modules/%s/images/%s%s", moduleID.Hex(), image.ID.Hex(), ext)
+			image.URL = moduleImageURL(moduleID, image.ID)
+			images[imagePath] = image
+		}
+		page.images++
+		return "![" + alt + "](" + image.URL + title + ")"
+	})
+}
+
+// orderSiblings numbers the pages under each folder: those with an order keep it, the rest follow in
+// file name order, a folder's index.md standing in for the folder
+func orderSiblings(pages []*bundlePage) {
+	siblings := make(map[string][]*bundlePage)
+	for _, page := range pages {
+		siblings[page.parent] = append(siblings[page.parent], page)
+	}
+	for _, group := range siblings {
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].sortName() < group[j].sortName()
+		})
+		next := 1
+		for _, page := range group {
+			if page.order > 0 {
+				next = max(next, page.order+1)
+			}
+		}
+		for _, page := range group {
+			if page.order == 0 {
+				page.order = next
+				next++
+			}
+		}
+	}
+}
+
+func (page *bundlePage) sortName() string {
+	if page.folder != "" {
+		return path.Base(page.folder)
+	}
+	return path.Base(page.path)
+}
+
+// bundleModule assembles the module from its pages, reporting content that does not validate
+func (s *moduleService) bundleModule(ctx context.Context, moduleID primitive.ObjectID, modulePage *bundlePage, pages []*bundlePage, folders map[string]*bundlePage, problem func(string, string, ...interface{}), userID primitive.ObjectID) *models.Module {
+	now := time.Now()
+	module := &models.Module{
+		ID:         moduleID,
+		SubModules: []models.SubModule{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		CreatedBy:  userID,
+		UpdatedBy:  userID,
+	}
+	if modulePage != nil {
+		blocks, content, err := requiredModuleContent(nil, modulePage.content)
+		if err != nil {
+			problem(modulePage.path, "%s", err.Error())
+		}
+		module.Name = modulePage.name
+		module.Description = modulePage.description
+		module.Content = content
+		module.Blocks = blocks
+		module.Order = modulePage.order
+	}
+
+	for _, page := range pages {
+		page.subModuleID = primitive.NewObjectID()
+	}
+	for _, page := range pages {
+		blocks, content, err := moduleContent(nil, page.content)
+		if err != nil {
+			problem(page.path, "%s", err.Error())
+		}
+		subModule := models.SubModule{
+			ID:          page.subModuleID,
+			Name:        page.name,
+			Description: page.description,
+			Content:     content,
+			Blocks:      blocks,
+			Order:       page.order,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			CreatedBy:   userID,
+			UpdatedBy:   userID,
+		}
+		if parent := folders[page.parent]; parent != nil {
+			subModule.ParentID = &parent.subModuleID
+		}
+		module.SubModules = append(module.SubModules, subModule)
+	}
+	if err := checkSubModuleTree(module); err != nil {
+		problem("", "%s", err.Error())
+	}
+
+	if module.Order == 0 && modulePage != nil {
+		module.Order = s.nextModuleOrder(ctx)
+	}
+	return module
+}
+
+// storeBundleImages uploads the images a bundle's pages show and records them, removing what was
+// stored if any of it fails
+func (s *moduleService) storeBundleImages(ctx context.Context, images map[string]*models.ModuleImage, imageData map[string][]byte) error {
+	records := make([]models.ModuleImage, 0, len(images))
+	for _, imagePath := range sortedKeys(images) {
+		image := images[imagePath]
+		image.CreatedAt = time.Now()
+		if err := s.storage.Put(ctx, image.StorageKey, bytes.NewReader(imageData[imagePath]), image.Size, image.ContentType); err != nil {
+			s.deleteStoredImages(ctx, records)
+			return fmt.Errorf("failed to store image %s: %w", imagePath, err)
+		}
+		records = append(records, *image)
+	}
+	if err := s.imageRepo.CreateMany(ctx, records); err != nil {
+		s.deleteStoredImages(ctx, records)
+		return fmt.Errorf("failed to save images: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
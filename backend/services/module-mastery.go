@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// masteryResultLimit bounds how much of a student's history mastery is computed from
+	masteryResultLimit = 200
+
+	// masteryHalfLife is how long until a quiz counts half as much toward mastery as one taken today
+	masteryHalfLife = 30 * 24 * time.Hour
+
+	// Modules need this many answered questions before they are recommended as weak
+	minMasteryQuestions = 5
+
+	weakestModuleCount = 3
+)
+
+// GetModuleMastery computes the student's mastery of each module their quiz history assesses, from the
+// points earned on its questions with recent quizzes weighing more. Essays awaiting a grade and
+// embargoed results are left out until they count.
+func (s *quizSessionService) GetModuleMastery(ctx context.Context, userID primitive.ObjectID) (*models.ModuleMasteryReport, error) {
+	results, err := s.sessionRepo.GetUserDetailedResults(ctx, userID, "", masteryResultLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz results: %w", err)
+	}
+	newResultReleaseChecker(s.examRepo).applyDetailed(ctx, results)
+
+	type tally struct {
+		mastery models.ModuleMastery
+		earned  float64
+		points  float64
+	}
+	tallies := make(map[primitive.ObjectID]*tally)
+	now := time.Now()
+	for _, result := range results {
+		weight := math.Pow(0.5, float64(now.Sub(result.CompletedAt))/float64(masteryHalfLife))
+		for _, qr := range result.QuestionResults {
+			if qr.PendingGrade || qr.Points <= 0 {
+				continue
+			}
+			for _, moduleID := range resultModules(qr) {
+				t, ok := tallies[moduleID]
+				if !ok {
+					t = &tally{mastery: models.ModuleMastery{ModuleID: moduleID}}
+					tallies[moduleID] = t
+				}
+				t.earned += weight * float64(qr.PointsEarned)
+				t.points += weight * float64(qr.Points)
+				t.mastery.Questions++
+				if qr.IsCorrect {
+					t.mastery.Correct++
+				}
+				if result.CompletedAt.After(t.mastery.LastPracticedAt) {
+					t.mastery.LastPracticedAt = result.CompletedAt
+				}
+			}
+		}
+	}
+
+	report := &models.ModuleMasteryReport{
+		Modules:         []models.ModuleMastery{},
+		Weakest:         []models.ModuleMastery{},
+		ResultsAnalyzed: len(results),
+	}
+	published := make(map[primitive.ObjectID]bool)
+	for moduleID, t := range tallies {
+		// Modules deleted since the quizzes were taken have nothing left to study
+		module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+		if err != nil {
+			if errors.Is(err, repository.ErrModuleNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get module: %w", err)
+		}
+		t.mastery.ModuleName = module.Name
+		if t.points > 0 {
+			t.mastery.Mastery = math.Round(t.earned/t.points*1000) / 10
+		}
+		published[moduleID] = module.IsPublished
+		report.Modules = append(report.Modules, t.mastery)
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool {
+		a, b := report.Modules[i], report.Modules[j]
+		if a.Mastery != b.Mastery {
+			return a.Mastery < b.Mastery
+		}
+		return a.Questions > b.Questions
+	})
+	for _, mastery := range report.Modules {
+		if len(report.Weakest) == weakestModuleCount {
+			break
+		}
+		if published[mastery.ModuleID] && mastery.Questions >= minMasteryQuestions {
+			report.Weakest = append(report.Weakest, mastery)
+		}
+	}
+	return report, nil
+}
+
+// assessedModules lists the modules a session question counts toward: those linked to the question or
+// its sections, and those of the template the session was built from
+func assessedModules(question models.SessionQuestion, templateModules []primitive.ObjectID) []primitive.ObjectID {
+	var modules []primitive.ObjectID
+	seen := make(map[primitive.ObjectID]bool)
+	add := func(id primitive.ObjectID) {
+		if !seen[id] {
+			seen[id] = true
+			modules = append(modules, id)
+		}
+	}
+	for _, id := range question.ModuleIDs {
+		add(id)
+	}
+	for _, ref := range question.Sections {
+		add(ref.ModuleID)
+	}
+	for _, id := range templateModules {
+		add(id)
+	}
+	return modules
+}
+
+// resultModules lists the modules an answer counts toward. Results saved before module links kept only
+// the question's sections.
+func resultModules(qr models.QuestionResult) []primitive.ObjectID {
+	if len(qr.ModuleIDs) > 0 {
+		return qr.ModuleIDs
+	}
+	return assessedModules(models.SessionQuestion{Sections: qr.Sections}, nil)
+}
+
+// checkModuleIDs checks that each linked module exists, dropping duplicates
+func checkModuleIDs(ctx context.Context, moduleRepo repository.ModuleRepository, ids []primitive.ObjectID) ([]primitive.ObjectID, error) {
+	checked := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		if _, err := moduleRepo.GetModuleByID(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrModuleNotFound) {
+				return nil, models.BadRequestError("linked module not found")
+			}
+			return nil, fmt.Errorf("failed to get module: %w", err)
+		}
+		seen[id] = true
+		checked = append(checked, id)
+	}
+	return checked, nil
+}
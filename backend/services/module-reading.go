@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveReadingPosition stores where the learner is in a module they can read, replacing where they were
+func (s *moduleService) SaveReadingPosition(ctx context.Context, userID, moduleID primitive.ObjectID, req *models.SaveReadingPositionRequest) (*models.ReadingPosition, error) {
+	module, err := s.readableModule(ctx, moduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	blocks := len(module.Blocks)
+	if req.SubModuleID != nil {
+		subModule := findSubModule(module, *req.SubModuleID)
+		if subModule == nil || !module.SubModulePublished(subModule.ID) {
+			return nil, ErrSubModuleNotFound
+		}
+		if subModule.Locked {
+			return nil, ErrSubModuleLocked
+		}
+		blocks = len(subModule.Blocks)
+	}
+	if req.BlockIndex > 0 && req.BlockIndex >= blocks {
+		return nil, models.BadRequestError("block index is past the end of the content")
+	}
+
+	position := models.ReadingPosition{
+		SubModuleID:   req.SubModuleID,
+		BlockIndex:    req.BlockIndex,
+		ScrollPercent: req.ScrollPercent,
+		UpdatedAt:     time.Now(),
+	}
+	if _, err := s.progressRepo.SetLastPosition(ctx, userID, moduleID, position); err != nil {
+		return nil, fmt.Errorf("failed to save reading position: %w", err)
+	}
+	return &position, nil
+}
+
+// GetContinueReading lists the modules the learner left part way through, most recently read first,
+// skipping any they can no longer read. A position in a submodule since unpublished or locked falls
+// back to the module page.
+func (s *moduleService) GetContinueReading(ctx context.Context, userID primitive.ObjectID, req *models.ContinueReadingRequest) (*models.ContinueReadingResponse, error) {
+	// Read past the limit, as some of the modules may have been unpublished since
+	inProgress, err := s.progressRepo.ListInProgress(ctx, userID, req.Limit*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading positions: %w", err)
+	}
+	response := &models.ContinueReadingResponse{Items: []models.ContinueReadingItem{}}
+	if len(inProgress) == 0 {
+		return response, nil
+	}
+
+	moduleIDs := make([]primitive.ObjectID, len(inProgress))
+	for i, progress := range inProgress {
+		moduleIDs[i] = progress.ModuleID
+	}
+	modules, err := s.moduleRepo.FindModules(ctx, bson.M{"_id": bson.M{"$in": moduleIDs}, "is_published": true}, len(moduleIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get modules: %w", err)
+	}
+	progress, err := s.learnerProgress(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*models.Module, len(modules))
+	for i := range modules {
+		gateModule(&modules[i], progress)
+		byID[modules[i].ID] = &modules[i]
+	}
+
+	for _, own := range inProgress {
+		module := byID[own.ModuleID]
+		if module == nil || module.Locked {
+			continue
+		}
+		position := *own.LastPosition
+		var subModule *models.SubModule
+		if position.SubModuleID != nil {
+			subModule = findSubModule(module, *position.SubModuleID)
+			if subModule == nil || subModule.Locked || !module.SubModulePublished(subModule.ID) {
+				subModule = nil
+				position = models.ReadingPosition{UpdatedAt: position.UpdatedAt}
+			}
+		}
+
+		item := models.ContinueReadingItem{
+			ModuleID:    module.ID,
+			ModuleName:  module.Name,
+			Position:    position,
+			MinutesLeft: minutesLeft(module, &position),
+		}
+		if subModule != nil {
+			item.SubModuleID = &subModule.ID
+			item.SubModuleName = subModule.Name
+		}
+		response.Items = append(response.Items, item)
+		if len(response.Items) == req.Limit {
+			break
+		}
+	}
+	return response, nil
+}
+
+// minutesLeft estimates how long the rest of a module takes to read from a position: what is left of
+// the page the learner is on, and every later published page they have not completed
+func minutesLeft(module *models.Module, position *models.ReadingPosition) int {
+	remaining := func(minutes int) float64 {
+		return float64(minutes) * (100 - position.ScrollPercent) / 100
+	}
+
+	left := 0.0
+	reached := position.SubModuleID == nil
+	if reached {
+		left = remaining(module.ReadingMinutes)
+	}
+	for _, sub := range module.SubModules {
+		if !module.SubModulePublished(sub.ID) {
+			continue
+		}
+		switch {
+		case !reached && sub.ID == *position.SubModuleID:
+			reached = true
+			left += remaining(sub.ReadingMinutes)
+		case reached && !sub.Completed:
+			left += float64(sub.ReadingMinutes)
+		}
+	}
+	return int(math.Ceil(left))
+}
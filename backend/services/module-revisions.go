@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrModuleChanged is returned when someone else saved the module since it was loaded
+var ErrModuleChanged = models.NewCodedError(http.StatusConflict, models.ErrorCodeEditConflict, "module was changed by someone else, reload it and try again")
+
+// Module revisions track a module's content: its name, description, content and prerequisites, and
+// its submodules' along with where they are nested. Order and publication are left out; they change on their own and rolling back
+// does not undo them.
+
+// maxDiffCells bounds the work of a line diff; longer texts are shown as fully replaced
+const maxDiffCells = 1_000_000
+
+// GetModuleRevisions lists a module's revisions, newest first, without their snapshots
+func (s *moduleService) GetModuleRevisions(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleRevisionsResponse, error) {
+	if _, err := s.moduleRepo.GetModuleByID(ctx, moduleID); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.ListByModule(ctx, moduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list module revisions: %w", err)
+	}
+
+	people := make(map[primitive.ObjectID]creditedPerson)
+	for i := range revisions {
+		revisions[i].AuthorName = lookupPerson(ctx, s.userRepo, people, revisions[i].AuthorID).fullName
+	}
+	if revisions == nil {
+		revisions = []models.ModuleRevision{}
+	}
+
+	response := &models.ModuleRevisionsResponse{
+		ModuleID:  moduleID,
+		Revisions: revisions,
+		Total:     len(revisions),
+	}
+	if len(revisions) > 0 {
+		response.CurrentRevision = revisions[0].Revision
+	}
+	return response, nil
+}
+
+// DiffModuleRevisions compares two revisions of a module; without a To it compares against the
+// current revision
+func (s *moduleService) DiffModuleRevisions(ctx context.Context, moduleID primitive.ObjectID, req *models.ModuleRevisionDiffRequest) (*models.ModuleRevisionDiff, error) {
+	from, err := s.revisionRepo.GetByNumber(ctx, moduleID, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *models.ModuleRevision
+	if req.To == 0 {
+		to, err = s.revisionRepo.GetLatest(ctx, moduleID)
+		if err == nil && to == nil {
+			err = models.NotFoundError("module revision not found")
+		}
+	} else {
+		to, err = s.revisionRepo.GetByNumber(ctx, moduleID, req.To)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := moduleChanges(from.Snapshot, to.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare module revisions: %w", err)
+	}
+	if changes == nil {
+		changes = []models.ModuleFieldChange{}
+	}
+	return &models.ModuleRevisionDiff{
+		ModuleID: moduleID,
+		From:     from.Revision,
+		To:       to.Revision,
+		Changes:  changes,
+	}, nil
+}
+
+// RollbackModule restores a module's content to an earlier revision, recorded as a new revision.
+// Submodules keep their current publication and discussion; ones the rollback brings back return as
+// drafts with their discussion closed.
+func (s *moduleService) RollbackModule(ctx context.Context, moduleID primitive.ObjectID, number int, userID primitive.ObjectID) (*models.Module, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionRepo.GetByNumber(ctx, moduleID, number)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := revision.Snapshot
+	if snapshot == nil {
+		return nil, errors.New("module revision has no snapshot")
+	}
+
+	current := make(map[primitive.ObjectID]models.SubModule, len(module.SubModules))
+	for _, sub := range module.SubModules {
+		current[sub.ID] = sub
+	}
+	restored := *module
+	restored.Name = snapshot.Name
+	restored.Description = snapshot.Description
+	restored.Content = snapshot.Content
+	restored.Blocks = snapshot.Blocks
+	restored.Prerequisites = snapshot.Prerequisites
+	restored.SubModules = make([]models.SubModule, len(snapshot.SubModules))
+	for i, sub := range snapshot.SubModules {
+		if existing, ok := current[sub.ID]; ok {
+			sub.IsPublished, sub.PublishedAt = existing.IsPublished, existing.PublishedAt
+			sub.DiscussionEnabled = existing.DiscussionEnabled
+		} else {
+			sub.IsPublished, sub.PublishedAt = false, nil
+			sub.DiscussionEnabled = false
+		}
+		restored.SubModules[i] = sub
+	}
+	if err := s.checkPrerequisites(ctx, &restored); err != nil {
+		return nil, err
+	}
+
+	restored.UpdatedBy = userID
+	if err := s.saveRevisedModule(ctx, module, &restored, userID, revision.Revision); err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// saveRevisedModule saves an edited module along with a revision of it. Edits made elsewhere since the
+// last revision, such as to one submodule, are first recorded as a revision of their own, so the
+// history covers whatever a later rollback would undo. The revision is stored before the module
+// changes, so of two concurrent edits only one can claim its number. Edits that change no revisioned
+// content add no revision.
+func (s *moduleService) saveRevisedModule(ctx context.Context, existing, updated *models.Module, authorID primitive.ObjectID, restoredFrom int) error {
+	changed, err := changedModuleFields(existing, updated)
+	if err != nil {
+		return fmt.Errorf("failed to compare module revisions: %w", err)
+	}
+	if len(changed) == 0 {
+		if err := s.moduleRepo.UpdateModule(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update module: %w", err)
+		}
+		s.cache.Invalidate(ctx, utils.CacheModules)
+		return nil
+	}
+
+	latest, err := s.revisionRepo.GetLatest(ctx, existing.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get module revisions: %w", err)
+	}
+	number := 0
+	var unrecorded []string
+	if latest != nil {
+		number = latest.Revision
+		if unrecorded, err = changedModuleFields(latest.Snapshot, existing); err != nil {
+			return fmt.Errorf("failed to compare module revisions: %w", err)
+		}
+	}
+	if latest == nil || len(unrecorded) > 0 {
+		// Who made edits outside revisions is only known from the module's own bookkeeping
+		author := existing.UpdatedBy
+		if author.IsZero() {
+			author = existing.CreatedBy
+		}
+		number++
+		baseline := *existing
+		err := s.revisionRepo.Create(ctx, &models.ModuleRevision{
+			ModuleID:      existing.ID,
+			Revision:      number,
+			Snapshot:      &baseline,
+			ChangedFields: unrecorded,
+			AuthorID:      author,
+			CreatedAt:     existing.UpdatedAt,
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrModuleRevisionExists) {
+				return ErrModuleChanged
+			}
+			return fmt.Errorf("failed to record module revision: %w", err)
+		}
+	}
+
+	now := time.Now()
+	updated.UpdatedAt = now
+	revision := &models.ModuleRevision{
+		ModuleID:      existing.ID,
+		Revision:      number + 1,
+		Snapshot:      updated,
+		ChangedFields: changed,
+		RestoredFrom:  restoredFrom,
+		AuthorID:      authorID,
+		CreatedAt:     now,
+	}
+	if err := s.revisionRepo.Create(ctx, revision); err != nil {
+		if errors.Is(err, repository.ErrModuleRevisionExists) {
+			return ErrModuleChanged
+		}
+		return fmt.Errorf("failed to record module revision: %w", err)
+	}
+
+	if err := s.moduleRepo.UpdateModule(ctx, updated); err != nil {
+		if discardErr := s.revisionRepo.Discard(ctx, revision.ID); discardErr != nil {
+			slog.ErrorContext(ctx, "Failed to discard module revision", "module_id", existing.ID.Hex(), "revision", revision.Revision, "error", discardErr)
+		}
+		return fmt.Errorf("failed to update module: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	return nil
+}
+
+// changedModuleFields names the revisioned fields that differ between two states of a module, in
+// field order
+func changedModuleFields(before, after *models.Module) ([]string, error) {
+	from, err := revisionFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := revisionFields(after)
+	if err != nil {
+		return nil, err
+	}
+	return differingFields(from, to), nil
+}
+
+// moduleChanges lists the revisioned fields that differ between two states of a module, in field
+// order, with their values as the API shows them and a line diff of text
+func moduleChanges(before, after *models.Module) ([]models.ModuleFieldChange, error) {
+	from, err := revisionFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := revisionFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []models.ModuleFieldChange
+	for _, field := range differingFields(from, to) {
+		change := models.ModuleFieldChange{Field: field, From: from[field], To: to[field]}
+		fromText, fromIsText := from[field].(string)
+		toText, toIsText := to[field].(string)
+		if (fromIsText || from[field] == nil) && (toIsText || to[field] == nil) && strings.Contains(fromText+toText, "\n") {
+			change.Lines = diffLines(fromText, toText)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func differingFields(from, to map[string]interface{}) []string {
+	fields := make([]string, 0, len(from)+len(to))
+	for field := range from {
+		if !reflect.DeepEqual(from[field], to[field]) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range to {
+		if _, ok := from[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// revisionFields flattens the revisioned content of a module into fields named as the API shows them,
+// with submodules' fields under sub_modules.<id>
+func revisionFields(module *models.Module) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if module == nil {
+		return fields, nil
+	}
+	add := func(prefix, name, description, content string, prerequisites []models.ContentRef) {
+		fields[prefix+"name"] = name
+		fields[prefix+"description"] = description
+		fields[prefix+"content"] = content
+		if len(prerequisites) > 0 {
+			fields[prefix+"prerequisites"] = prerequisites
+		}
+	}
+	add("", module.Name, module.Description, module.Content, module.Prerequisites)
+	for _, sub := range module.SubModules {
+		prefix := "sub_modules." + sub.ID.Hex() + "."
+		add(prefix, sub.Name, sub.Description, sub.Content, sub.Prerequisites)
+		if sub.ParentID != nil {
+			fields[prefix+"parent_id"] = sub.ParentID
+		}
+	}
+
+	// Compare values as the API shows them, so IDs and strings decoded from storage match fresh ones
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// diffLines is a line diff of two texts by longest common subsequence, after their common first and
+// last lines
+func diffLines(before, after string) []models.DiffLine {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+	if before == "" {
+		a = nil
+	}
+	if after == "" {
+		b = nil
+	}
+
+	var head, tail []models.DiffLine
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		head = append(head, models.DiffLine{Op: "=", Text: a[0]})
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		tail = append([]models.DiffLine{{Op: "=", Text: a[len(a)-1]}}, tail...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	var middle []models.DiffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			middle = append(middle, models.DiffLine{Op: "-", Text: line})
+		}
+		for _, line := range b {
+			middle = append(middle, models.DiffLine{Op: "+", Text: line})
+		}
+	} else {
+		// common[i][j] is the longest common subsequence of a[i:] and b[j:]
+		common := make([][]int, len(a)+1)
+		for i := range common {
+			common[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					common[i][j] = common[i+1][j+1] + 1
+				} else {
+					common[i][j] = max(common[i+1][j], common[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(a) || j < len(b) {
+			switch {
+			case i < len(a) && j < len(b) && a[i] == b[j]:
+				middle = append(middle, models.DiffLine{Op: "=", Text: a[i]})
+				i++
+				j++
+			case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+				middle = append(middle, models.DiffLine{Op: "-", Text: a[i]})
+				i++
+			default:
+				middle = append(middle, models.DiffLine{Op: "+", Text: b[j]})
+				j++
+			}
+		}
+	}
+
+	lines := append(head, middle...)
+	return append(lines, tail...)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxModuleSearchMatches bounds how many modules one search reads. Matches are narrowed to what the
+	// searcher may see before they are paged, so they are read all at once.
+	maxModuleSearchMatches = 500
+
+	// maxModuleSearchHighlights bounds the highlights shown for one module
+	maxModuleSearchHighlights = 10
+)
+
+// SearchModules searches module and submodule names, descriptions and content, ranked by the text
+// index. The learner view only finds published content, and matches content locked for the learner
+// by name and description alone. Modules without a match the searcher can see are left out.
+func (s *moduleService) SearchModules(ctx context.Context, req *models.ModuleSearchRequest, learnerID *primitive.ObjectID, learnerView bool) (*models.ModuleSearchResponse, error) {
+	search := strings.TrimSpace(req.Query)
+	filter := bson.M{}
+	if learnerView {
+		filter["is_published"] = true
+	} else if req.Published != nil {
+		filter["is_published"] = *req.Published
+	}
+
+	mode := models.SearchModeText
+	modules, err := s.moduleRepo.SearchModules(ctx, withTextSearch(filter, search), maxModuleSearchMatches)
+	if errors.Is(err, repository.ErrModuleTextIndexNotFound) {
+		slog.WarnContext(ctx, "Module text index not found, searching by pattern")
+		mode = models.SearchModePattern
+		modules, err = s.moduleRepo.FindModules(ctx, withModulePatternSearch(filter, search), maxModuleSearchMatches)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search modules: %w", err)
+	}
+
+	if learnerView {
+		// Gating leaves locked content out, so it cannot match below
+		if err := s.ApplyGating(ctx, modules, learnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	results := []models.ModuleSearchResult{}
+	if expression := searchExpression(searchTerms(search)); expression != "" {
+		pattern := regexp.MustCompile("(?i)" + expression)
+		for i := range modules {
+			result := moduleSearchResult(&modules[i], pattern, learnerView)
+			if len(result.Highlights) > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+
+	total := len(results)
+	from := min((req.Page-1)*req.Limit, total)
+	to := min(from+req.Limit, total)
+	return &models.ModuleSearchResponse{
+		Results:    results[from:to],
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: (total + req.Limit - 1) / req.Limit,
+		SearchMode: mode,
+	}, nil
+}
+
+// withModulePatternSearch returns a copy of the filter matching any of the search terms in the fields
+// the module text index covers
+func withModulePatternSearch(filter bson.M, search string) bson.M {
+	searched := copyFilter(filter)
+	expression := searchExpression(searchTerms(search))
+	if expression == "" {
+		return searched
+	}
+	match := bson.M{"$regex": expression, "$options": "i"}
+	searched["$or"] = []bson.M{
+		{"name": match},
+		{"description": match},
+		{"content": match},
+		{"sub_modules.name": match},
+		{"sub_modules.description": match},
+		{"sub_modules.content": match},
+	}
+	return searched
+}
+
+// moduleSearchResult marks where the pattern matches a module and the submodules the searcher can see
+func moduleSearchResult(module *models.Module, pattern *regexp.Regexp, learnerView bool) models.ModuleSearchResult {
+	result := models.ModuleSearchResult{
+		ModuleID:    module.ID,
+		Name:        module.Name,
+		Description: module.Description,
+		IsPublished: module.IsPublished,
+		Locked:      module.Locked,
+		Score:       module.SearchScore,
+		Highlights:  searchHighlights(module.Name, module.Description, module.Content, pattern),
+	}
+
+	for _, sub := range module.SubModules {
+		if learnerView && !module.SubModulePublished(sub.ID) {
+			continue
+		}
+		for _, highlight := range searchHighlights(sub.Name, sub.Description, sub.Content, pattern) {
+			highlight.SubModuleID = &sub.ID
+			highlight.Breadcrumbs = append(append([]models.Breadcrumb{}, sub.Breadcrumbs...), models.Breadcrumb{ID: sub.ID, Name: sub.Name})
+			result.Highlights = append(result.Highlights, highlight)
+		}
+	}
+
+	if len(result.Highlights) > maxModuleSearchHighlights {
+		result.Highlights = result.Highlights[:maxModuleSearchHighlights]
+	}
+	return result
+}
+
+// searchHighlights marks where the pattern matches a name and description, and the first match in the
+// content with some context around it
+func searchHighlights(name, description, content string, pattern *regexp.Regexp) []models.ModuleSearchHighlight {
+	var highlights []models.ModuleSearchHighlight
+	if pattern.MatchString(name) {
+		highlights = append(highlights, models.ModuleSearchHighlight{Field: "name", Snippet: markMatches(name, pattern)})
+	}
+	if pattern.MatchString(description) {
+		highlights = append(highlights, models.ModuleSearchHighlight{Field: "description", Snippet: markMatches(description, pattern)})
+	}
+	if loc := pattern.FindStringIndex(content); loc != nil {
+		highlights = append(highlights, models.ModuleSearchHighlight{
+			Field:   "content",
+			Snippet: markMatches(snippetAround(content, loc[0], loc[1]), pattern),
+		})
+	}
+	return highlights
+}
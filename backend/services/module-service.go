@@ -2,15 +2,24 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"sort"
 	"time"
 
 	"backend/models"
 	"backend/repository"
+	"backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrSubModuleNotFound is returned when the module has no submodule with the ID
+var ErrSubModuleNotFound = models.NotFoundError("submodule not found")
+
 type ModuleService interface {
 	GetAllModules(ctx context.Context, req *models.GetModulesRequest) (*models.GetModulesResponse, error)
 	GetModuleByID(ctx context.Context, moduleID primitive.ObjectID) (*models.Module, error)
@@ -21,21 +30,104 @@ type ModuleService interface {
 	ReorderModules(ctx context.Context, moduleIDs []string, userID primitive.ObjectID) error
 	ReorderSubModules(ctx context.Context, moduleID primitive.ObjectID, subModuleIDs []string, userID primitive.ObjectID) error
 	BulkReorder(ctx context.Context, req *models.BulkReorderRequest, userID primitive.ObjectID) error
+	GetContentFeed(ctx context.Context, limit int, siteURL, selfURL string) (*models.ContentFeed, error)
+	SearchModules(ctx context.Context, req *models.ModuleSearchRequest, learnerID *primitive.ObjectID, learnerView bool) (*models.ModuleSearchResponse, error)
+
+	// Import from markdown bundles, and the images uploaded with them
+	ImportModule(ctx context.Context, bundle io.ReaderAt, size int64, req *models.ImportModuleRequest, userID primitive.ObjectID) (*models.ImportModuleResponse, error)
+	OpenModuleImage(ctx context.Context, moduleID, imageID primitive.ObjectID) (*models.ModuleImage, io.ReadCloser, error)
+	GetModulePackage(ctx context.Context, moduleID primitive.ObjectID, siteURL, apiURL string) (*models.LearningPackage, error)
+	SaveReadingPosition(ctx context.Context, userID, moduleID primitive.ObjectID, req *models.SaveReadingPositionRequest) (*models.ReadingPosition, error)
+	GetContinueReading(ctx context.Context, userID primitive.ObjectID, req *models.ContinueReadingRequest) (*models.ContinueReadingResponse, error)
+
+	// Prerequisites and learner progress
+	ApplyGating(ctx context.Context, modules []models.Module, learnerID *primitive.ObjectID) error
+	GetModuleForLearner(ctx context.Context, moduleID primitive.ObjectID, learnerID *primitive.ObjectID) (*models.Module, error)
+	CompleteModule(ctx context.Context, userID, moduleID primitive.ObjectID) (*models.ModuleProgress, error)
+	CompleteSubModule(ctx context.Context, userID, moduleID, subModuleID primitive.ObjectID) (*models.ModuleProgress, error)
+	SetModuleUnlocked(ctx context.Context, adminID, userID, moduleID primitive.ObjectID, unlocked bool) (*models.ModuleProgress, error)
+
+	// Revision history
+	GetModuleRevisions(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleRevisionsResponse, error)
+	DiffModuleRevisions(ctx context.Context, moduleID primitive.ObjectID, req *models.ModuleRevisionDiffRequest) (*models.ModuleRevisionDiff, error)
+	RollbackModule(ctx context.Context, moduleID primitive.ObjectID, revision int, userID primitive.ObjectID) (*models.Module, error)
+
+	// File attachments
+	UploadModuleAttachment(ctx context.Context, moduleID primitive.ObjectID, subModuleID *primitive.ObjectID, fileName string, size int64, body io.Reader, userID primitive.ObjectID) (*models.ModuleAttachment, error)
+	GetModuleAttachments(ctx context.Context, moduleID primitive.ObjectID) (*models.ModuleAttachmentsResponse, error)
+	GetLearnerAttachments(ctx context.Context, moduleID, learnerID primitive.ObjectID) (*models.ModuleAttachmentsResponse, error)
+	OpenModuleAttachment(ctx context.Context, moduleID, attachmentID primitive.ObjectID) (*models.ModuleAttachment, io.ReadCloser, error)
+	OpenLearnerAttachment(ctx context.Context, moduleID, attachmentID, learnerID primitive.ObjectID) (*models.ModuleAttachment, io.ReadCloser, error)
+	DeleteModuleAttachment(ctx context.Context, moduleID, attachmentID primitive.ObjectID) (*models.ModuleAttachment, error)
+
+	// View analytics
+	RecordModuleView(ctx context.Context, moduleID, userID primitive.ObjectID, isGuest bool, req *models.RecordModuleViewRequest) error
+	GetModuleAnalytics(ctx context.Context, moduleID primitive.ObjectID, req *models.ModuleAnalyticsRequest) (*models.ModuleAnalytics, error)
+
+	// Submodule discussions
+	SetSubModuleDiscussion(ctx context.Context, moduleID, subModuleID primitive.ObjectID, enabled bool, userID primitive.ObjectID) (*models.SubModule, error)
+	GetSubModuleComments(ctx context.Context, moduleID, subModuleID, userID primitive.ObjectID, isAdmin bool, req *models.ListModuleCommentsRequest) (*models.ModuleCommentsResponse, error)
+	PostSubModuleComment(ctx context.Context, moduleID, subModuleID, userID primitive.ObjectID, isAdmin bool, req *models.PostModuleCommentRequest) (*models.ModuleComment, error)
+	ReportModuleComment(ctx context.Context, moduleID, subModuleID, commentID, userID primitive.ObjectID, req *models.ReportModuleCommentRequest) error
+	GetReportedModuleComments(ctx context.Context, req *models.ListReportedModuleCommentsRequest) (*models.ModuleCommentsResponse, error)
+	RemoveModuleComment(ctx context.Context, moduleID, commentID, adminID primitive.ObjectID) (*models.ModuleComment, error)
+	DismissModuleCommentReports(ctx context.Context, moduleID, commentID primitive.ObjectID) error
 
 	// SubModule methods
 	CreateSubModule(ctx context.Context, moduleID primitive.ObjectID, req *models.CreateSubModuleRequest, userID primitive.ObjectID) (*models.SubModule, error)
 	UpdateSubModule(ctx context.Context, moduleID primitive.ObjectID, subModuleID primitive.ObjectID, req *models.CreateSubModuleRequest, userID primitive.ObjectID) (*models.SubModule, error)
 	DeleteSubModule(ctx context.Context, moduleID primitive.ObjectID, subModuleID primitive.ObjectID) error
 	ToggleSubModulePublication(ctx context.Context, moduleID primitive.ObjectID, subModuleID primitive.ObjectID, published bool, userID primitive.ObjectID) (*models.SubModule, error)
+	MoveSubModule(ctx context.Context, moduleID, subModuleID primitive.ObjectID, req *models.MoveSubModuleRequest, userID primitive.ObjectID) (*models.SubModule, error)
 }
 
 type moduleService struct {
-	moduleRepo repository.ModuleRepository
+	moduleRepo     repository.ModuleRepository
+	progressRepo   repository.ModuleProgressRepository
+	revisionRepo   repository.ModuleRevisionRepository
+	attachmentRepo repository.ModuleAttachmentRepository
+	viewRepo       repository.ModuleViewRepository
+	commentRepo    repository.ModuleCommentRepository
+	imageRepo      repository.ModuleImageRepository
+	userRepo       repository.UserRepository
+
+	notificationRepo repository.NotificationRepository
+	transactor       repository.Transactor
+
+	storage           utils.FileStorage
+	maxAttachmentSize int64
+	cache             *utils.Cache
 }
 
-func NewModuleService(moduleRepo repository.ModuleRepository) ModuleService {
+func NewModuleService(
+	moduleRepo repository.ModuleRepository,
+	progressRepo repository.ModuleProgressRepository,
+	revisionRepo repository.ModuleRevisionRepository,
+	attachmentRepo repository.ModuleAttachmentRepository,
+	viewRepo repository.ModuleViewRepository,
+	commentRepo repository.ModuleCommentRepository,
+	imageRepo repository.ModuleImageRepository,
+	userRepo repository.UserRepository,
+	notificationRepo repository.NotificationRepository,
+	transactor repository.Transactor,
+	storage utils.FileStorage,
+	config models.StorageConfig,
+	cache *utils.Cache,
+) ModuleService {
 	return &moduleService{
-		moduleRepo: moduleRepo,
+		moduleRepo:        moduleRepo,
+		progressRepo:      progressRepo,
+		revisionRepo:      revisionRepo,
+		attachmentRepo:    attachmentRepo,
+		viewRepo:          viewRepo,
+		commentRepo:       commentRepo,
+		imageRepo:         imageRepo,
+		userRepo:          userRepo,
+		notificationRepo:  notificationRepo,
+		transactor:        transactor,
+		storage:           storage,
+		maxAttachmentSize: config.MaxAttachmentSize,
+		cache:             cache,
 	}
 }
 
@@ -51,6 +143,16 @@ func (s *moduleService) GetAllModules(ctx context.Context, req *models.GetModule
 		req.Limit = 100
 	}
 
+	// The published catalogue is what every learner lists, so its pages are cached
+	cacheKey := ""
+	if req.Published != nil && *req.Published && req.Search == "" {
+		cacheKey = fmt.Sprintf("published:%d:%d", req.Page, req.Limit)
+		var cached models.GetModulesResponse
+		if s.cache.Get(ctx, utils.CacheModules, cacheKey, &cached) {
+			return &cached, nil
+		}
+	}
+
 	modules, total, err := s.moduleRepo.GetAllModules(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get modules: %w", err)
@@ -61,13 +163,17 @@ func (s *moduleService) GetAllModules(ctx context.Context, req *models.GetModule
 		totalPages++
 	}
 
-	return &models.GetModulesResponse{
+	response := &models.GetModulesResponse{
 		Modules:    modules,
 		Total:      total,
 		Page:       req.Page,
 		Limit:      req.Limit,
 		TotalPages: totalPages,
-	}, nil
+	}
+	if cacheKey != "" {
+		s.cache.Set(ctx, utils.CacheModules, cacheKey, response)
+	}
+	return response, nil
 }
 
 func (s *moduleService) GetModuleByID(ctx context.Context, moduleID primitive.ObjectID) (*models.Module, error) {
@@ -80,26 +186,24 @@ func (s *moduleService) GetModuleByID(ctx context.Context, moduleID primitive.Ob
 }
 
 func (s *moduleService) CreateModule(ctx context.Context, req *models.CreateModuleRequest, userID primitive.ObjectID) (*models.Module, error) {
+	blocks, content, err := requiredModuleContent(req.Blocks, req.Content)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 
-	// If no order specified, set it to a high value (will be auto-ordered by creation time)
+	// If no order specified, put the module after the existing ones
 	order := req.Order
 	if order == 0 {
-		// Get the count of existing modules to set default order
-		// This ensures new modules without explicit order go to the end
-		allModules, _, err := s.moduleRepo.GetAllModules(ctx, &models.GetModulesRequest{Page: 1, Limit: 1000})
-		if err == nil {
-			order = len(allModules) + 1
-		} else {
-			order = 999 // Fallback if query fails
-		}
+		order = s.nextModuleOrder(ctx)
 	}
 
 	module := &models.Module{
 		ID:          primitive.NewObjectID(),
 		Name:        req.Name,
 		Description: req.Description,
-		Content:     req.Content,
+		Content:     content,
+		Blocks:      blocks,
 		SubModules:  []models.SubModule{},
 		IsPublished: false, // Always start as draft
 		Order:       order,
@@ -107,6 +211,8 @@ func (s *moduleService) CreateModule(ctx context.Context, req *models.CreateModu
 		UpdatedAt:   now,
 		CreatedBy:   userID,
 		UpdatedBy:   userID,
+
+		Prerequisites: normalizePrerequisites(req.Prerequisites),
 	}
 
 	// Create submodules if provided
@@ -116,36 +222,59 @@ func (s *moduleService) CreateModule(ctx context.Context, req *models.CreateModu
 			if subModuleOrder == 0 {
 				subModuleOrder = i + 1 // Auto-order submodules 1, 2, 3...
 			}
+			subModuleBlocks, subModuleContent, err := moduleContent(subModuleReq.Blocks, subModuleReq.Content)
+			if err != nil {
+				return nil, fmt.Errorf("submodule %d: %w", i+1, err)
+			}
 
 			subModule := models.SubModule{
 				ID:          primitive.NewObjectID(),
 				Name:        subModuleReq.Name,
 				Description: subModuleReq.Description,
-				Content:     subModuleReq.Content,
+				Content:     subModuleContent,
+				Blocks:      subModuleBlocks,
 				IsPublished: false, // Always start as draft
 				Order:       subModuleOrder,
 				CreatedAt:   now,
 				UpdatedAt:   now,
 				CreatedBy:   userID,
 				UpdatedBy:   userID,
+
+				Prerequisites: normalizePrerequisites(subModuleReq.Prerequisites),
 			}
 			module.SubModules = append(module.SubModules, subModule)
 		}
 	}
 
+	if err := s.checkPrerequisites(ctx, module); err != nil {
+		return nil, err
+	}
+
 	if err := s.moduleRepo.CreateModule(ctx, module); err != nil {
 		return nil, fmt.Errorf("failed to create module: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	module.EstimateReadingTime()
 
 	return module, nil
 }
 
+// nextModuleOrder is the order that puts a new module after the existing ones
+func (s *moduleService) nextModuleOrder(ctx context.Context) int {
+	allModules, _, err := s.moduleRepo.GetAllModules(ctx, &models.GetModulesRequest{Page: 1, Limit: 1000})
+	if err != nil {
+		return 999 // Fallback if query fails
+	}
+	return len(allModules) + 1
+}
+
 func (s *moduleService) UpdateModule(ctx context.Context, moduleID primitive.ObjectID, req *models.UpdateModuleRequest, userID primitive.ObjectID) (*models.Module, error) {
 	// Get existing module
 	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
 	if err != nil {
 		return nil, fmt.Errorf("module not found: %w", err)
 	}
+	existing := *module
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -154,22 +283,50 @@ func (s *moduleService) UpdateModule(ctx context.Context, moduleID primitive.Obj
 	if req.Description != nil {
 		module.Description = *req.Description
 	}
-	if req.Content != nil {
-		module.Content = *req.Content
+	if req.Blocks != nil || req.Content != nil {
+		content := ""
+		if req.Content != nil {
+			content = *req.Content
+		}
+		module.Blocks, module.Content, err = moduleContent(req.Blocks, content)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if req.Order != nil {
 		module.Order = *req.Order
 	}
 	if req.SubModules != nil {
+		for i := range req.SubModules {
+			subModule := &req.SubModules[i]
+			subModule.Blocks, subModule.Content, err = moduleContent(subModule.Blocks, subModule.Content)
+			if err != nil {
+				return nil, fmt.Errorf("submodule %d: %w", i+1, err)
+			}
+			subModule.Prerequisites = normalizePrerequisites(subModule.Prerequisites)
+		}
 		module.SubModules = req.SubModules
+		if err := checkSubModuleTree(module); err != nil {
+			return nil, err
+		}
+	}
+	if req.Prerequisites != nil {
+		module.Prerequisites = normalizePrerequisites(req.Prerequisites)
+	}
+	if req.Prerequisites != nil || req.SubModules != nil {
+		if err := s.checkPrerequisites(ctx, module); err != nil {
+			return nil, err
+		}
 	}
 
 	module.UpdatedAt = time.Now()
 	module.UpdatedBy = userID
 
-	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
-		return nil, fmt.Errorf("failed to update module: %w", err)
+	if err := s.saveRevisedModule(ctx, &existing, module, userID, 0); err != nil {
+		return nil, err
 	}
+	module.ArrangeSubModules()
+	module.EstimateReadingTime()
 
 	return module, nil
 }
@@ -178,6 +335,18 @@ func (s *moduleService) DeleteModule(ctx context.Context, moduleID primitive.Obj
 	if err := s.moduleRepo.DeleteModule(ctx, moduleID); err != nil {
 		return fmt.Errorf("failed to delete module: %w", err)
 	}
+	if err := s.moduleRepo.RemovePrerequisite(ctx, models.ContentRef{ModuleID: moduleID}); err != nil {
+		slog.ErrorContext(ctx, "Failed to remove prerequisites on deleted module", "module_id", moduleID.Hex(), "error", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	s.removeAttachments(ctx, moduleID, nil)
+	if err := s.viewRepo.DeleteByModule(ctx, moduleID); err != nil {
+		slog.ErrorContext(ctx, "Failed to remove views of deleted module", "module_id", moduleID.Hex(), "error", err)
+	}
+	if err := s.commentRepo.DeleteByModule(ctx, moduleID); err != nil {
+		slog.ErrorContext(ctx, "Failed to remove discussions of deleted module", "module_id", moduleID.Hex(), "error", err)
+	}
+	s.removeImages(ctx, moduleID)
 	return nil
 }
 
@@ -187,13 +356,18 @@ func (s *moduleService) ToggleModulePublication(ctx context.Context, moduleID pr
 		return nil, fmt.Errorf("module not found: %w", err)
 	}
 
+	now := time.Now()
 	module.IsPublished = published
-	module.UpdatedAt = time.Now()
+	module.UpdatedAt = now
+	if published && module.PublishedAt == nil {
+		module.PublishedAt = &now
+	}
 	module.UpdatedBy = userID
 
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return nil, fmt.Errorf("failed to update module publication status: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
 
 	return module, nil
 }
@@ -206,36 +380,53 @@ func (s *moduleService) CreateSubModule(ctx context.Context, moduleID primitive.
 		return nil, fmt.Errorf("module not found: %w", err)
 	}
 
+	blocks, content, err := requiredModuleContent(req.Blocks, req.Content)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
 
-	// If no order specified, set it to be after existing submodules
+	// If no order specified, set it to be after its siblings
 	order := req.Order
 	if order == 0 {
-		order = len(module.SubModules) + 1
+		order = len(siblingSubModules(module, req.ParentID, primitive.NilObjectID)) + 1
 	}
 
 	subModule := models.SubModule{
 		ID:          primitive.NewObjectID(),
 		Name:        req.Name,
 		Description: req.Description,
-		Content:     req.Content,
+		Content:     content,
+		Blocks:      blocks,
 		IsPublished: false, // Always start as draft
 		Order:       order,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		CreatedBy:   userID,
 		UpdatedBy:   userID,
+
+		Prerequisites: normalizePrerequisites(req.Prerequisites),
+		ParentID:      req.ParentID,
 	}
 
 	module.SubModules = append(module.SubModules, subModule)
+	if err := checkSubModuleTree(module); err != nil {
+		return nil, err
+	}
+	if err := s.checkPrerequisites(ctx, module); err != nil {
+		return nil, err
+	}
 	module.UpdatedAt = now
 	module.UpdatedBy = userID
 
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return nil, fmt.Errorf("failed to create submodule: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
 
-	return &subModule, nil
+	module.ArrangeSubModules()
+	module.EstimateReadingTime()
+	return findSubModule(module, subModule.ID), nil
 }
 
 func (s *moduleService) UpdateSubModule(ctx context.Context, moduleID primitive.ObjectID, subModuleID primitive.ObjectID, req *models.CreateSubModuleRequest, userID primitive.ObjectID) (*models.SubModule, error) {
@@ -254,13 +445,25 @@ func (s *moduleService) UpdateSubModule(ctx context.Context, moduleID primitive.
 	}
 
 	if subModuleIndex == -1 {
-		return nil, fmt.Errorf("submodule not found")
+		return nil, ErrSubModuleNotFound
+	}
+
+	blocks, content, err := requiredModuleContent(req.Blocks, req.Content)
+	if err != nil {
+		return nil, err
 	}
 
 	// Update submodule
 	module.SubModules[subModuleIndex].Name = req.Name
 	module.SubModules[subModuleIndex].Description = req.Description
-	module.SubModules[subModuleIndex].Content = req.Content
+	module.SubModules[subModuleIndex].Content = content
+	module.SubModules[subModuleIndex].Blocks = blocks
+	if req.Prerequisites != nil {
+		module.SubModules[subModuleIndex].Prerequisites = normalizePrerequisites(req.Prerequisites)
+		if err := s.checkPrerequisites(ctx, module); err != nil {
+			return nil, err
+		}
+	}
 	if req.Order != 0 {
 		module.SubModules[subModuleIndex].Order = req.Order
 	}
@@ -273,6 +476,8 @@ func (s *moduleService) UpdateSubModule(ctx context.Context, moduleID primitive.
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return nil, fmt.Errorf("failed to update submodule: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	module.EstimateReadingTime()
 
 	return &module.SubModules[subModuleIndex], nil
 }
@@ -283,27 +488,39 @@ func (s *moduleService) DeleteSubModule(ctx context.Context, moduleID primitive.
 		return fmt.Errorf("module not found: %w", err)
 	}
 
-	// Find and remove the submodule
+	if findSubModule(module, subModuleID) == nil {
+		return ErrSubModuleNotFound
+	}
+
+	// Find and remove the submodule, along with the submodules nested under it
+	deleted := append(module.SubModuleDescendants(subModuleID), subModuleID)
+	removed := make(map[primitive.ObjectID]bool, len(deleted))
+	for _, id := range deleted {
+		removed[id] = true
+	}
 	var newSubModules []models.SubModule
-	found := false
 	for _, subModule := range module.SubModules {
-		if subModule.ID != subModuleID {
+		if !removed[subModule.ID] {
 			newSubModules = append(newSubModules, subModule)
-		} else {
-			found = true
 		}
 	}
 
-	if !found {
-		return fmt.Errorf("submodule not found")
-	}
-
 	module.SubModules = newSubModules
 	module.UpdatedAt = time.Now()
 
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return fmt.Errorf("failed to delete submodule: %w", err)
 	}
+	for _, id := range deleted {
+		if err := s.moduleRepo.RemovePrerequisite(ctx, models.ContentRef{ModuleID: moduleID, SubModuleID: &id}); err != nil {
+			slog.ErrorContext(ctx, "Failed to remove prerequisites on deleted submodule", "submodule_id", id.Hex(), "error", err)
+		}
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	s.removeAttachments(ctx, moduleID, deleted)
+	if err := s.commentRepo.DeleteBySubModules(ctx, moduleID, deleted); err != nil {
+		slog.ErrorContext(ctx, "Failed to remove discussions of deleted submodules", "module_id", moduleID.Hex(), "error", err)
+	}
 
 	return nil
 }
@@ -324,11 +541,15 @@ func (s *moduleService) ToggleSubModulePublication(ctx context.Context, moduleID
 	}
 
 	if subModuleIndex == -1 {
-		return nil, fmt.Errorf("submodule not found")
+		return nil, ErrSubModuleNotFound
 	}
 
+	now := time.Now()
 	module.SubModules[subModuleIndex].IsPublished = published
-	module.SubModules[subModuleIndex].UpdatedAt = time.Now()
+	module.SubModules[subModuleIndex].UpdatedAt = now
+	if published && module.SubModules[subModuleIndex].PublishedAt == nil {
+		module.SubModules[subModuleIndex].PublishedAt = &now
+	}
 	module.SubModules[subModuleIndex].UpdatedBy = userID
 
 	module.UpdatedAt = time.Now()
@@ -337,6 +558,7 @@ func (s *moduleService) ToggleSubModulePublication(ctx context.Context, moduleID
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return nil, fmt.Errorf("failed to update submodule publication status: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
 
 	return &module.SubModules[subModuleIndex], nil
 }
@@ -361,11 +583,13 @@ func (s *moduleService) ReorderModules(ctx context.Context, moduleIDs []string,
 		if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 			return fmt.Errorf("failed to update module order %s: %w", moduleIDStr, err)
 		}
+		s.cache.Invalidate(ctx, utils.CacheModules)
 	}
 
 	return nil
 }
 
+// ReorderSubModules orders a group of sibling submodules as listed, leaving the rest of the tree as it is
 func (s *moduleService) ReorderSubModules(ctx context.Context, moduleID primitive.ObjectID, subModuleIDs []string, userID primitive.ObjectID) error {
 	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
 	if err != nil {
@@ -378,107 +602,126 @@ func (s *moduleService) ReorderSubModules(ctx context.Context, moduleID primitiv
 		subModuleMap[module.SubModules[i].ID.Hex()] = &module.SubModules[i]
 	}
 
-	// Reorder submodules according to the provided IDs
-	var reorderedSubModules []models.SubModule
+	// Update order to match position in array (1-based)
+	var parent *primitive.ObjectID
 	for i, subModuleIDStr := range subModuleIDs {
 		subModule, exists := subModuleMap[subModuleIDStr]
 		if !exists {
 			return fmt.Errorf("submodule not found: %s", subModuleIDStr)
 		}
+		if i == 0 {
+			parent = subModule.ParentID
+		} else if !sameParent(subModule.ParentID, parent) {
+			return errors.New("submodules to reorder must share a parent")
+		}
 
-		// Update order to match position in array (1-based)
 		subModule.Order = i + 1
 		subModule.UpdatedAt = time.Now()
 		subModule.UpdatedBy = userID
-
-		reorderedSubModules = append(reorderedSubModules, *subModule)
 	}
 
-	// Update module with reordered submodules
-	module.SubModules = reorderedSubModules
 	module.UpdatedAt = time.Now()
 	module.UpdatedBy = userID
 
 	if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
 		return fmt.Errorf("failed to update submodule order: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
 
 	return nil
 }
 
 func (s *moduleService) BulkReorder(ctx context.Context, req *models.BulkReorderRequest, userID primitive.ObjectID) error {
-	// Handle module reordering
-	if len(req.ModuleUpdates) > 0 {
-		// Add user ID to each update
-		for i := range req.ModuleUpdates {
-			req.ModuleUpdates[i].UpdatedBy = userID
-		}
-
-		if err := s.moduleRepo.BulkUpdateModuleOrder(ctx, req.ModuleUpdates); err != nil {
-			return fmt.Errorf("failed to bulk update module order: %w", err)
-		}
+	if len(req.ModuleUpdates) == 0 && len(req.SubModuleUpdates) == 0 {
+		return nil
 	}
 
-	// Handle submodule reordering
-	if len(req.SubModuleUpdates) > 0 {
-		// Group submodule updates by parent module
-		moduleSubModules := make(map[primitive.ObjectID][]models.SubModuleOrderUpdate)
-
-		for _, update := range req.SubModuleUpdates {
-			// We need to find the parent module for each submodule
-			// This requires querying each module to find which one contains the submodule
-			modules, _, err := s.moduleRepo.GetAllModules(ctx, &models.GetModulesRequest{Page: 1, Limit: 1000})
-			if err != nil {
-				return fmt.Errorf("failed to get modules for submodule reordering: %w", err)
-			}
+	// Add user ID to each update
+	for i := range req.ModuleUpdates {
+		req.ModuleUpdates[i].UpdatedBy = userID
+	}
+	for i := range req.SubModuleUpdates {
+		req.SubModuleUpdates[i].UpdatedBy = userID
+	}
 
-			var parentModuleID primitive.ObjectID
-			for _, module := range modules {
-				for _, subModule := range module.SubModules {
-					if subModule.ID == update.SubModuleID {
-						parentModuleID = module.ID
-						break
-					}
-				}
-				if !parentModuleID.IsZero() {
-					break
-				}
-			}
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		return s.moduleRepo.BulkUpdateOrder(ctx, req.ModuleUpdates, req.SubModuleUpdates)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to bulk reorder: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheModules)
+	return nil
+}
 
-			if parentModuleID.IsZero() {
-				return fmt.Errorf("parent module not found for submodule: %s", update.SubModuleID.Hex())
-			}
+// GetContentFeed lists newly published modules and submodules for syndication, linking each entry to
+// its page on siteURL
+func (s *moduleService) GetContentFeed(ctx context.Context, limit int, siteURL, selfURL string) (*models.ContentFeed, error) {
+	modules, err := s.moduleRepo.GetRecentlyUpdatedPublished(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published modules: %w", err)
+	}
 
-			moduleSubModules[parentModuleID] = append(moduleSubModules[parentModuleID], update)
-		}
+	documentationURL := siteURL + "/dokumentasi"
+	entryLink := func(id primitive.ObjectID) string {
+		return documentationURL + "?section=" + url.QueryEscape(id.Hex())
+	}
 
-		// Update each module's submodules
-		for moduleID, subModuleUpdates := range moduleSubModules {
-			module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
-			if err != nil {
-				return fmt.Errorf("failed to get module %s: %w", moduleID.Hex(), err)
-			}
+	entries := []models.FeedEntry{}
+	for _, module := range modules {
+		entries = append(entries, models.FeedEntry{
+			ID:          module.ID.Hex(),
+			Title:       module.Name,
+			Summary:     module.Description,
+			Link:        entryLink(module.ID),
+			PublishedAt: publishedAt(module.PublishedAt, module.CreatedAt),
+			UpdatedAt:   module.UpdatedAt,
+		})
 
-			// Update submodule orders
-			for _, update := range subModuleUpdates {
-				for i := range module.SubModules {
-					if module.SubModules[i].ID == update.SubModuleID {
-						module.SubModules[i].Order = update.Order
-						module.SubModules[i].UpdatedAt = time.Now()
-						module.SubModules[i].UpdatedBy = userID
-						break
-					}
-				}
+		for _, subModule := range module.SubModules {
+			if !module.SubModulePublished(subModule.ID) {
+				continue
 			}
+			entries = append(entries, models.FeedEntry{
+				ID:          subModule.ID.Hex(),
+				Title:       subModule.Name,
+				Summary:     subModule.Description,
+				Link:        entryLink(subModule.ID),
+				Category:    module.Name,
+				PublishedAt: publishedAt(subModule.PublishedAt, subModule.CreatedAt),
+				UpdatedAt:   subModule.UpdatedAt,
+			})
+		}
+	}
 
-			module.UpdatedAt = time.Now()
-			module.UpdatedBy = userID
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].PublishedAt.After(entries[j].PublishedAt) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
 
-			if err := s.moduleRepo.UpdateModule(ctx, module); err != nil {
-				return fmt.Errorf("failed to update module %s with reordered submodules: %w", moduleID.Hex(), err)
-			}
+	feed := &models.ContentFeed{
+		ID:       selfURL,
+		Title:    "z0nata learning modules",
+		Subtitle: "Newly published modules and submodules",
+		Link:     documentationURL,
+		SelfLink: selfURL,
+		Entries:  entries,
+	}
+	for _, entry := range entries {
+		if entry.UpdatedAt.After(feed.Updated) {
+			feed.Updated = entry.UpdatedAt
 		}
 	}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+	return feed, nil
+}
 
-	return nil
+// publishedAt falls back to the creation time for content published before publication times were kept
+func publishedAt(published *time.Time, created time.Time) time.Time {
+	if published != nil {
+		return *published
+	}
+	return created
 }
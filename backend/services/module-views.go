@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultModuleAnalyticsDays is how far back module analytics look without a date_from
+const defaultModuleAnalyticsDays = 30
+
+// RecordModuleView stores that a learner read a module page, or one of its submodules, they can read
+func (s *moduleService) RecordModuleView(ctx context.Context, moduleID, userID primitive.ObjectID, isGuest bool, req *models.RecordModuleViewRequest) error {
+	module, err := s.readableModule(ctx, moduleID, userID)
+	if err != nil {
+		return err
+	}
+	if req.SubModuleID != nil {
+		subModule := findSubModule(module, *req.SubModuleID)
+		if subModule == nil || !module.SubModulePublished(subModule.ID) {
+			return ErrSubModuleNotFound
+		}
+		if subModule.Locked {
+			return ErrSubModuleLocked
+		}
+	}
+
+	view := &models.ModuleView{
+		ModuleID:        moduleID,
+		SubModuleID:     req.SubModuleID,
+		UserID:          userID,
+		IsGuest:         isGuest,
+		DurationSeconds: req.DurationSeconds,
+	}
+	if err := s.viewRepo.Record(ctx, view); err != nil {
+		return fmt.Errorf("failed to record module view: %w", err)
+	}
+	return nil
+}
+
+// GetModuleAnalytics reports who read a module and its submodules over a range of days, and where in
+// the reading order viewers stop
+func (s *moduleService) GetModuleAnalytics(ctx context.Context, moduleID primitive.ObjectID, req *models.ModuleAnalyticsRequest) (*models.ModuleAnalytics, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		end := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+		to = &end
+	}
+	if from == nil {
+		start := to.AddDate(0, 0, -defaultModuleAnalyticsDays)
+		from = &start
+	}
+	interval, format := "day", "%Y-%m-%d"
+	if req.Interval == "week" {
+		interval, format = "week", "%G-W%V"
+	}
+
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.viewRepo.Summarize(ctx, moduleID, *from, *to, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize module views: %w", err)
+	}
+
+	analytics := &models.ModuleAnalytics{
+		ModuleID:        module.ID,
+		ModuleName:      module.Name,
+		From:            *from,
+		To:              *to,
+		Interval:        interval,
+		ModuleViewStats: summary.Totals,
+		PageViews:       summary.PageViews,
+		ViewsOverTime:   summary.Periods,
+		SubModules:      []models.SubModuleViewStats{},
+		Unread:          []models.Breadcrumb{},
+	}
+
+	// Submodules are arranged in reading order, so each is compared with the one read before it
+	previous := summary.Totals.UniqueViewers
+	for _, sub := range module.SubModules {
+		stats := models.SubModuleViewStats{
+			SubModuleID:     sub.ID,
+			Name:            sub.Name,
+			Depth:           sub.Depth,
+			IsPublished:     module.SubModulePublished(sub.ID),
+			ModuleViewStats: summary.SubModules[sub.ID],
+		}
+		if summary.Totals.UniqueViewers > 0 {
+			stats.Reach = float64(stats.UniqueViewers) / float64(summary.Totals.UniqueViewers)
+		}
+		if stats.IsPublished {
+			if previous > 0 && stats.UniqueViewers < previous {
+				stats.DropOff = 1 - float64(stats.UniqueViewers)/float64(previous)
+			}
+			previous = stats.UniqueViewers
+			if stats.Views == 0 {
+				analytics.Unread = append(analytics.Unread, models.Breadcrumb{ID: sub.ID, Name: sub.Name})
+			}
+		}
+		analytics.SubModules = append(analytics.SubModules, stats)
+	}
+	return analytics, nil
+}
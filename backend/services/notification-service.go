@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService interface {
+	ListNotifications(ctx context.Context, userID primitive.ObjectID, req *models.ListNotificationsRequest) (*models.ListNotificationsResponse, error)
+	MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error
+	MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, userID primitive.ObjectID, req *models.ListNotificationsRequest) (*models.ListNotificationsResponse, error) {
+	notifications, total, err := s.notificationRepo.List(ctx, userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListNotificationsResponse{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+		Page:          req.Page,
+		Limit:         req.Limit,
+		TotalPages:    totalPages,
+	}, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, notificationID primitive.ObjectID) error {
+	return s.notificationRepo.MarkRead(ctx, userID, notificationID)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	count, err := s.notificationRepo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return count, nil
+}
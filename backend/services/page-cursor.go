@@ -0,0 +1,20 @@
+package services
+
+import "backend/models"
+
+// parseCursor reads the cursor a listing was asked to continue after, or nil to page by number
+func parseCursor(value string) (*models.PageCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return models.ParsePageCursor(value)
+}
+
+// cursorPage trims a page read past a cursor, which holds one item past the limit when more follow,
+// and reports whether they do
+func cursorPage[T any](items []T, limit int) ([]T, bool) {
+	if limit > 0 && len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
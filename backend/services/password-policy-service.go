@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"backend/models"
+	"backend/utils"
+)
+
+type PasswordPolicyService interface {
+	GetPolicy() *models.PasswordPolicyResponse
+	Validate(password string) error
+	CheckReuse(password string, user *models.User) error
+	NextHistory(user *models.User) []string
+}
+
+type passwordPolicyService struct {
+	policy models.PasswordPolicyConfig
+}
+
+func NewPasswordPolicyService(policy models.PasswordPolicyConfig) PasswordPolicyService {
+	if policy.MinLength < 1 {
+		policy.MinLength = 8
+	}
+	if policy.HistorySize < 0 {
+		policy.HistorySize = 0
+	}
+
+	return &passwordPolicyService{
+		policy: policy,
+	}
+}
+
+func (s *passwordPolicyService) GetPolicy() *models.PasswordPolicyResponse {
+	requirements := []string{fmt.Sprintf("At least %d characters", s.policy.MinLength)}
+	if s.policy.RequireUppercase {
+		requirements = append(requirements, "At least one uppercase letter")
+	}
+	if s.policy.RequireLowercase {
+		requirements = append(requirements, "At least one lowercase letter")
+	}
+	if s.policy.RequireDigit {
+		requirements = append(requirements, "At least one digit")
+	}
+	if s.policy.RequireSymbol {
+		requirements = append(requirements, "At least one symbol")
+	}
+	if s.policy.BanCommonPasswords {
+		requirements = append(requirements, "Not a commonly used password")
+	}
+	if s.policy.HistorySize > 0 {
+		requirements = append(requirements, fmt.Sprintf("Different from your last %d passwords", s.policy.HistorySize))
+	}
+
+	return &models.PasswordPolicyResponse{
+		PasswordPolicyConfig: s.policy,
+		Requirements:         requirements,
+	}
+}
+
+// Validate checks a new password against the composition rules, reporting every rule it breaks
+func (s *passwordPolicyService) Validate(password string) error {
+	var violations []string
+
+	if len([]rune(password)) < s.policy.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", s.policy.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if s.policy.RequireUppercase && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if s.policy.RequireLowercase && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if s.policy.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if s.policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+	if s.policy.BanCommonPasswords && isCommonPassword(password) {
+		violations = append(violations, "is too common")
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("password does not meet policy: %s", strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// CheckReuse rejects the user's current password and the previous ones kept in their history
+func (s *passwordPolicyService) CheckReuse(password string, user *models.User) error {
+	if s.policy.HistorySize == 0 || user == nil {
+		return nil
+	}
+
+	for _, hash := range s.recentHashes(user) {
+		if valid, err := utils.VerifyPassword(password, hash); err == nil && valid {
+			return fmt.Errorf("password does not meet policy: must be different from your last %d passwords", s.policy.HistorySize)
+		}
+	}
+
+	return nil
+}
+
+// NextHistory returns the history to store alongside a new password: the outgoing hash followed
+// by the older ones, trimmed to the configured size
+func (s *passwordPolicyService) NextHistory(user *models.User) []string {
+	if s.policy.HistorySize == 0 || user == nil {
+		return []string{}
+	}
+	return s.recentHashes(user)
+}
+
+// recentHashes lists the current password hash and its predecessors, most recent first
+func (s *passwordPolicyService) recentHashes(user *models.User) []string {
+	hashes := make([]string, 0, s.policy.HistorySize)
+	if user.PasswordHash != "" {
+		hashes = append(hashes, user.PasswordHash)
+	}
+	for _, hash := range user.PasswordHistory {
+		if len(hashes) >= s.policy.HistorySize {
+			break
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// isCommonPassword compares case-insensitively so trivial capitalization doesn't get past the list
+func isCommonPassword(password string) bool {
+	_, banned := commonPasswords[strings.ToLower(password)]
+	return banned
+}
+
+// commonPasswords holds widely leaked passwords, lowercased
+var commonPasswords = func() map[string]struct{} {
+	list := []string{
+		"123456", "123456789", "12345678", "1234567890", "12345", "1234567", "password", "password1",
+		"password123", "passw0rd", "p@ssw0rd", "p@ssword", "qwerty", "qwerty123", "qwertyuiop", "1q2w3e4r",
+		"1q2w3e4r5t", "qwe123", "abc123", "abcd1234", "111111", "000000", "123123", "654321",
+		"666666", "121212", "112233", "123321", "iloveyou", "iloveyou1", "admin", "admin123",
+		"administrator", "welcome", "welcome1", "welcome123", "letmein", "letmein1", "monkey", "dragon",
+		"sunshine", "princess", "football", "baseball", "superman", "batman", "master", "shadow",
+		"michael", "jennifer", "trustno1", "starwars", "whatever", "freedom", "hello123", "login",
+		"changeme", "secret", "zaq12wsx", "asdfghjkl", "asdf1234", "1qaz2wsx", "aa123456", "qazwsx",
+		"student", "student123", "mahasiswa", "mahasiswa123", "indonesia", "bismillah", "sayang", "rahasia",
+		"katasandi", "default", "test1234", "testing123", "summer2024", "winter2024", "spring2024", "autumn2024",
+		"summer2025", "winter2025", "password2024", "password2025", "password!", "password1!", "qwerty1!", "abc12345",
+	}
+	set := make(map[string]struct{}, len(list))
+	for _, password := range list {
+		set[password] = struct{}{}
+	}
+	return set
+}()
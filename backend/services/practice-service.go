@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PracticeService interface {
+	GetDueQuestions(ctx context.Context, userID primitive.ObjectID, limit int) (*models.DuePracticeResponse, error)
+	RecordReview(ctx context.Context, userID primitive.ObjectID, req *models.RecordPracticeReviewRequest) (*models.QuestionMemory, error)
+
+	// RecordResult moves each credited student's memory of the result's questions along, in the background
+	RecordResult(userIDs []primitive.ObjectID, result *models.DetailedQuizResult)
+}
+
+type practiceService struct {
+	memoryRepo   repository.QuestionMemoryRepository
+	questionRepo repository.QuestionRepository
+	tasks        *utils.BackgroundTasks
+}
+
+func NewPracticeService(memoryRepo repository.QuestionMemoryRepository, questionRepo repository.QuestionRepository, tasks *utils.BackgroundTasks) PracticeService {
+	return &practiceService{
+		memoryRepo:   memoryRepo,
+		questionRepo: questionRepo,
+		tasks:        tasks,
+	}
+}
+
+// GetDueQuestions returns the questions whose review is due, most overdue first. Questions that were
+// deactivated or deleted since are left out.
+func (s *practiceService) GetDueQuestions(ctx context.Context, userID primitive.ObjectID, limit int) (*models.DuePracticeResponse, error) {
+	now := time.Now()
+	memories, err := s.memoryRepo.GetDue(ctx, userID, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due questions: %w", err)
+	}
+	totalDue, err := s.memoryRepo.CountDue(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count due questions: %w", err)
+	}
+
+	questionIDs := make([]primitive.ObjectID, len(memories))
+	for i, memory := range memories {
+		questionIDs[i] = memory.QuestionID
+	}
+	questions, err := s.questionRepo.GetByIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load due questions: %w", err)
+	}
+	byID := make(map[primitive.ObjectID]*models.Question, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	due := []models.PracticeQuestion{}
+	for _, memory := range memories {
+		q, ok := byID[memory.QuestionID]
+		if !ok {
+			continue
+		}
+		due = append(due, models.PracticeQuestion{
+			QuestionID:      q.ID,
+			Title:           q.Title,
+			Type:            q.Type,
+			Difficulty:      q.Difficulty,
+			Media:           q.Media,
+			Audio:           q.Audio,
+			Options:         displayOptions(q),
+			Unit:            numericUnit(q),
+			OrderingScoring: q.OrderingScoring,
+			Schedule:        memory.Schedule,
+			Reviews:         memory.Reviews,
+			Lapses:          memory.Lapses,
+		})
+	}
+
+	return &models.DuePracticeResponse{
+		Questions: due,
+		Total:     len(due),
+		TotalDue:  totalDue,
+	}, nil
+}
+
+// RecordReview reschedules a question the student reviewed outside a quiz session
+func (s *practiceService) RecordReview(ctx context.Context, userID primitive.ObjectID, req *models.RecordPracticeReviewRequest) (*models.QuestionMemory, error) {
+	questionID, err := primitive.ObjectIDFromHex(req.QuestionID)
+	if err != nil {
+		return nil, models.BadRequestError("invalid question ID")
+	}
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if !question.IsActive {
+		return nil, repository.ErrQuestionNotFound
+	}
+
+	quality := models.PracticeQualityWrong
+	if req.Quality != nil {
+		quality = *req.Quality
+	} else if *req.Correct {
+		quality = models.PracticeQualityCorrect
+	}
+
+	now := time.Now()
+	memory, err := s.memoryRepo.Get(ctx, userID, questionID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrQuestionMemoryNotFound) {
+			return nil, fmt.Errorf("failed to get question memory: %w", err)
+		}
+		memory = newQuestionMemory(userID, questionID, now)
+	}
+
+	reviewMemory(memory, quality, now)
+	if err := s.memoryRepo.Save(ctx, memory); err != nil {
+		return nil, fmt.Errorf("failed to save question memory: %w", err)
+	}
+	return memory, nil
+}
+
+func (s *practiceService) RecordResult(userIDs []primitive.ObjectID, result *models.DetailedQuizResult) {
+	qualities := make(map[primitive.ObjectID]int)
+	var questionIDs []primitive.ObjectID
+	for _, qr := range result.QuestionResults {
+		quality, ok := answerQuality(qr)
+		if !ok {
+			continue
+		}
+		if _, seen := qualities[qr.QuestionID]; !seen {
+			questionIDs = append(questionIDs, qr.QuestionID)
+		}
+		qualities[qr.QuestionID] = quality
+	}
+	if len(questionIDs) == 0 {
+		return
+	}
+
+	s.tasks.Go("practice_memories", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		reviewedAt := result.CompletedAt
+		for _, userID := range userIDs {
+			memories, err := s.memoryRepo.GetForQuestions(ctx, userID, questionIDs)
+			if err != nil {
+				slog.Error("Failed to load question memories", "error", err)
+				return
+			}
+
+			for _, questionID := range questionIDs {
+				memory, ok := memories[questionID]
+				if !ok {
+					memory = newQuestionMemory(userID, questionID, reviewedAt)
+				}
+				reviewMemory(memory, qualities[questionID], reviewedAt)
+				if err := s.memoryRepo.Save(ctx, memory); err != nil {
+					slog.Error("Failed to save question memory", "error", err)
+				}
+			}
+		}
+	})
+}
+
+func newQuestionMemory(userID, questionID primitive.ObjectID, now time.Time) *models.QuestionMemory {
+	return &models.QuestionMemory{
+		UserID:     userID,
+		QuestionID: questionID,
+		Schedule:   utils.NewReviewSchedule(now),
+	}
+}
+
+// reviewMemory applies one SM-2 review to a memory; grades below 3 count as a lapse
+func reviewMemory(memory *models.QuestionMemory, quality int, now time.Time) {
+	memory.Schedule = utils.ScheduleReview(memory.Schedule, quality, now)
+	memory.Reviews++
+	if quality < 3 {
+		memory.Lapses++
+	}
+}
+
+// answerQuality grades a quiz answer on the SM-2 scale. Questions never reached and essays still
+// awaiting a grade say nothing about memory, so they are left out.
+func answerQuality(qr models.QuestionResult) (int, bool) {
+	switch {
+	case qr.PendingGrade:
+		return 0, false
+	case qr.IsCorrect:
+		return models.PracticeQualityCorrect, true
+	case qr.IsSkipped:
+		return models.PracticeQualityWrong, true
+	case qr.UserAnswer == nil:
+		return 0, false
+	case qr.PointsEarned > 0:
+		return models.PracticeQualityPartial, true
+	default:
+		return models.PracticeQualityWrong, true
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// accountDeletionGracePeriod is how long a deleted account can still be restored by logging in
+const accountDeletionGracePeriod = 30 * 24 * time.Hour
+
+type PrivacyService interface {
+	ExportUserData(ctx context.Context, userID primitive.ObjectID) (*models.UserDataExport, error)
+	RequestAccountDeletion(ctx context.Context, userID primitive.ObjectID, req *models.DeleteAccountRequest) (*models.AccountDeletionResponse, error)
+	PurgeDeletedAccounts(ctx context.Context) (*models.PurgeDeletedAccountsResponse, error)
+}
+
+type privacyService struct {
+	userRepo         repository.UserRepository
+	userActivityRepo repository.UserActivityRepository
+	sessionRepo      repository.QuizSessionRepository
+	activityLogRepo  repository.ActivityLogRepository
+	bookmarkRepo     repository.BookmarkRepository
+	flashcardRepo    repository.FlashcardRepository
+	memoryRepo       repository.QuestionMemoryRepository
+	notificationRepo repository.NotificationRepository
+	threadRepo       repository.ResultThreadRepository
+	examRepo         repository.ExamRepository
+	reportRepo       repository.QuestionReportRepository
+	proposalRepo     repository.QuestionProposalRepository
+	cache            *utils.Cache
+}
+
+func NewPrivacyService(
+	userRepo repository.UserRepository,
+	userActivityRepo repository.UserActivityRepository,
+	sessionRepo repository.QuizSessionRepository,
+	activityLogRepo repository.ActivityLogRepository,
+	bookmarkRepo repository.BookmarkRepository,
+	flashcardRepo repository.FlashcardRepository,
+	memoryRepo repository.QuestionMemoryRepository,
+	notificationRepo repository.NotificationRepository,
+	threadRepo repository.ResultThreadRepository,
+	examRepo repository.ExamRepository,
+	reportRepo repository.QuestionReportRepository,
+	proposalRepo repository.QuestionProposalRepository,
+	cache *utils.Cache,
+) PrivacyService {
+	return &privacyService{
+		userRepo:         userRepo,
+		userActivityRepo: userActivityRepo,
+		sessionRepo:      sessionRepo,
+		activityLogRepo:  activityLogRepo,
+		bookmarkRepo:     bookmarkRepo,
+		flashcardRepo:    flashcardRepo,
+		memoryRepo:       memoryRepo,
+		notificationRepo: notificationRepo,
+		threadRepo:       threadRepo,
+		examRepo:         examRepo,
+		reportRepo:       reportRepo,
+		proposalRepo:     proposalRepo,
+		cache:            cache,
+	}
+}
+
+func (s *privacyService) ExportUserData(ctx context.Context, userID primitive.ObjectID) (*models.UserDataExport, error) {
+	profile, _, err := s.findAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit 0 returns every result
+	quizResults, _, err := s.userActivityRepo.GetUserQuizResults(ctx, userID, models.QuizResultsFilter{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export quiz results: %w", err)
+	}
+
+	detailedResults, err := s.sessionRepo.GetUserDetailedResults(ctx, userID, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export detailed results: %w", err)
+	}
+
+	sessions, err := s.sessionRepo.GetUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export quiz sessions: %w", err)
+	}
+
+	stats, err := s.userActivityRepo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export user stats: %w", err)
+	}
+
+	achievements, err := s.userActivityRepo.GetUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export achievements: %w", err)
+	}
+
+	bookmarks, err := s.bookmarkRepo.GetAllByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bookmarks: %w", err)
+	}
+
+	decks, err := s.flashcardRepo.ListDecks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export flashcard decks: %w", err)
+	}
+
+	activityLogs, err := s.activityLogRepo.GetByPerformer(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export activity logs: %w", err)
+	}
+
+	return &models.UserDataExport{
+		ExportedAt:      time.Now(),
+		UserID:          userID.Hex(),
+		Profile:         profile,
+		QuizResults:     quizResults,
+		DetailedResults: detailedResults,
+		QuizSessions:    sessions,
+		Stats:           stats,
+		Achievements:    achievements,
+		Bookmarks:       bookmarks,
+		FlashcardDecks:  decks,
+		ActivityLogs:    activityLogs,
+	}, nil
+}
+
+func (s *privacyService) RequestAccountDeletion(ctx context.Context, userID primitive.ObjectID, req *models.DeleteAccountRequest) (*models.AccountDeletionResponse, error) {
+	_, account, err := s.findAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.DeletionScheduledAt != nil {
+		return nil, models.ConflictError("account deletion already scheduled")
+	}
+
+	// OAuth-only accounts have no password to confirm with
+	if account.PasswordHash != "" {
+		valid, err := utils.VerifyPassword(req.Password, account.PasswordHash)
+		if err != nil || !valid {
+			return nil, models.NewCodedError(http.StatusForbidden, models.ErrorCodeInvalidCredentials, "invalid password")
+		}
+	}
+
+	now := time.Now()
+	scheduledAt := now.Add(accountDeletionGracePeriod)
+	if err := s.userRepo.MarkForDeletion(ctx, userID, now, scheduledAt); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	// Invalidate the stored refresh token; logging in again restores the account
+	if err := s.userRepo.SetRefreshToken(ctx, userID, ""); err != nil {
+		slog.ErrorContext(ctx, "Failed to clear refresh token for deleted account", "error", err)
+	}
+
+	return &models.AccountDeletionResponse{
+		Message:             "Account scheduled for deletion. Log in again before the deletion date to cancel.",
+		DeletionScheduledAt: scheduledAt,
+		GracePeriodDays:     int(accountDeletionGracePeriod.Hours() / 24),
+	}, nil
+}
+
+func (s *privacyService) PurgeDeletedAccounts(ctx context.Context) (*models.PurgeDeletedAccountsResponse, error) {
+	userIDs, err := s.userRepo.GetAccountsDueForDeletion(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts due for deletion: %w", err)
+	}
+
+	response := &models.PurgeDeletedAccountsResponse{}
+	defer func() {
+		if response.PurgedAccounts > 0 {
+			s.cache.Invalidate(ctx, utils.CacheUserStats)
+		}
+	}()
+	for _, userID := range userIDs {
+		anonymized, deletedSessions, err := s.purgeAccount(ctx, userID)
+		if err != nil {
+			return response, fmt.Errorf("failed to purge account %s: %w", userID.Hex(), err)
+		}
+
+		response.PurgedAccounts++
+		response.AnonymizedResults += anonymized
+		response.DeletedSessions += deletedSessions
+	}
+
+	return response, nil
+}
+
+// Private helper methods
+
+// purgeAccount removes personal data for a user while keeping their results as anonymous records
+func (s *privacyService) purgeAccount(ctx context.Context, userID primitive.ObjectID) (int64, int64, error) {
+	// A fresh ID per account keeps results from the same person grouped without being traceable
+	anonymousID := primitive.NewObjectID()
+
+	anonymizedResults, err := s.userActivityRepo.AnonymizeUserResults(ctx, userID, anonymousID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to anonymize quiz results: %w", err)
+	}
+
+	anonymizedDetailed, err := s.sessionRepo.AnonymizeUserResults(ctx, userID, anonymousID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deletedSessions, err := s.sessionRepo.DeleteUserSessions(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := s.userActivityRepo.DeleteUserData(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete user stats: %w", err)
+	}
+
+	if err := s.bookmarkRepo.DeleteByUser(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete bookmarks: %w", err)
+	}
+
+	if err := s.flashcardRepo.DeleteByUser(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete flashcards: %w", err)
+	}
+
+	if err := s.memoryRepo.DeleteByUser(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete question memories: %w", err)
+	}
+
+	if err := s.notificationRepo.DeleteByUser(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	if err := s.threadRepo.DeleteByStudent(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete result threads: %w", err)
+	}
+
+	if err := s.examRepo.DeleteParticipantsByUser(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete exam participation: %w", err)
+	}
+
+	if err := s.reportRepo.AnonymizeReporter(ctx, userID, anonymousID); err != nil {
+		return 0, 0, fmt.Errorf("failed to anonymize question reports: %w", err)
+	}
+
+	if err := s.proposalRepo.AnonymizeProposer(ctx, userID, anonymousID); err != nil {
+		return 0, 0, fmt.Errorf("failed to anonymize question proposals: %w", err)
+	}
+
+	if _, err := s.activityLogRepo.AnonymizePerformer(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to anonymize activity logs: %w", err)
+	}
+
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return anonymizedResults + anonymizedDetailed, deletedSessions, nil
+}
+
+// findAccount returns the full account document and its embedded base user from whichever collection holds it
+func (s *privacyService) findAccount(ctx context.Context, userID primitive.ObjectID) (interface{}, *models.User, error) {
+	if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, userID); err == nil {
+		return mahasiswa, &mahasiswa.User, nil
+	}
+
+	if admin, err := s.userRepo.GetAdminByID(ctx, userID); err == nil {
+		return admin, &admin.User, nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, repository.ErrUserNotFound
+	}
+
+	return user, user, nil
+}
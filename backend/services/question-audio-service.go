@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultAudioBatchLimit caps how many questions one batch run generates audio for
+const defaultAudioBatchLimit = 50
+
+type QuestionAudioService interface {
+	GenerateAudio(ctx context.Context, questionID primitive.ObjectID, force bool) (*models.QuestionAudio, error)
+	GenerateBatch(ctx context.Context, req *models.GenerateAudioBatchRequest) (*models.GenerateAudioBatchResponse, error)
+	GetAudioFile(ctx context.Context, questionID primitive.ObjectID) (*models.QuestionAudioFile, error)
+	DeleteAudio(ctx context.Context, questionID primitive.ObjectID) error
+
+	// QueueGeneration regenerates audio in the background after a question is saved, when enabled
+	QueueGeneration(questionID primitive.ObjectID)
+}
+
+type questionAudioService struct {
+	questionRepo      repository.QuestionRepository
+	questionAudioRepo repository.QuestionAudioRepository
+	provider          utils.TTSProvider
+	autoGenerate      bool
+	tasks             *utils.BackgroundTasks
+}
+
+func NewQuestionAudioService(
+	questionRepo repository.QuestionRepository,
+	questionAudioRepo repository.QuestionAudioRepository,
+	provider utils.TTSProvider,
+	config models.TTSConfig,
+	tasks *utils.BackgroundTasks,
+) QuestionAudioService {
+	return &questionAudioService{
+		questionRepo:      questionRepo,
+		questionAudioRepo: questionAudioRepo,
+		provider:          provider,
+		autoGenerate:      config.AutoGenerate,
+		tasks:             tasks,
+	}
+}
+
+// GenerateAudio synthesizes the question's text, skipping the provider call when the
+// existing audio was made from the same text and voice unless force is set
+func (s *questionAudioService) GenerateAudio(ctx context.Context, questionID primitive.ObjectID, force bool) (*models.QuestionAudio, error) {
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	audio, _, err := s.generate(ctx, question, force)
+	return audio, err
+}
+
+func (s *questionAudioService) GenerateBatch(ctx context.Context, req *models.GenerateAudioBatchRequest) (*models.GenerateAudioBatchResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAudioBatchLimit
+	}
+
+	response := &models.GenerateAudioBatchResponse{}
+	filter := bson.M{"is_active": true}
+
+	// Walk active questions until enough audio was generated; up-to-date ones don't count
+	const pageSize = 100
+	for page := 1; response.Generated+response.Failed < limit; page++ {
+		questions, _, err := s.questionRepo.List(ctx, filter, page, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list questions: %w", err)
+		}
+
+		for _, question := range questions {
+			if response.Generated+response.Failed >= limit {
+				break
+			}
+
+			_, generated, err := s.generate(ctx, question, req.Force)
+			switch {
+			case errors.Is(err, utils.ErrTTSDisabled):
+				return nil, err
+			case err != nil:
+				response.Failed++
+				response.Errors = append(response.Errors, models.QuestionAudioError{QuestionID: question.ID, Error: err.Error()})
+			case generated:
+				response.Generated++
+			default:
+				response.Skipped++
+			}
+		}
+
+		if len(questions) < pageSize {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+func (s *questionAudioService) GetAudioFile(ctx context.Context, questionID primitive.ObjectID) (*models.QuestionAudioFile, error) {
+	return s.questionAudioRepo.GetByQuestion(ctx, questionID)
+}
+
+func (s *questionAudioService) DeleteAudio(ctx context.Context, questionID primitive.ObjectID) error {
+	return s.questionAudioRepo.DeleteByQuestion(ctx, questionID)
+}
+
+func (s *questionAudioService) QueueGeneration(questionID primitive.ObjectID) {
+	if !s.autoGenerate || s.provider.Name() == "" {
+		return
+	}
+
+	s.tasks.Go("question_audio", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if _, err := s.GenerateAudio(ctx, questionID, false); err != nil {
+			slog.Error("Failed to generate question audio", "question_id", questionID.Hex(), "error", err)
+		}
+	})
+}
+
+// generate returns the question's audio and whether it had to be (re)generated
+func (s *questionAudioService) generate(ctx context.Context, question *models.Question, force bool) (*models.QuestionAudio, bool, error) {
+	if s.provider.Name() == "" {
+		return nil, false, utils.ErrTTSDisabled
+	}
+
+	text := speechText(question)
+	textHash := s.textHash(text)
+	if !force && question.Audio != nil && question.Audio.TextHash == textHash {
+		return question.Audio, false, nil
+	}
+
+	result, err := s.provider.Synthesize(ctx, text)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to synthesize audio: %w", err)
+	}
+
+	audio := &models.QuestionAudio{
+		// The hash in the query string busts client caches when the audio changes
+		URL:         fmt.Sprintf("/api/v1/questions/%s/audio?v=%s", question.ID.Hex(), textHash[:12]),
+		ContentType: result.ContentType,
+		Provider:    s.provider.Name(),
+		Voice:       s.provider.Voice(),
+		TextHash:    textHash,
+		SizeBytes:   len(result.Data),
+		GeneratedAt: time.Now(),
+	}
+	file := &models.QuestionAudioFile{
+		QuestionID:  question.ID,
+		Data:        result.Data,
+		ContentType: result.ContentType,
+		TextHash:    textHash,
+	}
+
+	if err := s.questionAudioRepo.Save(ctx, file, audio); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("failed to save audio: %w", err)
+	}
+
+	return audio, true, nil
+}
+
+// textHash identifies the spoken text together with the voice reading it
+func (s *questionAudioService) textHash(text string) string {
+	sum := sha256.Sum256([]byte(s.provider.Name() + "\x00" + s.provider.Voice() + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// speechText is what gets read aloud: the question, descriptions of its images and its options
+func speechText(question *models.Question) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(question.Title))
+	if !strings.HasSuffix(b.String(), ".") && !strings.HasSuffix(b.String(), "?") {
+		b.WriteString(".")
+	}
+
+	for _, media := range question.Media {
+		if media.Type == models.MediaImage && media.AltText != "" {
+			fmt.Fprintf(&b, " Image: %s.", strings.TrimSuffix(media.AltText, "."))
+		}
+	}
+
+	// Ordering questions store their options in the answer's order, so they are read alphabetically
+	options := question.Options
+	if question.Type == models.Ordering {
+		options = append([]models.Option(nil), options...)
+		sort.SliceStable(options, func(i, j int) bool {
+			return strings.ToLower(options[i].Text) < strings.ToLower(options[j].Text)
+		})
+	}
+
+	for i, option := range options {
+		fmt.Fprintf(&b, " Option %d: %s.", i+1, strings.TrimSuffix(strings.TrimSpace(option.Text), "."))
+		if option.ImageAlt != "" {
+			fmt.Fprintf(&b, " Image: %s.", strings.TrimSuffix(option.ImageAlt, "."))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionCommentService interface {
+	ListComments(ctx context.Context, questionID primitive.ObjectID, req *models.ListQuestionCommentsRequest) (*models.QuestionCommentsResponse, error)
+	PostComment(ctx context.Context, authorID, questionID primitive.ObjectID, req *models.PostQuestionCommentRequest) (*models.QuestionComment, error)
+	ResolveComment(ctx context.Context, adminID, questionID, commentID primitive.ObjectID, resolved bool) (*models.QuestionComment, error)
+}
+
+type questionCommentService struct {
+	commentRepo      repository.QuestionCommentRepository
+	questionRepo     repository.QuestionRepository
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+}
+
+func NewQuestionCommentService(
+	commentRepo repository.QuestionCommentRepository,
+	questionRepo repository.QuestionRepository,
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+) QuestionCommentService {
+	return &questionCommentService{
+		commentRepo:      commentRepo,
+		questionRepo:     questionRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+func (s *questionCommentService) ListComments(ctx context.Context, questionID primitive.ObjectID, req *models.ListQuestionCommentsRequest) (*models.QuestionCommentsResponse, error) {
+	if _, err := s.questionRepo.GetByID(ctx, questionID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListByQuestion(ctx, questionID, req.Resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	unresolved, err := s.commentRepo.CountUnresolved(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unresolved comments: %w", err)
+	}
+
+	return &models.QuestionCommentsResponse{
+		QuestionID: questionID,
+		Comments:   comments,
+		Unresolved: unresolved,
+	}, nil
+}
+
+// PostComment adds a comment to a question's discussion and notifies the admins it mentions
+func (s *questionCommentService) PostComment(ctx context.Context, authorID, questionID primitive.ObjectID, req *models.PostQuestionCommentRequest) (*models.QuestionComment, error) {
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, models.BadRequestError("comment cannot be empty")
+	}
+
+	mentions, err := s.resolveMentions(ctx, req.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.QuestionComment{
+		QuestionID: questionID,
+		AuthorID:   authorID,
+		AuthorName: s.adminName(ctx, authorID),
+		Body:       body,
+		Mentions:   mentions,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to post comment: %w", err)
+	}
+
+	for _, mention := range mentions {
+		if mention.UserID != authorID {
+			s.notifyMention(ctx, mention.UserID, question, comment)
+		}
+	}
+
+	return comment, nil
+}
+
+func (s *questionCommentService) ResolveComment(ctx context.Context, adminID, questionID, commentID primitive.ObjectID, resolved bool) (*models.QuestionComment, error) {
+	comment, err := s.commentRepo.SetResolved(ctx, questionID, commentID, resolved, adminID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	return comment, nil
+}
+
+// resolveMentions looks up the mentioned admins, once each, in the order given
+func (s *questionCommentService) resolveMentions(ctx context.Context, ids []string) ([]models.CommentMention, error) {
+	var mentions []models.CommentMention
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		userID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, models.BadRequestError(fmt.Sprintf("invalid mention ID: %s", id))
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		admin, err := s.userRepo.GetAdminByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAdminNotFound) {
+				return nil, models.BadRequestError(fmt.Sprintf("mentioned admin %s not found", id))
+			}
+			return nil, fmt.Errorf("failed to look up mentioned admin: %w", err)
+		}
+		mentions = append(mentions, models.CommentMention{UserID: userID, Name: admin.FullName})
+	}
+	return mentions, nil
+}
+
+// notifyMention records an in-platform notification for a mentioned admin; failures are logged only
+func (s *questionCommentService) notifyMention(ctx context.Context, recipientID primitive.ObjectID, question *models.Question, comment *models.QuestionComment) {
+	notification := &models.Notification{
+		UserID:     recipientID,
+		Type:       models.NotificationCommentMention,
+		Title:      "You were mentioned on a question",
+		Message:    fmt.Sprintf("%s mentioned you on \"%s\": %s", comment.AuthorName, question.Title, commentPreview(comment.Body)),
+		EntityType: "question",
+		EntityID:   question.ID.Hex(),
+	}
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to create comment mention notification", "error", err)
+	}
+}
+
+func (s *questionCommentService) adminName(ctx context.Context, adminID primitive.ObjectID) string {
+	if admin, err := s.userRepo.GetAdminByID(ctx, adminID); err == nil {
+		return admin.FullName
+	}
+	return "Unknown User"
+}
+
+// commentPreview shortens a comment for a notification
+func commentPreview(body string) string {
+	const maxRunes = 140
+	runes := []rune(body)
+	if len(runes) <= maxRunes {
+		return body
+	}
+	return string(runes[:maxRunes]) + "…"
+}
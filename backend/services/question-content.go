@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+
+	"backend/models"
+	"backend/utils"
+)
+
+// renderQuestionContent fills in the HTML rendering of a question's markdown title and options for
+// a response. It is not stored with the question, so it always follows the current text.
+func renderQuestionContent(q *models.Question) {
+	q.TitleHTML = utils.RenderMarkdown(q.Title)
+	for i := range q.Options {
+		q.Options[i].TextHTML = utils.RenderMarkdownInline(q.Options[i].Text)
+	}
+}
+
+func renderQuestionsContent(questions []*models.Question) {
+	for _, q := range questions {
+		renderQuestionContent(q)
+	}
+}
+
+// validateQuestionContent checks the LaTeX in a title and options is safe to typeset
+func validateQuestionContent(title string, options []models.CreateOption) error {
+	if err := utils.CheckMarkdown(title); err != nil {
+		return fmt.Errorf("title: %w", err)
+	}
+	for i, option := range options {
+		if err := utils.CheckMarkdown(option.Text); err != nil {
+			return fmt.Errorf("option %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// creditedPerson is what question credits need to know about someone
+type creditedPerson struct {
+	fullName    string
+	email       string
+	hideCredits bool
+}
+
+// lookupPerson finds a user in any of the user collections, caching the result in people
+func lookupPerson(ctx context.Context, userRepo repository.UserRepository, people map[primitive.ObjectID]creditedPerson, userID primitive.ObjectID) creditedPerson {
+	if person, ok := people[userID]; ok {
+		return person
+	}
+
+	person := creditedPerson{fullName: "Unknown User"}
+	if admin, err := userRepo.GetAdminByID(ctx, userID); err == nil {
+		person = creditedPerson{admin.FullName, admin.Email, admin.HideQuestionCredits}
+	} else if mahasiswa, err := userRepo.GetMahasiswaByID(ctx, userID); err == nil {
+		person = creditedPerson{mahasiswa.FullName, mahasiswa.Email, mahasiswa.HideQuestionCredits}
+	} else if user, err := userRepo.GetByID(ctx, userID); err == nil {
+		person = creditedPerson{user.FullName, user.Email, user.HideQuestionCredits}
+	}
+	people[userID] = person
+	return person
+}
+
+// attachCredits fills in who wrote and reviewed each question. People hiding their credits are
+// left anonymous, and their IDs are cleared from the question's own fields too.
+func (s *questionService) attachCredits(ctx context.Context, questions []*models.Question) {
+	people := make(map[primitive.ObjectID]creditedPerson)
+	contributor := func(userID primitive.ObjectID) (models.QuestionContributor, bool) {
+		person := lookupPerson(ctx, s.userRepo, people, userID)
+		if person.hideCredits {
+			return models.QuestionContributor{Name: "Anonymous", Anonymous: true}, true
+		}
+		id := userID
+		return models.QuestionContributor{UserID: &id, Name: person.fullName}, false
+	}
+
+	for _, q := range questions {
+		credits := &models.QuestionCredits{Reviewers: []models.QuestionContributor{}}
+
+		authorID := q.CreatedBy
+		if q.ContributedBy != nil {
+			authorID = *q.ContributedBy
+		}
+		author, hidden := contributor(authorID)
+		credits.Author = author
+		if hidden {
+			if q.ContributedBy != nil {
+				q.ContributedBy = nil
+			} else {
+				q.CreatedBy = primitive.NilObjectID
+			}
+		}
+
+		for _, review := range q.Reviews {
+			reviewer, _ := contributor(review.ReviewerID)
+			reviewedAt := review.ReviewedAt
+			reviewer.ReviewedAt = &reviewedAt
+			credits.Reviewers = append(credits.Reviewers, reviewer)
+		}
+		q.Credits = credits
+	}
+}
+
+// creditFilter narrows a question list to an author's or reviewer's questions
+func creditFilter(filter bson.M, req *models.ListQuestionsRequest) error {
+	var clauses bson.A
+	if req.AuthorID != "" {
+		authorID, err := primitive.ObjectIDFromHex(req.AuthorID)
+		if err != nil {
+			return models.BadRequestError("invalid author ID")
+		}
+		clauses = append(clauses, bson.M{"$or": bson.A{
+			bson.M{"contributed_by": authorID},
+			bson.M{"contributed_by": nil, "created_by": authorID},
+		}})
+	}
+	if req.ReviewerID != "" {
+		reviewerID, err := primitive.ObjectIDFromHex(req.ReviewerID)
+		if err != nil {
+			return models.BadRequestError("invalid reviewer ID")
+		}
+		clauses = append(clauses, bson.M{"reviews.reviewer_id": reviewerID})
+	}
+	if len(clauses) > 0 {
+		filter["$and"] = clauses
+	}
+	return nil
+}
+
+// RecordReview credits an admin with reviewing a question. Authors cannot review their own questions.
+func (s *questionService) RecordReview(ctx context.Context, questionID, reviewerID primitive.ObjectID, req *models.RecordQuestionReviewRequest) (*models.Question, error) {
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorID := question.CreatedBy
+	if question.ContributedBy != nil {
+		authorID = *question.ContributedBy
+	}
+	if authorID == reviewerID {
+		return nil, models.ConflictError("authors cannot review their own questions")
+	}
+
+	review := models.QuestionReview{
+		ReviewerID: reviewerID,
+		Note:       strings.TrimSpace(req.Note),
+		ReviewedAt: time.Now(),
+	}
+	if err := s.questionRepo.AddReview(ctx, questionID, review); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record review: %w", err)
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+// GetAuthoringReport counts the questions each person wrote and reviewed, so departmental
+// workload claims can be checked against the bank
+func (s *questionService) GetAuthoringReport(ctx context.Context, req *models.AuthoringReportRequest) (*models.AuthoringReportResponse, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	authored, err := s.questionRepo.CountAuthored(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count authored questions: %w", err)
+	}
+	reviewed, err := s.questionRepo.CountReviewed(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reviewed questions: %w", err)
+	}
+
+	report := &models.AuthoringReportResponse{
+		DateFrom:     req.DateFrom,
+		DateTo:       req.DateTo,
+		Contributors: []models.AuthorContribution{},
+	}
+	rows := make(map[primitive.ObjectID]*models.AuthorContribution)
+	row := func(userID primitive.ObjectID) *models.AuthorContribution {
+		if r, ok := rows[userID]; ok {
+			return r
+		}
+		r := &models.AuthorContribution{UserID: userID}
+		rows[userID] = r
+		return r
+	}
+	for _, count := range authored {
+		r := row(count.UserID)
+		r.Authored, r.ActiveAuthored = count.Count, count.Active
+		report.TotalAuthored += count.Count
+	}
+	for _, count := range reviewed {
+		row(count.UserID).Reviewed = count.Count
+		report.TotalReviewed += count.Count
+	}
+
+	people := make(map[primitive.ObjectID]creditedPerson)
+	for userID, r := range rows {
+		person := lookupPerson(ctx, s.userRepo, people, userID)
+		r.FullName, r.Email, r.HidesCredits = person.fullName, person.email, person.hideCredits
+		report.Contributors = append(report.Contributors, *r)
+	}
+	sort.Slice(report.Contributors, func(i, j int) bool {
+		a, b := report.Contributors[i], report.Contributors[j]
+		if a.Authored+a.Reviewed != b.Authored+b.Reviewed {
+			return a.Authored+a.Reviewed > b.Authored+b.Reviewed
+		}
+		return a.FullName < b.FullName
+	})
+
+	return report, nil
+}
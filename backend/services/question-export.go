@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+)
+
+// questionExportColumns are the import columns, so an exported sheet can be imported elsewhere,
+// with the question's identity and state around them
+var questionExportColumns = []string{
+	"id", "title", "type", "difficulty", "points", "is_active",
+	"options", "correct_answers", "sample_answer", "explanation", "blanks",
+	"numeric_answer", "tolerance", "relative_tolerance", "unit", "ordering_scoring", "tags",
+	"version", "created_at",
+}
+
+// ExportQuestions writes every question matching the list filters to w as CSV, XLSX or JSON,
+// reading them one at a time so large banks stream out. It returns how many were written. Filter
+// errors are returned before anything is written.
+func (s *questionService) ExportQuestions(ctx context.Context, req *models.ExportQuestionsRequest, w io.Writer) (int, error) {
+	filter, err := questionListFilter(&req.ListQuestionsRequest)
+	if err != nil {
+		return 0, err
+	}
+	search := strings.TrimSpace(req.Search)
+
+	var write func(*models.Question) error
+	var finish func() error
+	if req.Format == "json" {
+		write, finish = questionJSONExporter(w)
+	} else {
+		sheet, err := utils.NewSpreadsheetWriter(req.Format, w)
+		if err != nil {
+			return 0, err
+		}
+		if err := sheet.WriteRow(questionExportColumns); err != nil {
+			return 0, fmt.Errorf("failed to write export: %w", err)
+		}
+		write = func(q *models.Question) error {
+			return sheet.WriteRow(questionExportRow(q))
+		}
+		finish = sheet.Close
+	}
+
+	count := 0
+	each := func(q *models.Question) error {
+		if req.ExcludeAnswers {
+			redactAnswers(q)
+		}
+		count++
+		return write(q)
+	}
+	if search == "" {
+		err = s.questionRepo.ForEach(ctx, filter, each)
+	} else {
+		// A missing text index fails the query before any question is read
+		err = s.questionRepo.ForEach(ctx, withTextSearch(filter, search), each)
+		if errors.Is(err, repository.ErrQuestionTextIndexNotFound) {
+			err = s.questionRepo.ForEach(ctx, withPatternSearch(filter, search), each)
+		}
+	}
+	if err != nil {
+		return count, fmt.Errorf("failed to export questions: %w", err)
+	}
+	if err := finish(); err != nil {
+		return count, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return count, nil
+}
+
+// questionJSONExporter writes questions as the elements of a JSON array
+func questionJSONExporter(w io.Writer) (func(*models.Question) error, func() error) {
+	separator := "[\n"
+	write := func(q *models.Question) error {
+		data, err := json.Marshal(q)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		separator = ",\n"
+		_, err = w.Write(data)
+		return err
+	}
+	finish := func() error {
+		if separator == "[\n" {
+			_, err := io.WriteString(w, "[]\n")
+			return err
+		}
+		_, err := io.WriteString(w, "\n]\n")
+		return err
+	}
+	return write, finish
+}
+
+// questionExportRow renders a question in the order of questionExportColumns
+func questionExportRow(q *models.Question) []string {
+	options := make([]string, len(q.Options))
+	letters := make(map[string]string, len(q.Options))
+	for i, option := range q.Options {
+		options[i] = option.Text
+		letters[option.ID] = string(rune('A' + i))
+	}
+
+	// Ordering answers are the option order itself
+	var correctAnswers []string
+	if q.Type != models.Ordering {
+		for _, id := range q.CorrectAnswers {
+			if letter, ok := letters[id]; ok {
+				correctAnswers = append(correctAnswers, letter)
+			}
+		}
+	}
+
+	blanks := make([]string, len(q.Blanks))
+	for i, blank := range q.Blanks {
+		blanks[i] = blank.ID + "=" + strings.Join(blank.AcceptedAnswers, ";")
+	}
+
+	var value, tolerance, relativeTolerance, unit string
+	if q.NumericAnswer != nil {
+		value = formatExportNumber(q.NumericAnswer.Value)
+		if q.NumericAnswer.AbsoluteTolerance > 0 {
+			tolerance = formatExportNumber(q.NumericAnswer.AbsoluteTolerance)
+		}
+		if q.NumericAnswer.RelativeTolerance > 0 {
+			relativeTolerance = formatExportNumber(q.NumericAnswer.RelativeTolerance)
+		}
+		unit = q.NumericAnswer.Unit
+	}
+
+	return []string{
+		q.ID.Hex(),
+		q.Title,
+		string(q.Type),
+		string(q.Difficulty),
+		strconv.Itoa(q.Points),
+		strconv.FormatBool(q.IsActive),
+		strings.Join(options, importListSeparator),
+		strings.Join(correctAnswers, importListSeparator),
+		q.SampleAnswer,
+		q.Explanation,
+		strings.Join(blanks, importListSeparator),
+		value,
+		tolerance,
+		relativeTolerance,
+		unit,
+		string(q.OrderingScoring),
+		strings.Join(q.Tags, importListSeparator),
+		strconv.Itoa(questionVersion(q)),
+		q.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// redactAnswers strips everything that gives a question's answer away. Ordering options are
+// shuffled, since their stored order is the answer.
+func redactAnswers(q *models.Question) {
+	q.CorrectAnswers = nil
+	q.SampleAnswer = ""
+	q.Explanation = ""
+	q.Blanks = nil
+	q.NumericAnswer = nil
+	if q.Type == models.Ordering {
+		q.Options = displayOptions(q)
+	}
+}
+
+func formatExportNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
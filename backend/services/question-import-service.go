@@ -0,0 +1,372 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// questionColumnAliases maps accepted header names to question import fields
+var questionColumnAliases = map[string]string{
+	"title":              "title",
+	"question":           "title",
+	"pertanyaan":         "title",
+	"type":               "type",
+	"difficulty":         "difficulty",
+	"points":             "points",
+	"options":            "options",
+	"correct_answers":    "correct_answers",
+	"correct_answer":     "correct_answers",
+	"answer":             "correct_answers",
+	"sample_answer":      "sample_answer",
+	"explanation":        "explanation",
+	"blanks":             "blanks",
+	"numeric_answer":     "numeric_answer",
+	"tolerance":          "tolerance",
+	"relative_tolerance": "relative_tolerance",
+	"unit":               "unit",
+	"ordering_scoring":   "ordering_scoring",
+	"tags":               "tags",
+}
+
+var requiredQuestionColumns = []string{"title", "type", "difficulty", "points"}
+
+// importListSeparator separates the values of list columns such as options and tags
+const importListSeparator = "|"
+
+type QuestionImportService interface {
+	ImportQuestions(ctx context.Context, filename string, file io.Reader, req *models.ImportQuestionsRequest, importedBy primitive.ObjectID) (*models.ImportQuestionsResponse, error)
+}
+
+type questionImportService struct {
+	questionService QuestionService
+	questionRepo    repository.QuestionRepository
+}
+
+func NewQuestionImportService(questionService QuestionService, questionRepo repository.QuestionRepository) QuestionImportService {
+	return &questionImportService{
+		questionService: questionService,
+		questionRepo:    questionRepo,
+	}
+}
+
+// questionImportRow holds one question read from the import file, with any errors met reading it
+type questionImportRow struct {
+	number int
+	req    models.CreateQuestionRequest
+	errors []string
+}
+
+// ImportQuestions reads questions from a CSV, XLSX or JSON file and creates those that pass
+// validation and whose title is not already in the bank or earlier in the file. A dry run stops
+// short of creating them, so the report shows what an import would do.
+func (s *questionImportService) ImportQuestions(ctx context.Context, filename string, file io.Reader, req *models.ImportQuestionsRequest, importedBy primitive.ObjectID) (*models.ImportQuestionsResponse, error) {
+	var rows []questionImportRow
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import file: %w", err)
+		}
+		if rows, err = parseQuestionJSON(data); err != nil {
+			return nil, err
+		}
+	} else {
+		cells, err := utils.ReadSpreadsheet(filename, file)
+		if err != nil {
+			if errors.Is(err, utils.ErrUnsupportedSpreadsheet) {
+				return nil, models.BadRequestError("unsupported file type, must be .csv, .xlsx or .json")
+			}
+			return nil, err
+		}
+		if rows, err = parseQuestionSpreadsheet(cells); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, models.BadRequestError("import file has no data rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, models.BadRequestError(fmt.Sprintf("import file has too many rows, maximum is %d", maxImportRows))
+	}
+
+	existing, err := s.existingTitles(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ImportQuestionsResponse{
+		DryRun:    req.DryRun,
+		TotalRows: len(rows),
+		Results:   make([]models.QuestionImportRowResult, 0, len(rows)),
+	}
+
+	// Track titles seen earlier in the file to catch duplicates within the upload
+	seenTitles := make(map[string]int)
+
+	for _, row := range rows {
+		result := models.QuestionImportRowResult{
+			Row:   row.number,
+			Title: strings.TrimSpace(row.req.Title),
+			Type:  row.req.Type,
+		}
+
+		if validationErrors := s.validateRow(ctx, row); len(validationErrors) > 0 {
+			result.Status = models.ImportRowInvalid
+			result.Errors = validationErrors
+			response.Invalid++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		key := questionTitleKey(row.req.Title)
+		if duplicateError := duplicateTitleError(key, seenTitles, existing); duplicateError != "" {
+			result.Status = models.ImportRowDuplicate
+			result.Errors = []string{duplicateError}
+			response.Duplicates++
+			response.Results = append(response.Results, result)
+			continue
+		}
+		seenTitles[key] = row.number
+
+		if req.DryRun {
+			result.Status = models.ImportRowValid
+			response.Valid++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		question, err := s.questionService.CreateQuestion(ctx, &row.req, importedBy)
+		if err != nil {
+			result.Status = models.ImportRowFailed
+			result.Errors = []string{err.Error()}
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		result.Status = models.ImportRowCreated
+		result.QuestionID = &question.ID
+		response.Created++
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// Private helper methods
+
+func (s *questionImportService) validateRow(ctx context.Context, row questionImportRow) []string {
+	if len(row.errors) > 0 {
+		return row.errors
+	}
+
+	var validationErrors []string
+	switch row.req.Difficulty {
+	case models.Easy, models.Medium, models.Hard:
+	default:
+		validationErrors = append(validationErrors, "difficulty must be easy, medium or hard")
+	}
+	if err := s.questionService.ValidateQuestionRequest(ctx, &row.req); err != nil {
+		validationErrors = append(validationErrors, err.Error())
+	}
+	return validationErrors
+}
+
+// existingTitles looks up which titles in the file are already in the bank, keyed by
+// questionTitleKey; the value tells whether the question holding the title is archived
+func (s *questionImportService) existingTitles(ctx context.Context, rows []questionImportRow) (map[string]bool, error) {
+	titles := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if title := strings.TrimSpace(row.req.Title); title != "" {
+			titles = append(titles, title)
+		}
+	}
+
+	questions, err := s.questionRepo.GetByTitles(ctx, titles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing titles: %w", err)
+	}
+
+	existing := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		// A title held by an active question is reported as such even if an archived one shares it
+		key := questionTitleKey(q.Title)
+		if archived, ok := existing[key]; !ok || archived {
+			existing[key] = q.Archived
+		}
+	}
+	return existing, nil
+}
+
+func duplicateTitleError(key string, seenTitles map[string]int, existing map[string]bool) string {
+	if previous, ok := seenTitles[key]; ok {
+		return fmt.Sprintf("title already used in row %d", previous)
+	}
+	archived, ok := existing[key]
+	switch {
+	case !ok:
+		return ""
+	case archived:
+		return "an archived question with this title already exists"
+	default:
+		return "question with this title already exists"
+	}
+}
+
+// questionTitleKey compares titles ignoring case and runs of whitespace
+func questionTitleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// parseQuestionJSON reads an array of question objects. An object that does not decode is
+// reported on its own row instead of failing the file.
+func parseQuestionJSON(data []byte) ([]questionImportRow, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\uFEFF")), &items); err != nil {
+		return nil, models.BadRequestError("invalid JSON file, expected an array of questions")
+	}
+
+	rows := make([]questionImportRow, 0, len(items))
+	for i, item := range items {
+		row := questionImportRow{number: i + 1}
+		if err := json.Unmarshal(item, &row.req); err != nil {
+			row.errors = []string{fmt.Sprintf("invalid question object: %v", err)}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseQuestionSpreadsheet reads the data rows of a CSV or XLSX file, skipping blank lines
+func parseQuestionSpreadsheet(cells [][]string) ([]questionImportRow, error) {
+	if len(cells) < 2 {
+		return nil, models.BadRequestError("import file has no data rows")
+	}
+
+	columns, err := mapImportColumns(cells[0], questionColumnAliases, requiredQuestionColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []questionImportRow
+	for i, values := range cells[1:] {
+		if strings.TrimSpace(strings.Join(values, "")) == "" {
+			continue
+		}
+		rows = append(rows, parseQuestionRow(i+2, values, columns))
+	}
+	return rows, nil
+}
+
+func parseQuestionRow(number int, values []string, columns map[string]int) questionImportRow {
+	get := func(field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(values) {
+			return ""
+		}
+		return strings.TrimSpace(values[index])
+	}
+
+	row := questionImportRow{number: number}
+	req := &row.req
+	req.Title = get("title")
+	req.Type = models.QuestionType(strings.ReplaceAll(strings.ToLower(get("type")), " ", "_"))
+	req.Difficulty = models.DifficultyLevel(strings.ToLower(get("difficulty")))
+	req.SampleAnswer = get("sample_answer")
+	req.Explanation = get("explanation")
+	req.OrderingScoring = models.OrderingScoring(strings.ToLower(get("ordering_scoring")))
+	req.Tags = splitImportList(get("tags"))
+
+	if points, err := strconv.Atoi(get("points")); err == nil {
+		req.Points = points
+	} else {
+		row.errors = append(row.errors, "points must be a whole number")
+	}
+
+	for _, text := range splitImportList(get("options")) {
+		req.Options = append(req.Options, models.CreateOption{Text: text})
+	}
+
+	if req.Type == models.SingleChoice || req.Type == models.MultipleChoice {
+		for _, answer := range splitImportList(get("correct_answers")) {
+			index, ok := optionIndex(answer, len(req.Options))
+			if !ok {
+				row.errors = append(row.errors, fmt.Sprintf("correct answer %q is not an option letter or number", answer))
+				continue
+			}
+			req.CorrectAnswers = append(req.CorrectAnswers, strconv.Itoa(index))
+		}
+	}
+
+	for _, blank := range splitImportList(get("blanks")) {
+		id, answers, found := strings.Cut(blank, "=")
+		if !found {
+			row.errors = append(row.errors, fmt.Sprintf("blank %q must be written as id=answer;answer", blank))
+			continue
+		}
+		req.Blanks = append(req.Blanks, models.Blank{
+			ID:              strings.TrimSpace(id),
+			AcceptedAnswers: splitNonEmpty(answers, ";"),
+		})
+	}
+
+	if value := get("numeric_answer"); value != "" {
+		number, ok := models.ParseNumber(value)
+		if !ok {
+			row.errors = append(row.errors, "numeric_answer must be a number")
+		}
+		req.NumericAnswer = &models.NumericAnswer{Value: number, Unit: get("unit")}
+		if tolerance := get("tolerance"); tolerance != "" {
+			if req.NumericAnswer.AbsoluteTolerance, ok = models.ParseNumber(tolerance); !ok || req.NumericAnswer.AbsoluteTolerance < 0 {
+				row.errors = append(row.errors, "tolerance must be a number of at least 0")
+			}
+		}
+		if tolerance := get("relative_tolerance"); tolerance != "" {
+			relative, ok := models.ParseNumber(tolerance)
+			if !ok || relative < 0 || relative > 1 {
+				row.errors = append(row.errors, "relative_tolerance must be a fraction between 0 and 1")
+			}
+			req.NumericAnswer.RelativeTolerance = relative
+		}
+	}
+
+	return row
+}
+
+// optionIndex resolves an option letter (A) or 1-based number to a 0-based option index
+func optionIndex(answer string, optionCount int) (int, bool) {
+	index := -1
+	if n, err := strconv.Atoi(answer); err == nil {
+		index = n - 1
+	} else if len(answer) == 1 {
+		index = int(strings.ToUpper(answer)[0]) - 'A'
+	}
+	return index, index >= 0 && index < optionCount
+}
+
+func splitImportList(value string) []string {
+	return splitNonEmpty(value, importListSeparator)
+}
+
+func splitNonEmpty(value, separator string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, separator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
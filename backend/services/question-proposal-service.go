@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionProposalService interface {
+	// Student side
+	ProposeQuestion(ctx context.Context, userID primitive.ObjectID, email string, req *models.ProposeQuestionRequest) (*models.QuestionProposal, error)
+	ListMyProposals(ctx context.Context, userID primitive.ObjectID, req *models.ListQuestionProposalsRequest) (*models.ListQuestionProposalsResponse, error)
+
+	// Admin review queue
+	ListProposals(ctx context.Context, req *models.ListQuestionProposalsRequest) (*models.ListQuestionProposalsResponse, error)
+	GetProposal(ctx context.Context, proposalID primitive.ObjectID) (*models.QuestionProposal, error)
+	ReviewProposal(ctx context.Context, reviewerID, proposalID primitive.ObjectID, req *models.ReviewQuestionProposalRequest) (*models.QuestionProposal, error)
+}
+
+type questionProposalService struct {
+	proposalRepo     repository.QuestionProposalRepository
+	questionService  QuestionService
+	userActivityRepo repository.UserActivityRepository
+	notificationRepo repository.NotificationRepository
+}
+
+func NewQuestionProposalService(
+	proposalRepo repository.QuestionProposalRepository,
+	questionService QuestionService,
+	userActivityRepo repository.UserActivityRepository,
+	notificationRepo repository.NotificationRepository,
+) QuestionProposalService {
+	return &questionProposalService{
+		proposalRepo:     proposalRepo,
+		questionService:  questionService,
+		userActivityRepo: userActivityRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ProposeQuestion queues a student's question for review. It is validated like an admin-created
+// question up front, so students fix mistakes before a reviewer sees them.
+func (s *questionProposalService) ProposeQuestion(ctx context.Context, userID primitive.ObjectID, email string, req *models.ProposeQuestionRequest) (*models.QuestionProposal, error) {
+	question := req.CreateQuestionRequest
+	if err := s.questionService.ValidateQuestionData(&question); err != nil {
+		return nil, err
+	}
+
+	proposal := &models.QuestionProposal{
+		ProposedBy:    userID,
+		ProposerEmail: email,
+		Question:      question,
+		Rationale:     strings.TrimSpace(req.Rationale),
+	}
+	if err := s.proposalRepo.Create(ctx, proposal); err != nil {
+		return nil, fmt.Errorf("failed to save question proposal: %w", err)
+	}
+
+	return proposal, nil
+}
+
+func (s *questionProposalService) ListMyProposals(ctx context.Context, userID primitive.ObjectID, req *models.ListQuestionProposalsRequest) (*models.ListQuestionProposalsResponse, error) {
+	return s.list(ctx, req, &userID)
+}
+
+func (s *questionProposalService) ListProposals(ctx context.Context, req *models.ListQuestionProposalsRequest) (*models.ListQuestionProposalsResponse, error) {
+	return s.list(ctx, req, nil)
+}
+
+func (s *questionProposalService) list(ctx context.Context, req *models.ListQuestionProposalsRequest, proposedBy *primitive.ObjectID) (*models.ListQuestionProposalsResponse, error) {
+	proposals, total, err := s.proposalRepo.List(ctx, req, proposedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question proposals: %w", err)
+	}
+	if proposals == nil {
+		proposals = []models.QuestionProposal{}
+	}
+
+	pendingCount, err := s.proposalRepo.CountPending(ctx, proposedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending question proposals: %w", err)
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListQuestionProposalsResponse{
+		Proposals:    proposals,
+		Total:        total,
+		PendingCount: pendingCount,
+		Page:         req.Page,
+		Limit:        req.Limit,
+		TotalPages:   totalPages,
+	}, nil
+}
+
+func (s *questionProposalService) GetProposal(ctx context.Context, proposalID primitive.ObjectID) (*models.QuestionProposal, error) {
+	return s.proposalRepo.GetByID(ctx, proposalID)
+}
+
+// ReviewProposal accepts or rejects a pending proposal. Accepting adds the question to the bank
+// credited to the student and awards them contribution XP.
+func (s *questionProposalService) ReviewProposal(ctx context.Context, reviewerID, proposalID primitive.ObjectID, req *models.ReviewQuestionProposalRequest) (*models.QuestionProposal, error) {
+	proposal, err := s.proposalRepo.GetByID(ctx, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claiming first keeps two reviewers from adding the same question twice
+	note := strings.TrimSpace(req.Note)
+	if err := s.proposalRepo.ClaimForReview(ctx, proposalID, reviewerID, req.Status, note); err != nil {
+		return nil, err
+	}
+
+	if req.Status == models.ProposalStatusAccepted {
+		if err := s.accept(ctx, reviewerID, proposal, req); err != nil {
+			if releaseErr := s.proposalRepo.ReleaseReview(ctx, proposalID); releaseErr != nil {
+				slog.ErrorContext(ctx, "Failed to return question proposal to the queue", "proposal_id", proposalID.Hex(), "error", releaseErr)
+			}
+			return nil, err
+		}
+	}
+
+	s.notifyProposer(ctx, proposal, req.Status, note)
+
+	return s.proposalRepo.GetByID(ctx, proposalID)
+}
+
+// accept adds the proposed question to the bank with the reviewer's adjustments and credits the student
+func (s *questionProposalService) accept(ctx context.Context, reviewerID primitive.ObjectID, proposal *models.QuestionProposal, req *models.ReviewQuestionProposalRequest) error {
+	questionReq := proposal.Question
+	if req.Difficulty != nil {
+		questionReq.Difficulty = *req.Difficulty
+	}
+	if req.Points != nil {
+		questionReq.Points = *req.Points
+	}
+	questionReq.ContributedBy = &proposal.ProposedBy
+
+	question, err := s.questionService.CreateQuestion(ctx, &questionReq, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.proposalRepo.SetAccepted(ctx, proposal.ID, question.ID, models.ContributionXP); err != nil {
+		return fmt.Errorf("failed to record accepted question: %w", err)
+	}
+
+	if err := s.userActivityRepo.AwardContributionXP(ctx, proposal.ProposedBy, models.ContributionXP); err != nil {
+		slog.ErrorContext(ctx, "Failed to award contribution XP", "user_id", proposal.ProposedBy.Hex(), "error", err)
+	}
+
+	// Accepting a proposal counts as reviewing the student's question
+	if _, err := s.questionService.RecordReview(ctx, question.ID, reviewerID, &models.RecordQuestionReviewRequest{Note: req.Note}); err != nil {
+		slog.ErrorContext(ctx, "Failed to record question review", "question_id", question.ID.Hex(), "error", err)
+	}
+
+	return nil
+}
+
+// notifyProposer tells the student how their proposal was reviewed. Failures are logged only.
+func (s *questionProposalService) notifyProposer(ctx context.Context, proposal *models.QuestionProposal, status models.QuestionProposalStatus, note string) {
+	notification := &models.Notification{
+		UserID:     proposal.ProposedBy,
+		Type:       models.NotificationProposalReview,
+		Title:      "Your question was not accepted",
+		Message:    proposal.Question.Title,
+		EntityType: "question_proposal",
+		EntityID:   proposal.ID.Hex(),
+	}
+	if status == models.ProposalStatusAccepted {
+		notification.Title = fmt.Sprintf("Your question was added to the bank (+%d XP)", models.ContributionXP)
+	}
+	if note != "" {
+		notification.Message = note
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to create proposal review notification", "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuestionReportService interface {
+	// Student side
+	FlagQuestion(ctx context.Context, userID primitive.ObjectID, isGuest bool, sessionToken string, questionIndex int, req *models.FlagQuestionRequest) (*models.FlagQuestionResponse, error)
+
+	// Admin review queue
+	ListReports(ctx context.Context, req *models.ListQuestionReportsRequest) (*models.ListQuestionReportsResponse, error)
+	GetReportContext(ctx context.Context, reportID primitive.ObjectID) (*models.QuestionReportContextResponse, error)
+	ReviewReport(ctx context.Context, reviewerID, reportID primitive.ObjectID, req *models.ReviewQuestionReportRequest) (*models.QuestionReport, error)
+}
+
+type questionReportService struct {
+	reportRepo   repository.QuestionReportRepository
+	sessionRepo  repository.QuizSessionRepository
+	questionRepo repository.QuestionRepository
+}
+
+func NewQuestionReportService(
+	reportRepo repository.QuestionReportRepository,
+	sessionRepo repository.QuizSessionRepository,
+	questionRepo repository.QuestionRepository,
+) QuestionReportService {
+	return &questionReportService{
+		reportRepo:   reportRepo,
+		sessionRepo:  sessionRepo,
+		questionRepo: questionRepo,
+	}
+}
+
+// FlagQuestion reports the question at an index of the caller's session. Reports are accepted
+// after the session ends too, so students can flag questions they noticed while reviewing.
+func (s *questionReportService) FlagQuestion(ctx context.Context, userID primitive.ObjectID, isGuest bool, sessionToken string, questionIndex int, req *models.FlagQuestionRequest) (*models.FlagQuestionResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsTakenBy(userID) {
+		return nil, repository.ErrSessionNotFound
+	}
+
+	if questionIndex < 0 || questionIndex >= len(session.Questions) {
+		return nil, models.BadRequestError("invalid question index")
+	}
+	question := session.Questions[questionIndex]
+
+	report := &models.QuestionReport{
+		QuestionID:    question.QuestionID,
+		ReporterID:    userID,
+		IsGuest:       isGuest,
+		Reason:        req.Reason,
+		Comment:       strings.TrimSpace(req.Comment),
+		QuestionTitle: question.Title,
+		QuestionType:  question.Type,
+		Difficulty:    question.Difficulty,
+		SessionID:     session.ID,
+		QuizType:      session.QuizType,
+		QuestionIndex: questionIndex,
+		UserAnswer:    question.UserAnswer,
+		ExamSittingID: session.ExamSittingID,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return &models.FlagQuestionResponse{
+		ReportID: report.ID,
+		Message:  "Thanks, the question has been reported for review",
+	}, nil
+}
+
+func (s *questionReportService) ListReports(ctx context.Context, req *models.ListQuestionReportsRequest) (*models.ListQuestionReportsResponse, error) {
+	var questionID *primitive.ObjectID
+	if req.QuestionID != "" {
+		id, err := primitive.ObjectIDFromHex(req.QuestionID)
+		if err != nil {
+			return nil, models.BadRequestError("invalid question ID")
+		}
+		questionID = &id
+	}
+
+	reports, total, err := s.reportRepo.List(ctx, req, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question reports: %w", err)
+	}
+
+	openCount, err := s.reportRepo.CountOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open question reports: %w", err)
+	}
+
+	items := make([]models.QuestionReportItem, 0, len(reports))
+	for _, report := range reports {
+		items = append(items, models.QuestionReportItem{
+			QuestionReport: report,
+			Links:          questionReportLinks(&report),
+		})
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListQuestionReportsResponse{
+		Reports:    items,
+		Total:      total,
+		OpenCount:  openCount,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetReportContext returns a report with the current question and the session question as the student saw it
+func (s *questionReportService) GetReportContext(ctx context.Context, reportID primitive.ObjectID) (*models.QuestionReportContextResponse, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.QuestionReportContextResponse{Report: report}
+
+	question, err := s.questionRepo.GetByID(ctx, report.QuestionID)
+	if err != nil && !errors.Is(err, repository.ErrQuestionNotFound) {
+		return nil, fmt.Errorf("failed to get question: %w", err)
+	}
+	response.Question = question
+
+	session, err := s.sessionRepo.GetSessionByID(ctx, report.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return response, nil
+		}
+		return nil, err
+	}
+
+	response.Session = &models.SessionSummary{
+		ID:              session.ID,
+		UserID:          session.UserID,
+		QuizType:        session.QuizType,
+		Status:          session.Status,
+		StartTime:       session.StartTime,
+		EndTime:         session.EndTime,
+		TotalQuestions:  session.TotalQuestions,
+		CurrentQuestion: session.CurrentQuestion,
+	}
+	if report.QuestionIndex < len(session.Questions) {
+		response.SessionQuestion = &session.Questions[report.QuestionIndex]
+	}
+
+	return response, nil
+}
+
+func (s *questionReportService) ReviewReport(ctx context.Context, reviewerID, reportID primitive.ObjectID, req *models.ReviewQuestionReportRequest) (*models.QuestionReport, error) {
+	if err := s.reportRepo.Review(ctx, reportID, reviewerID, req.Status, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.GetByID(ctx, reportID)
+}
+
+func questionReportLinks(report *models.QuestionReport) models.QuestionReportLinks {
+	return models.QuestionReportLinks{
+		Question: "/api/v1/admin/questions/" + report.QuestionID.Hex(),
+		Session:  "/api/v1/admin/question-reports/" + report.ID.Hex() + "/context",
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"html"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// explanationSnippetRadius is how much of an explanation is shown either side of its first match
+const explanationSnippetRadius = 60
+
+// searchQuestions pages through the questions matching a list filter and search text, ranked by the
+// text index. Without the index, the wording is matched as case-insensitive patterns instead.
+func (s *questionService) searchQuestions(ctx context.Context, filter bson.M, search string, page, limit int) ([]*models.Question, int64, models.QuestionSearchMode, error) {
+	questions, total, err := s.questionRepo.Search(ctx, withTextSearch(filter, search), page, limit)
+	if err == nil {
+		return questions, total, models.SearchModeText, nil
+	}
+	if !errors.Is(err, repository.ErrQuestionTextIndexNotFound) {
+		return nil, 0, "", err
+	}
+
+	slog.WarnContext(ctx, "Question text index not found, searching by pattern")
+	questions, total, err = s.questionRepo.List(ctx, withPatternSearch(filter, search), page, limit)
+	return questions, total, models.SearchModePattern, err
+}
+
+// withTextSearch returns a copy of the filter with a $text query for the search
+func withTextSearch(filter bson.M, search string) bson.M {
+	searched := copyFilter(filter)
+	searched["$text"] = bson.M{"$search": search}
+	return searched
+}
+
+// withPatternSearch returns a copy of the filter matching any of the search terms in the fields the
+// text index covers
+func withPatternSearch(filter bson.M, search string) bson.M {
+	searched := copyFilter(filter)
+	expression := searchExpression(searchTerms(search))
+	if expression == "" {
+		return searched
+	}
+	match := bson.M{"$regex": expression, "$options": "i"}
+	searched["$or"] = []bson.M{
+		{"title": match},
+		{"options.text": match},
+		{"explanation": match},
+	}
+	return searched
+}
+
+func copyFilter(filter bson.M) bson.M {
+	copied := make(bson.M, len(filter)+1)
+	for key, value := range filter {
+		copied[key] = value
+	}
+	return copied
+}
+
+// searchTerms splits search text the way a $text query reads it: words and "quoted phrases", less
+// the -negated words, which only exclude questions
+func searchTerms(search string) []string {
+	var terms []string
+	for i, part := range strings.Split(search, `"`) {
+		if i%2 == 1 {
+			if phrase := strings.TrimSpace(part); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if !strings.HasPrefix(word, "-") {
+				terms = append(terms, word)
+			}
+		}
+	}
+	return terms
+}
+
+// searchExpression is a regular expression matching any of the terms
+func searchExpression(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// highlightQuestions marks where the search terms appear in each question's title, options and
+// explanation
+func highlightQuestions(questions []*models.Question, search string) {
+	expression := searchExpression(searchTerms(search))
+	if expression == "" {
+		return
+	}
+	pattern := regexp.MustCompile("(?i)" + expression)
+
+	for _, q := range questions {
+		if pattern.MatchString(q.Title) {
+			q.Highlights = append(q.Highlights, models.QuestionSearchHighlight{
+				Field:   "title",
+				Snippet: markMatches(q.Title, pattern),
+			})
+		}
+		for _, option := range q.Options {
+			if pattern.MatchString(option.Text) {
+				q.Highlights = append(q.Highlights, models.QuestionSearchHighlight{
+					Field:   "options",
+					Snippet: markMatches(option.Text, pattern),
+				})
+			}
+		}
+		if loc := pattern.FindStringIndex(q.Explanation); loc != nil {
+			q.Highlights = append(q.Highlights, models.QuestionSearchHighlight{
+				Field:   "explanation",
+				Snippet: markMatches(snippetAround(q.Explanation, loc[0], loc[1]), pattern),
+			})
+		}
+	}
+}
+
+// markMatches escapes text for HTML, wrapping the pattern's matches in <mark>
+func markMatches(text string, pattern *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString("</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// snippetAround cuts the text down to the match at start:end with some context either side, on
+// character boundaries
+func snippetAround(text string, start, end int) string {
+	from := max(start-explanationSnippetRadius, 0)
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	to := min(end+explanationSnippetRadius, len(text))
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+
+	snippet := strings.TrimSpace(text[from:to])
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
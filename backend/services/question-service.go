@@ -4,36 +4,62 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"backend/models"
 	"backend/repository"
+	"backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrQuestionArchived is returned when changing a question that is archived
+var ErrQuestionArchived = models.ConflictError("question is archived; restore it first")
+
 type QuestionService interface {
 	CreateQuestion(ctx context.Context, req *models.CreateQuestionRequest, createdBy primitive.ObjectID) (*models.Question, error)
 	GetQuestion(ctx context.Context, id primitive.ObjectID) (*models.Question, error)
-	UpdateQuestion(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuestionRequest) (*models.Question, error)
-	DeleteQuestion(ctx context.Context, id primitive.ObjectID) error
+	UpdateQuestion(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuestionRequest, updatedBy primitive.ObjectID) (*models.Question, error)
+	GetQuestionVersions(ctx context.Context, id primitive.ObjectID) (*models.QuestionVersionsResponse, error)
+	DeleteQuestion(ctx context.Context, id, deletedBy primitive.ObjectID, permanent bool) error
+	RestoreQuestion(ctx context.Context, id primitive.ObjectID) (*models.Question, error)
 	ListQuestions(ctx context.Context, req *models.ListQuestionsRequest) (*models.ListQuestionsResponse, error)
+	ExportQuestions(ctx context.Context, req *models.ExportQuestionsRequest, w io.Writer) (int, error)
 	GetQuestionStats(ctx context.Context) (*models.QuestionStatsResponse, error)
 	GetRandomQuestions(ctx context.Context, questionType models.QuestionType, limit int) ([]*models.Question, error)
 	ToggleQuestionStatus(ctx context.Context, id primitive.ObjectID, isActive bool) (*models.Question, error)
 	ValidateQuestionData(req *models.CreateQuestionRequest) error
+	ValidateQuestionRequest(ctx context.Context, req *models.CreateQuestionRequest) error
+	GetAccessibilityReport(ctx context.Context, req *models.AccessibilityReportRequest) (*models.AccessibilityReportResponse, error)
+	RecordReview(ctx context.Context, questionID, reviewerID primitive.ObjectID, req *models.RecordQuestionReviewRequest) (*models.Question, error)
+	SubmitQuestion(ctx context.Context, id primitive.ObjectID) (*models.Question, error)
+	ApproveQuestion(ctx context.Context, id, reviewerID primitive.ObjectID, req *models.ApproveQuestionRequest) (*models.Question, error)
+	RejectQuestion(ctx context.Context, id, reviewerID primitive.ObjectID, req *models.RejectQuestionRequest) (*models.Question, error)
+	GetAuthoringReport(ctx context.Context, req *models.AuthoringReportRequest) (*models.AuthoringReportResponse, error)
 }
 
 type questionService struct {
 	questionRepo repository.QuestionRepository
+	moduleRepo   repository.ModuleRepository
+	userRepo     repository.UserRepository
+	versionRepo  repository.QuestionVersionRepository
+	sessionRepo  repository.QuizSessionRepository
+	cache        *utils.Cache
 }
 
-func NewQuestionService(questionRepo repository.QuestionRepository) QuestionService {
+func NewQuestionService(questionRepo repository.QuestionRepository, moduleRepo repository.ModuleRepository, userRepo repository.UserRepository, versionRepo repository.QuestionVersionRepository, sessionRepo repository.QuizSessionRepository, cache *utils.Cache) QuestionService {
 	return &questionService{
 		questionRepo: questionRepo,
+		moduleRepo:   moduleRepo,
+		userRepo:     userRepo,
+		versionRepo:  versionRepo,
+		sessionRepo:  sessionRepo,
+		cache:        cache,
 	}
 }
 
@@ -42,15 +68,30 @@ func (s *questionService) CreateQuestion(ctx context.Context, req *models.Create
 	if err := s.ValidateQuestionData(req); err != nil {
 		return nil, err
 	}
+	sections, err := s.validateSections(ctx, req.Sections)
+	if err != nil {
+		return nil, err
+	}
+	moduleIDs, err := checkModuleIDs(ctx, s.moduleRepo, req.ModuleIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create question from request
 	question := &models.Question{
-		Title:      strings.TrimSpace(req.Title),
-		Type:       req.Type,
-		Difficulty: req.Difficulty,
-		Points:     req.Points,
-		IsActive:   true, // New questions are active by default
-		CreatedBy:  createdBy,
+		Title:         strings.TrimSpace(req.Title),
+		Type:          req.Type,
+		Difficulty:    req.Difficulty,
+		Points:        req.Points,
+		Media:         normalizeMedia(req.Media),
+		Explanation:   strings.TrimSpace(req.Explanation),
+		Sections:      sections,
+		ModuleIDs:     moduleIDs,
+		Tags:          normalizeTags(req.Tags),
+		Translations:  normalizeTranslations(req.Translations),
+		IsActive:      true, // New questions are active by default
+		ContributedBy: req.ContributedBy,
+		CreatedBy:     createdBy,
 	}
 
 	// Handle different question types
@@ -63,12 +104,30 @@ func (s *questionService) CreateQuestion(ctx context.Context, req *models.Create
 		if err := s.processEssayQuestion(question, req); err != nil {
 			return nil, err
 		}
+	case models.FillInBlank:
+		question.Blanks = normalizeBlanks(req.Blanks)
+	case models.Numeric:
+		question.NumericAnswer = normalizeNumericAnswer(req.NumericAnswer)
+		question.Variables = normalizeVariables(req.Variables)
+		question.AnswerExpression = strings.TrimSpace(req.AnswerExpression)
+	case models.Ordering:
+		question.Options = newOptions(req.Options)
+		question.CorrectAnswers = optionSequence(question.Options)
+		question.OrderingScoring = orderingScoring(req.OrderingScoring)
+	}
+
+	if err := s.initialReviewStatus(ctx, question); err != nil {
+		return nil, err
 	}
 
 	// Create question in database
+	question.Version = 1
 	if err := s.questionRepo.Create(ctx, question); err != nil {
 		return nil, fmt.Errorf("failed to create question: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+	s.recordFirstVersion(ctx, question)
+	renderQuestionContent(question)
 
 	return question, nil
 }
@@ -78,15 +137,27 @@ func (s *questionService) GetQuestion(ctx context.Context, id primitive.ObjectID
 	if err != nil {
 		return nil, fmt.Errorf("failed to get question: %w", err)
 	}
+	s.attachCredits(ctx, []*models.Question{question})
+	renderQuestionContent(question)
 	return question, nil
 }
 
-func (s *questionService) UpdateQuestion(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuestionRequest) (*models.Question, error) {
+func (s *questionService) UpdateQuestion(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuestionRequest, updatedBy primitive.ObjectID) (*models.Question, error) {
 	// Get existing question
 	existingQuestion, err := s.questionRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("question not found: %w", err)
 	}
+	if existingQuestion.Archived && req.IsActive != nil && *req.IsActive {
+		return nil, ErrQuestionArchived
+	}
+	title := ""
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if err := validateQuestionContent(title, req.Options); err != nil {
+		return nil, err
+	}
 
 	// Build updates map
 	updates := bson.M{}
@@ -106,21 +177,42 @@ func (s *questionService) UpdateQuestion(ctx context.Context, id primitive.Objec
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
 	}
+	if req.Explanation != nil {
+		updates["explanation"] = strings.TrimSpace(*req.Explanation)
+	}
+	if req.Sections != nil {
+		sections, err := s.validateSections(ctx, req.Sections)
+		if err != nil {
+			return nil, err
+		}
+		updates["sections"] = sections
+	}
+	if req.ModuleIDs != nil {
+		moduleIDs, err := checkModuleIDs(ctx, s.moduleRepo, req.ModuleIDs)
+		if err != nil {
+			return nil, err
+		}
+		updates["module_ids"] = moduleIDs
+	}
+	if req.Tags != nil {
+		updates["tags"] = normalizeTags(req.Tags)
+	}
+	if req.Media != nil {
+		if err := validateAltText(req.Media, req.Options); err != nil {
+			return nil, err
+		}
+		updates["media"] = normalizeMedia(req.Media)
+	} else if req.Options != nil {
+		if err := validateAltText(nil, req.Options); err != nil {
+			return nil, err
+		}
+	}
 
 	// Handle type-specific updates
 	switch existingQuestion.Type {
 	case models.SingleChoice, models.MultipleChoice:
 		if req.Options != nil {
-			// Convert CreateOption to Option
-			options := make([]models.Option, len(req.Options))
-			for i, opt := range req.Options {
-				options[i] = models.Option{
-					ID:    primitive.NewObjectID().Hex(),
-					Text:  strings.TrimSpace(opt.Text),
-					Order: i + 1,
-				}
-			}
-			updates["options"] = options
+			updates["options"] = newOptions(req.Options)
 		}
 		if req.CorrectAnswers != nil {
 			updates["correct_answers"] = req.CorrectAnswers
@@ -129,40 +221,232 @@ func (s *questionService) UpdateQuestion(ctx context.Context, id primitive.Objec
 		if req.SampleAnswer != nil {
 			updates["sample_answer"] = strings.TrimSpace(*req.SampleAnswer)
 		}
+	case models.FillInBlank:
+		// The title's placeholders and the blanks must keep matching whichever of them changes
+		if req.Title != nil || req.Blanks != nil {
+			title := existingQuestion.Title
+			if req.Title != nil {
+				title = *req.Title
+			}
+			blanks := existingQuestion.Blanks
+			if req.Blanks != nil {
+				blanks = normalizeBlanks(req.Blanks)
+			}
+			if err := validateBlanks(title, blanks); err != nil {
+				return nil, err
+			}
+			updates["blanks"] = blanks
+		}
+	case models.Numeric:
+		variables, expression := existingQuestion.Variables, existingQuestion.AnswerExpression
+		if req.Variables != nil || req.AnswerExpression != nil {
+			if req.Variables != nil {
+				variables = normalizeVariables(req.Variables)
+			}
+			if req.AnswerExpression != nil {
+				expression = strings.TrimSpace(*req.AnswerExpression)
+			}
+			if err := validateVariables(models.Numeric, variables, expression); err != nil {
+				return nil, err
+			}
+			updates["variables"] = variables
+			updates["answer_expression"] = expression
+		}
+		if req.NumericAnswer != nil {
+			answer := normalizeNumericAnswer(req.NumericAnswer)
+			if err := validateNumericAnswer(answer, expression != ""); err != nil {
+				return nil, err
+			}
+			updates["numeric_answer"] = answer
+		}
+	case models.Ordering:
+		// New options get new IDs, so the sequence is rebuilt from them
+		if req.Options != nil {
+			if err := validateOrderingOptions(req.Options); err != nil {
+				return nil, err
+			}
+			options := newOptions(req.Options)
+			updates["options"] = options
+			updates["correct_answers"] = optionSequence(options)
+		}
+		if req.OrderingScoring != nil {
+			updates["ordering_scoring"] = *req.OrderingScoring
+		}
 	}
 
-	// Update question in database
-	if err := s.questionRepo.Update(ctx, id, updates); err != nil {
-		return nil, fmt.Errorf("failed to update question: %w", err)
+	// Translations must keep fitting the options and blanks they translate, whichever changed
+	translations := existingQuestion.Translations
+	if req.Translations != nil {
+		translations = normalizeTranslations(req.Translations)
+		updates["translations"] = translations
+	}
+	optionCount := len(existingQuestion.Options)
+	if options, ok := updates["options"].([]models.Option); ok {
+		optionCount = len(options)
+	}
+	blanks := existingQuestion.Blanks
+	if updated, ok := updates["blanks"].([]models.Blank); ok {
+		blanks = updated
+	}
+	if err := validateTranslations(translations, existingQuestion.Type, optionCount, blanks); err != nil {
+		return nil, err
+	}
+
+	// A content author's edit needs approving again before quizzes see it
+	if existingQuestion.ReviewStatus != models.QuestionDraft {
+		author, err := s.isQuestionAuthor(ctx, updatedBy)
+		if err != nil {
+			return nil, err
+		}
+		if author {
+			updates["review_status"] = models.QuestionDraft
+			updates["submitted_at"] = nil
+		}
 	}
 
-	// Return updated question
-	return s.questionRepo.GetByID(ctx, id)
+	// Store the edit as a new version rather than overwriting the old one
+	question, err := s.saveVersionedUpdate(ctx, existingQuestion, updates, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	renderQuestionContent(question)
+	return question, nil
 }
 
-func (s *questionService) DeleteQuestion(ctx context.Context, id primitive.ObjectID) error {
+// DeleteQuestion archives a question, or removes it for good when permanent is set. A question that
+// quiz sessions or results include can only be archived, so past results keep their source.
+func (s *questionService) DeleteQuestion(ctx context.Context, id, deletedBy primitive.ObjectID, permanent bool) error {
 	// Check if question exists
-	_, err := s.questionRepo.GetByID(ctx, id)
+	question, err := s.questionRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("question not found: %w", err)
 	}
 
+	if !permanent {
+		if question.Archived {
+			return models.ConflictError("question is already archived")
+		}
+		updates := bson.M{
+			"archived":              true,
+			"archived_at":           time.Now(),
+			"archived_by":           deletedBy,
+			"active_before_archive": question.IsActive,
+			"is_active":             false,
+		}
+		if err := s.questionRepo.Update(ctx, id, updates); err != nil {
+			return fmt.Errorf("failed to archive question: %w", err)
+		}
+		s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+		return nil
+	}
+
+	referenced, err := s.sessionRepo.IsQuestionReferenced(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check question references: %w", err)
+	}
+	if referenced {
+		return models.ConflictError("question is used in quiz sessions and can only be archived")
+	}
+
 	// Delete question
 	if err := s.questionRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete question: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
 
 	return nil
 }
 
+// RestoreQuestion returns an archived question to the bank, active again if it was when archived
+func (s *questionService) RestoreQuestion(ctx context.Context, id primitive.ObjectID) (*models.Question, error) {
+	question, err := s.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !question.Archived {
+		return nil, repository.ErrQuestionNotArchived
+	}
+
+	if err := s.questionRepo.Unarchive(ctx, id, question.ActiveBeforeArchive); err != nil {
+		if errors.Is(err, repository.ErrQuestionNotArchived) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to restore question: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+
+	question, err = s.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	renderQuestionContent(question)
+	return question, nil
+}
+
 func (s *questionService) ListQuestions(ctx context.Context, req *models.ListQuestionsRequest) (*models.ListQuestionsResponse, error) {
-	// Build filter
-	filter := bson.M{}
+	filter, err := questionListFilter(req)
+	if err != nil {
+		return nil, err
+	}
 
-	// Add search filter
-	if req.Search != "" {
-		filter["title"] = bson.M{"$regex": req.Search, "$options": "i"}
+	// Search results are ranked by relevance, which a cursor cannot continue from
+	search := strings.TrimSpace(req.Search)
+	after, err := parseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
 	}
+	if after != nil && search != "" {
+		return nil, models.BadRequestError("cursor cannot be combined with search")
+	}
+
+	// Get questions from repository, by relevance when searching
+	var questions []*models.Question
+	var total int64
+	var searchMode models.QuestionSearchMode
+	switch {
+	case after != nil:
+		questions, err = s.questionRepo.ListAfter(ctx, filter, after, req.Limit)
+	case search != "":
+		questions, total, searchMode, err = s.searchQuestions(ctx, filter, search, req.Page, req.Limit)
+	default:
+		questions, total, err = s.questionRepo.List(ctx, filter, req.Page, req.Limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list questions: %w", err)
+	}
+
+	response := &models.ListQuestionsResponse{
+		Limit:      req.Limit,
+		SearchMode: searchMode,
+	}
+	var more bool
+	if after != nil {
+		questions, more = cursorPage(questions, req.Limit)
+	} else {
+		response.Total = total
+		response.Page = req.Page
+		// Calculate total pages
+		response.TotalPages = int(math.Ceil(float64(total) / float64(req.Limit)))
+		more = search == "" && req.Page < response.TotalPages
+	}
+	if more && len(questions) > 0 {
+		last := questions[len(questions)-1]
+		response.NextCursor = models.NewPageCursor(last.CreatedAt, last.ID).String()
+	}
+
+	s.attachCredits(ctx, questions)
+	renderQuestionsContent(questions)
+	highlightQuestions(questions, search)
+	response.Questions = questions
+
+	return response, nil
+}
+
+// questionListFilter builds the query for the question bank list filters. Search is applied by the
+// callers, since it depends on whether the text index exists.
+func questionListFilter(req *models.ListQuestionsRequest) (bson.M, error) {
+	// Build filter
+	filter := bson.M{}
 
 	// Add type filter
 	if req.Type != "" {
@@ -174,34 +458,52 @@ func (s *questionService) ListQuestions(ctx context.Context, req *models.ListQue
 		filter["difficulty"] = req.Difficulty
 	}
 
+	// Add tag filter
+	if tags := normalizeTags([]string{req.Tag}); len(tags) > 0 {
+		filter["tags"] = tags[0]
+	}
+
+	// Archived questions are listed apart from the bank
+	if req.Archived {
+		filter["archived"] = true
+	} else {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+
 	// Add active status filter
 	if req.IsActive != nil {
 		filter["is_active"] = *req.IsActive
 	}
 
-	// Get questions from repository
-	questions, total, err := s.questionRepo.List(ctx, filter, req.Page, req.Limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list questions: %w", err)
+	// Questions from before the publishing workflow have no status and count as approved
+	switch req.ReviewStatus {
+	case "":
+	case models.QuestionApproved:
+		filter["review_status"] = bson.M{"$nin": models.UnapprovedReviewStatuses}
+	case models.QuestionDraft, models.QuestionInReview:
+		filter["review_status"] = req.ReviewStatus
+	default:
+		return nil, models.BadRequestError("invalid review status")
 	}
 
-	// Calculate total pages
-	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+	// Add author and reviewer filters
+	if err := creditFilter(filter, req); err != nil {
+		return nil, err
+	}
 
-	return &models.ListQuestionsResponse{
-		Questions:  questions,
-		Total:      total,
-		Page:       req.Page,
-		Limit:      req.Limit,
-		TotalPages: totalPages,
-	}, nil
+	return filter, nil
 }
 
 func (s *questionService) GetQuestionStats(ctx context.Context) (*models.QuestionStatsResponse, error) {
+	var cached models.QuestionStatsResponse
+	if s.cache.Get(ctx, utils.CacheQuestionStats, "all", &cached) {
+		return &cached, nil
+	}
 	stats, err := s.questionRepo.GetStats(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get question statistics: %w", err)
 	}
+	s.cache.Set(ctx, utils.CacheQuestionStats, "all", stats)
 	return stats, nil
 }
 
@@ -215,30 +517,85 @@ func (s *questionService) GetRandomQuestions(ctx context.Context, questionType m
 		return nil, fmt.Errorf("failed to get random questions: %w", err)
 	}
 
+	// An ordering question's stored option order is its answer; credits are for staff only
+	for _, q := range questions {
+		q.CreatedBy = primitive.NilObjectID
+		q.ContributedBy = nil
+		if q.Type == models.Ordering {
+			q.Options = displayOptions(q)
+			q.CorrectAnswers = nil
+		}
+		renderQuestionContent(q)
+	}
+
 	return questions, nil
 }
 
 func (s *questionService) ToggleQuestionStatus(ctx context.Context, id primitive.ObjectID, isActive bool) (*models.Question, error) {
+	if isActive {
+		question, err := s.questionRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if question.Archived {
+			return nil, ErrQuestionArchived
+		}
+	}
 	updates := bson.M{"is_active": isActive}
 
 	if err := s.questionRepo.Update(ctx, id, updates); err != nil {
 		return nil, fmt.Errorf("failed to toggle question status: %w", err)
 	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
 
-	return s.questionRepo.GetByID(ctx, id)
+	question, err := s.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	renderQuestionContent(question)
+	return question, nil
 }
 
+// ValidateQuestionData checks a question on its own, before anything it links to is looked up.
+// Every problem it finds is with the request.
 func (s *questionService) ValidateQuestionData(req *models.CreateQuestionRequest) error {
+	if err := s.validateQuestionData(req); err != nil {
+		return models.BadRequestError(err.Error())
+	}
+	return nil
+}
+
+func (s *questionService) validateQuestionData(req *models.CreateQuestionRequest) error {
 	// Validate title
 	if strings.TrimSpace(req.Title) == "" {
 		return errors.New("title is required")
 	}
 
+	// Titles and options are markdown whose LaTeX is typeset by the client
+	if err := validateQuestionContent(req.Title, req.Options); err != nil {
+		return err
+	}
+
 	// Validate points
 	if req.Points < 1 {
 		return errors.New("points must be at least 1")
 	}
 
+	// Images must be described for screen reader users
+	if err := validateAltText(req.Media, req.Options); err != nil {
+		return err
+	}
+
+	// Translations must fit the question they translate
+	if err := validateTranslations(req.Translations, req.Type, len(req.Options), normalizeBlanks(req.Blanks)); err != nil {
+		return err
+	}
+
+	// Parameterized questions compute their answer from the values drawn per session
+	if err := validateVariables(req.Type, normalizeVariables(req.Variables), strings.TrimSpace(req.AnswerExpression)); err != nil {
+		return err
+	}
+
 	// Validate based on question type
 	switch req.Type {
 	case models.SingleChoice:
@@ -247,11 +604,176 @@ func (s *questionService) ValidateQuestionData(req *models.CreateQuestionRequest
 		return s.validateMultipleChoiceQuestion(req)
 	case models.Essay:
 		return s.validateEssayQuestion(req)
+	case models.FillInBlank:
+		return s.validateFillInBlankQuestion(req)
+	case models.Numeric:
+		return s.validateNumericQuestion(req)
+	case models.Ordering:
+		return s.validateOrderingQuestion(req)
 	default:
 		return errors.New("invalid question type")
 	}
 }
 
+// ValidateQuestionRequest runs every check CreateQuestion makes, including that linked sections and
+// modules exist, without saving anything
+func (s *questionService) ValidateQuestionRequest(ctx context.Context, req *models.CreateQuestionRequest) error {
+	if err := s.ValidateQuestionData(req); err != nil {
+		return err
+	}
+	if _, err := s.validateSections(ctx, req.Sections); err != nil {
+		return err
+	}
+	_, err := checkModuleIDs(ctx, s.moduleRepo, req.ModuleIDs)
+	return err
+}
+
+// validateSections checks that each referenced submodule exists in its module, dropping duplicates.
+// Unpublished sections may be linked; students are only pointed at them once published.
+func (s *questionService) validateSections(ctx context.Context, refs []models.SectionRef) ([]models.SectionRef, error) {
+	if len(refs) == 0 {
+		return []models.SectionRef{}, nil
+	}
+
+	modules := make(map[primitive.ObjectID]*models.Module)
+	seen := make(map[primitive.ObjectID]bool)
+	sections := make([]models.SectionRef, 0, len(refs))
+	for i, ref := range refs {
+		module, ok := modules[ref.ModuleID]
+		if !ok {
+			var err error
+			module, err = s.moduleRepo.GetModuleByID(ctx, ref.ModuleID)
+			if err != nil {
+				if errors.Is(err, repository.ErrModuleNotFound) {
+					return nil, models.BadRequestError(fmt.Sprintf("section %d: module not found", i+1))
+				}
+				return nil, fmt.Errorf("failed to get module: %w", err)
+			}
+			modules[ref.ModuleID] = module
+		}
+
+		found := false
+		for _, sub := range module.SubModules {
+			if sub.ID == ref.SubModuleID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, models.BadRequestError(fmt.Sprintf("section %d: submodule not found in module", i+1))
+		}
+
+		if !seen[ref.SubModuleID] {
+			seen[ref.SubModuleID] = true
+			sections = append(sections, ref)
+		}
+	}
+	return sections, nil
+}
+
+// validateAltText requires alt text on every question image and option image
+func validateAltText(media []models.QuestionMedia, options []models.CreateOption) error {
+	for i, m := range media {
+		if m.Type == models.MediaImage && strings.TrimSpace(m.AltText) == "" {
+			return fmt.Errorf("media %d image requires alt text", i+1)
+		}
+	}
+	for i, opt := range options {
+		if strings.TrimSpace(opt.ImageURL) != "" && strings.TrimSpace(opt.ImageAlt) == "" {
+			return fmt.Errorf("option %d image requires alt text", i+1)
+		}
+	}
+	return nil
+}
+
+func normalizeMedia(media []models.QuestionMedia) []models.QuestionMedia {
+	if media == nil {
+		return nil
+	}
+	normalized := make([]models.QuestionMedia, len(media))
+	for i, m := range media {
+		normalized[i] = models.QuestionMedia{
+			URL:     strings.TrimSpace(m.URL),
+			Type:    m.Type,
+			AltText: strings.TrimSpace(m.AltText),
+		}
+	}
+	return normalized
+}
+
+// normalizeTags lowercases tags and collapses their whitespace, dropping blanks and duplicates
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// GetAccessibilityReport lists questions with images that predate alt text enforcement, so they can be fixed
+func (s *questionService) GetAccessibilityReport(ctx context.Context, req *models.AccessibilityReportRequest) (*models.AccessibilityReportResponse, error) {
+	blank := bson.M{"$in": bson.A{nil, ""}}
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"media": bson.M{"$elemMatch": bson.M{"type": models.MediaImage, "alt_text": blank}}},
+			bson.M{"options": bson.M{"$elemMatch": bson.M{"image_url": bson.M{"$nin": bson.A{nil, ""}}, "image_alt": blank}}},
+		},
+	}
+	if req.IsActive != nil {
+		filter["is_active"] = *req.IsActive
+	}
+
+	questions, total, err := s.questionRepo.List(ctx, filter, req.Page, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list questions missing alt text: %w", err)
+	}
+
+	items := make([]models.AccessibilityReportItem, 0, len(questions))
+	for _, q := range questions {
+		items = append(items, models.AccessibilityReportItem{
+			QuestionID: q.ID,
+			Title:      q.Title,
+			Type:       q.Type,
+			IsActive:   q.IsActive,
+			Issues:     missingAltText(q),
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+
+	return &models.AccessibilityReportResponse{
+		Items:      items,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func missingAltText(q *models.Question) []models.AccessibilityIssue {
+	issues := []models.AccessibilityIssue{}
+	for i, m := range q.Media {
+		if m.Type == models.MediaImage && strings.TrimSpace(m.AltText) == "" {
+			issues = append(issues, models.AccessibilityIssue{Field: fmt.Sprintf("media[%d]", i), URL: m.URL})
+		}
+	}
+	for i, opt := range q.Options {
+		if opt.ImageURL != "" && strings.TrimSpace(opt.ImageAlt) == "" {
+			issues = append(issues, models.AccessibilityIssue{Field: fmt.Sprintf("options[%d]", i), URL: opt.ImageURL})
+		}
+	}
+	return issues
+}
+
 func (s *questionService) validateSingleChoiceQuestion(req *models.CreateQuestionRequest) error {
 	// Check options
 	if len(req.Options) < 2 {
@@ -311,17 +833,180 @@ func (s *questionService) validateEssayQuestion(req *models.CreateQuestionReques
 	return nil
 }
 
-func (s *questionService) processChoiceQuestion(question *models.Question, req *models.CreateQuestionRequest) error {
-	// Convert CreateOption to Option
-	options := make([]models.Option, len(req.Options))
-	for i, opt := range req.Options {
-		options[i] = models.Option{
-			ID:    primitive.NewObjectID().Hex(),
-			Text:  strings.TrimSpace(opt.Text),
-			Order: i + 1,
+func (s *questionService) validateFillInBlankQuestion(req *models.CreateQuestionRequest) error {
+	// Blanks are answered by typing, not by choosing
+	if len(req.Options) > 0 {
+		return errors.New("fill-in-the-blank questions should not have options")
+	}
+	if len(req.CorrectAnswers) > 0 {
+		return errors.New("fill-in-the-blank questions take accepted answers per blank, not correct answers")
+	}
+
+	return validateBlanks(req.Title, normalizeBlanks(req.Blanks))
+}
+
+// validateBlanks checks that every {{id}} placeholder in the title has exactly one blank and every blank
+// is placed in the title, and that each blank accepts at least one answer
+func validateBlanks(title string, blanks []models.Blank) error {
+	if len(blanks) == 0 {
+		return errors.New("fill-in-the-blank questions must have at least 1 blank")
+	}
+
+	placed := make(map[string]bool)
+	for _, id := range models.BlankPlaceholders(title) {
+		if placed[id] {
+			return fmt.Errorf("blank {{%s}} appears more than once in the title", id)
+		}
+		placed[id] = true
+	}
+
+	defined := make(map[string]bool, len(blanks))
+	for _, blank := range blanks {
+		if blank.ID == "" {
+			return errors.New("blank ID cannot be empty")
+		}
+		if defined[blank.ID] {
+			return fmt.Errorf("blank %s is defined more than once", blank.ID)
+		}
+		defined[blank.ID] = true
+
+		if !placed[blank.ID] {
+			return fmt.Errorf("blank %s has no {{%s}} placeholder in the title", blank.ID, blank.ID)
+		}
+		if len(blank.AcceptedAnswers) == 0 {
+			return fmt.Errorf("blank %s must have at least 1 accepted answer", blank.ID)
+		}
+		for _, answer := range blank.AcceptedAnswers {
+			if answer == "" {
+				return fmt.Errorf("blank %s has an empty accepted answer", blank.ID)
+			}
+			if blank.Regex {
+				if _, err := models.CompileBlankPattern(answer, blank.CaseSensitive); err != nil {
+					return fmt.Errorf("blank %s has an invalid pattern %q: %v", blank.ID, answer, err)
+				}
+			}
+		}
+	}
+
+	for id := range placed {
+		if !defined[id] {
+			return fmt.Errorf("placeholder {{%s}} has no matching blank", id)
+		}
+	}
+
+	return nil
+}
+
+func (s *questionService) validateNumericQuestion(req *models.CreateQuestionRequest) error {
+	// The answer is a typed number, not a choice
+	if len(req.Options) > 0 {
+		return errors.New("numeric questions should not have options")
+	}
+	if len(req.CorrectAnswers) > 0 {
+		return errors.New("numeric questions take a numeric answer, not correct answers")
+	}
+	// A parameterized question computes its value, so its numeric answer may be left out
+	parameterized := strings.TrimSpace(req.AnswerExpression) != ""
+	if req.NumericAnswer == nil && !parameterized {
+		return errors.New("numeric questions must have a numeric answer")
+	}
+
+	return validateNumericAnswer(normalizeNumericAnswer(req.NumericAnswer), parameterized)
+}
+
+// validateNumericAnswer checks that the expected value is a finite number and the tolerances are
+// usable. When the value is computed per session, only the tolerances and unit are checked.
+func validateNumericAnswer(answer *models.NumericAnswer, computed bool) error {
+	if computed {
+		checked := *answer
+		checked.Value = 1 // Stands in for the values computed, which are not all known here
+		answer = &checked
+	}
+	if math.IsNaN(answer.Value) || math.IsInf(answer.Value, 0) {
+		return errors.New("numeric answer value must be a finite number")
+	}
+	if answer.AbsoluteTolerance < 0 || math.IsInf(answer.AbsoluteTolerance, 0) {
+		return errors.New("absolute tolerance must be a non-negative number")
+	}
+	if answer.RelativeTolerance < 0 || answer.RelativeTolerance > 1 {
+		return errors.New("relative tolerance must be between 0 and 1")
+	}
+	if answer.RelativeTolerance > 0 && answer.Value == 0 && answer.AbsoluteTolerance == 0 {
+		return errors.New("relative tolerance has no effect when the value is 0; use an absolute tolerance")
+	}
+	if _, _, ok := models.NumericResponse(answer.Unit); ok {
+		return errors.New("numeric answer unit cannot start with a number")
+	}
+	return nil
+}
+
+// normalizeNumericAnswer trims the unit; a missing answer, left out of a parameterized question, is a zero one
+func normalizeNumericAnswer(answer *models.NumericAnswer) *models.NumericAnswer {
+	if answer == nil {
+		return &models.NumericAnswer{}
+	}
+	normalized := *answer
+	normalized.Unit = strings.TrimSpace(answer.Unit)
+	return &normalized
+}
+
+func (s *questionService) validateOrderingQuestion(req *models.CreateQuestionRequest) error {
+	// The options are given in the correct sequence, so there is nothing to mark as correct
+	if len(req.CorrectAnswers) > 0 {
+		return errors.New("ordering questions take their options in the correct order, not correct answers")
+	}
+
+	return validateOrderingOptions(req.Options)
+}
+
+func validateOrderingOptions(options []models.CreateOption) error {
+	if len(options) < 2 {
+		return errors.New("ordering questions must have at least 2 options")
+	}
+	if len(options) > 10 {
+		return errors.New("ordering questions cannot have more than 10 options")
+	}
+
+	for i, opt := range options {
+		if strings.TrimSpace(opt.Text) == "" {
+			return fmt.Errorf("option %d text cannot be empty", i+1)
 		}
 	}
+	return nil
+}
 
+// orderingScoring defaults ordering questions to exact scoring
+func orderingScoring(scoring models.OrderingScoring) models.OrderingScoring {
+	if scoring == "" {
+		return models.OrderingExact
+	}
+	return scoring
+}
+
+// normalizeBlanks trims blank IDs and accepted answers; regex patterns are kept as written
+func normalizeBlanks(blanks []models.Blank) []models.Blank {
+	normalized := make([]models.Blank, len(blanks))
+	for i, blank := range blanks {
+		answers := make([]string, len(blank.AcceptedAnswers))
+		for j, answer := range blank.AcceptedAnswers {
+			if blank.Regex {
+				answers[j] = answer
+			} else {
+				answers[j] = strings.TrimSpace(answer)
+			}
+		}
+		normalized[i] = models.Blank{
+			ID:              strings.TrimSpace(blank.ID),
+			AcceptedAnswers: answers,
+			CaseSensitive:   blank.CaseSensitive,
+			Regex:           blank.Regex,
+		}
+	}
+	return normalized
+}
+
+func (s *questionService) processChoiceQuestion(question *models.Question, req *models.CreateQuestionRequest) error {
+	options := newOptions(req.Options)
 	question.Options = options
 
 	// Map correct answer indices to generated option IDs
@@ -339,6 +1024,30 @@ func (s *questionService) processChoiceQuestion(question *models.Question, req *
 	return nil
 }
 
+// newOptions converts CreateOption to Option, numbering them in the order given
+func newOptions(createOptions []models.CreateOption) []models.Option {
+	options := make([]models.Option, len(createOptions))
+	for i, opt := range createOptions {
+		options[i] = models.Option{
+			ID:       primitive.NewObjectID().Hex(),
+			Text:     strings.TrimSpace(opt.Text),
+			ImageURL: strings.TrimSpace(opt.ImageURL),
+			ImageAlt: strings.TrimSpace(opt.ImageAlt),
+			Order:    i + 1,
+		}
+	}
+	return options
+}
+
+// optionSequence is an ordering question's correct sequence: its option IDs in stored order
+func optionSequence(options []models.Option) []string {
+	sequence := make([]string, len(options))
+	for i, option := range options {
+		sequence[i] = option.ID
+	}
+	return sequence
+}
+
 func (s *questionService) processEssayQuestion(question *models.Question, req *models.CreateQuestionRequest) error {
 	if req.SampleAnswer != "" {
 		question.SampleAnswer = strings.TrimSpace(req.SampleAnswer)
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"backend/models"
+)
+
+func TestValidateBlanks(t *testing.T) {
+	title := "The capital of {{city}} is {{capital}}"
+	blank := func(id string, regex bool, answers ...string) models.Blank {
+		return models.Blank{ID: id, AcceptedAnswers: answers, Regex: regex}
+	}
+
+	tests := []struct {
+		name   string
+		title  string
+		blanks []models.Blank
+		want   string // Empty when the blanks are valid
+	}{
+		{"valid", title, []models.Blank{blank("city", false, "Indonesia"), blank("capital", true, "Jakarta|Batavia")}, ""},
+		{"no blanks", title, nil, "at least 1 blank"},
+		{"placeholder repeated", "{{city}} {{city}}", []models.Blank{blank("city", false, "a")}, "appears more than once"},
+		{"blank defined twice", "{{city}}", []models.Blank{blank("city", false, "a"), blank("city", false, "b")}, "defined more than once"},
+		{"blank without placeholder", "{{city}}", []models.Blank{blank("city", false, "a"), blank("capital", false, "b")}, "has no {{capital}} placeholder"},
+		{"placeholder without blank", title, []models.Blank{blank("city", false, "a")}, "capital"},
+		{"no accepted answers", "{{city}}", []models.Blank{blank("city", false)}, "at least 1 accepted answer"},
+		{"empty accepted answer", "{{city}}", []models.Blank{blank("city", false, "a", "")}, "empty accepted answer"},
+		{"invalid pattern", "{{city}}", []models.Blank{blank("city", true, "Jakarta", "(")}, `invalid pattern "("`},
+		{"pattern breaking out of the anchors", "{{city}}", []models.Blank{blank("city", true, "a)|(b")}, `invalid pattern "a)|(b"`},
+		{"invalid pattern as a literal answer", "{{city}}", []models.Blank{blank("city", false, "(")}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBlanks(tt.title, tt.blanks)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("validateBlanks() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("validateBlanks() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
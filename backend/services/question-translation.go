@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"backend/models"
+	"backend/utils"
+)
+
+// validateTranslations checks each translation fits the question it translates: a translated title,
+// one translated option per option when options are translated at all, and for fill-in-the-blank
+// questions the same placeholders as the original
+func validateTranslations(translations map[string]models.QuestionTranslation, questionType models.QuestionType, optionCount int, blanks []models.Blank) error {
+	langs := make([]string, 0, len(translations))
+	for lang := range translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		t := translations[lang]
+		if !models.IsQuestionLanguage(lang) {
+			return fmt.Errorf("translation %s: unsupported language", lang)
+		}
+		if lang == models.DefaultQuestionLanguage {
+			return fmt.Errorf("translation %s: the question itself is written in %s", lang, models.DefaultQuestionLanguage)
+		}
+		if strings.TrimSpace(t.Title) == "" {
+			return fmt.Errorf("translation %s: title is required", lang)
+		}
+
+		if err := utils.CheckMarkdown(t.Title); err != nil {
+			return fmt.Errorf("translation %s: title: %w", lang, err)
+		}
+		for i, option := range t.Options {
+			if strings.TrimSpace(option) == "" {
+				return fmt.Errorf("translation %s: option %d is empty", lang, i+1)
+			}
+			if err := utils.CheckMarkdown(option); err != nil {
+				return fmt.Errorf("translation %s: option %d: %w", lang, i+1, err)
+			}
+		}
+
+		switch questionType {
+		case models.SingleChoice, models.MultipleChoice, models.Ordering:
+			if len(t.Options) > 0 && len(t.Options) != optionCount {
+				return fmt.Errorf("translation %s: has %d options, the question has %d", lang, len(t.Options), optionCount)
+			}
+		default:
+			if len(t.Options) > 0 {
+				return fmt.Errorf("translation %s: %s questions have no options", lang, questionType)
+			}
+		}
+
+		if questionType == models.FillInBlank {
+			if err := validateBlanks(t.Title, blanks); err != nil {
+				return fmt.Errorf("translation %s: %w", lang, err)
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeTranslations(translations map[string]models.QuestionTranslation) map[string]models.QuestionTranslation {
+	if translations == nil {
+		return nil
+	}
+	normalized := make(map[string]models.QuestionTranslation, len(translations))
+	for lang, t := range translations {
+		options := make([]string, len(t.Options))
+		for i, option := range t.Options {
+			options[i] = strings.TrimSpace(option)
+		}
+		normalized[lang] = models.QuestionTranslation{
+			Title:       strings.TrimSpace(t.Title),
+			Options:     options,
+			Explanation: strings.TrimSpace(t.Explanation),
+		}
+	}
+	return normalized
+}
+
+// localizeQuestion switches a question's wording to its translation in lang and returns the
+// language it is now in. Without a translation, or for untranslated options and explanations, the
+// default language is kept. An empty lang leaves the question as it is.
+func localizeQuestion(q *models.Question, lang string) string {
+	if lang == "" {
+		return ""
+	}
+	t, ok := q.Translations[lang]
+	if !ok || lang == models.DefaultQuestionLanguage {
+		return models.DefaultQuestionLanguage
+	}
+
+	q.Title = t.Title
+	if len(t.Options) == len(q.Options) {
+		for i := range q.Options {
+			q.Options[i].Text = t.Options[i]
+		}
+	}
+	if t.Explanation != "" {
+		q.Explanation = t.Explanation
+	}
+	// The spoken rendition reads the original wording
+	q.Audio = nil
+	return lang
+}
+
+// localizedSessionQuestion prepares a question for a session served in lang
+func (s *quizSessionService) localizedSessionQuestion(q *models.Question, lang string) models.SessionQuestion {
+	served := localizeQuestion(q, lang)
+	question := s.convertQuestionToSessionQuestion(q)
+	question.Language = served
+	return question
+}
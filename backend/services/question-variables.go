@@ -0,0 +1,191 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"backend/models"
+	"backend/utils"
+)
+
+// variableName is the form of a variable's name, the same inside {placeholders} and expressions
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// variablePlaceholder finds {name} placeholders; names that are not declared variables are left alone,
+// so LaTeX groups such as x^{2} are untouched
+var variablePlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// maxVariableSteps bounds how many values one variable may take, keeping draws exact
+const maxVariableSteps = 1e9
+
+// maxVariableDraws is how many times a draw is retried when the answer cannot be computed from it
+const maxVariableDraws = 20
+
+// validateVariables checks a parameterized question: numeric only, with both variables and an
+// answer expression that uses nothing else and has a value at least for the smallest values
+func validateVariables(questionType models.QuestionType, variables []models.QuestionVariable, expression string) error {
+	if len(variables) == 0 && expression == "" {
+		return nil
+	}
+	if questionType != models.Numeric {
+		return errors.New("only numeric questions can have variables")
+	}
+	if len(variables) == 0 {
+		return errors.New("an answer expression needs variables to compute from")
+	}
+	if expression == "" {
+		return errors.New("questions with variables need an answer expression")
+	}
+	if len(variables) > models.MaxQuestionVariables {
+		return fmt.Errorf("questions can have at most %d variables", models.MaxQuestionVariables)
+	}
+
+	declared := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		if !variableName.MatchString(v.Name) {
+			return fmt.Errorf("variable %q: names use letters, digits and underscores, starting with a letter", v.Name)
+		}
+		if utils.IsExpressionKeyword(v.Name) {
+			return fmt.Errorf("variable %s: the name is taken by a function or constant", v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("variable %s is declared twice", v.Name)
+		}
+		declared[v.Name] = true
+
+		for _, bound := range []float64{v.Min, v.Max, v.Step} {
+			if math.IsNaN(bound) || math.IsInf(bound, 0) {
+				return fmt.Errorf("variable %s: range must be finite numbers", v.Name)
+			}
+		}
+		if v.Min > v.Max {
+			return fmt.Errorf("variable %s: min is greater than max", v.Name)
+		}
+		if v.Step <= 0 {
+			return fmt.Errorf("variable %s: step must be positive", v.Name)
+		}
+		if (v.Max-v.Min)/v.Step > maxVariableSteps {
+			return fmt.Errorf("variable %s: the range has too many steps", v.Name)
+		}
+	}
+
+	parsed, err := utils.ParseExpression(expression)
+	if err != nil {
+		return fmt.Errorf("answer expression: %w", err)
+	}
+	for _, name := range parsed.Variables() {
+		if !declared[name] {
+			return fmt.Errorf("answer expression uses undeclared variable %s", name)
+		}
+	}
+	if _, err := parsed.Eval(lowestValues(variables)); err != nil {
+		return fmt.Errorf("answer expression with every variable at its min: %w", err)
+	}
+	return nil
+}
+
+// normalizeVariables trims names and gives variables without a step whole-number steps
+func normalizeVariables(variables []models.QuestionVariable) []models.QuestionVariable {
+	if variables == nil {
+		return nil
+	}
+	normalized := make([]models.QuestionVariable, len(variables))
+	for i, v := range variables {
+		v.Name = strings.TrimSpace(v.Name)
+		if v.Step == 0 {
+			v.Step = 1
+		}
+		normalized[i] = v
+	}
+	return normalized
+}
+
+func lowestValues(variables []models.QuestionVariable) map[string]float64 {
+	values := make(map[string]float64, len(variables))
+	for _, v := range variables {
+		values[v.Name] = v.Min
+	}
+	return values
+}
+
+// instantiateQuestion draws values for a parameterized question and returns a copy of it with the
+// values filled into the title and explanation and the computed answer, along with the values. Other
+// questions are returned as they are. A draw the answer cannot be computed from, such as one dividing
+// by zero, is retried, falling back to every variable's min, which validation made sure works.
+func instantiateQuestion(q *models.Question) (*models.Question, map[string]float64) {
+	if len(q.Variables) == 0 || q.AnswerExpression == "" {
+		return q, nil
+	}
+	expression, err := utils.ParseExpression(q.AnswerExpression)
+	if err != nil {
+		slog.Error("Failed to parse answer expression", "question_id", q.ID.Hex(), "error", err)
+		return q, nil
+	}
+
+	var values map[string]float64
+	var answer float64
+	for draw := 0; draw <= maxVariableDraws; draw++ {
+		values = drawValues(q.Variables)
+		if draw == maxVariableDraws {
+			values = lowestValues(q.Variables)
+		}
+		if answer, err = expression.Eval(values); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		slog.Error("Failed to compute question answer", "question_id", q.ID.Hex(), "error", err)
+		return q, nil
+	}
+
+	instance := *q
+	instance.Title = fillVariables(q.Title, values)
+	instance.Explanation = fillVariables(q.Explanation, values)
+	numeric := models.NumericAnswer{}
+	if q.NumericAnswer != nil {
+		numeric = *q.NumericAnswer
+	}
+	numeric.Value = answer
+	instance.NumericAnswer = &numeric
+	return &instance, values
+}
+
+// drawValues picks a value for each variable, uniformly among the steps of its range
+func drawValues(variables []models.QuestionVariable) map[string]float64 {
+	values := make(map[string]float64, len(variables))
+	for _, v := range variables {
+		steps := int64(math.Floor((v.Max-v.Min)/v.Step + 1e-9))
+		k, _ := rand.Int(rand.Reader, big.NewInt(steps+1))
+		value := v.Min + float64(k.Int64())*v.Step
+		// Round away float drift, so steps of 0.1 give 0.3 rather than 0.30000000000000004
+		scale := math.Pow(10, float64(max(decimalPlaces(v.Min), decimalPlaces(v.Step))))
+		values[v.Name] = math.Round(value*scale) / scale
+	}
+	return values
+}
+
+func decimalPlaces(x float64) int {
+	text := strconv.FormatFloat(x, 'f', -1, 64)
+	if dot := strings.IndexByte(text, '.'); dot >= 0 {
+		return min(len(text)-dot-1, 10)
+	}
+	return 0
+}
+
+// fillVariables replaces the {name} placeholders of declared variables with their values
+func fillVariables(text string, values map[string]float64) string {
+	return variablePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		value, ok := values[placeholder[1:len(placeholder)-1]]
+		if !ok {
+			return placeholder
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	})
+}
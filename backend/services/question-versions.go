@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrQuestionChanged is returned when someone else saved the question since it was loaded
+var ErrQuestionChanged = models.NewCodedError(http.StatusConflict, models.ErrorCodeEditConflict, "question was changed by someone else, reload it and try again")
+
+// Question fields left out of version diffs: identity and bookkeeping, review state, and generated audio, which
+// follows the text on its own
+var unversionedQuestionFields = map[string]bool{
+	"id":             true,
+	"version":        true,
+	"audio":          true,
+	"credits":        true,
+	"created_by":     true,
+	"contributed_by": true,
+	"created_at":     true,
+	"updated_at":     true,
+
+	// Publishing workflow state
+	"review_status": true,
+	"submitted_at":  true,
+	"review_note":   true,
+	"approved_by":   true,
+	"approved_at":   true,
+}
+
+// GetQuestionVersions lists a question's versions, newest first, with how many quiz sessions were
+// built from each. Versions outlive the question, so a deleted question's history can still be read.
+func (s *questionService) GetQuestionVersions(ctx context.Context, id primitive.ObjectID) (*models.QuestionVersionsResponse, error) {
+	versions, err := s.versionRepo.ListByQuestion(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question versions: %w", err)
+	}
+
+	response := &models.QuestionVersionsResponse{QuestionID: id}
+	question, err := s.questionRepo.GetByID(ctx, id)
+	switch {
+	case err == nil:
+		response.CurrentVersion = questionVersion(question)
+	case errors.Is(err, repository.ErrQuestionNotFound) && len(versions) > 0:
+		response.CurrentVersion = versions[0].Version
+	default:
+		return nil, err
+	}
+
+	usage, err := s.sessionRepo.CountQuestionVersionUsage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	people := make(map[primitive.ObjectID]creditedPerson)
+	for i := range versions {
+		versions[i].AuthorName = lookupPerson(ctx, s.userRepo, people, versions[i].AuthorID).fullName
+		versions[i].SessionCount = usage[versions[i].Version]
+		versions[i].Frozen = versions[i].SessionCount > 0
+	}
+	if versions == nil {
+		versions = []models.QuestionVersion{}
+	}
+
+	response.Versions = versions
+	response.Total = len(versions)
+	response.UnversionedSessions = usage[0]
+	return response, nil
+}
+
+// questionVersion is the version a quiz session built from the question records. Questions written
+// before versioning are on their first version until they are edited.
+func questionVersion(q *models.Question) int {
+	return max(q.Version, 1)
+}
+
+// recordFirstVersion stores a newly created question as its first version. A failure is only logged,
+// since the next edit stores the missing version before its own.
+func (s *questionService) recordFirstVersion(ctx context.Context, question *models.Question) {
+	err := s.versionRepo.Create(ctx, &models.QuestionVersion{
+		QuestionID: question.ID,
+		Version:    question.Version,
+		Snapshot:   *question,
+		AuthorID:   question.CreatedBy,
+		CreatedAt:  question.CreatedAt,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record first question version", "question_id", question.ID.Hex(), "error", err)
+	}
+}
+
+// saveVersionedUpdate applies an edit as the question's next version, keeping the versions before it
+// intact. The version is stored before the question changes, so of two concurrent edits only one can
+// claim its number. Edits that change nothing add no version.
+func (s *questionService) saveVersionedUpdate(ctx context.Context, existing *models.Question, updates bson.M, authorID primitive.ObjectID) (*models.Question, error) {
+	updated, err := applyQuestionUpdates(existing, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply question updates: %w", err)
+	}
+	changes, err := questionChanges(existing, updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare question versions: %w", err)
+	}
+	if len(changes) == 0 {
+		return existing, nil
+	}
+
+	// Questions written before versioning get their current state stored as the version edited from.
+	// Who last edited them is unknown, so it is credited to their creator.
+	current := questionVersion(existing)
+	baseline := *existing
+	baseline.Version = current
+	err = s.versionRepo.Create(ctx, &models.QuestionVersion{
+		QuestionID: existing.ID,
+		Version:    current,
+		Snapshot:   baseline,
+		AuthorID:   existing.CreatedBy,
+		CreatedAt:  existing.UpdatedAt,
+	})
+	if err != nil && !errors.Is(err, repository.ErrQuestionVersionExists) {
+		return nil, fmt.Errorf("failed to record question version: %w", err)
+	}
+
+	now := time.Now()
+	updated.Version = current + 1
+	updated.UpdatedAt = now
+	version := &models.QuestionVersion{
+		QuestionID: existing.ID,
+		Version:    updated.Version,
+		Snapshot:   *updated,
+		Changes:    changes,
+		AuthorID:   authorID,
+		CreatedAt:  now,
+	}
+	if err := s.versionRepo.Create(ctx, version); err != nil {
+		if errors.Is(err, repository.ErrQuestionVersionExists) {
+			return nil, ErrQuestionChanged
+		}
+		return nil, fmt.Errorf("failed to record question version: %w", err)
+	}
+
+	updates["version"] = updated.Version
+	if err := s.questionRepo.Update(ctx, existing.ID, updates); err != nil {
+		if discardErr := s.versionRepo.Discard(ctx, version.ID); discardErr != nil {
+			slog.ErrorContext(ctx, "Failed to discard question version", "question_id", existing.ID.Hex(), "version", version.Version, "error", discardErr)
+		}
+		return nil, fmt.Errorf("failed to update question: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+
+	return s.questionRepo.GetByID(ctx, existing.ID)
+}
+
+// applyQuestionUpdates returns a copy of the question with the $set updates applied, as it will be stored
+func applyQuestionUpdates(question *models.Question, updates bson.M) (*models.Question, error) {
+	data, err := bson.Marshal(question)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for key, value := range updates {
+		doc[key] = value
+	}
+
+	if data, err = bson.Marshal(doc); err != nil {
+		return nil, err
+	}
+	var updated models.Question
+	if err := bson.Unmarshal(data, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// questionChanges lists the fields that differ between two versions of a question, in field order,
+// with their values as the API shows them
+func questionChanges(before, after *models.Question) ([]models.QuestionFieldChange, error) {
+	from, err := questionFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := questionFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(from)+len(to))
+	for field := range from {
+		fields = append(fields, field)
+	}
+	for field := range to {
+		if _, ok := from[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	var changes []models.QuestionFieldChange
+	for _, field := range fields {
+		if unversionedQuestionFields[field] || reflect.DeepEqual(from[field], to[field]) {
+			continue
+		}
+		changes = append(changes, models.QuestionFieldChange{
+			Field: field,
+			From:  from[field],
+			To:    to[field],
+		})
+	}
+	return changes, nil
+}
+
+func questionFields(question *models.Question) (map[string]interface{}, error) {
+	data, err := json.Marshal(question)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// isQuestionAuthor reports whether the admin writes questions that need approval before publishing
+func (s *questionService) isQuestionAuthor(ctx context.Context, adminID primitive.ObjectID) (bool, error) {
+	admin, err := s.userRepo.GetAdminByID(ctx, adminID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdminNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check admin permissions: %w", err)
+	}
+	return admin.HasPermission(models.PermissionQuestionAuthor), nil
+}
+
+// initialReviewStatus starts content authors' questions as drafts and publishes everyone else's
+func (s *questionService) initialReviewStatus(ctx context.Context, question *models.Question) error {
+	author, err := s.isQuestionAuthor(ctx, question.CreatedBy)
+	if err != nil {
+		return err
+	}
+	if author {
+		question.ReviewStatus = models.QuestionDraft
+		return nil
+	}
+	now := time.Now()
+	question.ReviewStatus = models.QuestionApproved
+	question.ApprovedBy = &question.CreatedBy
+	question.ApprovedAt = &now
+	return nil
+}
+
+// SubmitQuestion sends a draft to the reviewers
+func (s *questionService) SubmitQuestion(ctx context.Context, id primitive.ObjectID) (*models.Question, error) {
+	question, err := s.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if question.Archived {
+		return nil, ErrQuestionArchived
+	}
+	if question.ReviewStatus != models.QuestionDraft {
+		return nil, models.ConflictError("only draft questions can be submitted for review")
+	}
+
+	return s.transitionReview(ctx, id, models.QuestionDraft, bson.M{
+		"review_status": models.QuestionInReview,
+		"submitted_at":  time.Now(),
+	})
+}
+
+// ApproveQuestion publishes a question in review, crediting the reviewer
+func (s *questionService) ApproveQuestion(ctx context.Context, id, reviewerID primitive.ObjectID, req *models.ApproveQuestionRequest) (*models.Question, error) {
+	if _, err := s.reviewableQuestion(ctx, id, reviewerID); err != nil {
+		return nil, err
+	}
+
+	note := strings.TrimSpace(req.Note)
+	now := time.Now()
+	if _, err := s.transitionReview(ctx, id, models.QuestionInReview, bson.M{
+		"review_status": models.QuestionApproved,
+		"review_note":   note,
+		"approved_by":   reviewerID,
+		"approved_at":   now,
+	}); err != nil {
+		return nil, err
+	}
+
+	review := models.QuestionReview{ReviewerID: reviewerID, Note: note, ReviewedAt: now}
+	if err := s.questionRepo.AddReview(ctx, id, review); err != nil {
+		return nil, fmt.Errorf("failed to record review: %w", err)
+	}
+	return s.GetQuestion(ctx, id)
+}
+
+// RejectQuestion returns a question in review to its author as a draft, with the reviewer's note
+func (s *questionService) RejectQuestion(ctx context.Context, id, reviewerID primitive.ObjectID, req *models.RejectQuestionRequest) (*models.Question, error) {
+	if _, err := s.reviewableQuestion(ctx, id, reviewerID); err != nil {
+		return nil, err
+	}
+
+	return s.transitionReview(ctx, id, models.QuestionInReview, bson.M{
+		"review_status": models.QuestionDraft,
+		"review_note":   strings.TrimSpace(req.Note),
+		"submitted_at":  nil,
+	})
+}
+
+// reviewableQuestion loads a question in review, checking the reviewer may decide on it
+func (s *questionService) reviewableQuestion(ctx context.Context, id, reviewerID primitive.ObjectID) (*models.Question, error) {
+	author, err := s.isQuestionAuthor(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	if author {
+		return nil, models.ForbiddenError("publish permission required")
+	}
+
+	question, err := s.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if question.ReviewStatus != models.QuestionInReview {
+		return nil, models.ConflictError("question is not in review")
+	}
+
+	authorID := question.CreatedBy
+	if question.ContributedBy != nil {
+		authorID = *question.ContributedBy
+	}
+	if authorID == reviewerID {
+		return nil, models.ConflictError("authors cannot review their own questions")
+	}
+	return question, nil
+}
+
+func (s *questionService) transitionReview(ctx context.Context, id primitive.ObjectID, from models.QuestionReviewStatus, updates bson.M) (*models.Question, error) {
+	if err := s.questionRepo.TransitionReview(ctx, id, from, updates); err != nil {
+		if errors.Is(err, repository.ErrReviewStatusChanged) {
+			return nil, ErrQuestionChanged
+		}
+		return nil, fmt.Errorf("failed to update review status: %w", err)
+	}
+	s.cache.Invalidate(ctx, utils.CacheQuestionStats)
+	return s.GetQuestion(ctx, id)
+}
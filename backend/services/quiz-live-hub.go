@@ -0,0 +1,67 @@
+package services
+
+import (
+	"sync"
+
+	"backend/models"
+)
+
+// Events queued for a slow connection beyond this are dropped; the next timer tick resyncs it
+const quizLiveBufferSize = 16
+
+// QuizLiveHub fans quiz session events out to every connection watching the session, so each
+// open tab sees answers saved and submissions made in the others. It is in-memory: connections
+// only receive events raised on the instance they are connected to.
+type QuizLiveHub interface {
+	Subscribe(sessionToken string) (<-chan models.QuizLiveEvent, func())
+	Publish(sessionToken string, event models.QuizLiveEvent)
+}
+
+type quizLiveHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan models.QuizLiveEvent]struct{}
+}
+
+func NewQuizLiveHub() QuizLiveHub {
+	return &quizLiveHub{
+		subscribers: make(map[string]map[chan models.QuizLiveEvent]struct{}),
+	}
+}
+
+// Subscribe returns a channel of the session's events and a function that stops the subscription
+func (h *quizLiveHub) Subscribe(sessionToken string) (<-chan models.QuizLiveEvent, func()) {
+	events := make(chan models.QuizLiveEvent, quizLiveBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[sessionToken] == nil {
+		h.subscribers[sessionToken] = make(map[chan models.QuizLiveEvent]struct{})
+	}
+	h.subscribers[sessionToken][events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers[sessionToken], events)
+			if len(h.subscribers[sessionToken]) == 0 {
+				delete(h.subscribers, sessionToken)
+			}
+			close(events)
+		})
+	}
+	return events, unsubscribe
+}
+
+func (h *quizLiveHub) Publish(sessionToken string, event models.QuizLiveEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.subscribers[sessionToken] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
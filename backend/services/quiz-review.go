@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetResultReview lists one of the user's submitted results question by question, with the answer
+// they chose, the correct answer, its explanation and the module sections that teach it. Results are
+// reviewable once their sitting releases them.
+func (s *quizSessionService) GetResultReview(ctx context.Context, userID, resultID primitive.ObjectID) (*models.QuizReview, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if !result.BelongsTo(userID) {
+		return nil, repository.ErrResultNotFound
+	}
+	if withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		return nil, ErrResultsNotReleased
+	}
+	result.HideAnswerKey()
+
+	review := &models.QuizReview{
+		ResultID:             result.ID,
+		QuizType:             result.QuizType,
+		ScorePercentage:      result.ScorePercentage,
+		PendingEssays:        result.PendingEssays,
+		CorrectAnswersHidden: result.CorrectAnswersHidden,
+		Questions:            make([]models.QuestionReviewItem, 0, len(result.QuestionResults)),
+	}
+
+	sections := newRemediationBuilder(s.moduleRepo)
+	for _, qr := range result.QuestionResults {
+		item := models.QuestionReviewItem{
+			QuestionID:    qr.QuestionID,
+			Title:         qr.Title,
+			Type:          qr.Type,
+			Difficulty:    qr.Difficulty,
+			Points:        qr.Points,
+			PointsEarned:  qr.PointsEarned,
+			Options:       qr.Options,
+			ChosenAnswer:  qr.UserAnswer,
+			CorrectAnswer: qr.CorrectAnswer,
+			IsCorrect:     qr.IsCorrect,
+			IsSkipped:     qr.IsSkipped,
+			PendingGrade:  qr.PendingGrade,
+			BlankResults:  qr.BlankResults,
+			Explanation:   qr.Explanation,
+			Modules:       []models.StudySection{},
+		}
+		for _, ref := range qr.Sections {
+			if section, ok := sections.section(ctx, ref); ok {
+				item.Modules = append(item.Modules, section)
+			}
+		}
+		review.Questions = append(review.Questions, item)
+	}
+
+	return review, nil
+}
@@ -4,31 +4,72 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"math/big"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
 
 	"backend/models"
 	"backend/repository"
+	"backend/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var (
+	// ErrSessionExpired is returned once the quiz session's time ran out
+	ErrSessionExpired = models.NewCodedError(http.StatusConflict, models.ErrorCodeSessionExpired, "quiz session has expired")
+	// ErrSessionPaused is returned while an invigilator has the quiz session paused
+	ErrSessionPaused = models.NewCodedError(http.StatusConflict, models.ErrorCodeSessionPaused, "quiz session is paused")
+	// ErrSessionNotActive is returned when the quiz session was already submitted, abandoned or expired
+	ErrSessionNotActive = models.ConflictError("quiz session is not active")
+	// ErrQuestionPoolTooSmall is returned when the question bank cannot fill a quiz
+	ErrQuestionPoolTooSmall = models.NewCodedError(http.StatusServiceUnavailable, models.ErrorCodeQuestionPoolShort, "not enough active questions for this quiz")
+)
+
+// Expired and idle sessions are handled in batches so one cleanup run does not load them all at once
+const expiredSessionBatchSize = 100
+
 type QuizSessionService interface {
 	// Session Management
 	StartQuiz(ctx context.Context, userID primitive.ObjectID, req *models.StartQuizRequest) (*models.StartQuizResponse, error)
+	StartPracticeQuiz(ctx context.Context, userID primitive.ObjectID, questions []*models.Question) (*models.StartQuizResponse, error)
+	StartClassQuiz(ctx context.Context, userID primitive.ObjectID, classQuiz *models.ClassQuiz, questions []*models.Question, req *models.JoinClassQuizRequest) (*models.StartQuizResponse, error)
+	StartCustomQuiz(ctx context.Context, userID primitive.ObjectID, req *models.StartCustomQuizRequest) (*models.StartQuizResponse, error)
+	ListCustomQuizTags(ctx context.Context) ([]models.QuestionTagCount, error)
+	StartWidgetQuiz(ctx context.Context, widget *models.EmbedWidget) (*models.StartQuizResponse, error)
+	StartTeamQuiz(ctx context.Context, sessionID, userID primitive.ObjectID, team *models.Team, req *models.StartTeamQuizRequest) (*models.StartQuizResponse, error)
 	GetSession(ctx context.Context, sessionToken string) (*models.GetSessionResponse, error)
 	SaveAnswer(ctx context.Context, sessionToken string, req *models.SaveAnswerRequest) (*models.SaveAnswerResponse, error)
+	SaveAnswersBatch(ctx context.Context, sessionToken string, req *models.SaveAnswersBatchRequest) (*models.SaveAnswersBatchResponse, error)
 	NavigateToQuestion(ctx context.Context, sessionToken string, req *models.NavigateQuestionRequest) error
 	SkipQuestion(ctx context.Context, sessionToken string, req *models.SkipQuestionRequest) error
-	SubmitQuiz(ctx context.Context, sessionToken string) (*models.SubmitQuizResponse, error)
+	SubmitQuiz(ctx context.Context, userID primitive.ObjectID, sessionToken string) (*models.SubmitQuizResponse, error)
+	ResolveAbandonedSession(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.ResolveAbandonedSessionRequest) (*models.ResolveAbandonedSessionResponse, error)
+	GetMediaManifest(ctx context.Context, sessionToken string) (*models.MediaManifestResponse, error)
+
+	// Proctoring
+	RecordIntegrityEvents(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.RecordIntegrityEventsRequest) (*models.RecordIntegrityEventsResponse, error)
+	GetSessionIntegrity(ctx context.Context, sessionID primitive.ObjectID) (*models.SessionIntegrityResponse, error)
+	PauseSession(ctx context.Context, adminID primitive.ObjectID, adminEmail string, sessionID primitive.ObjectID, req *models.SessionPauseRequest) (*models.GetSessionResponse, error)
+	ResumePausedSession(ctx context.Context, adminID primitive.ObjectID, adminEmail string, sessionID primitive.ObjectID, req *models.SessionPauseRequest) (*models.GetSessionResponse, error)
+
+	// Attempts taken outside a live session (offline exam packages)
+	BuildQuestionSet(ctx context.Context, quizType models.QuizType) ([]models.SessionQuestion, int, *models.QuizTemplate, error)
+	RecordCompletedSession(ctx context.Context, session *models.QuizSession, endTime time.Time) (*models.DetailedQuizResult, error)
 
 	// Utility
 	ResumeSession(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType) (*models.QuizSession, error)
 	GetUserResults(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, limit int) ([]models.DetailedQuizResult, error)
+	GetResult(ctx context.Context, userID, resultID primitive.ObjectID) (*models.QuizResultResponse, error)
+	GetResultReview(ctx context.Context, userID, resultID primitive.ObjectID) (*models.QuizReview, error)
+	GetModuleMastery(ctx context.Context, userID primitive.ObjectID) (*models.ModuleMasteryReport, error)
 	CleanupExpiredSessions(ctx context.Context) (int64, error)
 }
 
@@ -36,23 +77,76 @@ type quizSessionService struct {
 	sessionRepo      repository.QuizSessionRepository
 	questionRepo     repository.QuestionRepository
 	userActivityRepo repository.UserActivityRepository
+	examRepo         repository.ExamRepository
+	templateRepo     repository.QuizTemplateRepository
+	moduleRepo       repository.ModuleRepository
+	transactor       repository.Transactor
+	liveHub          QuizLiveHub
+	resultWebhooks   ResultWebhookService
+	practice         PracticeService
+	settings         SettingsService
+	cache            *utils.Cache
 }
 
 func NewQuizSessionService(
 	sessionRepo repository.QuizSessionRepository,
 	questionRepo repository.QuestionRepository,
 	userActivityRepo repository.UserActivityRepository,
+	examRepo repository.ExamRepository,
+	templateRepo repository.QuizTemplateRepository,
+	moduleRepo repository.ModuleRepository,
+	transactor repository.Transactor,
+	liveHub QuizLiveHub,
+	resultWebhooks ResultWebhookService,
+	practice PracticeService,
+	settings SettingsService,
+	cache *utils.Cache,
 ) QuizSessionService {
 	return &quizSessionService{
 		sessionRepo:      sessionRepo,
 		questionRepo:     questionRepo,
 		userActivityRepo: userActivityRepo,
+		examRepo:         examRepo,
+		templateRepo:     templateRepo,
+		moduleRepo:       moduleRepo,
+		transactor:       transactor,
+		liveHub:          liveHub,
+		resultWebhooks:   resultWebhooks,
+		practice:         practice,
+		settings:         settings,
+		cache:            cache,
 	}
 }
 
 func (s *quizSessionService) StartQuiz(ctx context.Context, userID primitive.ObjectID, req *models.StartQuizRequest) (*models.StartQuizResponse, error) {
+	if req.Lang != "" && !models.IsQuestionLanguage(req.Lang) {
+		return nil, models.BadRequestError("unsupported language")
+	}
+
+	template, err := s.resolveTemplate(ctx, req.TemplateID, req.QuizType)
+	if err != nil {
+		return nil, err
+	}
+	quizType := template.QuizType
+
+	// Guests get the quick feedback quiz only; mock tests need an account
+	if req.IsGuest && quizType != models.TimeQuiz {
+		return nil, models.NewCodedError(http.StatusForbidden, models.ErrorCodeAccountRequired, "guests can only take time quizzes")
+	}
+
+	// Students seated in a sitting that requires it must start from the lockdown browser
+	sitting, err := s.getActiveSitting(ctx, userID, quizType, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to check exam sittings: %w", err)
+	}
+	if sitting != nil && sitting.RequireLockdownBrowser {
+		if err := utils.VerifyLockdownHandshake(sitting.LockdownKey, req.Lockdown, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if user has an active session for this quiz type
-	existingSession, err := s.sessionRepo.GetActiveSessionByUser(ctx, userID, req.QuizType)
+	existingSession, err := s.sessionRepo.GetActiveSessionByUser(ctx, userID, quizType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing session: %w", err)
 	}
@@ -61,12 +155,15 @@ func (s *quizSessionService) StartQuiz(ctx context.Context, userID primitive.Obj
 		// Calculate remaining time
 		timeRemaining := s.calculateTimeRemaining(existingSession)
 		if timeRemaining <= 0 {
-			// Session expired, mark as timeout
-			err = s.sessionRepo.MarkSessionCompleted(ctx, existingSession.ID, time.Now())
-			if err != nil {
-				return nil, fmt.Errorf("failed to mark expired session: %w", err)
+			// Session expired; score it before starting a new one
+			if _, err := s.autoSubmit(ctx, existingSession, ""); err != nil {
+				return nil, fmt.Errorf("failed to submit expired session: %w", err)
 			}
 		} else {
+			if err := s.verifySessionLockdown(ctx, existingSession, req.Lockdown); err != nil {
+				return nil, err
+			}
+
 			// Return existing session
 			resumeToken := existingSession.SessionToken
 			return &models.StartQuizResponse{
@@ -77,13 +174,52 @@ func (s *quizSessionService) StartQuiz(ctx context.Context, userID primitive.Obj
 		}
 	}
 
-	// Get quiz configuration
-	config := models.GetQuizConfig(req.QuizType)
+	if template.MaxAttempts > 0 {
+		attempts, err := s.sessionRepo.CountTemplateAttempts(ctx, userID, template.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check attempts: %w", err)
+		}
+		if attempts >= int64(template.MaxAttempts) {
+			return nil, models.NewCodedError(http.StatusForbidden, models.ErrorCodeAttemptsExhausted, "no attempts left for this quiz")
+		}
+	}
 
-	// Select and prepare questions
-	questions, totalPoints, err := s.selectQuestions(ctx, req.QuizType, config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to select questions: %w", err)
+	// Exam sittings are scheduled by their instructors, so only self-started attempts wait out the cooldown
+	if template.RetakeCooldownMinutes > 0 && sitting == nil {
+		lastStart, err := s.sessionRepo.GetLastAttemptStart(ctx, userID, template.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check retake cooldown: %w", err)
+		}
+		if lastStart != nil {
+			nextEligible := lastStart.Add(time.Duration(template.RetakeCooldownMinutes) * time.Minute)
+			if time.Now().Before(nextEligible) {
+				return nil, models.NewRetryError(models.ErrorCodeRetakeCooldown, "quiz cannot be retaken until its retake cooldown has passed", nextEligible)
+			}
+		}
+	}
+
+	// Select and prepare questions; adaptive practice draws them one at a time, starting with one
+	var questions []models.SessionQuestion
+	var totalPoints int
+	var adaptive *models.AdaptiveState
+	totalQuestions := 0
+	if quizType == models.AdaptivePractice {
+		adaptive = models.NewAdaptiveState()
+		first, err := s.drawAdaptiveQuestion(ctx, template.Scoring, adaptive, nil, req.Lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select questions: %w", err)
+		}
+		if first == nil {
+			return nil, fmt.Errorf("failed to select questions: %w: no questions available", ErrQuestionPoolTooSmall)
+		}
+		questions, totalPoints = []models.SessionQuestion{*first}, first.Points
+		totalQuestions = template.TotalQuestions()
+	} else {
+		questions, totalPoints, err = s.selectQuestions(ctx, template, req.Lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select questions: %w", err)
+		}
+		totalQuestions = len(questions)
 	}
 
 	// Generate session token
@@ -95,20 +231,38 @@ func (s *quizSessionService) StartQuiz(ctx context.Context, userID primitive.Obj
 	// Create quiz session
 	session := &models.QuizSession{
 		UserID:           userID,
-		QuizType:         req.QuizType,
+		QuizType:         quizType,
 		SessionToken:     sessionToken,
-		TotalQuestions:   len(questions),
+		TotalQuestions:   totalQuestions,
 		MaxPoints:        totalPoints,
-		TimeLimitMinutes: config.TimeLimitMinutes,
+		TimeLimitMinutes: template.TimeLimitMinutes,
 		Questions:        questions,
 		StartTime:        time.Now(),
-		TimeRemaining:    int64(config.TimeLimitMinutes * 60), // Convert to seconds
+		TimeRemaining:    int64(template.TimeLimitMinutes * 60), // Convert to seconds
+		Adaptive:         adaptive,
+		Language:         req.Lang,
+		StartIP:          req.IPAddress,
+		StartUserAgent:   req.UserAgent,
+		IsGuest:          req.IsGuest,
+		TemplateName:     template.Name,
+		Scoring:          &template.Scoring,
 		CurrentQuestion:  0,
 		AnsweredCount:    0,
 		SkippedCount:     0,
 		Status:           models.QuizInProgress,
 		IsSubmitted:      false,
 	}
+	if !template.IsBuiltin {
+		session.TemplateID = &template.ID
+		session.HideCorrectAnswers = template.HideCorrectAnswers
+		session.ModuleIDs = template.ModuleIDs
+	}
+	if sitting != nil {
+		session.ExamSittingID = &sitting.ID
+		if sitting.RequireLockdownBrowser {
+			session.LockdownSittingID = &sitting.ID
+		}
+	}
 
 	err = s.sessionRepo.CreateSession(ctx, session)
 	if err != nil {
@@ -122,6 +276,216 @@ func (s *quizSessionService) StartQuiz(ctx context.Context, userID primitive.Obj
 	}, nil
 }
 
+// StartPracticeQuiz starts a practice session from a caller-provided set of questions (e.g. bookmarks)
+func (s *quizSessionService) StartPracticeQuiz(ctx context.Context, userID primitive.ObjectID, questions []*models.Question) (*models.StartQuizResponse, error) {
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("no questions available for practice")
+	}
+
+	// Resume an unfinished practice session instead of stacking new ones
+	existingSession, err := s.sessionRepo.GetActiveSessionByUser(ctx, userID, models.Practice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing session: %w", err)
+	}
+
+	if existingSession != nil {
+		if s.calculateTimeRemaining(existingSession) > 0 {
+			return &models.StartQuizResponse{
+				Session:     *existingSession,
+				Message:     "Resumed existing practice session",
+				ResumeToken: existingSession.SessionToken,
+			}, nil
+		}
+
+		if _, err := s.autoSubmit(ctx, existingSession, ""); err != nil {
+			return nil, fmt.Errorf("failed to submit expired session: %w", err)
+		}
+	}
+
+	template := models.DefaultQuizTemplate(models.Practice)
+
+	var sessionQuestions []models.SessionQuestion
+	totalPoints := 0
+	for _, q := range questions {
+		sessionQuestion := s.convertQuestionToSessionQuestion(q)
+		sessionQuestion.Points = template.Scoring.PointsFor(q)
+		totalPoints += sessionQuestion.Points
+		sessionQuestions = append(sessionQuestions, sessionQuestion)
+	}
+	s.shuffleSessionQuestions(sessionQuestions)
+
+	sessionToken, err := generateSessionToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	session := &models.QuizSession{
+		UserID:           userID,
+		QuizType:         models.Practice,
+		SessionToken:     sessionToken,
+		TotalQuestions:   len(sessionQuestions),
+		MaxPoints:        totalPoints,
+		TimeLimitMinutes: template.TimeLimitMinutes,
+		Questions:        sessionQuestions,
+		StartTime:        time.Now(),
+		TimeRemaining:    int64(template.TimeLimitMinutes * 60),
+		TemplateName:     template.Name,
+		Scoring:          &template.Scoring,
+		Status:           models.QuizInProgress,
+	}
+
+	err = s.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &models.StartQuizResponse{
+		Session:     *session,
+		Message:     "Practice session started successfully",
+		ResumeToken: sessionToken,
+	}, nil
+}
+
+// StartClassQuiz starts a student's session in a class quiz they joined, with the quiz's questions
+// in their own order. Students only take a class quiz once; joining again resumes the session.
+func (s *quizSessionService) StartClassQuiz(ctx context.Context, userID primitive.ObjectID, classQuiz *models.ClassQuiz, questions []*models.Question, req *models.JoinClassQuizRequest) (*models.StartQuizResponse, error) {
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("no questions available for this quiz")
+	}
+
+	existingSession, err := s.sessionRepo.GetActiveSessionByUser(ctx, userID, models.QuickQuiz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing session: %w", err)
+	}
+
+	if existingSession != nil {
+		if s.calculateTimeRemaining(existingSession) > 0 {
+			if existingSession.ClassQuizID == nil || *existingSession.ClassQuizID != classQuiz.ID {
+				return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeQuizInProgress, "finish your current quick quiz before joining another")
+			}
+			return &models.StartQuizResponse{
+				Session:     *existingSession,
+				Message:     "Resumed existing quick quiz session",
+				ResumeToken: existingSession.SessionToken,
+			}, nil
+		}
+
+		if _, err := s.autoSubmit(ctx, existingSession, ""); err != nil {
+			return nil, fmt.Errorf("failed to submit expired session: %w", err)
+		}
+	}
+
+	scoring := models.QuizScoring{PointsSource: models.PointsFromQuestion}
+
+	var sessionQuestions []models.SessionQuestion
+	totalPoints := 0
+	for _, q := range questions {
+		sessionQuestion := s.convertQuestionToSessionQuestion(q)
+		sessionQuestion.Points = scoring.PointsFor(q)
+		totalPoints += sessionQuestion.Points
+		sessionQuestions = append(sessionQuestions, sessionQuestion)
+	}
+	s.shuffleSessionQuestions(sessionQuestions)
+
+	sessionToken, err := generateSessionToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	session := &models.QuizSession{
+		UserID:           userID,
+		QuizType:         models.QuickQuiz,
+		SessionToken:     sessionToken,
+		TotalQuestions:   len(sessionQuestions),
+		MaxPoints:        totalPoints,
+		TimeLimitMinutes: classQuiz.TimeLimitMinutes,
+		Questions:        sessionQuestions,
+		StartTime:        time.Now(),
+		TimeRemaining:    int64(classQuiz.TimeLimitMinutes * 60),
+		StartIP:          req.IPAddress,
+		StartUserAgent:   req.UserAgent,
+		ClassQuizID:      &classQuiz.ID,
+		DisplayName:      strings.TrimSpace(req.DisplayName),
+		IsGuest:          req.IsGuest,
+		TemplateName:     classQuiz.Title,
+		Scoring:          &scoring,
+		Status:           models.QuizInProgress,
+	}
+
+	err = s.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &models.StartQuizResponse{
+		Session:     *session,
+		Message:     "Joined quick quiz successfully",
+		ResumeToken: sessionToken,
+	}, nil
+}
+
+// StartTeamQuiz creates a team's shared session with the ID the team has already claimed for it. The
+// team's current members are credited with the result and its captain is the one who submits.
+func (s *quizSessionService) StartTeamQuiz(ctx context.Context, sessionID, userID primitive.ObjectID, team *models.Team, req *models.StartTeamQuizRequest) (*models.StartQuizResponse, error) {
+	template, err := s.resolveTemplate(ctx, req.TemplateID, req.QuizType)
+	if err != nil {
+		return nil, err
+	}
+
+	captain := team.Captain()
+	if captain == nil {
+		return nil, errors.New("team has no captain")
+	}
+
+	questions, totalPoints, err := s.selectQuestions(ctx, template, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to select questions: %w", err)
+	}
+
+	sessionToken, err := generateSessionToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	session := &models.QuizSession{
+		ID:               sessionID,
+		UserID:           userID,
+		QuizType:         models.TeamQuiz,
+		SessionToken:     sessionToken,
+		TotalQuestions:   len(questions),
+		MaxPoints:        totalPoints,
+		TimeLimitMinutes: template.TimeLimitMinutes,
+		Questions:        questions,
+		StartTime:        time.Now(),
+		TimeRemaining:    int64(template.TimeLimitMinutes * 60),
+		StartIP:          req.IPAddress,
+		StartUserAgent:   req.UserAgent,
+		TeamID:           &team.ID,
+		TeamName:         team.Name,
+		TeamMemberIDs:    team.MemberIDs(),
+		TeamCaptainID:    &captain.UserID,
+		TemplateName:     template.Name,
+		Scoring:          &template.Scoring,
+		Status:           models.QuizInProgress,
+	}
+	if !template.IsBuiltin {
+		session.TemplateID = &template.ID
+		session.HideCorrectAnswers = template.HideCorrectAnswers
+		session.ModuleIDs = template.ModuleIDs
+	}
+
+	err = s.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &models.StartQuizResponse{
+		Session:     *session,
+		Message:     "Team quiz started successfully",
+		ResumeToken: sessionToken,
+	}, nil
+}
+
 func (s *quizSessionService) GetSession(ctx context.Context, sessionToken string) (*models.GetSessionResponse, error) {
 	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
 	if err != nil {
@@ -132,10 +496,9 @@ func (s *quizSessionService) GetSession(ctx context.Context, sessionToken string
 	isExpired := timeRemaining <= 0
 
 	if isExpired && session.Status == models.QuizInProgress {
-		// Mark session as expired
-		err = s.sessionRepo.MarkSessionCompleted(ctx, session.ID, time.Now())
-		if err != nil {
-			return nil, fmt.Errorf("failed to mark session expired: %w", err)
+		// Submit on the student's behalf if the cleanup job has not got to it yet
+		if _, err := s.autoSubmit(ctx, session, ""); err != nil {
+			return nil, fmt.Errorf("failed to submit expired session: %w", err)
 		}
 		session.Status = models.QuizTimeout
 	}
@@ -147,6 +510,51 @@ func (s *quizSessionService) GetSession(ctx context.Context, sessionToken string
 	}, nil
 }
 
+// GetMediaManifest lists the media a session's questions and options use, deduplicated in question order,
+// so clients can prefetch everything instead of loading images as each question is shown
+func (s *quizSessionService) GetMediaManifest(ctx context.Context, sessionToken string) (*models.MediaManifestResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	items := []models.MediaManifestItem{}
+	positions := make(map[string]int)
+	add := func(url string, mediaType models.MediaType, questionIndex int) {
+		if url == "" {
+			return
+		}
+		pos, seen := positions[url]
+		if !seen {
+			positions[url] = len(items)
+			items = append(items, models.MediaManifestItem{URL: url, Type: mediaType, QuestionIndexes: []int{questionIndex}})
+			return
+		}
+		indexes := items[pos].QuestionIndexes
+		if indexes[len(indexes)-1] != questionIndex {
+			items[pos].QuestionIndexes = append(indexes, questionIndex)
+		}
+	}
+
+	for i, question := range session.Questions {
+		for _, media := range question.Media {
+			add(media.URL, media.Type, i)
+		}
+		for _, option := range question.Options {
+			add(option.ImageURL, models.MediaImage, i)
+		}
+		if question.Audio != nil {
+			add(question.Audio.URL, models.MediaAudio, i)
+		}
+	}
+
+	return &models.MediaManifestResponse{
+		SessionToken: session.SessionToken,
+		Items:        items,
+		TotalItems:   len(items),
+	}, nil
+}
+
 func (s *quizSessionService) SaveAnswer(ctx context.Context, sessionToken string, req *models.SaveAnswerRequest) (*models.SaveAnswerResponse, error) {
 	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
 	if err != nil {
@@ -154,18 +562,29 @@ func (s *quizSessionService) SaveAnswer(ctx context.Context, sessionToken string
 	}
 
 	if session.Status != models.QuizInProgress {
-		return nil, fmt.Errorf("quiz session is not active")
+		return nil, ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return nil, ErrSessionPaused
+	}
+
+	if err := s.verifySessionLockdown(ctx, session, req.Lockdown); err != nil {
+		return nil, err
 	}
 
 	// Check if time expired
 	timeRemaining := s.calculateTimeRemaining(session)
 	if timeRemaining <= 0 {
-		return nil, fmt.Errorf("quiz session has expired")
+		return nil, ErrSessionExpired
 	}
 
 	// Validate question index
 	if req.QuestionIndex < 0 || req.QuestionIndex >= len(session.Questions) {
-		return nil, fmt.Errorf("invalid question index")
+		return nil, models.BadRequestError("invalid question index")
+	}
+
+	if session.Adaptive != nil {
+		return s.saveAdaptiveAnswer(ctx, session, req.QuestionIndex, req.Answer, req.TimeSpent)
 	}
 
 	// Update question answer in database
@@ -173,6 +592,7 @@ func (s *quizSessionService) SaveAnswer(ctx context.Context, sessionToken string
 	if err != nil {
 		return nil, fmt.Errorf("failed to save answer: %w", err)
 	}
+	s.publishLive(sessionToken, models.QuizLiveEvent{Type: models.QuizLiveAnswerSaved, QuestionIndex: &req.QuestionIndex})
 
 	// For TimeQuiz, provide immediate feedback
 	response := &models.SaveAnswerResponse{
@@ -180,21 +600,119 @@ func (s *quizSessionService) SaveAnswer(ctx context.Context, sessionToken string
 		Message: "Answer saved successfully",
 	}
 
-	if session.QuizType == models.TimeQuiz {
+	// No immediate feedback while the sitting's results are embargoed
+	withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, session.ExamSittingID)
+	if session.QuizType == models.TimeQuiz && !withheld {
 		question := session.Questions[req.QuestionIndex]
 		isCorrect := s.checkAnswer(question, req.Answer)
-		pointsEarned := 0
-		if isCorrect {
-			pointsEarned = question.Points
-		}
+		pointsEarned := answerPoints(question, req.Answer, isCorrect)
 
-		response.IsCorrect = isCorrect
-		response.CorrectAnswer = question.CorrectAnswers
-		response.PointsEarned = pointsEarned
+		response.CorrectAnswer = sessionCorrectAnswer(question)
 
-		// For essay questions, include sample answer if available
-		if question.Type == models.Essay && question.SampleAnswer != "" {
+		// Essays are graded by an admin later; the sample answer is the only feedback
+		if question.Type == models.Essay {
 			response.SampleAnswer = question.SampleAnswer
+		} else {
+			response.IsCorrect = isCorrect
+			response.PointsEarned = pointsEarned
+		}
+	}
+
+	return response, nil
+}
+
+// SaveAnswersBatch merges answers the client buffered while its connection was down. For each
+// question only the most recently given answer is kept, whether it arrived online or in a batch,
+// so a batch can be retried safely until the client sees it acknowledged.
+func (s *quizSessionService) SaveAnswersBatch(ctx context.Context, sessionToken string, req *models.SaveAnswersBatchRequest) (*models.SaveAnswersBatchResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if session.Status != models.QuizInProgress {
+		return nil, ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return nil, ErrSessionPaused
+	}
+
+	if err := s.verifySessionLockdown(ctx, session, req.Lockdown); err != nil {
+		return nil, err
+	}
+
+	timeRemaining := s.calculateTimeRemaining(session)
+	if timeRemaining <= 0 {
+		return nil, ErrSessionExpired
+	}
+
+	response := &models.SaveAnswersBatchResponse{
+		Results:       make([]models.BatchAnswerResult, len(req.Answers)),
+		TimeRemaining: timeRemaining,
+	}
+
+	// A client clock running ahead must not let its answers outrank ones saved later
+	now := time.Now()
+	answeredAt := make([]time.Time, len(req.Answers))
+	latest := make(map[int]int) // question index -> position of its latest answer in the batch
+	for i, item := range req.Answers {
+		result := &response.Results[i]
+		result.QuestionIndex = item.QuestionIndex
+		result.ClientTimestamp = item.ClientTimestamp
+
+		if item.QuestionIndex < 0 || item.QuestionIndex >= len(session.Questions) {
+			result.Status, result.Error = models.BatchAnswerRejected, "invalid question index"
+			continue
+		}
+		if session.Adaptive != nil {
+			// Each adaptive answer decides the next question, which needs the server
+			result.Status, result.Error = models.BatchAnswerRejected, "adaptive practice answers cannot be saved offline"
+			continue
+		}
+		if item.ClientTimestamp.Before(session.StartTime) {
+			result.Status, result.Error = models.BatchAnswerRejected, "answer was given before the session started"
+			continue
+		}
+
+		answeredAt[i] = item.ClientTimestamp
+		if answeredAt[i].After(now) {
+			answeredAt[i] = now
+		}
+		if j, seen := latest[item.QuestionIndex]; !seen || !answeredAt[i].Before(answeredAt[j]) {
+			latest[item.QuestionIndex] = i
+		}
+	}
+
+	for i, item := range req.Answers {
+		result := &response.Results[i]
+		if result.Status == models.BatchAnswerRejected {
+			continue
+		}
+		if latest[item.QuestionIndex] != i {
+			result.Status = models.BatchAnswerStale
+			continue
+		}
+
+		applied, err := s.sessionRepo.MergeQuestionAnswer(ctx, session.ID, item.QuestionIndex, item.Answer, item.TimeSpent, answeredAt[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to save answers: %w", err)
+		}
+		if !applied {
+			result.Status = models.BatchAnswerStale
+			continue
+		}
+		result.Status = models.BatchAnswerApplied
+		s.publishLive(sessionToken, models.QuizLiveEvent{Type: models.QuizLiveAnswerSaved, QuestionIndex: &result.QuestionIndex})
+	}
+
+	for _, result := range response.Results {
+		switch result.Status {
+		case models.BatchAnswerApplied:
+			response.Applied++
+		case models.BatchAnswerStale:
+			response.Stale++
+		case models.BatchAnswerRejected:
+			response.Rejected++
 		}
 	}
 
@@ -208,12 +726,15 @@ func (s *quizSessionService) NavigateToQuestion(ctx context.Context, sessionToke
 	}
 
 	if session.Status != models.QuizInProgress {
-		return fmt.Errorf("quiz session is not active")
+		return ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return ErrSessionPaused
 	}
 
 	// Validate question index
 	if req.QuestionIndex < 0 || req.QuestionIndex >= len(session.Questions) {
-		return fmt.Errorf("invalid question index")
+		return models.BadRequestError("invalid question index")
 	}
 
 	// Update current question
@@ -231,6 +752,7 @@ func (s *quizSessionService) NavigateToQuestion(ctx context.Context, sessionToke
 	if err != nil {
 		return fmt.Errorf("failed to update session progress: %w", err)
 	}
+	s.publishLive(sessionToken, models.QuizLiveEvent{Type: models.QuizLiveNavigated, QuestionIndex: &req.QuestionIndex})
 
 	return nil
 }
@@ -242,18 +764,27 @@ func (s *quizSessionService) SkipQuestion(ctx context.Context, sessionToken stri
 	}
 
 	if session.Status != models.QuizInProgress {
-		return fmt.Errorf("quiz session is not active")
+		return ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return ErrSessionPaused
 	}
 
 	// Check if time expired
 	timeRemaining := s.calculateTimeRemaining(session)
 	if timeRemaining <= 0 {
-		return fmt.Errorf("quiz session has expired")
+		return ErrSessionExpired
 	}
 
 	// Validate question index
 	if req.QuestionIndex < 0 || req.QuestionIndex >= len(session.Questions) {
-		return fmt.Errorf("invalid question index")
+		return models.BadRequestError("invalid question index")
+	}
+
+	// A skipped adaptive question counts as a miss and draws the next one
+	if session.Adaptive != nil {
+		_, err := s.saveAdaptiveAnswer(ctx, session, req.QuestionIndex, nil, req.TimeSpent)
+		return err
 	}
 
 	// Skip question in database
@@ -261,50 +792,130 @@ func (s *quizSessionService) SkipQuestion(ctx context.Context, sessionToken stri
 	if err != nil {
 		return fmt.Errorf("failed to skip question: %w", err)
 	}
+	s.publishLive(sessionToken, models.QuizLiveEvent{Type: models.QuizLiveAnswerSaved, QuestionIndex: &req.QuestionIndex, Skipped: true})
 
 	return nil
 }
 
-func (s *quizSessionService) SubmitQuiz(ctx context.Context, sessionToken string) (*models.SubmitQuizResponse, error) {
+func (s *quizSessionService) SubmitQuiz(ctx context.Context, userID primitive.ObjectID, sessionToken string) (*models.SubmitQuizResponse, error) {
+	ctx, span := utils.StartSpan(ctx, "QuizSessionService.SubmitQuiz")
+	defer span.End()
+
 	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
+	span.SetAttributes(
+		attribute.String("quiz.session_id", session.ID.Hex()),
+		attribute.String("quiz.type", string(session.QuizType)),
+		attribute.Int("quiz.questions", len(session.Questions)),
+	)
 
 	if session.Status != models.QuizInProgress {
-		return nil, fmt.Errorf("quiz session is not active")
+		return nil, ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return nil, ErrSessionPaused
 	}
 
-	// Mark session as completed
-	endTime := time.Now()
-	err = s.sessionRepo.MarkSessionCompleted(ctx, session.ID, endTime)
-	if err != nil {
-		return nil, fmt.Errorf("failed to mark session completed: %w", err)
+	// The whole team answers together, but one member hands it in
+	if session.TeamCaptainID != nil && *session.TeamCaptainID != userID {
+		return nil, models.ForbiddenError("only the team captain can submit this quiz")
 	}
 
-	// Calculate results
-	result, err := s.calculateResults(session, endTime)
+	var result *models.DetailedQuizResult
+	message := "Quiz submitted successfully"
+	if s.calculateTimeRemaining(session) <= 0 {
+		// Time ran out before the submission arrived; score the answers saved by the deadline
+		result, err = s.autoSubmit(ctx, session, "")
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, ErrSessionNotActive
+		}
+		message = "Time ran out, so the quiz was submitted automatically"
+	} else {
+		// Mark session as completed along with saving its results
+		endTime := time.Now()
+		result, err = s.saveResults(ctx, session, endTime, func(ctx context.Context) (bool, error) {
+			return s.sessionRepo.MarkSessionCompleted(ctx, session.ID, endTime)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			// Another submission or the timeout job finished it first
+			return nil, ErrSessionNotActive
+		}
+		s.publishLive(sessionToken, models.QuizLiveEvent{
+			Type:     models.QuizLiveSubmitted,
+			Status:   models.QuizCompleted,
+			Reason:   models.SubmitReasonStudent,
+			ResultID: result.ID.Hex(),
+		})
+	}
+
+	response := &models.SubmitQuizResponse{
+		Result:  *result,
+		Message: message,
+	}
+	if withheld, releaseAt := newResultReleaseChecker(s.examRepo).withheld(ctx, session.ExamSittingID); withheld {
+		response.Result.Withhold(releaseAt)
+		response.Message = message + ". Results will be available once they are released"
+	} else {
+		response.Result.HideAnswerKey()
+		newRemediationBuilder(s.moduleRepo).applyOne(ctx, &response.Result)
+		response.Comparison = s.compareScore(ctx, &response.Result)
+	}
+	return response, nil
+}
+
+// BuildQuestionSet selects questions from a quiz type's default template the same way StartQuiz does,
+// without creating a session. It returns the template so callers can apply its time limit and scoring.
+func (s *quizSessionService) BuildQuestionSet(ctx context.Context, quizType models.QuizType) ([]models.SessionQuestion, int, *models.QuizTemplate, error) {
+	template, err := s.resolveTemplate(ctx, "", quizType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to calculate results: %w", err)
+		return nil, 0, nil, err
 	}
 
-	// Save detailed result
-	err = s.sessionRepo.CreateDetailedResult(ctx, result)
+	questions, totalPoints, err := s.selectQuestions(ctx, template, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to save detailed result: %w", err)
+		return nil, 0, nil, fmt.Errorf("failed to select questions: %w", err)
 	}
 
-	// Also create simple QuizResult for existing user activity tracking
-	simpleResult := s.convertToSimpleQuizResult(result, session.UserID)
-	_, err = s.userActivityRepo.CreateQuizResult(ctx, simpleResult)
+	return questions, totalPoints, template, nil
+}
+
+// RecordCompletedSession stores an attempt whose answers were collected elsewhere and scores it like SubmitQuiz
+func (s *quizSessionService) RecordCompletedSession(ctx context.Context, session *models.QuizSession, endTime time.Time) (*models.DetailedQuizResult, error) {
+	sessionToken, err := generateSessionToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to save simple result: %w", err)
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
 	}
 
-	return &models.SubmitQuizResponse{
-		Result:  *result,
-		Message: "Quiz submitted successfully",
-	}, nil
+	answeredCount := 0
+	skippedCount := 0
+	for _, q := range session.Questions {
+		if q.IsAnswered {
+			answeredCount++
+		} else if q.IsSkipped {
+			skippedCount++
+		}
+	}
+
+	session.SessionToken = sessionToken
+	session.TotalQuestions = len(session.Questions)
+	session.AnsweredCount = answeredCount
+	session.SkippedCount = skippedCount
+	session.Status = models.QuizInProgress
+
+	return s.saveResults(ctx, session, endTime, func(ctx context.Context) (bool, error) {
+		if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+			return false, fmt.Errorf("failed to create session: %w", err)
+		}
+		return s.sessionRepo.MarkSessionCompleted(ctx, session.ID, endTime)
+	})
 }
 
 func (s *quizSessionService) ResumeSession(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType) (*models.QuizSession, error) {
@@ -312,334 +923,524 @@ func (s *quizSessionService) ResumeSession(ctx context.Context, userID primitive
 }
 
 func (s *quizSessionService) GetUserResults(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, limit int) ([]models.DetailedQuizResult, error) {
-	return s.sessionRepo.GetUserDetailedResults(ctx, userID, quizType, limit)
+	results, err := s.sessionRepo.GetUserDetailedResults(ctx, userID, quizType, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	newResultReleaseChecker(s.examRepo).applyDetailed(ctx, results)
+	for i := range results {
+		results[i].HideAnswerKey()
+	}
+	newRemediationBuilder(s.moduleRepo).apply(ctx, results)
+	return results, nil
 }
 
 func (s *quizSessionService) CleanupExpiredSessions(ctx context.Context) (int64, error) {
-	// Mark sessions that have exceeded their time limit as timeout
-	expiredBefore := time.Now().Add(-2 * time.Hour) // Sessions older than 2 hours are expired
-	timeoutCount, err := s.sessionRepo.CleanupExpiredSessions(ctx, expiredBefore)
-	if err != nil {
-		return 0, fmt.Errorf("failed to cleanup expired sessions: %w", err)
+	// Submit sessions that have exceeded their time limit so students get a score without returning
+	var timeoutCount int64
+	for {
+		sessions, err := s.sessionRepo.GetExpiredSessions(ctx, time.Now(), expiredSessionBatchSize)
+		if err != nil {
+			return timeoutCount, fmt.Errorf("failed to cleanup expired sessions: %w", err)
+		}
+
+		for i := range sessions {
+			result, err := s.autoSubmit(ctx, &sessions[i], models.AbandonTimeout)
+			if err != nil {
+				return timeoutCount, fmt.Errorf("failed to submit expired session %s: %w", sessions[i].ID.Hex(), err)
+			}
+			if result != nil {
+				timeoutCount++
+			}
+		}
+
+		if len(sessions) < expiredSessionBatchSize {
+			break
+		}
 	}
 
 	// Mark sessions that haven't been updated in a while as abandoned
-	abandonedCount, err := s.sessionRepo.CleanupAbandonedSessions(ctx, 1*time.Hour)
+	abandonedCount, err := s.abandonIdleSessions(ctx, time.Now().Add(-models.AbandonedSessionIdleTime))
 	if err != nil {
-		return timeoutCount, fmt.Errorf("failed to cleanup abandoned sessions: %w", err)
+		return timeoutCount + abandonedCount, fmt.Errorf("failed to cleanup abandoned sessions: %w", err)
 	}
 
 	return timeoutCount + abandonedCount, nil
 }
 
-// Private helper methods
+// abandonIdleSessions abandons in-progress sessions with no activity since idleBefore. Sessions the
+// student left behind by starting another quiz are forced out; the rest were left idle.
+func (s *quizSessionService) abandonIdleSessions(ctx context.Context, idleBefore time.Time) (int64, error) {
+	var count int64
+	for {
+		sessions, err := s.sessionRepo.GetIdleSessions(ctx, idleBefore, expiredSessionBatchSize)
+		if err != nil {
+			return count, err
+		}
 
-func (s *quizSessionService) selectQuestions(ctx context.Context, quizType models.QuizType, config models.QuizConfig) ([]models.SessionQuestion, int, error) {
-	var questions []models.SessionQuestion
-	totalPoints := 0
+		for _, session := range sessions {
+			reason := models.AbandonInactivity
+			moved, err := s.sessionRepo.HasSessionStartedSince(ctx, session.UserID, session.ID, session.UpdatedAt)
+			if err != nil {
+				return count, err
+			}
+			if moved {
+				reason = models.AbandonForced
+			}
 
-	switch quizType {
-	case models.TimeQuiz:
-		// Fixed distribution for TimeQuiz
-		easyQuestions, err := s.getQuestionsByDifficulty(ctx, models.Easy, config.EasyQuestions)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get easy questions: %w", err)
+			abandoned, err := s.sessionRepo.MarkSessionAbandoned(ctx, session.ID, session.UpdatedAt, reason)
+			if err != nil {
+				return count, err
+			}
+			if abandoned {
+				count++
+			}
 		}
 
-		mediumQuestions, err := s.getQuestionsByDifficulty(ctx, models.Medium, config.MediumQuestions)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get medium questions: %w", err)
+		if len(sessions) < expiredSessionBatchSize {
+			return count, nil
 		}
+	}
+}
 
-		hardQuestions, err := s.getQuestionsByDifficulty(ctx, models.Hard, config.HardQuestions)
+// ResolveAbandonedSession lets a student resume an abandoned session that still has time left, or
+// discard an unfinished one so it is never scored. Team sessions are resolved by their captain.
+func (s *quizSessionService) ResolveAbandonedSession(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.ResolveAbandonedSessionRequest) (*models.ResolveAbandonedSessionResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsTakenBy(userID) {
+		return nil, repository.ErrSessionNotFound
+	}
+	if session.TeamCaptainID != nil && *session.TeamCaptainID != userID {
+		return nil, models.ForbiddenError("only the team captain can resume or discard this quiz")
+	}
+
+	response := &models.ResolveAbandonedSessionResponse{}
+	if req.Action == "discard" {
+		discarded, err := s.sessionRepo.DiscardSession(ctx, session.ID)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to get hard questions: %w", err)
+			return nil, err
 		}
-
-		// Convert to session questions using original question points
-		for _, q := range easyQuestions {
-			questions = append(questions, s.convertQuestionToSessionQuestion(q))
+		if !discarded {
+			return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeSessionFinished, "quiz session is already finished")
 		}
-		for _, q := range mediumQuestions {
-			questions = append(questions, s.convertQuestionToSessionQuestion(q))
+		s.publishLive(session.SessionToken, models.QuizLiveEvent{
+			Type:   models.QuizLiveSubmitted,
+			Status: models.QuizAbandoned,
+			Reason: models.SubmitReasonDiscarded,
+		})
+		response.Message = "Quiz session discarded"
+	} else {
+		if session.Status != models.QuizAbandoned || session.AbandonReason == models.AbandonDiscarded {
+			return nil, models.ConflictError("quiz session is not resumable")
+		}
+		if s.calculateTimeRemaining(session) <= 0 {
+			return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeSessionExpired, "quiz time has expired")
 		}
-		for _, q := range hardQuestions {
-			questions = append(questions, s.convertQuestionToSessionQuestion(q))
+		if err := s.verifySessionLockdown(ctx, session, req.Lockdown); err != nil {
+			return nil, err
 		}
 
-		// Calculate total points from actual question values
-		totalPoints = 0
-		for _, q := range questions {
-			totalPoints += q.Points
+		active, err := s.sessionRepo.GetActiveSessionByUser(ctx, session.UserID, session.QuizType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing session: %w", err)
+		}
+		if active != nil {
+			return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeQuizInProgress, "another quiz of this type is in progress")
 		}
 
-	case models.MockTest:
-		// Dynamic allocation to reach ~1000 points
-		questions, totalPoints, err := s.selectMockTestQuestions(ctx, config)
+		resumed, err := s.sessionRepo.ResumeAbandonedSession(ctx, session.ID)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to select mock test questions: %w", err)
+			return nil, err
+		}
+		if !resumed {
+			return nil, models.ConflictError("quiz session is not resumable")
 		}
-		return questions, totalPoints, nil
+		response.Message = "Quiz session resumed"
 	}
 
-	// Shuffle questions
-	s.shuffleSessionQuestions(questions)
+	updated, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	response.Session = *updated
+	if updated.Status == models.QuizInProgress {
+		response.TimeRemaining = s.calculateTimeRemaining(updated)
+	}
+	return response, nil
+}
 
-	return questions, totalPoints, nil
+// Private helper methods
+
+// autoSubmit scores a session that ran out of time as if it was submitted at its deadline, with the
+// abandon reason when the student was not there. It returns nil without saving anything when the
+// session was already finished elsewhere
+func (s *quizSessionService) autoSubmit(ctx context.Context, session *models.QuizSession, reason models.AbandonReason) (*models.DetailedQuizResult, error) {
+	deadline := session.Deadline(time.Now())
+	result, err := s.saveResults(ctx, session, deadline, func(ctx context.Context) (bool, error) {
+		return s.sessionRepo.MarkSessionTimedOut(ctx, session.ID, deadline, reason)
+	})
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	s.publishLive(session.SessionToken, models.QuizLiveEvent{
+		Type:     models.QuizLiveSubmitted,
+		Status:   models.QuizTimeout,
+		Reason:   models.SubmitReasonTimeExpired,
+		ResultID: result.ID.Hex(),
+	})
+	return result, nil
 }
 
-func (s *quizSessionService) selectMockTestQuestions(ctx context.Context, config models.QuizConfig) ([]models.SessionQuestion, int, error) {
-	// Get available questions by difficulty (reasonable limit, not all)
-	easyQuestions, err := s.getQuestionsByDifficulty(ctx, models.Easy, 200) // Get up to 200 easy questions
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get easy questions: %w", err)
+// publishLive pushes an event to the session's open live connections
+func (s *quizSessionService) publishLive(sessionToken string, event models.QuizLiveEvent) {
+	if s.liveHub == nil {
+		return
 	}
+	event.ServerTime = time.Now()
+	s.liveHub.Publish(sessionToken, event)
+}
 
-	mediumQuestions, err := s.getQuestionsByDifficulty(ctx, models.Medium, 150) // Get up to 150 medium questions
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get medium questions: %w", err)
-	}
+// saveResults scores a session, then finishes it with finish and stores its results in one
+// transaction, so a session is never left finished without results or scored twice. finish reports
+// false when the session was already finished elsewhere; nothing is stored then and the result is nil.
+func (s *quizSessionService) saveResults(ctx context.Context, session *models.QuizSession, endTime time.Time, finish func(ctx context.Context) (bool, error)) (*models.DetailedQuizResult, error) {
+	ctx, span := utils.StartSpan(ctx, "QuizSessionService.saveResults", attribute.String("quiz.session_id", session.ID.Hex()))
+	defer span.End()
 
-	hardQuestions, err := s.getQuestionsByDifficulty(ctx, models.Hard, 100) // Get up to 100 hard questions
+	_, scoreSpan := utils.StartSpan(ctx, "QuizSessionService.calculateResults", attribute.Int("quiz.questions", len(session.Questions)))
+	result, err := s.calculateResults(session, endTime)
+	utils.RecordSpanError(scoreSpan, err)
+	scoreSpan.End()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get hard questions: %w", err)
+		return nil, fmt.Errorf("failed to calculate results: %w", err)
 	}
 
-	// Check minimum requirements
-	totalAvailable := len(easyQuestions) + len(mediumQuestions) + len(hardQuestions)
-	if totalAvailable < 10 {
-		return nil, 0, fmt.Errorf("insufficient questions available: need at least 10, have %d", totalAvailable)
+	s.applySittingRules(ctx, result)
+
+	// Also create simple QuizResult for existing user activity tracking; every team member is credited
+	creditedIDs := []primitive.ObjectID{session.UserID}
+	if session.TeamID != nil {
+		creditedIDs = session.TeamMemberIDs
 	}
 
-	// Combine all questions and calculate how many we need to reach ~1000 points
-	var allQuestions []*models.Question
-	allQuestions = append(allQuestions, easyQuestions...)
-	allQuestions = append(allQuestions, mediumQuestions...)
-	allQuestions = append(allQuestions, hardQuestions...)
+	var createdResults []*models.QuizResult
+	var finished bool
+	err = s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		createdResults = createdResults[:0]
+
+		var err error
+		finished, err = finish(ctx)
+		if err != nil || !finished {
+			return err
+		}
 
-	// Shuffle all questions to ensure random distribution
-	s.shuffleQuestionsSlice(allQuestions)
+		if err := s.sessionRepo.CreateDetailedResult(ctx, result); err != nil {
+			return fmt.Errorf("failed to save detailed result: %w", err)
+		}
+		for _, userID := range creditedIDs {
+			simpleResult := s.convertToSimpleQuizResult(result, userID)
+			createdResult, err := s.userActivityRepo.CreateQuizResult(ctx, simpleResult)
+			if err != nil {
+				return fmt.Errorf("failed to save simple result: %w", err)
+			}
+			createdResults = append(createdResults, createdResult)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, nil
+	}
 
-	// Select 100 questions for MockTest (10 points each = 1000 points total)
-	targetQuestionCount := 100
-	if len(allQuestions) < targetQuestionCount {
-		targetQuestionCount = len(allQuestions)
+	if session.TeamID != nil {
+		s.cache.Invalidate(ctx, utils.CacheLeaderboards)
 	}
 
-	// Ensure we have at least 10 questions for a meaningful quiz
-	if targetQuestionCount < 10 {
-		return nil, 0, fmt.Errorf("insufficient questions available: need at least 10, have %d", len(allQuestions))
+	// Attempts at admin templates count toward stats as the template's attempt policy says
+	if result.TemplateID != nil && !session.IsGuest {
+		for _, createdResult := range createdResults {
+			if err := s.userActivityRepo.UpdateUserStats(ctx, createdResult.UserID, createdResult); err != nil {
+				slog.ErrorContext(ctx, "Failed to update user stats", "error", err)
+			}
+		}
 	}
 
-	selectedQuestions := allQuestions[:targetQuestionCount]
+	// Answers move each student's spaced-repetition schedule for the questions along
+	if s.practice != nil && !session.IsGuest && session.EmbedWidgetID == nil {
+		s.practice.RecordResult(creditedIDs, result)
+	}
 
-	// Calculate total points (should be targetQuestionCount * 10)
-	totalPoints := 0
-	for _, q := range selectedQuestions {
-		totalPoints += q.Points
+	// Exam results visible to the student are final; withheld ones are sent when the sitting is released,
+	// and ones with essays to grade once they are graded
+	if s.resultWebhooks != nil && result.ExamSittingID != nil && result.PendingEssays == 0 {
+		if withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); !withheld {
+			s.resultWebhooks.ResultFinalized(result)
+		}
 	}
 
-	// Convert to session questions (using their original points, not config points)
-	var sessionQuestions []models.SessionQuestion
-	for _, q := range selectedQuestions {
-		sessionQ := s.convertQuestionToSessionQuestion(q)
-		sessionQuestions = append(sessionQuestions, sessionQ)
+	return result, nil
+}
+
+// getActiveSitting returns the sitting of this quiz type the user is seated in right now, if any
+func (s *quizSessionService) getActiveSitting(ctx context.Context, userID primitive.ObjectID, quizType models.QuizType, now time.Time) (*models.ExamSitting, error) {
+	participations, err := s.examRepo.GetParticipationsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Final shuffle for good measure
-	s.shuffleSessionQuestions(sessionQuestions)
+	for _, participation := range participations {
+		sitting, err := s.examRepo.GetSitting(ctx, participation.SittingID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSittingNotFound) {
+				continue
+			}
+			return nil, err
+		}
 
-	fmt.Printf("Selected %d questions for MockTest with %d total points (target: %d)\n",
-		len(selectedQuestions), totalPoints, config.MaxPoints)
+		if sitting.QuizType != quizType {
+			continue
+		}
+		if now.Before(sitting.ScheduledStart.Add(-attendanceEarlyStartWindow)) || now.After(sitting.ScheduledEnd) {
+			continue
+		}
+		return sitting, nil
+	}
 
-	return sessionQuestions, totalPoints, nil
+	return nil, nil
 }
 
-func (s *quizSessionService) getQuestionsByDifficulty(ctx context.Context, difficulty models.DifficultyLevel, limit int) ([]*models.Question, error) {
-	if limit == -1 {
-		// Get all questions of this difficulty
-		limit = 1000 // Set a reasonable max limit
+// verifySessionLockdown checks the handshake for sessions started under a lockdown sitting.
+// Sessions are let through once an admin lifts the requirement or deletes the sitting.
+func (s *quizSessionService) verifySessionLockdown(ctx context.Context, session *models.QuizSession, handshake *models.LockdownHandshake) error {
+	if session.LockdownSittingID == nil {
+		return nil
 	}
 
-	// Try to get questions from database first
-	dbQuestions, err := s.questionRepo.GetRandomQuestionsByDifficulty(ctx, difficulty, limit)
+	sitting, err := s.examRepo.GetSitting(ctx, *session.LockdownSittingID)
 	if err != nil {
-		// If database query fails, return error but continue with samples
-		fmt.Printf("Warning: Database query failed for %s questions: %v\n", difficulty, err)
+		if errors.Is(err, repository.ErrSittingNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get exam sitting: %w", err)
 	}
-
-	// Check if we have enough questions from database
-	if len(dbQuestions) >= limit {
-		return dbQuestions, nil
+	if !sitting.RequireLockdownBrowser {
+		return nil
 	}
 
-	// Not enough questions in database, supplement with hardcoded samples
-	fmt.Printf("Only found %d %s questions in database, need %d. Adding sample questions.\n",
-		len(dbQuestions), difficulty, limit)
+	return utils.VerifyLockdownHandshake(sitting.LockdownKey, handshake, time.Now())
+}
+
+// resolveTemplate picks the template a quiz starts from: the requested one, else the quiz type's
+// default template, else its built-in definition
+func (s *quizSessionService) resolveTemplate(ctx context.Context, templateID string, quizType models.QuizType) (*models.QuizTemplate, error) {
+	if templateID != "" {
+		id, err := primitive.ObjectIDFromHex(templateID)
+		if err != nil {
+			return nil, models.BadRequestError("invalid template ID")
+		}
+		template, err := s.getTemplate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !template.IsActive {
+			return nil, models.BadRequestError("quiz template is not active")
+		}
+		if quizType != "" && template.QuizType != quizType {
+			return nil, models.BadRequestError("quiz template does not match the quiz type")
+		}
+		return template, nil
+	}
 
-	// Generate sample questions for the missing count
-	missingCount := limit - len(dbQuestions)
-	sampleQuestions := generateSampleQuestions(difficulty, missingCount)
+	template, err := s.getDefaultTemplate(ctx, quizType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default quiz template: %w", err)
+	}
+	if template == nil {
+		template = models.DefaultQuizTemplate(quizType)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("unsupported quiz type: %s", quizType)
+	}
+	return template, nil
+}
 
-	// Combine database questions with samples
-	allQuestions := make([]*models.Question, 0, len(dbQuestions)+len(sampleQuestions))
-	allQuestions = append(allQuestions, dbQuestions...)
-	allQuestions = append(allQuestions, sampleQuestions...)
+// getTemplate reads a template through the cache, as every quiz started from it does
+func (s *quizSessionService) getTemplate(ctx context.Context, id primitive.ObjectID) (*models.QuizTemplate, error) {
+	var cached models.QuizTemplate
+	if s.cache.Get(ctx, utils.CacheQuizTemplates, "id:"+id.Hex(), &cached) {
+		return &cached, nil
+	}
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(ctx, utils.CacheQuizTemplates, "id:"+id.Hex(), template)
+	return template, nil
+}
 
-	return allQuestions, nil
+// getDefaultTemplate reads the default template of a quiz type through the cache, remembering too that
+// there is none
+func (s *quizSessionService) getDefaultTemplate(ctx context.Context, quizType models.QuizType) (*models.QuizTemplate, error) {
+	var cached *models.QuizTemplate
+	if s.cache.Get(ctx, utils.CacheQuizTemplates, "default:"+string(quizType), &cached) {
+		return cached, nil
+	}
+	template, err := s.templateRepo.GetDefault(ctx, quizType)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(ctx, utils.CacheQuizTemplates, "default:"+string(quizType), template)
+	return template, nil
 }
 
-// generateSampleQuestions creates hardcoded sample questions for testing
-func generateSampleQuestions(difficulty models.DifficultyLevel, count int) []*models.Question {
-	var samples []*models.Question
-	adminID := primitive.NewObjectID() // Dummy admin ID
+// selectQuestions draws a template's questions: the per-difficulty counts first, then the mixed
+// questions from every difficulty, skipping questions already drawn
+func (s *quizSessionService) selectQuestions(ctx context.Context, template *models.QuizTemplate, lang string) ([]models.SessionQuestion, int, error) {
+	// Check the bank up front so a short pool fails clearly instead of producing a short quiz
+	available, err := s.questionRepo.CountActiveByDifficulty(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count questions: %w", err)
+	}
+	if pool := template.CheckPool(available); !pool.Ready {
+		return nil, 0, poolShortfallError(pool)
+	}
 
-	// Sample question templates based on difficulty
-	switch difficulty {
-	case models.Easy:
-		easyTemplates := []struct {
-			title   string
-			options []string
-			correct int // 0-based index of correct answer
-		}{
-			{"What is 2 + 2?", []string{"3", "4", "5", "6"}, 1},
-			{"What is the capital of France?", []string{"London", "Berlin", "Paris", "Madrid"}, 2},
-			{"Which color is made by mixing red and blue?", []string{"Green", "Purple", "Yellow", "Orange"}, 1},
-			{"How many days are in a week?", []string{"5", "6", "7", "8"}, 2},
-			{"What is the first letter of the alphabet?", []string{"A", "B", "C", "D"}, 0},
-			{"Which programming language uses 'print()' function?", []string{"Java", "Python", "C++", "Assembly"}, 1},
-			{"What does HTML stand for?", []string{"Hypertext Markup Language", "High Tech Modern Language", "Home Tool Markup Language", "Hyperlink Text Markup Language"}, 0},
-			{"Which planet is closest to the Sun?", []string{"Venus", "Earth", "Mercury", "Mars"}, 2},
-		}
+	var selected []*models.Question
+	drawn := make(map[primitive.ObjectID]bool)
 
-		for i := 0; i < count && i < len(easyTemplates); i++ {
-			template := easyTemplates[i%len(easyTemplates)]
-			// All questions: 10 points each
-			question := createSampleQuestion(template.title, template.options, template.correct, difficulty, 10, adminID)
-			samples = append(samples, question)
+	counts := []struct {
+		difficulty models.DifficultyLevel
+		count      int
+	}{
+		{models.Easy, template.EasyQuestions},
+		{models.Medium, template.MediumQuestions},
+		{models.Hard, template.HardQuestions},
+	}
+	for _, c := range counts {
+		if c.count == 0 {
+			continue
 		}
-
-	case models.Medium:
-		mediumTemplates := []struct {
-			title   string
-			options []string
-			correct int
-		}{
-			{"What is the time complexity of binary search?", []string{"O(n)", "O(log n)", "O(n²)", "O(1)"}, 1},
-			{"Which HTTP status code indicates 'Not Found'?", []string{"200", "404", "500", "301"}, 1},
-			{"In object-oriented programming, what is inheritance?", []string{"Creating objects", "Reusing code from parent class", "Deleting objects", "Changing object state"}, 1},
-			{"What does SQL stand for?", []string{"Structured Query Language", "Simple Query Language", "Standard Query Language", "Sequential Query Language"}, 0},
-			{"Which data structure follows LIFO principle?", []string{"Queue", "Array", "Stack", "Tree"}, 2},
-			{"What is the default port for HTTP?", []string{"21", "22", "80", "443"}, 2},
-			{"In Git, what command is used to create a new branch?", []string{"git new-branch", "git create-branch", "git branch", "git checkout -b"}, 3},
+		questions, err := s.getQuestionsByDifficulty(ctx, c.difficulty, c.count)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get %s questions: %w", c.difficulty, err)
 		}
-
-		for i := 0; i < count && i < len(mediumTemplates); i++ {
-			template := mediumTemplates[i%len(mediumTemplates)]
-			// All questions: 10 points each
-			question := createSampleQuestion(template.title, template.options, template.correct, difficulty, 10, adminID)
-			samples = append(samples, question)
+		if len(questions) < c.count {
+			return nil, 0, fmt.Errorf("%w: need %d %s questions, have %d", ErrQuestionPoolTooSmall, c.count, c.difficulty, len(questions))
 		}
-
-	case models.Hard:
-		hardTemplates := []struct {
-			title   string
-			options []string
-			correct int
-		}{
-			{"What is the purpose of the 'volatile' keyword in Java?", []string{"Prevents method overriding", "Ensures thread-safe access to variables", "Makes variables immutable", "Improves performance"}, 1},
-			{"Which algorithm has the best average-case time complexity for sorting?", []string{"Bubble Sort", "Quick Sort", "Merge Sort", "Selection Sort"}, 2},
-			{"In database design, what is normalization?", []string{"Adding indexes", "Organizing data to reduce redundancy", "Encrypting data", "Backing up data"}, 1},
-			{"What does the CAP theorem state?", []string{"Consistency, Availability, Partition tolerance - pick two", "All distributed systems are consistent", "Performance is more important than consistency", "Databases should always be available"}, 0},
-			{"Which design pattern ensures a class has only one instance?", []string{"Factory", "Observer", "Singleton", "Strategy"}, 2},
-			{"What is the space complexity of merge sort?", []string{"O(1)", "O(log n)", "O(n)", "O(n log n)"}, 2},
+		for _, q := range questions {
+			drawn[q.ID] = true
+			selected = append(selected, q)
 		}
+	}
 
-		for i := 0; i < count && i < len(hardTemplates); i++ {
-			template := hardTemplates[i%len(hardTemplates)]
-			// All questions: 10 points each
-			question := createSampleQuestion(template.title, template.options, template.correct, difficulty, 10, adminID)
-			samples = append(samples, question)
+	if template.MixedQuestions > 0 {
+		mixed, err := s.selectMixedQuestions(ctx, template.MixedQuestions, drawn)
+		if err != nil {
+			return nil, 0, err
 		}
+		selected = append(selected, mixed...)
+	}
+
+	questions := make([]models.SessionQuestion, 0, len(selected))
+	totalPoints := 0
+	for _, q := range selected {
+		sessionQuestion := s.localizedSessionQuestion(q, lang)
+		sessionQuestion.Points = template.Scoring.PointsFor(q)
+		totalPoints += sessionQuestion.Points
+		questions = append(questions, sessionQuestion)
 	}
 
-	return samples
+	// Shuffle questions
+	s.shuffleSessionQuestions(questions)
+
+	return questions, totalPoints, nil
 }
 
-// createSampleQuestion creates a sample question with the given parameters
-func createSampleQuestion(title string, optionTexts []string, correctIndex int, difficulty models.DifficultyLevel, points int, adminID primitive.ObjectID) *models.Question {
-	// Create options with IDs
-	options := make([]models.Option, len(optionTexts))
-	for i, text := range optionTexts {
-		options[i] = models.Option{
-			ID:    primitive.NewObjectID().Hex(),
-			Text:  text,
-			Order: i + 1,
-		}
-	}
-
-	// Set correct answer
-	var correctAnswers []string
-	if correctIndex >= 0 && correctIndex < len(options) {
-		correctAnswers = []string{options[correctIndex].ID}
-	}
-
-	return &models.Question{
-		ID:             primitive.NewObjectID(),
-		Title:          title,
-		Type:           models.SingleChoice,
-		Difficulty:     difficulty,
-		Points:         points,
-		IsActive:       true,
-		Options:        options,
-		CorrectAnswers: correctAnswers,
-		CreatedBy:      adminID,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
-	}
-}
-
-func (s *quizSessionService) selectRandomQuestions(questions []*models.Question, count int) []*models.Question {
-	if count >= len(questions) {
-		return questions
-	}
+// poolShortfallError describes what a template is missing, e.g. "need 10 easy questions, have 4"
+func poolShortfallError(pool *models.QuestionPoolStatus) error {
+	parts := make([]string, 0, len(pool.Shortfalls))
+	for _, shortfall := range pool.Shortfalls {
+		parts = append(parts, fmt.Sprintf("need %d %s questions, have %d", shortfall.Required, shortfall.Difficulty, shortfall.Available))
+	}
+	return fmt.Errorf("%w: %s", ErrQuestionPoolTooSmall, strings.Join(parts, "; "))
+}
 
-	// Shuffle and take first 'count' questions
-	shuffled := make([]*models.Question, len(questions))
-	copy(shuffled, questions)
+// selectMixedQuestions draws up to count questions at random across difficulties. Smaller pools are
+// accepted as long as they hold a meaningful quiz (at least models.MinMixedQuestions, or count if lower).
+func (s *quizSessionService) selectMixedQuestions(ctx context.Context, count int, drawn map[primitive.ObjectID]bool) ([]*models.Question, error) {
+	exclude := make([]primitive.ObjectID, 0, len(drawn))
+	for id := range drawn {
+		exclude = append(exclude, id)
+	}
 
-	// Fisher-Yates shuffle
-	for i := len(shuffled) - 1; i > 0; i-- {
-		j, _ := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
-		shuffled[i], shuffled[j.Int64()] = shuffled[j.Int64()], shuffled[i]
+	selected, err := s.questionRepo.SampleQuestions(ctx, models.QuestionSample{
+		Difficulties: []models.DifficultyLevel{models.Easy, models.Medium, models.Hard},
+		ExcludeIDs:   exclude,
+	}, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mixed questions: %w", err)
+	}
+
+	required := min(count, models.MinMixedQuestions)
+	if len(selected) < required {
+		return nil, fmt.Errorf("%w: need at least %d mixed questions, have %d", ErrQuestionPoolTooSmall, required, len(selected))
+	}
+
+	slog.Debug("Selected mixed questions", "selected", len(selected), "target", count)
+
+	return selected, nil
+}
+
+// getQuestionsByDifficulty draws up to limit active questions of a difficulty at random
+func (s *quizSessionService) getQuestionsByDifficulty(ctx context.Context, difficulty models.DifficultyLevel, limit int) ([]*models.Question, error) {
+	if limit == -1 {
+		// Get all questions of this difficulty
+		limit = 1000 // Set a reasonable max limit
 	}
 
-	return shuffled[:count]
+	return s.questionRepo.GetRandomQuestionsByDifficulty(ctx, difficulty, limit)
 }
 
 func (s *quizSessionService) convertToSessionQuestions(questions []*models.Question, points int) []models.SessionQuestion {
 	var sessionQuestions []models.SessionQuestion
 
 	for _, q := range questions {
-		// Shuffle options for this session
-		shuffledOptions := make([]models.Option, len(q.Options))
-		copy(shuffledOptions, q.Options)
-		s.shuffleOptions(shuffledOptions)
-
+		q, values := instantiateQuestion(q)
+		renderQuestionContent(q)
 		sessionQuestions = append(sessionQuestions, models.SessionQuestion{
-			QuestionID:     q.ID,
-			Title:          q.Title,
-			Type:           q.Type,
-			Difficulty:     q.Difficulty,
-			Points:         points, // Use configured points, not question points
-			Options:        shuffledOptions,
-			CorrectAnswers: q.CorrectAnswers,
-			IsAnswered:     false,
-			IsSkipped:      false,
-			IsCorrect:      false,
-			PointsEarned:   0,
-			TimeSpent:      0,
-			VisitCount:     0,
+			QuestionID:      q.ID,
+			Title:           q.Title,
+			TitleHTML:       q.TitleHTML,
+			Type:            q.Type,
+			Difficulty:      q.Difficulty,
+			Points:          points, // Use configured points, not question points
+			QuestionVersion: questionVersion(q),
+			Variables:       values,
+			Media:           q.Media,
+			Audio:           q.Audio,
+			Options:         displayOptions(q),
+			CorrectAnswers:  q.CorrectAnswers,
+			SampleAnswer:    q.SampleAnswer, // Include sample answer for essay questions
+			Blanks:          q.Blanks,
+			NumericAnswer:   q.NumericAnswer,
+			Unit:            numericUnit(q),
+			OrderingScoring: q.OrderingScoring,
+			Sections:        q.Sections,
+			ModuleIDs:       q.ModuleIDs,
+			Explanation:     q.Explanation,
+			IsAnswered:      false,
+			IsSkipped:       false,
+			IsCorrect:       false,
+			PointsEarned:    0,
+			TimeSpent:       0,
+			VisitCount:      0,
 		})
 	}
 
@@ -653,18 +1454,26 @@ func (s *quizSessionService) shuffleQuestions(questions []models.SessionQuestion
 	}
 }
 
-func (s *quizSessionService) shuffleOptions(options []models.Option) {
+func shuffleOptions(options []models.Option) {
 	for i := len(options) - 1; i > 0; i-- {
 		j, _ := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
 		options[i], options[j.Int64()] = options[j.Int64()], options[i]
 	}
 }
 
-func (s *quizSessionService) shuffleQuestionsSlice(questions []*models.Question) {
-	for i := len(questions) - 1; i > 0; i-- {
-		j, _ := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
-		questions[i], questions[j.Int64()] = questions[j.Int64()], questions[i]
+// displayOptions copies a question's options in a shuffled display order. Ordering questions are
+// renumbered by display position, since their stored order is the answer.
+func displayOptions(q *models.Question) []models.Option {
+	options := make([]models.Option, len(q.Options))
+	copy(options, q.Options)
+	shuffleOptions(options)
+
+	if q.Type == models.Ordering {
+		for i := range options {
+			options[i].Order = i + 1
+		}
 	}
+	return options
 }
 
 func (s *quizSessionService) shuffleSessionQuestions(questions []models.SessionQuestion) {
@@ -675,33 +1484,41 @@ func (s *quizSessionService) shuffleSessionQuestions(questions []models.SessionQ
 }
 
 func (s *quizSessionService) convertQuestionToSessionQuestion(q *models.Question) models.SessionQuestion {
-	// Create a copy of options to shuffle
-	options := make([]models.Option, len(q.Options))
-	copy(options, q.Options)
-	s.shuffleOptions(options)
-
+	q, values := instantiateQuestion(q)
+	renderQuestionContent(q)
 	return models.SessionQuestion{
-		QuestionID:     q.ID,
-		Title:          q.Title,
-		Type:           q.Type,
-		Difficulty:     q.Difficulty,
-		Points:         q.Points, // Use the question's original points
-		Options:        options,
-		CorrectAnswers: q.CorrectAnswers,
-		SampleAnswer:   q.SampleAnswer, // Include sample answer for essay questions
-		IsAnswered:     false,
-		IsSkipped:      false,
-		IsCorrect:      false,
-		PointsEarned:   0,
-		TimeSpent:      0,
-		VisitCount:     0,
+		QuestionID:      q.ID,
+		Title:           q.Title,
+		TitleHTML:       q.TitleHTML,
+		Type:            q.Type,
+		Difficulty:      q.Difficulty,
+		Points:          q.Points, // Use the question's original points
+		QuestionVersion: questionVersion(q),
+		Variables:       values,
+		Media:           q.Media,
+		Audio:           q.Audio,
+		Options:         displayOptions(q),
+		CorrectAnswers:  q.CorrectAnswers,
+		SampleAnswer:    q.SampleAnswer, // Include sample answer for essay questions
+		Blanks:          q.Blanks,
+		NumericAnswer:   q.NumericAnswer,
+		Unit:            numericUnit(q),
+		OrderingScoring: q.OrderingScoring,
+		Sections:        q.Sections,
+		ModuleIDs:       q.ModuleIDs,
+		Explanation:     q.Explanation,
+		IsAnswered:      false,
+		IsSkipped:       false,
+		IsCorrect:       false,
+		PointsEarned:    0,
+		TimeSpent:       0,
+		VisitCount:      0,
 	}
 }
 
+// calculateTimeRemaining counts down to the session's deadline, so it stays frozen while the session is paused
 func (s *quizSessionService) calculateTimeRemaining(session *models.QuizSession) int64 {
-	elapsed := time.Since(session.StartTime)
-	timeLimit := time.Duration(session.TimeLimitMinutes) * time.Minute
-	remaining := timeLimit - elapsed
+	remaining := time.Until(session.Deadline(time.Now()))
 
 	if remaining < 0 {
 		return 0
@@ -711,6 +1528,25 @@ func (s *quizSessionService) calculateTimeRemaining(session *models.QuizSession)
 }
 
 func (s *quizSessionService) checkAnswer(question models.SessionQuestion, userAnswer interface{}) bool {
+	// Fill-in-the-blank answers are keyed by blank, and every blank must be filled correctly
+	if question.Type == models.FillInBlank {
+		for _, correct := range models.GradeBlanks(userAnswer, question.Blanks) {
+			if !correct {
+				return false
+			}
+		}
+		return len(question.Blanks) > 0
+	}
+
+	if question.Type == models.Numeric {
+		return question.NumericAnswer != nil && question.NumericAnswer.Accepts(userAnswer)
+	}
+
+	// Ordering answers are only correct in the exact sequence; pairwise scoring gives partial points instead
+	if question.Type == models.Ordering {
+		return models.GradeOrdering(userAnswer, question.CorrectAnswers, models.OrderingExact) == 1
+	}
+
 	// Convert user answer to string slice for comparison
 	var userAnswers []string
 
@@ -725,6 +1561,12 @@ func (s *quizSessionService) checkAnswer(question models.SessionQuestion, userAn
 				userAnswers = append(userAnswers, str)
 			}
 		}
+	case primitive.A: // Answers read back from the database
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				userAnswers = append(userAnswers, str)
+			}
+		}
 	default:
 		return false
 	}
@@ -796,8 +1638,41 @@ func (s *quizSessionService) checkAnswer(question models.SessionQuestion, userAn
 	return true
 }
 
+// sessionCorrectAnswer is the answer key shown in feedback and result review; fill-in-the-blank
+// questions list the accepted answers of each blank, and numeric questions give the value and tolerance
+func sessionCorrectAnswer(question models.SessionQuestion) interface{} {
+	if question.Type == models.FillInBlank {
+		return models.BlankAnswerKey(question.Blanks)
+	}
+	if question.Type == models.Numeric && question.NumericAnswer != nil {
+		return question.NumericAnswer
+	}
+	return question.CorrectAnswers
+}
+
+// answerPoints is what an answer earns: all the question's points when correct, and for ordering
+// questions scored pairwise, a share of them for the option pairs placed in the right order
+func answerPoints(question models.SessionQuestion, userAnswer interface{}, isCorrect bool) int {
+	if isCorrect {
+		return question.Points
+	}
+	if question.Type == models.Ordering && question.OrderingScoring == models.OrderingPairwise {
+		credit := models.GradeOrdering(userAnswer, question.CorrectAnswers, models.OrderingPairwise)
+		return int(math.Floor(float64(question.Points) * credit))
+	}
+	return 0
+}
+
+// numericUnit is the unit students see next to a numeric question's input
+func numericUnit(q *models.Question) string {
+	if q.NumericAnswer == nil {
+		return ""
+	}
+	return q.NumericAnswer.Unit
+}
+
 func (s *quizSessionService) calculateResults(session *models.QuizSession, endTime time.Time) (*models.DetailedQuizResult, error) {
-	var correctAnswers, wrongAnswers, skippedQuestions int
+	var correctAnswers, wrongAnswers, skippedQuestions, pendingEssays int
 	var earnedPoints int
 	var easyCorrect, easyTotal, mediumCorrect, mediumTotal, hardCorrect, hardTotal int
 	var questionResults []models.QuestionResult
@@ -811,13 +1686,18 @@ func (s *quizSessionService) calculateResults(session *models.QuizSession, endTi
 			Difficulty:    question.Difficulty,
 			Points:        question.Points,
 			UserAnswer:    question.UserAnswer,
-			CorrectAnswer: question.CorrectAnswers,
+			CorrectAnswer: sessionCorrectAnswer(question),
 			IsCorrect:     false,
 			IsSkipped:     question.IsSkipped,
 			PointsEarned:  0,
 			TimeSpent:     question.TimeSpent,
 			Options:       question.Options,
+			Sections:      question.Sections,
+			ModuleIDs:     assessedModules(question, session.ModuleIDs),
+			Explanation:   question.Explanation,
 		}
+		qr.QuestionVersion = question.QuestionVersion
+		qr.TitleHTML = question.TitleHTML
 
 		// Count by difficulty
 		switch question.Difficulty {
@@ -831,9 +1711,16 @@ func (s *quizSessionService) calculateResults(session *models.QuizSession, endTi
 
 		if question.IsSkipped {
 			skippedQuestions++
+		} else if question.IsAnswered && question.Type == models.Essay {
+			// Essays are graded by an admin; the score is recomputed once they all are
+			qr.PendingGrade = true
+			pendingEssays++
 		} else if question.IsAnswered {
 			isCorrect := s.checkAnswer(question, question.UserAnswer)
 			qr.IsCorrect = isCorrect
+			if question.Type == models.FillInBlank {
+				qr.BlankResults = models.GradeBlanks(question.UserAnswer, question.Blanks)
+			}
 
 			if isCorrect {
 				correctAnswers++
@@ -851,23 +1738,26 @@ func (s *quizSessionService) calculateResults(session *models.QuizSession, endTi
 				}
 			} else {
 				wrongAnswers++
+				partial := answerPoints(question, question.UserAnswer, false)
+				earnedPoints += partial
+				qr.PointsEarned = partial
 			}
 		}
 
 		questionResults = append(questionResults, qr)
 	}
 
-	// Calculate time used
-	timeUsedSeconds := int64(endTime.Sub(session.StartTime).Seconds())
+	// Calculate time used, leaving out time the session spent paused
+	timeUsedSeconds := int64((endTime.Sub(session.StartTime) - session.PausedDuration(endTime)).Seconds())
 	timeLeftSeconds := int64(session.TimeLimitMinutes*60) - timeUsedSeconds
 	if timeLeftSeconds < 0 {
 		timeLeftSeconds = 0
 	}
 
-	// Calculate time bonus for TimeQuiz
+	// Calculate time bonus when the scoring rules award one
 	timeBonus := 0
-	if session.QuizType == models.TimeQuiz {
-		timeBonus = models.CalculateTimeBonus(timeLeftSeconds, 50) // Max 50 bonus points
+	if scoring := sessionScoring(session); scoring.TimeBonusMax > 0 {
+		timeBonus = models.CalculateTimeBonus(timeLeftSeconds, int64(session.TimeLimitMinutes*60), scoring.TimeBonusMax)
 	}
 
 	finalScore := earnedPoints + timeBonus
@@ -896,8 +1786,13 @@ func (s *quizSessionService) calculateResults(session *models.QuizSession, endTi
 			CompletedAt:    endTime,
 			Status:         string(completionStatus),
 			IsTimedOut:     completionStatus == models.QuizTimeout,
+			ExamSittingID:  session.ExamSittingID,
+			TemplateID:     session.TemplateID,
 		},
 		SessionID:        session.ID,
+		TeamID:           session.TeamID,
+		TeamName:         session.TeamName,
+		TeamMemberIDs:    session.TeamMemberIDs,
 		TotalPoints:      session.MaxPoints,
 		EarnedPoints:     earnedPoints,
 		TimeBonus:        timeBonus,
@@ -915,11 +1810,61 @@ func (s *quizSessionService) calculateResults(session *models.QuizSession, endTi
 		QuestionResults:  questionResults,
 		CompletionStatus: completionStatus,
 		SubmittedAt:      endTime,
+		PendingEssays:    pendingEssays,
+		Integrity:        models.SummarizeIntegrity(session.IntegrityEvents, session.IntegrityEventCount),
+	}
+	if session.Scoring != nil {
+		result.AttemptScoring = session.Scoring.AttemptScoring
 	}
+	result.CorrectAnswersHidden = session.HideCorrectAnswers
 
 	return result, nil
 }
 
+// sessionScoring returns the scoring rules a session was started with, falling back to the quiz
+// type's built-in rules for sessions created before templates
+func sessionScoring(session *models.QuizSession) models.QuizScoring {
+	if session.Scoring != nil {
+		return *session.Scoring
+	}
+	if template := models.DefaultQuizTemplate(session.QuizType); template != nil {
+		return template.Scoring
+	}
+	return models.QuizScoring{}
+}
+
+// applySittingRules curves an exam sitting result when the sitting's scores have been scaled, then
+// marks it for instructor review when it lands near the pass mark
+func (s *quizSessionService) applySittingRules(ctx context.Context, result *models.DetailedQuizResult) {
+	if result.ExamSittingID == nil {
+		return
+	}
+
+	sitting, err := s.examRepo.GetSitting(ctx, *result.ExamSittingID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrSittingNotFound) {
+			slog.ErrorContext(ctx, "Failed to check moderation for result", "error", err)
+		}
+		return
+	}
+
+	if sitting.Scaling != nil {
+		sitting.Scaling.ApplyTo(result, time.Now())
+	}
+	if !sitting.NeedsModeration(result.ScorePercentage) {
+		return
+	}
+
+	flaggedAt := time.Now()
+	result.Moderation = &models.ResultModeration{
+		Status:             models.ModerationPending,
+		OriginalScore:      result.FinalScore,
+		OriginalPercentage: result.ScorePercentage,
+		FlaggedAt:          &flaggedAt,
+		Adjustments:        []models.ScoreAdjustment{},
+	}
+}
+
 func (s *quizSessionService) convertToSimpleQuizResult(detailed *models.DetailedQuizResult, userID primitive.ObjectID) *models.QuizResult {
 	return &models.QuizResult{
 		UserID:         userID,
@@ -934,6 +1879,9 @@ func (s *quizSessionService) convertToSimpleQuizResult(detailed *models.Detailed
 		CompletedAt:    detailed.CompletedAt,
 		Status:         detailed.Status,
 		IsTimedOut:     detailed.IsTimedOut,
+		ExamSittingID:  detailed.ExamSittingID,
+		TemplateID:     detailed.TemplateID,
+		AttemptScoring: detailed.AttemptScoring,
 	}
 }
 
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type QuizTemplateService interface {
+	CreateTemplate(ctx context.Context, adminID primitive.ObjectID, req *models.CreateQuizTemplateRequest) (*models.QuizTemplate, error)
+	GetTemplate(ctx context.Context, id primitive.ObjectID) (*models.QuizTemplate, error)
+	ListTemplates(ctx context.Context, req *models.ListQuizTemplatesRequest) ([]models.QuizTemplate, error)
+	UpdateTemplate(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuizTemplateRequest) (*models.QuizTemplate, error)
+	DeleteTemplate(ctx context.Context, id primitive.ObjectID) error
+	CheckQuestionPools(ctx context.Context) (*models.QuestionPoolReport, error)
+}
+
+type quizTemplateService struct {
+	templateRepo repository.QuizTemplateRepository
+	questionRepo repository.QuestionRepository
+	moduleRepo   repository.ModuleRepository
+	cache        *utils.Cache
+}
+
+func NewQuizTemplateService(templateRepo repository.QuizTemplateRepository, questionRepo repository.QuestionRepository, moduleRepo repository.ModuleRepository, cache *utils.Cache) QuizTemplateService {
+	return &quizTemplateService{
+		templateRepo: templateRepo,
+		questionRepo: questionRepo,
+		moduleRepo:   moduleRepo,
+		cache:        cache,
+	}
+}
+
+func (s *quizTemplateService) CreateTemplate(ctx context.Context, adminID primitive.ObjectID, req *models.CreateQuizTemplateRequest) (*models.QuizTemplate, error) {
+	moduleIDs, err := checkModuleIDs(ctx, s.moduleRepo, req.ModuleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.QuizTemplate{
+		Name:                  strings.TrimSpace(req.Name),
+		Description:           strings.TrimSpace(req.Description),
+		QuizType:              req.QuizType,
+		TimeLimitMinutes:      req.TimeLimitMinutes,
+		MaxAttempts:           req.MaxAttempts,
+		RetakeCooldownMinutes: req.RetakeCooldownMinutes,
+		HideCorrectAnswers:    req.HideCorrectAnswers,
+		EasyQuestions:         req.EasyQuestions,
+		MediumQuestions:       req.MediumQuestions,
+		HardQuestions:         req.HardQuestions,
+		MixedQuestions:        req.MixedQuestions,
+		Scoring:               scoringFromRequest(req.Scoring),
+		ModuleIDs:             moduleIDs,
+		IsActive:              req.IsActive == nil || *req.IsActive,
+		IsDefault:             req.IsDefault,
+		CreatedBy:             adminID,
+	}
+
+	if err := validateQuizTemplate(template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create quiz template: %w", err)
+	}
+	defer s.cache.Invalidate(ctx, utils.CacheQuizTemplates)
+
+	if template.IsDefault {
+		if err := s.templateRepo.ClearDefault(ctx, template.QuizType, template.ID); err != nil {
+			return nil, fmt.Errorf("failed to update default quiz template: %w", err)
+		}
+	}
+
+	return template, nil
+}
+
+func (s *quizTemplateService) GetTemplate(ctx context.Context, id primitive.ObjectID) (*models.QuizTemplate, error) {
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// ListTemplates lists templates; the active ones, which students pick from, are cached
+func (s *quizTemplateService) ListTemplates(ctx context.Context, req *models.ListQuizTemplatesRequest) ([]models.QuizTemplate, error) {
+	cacheKey := "active:" + string(req.QuizType)
+	if req.ActiveOnly {
+		var cached []models.QuizTemplate
+		if s.cache.Get(ctx, utils.CacheQuizTemplates, cacheKey, &cached) {
+			return cached, nil
+		}
+	}
+
+	templates, err := s.templateRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz templates: %w", err)
+	}
+
+	if templates == nil {
+		templates = []models.QuizTemplate{}
+	}
+	if req.ActiveOnly {
+		s.cache.Set(ctx, utils.CacheQuizTemplates, cacheKey, templates)
+	}
+	return templates, nil
+}
+
+func (s *quizTemplateService) UpdateTemplate(ctx context.Context, id primitive.ObjectID, req *models.UpdateQuizTemplateRequest) (*models.QuizTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the changes to a copy so the merged template can be validated as a whole
+	updates := bson.M{}
+	if req.Name != nil {
+		template.Name = strings.TrimSpace(*req.Name)
+		updates["name"] = template.Name
+	}
+	if req.Description != nil {
+		template.Description = strings.TrimSpace(*req.Description)
+		updates["description"] = template.Description
+	}
+	if req.TimeLimitMinutes != nil {
+		template.TimeLimitMinutes = *req.TimeLimitMinutes
+		updates["time_limit_minutes"] = template.TimeLimitMinutes
+	}
+	if req.MaxAttempts != nil {
+		template.MaxAttempts = *req.MaxAttempts
+		updates["max_attempts"] = template.MaxAttempts
+	}
+	if req.RetakeCooldownMinutes != nil {
+		template.RetakeCooldownMinutes = *req.RetakeCooldownMinutes
+		updates["retake_cooldown_minutes"] = template.RetakeCooldownMinutes
+	}
+	if req.HideCorrectAnswers != nil {
+		template.HideCorrectAnswers = *req.HideCorrectAnswers
+		updates["hide_correct_answers"] = template.HideCorrectAnswers
+	}
+	if req.EasyQuestions != nil {
+		template.EasyQuestions = *req.EasyQuestions
+		updates["easy_questions"] = template.EasyQuestions
+	}
+	if req.MediumQuestions != nil {
+		template.MediumQuestions = *req.MediumQuestions
+		updates["medium_questions"] = template.MediumQuestions
+	}
+	if req.HardQuestions != nil {
+		template.HardQuestions = *req.HardQuestions
+		updates["hard_questions"] = template.HardQuestions
+	}
+	if req.MixedQuestions != nil {
+		template.MixedQuestions = *req.MixedQuestions
+		updates["mixed_questions"] = template.MixedQuestions
+	}
+	if req.Scoring != nil {
+		template.Scoring = scoringFromRequest(*req.Scoring)
+		updates["scoring"] = template.Scoring
+	}
+	if req.ModuleIDs != nil {
+		template.ModuleIDs, err = checkModuleIDs(ctx, s.moduleRepo, req.ModuleIDs)
+		if err != nil {
+			return nil, err
+		}
+		updates["module_ids"] = template.ModuleIDs
+	}
+	if req.IsActive != nil {
+		template.IsActive = *req.IsActive
+		updates["is_active"] = template.IsActive
+	}
+	if req.IsDefault != nil {
+		template.IsDefault = *req.IsDefault
+		updates["is_default"] = template.IsDefault
+	}
+
+	if err := validateQuizTemplate(template); err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := s.templateRepo.Update(ctx, id, updates); err != nil {
+			return nil, err
+		}
+		defer s.cache.Invalidate(ctx, utils.CacheQuizTemplates)
+	}
+
+	if req.IsDefault != nil && *req.IsDefault {
+		if err := s.templateRepo.ClearDefault(ctx, template.QuizType, template.ID); err != nil {
+			return nil, fmt.Errorf("failed to update default quiz template: %w", err)
+		}
+	}
+
+	return s.templateRepo.GetByID(ctx, id)
+}
+
+// DeleteTemplate removes a template; sessions already started from it keep their copy of its rules.
+// Deleting a quiz type's default falls back to the built-in definition.
+func (s *quizTemplateService) DeleteTemplate(ctx context.Context, id primitive.ObjectID) error {
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.cache.Invalidate(ctx, utils.CacheQuizTemplates)
+	return nil
+}
+
+// CheckQuestionPools checks every active template, and the built-in definition of each quiz type
+// without an active default, against the active questions in the bank
+func (s *quizTemplateService) CheckQuestionPools(ctx context.Context) (*models.QuestionPoolReport, error) {
+	available, err := s.questionRepo.CountActiveByDifficulty(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count questions: %w", err)
+	}
+
+	templates, err := s.templateRepo.List(ctx, &models.ListQuizTemplatesRequest{ActiveOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz templates: %w", err)
+	}
+
+	for _, quizType := range []models.QuizType{models.MockTest, models.TimeQuiz} {
+		hasDefault := false
+		for _, t := range templates {
+			if t.QuizType == quizType && t.IsDefault {
+				hasDefault = true
+				break
+			}
+		}
+		if !hasDefault {
+			templates = append(templates, *models.DefaultQuizTemplate(quizType))
+		}
+	}
+
+	report := &models.QuestionPoolReport{
+		Available: available,
+		Ready:     true,
+		Templates: make([]models.QuestionPoolStatus, 0, len(templates)),
+	}
+	for i := range templates {
+		status := templates[i].CheckPool(available)
+		if !status.Ready {
+			report.Ready = false
+		}
+		report.Templates = append(report.Templates, *status)
+	}
+	return report, nil
+}
+
+func scoringFromRequest(req models.QuizScoringRequest) models.QuizScoring {
+	scoring := models.QuizScoring{
+		PointsSource:   req.PointsSource,
+		TimeBonusMax:   req.TimeBonusMax,
+		AttemptScoring: req.AttemptScoring,
+	}
+	if req.PointsSource == models.PointsByDifficulty {
+		scoring.EasyPoints = req.EasyPoints
+		scoring.MediumPoints = req.MediumPoints
+		scoring.HardPoints = req.HardPoints
+	}
+	return scoring
+}
+
+func validateQuizTemplate(template *models.QuizTemplate) error {
+	if template.TotalQuestions() == 0 {
+		return models.BadRequestError("template must include at least one question")
+	}
+	if template.IsDefault && !template.IsActive {
+		return models.BadRequestError("default template must be active")
+	}
+
+	scoring := template.Scoring
+	if scoring.PointsSource == models.PointsByDifficulty {
+		if (template.EasyQuestions > 0 || template.MixedQuestions > 0) && scoring.EasyPoints < 1 ||
+			(template.MediumQuestions > 0 || template.MixedQuestions > 0) && scoring.MediumPoints < 1 ||
+			(template.HardQuestions > 0 || template.MixedQuestions > 0) && scoring.HardPoints < 1 {
+			return models.BadRequestError("points are required for every difficulty the template draws from")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// remediationBuilder recommends module sections for the questions a student missed. Modules are
+// cached for the builder's lifetime, so create one per request.
+type remediationBuilder struct {
+	moduleRepo repository.ModuleRepository
+	modules    map[primitive.ObjectID]*models.Module
+}
+
+func newRemediationBuilder(moduleRepo repository.ModuleRepository) *remediationBuilder {
+	return &remediationBuilder{
+		moduleRepo: moduleRepo,
+		modules:    make(map[primitive.ObjectID]*models.Module),
+	}
+}
+
+// apply fills in study sections and the remediation summary of results shown in full; withheld
+// results have no question results to go on
+func (b *remediationBuilder) apply(ctx context.Context, results []models.DetailedQuizResult) {
+	for i := range results {
+		b.applyOne(ctx, &results[i])
+	}
+}
+
+func (b *remediationBuilder) applyOne(ctx context.Context, result *models.DetailedQuizResult) {
+	if len(result.QuestionResults) == 0 {
+		return
+	}
+
+	summary := &models.RemediationSummary{Sections: []models.RemediationSection{}}
+	positions := make(map[primitive.ObjectID]int)
+	for i := range result.QuestionResults {
+		qr := &result.QuestionResults[i]
+		// Essays awaiting a grade are not missed yet
+		if qr.IsCorrect || qr.PendingGrade {
+			continue
+		}
+		summary.MissedQuestions++
+
+		for _, ref := range qr.Sections {
+			section, ok := b.section(ctx, ref)
+			if !ok {
+				continue
+			}
+			qr.StudySections = append(qr.StudySections, section)
+
+			pos, seen := positions[ref.SubModuleID]
+			if !seen {
+				pos = len(summary.Sections)
+				positions[ref.SubModuleID] = pos
+				summary.Sections = append(summary.Sections, models.RemediationSection{StudySection: section})
+			}
+			summary.Sections[pos].MissedQuestions++
+			summary.Sections[pos].PointsMissed += qr.Points - qr.PointsEarned
+			summary.Sections[pos].QuestionIndexes = append(summary.Sections[pos].QuestionIndexes, i)
+		}
+		if len(qr.StudySections) == 0 {
+			summary.UnlinkedMissed++
+		}
+	}
+
+	sort.SliceStable(summary.Sections, func(i, j int) bool {
+		if summary.Sections[i].MissedQuestions != summary.Sections[j].MissedQuestions {
+			return summary.Sections[i].MissedQuestions > summary.Sections[j].MissedQuestions
+		}
+		return summary.Sections[i].PointsMissed > summary.Sections[j].PointsMissed
+	})
+	result.Remediation = summary
+}
+
+// section resolves a reference to a published section; deleted and unpublished ones are skipped
+func (b *remediationBuilder) section(ctx context.Context, ref models.SectionRef) (models.StudySection, bool) {
+	module, cached := b.modules[ref.ModuleID]
+	if !cached {
+		module, _ = b.moduleRepo.GetModuleByID(ctx, ref.ModuleID)
+		b.modules[ref.ModuleID] = module
+	}
+	if module == nil || !module.IsPublished {
+		return models.StudySection{}, false
+	}
+
+	for _, sub := range module.SubModules {
+		if sub.ID != ref.SubModuleID {
+			continue
+		}
+		if !module.SubModulePublished(sub.ID) {
+			break
+		}
+		return models.StudySection{
+			ModuleID:      module.ID,
+			SubModuleID:   sub.ID,
+			ModuleName:    module.Name,
+			SubModuleName: sub.Name,
+			Link:          "/dokumentasi?section=" + url.QueryEscape(sub.ID.Hex()),
+		}, true
+	}
+	return models.StudySection{}, false
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Peer distributions are shared by every student viewing a benchmark for the same quiz or exam,
+// so they are aggregated once and reused for a while
+const resultBenchmarkTTL = 15 * time.Minute
+
+// Practice attempts are compared with those of roughly the current semester
+const benchmarkPracticeLookback = 120 * 24 * time.Hour
+
+// Histogram bucket widths, matching GetBenchmarkDistribution
+const (
+	benchmarkScoreBucket = 1.0  // Score percentage points
+	benchmarkTimeBucket  = 10.0 // Seconds
+)
+
+type ResultBenchmarkService interface {
+	GetBenchmark(ctx context.Context, userID, resultID primitive.ObjectID) (*models.ResultBenchmark, error)
+}
+
+type cachedDistribution struct {
+	distribution *models.BenchmarkDistribution
+	generatedAt  time.Time
+	expiresAt    time.Time
+}
+
+type resultBenchmarkService struct {
+	sessionRepo repository.QuizSessionRepository
+	examRepo    repository.ExamRepository
+
+	mu            sync.Mutex
+	distributions map[string]cachedDistribution
+}
+
+func NewResultBenchmarkService(sessionRepo repository.QuizSessionRepository, examRepo repository.ExamRepository) ResultBenchmarkService {
+	return &resultBenchmarkService{
+		sessionRepo:   sessionRepo,
+		examRepo:      examRepo,
+		distributions: make(map[string]cachedDistribution),
+	}
+}
+
+// GetBenchmark compares one of the student's results with peers on the same exam sitting, or with
+// recent practice attempts of the same quiz type. Only aggregates are shown, and only once there
+// are enough peers that no one's result can be singled out.
+func (s *resultBenchmarkService) GetBenchmark(ctx context.Context, userID, resultID primitive.ObjectID) (*models.ResultBenchmark, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if !result.BelongsTo(userID) {
+		return nil, repository.ErrResultNotFound
+	}
+	if withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		return nil, ErrResultsNotReleased
+	}
+	if result.PendingEssays > 0 {
+		return nil, models.NewCodedError(http.StatusConflict, models.ErrorCodeResultsPending, "result is still being graded")
+	}
+
+	filter := &models.BenchmarkPeerFilter{ExamSittingID: result.ExamSittingID, QuizType: result.QuizType}
+	peerGroup := models.BenchmarkByExamSitting
+	key := "sitting:"
+	if result.ExamSittingID != nil {
+		key += result.ExamSittingID.Hex()
+	} else {
+		peerGroup = models.BenchmarkByQuizType
+		key = "type:" + string(result.QuizType)
+	}
+
+	cached, err := s.distribution(ctx, key, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	peerCount := int64(0)
+	for _, bucket := range cached.distribution.Scores {
+		peerCount += bucket.Count
+	}
+	if peerCount < models.ResultBenchmarkMinPeers {
+		return nil, models.ConflictError("not enough results to compare with yet")
+	}
+
+	return &models.ResultBenchmark{
+		ResultID:         result.ID,
+		QuizType:         result.QuizType,
+		PeerGroup:        peerGroup,
+		ExamSittingID:    result.ExamSittingID,
+		PeerCount:        peerCount,
+		ScorePercentage:  result.ScorePercentage,
+		ScorePercentile:  percentileRank(cached.distribution.Scores, benchmarkScoreBucket, math.Min(result.ScorePercentage, 100)),
+		ScorePercentiles: histogramPercentiles(cached.distribution.Scores, benchmarkScoreBucket),
+		TimeUsedSeconds:  result.TimeUsedSeconds,
+		TimePercentile:   100 - percentileRank(cached.distribution.Times, benchmarkTimeBucket, float64(result.TimeUsedSeconds)),
+		TimePercentiles:  histogramPercentiles(cached.distribution.Times, benchmarkTimeBucket),
+		GeneratedAt:      cached.generatedAt,
+	}, nil
+}
+
+// distribution returns the peer group's cached histograms, aggregating them when missing or expired
+func (s *resultBenchmarkService) distribution(ctx context.Context, key string, filter *models.BenchmarkPeerFilter) (cachedDistribution, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	for k, entry := range s.distributions {
+		if !now.Before(entry.expiresAt) {
+			delete(s.distributions, k)
+		}
+	}
+	entry, ok := s.distributions[key]
+	s.mu.Unlock()
+	if ok {
+		return entry, nil
+	}
+
+	if filter.ExamSittingID == nil {
+		since := now.Add(-benchmarkPracticeLookback)
+		filter.Since = &since
+	}
+	distribution, err := s.sessionRepo.GetBenchmarkDistribution(ctx, filter)
+	if err != nil {
+		return cachedDistribution{}, err
+	}
+
+	entry = cachedDistribution{distribution: distribution, generatedAt: now, expiresAt: now.Add(resultBenchmarkTTL)}
+	s.mu.Lock()
+	s.distributions[key] = entry
+	s.mu.Unlock()
+	return entry, nil
+}
+
+// histogramPercentiles estimates percentiles from a histogram, interpolating within buckets
+func histogramPercentiles(buckets []models.BenchmarkBucket, width float64) models.BenchmarkPercentiles {
+	var total int64
+	for _, bucket := range buckets {
+		total += bucket.Count
+	}
+
+	at := func(p float64) float64 {
+		if total == 0 {
+			return 0
+		}
+		rank := p / 100 * float64(total)
+		cumulative := 0.0
+		for _, bucket := range buckets {
+			count := float64(bucket.Count)
+			if cumulative+count >= rank {
+				return math.Round((bucket.Value+width*(rank-cumulative)/count)*10) / 10
+			}
+			cumulative += count
+		}
+		last := buckets[len(buckets)-1]
+		return last.Value + width
+	}
+
+	return models.BenchmarkPercentiles{P10: at(10), P25: at(25), P50: at(50), P75: at(75), P90: at(90)}
+}
+
+// percentileRank is the share of a histogram's values below value, as a percentage
+func percentileRank(buckets []models.BenchmarkBucket, width, value float64) float64 {
+	var total int64
+	below := 0.0
+	for _, bucket := range buckets {
+		total += bucket.Count
+		switch {
+		case bucket.Value+width <= value:
+			below += float64(bucket.Count)
+		case bucket.Value < value:
+			below += float64(bucket.Count) * (value - bucket.Value) / width
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(below/float64(total)*1000) / 10
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrResultsNotReleased is returned when a student asks for a result before its sitting releases results
+var ErrResultsNotReleased = models.NewCodedError(http.StatusForbidden, models.ErrorCodeResultsPending, "results have not been released yet")
+
+// resultReleaseChecker applies exam sittings' result embargoes to results shown to students.
+// Sittings are cached for the checker's lifetime, so create one per request.
+type resultReleaseChecker struct {
+	examRepo repository.ExamRepository
+	now      time.Time
+	sittings map[primitive.ObjectID]*models.ExamSitting
+}
+
+func newResultReleaseChecker(examRepo repository.ExamRepository) *resultReleaseChecker {
+	return &resultReleaseChecker{
+		examRepo: examRepo,
+		now:      time.Now(),
+		sittings: make(map[primitive.ObjectID]*models.ExamSitting),
+	}
+}
+
+// withheld reports whether results for the sitting are still embargoed, and when they will be released
+// if scheduled. Results of deleted sittings are released; lookup failures keep results withheld.
+func (c *resultReleaseChecker) withheld(ctx context.Context, sittingID *primitive.ObjectID) (bool, *time.Time) {
+	if sittingID == nil {
+		return false, nil
+	}
+
+	sitting, cached := c.sittings[*sittingID]
+	if !cached {
+		var err error
+		sitting, err = c.examRepo.GetSitting(ctx, *sittingID)
+		if err != nil && !errors.Is(err, repository.ErrSittingNotFound) {
+			return true, nil
+		}
+		c.sittings[*sittingID] = sitting
+	}
+
+	if sitting == nil || sitting.ResultsReleased(c.now) {
+		return false, nil
+	}
+	return true, sitting.ResultsAvailableAt()
+}
+
+func (c *resultReleaseChecker) applyDetailed(ctx context.Context, results []models.DetailedQuizResult) {
+	for i := range results {
+		if withheld, releaseAt := c.withheld(ctx, results[i].ExamSittingID); withheld {
+			results[i].Withhold(releaseAt)
+		}
+	}
+}
+
+func (c *resultReleaseChecker) apply(ctx context.Context, results []models.QuizResult) {
+	for i := range results {
+		if withheld, releaseAt := c.withheld(ctx, results[i].ExamSittingID); withheld {
+			results[i].Withhold(releaseAt)
+		}
+	}
+}
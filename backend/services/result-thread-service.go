@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ResultThreadService interface {
+	// Student side
+	GetStudentThread(ctx context.Context, studentID, resultID primitive.ObjectID) (*models.ResultThreadResponse, error)
+	PostStudentMessage(ctx context.Context, studentID, resultID primitive.ObjectID, req *models.PostThreadMessageRequest) (*models.ThreadMessage, error)
+	ListStudentThreads(ctx context.Context, studentID primitive.ObjectID, req *models.ListResultThreadsRequest) (*models.ListResultThreadsResponse, error)
+
+	// Instructor side
+	ListThreads(ctx context.Context, req *models.ListResultThreadsRequest) (*models.ListResultThreadsResponse, error)
+	GetThread(ctx context.Context, instructorID, threadID primitive.ObjectID) (*models.ResultThreadResponse, error)
+	PostInstructorMessage(ctx context.Context, instructorID, threadID primitive.ObjectID, req *models.PostThreadMessageRequest) (*models.ThreadMessage, error)
+}
+
+type resultThreadService struct {
+	threadRepo       repository.ResultThreadRepository
+	sessionRepo      repository.QuizSessionRepository
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	examRepo         repository.ExamRepository
+}
+
+func NewResultThreadService(
+	threadRepo repository.ResultThreadRepository,
+	sessionRepo repository.QuizSessionRepository,
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	examRepo repository.ExamRepository,
+) ResultThreadService {
+	return &resultThreadService{
+		threadRepo:       threadRepo,
+		sessionRepo:      sessionRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		examRepo:         examRepo,
+	}
+}
+
+func (s *resultThreadService) GetStudentThread(ctx context.Context, studentID, resultID primitive.ObjectID) (*models.ResultThreadResponse, error) {
+	if _, err := s.getOwnedResult(ctx, studentID, resultID); err != nil {
+		return nil, err
+	}
+
+	thread, err := s.threadRepo.GetByResultID(ctx, resultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrResultThreadNotFound) {
+			// No discussion yet
+			return &models.ResultThreadResponse{Messages: []models.ThreadMessage{}}, nil
+		}
+		return nil, fmt.Errorf("failed to get result thread: %w", err)
+	}
+
+	return s.openThread(ctx, thread, models.ThreadRoleStudent, studentID)
+}
+
+func (s *resultThreadService) PostStudentMessage(ctx context.Context, studentID, resultID primitive.ObjectID, req *models.PostThreadMessageRequest) (*models.ThreadMessage, error) {
+	result, err := s.getOwnedResult(ctx, studentID, resultID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &models.ResultThread{
+		ResultID:    resultID,
+		StudentID:   studentID,
+		ResultTitle: result.Title,
+	}
+	if err := s.threadRepo.GetOrCreate(ctx, thread); err != nil {
+		return nil, fmt.Errorf("failed to open result thread: %w", err)
+	}
+
+	message, err := s.buildMessage(ctx, result, studentID, models.ThreadRoleStudent, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.threadRepo.AddMessage(ctx, thread, message); err != nil {
+		return nil, fmt.Errorf("failed to post message: %w", err)
+	}
+
+	// Instructors who already joined the thread get notified; others see it in the unread thread list
+	for _, instructorID := range thread.InstructorIDs {
+		s.notify(ctx, instructorID, thread, message)
+	}
+
+	return message, nil
+}
+
+func (s *resultThreadService) ListStudentThreads(ctx context.Context, studentID primitive.ObjectID, req *models.ListResultThreadsRequest) (*models.ListResultThreadsResponse, error) {
+	return s.listThreads(ctx, &studentID, req)
+}
+
+func (s *resultThreadService) ListThreads(ctx context.Context, req *models.ListResultThreadsRequest) (*models.ListResultThreadsResponse, error) {
+	return s.listThreads(ctx, nil, req)
+}
+
+func (s *resultThreadService) GetThread(ctx context.Context, instructorID, threadID primitive.ObjectID) (*models.ResultThreadResponse, error) {
+	thread, err := s.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.openThread(ctx, thread, models.ThreadRoleInstructor, instructorID)
+}
+
+func (s *resultThreadService) PostInstructorMessage(ctx context.Context, instructorID, threadID primitive.ObjectID, req *models.PostThreadMessageRequest) (*models.ThreadMessage, error) {
+	thread, err := s.threadRepo.GetByID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, thread.ResultID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.buildMessage(ctx, result, instructorID, models.ThreadRoleInstructor, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.threadRepo.AddMessage(ctx, thread, message); err != nil {
+		return nil, fmt.Errorf("failed to post message: %w", err)
+	}
+
+	s.notify(ctx, thread.StudentID, thread, message)
+
+	return message, nil
+}
+
+// Private helper methods
+
+// getOwnedResult loads a detailed result, hiding results that belong to someone else
+func (s *resultThreadService) getOwnedResult(ctx context.Context, studentID, resultID primitive.ObjectID) (*models.DetailedQuizResult, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.BelongsTo(studentID) {
+		return nil, repository.ErrResultNotFound
+	}
+
+	// Reviewing answers would reveal scores before the sitting releases them
+	if withheld, _ := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		return nil, ErrResultsNotReleased
+	}
+
+	return result, nil
+}
+
+// openThread returns the thread with its messages and marks it read for the viewer
+func (s *resultThreadService) openThread(ctx context.Context, thread *models.ResultThread, role models.ThreadRole, viewerID primitive.ObjectID) (*models.ResultThreadResponse, error) {
+	messages, err := s.threadRepo.GetMessages(ctx, thread.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread messages: %w", err)
+	}
+
+	if err := s.threadRepo.MarkRead(ctx, thread.ID, role); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark result thread as read", "error", err)
+	}
+	if err := s.notificationRepo.MarkEntityRead(ctx, viewerID, "result_thread", thread.ID.Hex()); err != nil {
+		slog.ErrorContext(ctx, "Failed to mark thread notifications as read", "error", err)
+	}
+
+	if messages == nil {
+		messages = []models.ThreadMessage{}
+	}
+
+	return &models.ResultThreadResponse{
+		Thread:   thread,
+		Messages: messages,
+	}, nil
+}
+
+func (s *resultThreadService) buildMessage(ctx context.Context, result *models.DetailedQuizResult, authorID primitive.ObjectID, role models.ThreadRole, req *models.PostThreadMessageRequest) (*models.ThreadMessage, error) {
+	message := &models.ThreadMessage{
+		AuthorID:   authorID,
+		AuthorName: s.displayName(ctx, authorID),
+		AuthorRole: role,
+		Body:       req.Body,
+	}
+
+	if req.QuestionID == "" {
+		return message, nil
+	}
+
+	questionID, err := primitive.ObjectIDFromHex(req.QuestionID)
+	if err != nil {
+		return nil, models.BadRequestError("invalid question ID")
+	}
+
+	for _, questionResult := range result.QuestionResults {
+		if questionResult.QuestionID == questionID {
+			message.QuestionID = &questionID
+			message.QuestionTitle = questionResult.Title
+			return message, nil
+		}
+	}
+
+	return nil, models.BadRequestError("question is not part of this result")
+}
+
+func (s *resultThreadService) listThreads(ctx context.Context, studentID *primitive.ObjectID, req *models.ListResultThreadsRequest) (*models.ListResultThreadsResponse, error) {
+	threads, total, err := s.threadRepo.List(ctx, studentID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list result threads: %w", err)
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListResultThreadsResponse{
+		Threads:    threads,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// notify records an in-platform notification about a new thread message; failures are logged only
+func (s *resultThreadService) notify(ctx context.Context, recipientID primitive.ObjectID, thread *models.ResultThread, message *models.ThreadMessage) {
+	if recipientID == message.AuthorID {
+		return
+	}
+
+	text := fmt.Sprintf("%s replied about %s", message.AuthorName, thread.ResultTitle)
+	if message.QuestionTitle != "" {
+		text = fmt.Sprintf("%s replied about \"%s\" in %s", message.AuthorName, message.QuestionTitle, thread.ResultTitle)
+	}
+
+	notification := &models.Notification{
+		UserID:     recipientID,
+		Type:       models.NotificationThreadMessage,
+		Title:      "New message about your quiz result",
+		Message:    text,
+		EntityType: "result_thread",
+		EntityID:   thread.ID.Hex(),
+	}
+	if message.AuthorRole == models.ThreadRoleStudent {
+		notification.Title = "New question from a student"
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		slog.ErrorContext(ctx, "Failed to create thread notification", "error", err)
+	}
+}
+
+// displayName looks up a user's full name across the user collections
+func (s *resultThreadService) displayName(ctx context.Context, userID primitive.ObjectID) string {
+	if mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, userID); err == nil {
+		return mahasiswa.FullName
+	}
+	if admin, err := s.userRepo.GetAdminByID(ctx, userID); err == nil {
+		return admin.FullName
+	}
+	if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+		return user.FullName
+	}
+	return "Unknown User"
+}
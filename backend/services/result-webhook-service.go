@@ -0,0 +1,509 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// resultWebhookBatchSize caps the results sent in one delivery when a whole sitting is released
+const resultWebhookBatchSize = 100
+
+// resultWebhookRetryDelays are the waits before each retry of a failed delivery
+var resultWebhookRetryDelays = []time.Duration{10 * time.Second, time.Minute}
+
+type ResultWebhookService interface {
+	// Admin lifecycle
+	CreateWebhook(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateResultWebhookRequest) (*models.ResultWebhookSecretResponse, error)
+	ListWebhooks(ctx context.Context) (*models.ListResultWebhooksResponse, error)
+	GetWebhook(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error)
+	UpdateWebhook(ctx context.Context, id primitive.ObjectID, req *models.UpdateResultWebhookRequest) (*models.ResultWebhook, error)
+	DeleteWebhook(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error)
+	RotateSecret(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhookSecretResponse, error)
+	PingWebhook(ctx context.Context, id primitive.ObjectID) (*models.PingResultWebhookResponse, error)
+
+	// Delivery; both return immediately and deliver in the background
+	ResultFinalized(result *models.DetailedQuizResult)
+	SittingReleased(sittingID primitive.ObjectID)
+	SendReleasedResults(ctx context.Context) (int, error)
+}
+
+type resultWebhookService struct {
+	webhookRepo repository.ResultWebhookRepository
+	examRepo    repository.ExamRepository
+	sessionRepo repository.QuizSessionRepository
+	userRepo    repository.UserRepository
+	client      *http.Client
+	allowHTTP   bool
+	tasks       *utils.BackgroundTasks
+}
+
+func NewResultWebhookService(
+	webhookRepo repository.ResultWebhookRepository,
+	examRepo repository.ExamRepository,
+	sessionRepo repository.QuizSessionRepository,
+	userRepo repository.UserRepository,
+	cfg models.WebhookConfig,
+	tasks *utils.BackgroundTasks,
+) ResultWebhookService {
+	return &resultWebhookService{
+		webhookRepo: webhookRepo,
+		examRepo:    examRepo,
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		allowHTTP:   cfg.AllowHTTP,
+		tasks:       tasks,
+	}
+}
+
+func (s *resultWebhookService) CreateWebhook(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateResultWebhookRequest) (*models.ResultWebhookSecretResponse, error) {
+	webhookURL, err := s.validateURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	group := strings.TrimSpace(req.Group)
+	if group == "" {
+		return nil, models.BadRequestError("group is required")
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := &models.ResultWebhook{
+		Name:           strings.TrimSpace(req.Name),
+		URL:            webhookURL,
+		GroupBy:        req.GroupBy,
+		Group:          group,
+		Secret:         secret,
+		CreatedBy:      adminID,
+		CreatedByEmail: adminEmail,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create result webhook: %w", err)
+	}
+
+	return &models.ResultWebhookSecretResponse{
+		Webhook: webhook,
+		Secret:  secret,
+		Message: "Webhook created. Store the signing secret now; it will not be shown again",
+	}, nil
+}
+
+func (s *resultWebhookService) ListWebhooks(ctx context.Context) (*models.ListResultWebhooksResponse, error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list result webhooks: %w", err)
+	}
+
+	if webhooks == nil {
+		webhooks = []models.ResultWebhook{}
+	}
+	return &models.ListResultWebhooksResponse{Webhooks: webhooks, Total: len(webhooks)}, nil
+}
+
+func (s *resultWebhookService) GetWebhook(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error) {
+	return s.webhookRepo.GetByID(ctx, id)
+}
+
+func (s *resultWebhookService) UpdateWebhook(ctx context.Context, id primitive.ObjectID, req *models.UpdateResultWebhookRequest) (*models.ResultWebhook, error) {
+	updates := bson.M{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		webhookURL, err := s.validateURL(*req.URL)
+		if err != nil {
+			return nil, err
+		}
+		updates["url"] = webhookURL
+	}
+	if req.GroupBy != nil {
+		updates["group_by"] = *req.GroupBy
+	}
+	if req.Group != nil {
+		group := strings.TrimSpace(*req.Group)
+		if group == "" {
+			return nil, models.BadRequestError("group is required")
+		}
+		updates["group"] = group
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	if len(updates) == 0 {
+		return s.webhookRepo.GetByID(ctx, id)
+	}
+
+	webhook, err := s.webhookRepo.Update(ctx, id, updates)
+	if err != nil {
+		if errors.Is(err, repository.ErrResultWebhookNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update result webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook removes the webhook and returns what was deleted, for the audit log
+func (s *resultWebhookService) DeleteWebhook(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// RotateSecret issues a new signing secret; deliveries are signed with it from now on
+func (s *resultWebhookService) RotateSecret(ctx context.Context, id primitive.ObjectID) (*models.ResultWebhookSecretResponse, error) {
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook, err := s.webhookRepo.Update(ctx, id, bson.M{"secret": secret})
+	if err != nil {
+		if errors.Is(err, repository.ErrResultWebhookNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	return &models.ResultWebhookSecretResponse{
+		Webhook: webhook,
+		Secret:  secret,
+		Message: "Signing secret rotated. Store it now; it will not be shown again",
+	}, nil
+}
+
+// PingWebhook sends one signed delivery without results so a department can check its receiver.
+// It is attempted once, even for inactive webhooks, and counts toward the delivery tracking.
+func (s *resultWebhookService) PingWebhook(ctx context.Context, id primitive.ObjectID) (*models.PingResultWebhookResponse, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := s.newPayload(webhook, models.ResultWebhookPing, nil, []models.ResultWebhookSummary{})
+	statusCode, deliveryErr := s.send(ctx, webhook, payload)
+	s.recordDelivery(webhook, statusCode, deliveryErr)
+
+	response := &models.PingResultWebhookResponse{Delivered: deliveryErr == nil, StatusCode: statusCode}
+	if deliveryErr != nil {
+		response.Error = deliveryErr.Error()
+	}
+	return response, nil
+}
+
+// ResultFinalized sends a result whose sitting has already released its results. Results of
+// sittings still withheld are sent in bulk by SittingReleased when the sitting is released.
+func (s *resultWebhookService) ResultFinalized(result *models.DetailedQuizResult) {
+	if result.ExamSittingID == nil {
+		return
+	}
+
+	s.tasks.Go("result_webhooks", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		webhooks, err := s.webhookRepo.ListActive(ctx)
+		if err != nil {
+			slog.Error("Failed to load result webhooks", "error", err)
+			return
+		}
+		if len(webhooks) == 0 {
+			return
+		}
+
+		sitting, err := s.examRepo.GetSitting(ctx, *result.ExamSittingID)
+		if err != nil {
+			return
+		}
+
+		students := make(map[primitive.ObjectID]*models.UserMahasiswa)
+		summary, student := s.summarize(ctx, sitting, result, students)
+		if student == nil {
+			return
+		}
+
+		for i := range webhooks {
+			if webhookMatches(&webhooks[i], student) {
+				s.deliver(ctx, &webhooks[i], webhookSitting(sitting), []models.ResultWebhookSummary{summary})
+			}
+		}
+	})
+}
+
+// SittingReleased sends every result of a sitting whose withheld results were just released.
+// A sitting is sent once per release, however many times this is called.
+func (s *resultWebhookService) SittingReleased(sittingID primitive.ObjectID) {
+	s.tasks.Go("result_webhooks", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		if err := s.sendSitting(ctx, sittingID); err != nil {
+			slog.Error("Failed to send sitting results to webhooks", "sitting_id", sittingID.Hex(), "error", err)
+		}
+	})
+}
+
+// SendReleasedResults sends sittings whose results were released on schedule, or whose manual release
+// wasn't sent, returning how many were handled without an error
+func (s *resultWebhookService) SendReleasedResults(ctx context.Context) (int, error) {
+	sittings, err := s.examRepo.ListSittingsAwaitingResultsWebhook(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to check released sittings for webhooks: %w", err)
+	}
+
+	sent := 0
+	for _, sitting := range sittings {
+		if err := s.sendSitting(ctx, sitting.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to send sitting results to webhooks", "sitting_id", sitting.ID.Hex(), "error", err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Private helper methods
+
+// sendSitting claims a released sitting and delivers its results to every matching webhook in batches
+func (s *resultWebhookService) sendSitting(ctx context.Context, sittingID primitive.ObjectID) error {
+	sitting, err := s.examRepo.GetSitting(ctx, sittingID)
+	if err != nil {
+		return err
+	}
+	if !sitting.ResultsReleased(time.Now()) {
+		return nil
+	}
+
+	claimed, err := s.examRepo.ClaimResultsWebhook(ctx, sittingID, time.Now())
+	if err != nil || !claimed {
+		return err
+	}
+
+	webhooks, err := s.webhookRepo.ListActive(ctx)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	results, err := s.sessionRepo.GetSittingResults(ctx, sittingID)
+	if err != nil {
+		return err
+	}
+
+	students := make(map[primitive.ObjectID]*models.UserMahasiswa)
+	summaries := make([]models.ResultWebhookSummary, 0, len(results))
+	resultStudents := make([]*models.UserMahasiswa, 0, len(results))
+	for i := range results {
+		// Results with essays to grade are sent on their own once graded
+		if results[i].PendingEssays > 0 {
+			continue
+		}
+		summary, student := s.summarize(ctx, sitting, &results[i], students)
+		if student == nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+		resultStudents = append(resultStudents, student)
+	}
+
+	for i := range webhooks {
+		var matching []models.ResultWebhookSummary
+		for j, student := range resultStudents {
+			if webhookMatches(&webhooks[i], student) {
+				matching = append(matching, summaries[j])
+			}
+		}
+
+		for start := 0; start < len(matching); start += resultWebhookBatchSize {
+			end := min(start+resultWebhookBatchSize, len(matching))
+			s.deliver(ctx, &webhooks[i], webhookSitting(sitting), matching[start:end])
+		}
+	}
+	return nil
+}
+
+// summarize builds a result's summary with the student's department. The student is nil for
+// results of users who aren't mahasiswa, or no longer exist, since they belong to no department.
+func (s *resultWebhookService) summarize(ctx context.Context, sitting *models.ExamSitting, result *models.DetailedQuizResult, students map[primitive.ObjectID]*models.UserMahasiswa) (models.ResultWebhookSummary, *models.UserMahasiswa) {
+	student, cached := students[result.UserID]
+	if !cached {
+		student, _ = s.userRepo.GetMahasiswaByID(ctx, result.UserID)
+		students[result.UserID] = student
+	}
+	if student == nil {
+		return models.ResultWebhookSummary{}, nil
+	}
+
+	summary := models.ResultWebhookSummary{
+		ResultID:         result.ID.Hex(),
+		UserID:           result.UserID.Hex(),
+		NIM:              student.NIM,
+		FullName:         student.FullName,
+		Faculty:          student.Faculty,
+		Major:            student.Major,
+		FinalScore:       result.FinalScore,
+		TotalPoints:      result.TotalPoints,
+		ScorePercentage:  result.ScorePercentage,
+		Scaled:           result.Scaling != nil,
+		CorrectAnswers:   result.CorrectAnswers,
+		TotalQuestions:   result.TotalQuestions,
+		CompletionStatus: result.CompletionStatus,
+		SubmittedAt:      result.SubmittedAt,
+	}
+	if sitting.PassMark > 0 {
+		passed := result.ScorePercentage >= sitting.PassMark
+		summary.Passed = &passed
+	}
+	return summary, student
+}
+
+// deliver sends results to one webhook, retrying failed attempts, and records the final outcome
+func (s *resultWebhookService) deliver(ctx context.Context, webhook *models.ResultWebhook, sitting *models.ResultWebhookSitting, results []models.ResultWebhookSummary) {
+	payload := s.newPayload(webhook, models.ResultWebhookFinalized, sitting, results)
+
+	statusCode, err := s.send(ctx, webhook, payload)
+	for _, delay := range resultWebhookRetryDelays {
+		if err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			s.recordDelivery(webhook, statusCode, err)
+			return
+		case <-time.After(delay):
+		}
+		statusCode, err = s.send(ctx, webhook, payload)
+	}
+
+	if err != nil {
+		slog.Warn("Failed to deliver results to webhook", "webhook_id", webhook.ID.Hex(), "error", err)
+	}
+	s.recordDelivery(webhook, statusCode, err)
+}
+
+func (s *resultWebhookService) newPayload(webhook *models.ResultWebhook, event models.ResultWebhookEvent, sitting *models.ResultWebhookSitting, results []models.ResultWebhookSummary) *models.ResultWebhookPayload {
+	return &models.ResultWebhookPayload{
+		Event:      event,
+		DeliveryID: primitive.NewObjectID().Hex(),
+		WebhookID:  webhook.ID.Hex(),
+		GroupBy:    webhook.GroupBy,
+		Group:      webhook.Group,
+		Sitting:    sitting,
+		Results:    results,
+		SentAt:     time.Now(),
+	}
+}
+
+// send makes one signed delivery attempt; retries of a delivery reuse its ID so receivers can ignore repeats
+func (s *resultWebhookService) send(ctx context.Context, webhook *models.ResultWebhook, payload *models.ResultWebhookPayload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "z0nata-webhooks")
+	req.Header.Set(utils.WebhookEventHeader, string(payload.Event))
+	req.Header.Set(utils.WebhookDeliveryHeader, payload.DeliveryID)
+	req.Header.Set(utils.WebhookTimestampHeader, fmt.Sprintf("%d", timestamp))
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordDelivery tracks the outcome on the webhook; it must not be cut short by the delivery's context
+func (s *resultWebhookService) recordDelivery(webhook *models.ResultWebhook, statusCode int, deliveryErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.webhookRepo.RecordDelivery(ctx, webhook.ID, statusCode, deliveryErr, time.Now()); err != nil {
+		slog.Error("Failed to record webhook delivery", "error", err)
+	}
+}
+
+// validateURL requires an absolute https URL, or http when allowed by configuration
+func (s *resultWebhookService) validateURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "", models.BadRequestError("webhook URL must be an absolute URL")
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !s.allowHTTP {
+			return "", models.BadRequestError("webhook URL must use https")
+		}
+	default:
+		return "", models.BadRequestError("webhook URL must use https")
+	}
+	if parsed.User != nil {
+		return "", models.BadRequestError("webhook URL must not contain credentials")
+	}
+	return raw, nil
+}
+
+// webhookMatches reports whether the student belongs to the webhook's department
+func webhookMatches(webhook *models.ResultWebhook, student *models.UserMahasiswa) bool {
+	var group string
+	switch webhook.GroupBy {
+	case models.CohortByFaculty:
+		group = student.Faculty
+	case models.CohortByMajor:
+		group = student.Major
+	default:
+		return false
+	}
+	return group != "" && strings.EqualFold(strings.TrimSpace(group), webhook.Group)
+}
+
+func webhookSitting(sitting *models.ExamSitting) *models.ResultWebhookSitting {
+	return &models.ResultWebhookSitting{
+		ID:       sitting.ID.Hex(),
+		Name:     sitting.Name,
+		QuizType: sitting.QuizType,
+		PassMark: sitting.PassMark,
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Results are compared with practice attempts of the same quiz type from about the last month
+const scoreComparisonWindow = 30 * 24 * time.Hour
+
+// GetResult returns one of the user's results, withheld while its sitting's results are embargoed,
+// along with how it compares to recent results of the same quiz type
+func (s *quizSessionService) GetResult(ctx context.Context, userID, resultID primitive.ObjectID) (*models.QuizResultResponse, error) {
+	result, err := s.sessionRepo.GetDetailedResultByID(ctx, resultID)
+	if err != nil {
+		return nil, err
+	}
+	if !result.BelongsTo(userID) {
+		return nil, repository.ErrResultNotFound
+	}
+
+	response := &models.QuizResultResponse{Result: *result}
+	if withheld, releaseAt := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		response.Result.Withhold(releaseAt)
+		return response, nil
+	}
+
+	response.Result.HideAnswerKey()
+	newRemediationBuilder(s.moduleRepo).applyOne(ctx, &response.Result)
+	response.Comparison = s.compareScore(ctx, &response.Result)
+	return response, nil
+}
+
+// compareScore places a released result among recent results of its quiz type. Comparisons are
+// extras on the result, so nothing is returned when they fail, the result is still being graded,
+// or there are too few peers for the aggregates to stay anonymous.
+func (s *quizSessionService) compareScore(ctx context.Context, result *models.DetailedQuizResult) *models.ScoreComparison {
+	if result.PendingEssays > 0 {
+		return nil
+	}
+
+	score := math.Round(result.ScorePercentage*100) / 100
+	comparison, err := s.sessionRepo.GetScoreComparison(ctx, result.QuizType, time.Now().Add(-scoreComparisonWindow), score)
+	if err != nil || comparison.PeerCount < models.ResultBenchmarkMinPeers {
+		return nil
+	}
+
+	comparison.WindowDays = int(scoreComparisonWindow / (24 * time.Hour))
+	comparison.Percentile = math.Round(comparison.Percentile*10) / 10
+	comparison.MeanScore = math.Round(comparison.MeanScore*10) / 10
+	comparison.MedianScore = math.Round(comparison.MedianScore*10) / 10
+	return comparison
+}
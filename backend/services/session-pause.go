@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PauseSession freezes an in-progress session's timer on an admin's say-so, e.g. while a proctor
+// deals with an incident or during an outage. The student cannot answer or submit until it is resumed.
+func (s *quizSessionService) PauseSession(ctx context.Context, adminID primitive.ObjectID, adminEmail string, sessionID primitive.ObjectID, req *models.SessionPauseRequest) (*models.GetSessionResponse, error) {
+	session, err := s.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.QuizInProgress {
+		return nil, ErrSessionNotActive
+	}
+	if session.PausedAt != nil {
+		return nil, models.ConflictError("quiz session is already paused")
+	}
+
+	timeRemaining := s.calculateTimeRemaining(session)
+	if timeRemaining <= 0 {
+		return nil, ErrSessionExpired
+	}
+
+	event := models.SessionPauseEvent{
+		Action:        models.SessionPaused,
+		Reason:        strings.TrimSpace(req.Reason),
+		At:            time.Now(),
+		By:            adminID,
+		ByEmail:       adminEmail,
+		TimeRemaining: timeRemaining,
+	}
+	paused, err := s.sessionRepo.PauseSession(ctx, session.ID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !paused {
+		return nil, models.ConflictError("quiz session is already paused")
+	}
+
+	s.publishLive(session.SessionToken, models.QuizLiveEvent{
+		Type:          models.QuizLivePaused,
+		TimeRemaining: &timeRemaining,
+		Status:        models.QuizInProgress,
+		Paused:        true,
+		Reason:        event.Reason,
+	})
+	return s.pauseResponse(ctx, sessionID)
+}
+
+// ResumePausedSession restarts a paused session's timer with the time it had left when paused
+func (s *quizSessionService) ResumePausedSession(ctx context.Context, adminID primitive.ObjectID, adminEmail string, sessionID primitive.ObjectID, req *models.SessionPauseRequest) (*models.GetSessionResponse, error) {
+	session, err := s.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.QuizInProgress {
+		return nil, ErrSessionNotActive
+	}
+	if session.PausedAt == nil {
+		return nil, models.ConflictError("quiz session is not paused")
+	}
+
+	timeRemaining := s.calculateTimeRemaining(session)
+	event := models.SessionPauseEvent{
+		Action:        models.SessionResumed,
+		Reason:        strings.TrimSpace(req.Reason),
+		At:            time.Now(),
+		By:            adminID,
+		ByEmail:       adminEmail,
+		TimeRemaining: timeRemaining,
+	}
+	resumed, err := s.sessionRepo.ResumePausedSession(ctx, session.ID, *session.PausedAt, event)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, models.ConflictError("quiz session is not paused")
+	}
+
+	deadline := event.At.Add(time.Duration(timeRemaining) * time.Second)
+	s.publishLive(session.SessionToken, models.QuizLiveEvent{
+		Type:          models.QuizLiveResumed,
+		TimeRemaining: &timeRemaining,
+		Deadline:      &deadline,
+		Status:        models.QuizInProgress,
+		Reason:        event.Reason,
+	})
+	return s.pauseResponse(ctx, sessionID)
+}
+
+func (s *quizSessionService) pauseResponse(ctx context.Context, sessionID primitive.ObjectID) (*models.GetSessionResponse, error) {
+	updated, err := s.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GetSessionResponse{
+		Session:       *updated,
+		TimeRemaining: s.calculateTimeRemaining(updated),
+	}, nil
+}
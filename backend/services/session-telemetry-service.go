@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SessionTelemetryService interface {
+	Ingest(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.IngestTelemetryRequest) (*models.IngestTelemetryResponse, error)
+	GetSummary(ctx context.Context, req *models.TelemetrySummaryRequest) (*models.TelemetrySummaryResponse, error)
+}
+
+type sessionTelemetryService struct {
+	telemetryRepo repository.SessionTelemetryRepository
+	sessionRepo   repository.QuizSessionRepository
+}
+
+func NewSessionTelemetryService(telemetryRepo repository.SessionTelemetryRepository, sessionRepo repository.QuizSessionRepository) SessionTelemetryService {
+	return &sessionTelemetryService{
+		telemetryRepo: telemetryRepo,
+		sessionRepo:   sessionRepo,
+	}
+}
+
+// Ingest stores a batch of client telemetry for the caller's session. Batches are accepted after
+// the session ends too, since clients flush what they buffered once the quiz is submitted.
+func (s *sessionTelemetryService) Ingest(ctx context.Context, userID primitive.ObjectID, sessionToken string, req *models.IngestTelemetryRequest) (*models.IngestTelemetryResponse, error) {
+	session, err := s.sessionRepo.GetSessionByToken(ctx, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsTakenBy(userID) {
+		return nil, repository.ErrSessionNotFound
+	}
+
+	for i := range req.Events {
+		req.Events[i].Endpoint = strings.TrimSpace(req.Events[i].Endpoint)
+		req.Events[i].Detail = strings.TrimSpace(req.Events[i].Detail)
+	}
+
+	batch := &models.SessionTelemetryBatch{
+		SessionID: session.ID,
+		UserID:    userID,
+		QuizType:  session.QuizType,
+		IsGuest:   req.IsGuest,
+		UserAgent: req.UserAgent,
+		Events:    req.Events,
+	}
+	if req.SentAt != nil {
+		offset := time.Since(*req.SentAt).Milliseconds()
+		batch.ClockOffsetMs = &offset
+	}
+	if err := s.telemetryRepo.Insert(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to store telemetry: %w", err)
+	}
+
+	return &models.IngestTelemetryResponse{Accepted: len(req.Events)}, nil
+}
+
+// GetSummary aggregates telemetry by event type. When a session is chosen, its event timeline is
+// returned as well, to check a student's report against what their client recorded.
+func (s *sessionTelemetryService) GetSummary(ctx context.Context, req *models.TelemetrySummaryRequest) (*models.TelemetrySummaryResponse, error) {
+	from, to, err := parseDateRange(req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &models.TelemetryFilter{
+		QuizType: req.QuizType,
+		Type:     req.Type,
+		From:     from,
+		To:       to,
+	}
+	if req.SessionID != "" {
+		sessionID, err := primitive.ObjectIDFromHex(req.SessionID)
+		if err != nil {
+			return nil, models.BadRequestError("invalid session ID")
+		}
+		filter.SessionID = &sessionID
+	} else if token := strings.TrimSpace(req.SessionToken); token != "" {
+		session, err := s.sessionRepo.GetSessionByToken(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		filter.SessionID = &session.ID
+	}
+
+	summary, err := s.telemetryRepo.Summarize(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize telemetry: %w", err)
+	}
+
+	response := &models.TelemetrySummaryResponse{
+		SessionID: filter.SessionID,
+		Summary:   summary,
+	}
+	if filter.SessionID != nil {
+		response.Events, err = s.telemetryRepo.ListSessionEvents(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list session telemetry: %w", err)
+		}
+	}
+
+	return response, nil
+}
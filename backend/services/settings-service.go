@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+)
+
+type SettingsService interface {
+	LoadSettings(ctx context.Context) error
+	StartAutoRefresh(ctx context.Context, interval time.Duration)
+	Current() models.RuntimeSettings
+	GetSettings(ctx context.Context) (*models.RuntimeSettings, error)
+	UpdateSettings(ctx context.Context, adminEmail string, req *models.UpdateRuntimeSettingsRequest) (*models.RuntimeSettings, error)
+}
+
+type settingsService struct {
+	settingsRepo repository.SettingsRepository
+	defaults     models.RuntimeSettings
+
+	mu      sync.RWMutex
+	current models.RuntimeSettings
+}
+
+// NewSettingsService serves the runtime settings, starting from the values the environment configures
+func NewSettingsService(settingsRepo repository.SettingsRepository, cfg models.Config) SettingsService {
+	defaults := models.RuntimeSettings{
+		ActivityLogRetentionDays:     cfg.Scheduler.ActivityLogRetentionDays,
+		CertificatePassingScore:      cfg.Certificates.PassingScore,
+		CustomQuizMinutesPerQuestion: models.CustomQuizMinutesPerQuestion,
+	}
+	return &settingsService{
+		settingsRepo: settingsRepo,
+		defaults:     defaults,
+		current:      defaults,
+	}
+}
+
+// LoadSettings replaces the settings in force with the ones admins saved, if any
+func (s *settingsService) LoadSettings(ctx context.Context) error {
+	settings, err := s.stored(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.current = *settings
+	s.mu.Unlock()
+	return nil
+}
+
+// StartAutoRefresh reloads the settings periodically so every instance picks up changes saved on another
+func (s *settingsService) StartAutoRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.LoadSettings(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to refresh runtime settings", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Current returns the settings in force on this instance without asking the database
+func (s *settingsService) Current() models.RuntimeSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// GetSettings returns the saved settings, which instances that have not refreshed yet may not be using
+func (s *settingsService) GetSettings(ctx context.Context) (*models.RuntimeSettings, error) {
+	return s.stored(ctx)
+}
+
+func (s *settingsService) UpdateSettings(ctx context.Context, adminEmail string, req *models.UpdateRuntimeSettingsRequest) (*models.RuntimeSettings, error) {
+	// Start from what is saved rather than this instance's copy, which may not have caught up yet
+	settings, err := s.stored(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ActivityLogRetentionDays != nil {
+		settings.ActivityLogRetentionDays = *req.ActivityLogRetentionDays
+	}
+	if req.CertificatePassingScore != nil {
+		settings.CertificatePassingScore = *req.CertificatePassingScore
+	}
+	if req.CustomQuizMinutesPerQuestion != nil {
+		settings.CustomQuizMinutesPerQuestion = *req.CustomQuizMinutesPerQuestion
+	}
+	now := time.Now()
+	settings.UpdatedAt = &now
+	settings.UpdatedBy = adminEmail
+
+	if err := s.settingsRepo.Save(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	s.mu.Lock()
+	s.current = *settings
+	s.mu.Unlock()
+	return settings, nil
+}
+
+// stored returns the saved settings, or the defaults when none are saved
+func (s *settingsService) stored(ctx context.Context) (*models.RuntimeSettings, error) {
+	settings, err := s.settingsRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if settings == nil {
+		defaults := s.defaults
+		return &defaults, nil
+	}
+	return settings, nil
+}
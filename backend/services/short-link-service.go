@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Generated codes are retried this many times if they collide with an existing one
+const shortCodeAttempts = 5
+
+type ShortLinkService interface {
+	CreateShortLink(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateShortLinkRequest) (*models.ShortLink, error)
+	ListShortLinks(ctx context.Context, req *models.ListShortLinksRequest) (*models.ListShortLinksResponse, error)
+	RevokeShortLink(ctx context.Context, adminID, linkID primitive.ObjectID) (*models.ShortLink, error)
+	ResolveShortLink(ctx context.Context, code string) (*models.ShortLink, error)
+}
+
+type shortLinkService struct {
+	shortLinkRepo repository.ShortLinkRepository
+}
+
+func NewShortLinkService(shortLinkRepo repository.ShortLinkRepository) ShortLinkService {
+	return &shortLinkService{
+		shortLinkRepo: shortLinkRepo,
+	}
+}
+
+func (s *shortLinkService) CreateShortLink(ctx context.Context, adminID primitive.ObjectID, adminEmail string, req *models.CreateShortLinkRequest) (*models.ShortLink, error) {
+	link := &models.ShortLink{
+		Path:           strings.TrimSpace(req.Path),
+		Label:          strings.TrimSpace(req.Label),
+		CreatedBy:      adminID,
+		CreatedByEmail: adminEmail,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+
+	// Codes are case-insensitive so they survive being retyped from print
+	if req.Code != "" {
+		link.Code = strings.ToLower(req.Code)
+		if err := s.shortLinkRepo.Create(ctx, link); err != nil {
+			return nil, err
+		}
+		return link, nil
+	}
+
+	for attempt := 0; attempt < shortCodeAttempts; attempt++ {
+		code, err := utils.GenerateShortCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate short code: %w", err)
+		}
+		link.Code = code
+
+		err = s.shortLinkRepo.Create(ctx, link)
+		if err == nil {
+			return link, nil
+		}
+		if !errors.Is(err, repository.ErrShortCodeInUse) {
+			return nil, fmt.Errorf("failed to create short link: %w", err)
+		}
+	}
+
+	return nil, errors.New("failed to generate a unique short code")
+}
+
+func (s *shortLinkService) ListShortLinks(ctx context.Context, req *models.ListShortLinksRequest) (*models.ListShortLinksResponse, error) {
+	links, total, err := s.shortLinkRepo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list short links: %w", err)
+	}
+
+	if links == nil {
+		links = []models.ShortLink{}
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	return &models.ListShortLinksResponse{
+		Links:      links,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *shortLinkService) RevokeShortLink(ctx context.Context, adminID, linkID primitive.ObjectID) (*models.ShortLink, error) {
+	return s.shortLinkRepo.Revoke(ctx, linkID, adminID)
+}
+
+// ResolveShortLink counts a click and returns the link to redirect to. Links that existed but were
+// revoked or have expired return "short link is no longer available", so they can be told apart
+// from codes that were mistyped.
+func (s *shortLinkService) ResolveShortLink(ctx context.Context, code string) (*models.ShortLink, error) {
+	code = strings.ToLower(code)
+
+	link, err := s.shortLinkRepo.RecordClick(ctx, code)
+	if err == nil {
+		return link, nil
+	}
+	if !errors.Is(err, repository.ErrShortLinkNotFound) {
+		return nil, fmt.Errorf("failed to resolve short link: %w", err)
+	}
+
+	if _, getErr := s.shortLinkRepo.GetByCode(ctx, code); getErr == nil {
+		return nil, models.NewError(http.StatusGone, "short link is no longer available")
+	}
+	return nil, err
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SigningKeyService interface {
+	LoadKeys(ctx context.Context) error
+	RotateKeys(ctx context.Context) (*models.KeyRotationResult, error)
+	StartAutoRefresh(ctx context.Context, interval time.Duration)
+	GetJWKS() (*models.JWKSet, error)
+}
+
+type signingKeyService struct {
+	signingKeyRepo repository.SigningKeyRepository
+	jwtManager     *utils.JWTManager
+	encryptionKey  string
+	verifyGrace    time.Duration
+}
+
+func NewSigningKeyService(signingKeyRepo repository.SigningKeyRepository, jwtManager *utils.JWTManager, config models.JWTConfig) SigningKeyService {
+	// A rotated-out key must outlive every token it signed, including ones signed by
+	// instances that haven't reloaded keys yet
+	verifyGrace := config.AccessTokenDuration
+	if config.RefreshTokenDuration > verifyGrace {
+		verifyGrace = config.RefreshTokenDuration
+	}
+	if config.RememberMeDuration > verifyGrace {
+		verifyGrace = config.RememberMeDuration
+	}
+	verifyGrace += config.KeyRefreshInterval
+
+	return &signingKeyService{
+		signingKeyRepo: signingKeyRepo,
+		jwtManager:     jwtManager,
+		encryptionKey:  utils.DeriveEncryptionKey(config.SecretKey, "jwt-signing-keys"),
+		verifyGrace:    verifyGrace,
+	}
+}
+
+// LoadKeys installs the trusted keys into the JWT manager, creating the first signing key
+// if none exists yet
+func (s *signingKeyService) LoadKeys(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.GetTrusted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	if !hasCurrentKey(keys) {
+		if _, err := s.createKey(ctx); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to create initial signing key: %w", err)
+		}
+		// Reload whether we created it or another instance beat us to it
+		if keys, err = s.signingKeyRepo.GetTrusted(ctx); err != nil {
+			return fmt.Errorf("failed to load signing keys: %w", err)
+		}
+	}
+
+	var current *utils.SigningKey
+	verify := make(map[string]*ecdsa.PublicKey, len(keys))
+	for _, record := range keys {
+		key, err := s.decryptKey(&record)
+		if err != nil {
+			return err
+		}
+		verify[key.KID] = &key.PrivateKey.PublicKey
+		if record.Status == models.SigningKeyCurrent {
+			current = key
+		}
+	}
+
+	if current == nil {
+		return errors.New("no current signing key")
+	}
+
+	s.jwtManager.SetKeys(current, verify)
+	return nil
+}
+
+// RotateKeys makes a new key current, keeps the outgoing one trusted until its tokens have
+// expired, and retires keys whose verification window has passed
+func (s *signingKeyService) RotateKeys(ctx context.Context) (*models.KeyRotationResult, error) {
+	now := time.Now()
+	verifyUntil := now.Add(s.verifyGrace)
+
+	previous, err := s.signingKeyRepo.DemoteCurrent(ctx, now, verifyUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to demote current signing key: %w", err)
+	}
+
+	current, err := s.createKey(ctx)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("another key rotation is in progress")
+		}
+		return nil, fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	retired, err := s.signingKeyRepo.RetireExpired(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire expired signing keys: %w", err)
+	}
+
+	if err := s.LoadKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &models.KeyRotationResult{
+		CurrentKID:  current.KID,
+		RetiredKIDs: retired,
+	}
+	if previous != nil {
+		result.PreviousKID = previous.KID
+		result.VerifyUntil = verifyUntil
+	}
+	return result, nil
+}
+
+// StartAutoRefresh reloads keys periodically so every instance picks up a rotation
+func (s *signingKeyService) StartAutoRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.LoadKeys(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to refresh JWT signing keys", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *signingKeyService) GetJWKS() (*models.JWKSet, error) {
+	return s.jwtManager.JWKS()
+}
+
+func (s *signingKeyService) createKey(ctx context.Context) (*models.JWTSigningKey, error) {
+	key, err := utils.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := utils.MarshalSigningKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+
+	nonce, ciphertext, err := utils.EncryptAESGCM(s.encryptionKey, der, []byte(key.KID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+
+	record := &models.JWTSigningKey{
+		KID:          key.KID,
+		Algorithm:    utils.SigningKeyAlgorithm,
+		EncryptedKey: ciphertext,
+		Nonce:        nonce,
+	}
+	if err := s.signingKeyRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *signingKeyService) decryptKey(record *models.JWTSigningKey) (*utils.SigningKey, error) {
+	der, err := utils.DecryptAESGCM(s.encryptionKey, record.Nonce, record.EncryptedKey, []byte(record.KID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt signing key %s (was the JWT secret changed?): %w", record.KID, err)
+	}
+	return utils.ParseSigningKey(record.KID, der)
+}
+
+func hasCurrentKey(keys []models.JWTSigningKey) bool {
+	for _, key := range keys {
+		if key.Status == models.SigningKeyCurrent {
+			return true
+		}
+	}
+	return false
+}
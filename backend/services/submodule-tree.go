@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MoveSubModule nests a submodule under another submodule of the same module, or with a nil parent
+// directly in the module, taking the submodules under it along
+func (s *moduleService) MoveSubModule(ctx context.Context, moduleID, subModuleID primitive.ObjectID, req *models.MoveSubModuleRequest, userID primitive.ObjectID) (*models.SubModule, error) {
+	module, err := s.moduleRepo.GetModuleByID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	existing := *module
+	existing.SubModules = append([]models.SubModule{}, module.SubModules...)
+
+	subModule := findSubModule(module, subModuleID)
+	if subModule == nil {
+		return nil, ErrSubModuleNotFound
+	}
+
+	now := time.Now()
+	subModule.ParentID = req.ParentID
+	subModule.Order = req.Order
+	if subModule.Order == 0 {
+		subModule.Order = len(siblingSubModules(module, req.ParentID, subModuleID)) + 1
+	}
+	subModule.UpdatedAt = now
+	subModule.UpdatedBy = userID
+	if err := checkSubModuleTree(module); err != nil {
+		return nil, err
+	}
+	// Reading a submodule takes reading its new parent, which must not in turn require it
+	if err := s.checkPrerequisites(ctx, module); err != nil {
+		return nil, err
+	}
+
+	module.UpdatedBy = userID
+	if err := s.saveRevisedModule(ctx, &existing, module, userID, 0); err != nil {
+		return nil, err
+	}
+	module.ArrangeSubModules()
+	module.EstimateReadingTime()
+	return findSubModule(module, subModuleID), nil
+}
+
+// checkSubModuleTree checks that every submodule's parent is another submodule of the module, and that
+// nesting stays within MaxSubModuleDepth without any submodule ending up inside itself
+func checkSubModuleTree(module *models.Module) error {
+	parents := make(map[primitive.ObjectID]*primitive.ObjectID, len(module.SubModules))
+	for _, sub := range module.SubModules {
+		parents[sub.ID] = sub.ParentID
+	}
+	for _, sub := range module.SubModules {
+		if sub.ParentID == nil {
+			continue
+		}
+		if _, ok := parents[*sub.ParentID]; !ok {
+			return models.BadRequestError("parent submodule not found in module")
+		}
+
+		depth := 1
+		seen := map[primitive.ObjectID]bool{sub.ID: true}
+		for parent := sub.ParentID; parent != nil; parent = parents[*parent] {
+			if seen[*parent] {
+				return models.BadRequestError("a submodule cannot be nested inside itself")
+			}
+			seen[*parent] = true
+			depth++
+		}
+		if depth > models.MaxSubModuleDepth {
+			return models.BadRequestError(fmt.Sprintf("submodules can be nested at most %d levels deep", models.MaxSubModuleDepth))
+		}
+	}
+	return nil
+}
+
+// siblingSubModules lists the submodules directly under parent, or directly in the module for a nil
+// parent, other than the one excluded
+func siblingSubModules(module *models.Module, parent *primitive.ObjectID, exclude primitive.ObjectID) []primitive.ObjectID {
+	var siblings []primitive.ObjectID
+	for _, sub := range module.SubModules {
+		if sub.ID != exclude && sameParent(sub.ParentID, parent) {
+			siblings = append(siblings, sub.ID)
+		}
+	}
+	return siblings
+}
+
+func sameParent(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func findSubModule(module *models.Module, id primitive.ObjectID) *models.SubModule {
+	for i := range module.SubModules {
+		if module.SubModules[i].ID == id {
+			return &module.SubModules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TeamService interface {
+	CreateTeam(ctx context.Context, userID primitive.ObjectID, email string, req *models.CreateTeamRequest) (*models.Team, error)
+	JoinTeam(ctx context.Context, userID primitive.ObjectID, email string, req *models.JoinTeamRequest) (*models.Team, error)
+	GetUserTeams(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error)
+	GetTeam(ctx context.Context, userID, teamID primitive.ObjectID) (*models.Team, error)
+	LeaveTeam(ctx context.Context, userID, teamID primitive.ObjectID) error
+	RemoveMember(ctx context.Context, captainID, teamID, memberID primitive.ObjectID) (*models.Team, error)
+	StartTeamQuiz(ctx context.Context, userID, teamID primitive.ObjectID, req *models.StartTeamQuizRequest) (*models.StartQuizResponse, error)
+	GetLeaderboard(ctx context.Context, req *models.TeamLeaderboardRequest) (*models.TeamLeaderboardResponse, error)
+}
+
+type teamService struct {
+	teamRepo           repository.TeamRepository
+	quizSessionRepo    repository.QuizSessionRepository
+	quizSessionService QuizSessionService
+	cache              *utils.Cache
+}
+
+func NewTeamService(
+	teamRepo repository.TeamRepository,
+	quizSessionRepo repository.QuizSessionRepository,
+	quizSessionService QuizSessionService,
+	cache *utils.Cache,
+) TeamService {
+	return &teamService{
+		teamRepo:           teamRepo,
+		quizSessionRepo:    quizSessionRepo,
+		quizSessionService: quizSessionService,
+		cache:              cache,
+	}
+}
+
+// CreateTeam creates a team with the caller as its captain
+func (s *teamService) CreateTeam(ctx context.Context, userID primitive.ObjectID, email string, req *models.CreateTeamRequest) (*models.Team, error) {
+	team := &models.Team{
+		Name: strings.TrimSpace(req.Name),
+		Members: []models.TeamMemberInfo{{
+			UserID:   userID,
+			Email:    email,
+			Role:     models.TeamCaptain,
+			JoinedAt: time.Now(),
+		}},
+	}
+
+	for attempt := 0; attempt < joinCodeAttempts; attempt++ {
+		code, err := utils.GenerateJoinCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate join code: %w", err)
+		}
+		team.JoinCode = code
+
+		err = s.teamRepo.Create(ctx, team)
+		if err == nil {
+			return team, nil
+		}
+		if !errors.Is(err, repository.ErrJoinCodeInUse) {
+			return nil, fmt.Errorf("failed to create team: %w", err)
+		}
+	}
+
+	return nil, errors.New("failed to generate a unique join code")
+}
+
+func (s *teamService) JoinTeam(ctx context.Context, userID primitive.ObjectID, email string, req *models.JoinTeamRequest) (*models.Team, error) {
+	team, err := s.teamRepo.GetByJoinCode(ctx, strings.ToLower(strings.TrimSpace(req.JoinCode)))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.teamRepo.AddMember(ctx, team.ID, models.TeamMemberInfo{
+		UserID:   userID,
+		Email:    email,
+		Role:     models.TeamMember,
+		JoinedAt: time.Now(),
+	})
+}
+
+func (s *teamService) GetUserTeams(ctx context.Context, userID primitive.ObjectID) ([]models.Team, error) {
+	teams, err := s.teamRepo.GetByMember(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	if teams == nil {
+		teams = []models.Team{}
+	}
+	return teams, nil
+}
+
+// GetTeam returns a team the caller belongs to; other teams are reported as not found
+func (s *teamService) GetTeam(ctx context.Context, userID, teamID primitive.ObjectID) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.Member(userID) == nil {
+		return nil, models.NotFoundError("team not found")
+	}
+	return team, nil
+}
+
+// LeaveTeam removes the caller from a team. A captain who leaves hands over to the longest-standing
+// member, and the last member to leave deletes the team.
+func (s *teamService) LeaveTeam(ctx context.Context, userID, teamID primitive.ObjectID) error {
+	team, err := s.GetTeam(ctx, userID, teamID)
+	if err != nil {
+		return err
+	}
+	wasCaptain := team.Member(userID).Role == models.TeamCaptain
+
+	team, err = s.teamRepo.RemoveMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(team.Members) == 0 {
+		if err := s.teamRepo.Delete(ctx, teamID); err != nil {
+			return fmt.Errorf("failed to delete empty team: %w", err)
+		}
+		return nil
+	}
+
+	if wasCaptain {
+		successor := team.Members[0]
+		for _, member := range team.Members[1:] {
+			if member.JoinedAt.Before(successor.JoinedAt) {
+				successor = member
+			}
+		}
+		if _, err := s.teamRepo.SetCaptain(ctx, teamID, successor.UserID); err != nil {
+			return fmt.Errorf("failed to hand over captaincy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveMember lets the captain remove someone else from the team
+func (s *teamService) RemoveMember(ctx context.Context, captainID, teamID, memberID primitive.ObjectID) (*models.Team, error) {
+	team, err := s.GetTeam(ctx, captainID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.Member(captainID).Role != models.TeamCaptain {
+		return nil, models.ForbiddenError("only the team captain can remove members")
+	}
+	if memberID == captainID {
+		return nil, models.BadRequestError("captains leave the team instead of removing themselves")
+	}
+
+	return s.teamRepo.RemoveMember(ctx, teamID, memberID)
+}
+
+// StartTeamQuiz returns the team's running shared session, or starts one. Members who start at the
+// same moment race to claim the team's session slot; the losers get the winner's session.
+func (s *teamService) StartTeamQuiz(ctx context.Context, userID, teamID primitive.ObjectID, req *models.StartTeamQuizRequest) (*models.StartQuizResponse, error) {
+	team, err := s.GetTeam(ctx, userID, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if response, err := s.resumeTeamSession(ctx, userID, team); response != nil || err != nil {
+		return response, err
+	}
+
+	sessionID := primitive.NewObjectID()
+	claimed, err := s.teamRepo.ClaimActiveSession(ctx, team.ID, team.ActiveSessionID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim team session: %w", err)
+	}
+	if !claimed {
+		// Another member got there first
+		team, err = s.teamRepo.GetByID(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		if response, err := s.resumeTeamSession(ctx, userID, team); response != nil || err != nil {
+			return response, err
+		}
+		return nil, models.ConflictError("another team quiz is starting, try again")
+	}
+
+	return s.quizSessionService.StartTeamQuiz(ctx, sessionID, userID, team, req)
+}
+
+// resumeTeamSession returns the team's shared session if it is still running, or nil if a new one
+// can be started. Sessions that ran out of time are submitted on the way.
+func (s *teamService) resumeTeamSession(ctx context.Context, userID primitive.ObjectID, team *models.Team) (*models.StartQuizResponse, error) {
+	if team.ActiveSessionID == nil {
+		return nil, nil
+	}
+
+	session, err := s.quizSessionRepo.GetSessionByID(ctx, *team.ActiveSessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			// The claim was made but the session never got created
+			return nil, nil
+		}
+		return nil, err
+	}
+	if session.Status != models.QuizInProgress {
+		return nil, nil
+	}
+
+	current, err := s.quizSessionService.GetSession(ctx, session.SessionToken)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsExpired {
+		return nil, nil
+	}
+
+	if !current.Session.IsTakenBy(userID) {
+		return nil, models.ConflictError("your team started this quiz before you joined; wait for it to finish")
+	}
+
+	return &models.StartQuizResponse{
+		Session:     current.Session,
+		Message:     "Joined your team's quiz session",
+		ResumeToken: current.Session.SessionToken,
+	}, nil
+}
+
+func (s *teamService) GetLeaderboard(ctx context.Context, req *models.TeamLeaderboardRequest) (*models.TeamLeaderboardResponse, error) {
+	var from, to *time.Time
+	if req.DateFrom != "" {
+		parsed, err := time.Parse("2006-01-02", req.DateFrom)
+		if err != nil {
+			return nil, models.BadRequestError("invalid date_from, expected YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+	if req.DateTo != "" {
+		parsed, err := time.Parse("2006-01-02", req.DateTo)
+		if err != nil {
+			return nil, models.BadRequestError("invalid date_to, expected YYYY-MM-DD")
+		}
+		// Include the whole of the last day
+		parsed = parsed.AddDate(0, 0, 1)
+		to = &parsed
+	}
+
+	cacheKey := fmt.Sprintf("%s:%s:%d", req.DateFrom, req.DateTo, req.Limit)
+	var cached models.TeamLeaderboardResponse
+	if s.cache.Get(ctx, utils.CacheLeaderboards, cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	entries, err := s.teamRepo.GetLeaderboard(ctx, from, to, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team leaderboard: %w", err)
+	}
+	if entries == nil {
+		entries = []models.TeamLeaderboardEntry{}
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	response := &models.TeamLeaderboardResponse{Entries: entries}
+	s.cache.Set(ctx, utils.CacheLeaderboards, cacheKey, response)
+	return response, nil
+}
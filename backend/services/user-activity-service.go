@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"backend/models"
 	"backend/repository"
@@ -18,6 +20,7 @@ type UserActivityService interface {
 
 	// User Statistics
 	GetUserStats(ctx context.Context, userID primitive.ObjectID) (*models.UserStats, error)
+	RefreshUserStats(ctx context.Context) (int64, error)
 
 	// Achievements
 	GetUserAchievements(ctx context.Context, userID primitive.ObjectID) ([]models.Achievement, error)
@@ -25,11 +28,13 @@ type UserActivityService interface {
 
 type userActivityService struct {
 	userActivityRepo repository.UserActivityRepository
+	examRepo         repository.ExamRepository
 }
 
-func NewUserActivityService(userActivityRepo repository.UserActivityRepository) UserActivityService {
+func NewUserActivityService(userActivityRepo repository.UserActivityRepository, examRepo repository.ExamRepository) UserActivityService {
 	return &userActivityService{
 		userActivityRepo: userActivityRepo,
+		examRepo:         examRepo,
 	}
 }
 
@@ -75,14 +80,14 @@ func (s *userActivityService) CreateQuizResult(ctx context.Context, userID primi
 	// Update user statistics
 	if err := s.userActivityRepo.UpdateUserStats(ctx, userID, createdResult); err != nil {
 		// Log error but don't fail the request
-		fmt.Printf("Failed to update user stats: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to update user stats", "error", err)
 	}
 
 	// Check and create achievements
 	newAchievements, err := s.userActivityRepo.CheckAndCreateAchievements(ctx, userID, createdResult)
 	if err != nil {
 		// Log error but don't fail the request
-		fmt.Printf("Failed to check achievements: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to check achievements", "error", err)
 		newAchievements = []models.Achievement{}
 	}
 
@@ -90,12 +95,33 @@ func (s *userActivityService) CreateQuizResult(ctx context.Context, userID primi
 }
 
 func (s *userActivityService) GetUserResults(ctx context.Context, userID primitive.ObjectID, filter models.QuizResultsFilter) (*models.UserResultsResponse, error) {
+	after, err := parseCursor(filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get quiz results
-	results, totalCount, err := s.userActivityRepo.GetUserQuizResults(ctx, userID, filter)
+	results, totalCount, err := s.userActivityRepo.GetUserQuizResults(ctx, userID, filter, after)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user quiz results: %w", err)
 	}
 
+	var more bool
+	if after != nil {
+		results, more = cursorPage(results, filter.Limit)
+	} else {
+		more = filter.Limit > 0 && int64(max(filter.Page, 1)*filter.Limit) < totalCount
+	}
+	var nextCursor string
+	if more && len(results) > 0 {
+		last := results[len(results)-1]
+		nextCursor = models.NewPageCursor(last.CompletedAt, last.ID).String()
+	}
+
+	if !filter.IncludeWithheld {
+		newResultReleaseChecker(s.examRepo).apply(ctx, results)
+	}
+
 	// Get user statistics
 	stats, err := s.userActivityRepo.GetUserStats(ctx, userID)
 	if err != nil {
@@ -113,6 +139,7 @@ func (s *userActivityService) GetUserResults(ctx context.Context, userID primiti
 		Stats:        *stats,
 		Achievements: achievements,
 		TotalCount:   totalCount,
+		NextCursor:   nextCursor,
 	}, nil
 }
 
@@ -121,6 +148,9 @@ func (s *userActivityService) GetQuizResultByID(ctx context.Context, id primitiv
 	if err != nil {
 		return nil, fmt.Errorf("failed to get quiz result: %w", err)
 	}
+	if withheld, releaseAt := newResultReleaseChecker(s.examRepo).withheld(ctx, result.ExamSittingID); withheld {
+		result.Withhold(releaseAt)
+	}
 	return result, nil
 }
 
@@ -132,6 +162,16 @@ func (s *userActivityService) GetUserStats(ctx context.Context, userID primitive
 	return stats, nil
 }
 
+// RefreshUserStats recomputes the streaks and weekly progress that go stale while students don't take
+// quizzes, returning how many users' stats changed
+func (s *userActivityService) RefreshUserStats(ctx context.Context) (int64, error) {
+	changed, err := s.userActivityRepo.RefreshTimeBasedStats(ctx, time.Now())
+	if err != nil {
+		return changed, fmt.Errorf("failed to refresh user stats: %w", err)
+	}
+	return changed, nil
+}
+
 func (s *userActivityService) GetUserAchievements(ctx context.Context, userID primitive.ObjectID) ([]models.Achievement, error) {
 	achievements, err := s.userActivityRepo.GetUserAchievements(ctx, userID)
 	if err != nil {
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"backend/models"
+	"backend/repository"
+	"backend/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxImportRows             = 1000
+	generatedPasswordLength   = 12
+	minImportedPasswordLength = 8
+)
+
+// importColumnAliases maps accepted header names to import fields
+var importColumnAliases = map[string]string{
+	"name":      "full_name",
+	"full_name": "full_name",
+	"fullname":  "full_name",
+	"nama":      "full_name",
+	"email":     "email",
+	"e-mail":    "email",
+	"nim":       "nim",
+	"faculty":   "faculty",
+	"fakultas":  "faculty",
+	"major":     "major",
+	"jurusan":   "major",
+	"prodi":     "major",
+	"password":  "password",
+}
+
+var requiredImportColumns = []string{"full_name", "email", "nim"}
+
+type UserImportService interface {
+	ImportMahasiswa(ctx context.Context, rows [][]string, req *models.ImportUsersRequest, loginURL string) (*models.ImportUsersResponse, error)
+}
+
+type userImportService struct {
+	userRepo     repository.UserRepository
+	emailService *utils.EmailService
+	cache        *utils.Cache
+}
+
+func NewUserImportService(userRepo repository.UserRepository, emailService *utils.EmailService, cache *utils.Cache) UserImportService {
+	return &userImportService{
+		userRepo:     userRepo,
+		emailService: emailService,
+		cache:        cache,
+	}
+}
+
+// importRow holds the parsed values of one spreadsheet row
+type importRow struct {
+	number   int
+	fullName string
+	email    string
+	nim      string
+	faculty  string
+	major    string
+	password string
+}
+
+func (s *userImportService) ImportMahasiswa(ctx context.Context, rows [][]string, req *models.ImportUsersRequest, loginURL string) (*models.ImportUsersResponse, error) {
+	if len(rows) < 2 {
+		return nil, models.BadRequestError("import file has no data rows")
+	}
+
+	columns, err := mapImportColumns(rows[0], importColumnAliases, requiredImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	dataRows := rows[1:]
+	if len(dataRows) > maxImportRows {
+		return nil, models.BadRequestError(fmt.Sprintf("import file has too many rows, maximum is %d", maxImportRows))
+	}
+
+	response := &models.ImportUsersResponse{
+		Results: make([]models.ImportRowResult, 0, len(dataRows)),
+	}
+
+	// Track values seen earlier in the file to catch duplicates within the upload
+	seenEmails := make(map[string]int)
+	seenNIMs := make(map[string]int)
+
+	for i, values := range dataRows {
+		row := s.parseRow(i+2, values, columns)
+		if row.fullName == "" && row.email == "" && row.nim == "" {
+			continue // Skip blank lines
+		}
+		response.TotalRows++
+
+		result := models.ImportRowResult{
+			Row:      row.number,
+			FullName: row.fullName,
+			Email:    row.email,
+			NIM:      row.nim,
+		}
+
+		if validationErrors := s.validateRow(row, req); len(validationErrors) > 0 {
+			result.Status = models.ImportRowInvalid
+			result.Errors = validationErrors
+			response.Invalid++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		if duplicateErrors := s.findDuplicates(ctx, row, seenEmails, seenNIMs); len(duplicateErrors) > 0 {
+			result.Status = models.ImportRowDuplicate
+			result.Errors = duplicateErrors
+			response.Duplicates++
+			response.Results = append(response.Results, result)
+			continue
+		}
+		seenEmails[strings.ToLower(row.email)] = row.number
+		seenNIMs[row.nim] = row.number
+
+		generatedPassword := ""
+		if row.password == "" {
+			generatedPassword, err = utils.GenerateTemporaryPassword(generatedPasswordLength)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate password: %w", err)
+			}
+			row.password = generatedPassword
+		}
+
+		mahasiswa, err := s.createMahasiswa(ctx, row)
+		if err != nil {
+			result.Status = models.ImportRowFailed
+			result.Errors = []string{err.Error()}
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		result.Status = models.ImportRowCreated
+		result.UserID = mahasiswa.ID.Hex()
+		response.Created++
+
+		if req.SendInvitations {
+			if err := s.emailService.SendInvitationEmail(row.email, row.fullName, row.password, loginURL); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to send invitation: %v", err))
+			} else {
+				result.InvitationSent = true
+				response.InvitationsSent++
+			}
+		}
+
+		// The admin has to hand out generated passwords unless the invitation delivered them
+		if !result.InvitationSent {
+			result.GeneratedPassword = generatedPassword
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	if response.Created > 0 {
+		s.cache.Invalidate(ctx, utils.CacheUserStats)
+	}
+
+	if response.TotalRows == 0 {
+		return nil, models.BadRequestError("import file has no data rows")
+	}
+
+	return response, nil
+}
+
+// Private helper methods
+
+// mapImportColumns resolves header cells to field positions through the aliases and checks the
+// required columns are present
+func mapImportColumns(header []string, aliases map[string]string, required []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, cell := range header {
+		key := strings.ToLower(strings.TrimSpace(cell))
+		key = strings.ReplaceAll(key, " ", "_")
+		if field, ok := aliases[key]; ok {
+			if _, exists := columns[field]; !exists {
+				columns[field] = i
+			}
+		}
+	}
+
+	var missing []string
+	for _, field := range required {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, models.BadRequestError(fmt.Sprintf("missing required columns: %s", strings.Join(missing, ", ")))
+	}
+
+	return columns, nil
+}
+
+func (s *userImportService) parseRow(number int, values []string, columns map[string]int) importRow {
+	get := func(field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(values) {
+			return ""
+		}
+		return strings.TrimSpace(values[index])
+	}
+
+	return importRow{
+		number:   number,
+		fullName: get("full_name"),
+		email:    get("email"),
+		nim:      get("nim"),
+		faculty:  get("faculty"),
+		major:    get("major"),
+		password: get("password"),
+	}
+}
+
+func (s *userImportService) validateRow(row importRow, req *models.ImportUsersRequest) []string {
+	var validationErrors []string
+
+	if row.fullName == "" {
+		validationErrors = append(validationErrors, "name is required")
+	}
+
+	if row.email == "" {
+		validationErrors = append(validationErrors, "email is required")
+	} else if addr, err := mail.ParseAddress(row.email); err != nil || addr.Address != row.email {
+		validationErrors = append(validationErrors, "email is not a valid address")
+	}
+
+	if row.nim == "" {
+		validationErrors = append(validationErrors, "NIM is required")
+	} else if !isAlphanumeric(row.nim) {
+		validationErrors = append(validationErrors, "NIM must contain only letters and digits")
+	}
+
+	if row.password == "" {
+		if !req.GeneratePasswords {
+			validationErrors = append(validationErrors, "password is required when generate_passwords is disabled")
+		}
+	} else if len(row.password) < minImportedPasswordLength {
+		validationErrors = append(validationErrors, fmt.Sprintf("password must be at least %d characters", minImportedPasswordLength))
+	}
+
+	return validationErrors
+}
+
+// findDuplicates checks the row against earlier rows and all three user collections
+func (s *userImportService) findDuplicates(ctx context.Context, row importRow, seenEmails, seenNIMs map[string]int) []string {
+	var duplicateErrors []string
+
+	if previous, ok := seenEmails[strings.ToLower(row.email)]; ok {
+		duplicateErrors = append(duplicateErrors, fmt.Sprintf("email already used in row %d", previous))
+	} else if s.emailExists(ctx, row.email) {
+		duplicateErrors = append(duplicateErrors, "user with this email already exists")
+	}
+
+	if previous, ok := seenNIMs[row.nim]; ok {
+		duplicateErrors = append(duplicateErrors, fmt.Sprintf("NIM already used in row %d", previous))
+	} else if existing, _ := s.userRepo.GetMahasiswaByNIM(ctx, row.nim); existing != nil {
+		duplicateErrors = append(duplicateErrors, "user with this NIM already exists")
+	}
+
+	return duplicateErrors
+}
+
+func (s *userImportService) emailExists(ctx context.Context, email string) bool {
+	if existing, _ := s.userRepo.GetByEmail(ctx, email); existing != nil {
+		return true
+	}
+	if existing, _ := s.userRepo.GetMahasiswaByEmail(ctx, email); existing != nil {
+		return true
+	}
+	if existing, _ := s.userRepo.GetAdminByEmail(ctx, email); existing != nil {
+		return true
+	}
+	return false
+}
+
+func (s *userImportService) createMahasiswa(ctx context.Context, row importRow) (*models.UserMahasiswa, error) {
+	hashedPassword, err := utils.HashPassword(row.password, utils.DefaultPasswordConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	mahasiswa := &models.UserMahasiswa{
+		User: models.User{
+			FullName:      row.fullName,
+			Email:         row.email,
+			PasswordHash:  hashedPassword,
+			EmailVerified: true, // Imported by an admin from an institutional list
+			RecoveryCodes: recoveryCodes,
+			UserType:      models.UserTypeMahasiswa,
+			Status:        models.UserStatusActive,
+		},
+		NIM:     row.nim,
+		Faculty: row.faculty,
+		Major:   row.major,
+	}
+
+	if err := s.userRepo.CreateMahasiswa(ctx, mahasiswa); err != nil {
+		if errors.Is(err, repository.ErrNIMTaken) {
+			return nil, err
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, fmt.Errorf("failed to create mahasiswa: %w", err)
+	}
+
+	return mahasiswa, nil
+}
+
+func isAlphanumeric(value string) bool {
+	for _, ch := range value {
+		if !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
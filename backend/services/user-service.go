@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
@@ -21,6 +23,9 @@ import (
 	"golang.org/x/oauth2/google"
 )
 
+// ErrEmailTaken is returned when another user already has the email
+var ErrEmailTaken = models.NewCodedError(http.StatusConflict, models.ErrorCodeEmailTaken, "user with this email already exists")
+
 type UserService interface {
 	Register(ctx context.Context, req *models.RegisterRequest) (*models.AuthResponse, error)
 	Login(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error)
@@ -39,25 +44,45 @@ type UserService interface {
 	VerifyEmail(ctx context.Context, token string) error
 	ResendVerification(ctx context.Context, email string) error
 	UpdateLastLogout(userID string) error
+	ImpersonateUser(ctx context.Context, adminID, targetID primitive.ObjectID) (*models.ImpersonationResponse, error)
+	GetPasswordPolicy() *models.PasswordPolicyResponse
+	GetUserStats(ctx context.Context) (*models.UserStatsResponse, error)
+	ListUsers(ctx context.Context, req *models.ListUsersRequest) (*models.ListUsersResponse, error)
+	UpdateUserStatus(ctx context.Context, userID primitive.ObjectID, status models.UserStatus) error
 }
 
+// impersonationTokenDuration keeps support sessions short so they cannot be used as a standing credential
+const impersonationTokenDuration = 15 * time.Minute
+
 type userService struct {
-	userRepo     repository.UserRepository
-	jwtManager   *utils.JWTManager
-	config       models.Config
-	oauthConfigs map[string]*oauth2.Config
+	userRepo          repository.UserRepository
+	transactor        repository.Transactor
+	jwtManager        *utils.JWTManager
+	config            models.Config
+	oauthConfigs      map[string]*oauth2.Config
+	passwordPolicy    PasswordPolicyService
+	invitationService InvitationService
+	cache             *utils.Cache
 }
 
 func NewUserService(
 	userRepo repository.UserRepository,
+	transactor repository.Transactor,
 	jwtManager *utils.JWTManager,
 	config models.Config,
+	passwordPolicy PasswordPolicyService,
+	invitationService InvitationService,
+	cache *utils.Cache,
 ) UserService {
 	service := &userService{
-		userRepo:     userRepo,
-		jwtManager:   jwtManager,
-		config:       config,
-		oauthConfigs: make(map[string]*oauth2.Config),
+		userRepo:          userRepo,
+		transactor:        transactor,
+		jwtManager:        jwtManager,
+		config:            config,
+		oauthConfigs:      make(map[string]*oauth2.Config),
+		passwordPolicy:    passwordPolicy,
+		invitationService: invitationService,
+		cache:             cache,
 	}
 
 	// Initialize OAuth configs
@@ -120,19 +145,33 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 	// Check if user already exists in any collection
 	existing, _ := s.userRepo.GetByEmail(ctx, req.Email)
 	if existing != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrEmailTaken
 	}
 
 	// Check mahasiswa collection
 	existingMahasiswa, _ := s.userRepo.GetMahasiswaByEmail(ctx, req.Email)
 	if existingMahasiswa != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrEmailTaken
 	}
 
 	// Check admin collection
 	existingAdmin, _ := s.userRepo.GetAdminByEmail(ctx, req.Email)
 	if existingAdmin != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrEmailTaken
+	}
+
+	if err := s.passwordPolicy.Validate(req.Password); err != nil {
+		return nil, err
+	}
+
+	// Invited registrations must match the invitation's email and user type
+	var invitation *models.Invitation
+	if req.InviteToken != "" {
+		validated, err := s.invitationService.ValidateInvitation(ctx, req.InviteToken, req.Email, req.UserType)
+		if err != nil {
+			return nil, err
+		}
+		invitation = validated
 	}
 
 	// Hash password
@@ -150,11 +189,12 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 
 	// Create user based on type
 	if req.UserType == "mahasiswa" {
-		// Check if NIM already exists
+		// Check if NIM already exists; the unique index catches registrations racing this check
+		req.NIM = strings.TrimSpace(req.NIM)
 		if req.NIM != "" {
 			existingNIM, _ := s.userRepo.GetMahasiswaByNIM(ctx, req.NIM)
 			if existingNIM != nil {
-				return nil, errors.New("user with this NIM already exists")
+				return nil, repository.ErrNIMTaken
 			}
 		}
 
@@ -173,30 +213,22 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			Major:   req.Major,
 		}
 
-		if err := s.userRepo.CreateMahasiswa(ctx, mahasiswa); err != nil {
-			// Check if it's a duplicate key error
-			if mongo.IsDuplicateKeyError(err) {
-				return nil, fmt.Errorf("user with this email or OAuth account already exists")
-			}
-			return nil, fmt.Errorf("failed to create mahasiswa: %w", err)
-		}
-
 		// Note: Recovery codes will be displayed to user after registration
-
-		// Generate tokens
-		accessToken, err := s.jwtManager.GenerateAccessToken(mahasiswa.ID, mahasiswa.Email, "mahasiswa", false)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate access token: %w", err)
-		}
-
-		refreshToken, err := s.jwtManager.GenerateRefreshToken(mahasiswa.ID, mahasiswa.Email, false)
+		accessToken, refreshToken, err := s.createAccount(ctx, invitation, "mahasiswa", false, func(ctx context.Context) (primitive.ObjectID, string, error) {
+			if err := s.userRepo.CreateMahasiswa(ctx, mahasiswa); err != nil {
+				if errors.Is(err, repository.ErrNIMTaken) {
+					return primitive.NilObjectID, "", err
+				}
+				// Check if it's a duplicate key error
+				if mongo.IsDuplicateKeyError(err) {
+					return primitive.NilObjectID, "", fmt.Errorf("user with this email or OAuth account already exists")
+				}
+				return primitive.NilObjectID, "", fmt.Errorf("failed to create mahasiswa: %w", err)
+			}
+			return mahasiswa.ID, mahasiswa.Email, nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-		}
-
-		// Store refresh token
-		if err := s.userRepo.SetRefreshToken(ctx, mahasiswa.ID, refreshToken); err != nil {
-			return nil, fmt.Errorf("failed to store refresh token: %w", err)
+			return nil, err
 		}
 
 		return &models.AuthResponse{
@@ -204,6 +236,7 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
 			ExpiresIn:    int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
+			Invitation:   invitation,
 		}, nil
 
 	} else if req.UserType == "admin" {
@@ -221,26 +254,15 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			Permissions: []string{"read", "write", "delete"},
 		}
 
-		if err := s.userRepo.CreateAdmin(ctx, admin); err != nil {
-			return nil, fmt.Errorf("failed to create admin: %w", err)
-		}
-
 		// Note: Recovery codes will be displayed to admin after registration
-
-		// Generate tokens
-		accessToken, err := s.jwtManager.GenerateAccessToken(admin.ID, admin.Email, "admin", true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate access token: %w", err)
-		}
-
-		refreshToken, err := s.jwtManager.GenerateRefreshToken(admin.ID, admin.Email, false)
+		accessToken, refreshToken, err := s.createAccount(ctx, invitation, "admin", true, func(ctx context.Context) (primitive.ObjectID, string, error) {
+			if err := s.userRepo.CreateAdmin(ctx, admin); err != nil {
+				return primitive.NilObjectID, "", fmt.Errorf("failed to create admin: %w", err)
+			}
+			return admin.ID, admin.Email, nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-		}
-
-		// Store refresh token
-		if err := s.userRepo.SetRefreshToken(ctx, admin.ID, refreshToken); err != nil {
-			return nil, fmt.Errorf("failed to store refresh token: %w", err)
+			return nil, err
 		}
 
 		return &models.AuthResponse{
@@ -248,6 +270,7 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
 			ExpiresIn:    int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
+			Invitation:   invitation,
 		}, nil
 
 	} else if req.UserType == "user" {
@@ -261,27 +284,19 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			UserType:      models.UserTypeExternal,  // Use external type for regular users
 			Status:        models.UserStatusPending, // External users need approval
 		}
-
-		if err := s.userRepo.Create(ctx, user); err != nil {
-			return nil, fmt.Errorf("failed to create user: %w", err)
+		if invitation != nil {
+			user.Status = models.UserStatusActive // Invited users were vetted by the inviting admin
 		}
 
 		// Note: Recovery codes will be displayed to user after registration
-
-		// Generate tokens
-		accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, "user", false)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate access token: %w", err)
-		}
-
-		refreshToken, err := s.jwtManager.GenerateRefreshToken(user.ID, user.Email, false)
+		accessToken, refreshToken, err := s.createAccount(ctx, invitation, "user", false, func(ctx context.Context) (primitive.ObjectID, string, error) {
+			if err := s.userRepo.Create(ctx, user); err != nil {
+				return primitive.NilObjectID, "", fmt.Errorf("failed to create user: %w", err)
+			}
+			return user.ID, user.Email, nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-		}
-
-		// Store refresh token
-		if err := s.userRepo.SetRefreshToken(ctx, user.ID, refreshToken); err != nil {
-			return nil, fmt.Errorf("failed to store refresh token: %w", err)
+			return nil, err
 		}
 
 		return &models.AuthResponse{
@@ -289,12 +304,54 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
 			ExpiresIn:    int64(s.jwtManager.GetAccessTokenExpiry().Seconds()),
+			Invitation:   invitation,
 		}, nil
 	}
 
 	return nil, errors.New("invalid user type")
 }
 
+// createAccount creates a registering account with create, consumes the invitation it registers
+// with and stores its refresh token in one transaction, so a registration that fails part way leaves
+// no account behind holding the email. It returns the account's tokens.
+func (s *userService) createAccount(ctx context.Context, invitation *models.Invitation, userType string, isAdmin bool, create func(ctx context.Context) (primitive.ObjectID, string, error)) (string, string, error) {
+	var accessToken, refreshToken string
+	err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		userID, email, err := create(ctx)
+		if err != nil {
+			return err
+		}
+
+		if invitation != nil {
+			if err := s.invitationService.AcceptInvitation(ctx, invitation, userID); err != nil {
+				return err
+			}
+		}
+
+		// Generate tokens
+		accessToken, err = s.jwtManager.GenerateAccessToken(userID, email, userType, isAdmin)
+		if err != nil {
+			return fmt.Errorf("failed to generate access token: %w", err)
+		}
+
+		refreshToken, err = s.jwtManager.GenerateRefreshToken(userID, email, false)
+		if err != nil {
+			return fmt.Errorf("failed to generate refresh token: %w", err)
+		}
+
+		// Store refresh token
+		if err := s.userRepo.SetRefreshToken(ctx, userID, refreshToken); err != nil {
+			return fmt.Errorf("failed to store refresh token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	s.cache.Invalidate(ctx, utils.CacheUserStats)
+	return accessToken, refreshToken, nil
+}
+
 func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error) {
 	// Try to find user in all collections
 	var user interface{}
@@ -302,6 +359,7 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 	var email, userType string
 	var isAdmin bool
 	var passwordHash string
+	var deletionScheduledAt *time.Time
 
 	// Check mahasiswa collection
 	mahasiswa, err := s.userRepo.GetMahasiswaByEmail(ctx, req.Email)
@@ -312,6 +370,7 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		userType = "mahasiswa"
 		isAdmin = false
 		passwordHash = mahasiswa.PasswordHash
+		deletionScheduledAt = mahasiswa.DeletionScheduledAt
 	} else {
 		// Check admin collection
 		admin, err := s.userRepo.GetAdminByEmail(ctx, req.Email)
@@ -322,11 +381,12 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 			userType = "admin"
 			isAdmin = admin.IsAdmin
 			passwordHash = admin.PasswordHash
+			deletionScheduledAt = admin.DeletionScheduledAt
 		} else {
 			// Check regular user collection
 			regularUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 			if err != nil {
-				return nil, errors.New("invalid email or password")
+				return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeInvalidCredentials, "invalid email or password")
 			}
 			user = regularUser
 			userID = regularUser.ID
@@ -334,19 +394,27 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 			userType = "user"
 			isAdmin = false
 			passwordHash = regularUser.PasswordHash
+			deletionScheduledAt = regularUser.DeletionScheduledAt
 		}
 	}
 
 	// Verify password
 	valid, err := utils.VerifyPassword(req.Password, passwordHash)
 	if err != nil || !valid {
-		return nil, errors.New("invalid email or password")
+		return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeInvalidCredentials, "invalid email or password")
+	}
+
+	// Logging in during the deletion grace period restores the account
+	if deletionScheduledAt != nil {
+		if err := s.userRepo.CancelDeletion(ctx, userID); err != nil {
+			return nil, fmt.Errorf("failed to restore account: %w", err)
+		}
 	}
 
 	// Update last login
 	if err := s.userRepo.UpdateLastLogin(ctx, userID); err != nil {
 		// Log error but don't fail login
-		fmt.Printf("Failed to update last login: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to update last login", "error", err)
 	}
 
 	// Generate tokens
@@ -369,7 +437,7 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 	if req.RememberMe {
 		if err := s.userRepo.Update(ctx, userID, map[string]interface{}{"remember_me": true}); err != nil {
 			// Log error but don't fail login
-			fmt.Printf("Failed to update remember me setting: %v\n", err)
+			slog.ErrorContext(ctx, "Failed to update remember me setting", "error", err)
 		}
 	}
 
@@ -385,7 +453,7 @@ func (s *userService) RefreshToken(ctx context.Context, req *models.RefreshToken
 	// Validate refresh token
 	claims, err := s.jwtManager.ValidateToken(req.RefreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "invalid refresh token")
 	}
 
 	// Get user from database
@@ -397,7 +465,7 @@ func (s *userService) RefreshToken(ctx context.Context, req *models.RefreshToken
 	// Verify refresh token exists in database
 	user, err := s.userRepo.GetByRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, models.NewCodedError(http.StatusUnauthorized, models.ErrorCodeTokenInvalid, "invalid refresh token")
 	}
 
 	// Determine user type and get full user data
@@ -451,6 +519,48 @@ func (s *userService) Logout(ctx context.Context, userID primitive.ObjectID) err
 	return s.userRepo.ClearRefreshToken(ctx, userID)
 }
 
+func (s *userService) ImpersonateUser(ctx context.Context, adminID, targetID primitive.ObjectID) (*models.ImpersonationResponse, error) {
+	if adminID == targetID {
+		return nil, models.ForbiddenError("cannot impersonate yourself")
+	}
+
+	// Admin accounts cannot be impersonated so the token never carries admin privileges
+	if _, err := s.userRepo.GetAdminByID(ctx, targetID); err == nil {
+		return nil, models.ForbiddenError("cannot impersonate an admin")
+	}
+
+	var fullUser interface{}
+	var email string
+	var userType string
+
+	mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, targetID)
+	if err == nil {
+		fullUser = mahasiswa
+		email = mahasiswa.Email
+		userType = "mahasiswa"
+	} else {
+		user, err := s.userRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return nil, repository.ErrUserNotFound
+		}
+		fullUser = user
+		email = user.Email
+		userType = "user"
+	}
+
+	accessToken, err := s.jwtManager.GenerateImpersonationToken(targetID, email, userType, false, adminID, impersonationTokenDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	return &models.ImpersonationResponse{
+		User:           fullUser,
+		AccessToken:    accessToken,
+		ExpiresIn:      int64(impersonationTokenDuration.Seconds()),
+		ImpersonatedBy: adminID.Hex(),
+	}, nil
+}
+
 func (s *userService) GetProfile(ctx context.Context, userID primitive.ObjectID) (interface{}, error) {
 	// Try to get user from different collections
 	mahasiswa, err := s.userRepo.GetMahasiswaByID(ctx, userID)
@@ -465,7 +575,7 @@ func (s *userService) GetProfile(ctx context.Context, userID primitive.ObjectID)
 
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, repository.ErrUserNotFound
 	}
 
 	return user, nil
@@ -486,13 +596,20 @@ func (s *userService) ChangePassword(ctx context.Context, userID primitive.Objec
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return repository.ErrUserNotFound
 	}
 
 	// Verify current password
 	valid, err := utils.VerifyPassword(req.CurrentPassword, user.PasswordHash)
 	if err != nil || !valid {
-		return errors.New("invalid current password")
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeInvalidCredentials, "invalid current password")
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.CheckReuse(req.NewPassword, user); err != nil {
+		return err
 	}
 
 	// Hash new password
@@ -502,8 +619,8 @@ func (s *userService) ChangePassword(ctx context.Context, userID primitive.Objec
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Update password
-	return s.userRepo.UpdatePassword(ctx, userID, hashedPassword)
+	// Update password, remembering the old one for reuse checks
+	return s.userRepo.UpdatePasswordWithHistory(ctx, userID, hashedPassword, s.passwordPolicy.NextHistory(user))
 }
 
 func (s *userService) RequestPasswordReset(ctx context.Context, req *models.PasswordResetRequest) (*models.PasswordResetOptionsResponse, error) {
@@ -534,7 +651,14 @@ func (s *userService) ResetPassword(ctx context.Context, req *models.PasswordRes
 	// Get user by reset token
 	user, err := s.userRepo.GetByResetToken(ctx, req.Token)
 	if err != nil {
-		return errors.New("invalid or expired reset token")
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeTokenInvalid, "invalid or expired reset token")
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.CheckReuse(req.NewPassword, user); err != nil {
+		return err
 	}
 
 	// Hash new password
@@ -545,7 +669,7 @@ func (s *userService) ResetPassword(ctx context.Context, req *models.PasswordRes
 	}
 
 	// Update password and clear reset token
-	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+	if err := s.userRepo.UpdatePasswordWithHistory(ctx, user.ID, hashedPassword, s.passwordPolicy.NextHistory(user)); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
@@ -597,25 +721,18 @@ func (s *userService) OAuthLogin(ctx context.Context, req *models.OAuthRequest)
 
 	// Handle OAuth ID - it might be a string or number depending on provider
 	var oauthID string
-	fmt.Printf("🔍 Processing OAuth ID: %v (type: %T)\n", userInfo["id"], userInfo["id"])
-
 	if id, ok := userInfo["id"].(string); ok {
 		oauthID = id
-		fmt.Printf("✅ OAuth ID as string: %s\n", oauthID)
 	} else if id, ok := userInfo["id"].(float64); ok {
 		oauthID = fmt.Sprintf("%.0f", id)
-		fmt.Printf("✅ OAuth ID as float64: %s\n", oauthID)
 	} else if id, ok := userInfo["id"].(int64); ok {
 		oauthID = fmt.Sprintf("%d", id)
-		fmt.Printf("✅ OAuth ID as int64: %s\n", oauthID)
 	} else if id, ok := userInfo["id"].(int); ok {
 		oauthID = fmt.Sprintf("%d", id)
-		fmt.Printf("✅ OAuth ID as int: %s\n", oauthID)
 	}
 
 	// Validate required fields
 	if oauthID == "" {
-		fmt.Printf("❌ Failed to extract OAuth ID from: %v (type: %T)\n", userInfo["id"], userInfo["id"])
 		return nil, fmt.Errorf("OAuth ID is required from provider (got: %v, type: %T)", userInfo["id"], userInfo["id"])
 	}
 
@@ -709,40 +826,30 @@ func (s *userService) getGoogleUserInfo(ctx context.Context, config *oauth2.Conf
 }
 
 func (s *userService) getFacebookUserInfo(ctx context.Context, config *oauth2.Config, code string) (map[string]interface{}, error) {
-	fmt.Printf("🔄 Exchanging Facebook OAuth code for token...\n")
 	token, err := config.Exchange(ctx, code)
 	if err != nil {
-		fmt.Printf("❌ Failed to exchange Facebook OAuth code: %v\n", err)
 		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
-	fmt.Printf("✅ Successfully obtained Facebook OAuth token\n")
 
 	client := config.Client(ctx, token)
 	// Note: Using public_profile scope only (email removed due to Facebook restrictions)
-	fmt.Printf("🔄 Fetching Facebook user info...\n")
 	resp, err := client.Get("https://graph.facebook.com/me?fields=id,name,picture.type(large)")
 	if err != nil {
-		fmt.Printf("❌ Failed to fetch Facebook user info: %v\n", err)
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		fmt.Printf("❌ Facebook API returned status %d\n", resp.StatusCode)
 		return nil, fmt.Errorf("Facebook API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("❌ Failed to read Facebook user response: %v\n", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	fmt.Printf("📄 Facebook user response: %s\n", string(body))
-
 	var userInfo map[string]interface{}
 	if err := json.Unmarshal(body, &userInfo); err != nil {
-		fmt.Printf("❌ Failed to parse Facebook user response: %v\n", err)
 		return nil, fmt.Errorf("failed to parse user info: %w", err)
 	}
 
@@ -750,7 +857,6 @@ func (s *userService) getFacebookUserInfo(ctx context.Context, config *oauth2.Co
 	// Generate a placeholder email based on Facebook ID
 	if facebookID, ok := userInfo["id"].(string); ok {
 		userInfo["email"] = fmt.Sprintf("facebook_%s@facebook.local", facebookID)
-		fmt.Printf("ℹ️ Generated placeholder email for Facebook user: %s\n", userInfo["email"])
 	}
 
 	// Extract picture URL from nested structure
@@ -758,128 +864,100 @@ func (s *userService) getFacebookUserInfo(ctx context.Context, config *oauth2.Co
 		if data, ok := picture["data"].(map[string]interface{}); ok {
 			if url, ok := data["url"].(string); ok {
 				userInfo["picture"] = url
-				fmt.Printf("✅ Extracted Facebook profile picture URL\n")
 			}
 		}
 	}
 
-	fmt.Printf("✅ Successfully retrieved Facebook user info for ID: %v\n", userInfo["id"])
 	return userInfo, nil
 }
 
 func (s *userService) getGithubUserInfo(ctx context.Context, config *oauth2.Config, code string) (map[string]interface{}, error) {
-	fmt.Printf("🔄 Exchanging GitHub OAuth code for token...\n")
 	token, err := config.Exchange(ctx, code)
 	if err != nil {
-		fmt.Printf("❌ Failed to exchange GitHub OAuth code: %v\n", err)
 		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
-	fmt.Printf("✅ Successfully obtained GitHub OAuth token\n")
 
 	client := config.Client(ctx, token)
-	fmt.Printf("🔄 Fetching GitHub user info...\n")
 	resp, err := client.Get("https://api.github.com/user")
 	if err != nil {
-		fmt.Printf("❌ Failed to fetch GitHub user info: %v\n", err)
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		fmt.Printf("❌ GitHub API returned status %d\n", resp.StatusCode)
 		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("❌ Failed to read GitHub user response: %v\n", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	fmt.Printf("📄 GitHub user response: %s\n", string(body))
-
 	var userInfo map[string]interface{}
 	if err := json.Unmarshal(body, &userInfo); err != nil {
-		fmt.Printf("❌ Failed to parse GitHub user response: %v\n", err)
 		return nil, fmt.Errorf("failed to parse user info: %w", err)
 	}
 
 	// Get user's primary email
-	fmt.Printf("🔄 Fetching GitHub user emails...\n")
 	emailResp, err := client.Get("https://api.github.com/user/emails")
 	if err == nil {
 		defer emailResp.Body.Close()
 		if emailResp.StatusCode == 200 {
 			emailBody, _ := io.ReadAll(emailResp.Body)
-			fmt.Printf("📧 GitHub emails response: %s\n", string(emailBody))
 			var emails []map[string]interface{}
 			if json.Unmarshal(emailBody, &emails) == nil {
 				for _, email := range emails {
 					if primary, ok := email["primary"].(bool); ok && primary {
 						userInfo["email"] = email["email"]
-						fmt.Printf("✅ Found primary email: %s\n", email["email"])
 						break
 					}
 				}
 			}
 		} else {
-			fmt.Printf("⚠️ GitHub emails API returned status %d\n", emailResp.StatusCode)
+			slog.WarnContext(ctx, "GitHub emails API returned an error", "status", emailResp.StatusCode)
 		}
 	} else {
-		fmt.Printf("⚠️ Failed to fetch GitHub emails: %v\n", err)
+		slog.WarnContext(ctx, "Failed to fetch GitHub emails", "error", err)
 	}
 
 	// Ensure we have an ID field
 	if userInfo["id"] == nil {
-		fmt.Printf("❌ GitHub user info missing ID field\n")
 		return nil, fmt.Errorf("GitHub user info missing required ID field")
 	}
 
-	fmt.Printf("✅ Successfully retrieved GitHub user info for ID: %v\n", userInfo["id"])
 	return userInfo, nil
 }
 
 func (s *userService) getXUserInfo(ctx context.Context, config *oauth2.Config, code string) (map[string]interface{}, error) {
-	fmt.Printf("🔄 Exchanging X OAuth code for token...\n")
 	token, err := config.Exchange(ctx, code)
 	if err != nil {
-		fmt.Printf("❌ Failed to exchange X OAuth code: %v\n", err)
 		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
-	fmt.Printf("✅ Successfully obtained X OAuth token\n")
 
 	client := config.Client(ctx, token)
-	fmt.Printf("🔄 Fetching X user info...\n")
 	resp, err := client.Get("https://api.x.com/2/users/me?user.fields=id,username,name,profile_image_url")
 	if err != nil {
-		fmt.Printf("❌ Failed to fetch X user info: %v\n", err)
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		fmt.Printf("❌ X API returned status %d\n", resp.StatusCode)
 		return nil, fmt.Errorf("X API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("❌ Failed to read X user response: %v\n", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	fmt.Printf("📄 X user response: %s\n", string(body))
-
 	var response map[string]interface{}
 	if err := json.Unmarshal(body, &response); err != nil {
-		fmt.Printf("❌ Failed to parse X user response: %v\n", err)
 		return nil, fmt.Errorf("failed to parse user info: %w", err)
 	}
 
 	// Extract user data from X API response
 	data, ok := response["data"].(map[string]interface{})
 	if !ok {
-		fmt.Printf("❌ Invalid X API response format - missing 'data' field\n")
 		return nil, errors.New("invalid response format from X API")
 	}
 
@@ -899,7 +977,6 @@ func (s *userService) getXUserInfo(ctx context.Context, config *oauth2.Config, c
 		"picture": data["profile_image_url"],
 	}
 
-	fmt.Printf("✅ Successfully retrieved X user info for ID: %v\n", userInfo["id"])
 	return userInfo, nil
 }
 
@@ -929,7 +1006,7 @@ func (s *userService) loginExistingOAuthUser(ctx context.Context, user *models.U
 
 	// Update last login
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		fmt.Printf("Failed to update last login: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to update last login", "error", err)
 	}
 
 	// Generate tokens
@@ -1016,6 +1093,7 @@ func (s *userService) createOAuthUser(ctx context.Context, email, name, picture,
 			}
 			return nil, fmt.Errorf("failed to create mahasiswa: %w", err)
 		}
+		s.cache.Invalidate(ctx, utils.CacheUserStats)
 
 		// OAuth users don't need email verification (already verified by OAuth provider)
 
@@ -1076,6 +1154,7 @@ func (s *userService) createOAuthUser(ctx context.Context, email, name, picture,
 			}
 			return nil, fmt.Errorf("failed to create admin: %w", err)
 		}
+		s.cache.Invalidate(ctx, utils.CacheUserStats)
 
 		// Generate tokens
 		accessToken, err := s.jwtManager.GenerateAccessToken(admin.ID, admin.Email, "admin", true)
@@ -1132,6 +1211,7 @@ func (s *userService) createOAuthUser(ctx context.Context, email, name, picture,
 			}
 			return nil, fmt.Errorf("failed to create user: %w", err)
 		}
+		s.cache.Invalidate(ctx, utils.CacheUserStats)
 
 		// Generate tokens
 		accessToken, err := s.jwtManager.GenerateAccessToken(user.ID, user.Email, "user", false)
@@ -1162,17 +1242,54 @@ func (s *userService) createOAuthUser(ctx context.Context, email, name, picture,
 	return nil, errors.New("invalid user type")
 }
 
+// GetPasswordPolicy returns the active password rules
+func (s *userService) GetPasswordPolicy() *models.PasswordPolicyResponse {
+	return s.passwordPolicy.GetPolicy()
+}
+
 // UpdateLastLogout updates the user's last logout timestamp
 func (s *userService) UpdateLastLogout(userID string) error {
 	return s.userRepo.UpdateLastLogout(userID)
 }
 
+// GetUserStats returns the user counts for the admin dashboard, cached until users are added or change status
+func (s *userService) GetUserStats(ctx context.Context) (*models.UserStatsResponse, error) {
+	var cached models.UserStatsResponse
+	if s.cache.Get(ctx, utils.CacheUserStats, "all", &cached) {
+		return &cached, nil
+	}
+	stats, err := s.userRepo.GetUserStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user statistics: %w", err)
+	}
+	s.cache.Set(ctx, utils.CacheUserStats, "all", stats)
+	return stats, nil
+}
+
+// ListUsers returns a page of accounts for the admin user list
+func (s *userService) ListUsers(ctx context.Context, req *models.ListUsersRequest) (*models.ListUsersResponse, error) {
+	response, err := s.userRepo.ListUsers(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return response, nil
+}
+
+// UpdateUserStatus sets a user's account status
+func (s *userService) UpdateUserStatus(ctx context.Context, userID primitive.ObjectID, status models.UserStatus) error {
+	if err := s.userRepo.UpdateUserStatus(ctx, userID, status); err != nil {
+		return err
+	}
+	s.cache.Invalidate(ctx, utils.CacheUserStats)
+	return nil
+}
+
 // ResetPasswordWithRecoveryCode allows users to reset password using recovery codes
 func (s *userService) ResetPasswordWithRecoveryCode(ctx context.Context, req *models.PasswordResetWithRecoveryRequest) error {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return errors.New("invalid email or recovery code")
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeInvalidCredentials, "invalid email or recovery code")
 	}
 
 	// Check if recovery code exists and remove it (single use)
@@ -1187,7 +1304,14 @@ func (s *userService) ResetPasswordWithRecoveryCode(ctx context.Context, req *mo
 	}
 
 	if !found {
-		return errors.New("invalid recovery code")
+		return models.NewCodedError(http.StatusBadRequest, models.ErrorCodeInvalidCredentials, "invalid recovery code")
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.CheckReuse(req.NewPassword, user); err != nil {
+		return err
 	}
 
 	// Hash new password
@@ -1199,9 +1323,10 @@ func (s *userService) ResetPasswordWithRecoveryCode(ctx context.Context, req *mo
 
 	// Update password and recovery codes
 	updates := map[string]interface{}{
-		"password_hash":  hashedPassword,
-		"recovery_codes": newCodes,
-		"updated_at":     time.Now(),
+		"password_hash":    hashedPassword,
+		"password_history": s.passwordPolicy.NextHistory(user),
+		"recovery_codes":   newCodes,
+		"updated_at":       time.Now(),
 	}
 
 	return s.userRepo.Update(ctx, user.ID, updates)
@@ -1236,7 +1361,7 @@ func (s *userService) GetRecoveryCodes(ctx context.Context, userID primitive.Obj
 	// Get user
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, repository.ErrUserNotFound
 	}
 
 	if len(user.RecoveryCodes) == 0 {
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiKeyPrefix marks keys issued by this service so they are easy to spot in logs and secret scanners
+const apiKeyPrefix = "zk_"
+
+// GenerateAPIKey returns a new random API key and the short prefix shown to admins to identify it
+func GenerateAPIKey() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	key := apiKeyPrefix + hex.EncodeToString(bytes)
+	return key, key[:len(apiKeyPrefix)+8], nil
+}
+
+// HashAPIKey returns the digest stored in place of the key. API keys are long and random,
+// so a fast hash is enough and lets keys be looked up directly by their digest.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// BackgroundTasks runs work handed off from requests on its own goroutines and counts what is still
+// pending, by kind, so health checks can report the backlog and shutdown can wait for it. A nil
+// BackgroundTasks runs tasks without counting them.
+type BackgroundTasks struct {
+	mu      sync.Mutex
+	pending map[string]int
+	wg      sync.WaitGroup
+}
+
+func NewBackgroundTasks() *BackgroundTasks {
+	return &BackgroundTasks{pending: make(map[string]int)}
+}
+
+// Go runs fn on a new goroutine, counted under kind until it returns
+func (b *BackgroundTasks) Go(kind string, fn func()) {
+	if b == nil {
+		go fn()
+		return
+	}
+
+	b.mu.Lock()
+	b.pending[kind]++
+	b.mu.Unlock()
+	b.wg.Add(1)
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			b.pending[kind]--
+			if b.pending[kind] == 0 {
+				delete(b.pending, kind)
+			}
+			b.mu.Unlock()
+			b.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+// Pending returns how many tasks have not finished, in total and by kind
+func (b *BackgroundTasks) Pending() (int, map[string]int) {
+	byKind := make(map[string]int)
+	if b == nil {
+		return 0, byKind
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	total := 0
+	for kind, count := range b.pending {
+		byKind[kind] = count
+		total += count
+	}
+	return total, byKind
+}
+
+// Wait blocks until every task has finished or the context is done
+func (b *BackgroundTasks) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,221 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheNamespace groups cached reads that are invalidated together
+type CacheNamespace string
+
+const (
+	CacheModules       CacheNamespace = "modules"        // Published module lists
+	CacheQuestionStats CacheNamespace = "question_stats" // Question bank statistics
+	CacheLeaderboards  CacheNamespace = "leaderboards"   // Team leaderboards
+	CacheQuizTemplates CacheNamespace = "quiz_templates" // Quiz templates students start from
+	CacheUserStats     CacheNamespace = "user_stats"     // Admin user statistics
+)
+
+// CacheStore holds raw cache entries. Incr counts up a key that never expires.
+type CacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+// Cache keeps JSON copies of hot reads for a while. Each namespace has a generation that is part of
+// every key in it, so invalidating a namespace, whatever was read with whichever parameters, is
+// counting its generation up; the old entries are never read again and expire. Cache failures are
+// logged and read as misses, never failing a request, and a nil Cache caches nothing.
+type Cache struct {
+	store CacheStore
+	ttl   time.Duration
+}
+
+type cacheBypassKey struct{}
+
+// NewCache returns the cache selected by the config, or nil when caching is off
+func NewCache(config models.CacheConfig) (*Cache, error) {
+	var store CacheStore
+	switch config.Store {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		store = &memoryCacheStore{entries: make(map[string]memoryCacheEntry), counters: make(map[string]int64)}
+	case "redis":
+		client, err := NewRedisClient(config.RedisURL, config.RedisTimeout)
+		if err != nil {
+			return nil, err
+		}
+		store = &redisCacheStore{client: client}
+	default:
+		return nil, fmt.Errorf("unsupported cache store: %s", config.Store)
+	}
+	return &Cache{store: store, ttl: config.TTL}, nil
+}
+
+// WithCacheBypass returns a context whose reads skip the cache; what they load still refreshes it
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// Get decodes the cached value of key into dest, reporting whether there was one
+func (c *Cache) Get(ctx context.Context, namespace CacheNamespace, key string, dest any) bool {
+	if c == nil {
+		return false
+	}
+	if bypass, _ := ctx.Value(cacheBypassKey{}).(bool); bypass {
+		return false
+	}
+	fullKey, err := c.key(ctx, namespace, key)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read cache", "namespace", namespace, "error", err)
+		return false
+	}
+	data, found, err := c.store.Get(ctx, fullKey)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read cache", "namespace", namespace, "error", err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		slog.WarnContext(ctx, "Failed to decode cached value", "namespace", namespace, "error", err)
+		return false
+	}
+	return true
+}
+
+// Set caches value under key for the configured time
+func (c *Cache) Set(ctx context.Context, namespace CacheNamespace, key string, value any) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to encode value to cache", "namespace", namespace, "error", err)
+		return
+	}
+	fullKey, err := c.key(ctx, namespace, key)
+	if err == nil {
+		err = c.store.Set(ctx, fullKey, data, c.ttl)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to write cache", "namespace", namespace, "error", err)
+	}
+}
+
+// Invalidate drops everything cached in the namespaces
+func (c *Cache) Invalidate(ctx context.Context, namespaces ...CacheNamespace) {
+	if c == nil {
+		return
+	}
+	for _, namespace := range namespaces {
+		if _, err := c.store.Incr(ctx, generationKey(namespace)); err != nil {
+			slog.WarnContext(ctx, "Failed to invalidate cache", "namespace", namespace, "error", err)
+		}
+	}
+}
+
+func (c *Cache) key(ctx context.Context, namespace CacheNamespace, key string) (string, error) {
+	data, _, err := c.store.Get(ctx, generationKey(namespace))
+	if err != nil {
+		return "", err
+	}
+	generation := "0"
+	if len(data) > 0 {
+		generation = string(data)
+	}
+	return "cache:" + string(namespace) + ":" + generation + ":" + key, nil
+}
+
+func generationKey(namespace CacheNamespace) string {
+	return "cache:" + string(namespace) + ":generation"
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryCacheStore keeps entries in the server's memory, so each instance caches separately
+type memoryCacheStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryCacheEntry
+	counters  map[string]int64
+	lastSweep time.Time
+}
+
+func (s *memoryCacheStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if counter, ok := s.counters[key]; ok {
+		return []byte(strconv.FormatInt(counter, 10)), true, nil
+	}
+	entry, ok := s.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *memoryCacheStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries now and then, including those of invalidated generations
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for k, entry := range s.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = memoryCacheEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *memoryCacheStore) Incr(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[key]++
+	return s.counters[key], nil
+}
+
+// redisCacheStore keeps entries in Redis, shared by every server instance
+type redisCacheStore struct {
+	client *redis.Client
+}
+
+func (s *redisCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *redisCacheStore) Incr(ctx context.Context, key string) (int64, error) {
+	return s.client.Incr(ctx, key).Result()
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/models"
+)
+
+const (
+	recaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// ErrCaptchaFailed is returned when a captcha token is missing, invalid or scored as a bot
+var ErrCaptchaFailed = models.NewCodedError(http.StatusForbidden, models.ErrorCodeCaptcha, "captcha verification failed")
+
+// CaptchaVerifier checks a token produced by the captcha widget on the client
+type CaptchaVerifier interface {
+	Enabled() bool
+	Provider() string
+	SiteKey() string
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// NewCaptchaVerifier returns the verifier selected by the config
+func NewCaptchaVerifier(config models.CaptchaConfig) (CaptchaVerifier, error) {
+	provider := strings.ToLower(config.Provider)
+
+	var verifyURL string
+	switch provider {
+	case "":
+		return &captchaVerifier{config: config}, nil
+	case "recaptcha":
+		verifyURL = recaptchaVerifyURL
+	case "turnstile":
+		verifyURL = turnstileVerifyURL
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q", config.Provider)
+	}
+
+	if config.SecretKey == "" {
+		return nil, errors.New("CAPTCHA_SECRET_KEY is required when CAPTCHA_PROVIDER is set")
+	}
+
+	return &captchaVerifier{
+		config:    config,
+		provider:  provider,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// captchaVerifier talks to the siteverify endpoint, which reCAPTCHA and Turnstile share the shape of
+type captchaVerifier struct {
+	config    models.CaptchaConfig
+	provider  string
+	verifyURL string
+	client    *http.Client
+}
+
+type captchaVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score,omitempty"` // reCAPTCHA v3 only
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *captchaVerifier) Enabled() bool    { return v.provider != "" }
+func (v *captchaVerifier) Provider() string { return v.provider }
+func (v *captchaVerifier) SiteKey() string  { return v.config.SiteKey }
+
+func (v *captchaVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if !v.Enabled() {
+		return nil
+	}
+	if token == "" {
+		return ErrCaptchaFailed
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.config.SecretKey)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to verify captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var result captchaVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode captcha response: %w", err)
+	}
+
+	if !result.Success {
+		return ErrCaptchaFailed
+	}
+	if result.Score != nil && *result.Score < v.config.MinScore {
+		return ErrCaptchaFailed
+	}
+	return nil
+}
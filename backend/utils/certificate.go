@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// CertificateCodeLength makes certificate codes long enough that they cannot be guessed
+const CertificateCodeLength = 12
+
+// GenerateCertificateCode returns a random certificate verification code, grouped as xxxx-xxxx-xxxx
+func GenerateCertificateCode() (string, error) {
+	code, err := randomCode(CertificateCodeLength)
+	if err != nil {
+		return "", err
+	}
+	return code[:4] + "-" + code[4:8] + "-" + code[8:], nil
+}
+
+// SignCertificate returns the hex HMAC-SHA256 of a certificate's fields, one per line
+func SignCertificate(key string, fields ...string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCertificateSignature reports whether signature matches the certificate's fields
+func VerifyCertificateSignature(key, signature string, fields ...string) bool {
+	return hmac.Equal([]byte(SignCertificate(key, fields...)), []byte(signature))
+}
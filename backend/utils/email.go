@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"net/smtp"
+	"time"
 
 	"backend/models"
 )
@@ -40,6 +41,20 @@ func (e *EmailService) SendWelcomeEmail(email, fullName string) error {
 	return e.sendEmail(email, subject, body)
 }
 
+func (e *EmailService) SendInvitationEmail(email, fullName, password, loginURL string) error {
+	subject := "You're Invited to QuizApp"
+	body := e.generateInvitationHTML(fullName, email, password, loginURL)
+
+	return e.sendEmail(email, subject, body)
+}
+
+func (e *EmailService) SendRegistrationInviteEmail(email, invitedBy, userType, inviteURL string, expiresAt time.Time) error {
+	subject := "You're Invited to Join QuizApp"
+	body := e.generateRegistrationInviteHTML(invitedBy, userType, inviteURL, expiresAt)
+
+	return e.sendEmail(email, subject, body)
+}
+
 func (e *EmailService) sendEmail(to, subject, body string) error {
 	auth := smtp.PlainAuth("", e.config.SMTPUsername, e.config.SMTPPassword, e.config.SMTPHost)
 
@@ -179,3 +194,102 @@ func (e *EmailService) generateWelcomeHTML(fullName string) string {
 	})
 	return buf.String()
 }
+
+func (e *EmailService) generateInvitationHTML(fullName, email, password, loginURL string) string {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>You're Invited to QuizApp</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #6366F1; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 8px 8px; }
+        .button { display: inline-block; background: #6366F1; color: white; padding: 12px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; }
+        .credentials { background: white; border: 1px solid #ddd; padding: 15px; border-radius: 5px; }
+        .footer { margin-top: 30px; font-size: 14px; color: #666; text-align: center; }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>Welcome to QuizApp!</h1>
+    </div>
+    <div class="content">
+        <p>Hello {{.FullName}},</p>
+        <p>An account has been created for you on QuizApp.</p>
+        {{if .Password}}
+        <div class="credentials">
+            <p><strong>Email:</strong> {{.Email}}</p>
+            <p><strong>Temporary password:</strong> {{.Password}}</p>
+        </div>
+        <p>Please change your password after your first login.</p>
+        {{end}}
+        <a href="{{.LoginURL}}" class="button">Log In</a>
+        <p>If the button doesn't work, copy and paste this link into your browser:</p>
+        <p style="word-break: break-all;">{{.LoginURL}}</p>
+        <p>Best regards,<br>The QuizApp Team</p>
+    </div>
+    <div class="footer">
+        <p>This is an automated email. Please do not reply to this email.</p>
+    </div>
+</body>
+</html>`
+
+	t, _ := template.New("invitation").Parse(tmpl)
+	var buf bytes.Buffer
+	t.Execute(&buf, map[string]string{
+		"FullName": fullName,
+		"Email":    email,
+		"Password": password,
+		"LoginURL": loginURL,
+	})
+	return buf.String()
+}
+
+func (e *EmailService) generateRegistrationInviteHTML(invitedBy, userType, inviteURL string, expiresAt time.Time) string {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>You're Invited to Join QuizApp</title>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #6366F1; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+        .content { background: #f9f9f9; padding: 30px; border-radius: 0 0 8px 8px; }
+        .button { display: inline-block; background: #6366F1; color: white; padding: 12px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; }
+        .footer { margin-top: 30px; font-size: 14px; color: #666; text-align: center; }
+    </style>
+</head>
+<body>
+    <div class="header">
+        <h1>You're Invited!</h1>
+    </div>
+    <div class="content">
+        <p>Hello,</p>
+        <p>{{.InvitedBy}} has invited you to create a {{.UserType}} account on QuizApp. Accounts created from this invitation are approved right away.</p>
+        <a href="{{.InviteURL}}" class="button">Create Account</a>
+        <p>If the button doesn't work, copy and paste this link into your browser:</p>
+        <p style="word-break: break-all;">{{.InviteURL}}</p>
+        <p>This invitation expires on {{.ExpiresAt}} and can only be used with this email address.</p>
+        <p>Best regards,<br>The QuizApp Team</p>
+    </div>
+    <div class="footer">
+        <p>This is an automated email. Please do not reply to this email.</p>
+    </div>
+</body>
+</html>`
+
+	t, _ := template.New("registration-invite").Parse(tmpl)
+	var buf bytes.Buffer
+	t.Execute(&buf, map[string]string{
+		"InvitedBy": invitedBy,
+		"UserType":  userType,
+		"InviteURL": inviteURL,
+		"ExpiresAt": expiresAt.UTC().Format("2 January 2006 15:04 MST"),
+	})
+	return buf.String()
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// encryptionKeyLength selects AES-256
+const encryptionKeyLength = 32
+
+// GenerateEncryptionKey returns a random base64-encoded AES-256 key
+func GenerateEncryptionKey() (string, error) {
+	key := make([]byte, encryptionKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// DeriveEncryptionKey derives a base64-encoded AES-256 key for one purpose from a longer-lived secret
+func DeriveEncryptionKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EncryptAESGCM seals plaintext with a base64-encoded AES-256 key, binding additionalData to the
+// ciphertext. It returns the base64-encoded nonce and ciphertext.
+func EncryptAESGCM(encodedKey string, plaintext, additionalData []byte) (string, string, error) {
+	gcm, err := newGCM(encodedKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, additionalData)
+	return base64.StdEncoding.EncodeToString(nonce), base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptAESGCM opens a ciphertext produced by EncryptAESGCM, failing if it was tampered with or
+// sealed with a different key or additional data
+func DecryptAESGCM(encodedKey, encodedNonce, encodedCiphertext string, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(encodedNonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encodedCiphertext)
+	if err != nil {
+		return nil, errors.New("invalid ciphertext encoding")
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("decryption failed: file was modified or encrypted with a different key")
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(encodedKey string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != encryptionKeyLength {
+		return nil, errors.New("invalid encryption key")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
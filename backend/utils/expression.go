@@ -0,0 +1,428 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expressions compute the answers of parameterized questions from the values drawn for their
+// variables. They are arithmetic only:
+//
+//   - numbers, variables, and the constants pi and e
+//   - + - * / and ^ (power, right-associative), unary minus, parentheses
+//   - sqrt, abs, round, floor, ceil, sin, cos, tan (radians), ln, log (base 10), exp, min and max
+//
+// Nothing in an expression can reach outside the values it is given.
+
+// expressionFunctions maps each function name to its implementation and argument count
+var expressionFunctions = map[string]struct {
+	args int
+	call func(args []float64) float64
+}{
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"ln":    {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+var expressionConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// IsExpressionKeyword reports whether a name is taken by a function or constant
+func IsExpressionKeyword(name string) bool {
+	_, function := expressionFunctions[name]
+	_, constant := expressionConstants[name]
+	return function || constant
+}
+
+// Expression is a parsed arithmetic expression
+type Expression struct {
+	root exprNode
+}
+
+// ParseExpression parses an expression, reporting the first syntax error
+func ParseExpression(src string) (*Expression, error) {
+	tokens, err := tokenizeExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return &Expression{root: root}, nil
+}
+
+// Variables lists the variable names the expression uses, sorted
+func (e *Expression) Variables() []string {
+	seen := make(map[string]bool)
+	e.root.variables(seen)
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval computes the expression with the given variable values. A result that is not a finite number,
+// such as from dividing by zero, is an error.
+func (e *Expression) Eval(vars map[string]float64) (float64, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("expression has no finite value")
+	}
+	return value, nil
+}
+
+type exprNode interface {
+	eval(vars map[string]float64) (float64, error)
+	variables(seen map[string]bool)
+}
+
+type exprNumber float64
+
+func (n exprNumber) eval(map[string]float64) (float64, error) { return float64(n), nil }
+func (n exprNumber) variables(map[string]bool)                {}
+
+type exprVariable string
+
+func (v exprVariable) eval(vars map[string]float64) (float64, error) {
+	value, ok := vars[string(v)]
+	if !ok {
+		return 0, fmt.Errorf("unknown variable %s", string(v))
+	}
+	return value, nil
+}
+
+func (v exprVariable) variables(seen map[string]bool) { seen[string(v)] = true }
+
+type exprUnary struct {
+	operand exprNode
+}
+
+func (u exprUnary) eval(vars map[string]float64) (float64, error) {
+	value, err := u.operand.eval(vars)
+	return -value, err
+}
+
+func (u exprUnary) variables(seen map[string]bool) { u.operand.variables(seen) }
+
+type exprBinary struct {
+	op          byte
+	left, right exprNode
+}
+
+func (b exprBinary) eval(vars map[string]float64) (float64, error) {
+	left, err := b.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	right, err := b.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return left / right, nil
+	default:
+		return math.Pow(left, right), nil
+	}
+}
+
+func (b exprBinary) variables(seen map[string]bool) {
+	b.left.variables(seen)
+	b.right.variables(seen)
+}
+
+type exprCall struct {
+	name string
+	args []exprNode
+}
+
+func (c exprCall) eval(vars map[string]float64) (float64, error) {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		value, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = value
+	}
+	return expressionFunctions[c.name].call(args), nil
+}
+
+func (c exprCall) variables(seen map[string]bool) {
+	for _, arg := range c.args {
+		arg.variables(seen)
+	}
+}
+
+type exprTokenKind int
+
+const (
+	tokenNumber exprTokenKind = iota
+	tokenName
+	tokenSymbol
+)
+
+type exprToken struct {
+	kind  exprTokenKind
+	text  string
+	value float64
+}
+
+func tokenizeExpression(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Exponent, as in 6.02e23 or 1e-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					for j < len(runes) && unicode.IsDigit(runes[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", text)
+			}
+			tokens = append(tokens, exprToken{kind: tokenNumber, text: text, value: value})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokenName, text: string(runes[start:i])})
+		case strings.ContainsRune("+-*/^(),", r):
+			tokens = append(tokens, exprToken{kind: tokenSymbol, text: string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q", string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// exprParser is a recursive-descent parser over the grammar
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | "+" unary | power
+//	power   = atom [ "^" unary ]
+//	atom    = number | constant | variable | function "(" sum { "," sum } ")" | "(" sum ")"
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	depth  int
+}
+
+// maxExpressionDepth bounds nesting so a hostile expression cannot exhaust the stack
+const maxExpressionDepth = 100
+
+func (p *exprParser) peekSymbol(symbols string) (byte, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenSymbol {
+		return 0, false
+	}
+	symbol := p.tokens[p.pos].text[0]
+	return symbol, strings.IndexByte(symbols, symbol) >= 0
+}
+
+func (p *exprParser) expectSymbol(symbol byte) error {
+	if s, ok := p.peekSymbol(string(symbol)); ok && s == symbol {
+		p.pos++
+		return nil
+	}
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("expected %q at end of expression", string(symbol))
+	}
+	return fmt.Errorf("expected %q, found %q", string(symbol), p.tokens[p.pos].text)
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return nil, errors.New("expression is nested too deeply")
+	}
+
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekSymbol("+-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekSymbol("*/")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.peekSymbol("+-"); ok {
+		p.pos++
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > maxExpressionDepth {
+			return nil, errors.New("expression is nested too deeply")
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == '-' {
+			return exprUnary{operand: operand}, nil
+		}
+		return operand, nil
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.peekSymbol("^"); !ok {
+		return base, nil
+	}
+	p.pos++
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return exprBinary{op: '^', left: base, right: exponent}, nil
+}
+
+func (p *exprParser) parseAtom() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("unexpected end of expression")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case tokenNumber:
+		return exprNumber(token.value), nil
+
+	case tokenName:
+		if function, ok := expressionFunctions[token.text]; ok {
+			if err := p.expectSymbol('('); err != nil {
+				return nil, fmt.Errorf("%s: %w", token.text, err)
+			}
+			var args []exprNode
+			for {
+				arg, err := p.parseSum()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if _, ok := p.peekSymbol(","); !ok {
+					break
+				}
+				p.pos++
+			}
+			if err := p.expectSymbol(')'); err != nil {
+				return nil, err
+			}
+			if len(args) != function.args {
+				return nil, fmt.Errorf("%s takes %d argument(s), got %d", token.text, function.args, len(args))
+			}
+			return exprCall{name: token.text, args: args}, nil
+		}
+		if value, ok := expressionConstants[token.text]; ok {
+			return exprNumber(value), nil
+		}
+		return exprVariable(token.text), nil
+
+	default:
+		if token.text == "(" {
+			inner, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(')'); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+		return nil, fmt.Errorf("unexpected %q", token.text)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpressionEval(t *testing.T) {
+	vars := map[string]float64{"x": 3, "rate_2": 0.5}
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"1+2*3", 7},
+		{"(1+2)*3", 9},
+		{"10-4-3", 3},
+		{"12/4/3", 1},
+		{"2^3^2", 512}, // right-associative: 2^(3^2)
+		{"-2^2", -4},   // power binds tighter than unary minus
+		{"(-2)^2", 4},
+		{"2^-1", 0.5},
+		{"--x", 3},
+		{"+x", 3},
+		{"x*rate_2", 1.5},
+		{"1.5e2 + 1e-1", 150.1},
+		{"max(x, 2) + min(x, 2)", 5},
+		{"sqrt(16) + abs(-2) + round(2.5) + floor(2.7) + ceil(2.1)", 4 + 2 + 3 + 2 + 3},
+		{"log(1000) + ln(e) + exp(0)", 5},
+		{"2*pi", 2 * math.Pi},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) error = %v", tt.expr, err)
+			}
+			got, err := expr.Eval(vars)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionEvalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"division by zero", "1/(x-x)", "division by zero"},
+		{"unknown variable", "x + y", "unknown variable y"},
+		{"no finite value", "sqrt(0-1)", "no finite value"},
+		{"overflow", "10^400", "no finite value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) error = %v", tt.expr, err)
+			}
+			_, err = expr.Eval(map[string]float64{"x": 1})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Eval() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"empty", "", "expression is empty"},
+		{"only spaces", "   ", "expression is empty"},
+		{"unclosed parenthesis", "(1+2", `expected ")" at end of expression`},
+		{"extra closing parenthesis", "1+2)", `unexpected ")"`},
+		{"empty parentheses", "()", `unexpected ")"`},
+		{"trailing operator", "1+", "unexpected end of expression"},
+		{"unexpected character", "2 % 3", `unexpected "%"`},
+		{"invalid number", "1.2.3", `invalid number "1.2.3"`},
+		{"function without call", "sqrt 4", `sqrt: expected "("`},
+		{"wrong argument count", "max(1)", "max takes 2 argument(s), got 1"},
+		{"nested too deeply", strings.Repeat("(", 200) + "1" + strings.Repeat(")", 200), "nested too deeply"},
+		{"too many minus signs", strings.Repeat("-", 200) + "1", "nested too deeply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpression(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseExpression(%q) error = %v, want it to contain %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionVariables(t *testing.T) {
+	expr, err := ParseExpression("b * max(a, pi) - b^c + e")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+	if got, want := expr.Variables(), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"backend/models"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Summary   string        `xml:"summary,omitempty"`
+	Link      atomLink      `xml:"link"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      rssSelf   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description,omitempty"`
+	Category    string  `xml:"category,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RenderFeed encodes a content feed as an Atom 1.0 or RSS 2.0 document and returns it with its content type
+func RenderFeed(feed *models.ContentFeed, format models.FeedFormat) ([]byte, string, error) {
+	var doc interface{}
+	var contentType string
+	switch format {
+	case models.FeedAtom:
+		doc, contentType = atomDocument(feed), "application/atom+xml; charset=utf-8"
+	case models.FeedRSS:
+		doc, contentType = rssDocument(feed), "application/rss+xml; charset=utf-8"
+	default:
+		return nil, "", fmt.Errorf("unsupported feed format: %s", format)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), contentType, nil
+}
+
+func atomDocument(feed *models.ContentFeed) *atomFeed {
+	doc := &atomFeed{
+		ID:       feed.ID,
+		Title:    feed.Title,
+		Subtitle: feed.Subtitle,
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.SelfLink, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, entry := range feed.Entries {
+		item := atomEntry{
+			ID:        "urn:z0nata:content:" + entry.ID,
+			Title:     entry.Title,
+			Summary:   entry.Summary,
+			Link:      atomLink{Href: entry.Link, Rel: "alternate", Type: "text/html"},
+			Published: entry.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:   entry.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if entry.Category != "" {
+			item.Category = &atomCategory{Term: entry.Category}
+		}
+		doc.Entries = append(doc.Entries, item)
+	}
+	return doc
+}
+
+func rssDocument(feed *models.ContentFeed) *rssFeed {
+	doc := &rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Subtitle,
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			SelfLink:      rssSelf{Href: feed.SelfLink, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	for _, entry := range feed.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Summary,
+			Category:    entry.Category,
+			GUID:        rssGUID{Value: "urn:z0nata:content:" + entry.ID},
+			PubDate:     entry.PublishedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return doc
+}
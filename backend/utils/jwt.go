@@ -1,7 +1,13 @@
 package utils
 
 import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"backend/models"
@@ -10,11 +16,25 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// GuestUserType marks tokens issued to anonymous quiz takers, which have no user record
+const GuestUserType = "guest"
+
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Email    string `json:"email"`
-	UserType string `json:"user_type"` // "mahasiswa", "admin", "user"
+	UserType string `json:"user_type"` // "mahasiswa", "admin", "user", "guest"
 	IsAdmin  bool   `json:"is_admin"`
+	// ImpersonatedBy holds the admin ID when the token was issued through impersonation
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// InvitationClaims identify a registration invitation. They are signed with a key derived from the
+// JWT secret so an invite token can never pass as an access token.
+type InvitationClaims struct {
+	InvitationID string `json:"invitation_id"`
+	Email        string `json:"email"`
+	UserType     string `json:"user_type"`
 	jwt.RegisteredClaims
 }
 
@@ -23,6 +43,13 @@ type JWTManager struct {
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
 	rememberMeDuration   time.Duration
+
+	// Rotating ES256 keys. Until a signing key is loaded, tokens are signed with the
+	// HS256 secret; tokens without a kid header are always checked against the secret
+	// so ones issued before rotation was enabled stay valid until they expire.
+	mu         sync.RWMutex
+	signingKey *SigningKey
+	verifyKeys map[string]*ecdsa.PublicKey
 }
 
 func NewJWTManager(config models.JWTConfig) *JWTManager {
@@ -48,8 +75,44 @@ func (j *JWTManager) GenerateAccessToken(userID primitive.ObjectID, email, userT
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+	return j.sign(claims)
+}
+
+// GenerateImpersonationToken issues a short-lived access token for the target user
+// carrying the impersonating admin's ID. No refresh token is issued for these sessions.
+func (j *JWTManager) GenerateImpersonationToken(userID primitive.ObjectID, email, userType string, isAdmin bool, impersonatedBy primitive.ObjectID, duration time.Duration) (string, error) {
+	claims := Claims{
+		UserID:         userID.Hex(),
+		Email:          email,
+		UserType:       userType,
+		IsAdmin:        isAdmin,
+		ImpersonatedBy: impersonatedBy.Hex(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   userID.Hex(),
+		},
+	}
+
+	return j.sign(claims)
+}
+
+// GenerateGuestToken issues an access token for an ephemeral guest identity. Guest tokens are
+// only accepted on quiz routes and are never paired with a refresh token.
+func (j *JWTManager) GenerateGuestToken(guestID primitive.ObjectID, duration time.Duration) (string, error) {
+	claims := Claims{
+		UserID:   guestID.Hex(),
+		UserType: GuestUserType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   guestID.Hex(),
+		},
+	}
+
+	return j.sign(claims)
 }
 
 func (j *JWTManager) GenerateRefreshToken(userID primitive.ObjectID, email string, rememberMe bool) (string, error) {
@@ -69,29 +132,149 @@ func (j *JWTManager) GenerateRefreshToken(userID primitive.ObjectID, email strin
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+	return j.sign(claims)
 }
 
 func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, errors.New("invalid token")
+}
+
+// IsTokenExpired reports whether ValidateToken failed only because the token expired
+func IsTokenExpired(err error) bool {
+	return errors.Is(err, jwt.ErrTokenExpired)
+}
+
+// SetKeys replaces the key set: current signs new tokens, and tokens carrying any kid in
+// verify (which should include current) are accepted
+func (j *JWTManager) SetKeys(current *SigningKey, verify map[string]*ecdsa.PublicKey) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.signingKey = current
+	j.verifyKeys = verify
+}
+
+// CurrentKeyID returns the kid new tokens are signed with, or "" while using the legacy secret
+func (j *JWTManager) CurrentKeyID() string {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	if j.signingKey == nil {
+		return ""
+	}
+	return j.signingKey.KID
+}
+
+// JWKS returns the public keys tokens may currently be verified with
+func (j *JWTManager) JWKS() (*models.JWKSet, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	kids := make([]string, 0, len(j.verifyKeys))
+	for kid := range j.verifyKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := &models.JWKSet{Keys: make([]models.JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwk, err := PublicJWK(kid, j.verifyKeys[kid])
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+func (j *JWTManager) sign(claims jwt.Claims) (string, error) {
+	j.mu.RLock()
+	signingKey := j.signingKey
+	j.mu.RUnlock()
+
+	if signingKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.secretKey))
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = signingKey.KID
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+// verificationKey picks the key for a token by its kid header, falling back to the legacy secret
+func (j *JWTManager) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return []byte(j.secretKey), nil
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	j.mu.RLock()
+	publicKey, exists := j.verifyKeys[kid]
+	j.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown signing key %s", kid)
+	}
+	return publicKey, nil
+}
+
+// GenerateInvitationToken signs an invite link token that expires with the invitation
+func (j *JWTManager) GenerateInvitationToken(invitationID primitive.ObjectID, email, userType string, expiresAt time.Time) (string, error) {
+	claims := InvitationClaims{
+		InvitationID: invitationID.Hex(),
+		Email:        email,
+		UserType:     userType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Subject:   invitationID.Hex(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.invitationKey())
+}
+
+func (j *JWTManager) ValidateInvitationToken(tokenString string) (*InvitationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InvitationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return j.invitationKey(), nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*InvitationClaims); ok && token.Valid {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+func (j *JWTManager) invitationKey() []byte {
+	mac := hmac.New(sha256.New, []byte(j.secretKey))
+	mac.Write([]byte("invitation"))
+	return mac.Sum(nil)
+}
+
 func (j *JWTManager) GetTokenExpiry(rememberMe bool) time.Duration {
 	if rememberMe {
 		return j.rememberMeDuration
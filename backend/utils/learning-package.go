@@ -0,0 +1,356 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"backend/models"
+)
+
+type scormManifest struct {
+	XMLName       xml.Name           `xml:"manifest"`
+	Identifier    string             `xml:"identifier,attr"`
+	Version       string             `xml:"version,attr"`
+	Namespace     string             `xml:"xmlns,attr"`
+	ADLCP         string             `xml:"xmlns:adlcp,attr"`
+	Metadata      scormMetadata      `xml:"metadata"`
+	Organizations scormOrganizations `xml:"organizations"`
+	Resources     []scormResource    `xml:"resources>resource"`
+}
+
+type scormMetadata struct {
+	Schema        string `xml:"schema"`
+	SchemaVersion string `xml:"schemaversion"`
+}
+
+type scormOrganizations struct {
+	Default      string            `xml:"default,attr"`
+	Organization scormOrganization `xml:"organization"`
+}
+
+type scormOrganization struct {
+	Identifier string      `xml:"identifier,attr"`
+	Title      string      `xml:"title"`
+	Items      []scormItem `xml:"item"`
+}
+
+type scormItem struct {
+	Identifier    string      `xml:"identifier,attr"`
+	IdentifierRef string      `xml:"identifierref,attr,omitempty"`
+	Title         string      `xml:"title"`
+	Items         []scormItem `xml:"item"`
+}
+
+type scormResource struct {
+	Identifier   string            `xml:"identifier,attr"`
+	Type         string            `xml:"type,attr"`
+	SCORMType    string            `xml:"adlcp:scormtype,attr"`
+	Href         string            `xml:"href,attr,omitempty"`
+	Files        []scormFile       `xml:"file"`
+	Dependencies []scormDependency `xml:"dependency"`
+}
+
+type scormFile struct {
+	Href string `xml:"href,attr"`
+}
+
+type scormDependency struct {
+	IdentifierRef string `xml:"identifierref,attr"`
+}
+
+type tincanManifest struct {
+	XMLName    xml.Name         `xml:"http://projecttincan.com/tincan.xsd tincan"`
+	Activities []tincanActivity `xml:"activities>activity"`
+}
+
+type tincanActivity struct {
+	ID          string      `xml:"id,attr"`
+	Type        string      `xml:"type,attr"`
+	Name        string      `xml:"name"`
+	Description *tincanText `xml:"description,omitempty"`
+	Launch      *tincanText `xml:"launch,omitempty"`
+}
+
+type tincanText struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+// learningPackageStyles keep packaged pages readable inside the LMS frame
+const learningPackageStyles = `body { margin: 0; font-family: system-ui, sans-serif; line-height: 1.6; color: #1f2933; }
+main { max-width: 48rem; margin: 0 auto; padding: 1.5rem; }
+img, iframe { max-width: 100%; }
+figure.video iframe { width: 100%; aspect-ratio: 16 / 9; border: 0; }
+pre { overflow-x: auto; padding: 1rem; background: #f3f4f6; }
+.callout { margin: 1rem 0; padding: 0.75rem 1rem; border-left: 4px solid #3b82f6; background: #eff6ff; }
+.callout-tip { border-color: #10b981; background: #ecfdf5; }
+.callout-warning { border-color: #f59e0b; background: #fffbeb; }
+.callout-danger { border-color: #ef4444; background: #fef2f2; }
+nav.pager { display: flex; justify-content: space-between; gap: 1rem; margin-top: 2rem; }
+`
+
+// scormRuntime marks each page complete in the LMS when it is opened, through the SCORM 1.2 API the
+// LMS puts on a parent or opener window
+const scormRuntime = `(function () {
+  function findAPI(win) {
+    for (var i = 0; win && i < 10; i++) {
+      if (win.API) return win.API;
+      if (win.parent === win) break;
+      win = win.parent;
+    }
+    return null;
+  }
+  var api = findAPI(window) || (window.opener && findAPI(window.opener));
+  if (!api) return;
+  api.LMSInitialize("");
+  if (api.LMSGetValue("cmi.core.lesson_status") !== "completed") {
+    api.LMSSetValue("cmi.core.lesson_status", "completed");
+    api.LMSCommit("");
+  }
+  var finished = false;
+  function finish() {
+    if (!finished) {
+      finished = true;
+      api.LMSFinish("");
+    }
+  }
+  window.addEventListener("pagehide", finish);
+  window.addEventListener("beforeunload", finish);
+})();
+`
+
+// xapiRuntime sends an experienced statement for each page opened, and completed for the module on its
+// last page, to the LRS named in the launch parameters. The parameters are kept for the session so
+// they follow the learner from page to page.
+const xapiRuntime = `(function () {
+  var key = "z0nata-xapi-launch";
+  var query = new URLSearchParams(window.location.search);
+  var launch = null;
+  if (query.get("endpoint")) {
+    launch = {
+      endpoint: query.get("endpoint"),
+      auth: query.get("auth"),
+      actor: query.get("actor"),
+      registration: query.get("registration")
+    };
+    try { sessionStorage.setItem(key, JSON.stringify(launch)); } catch (e) {}
+  } else {
+    try { launch = JSON.parse(sessionStorage.getItem(key)); } catch (e) {}
+  }
+  if (!launch || !launch.endpoint || !launch.actor) return;
+
+  var body = document.body;
+  var endpoint = launch.endpoint.replace(/\/?$/, "/") + "statements";
+  function send(verb, activity, name) {
+    var statement = {
+      actor: JSON.parse(launch.actor),
+      verb: { id: "http://adlnet.gov/expapi/verbs/" + verb, display: { "en-US": verb } },
+      object: { objectType: "Activity", id: activity, definition: { name: { "und": name } } },
+      context: { contextActivities: { parent: [{ id: body.dataset.course }] } }
+    };
+    if (activity === body.dataset.course) delete statement.context.contextActivities;
+    if (launch.registration) statement.context.registration = launch.registration;
+    var headers = { "Content-Type": "application/json", "X-Experience-API-Version": "1.0.3" };
+    if (launch.auth) headers.Authorization = launch.auth;
+    fetch(endpoint, { method: "POST", headers: headers, body: JSON.stringify(statement), keepalive: true });
+  }
+  send("experienced", body.dataset.activity, document.title);
+  if (body.dataset.last === "true") send("completed", body.dataset.course, body.dataset.courseTitle);
+})();
+`
+
+// WriteLearningPackage writes a learning package to w as a zip an LMS imports: a SCORM 1.2 package
+// with an imsmanifest.xml, or an xAPI package with a tincan.xml. Either way each page is an HTML file
+// at the root that reports to the LMS when opened, and assets keep their paths.
+func WriteLearningPackage(w io.Writer, pkg *models.LearningPackage, format models.LearningPackageFormat) error {
+	var manifestName, runtime string
+	var manifest interface{}
+	switch format {
+	case models.PackageSCORM12:
+		manifestName, runtime, manifest = "imsmanifest.xml", scormRuntime, scormDocument(pkg)
+	case models.PackageXAPI:
+		manifestName, runtime, manifest = "tincan.xml", xapiRuntime, tincanDocument(pkg)
+	default:
+		return fmt.Errorf("unsupported package format: %s", format)
+	}
+
+	archive := zip.NewWriter(w)
+	write := func(name string, body []byte) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(body)
+		return err
+	}
+
+	body, err := xml.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode package manifest: %w", err)
+	}
+	if err := write(manifestName, append([]byte(xml.Header), body...)); err != nil {
+		return err
+	}
+	if err := write("styles.css", []byte(learningPackageStyles)); err != nil {
+		return err
+	}
+	if err := write("runtime.js", []byte(runtime)); err != nil {
+		return err
+	}
+	for i, page := range pkg.Pages {
+		if err := write(page.FileName, []byte(learningPageHTML(pkg, i, format == models.PackageXAPI))); err != nil {
+			return err
+		}
+	}
+	for _, asset := range pkg.Assets {
+		if err := writeLearningAsset(archive, asset); err != nil {
+			return fmt.Errorf("failed to package %s: %w", asset.Path, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+func writeLearningAsset(archive *zip.Writer, asset models.LearningAsset) error {
+	source, err := asset.Open()
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	file, err := archive.Create(asset.Path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, source)
+	return err
+}
+
+// learningPageHTML is the standalone document of one page. xAPI packages link pages to each other, as
+// the LMS only launches the first; SCORM LMSs navigate between pages themselves.
+func learningPageHTML(pkg *models.LearningPackage, index int, pager bool) string {
+	page := pkg.Pages[index]
+	last := index == len(pkg.Pages)-1
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(page.Title))
+	b.WriteString("<link rel=\"stylesheet\" href=\"styles.css\">\n</head>\n")
+	fmt.Fprintf(&b, "<body data-activity=\"%s\" data-course=\"%s\" data-course-title=\"%s\" data-last=\"%t\">\n<main>\n",
+		html.EscapeString(page.ID), html.EscapeString(pkg.ID), html.EscapeString(pkg.Title), last)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(page.Title))
+	b.WriteString(page.HTML)
+	if pager && len(pkg.Pages) > 1 {
+		b.WriteString("<nav class=\"pager\">\n")
+		if index > 0 {
+			previous := pkg.Pages[index-1]
+			fmt.Fprintf(&b, "<a href=\"%s\" rel=\"prev\">&larr; %s</a>\n", html.EscapeString(previous.FileName), html.EscapeString(previous.Title))
+		} else {
+			b.WriteString("<span></span>\n")
+		}
+		if !last {
+			next := pkg.Pages[index+1]
+			fmt.Fprintf(&b, "<a href=\"%s\" rel=\"next\">%s &rarr;</a>\n", html.EscapeString(next.FileName), html.EscapeString(next.Title))
+		}
+		b.WriteString("</nav>\n")
+	}
+	b.WriteString("</main>\n<script src=\"runtime.js\"></script>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// scormDocument makes each page a SCO. A submodule with submodules under it becomes a group holding its
+// own page and theirs, as SCORM items that hold others are not launched.
+func scormDocument(pkg *models.LearningPackage) *scormManifest {
+	shared := scormResource{
+		Identifier: "SHARED",
+		Type:       "webcontent",
+		SCORMType:  "asset",
+		Files:      []scormFile{{Href: "styles.css"}, {Href: "runtime.js"}},
+	}
+	for _, asset := range pkg.Assets {
+		shared.Files = append(shared.Files, scormFile{Href: asset.Path})
+	}
+
+	doc := &scormManifest{
+		Identifier: pkg.Identifier,
+		Version:    "1",
+		Namespace:  "http://www.imsproject.org/xsd/imscp_rootv1p1p2",
+		ADLCP:      "http://www.adlnet.org/xsd/adlcp_rootv1p2",
+		Metadata:   scormMetadata{Schema: "ADL SCORM", SchemaVersion: "1.2"},
+		Organizations: scormOrganizations{
+			Default:      "ORG",
+			Organization: scormOrganization{Identifier: "ORG", Title: pkg.Title},
+		},
+	}
+	doc.Organizations.Organization.Items, _ = scormItems(pkg.Pages, 0, 0)
+	for i, page := range pkg.Pages {
+		doc.Resources = append(doc.Resources, scormResource{
+			Identifier:   fmt.Sprintf("RES-%d", i+1),
+			Type:         "webcontent",
+			SCORMType:    "sco",
+			Href:         page.FileName,
+			Files:        []scormFile{{Href: page.FileName}},
+			Dependencies: []scormDependency{{IdentifierRef: shared.Identifier}},
+		})
+	}
+	doc.Resources = append(doc.Resources, shared)
+	return doc
+}
+
+// scormItems nests the pages from start that sit at level or deeper, returning where they end. The
+// module's page and its top-level submodules share the top level.
+func scormItems(pages []models.LearningPage, start, level int) ([]scormItem, int) {
+	pageLevel := func(page models.LearningPage) int {
+		return max(page.Depth-1, 0)
+	}
+
+	var items []scormItem
+	i := start
+	for i < len(pages) && pageLevel(pages[i]) >= level {
+		item := scormItem{
+			Identifier:    fmt.Sprintf("ITEM-%d", i+1),
+			IdentifierRef: fmt.Sprintf("RES-%d", i+1),
+			Title:         pages[i].Title,
+		}
+		group := scormItem{Identifier: fmt.Sprintf("GROUP-%d", i+1), Title: pages[i].Title}
+		i++
+		if i < len(pages) && pageLevel(pages[i]) > level {
+			var children []scormItem
+			children, i = scormItems(pages, i, level+1)
+			group.Items = append([]scormItem{item}, children...)
+			items = append(items, group)
+		} else {
+			items = append(items, item)
+		}
+	}
+	return items, i
+}
+
+// tincanDocument declares the module as a course launched at its first page, and each page as a
+// module activity its statements name
+func tincanDocument(pkg *models.LearningPackage) *tincanManifest {
+	doc := &tincanManifest{}
+	for i, page := range pkg.Pages {
+		activity := tincanActivity{
+			ID:   page.ID,
+			Type: "http://adlnet.gov/expapi/activities/module",
+			Name: page.Title,
+		}
+		if i == 0 {
+			activity.ID = pkg.ID
+			activity.Type = "http://adlnet.gov/expapi/activities/course"
+			activity.Name = pkg.Title
+			activity.Launch = &tincanText{Lang: "und", Value: page.FileName}
+			if pkg.Description != "" {
+				activity.Description = &tincanText{Lang: "und", Value: pkg.Description}
+			}
+		}
+		doc.Activities = append(doc.Activities, activity)
+	}
+	return doc
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/models"
+)
+
+// Headers a lockdown browser sends with every request
+const (
+	LockdownTimestampHeader = "X-Lockdown-Timestamp"
+	LockdownSignatureHeader = "X-Lockdown-Signature"
+)
+
+// lockdownClockSkew bounds how old (or early) a handshake timestamp may be
+const lockdownClockSkew = 5 * time.Minute
+
+var ErrLockdownRequired = models.NewCodedError(http.StatusForbidden, models.ErrorCodeLockdownRequired, "lockdown browser required")
+
+// LockdownHandshakeFromRequest reads the handshake headers, returning nil when there are none
+func LockdownHandshakeFromRequest(r *http.Request) *models.LockdownHandshake {
+	timestamp := r.Header.Get(LockdownTimestampHeader)
+	signature := r.Header.Get(LockdownSignatureHeader)
+	if timestamp == "" && signature == "" {
+		return nil
+	}
+	return &models.LockdownHandshake{
+		Timestamp: timestamp,
+		Signature: signature,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+	}
+}
+
+// SignLockdownRequest computes the hex HMAC-SHA256, keyed with the base64 sitting key, of
+// "<unix timestamp>\n<METHOD>\n<path>" - the signature a lockdown browser must send
+func SignLockdownRequest(encodedKey, timestamp, method, path string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return "", errors.New("invalid lockdown key")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + path))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyLockdownHandshake checks that the handshake is recent and signed with the sitting key.
+// Binding the method and path keeps a captured signature from being reused on other endpoints.
+func VerifyLockdownHandshake(encodedKey string, handshake *models.LockdownHandshake, now time.Time) error {
+	if handshake == nil || handshake.Timestamp == "" || handshake.Signature == "" {
+		return ErrLockdownRequired
+	}
+
+	seconds, err := strconv.ParseInt(handshake.Timestamp, 10, 64)
+	if err != nil {
+		return ErrLockdownRequired
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > lockdownClockSkew || skew < -lockdownClockSkew {
+		return ErrLockdownRequired
+	}
+
+	expected, err := SignLockdownRequest(encodedKey, handshake.Timestamp, handshake.Method, handshake.Path)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(handshake.Signature)) {
+		return ErrLockdownRequired
+	}
+	return nil
+}
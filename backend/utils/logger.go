@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"backend/models"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The server logs through log/slog. NewLogger builds the handler main installs as the default, so
+// packages log with slog's functions and need nothing passed in. Records logged with a request's
+// context carry its request ID and trace, and anything that looks like a secret or an email address is
+// redacted on the way out, whatever key it is logged under.
+
+type requestIDKey struct{}
+
+// redactedValue replaces the values of secret attributes
+const redactedValue = "[REDACTED]"
+
+// secretKeyParts mark attribute keys whose values are never logged, as do secretKeys as a whole; an
+// OAuth code or state can be exchanged for a login
+var (
+	secretKeyParts = []string{"token", "password", "secret", "authorization", "cookie", "api_key", "apikey", "signature"}
+	secretKeys     = map[string]bool{"code": true, "state": true, "oauth_code": true, "oauth_state": true}
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+)
+
+// NewLogger builds a logger writing to w at the configured level, as JSON or as text
+func NewLogger(config models.LogConfig, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", config.Level)
+	}
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	var handler slog.Handler
+	switch config.Format {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", config.Format)
+	}
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID is the ID of the request a context belongs to, or "" outside of one
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewRequestID makes a random ID for a request the client did not name
+func NewRequestID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// MaskEmail keeps the first letter and the domain of an email address, enough to tell accounts apart
+// in logs without identifying anyone
+func MaskEmail(email string) string {
+	return emailPattern.ReplaceAllString(email, "$1***@$2")
+}
+
+// RedactSecrets masks email addresses, bearer tokens and JWTs inside free text, such as error messages
+func RedactSecrets(text string) string {
+	text = bearerPattern.ReplaceAllString(text, "${1}"+redactedValue)
+	text = jwtPattern.ReplaceAllString(text, redactedValue)
+	return MaskEmail(text)
+}
+
+// contextHandler adds the request ID and trace of the record's context
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()), slog.String("span_id", spanContext.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// redactAttr hides the values of secret keys and masks secrets in every other string or error logged
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && (attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.SourceKey) {
+		return attr
+	}
+	if secretKey(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactSecrets(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(attr.Key, RedactSecrets(err.Error()))
+		}
+	}
+	return attr
+}
+
+func secretKey(key string) bool {
+	key = strings.ToLower(key)
+	if secretKeys[key] {
+		return true
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
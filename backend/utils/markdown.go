@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Question content is written in a small markdown subset with embedded LaTeX:
+//
+//   - paragraphs, # headings, - and 1. lists, ``` fenced code blocks
+//   - **bold**, *italic*, ~~strikethrough~~, `code`, [links](https://...) and ![images](https://...)
+//   - $inline$ and $$display$$ math, also as a block of lines between $$ fences
+//
+// Rendering escapes everything else, so raw HTML in the source shows as text and the output needs
+// no further sanitizing. Math is left as TeX in math-inline and math-display elements for the
+// client to typeset, the markup KaTeX and MathJax auto-render pick up.
+
+// unsafeLaTeX matches commands that load URLs, emit HTML or define macros when typeset
+var unsafeLaTeX = regexp.MustCompile(`\\(href|url|includegraphics|html[A-Za-z@]*|def|edef|gdef|xdef|let|newcommand|renewcommand|providecommand|global|input|include)\b`)
+
+var (
+	markdownHeadingLine = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	markdownBulletLine  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	markdownOrderedLine = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+)
+
+// CheckMarkdown rejects content whose LaTeX uses a command that is not safe to typeset
+func CheckMarkdown(src string) error {
+	if match := unsafeLaTeX.FindStringSubmatch(src); match != nil {
+		return fmt.Errorf("LaTeX command \\%s is not allowed", match[1])
+	}
+	return nil
+}
+
+// RenderMarkdown renders question content to HTML, block by block
+func RenderMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(src), "\r\n", "\n"), "\n")
+
+	var b strings.Builder
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>")
+			renderInline(&b, strings.Join(paragraph, "\n"))
+			b.WriteString("</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+
+		case line == "$$":
+			flush()
+			var tex []string
+			for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "$$"; i++ {
+				tex = append(tex, lines[i])
+			}
+			writeMath(&b, strings.Join(tex, "\n"), "div", "math math-display", "$$")
+			b.WriteString("\n")
+
+		case markdownHeadingLine.MatchString(line):
+			flush()
+			match := markdownHeadingLine.FindStringSubmatch(line)
+			fmt.Fprintf(&b, "<h%d>", len(match[1]))
+			renderInline(&b, match[2])
+			fmt.Fprintf(&b, "</h%d>\n", len(match[1]))
+
+		case markdownBulletLine.MatchString(line), markdownOrderedLine.MatchString(line):
+			flush()
+			pattern, tag := markdownBulletLine, "ul"
+			if !markdownBulletLine.MatchString(line) {
+				pattern, tag = markdownOrderedLine, "ol"
+			}
+			fmt.Fprintf(&b, "<%s>\n", tag)
+			for ; i < len(lines); i++ {
+				match := pattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+				if match == nil {
+					break
+				}
+				b.WriteString("<li>")
+				renderInline(&b, match[1])
+				b.WriteString("</li>\n")
+			}
+			i--
+			fmt.Fprintf(&b, "</%s>\n", tag)
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// RenderMarkdownInline renders one line of content, such as an option, without block elements
+func RenderMarkdownInline(src string) string {
+	var b strings.Builder
+	renderInline(&b, strings.TrimSpace(src))
+	return b.String()
+}
+
+func renderInline(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			n := runLength(s, i, '`')
+			fence := s[i : i+n]
+			if end := strings.Index(s[i+n:], fence); end >= 0 {
+				b.WriteString("<code>")
+				b.WriteString(html.EscapeString(strings.TrimSpace(s[i+n : i+n+end])))
+				b.WriteString("</code>")
+				i += n + end + n
+				continue
+			}
+
+		case c == '$':
+			if strings.HasPrefix(s[i:], "$$") {
+				if end := strings.Index(s[i+2:], "$$"); end > 0 {
+					writeMath(b, s[i+2:i+2+end], "span", "math math-display", "$$")
+					i += 2 + end + 2
+					continue
+				}
+			} else if end := closingDollar(s, i); end > 0 {
+				writeMath(b, s[i+1:end], "span", "math math-inline", "$")
+				i = end + 1
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, url, next, ok := parseLink(s, i+1); ok {
+				if isSafeURL(url, false) {
+					fmt.Fprintf(b, `<img src="%s" alt="%s">`, html.EscapeString(url), html.EscapeString(text))
+				} else {
+					b.WriteString(html.EscapeString(text))
+				}
+				i = next
+				continue
+			}
+
+		case c == '[':
+			if text, url, next, ok := parseLink(s, i); ok {
+				if isSafeURL(url, true) {
+					fmt.Fprintf(b, `<a href="%s" rel="nofollow noopener noreferrer">`, html.EscapeString(url))
+					renderInline(b, text)
+					b.WriteString("</a>")
+				} else {
+					renderInline(b, text)
+				}
+				i = next
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if next, ok := renderEmphasis(b, s, i); ok {
+				i = next
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+}
+
+// renderEmphasis renders the span opened by the delimiter run at i, if the run is closed later
+func renderEmphasis(b *strings.Builder, s string, i int) (int, bool) {
+	c := s[i]
+	n := min(runLength(s, i, c), 2)
+	if c == '~' && n != 2 {
+		return 0, false
+	}
+	delimiter := s[i : i+n]
+
+	// An opening run is followed by text; underscores also may not start inside a word
+	if i+n >= len(s) || s[i+n] == ' ' || (c == '_' && i > 0 && isWordByte(s[i-1])) {
+		return 0, false
+	}
+
+	for from := i + n + 1; from < len(s); {
+		end := strings.Index(s[from:], delimiter)
+		if end < 0 {
+			return 0, false
+		}
+		end += from
+		closes := s[end-1] != ' ' && s[end-1] != '\\' &&
+			!(c == '_' && end+n < len(s) && isWordByte(s[end+n])) &&
+			!(n == 1 && end+1 < len(s) && s[end+1] == c)
+		if closes {
+			tag := map[string]string{"*": "em", "_": "em", "**": "strong", "__": "strong", "~~": "del"}[delimiter]
+			fmt.Fprintf(b, "<%s>", tag)
+			renderInline(b, s[i+n:end])
+			fmt.Fprintf(b, "</%s>", tag)
+			return end + n, true
+		}
+		from = end + n
+	}
+	return 0, false
+}
+
+// closingDollar finds the $ closing inline math opened at i. As in pandoc, the TeX may not start or
+// end with a space and the closing $ may not be followed by a digit, so prices like $5 stay text.
+func closingDollar(s string, i int) int {
+	if i+1 >= len(s) || s[i+1] == ' ' || s[i+1] == '$' {
+		return -1
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] == '$' && s[j-1] != ' ' && s[j-1] != '\\' {
+			if j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9' {
+				return -1
+			}
+			return j
+		}
+	}
+	return -1
+}
+
+// writeMath writes TeX for the client to typeset. TeX using an unsafe command is shown as the
+// source text instead, for content stored before it was checked.
+func writeMath(b *strings.Builder, tex, element, class, delimiter string) {
+	if unsafeLaTeX.MatchString(tex) {
+		b.WriteString(html.EscapeString(delimiter + tex + delimiter))
+		return
+	}
+	fmt.Fprintf(b, `<%s class="%s">%s</%s>`, element, class, html.EscapeString(strings.TrimSpace(tex)), element)
+}
+
+// parseLink reads [text](url) starting at the bracket at i
+func parseLink(s string, i int) (text, url string, next int, ok bool) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if j+1 >= len(s) || s[j+1] != '(' {
+				return "", "", 0, false
+			}
+			end := closingParen(s[j+2:])
+			if end < 0 {
+				return "", "", 0, false
+			}
+			url = strings.TrimSpace(s[j+2 : j+2+end])
+			if url == "" || strings.ContainsAny(url, " \n") {
+				return "", "", 0, false
+			}
+			return s[i+1 : j], url, j + 2 + end + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// closingParen finds the parenthesis closing a link destination, which may hold balanced pairs
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// isSafeURL allows web links and site-relative paths, and mail links where a link is wanted
+func isSafeURL(url string, link bool) bool {
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") ||
+		(strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "//")) ||
+		(link && strings.HasPrefix(lower, "mailto:"))
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("\\`*_~[]()#+-.!$>{}|", c) >= 0
+}
+
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -153,3 +153,19 @@ func GenerateRecoveryCodes(count int) ([]string, error) {
 	}
 	return codes, nil
 }
+
+// GenerateTemporaryPassword generates a random password for accounts created on a user's behalf
+func GenerateTemporaryPassword(length int) (string, error) {
+	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	for i := range bytes {
+		bytes[i] = charset[int(bytes[i])%len(charset)]
+	}
+
+	return string(bytes), nil
+}
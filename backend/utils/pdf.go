@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait in PDF points, with the margins printed papers use
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 56.0
+	pdfLineFactor = 1.35 // Line height as a multiple of the font size
+)
+
+// helveticaWidths are the widths of printable ASCII characters (space to tilde) in the standard
+// Helvetica font, in thousandths of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// PDFTextStyle is how a paragraph of a PDFDocument is set
+type PDFTextStyle struct {
+	Size   float64 // Font size in points
+	Bold   bool
+	Indent float64 // Left indent in points, added to the page margin
+	Center bool    // Centre each line between the margins instead
+}
+
+// PDFDocument writes plain text documents, such as printed exam papers, as PDF without external
+// dependencies. Text uses the standard Helvetica fonts with WinAnsi encoding; characters outside
+// Latin-1 are printed as "?".
+type PDFDocument struct {
+	pages [][]byte
+	page  *bytes.Buffer
+	y     float64 // Baseline of the next line, from the bottom of the page
+}
+
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{}
+}
+
+// AddPage finishes the current page and starts a new one
+func (d *PDFDocument) AddPage() {
+	d.finishPage()
+	d.page = &bytes.Buffer{}
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// Paragraph writes text wrapped to the page width, starting new pages as needed.
+// Line breaks in the text start new lines.
+func (d *PDFDocument) Paragraph(text string, style PDFTextStyle) {
+	if d.page == nil {
+		d.AddPage()
+	}
+
+	font := "F1"
+	if style.Bold {
+		font = "F2"
+	}
+	lineHeight := style.Size * pdfLineFactor
+	maxWidth := pdfPageWidth - 2*pdfMargin - style.Indent
+
+	for _, line := range wrapPDFText(text, style.Size, maxWidth) {
+		if d.y-lineHeight < pdfMargin {
+			d.AddPage()
+		}
+		d.y -= lineHeight
+		x := pdfMargin + style.Indent
+		if style.Center {
+			x = (pdfPageWidth - pdfTextWidth(line, style.Size)) / 2
+		}
+		fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, style.Size, x, d.y, escapePDFText(line))
+	}
+}
+
+// QRCode draws a QR code centred on the page, size points wide including its quiet zone,
+// starting a new page if it does not fit
+func (d *PDFDocument) QRCode(code *QRCode, size float64) {
+	d.EnsureSpace(size)
+
+	module := size / float64(code.Size+8)
+	left := (pdfPageWidth-size)/2 + 4*module
+	top := d.y - 4*module
+	d.page.WriteString("0 g\n")
+	for y, row := range code.Modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f re\n", left+float64(x)*module, top-float64(y+1)*module, module, module)
+			}
+		}
+	}
+	d.page.WriteString("f\n")
+	d.y -= size
+}
+
+// Space leaves vertical space, starting a new page when it runs past the bottom margin
+func (d *PDFDocument) Space(points float64) {
+	if d.page == nil {
+		d.AddPage()
+	}
+	d.y -= points
+	if d.y < pdfMargin {
+		d.AddPage()
+	}
+}
+
+// EnsureSpace starts a new page unless at least points of space remain on the current one,
+// so short blocks are not split across pages
+func (d *PDFDocument) EnsureSpace(points float64) {
+	if d.page == nil || d.y-points < pdfMargin {
+		d.AddPage()
+	}
+}
+
+// Bytes returns the finished document
+func (d *PDFDocument) Bytes() []byte {
+	d.finishPage()
+	if len(d.pages) == 0 {
+		d.pages = append(d.pages, []byte{})
+	}
+
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are the catalog, page tree and fonts; each page is followed by its content stream
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+func (d *PDFDocument) finishPage() {
+	if d.page != nil {
+		d.pages = append(d.pages, d.page.Bytes())
+		d.page = nil
+	}
+}
+
+// wrapPDFText breaks text into lines no wider than maxWidth, splitting words longer than a line
+func wrapPDFText(text string, size, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if pdfTextWidth(candidate, size) <= maxWidth {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for pdfTextWidth(word, size) > maxWidth {
+				cut := len([]rune(word)) - 1
+				for cut > 1 && pdfTextWidth(string([]rune(word)[:cut]), size) > maxWidth {
+					cut--
+				}
+				lines = append(lines, string([]rune(word)[:cut]))
+				word = string([]rune(word)[cut:])
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func pdfTextWidth(text string, size float64) float64 {
+	width := 0
+	for _, r := range text {
+		if r >= ' ' && r <= '~' {
+			width += helveticaWidths[r-' ']
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// escapePDFText encodes text as a PDF string literal body in WinAnsi
+func escapePDFText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
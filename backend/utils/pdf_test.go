@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkPDFStructure checks what readers rely on to find objects: every xref entry points at its
+// object's header, startxref points at the xref table, and each stream is as long as it says
+func checkPDFStructure(t *testing.T, pdf []byte) (objects int) {
+	t.Helper()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) {
+		t.Fatalf("document starts with %q", pdf[:min(len(pdf), 9)])
+	}
+	if !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("document does not end with the end-of-file marker")
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d points at %q", xref, pdf[xref:min(len(pdf), xref+10)])
+	}
+
+	header := regexp.MustCompile(`^xref\n0 (\d+)\n0000000000 65535 f \n`).FindSubmatch(pdf[xref:])
+	if header == nil {
+		t.Fatal("xref table has no header or free entry")
+	}
+	size, _ := strconv.Atoi(string(header[1]))
+	entries := pdf[xref+len(header[0]):]
+	for n := 1; n < size; n++ {
+		// Entries are exactly 20 bytes, including the two-character line ending
+		entry := string(entries[(n-1)*20 : n*20])
+		if !strings.HasSuffix(entry, " 00000 n \n") {
+			t.Fatalf("xref entry %d = %q", n, entry)
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		if err != nil {
+			t.Fatalf("xref entry %d = %q", n, entry)
+		}
+		want := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", n, pdf[offset:min(len(pdf), offset+len(want))], want)
+		}
+	}
+	if trailer := fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\n", size); !bytes.HasPrefix(entries[(size-1)*20:], []byte(trailer)) {
+		t.Errorf("trailer does not follow the xref table with /Size %d", size)
+	}
+
+	streams := regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`)
+	for _, match := range streams.FindAllSubmatchIndex(pdf, -1) {
+		length, _ := strconv.Atoi(string(pdf[match[2]:match[3]]))
+		if end := match[1] + length; !bytes.HasPrefix(pdf[end:], []byte("endstream\n")) {
+			t.Errorf("stream at %d is not %d bytes long", match[1], length)
+		}
+	}
+	return size - 1
+}
+
+func TestPDFDocumentStructure(t *testing.T) {
+	code, err := EncodeQRCode("https://z0nata.example/verify/abc")
+	if err != nil {
+		t.Fatalf("EncodeQRCode() error = %v", err)
+	}
+
+	doc := NewPDFDocument()
+	doc.Paragraph("Ujian Akhir (Final) \\ Semester", PDFTextStyle{Size: 16, Bold: true, Center: true})
+	doc.Space(12)
+	doc.Paragraph(strings.Repeat("Jawablah semua pertanyaan berikut dengan jelas. ", 120), PDFTextStyle{Size: 11})
+	doc.QRCode(code, 120)
+	pdf := doc.Bytes()
+
+	objects := checkPDFStructure(t, pdf)
+	pages := bytes.Count(pdf, []byte("/Type /Page "))
+	if pages < 2 {
+		t.Fatalf("got %d pages, want the long paragraph to start a second page", pages)
+	}
+	if want := 4 + 2*pages; objects != want {
+		t.Errorf("got %d objects, want %d for %d pages", objects, want, pages)
+	}
+	if !bytes.Contains(pdf, []byte(fmt.Sprintf("/Count %d >>", pages))) {
+		t.Errorf("page tree does not count %d pages", pages)
+	}
+	if !bytes.Contains(pdf, []byte(`(Ujian Akhir \(Final\) \\ Semester) Tj`)) {
+		t.Error("title was not escaped")
+	}
+}
+
+func TestPDFDocumentEmpty(t *testing.T) {
+	pdf := NewPDFDocument().Bytes()
+	if objects := checkPDFStructure(t, pdf); objects != 6 {
+		t.Errorf("got %d objects, want one blank page (6 objects)", objects)
+	}
+}
+
+func TestEscapePDFText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{`a(b)c\d`, `a\(b\)c\\d`},
+		{"café", `caf\351`},
+		{"π ≈ 3", "? ? 3"},
+		{"tab\there", "tab?here"},
+	}
+
+	for _, tt := range tests {
+		if got := escapePDFText(tt.text); got != tt.want {
+			t.Errorf("escapePDFText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWrapPDFText(t *testing.T) {
+	lines := wrapPDFText("one two three\n\nfour "+strings.Repeat("w", 40), 10, 100)
+	for _, line := range lines {
+		if width := pdfTextWidth(line, 10); width > 100 {
+			t.Errorf("line %q is %.1f points wide, want at most 100", line, width)
+		}
+	}
+	if lines[0] != "one two three" || lines[1] != "" || lines[2] != "four" {
+		t.Errorf("wrapPDFText() = %q", lines)
+	}
+	if joined := strings.Join(lines[3:], ""); joined != strings.Repeat("w", 40) {
+		t.Errorf("the long word was not split across lines: %q", lines[3:])
+	}
+}
@@ -0,0 +1,421 @@
+package utils
+
+import (
+	"errors"
+)
+
+// qrVersion is the layout of one QR code version at error correction level M
+type qrVersion struct {
+	codewords  int   // Total codewords, data and error correction
+	blocks     int   // Blocks the codewords are split into
+	ecPerBlock int   // Error correction codewords in each block
+	alignment  []int // Centre coordinates of the alignment patterns
+}
+
+// Versions 1 to 10 at level M hold up to 213 bytes, plenty for a verification link
+var qrVersions = []qrVersion{
+	{26, 1, 10, nil},
+	{44, 1, 16, []int{6, 18}},
+	{70, 1, 26, []int{6, 22}},
+	{100, 2, 18, []int{6, 26}},
+	{134, 2, 24, []int{6, 30}},
+	{172, 4, 16, []int{6, 34}},
+	{196, 4, 18, []int{6, 22, 38}},
+	{242, 4, 22, []int{6, 24, 42}},
+	{292, 5, 22, []int{6, 26, 46}},
+	{346, 5, 26, []int{6, 28, 50}},
+}
+
+// QRCode is a QR code symbol, true for dark modules. It has no quiet zone; leave four modules of
+// space around it when drawing.
+type QRCode struct {
+	Size    int
+	Modules [][]bool
+}
+
+// EncodeQRCode encodes text as a byte-mode QR code with medium (about 15%) error correction,
+// using the smallest version that fits and the mask with the lowest penalty
+func EncodeQRCode(text string) (*QRCode, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= len(qrVersions); v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*qrDataCodewords(v) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("text is too long for a QR code")
+	}
+
+	q := newQRMatrix(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(qrInterleave(version, qrDataBits(version, data)))
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask) // Masks are their own inverse
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+
+	return &QRCode{Size: q.size, Modules: q.modules}, nil
+}
+
+func qrDataCodewords(version int) int {
+	v := qrVersions[version-1]
+	return v.codewords - v.blocks*v.ecPerBlock
+}
+
+// qrDataBits lays out the byte-mode segment, terminator and padding as data codewords
+func qrDataBits(version int, data []byte) []byte {
+	capacity := qrDataCodewords(version)
+	bits := make([]bool, 0, capacity*8)
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, value>>i&1 == 1)
+		}
+	}
+
+	appendBits(0x4, 4) // Byte mode
+	if version >= 10 {
+		appendBits(len(data), 16)
+	} else {
+		appendBits(len(data), 8)
+	}
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	appendBits(0, min(4, capacity*8-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+
+	codewords := make([]byte, capacity)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	for i, pad := len(bits)/8, byte(0xEC); i < capacity; i, pad = i+1, pad^0xEC^0x11 {
+		codewords[i] = pad
+	}
+	return codewords
+}
+
+// qrInterleave splits the data into blocks, adds each block's error correction and interleaves them.
+// When the data does not split evenly, the later blocks hold one more codeword.
+func qrInterleave(version int, data []byte) []byte {
+	v := qrVersions[version-1]
+	shortBlocks := v.blocks - v.codewords%v.blocks
+	shortLen := v.codewords/v.blocks - v.ecPerBlock
+	divisor := reedSolomonDivisor(v.ecPerBlock)
+
+	dataBlocks := make([][]byte, v.blocks)
+	ecBlocks := make([][]byte, v.blocks)
+	offset := 0
+	for i := range dataBlocks {
+		length := shortLen
+		if i >= shortBlocks {
+			length++
+		}
+		dataBlocks[i] = data[offset : offset+length]
+		ecBlocks[i] = reedSolomonRemainder(dataBlocks[i], divisor)
+		offset += length
+	}
+
+	result := make([]byte, 0, v.codewords)
+	for i := 0; i <= shortLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree, highest term dropped
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+type qrMatrix struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool // Finder, timing, alignment and format modules, which masks leave alone
+}
+
+func newQRMatrix(version int) *qrMatrix {
+	size := 17 + 4*version
+	q := &qrMatrix{version: version, size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+	return q
+}
+
+func (q *qrMatrix) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *qrMatrix) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	for _, corner := range [][2]int{{3, 3}, {q.size - 4, 3}, {3, q.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || x >= q.size || y < 0 || y >= q.size {
+					continue
+				}
+				dist := max(abs(dx), abs(dy))
+				q.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+
+	positions := qrVersions[q.version-1].alignment
+	last := len(positions) - 1
+	for i, cy := range positions {
+		for j, cx := range positions {
+			// Skip the three corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas until the mask is chosen
+	q.drawFormatBits(0)
+
+	if q.version >= 7 {
+		rem := q.version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := q.version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>i&1 == 1
+			a, b := q.size-11+i%3, i/3
+			q.setFunction(a, b, dark)
+			q.setFunction(b, a, dark)
+		}
+	}
+}
+
+// drawFormatBits writes both copies of the level M format information for a mask
+func (q *qrMatrix) drawFormatBits(mask int) {
+	data := mask // Level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // Always dark
+}
+
+// drawCodewords fills the data area in the zigzag order, two columns at a time from the right
+func (q *qrMatrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(codewords)*8 {
+					q.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *qrMatrix) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.function[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to scan: long runs, 2x2 blocks, finder-like patterns
+// and an uneven share of dark modules
+func (q *qrMatrix) penalty() int {
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+
+	penalty := 0
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < q.size; y++ {
+			run := 1
+			for x := 1; x <= q.size; x++ {
+				if x < q.size && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+
+			for x := 0; x+11 <= q.size; x++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if at(x+k, y, transpose) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+	total := q.size * q.size
+	penalty += abs(dark*20-total*10) / total * 10
+
+	return penalty
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,326 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// The decoder below reads symbols back using the layout tables of the QR code specification
+// (ISO/IEC 18004) rather than the encoder's own, so a mistake in one is not hidden by the other.
+
+// qrSpecFormatM is the 15-bit format information for level M with masks 0 to 7
+var qrSpecFormatM = [8]int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}
+
+// qrSpecBlocks gives the level M blocks of the versions the tests use: the data codewords of each
+// block, and the error correction codewords per block
+var qrSpecBlocks = map[int]struct {
+	data []int
+	ec   int
+}{
+	1:  {[]int{16}, 10},
+	4:  {[]int{32, 32}, 18},
+	8:  {[]int{38, 38, 39, 39}, 22},
+	10: {[]int{43, 43, 43, 43, 44}, 26},
+}
+
+var qrSpecAlignment = map[int][]int{
+	1:  nil,
+	4:  {6, 26},
+	8:  {6, 24, 42},
+	10: {6, 28, 50},
+}
+
+func TestEncodeQRCodeDecodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		version int
+	}{
+		{"short", "HELLO", 1},
+		{"verification link", "https://z0nata.example/verify/7f3a9c1e5b2d4068a1b3c5d7", 4},
+		{"uneven blocks", strings.Repeat("0123456789", 15), 8},
+		{"16-bit length", strings.Repeat("é", 100), 10},
+		{"largest", strings.Repeat("x", 213), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := EncodeQRCode(tt.text)
+			if err != nil {
+				t.Fatalf("EncodeQRCode() error = %v", err)
+			}
+			if want := 17 + 4*tt.version; code.Size != want || len(code.Modules) != want {
+				t.Fatalf("Size = %d, want version %d (%d modules)", code.Size, tt.version, want)
+			}
+			got := decodeQRCode(t, code)
+			if got != tt.text {
+				t.Errorf("decoded %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestEncodeQRCodeTooLong(t *testing.T) {
+	if _, err := EncodeQRCode(strings.Repeat("x", 214)); err == nil {
+		t.Error("EncodeQRCode() of 214 bytes succeeded, want an error")
+	}
+}
+
+func decodeQRCode(t *testing.T, code *QRCode) string {
+	t.Helper()
+	size := code.Size
+	version := (size - 17) / 4
+	at := func(x, y int) bool { return code.Modules[y][x] }
+
+	for y, row := range code.Modules {
+		if len(row) != size {
+			t.Fatalf("row %d has %d modules, want %d", y, len(row), size)
+		}
+	}
+
+	// Finder patterns and timing
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if want := ring != 2; at(corner[0]+dx, corner[1]+dy) != want {
+					t.Fatalf("finder pattern at %v is wrong at (%d, %d)", corner, dx, dy)
+				}
+			}
+		}
+	}
+	for i := 8; i < size-8; i++ {
+		if at(i, 6) != (i%2 == 0) || at(6, i) != (i%2 == 0) {
+			t.Fatalf("timing pattern is wrong at %d", i)
+		}
+	}
+	if !at(8, size-8) {
+		t.Error("the dark module is light")
+	}
+
+	// Format information, both copies, bit 0 first
+	var first, second int
+	for i := 0; i < 15; i++ {
+		var a, b bool
+		switch {
+		case i <= 5:
+			a = at(8, i)
+		case i == 6:
+			a = at(8, 7)
+		case i == 7:
+			a = at(8, 8)
+		case i == 8:
+			a = at(7, 8)
+		default:
+			a = at(14-i, 8)
+		}
+		if i < 8 {
+			b = at(size-1-i, 8)
+		} else {
+			b = at(8, size-15+i)
+		}
+		if a {
+			first |= 1 << i
+		}
+		if b {
+			second |= 1 << i
+		}
+	}
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+	mask := -1
+	for m, format := range qrSpecFormatM {
+		if format == first {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %015b is not level M", first)
+	}
+
+	// Version information, both copies
+	if version >= 7 {
+		for _, transpose := range []bool{false, true} {
+			bits := 0
+			for i := 0; i < 18; i++ {
+				a, b := size-11+i%3, i/3
+				if transpose {
+					a, b = b, a
+				}
+				if at(a, b) {
+					bits |= 1 << i
+				}
+			}
+			if bits>>12 != version {
+				t.Fatalf("version information says %d, want %d", bits>>12, version)
+			}
+		}
+	}
+
+	isFunction := func(x, y int) bool {
+		if (x < 9 && y < 9) || (x >= size-8 && y < 9) || (x < 9 && y >= size-8) || x == 6 || y == 6 {
+			return true
+		}
+		if version >= 7 && ((x >= size-11 && x < size-8 && y < 6) || (y >= size-11 && y < size-8 && x < 6)) {
+			return true
+		}
+		positions := qrSpecAlignment[version]
+		last := len(positions) - 1
+		for i, cy := range positions {
+			for j, cx := range positions {
+				if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+					continue
+				}
+				if abs(x-cx) <= 2 && abs(y-cy) <= 2 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	masked := func(x, y int) bool {
+		switch mask {
+		case 0:
+			return (y+x)%2 == 0
+		case 1:
+			return y%2 == 0
+		case 2:
+			return x%3 == 0
+		case 3:
+			return (y+x)%3 == 0
+		case 4:
+			return (y/2+x/3)%2 == 0
+		case 5:
+			return (y*x)%2+(y*x)%3 == 0
+		case 6:
+			return ((y*x)%2+(y*x)%3)%2 == 0
+		default:
+			return ((y+x)%2+(y*x)%3)%2 == 0
+		}
+	}
+
+	// Read the data area in zigzag order, two columns at a time from the bottom right
+	var bits []bool
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for _, x := range []int{right, right - 1} {
+				if !isFunction(x, y) {
+					bits = append(bits, at(x, y) != masked(x, y))
+				}
+			}
+		}
+		upward = !upward
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i := range codewords {
+		for _, bit := range bits[i*8 : i*8+8] {
+			codewords[i] <<= 1
+			if bit {
+				codewords[i] |= 1
+			}
+		}
+	}
+
+	// De-interleave the blocks and check each is a Reed-Solomon codeword
+	layout := qrSpecBlocks[version]
+	blocks := make([][]byte, len(layout.data))
+	pos := 0
+	for i := 0; i < layout.data[len(layout.data)-1]; i++ {
+		for b, length := range layout.data {
+			if i < length {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	for i := 0; i < layout.ec; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[pos])
+			pos++
+		}
+	}
+	if pos != len(codewords) {
+		t.Fatalf("symbol holds %d codewords, the layout uses %d", len(codewords), pos)
+	}
+	for b, block := range blocks {
+		for i := 0; i < layout.ec; i++ {
+			if s := qrSyndrome(block, i); s != 0 {
+				t.Fatalf("block %d has syndrome %d = %#x, want 0", b, i, s)
+			}
+		}
+	}
+
+	// Byte mode segment, terminator and padding
+	read := func(offset, length int) int {
+		value := 0
+		for i := offset; i < offset+length; i++ {
+			value = value<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		return value
+	}
+	if mode := read(0, 4); mode != 0x4 {
+		t.Fatalf("mode = %04b, want byte mode", mode)
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	count := read(4, countBits)
+	text := make([]byte, count)
+	for i := range text {
+		text[i] = byte(read(4+countBits+8*i, 8))
+	}
+	end := 4 + countBits + 8*count
+	if terminator := min(4, 8*len(data)-end); read(end, terminator) != 0 {
+		t.Error("terminator is not zero")
+	}
+	for i, pad := (end+7)/8, byte(0xEC); i < len(data); i, pad = i+1, pad^0xEC^0x11 {
+		if data[i] != pad {
+			t.Errorf("padding codeword %d = %#x, want %#x", i, data[i], pad)
+		}
+	}
+	return string(text)
+}
+
+// qrSyndrome evaluates the codeword polynomial at α^i in GF(2^8) modulo 0x11D
+func qrSyndrome(block []byte, i int) byte {
+	alpha := byte(1)
+	for k := 0; k < i; k++ {
+		alpha = qrGFMultiply(alpha, 2)
+	}
+	var result byte
+	for _, c := range block {
+		result = qrGFMultiply(result, alpha) ^ c
+	}
+	return result
+}
+
+func qrGFMultiply(x, y byte) byte {
+	var result byte
+	for y > 0 {
+		if y&1 == 1 {
+			result ^= x
+		}
+		carry := x&0x80 != 0
+		x <<= 1
+		if carry {
+			x ^= 0x1D
+		}
+		y >>= 1
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimitStore counts requests in fixed windows. Hit counts one request against a key and returns
+// how many the key has made in the current window and when that window ends.
+type RateLimitStore interface {
+	Hit(ctx context.Context, key string, window time.Duration) (count int, resetAt time.Time, err error)
+}
+
+// NewRateLimitStore returns the store selected by the config: counters in memory, which each server
+// instance keeps separately, or in Redis, shared by every instance
+func NewRateLimitStore(config models.RateLimitConfig) (RateLimitStore, error) {
+	switch config.Store {
+	case "", "memory":
+		return &memoryRateLimitStore{windows: make(map[string]*rateLimitWindow)}, nil
+	case "redis":
+		client, err := NewRedisClient(config.RedisURL, config.RedisTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return &redisRateLimitStore{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store: %s", config.Store)
+	}
+}
+
+type rateLimitWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	windows   map[string]*rateLimitWindow
+	lastSweep time.Time
+}
+
+func (s *memoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Forget ended windows now and then so clients seen once do not stay in memory
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for k, w := range s.windows {
+			if !now.Before(w.resetAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, exists := s.windows[key]
+	if !exists || !now.Before(w.resetAt) {
+		w = &rateLimitWindow{resetAt: now.Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count, w.resetAt, nil
+}
+
+// redisHitScript counts a hit and starts the window's expiry on its first one, returning the count
+// and the milliseconds left in the window
+var redisHitScript = redis.NewScript(`local count = redis.call('INCR', KEYS[1])
+if count == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then redis.call('PEXPIRE', KEYS[1], ARGV[1]); ttl = tonumber(ARGV[1]) end
+return {count, ttl}`)
+
+// redisRateLimitStore keeps the counters in Redis, shared by every server instance
+type redisRateLimitStore struct {
+	client *redis.Client
+}
+
+func (s *redisRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	values, err := redisHitScript.Run(ctx, s.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if len(values) != 2 {
+		return 0, time.Time{}, errors.New("unexpected reply from Redis")
+	}
+	return int(values[0]), time.Now().Add(time.Duration(values[1]) * time.Millisecond), nil
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient reads a redis:// or rediss:// URL such as redis://:password@host:6379/0, with every
+// dial, read and write bounded by timeout. Nothing is dialed until the first command.
+func NewRedisClient(rawURL string, timeout time.Duration) (*redis.Client, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, errors.New("invalid Redis URL: expected redis://[:password@]host:port[/db]")
+	}
+	options.DialTimeout = timeout
+	options.ReadTimeout = timeout
+	options.WriteTimeout = timeout
+	return redis.NewClient(options), nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"math"
+	"time"
+
+	"backend/models"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minimumEaseFactor = 1.3
+)
+
+// NewReviewSchedule returns the initial spaced-repetition state for a new item, due immediately
+func NewReviewSchedule(now time.Time) models.ReviewSchedule {
+	return models.ReviewSchedule{
+		EaseFactor:   defaultEaseFactor,
+		IntervalDays: 0,
+		Repetitions:  0,
+		DueAt:        now,
+	}
+}
+
+// ScheduleReview applies the SM-2 algorithm to a schedule for a review graded 0-5.
+// Grades below 3 reset the repetition count so the item comes back the next day.
+func ScheduleReview(schedule models.ReviewSchedule, quality int, now time.Time) models.ReviewSchedule {
+	if quality < 0 {
+		quality = 0
+	}
+	if quality > 5 {
+		quality = 5
+	}
+
+	if schedule.EaseFactor == 0 {
+		schedule.EaseFactor = defaultEaseFactor
+	}
+
+	if quality < 3 {
+		schedule.Repetitions = 0
+		schedule.IntervalDays = 1
+	} else {
+		switch schedule.Repetitions {
+		case 0:
+			schedule.IntervalDays = 1
+		case 1:
+			schedule.IntervalDays = 6
+		default:
+			schedule.IntervalDays = int(math.Round(float64(schedule.IntervalDays) * schedule.EaseFactor))
+		}
+		schedule.Repetitions++
+	}
+
+	q := float64(5 - quality)
+	schedule.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if schedule.EaseFactor < minimumEaseFactor {
+		schedule.EaseFactor = minimumEaseFactor
+	}
+
+	schedule.DueAt = now.AddDate(0, 0, schedule.IntervalDays)
+	schedule.LastReviewedAt = &now
+	schedule.LastQuality = quality
+
+	return schedule
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Short codes avoid characters that are easily confused when typed from print (0/o, 1/l/i)
+const shortCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// ShortCodeLength gives about 2.7e10 codes, so random collisions stay rare
+const ShortCodeLength = 7
+
+// JoinCodeLength is shorter since join codes are read off a projector and only needed for a class
+const JoinCodeLength = 6
+
+// GenerateShortCode returns a random code for a short link
+func GenerateShortCode() (string, error) {
+	return randomCode(ShortCodeLength)
+}
+
+// GenerateJoinCode returns a random code students type to join a class quiz
+func GenerateJoinCode() (string, error) {
+	return randomCode(JoinCodeLength)
+}
+
+func randomCode(length int) (string, error) {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(shortCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = shortCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
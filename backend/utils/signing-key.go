@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"backend/models"
+)
+
+// SigningKeyAlgorithm is the JWT algorithm used with rotating signing keys
+const SigningKeyAlgorithm = "ES256"
+
+// SigningKey is a private key tokens are signed with, identified by the kid header
+type SigningKey struct {
+	KID        string
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// GenerateSigningKey creates a new P-256 key with a random kid
+func GenerateSigningKey() (*SigningKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	kidBytes := make([]byte, 8)
+	if _, err := rand.Read(kidBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate key id: %w", err)
+	}
+
+	return &SigningKey{KID: hex.EncodeToString(kidBytes), PrivateKey: privateKey}, nil
+}
+
+// MarshalSigningKey encodes the private key as DER for storage
+func MarshalSigningKey(key *SigningKey) ([]byte, error) {
+	return x509.MarshalECPrivateKey(key.PrivateKey)
+}
+
+// ParseSigningKey decodes a private key stored by MarshalSigningKey
+func ParseSigningKey(kid string, der []byte) (*SigningKey, error) {
+	privateKey, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key %s: %w", kid, err)
+	}
+	return &SigningKey{KID: kid, PrivateKey: privateKey}, nil
+}
+
+// PublicJWK returns the public half of the key in JSON Web Key format
+func PublicJWK(kid string, publicKey *ecdsa.PublicKey) (models.JWK, error) {
+	ecdhKey, err := publicKey.ECDH()
+	if err != nil {
+		return models.JWK{}, fmt.Errorf("failed to encode public key %s: %w", kid, err)
+	}
+
+	// Uncompressed point: 0x04 || X || Y
+	point := ecdhKey.Bytes()
+	size := (len(point) - 1) / 2
+
+	return models.JWK{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[1+size:]),
+		Kid: kid,
+		Use: "sig",
+		Alg: SigningKeyAlgorithm,
+	}, nil
+}
@@ -0,0 +1,308 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"backend/models"
+)
+
+// ErrUnsupportedSpreadsheet is returned for a file that is neither CSV nor XLSX
+var ErrUnsupportedSpreadsheet = models.BadRequestError("unsupported file type, must be .csv or .xlsx")
+
+// ReadSpreadsheet reads all rows of a CSV or XLSX file, picking the parser from the file extension.
+// For XLSX files only the first worksheet is read.
+func ReadSpreadsheet(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(r)
+	case ".xlsx":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedSpreadsheet
+	}
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Row-level validation reports short rows instead of failing the whole file
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, models.BadRequestError(fmt.Sprintf("invalid CSV file: %v", err))
+	}
+
+	// Strip a UTF-8 BOM left by spreadsheet exports
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\uFEFF")
+	}
+
+	return rows, nil
+}
+
+// Minimal SpreadsheetML structures, enough to read cell values
+type xlsxSharedStrings struct {
+	Items []xlsxStringItem `xml:"si"`
+}
+
+type xlsxStringItem struct {
+	Text string        `xml:"t"`
+	Runs []xlsxTextRun `xml:"r"`
+}
+
+type xlsxTextRun struct {
+	Text string `xml:"t"`
+}
+
+type xlsxWorksheet struct {
+	Rows []xlsxRow `xml:"sheetData>row"`
+}
+
+type xlsxRow struct {
+	Number int        `xml:"r,attr"`
+	Cells  []xlsxCell `xml:"c"`
+}
+
+type xlsxCell struct {
+	Ref    string          `xml:"r,attr"`
+	Type   string          `xml:"t,attr"`
+	Value  string          `xml:"v"`
+	Inline *xlsxStringItem `xml:"is"`
+}
+
+func (si xlsxStringItem) String() string {
+	if len(si.Runs) == 0 {
+		return si.Text
+	}
+	var b strings.Builder
+	for _, run := range si.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, models.BadRequestError(fmt.Sprintf("invalid XLSX file: %v", err))
+	}
+
+	var sharedStrings xlsxSharedStrings
+	var worksheet *zip.File
+	for _, file := range archive.File {
+		switch {
+		case file.Name == "xl/sharedStrings.xml":
+			if err := decodeZipXML(file, &sharedStrings); err != nil {
+				return nil, models.BadRequestError(fmt.Sprintf("invalid XLSX shared strings: %v", err))
+			}
+		case strings.HasPrefix(file.Name, "xl/worksheets/sheet") && strings.HasSuffix(file.Name, ".xml"):
+			// sheet1.xml sorts before sheet2.xml, sheet10.xml, ...
+			if worksheet == nil || file.Name < worksheet.Name {
+				worksheet = file
+			}
+		}
+	}
+
+	if worksheet == nil {
+		return nil, models.BadRequestError("invalid XLSX file: no worksheet found")
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(worksheet, &sheet); err != nil {
+		return nil, models.BadRequestError(fmt.Sprintf("invalid XLSX worksheet: %v", err))
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		// Empty rows are omitted as well; pad them so row numbers match the spreadsheet
+		for row.Number > 0 && len(rows) < row.Number-1 {
+			rows = append(rows, []string{})
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			// Empty cells are omitted from the XML, so position comes from the cell reference
+			col := columnIndex(cell.Ref)
+			if col < 0 {
+				col = i
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err == nil && index >= 0 && index < len(sharedStrings.Items) {
+					values[col] = sharedStrings.Items[index].String()
+				}
+			case "inlineStr":
+				if cell.Inline != nil {
+					values[col] = cell.Inline.String()
+				}
+			default:
+				values[col] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+func decodeZipXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// columnIndex converts the letters of a cell reference like "AB12" to a zero-based column index
+func columnIndex(ref string) int {
+	index := 0
+	letters := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		index = index*26 + int(ch-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return -1
+	}
+	return index - 1
+}
+
+// SpreadsheetWriter writes rows to a CSV or XLSX file as they come, so large sheets are never held
+// in memory. Close must be called to finish the file.
+type SpreadsheetWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+// NewSpreadsheetWriter starts a spreadsheet in the given format, csv or xlsx. XLSX files hold a
+// single worksheet of inline strings, which ReadSpreadsheet reads back.
+func NewSpreadsheetWriter(format string, w io.Writer) (SpreadsheetWriter, error) {
+	switch format {
+	case "csv":
+		return &csvSpreadsheetWriter{writer: csv.NewWriter(w)}, nil
+	case "xlsx":
+		return newXLSXWriter(w)
+	default:
+		return nil, errors.New("unsupported spreadsheet format, must be csv or xlsx")
+	}
+}
+
+type csvSpreadsheetWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvSpreadsheetWriter) WriteRow(values []string) error {
+	return cw.writer.Write(values)
+}
+
+func (cw *csvSpreadsheetWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// Fixed parts of a one-sheet workbook
+var xlsxPackageFiles = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, file := range xlsxPackageFiles {
+		part, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(part, file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// The worksheet is written last and left open for rows
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []string) error {
+	xw.rows++
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<row r="%d">`, xw.rows)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(i), xw.rows)
+		if err := xml.EscapeText(&b, []byte(value)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	_, err := xw.sheet.Write(b.Bytes())
+	return err
+}
+
+func (xw *xlsxWriter) Close() error {
+	if _, err := io.WriteString(xw.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return xw.archive.Close()
+}
+
+// columnName converts a zero-based column index to its letters, the inverse of columnIndex
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
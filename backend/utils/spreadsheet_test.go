@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXLSXWriterRoundTrip(t *testing.T) {
+	wide := make([]string, 28)
+	for i := range wide {
+		wide[i] = columnName(i)
+	}
+	rows := [][]string{
+		{"question", "answer", "note"},
+		{"Is 1 < 2 & 3 > 2?", "", `"yes"`},
+		{"  spaced  ", "line\nbreak", "ünïcödé"},
+		wide,
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewSpreadsheetWriter("xlsx", &buf)
+	if err != nil {
+		t.Fatalf("NewSpreadsheetWriter() error = %v", err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("written file is not a zip archive: %v", err)
+	}
+	parts := map[string][]byte{}
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", file.Name, err)
+		}
+		parts[file.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		content, ok := parts[name]
+		if !ok {
+			t.Errorf("archive has no %s", name)
+			continue
+		}
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s is not well-formed: %v", name, err)
+				break
+			}
+		}
+	}
+	if _, ok := parts["xl/sharedStrings.xml"]; ok {
+		t.Error("writer emitted shared strings; it writes inline strings only")
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !bytes.Contains(sheet, []byte("Is 1 &lt; 2 &amp; 3 &gt; 2?")) {
+		t.Errorf("text was not escaped: %s", sheet)
+	}
+
+	var parsed struct {
+		Rows []struct {
+			Number string `xml:"r,attr"`
+			Cells  []struct {
+				Ref  string `xml:"r,attr"`
+				Type string `xml:"t,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(sheet, &parsed); err != nil {
+		t.Fatalf("worksheet does not parse: %v", err)
+	}
+	if len(parsed.Rows) != len(rows) {
+		t.Fatalf("worksheet has %d rows, want %d", len(parsed.Rows), len(rows))
+	}
+	second := parsed.Rows[1]
+	if second.Number != "2" || len(second.Cells) != 2 || second.Cells[0].Ref != "A2" || second.Cells[1].Ref != "C2" {
+		t.Errorf("row 2 = %+v, want cells A2 and C2 with the empty B2 left out", second)
+	}
+	if cell := second.Cells[0]; cell.Type != "inlineStr" || cell.Text != "Is 1 < 2 & 3 > 2?" {
+		t.Errorf("A2 = %+v", cell)
+	}
+	last := parsed.Rows[3].Cells
+	if got := last[len(last)-1].Ref; got != "AB4" {
+		t.Errorf("28th column of row 4 is %s, want AB4", got)
+	}
+
+	read, err := ReadSpreadsheet("export.xlsx", bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadSpreadsheet() error = %v", err)
+	}
+	if !reflect.DeepEqual(read, rows) {
+		t.Errorf("ReadSpreadsheet() = %q, want %q", read, rows)
+	}
+}
+
+func TestReadSpreadsheetXLSXSharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="3" uniqueCount="3">
+<si><t>Name</t></si>
+<si><t>Tom &amp; Jerry &lt;3</t></si>
+<si><r><t>rich </t></r><r><t>text</t></r></si>
+</sst>`,
+		// Row 2 is missing and B3 is empty, as spreadsheet programs leave them out
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>2</v></c></row>
+<row r="3"><c r="A3" t="s"><v>1</v></c><c r="C3"><v>42.5</v></c><c r="D3" t="s"><v>9</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row r="1"><c r="A1"><v>ignored</v></c></row></sheetData></worksheet>`,
+	} {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		io.WriteString(part, content)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	rows, err := ReadSpreadsheet("Import.XLSX", &buf)
+	if err != nil {
+		t.Fatalf("ReadSpreadsheet() error = %v", err)
+	}
+	// An out-of-range shared string index reads as empty
+	want := [][]string{{"Name", "rich text"}, {}, {"Tom & Jerry <3", "", "42.5", ""}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ReadSpreadsheet() = %q, want %q", rows, want)
+	}
+}
+
+func TestReadSpreadsheetErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     string
+	}{
+		{"unsupported type", "questions.ods", "", "unsupported file type"},
+		{"not a zip", "questions.xlsx", "name,answer", "invalid XLSX file"},
+		{"bad CSV quoting", "questions.csv", "a,\"b\n", "invalid CSV file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSpreadsheet(tt.filename, strings.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadSpreadsheet() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSpreadsheetCSV(t *testing.T) {
+	rows, err := ReadSpreadsheet("questions.csv", strings.NewReader("\uFEFFquestion, answer\n\"a, b\",c,extra\nshort\n"))
+	if err != nil {
+		t.Fatalf("ReadSpreadsheet() error = %v", err)
+	}
+	want := [][]string{{"question", "answer"}, {"a, b", "c", "extra"}, {"short"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ReadSpreadsheet() = %q, want %q", rows, want)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		name  string
+	}{
+		{0, "A"}, {25, "Z"}, {26, "AA"}, {27, "AB"}, {51, "AZ"}, {52, "BA"}, {701, "ZZ"}, {702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.name {
+			t.Errorf("columnName(%d) = %q, want %q", tt.index, got, tt.name)
+		}
+		if got := columnIndex(tt.name + "12"); got != tt.index {
+			t.Errorf("columnIndex(%q) = %d, want %d", tt.name+"12", got, tt.index)
+		}
+	}
+	if got := columnIndex("12"); got != -1 {
+		t.Errorf("columnIndex(%q) = %d, want -1", "12", got)
+	}
+}
@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/models"
+)
+
+// ErrStoredFileNotFound is returned when a key has nothing stored under it
+var ErrStoredFileNotFound = errors.New("stored file not found")
+
+// FileStorage keeps uploaded files under slash-separated keys. Implementations must be safe for
+// concurrent use.
+type FileStorage interface {
+	Name() string
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the file under key; deleting a key with nothing stored is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFileStorage returns the storage selected by the config
+func NewFileStorage(config models.StorageConfig) (FileStorage, error) {
+	switch strings.ToLower(config.Provider) {
+	case "", "local":
+		if config.LocalDir == "" {
+			return nil, errors.New("STORAGE_LOCAL_DIR is required for local storage")
+		}
+		return &localFileStorage{dir: config.LocalDir}, nil
+	case "s3":
+		if config.S3Bucket == "" || config.S3AccessKey == "" || config.S3SecretKey == "" {
+			return nil, errors.New("STORAGE_S3_BUCKET, STORAGE_S3_ACCESS_KEY and STORAGE_S3_SECRET_KEY are required for S3 storage")
+		}
+		return &s3FileStorage{config: config, client: &http.Client{Timeout: config.Timeout}}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage provider %q", config.Provider)
+	}
+}
+
+// checkStorageKey rejects keys that could reach outside the storage root
+func checkStorageKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// localFileStorage keeps files in a directory on the server's disk
+type localFileStorage struct {
+	dir string
+}
+
+func (s *localFileStorage) Name() string { return "local" }
+
+func (s *localFileStorage) path(key string) (string, error) {
+	if err := checkStorageKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file first so a failed upload never leaves a partial file under key
+func (s *localFileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localFileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStoredFileNotFound
+	}
+	return file, err
+}
+
+func (s *localFileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// s3FileStorage keeps files in an S3 bucket, or a bucket of an S3-compatible service, signing
+// requests with AWS Signature Version 4
+type s3FileStorage struct {
+	config models.StorageConfig
+	client *http.Client
+}
+
+func (s *s3FileStorage) Name() string { return "s3" }
+
+func (s *s3FileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *s3FileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *s3FileStorage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, ErrStoredFileNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// newRequest addresses the object in the bucket's virtual host on AWS, or by path on a custom endpoint
+func (s *s3FileStorage) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if err := checkStorageKey(key); err != nil {
+		return nil, err
+	}
+
+	var objectURL string
+	if s.config.S3Endpoint != "" {
+		objectURL = strings.TrimRight(s.config.S3Endpoint, "/") + "/" + s3EscapePath(s.config.S3Bucket+"/"+key)
+	} else {
+		objectURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.config.S3Bucket, s.config.S3Region, s3EscapePath(key))
+	}
+	return http.NewRequestWithContext(ctx, method, objectURL, body)
+}
+
+// do signs and sends a request, turning a missing object and any other failure status into errors
+func (s *s3FileStorage) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrStoredFileNotFound
+	}
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// sign adds a Signature Version 4 authorization header. The body is left unsigned so uploads can
+// stream without being read twice.
+func (s *s3FileStorage) sign(req *http.Request, now time.Time) {
+	const payloadHash = "UNSIGNED-PAYLOAD"
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := day + "/" + s.config.S3Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.S3SecretKey), day)
+	key = hmacSHA256(key, s.config.S3Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.S3AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath percent-encodes everything but unreserved characters and slashes, as S3 signing expects
+func s3EscapePath(path string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(path) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/models"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Traces go through the OpenTelemetry SDK: spans carry W3C trace context in and out of the server and
+// are sent to an OTLP collector over HTTP in batches. A provider is installed with SetTracerProvider;
+// until one is, or when no collector is configured, the spans StartSpan returns record nothing, so
+// instrumented code needs no checks.
+
+// TracerName is the instrumentation scope the server's spans are recorded under
+const TracerName = "backend"
+
+// NewTracerProvider returns a provider exporting to the configured collector, or nil when tracing is off
+func NewTracerProvider(config models.TracingConfig) (*sdktrace.TracerProvider, error) {
+	if config.Endpoint == "" {
+		return nil, nil
+	}
+	if _, err := url.ParseRequestURI(config.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q", config.Endpoint)
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, fmt.Errorf("trace sample ratio must be between 0 and 1, got %v", config.SampleRatio)
+	}
+	headers, err := parseOTLPHeaders(config.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(config.Endpoint),
+		otlptracehttp.WithHeaders(headers),
+		otlptracehttp.WithTimeout(config.Timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", config.ServiceName),
+			attribute.String("deployment.environment", config.Environment),
+		)),
+		// Keep the configured share of new traces and follow the caller's decision for the rest
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	), nil
+}
+
+// SetTracerProvider makes provider the one StartSpan uses, reading and writing W3C trace context and
+// logging spans it fails to export; nil leaves tracing off
+func SetTracerProvider(provider *sdktrace.TracerProvider) {
+	if provider == nil {
+		return
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Warn("Failed to export spans", "error", err)
+	}))
+}
+
+// StartSpan starts a span for work done inside the server, as a child of the span in ctx if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartServerSpan starts the span of a request, continuing the trace of the W3C traceparent header
+// the caller sent, if valid
+func StartServerSpan(ctx context.Context, name string, header http.Header, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// StartClientSpan starts a span for a call the server makes to another system
+func StartClientSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// InjectTraceContext writes the W3C trace context of the span in ctx to header, continuing its trace
+// in whoever reads it
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// RecordSpanError marks the span as failed with the error's message, secrets redacted. The error is
+// not recorded as an event, which would keep its message as it is.
+func RecordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.SetStatus(codes.Error, RedactSecrets(err.Error()))
+}
+
+// parseOTLPHeaders reads OTEL_EXPORTER_OTLP_HEADERS: comma separated key=value pairs with URL encoded values
+func parseOTLPHeaders(raw string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, errors.New("invalid OTLP headers: expected key=value pairs")
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", key, err)
+		}
+		headers[strings.TrimSpace(key)] = decoded
+	}
+	return headers, nil
+}
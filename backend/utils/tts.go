@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/models"
+)
+
+// maxTTSAudioSize keeps generated audio well under MongoDB's document size limit
+const maxTTSAudioSize = 8 << 20
+
+// ErrTTSDisabled is returned when no text-to-speech provider is configured
+var ErrTTSDisabled = models.NewError(http.StatusServiceUnavailable, "text-to-speech is not configured")
+
+// TTSAudio is synthesized speech returned by a provider
+type TTSAudio struct {
+	Data        []byte
+	ContentType string
+}
+
+// TTSProvider turns text into speech. Implementations must be safe for concurrent use.
+type TTSProvider interface {
+	Name() string
+	Voice() string
+	Synthesize(ctx context.Context, text string) (*TTSAudio, error)
+}
+
+// NewTTSProvider returns the provider selected by the config
+func NewTTSProvider(config models.TTSConfig) (TTSProvider, error) {
+	client := &http.Client{Timeout: config.Timeout}
+
+	switch strings.ToLower(config.Provider) {
+	case "":
+		return disabledTTSProvider{}, nil
+	case "google":
+		if config.APIKey == "" {
+			return nil, errors.New("TTS_API_KEY is required for the google TTS provider")
+		}
+		return &googleTTSProvider{config: config, client: client}, nil
+	case "http":
+		if config.Endpoint == "" {
+			return nil, errors.New("TTS_ENDPOINT is required for the http TTS provider")
+		}
+		return &httpTTSProvider{config: config, client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown TTS provider %q", config.Provider)
+	}
+}
+
+type disabledTTSProvider struct{}
+
+func (disabledTTSProvider) Name() string  { return "" }
+func (disabledTTSProvider) Voice() string { return "" }
+func (disabledTTSProvider) Synthesize(ctx context.Context, text string) (*TTSAudio, error) {
+	return nil, ErrTTSDisabled
+}
+
+// googleTTSProvider calls the Google Cloud Text-to-Speech REST API with an API key
+type googleTTSProvider struct {
+	config models.TTSConfig
+	client *http.Client
+}
+
+func (p *googleTTSProvider) Name() string  { return "google" }
+func (p *googleTTSProvider) Voice() string { return p.config.Voice }
+
+func (p *googleTTSProvider) Synthesize(ctx context.Context, text string) (*TTSAudio, error) {
+	voice := map[string]string{"languageCode": p.config.LanguageCode}
+	if p.config.Voice != "" {
+		voice["name"] = p.config.Voice
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"input":       map[string]string{"text": text},
+		"voice":       voice,
+		"audioConfig": map[string]string{"audioEncoding": "MP3"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "https://texttospeech.googleapis.com/v1/text:synthesize?key=" + url.QueryEscape(p.config.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tts request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("tts provider returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var result struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTTSAudioSize*2)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode tts response: %w", err)
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil || len(audio) == 0 {
+		return nil, errors.New("tts provider returned no audio")
+	}
+	if len(audio) > maxTTSAudioSize {
+		return nil, errors.New("generated audio is too large")
+	}
+
+	return &TTSAudio{Data: audio, ContentType: "audio/mpeg"}, nil
+}
+
+// httpTTSProvider posts {"text","voice","language_code"} to a self-hosted or third-party
+// endpoint that responds with the audio bytes
+type httpTTSProvider struct {
+	config models.TTSConfig
+	client *http.Client
+}
+
+func (p *httpTTSProvider) Name() string  { return "http" }
+func (p *httpTTSProvider) Voice() string { return p.config.Voice }
+
+func (p *httpTTSProvider) Synthesize(ctx context.Context, text string) (*TTSAudio, error) {
+	body, err := json.Marshal(map[string]string{
+		"text":          text,
+		"voice":         p.config.Voice,
+		"language_code": p.config.LanguageCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "audio/*")
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tts request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("tts provider returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "audio/") {
+		return nil, fmt.Errorf("tts provider returned %q instead of audio", contentType)
+	}
+
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxTTSAudioSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tts response: %w", err)
+	}
+	if len(audio) == 0 {
+		return nil, errors.New("tts provider returned no audio")
+	}
+	if len(audio) > maxTTSAudioSize {
+		return nil, errors.New("generated audio is too large")
+	}
+
+	return &TTSAudio{Data: audio, ContentType: contentType}, nil
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+// GenerateWebhookSecret returns a random secret for signing a webhook's deliveries
+func GenerateWebhookSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(bytes), nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value for a delivery: "sha256=" and the hex
+// HMAC-SHA256, keyed with the webhook secret, of "<unix timestamp>.<body>". Receivers recompute it
+// and reject old timestamps so captured deliveries cannot be replayed.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
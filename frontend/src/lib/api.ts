@@ -7,8 +7,27 @@ export interface ApiResponse<T> {
 }
 
 export interface ApiError {
-  error: string
-  details?: string
+  code: string
+  message: string
+  details?: unknown
+  request_id?: string
+}
+
+// ApiRequestError carries the code the API answered with, for callers to branch on
+export class ApiRequestError extends Error {
+  status: number
+  code: string
+  details?: unknown
+  requestId?: string
+
+  constructor(status: number, body: ApiError) {
+    super(body.message)
+    this.name = 'ApiRequestError'
+    this.status = status
+    this.code = body.code
+    this.details = body.details
+    this.requestId = body.request_id
+  }
 }
 
 // JWT token inspection utility
@@ -233,7 +252,8 @@ const apiCall = async <T>(
     
     if (!response.ok) {
       const errorData: ApiError = await response.json().catch(() => ({
-        error: `HTTP ${response.status}: ${response.statusText}`,
+        code: 'unknown',
+        message: `HTTP ${response.status}: ${response.statusText}`,
       }))
     
     // Provide more specific error messages for common issues
@@ -259,7 +279,10 @@ const apiCall = async <T>(
       throw new Error('Access denied. You do not have permission to perform this action.')
     }
     
-      throw new Error(errorData.error || `Request failed with status ${response.status}`)
+      throw new ApiRequestError(response.status, {
+        ...errorData,
+        message: errorData.message || `Request failed with status ${response.status}`,
+      })
     }
 
     return response.json()
@@ -54,6 +54,7 @@ export interface GetActivityLogsResponse {
   page: number;
   limit: number;
   total_pages: number;
+  next_cursor?: string;
 }
 
 export interface ActivityType {
@@ -63,6 +63,7 @@ export interface ListQuestionsResponse {
   page: number
   limit: number
   total_pages: number
+  next_cursor?: string
 }
 
 export interface QuestionStats {
@@ -109,17 +109,15 @@ class QuizService {
 
   // Utility methods for error handling
   isQuizExpiredError(error: any): boolean {
-    return error?.response?.data?.details?.includes('expired') || 
-           error?.response?.data?.error?.includes('expired')
+    return error?.code === 'session_expired'
   }
 
   isSessionNotFoundError(error: any): boolean {
-    return error?.response?.status === 404 ||
-           error?.response?.data?.error?.includes('not found')
+    return error?.status === 404
   }
 
   isUnauthorizedError(error: any): boolean {
-    return error?.response?.status === 401
+    return error?.status === 401
   }
 }
 